@@ -0,0 +1,876 @@
+// Package admin runs the operational tasks of the admins for cmd/bankctl. The operations go
+// through the store like the servers do, each in a transaction that also writes an audit entry
+// of who ran it, on what and why:
+//
+//	operator, err := admin.NewOperator(store, "alice")
+//	account, err := operator.FreezeAccount(ctx, 42, "chargeback fraud, ticket 1234")
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/backendmaster/simple_bank/screening"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/userimport"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/val"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The actions of the audit entries.
+const (
+	ActionCreateAdminUser          = "create_admin_user"
+	ActionFreezeAccount            = "freeze_account"
+	ActionAdjustBalance            = "adjust_balance"
+	ActionReissueVerificationEmail = "reissue_verification_email"
+	ActionRevokeSessions           = "revoke_sessions"
+	ActionImpersonateUser          = "impersonate_user"
+	ActionRevokeImpersonation      = "revoke_impersonation"
+	ActionSetDisputeStatus         = "set_dispute_status"
+	ActionReviewTransfer           = "review_transfer"
+	ActionAddDenylistEntry         = "add_denylist_entry"
+	ActionRemoveDenylistEntry      = "remove_denylist_entry"
+	ActionSetScreeningHold         = "set_screening_hold"
+	ActionCreateLoanOffer          = "create_loan_offer"
+	ActionSetLoanOfferActive       = "set_loan_offer_active"
+	ActionCreateTenant             = "create_tenant"
+	ActionUpdateTenant             = "update_tenant"
+	ActionSetCurrencyEnabled       = "set_currency_enabled"
+	ActionImportUsers              = "import_users"
+	ActionPublishLegalDocument     = "publish_legal_document"
+)
+
+var (
+	// ErrImpersonateAdmin is returned by Impersonate for a user who is an admin.
+	ErrImpersonateAdmin = errors.New("admins can't be impersonated")
+	// ErrInvalidReasonCode is returned by AdjustBalance for a code missing from AdjustmentReasons.
+	ErrInvalidReasonCode = errors.New("invalid reason code")
+	// ErrInvalidDenylistEntry is returned by AddDenylistEntry for an unknown kind or a malformed value.
+	ErrInvalidDenylistEntry = errors.New("invalid denylist entry")
+	// ErrInvalidLoanOffer is returned by CreateLoanOffer for terms no loan can be offered under.
+	ErrInvalidLoanOffer = errors.New("invalid loan offer")
+	// ErrInvalidTenant is returned by CreateTenant and UpdateTenant for terms a tenant can't have.
+	ErrInvalidTenant = errors.New("invalid tenant")
+	// ErrCurrencyInUse is returned by SetCurrencyEnabled for a currency a tenant still offers.
+	ErrCurrencyInUse = errors.New("currency is offered by a tenant")
+	// ErrInvalidUserImport is returned by ImportUsers for a file that can't be imported at all.
+	ErrInvalidUserImport = errors.New("invalid user import")
+	// ErrInvalidLegalDocument is returned by PublishLegalDocument for an unknown kind, or a version
+	// or a url the users couldn't be shown.
+	ErrInvalidLegalDocument = errors.New("invalid legal document")
+)
+
+// The bounds of the terms of the loan offers.
+const (
+	MaxLoanRateBps    = 10000
+	MaxLoanTermMonths = 360
+)
+
+// AdjustmentReasons are the reason codes of the balance adjustments, each with the reason the
+// owner of the account is notified of.
+var AdjustmentReasons = map[string]string{
+	"bank_error":            "correction of an error of the bank",
+	"duplicate_transaction": "reversal of a duplicate transaction",
+	"chargeback":            "chargeback of a disputed payment",
+	"fee_refund":            "refund of a fee",
+	"goodwill_credit":       "goodwill credit",
+	"fraud_recovery":        "recovery of fraudulent funds",
+}
+
+// Store is what the operations need of db.Store.
+type Store interface {
+	db.UserStore
+	db.AccountStore
+	db.SessionStore
+	db.AuditStore
+	db.DisputeStore
+	db.TransferReviewStore
+	db.ScreeningStore
+	db.LoanStore
+	db.TenantStore
+	db.CurrencyStore
+	db.UserImportStore
+	db.ConsentStore
+}
+
+// Operator runs the operations on behalf of an admin, the actor of their audit entries.
+type Operator struct {
+	store Store
+	actor string
+}
+
+func NewOperator(store Store, actor string) (*Operator, error) {
+	if strings.TrimSpace(actor) == "" {
+		return nil, errors.New("the actor of the operations is required")
+	}
+	return &Operator{store: store, actor: actor}, nil
+}
+
+// UserTarget and AccountTarget are the targets of the audit entries of a user and an account.
+func UserTarget(username string) string {
+	return "user:" + username
+}
+
+func AccountTarget(id int64) string {
+	return "account:" + strconv.FormatInt(id, 10)
+}
+
+// DenylistTarget is the target of the audit entries of a denylist entry, e.g. denylist:name:John Doe.
+func DenylistTarget(entry db.DenylistEntry) string {
+	return "denylist:" + entry.Match()
+}
+
+// LoanOfferTarget is the target of the audit entries of a loan offer, e.g. loan_offer:Personal loan.
+func LoanOfferTarget(name string) string {
+	return "loan_offer:" + name
+}
+
+// TenantTarget is the target of the audit entries of a tenant, e.g. tenant:acme.
+func TenantTarget(id string) string {
+	return "tenant:" + id
+}
+
+// UserImportTarget is the target of the audit entries of the user imports.
+const UserImportTarget = "user_import"
+
+// LegalDocumentTarget is the target of the audit entries of a version of a legal document, e.g.
+// legal_document:terms:2026-10.
+func LegalDocumentTarget(kind, version string) string {
+	return "legal_document:" + kind + ":" + version
+}
+
+// CurrencyTarget is the target of the audit entries of a currency, e.g. currency:JPY.
+func CurrencyTarget(code string) string {
+	return "currency:" + code
+}
+
+// audit builds the audit entry of an operation. Every operation needs a reason.
+func (operator *Operator) audit(action, target, reason string, details any) (db.CreateAuditEntryParams, error) {
+	if strings.TrimSpace(reason) == "" {
+		return db.CreateAuditEntryParams{}, fmt.Errorf("%s needs a reason", action)
+	}
+	if details == nil {
+		details = struct{}{}
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return db.CreateAuditEntryParams{}, fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+	return db.CreateAuditEntryParams{
+		Actor:   operator.actor,
+		Action:  action,
+		Target:  target,
+		Reason:  reason,
+		Details: data,
+	}, nil
+}
+
+type CreateAdminUserParams struct {
+	Username string
+	Password string
+	FullName string
+	Email    string
+	Reason   string
+}
+
+// CreateAdminUser creates a user with the admin role, who gets the verification email every
+// new user gets.
+func (operator *Operator) CreateAdminUser(ctx context.Context, arg CreateAdminUserParams) (db.User, error) {
+	for _, err := range []error{
+		val.ValidateUserName(arg.Username),
+		val.ValidatePassword(arg.Password),
+		val.ValidateFullName(arg.FullName),
+		val.ValidateEmail(arg.Email),
+	} {
+		if err != nil {
+			return db.User{}, err
+		}
+	}
+	audit, err := operator.audit(ActionCreateAdminUser, UserTarget(arg.Username), arg.Reason, nil)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	hashedPassword, err := util.HashedPassword(arg.Password)
+	if err != nil {
+		return db.User{}, err
+	}
+	result, err := operator.store.CreateUserTx(ctx, db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       arg.Username,
+			HashedPassword: hashedPassword,
+			FullName:       arg.FullName,
+			Email:          arg.Email,
+		},
+		AfterCreate: worker.AfterCreateUser,
+		Role:        util.AdminRole,
+		Audit:       &audit,
+		Screen:      screening.NewDenylist(operator.store).ScreenUser,
+	})
+	return result.User, err
+}
+
+// FreezeAccount freezes an account, so that it can neither send nor receive transfers.
+func (operator *Operator) FreezeAccount(ctx context.Context, accountID int64, reason string) (db.Account, error) {
+	audit, err := operator.audit(ActionFreezeAccount, AccountTarget(accountID), reason, nil)
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	return operator.store.FreezeAccountTx(ctx, db.FreezeAccountTxParams{
+		AccountID: accountID,
+		Audit:     audit,
+	})
+}
+
+type AdjustBalanceParams struct {
+	AccountID int64
+	// Amount is credited to the account, or debited when negative.
+	Amount int64
+	// ReasonCode is a key of AdjustmentReasons, and Reason the justification of the admin.
+	ReasonCode string
+	Reason     string
+}
+
+// AdjustBalance posts an entry of the ledger that corrects the balance of an account, and
+// notifies its owner of the reason code.
+func (operator *Operator) AdjustBalance(ctx context.Context, arg AdjustBalanceParams) (db.AdjustBalanceTxResult, error) {
+	if arg.Amount == 0 {
+		return db.AdjustBalanceTxResult{}, errors.New("amount must not be zero")
+	}
+	label, ok := AdjustmentReasons[arg.ReasonCode]
+	if !ok {
+		return db.AdjustBalanceTxResult{}, fmt.Errorf("%w %q", ErrInvalidReasonCode, arg.ReasonCode)
+	}
+	if _, err := operator.store.GetAccount(ctx, arg.AccountID); err != nil {
+		return db.AdjustBalanceTxResult{}, err
+	}
+	audit, err := operator.audit(ActionAdjustBalance, AccountTarget(arg.AccountID), arg.Reason, map[string]any{
+		"amount":      arg.Amount,
+		"reason_code": arg.ReasonCode,
+	})
+	if err != nil {
+		return db.AdjustBalanceTxResult{}, err
+	}
+
+	return operator.store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{
+		AccountID: arg.AccountID,
+		Amount:    arg.Amount,
+		Audit:     audit,
+		AfterAdjust: func(result db.AdjustBalanceTxResult) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewBalanceAdjustedTask(result, label)
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		},
+	})
+}
+
+func (operator *Operator) ReissueVerificationEmail(ctx context.Context, username, reason string) error {
+	if _, err := operator.store.GetUser(ctx, username); err != nil {
+		return err
+	}
+	audit, err := operator.audit(ActionReissueVerificationEmail, UserTarget(username), reason, nil)
+	if err != nil {
+		return err
+	}
+
+	task, err := worker.NewSendVerifyEmailTask(&worker.PayloadSendVerifyEmail{Username: username})
+	if err != nil {
+		return err
+	}
+	return operator.store.CreateOutboxTasksTx(ctx, db.CreateOutboxTasksTxParams{
+		OutboxTasks: []db.CreateOutboxTaskParams{task},
+		Audit:       audit,
+	})
+}
+
+// RevokeSessions blocks every session of a user. Their access tokens stay valid until they
+// expire, but can't be renewed.
+func (operator *Operator) RevokeSessions(ctx context.Context, username, reason string) error {
+	if _, err := operator.store.GetUser(ctx, username); err != nil {
+		return err
+	}
+	audit, err := operator.audit(ActionRevokeSessions, UserTarget(username), reason, nil)
+	if err != nil {
+		return err
+	}
+
+	return operator.store.RevokeSessionsTx(ctx, db.RevokeSessionsTxParams{
+		Username: username,
+		Audit:    audit,
+	})
+}
+
+// Impersonate mints an access token of tokenMaker acting as a user for duration, e.g. to debug
+// a support ticket. The token carries the operator as its impersonator, and its id is the id of
+// the impersonation, which RevokeImpersonation revokes.
+func (operator *Operator) Impersonate(ctx context.Context, tokenMaker token.Maker, username, reason string, duration time.Duration) (string, db.Impersonation, error) {
+	user, err := operator.store.GetUser(ctx, username)
+	if err != nil {
+		return "", db.Impersonation{}, err
+	}
+	if user.Role == util.AdminRole {
+		return "", db.Impersonation{}, ErrImpersonateAdmin
+	}
+
+	accessToken, payload, err := tokenMaker.CreateImpersonationToken(user.Username, user.Role, user.TenantID, preferences.Locale(user.Preferences), operator.actor, duration)
+	if err != nil {
+		return "", db.Impersonation{}, err
+	}
+	audit, err := operator.audit(ActionImpersonateUser, UserTarget(username), reason, map[string]any{
+		"impersonation_id": payload.ID,
+		"expires_at":       payload.ExpiredAt,
+	})
+	if err != nil {
+		return "", db.Impersonation{}, err
+	}
+
+	impersonation, err := operator.store.CreateImpersonationTx(ctx, db.CreateImpersonationTxParams{
+		CreateImpersonationParams: db.CreateImpersonationParams{
+			ID:        payload.ID,
+			Admin:     operator.actor,
+			Username:  user.Username,
+			Reason:    reason,
+			ExpiresAt: payload.ExpiredAt,
+		},
+		Audit: audit,
+	})
+	if err != nil {
+		return "", db.Impersonation{}, err
+	}
+	return accessToken, impersonation, nil
+}
+
+// RevokeImpersonation revokes an impersonation, whose token is refused from then on.
+func (operator *Operator) RevokeImpersonation(ctx context.Context, id uuid.UUID, reason string) (db.Impersonation, error) {
+	impersonation, err := operator.store.GetImpersonation(ctx, id)
+	if err != nil {
+		return db.Impersonation{}, err
+	}
+	audit, err := operator.audit(ActionRevokeImpersonation, UserTarget(impersonation.Username), reason, map[string]any{
+		"impersonation_id": id,
+	})
+	if err != nil {
+		return db.Impersonation{}, err
+	}
+
+	return operator.store.RevokeImpersonationTx(ctx, db.RevokeImpersonationTxParams{
+		ID:    id,
+		Audit: audit,
+	})
+}
+
+// AuditLog returns the last limit audit entries of target, newest first.
+func (operator *Operator) AuditLog(ctx context.Context, target string, limit int32) ([]db.AuditEntry, error) {
+	return operator.store.ListAuditEntries(ctx, db.ListAuditEntriesParams{
+		Target: target,
+		Limit:  limit,
+	})
+}
+
+// SetDisputeStatus moves a dispute to investigating, or closes it as resolved, or as refunded
+// with a reversal of the disputed transfer. The reason is also the resolution the user sees.
+func (operator *Operator) SetDisputeStatus(ctx context.Context, id int64, status, reason string) (db.SetDisputeStatusTxResult, error) {
+	dispute, err := operator.store.GetDispute(ctx, id)
+	if err != nil {
+		return db.SetDisputeStatusTxResult{}, err
+	}
+	audit, err := operator.audit(ActionSetDisputeStatus, AccountTarget(dispute.AccountID), reason, map[string]any{
+		"dispute_id":  dispute.ID,
+		"transfer_id": dispute.TransferID,
+		"status":      status,
+	})
+	if err != nil {
+		return db.SetDisputeStatusTxResult{}, err
+	}
+
+	return operator.store.SetDisputeStatusTx(ctx, db.SetDisputeStatusTxParams{
+		ID:         dispute.ID,
+		Status:     status,
+		Resolution: reason,
+		Admin:      operator.actor,
+		Audit:      audit,
+	})
+}
+
+// ReviewTransfer approves a transfer the fraud rules held, which runs it, or rejects it. The
+// reason is also the note the user sees on the review.
+func (operator *Operator) ReviewTransfer(ctx context.Context, id int64, status, reason string) (db.CloseTransferReviewTxResult, error) {
+	review, err := operator.store.GetTransferReview(ctx, id)
+	if err != nil {
+		return db.CloseTransferReviewTxResult{}, err
+	}
+	audit, err := operator.audit(ActionReviewTransfer, AccountTarget(review.FromAccountID), reason, map[string]any{
+		"review_id": review.ID,
+		"rules":     review.Rules,
+		"status":    status,
+	})
+	if err != nil {
+		return db.CloseTransferReviewTxResult{}, err
+	}
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        review.Amount,
+	})
+	if err != nil {
+		return db.CloseTransferReviewTxResult{}, err
+	}
+
+	return operator.store.CloseTransferReviewTx(ctx, db.CloseTransferReviewTxParams{
+		ID:          review.ID,
+		Status:      status,
+		ReviewedBy:  operator.actor,
+		Note:        reason,
+		OutboxTasks: []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:   worker.NewAccountAlertTask,
+		RewardTask:  worker.NewAccrueRewardsTask,
+		Audit:       &audit,
+	})
+}
+
+// AddDenylistEntry adds a name, an email or an account id to the denylist, holding the users it
+// matches. The reason, e.g. the sanctions list of the entry, is kept with it.
+func (operator *Operator) AddDenylistEntry(ctx context.Context, kind, value, reason string) (db.AddDenylistEntryTxResult, error) {
+	value = strings.TrimSpace(value)
+	switch kind {
+	case db.DenylistName:
+		if value == "" {
+			return db.AddDenylistEntryTxResult{}, fmt.Errorf("%w: the name is empty", ErrInvalidDenylistEntry)
+		}
+	case db.DenylistEmail:
+		if err := val.ValidateEmail(value); err != nil {
+			return db.AddDenylistEntryTxResult{}, fmt.Errorf("%w: %s", ErrInvalidDenylistEntry, err)
+		}
+	case db.DenylistAccount:
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return db.AddDenylistEntryTxResult{}, fmt.Errorf("%w: %q is not an account id", ErrInvalidDenylistEntry, value)
+		}
+		value = strconv.FormatInt(id, 10)
+	default:
+		return db.AddDenylistEntryTxResult{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidDenylistEntry, kind)
+	}
+
+	entry := db.DenylistEntry{Kind: kind, Value: value}
+	audit, err := operator.audit(ActionAddDenylistEntry, DenylistTarget(entry), reason, nil)
+	if err != nil {
+		return db.AddDenylistEntryTxResult{}, err
+	}
+
+	return operator.store.AddDenylistEntryTx(ctx, db.AddDenylistEntryTxParams{
+		CreateDenylistEntryParams: db.CreateDenylistEntryParams{
+			Kind:      kind,
+			Value:     value,
+			Reason:    reason,
+			CreatedBy: operator.actor,
+		},
+		Audit: audit,
+	})
+}
+
+// RemoveDenylistEntry removes an entry from the denylist, e.g. one taken off its sanctions list.
+// The holds it caused are cleared separately.
+func (operator *Operator) RemoveDenylistEntry(ctx context.Context, id int64, reason string) (db.DenylistEntry, error) {
+	entry, err := operator.store.GetDenylistEntry(ctx, id)
+	if err != nil {
+		return db.DenylistEntry{}, err
+	}
+	audit, err := operator.audit(ActionRemoveDenylistEntry, DenylistTarget(entry), reason, map[string]any{
+		"entry_id": entry.ID,
+	})
+	if err != nil {
+		return db.DenylistEntry{}, err
+	}
+
+	return operator.store.DeleteDenylistEntryTx(ctx, db.DeleteDenylistEntryTxParams{
+		ID:    entry.ID,
+		Audit: audit,
+	})
+}
+
+// SetScreeningHold clears the screening hold of a user, e.g. a namesake of a sanctioned person,
+// or confirms the match, which keeps their transfers held.
+func (operator *Operator) SetScreeningHold(ctx context.Context, id int64, status, reason string) (db.ScreeningHold, error) {
+	hold, err := operator.store.GetScreeningHold(ctx, id)
+	if err != nil {
+		return db.ScreeningHold{}, err
+	}
+	audit, err := operator.audit(ActionSetScreeningHold, UserTarget(hold.Username), reason, map[string]any{
+		"hold_id": hold.ID,
+		"matches": hold.Matches,
+		"status":  status,
+	})
+	if err != nil {
+		return db.ScreeningHold{}, err
+	}
+
+	return operator.store.SetScreeningHoldStatusTx(ctx, db.SetScreeningHoldStatusTxParams{
+		ID:     hold.ID,
+		Status: status,
+		Note:   reason,
+		Admin:  operator.actor,
+		Audit:  audit,
+	})
+}
+
+type CreateLoanOfferParams struct {
+	Name      string
+	Currency  string
+	MinAmount int64
+	MaxAmount int64
+	// AnnualRateBps is the annual interest rate, in hundredths of a percent.
+	AnnualRateBps int32
+	TermMonths    int32
+	Reason        string
+}
+
+// CreateLoanOffer offers the users to borrow between MinAmount and MaxAmount at a fixed rate,
+// repaid in monthly installments over the term.
+func (operator *Operator) CreateLoanOffer(ctx context.Context, arg CreateLoanOfferParams) (db.LoanOffer, error) {
+	arg.Name = strings.TrimSpace(arg.Name)
+	switch {
+	case arg.Name == "":
+		return db.LoanOffer{}, fmt.Errorf("%w: the name is empty", ErrInvalidLoanOffer)
+	case !util.IsSupportedCurrency(arg.Currency):
+		return db.LoanOffer{}, fmt.Errorf("%w: unsupported currency %q", ErrInvalidLoanOffer, arg.Currency)
+	case arg.MinAmount <= 0 || arg.MaxAmount < arg.MinAmount:
+		return db.LoanOffer{}, fmt.Errorf("%w: the amounts must be positive, the minimum at most the maximum", ErrInvalidLoanOffer)
+	case arg.AnnualRateBps < 0 || arg.AnnualRateBps > MaxLoanRateBps:
+		return db.LoanOffer{}, fmt.Errorf("%w: the rate must be between 0 and %d basis points", ErrInvalidLoanOffer, MaxLoanRateBps)
+	case arg.TermMonths <= 0 || arg.TermMonths > MaxLoanTermMonths:
+		return db.LoanOffer{}, fmt.Errorf("%w: the term must be between 1 and %d months", ErrInvalidLoanOffer, MaxLoanTermMonths)
+	}
+	audit, err := operator.audit(ActionCreateLoanOffer, LoanOfferTarget(arg.Name), arg.Reason, map[string]any{
+		"currency":        arg.Currency,
+		"min_amount":      arg.MinAmount,
+		"max_amount":      arg.MaxAmount,
+		"annual_rate_bps": arg.AnnualRateBps,
+		"term_months":     arg.TermMonths,
+	})
+	if err != nil {
+		return db.LoanOffer{}, err
+	}
+
+	return operator.store.CreateLoanOfferTx(ctx, db.CreateLoanOfferTxParams{
+		CreateLoanOfferParams: db.CreateLoanOfferParams{
+			Name:          arg.Name,
+			Currency:      arg.Currency,
+			MinAmount:     arg.MinAmount,
+			MaxAmount:     arg.MaxAmount,
+			AnnualRateBps: arg.AnnualRateBps,
+			TermMonths:    arg.TermMonths,
+			CreatedBy:     operator.actor,
+		},
+		Audit: audit,
+	})
+}
+
+// SetLoanOfferActive retires a loan offer, which no new loan can be disbursed under, or offers it
+// again.
+func (operator *Operator) SetLoanOfferActive(ctx context.Context, id int64, active bool, reason string) (db.LoanOffer, error) {
+	offer, err := operator.store.GetLoanOffer(ctx, id)
+	if err != nil {
+		return db.LoanOffer{}, err
+	}
+	audit, err := operator.audit(ActionSetLoanOfferActive, LoanOfferTarget(offer.Name), reason, map[string]any{
+		"offer_id": offer.ID,
+		"active":   active,
+	})
+	if err != nil {
+		return db.LoanOffer{}, err
+	}
+
+	return operator.store.SetLoanOfferActiveTx(ctx, db.SetLoanOfferActiveTxParams{
+		SetLoanOfferActiveParams: db.SetLoanOfferActiveParams{
+			ID:     offer.ID,
+			Active: active,
+		},
+		Audit: audit,
+	})
+}
+
+type CreateTenantParams struct {
+	// ID is what the users name the tenant by when they sign up, e.g. acme.
+	ID         string
+	Name       string
+	Currencies []string
+	// MaxTransferAmount caps the amount of a transfer, 0 for no cap.
+	MaxTransferAmount int64
+	// EmailSenderName is the sender of the emails to the users, Name when empty.
+	EmailSenderName string
+	SupportEmail    string
+	Reason          string
+}
+
+// CreateTenant opens the bank to another tenant, whose users only ever see each other's accounts.
+func (operator *Operator) CreateTenant(ctx context.Context, arg CreateTenantParams) (db.Tenant, error) {
+	arg.Name = strings.TrimSpace(arg.Name)
+	arg.EmailSenderName = strings.TrimSpace(arg.EmailSenderName)
+	if arg.EmailSenderName == "" {
+		arg.EmailSenderName = arg.Name
+	}
+	switch {
+	case !isTenantID(arg.ID):
+		return db.Tenant{}, fmt.Errorf("%w: the id must be 1 to 32 lowercase letters and digits", ErrInvalidTenant)
+	case arg.Name == "":
+		return db.Tenant{}, fmt.Errorf("%w: the name is empty", ErrInvalidTenant)
+	}
+	if err := validateTenantTerms(arg.Currencies, arg.MaxTransferAmount, arg.SupportEmail); err != nil {
+		return db.Tenant{}, err
+	}
+	audit, err := operator.audit(ActionCreateTenant, TenantTarget(arg.ID), arg.Reason, map[string]any{
+		"name":                arg.Name,
+		"currencies":          arg.Currencies,
+		"max_transfer_amount": arg.MaxTransferAmount,
+	})
+	if err != nil {
+		return db.Tenant{}, err
+	}
+
+	return operator.store.CreateTenantTx(ctx, db.CreateTenantTxParams{
+		CreateTenantParams: db.CreateTenantParams{
+			ID:                arg.ID,
+			Name:              arg.Name,
+			Currencies:        arg.Currencies,
+			MaxTransferAmount: arg.MaxTransferAmount,
+			EmailSenderName:   arg.EmailSenderName,
+			SupportEmail:      arg.SupportEmail,
+		},
+		Audit: audit,
+	})
+}
+
+// UpdateTenantParams change the terms of a tenant that are set, and keep the others.
+type UpdateTenantParams struct {
+	ID                string
+	Name              *string
+	Currencies        []string
+	MaxTransferAmount *int64
+	EmailSenderName   *string
+	SupportEmail      *string
+	Reason            string
+}
+
+// UpdateTenant changes the terms of a tenant. They apply to the transfers and the emails from
+// then on, and to the accounts opened from then on.
+func (operator *Operator) UpdateTenant(ctx context.Context, arg UpdateTenantParams) (db.Tenant, error) {
+	tenant, err := operator.store.GetTenant(ctx, arg.ID)
+	if err != nil {
+		return db.Tenant{}, err
+	}
+
+	params := db.UpdateTenantParams{ID: tenant.ID}
+	details := map[string]any{}
+	if arg.Name != nil {
+		name := strings.TrimSpace(*arg.Name)
+		if name == "" {
+			return db.Tenant{}, fmt.Errorf("%w: the name is empty", ErrInvalidTenant)
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+		details["name"] = name
+	}
+	if arg.Currencies != nil {
+		tenant.Currencies = arg.Currencies
+		params.Currencies = arg.Currencies
+		details["currencies"] = arg.Currencies
+	}
+	if arg.MaxTransferAmount != nil {
+		tenant.MaxTransferAmount = *arg.MaxTransferAmount
+		params.MaxTransferAmount = pgtype.Int8{Int64: *arg.MaxTransferAmount, Valid: true}
+		details["max_transfer_amount"] = *arg.MaxTransferAmount
+	}
+	if arg.EmailSenderName != nil {
+		params.EmailSenderName = pgtype.Text{String: strings.TrimSpace(*arg.EmailSenderName), Valid: true}
+		details["email_sender_name"] = params.EmailSenderName.String
+	}
+	if arg.SupportEmail != nil {
+		tenant.SupportEmail = *arg.SupportEmail
+		params.SupportEmail = pgtype.Text{String: *arg.SupportEmail, Valid: true}
+		details["support_email"] = *arg.SupportEmail
+	}
+	if err := validateTenantTerms(tenant.Currencies, tenant.MaxTransferAmount, tenant.SupportEmail); err != nil {
+		return db.Tenant{}, err
+	}
+	audit, err := operator.audit(ActionUpdateTenant, TenantTarget(tenant.ID), arg.Reason, details)
+	if err != nil {
+		return db.Tenant{}, err
+	}
+
+	return operator.store.UpdateTenantTx(ctx, db.UpdateTenantTxParams{
+		UpdateTenantParams: params,
+		Audit:              audit,
+	})
+}
+
+func isTenantID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateTenantTerms returns ErrInvalidTenant unless the tenant offers supported currencies, caps
+// the transfers at a positive amount if at all, and has a valid support email if any.
+func validateTenantTerms(currencies []string, maxTransferAmount int64, supportEmail string) error {
+	if len(currencies) == 0 {
+		return fmt.Errorf("%w: no currency is offered", ErrInvalidTenant)
+	}
+	for _, currency := range currencies {
+		if !util.IsSupportedCurrency(currency) {
+			return fmt.Errorf("%w: unsupported currency %q", ErrInvalidTenant, currency)
+		}
+	}
+	if maxTransferAmount < 0 {
+		return fmt.Errorf("%w: the transfer cap is negative", ErrInvalidTenant)
+	}
+	if supportEmail != "" {
+		if err := val.ValidateEmail(supportEmail); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTenant, err)
+		}
+	}
+	return nil
+}
+
+// SetCurrencyEnabled offers a currency of the registry, which tenants can then offer their users,
+// or stops offering it. A currency can't be disabled while a tenant offers it.
+func (operator *Operator) SetCurrencyEnabled(ctx context.Context, code string, enabled bool, reason string) (db.Currency, error) {
+	currency, err := operator.store.GetCurrency(ctx, code)
+	if err != nil {
+		return db.Currency{}, err
+	}
+	if !enabled {
+		tenants, err := operator.store.ListTenants(ctx)
+		if err != nil {
+			return db.Currency{}, err
+		}
+		for _, tenant := range tenants {
+			if tenant.OffersCurrency(currency.Code) {
+				return db.Currency{}, fmt.Errorf("%w: %s offers %s", ErrCurrencyInUse, tenant.ID, currency.Code)
+			}
+		}
+	}
+	audit, err := operator.audit(ActionSetCurrencyEnabled, CurrencyTarget(currency.Code), reason, map[string]any{
+		"enabled": enabled,
+	})
+	if err != nil {
+		return db.Currency{}, err
+	}
+
+	return operator.store.SetCurrencyEnabledTx(ctx, db.SetCurrencyEnabledTxParams{
+		SetCurrencyEnabledParams: db.SetCurrencyEnabledParams{
+			Code:    currency.Code,
+			Enabled: enabled,
+		},
+		Audit: audit,
+	})
+}
+
+// MaxUserImportBytes is the size of the largest file of a user import, some 100k users.
+const MaxUserImportBytes = 16 << 20
+
+type ImportUsersParams struct {
+	// Format is userimport.FormatCSV or userimport.FormatNDJSON.
+	Format  string
+	Content []byte
+	Reason  string
+}
+
+// ImportUsers imports the users of a file of a legacy system. The worker creates them row by row,
+// each like a signup, and reports the rows it couldn't import in the rows of the import.
+func (operator *Operator) ImportUsers(ctx context.Context, arg ImportUsersParams) (db.UserImport, error) {
+	switch {
+	case arg.Format != userimport.FormatCSV && arg.Format != userimport.FormatNDJSON:
+		return db.UserImport{}, fmt.Errorf("%w: unknown format %q", ErrInvalidUserImport, arg.Format)
+	case len(bytes.TrimSpace(arg.Content)) == 0:
+		return db.UserImport{}, fmt.Errorf("%w: the file is empty", ErrInvalidUserImport)
+	case len(arg.Content) > MaxUserImportBytes:
+		return db.UserImport{}, fmt.Errorf("%w: the file is larger than %d bytes", ErrInvalidUserImport, MaxUserImportBytes)
+	}
+	audit, err := operator.audit(ActionImportUsers, UserImportTarget, arg.Reason, map[string]any{
+		"format": arg.Format,
+		"bytes":  len(arg.Content),
+	})
+	if err != nil {
+		return db.UserImport{}, err
+	}
+
+	return operator.store.CreateUserImportTx(ctx, db.CreateUserImportTxParams{
+		CreateUserImportParams: db.CreateUserImportParams{
+			CreatedBy: operator.actor,
+			Format:    arg.Format,
+		},
+		Content: arg.Content,
+		Audit:   audit,
+		AfterCreate: func(userImport db.UserImport) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewImportUsersTask(&worker.PayloadImportUsers{ImportID: userImport.ID})
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		},
+	})
+}
+
+var legalDocumentVersion = regexp.MustCompile(`^[A-Za-z0-9._-]{1,32}$`)
+
+type PublishLegalDocumentParams struct {
+	// Kind is db.LegalTerms or db.LegalPrivacy.
+	Kind    string
+	Version string
+	// URL is where the users read the version.
+	URL    string
+	Reason string
+}
+
+// PublishLegalDocument publishes a version of the terms of service or of the privacy policy. It
+// becomes the latest of its kind, which the users must accept before their next sensitive
+// operation.
+func (operator *Operator) PublishLegalDocument(ctx context.Context, arg PublishLegalDocumentParams) (db.LegalDocument, error) {
+	switch {
+	case arg.Kind != db.LegalTerms && arg.Kind != db.LegalPrivacy:
+		return db.LegalDocument{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidLegalDocument, arg.Kind)
+	case !legalDocumentVersion.MatchString(arg.Version):
+		return db.LegalDocument{}, fmt.Errorf("%w: the version must be 1 to 32 letters, digits, dots, dashes or underscores", ErrInvalidLegalDocument)
+	}
+	link, err := url.Parse(arg.URL)
+	if err != nil || (link.Scheme != "https" && link.Scheme != "http") || link.Host == "" {
+		return db.LegalDocument{}, fmt.Errorf("%w: the url must be an http or https url", ErrInvalidLegalDocument)
+	}
+	audit, err := operator.audit(ActionPublishLegalDocument, LegalDocumentTarget(arg.Kind, arg.Version), arg.Reason, map[string]any{
+		"url": arg.URL,
+	})
+	if err != nil {
+		return db.LegalDocument{}, err
+	}
+
+	return operator.store.CreateLegalDocumentTx(ctx, db.CreateLegalDocumentTxParams{
+		CreateLegalDocumentParams: db.CreateLegalDocumentParams{
+			Kind:      arg.Kind,
+			Version:   arg.Version,
+			Url:       arg.URL,
+			CreatedBy: operator.actor,
+		},
+		Audit: audit,
+	})
+}
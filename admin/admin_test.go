@@ -0,0 +1,1082 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOperator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := NewOperator(mockdb.NewMockStore(ctrl), " ")
+	require.Error(t, err)
+
+	operator, err := NewOperator(mockdb.NewMockStore(ctrl), "alice")
+	require.NoError(t, err)
+	require.Equal(t, "alice", operator.actor)
+}
+
+func TestOperations(t *testing.T) {
+	const actor = "alice"
+	username := util.RandomOwnerName()
+	accountID := util.RandomInt(1, 1000)
+	impersonationID := uuid.New()
+	disputeID := util.RandomInt(1, 1000)
+	reviewID := util.RandomInt(1, 1000)
+	entryID := util.RandomInt(1, 1000)
+	holdID := util.RandomInt(1, 1000)
+	offerID := util.RandomInt(1, 1000)
+	tokenMaker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		run        func(ctx context.Context, operator *Operator) error
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "CreateAdminUser",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+						require.Equal(t, username, arg.Username)
+						require.NoError(t, util.CheckPassword("secret", arg.HashedPassword))
+						require.Equal(t, util.AdminRole, arg.Role)
+						require.NotNil(t, arg.AfterCreate)
+						require.NotNil(t, arg.Screen)
+						require.Equal(t, db.CreateAuditEntryParams{
+							Actor:   actor,
+							Action:  ActionCreateAdminUser,
+							Target:  UserTarget(username),
+							Reason:  "on call",
+							Details: []byte("{}"),
+						}, *arg.Audit)
+						return db.CreateUserTxResult{User: db.User{Username: arg.Username, Role: arg.Role}}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				user, err := operator.CreateAdminUser(ctx, CreateAdminUserParams{
+					Username: username,
+					Password: "secret",
+					FullName: "Alice Admin",
+					Email:    util.RandomEmail(),
+					Reason:   "on call",
+				})
+				require.Equal(t, util.AdminRole, user.Role)
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "CreateAdminUserInvalidUsername",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.CreateAdminUser(ctx, CreateAdminUserParams{
+					Username: "Not Valid",
+					Password: "secret",
+					FullName: "Alice Admin",
+					Email:    util.RandomEmail(),
+					Reason:   "on call",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "FreezeAccount",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), gomock.Eq(db.FreezeAccountTxParams{
+						AccountID: accountID,
+						Audit: db.CreateAuditEntryParams{
+							Actor:   actor,
+							Action:  ActionFreezeAccount,
+							Target:  AccountTarget(accountID),
+							Reason:  "chargeback fraud",
+							Details: []byte("{}"),
+						},
+					})).
+					Times(1).
+					Return(db.Account{ID: accountID}, nil)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.FreezeAccount(ctx, accountID, "chargeback fraud")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "FreezeAccountWithoutReason",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.FreezeAccount(ctx, accountID, "  ")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "AdjustBalance",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(accountID)).
+					Times(1).
+					Return(db.Account{ID: accountID}, nil)
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+						require.Equal(t, accountID, arg.AccountID)
+						require.Equal(t, int64(-500), arg.Amount)
+						require.Equal(t, ActionAdjustBalance, arg.Audit.Action)
+						require.Equal(t, AccountTarget(accountID), arg.Audit.Target)
+						require.JSONEq(t, `{"amount":-500,"reason_code":"duplicate_transaction"}`, string(arg.Audit.Details))
+
+						// the owner is notified of the reason code, not of the justification
+						result := db.AdjustBalanceTxResult{
+							Account: db.Account{ID: accountID, Owner: username, Currency: util.USD},
+							Entry:   db.Entry{AccountID: accountID, Amount: arg.Amount},
+						}
+						tasks, err := arg.AfterAdjust(result)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						var payload worker.PayloadSendNotification
+						require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+						require.Equal(t, username, payload.Username)
+						require.Equal(t, AdjustmentReasons["duplicate_transaction"], payload.Data["reason"])
+						return result, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AdjustBalance(ctx, AdjustBalanceParams{
+					AccountID:  accountID,
+					Amount:     -500,
+					ReasonCode: "duplicate_transaction",
+					Reason:     "duplicate deposit, ticket 1234",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AdjustBalanceByZero",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AdjustBalance(ctx, AdjustBalanceParams{
+					AccountID:  accountID,
+					ReasonCode: "duplicate_transaction",
+					Reason:     "duplicate deposit",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "AdjustBalanceInvalidReasonCode",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AdjustBalance(ctx, AdjustBalanceParams{
+					AccountID:  accountID,
+					Amount:     500,
+					ReasonCode: "because",
+					Reason:     "duplicate deposit",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidReasonCode)
+			},
+		},
+		{
+			name: "AdjustBalanceAccountNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(accountID)).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AdjustBalance(ctx, AdjustBalanceParams{
+					AccountID:  accountID,
+					Amount:     500,
+					ReasonCode: "bank_error",
+					Reason:     "missing deposit",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "ReissueVerificationEmail",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username}, nil)
+				store.EXPECT().
+					CreateOutboxTasksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateOutboxTasksTxParams) error {
+						require.Len(t, arg.OutboxTasks, 1)
+						require.Equal(t, worker.TaskSendVerifyEmail, arg.OutboxTasks[0].TaskType)
+						var payload worker.PayloadSendVerifyEmail
+						require.NoError(t, json.Unmarshal(arg.OutboxTasks[0].Payload, &payload))
+						require.Equal(t, username, payload.Username)
+						require.Equal(t, ActionReissueVerificationEmail, arg.Audit.Action)
+						return nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				return operator.ReissueVerificationEmail(ctx, username, "email bounced")
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RevokeSessions",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username}, nil)
+				store.EXPECT().
+					RevokeSessionsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RevokeSessionsTxParams) error {
+						require.Equal(t, username, arg.Username)
+						require.Equal(t, ActionRevokeSessions, arg.Audit.Action)
+						require.Equal(t, UserTarget(username), arg.Audit.Target)
+						return nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				return operator.RevokeSessions(ctx, username, "stolen laptop")
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RevokeSessionsUserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					RevokeSessionsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				return operator.RevokeSessions(ctx, username, "stolen laptop")
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "Impersonate",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, Role: util.DepositorRole}, nil)
+				store.EXPECT().
+					CreateImpersonationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateImpersonationTxParams) (db.Impersonation, error) {
+						require.Equal(t, actor, arg.Admin)
+						require.Equal(t, username, arg.Username)
+						require.Equal(t, "ticket 1234", arg.Reason)
+						require.WithinDuration(t, time.Now().Add(10*time.Minute), arg.ExpiresAt, time.Second)
+						require.Equal(t, ActionImpersonateUser, arg.Audit.Action)
+						require.Equal(t, UserTarget(username), arg.Audit.Target)
+						require.Contains(t, string(arg.Audit.Details), arg.ID.String())
+						return db.Impersonation{ID: arg.ID, Admin: arg.Admin, Username: arg.Username}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				accessToken, impersonation, err := operator.Impersonate(ctx, tokenMaker, username, "ticket 1234", 10*time.Minute)
+				require.NoError(t, err)
+
+				payload, err := tokenMaker.VerifyToken(accessToken)
+				require.NoError(t, err)
+				require.Equal(t, impersonation.ID, payload.ID)
+				require.Equal(t, username, payload.Username)
+				require.Equal(t, actor, payload.Impersonator)
+				return nil
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ImpersonateAdmin",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(db.User{Username: username, Role: util.AdminRole}, nil)
+				store.EXPECT().
+					CreateImpersonationTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, _, err := operator.Impersonate(ctx, tokenMaker, username, "ticket 1234", 10*time.Minute)
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrImpersonateAdmin)
+			},
+		},
+		{
+			name: "RevokeImpersonation",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(impersonationID)).
+					Times(1).
+					Return(db.Impersonation{ID: impersonationID, Username: username}, nil)
+				store.EXPECT().
+					RevokeImpersonationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RevokeImpersonationTxParams) (db.Impersonation, error) {
+						require.Equal(t, impersonationID, arg.ID)
+						require.Equal(t, ActionRevokeImpersonation, arg.Audit.Action)
+						require.Equal(t, UserTarget(username), arg.Audit.Target)
+						return db.Impersonation{ID: arg.ID}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.RevokeImpersonation(ctx, impersonationID, "ticket closed")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SetDisputeStatus",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDispute(gomock.Any(), gomock.Eq(disputeID)).
+					Times(1).
+					Return(db.Dispute{ID: disputeID, TransferID: 7, AccountID: accountID, Status: db.DisputeInvestigating}, nil)
+				store.EXPECT().
+					SetDisputeStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetDisputeStatusTxParams) (db.SetDisputeStatusTxResult, error) {
+						require.Equal(t, disputeID, arg.ID)
+						require.Equal(t, db.DisputeRefunded, arg.Status)
+						require.Equal(t, "unauthorized payment", arg.Resolution)
+						require.Equal(t, actor, arg.Admin)
+						require.Equal(t, ActionSetDisputeStatus, arg.Audit.Action)
+						require.Equal(t, AccountTarget(accountID), arg.Audit.Target)
+						require.JSONEq(t, fmt.Sprintf(`{"dispute_id":%d,"transfer_id":7,"status":"refunded"}`, disputeID), string(arg.Audit.Details))
+						return db.SetDisputeStatusTxResult{}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetDisputeStatus(ctx, disputeID, db.DisputeRefunded, "unauthorized payment")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SetDisputeStatusWithoutReason",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDispute(gomock.Any(), gomock.Eq(disputeID)).
+					Times(1).
+					Return(db.Dispute{ID: disputeID, AccountID: accountID}, nil)
+				store.EXPECT().
+					SetDisputeStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetDisputeStatus(ctx, disputeID, db.DisputeResolved, " ")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "ReviewTransfer",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferReview(gomock.Any(), gomock.Eq(reviewID)).
+					Times(1).
+					Return(db.TransferReview{
+						ID:            reviewID,
+						FromAccountID: accountID,
+						ToAccountID:   accountID + 1,
+						Amount:        5000,
+						Rules:         []string{"new_payee_large_amount"},
+						Status:        db.ReviewPending,
+					}, nil)
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+						require.Equal(t, reviewID, arg.ID)
+						require.Equal(t, db.ReviewApproved, arg.Status)
+						require.Equal(t, actor, arg.ReviewedBy)
+						require.Equal(t, "called the owner, who confirmed", arg.Note)
+						require.Len(t, arg.OutboxTasks, 1)
+						require.NotNil(t, arg.AlertTask)
+						require.NotNil(t, arg.Audit)
+						require.Equal(t, ActionReviewTransfer, arg.Audit.Action)
+						require.Equal(t, AccountTarget(accountID), arg.Audit.Target)
+						require.JSONEq(t, fmt.Sprintf(`{"review_id":%d,"rules":["new_payee_large_amount"],"status":"approved"}`, reviewID), string(arg.Audit.Details))
+						return db.CloseTransferReviewTxResult{}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.ReviewTransfer(ctx, reviewID, db.ReviewApproved, "called the owner, who confirmed")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ReviewTransferNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTransferReview(gomock.Any(), gomock.Eq(reviewID)).
+					Times(1).
+					Return(db.TransferReview{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.ReviewTransfer(ctx, reviewID, db.ReviewRejected, "the owner didn't make it")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "AddDenylistEntry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddDenylistEntryTx(gomock.Any(), gomock.Eq(db.AddDenylistEntryTxParams{
+						CreateDenylistEntryParams: db.CreateDenylistEntryParams{
+							Kind:      db.DenylistName,
+							Value:     "John Doe",
+							Reason:    "sanctions list",
+							CreatedBy: actor,
+						},
+						Audit: db.CreateAuditEntryParams{
+							Actor:   actor,
+							Action:  ActionAddDenylistEntry,
+							Target:  "denylist:name:John Doe",
+							Reason:  "sanctions list",
+							Details: []byte("{}"),
+						},
+					})).
+					Times(1).
+					Return(db.AddDenylistEntryTxResult{}, nil)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AddDenylistEntry(ctx, db.DenylistName, " John Doe ", "sanctions list")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AddDenylistEntryAccount",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AddDenylistEntryTxParams) (db.AddDenylistEntryTxResult, error) {
+						require.Equal(t, db.DenylistAccount, arg.Kind)
+						require.Equal(t, "42", arg.Value)
+						require.Equal(t, "denylist:account:42", arg.Audit.Target)
+						return db.AddDenylistEntryTxResult{}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.AddDenylistEntry(ctx, db.DenylistAccount, "042", "mule account")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AddDenylistEntryInvalid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				for _, entry := range [][2]string{
+					{db.DenylistName, " "},
+					{db.DenylistEmail, "not an email"},
+					{db.DenylistAccount, "-1"},
+					{"phone", "555-0100"},
+				} {
+					_, err := operator.AddDenylistEntry(ctx, entry[0], entry[1], "sanctions list")
+					require.ErrorIs(t, err, ErrInvalidDenylistEntry)
+				}
+				return nil
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RemoveDenylistEntry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDenylistEntry(gomock.Any(), gomock.Eq(entryID)).
+					Times(1).
+					Return(db.DenylistEntry{ID: entryID, Kind: db.DenylistEmail, Value: "john@example.com"}, nil)
+				store.EXPECT().
+					DeleteDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DeleteDenylistEntryTxParams) (db.DenylistEntry, error) {
+						require.Equal(t, entryID, arg.ID)
+						require.Equal(t, ActionRemoveDenylistEntry, arg.Audit.Action)
+						require.Equal(t, "denylist:email:john@example.com", arg.Audit.Target)
+						require.JSONEq(t, fmt.Sprintf(`{"entry_id":%d}`, entryID), string(arg.Audit.Details))
+						return db.DenylistEntry{ID: arg.ID}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.RemoveDenylistEntry(ctx, entryID, "delisted")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SetScreeningHold",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScreeningHold(gomock.Any(), gomock.Eq(holdID)).
+					Times(1).
+					Return(db.ScreeningHold{
+						ID:       holdID,
+						Username: username,
+						Matches:  []string{"name:John Doe"},
+						Status:   db.HoldPending,
+					}, nil)
+				store.EXPECT().
+					SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetScreeningHoldStatusTxParams) (db.ScreeningHold, error) {
+						require.Equal(t, holdID, arg.ID)
+						require.Equal(t, db.HoldCleared, arg.Status)
+						require.Equal(t, "different date of birth", arg.Note)
+						require.Equal(t, actor, arg.Admin)
+						require.Equal(t, ActionSetScreeningHold, arg.Audit.Action)
+						require.Equal(t, UserTarget(username), arg.Audit.Target)
+						require.JSONEq(t, fmt.Sprintf(`{"hold_id":%d,"matches":["name:John Doe"],"status":"cleared"}`, holdID), string(arg.Audit.Details))
+						return db.ScreeningHold{ID: arg.ID, Status: arg.Status}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetScreeningHold(ctx, holdID, db.HoldCleared, "different date of birth")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SetScreeningHoldWithoutReason",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScreeningHold(gomock.Any(), gomock.Eq(holdID)).
+					Times(1).
+					Return(db.ScreeningHold{ID: holdID, Username: username}, nil)
+				store.EXPECT().
+					SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetScreeningHold(ctx, holdID, db.HoldConfirmed, "")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "CreateLoanOffer",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLoanOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoanOfferTxParams) (db.LoanOffer, error) {
+						require.Equal(t, db.CreateLoanOfferParams{
+							Name:          "Personal loan",
+							Currency:      util.USD,
+							MinAmount:     100000,
+							MaxAmount:     2000000,
+							AnnualRateBps: 899,
+							TermMonths:    36,
+							CreatedBy:     actor,
+						}, arg.CreateLoanOfferParams)
+						require.Equal(t, ActionCreateLoanOffer, arg.Audit.Action)
+						require.Equal(t, "loan_offer:Personal loan", arg.Audit.Target)
+						require.JSONEq(t, `{"currency":"USD","min_amount":100000,"max_amount":2000000,"annual_rate_bps":899,"term_months":36}`, string(arg.Audit.Details))
+						return db.LoanOffer{ID: offerID, Name: arg.Name, Active: true}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.CreateLoanOffer(ctx, CreateLoanOfferParams{
+					Name:          " Personal loan ",
+					Currency:      util.USD,
+					MinAmount:     100000,
+					MaxAmount:     2000000,
+					AnnualRateBps: 899,
+					TermMonths:    36,
+					Reason:        "spring campaign",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "CreateLoanOfferInvalid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLoanOfferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				valid := CreateLoanOfferParams{
+					Name:          "Personal loan",
+					Currency:      util.EUR,
+					MinAmount:     100,
+					MaxAmount:     1000,
+					AnnualRateBps: 500,
+					TermMonths:    12,
+					Reason:        "spring campaign",
+				}
+				for _, change := range []func(arg *CreateLoanOfferParams){
+					func(arg *CreateLoanOfferParams) { arg.Name = " " },
+					func(arg *CreateLoanOfferParams) { arg.Currency = "XYZ" },
+					func(arg *CreateLoanOfferParams) { arg.MinAmount = 0 },
+					func(arg *CreateLoanOfferParams) { arg.MaxAmount = 99 },
+					func(arg *CreateLoanOfferParams) { arg.AnnualRateBps = MaxLoanRateBps + 1 },
+					func(arg *CreateLoanOfferParams) { arg.TermMonths = 0 },
+				} {
+					arg := valid
+					change(&arg)
+					_, err := operator.CreateLoanOffer(ctx, arg)
+					require.ErrorIs(t, err, ErrInvalidLoanOffer)
+				}
+				return nil
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "SetLoanOfferActive",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLoanOffer(gomock.Any(), gomock.Eq(offerID)).
+					Times(1).
+					Return(db.LoanOffer{ID: offerID, Name: "Personal loan", Active: true}, nil)
+				store.EXPECT().
+					SetLoanOfferActiveTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetLoanOfferActiveTxParams) (db.LoanOffer, error) {
+						require.Equal(t, offerID, arg.ID)
+						require.False(t, arg.Active)
+						require.Equal(t, ActionSetLoanOfferActive, arg.Audit.Action)
+						require.JSONEq(t, fmt.Sprintf(`{"offer_id":%d,"active":false}`, offerID), string(arg.Audit.Details))
+						return db.LoanOffer{ID: arg.ID, Active: arg.Active}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetLoanOfferActive(ctx, offerID, false, "campaign over")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "CreateTenant",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTenantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTenantTxParams) (db.Tenant, error) {
+						require.Equal(t, db.CreateTenantParams{
+							ID:                "acme",
+							Name:              "Acme Bank",
+							Currencies:        []string{util.USD},
+							MaxTransferAmount: 500000,
+							EmailSenderName:   "Acme Bank",
+							SupportEmail:      "help@acme.com",
+						}, arg.CreateTenantParams)
+						require.Equal(t, ActionCreateTenant, arg.Audit.Action)
+						require.Equal(t, "tenant:acme", arg.Audit.Target)
+						require.JSONEq(t, `{"name":"Acme Bank","currencies":["USD"],"max_transfer_amount":500000}`, string(arg.Audit.Details))
+						return db.Tenant{ID: arg.ID, Name: arg.Name}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.CreateTenant(ctx, CreateTenantParams{
+					ID:                "acme",
+					Name:              " Acme Bank ",
+					Currencies:        []string{util.USD},
+					MaxTransferAmount: 500000,
+					SupportEmail:      "help@acme.com",
+					Reason:            "signed the white label contract",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "CreateTenantInvalid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTenantTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				valid := CreateTenantParams{
+					ID:         "acme",
+					Name:       "Acme Bank",
+					Currencies: []string{util.USD, util.EUR},
+					Reason:     "signed the white label contract",
+				}
+				for _, change := range []func(arg *CreateTenantParams){
+					func(arg *CreateTenantParams) { arg.ID = "Acme" },
+					func(arg *CreateTenantParams) { arg.ID = "" },
+					func(arg *CreateTenantParams) { arg.Name = " " },
+					func(arg *CreateTenantParams) { arg.Currencies = nil },
+					func(arg *CreateTenantParams) { arg.Currencies = []string{"XYZ"} },
+					func(arg *CreateTenantParams) { arg.MaxTransferAmount = -1 },
+					func(arg *CreateTenantParams) { arg.SupportEmail = "help" },
+				} {
+					arg := valid
+					change(&arg)
+					_, err := operator.CreateTenant(ctx, arg)
+					require.ErrorIs(t, err, ErrInvalidTenant)
+				}
+				return nil
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UpdateTenant",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTenant(gomock.Any(), gomock.Eq("acme")).
+					Times(1).
+					Return(db.Tenant{ID: "acme", Name: "Acme Bank", Currencies: []string{util.USD}}, nil)
+				store.EXPECT().
+					UpdateTenantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTenantTxParams) (db.Tenant, error) {
+						require.Equal(t, db.UpdateTenantParams{
+							ID:                "acme",
+							MaxTransferAmount: pgtype.Int8{Int64: 100000, Valid: true},
+						}, arg.UpdateTenantParams)
+						require.Equal(t, ActionUpdateTenant, arg.Audit.Action)
+						require.JSONEq(t, `{"max_transfer_amount":100000}`, string(arg.Audit.Details))
+						return db.Tenant{ID: arg.ID, MaxTransferAmount: arg.MaxTransferAmount.Int64}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				limit := int64(100000)
+				_, err := operator.UpdateTenant(ctx, UpdateTenantParams{
+					ID:                "acme",
+					MaxTransferAmount: &limit,
+					Reason:            "lower risk appetite",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UpdateTenantInvalid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTenant(gomock.Any(), gomock.Eq("acme")).
+					Times(1).
+					Return(db.Tenant{ID: "acme", Name: "Acme Bank", Currencies: []string{util.USD}}, nil)
+				store.EXPECT().
+					UpdateTenantTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.UpdateTenant(ctx, UpdateTenantParams{
+					ID:         "acme",
+					Currencies: []string{},
+					Reason:     "winding down",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidTenant)
+			},
+		},
+		{
+			name: "EnableCurrency",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCurrency(gomock.Any(), gomock.Eq("JPY")).
+					Times(1).
+					Return(db.Currency{Code: "JPY"}, nil)
+				store.EXPECT().ListTenants(gomock.Any()).Times(0)
+				store.EXPECT().
+					SetCurrencyEnabledTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetCurrencyEnabledTxParams) (db.Currency, error) {
+						require.Equal(t, db.SetCurrencyEnabledParams{Code: "JPY", Enabled: true}, arg.SetCurrencyEnabledParams)
+						require.Equal(t, ActionSetCurrencyEnabled, arg.Audit.Action)
+						require.Equal(t, "currency:JPY", arg.Audit.Target)
+						require.JSONEq(t, `{"enabled":true}`, string(arg.Audit.Details))
+						return db.Currency{Code: "JPY", Enabled: true}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetCurrencyEnabled(ctx, "JPY", true, "launch in Japan")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "DisableCurrencyInUse",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCurrency(gomock.Any(), gomock.Eq(util.CAD)).
+					Times(1).
+					Return(db.Currency{Code: util.CAD, Enabled: true}, nil)
+				store.EXPECT().
+					ListTenants(gomock.Any()).
+					Times(1).
+					Return([]db.Tenant{{ID: "acme", Currencies: []string{util.USD, util.CAD}}}, nil)
+				store.EXPECT().
+					SetCurrencyEnabledTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetCurrencyEnabled(ctx, util.CAD, false, "leaving Canada")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrCurrencyInUse)
+			},
+		},
+		{
+			name: "SetCurrencyNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCurrency(gomock.Any(), gomock.Eq("XXX")).
+					Times(1).
+					Return(db.Currency{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					SetCurrencyEnabledTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.SetCurrencyEnabled(ctx, "XXX", true, "typo")
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "ImportUsers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserImportTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserImportTxParams) (db.UserImport, error) {
+						require.Equal(t, db.CreateUserImportParams{CreatedBy: actor, Format: "csv"}, arg.CreateUserImportParams)
+						require.Equal(t, "username,full_name,email,password\n", string(arg.Content))
+						require.Equal(t, ActionImportUsers, arg.Audit.Action)
+						require.Equal(t, UserImportTarget, arg.Audit.Target)
+						require.JSONEq(t, `{"format": "csv", "bytes": 34}`, string(arg.Audit.Details))
+
+						userImport := db.UserImport{ID: 5, CreatedBy: actor, Format: "csv", Status: db.UserImportPending}
+						tasks, err := arg.AfterCreate(userImport)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskImportUsers, tasks[0].TaskType)
+						require.JSONEq(t, `{"import_id": 5}`, string(tasks[0].Payload))
+						return userImport, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.ImportUsers(ctx, ImportUsersParams{
+					Format:  "csv",
+					Content: []byte("username,full_name,email,password\n"),
+					Reason:  "migration of the legacy bank",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ImportUsersUnknownFormat",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserImportTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.ImportUsers(ctx, ImportUsersParams{
+					Format:  "xlsx",
+					Content: []byte("username"),
+					Reason:  "migration of the legacy bank",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidUserImport)
+			},
+		},
+		{
+			name: "PublishLegalDocument",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLegalDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLegalDocumentTxParams) (db.LegalDocument, error) {
+						require.Equal(t, db.CreateLegalDocumentParams{
+							Kind:      db.LegalTerms,
+							Version:   "2026-10",
+							Url:       "https://bank.example.com/terms/2026-10",
+							CreatedBy: actor,
+						}, arg.CreateLegalDocumentParams)
+						require.Equal(t, ActionPublishLegalDocument, arg.Audit.Action)
+						require.Equal(t, "legal_document:terms:2026-10", arg.Audit.Target)
+						return db.LegalDocument{Kind: arg.Kind, Version: arg.Version}, nil
+					})
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.PublishLegalDocument(ctx, PublishLegalDocumentParams{
+					Kind:    db.LegalTerms,
+					Version: "2026-10",
+					URL:     "https://bank.example.com/terms/2026-10",
+					Reason:  "new fees",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "PublishLegalDocumentInvalidURL",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLegalDocumentTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			run: func(ctx context.Context, operator *Operator) error {
+				_, err := operator.PublishLegalDocument(ctx, PublishLegalDocumentParams{
+					Kind:    db.LegalPrivacy,
+					Version: "2",
+					URL:     "javascript:alert(1)",
+					Reason:  "new processors",
+				})
+				return err
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInvalidLegalDocument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			operator, err := NewOperator(store, actor)
+			require.NoError(t, err)
+			tc.checkErr(t, tc.run(context.Background(), operator))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package amortization computes the repayment schedule of the loans: equal monthly installments,
+// each paying the interest of the month on the principal left and repaying the rest of it, the
+// last one repaying what the rounding left over.
+package amortization
+
+import (
+	"math"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// bpsPerUnit is the number of basis points in a rate of 1, i.e. 100%.
+const bpsPerUnit = 10000
+
+// Schedule returns the installments of a loan of principal under offer, the first one due a month
+// after start, then on the same day every month, or on the last day of the shorter months. Their
+// principals add up to principal.
+func Schedule(offer db.LoanOffer, principal int64, start time.Time) []db.CreateLoanInstallmentParams {
+	months := int(offer.TermMonths)
+	if months <= 0 || principal <= 0 {
+		return nil
+	}
+	rate := float64(offer.AnnualRateBps) / bpsPerUnit / 12
+	payment := Payment(principal, offer.AnnualRateBps, offer.TermMonths)
+
+	installments := make([]db.CreateLoanInstallmentParams, 0, months)
+	remaining := principal
+	for number := 1; number <= months; number++ {
+		interest := int64(math.Round(float64(remaining) * rate))
+		repaid := payment - interest
+		if number == months || repaid > remaining {
+			repaid = remaining
+		}
+		remaining -= repaid
+		installments = append(installments, db.CreateLoanInstallmentParams{
+			Number:    int32(number),
+			DueAt:     addMonths(start, number),
+			Principal: repaid,
+			Interest:  interest,
+			Amount:    repaid + interest,
+		})
+		if remaining == 0 {
+			break
+		}
+	}
+	return installments
+}
+
+// Payment returns the monthly installment repaying principal over the term at the annual rate, in
+// basis points, rounded to the minor unit.
+func Payment(principal int64, annualRateBps int32, termMonths int32) int64 {
+	if termMonths <= 0 {
+		return 0
+	}
+	n := float64(termMonths)
+	if annualRateBps == 0 {
+		return int64(math.Ceil(float64(principal) / n))
+	}
+	rate := float64(annualRateBps) / bpsPerUnit / 12
+	return int64(math.Round(float64(principal) * rate / (1 - math.Pow(1+rate, -n))))
+}
+
+// addMonths adds months to t, keeping its day unless the month is shorter, e.g. January 31 plus a
+// month is the last day of February rather than early March as time.AddDate has it.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	hour, minute, sec := t.Clock()
+	return time.Date(first.Year(), first.Month(), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
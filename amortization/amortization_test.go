@@ -0,0 +1,75 @@
+package amortization
+
+import (
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		offer     db.LoanOffer
+		principal int64
+		payment   int64
+	}{
+		{
+			name:      "Interest",
+			offer:     db.LoanOffer{AnnualRateBps: 1200, TermMonths: 12},
+			principal: 100000,
+			payment:   8885,
+		},
+		{
+			name:      "NoInterest",
+			offer:     db.LoanOffer{AnnualRateBps: 0, TermMonths: 3},
+			principal: 1000,
+			payment:   334,
+		},
+		{
+			name:      "LongTerm",
+			offer:     db.LoanOffer{AnnualRateBps: 699, TermMonths: 360},
+			principal: 25000000,
+			payment:   166158,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.payment, Payment(tc.principal, tc.offer.AnnualRateBps, tc.offer.TermMonths))
+
+			installments := Schedule(tc.offer, tc.principal, start)
+			require.Len(t, installments, int(tc.offer.TermMonths))
+
+			var repaid int64
+			for i, installment := range installments {
+				require.Equal(t, int32(i+1), installment.Number)
+				require.Equal(t, installment.Principal+installment.Interest, installment.Amount)
+				require.Positive(t, installment.Principal)
+				require.True(t, installment.DueAt.After(start))
+				if i < len(installments)-1 {
+					require.Equal(t, tc.payment, installment.Amount)
+				} else {
+					// the last installment takes what the rounding left over
+					require.InDelta(t, tc.payment, installment.Amount, float64(tc.offer.TermMonths))
+				}
+				repaid += installment.Principal
+			}
+			require.Equal(t, tc.principal, repaid)
+		})
+	}
+
+	// the installments fall on the same day every month, or the last day of shorter months
+	installments := Schedule(db.LoanOffer{AnnualRateBps: 500, TermMonths: 2}, 500, start)
+	require.Equal(t, time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC), installments[0].DueAt)
+	require.Equal(t, time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC), installments[1].DueAt)
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	require.Empty(t, Schedule(db.LoanOffer{AnnualRateBps: 500, TermMonths: 0}, 1000, time.Now()))
+	require.Empty(t, Schedule(db.LoanOffer{AnnualRateBps: 500, TermMonths: 12}, 0, time.Now()))
+}
@@ -1,29 +1,56 @@
 package api
 
 import (
-	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/pagination"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
 type createAccountRequest struct {
-	Currency string `json:"currency" binding:"required,currency"`
+	// Currency is the default currency of the preferences of the caller when empty.
+	Currency string `json:"currency" binding:"omitempty,currency"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.Currency == "" {
+		settings, err := server.userPreferences(ctx, payload.Username)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		if settings.DefaultCurrency == "" {
+			err := errors.New("currency is required without a default currency in the preferences")
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
+		}
+		req.Currency = settings.DefaultCurrency
+	}
+
+	tenant, err := server.store.GetTenant(ctx, payload.Tenant)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !tenant.OffersCurrency(req.Currency) {
+		err := fmt.Errorf("%s accounts aren't offered by %s", req.Currency, tenant.Name)
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return
+	}
 
 	arg := db.CreateAccountParams{
 		Owner:    payload.Username,
@@ -33,38 +60,52 @@ func (server *Server) createAccount(ctx *gin.Context) {
 
 	account, err := server.store.CreateAccount(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errResponse(err))
-				return
-			}
+		switch db.ErrorCode(err) {
+		case db.ForeignKeyViolation, db.UniqueViolation:
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	envelope.JSON(ctx, http.StatusOK, account)
 }
 
 type getAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+type getAccountQuery struct {
+	// DisplayCurrency is the currency to show the balance in as well, for display only.
+	DisplayCurrency string `form:"display_currency" binding:"omitempty,currency"`
+}
+
+type accountResponse struct {
+	db.Account
+	// DisplayBalance is the balance converted into display_currency at the latest rates.
+	DisplayBalance *fx.Conversion `json:"display_balance,omitempty"`
+}
+
 func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var query getAccountQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
 
@@ -72,37 +113,162 @@ func (server *Server) getAccount(ctx *gin.Context) {
 
 	if account.Owner != payload.Username {
 		err = errors.New("account doesn't belongs to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	rsp := accountResponse{Account: account}
+	if query.DisplayCurrency != "" {
+		conversion, err := server.rates.Convert(ctx, account.Balance, account.Currency, query.DisplayCurrency)
+		if err != nil {
+			ctx.JSON(exchangeErrStatus(err), errResponse(ctx, err))
+			return
+		}
+		rsp.DisplayBalance = &conversion
+	}
 
+	tag, err := etag(rsp, ctx.Query(envelope.FieldsParam))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	if notModified(ctx, tag) {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
 
 type listAccountRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	PageID   int32  `form:"page_id" binding:"omitempty,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+	Cursor   string `form:"cursor" binding:"excluded_with=PageID"`
+}
+
+type listAccountResponse struct {
+	Accounts []db.Account `json:"accounts"`
 }
 
+// listAccount pages through the accounts with a cursor, or with page_id through the plain array
+// of accounts it returned before cursors existed.
 func (server *Server) listAccount(ctx *gin.Context) {
 	var req listAccountRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	arg := db.ListAccountsParams{
-		Owner:  payload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	if req.PageID > 0 {
+		arg := db.ListAccountsParams{
+			Owner:  payload.Username,
+			Limit:  req.PageSize,
+			Offset: (req.PageID - 1) * req.PageSize,
+		}
+		account, err := server.store.ListAccounts(ctx, arg)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+
+		envelope.JSON(ctx, http.StatusOK, account)
+		return
+	}
+
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
 	}
-	account, err := server.store.ListAccounts(ctx, arg)
+	page, err := db.ListAccountsPage(ctx, server.store, payload.Username, cursor, req.PageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	envelope.Page(ctx, http.StatusOK, listAccountResponse{Accounts: page.Items}, page.NextCursor, page.PrevCursor)
+}
+
+type listAccountHistoryURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listAccountHistoryRequest struct {
+	Cursor   string `form:"cursor"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+type listEntriesResponse struct {
+	Entries []db.Entry `json:"entries"`
+	// Categories are those of the entries of the page that have one or tags.
+	Categories []db.EntryCategory `json:"categories"`
+}
+
+type listTransfersResponse struct {
+	Transfers []db.Transfer `json:"transfers"`
+}
+
+// bindAccountHistory binds the request of a list of the history of an account of the caller,
+// answering and returning false when it's invalid or the account isn't theirs.
+func (server *Server) bindAccountHistory(ctx *gin.Context) (int64, pagination.Cursor, int32, bool) {
+	var uri listAccountHistoryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return 0, pagination.Cursor{}, 0, false
+	}
+	var req listAccountHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return 0, pagination.Cursor{}, 0, false
+	}
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return 0, pagination.Cursor{}, 0, false
+	}
+	if _, ok := server.authorizeAccount(ctx, uri.ID); !ok {
+		return 0, pagination.Cursor{}, 0, false
+	}
+	return uri.ID, cursor, req.PageSize, true
+}
+
+func (server *Server) listAccountEntries(ctx *gin.Context) {
+	accountID, cursor, size, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	page, err := db.ListEntriesPage(ctx, server.store, accountID, cursor, size)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	entryIDs := make([]int64, len(page.Items))
+	for i, entry := range page.Items {
+		entryIDs[i] = entry.ID
+	}
+	categories, err := server.store.ListEntryCategories(ctx, entryIDs)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.Page(ctx, http.StatusOK, listEntriesResponse{
+		Entries:    page.Items,
+		Categories: categories,
+	}, page.NextCursor, page.PrevCursor)
+}
+
+func (server *Server) listAccountTransfers(ctx *gin.Context) {
+	accountID, cursor, size, ok := server.bindAccountHistory(ctx)
+	if !ok {
+		return
+	}
+
+	page, err := db.ListTransfersPage(ctx, server.store, accountID, cursor, size)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
 
+	envelope.Page(ctx, http.StatusOK, listTransfersResponse{Transfers: page.Items}, page.NextCursor, page.PrevCursor)
 }
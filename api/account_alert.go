@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type accountAlertResponse struct {
+	AccountID           int64  `json:"account_id"`
+	LowBalanceThreshold *int64 `json:"low_balance_threshold"`
+	LargeDebitThreshold *int64 `json:"large_debit_threshold"`
+}
+
+func newAccountAlertResponse(alert db.AccountAlert) accountAlertResponse {
+	rsp := accountAlertResponse{AccountID: alert.AccountID}
+	if alert.LowBalanceThreshold.Valid {
+		rsp.LowBalanceThreshold = &alert.LowBalanceThreshold.Int64
+	}
+	if alert.LargeDebitThreshold.Valid {
+		rsp.LargeDebitThreshold = &alert.LargeDebitThreshold.Int64
+	}
+	return rsp
+}
+
+type accountAlertURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// authorizeAccount answers and returns false unless the account exists and belongs to the caller.
+func (server *Server) authorizeAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return account, false
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return account, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != payload.Username {
+		err = errors.New("account doesn't belongs to authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return account, false
+	}
+	return account, true
+}
+
+func (server *Server) getAccountAlert(ctx *gin.Context) {
+	var uri accountAlertURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if _, ok := server.authorizeAccount(ctx, uri.ID); !ok {
+		return
+	}
+
+	alert, err := server.store.GetAccountAlert(ctx, uri.ID)
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		alert = db.AccountAlert{AccountID: uri.ID}
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newAccountAlertResponse(alert))
+}
+
+// updateAccountAlertRequest sets both thresholds, a missing or null one turns its alert off.
+type updateAccountAlertRequest struct {
+	LowBalanceThreshold *int64 `json:"low_balance_threshold" binding:"omitempty,gt=0"`
+	LargeDebitThreshold *int64 `json:"large_debit_threshold" binding:"omitempty,gt=0"`
+}
+
+func (server *Server) updateAccountAlert(ctx *gin.Context) {
+	var uri accountAlertURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req updateAccountAlertRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if _, ok := server.authorizeAccount(ctx, uri.ID); !ok {
+		return
+	}
+
+	arg := db.UpsertAccountAlertParams{AccountID: uri.ID}
+	if req.LowBalanceThreshold != nil {
+		arg.LowBalanceThreshold = pgtype.Int8{Int64: *req.LowBalanceThreshold, Valid: true}
+	}
+	if req.LargeDebitThreshold != nil {
+		arg.LargeDebitThreshold = pgtype.Int8{Int64: *req.LargeDebitThreshold, Valid: true}
+	}
+
+	alert, err := server.store.UpsertAccountAlert(ctx, arg)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newAccountAlertResponse(alert))
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountAlertAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	otherAccount := randomAccount("other")
+
+	testCases := []struct {
+		name          string
+		method        string
+		accountID     int64
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "GetNotSet",
+			method:    http.MethodGet,
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountAlert(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.AccountAlert{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp accountAlertResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, accountAlertResponse{AccountID: account.ID}, rsp)
+			},
+		},
+		{
+			name:      "Update",
+			method:    http.MethodPut,
+			accountID: account.ID,
+			body:      gin.H{"low_balance_threshold": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertAccountAlertParams{
+					AccountID:           account.ID,
+					LowBalanceThreshold: pgtype.Int8{Int64: 100, Valid: true},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpsertAccountAlert(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AccountAlert{AccountID: account.ID, LowBalanceThreshold: arg.LowBalanceThreshold}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp accountAlertResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(100), *rsp.LowBalanceThreshold)
+				require.Nil(t, rsp.LargeDebitThreshold)
+			},
+		},
+		{
+			name:      "UpdateInvalidThreshold",
+			method:    http.MethodPut,
+			accountID: account.ID,
+			body:      gin.H{"large_debit_threshold": -5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpsertAccountAlert(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "UpdateOtherUsersAccount",
+			method:    http.MethodPut,
+			accountID: otherAccount.ID,
+			body:      gin.H{"large_debit_threshold": 500},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().UpsertAccountAlert(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "AccountNotFound",
+			method:    http.MethodGet,
+			accountID: account.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountAlert(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			url := fmt.Sprintf("/accounts/%d/alerts", tc.accountID)
+			request, err := http.NewRequest(tc.method, url, &body)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidAccountNumber = errors.New("invalid account number")
+
+// accountByNumber looks up the open account with a number as people write it, answering 400 when
+// the number has a typo and 404 when no account has it.
+func (server *Server) accountByNumber(ctx *gin.Context, number string) (db.Account, bool) {
+	number = util.NormalizeAccountNumber(number)
+	if !util.IsValidAccountNumber(number) {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, errInvalidAccountNumber))
+		return db.Account{}, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetAccountByNumber(ctx, db.GetAccountByNumberParams{
+		Number:   number,
+		TenantID: payload.Tenant,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return account, false
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return account, false
+	}
+	return account, true
+}
+
+type accountNumberURI struct {
+	Number string `uri:"number" binding:"required,max=40"`
+}
+
+type accountNumberResponse struct {
+	AccountID int64  `json:"account_id"`
+	Number    string `json:"number"`
+	Currency  string `json:"currency"`
+}
+
+// getAccountNumber tells which account an account number belongs to, so that a payer can check it
+// before transferring to it. It doesn't tell the owner nor the balance of the account.
+func (server *Server) getAccountNumber(ctx *gin.Context) {
+	var uri accountNumberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	account, ok := server.accountByNumber(ctx, uri.Number)
+	if !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, accountNumberResponse{
+		AccountID: account.ID,
+		Number:    account.Number,
+		Currency:  account.Currency,
+	})
+}
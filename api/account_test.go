@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +12,9 @@ import (
 
 	mockdb "github.com/backendmaster/simple_bank/db/mock"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/testfixtures"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
@@ -35,7 +37,7 @@ func TestGetAccount(t *testing.T) {
 			name:      "ok",
 			accountID: account.ID,
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildstub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -52,7 +54,7 @@ func TestGetAccount(t *testing.T) {
 			name:      "unauthorization",
 			accountID: account.ID,
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unthorizated user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unthorizated user", util.DepositorRole, time.Minute)
 			},
 			buildstub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -84,13 +86,13 @@ func TestGetAccount(t *testing.T) {
 			name:      "Not Found",
 			accountID: account.ID,
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildstub: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.Account{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -100,7 +102,7 @@ func TestGetAccount(t *testing.T) {
 			name:      "Internal Error",
 			accountID: account.ID,
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildstub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -112,11 +114,27 @@ func TestGetAccount(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name:      "Store Unavailable",
+			accountID: account.ID,
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			buildstub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, db.ErrStoreUnavailable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
 		{
 			name:      "Invalid ID",
 			accountID: 0, // [1,100]
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
 			},
 			buildstub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -155,9 +173,105 @@ func TestGetAccount(t *testing.T) {
 	}
 }
 
+func TestGetAccountDisplayCurrency(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	account.Balance = 10000
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name            string
+		displayCurrency string
+		buildStubs      func(store *mockdb.MockStore)
+		checkResponse   func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:            "OK",
+			displayCurrency: util.EUR,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(randomExchangeRates(fetchedAt), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp accountResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, account.Balance, rsp.Balance)
+				require.Equal(t, &fx.Conversion{
+					FromCurrency: util.USD,
+					FromAmount:   account.Balance,
+					ToCurrency:   util.EUR,
+					ToAmount:     9240,
+					Rate:         "0.923958237",
+					FetchedAt:    fetchedAt,
+				}, rsp.DisplayBalance)
+			},
+		},
+		{
+			name: "NoDisplayCurrency",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "display_balance")
+			},
+		},
+		{
+			name:            "StaleRates",
+			displayCurrency: util.EUR,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListExchangeRates(gomock.Any()).
+					Times(1).
+					Return(randomExchangeRates(fetchedAt.Add(-fx.DefaultMaxAge-time.Hour)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+		{
+			name:            "InvalidDisplayCurrency",
+			displayCurrency: "XYZ",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/accounts/%d?display_currency=%s", account.ID, tc.displayCurrency)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func TestCreateAccount(t *testing.T) {
 	user, _ := randomUser(t)
 	account := randomAccount(user.Username)
+	tenant := db.Tenant{
+		ID:         util.DefaultTenant,
+		Name:       "Simple Bank",
+		Currencies: []string{util.USD, util.EUR, util.CAD},
+	}
 
 	testCases := []struct {
 		name          string
@@ -173,9 +287,10 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(util.DefaultTenant)).Times(1).Return(tenant, nil)
 				arg := db.CreateAccountParams{
 					Owner:    account.Owner,
 					Balance:  0,
@@ -198,9 +313,45 @@ func TestCreateAccount(t *testing.T) {
 				"currency": "NIL",
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Default Currency",
+			body: gin.H{},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				owner := user
+				owner.Preferences = []byte(`{"default_currency": "` + account.Currency + `"}`)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return(owner, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(util.DefaultTenant)).Times(1).Return(tenant, nil)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Eq(db.CreateAccountParams{Owner: account.Owner, Currency: account.Currency})).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "No Default Currency",
+			body: gin.H{},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return(user, nil)
 				store.EXPECT().
 					CreateAccount(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -209,6 +360,27 @@ func TestCreateAccount(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "Currency Not Offered",
+			body: gin.H{
+				"owner":    account.Owner,
+				"currency": account.Currency,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				offered := tenant
+				offered.Currencies = []string{}
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(util.DefaultTenant)).Times(1).Return(offered, nil)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Internal Error",
 			body: gin.H{
@@ -216,9 +388,10 @@ func TestCreateAccount(t *testing.T) {
 				"currency": account.Currency,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", account.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(util.DefaultTenant)).Times(1).Return(tenant, nil)
 				arg := db.CreateAccountParams{
 					Owner:    account.Owner,
 					Balance:  0,
@@ -290,7 +463,7 @@ func TestListAccount(t *testing.T) {
 				pageSize: int64(n),
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				arg := db.ListAccountsParams{
@@ -315,7 +488,7 @@ func TestListAccount(t *testing.T) {
 				pageSize: int64(n),
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -334,7 +507,7 @@ func TestListAccount(t *testing.T) {
 				pageSize: int64(n),
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "bearer", user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, "bearer", user.Username, util.DepositorRole, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
@@ -378,31 +551,248 @@ func TestListAccount(t *testing.T) {
 
 }
 
-func randomAccount(username string) db.Account {
+func TestListAccountCursor(t *testing.T) {
+	user, _ := randomUser(t)
+	accounts := []db.Account{randomAccount(user.Username), randomAccount(user.Username)}
+	accounts[0].ID, accounts[1].ID = 11, 12
+
+	testCases := []struct {
+		name          string
+		cursor        string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstPage",
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsAfterParams{Owner: user.Username, AfterID: 0, Limit: 6}
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountResponse
+				page := requireBodyPage(t, recorder.Body, &rsp)
+				require.Equal(t, accounts, rsp.Accounts)
+				require.Empty(t, page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name:   "PreviousPage",
+			cursor: pagination.Cursor{ID: 13, Backward: true}.Encode(),
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsBeforeParams{Owner: user.Username, BeforeID: 13, Limit: 6}
+				store.EXPECT().
+					ListAccountsBefore(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Account{accounts[1], accounts[0]}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountResponse
+				page := requireBodyPage(t, recorder.Body, &rsp)
+				require.Equal(t, accounts, rsp.Accounts)
+				require.Equal(t, pagination.Cursor{ID: 12}.Encode(), page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name:   "InvalidCursor",
+			cursor: "garbage",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+			require.NoError(t, err)
+
+			q := request.URL.Query()
+			q.Add("page_size", "5")
+			if tc.cursor != "" {
+				q.Add("cursor", tc.cursor)
+			}
+			request.URL.RawQuery = q.Encode()
+			addAuthorization(t, request, server.tokenMaker, "bearer", user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAccountHistory(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	entries := make([]db.Entry, 6)
+	transfers := make([]db.Transfer, 6)
+	for i := range entries {
+		entries[i] = db.Entry{ID: int64(i + 1), AccountID: account.ID, Amount: util.RandomBalance()}
+		transfers[i] = db.Transfer{ID: int64(i + 1), FromAccountID: account.ID, ToAccountID: account.ID + 1, Amount: util.RandomBalance()}
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		username      string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Entries",
+			path:     "entries",
+			username: user.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListStatementEntriesAfterParams{AccountID: account.ID, AfterID: 0, Limit: 6}
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+				store.EXPECT().
+					ListEntryCategories(gomock.Any(), gomock.Eq([]int64{1, 2, 3, 4, 5})).
+					Times(1).
+					Return([]db.EntryCategory{{EntryID: 2, AccountID: account.ID, Category: "rent", Tags: []string{}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listEntriesResponse
+				page := requireBodyPage(t, recorder.Body, &rsp)
+				require.Len(t, rsp.Entries, 5)
+				require.Len(t, rsp.Categories, 1)
+				require.Equal(t, "rent", rsp.Categories[0].Category)
+				require.Equal(t, pagination.Cursor{ID: 5}.Encode(), page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name:     "Transfers",
+			path:     "transfers",
+			username: user.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListTransfersAfterParams{AccountID: account.ID, AfterID: 0, Limit: 6}
+				store.EXPECT().
+					ListTransfersAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transfers, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listTransfersResponse
+				page := requireBodyPage(t, recorder.Body, &rsp)
+				require.Equal(t, transfers[:5], rsp.Transfers)
+				require.Equal(t, pagination.Cursor{ID: 5}.Encode(), page.NextCursor)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			path:     "entries",
+			username: "unauthorized",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			path:     "transfers",
+			username: user.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListTransfersAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/%s?page_size=5", account.ID, tc.path)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, "bearer", tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomAccount(username string, options ...testfixtures.AccountOption) db.Account {
+	account := testfixtures.NewAccount(append([]testfixtures.AccountOption{testfixtures.WithOwner(username)}, options...)...)
 	return db.Account{
-		ID:       util.RandomInt(1, 100),
-		Owner:    username,
-		Balance:  util.RandomBalance(),
-		Currency: util.RandomCurrency(),
+		ID:       account.ID,
+		Owner:    account.Owner,
+		Balance:  account.Balance,
+		Currency: account.Currency,
+		TenantID: util.DefaultTenant,
 	}
 }
 
 func requiredBodyMatched(t *testing.T, body *bytes.Buffer, account db.Account) {
-	data, err := ioutil.ReadAll(body)
-	require.NoError(t, err)
-
 	var gotAccount db.Account
-	err = json.Unmarshal(data, &gotAccount)
-	require.NoError(t, err)
+	requireBodyData(t, body, &gotAccount)
 	require.Equal(t, account, gotAccount)
 }
 
 func requiredBodyMatchedAccounts(t *testing.T, body *bytes.Buffer, accounts []db.Account) {
-	data, err := ioutil.ReadAll(body)
-	require.NoError(t, err)
-
 	var gotAccount []db.Account
-	err = json.Unmarshal(data, &gotAccount)
-	require.NoError(t, err)
+	requireBodyData(t, body, &gotAccount)
 	require.Equal(t, accounts, gotAccount)
 }
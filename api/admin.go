@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	readOnlyRoute         = "/admin/read_only"
+	transfersBlockedRoute = "/admin/transfers_blocked"
+)
+
+type setReadOnlyRequest struct {
+	ReadOnly *bool `json:"read_only" binding:"required"`
+}
+
+type readOnlyResponse struct {
+	ReadOnly bool `json:"read_only"`
+}
+
+type setTransfersBlockedRequest struct {
+	TransfersBlocked *bool `json:"transfers_blocked" binding:"required"`
+}
+
+type transfersBlockedResponse struct {
+	TransfersBlocked bool `json:"transfers_blocked"`
+}
+
+// requireAdmin answers 403 and returns false unless the caller is an admin.
+func requireAdmin(ctx *gin.Context, action string) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Role != util.AdminRole {
+		err := fmt.Errorf("only admins can %s", action)
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// adminOperator runs the operations of the admin authenticated by the request.
+func (server *Server) adminOperator(ctx *gin.Context) (*admin.Operator, error) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	return admin.NewOperator(server.store, payload.Username)
+}
+
+// operatorErrStatus maps the errors of the admin operations to a response status.
+func operatorErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, admin.ErrImpersonateAdmin):
+		return http.StatusForbidden
+	case errors.Is(err, admin.ErrInvalidReasonCode):
+		return http.StatusBadRequest
+	}
+	return errStatus(err)
+}
+
+// setReadOnly switches every instance of the api in or out of read-only mode, e.g. around a risky
+// migration. This instance switches right away, the others at their next reload of the mode.
+func (server *Server) setReadOnly(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change the read-only mode") {
+		return
+	}
+
+	var req setReadOnlyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	shared, err := server.store.SetMaintenanceReadOnly(ctx, *req.ReadOnly)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	server.mode.Set(shared)
+	envelope.JSON(ctx, http.StatusOK, readOnlyResponse{ReadOnly: server.mode.ReadOnly()})
+}
+
+// setTransfersBlocked switches the kill switch of the transfers, which refuses the new ones with a
+// 503 and leaves the reads and the other writes alone, e.g. while the ledger is suspected to be
+// inconsistent. Like the read-only mode, it is shared by every instance.
+func (server *Server) setTransfersBlocked(ctx *gin.Context) {
+	if !requireAdmin(ctx, "block the transfers") {
+		return
+	}
+
+	var req setTransfersBlockedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	shared, err := server.store.SetMaintenanceTransfersBlocked(ctx, *req.TransfersBlocked)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	server.mode.Set(shared)
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	log.Warn().Str("admin", payload.Username).Bool("transfers_blocked", *req.TransfersBlocked).Msg("switched the transfer kill switch")
+	envelope.JSON(ctx, http.StatusOK, transfersBlockedResponse{TransfersBlocked: server.mode.TransfersBlocked()})
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+// analyticsCounterparties is how many counterparties the analytics of an account list at most.
+const analyticsCounterparties = 20
+
+type accountAnalyticsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type accountAnalyticsRequest struct {
+	Period string `form:"period" binding:"omitempty,oneof=day week month year"`
+	// At is a time in the period, now by default.
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type analyticsTotal struct {
+	Count int64 `json:"count"`
+	Total int64 `json:"total"`
+}
+
+// analyticsDay is the money that went out of and into an account on a day.
+type analyticsDay struct {
+	// Day is the date of the day in the timezone of the owner, e.g. 2026-10-16.
+	Day    string `json:"day"`
+	Debit  int64  `json:"debit"`
+	Credit int64  `json:"credit"`
+	// Balance is the balance of the account at the end of the day.
+	Balance int64 `json:"balance"`
+}
+
+type accountAnalyticsResponse struct {
+	AccountID int64  `json:"account_id"`
+	Currency  string `json:"currency"`
+	Period    string `json:"period"`
+	// Timezone is the one of the preferences of the owner, which the period and its days are in.
+	Timezone string         `json:"timezone"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Debit    analyticsTotal `json:"debit"`
+	Credit   analyticsTotal `json:"credit"`
+	// Net is what the balance of the account moved by over the period.
+	Net            int64                              `json:"net"`
+	Categories     []db.SumEntriesByCategoryRow       `json:"categories"`
+	Counterparties []db.SumTransfersByCounterpartyRow `json:"counterparties"`
+	// Days are those of the period until today, the first one first.
+	Days []analyticsDay `json:"days"`
+}
+
+// periodBounds returns the start and the end of the calendar period holding at, in location. A
+// week starts on Monday. The bounds are midnights of location, so that a period over a change of
+// DST is an hour shorter or longer than the days it has.
+func periodBounds(period string, at time.Time, location *time.Location) (time.Time, time.Time) {
+	at = at.In(location)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, location)
+	switch period {
+	case "day":
+		return day, day.AddDate(0, 0, 1)
+	case "week":
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday, monday.AddDate(0, 0, 7)
+	case "year":
+		first := time.Date(at.Year(), time.January, 1, 0, 0, 0, 0, location)
+		return first, first.AddDate(1, 0, 0)
+	}
+	first := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, location)
+	return first, first.AddDate(0, 1, 0)
+}
+
+// dailyBalances lists the days of location from from until to, or until now when it is sooner,
+// with the totals of sums, and the balance of the account at the end of each, closing is the one
+// at to.
+func dailyBalances(from, to, now time.Time, location *time.Location, sums []db.SumEntriesByDayRow, closing int64) []analyticsDay {
+	totals := make(map[string]db.SumEntriesByDayRow, len(sums))
+	for _, sum := range sums {
+		totals[sum.Day] = sum
+	}
+
+	days := []analyticsDay{}
+	for day := from.In(location); day.Before(to) && !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		days = append(days, analyticsDay{Day: date, Debit: totals[date].Debit, Credit: totals[date].Credit})
+	}
+	for i := len(days) - 1; i >= 0; i-- {
+		days[i].Balance = closing
+		closing -= days[i].Credit - days[i].Debit
+	}
+	return days
+}
+
+// getAccountAnalytics sums the money that went out of and into an account of the caller over a
+// calendar period, the month by default, in all, by category, by counterparty and by day. The
+// calendar is that of the timezone of the owner.
+func (server *Server) getAccountAnalytics(ctx *gin.Context) {
+	var uri accountAnalyticsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req accountAnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	settings, err := server.userPreferences(ctx, account.Owner)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	location := settings.Location()
+
+	now := time.Now()
+	if req.Period == "" {
+		req.Period = "month"
+	}
+	if req.At.IsZero() {
+		req.At = now
+	}
+	from, to := periodBounds(req.Period, req.At, location)
+
+	categories, err := server.store.SumEntriesByCategory(ctx, db.SumEntriesByCategoryParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	counterparties, err := server.store.SumTransfersByCounterparty(ctx, db.SumTransfersByCounterpartyParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		Limit:     analyticsCounterparties,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	sums, err := server.store.SumEntriesByDay(ctx, db.SumEntriesByDayParams{
+		Timezone:  location.String(),
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	// the balance at the end of the period is the current one without what moved it since
+	since, err := server.store.SumEntriesSince(ctx, db.SumEntriesSinceParams{
+		AccountID: account.ID,
+		FromTime:  to,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := accountAnalyticsResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		Period:         req.Period,
+		Timezone:       location.String(),
+		From:           from,
+		To:             to,
+		Categories:     categories,
+		Counterparties: counterparties,
+		Days:           dailyBalances(from, to, now, location, sums, account.Balance-since),
+	}
+	// every entry is in one of the categories, uncategorized ones included
+	for _, category := range categories {
+		total := &rsp.Credit
+		if category.Direction == db.DirectionDebit {
+			total = &rsp.Debit
+		}
+		total.Count += category.Count
+		total.Total += category.Total
+	}
+	rsp.Net = rsp.Credit.Total - rsp.Debit.Total
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	// a Friday, late in the evening in Paris
+	at := time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	testCases := []struct {
+		period string
+		from   time.Time
+		to     time.Time
+	}{
+		{period: "day", from: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), to: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{period: "week", from: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), to: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{period: "month", from: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), to: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{period: "year", from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), to: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		from, to := periodBounds(tc.period, at, time.UTC)
+		require.Equal(t, tc.from, from, tc.period)
+		require.Equal(t, tc.to, to, tc.period)
+	}
+
+	// a Monday starts its own week, a Sunday ends it
+	from, _ := periodBounds("week", time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC), time.UTC)
+	require.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), from)
+	from, _ = periodBounds("week", time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC), time.UTC)
+	require.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), from)
+}
+
+func TestPeriodBoundsInTimezone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// Friday at 23:30 in Paris is already Saturday in Taipei, and still Friday in UTC
+	at := time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC)
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	from, to := periodBounds("day", at, taipei)
+	require.Equal(t, time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC), from.UTC())
+	require.Equal(t, time.Date(2026, 10, 17, 16, 0, 0, 0, time.UTC), to.UTC())
+
+	testCases := []struct {
+		name  string
+		at    time.Time
+		hours float64
+	}{
+		// the clocks go forward on the last Sunday of March, and back on that of October
+		{name: "SpringForward", at: time.Date(2026, 3, 29, 12, 0, 0, 0, paris), hours: 23},
+		{name: "FallBack", at: time.Date(2026, 10, 25, 12, 0, 0, 0, paris), hours: 25},
+		{name: "NoChange", at: time.Date(2026, 10, 16, 12, 0, 0, 0, paris), hours: 24},
+	}
+
+	for _, tc := range testCases {
+		from, to := periodBounds("day", tc.at, paris)
+		require.Equal(t, tc.hours, to.Sub(from).Hours(), tc.name)
+		require.Equal(t, 0, from.Hour(), tc.name)
+		require.Equal(t, 0, to.Hour(), tc.name)
+	}
+
+	// the week holding the change of March is an hour short
+	from, to = periodBounds("week", time.Date(2026, 3, 25, 12, 0, 0, 0, paris), paris)
+	require.Equal(t, time.Date(2026, 3, 23, 0, 0, 0, 0, paris), from)
+	require.Equal(t, float64(7*24-1), to.Sub(from).Hours())
+}
+
+func TestDailyBalances(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	from, to := periodBounds("week", time.Date(2026, 3, 25, 12, 0, 0, 0, paris), paris)
+
+	days := dailyBalances(from, to, to.Add(time.Hour), paris, []db.SumEntriesByDayRow{
+		{Day: "2026-03-24", Debit: 30, Credit: 100},
+		{Day: "2026-03-29", Debit: 20},
+	}, 150)
+	require.Len(t, days, 7)
+	require.Equal(t, "2026-03-23", days[0].Day)
+	require.Equal(t, int64(100), days[0].Balance)
+	require.Equal(t, analyticsDay{Day: "2026-03-24", Debit: 30, Credit: 100, Balance: 170}, days[1])
+	require.Equal(t, analyticsDay{Day: "2026-03-29", Debit: 20, Balance: 150}, days[6])
+
+	// the days after now aren't there yet
+	days = dailyBalances(from, to, time.Date(2026, 3, 25, 9, 0, 0, 0, paris), paris, nil, 150)
+	require.Len(t, days, 3)
+	require.Equal(t, "2026-03-25", days[2].Day)
+}
+
+func TestGetAccountAnalyticsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	categories := []db.SumEntriesByCategoryRow{
+		{Category: "salary", Direction: db.DirectionCredit, Count: 1, Total: 3000},
+		{Category: "rent", Direction: db.DirectionDebit, Count: 1, Total: 1200},
+		{Category: "", Direction: db.DirectionDebit, Count: 3, Total: 150},
+	}
+	counterparties := []db.SumTransfersByCounterpartyRow{
+		{CounterpartyAccountID: account.ID + 1, Direction: db.DirectionCredit, Count: 1, Total: 3000},
+		{CounterpartyAccountID: account.ID + 2, Direction: db.DirectionDebit, Count: 1, Total: 1200},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    url.Values{"period": {"month"}, "at": {"2026-10-16T12:00:00Z"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					SumEntriesByCategory(gomock.Any(), gomock.Eq(db.SumEntriesByCategoryParams{
+						AccountID: account.ID,
+						FromTime:  from,
+						ToTime:    to,
+					})).
+					Times(1).
+					Return(categories, nil)
+				store.EXPECT().
+					SumEntriesByDay(gomock.Any(), gomock.Eq(db.SumEntriesByDayParams{
+						Timezone:  "UTC",
+						AccountID: account.ID,
+						FromTime:  from,
+						ToTime:    to,
+					})).
+					Times(1).
+					Return([]db.SumEntriesByDayRow{{Day: "2026-10-02", Debit: 1350, Credit: 3000}}, nil)
+				store.EXPECT().
+					SumEntriesSince(gomock.Any(), gomock.Eq(db.SumEntriesSinceParams{AccountID: account.ID, FromTime: to})).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					SumTransfersByCounterparty(gomock.Any(), gomock.Eq(db.SumTransfersByCounterpartyParams{
+						AccountID: account.ID,
+						FromTime:  from,
+						ToTime:    to,
+						Limit:     analyticsCounterparties,
+					})).
+					Times(1).
+					Return(counterparties, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountAnalyticsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "month", rsp.Period)
+				require.Equal(t, account.Currency, rsp.Currency)
+				require.Equal(t, analyticsTotal{Count: 4, Total: 1350}, rsp.Debit)
+				require.Equal(t, analyticsTotal{Count: 1, Total: 3000}, rsp.Credit)
+				require.Equal(t, int64(1650), rsp.Net)
+				require.Equal(t, categories, rsp.Categories)
+				require.Equal(t, counterparties, rsp.Counterparties)
+				require.Equal(t, "UTC", rsp.Timezone)
+				require.Equal(t, "2026-10-01", rsp.Days[0].Day)
+				require.Equal(t, analyticsDay{Day: "2026-10-02", Debit: 1350, Credit: 3000, Balance: account.Balance}, rsp.Days[1])
+			},
+		},
+		{
+			name:     "DefaultPeriod",
+			query:    url.Values{},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				from, to := periodBounds("month", time.Now(), time.UTC)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					SumEntriesByCategory(gomock.Any(), gomock.Eq(db.SumEntriesByCategoryParams{
+						AccountID: account.ID,
+						FromTime:  from,
+						ToTime:    to,
+					})).
+					Times(1).
+					Return([]db.SumEntriesByCategoryRow{}, nil)
+				store.EXPECT().
+					SumTransfersByCounterparty(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.SumTransfersByCounterpartyRow{}, nil)
+				store.EXPECT().SumEntriesByDay(gomock.Any(), gomock.Any()).Times(1).Return([]db.SumEntriesByDayRow{}, nil)
+				store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountAnalyticsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "month", rsp.Period)
+				require.Zero(t, rsp.Net)
+			},
+		},
+		{
+			name:     "Timezone",
+			query:    url.Values{"period": {"day"}, "at": {"2026-09-11T21:30:00Z"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				// late on Friday in UTC is Saturday in Taipei
+				from := time.Date(2026, 9, 11, 16, 0, 0, 0, time.UTC)
+				to := time.Date(2026, 9, 12, 16, 0, 0, 0, time.UTC)
+				owner := user
+				owner.Preferences = []byte(`{"timezone": "Asia/Taipei"}`)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(owner, nil)
+				store.EXPECT().
+					SumEntriesByCategory(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SumEntriesByCategoryParams) ([]db.SumEntriesByCategoryRow, error) {
+						require.True(t, from.Equal(arg.FromTime))
+						require.True(t, to.Equal(arg.ToTime))
+						return []db.SumEntriesByCategoryRow{}, nil
+					})
+				store.EXPECT().SumTransfersByCounterparty(gomock.Any(), gomock.Any()).Times(1).Return([]db.SumTransfersByCounterpartyRow{}, nil)
+				store.EXPECT().
+					SumEntriesByDay(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SumEntriesByDayParams) ([]db.SumEntriesByDayRow, error) {
+						require.Equal(t, "Asia/Taipei", arg.Timezone)
+						return []db.SumEntriesByDayRow{}, nil
+					})
+				store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp accountAnalyticsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "Asia/Taipei", rsp.Timezone)
+				require.Equal(t, []analyticsDay{{Day: "2026-09-12", Balance: account.Balance}}, rsp.Days)
+			},
+		},
+		{
+			name:     "InvalidPeriod",
+			query:    url.Values{"period": {"quarter"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			query:    url.Values{"period": {"week"}},
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SumEntriesByCategory(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/analytics?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
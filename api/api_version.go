@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiV1 = "/v1"
+	// unversionedPrefix serves the routes from before the versions of the api.
+	unversionedPrefix = "/"
+)
+
+// unversionedDeprecation is when the routes without a version were deprecated in favor of v1.
+var unversionedDeprecation = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// apiVersion is a version of the api, serving the routes of addRoutes under its prefix. Shipping
+// a breaking change, e.g. of the shape of the responses, adds a version, /v2, and deprecates the
+// one before it with /v2 as its successor, so that its clients are told to move before its sunset.
+type apiVersion struct {
+	prefix string
+	// successor is the prefix of the version replacing a deprecated one, empty for a current one.
+	successor  string
+	deprecated time.Time
+	// sunset is when a deprecated version stops being served, zero when it isn't planned yet.
+	sunset time.Time
+}
+
+// apiVersions are the versions of the api the router serves. sunset is the one of the routes
+// without a version.
+func apiVersions(sunset time.Time) []apiVersion {
+	return []apiVersion{
+		{prefix: apiV1},
+		{prefix: unversionedPrefix, successor: apiV1, deprecated: unversionedDeprecation, sunset: sunset},
+	}
+}
+
+// route returns route, a route without a version, in version.
+func (version apiVersion) route(route string) string {
+	return strings.TrimSuffix(version.prefix, "/") + route
+}
+
+// versionedRoutes returns routes, which are routes without a version like those of ROUTE_TIMEOUTS,
+// in every version.
+func versionedRoutes(versions []apiVersion, routes ...string) []string {
+	versioned := make([]string, 0, len(routes)*len(versions))
+	for _, version := range versions {
+		for _, route := range routes {
+			versioned = append(versioned, version.route(route))
+		}
+	}
+	return versioned
+}
+
+// parseSunset parses the sunset of the routes without a version, a date like 2027-04-16, none
+// when empty.
+func parseSunset(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	sunset, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sunset of the routes without a version %q: %w", date, err)
+	}
+	return sunset, nil
+}
+
+// deprecated tells the clients of a deprecated version with the headers of RFC 9745 and RFC 8594
+// that it's going away, when, and where the same route is served in its successor.
+func deprecated(version apiVersion) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", "@"+strconv.FormatInt(version.deprecated.Unix(), 10))
+		if !version.sunset.IsZero() {
+			ctx.Header("Sunset", version.sunset.UTC().Format(http.TimeFormat))
+		}
+		successor := version.successor + strings.TrimPrefix(ctx.Request.URL.Path, strings.TrimSuffix(version.prefix, "/"))
+		ctx.Header("Link", "<"+successor+`>; rel="successor-version"`)
+		ctx.Next()
+	}
+}
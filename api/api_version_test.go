@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersions(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name        string
+		path        string
+		checkHeader func(t *testing.T, header http.Header)
+	}{
+		{
+			name: "V1",
+			path: fmt.Sprintf("/v1/accounts/%d", account.ID),
+			checkHeader: func(t *testing.T, header http.Header) {
+				require.Empty(t, header.Get("Deprecation"))
+				require.Empty(t, header.Get("Link"))
+			},
+		},
+		{
+			name: "Unversioned",
+			path: fmt.Sprintf("/accounts/%d", account.ID),
+			checkHeader: func(t *testing.T, header http.Header) {
+				require.Equal(t, "@"+strconv.FormatInt(unversionedDeprecation.Unix(), 10), header.Get("Deprecation"))
+				require.Equal(t, fmt.Sprintf(`</v1/accounts/%d>; rel="successor-version"`, account.ID), header.Get("Link"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			requiredBodyMatched(t, recorder.Body, account)
+			tc.checkHeader(t, recorder.Header())
+		})
+	}
+}
+
+func TestDeprecatedSunset(t *testing.T) {
+	sunset, err := parseSunset("2027-04-16")
+	require.NoError(t, err)
+	_, err = parseSunset("16/04/2027")
+	require.Error(t, err)
+
+	router := gin.New()
+	version := apiVersion{prefix: apiV1, successor: "/v2", deprecated: unversionedDeprecation, sunset: sunset}
+	router.Group(version.prefix).Use(deprecated(version)).GET("/accounts", func(ctx *gin.Context) {})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/accounts", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, "Fri, 16 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+	require.Equal(t, `</v2/accounts>; rel="successor-version"`, recorder.Header().Get("Link"))
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	versions := apiVersions(time.Time{})
+	require.ElementsMatch(t, []string{"/v1/transfers", "/transfers"}, versionedRoutes(versions, "/transfers"))
+}
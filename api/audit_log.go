@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type listAuditLogsRequest struct {
+	Actor    string    `form:"actor"`
+	Action   string    `form:"action"`
+	FromTime time.Time `form:"from_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	ToTime   time.Time `form:"to_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor   string    `form:"cursor"`
+	PageSize int32     `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+type auditChainReportResponse struct {
+	OK bool `json:"ok"`
+	db.AuditChainReport
+}
+
+type listAuditLogsResponse struct {
+	AuditLogs []db.AuditLog `json:"audit_logs"`
+}
+
+// listAuditLogs pages through the audit logs of the writes to the api, oldest first, narrowed down
+// to the actor, the action, e.g. "POST /transfers", and the time range given.
+func (server *Server) listAuditLogs(ctx *gin.Context) {
+	if !requireAdmin(ctx, "read the audit logs") {
+		return
+	}
+
+	var req listAuditLogsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	filter := db.AuditLogFilter{
+		Actor:    pgtype.Text{String: req.Actor, Valid: req.Actor != ""},
+		Action:   pgtype.Text{String: req.Action, Valid: req.Action != ""},
+		FromTime: pgtype.Timestamptz{Time: req.FromTime, Valid: !req.FromTime.IsZero()},
+		ToTime:   pgtype.Timestamptz{Time: req.ToTime, Valid: !req.ToTime.IsZero()},
+	}
+	page, err := db.ListAuditLogsPage(ctx, server.store, filter, cursor, req.PageSize)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.Page(ctx, http.StatusOK, listAuditLogsResponse{AuditLogs: page.Items}, page.NextCursor, page.PrevCursor)
+}
+
+// verifyAuditLogs runs the verification of the hash chain of the audit logs on demand and reports
+// the audit logs breaking it.
+func (server *Server) verifyAuditLogs(ctx *gin.Context) {
+	if !requireAdmin(ctx, "verify the audit logs") {
+		return
+	}
+
+	report, err := server.store.VerifyAuditLogChain(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, auditChainReportResponse{
+		OK:               report.OK(),
+		AuditChainReport: report,
+	})
+}
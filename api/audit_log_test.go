@@ -0,0 +1,293 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAuditLog(id int64) db.AuditLog {
+	return db.AuditLog{
+		ID:         id,
+		Actor:      util.RandomOwnerName(),
+		Protocol:   "http",
+		Action:     "POST /transfers",
+		Path:       "/transfers",
+		StatusCode: http.StatusOK,
+		ClientIp:   "10.0.0.1",
+		UserAgent:  "curl/8.0",
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestListAuditLogsAPI(t *testing.T) {
+	logs := make([]db.AuditLog, 6)
+	for i := range logs {
+		logs[i] = randomAuditLog(int64(i + 1))
+	}
+	fromTime := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLogsAfter(gomock.Any(), gomock.Eq(db.ListAuditLogsAfterParams{Limit: 6})).
+					Times(1).
+					Return(logs, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAuditLogsResponse
+				page := requireBodyPage(t, recorder.Body, &rsp)
+				require.Equal(t, logs[:5], rsp.AuditLogs)
+				require.Equal(t, pagination.Cursor{ID: 5}.Encode(), page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name: "Filters",
+			query: url.Values{
+				"actor":     {"alice"},
+				"action":    {"POST /transfers"},
+				"from_time": {fromTime.Format(time.RFC3339)},
+				"cursor":    {pagination.Cursor{ID: 10, Backward: true}.Encode()},
+				"page_size": {"5"},
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditLogsBefore(gomock.Any(), gomock.Eq(db.ListAuditLogsBeforeParams{
+						BeforeID: 10,
+						Actor:    pgtype.Text{String: "alice", Valid: true},
+						Action:   pgtype.Text{String: "POST /transfers", Valid: true},
+						FromTime: pgtype.Timestamptz{Time: fromTime, Valid: true},
+						Limit:    6,
+					})).
+					Times(1).
+					Return([]db.AuditLog{logs[1], logs[0]}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAuditLogsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, logs[:2], rsp.AuditLogs)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: url.Values{"cursor": {"!"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditLogsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidFromTime",
+			query: url.Values{"from_time": {"yesterday"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditLogsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: url.Values{"page_size": {"5"}},
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditLogsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: url.Values{"page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditLogsAfter(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit_logs?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifyAuditLogsAPI(t *testing.T) {
+	problem := db.AuditChainProblem{ID: util.RandomInt(1, 1000), Problem: "hash doesn't match the content of the audit log"}
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyAuditLogChain(gomock.Any()).
+					Times(1).
+					Return(db.AuditChainReport{CheckedAt: time.Now(), Checked: 10, HeadID: 10, HeadHash: "ab12"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp auditChainReportResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.True(t, rsp.OK)
+				require.Equal(t, int64(10), rsp.HeadID)
+				require.Equal(t, "ab12", rsp.HeadHash)
+			},
+		},
+		{
+			name: "Tampered",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyAuditLogChain(gomock.Any()).
+					Times(1).
+					Return(db.AuditChainReport{
+						CheckedAt: time.Now(),
+						Checked:   10,
+						Problems:  []db.AuditChainProblem{problem},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp auditChainReportResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.False(t, rsp.OK)
+				require.Equal(t, []db.AuditChainProblem{problem}, rsp.Problems)
+			},
+		},
+		{
+			name: "NotAdmin",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyAuditLogChain(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyAuditLogChain(gomock.Any()).
+					Times(1).
+					Return(db.AuditChainReport{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit_logs/verify", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAuditLogMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.config.AuditLog = true
+	server.setupRouter()
+
+	store.EXPECT().SetMaintenanceReadOnly(gomock.Any(), false).Times(1).Return(db.MaintenanceMode{ID: true}, nil)
+	store.EXPECT().
+		CreateAuditLogTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+			require.Equal(t, "admin", arg.Actor)
+			require.Equal(t, "PUT "+readOnlyRoute, arg.Action)
+			require.Equal(t, readOnlyRoute, arg.Path)
+			require.Equal(t, int32(http.StatusOK), arg.StatusCode)
+			return db.AuditLog{}, nil
+		})
+
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, readOnlyRoute, strings.NewReader(`{"read_only": false}`))
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The authorization hold routes let a payer hold an amount of an account for a payee, e.g. a
+// deposit for a rental: the amount stays on the account but can't be spent, until the payee
+// captures all or part of it or releases it. The worker expires the holds nobody closed.
+
+// defaultHoldExpiry is how long a hold lasts when its payer doesn't tell.
+const defaultHoldExpiry = 7 * 24 * time.Hour
+
+// authorizationHoldErrStatus maps the errors of the authorization holds to a response status.
+func authorizationHoldErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrInvalidCaptureAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrAuthorizationHoldClosed), errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+type placeAuthorizationHoldRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64 `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0,amount=Currency"`
+	// AmountDecimal is the amount in units of the currency, e.g. "12.34", instead of amount.
+	AmountDecimal string `json:"amount_decimal" binding:"omitempty,excluded_with=Amount,decimal_amount=Currency"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	Memo          string `json:"memo" binding:"max=140"`
+	// ExpiresInHours is how long the hold lasts if the payee neither captures nor releases it, a
+	// week by default.
+	ExpiresInHours int32 `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// placeAuthorizationHold holds an amount of an account of the caller for the payee account. The
+// amount is taken out of the available balance of the account right away, and can't take it
+// below zero.
+func (server *Server) placeAuthorizationHold(ctx *gin.Context) {
+	var req placeAuthorizationHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if !bindAmount(ctx, &req.Amount, req.AmountDecimal, req.Currency) {
+		return
+	}
+	if !server.checkAmountStepUp(ctx, req.Currency, req.Amount) {
+		return
+	}
+	if req.FromAccountID == req.ToAccountID {
+		err := errors.New("an account can't hold money for itself")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	fromAccount, valid := server.validateAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != payload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	toAccount, valid := server.validateAccount(ctx, req.ToAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	if !server.checkFraud(ctx, fromAccount, toAccount, req.Amount) {
+		return
+	}
+
+	expiry := defaultHoldExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	result, err := server.store.PlaceAuthorizationHoldTx(ctx, db.CreateAuthorizationHoldParams{
+		AccountID:   fromAccount.ID,
+		ToAccountID: req.ToAccountID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Memo:        req.Memo,
+		ExpiresAt:   time.Now().Add(expiry),
+	})
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, result)
+}
+
+type authorizationHoldURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// authorizationHoldOfCaller returns the hold of the request when the caller owns its payee
+// account, or its payer account too unless payeeOnly, answering the request otherwise.
+func (server *Server) authorizationHoldOfCaller(ctx *gin.Context, payeeOnly bool) (db.AuthorizationHold, bool) {
+	var uri authorizationHoldURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return db.AuthorizationHold{}, false
+	}
+	hold, err := server.store.GetAuthorizationHold(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return hold, false
+	}
+
+	accountIDs := []int64{hold.ToAccountID}
+	if !payeeOnly {
+		accountIDs = append(accountIDs, hold.AccountID)
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, id := range accountIDs {
+		account, err := server.store.GetAccountIncludeDeleted(ctx, id)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return hold, false
+		}
+		if account.Owner == payload.Username {
+			return hold, true
+		}
+	}
+	err = errors.New("authorization hold doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+	return hold, false
+}
+
+// getAuthorizationHold returns a hold on or in favor of an account of the caller.
+func (server *Server) getAuthorizationHold(ctx *gin.Context) {
+	hold, ok := server.authorizationHoldOfCaller(ctx, false)
+	if !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, hold)
+}
+
+type listAuthorizationHoldsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listAuthorizationHoldsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=active captured released expired"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listAuthorizationHolds lists the holds on an account of the caller and those in its favor, the
+// newest first.
+func (server *Server) listAuthorizationHolds(ctx *gin.Context) {
+	var uri listAuthorizationHoldsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req listAuthorizationHoldsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	holds, err := server.store.ListAccountAuthorizationHolds(ctx, db.ListAccountAuthorizationHoldsParams{
+		AccountID: account.ID,
+		Status:    pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, holds)
+}
+
+type captureAuthorizationHoldRequest struct {
+	// Amount is what the payee takes, the whole held amount by default.
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// captureAuthorizationHold transfers the captured amount of an active hold in favor of an account
+// of the caller, the rest going back to the payer.
+func (server *Server) captureAuthorizationHold(ctx *gin.Context) {
+	var req captureAuthorizationHoldRequest
+	// an empty body captures the whole amount
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	hold, ok := server.authorizationHoldOfCaller(ctx, true)
+	if !ok {
+		return
+	}
+	if req.Amount == 0 {
+		req.Amount = hold.Amount
+	}
+	if !server.checkAmountStepUp(ctx, hold.Currency, req.Amount) {
+		return
+	}
+	fromAccount, err := server.store.GetAccount(ctx, hold.AccountID)
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	toAccount, err := server.store.GetAccount(ctx, hold.ToAccountID)
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !server.checkFraud(ctx, fromAccount, toAccount, req.Amount) {
+		return
+	}
+
+	result, err := server.store.CaptureAuthorizationHoldTx(ctx, db.CaptureAuthorizationHoldTxParams{
+		ID:        hold.ID,
+		Amount:    req.Amount,
+		AlertTask: worker.NewAccountAlertTask,
+	})
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(hold.Currency, req.Amount)
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
+
+// releaseAuthorizationHold closes an active hold in favor of an account of the caller without
+// capturing anything.
+func (server *Server) releaseAuthorizationHold(ctx *gin.Context) {
+	hold, ok := server.authorizationHoldOfCaller(ctx, true)
+	if !ok {
+		return
+	}
+
+	hold, err := server.store.ReleaseAuthorizationHoldTx(ctx, db.ReleaseAuthorizationHoldTxParams{
+		ID:     hold.ID,
+		Status: db.AuthorizationHoldReleased,
+	})
+	if err != nil {
+		ctx.JSON(authorizationHoldErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, hold)
+}
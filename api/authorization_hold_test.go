@@ -0,0 +1,609 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceAuthorizationHoldAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	account := randomAccount(payer.Username, testfixtures.WithID(1), testfixtures.WithCurrency(util.USD))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2), testfixtures.WithCurrency(util.USD))
+	eurAccount := randomAccount(payee.Username, testfixtures.WithID(3), testfixtures.WithCurrency(util.EUR))
+
+	body := gin.H{
+		"from_account_id": account.ID,
+		"to_account_id":   payeeAccount.ID,
+		"amount":          50,
+		"currency":        util.USD,
+		"memo":            "car rental deposit",
+	}
+	with := func(key string, value interface{}) gin.H {
+		changed := gin.H{}
+		for k, v := range body {
+			changed[k] = v
+		}
+		changed[key] = value
+		return changed
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     body,
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAuthorizationHoldParams) (db.PlaceAuthorizationHoldTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, payeeAccount.ID, arg.ToAccountID)
+						require.Equal(t, int64(50), arg.Amount)
+						require.Equal(t, "car rental deposit", arg.Memo)
+						require.WithinDuration(t, time.Now().Add(defaultHoldExpiry), arg.ExpiresAt, time.Second)
+						return db.PlaceAuthorizationHoldTxResult{
+							Hold: db.AuthorizationHold{ID: 1, AccountID: arg.AccountID, Amount: arg.Amount, Status: db.AuthorizationHoldActive},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.PlaceAuthorizationHoldTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.AuthorizationHoldActive, rsp.Hold.Status)
+			},
+		},
+		{
+			name:     "ExpiresInHours",
+			body:     with("expires_in_hours", 24),
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAuthorizationHoldParams) (db.PlaceAuthorizationHoldTxResult, error) {
+						require.WithinDuration(t, time.Now().Add(24*time.Hour), arg.ExpiresAt, time.Second)
+						return db.PlaceAuthorizationHoldTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "SameAccount",
+			body:     with("to_account_id", account.ID),
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ExpiryTooLong",
+			body:     with("expires_in_hours", 721),
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			body:     body,
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "PayeeCurrencyMismatch",
+			body:     with("to_account_id", eurAccount.ID),
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ScreeningMatch",
+			body:     body,
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{"account_id"}, nil)
+				store.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "InsufficientFunds",
+			body:     body,
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PlaceAuthorizationHoldTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/authorization_holds", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetAuthorizationHoldAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(payer.Username, testfixtures.WithID(1))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2))
+	hold := db.AuthorizationHold{
+		ID:          util.RandomInt(1, 1000),
+		AccountID:   account.ID,
+		ToAccountID: payeeAccount.ID,
+		Amount:      50,
+		Currency:    account.Currency,
+		Status:      db.AuthorizationHoldActive,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Payee",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.AuthorizationHold
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, hold, rsp)
+			},
+		},
+		{
+			name:     "Payer",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(db.AuthorizationHold{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/authorization_holds/%d", hold.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAuthorizationHoldsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	holds := []db.AuthorizationHold{
+		{ID: 2, AccountID: account.ID, ToAccountID: account.ID + 1, Amount: 10, Status: db.AuthorizationHoldActive},
+		{ID: 1, AccountID: account.ID + 1, ToAccountID: account.ID, Amount: 20, Status: db.AuthorizationHoldActive},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"status": {db.AuthorizationHoldActive}, "page_id": {"1"}, "page_size": {"5"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListAccountAuthorizationHolds(gomock.Any(), gomock.Eq(db.ListAccountAuthorizationHoldsParams{
+						AccountID: account.ID,
+						Status:    pgtype.Text{String: db.AuthorizationHoldActive, Valid: true},
+						Limit:     5,
+						Offset:    0,
+					})).
+					Times(1).
+					Return(holds, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.AuthorizationHold
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, holds, rsp)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: url.Values{"status": {"pending"}, "page_id": {"1"}, "page_size": {"5"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountAuthorizationHolds(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/authorization_holds?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCaptureAuthorizationHoldAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	account := randomAccount(payer.Username, testfixtures.WithID(1))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2))
+	hold := db.AuthorizationHold{
+		ID:          util.RandomInt(1, 1000),
+		AccountID:   account.ID,
+		ToAccountID: payeeAccount.ID,
+		Amount:      50,
+		Currency:    account.Currency,
+		Status:      db.AuthorizationHoldActive,
+	}
+	// the capture pays the payee from the payer, through the fraud rules
+	paymentStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+		store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "WholeAmount",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				paymentStubs(store)
+				store.EXPECT().
+					CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CaptureAuthorizationHoldTxParams) (db.CaptureAuthorizationHoldTxResult, error) {
+						require.Equal(t, hold.ID, arg.ID)
+						require.Equal(t, hold.Amount, arg.Amount)
+						require.NotNil(t, arg.AlertTask)
+						captured := hold
+						captured.Status = db.AuthorizationHoldCaptured
+						captured.CapturedAmount = arg.Amount
+						return db.CaptureAuthorizationHoldTxResult{Hold: captured}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.CaptureAuthorizationHoldTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.AuthorizationHoldCaptured, rsp.Hold.Status)
+				require.Equal(t, hold.Amount, rsp.Hold.CapturedAmount)
+			},
+		},
+		{
+			name:     "PartialAmount",
+			body:     []byte(`{"amount": 30}`),
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(payeeAccount, nil)
+				paymentStubs(store)
+				store.EXPECT().
+					CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CaptureAuthorizationHoldTxParams) (db.CaptureAuthorizationHoldTxResult, error) {
+						require.Equal(t, int64(30), arg.Amount)
+						return db.CaptureAuthorizationHoldTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "OverHeldAmount",
+			body:     []byte(`{"amount": 80}`),
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(payeeAccount, nil)
+				paymentStubs(store)
+				store.EXPECT().
+					CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CaptureAuthorizationHoldTxResult{}, db.ErrInvalidCaptureAmount)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ScreeningMatch",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{"name"}, nil)
+				store.EXPECT().CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "PayerCannotCapture",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Closed",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(payeeAccount, nil)
+				paymentStubs(store)
+				store.EXPECT().
+					CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CaptureAuthorizationHoldTxResult{}, db.ErrAuthorizationHoldClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/authorization_holds/%d/capture", hold.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tc.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReleaseAuthorizationHoldAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	account := randomAccount(payer.Username, testfixtures.WithID(1))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2))
+	hold := db.AuthorizationHold{
+		ID:          util.RandomInt(1, 1000),
+		AccountID:   account.ID,
+		ToAccountID: payeeAccount.ID,
+		Amount:      50,
+		Currency:    account.Currency,
+		Status:      db.AuthorizationHoldActive,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				released := hold
+				released.Status = db.AuthorizationHoldReleased
+				store.EXPECT().
+					ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Eq(db.ReleaseAuthorizationHoldTxParams{
+						ID:     hold.ID,
+						Status: db.AuthorizationHoldReleased,
+					})).
+					Times(1).
+					Return(released, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.AuthorizationHold
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.AuthorizationHoldReleased, rsp.Status)
+			},
+		},
+		{
+			name:     "PayerCannotRelease",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/authorization_holds/%d/release", hold.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+// The balance adjustments let admins correct a balance with an entry of the ledger, never by
+// editing it. The reason code, one of admin.AdjustmentReasons, is what the owner of the account is
+// notified of; the justification stays in the audit entry.
+
+type adjustBalanceRequest struct {
+	Amount        int64  `json:"amount" binding:"required"`
+	ReasonCode    string `json:"reason_code" binding:"required"`
+	Justification string `json:"justification" binding:"required"`
+}
+
+func (server *Server) adjustBalance(ctx *gin.Context) {
+	if !requireAdmin(ctx, "adjust balances") {
+		return
+	}
+
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req adjustBalanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	result, err := operator.AdjustBalance(ctx, admin.AdjustBalanceParams{
+		AccountID:  uri.ID,
+		Amount:     req.Amount,
+		ReasonCode: req.ReasonCode,
+		Reason:     req.Justification,
+	})
+	if err != nil {
+		ctx.JSON(operatorErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/card"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// The card routes let users issue virtual cards on their accounts, freeze them and limit what they
+// spend, and let the card network authorize the payments made with them.
+
+const (
+	cardAuthorizationsRoute = "/card_network/authorizations"
+	// cardTimestampHeader and cardSignatureHeader are set by the card network, see card.Issuer.Sign.
+	cardTimestampHeader = "X-Card-Network-Timestamp"
+	cardSignatureHeader = "X-Card-Network-Signature"
+)
+
+// cardErrStatus maps the errors of the cards to a response status.
+func cardErrStatus(err error) int {
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+	return errStatus(err)
+}
+
+// cardResponse is a card without the fingerprint of its number.
+type cardResponse struct {
+	ID                  int64     `json:"id"`
+	AccountID           int64     `json:"account_id"`
+	Last4               string    `json:"last4"`
+	ExpiryMonth         int32     `json:"expiry_month"`
+	ExpiryYear          int32     `json:"expiry_year"`
+	PerTransactionLimit int64     `json:"per_transaction_limit"`
+	DailyLimit          int64     `json:"daily_limit"`
+	Frozen              bool      `json:"frozen"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+func newCardResponse(c db.Card) cardResponse {
+	return cardResponse{
+		ID:                  c.ID,
+		AccountID:           c.AccountID,
+		Last4:               c.Last4,
+		ExpiryMonth:         c.ExpiryMonth,
+		ExpiryYear:          c.ExpiryYear,
+		PerTransactionLimit: c.PerTransactionLimit,
+		DailyLimit:          c.DailyLimit,
+		Frozen:              c.FrozenAt.Valid,
+		CreatedAt:           c.CreatedAt,
+	}
+}
+
+type issueCardRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	// PerTransactionLimit and DailyLimit are 0 for no limit.
+	PerTransactionLimit int64 `json:"per_transaction_limit" binding:"min=0"`
+	DailyLimit          int64 `json:"daily_limit" binding:"min=0"`
+}
+
+type issueCardResponse struct {
+	Card    cardResponse `json:"card"`
+	Details card.Details `json:"details"`
+}
+
+// issueCard issues a virtual card on an account of the caller. Its number and CVV are in the
+// response only: the bank doesn't keep them.
+func (server *Server) issueCard(ctx *gin.Context) {
+	var req issueCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, req.AccountID)
+	if !ok {
+		return
+	}
+
+	details, err := server.cards.Issue(time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	issued, err := server.store.CreateCard(ctx, db.CreateCardParams{
+		AccountID:           account.ID,
+		Fingerprint:         server.cards.Fingerprint(details.Number),
+		Last4:               details.Number[len(details.Number)-4:],
+		ExpiryMonth:         details.ExpiryMonth,
+		ExpiryYear:          details.ExpiryYear,
+		PerTransactionLimit: req.PerTransactionLimit,
+		DailyLimit:          req.DailyLimit,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, issueCardResponse{Card: newCardResponse(issued), Details: details})
+}
+
+type cardURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// cardOfCaller answers and returns false unless the card of the uri exists and its account
+// belongs to the caller.
+func (server *Server) cardOfCaller(ctx *gin.Context) (db.Card, bool) {
+	var uri cardURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return db.Card{}, false
+	}
+	found, err := server.store.GetCard(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(cardErrStatus(err), errResponse(ctx, err))
+		return found, false
+	}
+	if _, ok := server.authorizeAccount(ctx, found.AccountID); !ok {
+		return found, false
+	}
+	return found, true
+}
+
+// getCard returns a card of the caller.
+func (server *Server) getCard(ctx *gin.Context) {
+	found, ok := server.cardOfCaller(ctx)
+	if !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newCardResponse(found))
+}
+
+type listCardsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// listCards lists the cards of an account of the caller, oldest first.
+func (server *Server) listCards(ctx *gin.Context) {
+	var uri listCardsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	cards, err := server.store.ListAccountCards(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	rsp := make([]cardResponse, len(cards))
+	for i, c := range cards {
+		rsp[i] = newCardResponse(c)
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+type setCardFrozenRequest struct {
+	Frozen *bool `json:"frozen" binding:"required"`
+}
+
+// setCardFrozen freezes a card of the caller, e.g. one they misplaced, which declines its
+// payments until it is unfrozen.
+func (server *Server) setCardFrozen(ctx *gin.Context) {
+	found, ok := server.cardOfCaller(ctx)
+	if !ok {
+		return
+	}
+	var req setCardFrozenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	updated, err := server.store.SetCardFrozen(ctx, db.SetCardFrozenParams{
+		ID:     found.ID,
+		Frozen: *req.Frozen,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newCardResponse(updated))
+}
+
+type setCardLimitsRequest struct {
+	PerTransactionLimit int64 `json:"per_transaction_limit" binding:"min=0"`
+	DailyLimit          int64 `json:"daily_limit" binding:"min=0"`
+}
+
+// setCardLimits sets the most a card of the caller spends in a payment and in a UTC day, 0 for no
+// limit.
+func (server *Server) setCardLimits(ctx *gin.Context) {
+	found, ok := server.cardOfCaller(ctx)
+	if !ok {
+		return
+	}
+	var req setCardLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	updated, err := server.store.UpdateCardLimits(ctx, db.UpdateCardLimitsParams{
+		ID:                  found.ID,
+		PerTransactionLimit: req.PerTransactionLimit,
+		DailyLimit:          req.DailyLimit,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newCardResponse(updated))
+}
+
+type listCardAuthorizationsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listCardAuthorizations lists the authorizations of a card of the caller, the declined ones
+// included, the newest first.
+func (server *Server) listCardAuthorizations(ctx *gin.Context) {
+	found, ok := server.cardOfCaller(ctx)
+	if !ok {
+		return
+	}
+	var req listCardAuthorizationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	authorizations, err := server.store.ListCardAuthorizations(ctx, db.ListCardAuthorizationsParams{
+		CardID: found.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, authorizations)
+}
+
+type cardAuthorizationRequest struct {
+	// NetworkTransactionID is the id the network gives the request, the same when it retries it.
+	NetworkTransactionID string `json:"network_transaction_id" binding:"required,max=64"`
+	CardNumber           string `json:"card_number" binding:"required,max=19"`
+	ExpiryMonth          int32  `json:"expiry_month" binding:"required,min=1,max=12"`
+	ExpiryYear           int32  `json:"expiry_year" binding:"required,min=2000"`
+	CVV                  string `json:"cvv" binding:"required,len=3"`
+	Amount               int64  `json:"amount" binding:"required,gt=0,amount=Currency"`
+	Currency             string `json:"currency" binding:"required,currency"`
+	// MerchantName and MerchantCategory are those of the merchant taking the payment.
+	MerchantName     string `json:"merchant_name" binding:"required,max=100"`
+	MerchantCategory string `json:"merchant_category" binding:"omitempty,len=4,numeric"`
+}
+
+type cardAuthorizationResponse struct {
+	Approved        bool   `json:"approved"`
+	AuthorizationID int64  `json:"authorization_id,omitempty"`
+	DeclineReason   string `json:"decline_reason,omitempty"`
+}
+
+// authorizeCardPayment answers an authorization request of the card network, signed with the
+// network key. A request the bank could decide on is answered with 200, approved or declined with
+// a reason, so that the network doesn't retry it; an approved payment debited the account already.
+// A request with the network transaction id of one answered before, a retry or a replay within
+// the tolerance of the signature, gets the answer of the original.
+func (server *Server) authorizeCardPayment(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	err = server.cards.VerifySignature(ctx.GetHeader(cardTimestampHeader), body, ctx.GetHeader(cardSignatureHeader), time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	var req cardAuthorizationRequest
+	if err := binding.JSON.BindBody(body, &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if !card.ValidNumber(req.CardNumber) {
+		envelope.JSON(ctx, http.StatusOK, cardAuthorizationResponse{DeclineReason: db.DeclineInvalidCard})
+		return
+	}
+	found, err := server.store.GetCardByFingerprint(ctx, server.cards.Fingerprint(req.CardNumber))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			envelope.JSON(ctx, http.StatusOK, cardAuthorizationResponse{DeclineReason: db.DeclineInvalidCard})
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	arg := db.AuthorizeCardTxParams{
+		NetworkTransactionID: req.NetworkTransactionID,
+		CardID:               found.ID,
+		Amount:               req.Amount,
+		Currency:             req.Currency,
+		MerchantName:         req.MerchantName,
+		MerchantCategory:     req.MerchantCategory,
+		AlertTask:            worker.NewAccountAlertTask,
+		RewardTask:           worker.NewAccrueRewardsTask,
+	}
+	switch {
+	case req.ExpiryMonth != found.ExpiryMonth || req.ExpiryYear != found.ExpiryYear:
+		arg.Decline = db.DeclineInvalidCard
+	case card.Expired(found.ExpiryMonth, found.ExpiryYear, time.Now()):
+		arg.Decline = db.DeclineExpiredCard
+	case !server.cards.CheckCVV(req.CardNumber, found.ExpiryMonth, found.ExpiryYear, req.CVV):
+		arg.Decline = db.DeclineInvalidCVV
+	default:
+		account, err := server.store.GetAccount(ctx, found.AccountID)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		matches, err := server.screener.ScreenAccount(ctx, account)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		if len(matches) > 0 {
+			arg.Decline = db.DeclineDoNotHonor
+		}
+	}
+
+	result, err := server.store.AuthorizeCardTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if result.Authorization.Approved && !result.Replayed {
+		metrics.ObserveTransfer(req.Currency, req.Amount)
+	}
+	envelope.JSON(ctx, http.StatusOK, cardAuthorizationResponse{
+		Approved:        result.Authorization.Approved,
+		AuthorizationID: result.Authorization.ID,
+		DeclineReason:   result.Authorization.DeclineReason,
+	})
+}
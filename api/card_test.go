@@ -0,0 +1,532 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/card"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueCardAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	other := randomAccount("other")
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_id": account.ID, "daily_limit": 500},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CreateCard(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCardParams) (db.Card, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Len(t, arg.Fingerprint, 64)
+						require.Len(t, arg.Last4, 4)
+						require.Equal(t, int64(500), arg.DailyLimit)
+						return db.Card{
+							ID:          1,
+							AccountID:   arg.AccountID,
+							Fingerprint: arg.Fingerprint,
+							Last4:       arg.Last4,
+							ExpiryMonth: arg.ExpiryMonth,
+							ExpiryYear:  arg.ExpiryYear,
+							DailyLimit:  arg.DailyLimit,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "fingerprint")
+
+				var rsp issueCardResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.True(t, card.ValidNumber(rsp.Details.Number))
+				require.Equal(t, rsp.Details.Number[12:], rsp.Card.Last4)
+				require.Len(t, rsp.Details.CVV, 3)
+			},
+		},
+		{
+			name: "NegativeLimit",
+			body: gin.H{"account_id": account.ID, "per_transaction_limit": -1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCard(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{"account_id": other.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().CreateCard(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/cards", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetCardFrozenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	userCard := db.Card{ID: util.RandomInt(1, 1000), AccountID: account.ID, Last4: "1234"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Freeze",
+			body:     gin.H{"frozen": true},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(userCard.ID)).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				frozen := userCard
+				frozen.FrozenAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					SetCardFrozen(gomock.Any(), gomock.Eq(db.SetCardFrozenParams{ID: userCard.ID, Frozen: true})).
+					Times(1).
+					Return(frozen, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cardResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.True(t, rsp.Frozen)
+			},
+		},
+		{
+			name:     "Unfreeze",
+			body:     gin.H{"frozen": false},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Any()).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					SetCardFrozen(gomock.Any(), gomock.Eq(db.SetCardFrozenParams{ID: userCard.ID, Frozen: false})).
+					Times(1).
+					Return(userCard, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingFrozen",
+			body:     gin.H{},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Any()).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().SetCardFrozen(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			body:     gin.H{"frozen": true},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Any()).Times(1).Return(db.Card{}, db.ErrRecordNotFound)
+				store.EXPECT().SetCardFrozen(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			body:     gin.H{"frozen": true},
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Any()).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().SetCardFrozen(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/cards/%d/frozen", userCard.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetCardLimitsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	userCard := db.Card{ID: util.RandomInt(1, 1000), AccountID: account.ID}
+	arg := db.UpdateCardLimitsParams{ID: userCard.ID, PerTransactionLimit: 100, DailyLimit: 300}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetCard(gomock.Any(), gomock.Eq(userCard.ID)).Times(1).Return(userCard, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		UpdateCardLimits(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.Card{ID: userCard.ID, AccountID: account.ID, PerTransactionLimit: 100, DailyLimit: 300}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	data, err := json.Marshal(gin.H{"per_transaction_limit": 100, "daily_limit": 300})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/cards/%d/limits", userCard.ID), bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp cardResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, int64(100), rsp.PerTransactionLimit)
+	require.Equal(t, int64(300), rsp.DailyLimit)
+}
+
+func TestAuthorizeCardPaymentAPI(t *testing.T) {
+	issuer, err := card.NewIssuer(util.RandomString(32), util.RandomString(32))
+	require.NoError(t, err)
+	details, err := issuer.Issue(time.Now())
+	require.NoError(t, err)
+
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	userCard := db.Card{
+		ID:          util.RandomInt(1, 1000),
+		AccountID:   account.ID,
+		Fingerprint: issuer.Fingerprint(details.Number),
+		Last4:       details.Number[12:],
+		ExpiryMonth: details.ExpiryMonth,
+		ExpiryYear:  details.ExpiryYear,
+	}
+
+	body := gin.H{
+		"network_transaction_id": "txn-" + util.RandomString(12),
+		"card_number":            details.Number,
+		"expiry_month":           details.ExpiryMonth,
+		"expiry_year":            details.ExpiryYear,
+		"cvv":                    details.CVV,
+		"amount":                 42,
+		"currency":               util.USD,
+		"merchant_name":          "Corner Shop",
+		"merchant_category":      "5411",
+	}
+	with := func(key string, value interface{}) gin.H {
+		changed := gin.H{}
+		for k, v := range body {
+			changed[k] = v
+		}
+		changed[key] = value
+		return changed
+	}
+	wrongCVV := "000"
+	if details.CVV == wrongCVV {
+		wrongCVV = "001"
+	}
+	// declined expects the authorization to be recorded as declined for reason
+	declined := func(store *mockdb.MockStore, reason string) {
+		store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Eq(userCard.Fingerprint)).Times(1).Return(userCard, nil)
+		store.EXPECT().
+			AuthorizeCardTx(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+				require.Equal(t, reason, arg.Decline)
+				return db.AuthorizeCardTxResult{Authorization: db.CardAuthorization{ID: 2, DeclineReason: reason}}, nil
+			})
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		sign          func(request *http.Request, data []byte)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Approved",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Eq(userCard.Fingerprint)).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					AuthorizeCardTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+						require.Equal(t, body["network_transaction_id"], arg.NetworkTransactionID)
+						require.Equal(t, userCard.ID, arg.CardID)
+						require.Equal(t, int64(42), arg.Amount)
+						require.Equal(t, "Corner Shop", arg.MerchantName)
+						require.Equal(t, "5411", arg.MerchantCategory)
+						require.Empty(t, arg.Decline)
+						return db.AuthorizeCardTxResult{Authorization: db.CardAuthorization{ID: 1, Approved: true}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cardAuthorizationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, cardAuthorizationResponse{Approved: true, AuthorizationID: 1}, rsp)
+			},
+		},
+		{
+			// a replay of an approved request gets its answer, without a second debit
+			name: "Replayed",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					AuthorizeCardTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuthorizeCardTxResult{Authorization: db.CardAuthorization{ID: 1, Approved: true}, Replayed: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cardAuthorizationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, cardAuthorizationResponse{Approved: true, AuthorizationID: 1}, rsp)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(1).Return(userCard, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					AuthorizeCardTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuthorizeCardTxResult{Authorization: db.CardAuthorization{ID: 3, DeclineReason: db.DeclineInsufficientFunds}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cardAuthorizationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.False(t, rsp.Approved)
+				require.Equal(t, db.DeclineInsufficientFunds, rsp.DeclineReason)
+			},
+		},
+		{
+			name: "WrongCVV",
+			body: with("cvv", wrongCVV),
+			buildStubs: func(store *mockdb.MockStore) {
+				declined(store, db.DeclineInvalidCVV)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), db.DeclineInvalidCVV)
+			},
+		},
+		{
+			name: "WrongExpiry",
+			body: with("expiry_year", details.ExpiryYear+1),
+			buildStubs: func(store *mockdb.MockStore) {
+				declined(store, db.DeclineInvalidCard)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ScreeningMatch",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				declined(store, db.DeclineDoNotHonor)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{"account:1"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownCard",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(1).Return(db.Card{}, db.ErrRecordNotFound)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp cardAuthorizationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, cardAuthorizationResponse{DeclineReason: db.DeclineInvalidCard}, rsp)
+			},
+		},
+		{
+			name: "InvalidNumber",
+			body: with("card_number", "4111111111111111"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), db.DeclineInvalidCard)
+			},
+		},
+		{
+			name: "InvalidSignature",
+			body: body,
+			sign: func(request *http.Request, data []byte) {
+				timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+				request.Header.Set(cardTimestampHeader, timestamp)
+				request.Header.Set(cardSignatureHeader, issuer.Sign(timestamp, []byte(`{"amount":1}`)))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredSignature",
+			body: body,
+			sign: func(request *http.Request, data []byte) {
+				timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+				request.Header.Set(cardTimestampHeader, timestamp)
+				request.Header.Set(cardSignatureHeader, issuer.Sign(timestamp, data))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoNetworkTransactionID",
+			body: with("network_transaction_id", ""),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: with("amount", 0),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCardByFingerprint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.cards = issuer
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, cardAuthorizationsRoute, bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.sign != nil {
+				tc.sign(request, data)
+			} else {
+				timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+				request.Header.Set(cardTimestampHeader, timestamp)
+				request.Header.Set(cardSignatureHeader, issuer.Sign(timestamp, data))
+			}
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
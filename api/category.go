@@ -0,0 +1,161 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The category routes let users categorize and tag the entries of their accounts, e.g. rent or
+// groceries, by hand or by rules that categorize the entries of the new transfers.
+
+// categoryErrStatus maps the errors of the categories to a response status.
+func categoryErrStatus(err error) int {
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+	return errStatus(err)
+}
+
+type entryCategoryURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type setEntryCategoryRequest struct {
+	Category string   `json:"category" binding:"omitempty,category"`
+	Tags     []string `json:"tags" binding:"max=10,dive,category"`
+}
+
+// setEntryCategory sets the category and the tags of an entry of the caller. An empty category
+// leaves the entry uncategorized, and no rule categorizes it anymore.
+func (server *Server) setEntryCategory(ctx *gin.Context) {
+	var uri entryCategoryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req setEntryCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	entry, err := server.store.GetEntry(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(categoryErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if _, ok := server.authorizeAccount(ctx, entry.AccountID); !ok {
+		return
+	}
+
+	category, err := server.store.SetEntryCategory(ctx, db.SetEntryCategoryParams{
+		EntryID:   entry.ID,
+		AccountID: entry.AccountID,
+		Category:  req.Category,
+		Tags:      uniqueTags(req.Tags),
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, category)
+}
+
+// uniqueTags drops the repeated tags, keeping their order.
+func uniqueTags(tags []string) []string {
+	unique := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
+
+// listCategoryRules lists the category rules of the caller, in the order they are tried.
+func (server *Server) listCategoryRules(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rules, err := server.store.ListCategoryRules(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, rules)
+}
+
+type createCategoryRuleRequest struct {
+	Category              string `json:"category" binding:"required,category"`
+	CounterpartyAccountID int64  `json:"counterparty_account_id" binding:"omitempty,min=1"`
+	Direction             string `json:"direction" binding:"omitempty,oneof=debit credit"`
+	MemoContains          string `json:"memo_contains" binding:"max=100"`
+}
+
+// createCategoryRule adds a rule categorizing the entries of the next transfers of the caller
+// whose criteria all match. The first rule matching an entry, oldest first, sets its category.
+func (server *Server) createCategoryRule(ctx *gin.Context) {
+	var req createCategoryRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	req.MemoContains = strings.TrimSpace(req.MemoContains)
+	if req.CounterpartyAccountID == 0 && req.Direction == "" && req.MemoContains == "" {
+		err := errors.New("a rule needs a counterparty_account_id, a direction or a memo_contains")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	rule, err := server.store.CreateCategoryRule(ctx, db.CreateCategoryRuleParams{
+		Owner:                 payload.Username,
+		Category:              req.Category,
+		CounterpartyAccountID: pgtype.Int8{Int64: req.CounterpartyAccountID, Valid: req.CounterpartyAccountID != 0},
+		Direction:             req.Direction,
+		MemoContains:          req.MemoContains,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, rule)
+}
+
+type categoryRuleURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteCategoryRule deletes a rule of the caller. The entries it categorized keep their category.
+func (server *Server) deleteCategoryRule(ctx *gin.Context) {
+	var uri categoryRuleURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	rule, err := server.store.GetCategoryRule(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(categoryErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if rule.Owner != payload.Username {
+		err := errors.New("category rule doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	if err := server.store.DeleteCategoryRule(ctx, rule.ID); err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEntryCategoryAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	entry := db.Entry{ID: util.RandomInt(1, 1000), AccountID: account.ID, Amount: -util.RandomInt(1, 1000)}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     gin.H{"category": "groceries", "tags": []string{"weekly", "family", "weekly"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(entry, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SetEntryCategoryParams{
+					EntryID:   entry.ID,
+					AccountID: account.ID,
+					Category:  "groceries",
+					Tags:      []string{"weekly", "family"},
+				}
+				store.EXPECT().
+					SetEntryCategory(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.EntryCategory{EntryID: arg.EntryID, AccountID: arg.AccountID, Category: arg.Category, Tags: arg.Tags}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.EntryCategory
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "groceries", rsp.Category)
+				require.Equal(t, []string{"weekly", "family"}, rsp.Tags)
+			},
+		},
+		{
+			name:     "Uncategorize",
+			body:     gin.H{},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(entry, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SetEntryCategoryParams{
+					EntryID:   entry.ID,
+					AccountID: account.ID,
+					Tags:      []string{},
+				}
+				store.EXPECT().
+					SetEntryCategory(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.EntryCategory{EntryID: arg.EntryID, AccountID: arg.AccountID, Tags: arg.Tags}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidCategory",
+			body:     gin.H{"category": "Groceries!"},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidTag",
+			body:     gin.H{"category": "rent", "tags": []string{"a"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "EntryNotFound",
+			body:     gin.H{"category": "rent"},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Any()).Times(1).Return(db.Entry{}, db.ErrRecordNotFound)
+				store.EXPECT().SetEntryCategory(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			body:     gin.H{"category": "rent"},
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Any()).Times(1).Return(entry, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SetEntryCategory(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/entries/%d/category", entry.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateCategoryRuleAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"category": "rent", "counterparty_account_id": 42, "direction": db.DirectionDebit, "memo_contains": " Rent "},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCategoryRuleParams{
+					Owner:                 user.Username,
+					Category:              "rent",
+					CounterpartyAccountID: pgtype.Int8{Int64: 42, Valid: true},
+					Direction:             db.DirectionDebit,
+					MemoContains:          "Rent",
+				}
+				store.EXPECT().
+					CreateCategoryRule(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CategoryRule{ID: 1, Owner: arg.Owner, Category: arg.Category}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.CategoryRule
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "rent", rsp.Category)
+			},
+		},
+		{
+			name: "Direction",
+			body: gin.H{"category": "salary", "direction": db.DirectionCredit},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateCategoryRuleParams{
+					Owner:     user.Username,
+					Category:  "salary",
+					Direction: db.DirectionCredit,
+				}
+				store.EXPECT().
+					CreateCategoryRule(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CategoryRule{ID: 1, Owner: arg.Owner, Category: arg.Category}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoCriterion",
+			body: gin.H{"category": "rent", "memo_contains": " "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCategoryRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDirection",
+			body: gin.H{"category": "rent", "direction": "both"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateCategoryRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/category_rules", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListCategoryRulesAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, _ := randomUser(t)
+	rules := []db.CategoryRule{
+		{ID: 1, Owner: user.Username, Category: "rent", MemoContains: "rent"},
+		{ID: 2, Owner: user.Username, Category: "salary", Direction: db.DirectionCredit},
+	}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCategoryRules(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(rules, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/category_rules", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []db.CategoryRule
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, rules, rsp)
+}
+
+func TestDeleteCategoryRuleAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	rule := db.CategoryRule{ID: util.RandomInt(1, 1000), Owner: user.Username, Category: "rent"}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCategoryRule(gomock.Any(), gomock.Eq(rule.ID)).Times(1).Return(rule, nil)
+				store.EXPECT().DeleteCategoryRule(gomock.Any(), gomock.Eq(rule.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCategoryRule(gomock.Any(), gomock.Any()).Times(1).Return(db.CategoryRule{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteCategoryRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCategoryRule(gomock.Any(), gomock.Any()).Times(1).Return(rule, nil)
+				store.EXPECT().DeleteCategoryRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/category_rules/%d", rule.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// The consent routes let the admins publish the versions of the terms of service and of the
+// privacy policy, and the users accept them. Until a user accepted the latest version of each,
+// the sensitive operations, those moving money or opening a product, answer 403.
+
+// errImpersonatedConsent is returned when an impersonator accepts a document, which only the
+// user can.
+var errImpersonatedConsent = errors.New("an impersonator can't accept a document for the user")
+
+// requireConsents answers 403 to the users who haven't accepted the latest legal documents yet.
+func requireConsents(store db.ConsentStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if err := db.CheckConsents(ctx, store, payload.Username); err != nil {
+			if errors.Is(err, db.ErrConsentRequired) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, errResponse(ctx, err))
+				return
+			}
+			ctx.AbortWithStatusJSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// consentErrStatus maps the errors of the consents and the legal documents to a response status.
+func consentErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrNotLatestLegalDocument), errors.Is(err, admin.ErrInvalidLegalDocument):
+		return http.StatusBadRequest
+	case db.ErrorCode(err) == db.UniqueViolation:
+		return http.StatusForbidden
+	}
+	return operatorErrStatus(err)
+}
+
+// listLegalDocuments lists the latest version of each document, e.g. for a sign up form.
+func (server *Server) listLegalDocuments(ctx *gin.Context) {
+	documents, err := server.store.ListLatestLegalDocuments(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, documents)
+}
+
+type consentsResponse struct {
+	// Pending are the latest documents the user has to accept.
+	Pending  []db.LegalDocument `json:"pending"`
+	Accepted []db.UserConsent   `json:"accepted"`
+}
+
+// getConsents returns the documents the caller has to accept, and the versions they accepted,
+// the latest first.
+func (server *Server) getConsents(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.store.ListPendingLegalDocuments(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	accepted, err := server.store.ListUserConsents(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, consentsResponse{Pending: pending, Accepted: accepted})
+}
+
+type acceptConsentRequest struct {
+	Kind    string `json:"kind" binding:"required,oneof=terms privacy"`
+	Version string `json:"version" binding:"required,max=32"`
+}
+
+// acceptConsent records that the caller accepted the latest version of a document, with the ip
+// and the user agent they accepted it from.
+func (server *Server) acceptConsent(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.Impersonated() {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errImpersonatedConsent))
+		return
+	}
+
+	var req acceptConsentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	consent, err := db.AcceptLegalDocument(ctx, server.store, db.CreateUserConsentParams{
+		Username:  payload.Username,
+		Kind:      req.Kind,
+		Version:   req.Version,
+		ClientIp:  clientIP(ctx),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		ctx.JSON(consentErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, consent)
+}
+
+type publishLegalDocumentRequest struct {
+	Kind    string `json:"kind" binding:"required,oneof=terms privacy"`
+	Version string `json:"version" binding:"required,max=32"`
+	URL     string `json:"url" binding:"required,url,max=500"`
+	Reason  string `json:"reason" binding:"required,max=500"`
+}
+
+// publishLegalDocument publishes a version of a document, which the users must accept before
+// their next sensitive operation.
+func (server *Server) publishLegalDocument(ctx *gin.Context) {
+	if !requireAdmin(ctx, "publish legal documents") {
+		return
+	}
+
+	var req publishLegalDocumentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	document, err := operator.PublishLegalDocument(ctx, admin.PublishLegalDocumentParams{
+		Kind:    req.Kind,
+		Version: req.Version,
+		URL:     req.URL,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(consentErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, document)
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireConsents(t *testing.T) {
+	user, _ := randomUser(t)
+	terms := db.LegalDocument{Kind: db.LegalTerms, Version: "2026-10", Url: "https://bank.example.com/terms/2026-10"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListPendingLegalDocuments(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return([]db.LegalDocument{terms}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	body, err := json.Marshal(gin.H{"from_account_id": 1, "to_account_id": 2, "amount": 10, "currency": util.USD})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/v1/transfers", bytes.NewReader(body))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Contains(t, recorder.Body.String(), db.ErrConsentRequired.Error())
+}
+
+func TestGetConsentsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	privacy := db.LegalDocument{Kind: db.LegalPrivacy, Version: "3", Url: "https://bank.example.com/privacy/3"}
+	accepted := []db.UserConsent{{Username: user.Username, Kind: db.LegalTerms, Version: "2026-10"}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListPendingLegalDocuments(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.LegalDocument{privacy}, nil)
+	store.EXPECT().ListUserConsents(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(accepted, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/users/me/consents", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp consentsResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, []db.LegalDocument{privacy}, rsp.Pending)
+	require.Equal(t, accepted, rsp.Accepted)
+}
+
+func TestAcceptConsentAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	latest := []db.LegalDocument{
+		{Kind: db.LegalPrivacy, Version: "3"},
+		{Kind: db.LegalTerms, Version: "2026-10"},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		impersonated  bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"kind": db.LegalTerms, "version": "2026-10"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListLatestLegalDocuments(gomock.Any()).Times(1).Return(latest, nil)
+				store.EXPECT().
+					CreateUserConsent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserConsentParams) (db.UserConsent, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, db.LegalTerms, arg.Kind)
+						require.Equal(t, "2026-10", arg.Version)
+						require.Equal(t, "bank-app/1.0", arg.UserAgent)
+						// the forged header of a client that isn't a trusted proxy is ignored
+						require.Equal(t, "192.0.2.1", arg.ClientIp)
+						return db.UserConsent{Username: arg.Username, Kind: arg.Kind, Version: arg.Version}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.UserConsent
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "2026-10", rsp.Version)
+			},
+		},
+		{
+			name: "NotLatest",
+			body: gin.H{"kind": db.LegalTerms, "version": "2025-01"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListLatestLegalDocuments(gomock.Any()).Times(1).Return(latest, nil)
+				store.EXPECT().CreateUserConsent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownKind",
+			body: gin.H{"kind": "cookies", "version": "1"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListLatestLegalDocuments(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "Impersonated",
+			body:         gin.H{"kind": db.LegalTerms, "version": "2026-10"},
+			impersonated: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetImpersonation(gomock.Any(), gomock.Any()).Times(1).Return(db.Impersonation{}, nil)
+				store.EXPECT().CreateUserConsent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/me/consents", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", "bank-app/1.0")
+			request.Header.Set("X-Forwarded-For", "203.0.113.7")
+			request.RemoteAddr = "192.0.2.1:54321"
+			if tc.impersonated {
+				accessToken, _, err := server.tokenMaker.CreateImpersonationToken(user.Username, util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+			} else {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			}
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPublishLegalDocumentAPI(t *testing.T) {
+	body := gin.H{"kind": db.LegalTerms, "version": "2026-10", "url": "https://bank.example.com/terms/2026-10", "reason": "new fees"}
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLegalDocumentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLegalDocumentTxParams) (db.LegalDocument, error) {
+						require.Equal(t, "admin", arg.CreatedBy)
+						require.Equal(t, admin.ActionPublishLegalDocument, arg.Audit.Action)
+						return db.LegalDocument{Kind: arg.Kind, Version: arg.Version, Url: arg.Url, CreatedBy: arg.CreatedBy}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.LegalDocument
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "2026-10", rsp.Version)
+			},
+		},
+		{
+			name: "NotAdmin",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateLegalDocumentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/admin/legal_documents", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	"github.com/backendmaster/simple_bank/currency"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+)
+
+// currencyErrStatus maps the errors of the currencies to a response status.
+func currencyErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, admin.ErrCurrencyInUse):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+type currencyResponse struct {
+	Code string `json:"code"`
+	// MinorUnits is the number of decimal places of the currency, which the amounts in it are
+	// counted in: an amount of 1234 is 12.34 USD but 1234 JPY.
+	MinorUnits int `json:"minor_units"`
+}
+
+// listCurrencies lists the currencies the accounts can be opened and the money moved in.
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	codes := util.SupportedCurrencies()
+	rsp := make([]currencyResponse, 0, len(codes))
+	for _, code := range codes {
+		registered, _ := util.LookupCurrency(code)
+		rsp = append(rsp, currencyResponse{Code: registered.Code, MinorUnits: registered.MinorUnits})
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+// listAllCurrencies lists the currencies of the registry, the disabled ones included.
+func (server *Server) listAllCurrencies(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the currencies") {
+		return
+	}
+
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, currencies)
+}
+
+type currencyURI struct {
+	Code string `uri:"code" binding:"required,len=3,uppercase"`
+}
+
+type setCurrencyEnabledRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Reason  string `json:"reason" binding:"required,max=500"`
+}
+
+// setCurrencyEnabled offers a currency of the registry or stops offering it. This instance applies
+// the change right away, the others when they reload the registry.
+func (server *Server) setCurrencyEnabled(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change currencies") {
+		return
+	}
+
+	var uri currencyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req setCurrencyEnabledRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	updated, err := operator.SetCurrencyEnabled(ctx, uri.Code, *req.Enabled, req.Reason)
+	if err != nil {
+		ctx.JSON(currencyErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	util.SetCurrency(currency.Registered(updated))
+	envelope.JSON(ctx, http.StatusOK, updated)
+}
+
+// bindAmount sets amount, in the minor units of currency, from amountDecimal when the request
+// wrote it in units of the currency instead. It answers 400 and returns false when amountDecimal
+// can't be an amount in currency.
+func bindAmount(ctx *gin.Context, amount *int64, amountDecimal, currency string) bool {
+	if amountDecimal == "" {
+		return true
+	}
+	parsed, err := money.Parse(amountDecimal, currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return false
+	}
+	*amount = parsed.Amount
+	return true
+}
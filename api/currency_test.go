@@ -0,0 +1,177 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrenciesAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/currencies", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "bob", util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp []currencyResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, []currencyResponse{
+		{Code: util.CAD, MinorUnits: 2},
+		{Code: util.EUR, MinorUnits: 2},
+		{Code: util.USD, MinorUnits: 2},
+	}, rsp)
+}
+
+func TestSetCurrencyEnabledAPI(t *testing.T) {
+	jpy := db.Currency{Code: "JPY", MinorUnits: 0}
+
+	testCases := []struct {
+		name          string
+		code          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: jpy.Code,
+			body: gin.H{"enabled": true, "reason": "launch in Japan"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq(jpy.Code)).Times(1).Return(jpy, nil)
+				store.EXPECT().
+					SetCurrencyEnabledTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetCurrencyEnabledTxParams) (db.Currency, error) {
+						require.True(t, arg.Enabled)
+						require.Equal(t, admin.ActionSetCurrencyEnabled, arg.Audit.Action)
+						enabled := jpy
+						enabled.Enabled = true
+						return enabled, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// offered by this instance right away
+				require.True(t, util.IsSupportedCurrency(jpy.Code))
+			},
+		},
+		{
+			name: "InUse",
+			code: util.EUR,
+			body: gin.H{"enabled": false, "reason": "leaving the eurozone"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCurrency(gomock.Any(), gomock.Eq(util.EUR)).
+					Times(1).
+					Return(db.Currency{Code: util.EUR, MinorUnits: 2, Enabled: true}, nil)
+				store.EXPECT().ListTenants(gomock.Any()).Times(1).Return([]db.Tenant{randomTenant()}, nil)
+				store.EXPECT().SetCurrencyEnabledTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.True(t, util.IsSupportedCurrency(util.EUR))
+			},
+		},
+		{
+			name: "NotFound",
+			code: "XYZ",
+			body: gin.H{"enabled": true, "reason": "launch"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("XYZ")).Times(1).Return(db.Currency{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "jpy",
+			body: gin.H{"enabled": true, "reason": "launch in Japan"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingEnabled",
+			code: jpy.Code,
+			body: gin.H{"reason": "launch in Japan"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			code: jpy.Code,
+			body: gin.H{"enabled": true, "reason": "launch in Japan"},
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				util.SetCurrencies([]util.Currency{
+					{Code: util.USD, MinorUnits: 2, Enabled: true},
+					{Code: util.EUR, MinorUnits: 2, Enabled: true},
+					{Code: util.CAD, MinorUnits: 2, Enabled: true},
+				})
+			})
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/currencies/%s", tc.code)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+type deadTaskResponse struct {
+	ID           string          `json:"id"`
+	Queue        string          `json:"queue"`
+	Type         string          `json:"type"`
+	Payload      json.RawMessage `json:"payload"`
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+	LastErr      string          `json:"last_err"`
+	LastFailedAt time.Time       `json:"last_failed_at"`
+}
+
+func newDeadTaskResponse(info *asynq.TaskInfo) deadTaskResponse {
+	payload := json.RawMessage(info.Payload)
+	if !json.Valid(payload) {
+		// json.RawMessage encodes []byte as is, which would break the response
+		payload, _ = json.Marshal(info.Payload)
+	}
+	return deadTaskResponse{
+		ID:           info.ID,
+		Queue:        info.Queue,
+		Type:         info.Type,
+		Payload:      payload,
+		Retried:      info.Retried,
+		MaxRetry:     info.MaxRetry,
+		LastErr:      info.LastErr,
+		LastFailedAt: info.LastFailedAt,
+	}
+}
+
+// deadTaskErrStatus maps the errors of the task inspector to a response status.
+func deadTaskErrStatus(err error) int {
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+type listDeadTasksRequest struct {
+	Queue    string `form:"queue" binding:"required"`
+	PageID   int    `form:"page_id" binding:"required,min=1"`
+	PageSize int    `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listDeadTasks(ctx *gin.Context) {
+	if !requireAdmin(ctx, "inspect dead tasks") {
+		return
+	}
+
+	var req listDeadTasksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	tasks, err := server.taskInspector.ListDeadTasks(req.Queue, req.PageSize, req.PageID)
+	if err != nil {
+		ctx.JSON(deadTaskErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]deadTaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		rsp = append(rsp, newDeadTaskResponse(task))
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+type deadTaskRequest struct {
+	Queue string `uri:"queue" binding:"required"`
+	ID    string `uri:"id" binding:"required"`
+}
+
+func (server *Server) getDeadTask(ctx *gin.Context) {
+	if !requireAdmin(ctx, "inspect dead tasks") {
+		return
+	}
+
+	var req deadTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	task, err := server.taskInspector.GetDeadTask(req.Queue, req.ID)
+	if err != nil {
+		ctx.JSON(deadTaskErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newDeadTaskResponse(task))
+}
+
+// requeueDeadTask sends the task back to its queue, e.g. once the smtp server is reachable again.
+func (server *Server) requeueDeadTask(ctx *gin.Context) {
+	if !requireAdmin(ctx, "requeue dead tasks") {
+		return
+	}
+
+	var req deadTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if err := server.taskInspector.RequeueDeadTask(req.Queue, req.ID); err != nil {
+		ctx.JSON(deadTaskErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (server *Server) discardDeadTask(ctx *gin.Context) {
+	if !requireAdmin(ctx, "discard dead tasks") {
+		return
+	}
+
+	var req deadTaskRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if err := server.taskInspector.DiscardDeadTask(req.Queue, req.ID); err != nil {
+		ctx.JSON(deadTaskErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
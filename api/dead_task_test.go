@@ -0,0 +1,204 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	mockwk "github.com/backendmaster/simple_bank/worker/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomDeadTask() *asynq.TaskInfo {
+	return &asynq.TaskInfo{
+		ID:           util.RandomString(10),
+		Queue:        worker.QueueCritical,
+		Type:         worker.TaskSendVerifyEmail,
+		Payload:      []byte(`{"username":"alice"}`),
+		State:        asynq.TaskStateArchived,
+		MaxRetry:     10,
+		Retried:      10,
+		LastErr:      "smtp is down",
+		LastFailedAt: time.Now().Truncate(time.Second),
+	}
+}
+
+func TestDeadTasksAPI(t *testing.T) {
+	task := randomDeadTask()
+	taskURL := fmt.Sprintf("/admin/dead_tasks/%s/%s", task.Queue, task.ID)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		role          string
+		buildStubs    func(inspector *mockwk.MockTaskInspector)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/admin/dead_tasks?queue=critical&page_id=1&page_size=5",
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					ListDeadTasks(gomock.Eq(worker.QueueCritical), gomock.Eq(5), gomock.Eq(1)).
+					Times(1).
+					Return([]*asynq.TaskInfo{task}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp []deadTaskResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp, 1)
+				require.Equal(t, task.ID, rsp[0].ID)
+				require.JSONEq(t, string(task.Payload), string(rsp[0].Payload))
+				require.Equal(t, task.LastErr, rsp[0].LastErr)
+			},
+		},
+		{
+			name:   "ListMissingQueue",
+			method: http.MethodGet,
+			url:    "/admin/dead_tasks?page_id=1&page_size=5",
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().ListDeadTasks(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "ListNotAdmin",
+			method: http.MethodGet,
+			url:    "/admin/dead_tasks?queue=critical&page_id=1&page_size=5",
+			role:   util.DepositorRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().ListDeadTasks(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			url:    taskURL,
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					GetDeadTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp deadTaskResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, task.Type, rsp.Type)
+				require.Equal(t, task.Retried, rsp.Retried)
+			},
+		},
+		{
+			name:   "GetNotFound",
+			method: http.MethodGet,
+			url:    taskURL,
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					GetDeadTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Requeue",
+			method: http.MethodPost,
+			url:    taskURL + "/requeue",
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					RequeueDeadTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:   "RequeueInternalError",
+			method: http.MethodPost,
+			url:    taskURL + "/requeue",
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					RequeueDeadTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errors.New("redis is down"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "Discard",
+			method: http.MethodDelete,
+			url:    taskURL,
+			role:   util.AdminRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().
+					DiscardDeadTask(gomock.Eq(task.Queue), gomock.Eq(task.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:   "DiscardNotAdmin",
+			method: http.MethodDelete,
+			url:    taskURL,
+			role:   util.DepositorRole,
+			buildStubs: func(inspector *mockwk.MockTaskInspector) {
+				inspector.EXPECT().DiscardDeadTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			inspector := mockwk.NewMockTaskInspector(ctrl)
+			tc.buildStubs(inspector)
+
+			server := newTestServer(t, nil)
+			server.taskInspector = inspector
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
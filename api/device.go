@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// The device routes let the users list, name and revoke the devices they logged in from. A login
+// binds its refresh token to its device, the fingerprint it sends or, without one, that of its user
+// agent: the token only renews an access token from the same device, and not once the device is
+// revoked. A device is trusted once its user confirmed it with a code emailed to them, which the
+// first renewal from the device asks for.
+
+var (
+	errDeviceRevoked              = errors.New("device is revoked")
+	errDeviceMismatch             = errors.New("refresh token is bound to another device")
+	errDeviceVerificationRequired = errors.New("verify the device with the code emailed to you")
+)
+
+// deviceResponse is a device without its fingerprint and the hash of its one-time code.
+type deviceResponse struct {
+	ID         int64              `json:"id"`
+	Name       string             `json:"name"`
+	Trusted    bool               `json:"trusted"`
+	TrustedAt  pgtype.Timestamptz `json:"trusted_at"`
+	LastSeenAt time.Time          `json:"last_seen_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func newDeviceResponse(device db.Device) deviceResponse {
+	return deviceResponse{
+		ID:         device.ID,
+		Name:       device.Name,
+		Trusted:    device.TrustedAt.Valid,
+		TrustedAt:  device.TrustedAt,
+		LastSeenAt: device.LastSeenAt,
+		CreatedAt:  device.CreatedAt,
+	}
+}
+
+// deviceErrStatus maps the errors of the devices to a response status.
+func deviceErrStatus(err error) int {
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+	return errStatus(err)
+}
+
+// checkDevice checks the device a session is bound to before its refresh token renews an access
+// token: it must be the device sending fingerprint, not revoked, and trusted. An untrusted device
+// is trusted by code, or gets a new code emailed. It writes the error response and returns false
+// when the token can't renew.
+func (server *Server) checkDevice(ctx *gin.Context, session db.Session, fingerprint, code string) bool {
+	device, err := server.store.GetDevice(ctx, session.DeviceID.Int64)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	if device.RevokedAt.Valid {
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errDeviceRevoked))
+		return false
+	}
+	if device.Fingerprint != fingerprint {
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errDeviceMismatch))
+		return false
+	}
+	if device.TrustedAt.Valid {
+		return true
+	}
+	return server.verifyDevice(ctx, device, code)
+}
+
+// bindSessionDevice binds a session bound to no device, logged in before every login was, to the
+// device renewing its refresh token. The token could be renewed from anywhere until then, so the
+// device must be confirmed with a code emailed to its user even when it is trusted. It writes the
+// error response and returns false when the token can't renew.
+func (server *Server) bindSessionDevice(ctx *gin.Context, session db.Session, fingerprint, code string) bool {
+	device, err := server.store.UpsertDevice(ctx, db.LoginDevice(session.Username, fingerprint, "", ctx.Request.UserAgent()))
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	if !server.verifyDevice(ctx, device, code) {
+		return false
+	}
+
+	_, err = server.store.BindSessionDevice(ctx, db.BindSessionDeviceParams{
+		ID:       session.ID,
+		DeviceID: pgtype.Int8{Int64: device.ID, Valid: true},
+	})
+	// a concurrent renewal bound it already
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// verifyDevice trusts a device by the code emailed to its user, or emails a new code when code is
+// empty. It writes the error response and returns false when the device isn't trusted.
+func (server *Server) verifyDevice(ctx *gin.Context, device db.Device, code string) bool {
+	if code == "" {
+		server.sendDeviceVerification(ctx, device)
+		return false
+	}
+	if !device.VerificationSentAt.Valid || time.Since(device.VerificationSentAt.Time) > fraud.OTPValidity {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errOTPExpired))
+		return false
+	}
+	if !fraud.CheckOTP(code, device.VerificationHash) {
+		attempted, err := server.store.AddDeviceVerificationAttempt(ctx, device.ID)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return false
+		}
+		// guessing the code of a stolen refresh token takes the device down with it
+		if attempted.VerificationAttempts >= fraud.MaxOTPAttempts {
+			_, err = server.store.RevokeDeviceTx(ctx, db.RevokeDeviceParams{ID: device.ID, Username: device.Username})
+			if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(errStatus(err), errResponse(ctx, err))
+				return false
+			}
+			log.Warn().Int64("device id", device.ID).Str("username", device.Username).
+				Msg("device revoked after too many wrong one-time codes")
+		}
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errWrongOTP))
+		return false
+	}
+
+	if _, err := server.store.TrustDevice(ctx, device.ID); err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// sendDeviceVerification emails a new one-time code confirming a device and answers 403 for the
+// client to renew again with the code.
+func (server *Server) sendDeviceVerification(ctx *gin.Context, device db.Device) {
+	code, hash, err := fraud.NewOTP()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	_, err = server.store.SendDeviceVerificationTx(ctx, db.SendDeviceVerificationTxParams{
+		SetDeviceVerificationParams: db.SetDeviceVerificationParams{
+			ID:               device.ID,
+			VerificationHash: hash,
+		},
+		AfterUpdate: func(device db.Device) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewSendDeviceOTPTask(&worker.PayloadSendDeviceOTP{
+				DeviceID: device.ID,
+				Code:     code,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		},
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.JSON(http.StatusForbidden, errResponse(ctx, errDeviceVerificationRequired))
+}
+
+// listDevices lists the devices of the caller that aren't revoked, the last seen first.
+func (server *Server) listDevices(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	devices, err := server.store.ListDevices(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]deviceResponse, len(devices))
+	for i, device := range devices {
+		rsp[i] = newDeviceResponse(device)
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+type deviceURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type renameDeviceRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// renameDevice names a device of the caller.
+func (server *Server) renameDevice(ctx *gin.Context) {
+	var uri deviceURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req renameDeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	device, err := server.store.RenameDevice(ctx, db.RenameDeviceParams{
+		ID:       uri.ID,
+		Username: payload.Username,
+		Name:     req.Name,
+	})
+	if err != nil {
+		ctx.JSON(deviceErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newDeviceResponse(device))
+}
+
+// revokeDevice revokes a device of the caller, blocking the sessions it logged in. Logging in
+// from it again registers it again, untrusted.
+func (server *Server) revokeDevice(ctx *gin.Context) {
+	var uri deviceURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err := server.store.RevokeDeviceTx(ctx, db.RevokeDeviceParams{ID: uri.ID, Username: payload.Username})
+	if err != nil {
+		ctx.JSON(deviceErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,501 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUserDevice(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		checkDevice   func(t *testing.T, device db.UpsertDeviceParams)
+		checkResponse func(t *testing.T, rsp loginUserResponse)
+	}{
+		{
+			name: "NamedAfterUserAgent",
+			body: gin.H{"username": user.Username, "password": password, "device_fingerprint": "3f2a9c"},
+			checkDevice: func(t *testing.T, device db.UpsertDeviceParams) {
+				require.Equal(t, db.UpsertDeviceParams{Username: user.Username, Fingerprint: "3f2a9c", Name: "bank-app/1.0"}, device)
+			},
+			checkResponse: func(t *testing.T, rsp loginUserResponse) {
+				require.NotNil(t, rsp.Device)
+				require.Equal(t, int64(7), rsp.Device.ID)
+				require.False(t, rsp.Device.Trusted)
+			},
+		},
+		{
+			name: "Named",
+			body: gin.H{"username": user.Username, "password": password, "device_fingerprint": "3f2a9c", "device_name": "Pixel 8"},
+			checkDevice: func(t *testing.T, device db.UpsertDeviceParams) {
+				require.Equal(t, "Pixel 8", device.Name)
+			},
+			checkResponse: func(t *testing.T, rsp loginUserResponse) {
+				require.NotNil(t, rsp.Device)
+			},
+		},
+		{
+			// the session is bound to the device of the user agent
+			name: "NoFingerprint",
+			body: gin.H{"username": user.Username, "password": password},
+			checkDevice: func(t *testing.T, device db.UpsertDeviceParams) {
+				require.Equal(t, db.UpsertDeviceParams{Username: user.Username, Fingerprint: db.DeviceFingerprint("", "bank-app/1.0"), Name: "bank-app/1.0"}, device)
+			},
+			checkResponse: func(t *testing.T, rsp loginUserResponse) {
+				require.NotNil(t, rsp.Device)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			store.EXPECT().HasPendingPasswordReset(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+			store.EXPECT().ListRecentSessions(gomock.Any(), gomock.Any()).Times(1).Return([]db.Session{}, nil)
+			store.EXPECT().
+				CreateSessionTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreateSessionTxParams) (db.CreateSessionTxResult, error) {
+					tc.checkDevice(t, arg.Device)
+					result := db.CreateSessionTxResult{Session: db.Session{ID: arg.ID, Username: arg.Username}}
+					if arg.Device.Fingerprint != "" {
+						result.Device = &db.Device{ID: 7, Username: arg.Username, Fingerprint: arg.Device.Fingerprint, Name: arg.Device.Name}
+					}
+					return result, nil
+				})
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/login", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", "bank-app/1.0")
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			var rsp loginUserResponse
+			requireBodyData(t, recorder.Body, &rsp)
+			tc.checkResponse(t, rsp)
+		})
+	}
+}
+
+func TestRenewAccessTokenDevice(t *testing.T) {
+	user, _ := randomUser(t)
+	code, hash, err := fraud.NewOTP()
+	require.NoError(t, err)
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	trusted := db.Device{ID: 7, Username: user.Username, Fingerprint: "3f2a9c", TrustedAt: now}
+	untrusted := db.Device{ID: 7, Username: user.Username, Fingerprint: "3f2a9c", VerificationHash: hash, VerificationSentAt: now}
+
+	testCases := []struct {
+		name          string
+		deviceID      pgtype.Int8
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			// a session bound to no device steps up, even from a trusted device
+			name: "NotBoundSendsCode",
+			body: gin.H{"device_fingerprint": "3f2a9c"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					UpsertDevice(gomock.Any(), gomock.Eq(db.UpsertDeviceParams{Username: user.Username, Fingerprint: "3f2a9c", Name: "unknown device"})).
+					Times(1).
+					Return(trusted, nil)
+				store.EXPECT().SendDeviceVerificationTx(gomock.Any(), gomock.Any()).Times(1).Return(trusted, nil)
+				store.EXPECT().BindSessionDevice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDeviceVerificationRequired.Error())
+			},
+		},
+		{
+			name: "NotBoundValidCode",
+			body: gin.H{"verification_code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertDevice(gomock.Any(), gomock.Eq(db.LoginDevice(user.Username, "", "", ""))).
+					Times(1).
+					Return(untrusted, nil)
+				store.EXPECT().TrustDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(trusted, nil)
+				store.EXPECT().
+					BindSessionDevice(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.BindSessionDeviceParams) (db.Session, error) {
+						require.Equal(t, pgtype.Int8{Int64: 7, Valid: true}, arg.DeviceID)
+						return db.Session{ID: arg.ID, DeviceID: arg.DeviceID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotBoundWrongCode",
+			body: gin.H{"verification_code": wrongCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertDevice(gomock.Any(), gomock.Any()).Times(1).Return(untrusted, nil)
+				store.EXPECT().AddDeviceVerificationAttempt(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(untrusted, nil)
+				store.EXPECT().TrustDevice(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().BindSessionDevice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Trusted",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(trusted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherDevice",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "b71e04"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(trusted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDeviceMismatch.Error())
+			},
+		},
+		{
+			name:     "Revoked",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c"},
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := trusted
+				revoked.RevokedAt = now
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(revoked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDeviceRevoked.Error())
+			},
+		},
+		{
+			name:     "UntrustedSendsCode",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(untrusted, nil)
+				store.EXPECT().
+					SendDeviceVerificationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SendDeviceVerificationTxParams) (db.Device, error) {
+						require.Equal(t, int64(7), arg.ID)
+						require.NotEqual(t, hash, arg.VerificationHash)
+						tasks, err := arg.AfterUpdate(untrusted)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskSendDeviceOTP, tasks[0].TaskType)
+						return untrusted, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDeviceVerificationRequired.Error())
+			},
+		},
+		{
+			name:     "UntrustedValidCode",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c", "verification_code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(untrusted, nil)
+				store.EXPECT().TrustDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(trusted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "UntrustedWrongCode",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c", "verification_code": wrongCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				attempted := untrusted
+				attempted.VerificationAttempts = 1
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(untrusted, nil)
+				store.EXPECT().AddDeviceVerificationAttempt(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(attempted, nil)
+				store.EXPECT().RevokeDeviceTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TrustDevice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyWrongCodes",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c", "verification_code": wrongCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				attempted := untrusted
+				attempted.VerificationAttempts = fraud.MaxOTPAttempts
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(untrusted, nil)
+				store.EXPECT().AddDeviceVerificationAttempt(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(attempted, nil)
+				store.EXPECT().
+					RevokeDeviceTx(gomock.Any(), gomock.Eq(db.RevokeDeviceParams{ID: 7, Username: user.Username})).
+					Times(1).
+					Return(attempted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "ExpiredCode",
+			deviceID: pgtype.Int8{Int64: 7, Valid: true},
+			body:     gin.H{"device_fingerprint": "3f2a9c", "verification_code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := untrusted
+				expired.VerificationSentAt = pgtype.Timestamptz{Time: time.Now().Add(-fraud.OTPValidity - time.Minute), Valid: true}
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(expired, nil)
+				store.EXPECT().TrustDevice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, util.DefaultTenant, "", time.Minute)
+			require.NoError(t, err)
+			store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.Session{
+				ID:           payload.ID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				ExpiresAt:    payload.ExpiredAt,
+				DeviceID:     tc.deviceID,
+			}, nil)
+			tc.buildStubs(store)
+
+			tc.body["refresh_token"] = refreshToken
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/tokens/renew_access", bytes.NewReader(body))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListDevicesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	devices := []db.Device{
+		{ID: 2, Username: user.Username, Fingerprint: "b71e04", Name: "Firefox", VerificationHash: "secret"},
+		{ID: 1, Username: user.Username, Fingerprint: "3f2a9c", Name: "Pixel 8", TrustedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListDevices(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(devices, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/users/me/devices", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), "3f2a9c")
+	require.NotContains(t, recorder.Body.String(), "secret")
+	var rsp []deviceResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Len(t, rsp, 2)
+	require.Equal(t, "Firefox", rsp[0].Name)
+	require.False(t, rsp[0].Trusted)
+	require.True(t, rsp[1].Trusted)
+}
+
+func TestRenameDeviceAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"name": "Work laptop"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RenameDevice(gomock.Any(), gomock.Eq(db.RenameDeviceParams{ID: 1, Username: user.Username, Name: "Work laptop"})).
+					Times(1).
+					Return(db.Device{ID: 1, Username: user.Username, Name: "Work laptop"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp deviceResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, "Work laptop", rsp.Name)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"name": "Work laptop"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RenameDevice(gomock.Any(), gomock.Any()).Times(1).Return(db.Device{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoName",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RenameDevice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, "/v1/users/me/devices/1/name", bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeDeviceAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		id            int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RevokeDeviceTx(gomock.Any(), gomock.Eq(db.RevokeDeviceParams{ID: 1, Username: user.Username})).
+					Times(1).
+					Return(db.Device{ID: 1, Username: user.Username}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RevokeDeviceTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Device{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RevokeDeviceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/v1/users/me/devices/%d", tc.id), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
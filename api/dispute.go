@@ -0,0 +1,206 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The dispute routes let users dispute a transfer they sent or received. The transfer amount is
+// held on the account it credited until an admin closes the dispute, as resolved, or as refunded
+// with a reversal of the transfer.
+
+type openDisputeRequest struct {
+	TransferID int64  `json:"transfer_id" binding:"required,min=1"`
+	Reason     string `json:"reason" binding:"required,max=500"`
+}
+
+// disputeErrStatus maps the errors of the disputes to a response status.
+func disputeErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrDisputeExists), errors.Is(err, db.ErrDisputeClosed):
+		return http.StatusForbidden
+	case errors.Is(err, db.ErrInvalidDisputeStatus), errors.Is(err, db.ErrRefundExceedsTransfer):
+		return http.StatusBadRequest
+	}
+	return operatorErrStatus(err)
+}
+
+func (server *Server) openDispute(ctx *gin.Context) {
+	var req openDisputeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	transfer, credited, err := server.getDisputedTransfer(ctx, req.TransferID)
+	if err != nil {
+		ctx.JSON(disputeErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	party, err := server.isTransferParty(ctx, payload.Username, transfer.FromAccountID, credited.ToAccountID)
+	if err != nil {
+		ctx.JSON(disputeErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !party {
+		err := errors.New("transfer doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	dispute, err := server.store.OpenDisputeTx(ctx, db.CreateDisputeParams{
+		TransferID: transfer.ID,
+		AccountID:  credited.ToAccountID,
+		OpenedBy:   payload.Username,
+		Amount:     credited.Amount,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(disputeErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, dispute)
+}
+
+// getDisputedTransfer returns the transfer of the payer and the transfer that credited the payee,
+// the same transfer unless it is an exchange, whichever of its two transfers id is.
+func (server *Server) getDisputedTransfer(ctx *gin.Context, id int64) (db.Transfer, db.Transfer, error) {
+	transfer, err := server.store.GetTransfer(ctx, id)
+	if err != nil {
+		return transfer, transfer, err
+	}
+	exchange, err := server.store.GetTransferExchange(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return transfer, transfer, nil
+	}
+	if err != nil {
+		return transfer, transfer, err
+	}
+	if exchange.TransferID != id {
+		payer, err := server.store.GetTransfer(ctx, exchange.TransferID)
+		return payer, transfer, err
+	}
+	credited, err := server.store.GetTransfer(ctx, exchange.ExchangeTransferID)
+	return transfer, credited, err
+}
+
+// isTransferParty tells whether the user owns one of the accounts, the payer or the payee of a
+// transfer.
+func (server *Server) isTransferParty(ctx *gin.Context, username string, accountIDs ...int64) (bool, error) {
+	for _, accountID := range accountIDs {
+		account, err := server.store.GetAccountIncludeDeleted(ctx, accountID)
+		if err != nil {
+			return false, err
+		}
+		if account.Owner == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type disputeURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getDispute returns a dispute to the user who opened it, or to an admin.
+func (server *Server) getDispute(ctx *gin.Context) {
+	var uri disputeURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	dispute, err := server.store.GetDispute(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(disputeErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if dispute.OpenedBy != payload.Username && payload.Role != util.AdminRole {
+		err := errors.New("dispute doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, dispute)
+}
+
+type listDisputesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=open investigating resolved refunded"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listDisputes lists the disputes for the admins, oldest first, e.g. the open ones to work on.
+func (server *Server) listDisputes(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the disputes") {
+		return
+	}
+
+	var req listDisputesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	disputes, err := server.store.ListDisputes(ctx, db.ListDisputesParams{
+		Status: pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, disputes)
+}
+
+type setDisputeStatusRequest struct {
+	Status     string `json:"status" binding:"required,oneof=investigating resolved refunded"`
+	Resolution string `json:"resolution" binding:"required"`
+}
+
+// setDisputeStatus moves a dispute to investigating, or closes it, refunded closing it with a
+// reversal of the transfer.
+func (server *Server) setDisputeStatus(ctx *gin.Context) {
+	if !requireAdmin(ctx, "resolve disputes") {
+		return
+	}
+
+	var uri disputeURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req setDisputeStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	result, err := operator.SetDisputeStatus(ctx, uri.ID, req.Status, req.Resolution)
+	if err != nil {
+		ctx.JSON(disputeErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
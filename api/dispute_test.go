@@ -0,0 +1,494 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDisputeAPI(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	fromAccount := randomAccount(sender.Username)
+	toAccount := randomAccount(recipient.Username)
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        util.RandomInt(1, 1000),
+	}
+	body := gin.H{"transfer_id": transfer.ID, "reason": "I didn't make this payment"}
+
+	// the transfer paying the payee of an exchange whose transfer of the payer is transfer
+	exchanged := db.Transfer{
+		ID:            transfer.ID + 1,
+		FromAccountID: util.RandomInt(1001, 2000),
+		ToAccountID:   toAccount.ID,
+		Amount:        util.RandomInt(1, 1000),
+	}
+	exchange := db.TransferExchange{TransferID: transfer.ID, ExchangeTransferID: exchanged.ID}
+	exchangeStubs := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+		store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+		arg := db.CreateDisputeParams{
+			TransferID: transfer.ID,
+			AccountID:  toAccount.ID,
+			OpenedBy:   recipient.Username,
+			Amount:     exchanged.Amount,
+			Reason:     "I didn't make this payment",
+		}
+		store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Dispute{ID: 1}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     body,
+			username: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.TransferExchange{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				arg := db.CreateDisputeParams{
+					TransferID: transfer.ID,
+					AccountID:  toAccount.ID,
+					OpenedBy:   sender.Username,
+					Amount:     transfer.Amount,
+					Reason:     "I didn't make this payment",
+				}
+				store.EXPECT().
+					OpenDisputeTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Dispute{ID: 1, TransferID: arg.TransferID, AccountID: arg.AccountID, Status: db.DisputeOpen}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var dispute db.Dispute
+				requireBodyData(t, recorder.Body, &dispute)
+				require.Equal(t, db.DisputeOpen, dispute.Status)
+				require.Equal(t, toAccount.ID, dispute.AccountID)
+			},
+		},
+		{
+			name:     "Recipient",
+			body:     body,
+			username: recipient.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.TransferExchange{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Exchange",
+			body:     body,
+			username: recipient.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(exchange, nil)
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(exchanged.ID)).Times(1).Return(exchanged, nil)
+				exchangeStubs(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "ExchangeToPayee",
+			body:     gin.H{"transfer_id": exchanged.ID, "reason": "I didn't make this payment"},
+			username: recipient.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(exchanged.ID)).Times(1).Return(exchanged, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(exchanged.ID)).Times(1).Return(exchange, nil)
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				exchangeStubs(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotParty",
+			body:     body,
+			username: util.RandomOwnerName(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.TransferExchange{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "TransferNotFound",
+			body:     body,
+			username: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.Transfer{}, db.ErrRecordNotFound)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyDisputed",
+			body:     body,
+			username: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.TransferExchange{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(fromAccount, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{}, db.ErrDisputeExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingReason",
+			body:     gin.H{"transfer_id": transfer.ID},
+			username: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalError",
+			body:     body,
+			username: sender.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().GetTransferExchange(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.TransferExchange{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(fromAccount, nil)
+				store.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/disputes", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetDisputeAPI(t *testing.T) {
+	dispute := db.Dispute{
+		ID:       util.RandomInt(1, 1000),
+		OpenedBy: util.RandomOwnerName(),
+		Amount:   util.RandomInt(1, 1000),
+		Status:   db.DisputeInvestigating,
+	}
+
+	testCases := []struct {
+		name     string
+		username string
+		role     string
+		expected int
+	}{
+		{name: "Opener", username: dispute.OpenedBy, role: util.DepositorRole, expected: http.StatusOK},
+		{name: "Admin", username: "admin", role: util.AdminRole, expected: http.StatusOK},
+		{name: "OtherUser", username: util.RandomOwnerName(), role: util.DepositorRole, expected: http.StatusUnauthorized},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetDispute(gomock.Any(), gomock.Eq(dispute.ID)).Times(1).Return(dispute, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/disputes/%d", dispute.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expected, recorder.Code)
+		})
+	}
+}
+
+func TestListDisputesAPI(t *testing.T) {
+	disputes := []db.Dispute{{ID: 1, Status: db.DisputeOpen}, {ID: 2, Status: db.DisputeOpen}}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"status": {db.DisputeOpen}, "page_id": {"2"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDisputes(gomock.Any(), gomock.Eq(db.ListDisputesParams{
+						Status: pgtype.Text{String: db.DisputeOpen, Valid: true},
+						Limit:  5,
+						Offset: 5,
+					})).
+					Times(1).
+					Return(disputes, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.Dispute
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, disputes, rsp)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: url.Values{"status": {"closed"}, "page_id": {"1"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDisputes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: url.Values{"page_id": {"1"}, "page_size": {"5"}},
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDisputes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/disputes?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetDisputeStatusAPI(t *testing.T) {
+	dispute := db.Dispute{
+		ID:         util.RandomInt(1, 1000),
+		TransferID: util.RandomInt(1, 1000),
+		AccountID:  util.RandomInt(1, 1000),
+		Amount:     util.RandomInt(1, 1000),
+		Status:     db.DisputeInvestigating,
+	}
+	body := gin.H{"status": db.DisputeRefunded, "resolution": "unauthorized payment"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		blocked       bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Refund",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Eq(dispute.ID)).Times(1).Return(dispute, nil)
+				store.EXPECT().
+					SetDisputeStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetDisputeStatusTxParams) (db.SetDisputeStatusTxResult, error) {
+						require.Equal(t, dispute.ID, arg.ID)
+						require.Equal(t, db.DisputeRefunded, arg.Status)
+						require.Equal(t, "admin", arg.Admin)
+						require.Equal(t, admin.ActionSetDisputeStatus, arg.Audit.Action)
+
+						refunded := dispute
+						refunded.Status = arg.Status
+						refunded.ReversalTransferID = pgtype.Int8{Int64: 42, Valid: true}
+						return db.SetDisputeStatusTxResult{
+							Dispute:  refunded,
+							Reversal: &db.TransferTxResult{Transfer: db.Transfer{ID: 42, Amount: dispute.Amount}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.SetDisputeStatusTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.DisputeRefunded, rsp.Dispute.Status)
+				require.NotNil(t, rsp.Reversal)
+				require.Equal(t, int64(42), rsp.Reversal.Transfer.ID)
+			},
+		},
+		{
+			name: "Closed",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(1).Return(dispute, nil)
+				store.EXPECT().SetDisputeStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetDisputeStatusTxResult{}, db.ErrDisputeClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "TransfersBlocked",
+			body:    body,
+			role:    util.AdminRole,
+			blocked: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SetDisputeStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+		{
+			name: "StoreTransfersBlocked",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(1).Return(dispute, nil)
+				store.EXPECT().SetDisputeStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetDisputeStatusTxResult{}, db.ErrTransfersBlocked)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidStatus",
+			body: gin.H{"status": db.DisputeOpen, "resolution": "reopened"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{}, db.ErrRecordNotFound)
+				store.EXPECT().SetDisputeStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDispute(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.mode.SetTransfersBlocked(tc.blocked)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/disputes/%d/status", dispute.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag returns the entity tag of data, the body of a response without its envelope, whose
+// request id changes with every request. Accounts have no updated_at, so their tag is derived
+// from what a client can see change: the balances, the holds and the freezing, and the display
+// balance with its rate. fields, those a partial response asks for, tell its representations apart.
+func etag(data any, fields string) (string, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(body, fields...))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// notModified sets the ETag of the response to tag, and answers 304 when the client already
+// holds the representation it tags, telling from If-None-Match. Clients polling a resource then
+// get an empty response until it changes.
+func notModified(ctx *gin.Context, tag string) bool {
+	ctx.Header("ETag", tag)
+	// the clients revalidate the representation they cache before using it
+	ctx.Header("Cache-Control", "private, no-cache")
+
+	ifNoneMatch := ctx.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		// If-None-Match compares the tags weakly, ignoring the W/ of a weak one
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			ctx.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountETag(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	tag, err := etag(accountResponse{Account: account}, "")
+	require.NoError(t, err)
+
+	moved := account
+	moved.Balance += 100
+	movedTag, err := etag(accountResponse{Account: moved}, "")
+	require.NoError(t, err)
+	require.NotEqual(t, tag, movedTag)
+	partialTag, err := etag(accountResponse{Account: account}, "balance")
+	require.NoError(t, err)
+	require.NotEqual(t, tag, partialTag)
+
+	testCases := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "NoIfNoneMatch", wantStatus: http.StatusOK},
+		{name: "Match", ifNoneMatch: tag, wantStatus: http.StatusNotModified},
+		{name: "WeakMatch", ifNoneMatch: "W/" + tag, wantStatus: http.StatusNotModified},
+		{name: "MatchInList", ifNoneMatch: `"stale", ` + tag, wantStatus: http.StatusNotModified},
+		{name: "Any", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "BalanceChanged", ifNoneMatch: movedTag, wantStatus: http.StatusOK},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/accounts/%d", account.ID), nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.wantStatus, recorder.Code)
+			require.Equal(t, tag, recorder.Header().Get("ETag"))
+			if tc.wantStatus == http.StatusNotModified {
+				require.Empty(t, recorder.Body.Bytes())
+				return
+			}
+			requiredBodyMatched(t, recorder.Body, account)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	eventStreamKeepAlive = 30 * time.Second
+	// eventStreamMaxReplay caps the entries replayed on resume, a client further behind
+	// catches up with the balances sent after the replay.
+	eventStreamMaxReplay = 100
+)
+
+type streamEventsRequest struct {
+	AccountIDs []int64 `form:"account_id" binding:"required,min=1,max=20,unique,dive,min=1"`
+}
+
+// streamEvents streams the activity of the accounts of the caller as Server-Sent Events, for
+// the clients that can't use the WebSocket. The events of an entry carry its id, so a client
+// that reconnects with Last-Event-ID gets the incoming transfers it missed replayed. Entries
+// committed out of id order around a disconnect can still be missed.
+func (server *Server) streamEvents(ctx *gin.Context) {
+	if server.entries == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errResponse(ctx, errors.New("real-time updates are not available")))
+		return
+	}
+
+	var req streamEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	var lastEventID int64
+	if header := ctx.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, errors.New("invalid Last-Event-ID")))
+			return
+		}
+		lastEventID = id
+	}
+
+	accounts := make([]db.Account, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		account, ok := server.authorizeAccount(ctx, accountID)
+		if !ok {
+			return
+		}
+		accounts = append(accounts, account)
+	}
+
+	// subscribe before the replay, so that no entry falls in between
+	entries, unsubscribe := server.mergeEntries(req.AccountIDs)
+	defer unsubscribe()
+
+	var replay []db.Entry
+	if lastEventID > 0 {
+		var err error
+		replay, err = server.store.ListEntriesAfter(ctx, db.ListEntriesAfterParams{
+			AccountIds: req.AccountIDs,
+			AfterID:    lastEventID,
+			Limit:      eventStreamMaxReplay,
+		})
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// keeps nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	replayed := make(map[int64]bool, len(replay))
+	for _, entry := range replay {
+		replayed[entry.ID] = true
+		if entry.Amount > 0 {
+			writeEvent(ctx.Writer, entry.ID, newTransferReceivedEvent(entry))
+		}
+	}
+	for _, account := range accounts {
+		writeEvent(ctx.Writer, 0, newBalanceEvent(account))
+	}
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	expired := time.NewTimer(time.Until(payload.ExpiredAt))
+	defer expired.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-expired.C:
+			// the client reconnects with a new token and resumes where it stopped
+			return
+		case entry := <-entries:
+			if replayed[entry.ID] {
+				continue
+			}
+			events := server.entryEvents(ctx, entry.AccountID, entry)
+			for i, event := range events {
+				// only the last event of an entry carries its id, so a resume replays the entry if it was cut short
+				id := int64(0)
+				if i == len(events)-1 {
+					id = entry.ID
+				}
+				writeEvent(ctx.Writer, id, event)
+			}
+			ctx.Writer.Flush()
+		case <-keepAlive.C:
+			// a comment keeps proxies from closing the idle connection
+			io.WriteString(ctx.Writer, ": keep-alive\n\n")
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent writes event in the Server-Sent Events format, with its id unless it is 0.
+func writeEvent(w io.Writer, id int64, event activityEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if id > 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+// mergeEntries subscribes to the entries of all the accounts at once.
+func (server *Server) mergeEntries(accountIDs []int64) (<-chan db.Entry, func()) {
+	merged := make(chan db.Entry)
+	done := make(chan struct{})
+	var forwarders sync.WaitGroup
+	cancels := make([]func(), 0, len(accountIDs))
+
+	for _, accountID := range accountIDs {
+		entries, cancel := server.entries.SubscribeEntries(accountID)
+		cancels = append(cancels, cancel)
+		forwarders.Add(1)
+		go func() {
+			defer forwarders.Done()
+			for entry := range entries {
+				select {
+				case merged <- entry:
+				case <-done:
+				}
+			}
+		}()
+	}
+
+	return merged, func() {
+		close(done)
+		for _, cancel := range cancels {
+			cancel()
+		}
+		forwarders.Wait()
+	}
+}
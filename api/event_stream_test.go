@@ -0,0 +1,180 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next event of an event stream, skipping the comments.
+func readEvent(t *testing.T, reader *bufio.Reader) (id, event, data string) {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && event != "":
+			return
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStreamEventsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	otherAccount := randomAccount("other_user")
+
+	testCases := []struct {
+		name        string
+		query       string
+		lastEventID string
+		buildStubs  func(store *mockdb.MockStore)
+		checkStream func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response)
+	}{
+		{
+			name:  "OK",
+			query: fmt.Sprintf("account_id=%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := account
+				updated.Balance -= 10
+				gomock.InOrder(
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil),
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(updated, nil),
+				)
+				store.EXPECT().ListEntriesAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkStream: func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response) {
+				require.Equal(t, http.StatusOK, response.StatusCode)
+				require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+				reader := bufio.NewReader(response.Body)
+
+				id, event, data := readEvent(t, reader)
+				require.Empty(t, id)
+				require.Equal(t, activityBalance, event)
+				require.Contains(t, data, fmt.Sprintf(`"balance":%d`, account.Balance))
+
+				// a debit only changes the balance
+				entries.publish(db.Entry{ID: 9, AccountID: account.ID, Amount: -10})
+				id, event, data = readEvent(t, reader)
+				require.Equal(t, "9", id)
+				require.Equal(t, activityBalance, event)
+				require.Contains(t, data, fmt.Sprintf(`"balance":%d`, account.Balance-10))
+			},
+		},
+		{
+			name:        "Resume",
+			query:       fmt.Sprintf("account_id=%d", account.ID),
+			lastEventID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListEntriesAfter(gomock.Any(), gomock.Eq(db.ListEntriesAfterParams{
+						AccountIds: []int64{account.ID},
+						AfterID:    5,
+						Limit:      eventStreamMaxReplay,
+					})).
+					Times(1).
+					Return([]db.Entry{
+						{ID: 6, AccountID: account.ID, Amount: 20},
+						{ID: 7, AccountID: account.ID, Amount: -5},
+					}, nil)
+			},
+			checkStream: func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response) {
+				require.Equal(t, http.StatusOK, response.StatusCode)
+				reader := bufio.NewReader(response.Body)
+
+				id, event, data := readEvent(t, reader)
+				require.Equal(t, "6", id)
+				require.Equal(t, activityTransferReceived, event)
+				require.Contains(t, data, `"amount":20`)
+
+				id, event, _ = readEvent(t, reader)
+				require.Empty(t, id)
+				require.Equal(t, activityBalance, event)
+			},
+		},
+		{
+			name:        "InvalidLastEventID",
+			query:       fmt.Sprintf("account_id=%d", account.ID),
+			lastEventID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkStream: func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response) {
+				require.Equal(t, http.StatusBadRequest, response.StatusCode)
+			},
+		},
+		{
+			name:  "NoAccount",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkStream: func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response) {
+				require.Equal(t, http.StatusBadRequest, response.StatusCode)
+			},
+		},
+		{
+			name:  "UnauthorizedAccount",
+			query: fmt.Sprintf("account_id=%d&account_id=%d", account.ID, otherAccount.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+			},
+			checkStream: func(t *testing.T, entries *fakeEntrySubscriber, response *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			entries := newFakeEntrySubscriber()
+			server.entries = entries
+			httpServer := httptest.NewServer(server.router)
+			defer httpServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/events?"+tc.query, nil)
+			require.NoError(t, err)
+			request.Header.Set("Accept", "text/event-stream")
+			if tc.lastEventID != "" {
+				request.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			tc.checkStream(t, entries, response)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/gin-gonic/gin"
+)
+
+// exchangeErrStatus maps the errors of the conversions to a response status. Missing or stale
+// rates are for the bank to fix, so the client can only retry later.
+func exchangeErrStatus(err error) int {
+	if errors.Is(err, fx.ErrNoRate) || errors.Is(err, fx.ErrStaleRate) {
+		return http.StatusServiceUnavailable
+	}
+	return errStatus(err)
+}
+
+type exchangeRateResponse struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// Rate is the units of quote_currency a unit of base_currency buys, e.g. "1.0823".
+	Rate        string    `json:"rate"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// listExchangeRates lists the exchange rates the transfers between currencies are converted at,
+// with when the provider published them and when the bank fetched them.
+func (server *Server) listExchangeRates(ctx *gin.Context) {
+	rates, err := server.store.ListExchangeRates(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]exchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		rsp[i] = exchangeRateResponse{
+			BaseCurrency:  rate.BaseCurrency,
+			QuoteCurrency: rate.QuoteCurrency,
+			Rate:          fx.FormatRate(rate.RateNanos),
+			Source:        rate.Source,
+			PublishedAt:   rate.PublishedAt,
+			FetchedAt:     rate.FetchedAt,
+		}
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// randomExchangeRates are the euro rates of the dollar and the canadian dollar, fetched at fetchedAt.
+func randomExchangeRates(fetchedAt time.Time) []db.ExchangeRate {
+	return []db.ExchangeRate{
+		{BaseCurrency: util.EUR, QuoteCurrency: util.CAD, RateNanos: 1_486_200_000, Source: fx.ProviderECB, PublishedAt: fetchedAt, FetchedAt: fetchedAt},
+		{BaseCurrency: util.EUR, QuoteCurrency: util.USD, RateNanos: 1_082_300_000, Source: fx.ProviderECB, PublishedAt: fetchedAt, FetchedAt: fetchedAt},
+	}
+}
+
+func TestListExchangeRatesAPI(t *testing.T) {
+	rates := randomExchangeRates(time.Now().UTC().Truncate(time.Second))
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(rates, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp []exchangeRateResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp, 2)
+				require.Equal(t, exchangeRateResponse{
+					BaseCurrency:  util.EUR,
+					QuoteCurrency: util.USD,
+					Rate:          "1.0823",
+					Source:        fx.ProviderECB,
+					PublishedAt:   rates[1].PublishedAt,
+					FetchedAt:     rates[1].FetchedAt,
+				}, rsp[1])
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/exchange_rates", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "bob", util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferExchangeFlagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, _ := randomUser(t)
+	account1 := randomAccount(user.Username)
+	account2 := randomAccount(util.RandomOwnerName())
+	account1.Currency = util.USD
+	account2.Currency = util.EUR
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(randomExchangeRates(time.Now()), nil)
+	store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+	// reviewed later, the transfer would go through at other rates
+	store.EXPECT().CreateTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.fraud = fraud.NewEngine(fraud.NewVelocityRule(store, 3, time.Hour))
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          10000,
+		"currency":        util.USD,
+		"to_currency":     util.EUR,
+	})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
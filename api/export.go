@@ -0,0 +1,244 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/export"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// The export routes let users download the entries of their accounts as OFX, QIF or CSV files to
+// import them into their accounting tools. A small export is served right away; a larger one is
+// built by the worker and downloaded once ready.
+
+const (
+	// exportSyncEntries is the largest number of entries an export served right away holds.
+	exportSyncEntries = 1000
+	// exportRetention is how long the file of an export built by the worker can be downloaded.
+	exportRetention = 7 * 24 * time.Hour
+)
+
+type exportEntriesURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type exportEntriesRequest struct {
+	Format string `form:"format" binding:"required,oneof=ofx qif csv"`
+	// From and To bound the creation time of the entries, from the opening of the account until
+	// now by default.
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Month is the calendar month of a monthly statement instead, e.g. 2026-09, which starts and
+	// ends at midnight in the timezone of the owner.
+	Month string `form:"month" binding:"omitempty,datetime=2006-01,excluded_with=From To"`
+}
+
+type entryExportResponse struct {
+	db.EntryExport
+	// DownloadURL is set once the export is ready.
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func newEntryExportResponse(entryExport db.EntryExport) entryExportResponse {
+	rsp := entryExportResponse{EntryExport: entryExport}
+	if entryExport.Status == db.ExportReady {
+		rsp.DownloadURL = fmt.Sprintf("%s/exports/%d/download", apiV1, entryExport.ID)
+	}
+	return rsp
+}
+
+// exportEntries exports the entries of an account of the caller. Up to exportSyncEntries of them
+// are served as the file; more are exported by the worker, answering 202 with the export to poll.
+func (server *Server) exportEntries(ctx *gin.Context) {
+	var uri exportEntriesURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req exportEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+	settings, err := server.userPreferences(ctx, account.Owner)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	now := time.Now()
+	statement := export.Statement{
+		Account:  account,
+		From:     req.From,
+		To:       req.To,
+		At:       now,
+		Location: settings.Location(),
+	}
+	if req.Month != "" {
+		month, err := time.ParseInLocation("2006-01", req.Month, statement.Location)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
+		}
+		statement.From, statement.To = periodBounds("month", month, statement.Location)
+	}
+	if statement.From.IsZero() {
+		statement.From = account.CreatedAt
+	}
+	if statement.To.IsZero() {
+		statement.To = now
+	}
+	if !statement.From.Before(statement.To) {
+		err := errors.New("from must be before to")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	count, err := server.store.CountExportEntries(ctx, db.CountExportEntriesParams{
+		AccountID: account.ID,
+		FromTime:  statement.From,
+		ToTime:    statement.To,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if count > exportSyncEntries {
+		server.createEntryExport(ctx, req.Format, statement)
+		return
+	}
+
+	var file bytes.Buffer
+	if _, err := export.Write(ctx, server.store, &file, req.Format, statement); err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	serveExportFile(ctx, req.Format, statement, file.Bytes())
+}
+
+// createEntryExport has the worker export the entries of statement, answering 202: the file
+// isn't there yet.
+func (server *Server) createEntryExport(ctx *gin.Context, format string, statement export.Statement) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	entryExport, err := server.store.CreateEntryExportTx(ctx, db.CreateEntryExportTxParams{
+		CreateEntryExportParams: db.CreateEntryExportParams{
+			Owner:     payload.Username,
+			AccountID: statement.Account.ID,
+			Format:    format,
+			FromTime:  statement.From,
+			ToTime:    statement.To,
+			ExpiresAt: statement.At.Add(exportRetention),
+		},
+		AfterCreate: func(entryExport db.EntryExport) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewExportEntriesTask(&worker.PayloadExportEntries{ExportID: entryExport.ID})
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		},
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	log.Info().Int64("export id", entryExport.ID).Int64("account id", entryExport.AccountID).
+		Str("format", entryExport.Format).Msg("entry export created")
+
+	envelope.JSON(ctx, http.StatusAccepted, newEntryExportResponse(entryExport))
+}
+
+// serveExportFile answers with the file of an export as an attachment.
+func serveExportFile(ctx *gin.Context, format string, statement export.Statement, file []byte) {
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName(format, statement)))
+	ctx.Data(http.StatusOK, export.ContentType(format), file)
+}
+
+type entryExportURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// authorizeEntryExport gets an export of the caller, answering 404 for a missing one and 401 for
+// one of another user.
+func (server *Server) authorizeEntryExport(ctx *gin.Context) (db.EntryExport, bool) {
+	var uri entryExportURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return db.EntryExport{}, false
+	}
+
+	entryExport, err := server.store.GetEntryExport(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return entryExport, false
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return entryExport, false
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if entryExport.Owner != payload.Username {
+		err := errors.New("export doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return entryExport, false
+	}
+	return entryExport, true
+}
+
+// getEntryExport returns an export of the caller, with its download url once it is ready.
+func (server *Server) getEntryExport(ctx *gin.Context) {
+	entryExport, ok := server.authorizeEntryExport(ctx)
+	if !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newEntryExportResponse(entryExport))
+}
+
+// downloadEntryExport serves the file of a ready export of the caller. An export still pending or
+// failed answers 409, an expired one 410.
+func (server *Server) downloadEntryExport(ctx *gin.Context) {
+	entryExport, ok := server.authorizeEntryExport(ctx)
+	if !ok {
+		return
+	}
+	if entryExport.Status != db.ExportReady {
+		err := fmt.Errorf("export is %s", entryExport.Status)
+		ctx.JSON(http.StatusConflict, errResponse(ctx, err))
+		return
+	}
+	if time.Now().After(entryExport.ExpiresAt) {
+		err := errors.New("export expired")
+		ctx.JSON(http.StatusGone, errResponse(ctx, err))
+		return
+	}
+
+	file, err := server.store.GetEntryExportFile(ctx, entryExport.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	settings, err := server.userPreferences(ctx, entryExport.Owner)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	serveExportFile(ctx, entryExport.Format, export.Statement{
+		Account:  db.Account{ID: entryExport.AccountID},
+		From:     entryExport.FromTime,
+		To:       entryExport.ToTime,
+		Location: settings.Location(),
+	}, file)
+}
@@ -0,0 +1,400 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	rangeQuery := url.Values{"from": {"2026-10-01T00:00:00Z"}, "to": {"2026-11-01T00:00:00Z"}}
+	withFormat := func(format string) url.Values {
+		query := url.Values{"format": {format}}
+		for key, values := range rangeQuery {
+			query[key] = values
+		}
+		return query
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			query:    withFormat("csv"),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CountExportEntries(gomock.Any(), gomock.Eq(db.CountExportEntriesParams{
+						AccountID: account.ID,
+						FromTime:  from,
+						ToTime:    to,
+					})).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					ListExportEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListExportEntriesRow{
+						{ID: 3, AccountID: account.ID, Amount: -25, CreatedAt: from.Add(time.Hour), Category: "rent", Tags: []string{}},
+					}, nil)
+				store.EXPECT().CreateEntryExportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Equal(t, fmt.Sprintf(`attachment; filename="account-%d-20261001-20261101.csv"`, account.ID),
+					recorder.Header().Get("Content-Disposition"))
+				require.Equal(t, "id,date,amount,currency,direction,category,tags\n"+
+					"3,2026-10-01T01:00:00Z,-0.25,"+account.Currency+",debit,rent,\n", recorder.Body.String())
+			},
+		},
+		{
+			name:     "Timezone",
+			query:    withFormat("csv"),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				taipei := user
+				taipei.Preferences = []byte(`{"timezone": "Asia/Taipei"}`)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(taipei, nil)
+				store.EXPECT().CountExportEntries(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().
+					ListExportEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListExportEntriesRow{
+						{ID: 3, AccountID: account.ID, Amount: -25, CreatedAt: from.Add(time.Hour), Category: "rent", Tags: []string{}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, fmt.Sprintf(`attachment; filename="account-%d-20261001-20261101.csv"`, account.ID),
+					recorder.Header().Get("Content-Disposition"))
+				require.Equal(t, "id,date,amount,currency,direction,category,tags\n"+
+					"3,2026-10-01T09:00:00+08:00,-0.25,"+account.Currency+",debit,rent,\n", recorder.Body.String())
+			},
+		},
+		{
+			name:     "Month",
+			query:    url.Values{"format": {"csv"}, "month": {"2026-10"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				taipei := user
+				taipei.Preferences = []byte(`{"timezone": "Asia/Taipei"}`)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(taipei, nil)
+				store.EXPECT().
+					CountExportEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CountExportEntriesParams) (int64, error) {
+						// the month starts and ends at midnight in Taipei
+						require.True(t, from.Add(-8*time.Hour).Equal(arg.FromTime))
+						require.True(t, to.Add(-8*time.Hour).Equal(arg.ToTime))
+						return 0, nil
+					})
+				store.EXPECT().ListExportEntries(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListExportEntriesRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, fmt.Sprintf(`attachment; filename="account-%d-20261001-20261101.csv"`, account.ID),
+					recorder.Header().Get("Content-Disposition"))
+			},
+		},
+		{
+			name:     "MonthWithRange",
+			query:    url.Values{"format": {"csv"}, "month": {"2026-10"}, "from": {"2026-10-01T00:00:00Z"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidMonth",
+			query:    url.Values{"format": {"csv"}, "month": {"2026-13"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Async",
+			query:    withFormat("ofx"),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountExportEntries(gomock.Any(), gomock.Any()).Times(1).Return(int64(exportSyncEntries+1), nil)
+				store.EXPECT().ListExportEntries(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateEntryExportTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateEntryExportTxParams) (db.EntryExport, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, "ofx", arg.Format)
+						require.Equal(t, from, arg.FromTime)
+						require.Equal(t, to, arg.ToTime)
+						require.WithinDuration(t, time.Now().Add(exportRetention), arg.ExpiresAt, time.Minute)
+
+						entryExport := db.EntryExport{
+							ID:        7,
+							Owner:     arg.Owner,
+							AccountID: arg.AccountID,
+							Format:    arg.Format,
+							Status:    db.ExportPending,
+						}
+						tasks, err := arg.AfterCreate(entryExport)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskExportEntries, tasks[0].TaskType)
+						return entryExport, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp entryExportResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(7), rsp.ID)
+				require.Equal(t, db.ExportPending, rsp.Status)
+				require.Empty(t, rsp.DownloadURL)
+			},
+		},
+		{
+			name:     "InvalidFormat",
+			query:    withFormat("xls"),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "FromAfterTo",
+			query:    url.Values{"format": {"qif"}, "from": {"2026-11-01T00:00:00Z"}, "to": {"2026-10-01T00:00:00Z"}},
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountExportEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			query:    withFormat("csv"),
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CountExportEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			query:    withFormat("csv"),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().CountExportEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/entries/export?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEntryExportAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	ready := db.EntryExport{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     user.Username,
+		AccountID: util.RandomInt(1, 1000),
+		Format:    "qif",
+		FromTime:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ToTime:    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:    db.ExportReady,
+		Entries:   1500,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	pending := ready
+	pending.Status = db.ExportPending
+	expired := ready
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+	file := []byte("!Type:Bank\n")
+
+	testCases := []struct {
+		name          string
+		path          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Get",
+			path:     fmt.Sprintf("/exports/%d", ready.ID),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(ready.ID)).Times(1).Return(ready, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp entryExportResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, ready.ID, rsp.ID)
+				require.Equal(t, fmt.Sprintf("/v1/exports/%d/download", ready.ID), rsp.DownloadURL)
+			},
+		},
+		{
+			name:     "GetNotFound",
+			path:     fmt.Sprintf("/exports/%d", ready.ID),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Any()).Times(1).Return(db.EntryExport{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "GetOtherUser",
+			path:     fmt.Sprintf("/exports/%d", ready.ID),
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(ready.ID)).Times(1).Return(ready, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Download",
+			path:     fmt.Sprintf("/exports/%d/download", ready.ID),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(ready.ID)).Times(1).Return(ready, nil)
+				store.EXPECT().GetEntryExportFile(gomock.Any(), gomock.Eq(ready.ID)).Times(1).Return(file, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/qif", recorder.Header().Get("Content-Type"))
+				require.Equal(t, fmt.Sprintf(`attachment; filename="account-%d-20260101-20270101.qif"`, ready.AccountID),
+					recorder.Header().Get("Content-Disposition"))
+				require.Equal(t, file, recorder.Body.Bytes())
+			},
+		},
+		{
+			name:     "DownloadPending",
+			path:     fmt.Sprintf("/exports/%d/download", pending.ID),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetEntryExportFile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "DownloadExpired",
+			path:     fmt.Sprintf("/exports/%d/download", expired.ID),
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(expired.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().GetEntryExportFile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+		{
+			name:     "DownloadOtherUser",
+			path:     fmt.Sprintf("/exports/%d/download", ready.ID),
+			username: "unauthorized",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(ready.ID)).Times(1).Return(ready, nil)
+				store.EXPECT().GetEntryExportFile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
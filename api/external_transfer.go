@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/settlement"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The external transfer routes send money to the accounts of other banks, through ACH for the
+// dollars and SEPA for the euros. The settlement worker moves a transfer from pending to submitted,
+// then to settled or returned.
+
+// externalTransferErrStatus maps the errors of the external transfers to a response status.
+func externalTransferErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	case errors.Is(err, settlement.ErrNoRail), errors.Is(err, settlement.ErrInvalidBeneficiary):
+		return http.StatusBadRequest
+	}
+	return errStatus(err)
+}
+
+type createExternalTransferRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+	Amount        int64 `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0,amount=Currency"`
+	// AmountDecimal is the amount in units of the currency, e.g. "12.34", instead of amount.
+	AmountDecimal      string `json:"amount_decimal" binding:"omitempty,excluded_with=Amount,decimal_amount=Currency"`
+	Currency           string `json:"currency" binding:"required,currency"`
+	BeneficiaryName    string `json:"beneficiary_name" binding:"required,max=70"`
+	BeneficiaryAccount string `json:"beneficiary_account" binding:"required,max=40"`
+	// BeneficiaryRouting is the routing number of the bank of the beneficiary, for an ACH transfer.
+	BeneficiaryRouting string `json:"beneficiary_routing" binding:"max=20"`
+	Memo               string `json:"memo" binding:"max=140"`
+}
+
+// createExternalTransfer debits an account of the caller for a transfer to another bank, which
+// answers 202: the money is held until the network settles the transfer, or back on the account
+// if the bank of the beneficiary returns it.
+func (server *Server) createExternalTransfer(ctx *gin.Context) {
+	var req createExternalTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if !bindAmount(ctx, &req.Amount, req.AmountDecimal, req.Currency) {
+		return
+	}
+	rail, err := settlement.RailFor(req.Currency)
+	if err != nil {
+		ctx.JSON(externalTransferErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	req.BeneficiaryAccount = util.NormalizeAccountNumber(req.BeneficiaryAccount)
+	req.BeneficiaryRouting = util.NormalizeAccountNumber(req.BeneficiaryRouting)
+	if err := settlement.ValidateBeneficiary(rail, req.BeneficiaryAccount, req.BeneficiaryRouting); err != nil {
+		ctx.JSON(externalTransferErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	account, ok := server.authorizeAccount(ctx, req.FromAccountID)
+	if !ok {
+		return
+	}
+	if account.Currency != req.Currency {
+		err := fmt.Errorf("account %v mismatched: %v vs %v", account.ID, account.Currency, req.Currency)
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	matches, err := server.screener.ScreenAccount(ctx, account)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if len(matches) > 0 {
+		err := errors.New("account is held for a screening review")
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return
+	}
+	limit, err := ratelimit.AllowTransfer(ctx, server.transfers, account.ID)
+	if err != nil {
+		ratelimit.SetHeaders(ctx.Writer.Header(), limit)
+		ctx.JSON(http.StatusTooManyRequests, errResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.CreateExternalTransferTx(ctx, db.CreateExternalTransferTxParams{
+		AccountID:          account.ID,
+		Amount:             req.Amount,
+		Rail:               rail,
+		BeneficiaryName:    req.BeneficiaryName,
+		BeneficiaryAccount: req.BeneficiaryAccount,
+		BeneficiaryRouting: req.BeneficiaryRouting,
+		Memo:               req.Memo,
+		AlertTask:          worker.NewAccountAlertTask,
+	})
+	if err != nil {
+		ctx.JSON(externalTransferErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(req.Currency, req.Amount)
+
+	envelope.JSON(ctx, http.StatusAccepted, result)
+}
+
+type externalTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getExternalTransfer returns an external transfer from an account of the caller, and where its
+// settlement is at.
+func (server *Server) getExternalTransfer(ctx *gin.Context) {
+	var uri externalTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	transfer, err := server.store.GetExternalTransfer(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(externalTransferErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if _, ok := server.authorizeAccount(ctx, transfer.AccountID); !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, transfer)
+}
+
+type listExternalTransfersURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listExternalTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listExternalTransfers lists the external transfers from an account of the caller, the newest first.
+func (server *Server) listExternalTransfers(ctx *gin.Context) {
+	var uri listExternalTransfersURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req listExternalTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	transfers, err := server.store.ListAccountExternalTransfers(ctx, db.ListAccountExternalTransfersParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, transfers)
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/settlement"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateExternalTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.EUR))
+	usdAccount := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	otherAccount := randomAccount(other.Username, testfixtures.WithCurrency(util.EUR))
+
+	body := gin.H{
+		"from_account_id":     account.ID,
+		"amount":              25,
+		"currency":            util.EUR,
+		"beneficiary_name":    "Anna Schmidt",
+		"beneficiary_account": "de89 3704 0044 0532 0130 00",
+	}
+	with := func(key string, value interface{}) gin.H {
+		changed := gin.H{}
+		for k, v := range body {
+			changed[k] = v
+		}
+		changed[key] = value
+		return changed
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					CreateExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateExternalTransferTxParams) (db.CreateExternalTransferTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, int64(25), arg.Amount)
+						require.Equal(t, settlement.RailSEPA, arg.Rail)
+						require.Equal(t, "DE89370400440532013000", arg.BeneficiaryAccount)
+						require.Empty(t, arg.BeneficiaryRouting)
+						require.NotNil(t, arg.AlertTask)
+						return db.CreateExternalTransferTxResult{
+							ExternalTransfer: db.ExternalTransfer{ID: 1, AccountID: account.ID, Amount: arg.Amount, Status: db.ExternalPending},
+							FromAccount:      account,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp db.CreateExternalTransferTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.ExternalPending, rsp.ExternalTransfer.Status)
+			},
+		},
+		{
+			name: "ACH",
+			body: gin.H{
+				"from_account_id":     usdAccount.ID,
+				"amount":              25,
+				"currency":            util.USD,
+				"beneficiary_name":    "John Smith",
+				"beneficiary_account": "000123456789",
+				"beneficiary_routing": "110000000",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					CreateExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateExternalTransferTxParams) (db.CreateExternalTransferTxResult, error) {
+						require.Equal(t, settlement.RailACH, arg.Rail)
+						require.Equal(t, "110000000", arg.BeneficiaryRouting)
+						return db.CreateExternalTransferTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidIBAN",
+			body: with("beneficiary_account", "DE89370400440532013001"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoRail",
+			body: with("currency", util.CAD),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: with("from_account_id", usdAccount.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Unauthorized",
+			body: with("from_account_id", otherAccount.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ScreeningMatch",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{"name:" + user.FullName}, nil)
+				store.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "FundsHeld",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(1).Return([]string{}, nil)
+				store.EXPECT().
+					CreateExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateExternalTransferTxResult{}, db.ErrFundsHeld)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/external_transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			addStepUpAuthorization(t, request, server.tokenMaker, user.Username, util.DepositorRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetExternalTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.EUR))
+	transfer := db.ExternalTransfer{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: account.ID,
+		Amount:    25,
+		Currency:  util.EUR,
+		Rail:      settlement.RailSEPA,
+		Status:    db.ExternalSubmitted,
+		Reference: "SEPA-0000000001",
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.ExternalTransfer
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, transfer, rsp)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ExternalTransfer{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/external_transfers/%d", transfer.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListExternalTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	transfers := []db.ExternalTransfer{
+		{ID: 2, AccountID: account.ID, Amount: 10, Currency: util.USD, Rail: settlement.RailACH, Status: db.ExternalPending},
+		{ID: 1, AccountID: account.ID, Amount: 20, Currency: util.USD, Rail: settlement.RailACH, Status: db.ExternalSettled},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"page_id": {"2"}, "page_size": {"5"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListAccountExternalTransfers(gomock.Any(), gomock.Eq(db.ListAccountExternalTransfersParams{
+						AccountID: account.ID,
+						Limit:     5,
+						Offset:    5,
+					})).
+					Times(1).
+					Return(transfers, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.ExternalTransfer
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, transfers, rsp)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: url.Values{"page_id": {"1"}, "page_size": {"500"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountExternalTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/external_transfers?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"github.com/backendmaster/simple_bank/graph"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const graphQLRoute = "/graphql"
+
+// graphQLRequest is the body of a GraphQL request, for the docs of the route.
+type graphQLRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLQuery is a GraphQL query sent by GET, its variables encoded as json.
+type graphQLQuery struct {
+	Query         string `form:"query" binding:"required"`
+	OperationName string `form:"operationName"`
+	Variables     string `form:"variables"`
+}
+
+// serveGraphQL serves the GraphQL api, see graph/schema.graphqls, as the authenticated user.
+func (server *Server) serveGraphQL(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	reqCtx := graph.WithPayload(ctx.Request.Context(), payload)
+	ctx.Request = ctx.Request.WithContext(graph.WithCountry(reqCtx, server.clientCountry(ctx)))
+	server.graphQL.ServeHTTP(ctx.Writer, ctx.Request)
+}
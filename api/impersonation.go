@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// The impersonation routes let admins act as a user for IMPERSONATION_TTL, e.g. to debug a support
+// ticket, with a token carrying both identities. Minting and revoking one are audit entries of
+// the admin, see admin.Operator.
+
+type createImpersonationRequest struct {
+	Username string `json:"username" binding:"required,username"`
+	Reason   string `json:"reason" binding:"required"`
+}
+
+type createImpersonationResponse struct {
+	Impersonation        db.Impersonation `json:"impersonation"`
+	AccessToken          string           `json:"access_token"`
+	AccessTokenExpiresAt time.Time        `json:"access_token_expires_at"`
+}
+
+func (server *Server) createImpersonation(ctx *gin.Context) {
+	if !requireAdmin(ctx, "impersonate users") {
+		return
+	}
+
+	var req createImpersonationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	accessToken, impersonation, err := operator.Impersonate(ctx, server.tokenMaker, req.Username, req.Reason, server.config.ImpersonationTTL)
+	if err != nil {
+		ctx.JSON(operatorErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, createImpersonationResponse{
+		Impersonation:        impersonation,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: impersonation.ExpiresAt,
+	})
+}
+
+type impersonationURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type revokeImpersonationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// revokeImpersonation revokes an impersonation, whose token is refused from the next request on.
+func (server *Server) revokeImpersonation(ctx *gin.Context) {
+	if !requireAdmin(ctx, "revoke impersonations") {
+		return
+	}
+
+	var uri impersonationURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req revokeImpersonationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	impersonation, err := operator.RevokeImpersonation(ctx, uuid.MustParse(uri.ID), req.Reason)
+	if err != nil {
+		ctx.JSON(operatorErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, impersonation)
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateImpersonationAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"username": user.Username, "reason": "ticket 1234"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateImpersonationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateImpersonationTxParams) (db.Impersonation, error) {
+						require.Equal(t, "admin", arg.Admin)
+						require.Equal(t, admin.ActionImpersonateUser, arg.Audit.Action)
+						return db.Impersonation{ID: arg.ID, Admin: arg.Admin, Username: arg.Username, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createImpersonationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, rsp.Impersonation.ID, payload.ID)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, "admin", payload.Impersonator)
+			},
+		},
+		{
+			name: "MissingReason",
+			body: gin.H{"username": user.Username},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateImpersonationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			body: gin.H{"username": user.Username, "reason": "ticket 1234"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateImpersonationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ImpersonateAdmin",
+			body: gin.H{"username": user.Username, "reason": "ticket 1234"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				adminUser := user
+				adminUser.Role = util.AdminRole
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(adminUser, nil)
+				store.EXPECT().CreateImpersonationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: gin.H{"username": user.Username, "reason": "ticket 1234"},
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"username": user.Username, "reason": "ticket 1234"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().CreateImpersonationTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Impersonation{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.ImpersonationTTL = 10 * time.Minute
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/admin/impersonations", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestRevokeImpersonationAPI(t *testing.T) {
+	impersonation := db.Impersonation{
+		ID:       uuid.New(),
+		Admin:    "admin",
+		Username: util.RandomOwnerName(),
+	}
+
+	testCases := []struct {
+		name          string
+		id            string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   impersonation.ID.String(),
+			body: gin.H{"reason": "ticket closed"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(impersonation.ID)).
+					Times(1).
+					Return(impersonation, nil)
+				store.EXPECT().
+					RevokeImpersonationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RevokeImpersonationTxParams) (db.Impersonation, error) {
+						require.Equal(t, impersonation.ID, arg.ID)
+						require.Equal(t, admin.ActionRevokeImpersonation, arg.Audit.Action)
+						require.Equal(t, admin.UserTarget(impersonation.Username), arg.Audit.Target)
+						return impersonation, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   "42",
+			body: gin.H{"reason": "ticket closed"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetImpersonation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   impersonation.ID.String(),
+			body: gin.H{"reason": "ticket closed"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetImpersonation(gomock.Any(), gomock.Any()).Times(1).Return(db.Impersonation{}, db.ErrRecordNotFound)
+				store.EXPECT().RevokeImpersonationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/impersonations/%s/revoke", tc.id)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+type ledgerReportResponse struct {
+	OK bool `json:"ok"`
+	db.LedgerReport
+}
+
+// verifyLedger runs the ledger verification on demand and reports the discrepancies it found.
+func (server *Server) verifyLedger(ctx *gin.Context) {
+	if !requireAdmin(ctx, "verify the ledger") {
+		return
+	}
+
+	report, err := server.store.VerifyLedger(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, ledgerReportResponse{
+		OK:           report.OK(),
+		LedgerReport: report,
+	})
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	"github.com/backendmaster/simple_bank/amortization"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The loan routes let users borrow under the loan offers the admins set up. A loan is disbursed
+// from the lending account of its currency, and the worker collects its installments when due.
+
+// loanErrStatus maps the errors of the loans to a response status.
+func loanErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrInvalidLoanAmount), errors.Is(err, admin.ErrInvalidLoanOffer):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrLoanOfferInactive), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+// listLoanOffers lists the loan offers the users can borrow under.
+func (server *Server) listLoanOffers(ctx *gin.Context) {
+	offers, err := server.store.ListLoanOffers(ctx, true)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, offers)
+}
+
+type createLoanRequest struct {
+	OfferID   int64 `json:"offer_id" binding:"required,min=1"`
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	Amount    int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// createLoan lends an amount to an account of the caller under an active offer in its currency,
+// repaid in the monthly installments of the schedule in the response.
+func (server *Server) createLoan(ctx *gin.Context) {
+	var req createLoanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	offer, err := server.store.GetLoanOffer(ctx, req.OfferID)
+	if err != nil {
+		ctx.JSON(loanErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, req.AccountID)
+	if !ok {
+		return
+	}
+	if account.Currency != offer.Currency {
+		err := fmt.Errorf("account %v mismatched: %v vs %v", account.ID, account.Currency, offer.Currency)
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	lending, err := server.store.GetLendingAccount(ctx, offer.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: lending.ID,
+		ToAccountID:   account.ID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	result, err := server.store.DisburseLoanTx(ctx, db.DisburseLoanTxParams{
+		OfferID:      offer.ID,
+		AccountID:    account.ID,
+		Amount:       req.Amount,
+		Installments: amortization.Schedule(offer, req.Amount, time.Now()),
+		OutboxTasks:  []db.CreateOutboxTaskParams{notifyTask},
+	})
+	if err != nil {
+		ctx.JSON(loanErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(offer.Currency, req.Amount)
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
+
+type loanURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type loanResponse struct {
+	Loan         db.Loan              `json:"loan"`
+	Installments []db.LoanInstallment `json:"installments"`
+	// OutstandingPrincipal is the principal the unpaid installments have left to repay.
+	OutstandingPrincipal int64 `json:"outstanding_principal"`
+	// OverdueAmount is what the unpaid installments past due add up to.
+	OverdueAmount int64 `json:"overdue_amount"`
+}
+
+func newLoanResponse(loan db.Loan, installments []db.LoanInstallment, now time.Time) loanResponse {
+	rsp := loanResponse{
+		Loan:         loan,
+		Installments: installments,
+	}
+	for _, installment := range installments {
+		if installment.Status != db.InstallmentDue {
+			continue
+		}
+		rsp.OutstandingPrincipal += installment.Principal
+		if !installment.DueAt.After(now) {
+			rsp.OverdueAmount += installment.Amount
+		}
+	}
+	return rsp
+}
+
+// getLoan returns a loan of an account of the caller with its schedule, and where its repayment
+// stands.
+func (server *Server) getLoan(ctx *gin.Context) {
+	var uri loanURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	loan, err := server.store.GetLoan(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(loanErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if _, ok := server.authorizeAccount(ctx, loan.AccountID); !ok {
+		return
+	}
+
+	installments, err := server.store.ListLoanInstallments(ctx, loan.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newLoanResponse(loan, installments, time.Now()))
+}
+
+type listLoansURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listLoansRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listLoans lists the loans of an account of the caller, the newest first.
+func (server *Server) listLoans(ctx *gin.Context) {
+	var uri listLoansURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req listLoansRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	loans, err := server.store.ListAccountLoans(ctx, db.ListAccountLoansParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, loans)
+}
+
+type createLoanOfferRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Currency  string `json:"currency" binding:"required,currency"`
+	MinAmount int64  `json:"min_amount" binding:"required,gt=0,amount=Currency"`
+	MaxAmount int64  `json:"max_amount" binding:"required,gtefield=MinAmount,amount=Currency"`
+	// AnnualRateBps is the annual interest rate, in hundredths of a percent.
+	AnnualRateBps int32  `json:"annual_rate_bps" binding:"min=0,max=10000"`
+	TermMonths    int32  `json:"term_months" binding:"required,min=1,max=360"`
+	Reason        string `json:"reason" binding:"required,max=500"`
+}
+
+// createLoanOffer offers the users a loan product, at a fixed rate over a term.
+func (server *Server) createLoanOffer(ctx *gin.Context) {
+	if !requireAdmin(ctx, "create loan offers") {
+		return
+	}
+
+	var req createLoanOfferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	offer, err := operator.CreateLoanOffer(ctx, admin.CreateLoanOfferParams{
+		Name:          req.Name,
+		Currency:      req.Currency,
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		AnnualRateBps: req.AnnualRateBps,
+		TermMonths:    req.TermMonths,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(loanErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, offer)
+}
+
+type loanOfferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type setLoanOfferActiveRequest struct {
+	Active *bool  `json:"active" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// setLoanOfferActive retires a loan offer or offers it again.
+func (server *Server) setLoanOfferActive(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change loan offers") {
+		return
+	}
+
+	var uri loanOfferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req setLoanOfferActiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	offer, err := operator.SetLoanOfferActive(ctx, uri.ID, *req.Active, req.Reason)
+	if err != nil {
+		ctx.JSON(loanErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, offer)
+}
+
+type listDelinquentLoansRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listDelinquentLoans lists the delinquent loans with what they have overdue, the longest overdue
+// first.
+func (server *Server) listDelinquentLoans(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the delinquent loans") {
+		return
+	}
+
+	var req listDelinquentLoansRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	loans, err := server.store.ListDelinquentLoans(ctx, db.ListDelinquentLoansParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, loans)
+}
@@ -0,0 +1,429 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomLoanOffer() db.LoanOffer {
+	return db.LoanOffer{
+		ID:            util.RandomInt(1, 1000),
+		Name:          "Personal loan",
+		Currency:      util.USD,
+		MinAmount:     10000,
+		MaxAmount:     500000,
+		AnnualRateBps: 1200,
+		TermMonths:    12,
+		Active:        true,
+		CreatedBy:     "admin",
+	}
+}
+
+func TestCreateLoanAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithID(1), testfixtures.WithCurrency(util.USD))
+	lending := randomAccount(db.LendingOwner, testfixtures.WithID(2), testfixtures.WithCurrency(util.USD))
+	offer := randomLoanOffer()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": 100000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Eq(offer.ID)).Times(1).Return(offer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetLendingAccount(gomock.Any(), gomock.Eq(util.USD)).Times(1).Return(lending, nil)
+				store.EXPECT().
+					DisburseLoanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DisburseLoanTxParams) (db.DisburseLoanTxResult, error) {
+						require.Equal(t, offer.ID, arg.OfferID)
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, int64(100000), arg.Amount)
+						require.Len(t, arg.Installments, int(offer.TermMonths))
+						require.Len(t, arg.OutboxTasks, 1)
+
+						var principal int64
+						for _, installment := range arg.Installments {
+							principal += installment.Principal
+						}
+						require.Equal(t, arg.Amount, principal)
+						return db.DisburseLoanTxResult{
+							Loan: db.Loan{ID: 1, OfferID: offer.ID, AccountID: account.ID, Principal: arg.Amount, Status: db.LoanActive},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.DisburseLoanTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.LoanActive, rsp.Loan.Status)
+			},
+		},
+		{
+			name: "OutOfRange",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": 600000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Eq(offer.ID)).Times(1).Return(offer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetLendingAccount(gomock.Any(), gomock.Any()).Times(1).Return(lending, nil)
+				store.EXPECT().
+					DisburseLoanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DisburseLoanTxResult{}, db.ErrInvalidLoanAmount)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OfferInactive",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": 100000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Eq(offer.ID)).Times(1).Return(offer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetLendingAccount(gomock.Any(), gomock.Any()).Times(1).Return(lending, nil)
+				store.EXPECT().
+					DisburseLoanTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DisburseLoanTxResult{}, db.ErrLoanOfferInactive)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": 100000},
+			buildStubs: func(store *mockdb.MockStore) {
+				eurOffer := offer
+				eurOffer.Currency = util.EUR
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Eq(offer.ID)).Times(1).Return(eurOffer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DisburseLoanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedAccount",
+			body: gin.H{"offer_id": offer.ID, "account_id": lending.ID, "amount": 100000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Eq(offer.ID)).Times(1).Return(offer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(lending.ID)).Times(1).Return(lending, nil)
+				store.EXPECT().DisburseLoanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "OfferNotFound",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": 100000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Any()).Times(1).Return(db.LoanOffer{}, db.ErrRecordNotFound)
+				store.EXPECT().DisburseLoanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: gin.H{"offer_id": offer.ID, "account_id": account.ID, "amount": -1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoanOffer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DisburseLoanTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/loans", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetLoanAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithID(1), testfixtures.WithCurrency(util.USD))
+	loan := db.Loan{ID: util.RandomInt(1, 1000), AccountID: account.ID, Principal: 300, Currency: util.USD, Status: db.LoanDelinquent}
+	now := time.Now()
+	installments := []db.LoanInstallment{
+		{ID: 1, LoanID: loan.ID, Number: 1, DueAt: now.AddDate(0, -2, 0), Principal: 100, Interest: 3, Amount: 103, Status: db.InstallmentPaid},
+		{ID: 2, LoanID: loan.ID, Number: 2, DueAt: now.AddDate(0, -1, 0), Principal: 100, Interest: 2, Amount: 102, Status: db.InstallmentDue},
+		{ID: 3, LoanID: loan.ID, Number: 3, DueAt: now.AddDate(0, 1, 0), Principal: 100, Interest: 1, Amount: 101, Status: db.InstallmentDue},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoan(gomock.Any(), gomock.Eq(loan.ID)).Times(1).Return(loan, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListLoanInstallments(gomock.Any(), gomock.Eq(loan.ID)).Times(1).Return(installments, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loanResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, loan.ID, rsp.Loan.ID)
+				require.Len(t, rsp.Installments, 3)
+				require.Equal(t, int64(200), rsp.OutstandingPrincipal)
+				require.Equal(t, int64(102), rsp.OverdueAmount)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoan(gomock.Any(), gomock.Eq(loan.ID)).Times(1).Return(loan, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListLoanInstallments(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoan(gomock.Any(), gomock.Any()).Times(1).Return(db.Loan{}, db.ErrRecordNotFound)
+				store.EXPECT().ListLoanInstallments(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/loans/%d", loan.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateLoanOfferAPI(t *testing.T) {
+	offer := randomLoanOffer()
+	body := gin.H{
+		"name":            offer.Name,
+		"currency":        offer.Currency,
+		"min_amount":      offer.MinAmount,
+		"max_amount":      offer.MaxAmount,
+		"annual_rate_bps": offer.AnnualRateBps,
+		"term_months":     offer.TermMonths,
+		"reason":          "new product",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLoanOfferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateLoanOfferTxParams) (db.LoanOffer, error) {
+						require.Equal(t, offer.Name, arg.Name)
+						require.Equal(t, offer.AnnualRateBps, arg.AnnualRateBps)
+						require.Equal(t, "admin", arg.CreatedBy)
+						require.Equal(t, admin.ActionCreateLoanOffer, arg.Audit.Action)
+						return offer, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.LoanOffer
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, offer.ID, rsp.ID)
+			},
+		},
+		{
+			name: "MaxUnderMin",
+			body: gin.H{
+				"name":        offer.Name,
+				"currency":    offer.Currency,
+				"min_amount":  500,
+				"max_amount":  100,
+				"term_months": offer.TermMonths,
+				"reason":      "new product",
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateLoanOfferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateLoanOfferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/admin/loan_offers", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListDelinquentLoansAPI(t *testing.T) {
+	loans := []db.ListDelinquentLoansRow{
+		{ID: 1, AccountID: 1, Principal: 100000, Currency: util.USD, Status: db.LoanDelinquent, OverdueAmount: 8885, OverdueInstallments: 1},
+	}
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDelinquentLoans(gomock.Any(), gomock.Eq(db.ListDelinquentLoansParams{Limit: 5, Offset: 0})).
+					Times(1).
+					Return(loans, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.ListDelinquentLoansRow
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, loans[0].OverdueAmount, rsp[0].OverdueAmount)
+			},
+		},
+		{
+			name: "NotAdmin",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDelinquentLoans(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/loans/delinquent?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// requestLocale returns the locale the errors of the request are written in: the one of its
+// Accept-Language header, else the one of the preferences of the caller, else English.
+func requestLocale(ctx *gin.Context) string {
+	if locale, ok := i18n.Negotiate(ctx.GetHeader("Accept-Language")); ok {
+		return locale
+	}
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		if locale := payload.(*token.Payload).Locale; i18n.IsSupported(locale) {
+			return locale
+		}
+	}
+	return i18n.English
+}
+
+// errResponse is the envelope of the errors, translated in the locale of the request. The errors
+// of the bindings list one error for each field of the request breaking a rule, like the field
+// violations of the gRPC api.
+func errResponse(ctx *gin.Context, err error) envelope.Envelope {
+	locale := requestLocale(ctx)
+	if violations := fieldViolations(locale, err); len(violations) > 0 {
+		return envelope.Errors(ctx, violations...)
+	}
+	return envelope.Errors(ctx, envelope.Error{Message: i18n.T(locale, err.Error())})
+}
+
+// fieldViolations returns the fields err, an error of a binding, complains about, in locale.
+func fieldViolations(locale string, err error) []envelope.Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations := make([]envelope.Error, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			violations[i] = envelope.Error{
+				Field:   fieldPath(fieldErr),
+				Message: i18n.Rule(locale, fieldName(fieldErr.Field()), fieldErr.Tag(), fieldErr.Param(), ruleKind(fieldErr.Kind())),
+			}
+		}
+		return violations
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field := typeErr.Field
+		name := field[strings.LastIndex(field, ".")+1:]
+		description := i18n.T(locale, "validation.type",
+			"field", i18n.Field(locale, name),
+			"param", i18n.T(locale, "type."+jsonType(typeErr.Type.Kind())))
+		return []envelope.Error{{Field: field, Message: description}}
+	}
+	return nil
+}
+
+// fieldPath is the path of the field of fieldErr in the request, e.g. notifications.muted[0].
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	// the namespace starts with the name of the struct of the request
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// fieldName is the name of the field without the index of the item, e.g. muted for muted[0].
+func fieldName(field string) string {
+	if i := strings.Index(field, "["); i >= 0 {
+		return field[:i]
+	}
+	return field
+}
+
+func ruleKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return ""
+}
+
+// jsonType is the JSON type of the values of kind.
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return "number"
+}
+
+// requestFieldName names the fields of the requests in the errors of the bindings like the
+// clients know them, by their json, form or uri names.
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalizedBindingErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           gin.H
+		acceptLanguage string
+		errs           []envelope.Error
+	}{
+		{
+			name: "English",
+			body: gin.H{"username": "Bob!", "password": "secret", "full_name": "Bob", "email": "bob@email.com"},
+			errs: []envelope.Error{{Field: "username", Message: "username must be 3-10 lowercase letters, digits or underscores"}},
+		},
+		{
+			name:           "TraditionalChinese",
+			body:           gin.H{"username": "bob", "full_name": "Bob", "email": "bob@email.com"},
+			acceptLanguage: "zh-TW,zh;q=0.9,en;q=0.8",
+			errs:           []envelope.Error{{Field: "password", Message: "密碼為必填"}},
+		},
+		{
+			name:           "UnsupportedLanguage",
+			body:           gin.H{"username": "bob", "password": "secret", "full_name": "Bob"},
+			acceptLanguage: "fr-FR",
+			errs:           []envelope.Error{{Field: "email", Message: "email is required"}},
+		},
+		{
+			name:           "ManyFields",
+			body:           gin.H{"username": "bob", "password": "123", "email": "bob"},
+			acceptLanguage: "zh-Hant",
+			errs: []envelope.Error{
+				{Field: "password", Message: "密碼必須是 6 到 10 個字元"},
+				{Field: "full_name", Message: "姓名為必填"},
+				{Field: "email", Message: "電子郵件必須是 3 到 200 個字元的電子郵件地址"},
+			},
+		},
+		{
+			name:           "WrongType",
+			body:           gin.H{"username": 42, "password": "secret", "full_name": "Bob", "email": "bob@email.com"},
+			acceptLanguage: "zh-TW",
+			errs:           []envelope.Error{{Field: "username", Message: "使用者名稱必須是字串"}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().CreateUserTx(gomock.Any(), gomock.Any()).Times(0)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.acceptLanguage != "" {
+				request.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+			var rsp envelope.Envelope
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Nil(t, rsp.Data)
+			require.Equal(t, tc.errs, rsp.Errors)
+		})
+	}
+}
+
+func TestLocalizedErrorsOfUser(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(util.RandomOwnerName())
+
+	testCases := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		err            string
+	}{
+		{
+			name:   "Preference",
+			locale: i18n.TraditionalChinese,
+			err:    "帳戶不屬於已驗證的使用者",
+		},
+		{
+			name:           "HeaderOverPreference",
+			locale:         i18n.TraditionalChinese,
+			acceptLanguage: "en-US",
+			err:            "account doesn't belongs to authenticated user",
+		},
+		{
+			name:   "NoPreference",
+			locale: "",
+			err:    "account doesn't belongs to authenticated user",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", account.ID), nil)
+			require.NoError(t, err)
+			addLocalizedAuthorization(t, request, server.tokenMaker, user, tc.locale)
+			if tc.acceptLanguage != "" {
+				request.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			var rsp envelope.Envelope
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, []envelope.Error{{Message: tc.err}}, rsp.Errors)
+		})
+	}
+}
+
+// addLocalizedAuthorization authorizes request as user with locale in the preferences.
+func addLocalizedAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, user db.User, locale string) {
+	accessToken, _, err := tokenMaker.CreateToken(user.Username, util.DepositorRole, util.DefaultTenant, locale, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+}
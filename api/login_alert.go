@@ -0,0 +1,239 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// A login unlike the last sessions of its user, see fraud.CheckLogin, is recorded as an alert,
+// and its user gets a security notification rather than the new login one, with a link to report
+// the login. The link carries a code of its own, so the routes reporting a login are public: the
+// user may be locked out by then. Reporting blocks every session of the user and holds their
+// logins until they reset their password with a code emailed to them. Whoever made the login may
+// read the notification, but not the email.
+
+var (
+	errWrongReportCode       = errors.New("wrong code of the login report")
+	errPasswordResetRequired = errors.New("reset your password with the code emailed to you first")
+	errTooManyWrongCodes     = errors.New("too many wrong codes, ask for a new one")
+)
+
+// loginAlertErrStatus maps the errors of the login alerts to a response status.
+func loginAlertErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrLoginAlertReported), errors.Is(err, db.ErrNoPasswordReset):
+		return http.StatusConflict
+	}
+	return errStatus(err)
+}
+
+// flagSuspiciousLogin sets the alert of the session of arg when the login is unlike history, the
+// last sessions of its user, along with the security notification with the link reporting it.
+func (server *Server) flagSuspiciousLogin(arg *db.CreateSessionTxParams, history []db.Session) error {
+	session := arg.CreateSessionParams
+	reasons := fraud.CheckLogin(history, session.ClientIp, session.Country, session.UserAgent)
+	if reasons == nil {
+		return nil
+	}
+
+	code, hash, err := fraud.NewLinkCode()
+	if err != nil {
+		return err
+	}
+	arg.Alert = &db.CreateLoginAlertParams{
+		Username:   session.Username,
+		ClientIp:   session.ClientIp,
+		UserAgent:  session.UserAgent,
+		Country:    session.Country,
+		Reasons:    reasons,
+		ReportHash: hash,
+	}
+	arg.AfterAlert = func(alert db.LoginAlert) ([]db.CreateOutboxTaskParams, error) {
+		task, err := worker.NewSuspiciousLoginTask(alert, server.config.LoginReportURL, code)
+		if err != nil {
+			return nil, err
+		}
+		return []db.CreateOutboxTaskParams{task}, nil
+	}
+	return nil
+}
+
+// passwordResetTasks returns the outbox tasks emailing code to the user of an alert.
+func passwordResetTasks(code string) func(alert db.LoginAlert) ([]db.CreateOutboxTaskParams, error) {
+	return func(alert db.LoginAlert) ([]db.CreateOutboxTaskParams, error) {
+		task, err := worker.NewSendPasswordResetTask(&worker.PayloadSendPasswordReset{
+			AlertID: alert.ID,
+			Code:    code,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []db.CreateOutboxTaskParams{task}, nil
+	}
+}
+
+type loginAlertURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type reportLoginRequest struct {
+	// Code is the code of the link of the security notification.
+	Code string `json:"code" binding:"required,max=128"`
+}
+
+// checkReportCode checks that code is the code of the link reporting the alert id, proving the
+// caller got the security notification. It writes the error response and returns false otherwise.
+func (server *Server) checkReportCode(ctx *gin.Context, id int64, code string) bool {
+	alert, err := server.store.GetLoginAlert(ctx, id)
+	if err != nil {
+		ctx.JSON(loginAlertErrStatus(err), errResponse(ctx, err))
+		return false
+	}
+	if !fraud.CheckOTP(code, alert.ReportHash) {
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errWrongReportCode))
+		return false
+	}
+	return true
+}
+
+// reportLogin reports a login its user didn't make: it blocks every session of the user and
+// emails them the code resetting their password.
+func (server *Server) reportLogin(ctx *gin.Context) {
+	var uri loginAlertURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req reportLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if !server.checkReportCode(ctx, uri.ID, req.Code) {
+		return
+	}
+	code, hash, err := fraud.NewOTP()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	alert, err := server.store.ReportLoginAlertTx(ctx, db.ReportLoginAlertTxParams{
+		ReportLoginAlertParams: db.ReportLoginAlertParams{ID: uri.ID, ResetHash: hash},
+		AfterReport:            passwordResetTasks(code),
+	})
+	if err != nil {
+		ctx.JSON(loginAlertErrStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	log.Warn().Int64("alert id", alert.ID).Str("username", alert.Username).
+		Str("client ip", alert.ClientIp).Msg("login reported, sessions blocked")
+	ctx.Status(http.StatusNoContent)
+}
+
+// sendPasswordResetCode emails a new code resetting the password of a reported login, e.g. once
+// the last one expired.
+func (server *Server) sendPasswordResetCode(ctx *gin.Context) {
+	var uri loginAlertURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req reportLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if !server.checkReportCode(ctx, uri.ID, req.Code) {
+		return
+	}
+	code, hash, err := fraud.NewOTP()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	_, err = server.store.SendPasswordResetCodeTx(ctx, db.SendPasswordResetCodeTxParams{
+		SetLoginAlertResetCodeParams: db.SetLoginAlertResetCodeParams{ID: uri.ID, ResetHash: hash},
+		AfterUpdate:                  passwordResetTasks(code),
+	})
+	if err != nil {
+		ctx.JSON(loginAlertErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+type resetPasswordRequest struct {
+	// Code is the code emailed once the login was reported.
+	Code     string `json:"code" binding:"required,len=6,numeric"`
+	Password string `json:"password" binding:"required,password"`
+}
+
+// resetPassword sets the new password of the user of a reported login, which lets them log in
+// again.
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var uri loginAlertURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	alert, err := server.store.GetLoginAlert(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(loginAlertErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !alert.ReportedAt.Valid || alert.PasswordResetAt.Valid {
+		ctx.JSON(http.StatusConflict, errResponse(ctx, db.ErrNoPasswordReset))
+		return
+	}
+	if alert.ResetAttempts >= fraud.MaxOTPAttempts {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errTooManyWrongCodes))
+		return
+	}
+	if !alert.ResetSentAt.Valid || time.Since(alert.ResetSentAt.Time) > fraud.OTPValidity {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errOTPExpired))
+		return
+	}
+	if !fraud.CheckOTP(req.Code, alert.ResetHash) {
+		if _, err := server.store.AddLoginAlertResetAttempt(ctx, alert.ID); err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errWrongOTP))
+		return
+	}
+
+	hashedPassword, err := util.HashedPassword(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	user, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		AlertID:        alert.ID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		ctx.JSON(loginAlertErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,347 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUserSuspicious(t *testing.T) {
+	user, password := randomUser(t)
+	usual := []db.Session{{Username: user.Username, ClientIp: "10.0.0.1", UserAgent: "Firefox"}}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Suspicious",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasPendingPasswordReset(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+				store.EXPECT().ListRecentSessions(gomock.Any(), gomock.Any()).Times(1).Return(usual, nil)
+				store.EXPECT().
+					CreateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionTxParams) (db.CreateSessionTxResult, error) {
+						require.NotNil(t, arg.Alert)
+						require.Equal(t, user.Username, arg.Alert.Username)
+						require.Equal(t, []string{fraud.LoginNewIP, fraud.LoginNewUserAgent}, arg.Alert.Reasons)
+						require.NotEmpty(t, arg.Alert.ReportHash)
+						// the security notification replaces the new login one
+						require.Empty(t, arg.OutboxTasks)
+
+						tasks, err := arg.AfterAlert(db.LoginAlert{ID: 12, Username: arg.Username, ClientIp: arg.ClientIp, UserAgent: arg.UserAgent})
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						var payload worker.PayloadSendNotification
+						require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+						require.Equal(t, notification.KindSuspiciousLogin, payload.Kind)
+						require.Contains(t, payload.Data["report_url"], "id=12")
+
+						return db.CreateSessionTxResult{Session: db.Session{ID: arg.ID, Username: arg.Username}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Usual",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasPendingPasswordReset(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+				store.EXPECT().
+					ListRecentSessions(gomock.Any(), gomock.Eq(db.ListRecentSessionsParams{Username: user.Username, Limit: fraud.LoginHistorySize})).
+					Times(1).
+					Return([]db.Session{{Username: user.Username, ClientIp: "10.0.0.1", UserAgent: "bank-app/1.0"}}, nil)
+				store.EXPECT().
+					CreateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionTxParams) (db.CreateSessionTxResult, error) {
+						require.Nil(t, arg.Alert)
+						require.Len(t, arg.OutboxTasks, 1)
+						return db.CreateSessionTxResult{Session: db.Session{ID: arg.ID, Username: arg.Username}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PasswordResetPending",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasPendingPasswordReset(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(true, nil)
+				store.EXPECT().ListRecentSessions(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errPasswordResetRequired.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(gin.H{"username": user.Username, "password": password})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/v1/users/login", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", "bank-app/1.0")
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReportLoginAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	code, hash, err := fraud.NewLinkCode()
+	require.NoError(t, err)
+	alert := db.LoginAlert{ID: 12, Username: user.Username, ClientIp: "1.2.3.4", ReportHash: hash}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Eq(alert.ID)).Times(1).Return(alert, nil)
+				store.EXPECT().
+					ReportLoginAlertTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ReportLoginAlertTxParams) (db.LoginAlert, error) {
+						require.Equal(t, alert.ID, arg.ID)
+						require.NotEmpty(t, arg.ResetHash)
+
+						tasks, err := arg.AfterReport(alert)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskSendPasswordReset, tasks[0].TaskType)
+						var payload worker.PayloadSendPasswordReset
+						require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+						require.True(t, fraud.CheckOTP(payload.Code, arg.ResetHash))
+						return alert, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "WrongCode",
+			body: gin.H{"code": "wrong"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Eq(alert.ID)).Times(1).Return(alert, nil)
+				store.EXPECT().ReportLoginAlertTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Reported",
+			body: gin.H{"code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Eq(alert.ID)).Times(1).Return(alert, nil)
+				store.EXPECT().ReportLoginAlertTx(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAlert{}, db.ErrLoginAlertReported)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"code": code},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Any()).Times(1).Return(db.LoginAlert{}, db.ErrRecordNotFound)
+				store.EXPECT().ReportLoginAlertTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoCode",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/v1/login_alerts/%d/report", alert.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResetPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	code, hash, err := fraud.NewOTP()
+	require.NoError(t, err)
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	reported := db.LoginAlert{ID: 12, Username: user.Username, ReportedAt: now, ResetHash: hash, ResetSentAt: now}
+
+	testCases := []struct {
+		name          string
+		code          string
+		alert         func() db.LoginAlert
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			code:  code,
+			alert: func() db.LoginAlert { return reported },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ResetPasswordTxParams) (db.User, error) {
+						require.Equal(t, reported.ID, arg.AlertID)
+						require.NoError(t, util.CheckPassword("n3wpass", arg.HashedPassword))
+						return user, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name:  "WrongCode",
+			code:  wrongCode,
+			alert: func() db.LoginAlert { return reported },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AddLoginAlertResetAttempt(gomock.Any(), gomock.Eq(reported.ID)).Times(1).Return(reported, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyAttempts",
+			code: code,
+			alert: func() db.LoginAlert {
+				alert := reported
+				alert.ResetAttempts = fraud.MaxOTPAttempts
+				return alert
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			code: code,
+			alert: func() db.LoginAlert {
+				alert := reported
+				alert.ResetSentAt = pgtype.Timestamptz{Time: time.Now().Add(-fraud.OTPValidity - time.Minute), Valid: true}
+				return alert
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotReported",
+			code: code,
+			alert: func() db.LoginAlert {
+				return db.LoginAlert{ID: reported.ID, Username: user.Username}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetLoginAlert(gomock.Any(), gomock.Eq(reported.ID)).Times(1).Return(tc.alert(), nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(gin.H{"code": tc.code, "password": "n3wpass"})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/v1/login_alerts/%d/reset_password", reported.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,27 +1,65 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"testing"
 	"time"
 
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/maintenance"
 	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
+		ReceiptKey:          util.RandomString(32),
+		CardKey:             util.RandomString(32),
+		CardNetworkKey:      util.RandomString(32),
+		PaymentRequestKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		StepUpMaxAge:        5 * time.Minute,
 	}
-	server, err := NewServer(config, store)
+	// the users of the tests accepted the latest legal documents, unless a test stubs otherwise
+	// before, see TestRequireConsents
+	if mock, ok := store.(*mockdb.MockStore); ok {
+		mock.EXPECT().ListPendingLegalDocuments(gomock.Any(), gomock.Any()).AnyTimes().Return([]db.LegalDocument{}, nil)
+	}
+	server, err := NewServer(config, store, maintenance.NewMode(false), nil, nil)
 	require.NoError(t, err)
 
 	return server
 }
 
+// requireBodyData decodes the data of the envelope of body into data.
+func requireBodyData(t *testing.T, body *bytes.Buffer, data any) {
+	var rsp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &rsp))
+	require.NoError(t, json.Unmarshal(rsp.Data, data))
+}
+
+// requireBodyPage decodes the data of the envelope of body, a page of a list, into data and
+// returns its pagination.
+func requireBodyPage(t *testing.T, body *bytes.Buffer, data any) envelope.Pagination {
+	var rsp struct {
+		Data       json.RawMessage      `json:"data"`
+		Pagination *envelope.Pagination `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &rsp))
+	require.NoError(t, json.Unmarshal(rsp.Data, data))
+	require.NotNil(t, rsp.Pagination)
+	return *rsp.Pagination
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
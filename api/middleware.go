@@ -5,8 +5,12 @@ import (
 	"net/http"
 	"strings"
 
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/ratelimit"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -15,27 +19,30 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// authMiddleware authenticates the access token of the request. The impersonation tokens are also
+// checked against store, so that a revoked one is refused, and their requests are logged with both
+// identities.
+func authMiddleware(tokenMaker token.Maker, store db.SessionStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		//check authorization header is provide
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(ctx, err))
 			return
 		}
 		//get authorization header type and verify, get the payload body
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
 			err := errors.New("Invalid authorization format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(ctx, err))
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
 			err := errors.New("unsupported authorization type")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(ctx, err))
 			return
 		}
 
@@ -43,11 +50,51 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 
 		payload, err := tokenMaker.VerifyToken(accesToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(ctx, err))
 			return
 		}
+		if payload.Impersonated() {
+			if err := db.CheckImpersonation(ctx, store, payload.ID); err != nil {
+				if errors.Is(err, db.ErrImpersonationRevoked) {
+					ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(ctx, err))
+					return
+				}
+				ctx.AbortWithStatusJSON(errStatus(err), errResponse(ctx, err))
+				return
+			}
+			log.Info().Str("protocol", "http").
+				Str("user", payload.Username).
+				Str("impersonator", payload.Impersonator).
+				Str("impersonation id", payload.ID.String()).
+				Str("method", ctx.Request.Method).
+				Str("path", ctx.Request.URL.Path).
+				Msg("impersonated request")
+		}
 		//set patyload body to ctx and forward to Next handler func
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// clientIP is the ip of the client of the request, the same for the sessions, the consents and
+// the audit log.
+func clientIP(ctx *gin.Context) string {
+	return util.ClientIP(ctx.Request.Header.Values("X-Forwarded-For"), ctx.Request.RemoteAddr)
+}
+
+// rateLimitKey limits authenticated requests per user and the others per client ip.
+func rateLimitKey(ctx *gin.Context) string {
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		return "user:" + payload.(*token.Payload).Username
+	}
+	return ratelimit.IPKey(ctx.Request)
+}
+
+// auditActor is the user of the audit log of a request and the admin impersonating them, or ""
+// when its route isn't authenticated.
+func auditActor(ctx *gin.Context) (string, string) {
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		return payload.(*token.Payload).Username, payload.(*token.Payload).Impersonator
+	}
+	return "", ""
+}
@@ -1,14 +1,21 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,15 +25,23 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	accessTokenDuration time.Duration,
 ) {
-	accessToken, payload, err := tokenMaker.CreateToken(username, accessTokenDuration)
+	accessToken, payload, err := tokenMaker.CreateToken(username, role, util.DefaultTenant, "", accessTokenDuration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+// addStepUpAuthorization is addAuthorization for a user who completed a multi-factor step-up now.
+func addStepUpAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, username string, role string) {
+	accessToken, _, err := tokenMaker.CreateTokenAuthenticatedAt(username, role, util.DefaultTenant, "", time.Now(), token.ACRMultiFactor, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+}
+
 func TestMiddleWare(t *testing.T) {
 
 	testCase := []struct {
@@ -37,7 +52,7 @@ func TestMiddleWare(t *testing.T) {
 		{
 			name: "ok",
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -54,7 +69,7 @@ func TestMiddleWare(t *testing.T) {
 		{
 			name: "unsupported authorizatio type",
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -63,7 +78,7 @@ func TestMiddleWare(t *testing.T) {
 		{
 			name: "invalid authorizatio type",
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", util.DepositorRole, time.Minute)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -72,7 +87,7 @@ func TestMiddleWare(t *testing.T) {
 		{
 			name: "expired token",
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.DepositorRole, -time.Minute)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -86,7 +101,7 @@ func TestMiddleWare(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			server := newTestServer(t, nil)
 			routePath := "/auth"
-			server.router.GET(routePath, authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+			server.router.GET(routePath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
 				ctx.JSON(http.StatusOK, gin.H{})
 			})
 
@@ -100,3 +115,89 @@ func TestMiddleWare(t *testing.T) {
 		})
 	}
 }
+
+func TestMiddlewareImpersonation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, id uuid.UUID)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, id uuid.UUID) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(id)).
+					Times(1).
+					Return(db.Impersonation{ID: id}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"user":"user","impersonator":"admin"}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "Revoked",
+			buildStubs: func(store *mockdb.MockStore, id uuid.UUID) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(id)).
+					Times(1).
+					Return(db.Impersonation{ID: id, RevokedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore, id uuid.UUID) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(id)).
+					Times(1).
+					Return(db.Impersonation{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, id uuid.UUID) {
+				store.EXPECT().
+					GetImpersonation(gomock.Any(), gomock.Eq(id)).
+					Times(1).
+					Return(db.Impersonation{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			accessToken, payload, err := server.tokenMaker.CreateImpersonationToken("user", util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+			require.NoError(t, err)
+			tc.buildStubs(store, payload.ID)
+
+			routePath := "/auth"
+			server.router.GET(routePath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
+				user, impersonator := auditActor(ctx)
+				ctx.JSON(http.StatusOK, gin.H{"user": user, "impersonator": impersonator})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, routePath, nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
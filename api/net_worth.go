@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type netWorthRequest struct {
+	// Currency is the default currency of the preferences of the caller when empty.
+	Currency string `form:"currency" binding:"omitempty,currency"`
+}
+
+type netWorthAccount struct {
+	AccountID int64  `json:"account_id"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	// ConvertedBalance is the balance in the currency of the net worth, at Rate.
+	ConvertedBalance int64  `json:"converted_balance"`
+	Rate             string `json:"rate"`
+}
+
+type netWorthResponse struct {
+	Currency string `json:"currency"`
+	NetWorth int64  `json:"net_worth"`
+	// RatesFetchedAt is when the oldest of the rates the balances were converted at was fetched,
+	// none when every account is in the currency of the net worth.
+	RatesFetchedAt *time.Time        `json:"rates_fetched_at,omitempty"`
+	Accounts       []netWorthAccount `json:"accounts"`
+}
+
+// getNetWorth sums the balances of the accounts of the caller, converted into their default
+// currency at the latest exchange rates.
+func (server *Server) getNetWorth(ctx *gin.Context) {
+	var req netWorthRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.Currency == "" {
+		settings, err := server.userPreferences(ctx, payload.Username)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		if settings.DefaultCurrency == "" {
+			err := errors.New("currency is required without a default currency in the preferences")
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
+		}
+		req.Currency = settings.DefaultCurrency
+	}
+
+	accounts, err := server.store.ListAllAccounts(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := netWorthResponse{Currency: req.Currency, Accounts: make([]netWorthAccount, len(accounts))}
+	for i, account := range accounts {
+		conversion, err := server.rates.Convert(ctx, account.Balance, account.Currency, req.Currency)
+		if err != nil {
+			ctx.JSON(exchangeErrStatus(err), errResponse(ctx, err))
+			return
+		}
+		rsp.NetWorth += conversion.ToAmount
+		rsp.Accounts[i] = netWorthAccount{
+			AccountID:        account.ID,
+			Currency:         account.Currency,
+			Balance:          account.Balance,
+			ConvertedBalance: conversion.ToAmount,
+			Rate:             conversion.Rate,
+		}
+		if account.Currency != req.Currency && (rsp.RatesFetchedAt == nil || conversion.FetchedAt.Before(*rsp.RatesFetchedAt)) {
+			fetchedAt := conversion.FetchedAt
+			rsp.RatesFetchedAt = &fetchedAt
+		}
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNetWorthAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Preferences = []byte(`{"default_currency": "EUR"}`)
+	usd := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	usd.Balance = 10000
+	eur := randomAccount(user.Username, testfixtures.WithCurrency(util.EUR))
+	eur.Balance = 5000
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.Account{usd, eur}, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(randomExchangeRates(fetchedAt), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp netWorthResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, util.EUR, rsp.Currency)
+				require.Equal(t, int64(9240+5000), rsp.NetWorth)
+				require.Equal(t, &fetchedAt, rsp.RatesFetchedAt)
+				require.Equal(t, []netWorthAccount{
+					{AccountID: usd.ID, Currency: util.USD, Balance: 10000, ConvertedBalance: 9240, Rate: "0.923958237"},
+					{AccountID: eur.ID, Currency: util.EUR, Balance: 5000, ConvertedBalance: 5000, Rate: "1"},
+				}, rsp.Accounts)
+			},
+		},
+		{
+			name:  "Currency",
+			query: "?currency=USD",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.Account{usd}, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp netWorthResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, util.USD, rsp.Currency)
+				require.Equal(t, usd.Balance, rsp.NetWorth)
+				require.Nil(t, rsp.RatesFetchedAt)
+			},
+		},
+		{
+			name: "NoDefaultCurrency",
+			buildStubs: func(store *mockdb.MockStore) {
+				noPreferences := user
+				noPreferences.Preferences = nil
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(noPreferences, nil)
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "StaleRates",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.Account{usd, eur}, nil)
+				store.EXPECT().
+					ListExchangeRates(gomock.Any()).
+					Times(1).
+					Return(randomExchangeRates(fetchedAt.Add(-fx.DefaultMaxAge-time.Hour)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidCurrency",
+			query: "?currency=usd",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAllAccounts(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/users/me/net-worth"+tc.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
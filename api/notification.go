@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type notificationResponse struct {
+	ID        int64      `json:"id"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newNotificationResponse(n db.Notification) notificationResponse {
+	rsp := notificationResponse{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		Title:     n.Title,
+		Body:      n.Body,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.ReadAt.Valid {
+		rsp.ReadAt = &n.ReadAt.Time
+	}
+	return rsp
+}
+
+type listNotificationsRequest struct {
+	PageID     int32 `form:"page_id" binding:"required,min=1"`
+	PageSize   int32 `form:"page_size" binding:"required,min=5,max=50"`
+	UnreadOnly bool  `form:"unread_only"`
+}
+
+type listNotificationsResponse struct {
+	Notifications []notificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+}
+
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var req listNotificationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		Username:   payload.Username,
+		UnreadOnly: req.UnreadOnly,
+		Limit:      req.PageSize,
+		Offset:     (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	unreadCount, err := server.store.CountUnreadNotifications(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := listNotificationsResponse{
+		Notifications: make([]notificationResponse, 0, len(notifications)),
+		UnreadCount:   unreadCount,
+	}
+	for _, n := range notifications {
+		rsp.Notifications = append(rsp.Notifications, newNotificationResponse(n))
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+type readNotificationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) readNotification(ctx *gin.Context) {
+	var req readNotificationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	// the notifications of other users are not found, so that their ids can't be probed
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	n, err := server.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:       req.ID,
+		Username: payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newNotificationResponse(n))
+}
+
+func (server *Server) readAllNotifications(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := server.store.MarkAllNotificationsRead(ctx, payload.Username); err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+type notificationPreferencesResponse struct {
+	EmailEnabled bool   `json:"email_enabled"`
+	SmsEnabled   bool   `json:"sms_enabled"`
+	PushEnabled  bool   `json:"push_enabled"`
+	PhoneNumber  string `json:"phone_number"`
+	PushToken    string `json:"push_token"`
+}
+
+func newNotificationPreferencesResponse(preferences db.NotificationPreference) notificationPreferencesResponse {
+	return notificationPreferencesResponse{
+		EmailEnabled: preferences.EmailEnabled,
+		SmsEnabled:   preferences.SmsEnabled,
+		PushEnabled:  preferences.PushEnabled,
+		PhoneNumber:  preferences.PhoneNumber,
+		PushToken:    preferences.PushToken,
+	}
+}
+
+func (server *Server) getNotificationPreferences(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	preferences, err := server.store.GetNotificationPreferences(ctx, payload.Username)
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		preferences = notification.DefaultPreferences(payload.Username)
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newNotificationPreferencesResponse(preferences))
+}
+
+type updateNotificationPreferencesRequest struct {
+	EmailEnabled bool   `json:"email_enabled"`
+	SmsEnabled   bool   `json:"sms_enabled"`
+	PushEnabled  bool   `json:"push_enabled"`
+	PhoneNumber  string `json:"phone_number" binding:"omitempty,e164"`
+	PushToken    string `json:"push_token" binding:"max=4096"`
+}
+
+func (server *Server) updateNotificationPreferences(ctx *gin.Context) {
+	var req updateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if req.SmsEnabled && req.PhoneNumber == "" {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, errors.New("sms notifications need a phone number")))
+		return
+	}
+	if req.PushEnabled && req.PushToken == "" {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, errors.New("push notifications need a push token")))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	preferences, err := server.store.UpsertNotificationPreferences(ctx, db.UpsertNotificationPreferencesParams{
+		Username:     payload.Username,
+		EmailEnabled: req.EmailEnabled,
+		SmsEnabled:   req.SmsEnabled,
+		PushEnabled:  req.PushEnabled,
+		PhoneNumber:  req.PhoneNumber,
+		PushToken:    req.PushToken,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newNotificationPreferencesResponse(preferences))
+}
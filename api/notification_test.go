@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomNotification(username string) db.Notification {
+	return db.Notification{
+		ID:        util.RandomInt(1, 1000),
+		Username:  username,
+		Kind:      notification.KindNewLogin,
+		Title:     "New login to your account",
+		Body:      util.RandomString(20),
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+}
+
+func TestNotificationsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	unread := randomNotification(user.Username)
+	read := randomNotification(user.Username)
+	read.ReadAt = pgtype.Timestamptz{Time: time.Now().Truncate(time.Second), Valid: true}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/notifications?page_id=2&page_size=5&unread_only=true",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{
+						Username:   user.Username,
+						UnreadOnly: true,
+						Limit:      5,
+						Offset:     5,
+					})).
+					Times(1).
+					Return([]db.Notification{unread}, nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(6), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp listNotificationsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(6), rsp.UnreadCount)
+				require.Len(t, rsp.Notifications, 1)
+				require.Equal(t, unread.ID, rsp.Notifications[0].ID)
+				require.False(t, rsp.Notifications[0].Read)
+				require.Nil(t, rsp.Notifications[0].ReadAt)
+			},
+		},
+		{
+			name:   "ListInvalidPageSize",
+			method: http.MethodGet,
+			url:    "/notifications?page_id=1&page_size=500",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Read",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/notifications/%d/read", read.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: read.ID, Username: user.Username})).
+					Times(1).
+					Return(read, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp notificationResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.True(t, rsp.Read)
+				require.NotNil(t, rsp.ReadAt)
+			},
+		},
+		{
+			name:   "ReadNotFound",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/notifications/%d/read", read.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "ReadAll",
+			method: http.MethodPost,
+			url:    "/notifications/read_all",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:   "GetDefaultPreferences",
+			method: http.MethodGet,
+			url:    "/notifications/preferences",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp notificationPreferencesResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, notificationPreferencesResponse{EmailEnabled: true}, rsp)
+			},
+		},
+		{
+			name:   "UpdatePreferences",
+			method: http.MethodPut,
+			url:    "/notifications/preferences",
+			body: gin.H{
+				"email_enabled": false,
+				"sms_enabled":   true,
+				"phone_number":  "+14155552671",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertNotificationPreferencesParams{
+					Username:    user.Username,
+					SmsEnabled:  true,
+					PhoneNumber: "+14155552671",
+				}
+				store.EXPECT().
+					UpsertNotificationPreferences(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.NotificationPreference{Username: user.Username, SmsEnabled: true, PhoneNumber: "+14155552671"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp notificationPreferencesResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.True(t, rsp.SmsEnabled)
+				require.False(t, rsp.EmailEnabled)
+			},
+		},
+		{
+			name:   "UpdatePreferencesSMSWithoutPhone",
+			method: http.MethodPut,
+			url:    "/notifications/preferences",
+			body:   gin.H{"sms_enabled": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "UpdatePreferencesInvalidPhone",
+			method: http.MethodPut,
+			url:    "/notifications/preferences",
+			body:   gin.H{"sms_enabled": true, "phone_number": "12345"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			request, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/openapi"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/backendmaster/simple_bank/receipt"
+	"github.com/backendmaster/simple_bank/version"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	openAPIRoute = "/openapi.json"
+	swaggerRoute = "/swagger"
+)
+
+// systemRouteDocs documents the routes of setupRouter outside of the versions of the api.
+var systemRouteDocs = []openapi.Route{
+	{Method: http.MethodGet, Path: "/metrics", Tag: "system", Summary: "Prometheus metrics", ContentType: "text/plain", Response: ""},
+	{Method: http.MethodGet, Path: "/healthz", Tag: "system", Summary: "Liveness probe"},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "system", Summary: "Readiness probe, checks the database and its migrations"},
+	{Method: http.MethodGet, Path: "/version", Tag: "system", Summary: "Build info of the server", Response: version.Info{}, Raw: true},
+}
+
+// routeDocs documents every route of addRoutes, without its version, TestOpenAPIRoutes fails
+// when a route is added without its doc.
+var routeDocs = []openapi.Route{
+	{Method: http.MethodPost, Path: "/users", Tag: "users", Summary: "Sign up", Body: createUserRequest{}, Response: userResponse{}},
+	{Method: http.MethodPost, Path: "/users/login", Tag: "users", Summary: "Log in; a user who reported a login answers 403 until they reset their password. The refresh token is bound to the device of device_fingerprint, or of the user agent without one", Body: loginUserRequest{}, Response: loginUserResponse{}},
+	{Method: http.MethodPost, Path: "/tokens/renew_access", Tag: "users", Summary: "Renew the access token with a refresh token; a token bound to an untrusted device, or to no device yet, answers 403 until renewed with the code emailed to trust it", Body: renewAccessTokenRequest{}, Response: renewAccessTokenResponse{}},
+	{Method: http.MethodPost, Path: "/login_alerts/:id/report", Tag: "users", Summary: "Report a suspicious login with the code of the link of its notification, blocking every session of the user and emailing them a code to reset their password", URI: loginAlertURI{}, Body: reportLoginRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/login_alerts/:id/reset_code", Tag: "users", Summary: "Email a new code to reset the password after a reported login", URI: loginAlertURI{}, Body: reportLoginRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/login_alerts/:id/reset_password", Tag: "users", Summary: "Reset the password with the code emailed once a login was reported, which lets the user log in again", URI: loginAlertURI{}, Body: resetPasswordRequest{}, Response: userResponse{}},
+	{Method: http.MethodGet, Path: "/receipts/verify", Tag: "transfers", Summary: "Check the verification code of a transfer receipt, returning the receipt when valid", Query: verifyReceiptRequest{}, Response: verifyReceiptResponse{}},
+	{Method: http.MethodPost, Path: "/card_network/authorizations", Tag: "cards", Summary: "Authorize a card payment for the card network, signed with the network key; declined payments answer 200 too", Body: cardAuthorizationRequest{}, Response: cardAuthorizationResponse{}},
+	{Method: http.MethodGet, Path: "/ws", Tag: "events", Summary: "Activity of the accounts over a websocket, authenticated by its first message", Status: http.StatusSwitchingProtocols},
+
+	{Method: http.MethodGet, Path: "/legal_documents", Tag: "users", Summary: "List the latest version of the terms of service and of the privacy policy", Response: []db.LegalDocument{}},
+	{Method: http.MethodGet, Path: "/users/me/consents", Tag: "users", Summary: "List the legal documents the caller has to accept and the versions they accepted", Auth: true, Response: consentsResponse{}},
+	{Method: http.MethodPost, Path: "/users/me/consents", Tag: "users", Summary: "Accept the latest version of the terms of service or of the privacy policy", Auth: true, Body: acceptConsentRequest{}, Response: db.UserConsent{}},
+	{Method: http.MethodPost, Path: "/tokens/step_up", Tag: "users", Summary: "Authenticate again with the password, answering an access token authenticated now", Auth: true, Body: stepUpRequest{}, Response: stepUpResponse{}},
+	{Method: http.MethodPost, Path: "/tokens/step_up/challenges", Tag: "users", Summary: "Check the password and email a one-time code for a multi-factor step-up, which the sensitive operations ask for", Auth: true, Body: stepUpRequest{}, Status: http.StatusAccepted, Response: stepUpChallengeResponse{}},
+	{Method: http.MethodPost, Path: "/tokens/step_up/challenges/:id", Tag: "users", Summary: "Complete a multi-factor step-up with the code emailed, answering an access token authenticated now", Auth: true, URI: stepUpChallengeURI{}, Body: completeStepUpChallengeRequest{}, Response: stepUpResponse{}},
+	{Method: http.MethodGet, Path: "/users/me/devices", Tag: "users", Summary: "List the devices the caller logged in from that aren't revoked", Auth: true, Response: []deviceResponse{}},
+	{Method: http.MethodPut, Path: "/users/me/devices/:id/name", Tag: "users", Summary: "Name a device of the caller", Auth: true, URI: deviceURI{}, Body: renameDeviceRequest{}, Response: deviceResponse{}},
+	{Method: http.MethodDelete, Path: "/users/me/devices/:id", Tag: "users", Summary: "Revoke a device of the caller, blocking the refresh tokens bound to it", Auth: true, URI: deviceURI{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/users/me/preferences", Tag: "users", Summary: "Get the preferences of the caller", Auth: true, Response: preferences.Preferences{}},
+	{Method: http.MethodPut, Path: "/users/me/preferences", Tag: "users", Summary: "Replace the preferences of the caller: locale, timezone, default currency and muted notifications", Auth: true, Body: updatePreferencesRequest{}, Response: preferences.Preferences{}},
+	{Method: http.MethodGet, Path: "/users/me/net-worth", Tag: "users", Summary: "Sum the balances of the accounts of the caller, converted into their default currency or currency at the latest exchange rates", Auth: true, Query: netWorthRequest{}, Response: netWorthResponse{}},
+	{Method: http.MethodPost, Path: "/accounts", Tag: "accounts", Summary: "Open an account, in the default currency of the caller unless told", Auth: true, Body: createAccountRequest{}, Response: db.Account{}},
+	{Method: http.MethodGet, Path: "/accounts/:id", Tag: "accounts", Summary: "Get an account, with its balance converted into display_currency at the latest exchange rates", Auth: true, URI: getAccountRequest{}, Query: getAccountQuery{}, Response: accountResponse{}},
+	{Method: http.MethodGet, Path: "/accounts", Tag: "accounts", Summary: "List the accounts, by cursor, or by page_id for a plain array", Auth: true, Query: listAccountRequest{}, Response: listAccountResponse{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/entries", Tag: "accounts", Summary: "List the entries of an account", Auth: true, URI: listAccountHistoryURI{}, Query: listAccountHistoryRequest{}, Response: listEntriesResponse{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/entries/export", Tag: "accounts", Summary: "Export the entries of an account as OFX, QIF or CSV; a large export answers 202 with the export the worker builds", Auth: true, URI: exportEntriesURI{}, Query: exportEntriesRequest{}, ContentType: "application/octet-stream", Response: ""},
+	{Method: http.MethodGet, Path: "/accounts/:id/transfers", Tag: "accounts", Summary: "List the transfers of an account", Auth: true, URI: listAccountHistoryURI{}, Query: listAccountHistoryRequest{}, Response: listTransfersResponse{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/external_transfers", Tag: "accounts", Summary: "List the transfers from an account to other banks", Auth: true, URI: listExternalTransfersURI{}, Query: listExternalTransfersRequest{}, Response: []db.ExternalTransfer{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/cards", Tag: "cards", Summary: "List the virtual cards of an account", Auth: true, URI: listCardsURI{}, Response: []cardResponse{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/authorization_holds", Tag: "accounts", Summary: "List the authorization holds on an account and those in its favor", Auth: true, URI: listAuthorizationHoldsURI{}, Query: listAuthorizationHoldsRequest{}, Response: []db.AuthorizationHold{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/payment-requests", Tag: "accounts", Summary: "List the payment requests to an account", Auth: true, URI: listPaymentRequestsURI{}, Query: listPaymentRequestsRequest{}, Response: []db.PaymentRequest{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/loans", Tag: "accounts", Summary: "List the loans of an account", Auth: true, URI: listLoansURI{}, Query: listLoansRequest{}, Response: []db.Loan{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/rewards", Tag: "accounts", Summary: "Get the cashback points of an account with their accruals and redemptions", Auth: true, URI: accountRewardsURI{}, Query: getRewardsRequest{}, Response: rewardsResponse{}},
+	{Method: http.MethodPost, Path: "/accounts/:id/rewards/redeem", Tag: "accounts", Summary: "Credit cashback points of an account to it, a unit of its currency each", Auth: true, URI: accountRewardsURI{}, Body: redeemRewardsRequest{}, Response: db.RedeemRewardsTxResult{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/analytics", Tag: "accounts", Summary: "Sum the money out of and into an account over a day, week, month or year, by category and counterparty", Auth: true, URI: accountAnalyticsURI{}, Query: accountAnalyticsRequest{}, Response: accountAnalyticsResponse{}},
+	{Method: http.MethodGet, Path: "/accounts/:id/alerts", Tag: "accounts", Summary: "Get the alerts of an account", Auth: true, URI: accountAlertURI{}, Response: accountAlertResponse{}},
+	{Method: http.MethodPut, Path: "/accounts/:id/alerts", Tag: "accounts", Summary: "Set the alerts of an account", Auth: true, URI: accountAlertURI{}, Body: updateAccountAlertRequest{}, Response: accountAlertResponse{}},
+	{Method: http.MethodGet, Path: "/account_numbers/:number", Tag: "accounts", Summary: "Look up the account of an account number before transferring to it", Auth: true, URI: accountNumberURI{}, Response: accountNumberResponse{}},
+	{Method: http.MethodPut, Path: "/entries/:id/category", Tag: "accounts", Summary: "Set the category and the tags of an entry, replacing the category a rule set", Auth: true, URI: entryCategoryURI{}, Body: setEntryCategoryRequest{}, Response: db.EntryCategory{}},
+	{Method: http.MethodGet, Path: "/category_rules", Tag: "accounts", Summary: "List the rules categorizing the new entries", Auth: true, Response: []db.CategoryRule{}},
+	{Method: http.MethodPost, Path: "/category_rules", Tag: "accounts", Summary: "Add a rule categorizing the new entries by counterparty, direction or memo", Auth: true, Body: createCategoryRuleRequest{}, Response: db.CategoryRule{}},
+	{Method: http.MethodDelete, Path: "/category_rules/:id", Tag: "accounts", Summary: "Delete a category rule", Auth: true, URI: categoryRuleURI{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/exports/:id", Tag: "accounts", Summary: "Get an export of entries, with its download url once ready", Auth: true, URI: entryExportURI{}, Response: entryExportResponse{}},
+	{Method: http.MethodGet, Path: "/exports/:id/download", Tag: "accounts", Summary: "Download the file of a ready export of entries", Auth: true, URI: entryExportURI{}, ContentType: "application/octet-stream", Response: ""},
+	{Method: http.MethodPost, Path: "/transfers", Tag: "transfers", Summary: "Transfer money between two accounts, to an account id or an account number, converted into to_currency at the latest exchange rates, answering 202 with a transfer review when the fraud rules flag it and 401 when a large amount asks for a recent multi-factor step-up", Auth: true, Body: transferRequest{}, Response: transferResponse{}},
+	{Method: http.MethodGet, Path: "/transfers/:id/receipt", Tag: "transfers", Summary: "Get the receipt of a transfer from or to an account of the caller, as json or pdf, with its verification code", Auth: true, URI: transferReceiptURI{}, Query: transferReceiptRequest{}, Response: receipt.Receipt{}},
+	{Method: http.MethodPost, Path: "/transfers/:id/refunds", Tag: "transfers", Summary: "Pay back all or part of a transfer to an account of the caller, up to what is left to refund", Auth: true, URI: transferRefundsURI{}, Body: createRefundRequest{}, Response: db.RefundTransferTxResult{}},
+	{Method: http.MethodGet, Path: "/transfers/:id/refunds", Tag: "transfers", Summary: "List the refunds of a transfer from or to an account of the caller, with the amount left to refund", Auth: true, URI: transferRefundsURI{}, Response: listRefundsResponse{}},
+	{Method: http.MethodPost, Path: "/external_transfers", Tag: "transfers", Summary: "Transfer money to an account at another bank, through ACH or SEPA, held until the transfer settles; asks for a recent multi-factor step-up", Auth: true, Body: createExternalTransferRequest{}, Status: http.StatusAccepted, Response: db.CreateExternalTransferTxResult{}},
+	{Method: http.MethodGet, Path: "/external_transfers/:id", Tag: "transfers", Summary: "Get a transfer to another bank and its settlement status", Auth: true, URI: externalTransferURI{}, Response: db.ExternalTransfer{}},
+	{Method: http.MethodPost, Path: "/cards", Tag: "cards", Summary: "Issue a virtual card on an account, its number and CVV only shown in the response", Auth: true, Body: issueCardRequest{}, Response: issueCardResponse{}},
+	{Method: http.MethodGet, Path: "/cards/:id", Tag: "cards", Summary: "Get a virtual card", Auth: true, URI: cardURI{}, Response: cardResponse{}},
+	{Method: http.MethodPut, Path: "/cards/:id/frozen", Tag: "cards", Summary: "Freeze or unfreeze a virtual card", Auth: true, URI: cardURI{}, Body: setCardFrozenRequest{}, Response: cardResponse{}},
+	{Method: http.MethodPut, Path: "/cards/:id/limits", Tag: "cards", Summary: "Set the per payment and daily limits of a virtual card, 0 for no limit", Auth: true, URI: cardURI{}, Body: setCardLimitsRequest{}, Response: cardResponse{}},
+	{Method: http.MethodGet, Path: "/cards/:id/authorizations", Tag: "cards", Summary: "List the payment authorizations of a virtual card, the declined ones included", Auth: true, URI: cardURI{}, Query: listCardAuthorizationsRequest{}, Response: []db.CardAuthorization{}},
+	{Method: http.MethodPost, Path: "/authorization_holds", Tag: "transfers", Summary: "Hold an amount of an account for a payee, out of its available balance until captured, released or expired", Auth: true, Body: placeAuthorizationHoldRequest{}, Response: db.PlaceAuthorizationHoldTxResult{}},
+	{Method: http.MethodGet, Path: "/authorization_holds/:id", Tag: "transfers", Summary: "Get an authorization hold, for its payer or its payee", Auth: true, URI: authorizationHoldURI{}, Response: db.AuthorizationHold{}},
+	{Method: http.MethodPost, Path: "/authorization_holds/:id/capture", Tag: "transfers", Summary: "Capture all or part of an active authorization hold, for its payee", Auth: true, URI: authorizationHoldURI{}, Body: captureAuthorizationHoldRequest{}, Response: db.CaptureAuthorizationHoldTxResult{}},
+	{Method: http.MethodPost, Path: "/authorization_holds/:id/release", Tag: "transfers", Summary: "Release an active authorization hold without capturing it, for its payee", Auth: true, URI: authorizationHoldURI{}, Response: db.AuthorizationHold{}},
+	{Method: http.MethodPost, Path: "/payment-requests", Tag: "transfers", Summary: "Ask to be paid an amount to an account, with the signed payload of its QR code", Auth: true, Body: createPaymentRequestRequest{}, Response: paymentRequestResponse{}},
+	{Method: http.MethodGet, Path: "/payment-requests/:id", Tag: "transfers", Summary: "Get a payment request to an account of the caller", Auth: true, URI: paymentRequestURI{}, Response: paymentRequestResponse{}},
+	{Method: http.MethodGet, Path: "/payment-requests/:id/qr", Tag: "transfers", Summary: "Get the QR code of a payment request as a PNG image", Auth: true, URI: paymentRequestURI{}, Query: paymentRequestQRRequest{}, ContentType: "image/png", Response: ""},
+	{Method: http.MethodPost, Path: "/payment-requests/:id/pay", Tag: "transfers", Summary: "Pay a scanned payment request, with the signature of its QR code", Auth: true, URI: paymentRequestURI{}, Body: payPaymentRequestRequest{}, Response: db.PayPaymentRequestTxResult{}},
+	{Method: http.MethodGet, Path: "/currencies", Tag: "accounts", Summary: "List the currencies accounts can be opened in, with the decimal places their amounts are counted in", Auth: true, Response: []currencyResponse{}},
+	{Method: http.MethodGet, Path: "/exchange_rates", Tag: "transfers", Summary: "List the exchange rates transfers between currencies are converted at, with when they were published and fetched", Auth: true, Response: []exchangeRateResponse{}},
+	{Method: http.MethodGet, Path: "/loan_offers", Tag: "loans", Summary: "List the loan offers the users can borrow under", Auth: true, Response: []db.LoanOffer{}},
+	{Method: http.MethodPost, Path: "/loans", Tag: "loans", Summary: "Borrow under a loan offer to an account of the caller, with its repayment schedule", Auth: true, Body: createLoanRequest{}, Response: db.DisburseLoanTxResult{}},
+	{Method: http.MethodGet, Path: "/loans/:id", Tag: "loans", Summary: "Get a loan of an account of the caller with its installments and what is outstanding and overdue", Auth: true, URI: loanURI{}, Response: loanResponse{}},
+	{Method: http.MethodGet, Path: "/referrals", Tag: "referrals", Summary: "List the users the caller referred and whether they qualified for the bonus", Auth: true, Query: listReferralsRequest{}, Response: []db.Referral{}},
+	{Method: http.MethodGet, Path: "/referrals/code", Tag: "referrals", Summary: "Get the referral code of the caller to share, created the first time", Auth: true, Response: db.ReferralCode{}},
+	{Method: http.MethodPost, Path: "/disputes", Tag: "transfers", Summary: "Dispute a transfer sent or received, holding its amount", Auth: true, Body: openDisputeRequest{}, Response: db.Dispute{}},
+	{Method: http.MethodGet, Path: "/disputes/:id", Tag: "transfers", Summary: "Get a dispute", Auth: true, URI: disputeURI{}, Response: db.Dispute{}},
+	{Method: http.MethodGet, Path: "/transfer_reviews/:id", Tag: "transfers", Summary: "Get the review of a transfer the fraud rules flagged", Auth: true, URI: transferReviewURI{}, Response: transferReviewResponse{}},
+	{Method: http.MethodPost, Path: "/transfer_reviews/:id/confirm", Tag: "transfers", Summary: "Confirm a flagged transfer with the one-time code emailed for it", Auth: true, URI: transferReviewURI{}, Body: confirmTransferRequest{}, Response: closeTransferReviewResponse{}},
+	{Method: http.MethodGet, Path: "/events", Tag: "events", Summary: "Server-sent events of the new entries of accounts", Auth: true, Query: streamEventsRequest{}, ContentType: "text/event-stream", Response: ""},
+
+	{Method: http.MethodGet, Path: "/notifications", Tag: "notifications", Summary: "List the notifications", Auth: true, Query: listNotificationsRequest{}, Response: listNotificationsResponse{}},
+	{Method: http.MethodPost, Path: "/notifications/:id/read", Tag: "notifications", Summary: "Mark a notification as read", Auth: true, URI: readNotificationRequest{}, Response: notificationResponse{}},
+	{Method: http.MethodPost, Path: "/notifications/read_all", Tag: "notifications", Summary: "Mark all the notifications as read", Auth: true, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/notifications/preferences", Tag: "notifications", Summary: "Get the notification preferences", Auth: true, Response: notificationPreferencesResponse{}},
+	{Method: http.MethodPut, Path: "/notifications/preferences", Tag: "notifications", Summary: "Set the notification preferences", Auth: true, Body: updateNotificationPreferencesRequest{}, Response: notificationPreferencesResponse{}},
+
+	{Method: http.MethodPut, Path: readOnlyRoute, Tag: "admin", Summary: "Switch the read-only maintenance mode", Auth: true, Body: setReadOnlyRequest{}, Response: readOnlyResponse{}},
+	{Method: http.MethodPut, Path: transfersBlockedRoute, Tag: "admin", Summary: "Switch the kill switch refusing the new transfers", Auth: true, Body: setTransfersBlockedRequest{}, Response: transfersBlockedResponse{}},
+	{Method: http.MethodGet, Path: "/admin/dead_tasks", Tag: "admin", Summary: "List the dead tasks of a queue", Auth: true, Query: listDeadTasksRequest{}, Response: []deadTaskResponse{}},
+	{Method: http.MethodGet, Path: "/admin/dead_tasks/:queue/:id", Tag: "admin", Summary: "Get a dead task", Auth: true, URI: deadTaskRequest{}, Response: deadTaskResponse{}},
+	{Method: http.MethodPost, Path: "/admin/dead_tasks/:queue/:id/requeue", Tag: "admin", Summary: "Requeue a dead task", Auth: true, URI: deadTaskRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/admin/dead_tasks/:queue/:id", Tag: "admin", Summary: "Discard a dead task", Auth: true, URI: deadTaskRequest{}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/admin/ledger", Tag: "admin", Summary: "Verify the consistency of the ledger", Auth: true, Response: ledgerReportResponse{}},
+	{Method: http.MethodGet, Path: "/admin/users/:username", Tag: "admin", Summary: "Look up a user", Auth: true, URI: adminGetUserRequest{}, Query: includeDeletedRequest{}, Response: adminUserResponse{}},
+	{Method: http.MethodGet, Path: "/admin/users/:username/accounts", Tag: "admin", Summary: "List the accounts of a user", Auth: true, URI: adminGetUserRequest{}, Query: adminListAccountsRequest{}, Response: []db.Account{}},
+	{Method: http.MethodGet, Path: "/admin/accounts/:id", Tag: "admin", Summary: "Look up an account", Auth: true, URI: getAccountRequest{}, Query: includeDeletedRequest{}, Response: db.Account{}},
+	{Method: http.MethodPost, Path: "/admin/accounts/:id/adjustments", Tag: "admin", Summary: "Post an entry adjusting the balance of an account, with a reason code", Auth: true, URI: getAccountRequest{}, Body: adjustBalanceRequest{}, Response: db.AdjustBalanceTxResult{}},
+	{Method: http.MethodGet, Path: "/admin/search", Tag: "admin", Summary: "Search the users and the transfers", Auth: true, Query: searchRequest{}, Response: searchResponse{}},
+	{Method: http.MethodGet, Path: "/admin/audit_logs", Tag: "admin", Summary: "List the audit logs of the writes, by actor, action and time", Auth: true, Query: listAuditLogsRequest{}, Response: listAuditLogsResponse{}},
+	{Method: http.MethodGet, Path: "/admin/audit_logs/verify", Tag: "admin", Summary: "Verify the hash chain of the audit logs", Auth: true, Response: auditChainReportResponse{}},
+	{Method: http.MethodPost, Path: "/admin/impersonations", Tag: "admin", Summary: "Mint a short-lived token acting as a user", Auth: true, Body: createImpersonationRequest{}, Response: createImpersonationResponse{}},
+	{Method: http.MethodPost, Path: "/admin/impersonations/:id/revoke", Tag: "admin", Summary: "Revoke the token of an impersonation", Auth: true, URI: impersonationURI{}, Body: revokeImpersonationRequest{}, Response: db.Impersonation{}},
+	{Method: http.MethodGet, Path: "/admin/disputes", Tag: "admin", Summary: "List the disputes, by status", Auth: true, Query: listDisputesRequest{}, Response: []db.Dispute{}},
+	{Method: http.MethodPost, Path: "/admin/disputes/:id/status", Tag: "admin", Summary: "Investigate a dispute, or close it as resolved or refunded", Auth: true, URI: disputeURI{}, Body: setDisputeStatusRequest{}, Response: db.SetDisputeStatusTxResult{}},
+	{Method: http.MethodGet, Path: "/admin/transfer_reviews", Tag: "admin", Summary: "List the transfer reviews, the pending held ones being the review queue", Auth: true, Query: listTransferReviewsRequest{}, Response: []transferReviewResponse{}},
+	{Method: http.MethodPost, Path: "/admin/transfer_reviews/:id/status", Tag: "admin", Summary: "Approve a held transfer, which runs it, or reject it", Auth: true, URI: transferReviewURI{}, Body: reviewTransferRequest{}, Response: closeTransferReviewResponse{}},
+	{Method: http.MethodGet, Path: "/admin/denylist", Tag: "admin", Summary: "List the denylist the users and transfers are screened against", Auth: true, Query: listDenylistRequest{}, Response: []db.DenylistEntry{}},
+	{Method: http.MethodPost, Path: "/admin/denylist", Tag: "admin", Summary: "Add a name, an email or an account to the denylist, holding the users it matches", Auth: true, Body: addDenylistEntryRequest{}, Response: db.AddDenylistEntryTxResult{}},
+	{Method: http.MethodPost, Path: "/admin/denylist/:id/remove", Tag: "admin", Summary: "Remove an entry from the denylist", Auth: true, URI: denylistEntryURI{}, Body: removeDenylistEntryRequest{}, Response: db.DenylistEntry{}},
+	{Method: http.MethodGet, Path: "/admin/screening_holds", Tag: "admin", Summary: "List the holds of the users who matched the denylist", Auth: true, Query: listScreeningHoldsRequest{}, Response: []db.ScreeningHold{}},
+	{Method: http.MethodPost, Path: "/admin/screening_holds/:id/status", Tag: "admin", Summary: "Clear a screening hold, e.g. for a namesake, or confirm the match", Auth: true, URI: screeningHoldURI{}, Body: setScreeningHoldRequest{}, Response: db.ScreeningHold{}},
+	{Method: http.MethodPost, Path: "/admin/user_imports", Tag: "admin", Summary: "Import the users of a legacy system from a csv or ndjson file, answering 202 with the import the worker runs", Auth: true, Query: createUserImportRequest{}, Body: "", BodyContentType: "application/octet-stream", Status: http.StatusAccepted, Response: db.UserImport{}},
+	{Method: http.MethodGet, Path: "/admin/user_imports/:id", Tag: "admin", Summary: "Get a user import with the counts of its imported and failed rows", Auth: true, URI: userImportURI{}, Response: db.UserImport{}},
+	{Method: http.MethodGet, Path: "/admin/user_imports/:id/rows", Tag: "admin", Summary: "Page through the report of a user import, the failed rows with their error", Auth: true, URI: userImportURI{}, Query: listUserImportRowsRequest{}, Response: listUserImportRowsResponse{}},
+	{Method: http.MethodPost, Path: "/admin/loan_offers", Tag: "admin", Summary: "Offer a loan product at a fixed rate over a term", Auth: true, Body: createLoanOfferRequest{}, Response: db.LoanOffer{}},
+	{Method: http.MethodPost, Path: "/admin/loan_offers/:id/active", Tag: "admin", Summary: "Retire a loan offer or offer it again", Auth: true, URI: loanOfferURI{}, Body: setLoanOfferActiveRequest{}, Response: db.LoanOffer{}},
+	{Method: http.MethodGet, Path: "/admin/loans/delinquent", Tag: "admin", Summary: "List the delinquent loans with what they have overdue", Auth: true, Query: listDelinquentLoansRequest{}, Response: []db.ListDelinquentLoansRow{}},
+	{Method: http.MethodPost, Path: "/admin/legal_documents", Tag: "admin", Summary: "Publish a version of the terms of service or of the privacy policy, which the users must accept before their next sensitive operation", Auth: true, Body: publishLegalDocumentRequest{}, Response: db.LegalDocument{}},
+	{Method: http.MethodGet, Path: "/admin/tenants", Tag: "admin", Summary: "List the tenants sharing the bank, with their terms", Auth: true, Response: []db.Tenant{}},
+	{Method: http.MethodPost, Path: "/admin/tenants", Tag: "admin", Summary: "Open the bank to a tenant, with its currencies, transfer cap and email branding", Auth: true, Body: createTenantRequest{}, Response: db.Tenant{}},
+	{Method: http.MethodPut, Path: "/admin/tenants/:id", Tag: "admin", Summary: "Change the terms of a tenant", Auth: true, URI: tenantURI{}, Body: updateTenantRequest{}, Response: db.Tenant{}},
+	{Method: http.MethodGet, Path: "/admin/currencies", Tag: "admin", Summary: "List the currencies of the registry, the disabled ones included", Auth: true, Response: []db.Currency{}},
+	{Method: http.MethodPut, Path: "/admin/currencies/:code", Tag: "admin", Summary: "Enable a currency of the registry, or disable one no tenant offers", Auth: true, URI: currencyURI{}, Body: setCurrencyEnabledRequest{}, Response: db.Currency{}},
+
+	{Method: http.MethodGet, Path: graphQLRoute, Tag: "graphql", Summary: "Run a GraphQL query, served in read-only mode too", Auth: true, Query: graphQLQuery{}, Response: graphql.Response{}, Raw: true},
+	{Method: http.MethodPost, Path: graphQLRoute, Tag: "graphql", Summary: "Run a GraphQL query or mutation, see graph/schema.graphqls", Auth: true, Body: graphQLRequest{}, Response: graphql.Response{}, Raw: true},
+}
+
+// bindingSchemas are the constraints of the custom binding tags, see validator.go.
+var bindingSchemas = map[string]openapi.Schema{
+	// the admins enable currencies at runtime, which GET /currencies lists
+	"currency":   {Pattern: "^[A-Z]{3}$"},
+	"username":   {Pattern: "^[a-z0-9_]+$", MinLength: intPtr(3), MaxLength: intPtr(10)},
+	"full_name":  {Pattern: `^[a-zA-Z\s]+$`, MinLength: intPtr(3), MaxLength: intPtr(20)},
+	"password":   {Format: "password", MinLength: intPtr(6), MaxLength: intPtr(10)},
+	"user_email": {Format: "email", MinLength: intPtr(3), MaxLength: intPtr(200)},
+	"category":   {Pattern: categoryPattern},
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// documentedRoutes are the routes of the spec: the system ones and those of v1. The deprecated
+// routes without a version aren't documented.
+func documentedRoutes() []openapi.Route {
+	routes := append([]openapi.Route{}, systemRouteDocs...)
+	for _, route := range routeDocs {
+		route.Path = apiV1 + route.Path
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// openAPIDocument is the spec of the REST api.
+func openAPIDocument() openapi.Document {
+	info := openapi.Info{
+		Title:       "Simple Bank",
+		Description: "The REST api of simple bank, under /v1. Responses answer with {\"data\": ..., \"request_id\": ...}, errors with {\"errors\": [{\"message\": ...}]}.",
+		Version:     version.Version,
+	}
+	return openapi.Generate(info, documentedRoutes(), bindingSchemas)
+}
+
+// serveOpenAPI serves the spec, which is only generated once.
+func serveOpenAPI() gin.HandlerFunc {
+	spec, err := json.Marshal(openAPIDocument())
+	return func(ctx *gin.Context) {
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+			return
+		}
+		ctx.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>Simple Bank API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + openAPIRoute + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// serveSwagger serves the Swagger UI of the spec.
+func serveSwagger(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPIRoutes keeps routeDocs in sync with the routes of the router.
+func TestOpenAPIRoutes(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	documented := map[string]bool{}
+	for _, doc := range documentedRoutes() {
+		documented[doc.Method+" "+openapi.Path(doc.Path)] = true
+	}
+
+	routes := map[string]bool{}
+	for _, route := range server.router.Routes() {
+		if route.Path == openAPIRoute || route.Path == swaggerRoute {
+			continue
+		}
+		key := route.Method + " " + openapi.Path(route.Path)
+		// the deprecated routes without a version are those of v1
+		if unversioned := route.Method + " " + openapi.Path(apiV1+route.Path); !documented[key] && documented[unversioned] {
+			continue
+		}
+		routes[key] = true
+		require.True(t, documented[key], "%s isn't in routeDocs", key)
+	}
+	for key := range documented {
+		require.True(t, routes[key], "%s is in routeDocs but not in the router", key)
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, openAPIRoute, nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &doc))
+	require.Equal(t, openapi.Version, doc.OpenAPI)
+	require.Contains(t, doc.Components.SecuritySchemes, "bearerAuth")
+
+	getAccount := doc.Paths["/v1/accounts/{id}"]["get"]
+	require.NotNil(t, getAccount)
+	require.Equal(t, []map[string][]string{{"bearerAuth": {}}}, getAccount.Security)
+	require.Len(t, getAccount.Parameters, 3)
+	require.Equal(t, "id", getAccount.Parameters[0].Name)
+	require.Equal(t, "path", getAccount.Parameters[0].In)
+	require.Equal(t, "display_currency", getAccount.Parameters[1].Name)
+	require.Equal(t, "query", getAccount.Parameters[1].In)
+	require.Equal(t, envelope.FieldsParam, getAccount.Parameters[2].Name)
+	require.Equal(t, "#/components/schemas/AccountResponse", getAccount.Responses["200"].Content["application/json"].Schema.Properties["data"].Ref)
+
+	createUser := doc.Paths["/v1/users"]["post"]
+	require.NotNil(t, createUser)
+	require.Empty(t, createUser.Security)
+	require.Equal(t, "#/components/schemas/CreateUserRequest", createUser.RequestBody.Content["application/json"].Schema.Ref)
+	request2 := doc.Components.Schemas["CreateUserRequest"]
+	require.ElementsMatch(t, []string{"username", "password", "full_name", "email"}, request2.Required)
+	require.Equal(t, "^[a-z0-9_]+$", request2.Properties["username"].Pattern)
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	invalid := validator.New().Struct(struct {
+		Amount int64 `validate:"required"`
+	}{})
+	errorSchema := doc.Components.Schemas["Error"]
+	for _, cause := range []error{errors.New("error"), invalid} {
+		data, err := json.Marshal(errResponse(ctx, cause))
+		require.NoError(t, err)
+		var body map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &body))
+		for _, field := range errorSchema.Required {
+			require.Contains(t, body, field)
+		}
+		for field := range body {
+			require.Contains(t, errorSchema.Properties, field)
+		}
+		var errs []map[string]string
+		require.NoError(t, json.Unmarshal(body["errors"], &errs))
+		for _, e := range errs {
+			for field := range e {
+				require.Contains(t, errorSchema.Properties["errors"].Items.Properties, field)
+			}
+		}
+	}
+
+	transfer := doc.Components.Schemas["TransferRequest"]
+	require.Equal(t, "^[A-Z]{3}$", transfer.Properties["currency"].Pattern)
+	require.True(t, transfer.Properties["amount"].ExclusiveMinimum)
+}
+
+func TestServeSwagger(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, swaggerRoute, nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), openAPIRoute)
+}
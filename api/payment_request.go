@@ -0,0 +1,287 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/payrequest"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The payment request routes let users ask to be paid an amount, showing a QR code that another
+// user scans with the app of the bank to pay it.
+
+// defaultPaymentRequestExpiry is how long a payment request can be paid when its owner doesn't
+// tell.
+const defaultPaymentRequestExpiry = 24 * time.Hour
+
+// paymentRequestErrStatus maps the errors of the payment requests to a response status.
+func paymentRequestErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrPaymentRequestClosed), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+type paymentRequestResponse struct {
+	PaymentRequest db.PaymentRequest  `json:"payment_request"`
+	Payload        payrequest.Payload `json:"payload"`
+	// URI is what the QR code of the request holds.
+	URI string `json:"uri"`
+}
+
+func (server *Server) newPaymentRequestResponse(request db.PaymentRequest, account db.Account) paymentRequestResponse {
+	payload := server.payRequests.Sign(request, account.Number)
+	return paymentRequestResponse{
+		PaymentRequest: request,
+		Payload:        payload,
+		URI:            payload.URI(),
+	}
+}
+
+type createPaymentRequestRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+	Amount    int64 `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0,amount=Currency"`
+	// AmountDecimal is the amount in units of the currency, e.g. "12.34", instead of amount.
+	AmountDecimal string `json:"amount_decimal" binding:"omitempty,excluded_with=Amount,decimal_amount=Currency"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	Memo          string `json:"memo" binding:"max=140"`
+	// ExpiresInHours is how long the request can be paid, a day by default.
+	ExpiresInHours int32 `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// createPaymentRequest asks for an amount to be paid to an account of the caller, and returns the
+// signed payload its QR code holds.
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if !bindAmount(ctx, &req.Amount, req.AmountDecimal, req.Currency) {
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, req.AccountID)
+	if !ok {
+		return
+	}
+	if account.Currency != req.Currency {
+		err := fmt.Errorf("account %v mismatched: %v vs %v", account.ID, account.Currency, req.Currency)
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	expiry := defaultPaymentRequestExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	request, err := server.store.CreatePaymentRequest(ctx, db.CreatePaymentRequestParams{
+		AccountID: account.ID,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Memo:      req.Memo,
+		ExpiresAt: time.Now().Add(expiry),
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, server.newPaymentRequestResponse(request, account))
+}
+
+type paymentRequestURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// paymentRequestOfCaller returns the payment request of the request and the account it pays,
+// answering the request unless the caller owns the account.
+func (server *Server) paymentRequestOfCaller(ctx *gin.Context) (db.PaymentRequest, db.Account, bool) {
+	var uri paymentRequestURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return db.PaymentRequest{}, db.Account{}, false
+	}
+	request, err := server.store.GetPaymentRequest(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(paymentRequestErrStatus(err), errResponse(ctx, err))
+		return request, db.Account{}, false
+	}
+	account, ok := server.authorizeAccount(ctx, request.AccountID)
+	return request, account, ok
+}
+
+// getPaymentRequest returns a payment request to an account of the caller, and whether it was paid.
+func (server *Server) getPaymentRequest(ctx *gin.Context) {
+	request, account, ok := server.paymentRequestOfCaller(ctx)
+	if !ok {
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, server.newPaymentRequestResponse(request, account))
+}
+
+type paymentRequestQRRequest struct {
+	// Size is the width and the height of the image, in pixels.
+	Size int `form:"size" binding:"omitempty,min=128,max=1024"`
+}
+
+// getPaymentRequestQR returns the QR code of a payment request to an account of the caller, as a
+// PNG image, for the payer to scan.
+func (server *Server) getPaymentRequestQR(ctx *gin.Context) {
+	var req paymentRequestQRRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	request, account, ok := server.paymentRequestOfCaller(ctx)
+	if !ok {
+		return
+	}
+	if req.Size == 0 {
+		req.Size = 256
+	}
+
+	png, err := payrequest.QRCode(server.payRequests.Sign(request, account.Number), req.Size)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	ctx.Data(http.StatusOK, "image/png", png)
+}
+
+type listPaymentRequestsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listPaymentRequestsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listPaymentRequests lists the payment requests to an account of the caller, the newest first.
+func (server *Server) listPaymentRequests(ctx *gin.Context) {
+	var uri listPaymentRequestsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req listPaymentRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	requests, err := server.store.ListAccountPaymentRequests(ctx, db.ListAccountPaymentRequestsParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, requests)
+}
+
+type payPaymentRequestRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+	// Signature is that of the payload the QR code of the request holds.
+	Signature string `json:"signature" binding:"required,max=64"`
+}
+
+// payPaymentRequest pays an open payment request someone else made, from an account of the caller
+// in its currency. The signature of its QR code proves the caller scanned it.
+func (server *Server) payPaymentRequest(ctx *gin.Context) {
+	var uri paymentRequestURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req payPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(paymentRequestErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !server.checkAmountStepUp(ctx, request.Currency, request.Amount) {
+		return
+	}
+	toAccount, err := server.store.GetAccount(ctx, request.AccountID)
+	if err != nil {
+		ctx.JSON(paymentRequestErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if !server.payRequests.Verify(request, toAccount.Number, req.Signature) {
+		err := errors.New("invalid payment request signature")
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return
+	}
+	if req.FromAccountID == toAccount.ID {
+		err := errors.New("an account can't pay its own payment request")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	fromAccount, valid := server.validateAccount(ctx, req.FromAccountID, request.Currency)
+	if !valid {
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != payload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	limit, err := ratelimit.AllowTransfer(ctx, server.transfers, fromAccount.ID)
+	if err != nil {
+		ratelimit.SetHeaders(ctx.Writer.Header(), limit)
+		ctx.JSON(http.StatusTooManyRequests, errResponse(ctx, err))
+		return
+	}
+	if !server.checkFraud(ctx, fromAccount, toAccount, request.Amount) {
+		return
+	}
+
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        request.Amount,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	result, err := server.store.PayPaymentRequestTx(ctx, db.PayPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+		OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:     worker.NewAccountAlertTask,
+		RewardTask:    worker.NewAccrueRewardsTask,
+	})
+	if err != nil {
+		ctx.JSON(paymentRequestErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(request.Currency, request.Amount)
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
@@ -0,0 +1,414 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/payrequest"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentRequestAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.EUR))
+	account.Number = "SB06123456789012"
+	otherAccount := randomAccount(other.Username, testfixtures.WithCurrency(util.EUR))
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_id": account.ID, "amount": 1250, "currency": util.EUR, "memo": "dinner"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CreatePaymentRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, int64(1250), arg.Amount)
+						require.Equal(t, "dinner", arg.Memo)
+						require.WithinDuration(t, time.Now().Add(defaultPaymentRequestExpiry), arg.ExpiresAt, time.Second)
+						return db.PaymentRequest{
+							ID:        1,
+							AccountID: arg.AccountID,
+							Amount:    arg.Amount,
+							Currency:  arg.Currency,
+							Memo:      arg.Memo,
+							Status:    db.PaymentRequestOpen,
+							ExpiresAt: arg.ExpiresAt,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp paymentRequestResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.PaymentRequestOpen, rsp.PaymentRequest.Status)
+				require.Equal(t, account.Number, rsp.Payload.AccountNumber)
+				require.True(t, server.payRequests.Verify(rsp.PaymentRequest, account.Number, rsp.Payload.Signature))
+
+				payload, err := payrequest.Parse(rsp.URI)
+				require.NoError(t, err)
+				require.Equal(t, rsp.Payload, payload)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{"account_id": account.ID, "amount": 1250, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Unauthorized",
+			body: gin.H{"account_id": otherAccount.ID, "amount": 1250, "currency": util.EUR},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: gin.H{"account_id": account.ID, "amount": 0, "currency": util.EUR},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/payment-requests", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestGetPaymentRequestQRAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	paymentRequest := db.PaymentRequest{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: account.ID,
+		Amount:    500,
+		Currency:  account.Currency,
+		Status:    db.PaymentRequestOpen,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			query:    "?size=300",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+
+				img, err := png.Decode(recorder.Body)
+				require.NoError(t, err)
+				require.Equal(t, 300, img.Bounds().Dx())
+			},
+		},
+		{
+			name:     "InvalidSize",
+			username: user.Username,
+			query:    "?size=5000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(db.PaymentRequest{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payment-requests/%d/qr%s", paymentRequest.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayPaymentRequestAPI(t *testing.T) {
+	payee, _ := randomUser(t)
+	payer, _ := randomUser(t)
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(1), testfixtures.WithCurrency(util.USD))
+	payeeAccount.Number = "SB06123456789012"
+	account := randomAccount(payer.Username, testfixtures.WithID(2), testfixtures.WithCurrency(util.USD))
+	eurAccount := randomAccount(payer.Username, testfixtures.WithID(3), testfixtures.WithCurrency(util.EUR))
+	paymentRequest := db.PaymentRequest{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: payeeAccount.ID,
+		Amount:    500,
+		Currency:  util.USD,
+		Memo:      "lunch",
+		Status:    db.PaymentRequestOpen,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          func(server *Server) gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payer.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": account.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					PayPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.PayPaymentRequestTxParams) (db.PayPaymentRequestTxResult, error) {
+						require.Equal(t, paymentRequest.ID, arg.ID)
+						require.Equal(t, account.ID, arg.FromAccountID)
+						require.Len(t, arg.OutboxTasks, 1)
+						require.NotNil(t, arg.AlertTask)
+						paid := paymentRequest
+						paid.Status = db.PaymentRequestPaid
+						return db.PayPaymentRequestTxResult{PaymentRequest: paid}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.PayPaymentRequestTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.PaymentRequestPaid, rsp.PaymentRequest.Status)
+			},
+		},
+		{
+			name:     "InvalidSignature",
+			username: payer.Username,
+			body: func(server *Server) gin.H {
+				tampered := paymentRequest
+				tampered.Amount = 5
+				signature := server.payRequests.Sign(tampered, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": account.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			username: payer.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": eurAccount.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnRequest",
+			username: payee.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": payeeAccount.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: payee.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": account.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "ScreeningMatch",
+			username: payer.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": account.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{}, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return([]string{"account_id"}, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyPaid",
+			username: payer.Username,
+			body: func(server *Server) gin.H {
+				signature := server.payRequests.Sign(paymentRequest, payeeAccount.Number).Signature
+				return gin.H{"from_account_id": account.ID, "signature": signature}
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					PayPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayPaymentRequestTxResult{}, db.ErrPaymentRequestClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body(server))
+			require.NoError(t, err)
+			url := fmt.Sprintf("/payment-requests/%d/pay", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// The preference routes let users choose their locale, the timezone their statements are dated
+// in, the currency of the accounts they open without telling, and the notifications they mute.
+
+// userPreferences returns the preferences of username.
+func (server *Server) userPreferences(ctx *gin.Context, username string) (preferences.Preferences, error) {
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		return preferences.Preferences{}, err
+	}
+	return preferences.Parse(user.Preferences)
+}
+
+// getPreferences returns the preferences of the caller, the defaults for those never saved.
+func (server *Server) getPreferences(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	settings, err := server.userPreferences(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, settings)
+}
+
+type notificationSettingsRequest struct {
+	Muted []string `json:"muted" binding:"max=20,dive,notification_kind"`
+}
+
+type updatePreferencesRequest struct {
+	// Locale and Timezone are the defaults when empty.
+	Locale          string                      `json:"locale"`
+	Timezone        string                      `json:"timezone" binding:"max=64"`
+	DefaultCurrency string                      `json:"default_currency" binding:"omitempty,currency"`
+	Notifications   notificationSettingsRequest `json:"notifications"`
+}
+
+// updatePreferences replaces the preferences of the caller.
+func (server *Server) updatePreferences(ctx *gin.Context) {
+	var req updatePreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	settings := preferences.Default()
+	if req.Locale != "" {
+		settings.Locale = req.Locale
+	}
+	if req.Timezone != "" {
+		settings.Timezone = req.Timezone
+	}
+	settings.DefaultCurrency = req.DefaultCurrency
+	if req.Notifications.Muted != nil {
+		settings.Notifications.Muted = req.Notifications.Muted
+	}
+	data, err := settings.Marshal()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, err = server.store.UpdateUserPreferences(ctx, db.UpdateUserPreferencesParams{
+		Username:    payload.Username,
+		Preferences: data,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, settings)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPreferencesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "NeverSaved",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requirePreferences(t, recorder, preferences.Default())
+			},
+		},
+		{
+			name: "Saved",
+			buildStubs: func(store *mockdb.MockStore) {
+				saved := user
+				saved.Preferences = []byte(`{"locale": "zh-TW", "timezone": "Asia/Taipei", "notifications": {"muted": ["low_balance"]}}`)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(saved, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requirePreferences(t, recorder, preferences.Preferences{
+					Locale:        "zh-TW",
+					Timezone:      "Asia/Taipei",
+					Notifications: preferences.Notifications{Muted: []string{"low_balance"}},
+				})
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/users/me/preferences", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdatePreferencesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"locale":           "zh-TW",
+				"timezone":         "Asia/Taipei",
+				"default_currency": util.EUR,
+				"notifications":    gin.H{"muted": []string{"low_balance", "new_login"}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserPreferences(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserPreferencesParams) ([]byte, error) {
+						require.Equal(t, user.Username, arg.Username)
+						saved, err := preferences.Parse(arg.Preferences)
+						require.NoError(t, err)
+						require.Equal(t, "Asia/Taipei", saved.Timezone)
+						require.Equal(t, util.EUR, saved.DefaultCurrency)
+						return arg.Preferences, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requirePreferences(t, recorder, preferences.Preferences{
+					Locale:          "zh-TW",
+					Timezone:        "Asia/Taipei",
+					DefaultCurrency: util.EUR,
+					Notifications:   preferences.Notifications{Muted: []string{"low_balance", "new_login"}},
+				})
+			},
+		},
+		{
+			name: "Defaults",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPreferences(gomock.Any(), gomock.Any()).Times(1).Return([]byte(`{}`), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requirePreferences(t, recorder, preferences.Default())
+			},
+		},
+		{
+			name: "UnknownTimezone",
+			body: gin.H{"timezone": "Mars/Olympus"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedLocale",
+			body: gin.H{"locale": "fr"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownNotificationKind",
+			body: gin.H{"notifications": gin.H{"muted": []string{"newsletter"}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, "/users/me/preferences", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requirePreferences(t *testing.T, recorder *httptest.ResponseRecorder, expected preferences.Preferences) {
+	var got preferences.Preferences
+	requireBodyData(t, recorder.Body, &got)
+	require.Equal(t, expected, got)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/receipt"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// The receipt routes give the parties of a transfer its receipt, and let anyone holding a receipt
+// check it with the bank.
+
+type transferReceiptURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type transferReceiptRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json pdf"`
+}
+
+// getTransferReceipt returns the receipt of a transfer from or to an account of the caller, as
+// json by default or as a pdf.
+func (server *Server) getTransferReceipt(ctx *gin.Context) {
+	var uri transferReceiptURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req transferReceiptRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	// the accounts of a transfer may have been closed since, their receipts stay
+	fromAccount, err := server.store.GetAccountIncludeDeleted(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	toAccount, err := server.store.GetAccountIncludeDeleted(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != payload.Username && toAccount.Owner != payload.Username {
+		err := errors.New("transfer doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	issued := server.receipts.Issue(transfer, fromAccount.Currency)
+	if req.Format == "pdf" {
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%d.pdf"`, transfer.ID))
+		ctx.Data(http.StatusOK, "application/pdf", receipt.PDF(issued))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, issued)
+}
+
+type verifyReceiptRequest struct {
+	TransferID int64  `form:"transfer_id" binding:"required,min=1"`
+	Code       string `form:"code" binding:"required,max=32"`
+}
+
+type verifyReceiptResponse struct {
+	Valid bool `json:"valid"`
+	// Receipt is the receipt the code is of, for the holder to compare with theirs, when valid.
+	Receipt *receipt.Receipt `json:"receipt,omitempty"`
+}
+
+// verifyReceipt checks the verification code of the receipt of a transfer, for anyone holding
+// it. An unknown transfer is only an invalid code, so that the route tells nothing about the
+// transfers to whoever doesn't hold a receipt.
+func (server *Server) verifyReceipt(ctx *gin.Context) {
+	var req verifyReceiptRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.TransferID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			envelope.JSON(ctx, http.StatusOK, verifyReceiptResponse{})
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	fromAccount, err := server.store.GetAccountIncludeDeleted(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	if !server.receipts.Verify(transfer, fromAccount.Currency, req.Code) {
+		envelope.JSON(ctx, http.StatusOK, verifyReceiptResponse{})
+		return
+	}
+	issued := server.receipts.Issue(transfer, fromAccount.Currency)
+	envelope.JSON(ctx, http.StatusOK, verifyReceiptResponse{Valid: true, Receipt: &issued})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/referral"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// The referral routes let users get the code they share to refer someone, and follow the referrals
+// it made. The worker pays the bonuses once a referee qualifies.
+
+// getReferralCode returns the referral code of the caller, creating it the first time.
+func (server *Server) getReferralCode(ctx *gin.Context) {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	code, err := server.store.GetReferralCode(ctx, payload.Username)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		var newCode string
+		newCode, err = referral.NewCode()
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		code, err = server.store.CreateReferralCode(ctx, db.CreateReferralCodeParams{
+			Username: payload.Username,
+			Code:     newCode,
+		})
+		// another request created it in between
+		if errors.Is(err, db.ErrRecordNotFound) {
+			code, err = server.store.GetReferralCode(ctx, payload.Username)
+		}
+	}
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, code)
+}
+
+type listReferralsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listReferrals lists the users the caller referred, the newest first, with whether they
+// qualified.
+func (server *Server) listReferrals(ctx *gin.Context) {
+	var req listReferralsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	referrals, err := server.store.ListReferrals(ctx, db.ListReferralsParams{
+		Referrer: payload.Username,
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, referrals)
+}
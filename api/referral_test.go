@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/referral"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReferralCodeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	code := db.ReferralCode{Username: user.Username, Code: "ABCD2345"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Existing",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetReferralCode(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(code, nil)
+				store.EXPECT().CreateReferralCode(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.ReferralCode
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, code.Code, rsp.Code)
+			},
+		},
+		{
+			name: "Created",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetReferralCode(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.ReferralCode{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateReferralCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateReferralCodeParams) (db.ReferralCode, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Len(t, arg.Code, referral.CodeLength)
+						return db.ReferralCode{Username: arg.Username, Code: arg.Code}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.ReferralCode
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp.Code, referral.CodeLength)
+			},
+		},
+		{
+			name: "CreatedConcurrently",
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().GetReferralCode(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.ReferralCode{}, db.ErrRecordNotFound),
+					store.EXPECT().CreateReferralCode(gomock.Any(), gomock.Any()).Times(1).Return(db.ReferralCode{}, db.ErrRecordNotFound),
+					store.EXPECT().GetReferralCode(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(code, nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.ReferralCode
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, code.Code, rsp.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetReferralCode(gomock.Any(), gomock.Any()).Times(1).Return(db.ReferralCode{}, errors.New("connection refused"))
+				store.EXPECT().CreateReferralCode(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/referrals/code", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListReferralsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	referee, _ := randomUser(t)
+	referrals := []db.Referral{
+		{ID: 1, Referrer: user.Username, Referee: referee.Username, Status: db.ReferralPending},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListReferrals(gomock.Any(), gomock.Eq(db.ListReferralsParams{Referrer: user.Username, Limit: 5, Offset: 5})).
+					Times(1).
+					Return(referrals, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.Referral
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp, 1)
+				require.Equal(t, referee.Username, rsp[0].Referee)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_id=1&page_size=500",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListReferrals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/referrals?%s", tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The refund routes let the payee of a transfer pay back all or part of it, e.g. for a returned
+// item, in as many refunds as it takes to refund the whole amount.
+
+// refundErrStatus maps the errors of the refunds to a response status.
+func refundErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrRefundExceedsTransfer):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+type transferRefundsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type createRefundRequest struct {
+	Amount int64 `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0,amount=Currency"`
+	// AmountDecimal is the amount in units of the currency, e.g. "12.34", instead of amount.
+	AmountDecimal string `json:"amount_decimal" binding:"omitempty,excluded_with=Amount,decimal_amount=Currency"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	Reason        string `json:"reason" binding:"max=140"`
+}
+
+// createRefund pays back an amount of a transfer to an account of the caller, to the account it
+// debited.
+func (server *Server) createRefund(ctx *gin.Context) {
+	var uri transferRefundsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req createRefundRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if !bindAmount(ctx, &req.Amount, req.AmountDecimal, req.Currency) {
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(refundErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	fromAccount, valid := server.validateAccount(ctx, transfer.ToAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != payload.Username {
+		err := errors.New("only the payee of a transfer can refund it")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	toAccount, valid := server.validateAccount(ctx, transfer.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	if req.Amount > transfer.Amount {
+		err := errors.New("a refund can't exceed the amount of the transfer")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	limit, err := ratelimit.AllowTransfer(ctx, server.transfers, fromAccount.ID)
+	if err != nil {
+		ratelimit.SetHeaders(ctx.Writer.Header(), limit)
+		ctx.JSON(http.StatusTooManyRequests, errResponse(ctx, err))
+		return
+	}
+
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	result, err := server.store.RefundTransferTx(ctx, db.RefundTransferTxParams{
+		TransferID:  transfer.ID,
+		Amount:      req.Amount,
+		Reason:      req.Reason,
+		Username:    payload.Username,
+		OutboxTasks: []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:   worker.NewAccountAlertTask,
+	})
+	if err != nil {
+		ctx.JSON(refundErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(req.Currency, req.Amount)
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
+
+type listRefundsResponse struct {
+	Refunds []db.Refund `json:"refunds"`
+	// RefundedAmount is the amount of all the refunds of the transfer.
+	RefundedAmount int64 `json:"refunded_amount"`
+	// RemainingAmount is what can still be refunded, the reversal of a refunded dispute deducted.
+	RemainingAmount int64 `json:"remaining_amount"`
+}
+
+// listRefunds lists the refunds of a transfer from or to an account of the caller, the oldest
+// first, with the amount left to refund.
+func (server *Server) listRefunds(ctx *gin.Context) {
+	var uri transferRefundsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(refundErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	fromAccount, err := server.store.GetAccountIncludeDeleted(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	toAccount, err := server.store.GetAccountIncludeDeleted(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != payload.Username && toAccount.Owner != payload.Username {
+		err := errors.New("transfer doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	refunds, err := server.store.ListTransferRefunds(ctx, transfer.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	returned, err := server.store.GetReturnedAmount(ctx, transfer.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	rsp := listRefundsResponse{
+		Refunds:         refunds,
+		RefundedAmount:  returned.Refunded,
+		RemainingAmount: transfer.Amount - returned.Refunded - returned.Reversed,
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRefundAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	payerAccount := randomAccount(payer.Username, testfixtures.WithID(1), testfixtures.WithCurrency(util.USD))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2), testfixtures.WithCurrency(util.USD))
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: payerAccount.ID,
+		ToAccountID:   payeeAccount.ID,
+		Amount:        100,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payee.Username,
+			body:     gin.H{"amount": 40, "currency": util.USD, "reason": "returned item"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					RefundTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RefundTransferTxParams) (db.RefundTransferTxResult, error) {
+						require.Equal(t, transfer.ID, arg.TransferID)
+						require.Equal(t, int64(40), arg.Amount)
+						require.Equal(t, "returned item", arg.Reason)
+						require.Equal(t, payee.Username, arg.Username)
+						require.Len(t, arg.OutboxTasks, 1)
+						require.NotNil(t, arg.AlertTask)
+						return db.RefundTransferTxResult{
+							Refund:          db.Refund{ID: 1, TransferID: transfer.ID, Amount: arg.Amount},
+							RefundedAmount:  40,
+							RemainingAmount: 60,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.RefundTransferTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(60), rsp.RemainingAmount)
+			},
+		},
+		{
+			name:     "Payer",
+			username: payer.Username,
+			body:     gin.H{"amount": 40, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().RefundTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			username: payee.Username,
+			body:     gin.H{"amount": 40, "currency": util.EUR},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().RefundTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "OverTransferAmount",
+			username: payee.Username,
+			body:     gin.H{"amount": 101, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().RefundTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyRefunded",
+			username: payee.Username,
+			body:     gin.H{"amount": 40, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					RefundTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RefundTransferTxResult{}, db.ErrRefundExceedsTransfer)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Frozen",
+			username: payee.Username,
+			body:     gin.H{"amount": 40, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					RefundTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RefundTransferTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: payee.Username,
+			body:     gin.H{"amount": 40, "currency": util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.Transfer{}, db.ErrRecordNotFound)
+				store.EXPECT().RefundTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/transfers/%d/refunds", transfer.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListRefundsAPI(t *testing.T) {
+	payer, _ := randomUser(t)
+	payee, _ := randomUser(t)
+	other, _ := randomUser(t)
+	payerAccount := randomAccount(payer.Username, testfixtures.WithID(1))
+	payeeAccount := randomAccount(payee.Username, testfixtures.WithID(2))
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: payerAccount.ID,
+		ToAccountID:   payeeAccount.ID,
+		Amount:        100,
+	}
+	refunds := []db.Refund{
+		{ID: 1, TransferID: transfer.ID, Amount: 30},
+		{ID: 2, TransferID: transfer.ID, Amount: 25},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Payer",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListTransferRefunds(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(refunds, nil)
+				store.EXPECT().GetReturnedAmount(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).
+					Return(db.GetReturnedAmountRow{Refunded: 55}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listRefundsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp.Refunds, 2)
+				require.Equal(t, int64(55), rsp.RefundedAmount)
+				require.Equal(t, int64(45), rsp.RemainingAmount)
+			},
+		},
+		{
+			name:     "ReversedDispute",
+			username: payee.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(payeeAccount.ID)).Times(1).Return(payeeAccount, nil)
+				store.EXPECT().ListTransferRefunds(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(refunds[:1], nil)
+				store.EXPECT().GetReturnedAmount(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).
+					Return(db.GetReturnedAmountRow{Refunded: 30, Reversed: 70}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listRefundsResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(30), rsp.RefundedAmount)
+				require.Equal(t, int64(0), rsp.RemainingAmount)
+			},
+		},
+		{
+			name:     "Unauthorized",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(2).Return(payerAccount, nil)
+				store.EXPECT().ListTransferRefunds(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfers/%d/refunds", transfer.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The reward routes let users see the cashback points their payments earned an account, which the
+// worker accrues after each payment, and redeem them as a credit to the account.
+
+// rewardErrStatus maps the errors of the rewards to a response status.
+func rewardErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrInsufficientPoints), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+type accountRewardsURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getRewardsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+type rewardsResponse struct {
+	AccountID int64 `json:"account_id"`
+	// Balance is the points the account can redeem, each worth a unit of Currency, e.g. a cent.
+	Balance  int64            `json:"balance"`
+	Currency string           `json:"currency"`
+	Entries  []db.RewardEntry `json:"entries"`
+}
+
+// getRewards returns the reward balance of an account of the caller, with its accruals and
+// redemptions, the newest first.
+func (server *Server) getRewards(ctx *gin.Context) {
+	var uri accountRewardsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req getRewardsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	balance, err := server.store.GetRewardBalance(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	entries, err := server.store.ListRewardEntries(ctx, db.ListRewardEntriesParams{
+		AccountID: account.ID,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, rewardsResponse{
+		AccountID: account.ID,
+		Balance:   balance,
+		Currency:  account.Currency,
+		Entries:   entries,
+	})
+}
+
+type redeemRewardsRequest struct {
+	Points int64 `json:"points" binding:"required,gt=0"`
+}
+
+// redeemRewards credits points of an account of the caller to it, up to its reward balance.
+func (server *Server) redeemRewards(ctx *gin.Context) {
+	var uri accountRewardsURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req redeemRewardsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	account, ok := server.authorizeAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.RedeemRewardsTx(ctx, db.RedeemRewardsTxParams{
+		AccountID: account.ID,
+		Points:    req.Points,
+		AlertTask: worker.NewAccountAlertTask,
+	})
+	if err != nil {
+		ctx.JSON(rewardErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(account.Currency, req.Points)
+
+	envelope.JSON(ctx, http.StatusOK, result)
+}
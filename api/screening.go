@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The screening routes let the admins keep the denylist the users and transfers are screened
+// against, and clear or confirm the holds of the users who matched it.
+
+// screeningErrStatus maps the errors of the denylist and the screening holds to a response status.
+func screeningErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrDenylistEntryExists), errors.Is(err, db.ErrHoldCleared):
+		return http.StatusForbidden
+	case errors.Is(err, db.ErrInvalidHoldStatus), errors.Is(err, admin.ErrInvalidDenylistEntry):
+		return http.StatusBadRequest
+	}
+	return operatorErrStatus(err)
+}
+
+type listDenylistRequest struct {
+	Kind     string `form:"kind" binding:"omitempty,oneof=name email account"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listDenylist lists the entries of the denylist, the newest first.
+func (server *Server) listDenylist(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the denylist") {
+		return
+	}
+
+	var req listDenylistRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	entries, err := server.store.ListDenylistEntries(ctx, db.ListDenylistEntriesParams{
+		Kind:   pgtype.Text{String: req.Kind, Valid: req.Kind != ""},
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, entries)
+}
+
+type addDenylistEntryRequest struct {
+	Kind   string `json:"kind" binding:"required,oneof=name email account"`
+	Value  string `json:"value" binding:"required,max=200"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// addDenylistEntry adds a name, an email or an account id to the denylist, holding the existing
+// users it matches.
+func (server *Server) addDenylistEntry(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change the denylist") {
+		return
+	}
+
+	var req addDenylistEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	result, err := operator.AddDenylistEntry(ctx, req.Kind, req.Value, req.Reason)
+	if err != nil {
+		ctx.JSON(screeningErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, result)
+}
+
+type denylistEntryURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type removeDenylistEntryRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// removeDenylistEntry removes an entry from the denylist. The holds it caused stay until an admin
+// clears them.
+func (server *Server) removeDenylistEntry(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change the denylist") {
+		return
+	}
+
+	var uri denylistEntryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req removeDenylistEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	entry, err := operator.RemoveDenylistEntry(ctx, uri.ID, req.Reason)
+	if err != nil {
+		ctx.JSON(screeningErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, entry)
+}
+
+type listScreeningHoldsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending cleared confirmed"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listScreeningHolds lists the screening holds, oldest first, the pending ones being the queue of
+// the matches to look into.
+func (server *Server) listScreeningHolds(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the screening holds") {
+		return
+	}
+
+	var req listScreeningHoldsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	holds, err := server.store.ListScreeningHolds(ctx, db.ListScreeningHoldsParams{
+		Status: pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, holds)
+}
+
+type screeningHoldURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type setScreeningHoldRequest struct {
+	Status string `json:"status" binding:"required,oneof=cleared confirmed"`
+	Note   string `json:"note" binding:"required,max=500"`
+}
+
+// setScreeningHold clears a screening hold, e.g. for a namesake, or confirms the match.
+func (server *Server) setScreeningHold(ctx *gin.Context) {
+	if !requireAdmin(ctx, "review the screening holds") {
+		return
+	}
+
+	var uri screeningHoldURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req setScreeningHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	hold, err := operator.SetScreeningHold(ctx, uri.ID, req.Status, req.Note)
+	if err != nil {
+		ctx.JSON(screeningErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, hold)
+}
@@ -0,0 +1,505 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDenylistAPI(t *testing.T) {
+	entries := []db.DenylistEntry{
+		{ID: 1, Kind: db.DenylistEmail, Value: util.RandomEmail()},
+		{ID: 2, Kind: db.DenylistEmail, Value: util.RandomEmail()},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"kind": {db.DenylistEmail}, "page_id": {"1"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDenylistEntries(gomock.Any(), gomock.Eq(db.ListDenylistEntriesParams{
+						Kind:   pgtype.Text{String: db.DenylistEmail, Valid: true},
+						Limit:  5,
+						Offset: 0,
+					})).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.DenylistEntry
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, entries, rsp)
+			},
+		},
+		{
+			name:  "InvalidKind",
+			query: url.Values{"kind": {"phone"}, "page_id": {"1"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDenylistEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: url.Values{"page_id": {"1"}, "page_size": {"5"}},
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDenylistEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/denylist?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddDenylistEntryAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	body := gin.H{"kind": db.DenylistName, "value": user.FullName, "reason": "sanctions list"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AddDenylistEntryTxParams) (db.AddDenylistEntryTxResult, error) {
+						require.Equal(t, db.DenylistName, arg.Kind)
+						require.Equal(t, user.FullName, arg.Value)
+						require.Equal(t, "admin", arg.CreatedBy)
+						require.Equal(t, admin.ActionAddDenylistEntry, arg.Audit.Action)
+
+						entry := db.DenylistEntry{ID: 1, Kind: arg.Kind, Value: arg.Value, Reason: arg.Reason}
+						return db.AddDenylistEntryTxResult{
+							Entry: entry,
+							Holds: []db.ScreeningHold{{ID: 1, Username: user.Username, Matches: []string{entry.Match()}, Status: db.HoldPending}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.AddDenylistEntryTxResult
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, user.FullName, rsp.Entry.Value)
+				require.Len(t, rsp.Holds, 1)
+				require.Equal(t, user.Username, rsp.Holds[0].Username)
+			},
+		},
+		{
+			name: "Exists",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AddDenylistEntryTxResult{}, db.ErrDenylistEntryExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"kind": db.DenylistEmail, "value": "not an email", "reason": "sanctions list"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AddDenylistEntryTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidKind",
+			body: gin.H{"kind": "phone", "value": "555-0100", "reason": "sanctions list"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AddDenylistEntryTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().AddDenylistEntryTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/admin/denylist", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveDenylistEntryAPI(t *testing.T) {
+	entry := db.DenylistEntry{
+		ID:    util.RandomInt(1, 1000),
+		Kind:  db.DenylistAccount,
+		Value: "42",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"reason": "account closed by the police"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDenylistEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(entry, nil)
+				store.EXPECT().
+					DeleteDenylistEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DeleteDenylistEntryTxParams) (db.DenylistEntry, error) {
+						require.Equal(t, entry.ID, arg.ID)
+						require.Equal(t, admin.ActionRemoveDenylistEntry, arg.Audit.Action)
+						require.Equal(t, "denylist:account:42", arg.Audit.Target)
+						return entry, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithoutReason",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDenylistEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"reason": "delisted"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDenylistEntry(gomock.Any(), gomock.Any()).Times(1).Return(db.DenylistEntry{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteDenylistEntryTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/denylist/%d/remove", entry.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListScreeningHoldsAPI(t *testing.T) {
+	holds := []db.ScreeningHold{{ID: 1, Matches: []string{"name:John Doe"}, Status: db.HoldPending}}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"status": {db.HoldPending}, "page_id": {"2"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListScreeningHolds(gomock.Any(), gomock.Eq(db.ListScreeningHoldsParams{
+						Status: pgtype.Text{String: db.HoldPending, Valid: true},
+						Limit:  5,
+						Offset: 5,
+					})).
+					Times(1).
+					Return(holds, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []db.ScreeningHold
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, holds, rsp)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: url.Values{"status": {"closed"}, "page_id": {"1"}, "page_size": {"5"}},
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListScreeningHolds(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: url.Values{"page_id": {"1"}, "page_size": {"5"}},
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListScreeningHolds(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/screening_holds?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetScreeningHoldAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	hold := db.ScreeningHold{
+		ID:       util.RandomInt(1, 1000),
+		Username: user.Username,
+		Matches:  []string{"name:" + user.FullName},
+		Status:   db.HoldPending,
+	}
+	body := gin.H{"status": db.HoldCleared, "note": "different date of birth"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Clear",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().
+					SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetScreeningHoldStatusTxParams) (db.ScreeningHold, error) {
+						require.Equal(t, hold.ID, arg.ID)
+						require.Equal(t, db.HoldCleared, arg.Status)
+						require.Equal(t, "admin", arg.Admin)
+						require.Equal(t, admin.ActionSetScreeningHold, arg.Audit.Action)
+						require.Equal(t, admin.UserTarget(user.Username), arg.Audit.Target)
+
+						cleared := hold
+						cleared.Status = arg.Status
+						cleared.ReviewedBy = arg.Admin
+						cleared.Note = arg.Note
+						return cleared, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.ScreeningHold
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.HoldCleared, rsp.Status)
+				require.Equal(t, "different date of birth", rsp.Note)
+			},
+		},
+		{
+			name: "Cleared",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ScreeningHold{}, db.ErrHoldCleared)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyConfirmed",
+			body: gin.H{"status": db.HoldConfirmed, "note": "same date of birth"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Any()).Times(1).Return(hold, nil)
+				store.EXPECT().SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ScreeningHold{}, db.ErrInvalidHoldStatus)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidStatus",
+			body: gin.H{"status": db.HoldPending, "note": "reopened"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Any()).Times(1).Return(db.ScreeningHold{}, db.ErrRecordNotFound)
+				store.EXPECT().SetScreeningHoldStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScreeningHold(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/screening_holds/%d/status", hold.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
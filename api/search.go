@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+type searchRequest struct {
+	Query    string `form:"q" binding:"required,max=100"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+type searchUserResult struct {
+	adminUserResponse
+	Rank float32 `json:"rank"`
+}
+
+type searchResponse struct {
+	Users     []searchUserResult      `json:"users"`
+	Transfers []db.SearchTransfersRow `json:"transfers"`
+}
+
+// search finds the users whose username, name or email, and the transfers whose memo, have words
+// starting with every word of q, best matches first. Both lists are paged by page_id.
+func (server *Server) search(ctx *gin.Context) {
+	if !requireAdmin(ctx, "search") {
+		return
+	}
+
+	var req searchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	query := db.SearchQuery(req.Query)
+	if query == "" {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, errors.New("q has no word to search for")))
+		return
+	}
+
+	users, err := server.store.SearchUsers(ctx, db.SearchUsersParams{
+		Query:  query,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	transfers, err := server.store.SearchTransfers(ctx, db.SearchTransfersParams{
+		Query:  query,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := searchResponse{
+		Users:     make([]searchUserResult, len(users)),
+		Transfers: transfers,
+	}
+	for i, user := range users {
+		rsp.Users[i] = searchUserResult{
+			adminUserResponse: newAdminUserResponse(db.User{
+				Username:          user.Username,
+				FullName:          user.FullName,
+				Email:             user.Email,
+				PasswordChangedAt: user.PasswordChangedAt,
+				CreatedAt:         user.CreatedAt,
+				Role:              user.Role,
+				DeletedAt:         user.DeletedAt,
+			}),
+			Rank: user.Rank,
+		}
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
@@ -1,60 +1,335 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/backendmaster/simple_bank/admin"
+	"github.com/backendmaster/simple_bank/auditlog"
+	"github.com/backendmaster/simple_bank/card"
+	"github.com/backendmaster/simple_bank/compression"
+	"github.com/backendmaster/simple_bank/crossorigin"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/graph"
+	"github.com/backendmaster/simple_bank/health"
+	"github.com/backendmaster/simple_bank/limits"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/payrequest"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/receipt"
+	"github.com/backendmaster/simple_bank/recovery"
+	"github.com/backendmaster/simple_bank/screening"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/tracing"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/version"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
 type Server struct {
-	config     util.Config
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
+	config        util.Config
+	store         db.Store
+	tokenMaker    token.Maker
+	taskInspector worker.TaskInspector
+	entries       EntrySubscriber
+	mode          *maintenance.Mode
+	fraud         *fraud.Engine
+	screener      screening.Screener
+	receipts      *receipt.Signer
+	cards         *card.Issuer
+	payRequests   *payrequest.Signer
+	rates         *fx.Converter
+	limiter       ratelimit.Limiter
+	transfers     ratelimit.Limiter
+	stepUpAmounts map[string]int64
+	graphQL       http.Handler
+	timeouts      map[string]time.Duration
+	versions      []apiVersion
+	router        *gin.Engine
+	httpServer    *http.Server
 }
 
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store, mode *maintenance.Mode, taskInspector worker.TaskInspector, entries EntrySubscriber) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("can't not create token")
 	}
+	routeTimeouts, err := limits.ParseRouteTimeouts(config.RouteTimeouts)
+	if err != nil {
+		return nil, err
+	}
+	sunset, err := parseSunset(config.UnversionedRoutesSunset)
+	if err != nil {
+		return nil, err
+	}
+	versions := apiVersions(sunset)
+	if _, ok := routeTimeouts[userImportsRoute]; !ok {
+		routeTimeouts[userImportsRoute] = userImportTimeout
+	}
+	timeouts := map[string]time.Duration{}
+	for route, timeout := range routeTimeouts {
+		for _, versioned := range versionedRoutes(versions, route) {
+			timeouts[versioned] = timeout
+		}
+	}
+	screener := screening.NewDenylist(store)
+	engine, err := fraud.New(config, store, screener)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := receipt.NewSigner(config.ReceiptKey)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := card.NewIssuer(config.CardKey, config.CardNetworkKey)
+	if err != nil {
+		return nil, err
+	}
+	payRequests, err := payrequest.NewSigner(config.PaymentRequestKey)
+	if err != nil {
+		return nil, err
+	}
+	stepUpAmounts, err := util.ParseCurrencyAmounts(config.StepUpTransferAmounts)
+	if err != nil {
+		return nil, err
+	}
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker}
+		config:        config,
+		store:         store,
+		tokenMaker:    tokenMaker,
+		taskInspector: taskInspector,
+		entries:       entries,
+		mode:          mode,
+		fraud:         engine,
+		screener:      screener,
+		receipts:      receipts,
+		cards:         cards,
+		payRequests:   payRequests,
+		rates:         fx.NewConverter(store, config.FXRateMaxAge),
+		limiter:       ratelimit.New(config),
+		transfers:     ratelimit.NewTransferLimiter(config),
+		stepUpAmounts: stepUpAmounts,
+		timeouts:      timeouts,
+		versions:      versions,
+	}
+	server.graphQL = graph.NewHandler(store, server.mode, engine, server.transfers,
+		graph.StepUp{Amounts: stepUpAmounts, MaxAge: config.StepUpMaxAge})
 
 	server.setupRouter()
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("amount", validAmount)
+		v.RegisterValidation("decimal_amount", validDecimalAmount)
+		v.RegisterValidation("category", validCategory)
+		v.RegisterValidation("notification_kind", validNotificationKind)
+		for tag, validation := range userValidations {
+			v.RegisterValidation(tag, validation)
+		}
 	}
 
 	return server, nil
 }
 
 func (server *Server) setupRouter() {
-	router := gin.Default()
-	router.POST("/users", server.createUser)
-	router.POST("/users/login", server.loginUser)
-	router.POST("tokens/renew_access", server.renewAccessToken)
+	bodySizes := map[string]int64{}
+	for _, route := range versionedRoutes(server.versions, userImportsRoute) {
+		bodySizes[route] = admin.MaxUserImportBytes
+	}
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(envelope.GinRequestID(), gin.Logger(), tracing.GinTracing(), metrics.GinMetrics(), recovery.GinRecovery(), crossorigin.GinCors(server.config),
+		maintenance.GinReadOnly(server.mode, versionedRoutes(server.versions, readOnlyRoute, transfersBlockedRoute)...),
+		limits.GinTimeout(server.config.RequestTimeout, server.timeouts),
+		limits.GinMaxBodySize(server.config.MaxBodySize, bodySizes),
+		compression.GinCompression(server.config.CompressionMinSize),
+		auditlog.GinAuditLog(auditlog.New(server.config, server.store), auditActor))
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/healthz", gin.WrapH(health.Liveness()))
+	router.GET("/version", gin.WrapH(version.Handler()))
+	router.GET("/readyz", gin.WrapH(health.Readiness(server.store)))
+	router.GET(openAPIRoute, serveOpenAPI())
+	router.GET(swaggerRoute, serveSwagger)
+	for _, version := range server.versions {
+		group := router.Group(version.prefix)
+		if version.successor != "" {
+			group.Use(deprecated(version))
+		}
+		server.addRoutes(group)
+	}
+	server.router = router
+	server.httpServer = &http.Server{Handler: router}
+}
 
-	authRoute := router.Group("/").Use(authMiddleware(server.tokenMaker))
-	authRoute.POST("/accounts", server.createAccount)
+// addRoutes adds the routes of the api to the group of a version.
+func (server *Server) addRoutes(group *gin.RouterGroup) {
+	rateLimit := ratelimit.GinRateLimit(server.limiter, rateLimitKey)
+	group.POST("/users", rateLimit, server.createUser)
+	group.POST("/users/login", rateLimit, server.loginUser)
+	group.POST("tokens/renew_access", rateLimit, server.renewAccessToken)
+	group.POST("/login_alerts/:id/report", rateLimit, server.reportLogin)
+	group.POST("/login_alerts/:id/reset_code", rateLimit, server.sendPasswordResetCode)
+	group.POST("/login_alerts/:id/reset_password", rateLimit, server.resetPassword)
+	// authenticated by its first message, see serveWebSocket
+	group.GET("/ws", rateLimit, server.serveWebSocket)
+	group.GET("/receipts/verify", rateLimit, server.verifyReceipt)
+	group.GET("/legal_documents", rateLimit, server.listLegalDocuments)
+	// authenticated by the signature of the card network, see authorizeCardPayment
+	group.POST(cardAuthorizationsRoute, maintenance.GinBlockTransfers(server.mode), server.authorizeCardPayment)
+
+	authRoute := group.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+	// the sensitive operations wait for the latest legal documents to be accepted
+	consent := requireConsents(server.store)
+	// and the payments to another bank, which name a new beneficiary each time, a recent
+	// multi-factor authentication
+	stepUp := requireStepUp(token.ACRMultiFactor, server.config.StepUpMaxAge)
+	authRoute.POST("/tokens/step_up", rateLimit, server.stepUp)
+	authRoute.POST("/tokens/step_up/challenges", rateLimit, server.createStepUpChallenge)
+	authRoute.POST("/tokens/step_up/challenges/:id", rateLimit, server.completeStepUpChallenge)
+	authRoute.GET("/users/me/consents", server.getConsents)
+	authRoute.POST("/users/me/consents", server.acceptConsent)
+	authRoute.GET("/users/me/devices", server.listDevices)
+	authRoute.PUT("/users/me/devices/:id/name", server.renameDevice)
+	authRoute.DELETE("/users/me/devices/:id", server.revokeDevice)
+	authRoute.GET("/users/me/preferences", server.getPreferences)
+	authRoute.PUT("/users/me/preferences", server.updatePreferences)
+	authRoute.GET("/users/me/net-worth", server.getNetWorth)
+	authRoute.POST("/accounts", consent, server.createAccount)
 	authRoute.GET("/accounts/:id", server.getAccount)
 	authRoute.GET("/accounts", server.listAccount)
-	authRoute.POST("/transfers", server.createTransfer)
-	server.router = router
+	authRoute.GET("/accounts/:id/entries", server.listAccountEntries)
+	authRoute.GET("/accounts/:id/entries/export", server.exportEntries)
+	authRoute.GET("/accounts/:id/transfers", server.listAccountTransfers)
+	authRoute.GET("/accounts/:id/external_transfers", server.listExternalTransfers)
+	authRoute.GET("/accounts/:id/cards", server.listCards)
+	authRoute.GET("/accounts/:id/authorization_holds", server.listAuthorizationHolds)
+	authRoute.GET("/accounts/:id/payment-requests", server.listPaymentRequests)
+	authRoute.GET("/accounts/:id/loans", server.listLoans)
+	authRoute.GET("/accounts/:id/rewards", server.getRewards)
+	authRoute.POST("/accounts/:id/rewards/redeem", maintenance.GinBlockTransfers(server.mode), consent, server.redeemRewards)
+	authRoute.GET("/accounts/:id/analytics", server.getAccountAnalytics)
+	authRoute.GET("/accounts/:id/alerts", server.getAccountAlert)
+	authRoute.PUT("/accounts/:id/alerts", server.updateAccountAlert)
+	authRoute.GET("/account_numbers/:number", rateLimit, server.getAccountNumber)
+	authRoute.PUT("/entries/:id/category", server.setEntryCategory)
+	authRoute.GET("/category_rules", server.listCategoryRules)
+	authRoute.POST("/category_rules", server.createCategoryRule)
+	authRoute.DELETE("/category_rules/:id", server.deleteCategoryRule)
+	authRoute.GET("/exports/:id", server.getEntryExport)
+	authRoute.GET("/exports/:id/download", server.downloadEntryExport)
+	authRoute.POST("/transfers", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.createTransfer)
+	authRoute.GET("/transfers/:id/receipt", server.getTransferReceipt)
+	authRoute.POST("/transfers/:id/refunds", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.createRefund)
+	authRoute.GET("/transfers/:id/refunds", server.listRefunds)
+	authRoute.POST("/external_transfers", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, stepUp, server.createExternalTransfer)
+	authRoute.GET("/external_transfers/:id", server.getExternalTransfer)
+	authRoute.POST("/cards", consent, server.issueCard)
+	authRoute.GET("/cards/:id", server.getCard)
+	authRoute.PUT("/cards/:id/frozen", server.setCardFrozen)
+	authRoute.PUT("/cards/:id/limits", server.setCardLimits)
+	authRoute.GET("/cards/:id/authorizations", server.listCardAuthorizations)
+	authRoute.POST("/authorization_holds", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.placeAuthorizationHold)
+	authRoute.GET("/authorization_holds/:id", server.getAuthorizationHold)
+	authRoute.POST("/authorization_holds/:id/capture", maintenance.GinBlockTransfers(server.mode), server.captureAuthorizationHold)
+	authRoute.POST("/authorization_holds/:id/release", server.releaseAuthorizationHold)
+	authRoute.POST("/payment-requests", server.createPaymentRequest)
+	authRoute.GET("/payment-requests/:id", server.getPaymentRequest)
+	authRoute.GET("/payment-requests/:id/qr", server.getPaymentRequestQR)
+	authRoute.POST("/payment-requests/:id/pay", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.payPaymentRequest)
+	authRoute.GET("/currencies", server.listCurrencies)
+	authRoute.GET("/exchange_rates", server.listExchangeRates)
+	authRoute.GET("/loan_offers", server.listLoanOffers)
+	authRoute.POST("/loans", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.createLoan)
+	authRoute.GET("/loans/:id", server.getLoan)
+	authRoute.GET("/referrals", server.listReferrals)
+	authRoute.GET("/referrals/code", server.getReferralCode)
+	authRoute.POST("/disputes", server.openDispute)
+	authRoute.GET("/disputes/:id", server.getDispute)
+	authRoute.GET("/transfer_reviews/:id", server.getTransferReview)
+	authRoute.POST("/transfer_reviews/:id/confirm", maintenance.GinBlockTransfers(server.mode), rateLimit, consent, server.confirmTransfer)
+	authRoute.GET("/events", server.streamEvents)
+	authRoute.GET("/notifications", server.listNotifications)
+	authRoute.POST("/notifications/:id/read", server.readNotification)
+	authRoute.POST("/notifications/read_all", server.readAllNotifications)
+	authRoute.GET("/notifications/preferences", server.getNotificationPreferences)
+	authRoute.PUT("/notifications/preferences", server.updateNotificationPreferences)
+	authRoute.PUT(readOnlyRoute, server.setReadOnly)
+	authRoute.PUT(transfersBlockedRoute, server.setTransfersBlocked)
+	authRoute.GET("/admin/dead_tasks", server.listDeadTasks)
+	authRoute.GET("/admin/dead_tasks/:queue/:id", server.getDeadTask)
+	authRoute.POST("/admin/dead_tasks/:queue/:id/requeue", server.requeueDeadTask)
+	authRoute.DELETE("/admin/dead_tasks/:queue/:id", server.discardDeadTask)
+	authRoute.GET("/admin/ledger", server.verifyLedger)
+	authRoute.GET("/admin/users/:username", server.adminGetUser)
+	authRoute.GET("/admin/users/:username/accounts", server.adminListAccounts)
+	authRoute.GET("/admin/accounts/:id", server.adminGetAccount)
+	authRoute.POST("/admin/accounts/:id/adjustments", server.adjustBalance)
+	authRoute.GET("/admin/search", server.search)
+	authRoute.GET("/admin/audit_logs", server.listAuditLogs)
+	authRoute.GET("/admin/audit_logs/verify", server.verifyAuditLogs)
+	authRoute.POST("/admin/impersonations", server.createImpersonation)
+	authRoute.POST("/admin/impersonations/:id/revoke", server.revokeImpersonation)
+	authRoute.GET("/admin/disputes", server.listDisputes)
+	authRoute.POST("/admin/disputes/:id/status", maintenance.GinBlockTransfers(server.mode), server.setDisputeStatus)
+	authRoute.GET("/admin/transfer_reviews", server.listTransferReviews)
+	authRoute.POST("/admin/transfer_reviews/:id/status", maintenance.GinBlockTransfers(server.mode), server.reviewTransfer)
+	authRoute.GET("/admin/denylist", server.listDenylist)
+	authRoute.POST("/admin/denylist", server.addDenylistEntry)
+	authRoute.POST("/admin/denylist/:id/remove", server.removeDenylistEntry)
+	authRoute.GET("/admin/screening_holds", server.listScreeningHolds)
+	authRoute.POST("/admin/screening_holds/:id/status", server.setScreeningHold)
+	authRoute.POST("/admin/loan_offers", server.createLoanOffer)
+	authRoute.POST("/admin/loan_offers/:id/active", server.setLoanOfferActive)
+	authRoute.GET("/admin/loans/delinquent", server.listDelinquentLoans)
+	authRoute.GET("/admin/tenants", server.listTenants)
+	authRoute.POST("/admin/tenants", server.createTenant)
+	authRoute.PUT("/admin/tenants/:id", server.updateTenant)
+	authRoute.POST("/admin/legal_documents", server.publishLegalDocument)
+	authRoute.POST(userImportsRoute, server.createUserImport)
+	authRoute.GET(userImportsRoute+"/:id", server.getUserImport)
+	authRoute.GET(userImportsRoute+"/:id/rows", server.listUserImportRows)
+	authRoute.GET("/admin/currencies", server.listAllCurrencies)
+	authRoute.PUT("/admin/currencies/:code", server.setCurrencyEnabled)
+	authRoute.GET(graphQLRoute, server.serveGraphQL)
+	authRoute.POST(graphQLRoute, rateLimit, server.serveGraphQL)
 }
 
+// Start serves the api on address until Shutdown is called.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	server.httpServer.Addr = address
+	err := server.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
 
-func errResponse(err error) gin.H {
-	return gin.H{"err": err.Error()}
+// Shutdown stops accepting connections and waits for the in-flight requests to finish,
+// or for ctx to be done.
+func (server *Server) Shutdown(ctx context.Context) error {
+	return server.httpServer.Shutdown(ctx)
+}
+
+// errStatus is the status of an unexpected error: 503 when the store failed fast because the
+// database is down, so that clients know to retry later, 403 when the store refused to move money
+// across tenants or over the cap of one, and 500 otherwise.
+func errStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrStoreUnavailable), errors.Is(err, db.ErrTransfersBlocked):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, db.ErrCrossTenant), errors.Is(err, db.ErrTransferLimitExceeded):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
 }
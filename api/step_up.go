@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+)
+
+// The sensitive operations ask for a recent authentication, as the auth_time and acr claims of the
+// access token tell, rather than only a valid token: a token stolen or left on a shared device
+// must not move large amounts. A caller without it gets a 401 with the challenge of RFC 9470 in
+// WWW-Authenticate, and steps up with their password, and with a one-time code emailed to them
+// for a multi-factor authentication, to get an access token authenticated now. Renewing the
+// access token keeps the authentication of the login.
+
+var (
+	errStepUpRequired       = errors.New("authenticate again to continue")
+	errImpersonatedStepUp   = errors.New("an impersonation can't authenticate as the user")
+	errStepUpCompleted      = errors.New("the code was used already")
+	errStepUpChallengeOwner = errors.New("step-up challenge belongs to another user")
+)
+
+// requireStepUp is the middleware of the routes asking for an authentication with at least acr
+// within maxAge.
+func requireStepUp(acr string, maxAge time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !checkStepUp(ctx, acr, maxAge) {
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// checkStepUp checks that the caller authenticated with at least acr within maxAge, for a handler
+// whose need of a step-up depends on its request. It writes the challenge and returns false
+// otherwise.
+func checkStepUp(ctx *gin.Context, acr string, maxAge time.Duration) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if payload.AuthenticatedSince(acr, maxAge) {
+		return true
+	}
+	ctx.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_user_authentication", error_description=%q, acr_values=%q, max_age=%d`,
+		errStepUpRequired.Error(), acr, int(maxAge.Seconds())))
+	ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errStepUpRequired))
+	return false
+}
+
+// checkAmountStepUp asks for a recent multi-factor authentication to move at least the
+// STEP_UP_TRANSFER_AMOUNTS of currency, amount being in its minor units. A currency without an
+// amount moves any amount without one.
+func (server *Server) checkAmountStepUp(ctx *gin.Context, currency string, amount int64) bool {
+	if threshold := server.stepUpAmounts[currency]; threshold <= 0 || amount < threshold {
+		return true
+	}
+	return checkStepUp(ctx, token.ACRMultiFactor, server.config.StepUpMaxAge)
+}
+
+type stepUpResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	AuthTime             time.Time `json:"auth_time"`
+	ACR                  string    `json:"acr"`
+}
+
+// newStepUpToken answers an access token for the caller authenticated now with acr.
+func (server *Server) newStepUpToken(ctx *gin.Context, payload *token.Payload, acr string) {
+	accessToken, accessPayload, err := server.tokenMaker.CreateTokenAuthenticatedAt(payload.Username, payload.Role, payload.Tenant, payload.Locale,
+		time.Now(), acr, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, stepUpResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		AuthTime:             accessPayload.AuthTime,
+		ACR:                  accessPayload.ACR,
+	})
+}
+
+type stepUpRequest struct {
+	Password string `json:"password" binding:"required,password"`
+}
+
+// checkStepUpPassword checks the password of the caller, who can't be an admin impersonating them.
+// It writes the error response and returns false otherwise.
+func (server *Server) checkStepUpPassword(ctx *gin.Context, payload *token.Payload, password string) bool {
+	if payload.Impersonated() {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errImpersonatedStepUp))
+		return false
+	}
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	if err := util.CheckPassword(password, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// stepUp authenticates the caller again with their password.
+func (server *Server) stepUp(ctx *gin.Context) {
+	var req stepUpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.checkStepUpPassword(ctx, payload, req.Password) {
+		return
+	}
+	server.newStepUpToken(ctx, payload, token.ACRPassword)
+}
+
+type stepUpChallengeResponse struct {
+	ID        int64     `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createStepUpChallenge checks the password of the caller and emails them the one-time code of a
+// multi-factor step-up, which completeStepUpChallenge takes.
+func (server *Server) createStepUpChallenge(ctx *gin.Context) {
+	var req stepUpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.checkStepUpPassword(ctx, payload, req.Password) {
+		return
+	}
+	code, hash, err := fraud.NewOTP()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	challenge, err := server.store.CreateStepUpChallengeTx(ctx, db.CreateStepUpChallengeTxParams{
+		CreateStepUpChallengeParams: db.CreateStepUpChallengeParams{
+			Username: payload.Username,
+			CodeHash: hash,
+		},
+		AfterCreate: func(challenge db.StepUpChallenge) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewSendStepUpOTPTask(&worker.PayloadSendStepUpOTP{
+				ChallengeID: challenge.ID,
+				Code:        code,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		},
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusAccepted, stepUpChallengeResponse{
+		ID:        challenge.ID,
+		ExpiresAt: challenge.CreatedAt.Add(fraud.OTPValidity),
+	})
+}
+
+type stepUpChallengeURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type completeStepUpChallengeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// completeStepUpChallenge authenticates the caller with the one-time code of their challenge,
+// after their password, answering an access token of a multi-factor authentication.
+func (server *Server) completeStepUpChallenge(ctx *gin.Context) {
+	var uri stepUpChallengeURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req completeStepUpChallengeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	challenge, err := server.store.GetStepUpChallenge(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if challenge.Username != payload.Username {
+		ctx.JSON(http.StatusNotFound, errResponse(ctx, errStepUpChallengeOwner))
+		return
+	}
+	if challenge.CompletedAt.Valid {
+		ctx.JSON(http.StatusConflict, errResponse(ctx, errStepUpCompleted))
+		return
+	}
+	if challenge.Attempts >= fraud.MaxOTPAttempts {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errTooManyWrongCodes))
+		return
+	}
+	if time.Since(challenge.CreatedAt) > fraud.OTPValidity {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errOTPExpired))
+		return
+	}
+	if !fraud.CheckOTP(req.Code, challenge.CodeHash) {
+		if _, err := server.store.AddStepUpChallengeAttempt(ctx, challenge.ID); err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errWrongOTP))
+		return
+	}
+
+	if _, err := server.store.CompleteStepUpChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, errResponse(ctx, errStepUpCompleted))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	server.newStepUpToken(ctx, payload, token.ACRMultiFactor)
+}
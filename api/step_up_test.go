@@ -0,0 +1,450 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireStepUp(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		path          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		addAuth       func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ExternalTransferWithPassword",
+			path: "/external_transfers",
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				challenge := recorder.Header().Get("WWW-Authenticate")
+				require.Contains(t, challenge, `error="insufficient_user_authentication"`)
+				require.Contains(t, challenge, `acr_values="mfa"`)
+				require.Contains(t, challenge, "max_age=300")
+			},
+		},
+		{
+			name: "ExternalTransferStepUpTooOld",
+			path: "/external_transfers",
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				accessToken, _, err := tokenMaker.CreateTokenAuthenticatedAt(user.Username, util.DepositorRole, util.DefaultTenant, "",
+					time.Now().Add(-time.Hour), token.ACRMultiFactor, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Header().Get("WWW-Authenticate"), "insufficient_user_authentication")
+			},
+		},
+		{
+			name: "LargeTransferWithPassword",
+			path: "/transfers",
+			body: gin.H{
+				"from_account_id": 1,
+				"to_account_id":   2,
+				"amount":          600_000,
+				"currency":        util.USD,
+			},
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Header().Get("WWW-Authenticate"), `acr_values="mfa"`)
+			},
+		},
+		{
+			// the same amount is below the threshold of another currency
+			name: "TransferBelowCurrencyAmount",
+			path: "/transfers",
+			body: gin.H{
+				"from_account_id": 1,
+				"to_account_id":   2,
+				"amount":          600_000,
+				"currency":        util.CAD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "LargePaymentRequestWithPassword",
+			path: "/payment-requests/7/pay",
+			body: gin.H{"from_account_id": 1, "signature": "signature"},
+			buildStubs: func(store *mockdb.MockStore) {
+				request := db.PaymentRequest{ID: 7, AccountID: 2, Amount: 600_000, Currency: util.USD}
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+			},
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Header().Get("WWW-Authenticate"), `acr_values="mfa"`)
+			},
+		},
+		{
+			name: "LargeAuthorizationHoldWithPassword",
+			path: "/authorization_holds",
+			body: gin.H{
+				"from_account_id": 1,
+				"to_account_id":   2,
+				"amount":          600_000,
+				"currency":        util.USD,
+			},
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Header().Get("WWW-Authenticate"), `acr_values="mfa"`)
+			},
+		},
+		{
+			name: "LargeCaptureWithPassword",
+			path: "/authorization_holds/7/capture",
+			buildStubs: func(store *mockdb.MockStore) {
+				hold := db.AuthorizationHold{ID: 7, AccountID: 1, ToAccountID: 2, Amount: 600_000, Currency: util.USD}
+				store.EXPECT().GetAuthorizationHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(hold.ToAccountID)).Times(1).
+					Return(db.Account{ID: hold.ToAccountID, Owner: user.Username}, nil)
+				store.EXPECT().CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			addAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Header().Get("WWW-Authenticate"), `acr_values="mfa"`)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// the step-up is checked before the money moves
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			server := newTestServer(t, store)
+			server.stepUpAmounts = map[string]int64{util.USD: 500_000, util.CAD: 700_000}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, tc.path, bytes.NewReader(data))
+			require.NoError(t, err)
+			tc.addAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestStepUpAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		password      string
+		impersonated  bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp stepUpResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, token.ACRPassword, rsp.ACR)
+
+				payload, err := tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.True(t, payload.AuthenticatedSince(token.ACRPassword, time.Minute))
+				require.False(t, payload.AuthenticatedSince(token.ACRMultiFactor, time.Minute))
+			},
+		},
+		{
+			name:     "WrongPassword",
+			password: password + "x",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:         "Impersonated",
+			password:     password,
+			impersonated: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetImpersonation(gomock.Any(), gomock.Any()).Times(1).Return(db.Impersonation{}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errImpersonatedStepUp.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"password": tc.password})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/tokens/step_up", bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.impersonated {
+				accessToken, _, err := server.tokenMaker.CreateImpersonationToken(user.Username, util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+			} else {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			}
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.tokenMaker)
+		})
+	}
+}
+
+func TestCreateStepUpChallengeAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		CreateStepUpChallengeTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateStepUpChallengeTxParams) (db.StepUpChallenge, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.NotEmpty(t, arg.CodeHash)
+
+			challenge := db.StepUpChallenge{ID: 7, Username: arg.Username, CodeHash: arg.CodeHash, CreatedAt: time.Now()}
+			tasks, err := arg.AfterCreate(challenge)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			require.Equal(t, worker.TaskSendStepUpOTP, tasks[0].TaskType)
+			var payload worker.PayloadSendStepUpOTP
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			require.Equal(t, challenge.ID, payload.ChallengeID)
+			require.True(t, fraud.CheckOTP(payload.Code, arg.CodeHash))
+			return challenge, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"password": password})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/tokens/step_up/challenges", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	var rsp stepUpChallengeResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, int64(7), rsp.ID)
+	require.WithinDuration(t, time.Now().Add(fraud.OTPValidity), rsp.ExpiresAt, time.Second)
+}
+
+func TestCompleteStepUpChallengeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	code, hash, err := fraud.NewOTP()
+	require.NoError(t, err)
+	challenge := db.StepUpChallenge{ID: 7, Username: user.Username, CodeHash: hash, CreatedAt: time.Now()}
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker)
+	}{
+		{
+			name: "OK",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp stepUpResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, token.ACRMultiFactor, rsp.ACR)
+
+				payload, err := tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.True(t, payload.AuthenticatedSince(token.ACRMultiFactor, time.Minute))
+			},
+		},
+		{
+			name: "WrongCode",
+			code: "000000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+				store.EXPECT().AddStepUpChallengeAttempt(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errWrongOTP.Error())
+			},
+		},
+		{
+			name: "TooManyAttempts",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				tried := challenge
+				tried.Attempts = fraud.MaxOTPAttempts
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(tried, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTooManyWrongCodes.Error())
+			},
+		},
+		{
+			name: "Expired",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				old := challenge
+				old.CreatedAt = time.Now().Add(-fraud.OTPValidity - time.Minute)
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(old, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errOTPExpired.Error())
+			},
+		},
+		{
+			name: "Completed",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				completed := challenge
+				completed.CompletedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(completed, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "CompletedConcurrently",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(db.StepUpChallenge{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUser",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				other := challenge
+				other.Username = "other"
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(other, nil)
+				store.EXPECT().CompleteStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "12ab",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"code": tc.code})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/tokens/step_up/challenges/%d", challenge.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.tokenMaker)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+// The support routes let admins look at any user or account. With include_deleted they also see
+// the soft-deleted ones, which the rest of the api treats as gone.
+
+type includeDeletedRequest struct {
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
+type adminUserResponse struct {
+	userResponse
+	Role      string     `json:"role"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func newAdminUserResponse(user db.User) adminUserResponse {
+	rsp := adminUserResponse{
+		userResponse: newUserResponse(user),
+		Role:         user.Role,
+	}
+	if user.DeletedAt.Valid {
+		rsp.DeletedAt = &user.DeletedAt.Time
+	}
+	return rsp
+}
+
+type adminGetUserRequest struct {
+	Username string `uri:"username" binding:"required,username"`
+}
+
+func (server *Server) adminGetUser(ctx *gin.Context) {
+	if !requireAdmin(ctx, "look up users") {
+		return
+	}
+
+	var req adminGetUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var query includeDeletedRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	getUser := server.store.GetUser
+	if query.IncludeDeleted {
+		getUser = server.store.GetUserIncludeDeleted
+	}
+	user, err := getUser(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newAdminUserResponse(user))
+}
+
+type adminListAccountsRequest struct {
+	includeDeletedRequest
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) adminListAccounts(ctx *gin.Context) {
+	if !requireAdmin(ctx, "look up accounts") {
+		return
+	}
+
+	var uri adminGetUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req adminListAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	var accounts []db.Account
+	var err error
+	if req.IncludeDeleted {
+		accounts, err = server.store.ListAccountsIncludeDeleted(ctx, db.ListAccountsIncludeDeletedParams{
+			Owner:  uri.Username,
+			Limit:  req.PageSize,
+			Offset: (req.PageID - 1) * req.PageSize,
+		})
+	} else {
+		accounts, err = server.store.ListAccounts(ctx, db.ListAccountsParams{
+			Owner:  uri.Username,
+			Limit:  req.PageSize,
+			Offset: (req.PageID - 1) * req.PageSize,
+		})
+	}
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, accounts)
+}
+
+func (server *Server) adminGetAccount(ctx *gin.Context) {
+	if !requireAdmin(ctx, "look up accounts") {
+		return
+	}
+
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var query includeDeletedRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	getAccount := server.store.GetAccount
+	if query.IncludeDeleted {
+		getAccount = server.store.GetAccountIncludeDeleted
+	}
+	account, err := getAccount(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, account)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+// The tenant routes let the admins, who run the bank for every tenant, open it to more tenants
+// and set their terms.
+
+// tenantErrStatus maps the errors of the tenants to a response status.
+func tenantErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, admin.ErrInvalidTenant):
+		return http.StatusBadRequest
+	case db.ErrorCode(err) == db.UniqueViolation:
+		return http.StatusForbidden
+	}
+	return errStatus(err)
+}
+
+// listTenants lists the tenants with their terms.
+func (server *Server) listTenants(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the tenants") {
+		return
+	}
+
+	tenants, err := server.store.ListTenants(ctx)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, tenants)
+}
+
+type createTenantRequest struct {
+	ID         string   `json:"id" binding:"required,alphanum,lowercase,max=32"`
+	Name       string   `json:"name" binding:"required,max=100"`
+	Currencies []string `json:"currencies" binding:"required,min=1,dive,currency"`
+	// MaxTransferAmount caps the amount of a transfer, 0 for no cap.
+	MaxTransferAmount int64  `json:"max_transfer_amount" binding:"min=0"`
+	EmailSenderName   string `json:"email_sender_name" binding:"max=100"`
+	SupportEmail      string `json:"support_email" binding:"omitempty,email"`
+	Reason            string `json:"reason" binding:"required,max=500"`
+}
+
+// createTenant opens the bank to a tenant, which users can sign up to right away.
+func (server *Server) createTenant(ctx *gin.Context) {
+	if !requireAdmin(ctx, "create tenants") {
+		return
+	}
+
+	var req createTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	tenant, err := operator.CreateTenant(ctx, admin.CreateTenantParams{
+		ID:                req.ID,
+		Name:              req.Name,
+		Currencies:        req.Currencies,
+		MaxTransferAmount: req.MaxTransferAmount,
+		EmailSenderName:   req.EmailSenderName,
+		SupportEmail:      req.SupportEmail,
+		Reason:            req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(tenantErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, tenant)
+}
+
+type tenantURI struct {
+	ID string `uri:"id" binding:"required,alphanum,lowercase,max=32"`
+}
+
+type updateTenantRequest struct {
+	Name              *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Currencies        []string `json:"currencies" binding:"omitempty,min=1,dive,currency"`
+	MaxTransferAmount *int64   `json:"max_transfer_amount" binding:"omitempty,min=0"`
+	EmailSenderName   *string  `json:"email_sender_name" binding:"omitempty,max=100"`
+	SupportEmail      *string  `json:"support_email" binding:"omitempty,email"`
+	Reason            string   `json:"reason" binding:"required,max=500"`
+}
+
+// updateTenant changes the terms of a tenant set in the request, and keeps the others.
+func (server *Server) updateTenant(ctx *gin.Context) {
+	if !requireAdmin(ctx, "change tenants") {
+		return
+	}
+
+	var uri tenantURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req updateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	tenant, err := operator.UpdateTenant(ctx, admin.UpdateTenantParams{
+		ID:                uri.ID,
+		Name:              req.Name,
+		Currencies:        req.Currencies,
+		MaxTransferAmount: req.MaxTransferAmount,
+		EmailSenderName:   req.EmailSenderName,
+		SupportEmail:      req.SupportEmail,
+		Reason:            req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(tenantErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, tenant)
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTenant() db.Tenant {
+	return db.Tenant{
+		ID:                "acme",
+		Name:              "Acme Bank",
+		Currencies:        []string{util.USD, util.EUR},
+		MaxTransferAmount: 500000,
+		SupportEmail:      "help@acme.com",
+	}
+}
+
+func TestCreateTenantAPI(t *testing.T) {
+	tenant := randomTenant()
+	body := gin.H{
+		"id":                  tenant.ID,
+		"name":                tenant.Name,
+		"currencies":          tenant.Currencies,
+		"max_transfer_amount": tenant.MaxTransferAmount,
+		"support_email":       tenant.SupportEmail,
+		"reason":              "signed the white label contract",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTenantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTenantTxParams) (db.Tenant, error) {
+						require.Equal(t, tenant.ID, arg.ID)
+						require.Equal(t, tenant.Currencies, arg.Currencies)
+						require.Equal(t, tenant.MaxTransferAmount, arg.MaxTransferAmount)
+						require.Equal(t, admin.ActionCreateTenant, arg.Audit.Action)
+						return tenant, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.Tenant
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, tenant.ID, rsp.ID)
+			},
+		},
+		{
+			name: "Duplicate",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTenantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Tenant{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedCurrency",
+			body: gin.H{
+				"id":         tenant.ID,
+				"name":       tenant.Name,
+				"currencies": []string{"XYZ"},
+				"reason":     "signed the white label contract",
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTenantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTenantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/admin/tenants", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateTenantAPI(t *testing.T) {
+	tenant := randomTenant()
+
+	testCases := []struct {
+		name          string
+		id            string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   tenant.ID,
+			body: gin.H{
+				"max_transfer_amount": 0,
+				"reason":              "lifted the cap",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(tenant.ID)).Times(1).Return(tenant, nil)
+				store.EXPECT().
+					UpdateTenantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTenantTxParams) (db.Tenant, error) {
+						require.True(t, arg.MaxTransferAmount.Valid)
+						require.Zero(t, arg.MaxTransferAmount.Int64)
+						require.False(t, arg.Name.Valid)
+						require.Nil(t, arg.Currencies)
+						updated := tenant
+						updated.MaxTransferAmount = 0
+						return updated, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.Tenant
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Zero(t, rsp.MaxTransferAmount)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   tenant.ID,
+			body: gin.H{"name": "Acme", "reason": "rebranding"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(tenant.ID)).Times(1).Return(db.Tenant{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTenantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   "Acme",
+			body: gin.H{"name": "Acme", "reason": "rebranding"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPut, "/admin/tenants/"+tc.id, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
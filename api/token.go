@@ -1,16 +1,23 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
 	"github.com/gin-gonic/gin"
 )
 
 type renewAccessTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
+	// DeviceFingerprint is the fingerprint the login sent, none when it sent none.
+	DeviceFingerprint string `json:"device_fingerprint" binding:"omitempty,max=128"`
+	// VerificationCode is the code emailed to trust the device, which the first renewal from an
+	// untrusted device asks for.
+	VerificationCode string `json:"verification_code" binding:"omitempty,len=6,numeric"`
 }
 
 type renewAccessTokenResponse struct {
@@ -23,54 +30,66 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 
 	// check user is existed and check password
 	session, err := server.store.GetSession(ctx, refreshPayload.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 
 	if session.IsBlocked {
 		err = fmt.Errorf("Blocked session !")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 
 	if session.Username != refreshPayload.Username {
 		err = fmt.Errorf("Incorrect username !")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 
 	if session.RefreshToken != req.RefreshToken {
 		err = fmt.Errorf("Mismatched token !")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 
 	if time.Now().After(session.ExpiresAt) {
 		err = fmt.Errorf("Expired session token !")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 
-	// return renewAccessTokenResponse
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(refreshPayload.Username, server.config.AccessTokenDuration)
+	fingerprint := db.DeviceFingerprint(req.DeviceFingerprint, ctx.Request.UserAgent())
+	if session.DeviceID.Valid {
+		if !server.checkDevice(ctx, session, fingerprint, req.VerificationCode) {
+			return
+		}
+	} else if !server.bindSessionDevice(ctx, session, fingerprint, req.VerificationCode) {
+		return
+	}
+
+	// the access token keeps the authentication of the login, so that renewing it doesn't step up
+	accessToken, accessPayload, err := server.tokenMaker.CreateTokenAuthenticatedAt(refreshPayload.Username, refreshPayload.Role, refreshPayload.Tenant, refreshPayload.Locale,
+		refreshPayload.AuthTime, refreshPayload.ACR, server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 
 	rsp := renewAccessTokenResponse{
@@ -78,5 +97,5 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		AccessTokenExpiresAt: accessPayload.ExpiredAt,
 	}
 
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
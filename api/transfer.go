@@ -1,29 +1,71 @@
 package api
 
 import (
-	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/ratelimit"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/gin-gonic/gin"
 )
 
 type transferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64 `json:"to_account_id" binding:"omitempty,min=1"`
+	// ToAccountNumber is the account number the transfer goes to, instead of its id.
+	ToAccountNumber string `json:"to_account_number" binding:"max=40"`
+	Amount          int64  `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0,amount=Currency"`
+	// AmountDecimal is the amount in units of the currency, e.g. "12.34", instead of amount.
+	AmountDecimal string `json:"amount_decimal" binding:"omitempty,excluded_with=Amount,decimal_amount=Currency"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	// ToCurrency is the currency of the account the transfer goes to, when it isn't currency. The
+	// amount is then converted at the latest exchange rates.
+	ToCurrency string `json:"to_currency" binding:"omitempty,currency"`
+	Memo       string `json:"memo" binding:"max=140"`
+}
+
+// errExchangeHeld is returned for a transfer between currencies the fraud rules flag, since the
+// review would run it later, at other rates.
+var errExchangeHeld = errors.New("a transfer between currencies can't wait for a review, send it in the currency of the payee")
+
+// transferResponse is a transfer with its conversion, when it went between currencies.
+type transferResponse struct {
+	db.TransferTxResult
+	Conversion *fx.Conversion `json:"conversion,omitempty"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if !bindAmount(ctx, &req.Amount, req.AmountDecimal, req.Currency) {
 		return
 	}
+	if !server.checkAmountStepUp(ctx, req.Currency, req.Amount) {
+		return
+	}
+	if (req.ToAccountID == 0) == (req.ToAccountNumber == "") {
+		err := errors.New("a transfer needs either a to_account_id or a to_account_number")
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	if req.ToAccountNumber != "" {
+		account, ok := server.accountByNumber(ctx, req.ToAccountNumber)
+		if !ok {
+			return
+		}
+		req.ToAccountID = account.ID
+	}
 
 	fromAccount, valid := server.validateAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
@@ -32,44 +74,122 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if fromAccount.Owner != payload.Username {
 		err := errors.New("account is not belongs to authentication user")
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
 	}
 
-	_, valid = server.validateAccount(ctx, req.ToAccountID, req.Currency)
-
+	toCurrency := req.Currency
+	if req.ToCurrency != "" {
+		toCurrency = req.ToCurrency
+	}
+	toAccount, valid := server.validateAccount(ctx, req.ToAccountID, toCurrency)
 	if !valid {
 		return
 	}
+	var conversion *fx.Conversion
+	if toCurrency != req.Currency {
+		converted, err := server.rates.Convert(ctx, req.Amount, req.Currency, toCurrency)
+		if err != nil {
+			ctx.JSON(exchangeErrStatus(err), errResponse(ctx, err))
+			return
+		}
+		if converted.ToAmount <= 0 {
+			err := fmt.Errorf("%d %s is too little to convert into %s", req.Amount, req.Currency, toCurrency)
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
+		}
+		conversion = &converted
+	}
+
+	limit, err := ratelimit.AllowTransfer(ctx, server.transfers, fromAccount.ID)
+	if err != nil {
+		ratelimit.SetHeaders(ctx.Writer.Header(), limit)
+		ctx.JSON(http.StatusTooManyRequests, errResponse(ctx, err))
+		return
+	}
+
+	verdict, err := server.fraud.Evaluate(ctx, fraud.Transfer{
+		Username:    payload.Username,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      req.Amount,
+		Country:     server.clientCountry(ctx),
+		Time:        time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if verdict.Decision != fraud.Allow {
+		if conversion != nil {
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, errExchangeHeld))
+			return
+		}
+		server.holdTransfer(ctx, req, payload.Username, verdict)
+		return
+	}
 
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
 	arg := db.TransferTxParams{
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
 		Amount:        req.Amount,
+		Memo:          req.Memo,
+		OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:     worker.NewAccountAlertTask,
+		RewardTask:    worker.NewAccrueRewardsTask,
+	}
+	if conversion != nil {
+		arg.Exchange = &db.Exchange{
+			FromCurrency: conversion.FromCurrency,
+			ToCurrency:   conversion.ToCurrency,
+			ToAmount:     conversion.ToAmount,
+		}
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		if errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrFundsHeld) {
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
+	metrics.ObserveTransfer(req.Currency, req.Amount)
 
-	ctx.JSON(http.StatusOK, result)
+	envelope.JSON(ctx, http.StatusOK, transferResponse{TransferTxResult: result, Conversion: conversion})
 }
 
 func (server *Server) validateAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 			return account, false
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return account, false
+	}
+	// the accounts of another tenant are out of sight, but those of the bank are everyone's
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.TenantID != payload.Tenant && !db.IsBankOwner(account.Owner) {
+		err := fmt.Errorf("account %v not found", accountID)
+		ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 		return account, false
 	}
 
 	if account.Currency != currency {
 		err := fmt.Errorf("accouont %v mismatched: %v vs %v", accountID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return account, false
 	}
 
@@ -0,0 +1,382 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// The transfer review routes serve the transfers the fraud rules didn't let through. A transfer
+// that needs a one-time code runs once its owner confirms it with the code emailed to them, and
+// a held one once an admin approves it.
+
+var (
+	errWrongOTP   = errors.New("wrong one-time code")
+	errOTPExpired = errors.New("one-time code expired")
+)
+
+// transferReviewResponse is a review without the hash of its one-time code.
+type transferReviewResponse struct {
+	ID            int64              `json:"id"`
+	Username      string             `json:"username"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        int64              `json:"amount"`
+	Currency      string             `json:"currency"`
+	Memo          string             `json:"memo"`
+	Decision      string             `json:"decision"`
+	Rules         []string           `json:"rules"`
+	Status        string             `json:"status"`
+	ReviewedBy    string             `json:"reviewed_by"`
+	Note          string             `json:"note"`
+	TransferID    pgtype.Int8        `json:"transfer_id"`
+	CreatedAt     time.Time          `json:"created_at"`
+	ReviewedAt    pgtype.Timestamptz `json:"reviewed_at"`
+}
+
+func newTransferReviewResponse(review db.TransferReview) transferReviewResponse {
+	return transferReviewResponse{
+		ID:            review.ID,
+		Username:      review.Username,
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        review.Amount,
+		Currency:      review.Currency,
+		Memo:          review.Memo,
+		Decision:      review.Decision,
+		Rules:         review.Rules,
+		Status:        review.Status,
+		ReviewedBy:    review.ReviewedBy,
+		Note:          review.Note,
+		TransferID:    review.TransferID,
+		CreatedAt:     review.CreatedAt,
+		ReviewedAt:    review.ReviewedAt,
+	}
+}
+
+type closeTransferReviewResponse struct {
+	Review   transferReviewResponse `json:"review"`
+	Transfer *db.TransferTxResult   `json:"transfer,omitempty"`
+}
+
+func newCloseTransferReviewResponse(result db.CloseTransferReviewTxResult) closeTransferReviewResponse {
+	return closeTransferReviewResponse{
+		Review:   newTransferReviewResponse(result.Review),
+		Transfer: result.Transfer,
+	}
+}
+
+// reviewErrStatus maps the errors of the transfer reviews, and of the transfers an approval runs,
+// to a response status.
+func reviewErrStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrReviewClosed), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrFundsHeld):
+		return http.StatusForbidden
+	case errors.Is(err, db.ErrInvalidReviewStatus):
+		return http.StatusBadRequest
+	}
+	return operatorErrStatus(err)
+}
+
+// clientCountry is the country of the client as resolved by the proxy in front of the server in
+// FRAUD_COUNTRY_HEADER, empty when the header isn't configured or sent.
+func (server *Server) clientCountry(ctx *gin.Context) string {
+	if server.config.FraudCountryHeader == "" {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(ctx.GetHeader(server.config.FraudCountryHeader)))
+}
+
+// checkFraud runs the fraud rules, the screening among them, on a payment that isn't a transfer,
+// e.g. of a payment request or an authorization hold, which can't wait for a review: a payment
+// the rules don't allow is refused with a 403. It answers the request and returns false then.
+func (server *Server) checkFraud(ctx *gin.Context, fromAccount, toAccount db.Account, amount int64) bool {
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	verdict, err := server.fraud.Evaluate(ctx, fraud.Transfer{
+		Username:    payload.Username,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      amount,
+		Country:     server.clientCountry(ctx),
+		Time:        time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	if verdict.Decision != fraud.Allow {
+		err := fmt.Errorf("payment needs a review (%s), send it with POST /transfers", strings.Join(verdict.Rules, ", "))
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+// holdTransfer holds a transfer the fraud rules flagged for a review, along with the email of its
+// one-time code when the rules asked for one. It answers 202: the transfer didn't run yet.
+func (server *Server) holdTransfer(ctx *gin.Context, req transferRequest, username string, verdict fraud.Verdict) {
+	arg := db.CreateTransferReviewTxParams{
+		CreateTransferReviewParams: db.CreateTransferReviewParams{
+			Username:      username,
+			FromAccountID: req.FromAccountID,
+			ToAccountID:   req.ToAccountID,
+			Amount:        req.Amount,
+			Currency:      req.Currency,
+			Memo:          req.Memo,
+			Decision:      string(verdict.Decision),
+			Rules:         verdict.Rules,
+		},
+	}
+	if verdict.Decision == fraud.RequireOTP {
+		code, hash, err := fraud.NewOTP()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+			return
+		}
+		arg.OtpHash = hash
+		arg.AfterCreate = func(review db.TransferReview) ([]db.CreateOutboxTaskParams, error) {
+			task, err := worker.NewSendTransferOTPTask(&worker.PayloadSendTransferOTP{
+				Username: username,
+				ReviewID: review.ID,
+				Code:     code,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return []db.CreateOutboxTaskParams{task}, nil
+		}
+	}
+
+	review, err := server.store.CreateTransferReviewTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	log.Info().Int64("review id", review.ID).Str("decision", review.Decision).
+		Strs("rules", review.Rules).Str("username", username).Msg("transfer held for review")
+
+	envelope.JSON(ctx, http.StatusAccepted, newTransferReviewResponse(review))
+}
+
+type transferReviewURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getTransferReview returns a review to the user whose transfer it holds, or to an admin.
+func (server *Server) getTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	review, err := server.store.GetTransferReview(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(reviewErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if review.Username != payload.Username && payload.Role != util.AdminRole {
+		err := errors.New("transfer review doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newTransferReviewResponse(review))
+}
+
+type confirmTransferRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// confirmTransfer runs a transfer waiting for its one-time code. An expired code, or
+// fraud.MaxOTPAttempts wrong ones, reject the transfer, which the user then sends again.
+func (server *Server) confirmTransfer(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req confirmTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	review, err := server.store.GetTransferReview(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(reviewErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if review.Username != payload.Username {
+		err := errors.New("transfer review doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	if review.Decision != string(fraud.RequireOTP) {
+		err := errors.New("transfer is held until an admin reviews it")
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+		return
+	}
+	if review.Status != db.ReviewPending {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, db.ErrReviewClosed))
+		return
+	}
+
+	if time.Since(review.CreatedAt) > fraud.OTPValidity {
+		if !server.rejectTransfer(ctx, review, payload.Username, "the one-time code expired") {
+			return
+		}
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errOTPExpired))
+		return
+	}
+	if !fraud.CheckOTP(req.Code, review.OtpHash) {
+		review, err = server.store.AddTransferReviewOTPAttempt(ctx, review.ID)
+		if err != nil {
+			ctx.JSON(errStatus(err), errResponse(ctx, err))
+			return
+		}
+		if review.OtpAttempts >= fraud.MaxOTPAttempts &&
+			!server.rejectTransfer(ctx, review, payload.Username, "too many wrong one-time codes") {
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, errWrongOTP))
+		return
+	}
+
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        review.Amount,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	result, err := server.store.CloseTransferReviewTx(ctx, db.CloseTransferReviewTxParams{
+		ID:          review.ID,
+		Status:      db.ReviewApproved,
+		ReviewedBy:  payload.Username,
+		Note:        "confirmed with the one-time code",
+		OutboxTasks: []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:   worker.NewAccountAlertTask,
+		RewardTask:  worker.NewAccrueRewardsTask,
+	})
+	if err != nil {
+		ctx.JSON(reviewErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	metrics.ObserveTransfer(review.Currency, review.Amount)
+
+	envelope.JSON(ctx, http.StatusOK, newCloseTransferReviewResponse(result))
+}
+
+// rejectTransfer rejects a review whose one-time code can't be used anymore. It writes the error
+// response and returns false when it fails.
+func (server *Server) rejectTransfer(ctx *gin.Context, review db.TransferReview, username, note string) bool {
+	_, err := server.store.CloseTransferReviewTx(ctx, db.CloseTransferReviewTxParams{
+		ID:         review.ID,
+		Status:     db.ReviewRejected,
+		ReviewedBy: username,
+		Note:       note,
+	})
+	// closed meanwhile by another request, which is as good
+	if err != nil && !errors.Is(err, db.ErrReviewClosed) {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return false
+	}
+	return true
+}
+
+type listTransferReviewsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Decision string `form:"decision" binding:"omitempty,oneof=require_otp hold"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listTransferReviews lists the transfer reviews for the admins, oldest first, e.g. the pending
+// held ones, which are the review queue.
+func (server *Server) listTransferReviews(ctx *gin.Context) {
+	if !requireAdmin(ctx, "list the transfer reviews") {
+		return
+	}
+
+	var req listTransferReviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	reviews, err := server.store.ListTransferReviews(ctx, db.ListTransferReviewsParams{
+		Status:   pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		Decision: pgtype.Text{String: req.Decision, Valid: req.Decision != ""},
+		Limit:    req.PageSize,
+		Offset:   (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]transferReviewResponse, len(reviews))
+	for i, review := range reviews {
+		rsp[i] = newTransferReviewResponse(review)
+	}
+	envelope.JSON(ctx, http.StatusOK, rsp)
+}
+
+type reviewTransferRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"required"`
+}
+
+// reviewTransfer approves a pending transfer review, which runs the transfer, or rejects it.
+func (server *Server) reviewTransfer(ctx *gin.Context) {
+	if !requireAdmin(ctx, "review transfers") {
+		return
+	}
+
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req reviewTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	result, err := operator.ReviewTransfer(ctx, uri.ID, req.Status, req.Note)
+	if err != nil {
+		ctx.JSON(reviewErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	if result.Transfer != nil {
+		metrics.ObserveTransfer(result.Review.Currency, result.Review.Amount)
+	}
+
+	envelope.JSON(ctx, http.StatusOK, newCloseTransferReviewResponse(result))
+}
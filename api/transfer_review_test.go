@@ -0,0 +1,576 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferFlagged(t *testing.T) {
+	user, _ := randomUser(t)
+	account1 := randomAccount(user.Username)
+	account2 := randomAccount(util.RandomOwnerName())
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Allowed",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Held",
+			amount: 5000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().
+					CreateTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTransferReviewTxParams) (db.TransferReview, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, string(fraud.Hold), arg.Decision)
+						require.Equal(t, []string{"new_payee_large_amount"}, arg.Rules)
+						require.Empty(t, arg.OtpHash)
+						require.Nil(t, arg.AfterCreate)
+						return db.TransferReview{ID: 1, Username: arg.Username, Amount: arg.Amount, Decision: arg.Decision, Rules: arg.Rules, Status: db.ReviewPending}, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp transferReviewResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, string(fraud.Hold), rsp.Decision)
+				require.Equal(t, db.ReviewPending, rsp.Status)
+			},
+		},
+		{
+			name:   "RequireOTP",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().
+					CreateTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTransferReviewTxParams) (db.TransferReview, error) {
+						require.Equal(t, string(fraud.RequireOTP), arg.Decision)
+						require.Equal(t, []string{"velocity"}, arg.Rules)
+						require.NotEmpty(t, arg.OtpHash)
+						require.NotNil(t, arg.AfterCreate)
+
+						review := db.TransferReview{ID: 1, Username: arg.Username, Decision: arg.Decision, OtpHash: arg.OtpHash, Status: db.ReviewPending}
+						tasks, err := arg.AfterCreate(review)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskSendTransferOTP, tasks[0].TaskType)
+
+						var payload worker.PayloadSendTransferOTP
+						require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+						require.Equal(t, review.ID, payload.ReviewID)
+						require.True(t, fraud.CheckOTP(payload.Code, arg.OtpHash))
+						return review, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "otp_hash")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.fraud = fraud.NewEngine(fraud.NewVelocityRule(store, 3, time.Hour), fraud.NewPayeeRule(store, 1000))
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          tc.amount,
+				"currency":        util.USD,
+			})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomTransferReview(username, decision string) (db.TransferReview, string) {
+	code, hash, _ := fraud.NewOTP()
+	return db.TransferReview{
+		ID:            util.RandomInt(1, 1000),
+		Username:      username,
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1001, 2000),
+		Amount:        util.RandomInt(1, 1000),
+		Currency:      util.USD,
+		Decision:      decision,
+		Rules:         []string{"velocity"},
+		Status:        db.ReviewPending,
+		OtpHash:       hash,
+		CreatedAt:     time.Now(),
+	}, code
+}
+
+func TestConfirmTransferAPI(t *testing.T) {
+	username := util.RandomOwnerName()
+	review, code := randomTransferReview(username, string(fraud.RequireOTP))
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	testCases := []struct {
+		name          string
+		review        func() db.TransferReview
+		code          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			code:     code,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+						require.Equal(t, review.ID, arg.ID)
+						require.Equal(t, db.ReviewApproved, arg.Status)
+						require.Equal(t, username, arg.ReviewedBy)
+						require.Len(t, arg.OutboxTasks, 1)
+						require.NotNil(t, arg.AlertTask)
+						require.Nil(t, arg.Audit)
+
+						closed := review
+						closed.Status = db.ReviewApproved
+						closed.TransferID = pgtype.Int8{Int64: 1, Valid: true}
+						return db.CloseTransferReviewTxResult{
+							Review:   closed,
+							Transfer: &db.TransferTxResult{Transfer: db.Transfer{ID: 1, Amount: review.Amount}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp closeTransferReviewResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.ReviewApproved, rsp.Review.Status)
+				require.NotNil(t, rsp.Transfer)
+				require.Equal(t, review.Amount, rsp.Transfer.Transfer.Amount)
+			},
+		},
+		{
+			name:     "WrongCode",
+			code:     wrongCode,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				attempted := review
+				attempted.OtpAttempts = 1
+				store.EXPECT().AddTransferReviewOTPAttempt(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(attempted, nil)
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "TooManyWrongCodes",
+			code:     wrongCode,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				attempted := review
+				attempted.OtpAttempts = fraud.MaxOTPAttempts
+				store.EXPECT().AddTransferReviewOTPAttempt(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(attempted, nil)
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+						require.Equal(t, db.ReviewRejected, arg.Status)
+						return db.CloseTransferReviewTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			review: func() db.TransferReview {
+				expired := review
+				expired.CreatedAt = time.Now().Add(-fraud.OTPValidity - time.Minute)
+				return expired
+			},
+			code:     code,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+						require.Equal(t, db.ReviewRejected, arg.Status)
+						return db.CloseTransferReviewTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "HeldForAdmin",
+			review: func() db.TransferReview {
+				held := review
+				held.Decision = string(fraud.Hold)
+				return held
+			},
+			code:     code,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Closed",
+			review: func() db.TransferReview {
+				closed := review
+				closed.Status = db.ReviewRejected
+				return closed
+			},
+			code:     code,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			code:     code,
+			username: util.RandomOwnerName(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountFrozen",
+			code:     code,
+			username: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CloseTransferReviewTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			current := review
+			if tc.review != nil {
+				current = tc.review()
+			}
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(current, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"code": tc.code})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/transfer_reviews/%d/confirm", review.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTransferReviewAPI(t *testing.T) {
+	username := util.RandomOwnerName()
+	review, _ := randomTransferReview(username, string(fraud.Hold))
+
+	testCases := []struct {
+		name     string
+		username string
+		role     string
+		status   int
+	}{
+		{name: "Owner", username: username, role: util.DepositorRole, status: http.StatusOK},
+		{name: "Admin", username: "admin", role: util.AdminRole, status: http.StatusOK},
+		{name: "OtherUser", username: util.RandomOwnerName(), role: util.DepositorRole, status: http.StatusUnauthorized},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/transfer_reviews/%d", review.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+			require.NotContains(t, recorder.Body.String(), review.OtpHash)
+		})
+	}
+}
+
+func TestListTransferReviewsAPI(t *testing.T) {
+	review, _ := randomTransferReview(util.RandomOwnerName(), string(fraud.Hold))
+
+	testCases := []struct {
+		name          string
+		query         string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "status=pending&decision=hold&page_id=1&page_size=5",
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListTransferReviewsParams{
+					Status:   pgtype.Text{String: db.ReviewPending, Valid: true},
+					Decision: pgtype.Text{String: string(fraud.Hold), Valid: true},
+					Limit:    5,
+					Offset:   0,
+				}
+				store.EXPECT().ListTransferReviews(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.TransferReview{review}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []transferReviewResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Len(t, rsp, 1)
+				require.Equal(t, review.ID, rsp[0].ID)
+			},
+		},
+		{
+			name:  "InvalidDecision",
+			query: "decision=block&page_id=1&page_size=5",
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTransferReviews(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: "page_id=1&page_size=5",
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTransferReviews(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/transfer_reviews?"+tc.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReviewTransferAPI(t *testing.T) {
+	review, _ := randomTransferReview(util.RandomOwnerName(), string(fraud.Hold))
+	body := gin.H{"status": db.ReviewApproved, "note": "called the owner, who confirmed"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().
+					CloseTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+						require.Equal(t, db.ReviewApproved, arg.Status)
+						require.Equal(t, "admin", arg.ReviewedBy)
+						require.NotNil(t, arg.Audit)
+						require.Equal(t, admin.ActionReviewTransfer, arg.Audit.Action)
+
+						closed := review
+						closed.Status = db.ReviewApproved
+						return db.CloseTransferReviewTxResult{Review: closed, Transfer: &db.TransferTxResult{}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp closeTransferReviewResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, db.ReviewApproved, rsp.Review.Status)
+			},
+		},
+		{
+			name: "Closed",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CloseTransferReviewTxResult{}, db.ErrReviewClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: body,
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferReview{}, db.ErrRecordNotFound)
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidStatus",
+			body: gin.H{"status": db.ReviewPending, "note": "not sure yet"},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: body,
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/admin/transfer_reviews/%d/status", review.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,22 +2,54 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	mockdb "github.com/backendmaster/simple_bank/db/mock"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/testfixtures"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
+type eqTransferTxParamsMatcher struct {
+	arg db.TransferTxParams
+}
+
+// Matches compares the params without AlertTask and RewardTask, since funcs are never equal, but
+// requires them to be set.
+func (e eqTransferTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.TransferTxParams)
+	if !ok || arg.AlertTask == nil || arg.RewardTask == nil {
+		return false
+	}
+	arg.AlertTask = nil
+	arg.RewardTask = nil
+	return reflect.DeepEqual(e.arg, arg)
+}
+
+func (e eqTransferTxParamsMatcher) String() string {
+	return fmt.Sprintf("match params %v with an alert task and a reward task", e.arg)
+}
+
+func EqTransferTxParamsMatcher(arg db.TransferTxParams) gomock.Matcher {
+	return eqTransferTxParamsMatcher{arg}
+}
+
 func TestTransferAPI(t *testing.T) {
 	amount := int64(10)
 
@@ -25,12 +57,12 @@ func TestTransferAPI(t *testing.T) {
 	user2, _ := randomUser(t)
 	user3, _ := randomUser(t)
 
-	sameCurrencyAccount1 := randomAccount(user1.Username)
-	sameCurrencyAccount2 := randomAccount(user2.Username)
-	differentCurrencyAccount := randomAccount(user3.Username)
-	sameCurrencyAccount1.Currency = util.USD
-	sameCurrencyAccount2.Currency = util.USD
-	differentCurrencyAccount.Currency = util.EUR
+	sameCurrencyAccount1 := randomAccount(user1.Username, testfixtures.WithCurrency(util.USD))
+	sameCurrencyAccount2 := randomAccount(user2.Username, testfixtures.WithCurrency(util.USD))
+	differentCurrencyAccount := randomAccount(user3.Username, testfixtures.WithCurrency(util.EUR))
+	sameCurrencyAccount2.Number = "SB06123456789012"
+	otherTenantAccount := randomAccount(user3.Username, testfixtures.WithCurrency(util.USD))
+	otherTenantAccount.TenantID = "acme"
 
 	testCase := []struct {
 		name          string
@@ -48,30 +80,221 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
+				notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+					FromAccountID: sameCurrencyAccount1.ID,
+					ToAccountID:   sameCurrencyAccount2.ID,
+					Amount:        amount,
+				})
+				require.NoError(t, err)
 				arg := db.TransferTxParams{
 					FromAccountID: sameCurrencyAccount1.ID,
 					ToAccountID:   sameCurrencyAccount2.ID,
 					Amount:        amount,
+					OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
+				}
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), EqTransferTxParamsMatcher(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AmountDecimal",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount_decimal":  "12.3",
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
+				notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+					FromAccountID: sameCurrencyAccount1.ID,
+					ToAccountID:   sameCurrencyAccount2.ID,
+					Amount:        1230,
+				})
+				require.NoError(t, err)
+				arg := db.TransferTxParams{
+					FromAccountID: sameCurrencyAccount1.ID,
+					ToAccountID:   sameCurrencyAccount2.ID,
+					Amount:        1230,
+					OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
 				}
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
 				store.EXPECT().
-					TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+					TransferTx(gomock.Any(), EqTransferTxParamsMatcher(arg)).Times(1)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "AmountDecimal Too Precise",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount_decimal":  "12.345",
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				var rsp envelope.Envelope
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, []envelope.Error{{
+					Field:   "amount_decimal",
+					Message: "amount_decimal must be a positive amount with at most the decimal places of the currency",
+				}}, rsp.Errors)
+			},
+		},
+		{
+			name: "Amount And AmountDecimal",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount":          amount,
+				"amount_decimal":  "0.10",
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "No Amount",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ToAccountNumber",
+			body: gin.H{
+				"from_account_id":   sameCurrencyAccount1.ID,
+				"to_account_number": "sb06 1234 5678 9012",
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(db.GetAccountByNumberParams{
+					Number:   sameCurrencyAccount2.Number,
+					TenantID: util.DefaultTenant,
+				})).Times(1).Return(sameCurrencyAccount2, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.Equal(t, sameCurrencyAccount2.ID, arg.ToAccountID)
+						return db.TransferTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAccountNumber",
+			body: gin.H{
+				"from_account_id":   sameCurrencyAccount1.ID,
+				"to_account_number": "SB60123456789012",
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNumberNotFound",
+			body: gin.H{
+				"from_account_id":   sameCurrencyAccount1.ID,
+				"to_account_number": sameCurrencyAccount2.Number,
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BothRecipients",
+			body: gin.H{
+				"from_account_id":   sameCurrencyAccount1.ID,
+				"to_account_id":     sameCurrencyAccount2.ID,
+				"to_account_number": sameCurrencyAccount2.Number,
+				"amount":            amount,
+				"currency":          util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "Invalid json body",
 			body: gin.H{
 				"from_account_id": -1,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -80,6 +303,27 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "Amount Too Large",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount":          util.MaxMajorAmount*100 + 1,
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				var rsp envelope.Envelope
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, []envelope.Error{{Message: "amount is more than an amount in the currency can be", Field: "amount"}}, rsp.Errors)
+			},
+		},
 		{
 			name: "Currency Mismatched of Account1",
 			body: gin.H{
@@ -89,7 +333,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(differentCurrencyAccount.ID)).Times(1).
@@ -109,7 +353,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).
@@ -123,6 +367,135 @@ func TestTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "CrossCurrency",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   differentCurrencyAccount.ID,
+				"amount":          10000,
+				"currency":        util.USD,
+				"to_currency":     util.EUR,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(differentCurrencyAccount.ID)).Times(1).Return(differentCurrencyAccount, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(randomExchangeRates(time.Now()), nil)
+				notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+					FromAccountID: sameCurrencyAccount1.ID,
+					ToAccountID:   differentCurrencyAccount.ID,
+					Amount:        10000,
+				})
+				require.NoError(t, err)
+				arg := db.TransferTxParams{
+					FromAccountID: sameCurrencyAccount1.ID,
+					ToAccountID:   differentCurrencyAccount.ID,
+					Amount:        10000,
+					OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
+					Exchange:      &db.Exchange{FromCurrency: util.USD, ToCurrency: util.EUR, ToAmount: 9240},
+				}
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParamsMatcher(arg)).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp transferResponse
+				requireBodyData(t, recorder.Body, &rsp)
+				require.NotNil(t, rsp.Conversion)
+				require.Equal(t, int64(9240), rsp.Conversion.ToAmount)
+				require.Equal(t, "0.923958237", rsp.Conversion.Rate)
+			},
+		},
+		{
+			name: "CrossCurrency Stale Rates",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   differentCurrencyAccount.ID,
+				"amount":          10000,
+				"currency":        util.USD,
+				"to_currency":     util.EUR,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(differentCurrencyAccount.ID)).Times(1).Return(differentCurrencyAccount, nil)
+				store.EXPECT().
+					ListExchangeRates(gomock.Any()).
+					Times(1).
+					Return(randomExchangeRates(time.Now().Add(-fx.DefaultMaxAge-time.Hour)), nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+		{
+			name: "CrossCurrency Mismatched of Account2",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   differentCurrencyAccount.ID,
+				"amount":          10000,
+				"currency":        util.USD,
+				"to_currency":     util.CAD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(differentCurrencyAccount.ID)).Times(1).Return(differentCurrencyAccount, nil)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Account of Another Tenant",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   otherTenantAccount.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherTenantAccount.ID)).Times(1).Return(otherTenantAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Transfer Limit Exceeded",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrTransferLimitExceeded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Account1 Not Found",
 			body: gin.H{
@@ -132,11 +505,11 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).
-					Return(db.Account{}, sql.ErrNoRows)
+					Return(db.Account{}, db.ErrRecordNotFound)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -152,22 +525,39 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
-				arg := db.TransferTxParams{
-					FromAccountID: sameCurrencyAccount1.ID,
-					ToAccountID:   sameCurrencyAccount2.ID,
-					Amount:        amount,
-				}
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "Account Frozen",
+			body: gin.H{
+				"from_account_id": sameCurrencyAccount1.ID,
+				"to_account_id":   sameCurrencyAccount2.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			addAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(sameCurrencyAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount2.ID)).Times(1).Return(sameCurrencyAccount2, nil)
+				store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "Get Account failed",
 			body: gin.H{
@@ -177,7 +567,7 @@ func TestTransferAPI(t *testing.T) {
 				"currency":        util.USD,
 			},
 			addAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, sameCurrencyAccount1.Owner, util.DepositorRole, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sameCurrencyAccount1.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
@@ -217,3 +607,44 @@ func TestTransferAPI(t *testing.T) {
 	}
 
 }
+
+func TestTransferRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	account1 := randomAccount(user1.Username, testfixtures.WithCurrency(util.USD))
+	account2 := randomAccount(user2.Username, testfixtures.WithCurrency(util.USD))
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(2).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(2).Return(account2, nil)
+	store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Any()).Times(2).Return([]string{}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+
+	server := newTestServer(t, store)
+	server.transfers = ratelimit.NewSlidingMemoryLimiter(1, time.Hour)
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          10,
+		"currency":        util.USD,
+	})
+	require.NoError(t, err)
+
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.Username, util.DepositorRole, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, expected, recorder.Code)
+
+		if expected == http.StatusTooManyRequests {
+			require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+			require.Contains(t, recorder.Body.String(), "at most 1 transfers per 1h")
+		}
+	}
+}
@@ -1,22 +1,34 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/preferences"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type createUserRequest struct {
-	Username string `json:"username" binding:"required,alphanumunicode"`
-	Password string `json:"password" binding:"required,min=6"`
-	FullName string `json:"full_name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Username string `json:"username" binding:"required,username"`
+	Password string `json:"password" binding:"required,password"`
+	FullName string `json:"full_name" binding:"required,full_name"`
+	Email    string `json:"email" binding:"required,user_email"`
+	// ReferralCode is the code of the user who referred the new one, if any.
+	ReferralCode string `json:"referral_code" binding:"omitempty,alphanum,len=8"`
+	// Tenant is the bank the user signs up to, the default one if empty.
+	Tenant string `json:"tenant" binding:"omitempty,alphanum,lowercase,max=32"`
 }
 
 type userResponse struct {
@@ -25,6 +37,7 @@ type userResponse struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Tenant            string    `json:"tenant"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -34,6 +47,7 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		Tenant:            user.TenantID,
 	}
 }
 
@@ -41,41 +55,60 @@ func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	hashedPassword, err := util.HashedPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
-		FullName:       req.FullName,
-		Email:          req.Email,
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			HashedPassword: hashedPassword,
+			FullName:       req.FullName,
+			Email:          req.Email,
+			TenantID:       pgtype.Text{String: req.Tenant, Valid: req.Tenant != ""},
+		},
+		AfterCreate:  worker.AfterCreateUser,
+		Screen:       server.screener.ScreenUser,
+		ReferralCode: strings.ToUpper(req.ReferralCode),
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	txResult, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, errResponse(err))
-				return
-			}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
+			return
+		}
+		// the only foreign key of a user is its tenant
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			err = fmt.Errorf("unknown tenant %q", req.Tenant)
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		if errors.Is(err, db.ErrInvalidReferralCode) {
+			ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
-	rsp := newUserResponse(user)
-	ctx.JSON(http.StatusOK, rsp)
+	metrics.ObserveSignup()
+	rsp := newUserResponse(txResult.User)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
 
 type loginUserRequest struct {
-	Username string `json:"username" binding:"required,alphanumunicode"`
-	Password string `json:"password" binding:"required,min=6"`
+	Username string `json:"username" binding:"required,username"`
+	Password string `json:"password" binding:"required,password"`
+	// DeviceFingerprint identifies the device of the client, which the refresh token is bound to.
+	// Without one, the refresh token is bound to the device of the user agent.
+	DeviceFingerprint string `json:"device_fingerprint" binding:"omitempty,max=128"`
+	// DeviceName names a device logging in for the first time, its user agent if empty.
+	DeviceName string `json:"device_name" binding:"omitempty,max=64"`
 }
 
 type loginUserResponse struct {
@@ -85,6 +118,8 @@ type loginUserResponse struct {
 	RefreshToken          string       `json:"refresh_token"`
 	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
 	User                  userResponse `json:"user_response"`
+	// Device is the device the refresh token is bound to, if the login sent its fingerprint.
+	Device *deviceResponse `json:"device,omitempty"`
 }
 
 func (server *Server) loginUser(ctx *gin.Context) {
@@ -92,57 +127,111 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	// check user is existed and check password
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			metrics.ObserveLoginFailure(metrics.LoginUserNotFound)
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		metrics.ObserveLoginFailure(metrics.LoginWrongPassword)
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
+		return
+	}
+	// a reported login holds the logins until the password is reset, the old one being known
+	resetPending, err := server.store.HasPendingPasswordReset(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+	if resetPending {
+		ctx.JSON(http.StatusForbidden, errResponse(ctx, errPasswordResetRequired))
+		return
+	}
+	history, err := server.store.ListRecentSessions(ctx, db.ListRecentSessionsParams{
+		Username: user.Username,
+		Limit:    fraud.LoginHistorySize,
+	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
 		return
 	}
 	// return loginUserResponse
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, preferences.Locale(user.Preferences), server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, preferences.Locale(user.Preferences), server.config.RefreshTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     refreshPayload.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+	ip := clientIP(ctx)
+	newLoginTask, err := worker.NewSendNotificationTask(&worker.PayloadSendNotification{
+		Username: user.Username,
+		Kind:     notification.KindNewLogin,
+		Data: map[string]string{
+			"client_ip":  ip,
+			"user_agent": ctx.Request.UserAgent(),
+		},
 	})
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	arg := db.CreateSessionTxParams{
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           refreshPayload.ID,
+			Username:     refreshPayload.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ip,
+			IsBlocked:    false,
+			ExpiresAt:    refreshPayload.ExpiredAt,
+			Country:      server.clientCountry(ctx),
+		},
+		Device: db.LoginDevice(user.Username, req.DeviceFingerprint, req.DeviceName, ctx.Request.UserAgent()),
+	}
+	if err := server.flagSuspiciousLogin(&arg, history); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+	if arg.Alert == nil {
+		arg.OutboxTasks = []db.CreateOutboxTaskParams{newLoginTask}
+	}
+	result, err := server.store.CreateSessionTx(ctx, arg)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
 	}
 
 	rsp := loginUserResponse{
-		SessionID:             session.ID,
+		SessionID:             result.Session.ID,
 		AccessToken:           accessToken,
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
 		User:                  newUserResponse(user),
 	}
+	if result.Device != nil {
+		device := newDeviceResponse(*result.Device)
+		rsp.Device = &device
+	}
 
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// userImportsRoute takes the file of an import as the raw body of the request, so its body may be
+// larger than the one of the other routes.
+const userImportsRoute = "/admin/user_imports"
+
+// userImportTimeout is the timeout of userImportsRoute unless ROUTE_TIMEOUTS sets one. Storing a
+// large file takes longer than the default timeout, the import itself runs in the worker.
+const userImportTimeout = time.Minute
+
+// The user import routes let the admins import the users of a legacy system from a file, and
+// follow the import with its report.
+
+// userImportErrStatus maps the errors of the user imports to a response status.
+func userImportErrStatus(err error) int {
+	if errors.Is(err, admin.ErrInvalidUserImport) {
+		return http.StatusBadRequest
+	}
+	return operatorErrStatus(err)
+}
+
+type createUserImportRequest struct {
+	Format string `form:"format" binding:"required,oneof=csv ndjson"`
+	Reason string `form:"reason" binding:"required,max=500"`
+}
+
+// createUserImport stores the file of the body and answers 202 with the import, whose users the
+// worker imports in the background.
+func (server *Server) createUserImport(ctx *gin.Context) {
+	if !requireAdmin(ctx, "import users") {
+		return
+	}
+
+	var req createUserImportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	content, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, errResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	operator, err := server.adminOperator(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
+		return
+	}
+
+	userImport, err := operator.ImportUsers(ctx, admin.ImportUsersParams{
+		Format:  req.Format,
+		Content: content,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		ctx.JSON(userImportErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusAccepted, userImport)
+}
+
+type userImportURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getUserImport returns an import, whose counts tell how far the worker got.
+func (server *Server) getUserImport(ctx *gin.Context) {
+	if !requireAdmin(ctx, "read the user imports") {
+		return
+	}
+
+	var uri userImportURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	userImport, err := server.store.GetUserImport(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(operatorErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	envelope.JSON(ctx, http.StatusOK, userImport)
+}
+
+type listUserImportRowsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=imported failed"`
+	Cursor   string `form:"cursor"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+type listUserImportRowsResponse struct {
+	Rows []db.UserImportRow `json:"rows"`
+}
+
+// listUserImportRows pages through the report of an import in the order of the file, the failed
+// rows with their error when status is failed.
+func (server *Server) listUserImportRows(ctx *gin.Context) {
+	if !requireAdmin(ctx, "read the user imports") {
+		return
+	}
+
+	var uri userImportURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	var req listUserImportRowsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
+		return
+	}
+
+	if _, err := server.store.GetUserImport(ctx, uri.ID); err != nil {
+		ctx.JSON(operatorErrStatus(err), errResponse(ctx, err))
+		return
+	}
+	status := pgtype.Text{String: req.Status, Valid: req.Status != ""}
+	page, err := db.ListUserImportRowsPage(ctx, server.store, uri.ID, status, cursor, req.PageSize)
+	if err != nil {
+		ctx.JSON(errStatus(err), errResponse(ctx, err))
+		return
+	}
+
+	envelope.Page(ctx, http.StatusOK, listUserImportRowsResponse{Rows: page.Items}, page.NextCursor, page.PrevCursor)
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/admin"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserImportAPI(t *testing.T) {
+	content := []byte("username,full_name,email,password\nalice,Alice Liddell,alice@example.com,secret123\n")
+	query := url.Values{"format": {"csv"}, "reason": {"migration of the legacy bank"}}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		body          []byte
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Accepted",
+			query: query,
+			body:  content,
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserImportTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserImportTxParams) (db.UserImport, error) {
+						require.Equal(t, "admin", arg.CreatedBy)
+						require.Equal(t, "csv", arg.Format)
+						require.Equal(t, content, arg.Content)
+						require.Equal(t, admin.ActionImportUsers, arg.Audit.Action)
+
+						userImport := db.UserImport{ID: 1, CreatedBy: arg.CreatedBy, Format: arg.Format, Status: db.UserImportPending}
+						tasks, err := arg.AfterCreate(userImport)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, worker.TaskImportUsers, tasks[0].TaskType)
+						return userImport, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp db.UserImport
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, int64(1), rsp.ID)
+				require.Equal(t, db.UserImportPending, rsp.Status)
+			},
+		},
+		{
+			name:  "EmptyFile",
+			query: query,
+			body:  []byte("\n"),
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserImportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "UnknownFormat",
+			query: url.Values{"format": {"xlsx"}, "reason": {"migration"}},
+			body:  content,
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserImportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "LargerThanMaxBodySize",
+			query: query,
+			// over the MAX_BODY_SIZE of the other routes, under the size of the route
+			body: bytes.Repeat(content, 1000),
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserImportTx(gomock.Any(), gomock.Any()).Times(1).Return(db.UserImport{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:  "TooLarge",
+			query: query,
+			body:  make([]byte, admin.MaxUserImportBytes+1),
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserImportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			query: query,
+			body:  content,
+			role:  util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserImportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.MaxBodySize = 4096
+			server.setupRouter()
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/admin/user_imports?"+tc.query.Encode(), bytes.NewReader(tc.body))
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetUserImportAPI(t *testing.T) {
+	userImport := db.UserImport{ID: 1, CreatedBy: "admin", Format: "csv", Status: db.UserImportCompleted, TotalRows: 3, ImportedRows: 2, FailedRows: 1}
+
+	testCases := []struct {
+		name          string
+		id            int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   userImport.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(userImport, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp db.UserImport
+				requireBodyData(t, recorder.Body, &rsp)
+				require.Equal(t, userImport, rsp)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(db.UserImport{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/admin/user_imports/%d", tc.id), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListUserImportRowsAPI(t *testing.T) {
+	userImport := db.UserImport{ID: 1, CreatedBy: "admin", Format: "csv", Status: db.UserImportCompleted}
+	rows := []db.UserImportRow{
+		{ImportID: 1, Line: 3, Username: "bob", Status: db.UserImportRowFailed, Error: "the username or the email is already taken"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(userImport, nil)
+	store.EXPECT().
+		ListUserImportRowsAfter(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ListUserImportRowsAfterParams) ([]db.UserImportRow, error) {
+			require.Equal(t, userImport.ID, arg.ImportID)
+			require.Equal(t, db.UserImportRowFailed, arg.Status.String)
+			require.True(t, arg.Status.Valid)
+			return rows, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/admin/user_imports/1/rows?status=failed&page_size=5", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", util.AdminRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp listUserImportRowsResponse
+	requireBodyData(t, recorder.Body, &rsp)
+	require.Equal(t, rows, rsp.Rows)
+}
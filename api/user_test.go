@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -13,19 +12,24 @@ import (
 
 	mockdb "github.com/backendmaster/simple_bank/db/mock"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
-type eqCreateUserParamsMatcher struct {
-	arg      db.CreateUserParams
+type eqCreateUserTxParamsMatcher struct {
+	arg      db.CreateUserTxParams
 	password string
+	user     db.User
 }
 
-func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -35,16 +39,29 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 	e.arg.HashedPassword = arg.HashedPassword
+	if !reflect.DeepEqual(e.arg.CreateUserParams, arg.CreateUserParams) || e.arg.ReferralCode != arg.ReferralCode {
+		return false
+	}
 
-	return reflect.DeepEqual(e.arg, arg)
+	// the callbacks can't be compared, the tasks they build for the created user can
+	if arg.AfterCreate == nil || arg.Screen == nil {
+		return false
+	}
+	tasks, err := arg.AfterCreate(e.user)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(e.arg.OutboxTasks, append(arg.OutboxTasks, tasks...))
 }
 
-func (e eqCreateUserParamsMatcher) String() string {
+func (e eqCreateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("match params %v and password %v", e.arg, e.password)
 }
 
-func EqCreateUserParamsMatcher(arg db.CreateUserParams, password string) gomock.Matcher {
-	return eqCreateUserParamsMatcher{arg, password}
+// EqCreateUserTxParamsMatcher matches the params of CreateUserTx with arg, the outbox tasks of arg
+// being the ones expected once the AfterCreate callback has run for user.
+func EqCreateUserTxParamsMatcher(arg db.CreateUserTxParams, password string, user db.User) gomock.Matcher {
+	return eqCreateUserTxParamsMatcher{arg, password, user}
 }
 
 func TestCreateUserAPI(t *testing.T) {
@@ -65,22 +82,169 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.CreateUserParams{
+				verifyEmailTask, err := worker.NewSendVerifyEmailTask(&worker.PayloadSendVerifyEmail{
 					Username: user.Username,
-					FullName: user.FullName,
-					Email:    user.Email,
+				})
+				require.NoError(t, err)
+				arg := db.CreateUserTxParams{
+					CreateUserParams: db.CreateUserParams{
+						Username: user.Username,
+						FullName: user.FullName,
+						Email:    user.Email,
+					},
+					OutboxTasks: []db.CreateOutboxTaskParams{verifyEmailTask},
 				}
 				//build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParamsMatcher(arg, password)).
+					CreateUserTx(gomock.Any(), EqCreateUserTxParamsMatcher(arg, password, user)).
+					Times(1).
+					Return(db.CreateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "ReferralCode",
+			body: gin.H{
+				"username":      user.Username,
+				"password":      password,
+				"full_name":     user.FullName,
+				"email":         user.Email,
+				"referral_code": "abcd2345",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				verifyEmailTask, err := worker.NewSendVerifyEmailTask(&worker.PayloadSendVerifyEmail{
+					Username: user.Username,
+				})
+				require.NoError(t, err)
+				arg := db.CreateUserTxParams{
+					CreateUserParams: db.CreateUserParams{
+						Username: user.Username,
+						FullName: user.FullName,
+						Email:    user.Email,
+					},
+					OutboxTasks:  []db.CreateOutboxTaskParams{verifyEmailTask},
+					ReferralCode: "ABCD2345",
+				}
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), EqCreateUserTxParamsMatcher(arg, password, user)).
 					Times(1).
-					Return(user, nil)
+					Return(db.CreateUserTxResult{User: user}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchUser(t, recorder.Body, user)
 			},
 		},
+		{
+			name: "Tenant",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+				"tenant":    "acme",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				verifyEmailTask, err := worker.NewSendVerifyEmailTask(&worker.PayloadSendVerifyEmail{
+					Username: user.Username,
+				})
+				require.NoError(t, err)
+				arg := db.CreateUserTxParams{
+					CreateUserParams: db.CreateUserParams{
+						Username: user.Username,
+						FullName: user.FullName,
+						Email:    user.Email,
+						TenantID: pgtype.Text{String: "acme", Valid: true},
+					},
+					OutboxTasks: []db.CreateOutboxTaskParams{verifyEmailTask},
+				}
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), EqCreateUserTxParamsMatcher(arg, password, user)).
+					Times(1).
+					Return(db.CreateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "UnknownTenant",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+				"tenant":    "acme",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateUserTxResult{}, &pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTenant",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+				"tenant":    "Acme Bank",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownReferralCode",
+			body: gin.H{
+				"username":      user.Username,
+				"password":      password,
+				"full_name":     user.FullName,
+				"email":         user.Email,
+				"referral_code": "ABCD2345",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateUserTxResult{}, db.ErrInvalidReferralCode)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidReferralCode",
+			body: gin.H{
+				"username":      user.Username,
+				"password":      password,
+				"full_name":     user.FullName,
+				"email":         user.Email,
+				"referral_code": "ABC-2345",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "Invalid Email",
 			body: gin.H{
@@ -92,7 +256,7 @@ func TestCreateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				//build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -110,7 +274,7 @@ func TestCreateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				//build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -128,7 +292,7 @@ func TestCreateUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				//build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -144,16 +308,11 @@ func TestCreateUserAPI(t *testing.T) {
 				"email":     user.Email,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.CreateUserParams{
-					Username: user.Username,
-					FullName: user.FullName,
-					Email:    user.Email,
-				}
 				//build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParamsMatcher(arg, password)).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Return(db.CreateUserTxResult{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -176,7 +335,7 @@ func TestCreateUserAPI(t *testing.T) {
 		// 		store.EXPECT().
 		// 			CreateUser(gomock.Any(), EqCreateUserParamsMatcher(arg, password)).
 		// 			Times(1).
-		// 			Return(db.User{}, &pq.Error{Code: "23505"})
+		// 			Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
 		// 	},
 		// 	checkResponse: func(recorder *httptest.ResponseRecorder) {
 		// 		require.Equal(t, http.StatusForbidden, recorder.Code)
@@ -210,31 +369,47 @@ func TestCreateUserAPI(t *testing.T) {
 	}
 }
 
-func randomUser(t *testing.T) (user db.User, password string) {
-	password = util.RandomString(6)
-
-	hashedPassword, err := util.HashedPassword(password)
-	require.NoError(t, err)
-
+func randomUser(t *testing.T, options ...testfixtures.UserOption) (user db.User, password string) {
+	fixture := testfixtures.NewUser(t, options...)
 	user = db.User{
-		Username:       util.RandomOwnerName(),
-		HashedPassword: hashedPassword,
-		FullName:       util.RandomOwnerName(),
-		Email:          util.RandomEmail(),
+		Username:       fixture.Username,
+		Role:           fixture.Role,
+		HashedPassword: fixture.HashedPassword,
+		FullName:       fixture.FullName,
+		Email:          fixture.Email,
 	}
-	return
+	return user, fixture.Password
 }
 
 func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
-	data, err := ioutil.ReadAll(body)
-	require.NoError(t, err)
-
 	var gotUser db.User
-	err = json.Unmarshal(data, &gotUser)
-
-	require.NoError(t, err)
+	requireBodyData(t, body, &gotUser)
 	require.Equal(t, user.Username, gotUser.Username)
 	require.Equal(t, user.FullName, gotUser.FullName)
 	require.Equal(t, user.Email, gotUser.Email)
 	require.Empty(t, gotUser.HashedPassword)
 }
+
+func TestLoginUserInternalError(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrConnDone)
+	store.EXPECT().HasPendingPasswordReset(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	data, err := json.Marshal(gin.H{"username": user.Username, "password": password})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/v1/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	// a single error, not followed by another response
+	var rsp map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+}
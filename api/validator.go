@@ -1,7 +1,13 @@
 package api
 
 import (
+	"reflect"
+	"regexp"
+
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/notification"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/val"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,3 +17,72 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	}
 	return false
 }
+
+// validAmount checks an amount in the minor units of the currency of the field named by the param
+// of the tag, e.g. amount=Currency, against the most an amount in that currency can be. An unknown
+// currency is left to the currency tag.
+var validAmount validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	amount, ok := fieldLevel.Field().Interface().(int64)
+	if !ok {
+		return false
+	}
+	currency := fieldLevel.Parent().FieldByName(fieldLevel.Param())
+	if !currency.IsValid() || currency.Kind() != reflect.String {
+		return false
+	}
+	if _, ok := util.LookupCurrency(currency.String()); !ok {
+		return true
+	}
+	return util.IsValidAmount(currency.String(), amount)
+}
+
+// validDecimalAmount checks an amount written in units of the currency of the field named by the
+// param of the tag, e.g. "12.34" for decimal_amount=Currency: it must be positive, at most the
+// most an amount in that currency can be, and have no more decimal places than the currency.
+var validDecimalAmount validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	value, ok := fieldLevel.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+	currency := fieldLevel.Parent().FieldByName(fieldLevel.Param())
+	if !currency.IsValid() || currency.Kind() != reflect.String {
+		return false
+	}
+	if _, ok := util.LookupCurrency(currency.String()); !ok {
+		return true
+	}
+	parsed, err := money.Parse(value, currency.String())
+	return err == nil && util.IsValidAmount(parsed.Currency, parsed.Amount)
+}
+
+var validNotificationKind validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	kind, ok := fieldLevel.Field().Interface().(string)
+	return ok && notification.IsKind(kind)
+}
+
+// categoryPattern is the format of the categories and the tags of the entries, e.g. groceries.
+const categoryPattern = "^[a-z][a-z0-9_]{1,29}$"
+
+var isValidCategory = regexp.MustCompile(categoryPattern).MatchString
+
+var validCategory validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	category, ok := fieldLevel.Field().Interface().(string)
+	return ok && isValidCategory(category)
+}
+
+// validString turns a rule of the val package into a binding tag, so that the fields are
+// checked like the gRPC api checks them.
+func validString(validate func(string) error) validator.Func {
+	return func(fieldLevel validator.FieldLevel) bool {
+		value, ok := fieldLevel.Field().Interface().(string)
+		return ok && validate(value) == nil
+	}
+}
+
+// userValidations are the binding tags of the fields of the users.
+var userValidations = map[string]validator.Func{
+	"username":   validString(val.ValidateUserName),
+	"full_name":  validString(val.ValidateFullName),
+	"password":   validString(val.ValidatePassword),
+	"user_email": validString(val.ValidateEmail),
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/require"
+)
+
+// FuzzCreateUserRequest checks that the REST api binds the users the gRPC api accepts, and
+// only those, so that the rules of the two can't drift apart:
+//
+//	go test ./api -run '^$' -fuzz FuzzCreateUserRequest
+func FuzzCreateUserRequest(f *testing.F) {
+	// registers the binding tags
+	_, err := NewServer(util.Config{TokenSymmetricKey: util.RandomString(32), ReceiptKey: util.RandomString(32), CardKey: util.RandomString(32), CardNetworkKey: util.RandomString(32), PaymentRequestKey: util.RandomString(32)}, nil, maintenance.NewMode(false), nil, nil)
+	require.NoError(f, err)
+
+	f.Add("alice", "Alice Bob", "alice@email.com", "secret")
+	f.Add("Alice", "Alice_Bob", "alice@email", "secret12345")
+	f.Add("bob_42", "Bob", "Bob <bob@email.com>", "pässwörd")
+	f.Add("", "", "", "")
+	f.Fuzz(func(t *testing.T, username, fullName, email, password string) {
+		restErr := binding.Validator.ValidateStruct(&createUserRequest{
+			Username: username,
+			Password: password,
+			FullName: fullName,
+			Email:    email,
+		})
+		grpcErr := (&pb.CreateUserRequest{
+			Username: username,
+			FullName: fullName,
+			Email:    email,
+			Password: password,
+		}).Validate()
+		require.Equal(t, grpcErr == nil, restErr == nil, "rest: %v, grpc: %v", restErr, grpcErr)
+
+		restErr = binding.Validator.ValidateStruct(&loginUserRequest{
+			Username: username,
+			Password: password,
+		})
+		grpcErr = (&pb.LoginUserRequest{
+			Username: username,
+			Password: password,
+		}).Validate()
+		require.Equal(t, grpcErr == nil, restErr == nil, "rest: %v, grpc: %v", restErr, grpcErr)
+	})
+}
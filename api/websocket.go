@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/backendmaster/simple_bank/crossorigin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	wsAuthTimeout      = 10 * time.Second
+	wsWriteTimeout     = 10 * time.Second
+	wsPongTimeout      = 60 * time.Second
+	wsPingInterval     = wsPongTimeout * 9 / 10
+	wsMaxMessageSize   = 1024
+	wsMaxSubscriptions = 20
+	wsSendBuffer       = 32
+)
+
+const (
+	wsTypeAuth          = "auth"
+	wsTypeSubscribe     = "subscribe"
+	wsTypeUnsubscribe   = "unsubscribe"
+	wsTypeAuthenticated = "authenticated"
+	wsTypeSubscribed    = "subscribed"
+	wsTypeUnsubscribed  = "unsubscribed"
+	wsTypeError         = "error"
+)
+
+const (
+	activityBalance          = "balance"
+	activityTransferReceived = "transfer_received"
+)
+
+// EntrySubscriber streams the new entries of an account, see eventbus.Bus.
+type EntrySubscriber interface {
+	SubscribeEntries(accountID int64) (entries <-chan db.Entry, cancel func())
+}
+
+// wsRequest is a message of the client: auth first, then any number of subscribe and unsubscribe.
+type wsRequest struct {
+	Type        string `json:"type"`
+	AccessToken string `json:"access_token"`
+	AccountID   int64  `json:"account_id"`
+}
+
+// activityEvent is a message of the server, on the WebSocket and the event stream alike.
+type activityEvent struct {
+	Type      string `json:"type"`
+	AccountID int64  `json:"account_id,omitempty"`
+	Balance   *int64 `json:"balance,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+	EntryID   int64  `json:"entry_id,omitempty"`
+	Amount    int64  `json:"amount,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newTransferReceivedEvent(entry db.Entry) activityEvent {
+	return activityEvent{
+		Type:      activityTransferReceived,
+		AccountID: entry.AccountID,
+		EntryID:   entry.ID,
+		Amount:    entry.Amount,
+	}
+}
+
+func newBalanceEvent(account db.Account) activityEvent {
+	balance := account.Balance
+	return activityEvent{
+		Type:      activityBalance,
+		AccountID: account.ID,
+		Balance:   &balance,
+		Currency:  account.Currency,
+	}
+}
+
+// serveWebSocket upgrades to a WebSocket pushing the balance changes and incoming transfers of
+// the accounts the client subscribes to. Browsers can't set headers on the upgrade request, so
+// the access token comes in the first message instead, and the connection ends when it expires.
+func (server *Server) serveWebSocket(ctx *gin.Context) {
+	if server.entries == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errResponse(ctx, errors.New("real-time updates are not available")))
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(req *http.Request) bool {
+			return crossorigin.OriginAllowed(server.config, req.Header.Get("Origin"))
+		},
+	}
+	// the upgrader answers the client itself when it fails
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsMaxMessageSize)
+
+	payload, err := server.authenticateWebSocket(ctx.Request.Context(), conn)
+	if err != nil {
+		closeWebSocket(conn, websocket.ClosePolicyViolation, err.Error())
+		return
+	}
+
+	session := &wsSession{
+		server:        server,
+		conn:          conn,
+		payload:       payload,
+		send:          make(chan activityEvent, wsSendBuffer),
+		subscriptions: make(map[int64]func()),
+	}
+	session.run(ctx.Request.Context())
+}
+
+func (server *Server) authenticateWebSocket(ctx context.Context, conn *websocket.Conn) (*token.Payload, error) {
+	conn.SetReadDeadline(time.Now().Add(wsAuthTimeout))
+	var req wsRequest
+	if err := conn.ReadJSON(&req); err != nil {
+		return nil, errors.New("expected an auth message")
+	}
+	if req.Type != wsTypeAuth {
+		return nil, errors.New("the first message must be auth")
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	// checked once: revoking the impersonation doesn't close the connection
+	if payload.Impersonated() {
+		if err := db.CheckImpersonation(ctx, server.store, payload.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := conn.WriteJSON(activityEvent{Type: wsTypeAuthenticated}); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+}
+
+// wsSession is an authenticated WebSocket connection. Its reader handles the requests of the
+// client, its writer is the only one to write to the connection, and each subscription
+// forwards the entries of its account to the writer.
+type wsSession struct {
+	server  *Server
+	conn    *websocket.Conn
+	payload *token.Payload
+	send    chan activityEvent
+	// subscriptions is only used by the reader
+	subscriptions map[int64]func()
+}
+
+func (session *wsSession) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	var forwarders sync.WaitGroup
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		defer cancel()
+		session.write(ctx)
+	}()
+	defer func() {
+		for _, unsubscribe := range session.subscriptions {
+			unsubscribe()
+		}
+		cancel()
+		forwarders.Wait()
+		<-writerDone
+	}()
+
+	session.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	session.conn.SetPongHandler(func(string) error {
+		return session.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		var req wsRequest
+		if err := session.conn.ReadJSON(&req); err != nil {
+			// closed by the client, or by the writer
+			return
+		}
+
+		switch req.Type {
+		case wsTypeSubscribe:
+			entries, err := session.subscribe(ctx, req.AccountID)
+			if err != nil {
+				session.sendError(ctx, err)
+				continue
+			}
+			forwarders.Add(1)
+			go func(accountID int64) {
+				defer forwarders.Done()
+				session.forward(ctx, accountID, entries)
+			}(req.AccountID)
+		case wsTypeUnsubscribe:
+			if unsubscribe, ok := session.subscriptions[req.AccountID]; ok {
+				unsubscribe()
+				delete(session.subscriptions, req.AccountID)
+			}
+			session.sendEvent(ctx, activityEvent{Type: wsTypeUnsubscribed, AccountID: req.AccountID})
+		default:
+			session.sendError(ctx, errors.New("unknown message type"))
+		}
+	}
+}
+
+// subscribe checks the account belongs to the user and subscribes to its entries.
+func (session *wsSession) subscribe(ctx context.Context, accountID int64) (<-chan db.Entry, error) {
+	if _, ok := session.subscriptions[accountID]; ok {
+		return nil, errors.New("already subscribed to account")
+	}
+	if len(session.subscriptions) >= wsMaxSubscriptions {
+		return nil, errors.New("too many subscriptions")
+	}
+
+	account, err := session.server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, errors.New("account not found")
+		}
+		return nil, errors.New("failed to get account")
+	}
+	if account.Owner != session.payload.Username {
+		return nil, errors.New("account doesn't belongs to authenticated user")
+	}
+
+	entries, unsubscribe := session.server.entries.SubscribeEntries(accountID)
+	session.subscriptions[accountID] = unsubscribe
+
+	subscribed := newBalanceEvent(account)
+	subscribed.Type = wsTypeSubscribed
+	session.sendEvent(ctx, subscribed)
+	return entries, nil
+}
+
+// forward sends the events of every entry of the account until the subscription ends.
+func (session *wsSession) forward(ctx context.Context, accountID int64, entries <-chan db.Entry) {
+	for entry := range entries {
+		for _, event := range session.server.entryEvents(ctx, accountID, entry) {
+			session.sendEvent(ctx, event)
+		}
+	}
+}
+
+// entryEvents are the events of a new entry of the account: an incoming transfer for a credit,
+// followed by the balance the entry left.
+func (server *Server) entryEvents(ctx context.Context, accountID int64, entry db.Entry) []activityEvent {
+	var events []activityEvent
+	if entry.Amount > 0 {
+		events = append(events, newTransferReceivedEvent(entry))
+	}
+
+	// entries don't carry the balance, which is read again instead
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error().Err(err).Int64("account id", accountID).Msg("failed to get account of new entry")
+		}
+		return events
+	}
+	event := newBalanceEvent(account)
+	event.EntryID = entry.ID
+	event.Amount = entry.Amount
+	return append(events, event)
+}
+
+func (session *wsSession) sendEvent(ctx context.Context, event activityEvent) {
+	select {
+	case session.send <- event:
+	case <-ctx.Done():
+	}
+}
+
+func (session *wsSession) sendError(ctx context.Context, err error) {
+	session.sendEvent(ctx, activityEvent{Type: wsTypeError, Error: err.Error()})
+}
+
+// write writes the events, the pings and the final close message until ctx is done or a write fails.
+func (session *wsSession) write(ctx context.Context) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	expired := time.NewTimer(time.Until(session.payload.ExpiredAt))
+	defer expired.Stop()
+	// unblocks the reader once the writer is done
+	defer session.conn.SetReadDeadline(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			closeWebSocket(session.conn, websocket.CloseGoingAway, "")
+			return
+		case <-expired.C:
+			closeWebSocket(session.conn, websocket.ClosePolicyViolation, token.ErrExpiredToken.Error())
+			return
+		case event := <-session.send:
+			session.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := session.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			session.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := session.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
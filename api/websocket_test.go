@@ -0,0 +1,208 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEntrySubscriber struct {
+	mu      sync.Mutex
+	entries map[int64]chan db.Entry
+}
+
+func newFakeEntrySubscriber() *fakeEntrySubscriber {
+	return &fakeEntrySubscriber{entries: make(map[int64]chan db.Entry)}
+}
+
+func (subscriber *fakeEntrySubscriber) SubscribeEntries(accountID int64) (<-chan db.Entry, func()) {
+	ch := make(chan db.Entry, 1)
+	subscriber.mu.Lock()
+	subscriber.entries[accountID] = ch
+	subscriber.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { close(ch) })
+	}
+}
+
+func (subscriber *fakeEntrySubscriber) publish(entry db.Entry) {
+	subscriber.mu.Lock()
+	defer subscriber.mu.Unlock()
+	subscriber.entries[entry.AccountID] <- entry
+}
+
+func dialWebSocket(t *testing.T, server *Server) *websocket.Conn {
+	httpServer := httptest.NewServer(server.router)
+	t.Cleanup(httpServer.Close)
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func authenticateWebSocket(t *testing.T, server *Server, conn *websocket.Conn, username string, duration time.Duration) {
+	accessToken, _, err := server.tokenMaker.CreateToken(username, util.DepositorRole, util.DefaultTenant, "", duration)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeAuth, AccessToken: accessToken}))
+
+	var event activityEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, wsTypeAuthenticated, event.Type)
+}
+
+func requireWebSocketClosed(t *testing.T, conn *websocket.Conn, code int) {
+	var event activityEvent
+	err := conn.ReadJSON(&event)
+	require.True(t, websocket.IsCloseError(err, code), "unexpected error %v", err)
+}
+
+func TestWebSocketAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		run        func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := account
+				updated.Balance += 10
+				gomock.InOrder(
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil),
+					store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(updated, nil),
+				)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				authenticateWebSocket(t, server, conn, user.Username, time.Minute)
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeSubscribe, AccountID: account.ID}))
+
+				var event activityEvent
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, wsTypeSubscribed, event.Type)
+				require.Equal(t, account.Balance, *event.Balance)
+
+				entries.publish(db.Entry{ID: 7, AccountID: account.ID, Amount: 10})
+
+				event = activityEvent{}
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, activityEvent{Type: activityTransferReceived, AccountID: account.ID, EntryID: 7, Amount: 10}, event)
+
+				event = activityEvent{}
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, activityBalance, event.Type)
+				require.Equal(t, account.Balance+10, *event.Balance)
+				require.Equal(t, int64(7), event.EntryID)
+
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeUnsubscribe, AccountID: account.ID}))
+				event = activityEvent{}
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, activityEvent{Type: wsTypeUnsubscribed, AccountID: account.ID}, event)
+			},
+		},
+		{
+			name: "UnauthorizedAccount",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				authenticateWebSocket(t, server, conn, "unauthorized_user", time.Minute)
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeSubscribe, AccountID: account.ID}))
+
+				var event activityEvent
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, wsTypeError, event.Type)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				authenticateWebSocket(t, server, conn, user.Username, time.Minute)
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeSubscribe, AccountID: account.ID}))
+
+				var event activityEvent
+				require.NoError(t, conn.ReadJSON(&event))
+				require.Equal(t, activityEvent{Type: wsTypeError, Error: "account not found"}, event)
+			},
+		},
+		{
+			name: "NoAuthMessage",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeSubscribe, AccountID: account.ID}))
+				requireWebSocketClosed(t, conn, websocket.ClosePolicyViolation)
+			},
+		},
+		{
+			name: "InvalidToken",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				require.NoError(t, conn.WriteJSON(wsRequest{Type: wsTypeAuth, AccessToken: "invalid"}))
+				requireWebSocketClosed(t, conn, websocket.ClosePolicyViolation)
+			},
+		},
+		{
+			name: "TokenExpires",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			run: func(t *testing.T, server *Server, entries *fakeEntrySubscriber, conn *websocket.Conn) {
+				authenticateWebSocket(t, server, conn, user.Username, 100*time.Millisecond)
+				requireWebSocketClosed(t, conn, websocket.ClosePolicyViolation)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			entries := newFakeEntrySubscriber()
+			server.entries = entries
+
+			conn := dialWebSocket(t, server)
+			tc.run(t, server, entries, conn)
+		})
+	}
+}
+
+func TestWebSocketUnavailable(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/ws", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
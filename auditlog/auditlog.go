@@ -0,0 +1,175 @@
+// Package auditlog records who made every write to the api, what it was, when and from where, in
+// the append-only audit_logs table. The middlewares record the calls that aren't reads once they
+// are answered, refused ones included, so that failed attempts show up too:
+//
+//	router.Use(auditlog.GinAuditLog(auditlog.New(config, store), actor))
+//
+// The actor is the user of the access token of the call, empty when it has none.
+package auditlog
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// writeTimeout bounds the write of a log, which doesn't use the context of the call, so that the
+// calls cancelled by their client are recorded too.
+const writeTimeout = 5 * time.Second
+
+const requestIDHeader = "x-request-id"
+
+// Writer stores the audit logs chained by hash, db.Store in the servers.
+type Writer interface {
+	CreateAuditLogTx(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error)
+}
+
+// New returns store as the writer of the audit logs when AUDIT_LOG is set, and nil, which the
+// middlewares treat as no audit log, otherwise.
+func New(config util.Config, store Writer) Writer {
+	if !config.AuditLog {
+		return nil
+	}
+	return store
+}
+
+// write stores the log of arg. A failure is only logged: the call it records has been answered already.
+func write(writer Writer, arg db.CreateAuditLogParams) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if _, err := writer.CreateAuditLogTx(ctx, arg); err != nil {
+		log.Error().Err(err).Str("actor", arg.Actor).Str("impersonator", arg.Impersonator).Str("action", arg.Action).Msg("can't not write audit log")
+	}
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isReadRpc tells the reads apart by their name, starting with Get or List, like GrpcReadOnly.
+func isReadRpc(fullMethod string) bool {
+	name := path.Base(fullMethod)
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
+
+// GinAuditLog records the requests to the routes of the router that aren't GET, HEAD or OPTIONS.
+// Their action is the method and the route, e.g. "PUT /accounts/:id/alerts". actor runs once
+// the request is served, after the authentication of its route, and returns the user along with
+// the admin impersonating them, if any.
+func GinAuditLog(writer Writer, actor func(ctx *gin.Context) (actor string, impersonator string)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if writer == nil || isReadMethod(ctx.Request.Method) || ctx.FullPath() == "" {
+			return
+		}
+		user, impersonator := actor(ctx)
+		write(writer, db.CreateAuditLogParams{
+			Actor:        user,
+			Impersonator: impersonator,
+			Protocol:     ProtocolHTTP,
+			Action:       ctx.Request.Method + " " + ctx.FullPath(),
+			Path:         ctx.Request.URL.Path,
+			StatusCode:   int32(ctx.Writer.Status()),
+			ClientIp:     util.ClientIP(ctx.Request.Header.Values("X-Forwarded-For"), ctx.Request.RemoteAddr),
+			UserAgent:    ctx.Request.UserAgent(),
+			RequestID:    ctx.GetHeader(requestIDHeader),
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rec *statusRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// HttpAuditLog is the net/http version of GinAuditLog, for the gateway, whose routes aren't known
+// to the middleware: the action is the method and the path.
+func HttpAuditLog(writer Writer, actor func(req *http.Request) (actor string, impersonator string), handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if writer == nil || isReadMethod(req.Method) {
+			handler.ServeHTTP(res, req)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: res, statusCode: http.StatusOK}
+		handler.ServeHTTP(rec, req)
+		user, impersonator := actor(req)
+		write(writer, db.CreateAuditLogParams{
+			Actor:        user,
+			Impersonator: impersonator,
+			Protocol:     ProtocolHTTP,
+			Action:       req.Method + " " + req.URL.Path,
+			Path:         req.URL.Path,
+			StatusCode:   int32(rec.statusCode),
+			ClientIp:     util.ClientIP(req.Header.Values("X-Forwarded-For"), req.RemoteAddr),
+			UserAgent:    req.UserAgent(),
+			RequestID:    req.Header.Get(requestIDHeader),
+		})
+	})
+}
+
+// GrpcAuditLog records the rpcs that aren't reads. Their action and path are the full method, and
+// their status code the grpc one.
+func GrpcAuditLog(writer Writer, actor func(ctx context.Context) (actor string, impersonator string)) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		resp, err = handler(ctx, req)
+		if writer == nil || isReadRpc(info.FullMethod) {
+			return resp, err
+		}
+
+		user, impersonator := actor(ctx)
+		arg := db.CreateAuditLogParams{
+			Actor:        user,
+			Impersonator: impersonator,
+			Protocol:     ProtocolGRPC,
+			Action:       info.FullMethod,
+			Path:         info.FullMethod,
+			StatusCode:   int32(status.Code(err)),
+		}
+		var forwardedFor []string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+				arg.UserAgent = userAgent[0]
+			}
+			if requestID := md.Get(requestIDHeader); len(requestID) > 0 {
+				arg.RequestID = requestID[0]
+			}
+			forwardedFor = md.Get("x-forwarded-for")
+		}
+		remoteAddr := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+		arg.ClientIp = util.ClientIP(forwardedFor, remoteAddr)
+
+		write(writer, arg)
+		return resp, err
+	}
+}
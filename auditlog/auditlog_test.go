@@ -0,0 +1,147 @@
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type fakeWriter struct {
+	logs []db.CreateAuditLogParams
+	err  error
+}
+
+func (writer *fakeWriter) CreateAuditLogTx(ctx context.Context, arg db.CreateAuditLogParams) (db.AuditLog, error) {
+	writer.logs = append(writer.logs, arg)
+	return db.AuditLog{}, writer.err
+}
+
+func TestNew(t *testing.T) {
+	writer := &fakeWriter{}
+	require.Nil(t, New(util.Config{}, writer))
+	require.Equal(t, writer, New(util.Config{AuditLog: true}, writer))
+}
+
+func TestGinAuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// the requests come from the proxy at the remote address of httptest
+	require.NoError(t, util.SetTrustedProxies([]string{"192.0.2.1"}))
+	t.Cleanup(func() { util.SetTrustedProxies(nil) })
+	writer := &fakeWriter{}
+	router := gin.New()
+	router.Use(GinAuditLog(writer, func(ctx *gin.Context) (string, string) { return ctx.GetString("user"), "" }))
+	handler := func(ctx *gin.Context) {
+		ctx.Set("user", "alice")
+		ctx.Status(http.StatusCreated)
+	}
+	router.GET("/accounts/:id", handler)
+	router.POST("/accounts/:id", handler)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/accounts/1", nil),
+		httptest.NewRequest(http.MethodPost, "/unknown", nil),
+		httptest.NewRequest(http.MethodPost, "/accounts/1", nil),
+	} {
+		req.Header.Set("User-Agent", "curl/8.0")
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("X-Request-Id", "request-1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Equal(t, []db.CreateAuditLogParams{{
+		Actor:      "alice",
+		Protocol:   ProtocolHTTP,
+		Action:     "POST /accounts/:id",
+		Path:       "/accounts/1",
+		StatusCode: http.StatusCreated,
+		ClientIp:   "203.0.113.7",
+		UserAgent:  "curl/8.0",
+		RequestID:  "request-1",
+	}}, writer.logs)
+}
+
+func TestHttpAuditLog(t *testing.T) {
+	testCases := []struct {
+		name   string
+		writer *fakeWriter
+		method string
+		logs   int
+	}{
+		{name: "Write", writer: &fakeWriter{}, method: http.MethodPatch, logs: 1},
+		{name: "Read", writer: &fakeWriter{}, method: http.MethodGet, logs: 0},
+		// the response is already sent when the write fails
+		{name: "WriteFailed", writer: &fakeWriter{err: errors.New("db down")}, method: http.MethodDelete, logs: 1},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			handler := HttpAuditLog(tc.writer, func(req *http.Request) (string, string) { return "alice", "" },
+				http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+					res.WriteHeader(http.StatusForbidden)
+				}))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, "/v1/update_user", nil))
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+			require.Len(t, tc.writer.logs, tc.logs)
+			for _, log := range tc.writer.logs {
+				require.Equal(t, tc.method+" /v1/update_user", log.Action)
+				require.Equal(t, int32(http.StatusForbidden), log.StatusCode)
+				require.Equal(t, "192.0.2.1", log.ClientIp)
+			}
+		})
+	}
+}
+
+func TestHttpAuditLogDisabled(t *testing.T) {
+	handler := HttpAuditLog(nil, nil, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusNoContent)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/create_user", nil))
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestGrpcAuditLog(t *testing.T) {
+	writer := &fakeWriter{}
+	// an admin impersonating alice
+	interceptor := GrpcAuditLog(writer, func(ctx context.Context) (string, string) { return "alice", "admin" })
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-agent", "grpc-go/1.56", "x-request-id", "request-1"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "not yours")
+	}
+
+	for _, method := range []string{"/pb.SimpleBank/GetUser", "/pb.SimpleBank/ListAccounts", "/pb.SimpleBank/UpdateUser"} {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+
+	require.Equal(t, []db.CreateAuditLogParams{{
+		Actor:        "alice",
+		Impersonator: "admin",
+		Protocol:     ProtocolGRPC,
+		Action:       "/pb.SimpleBank/UpdateUser",
+		Path:         "/pb.SimpleBank/UpdateUser",
+		StatusCode:   int32(codes.PermissionDenied),
+		ClientIp:     "10.0.0.1",
+		UserAgent:    "grpc-go/1.56",
+		RequestID:    "request-1",
+	}}, writer.logs)
+}
@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Store caches the accounts of db.Store in redis, cache-aside: GetAccount reads the cache first
+// and fills it on a miss, from the primary since a lagging replica would cache an account older
+// than the last invalidation for the whole ttl, and every write to an account deletes its entry once done. The writes
+// are those of the account queries, and those of the transactions changing accounts inside
+// themselves, which Store overrides to invalidate the accounts they touched, see
+// TestAccountWritesInvalidate. A read that started before a write can still put the old account
+// back, so the ttl bounds how stale a cached account can be. Redis being down only costs the
+// cache, the reads go to the database.
+type Store struct {
+	db.Store
+	client redis.Cmdable
+	ttl    time.Duration
+}
+
+// New wraps store with the account cache when ACCOUNT_CACHE_TTL and REDIS_ADDRESS are both set,
+// and returns it untouched otherwise.
+func New(config util.Config, store db.Store) db.Store {
+	if config.AccountCacheTTL <= 0 || config.RedisAddress == "" {
+		return store
+	}
+	client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+	return NewStore(store, client, config.AccountCacheTTL)
+}
+
+func NewStore(store db.Store, client redis.Cmdable, ttl time.Duration) *Store {
+	return &Store{
+		Store:  store,
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func accountKey(id int64) string {
+	return "account:" + strconv.FormatInt(id, 10)
+}
+
+func (store *Store) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+	data, err := store.client.Get(ctx, accountKey(id)).Bytes()
+	if err == nil {
+		var account db.Account
+		if err := json.Unmarshal(data, &account); err == nil {
+			return account, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Warn().Err(err).Int64("account id", id).Msg("failed to read cached account")
+	}
+
+	account, err := store.Store.GetPrimaryAccount(ctx, id)
+	if err != nil {
+		return account, err
+	}
+
+	data, err = json.Marshal(account)
+	if err == nil {
+		err = store.client.Set(ctx, accountKey(id), data, store.ttl).Err()
+	}
+	if err != nil {
+		log.Warn().Err(err).Int64("account id", id).Msg("failed to cache account")
+	}
+	return account, nil
+}
+
+// invalidate deletes the cached accounts. It uses a context of its own since the write is done
+// by now, and skipping the delete because the request was canceled would leave a stale entry.
+func (store *Store) invalidate(ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, accountKey(id))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := store.client.Del(ctx, keys...).Err(); err != nil {
+		log.Error().Err(err).Strs("keys", keys).Msg("failed to invalidate cached accounts")
+	}
+}
+
+func (store *Store) AddAccountBalance(ctx context.Context, arg db.AddAccountBalanceParams) (db.Account, error) {
+	account, err := store.Store.AddAccountBalance(ctx, arg)
+	store.invalidate(arg.ID)
+	return account, err
+}
+
+func (store *Store) UpdateAccount(ctx context.Context, arg db.UpdateAccountParams) (db.Account, error) {
+	account, err := store.Store.UpdateAccount(ctx, arg)
+	store.invalidate(arg.ID)
+	return account, err
+}
+
+func (store *Store) DeleteAccount(ctx context.Context, id int64) error {
+	err := store.Store.DeleteAccount(ctx, id)
+	store.invalidate(id)
+	return err
+}
+
+func (store *Store) AddAccountHeldBalance(ctx context.Context, arg db.AddAccountHeldBalanceParams) (db.Account, error) {
+	account, err := store.Store.AddAccountHeldBalance(ctx, arg)
+	store.invalidate(arg.ID)
+	return account, err
+}
+
+func (store *Store) FreezeAccount(ctx context.Context, id int64) (db.Account, error) {
+	account, err := store.Store.FreezeAccount(ctx, id)
+	store.invalidate(id)
+	return account, err
+}
+
+// transferAccounts returns the accounts the transfers moved money between, the fx accounts of an
+// exchange included. A nil transfer, e.g. one a transaction didn't run, has none.
+func transferAccounts(transfers ...*db.TransferTxResult) []int64 {
+	var ids []int64
+	for _, transfer := range transfers {
+		if transfer == nil {
+			continue
+		}
+		ids = append(ids, transfer.Transfer.FromAccountID, transfer.Transfer.ToAccountID)
+		if exchange := transfer.ExchangeTransfer; exchange != nil {
+			ids = append(ids, exchange.FromAccountID, exchange.ToAccountID)
+		}
+	}
+	return ids
+}
+
+// The transactions below change accounts inside themselves, which doesn't go through the cache,
+// so they invalidate the accounts once over. Those known from the arguments are invalidated even
+// on a failure, the others from the result of a committed transaction only, since a rolled back
+// one changed nothing.
+
+func (store *Store) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	result, err := store.Store.TransferTx(ctx, arg)
+	store.invalidate(append(transferAccounts(&result), arg.FromAccountID, arg.ToAccountID)...)
+	return result, err
+}
+
+func (store *Store) CreateExternalTransferTx(ctx context.Context, arg db.CreateExternalTransferTxParams) (db.CreateExternalTransferTxResult, error) {
+	result, err := store.Store.CreateExternalTransferTx(ctx, arg)
+	if err == nil {
+		store.invalidate(arg.AccountID, result.SuspenseAccountID)
+	}
+	return result, err
+}
+
+func (store *Store) CloseTransferReviewTx(ctx context.Context, arg db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+	result, err := store.Store.CloseTransferReviewTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) SetDisputeStatusTx(ctx context.Context, arg db.SetDisputeStatusTxParams) (db.SetDisputeStatusTxResult, error) {
+	result, err := store.Store.SetDisputeStatusTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(result.Reversal)...)
+	}
+	return result, err
+}
+
+func (store *Store) AdjustBalanceTx(ctx context.Context, arg db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+	result, err := store.Store.AdjustBalanceTx(ctx, arg)
+	store.invalidate(arg.AccountID)
+	return result, err
+}
+
+func (store *Store) FreezeAccountTx(ctx context.Context, arg db.FreezeAccountTxParams) (db.Account, error) {
+	account, err := store.Store.FreezeAccountTx(ctx, arg)
+	store.invalidate(arg.AccountID)
+	return account, err
+}
+
+func (store *Store) AuthorizeCardTx(ctx context.Context, arg db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+	result, err := store.Store.AuthorizeCardTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) PlaceAuthorizationHoldTx(ctx context.Context, arg db.CreateAuthorizationHoldParams) (db.PlaceAuthorizationHoldTxResult, error) {
+	result, err := store.Store.PlaceAuthorizationHoldTx(ctx, arg)
+	store.invalidate(arg.AccountID)
+	return result, err
+}
+
+func (store *Store) CaptureAuthorizationHoldTx(ctx context.Context, arg db.CaptureAuthorizationHoldTxParams) (db.CaptureAuthorizationHoldTxResult, error) {
+	result, err := store.Store.CaptureAuthorizationHoldTx(ctx, arg)
+	if err == nil {
+		// the transfer of the captured amount goes from the held account to the payee
+		store.invalidate(transferAccounts(&result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) ReleaseAuthorizationHoldTx(ctx context.Context, arg db.ReleaseAuthorizationHoldTxParams) (db.AuthorizationHold, error) {
+	hold, err := store.Store.ReleaseAuthorizationHoldTx(ctx, arg)
+	if err == nil {
+		store.invalidate(hold.AccountID)
+	}
+	return hold, err
+}
+
+func (store *Store) PayPaymentRequestTx(ctx context.Context, arg db.PayPaymentRequestTxParams) (db.PayPaymentRequestTxResult, error) {
+	result, err := store.Store.PayPaymentRequestTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(&result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) RefundTransferTx(ctx context.Context, arg db.RefundTransferTxParams) (db.RefundTransferTxResult, error) {
+	result, err := store.Store.RefundTransferTx(ctx, arg)
+	if err == nil {
+		// the refund goes between the two accounts of the transfer it pays back
+		store.invalidate(transferAccounts(&result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) DisburseLoanTx(ctx context.Context, arg db.DisburseLoanTxParams) (db.DisburseLoanTxResult, error) {
+	result, err := store.Store.DisburseLoanTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(&result.Disbursement)...)
+	}
+	return result, err
+}
+
+func (store *Store) RepayLoanInstallmentTx(ctx context.Context, arg db.RepayLoanInstallmentTxParams) (db.RepayLoanInstallmentTxResult, error) {
+	result, err := store.Store.RepayLoanInstallmentTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(&result.Repayment)...)
+	}
+	return result, err
+}
+
+func (store *Store) RedeemRewardsTx(ctx context.Context, arg db.RedeemRewardsTxParams) (db.RedeemRewardsTxResult, error) {
+	result, err := store.Store.RedeemRewardsTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(&result.Transfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) RewardReferralTx(ctx context.Context, arg db.RewardReferralTxParams) (db.RewardReferralTxResult, error) {
+	result, err := store.Store.RewardReferralTx(ctx, arg)
+	if err == nil {
+		store.invalidate(transferAccounts(result.ReferrerTransfer, result.RefereeTransfer)...)
+	}
+	return result, err
+}
+
+func (store *Store) OpenDisputeTx(ctx context.Context, arg db.CreateDisputeParams) (db.Dispute, error) {
+	dispute, err := store.Store.OpenDisputeTx(ctx, arg)
+	store.invalidate(arg.AccountID)
+	return dispute, err
+}
+
+func (store *Store) CompleteExternalTransferTx(ctx context.Context, arg db.CompleteExternalTransferTxParams) (db.CompleteExternalTransferTxResult, error) {
+	result, err := store.Store.CompleteExternalTransferTx(ctx, arg)
+	if err == nil {
+		// settling takes the amount out of the suspense account, returning it pays the account back
+		store.invalidate(result.SuspenseAccountID, result.ExternalTransfer.AccountID)
+	}
+	return result, err
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testenv"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransferTxCacheIntegration runs the cache on a real redis and database: the balances
+// read through the cache must follow the transfers.
+func TestTransferTxCacheIntegration(t *testing.T) {
+	ctx := context.Background()
+	env, err := testenv.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, env.Close()) })
+
+	source, err := env.Postgres(ctx)
+	require.NoError(t, err)
+	address, err := env.Redis(ctx)
+	require.NoError(t, err)
+
+	connPool, err := db.NewPool(ctx, util.Config{DBSource: source})
+	require.NoError(t, err)
+	t.Cleanup(connPool.Close)
+	client := redis.NewClient(&redis.Options{Addr: address})
+	t.Cleanup(func() { client.Close() })
+	store := NewStore(db.NewStore(connPool), client, time.Minute)
+
+	var accounts []db.Account
+	for i := 0; i < 2; i++ {
+		user, err := store.CreateUser(ctx, db.CreateUserParams{
+			Username:       util.RandomOwnerName(),
+			HashedPassword: "secret",
+			FullName:       util.RandomOwnerName(),
+			Email:          util.RandomEmail(),
+		})
+		require.NoError(t, err)
+		account, err := store.CreateAccount(ctx, db.CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  100,
+			Currency: util.USD,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	// caches both accounts
+	for _, account := range accounts {
+		cached, err := store.GetAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), cached.Balance)
+		require.Equal(t, int64(1), client.Exists(ctx, accountKey(account.ID)).Val())
+	}
+
+	_, err = store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: accounts[0].ID,
+		ToAccountID:   accounts[1].ID,
+		Amount:        30,
+	})
+	require.NoError(t, err)
+
+	for i, balance := range []int64{70, 130} {
+		account, err := store.GetAccount(ctx, accounts[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, balance, account.Balance)
+	}
+}
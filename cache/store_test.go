@@ -0,0 +1,405 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAccount(options ...testfixtures.AccountOption) db.Account {
+	account := testfixtures.NewAccount(options...)
+	return db.Account{
+		ID:       account.ID,
+		Owner:    account.Owner,
+		Balance:  account.Balance,
+		Currency: account.Currency,
+		TenantID: util.DefaultTenant,
+	}
+}
+
+func newTestStore(t *testing.T) (*Store, *mockdb.MockStore, *miniredis.Miniredis) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	mock := mockdb.NewMockStore(ctrl)
+	return NewStore(mock, client, time.Minute), mock, server
+}
+
+func TestGetAccount(t *testing.T) {
+	store, mock, server := newTestStore(t)
+	account := randomAccount()
+	ctx := context.Background()
+
+	// only the first read reaches the database, the primary rather than a replica
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	for i := 0; i < 2; i++ {
+		got, err := store.GetAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account, got)
+	}
+	require.Equal(t, time.Minute, server.TTL(accountKey(account.ID)))
+
+	// an expired entry is read again
+	server.FastForward(time.Minute)
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	_, err := store.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+}
+
+func TestGetAccountNotFound(t *testing.T) {
+	store, mock, server := newTestStore(t)
+
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+	_, err := store.GetAccount(context.Background(), 1)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+	require.False(t, server.Exists(accountKey(1)))
+}
+
+func TestGetAccountRedisDown(t *testing.T) {
+	store, mock, server := newTestStore(t)
+	account := randomAccount()
+	server.Close()
+
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+	for i := 0; i < 2; i++ {
+		got, err := store.GetAccount(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account, got)
+	}
+}
+
+func TestTransferTxInvalidates(t *testing.T) {
+	store, mock, server := newTestStore(t)
+	account1 := randomAccount()
+	account2 := randomAccount(testfixtures.WithID(account1.ID + 1))
+	ctx := context.Background()
+
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	mock.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	_, err := store.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	_, err = store.GetAccount(ctx, account2.ID)
+	require.NoError(t, err)
+
+	mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+	_, err = store.TransferTx(ctx, db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10})
+	require.NoError(t, err)
+	require.False(t, server.Exists(accountKey(account1.ID)))
+	require.False(t, server.Exists(accountKey(account2.ID)))
+}
+
+// transferResult is the result of a transfer from an account to another.
+func transferResult(from, to int64) db.TransferTxResult {
+	return db.TransferTxResult{Transfer: db.Transfer{FromAccountID: from, ToAccountID: to}}
+}
+
+// accountWrite is a query or a transaction of the store changing accounts: run calls it on a
+// store whose mock answers like it touched accounts.
+type accountWrite struct {
+	run      func(ctx context.Context, store *Store, mock *mockdb.MockStore) error
+	accounts []int64
+}
+
+// accountWrites are the queries and the transactions of db.Store changing accounts, by name.
+var accountWrites = map[string]accountWrite{
+	"AddAccountBalance": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 1}, nil)
+			_, err := store.AddAccountBalance(ctx, db.AddAccountBalanceParams{ID: 1, Amount: 10})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"AddAccountHeldBalance": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().AddAccountHeldBalance(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 1}, nil)
+			_, err := store.AddAccountHeldBalance(ctx, db.AddAccountHeldBalanceParams{ID: 1, Amount: 10})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"UpdateAccount": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().UpdateAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 1}, nil)
+			_, err := store.UpdateAccount(ctx, db.UpdateAccountParams{ID: 1})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"DeleteAccount": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(nil)
+			return store.DeleteAccount(ctx, 1)
+		},
+		accounts: []int64{1},
+	},
+	"FreezeAccount": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().FreezeAccount(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(db.Account{ID: 1}, nil)
+			_, err := store.FreezeAccount(ctx, 1)
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"TransferTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			result := transferResult(1, 3)
+			result.ExchangeTransfer = &db.Transfer{FromAccountID: 4, ToAccountID: 2}
+			mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			_, err := store.TransferTx(ctx, db.TransferTxParams{FromAccountID: 1, ToAccountID: 2})
+			return err
+		},
+		accounts: []int64{1, 2, 3, 4},
+	},
+	"CreateExternalTransferTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().CreateExternalTransferTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.CreateExternalTransferTxResult{SuspenseAccountID: 2}, nil)
+			_, err := store.CreateExternalTransferTx(ctx, db.CreateExternalTransferTxParams{AccountID: 1})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"CloseTransferReviewTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			transfer := transferResult(1, 2)
+			mock.EXPECT().CloseTransferReviewTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.CloseTransferReviewTxResult{Transfer: &transfer}, nil)
+			_, err := store.CloseTransferReviewTx(ctx, db.CloseTransferReviewTxParams{ID: 7})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"SetDisputeStatusTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			reversal := transferResult(2, 1)
+			mock.EXPECT().SetDisputeStatusTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.SetDisputeStatusTxResult{Reversal: &reversal}, nil)
+			_, err := store.SetDisputeStatusTx(ctx, db.SetDisputeStatusTxParams{ID: 7})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"AdjustBalanceTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().AdjustBalanceTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AdjustBalanceTxResult{}, nil)
+			_, err := store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{AccountID: 1, Amount: 10})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"FreezeAccountTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{ID: 1}, nil)
+			_, err := store.FreezeAccountTx(ctx, db.FreezeAccountTxParams{AccountID: 1})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"AuthorizeCardTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			debit := transferResult(1, 2)
+			mock.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.AuthorizeCardTxResult{Transfer: &debit}, nil)
+			_, err := store.AuthorizeCardTx(ctx, db.AuthorizeCardTxParams{CardID: 7, Amount: 10})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"PlaceAuthorizationHoldTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().PlaceAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PlaceAuthorizationHoldTxResult{}, nil)
+			_, err := store.PlaceAuthorizationHoldTx(ctx, db.CreateAuthorizationHoldParams{AccountID: 1, ToAccountID: 2, Amount: 10})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"CaptureAuthorizationHoldTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().CaptureAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.CaptureAuthorizationHoldTxResult{Transfer: transferResult(1, 2)}, nil)
+			_, err := store.CaptureAuthorizationHoldTx(ctx, db.CaptureAuthorizationHoldTxParams{ID: 7, Amount: 10})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"ReleaseAuthorizationHoldTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.AuthorizationHold{ID: 7, AccountID: 1, ToAccountID: 2}, nil)
+			_, err := store.ReleaseAuthorizationHoldTx(ctx, db.ReleaseAuthorizationHoldTxParams{ID: 7, Status: db.AuthorizationHoldReleased})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"PayPaymentRequestTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.PayPaymentRequestTxResult{Transfer: transferResult(1, 2)}, nil)
+			_, err := store.PayPaymentRequestTx(ctx, db.PayPaymentRequestTxParams{ID: 7, FromAccountID: 1})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"RefundTransferTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().RefundTransferTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.RefundTransferTxResult{Transfer: transferResult(2, 1)}, nil)
+			_, err := store.RefundTransferTx(ctx, db.RefundTransferTxParams{TransferID: 7, Amount: 10})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"DisburseLoanTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().DisburseLoanTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.DisburseLoanTxResult{Disbursement: transferResult(2, 1)}, nil)
+			_, err := store.DisburseLoanTx(ctx, db.DisburseLoanTxParams{OfferID: 7, AccountID: 1, Amount: 10})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"RepayLoanInstallmentTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.RepayLoanInstallmentTxResult{Repayment: transferResult(1, 2)}, nil)
+			_, err := store.RepayLoanInstallmentTx(ctx, db.RepayLoanInstallmentTxParams{ID: 7})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"RedeemRewardsTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().RedeemRewardsTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.RedeemRewardsTxResult{Transfer: transferResult(2, 1)}, nil)
+			_, err := store.RedeemRewardsTx(ctx, db.RedeemRewardsTxParams{AccountID: 1, Points: 10})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+	"RewardReferralTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			referrer := transferResult(3, 1)
+			referee := transferResult(3, 2)
+			mock.EXPECT().RewardReferralTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.RewardReferralTxResult{ReferrerTransfer: &referrer, RefereeTransfer: &referee}, nil)
+			_, err := store.RewardReferralTx(ctx, db.RewardReferralTxParams{ID: 7, Bonus: 10})
+			return err
+		},
+		accounts: []int64{1, 2, 3},
+	},
+	"OpenDisputeTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().OpenDisputeTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Dispute{ID: 7, AccountID: 1}, nil)
+			_, err := store.OpenDisputeTx(ctx, db.CreateDisputeParams{AccountID: 1, TransferID: 7, Amount: 10})
+			return err
+		},
+		accounts: []int64{1},
+	},
+	"CompleteExternalTransferTx": {
+		run: func(ctx context.Context, store *Store, mock *mockdb.MockStore) error {
+			mock.EXPECT().CompleteExternalTransferTx(gomock.Any(), gomock.Any()).Times(1).
+				Return(db.CompleteExternalTransferTxResult{ExternalTransfer: db.ExternalTransfer{ID: 7, AccountID: 1}, SuspenseAccountID: 2}, nil)
+			_, err := store.CompleteExternalTransferTx(ctx, db.CompleteExternalTransferTxParams{ID: 7, Status: db.ExternalSettled})
+			return err
+		},
+		accounts: []int64{1, 2},
+	},
+}
+
+func TestAccountWritesInvalidate(t *testing.T) {
+	for name, write := range accountWrites {
+		write := write
+		t.Run(name, func(t *testing.T) {
+			store, mock, server := newTestStore(t)
+			for _, id := range write.accounts {
+				require.NoError(t, server.Set(accountKey(id), "{}"))
+			}
+
+			require.NoError(t, write.run(context.Background(), store, mock))
+			for _, id := range write.accounts {
+				require.False(t, server.Exists(accountKey(id)), "account %d is still cached", id)
+			}
+		})
+	}
+}
+
+// noAccountWrites are the transactions of db.Store that change no account, creating one being no
+// change to a cached account.
+var noAccountWrites = map[string]bool{
+	"AddDenylistEntryTx":       true,
+	"CreateAuditLogTx":         true,
+	"CreateEntryExportTx":      true,
+	"CreateImpersonationTx":    true,
+	"CreateLegalDocumentTx":    true,
+	"CreateLoanOfferTx":        true,
+	"CreateOutboxTasksTx":      true,
+	"CreateSessionTx":          true,
+	"CreateStepUpChallengeTx":  true,
+	"CreateTenantTx":           true,
+	"CreateTransferReviewTx":   true,
+	"CreateUserImportTx":       true,
+	"CreateUserTx":             true,
+	"DeleteDenylistEntryTx":    true,
+	"DeleteUserTx":             true,
+	"FinishEntryExportTx":      true,
+	"ImportUserTx":             true,
+	"MarkLoanDelinquentTx":     true,
+	"PublishOutboxEventsTx":    true,
+	"PublishOutboxTx":          true,
+	"ReportLoginAlertTx":       true,
+	"ResetPasswordTx":          true,
+	"RevokeDeviceTx":           true,
+	"RevokeImpersonationTx":    true,
+	"RevokeSessionsTx":         true,
+	"SaveExchangeRatesTx":      true,
+	"SendDeviceVerificationTx": true,
+	"SendPasswordResetCodeTx":  true,
+	"SetCurrencyEnabledTx":     true,
+	"SetLoanOfferActiveTx":     true,
+	"SetScreeningHoldStatusTx": true,
+	"UpdateTenantTx":           true,
+}
+
+// TestAccountWritesCovered fails for a transaction of db.Store, or a query writing accounts,
+// that is neither in accountWrites nor in noAccountWrites, so that a new one changing accounts
+// can't skip the invalidation: TestAccountWritesInvalidate then checks its override.
+func TestAccountWritesCovered(t *testing.T) {
+	accountQuery := regexp.MustCompile(`^(Add|Update|Delete|Freeze)Account`)
+	storeType := reflect.TypeOf((*db.Store)(nil)).Elem()
+	for i := 0; i < storeType.NumMethod(); i++ {
+		name := storeType.Method(i).Name
+		_, writes := accountWrites[name]
+		switch {
+		case accountQuery.MatchString(name):
+			require.True(t, writes, "%s writes accounts but isn't in accountWrites", name)
+		case strings.HasSuffix(name, "Tx"):
+			require.True(t, writes != noAccountWrites[name],
+				"%s must be in accountWrites, with an override invalidating the accounts it changes, or in noAccountWrites", name)
+		}
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mock := mockdb.NewMockStore(ctrl)
+
+	require.Equal(t, db.Store(mock), New(util.Config{RedisAddress: "localhost:6379"}, mock))
+	require.Equal(t, db.Store(mock), New(util.Config{AccountCacheTTL: time.Minute}, mock))
+	require.IsType(t, &Store{}, New(util.Config{RedisAddress: "localhost:6379", AccountCacheTTL: time.Minute}, mock))
+}
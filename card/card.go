@@ -0,0 +1,170 @@
+// Package card issues the virtual cards of the accounts and checks the authorization requests the
+// card network sends for them. The bank doesn't keep the card numbers: a card is found by the
+// fingerprint of its number, and its CVV is derived from the number and the expiry date, both
+// HMACs under a key of the server.
+package card
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// MinKeySize is the size, in bytes, of the shortest key.
+const MinKeySize = 32
+
+// IIN is the issuer identification number the card numbers of the bank start with.
+const IIN = "462263"
+
+// numberSize is the number of digits of a card number, the check digit included.
+const numberSize = 16
+
+// validYears is how long an issued card is valid for.
+const validYears = 3
+
+// SignatureTolerance is how old a request of the card network can be, so that a request caught on
+// the wire can't be replayed later.
+const SignatureTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned by VerifySignature for a request the card network didn't
+	// sign, or signed too long ago.
+	ErrInvalidSignature = errors.New("invalid card network signature")
+)
+
+// Details are what the holder of a card pays with, only shown when the card is issued.
+type Details struct {
+	Number      string `json:"number"`
+	ExpiryMonth int32  `json:"expiry_month"`
+	ExpiryYear  int32  `json:"expiry_year"`
+	CVV         string `json:"cvv"`
+}
+
+// Issuer issues the cards and checks the requests of the card network. Changing its key makes the
+// cards issued before unusable, and changing its network key needs the card network to change it
+// too.
+type Issuer struct {
+	key        []byte
+	networkKey []byte
+}
+
+func NewIssuer(key string, networkKey string) (*Issuer, error) {
+	if len(key) < MinKeySize {
+		return nil, fmt.Errorf("invalid card key size: must be at least %d characters", MinKeySize)
+	}
+	if len(networkKey) < MinKeySize {
+		return nil, fmt.Errorf("invalid card network key size: must be at least %d characters", MinKeySize)
+	}
+	return &Issuer{key: []byte(key), networkKey: []byte(networkKey)}, nil
+}
+
+// Issue returns the details of a new card, with a random number, valid until the end of the
+// month validYears after now.
+func (issuer *Issuer) Issue(now time.Time) (Details, error) {
+	digits := make([]byte, 0, numberSize)
+	digits = append(digits, IIN...)
+	for len(digits) < numberSize-1 {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return Details{}, err
+		}
+		digits = append(digits, byte('0'+digit.Int64()))
+	}
+	digits = append(digits, checkDigit(digits))
+
+	now = now.UTC()
+	details := Details{
+		Number:      string(digits),
+		ExpiryMonth: int32(now.Month()),
+		ExpiryYear:  int32(now.Year() + validYears),
+	}
+	details.CVV = issuer.CVV(details.Number, details.ExpiryMonth, details.ExpiryYear)
+	return details, nil
+}
+
+// Fingerprint returns the fingerprint the card of number is found by.
+func (issuer *Issuer) Fingerprint(number string) string {
+	mac := hmac.New(sha256.New, issuer.key)
+	fmt.Fprintf(mac, "number|%s", number)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CVV returns the 3 digits CVV of the card of number expiring at the end of month in year.
+func (issuer *Issuer) CVV(number string, month int32, year int32) string {
+	mac := hmac.New(sha256.New, issuer.key)
+	fmt.Fprintf(mac, "cvv|%s|%02d|%04d", number, month, year)
+	return fmt.Sprintf("%03d", binary.BigEndian.Uint32(mac.Sum(nil))%1000)
+}
+
+// CheckCVV tells whether cvv is the CVV of the card of number expiring at the end of month in year.
+func (issuer *Issuer) CheckCVV(number string, month int32, year int32, cvv string) bool {
+	return hmac.Equal([]byte(issuer.CVV(number, month, year)), []byte(cvv))
+}
+
+// Sign returns the signature of a request of the card network sent at timestamp, in unix seconds,
+// with body: the hex HMAC of both under the network key.
+func (issuer *Issuer) Sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, issuer.networkKey)
+	fmt.Fprintf(mac, "%s.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks that the card network sent body at timestamp, in unix seconds, less than
+// SignatureTolerance before now.
+func (issuer *Issuer) VerifySignature(timestamp string, body []byte, signature string, now time.Time) error {
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(issuer.Sign(timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ValidNumber tells whether number is a card number of the bank, its check digit included.
+func ValidNumber(number string) bool {
+	if len(number) != numberSize || number[:len(IIN)] != IIN {
+		return false
+	}
+	for i := 0; i < len(number); i++ {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+	}
+	return checkDigit([]byte(number[:numberSize-1])) == number[numberSize-1]
+}
+
+// Expired tells whether a card expiring at the end of month in year has expired at now.
+func Expired(month int32, year int32, now time.Time) bool {
+	end := time.Date(int(year), time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(end)
+}
+
+// checkDigit returns the Luhn check digit of digits.
+func checkDigit(digits []byte) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		// doubling every other digit from the right, the check digit being the first
+		if (len(digits)-i)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
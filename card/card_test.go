@@ -0,0 +1,77 @@
+package card
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssue(t *testing.T) {
+	_, err := NewIssuer("short", util.RandomString(32))
+	require.Error(t, err)
+	_, err = NewIssuer(util.RandomString(32), "short")
+	require.Error(t, err)
+
+	issuer, err := NewIssuer(util.RandomString(32), util.RandomString(32))
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	details, err := issuer.Issue(now)
+	require.NoError(t, err)
+	require.Regexp(t, `^462263[0-9]{10}$`, details.Number)
+	require.True(t, ValidNumber(details.Number))
+	require.Equal(t, int32(10), details.ExpiryMonth)
+	require.Equal(t, int32(2029), details.ExpiryYear)
+	require.Regexp(t, `^[0-9]{3}$`, details.CVV)
+
+	require.Len(t, issuer.Fingerprint(details.Number), 64)
+	require.Equal(t, issuer.Fingerprint(details.Number), issuer.Fingerprint(details.Number))
+	require.True(t, issuer.CheckCVV(details.Number, details.ExpiryMonth, details.ExpiryYear, details.CVV))
+	require.False(t, issuer.CheckCVV(details.Number, details.ExpiryMonth, details.ExpiryYear+1, details.CVV))
+
+	other, err := NewIssuer(util.RandomString(32), util.RandomString(32))
+	require.NoError(t, err)
+	require.NotEqual(t, issuer.Fingerprint(details.Number), other.Fingerprint(details.Number))
+}
+
+func TestValidNumber(t *testing.T) {
+	testCases := []struct {
+		number string
+		valid  bool
+	}{
+		{"4622630000000004", true},
+		{"4622630000000003", false},
+		{"4111111111111111", false}, // another issuer
+		{"462263000000000", false},
+		{"46226300000000a4", false},
+		{"", false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.valid, ValidNumber(tc.number), tc.number)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	require.False(t, Expired(10, 2026, time.Date(2026, 10, 31, 23, 59, 59, 0, time.UTC)))
+	require.True(t, Expired(10, 2026, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)))
+	require.False(t, Expired(12, 2026, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)))
+	require.True(t, Expired(12, 2026, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestVerifySignature(t *testing.T) {
+	issuer, err := NewIssuer(util.RandomString(32), util.RandomString(32))
+	require.NoError(t, err)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"amount":100}`)
+	signature := issuer.Sign(timestamp, body)
+
+	require.NoError(t, issuer.VerifySignature(timestamp, body, signature, now))
+	require.ErrorIs(t, issuer.VerifySignature(timestamp, []byte(`{"amount":1000}`), signature, now), ErrInvalidSignature)
+	require.ErrorIs(t, issuer.VerifySignature(timestamp, body, signature, now.Add(SignatureTolerance+time.Second)), ErrInvalidSignature)
+	require.ErrorIs(t, issuer.VerifySignature("soon", body, signature, now), ErrInvalidSignature)
+	require.ErrorIs(t, issuer.VerifySignature(timestamp, body, "", now), ErrInvalidSignature)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	Username          string    `json:"username"`
+	FullName          string    `json:"full_name"`
+	Email             string    `json:"email"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Session holds the tokens of a logged in user.
+type Session struct {
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+	User                  User      `json:"user_response"`
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+func (client *Client) CreateUser(ctx context.Context, req CreateUserRequest) (User, error) {
+	var user User
+	err := client.do(ctx, http.MethodPost, "/users", nil, req, &user, false)
+	return user, err
+}
+
+// Login logs the user in, the following calls are made on their behalf.
+func (client *Client) Login(ctx context.Context, username, password string) (Session, error) {
+	req := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{username, password}
+
+	var session Session
+	if err := client.do(ctx, http.MethodPost, "/users/login", nil, req, &session, false); err != nil {
+		return Session{}, err
+	}
+
+	client.mu.Lock()
+	client.session = &session
+	client.mu.Unlock()
+	return session, nil
+}
+
+// Session returns the current session, with the last renewed access token.
+func (client *Client) Session() (Session, bool) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.session == nil {
+		return Session{}, false
+	}
+	return *client.session, true
+}
+
+// accessToken returns the access token of the session, renewed when it's about to expire or
+// when it's rejected, the token the server refused.
+func (client *Client) accessToken(ctx context.Context, rejected string) (string, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	session := client.session
+	if session == nil {
+		return "", ErrNotLoggedIn
+	}
+	// another call renewed the rejected token already
+	if session.AccessToken != rejected && time.Until(session.AccessTokenExpiresAt) > refreshMargin {
+		return session.AccessToken, nil
+	}
+	if time.Now().After(session.RefreshTokenExpiresAt) {
+		return "", fmt.Errorf("%w: the session expired at %s", ErrNotLoggedIn, session.RefreshTokenExpiresAt)
+	}
+
+	req := struct {
+		RefreshToken string `json:"refresh_token"`
+	}{session.RefreshToken}
+	var rsp struct {
+		AccessToken          string    `json:"access_token"`
+		AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	}
+	if err := client.do(ctx, http.MethodPost, "/tokens/renew_access", nil, req, &rsp, false); err != nil {
+		return "", fmt.Errorf("failed to renew access token: %w", err)
+	}
+	session.AccessToken = rsp.AccessToken
+	session.AccessTokenExpiresAt = rsp.AccessTokenExpiresAt
+	return session.AccessToken, nil
+}
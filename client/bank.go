@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Account struct {
+	ID        int64      `json:"id"`
+	Owner     string     `json:"owner"`
+	Balance   int64      `json:"balance"`
+	Currency  string     `json:"currency"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type Entry struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"account_id"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Transfer struct {
+	ID            int64     `json:"id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+	Memo          string    `json:"memo"`
+}
+
+// The pages of the lists. NextCursor and PrevCursor are empty at the ends of the list.
+type (
+	AccountPage struct {
+		Accounts   []Account `json:"accounts"`
+		NextCursor string    `json:"next_cursor"`
+		PrevCursor string    `json:"prev_cursor"`
+	}
+	EntryPage struct {
+		Entries    []Entry `json:"entries"`
+		NextCursor string  `json:"next_cursor"`
+		PrevCursor string  `json:"prev_cursor"`
+	}
+	TransferPage struct {
+		Transfers  []Transfer `json:"transfers"`
+		NextCursor string     `json:"next_cursor"`
+		PrevCursor string     `json:"prev_cursor"`
+	}
+)
+
+func (client *Client) CreateAccount(ctx context.Context, currency string) (Account, error) {
+	req := struct {
+		Currency string `json:"currency"`
+	}{currency}
+
+	var account Account
+	err := client.do(ctx, http.MethodPost, "/accounts", nil, req, &account, true)
+	return account, err
+}
+
+func (client *Client) GetAccount(ctx context.Context, id int64) (Account, error) {
+	var account Account
+	err := client.do(ctx, http.MethodGet, accountPath(id), nil, nil, &account, true)
+	return account, err
+}
+
+// ListAccounts returns the page of the accounts of the user after cursor, the first page when
+// it's empty.
+func (client *Client) ListAccounts(ctx context.Context, cursor string, pageSize int32) (AccountPage, error) {
+	var page AccountPage
+	err := client.do(ctx, http.MethodGet, "/accounts", pageQuery(cursor, pageSize), nil, &page, true)
+	return page, err
+}
+
+func (client *Client) ListEntries(ctx context.Context, accountID int64, cursor string, pageSize int32) (EntryPage, error) {
+	var page EntryPage
+	err := client.do(ctx, http.MethodGet, accountPath(accountID)+"/entries", pageQuery(cursor, pageSize), nil, &page, true)
+	return page, err
+}
+
+func (client *Client) ListTransfers(ctx context.Context, accountID int64, cursor string, pageSize int32) (TransferPage, error) {
+	var page TransferPage
+	err := client.do(ctx, http.MethodGet, accountPath(accountID)+"/transfers", pageQuery(cursor, pageSize), nil, &page, true)
+	return page, err
+}
+
+type TransferRequest struct {
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Memo          string `json:"memo,omitempty"`
+}
+
+type TransferResult struct {
+	Transfer    Transfer `json:"transfer"`
+	FromAccount Account  `json:"from_account"`
+	ToAccount   Account  `json:"to_account"`
+	FromEntry   Entry    `json:"from_entry"`
+	ToEntry     Entry    `json:"to_entry"`
+}
+
+// Transfer moves money out of an account of the user. It's only retried when the server refused
+// it, so that a transfer which failed on the way isn't made twice.
+func (client *Client) Transfer(ctx context.Context, req TransferRequest) (TransferResult, error) {
+	var result TransferResult
+	err := client.do(ctx, http.MethodPost, "/transfers", nil, req, &result, true)
+	return result, err
+}
+
+func accountPath(id int64) string {
+	return fmt.Sprintf("/accounts/%d", id)
+}
+
+func pageQuery(cursor string, pageSize int32) url.Values {
+	query := url.Values{"page_size": {strconv.Itoa(int(pageSize))}}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return query
+}
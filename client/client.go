@@ -0,0 +1,270 @@
+// Package client is the Go client of the simple bank, for the services that call its REST or
+// its gRPC api:
+//
+//	c, err := client.New("http://localhost:8080/v1", client.WithGRPC(conn))
+//	session, err := c.Login(ctx, username, password)
+//	result, err := c.Transfer(ctx, client.TransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 10, Currency: "USD"})
+//	rsp, err := c.GRPC().ListAccounts(ctx, &pb.ListAccountsRequest{PageSize: 10})
+//
+// After Login the client sends the access token with every call, and renews it with the
+// refresh token before it expires or when the server rejects it. Calls refused because the
+// server is rate limiting or unavailable are retried, and the failures the server answers are
+// returned as *Error, which errors.Is matches against ErrNotFound, ErrUnauthorized, etc.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// RetryPolicy controls how the calls refused by the server are retried: after a delay doubling
+// from BaseDelay up to MaxDelay, or after the Retry-After of the response when the server sent
+// one no longer than MaxDelay.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  100 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+}
+
+func (policy RetryPolicy) delay(retried int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 0; i < retried && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return delay
+}
+
+// refreshMargin is how long before it expires the access token is renewed, so that it doesn't
+// expire on the way to the server.
+const refreshMargin = 30 * time.Second
+
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	grpcConn    grpc.ClientConnInterface
+	retryPolicy RetryPolicy
+
+	// mu serializes the renewals of the session, so that concurrent calls renew it once.
+	mu      sync.Mutex
+	session *Session
+}
+
+type Option func(client *Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithGRPC lets GRPC call the gRPC api through conn.
+func WithGRPC(conn grpc.ClientConnInterface) Option {
+	return func(client *Client) {
+		client.grpcConn = conn
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(client *Client) {
+		client.retryPolicy = policy
+	}
+}
+
+// New returns a client of the REST api served at baseURL, e.g. http://localhost:8080/v1 for its
+// version 1, which also logs in and renews the tokens of the gRPC calls.
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	client := &Client{
+		baseURL:     u,
+		httpClient:  http.DefaultClient,
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client, nil
+}
+
+// do sends a request to the REST api and decodes the data of the envelope it answers into rsp,
+// along with its pagination, unless rsp is nil. auth sends the access token. The requests refused by the rate limiter or while the
+// server is unavailable are retried, and so are the GET requests which failed on the way.
+func (client *Client) do(ctx context.Context, method, path string, query url.Values, body, rsp any, auth bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	var rejected string
+	for retried := 0; ; {
+		var accessToken string
+		if auth {
+			var err error
+			accessToken, err = client.accessToken(ctx, rejected)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := client.send(ctx, method, path, query, payload, rsp, accessToken)
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *Error
+		isAPIErr := errors.As(err, &apiErr)
+		// the access token was revoked or expired early, renew it once
+		if auth && isAPIErr && apiErr.StatusCode == http.StatusUnauthorized && rejected == "" {
+			rejected = accessToken
+			continue
+		}
+		if retried >= client.retryPolicy.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		delay := client.retryPolicy.delay(retried)
+		switch {
+		case isAPIErr && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable):
+			if apiErr.RetryAfter > client.retryPolicy.MaxDelay {
+				return err
+			}
+			if apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+		case method == http.MethodGet && (!isAPIErr || apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusGatewayTimeout):
+		default:
+			return err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		retried++
+	}
+}
+
+func (client *Client) send(ctx context.Context, method, path string, query url.Values, payload []byte, rsp any, accessToken string) error {
+	u := *client.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	res, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return readError(res)
+	}
+	if rsp == nil {
+		return nil
+	}
+	var envelope struct {
+		Data       json.RawMessage `json:"data"`
+		Pagination json.RawMessage `json:"pagination"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(envelope.Data, rsp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	// the pages of the lists take their cursors from the pagination
+	if envelope.Pagination != nil {
+		if err := json.Unmarshal(envelope.Pagination, rsp); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// readError reads the errors of the envelope the REST api answers its errors with, those of the
+// fields of a binding as Violations.
+func readError(res *http.Response) error {
+	var body struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	message := http.StatusText(res.StatusCode)
+	var violations []FieldViolation
+	if json.Unmarshal(data, &body) == nil && len(body.Errors) > 0 {
+		messages := make([]string, len(body.Errors))
+		for i, e := range body.Errors {
+			messages[i] = e.Message
+			if e.Field != "" {
+				violations = append(violations, FieldViolation{Field: e.Field, Description: e.Message})
+			}
+		}
+		message = strings.Join(messages, "; ")
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	e := newHTTPError(res.StatusCode, message, retryAfter)
+	e.Violations = violations
+	return e
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
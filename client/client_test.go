@@ -0,0 +1,334 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.Handler, options ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, append([]Option{WithRetryPolicy(testRetryPolicy)}, options...)...)
+	require.NoError(t, err)
+	return client
+}
+
+func newTestSession(accessTokenDuration time.Duration) *Session {
+	return &Session{
+		AccessToken:           "access-1",
+		AccessTokenExpiresAt:  time.Now().Add(accessTokenDuration),
+		RefreshToken:          "refresh",
+		RefreshTokenExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// writeJSON answers data in the envelope of the REST api.
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data, "request_id": "request"}))
+}
+
+// writeError answers the error message in the envelope of the REST api.
+func writeError(t *testing.T, w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+		"data":       nil,
+		"request_id": "request",
+		"errors":     []map[string]string{{"message": message}},
+	}))
+}
+
+// renewHandler renews the refresh token of newTestSession into access-2.
+func renewHandler(t *testing.T, renewals *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "refresh", req.RefreshToken)
+		*renewals++
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"access_token":            "access-2",
+			"access_token_expires_at": time.Now().Add(time.Hour),
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	client, err := New("http://localhost:8080/api/")
+	require.NoError(t, err)
+	require.Equal(t, "/api", client.baseURL.Path)
+
+	_, err = New("localhost:8080")
+	require.Error(t, err)
+}
+
+func TestLogin(t *testing.T) {
+	account := Account{ID: 1, Owner: util.RandomOwnerName(), Balance: util.RandomBalance(), Currency: util.USD}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/login", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		writeJSON(t, w, http.StatusOK, map[string]any{
+			"access_token":             "access-1",
+			"access_token_expires_at":  time.Now().Add(time.Hour),
+			"refresh_token":            "refresh",
+			"refresh_token_expires_at": time.Now().Add(24 * time.Hour),
+			"user_response":            map[string]any{"username": account.Owner},
+		})
+	})
+	mux.HandleFunc("/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		writeJSON(t, w, http.StatusOK, account)
+	})
+	client := newTestClient(t, mux)
+
+	_, err := client.GetAccount(context.Background(), 1)
+	require.ErrorIs(t, err, ErrNotLoggedIn)
+
+	session, err := client.Login(context.Background(), account.Owner, "secret")
+	require.NoError(t, err)
+	require.Equal(t, account.Owner, session.User.Username)
+
+	got, err := client.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+	require.Equal(t, account.Balance, got.Balance)
+	require.Nil(t, got.DeletedAt)
+}
+
+func TestRenewAccessToken(t *testing.T) {
+	testCases := []struct {
+		name        string
+		session     *Session
+		rejectFirst bool
+		check       func(t *testing.T, err error, renewals int)
+	}{
+		{
+			name:    "Valid",
+			session: newTestSession(time.Hour),
+			check: func(t *testing.T, err error, renewals int) {
+				require.NoError(t, err)
+				require.Zero(t, renewals)
+			},
+		},
+		{
+			name:    "AboutToExpire",
+			session: newTestSession(time.Second),
+			check: func(t *testing.T, err error, renewals int) {
+				require.NoError(t, err)
+				require.Equal(t, 1, renewals)
+			},
+		},
+		{
+			name:        "Rejected",
+			session:     newTestSession(time.Hour),
+			rejectFirst: true,
+			check: func(t *testing.T, err error, renewals int) {
+				require.NoError(t, err)
+				require.Equal(t, 1, renewals)
+			},
+		},
+		{
+			name: "SessionExpired",
+			session: &Session{
+				AccessToken:           "access-1",
+				AccessTokenExpiresAt:  time.Now().Add(-time.Hour),
+				RefreshToken:          "refresh",
+				RefreshTokenExpiresAt: time.Now().Add(-time.Minute),
+			},
+			check: func(t *testing.T, err error, renewals int) {
+				require.ErrorIs(t, err, ErrNotLoggedIn)
+				require.Zero(t, renewals)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var renewals int
+			mux := http.NewServeMux()
+			mux.HandleFunc("/tokens/renew_access", renewHandler(t, &renewals))
+			mux.HandleFunc("/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+				if tc.rejectFirst && r.Header.Get("Authorization") == "Bearer access-1" {
+					writeError(t, w, http.StatusUnauthorized, "token has expired")
+					return
+				}
+				writeJSON(t, w, http.StatusOK, Account{ID: 1})
+			})
+			client := newTestClient(t, mux)
+			client.session = tc.session
+
+			_, err := client.GetAccount(context.Background(), 1)
+			tc.check(t, err, renewals)
+		})
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	accounts := []Account{{ID: 1, Currency: util.USD}, {ID: 2, Currency: util.EUR}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/accounts", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "5", r.URL.Query().Get("page_size"))
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"data":       map[string]any{"accounts": accounts},
+			"request_id": "request",
+			"pagination": map[string]string{"next_cursor": "next"},
+		}))
+	})
+	client := newTestClient(t, mux)
+	client.session = newTestSession(time.Hour)
+
+	page, err := client.ListAccounts(context.Background(), "", 5)
+	require.NoError(t, err)
+	require.Len(t, page.Accounts, 2)
+	require.Equal(t, "next", page.NextCursor)
+	require.Empty(t, page.PrevCursor)
+}
+
+func TestRetry(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		// failures is the number of times the server fails before answering ok, -1 for always
+		failures int
+		check    func(t *testing.T, err error, calls int)
+	}{
+		{
+			name:     "UnavailableGet",
+			method:   http.MethodGet,
+			status:   http.StatusServiceUnavailable,
+			failures: 2,
+			check: func(t *testing.T, err error, calls int) {
+				require.NoError(t, err)
+				require.Equal(t, 3, calls)
+			},
+		},
+		{
+			name:     "RateLimitedTransfer",
+			method:   http.MethodPost,
+			status:   http.StatusTooManyRequests,
+			failures: 1,
+			check: func(t *testing.T, err error, calls int) {
+				require.NoError(t, err)
+				require.Equal(t, 2, calls)
+			},
+		},
+		{
+			name:     "BadGatewayTransfer",
+			method:   http.MethodPost,
+			status:   http.StatusBadGateway,
+			failures: 1,
+			check: func(t *testing.T, err error, calls int) {
+				require.ErrorIs(t, err, ErrInternal)
+				require.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "BadGatewayGet",
+			method:   http.MethodGet,
+			status:   http.StatusBadGateway,
+			failures: 1,
+			check: func(t *testing.T, err error, calls int) {
+				require.NoError(t, err)
+				require.Equal(t, 2, calls)
+			},
+		},
+		{
+			name:       "RetryAfterTooLong",
+			method:     http.MethodGet,
+			status:     http.StatusServiceUnavailable,
+			retryAfter: "60",
+			failures:   -1,
+			check: func(t *testing.T, err error, calls int) {
+				require.ErrorIs(t, err, ErrUnavailable)
+				var apiErr *Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, time.Minute, apiErr.RetryAfter)
+				require.Equal(t, 1, calls)
+			},
+		},
+		{
+			name:     "Exhausted",
+			method:   http.MethodGet,
+			status:   http.StatusServiceUnavailable,
+			failures: -1,
+			check: func(t *testing.T, err error, calls int) {
+				require.ErrorIs(t, err, ErrUnavailable)
+				require.Equal(t, testRetryPolicy.MaxRetries+1, calls)
+			},
+		},
+		{
+			name:     "NotFound",
+			method:   http.MethodGet,
+			status:   http.StatusNotFound,
+			failures: -1,
+			check: func(t *testing.T, err error, calls int) {
+				require.ErrorIs(t, err, ErrNotFound)
+				require.EqualError(t, err, "simple bank: 404 no rows in result set")
+				require.Equal(t, 1, calls)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.method, r.Method)
+				calls++
+				if tc.failures < 0 || calls <= tc.failures {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					writeError(t, w, tc.status, "no rows in result set")
+					return
+				}
+				writeJSON(t, w, http.StatusOK, map[string]any{})
+			}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/accounts/1", handler)
+			mux.HandleFunc("/transfers", handler)
+			client := newTestClient(t, mux)
+			client.session = newTestSession(time.Hour)
+
+			var err error
+			if tc.method == http.MethodGet {
+				_, err = client.GetAccount(context.Background(), 1)
+			} else {
+				_, err = client.Transfer(context.Background(), TransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 10, Currency: util.USD})
+			}
+			tc.check(t, err, calls)
+		})
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	err := newHTTPError(http.StatusForbidden, "duplicate key", 0)
+	require.ErrorIs(t, err, ErrForbidden)
+	require.False(t, errors.Is(err, ErrNotFound))
+
+	err = newHTTPError(http.StatusTeapot, "teapot", 0)
+	require.ErrorIs(t, err, ErrInternal)
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The kinds of *Error, for errors.Is. The REST and the gRPC api answer the same failure with
+// the same kind.
+var (
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrInternal        = errors.New("internal error")
+)
+
+// ErrNotLoggedIn is returned by the calls that need an access token before Login.
+var ErrNotLoggedIn = errors.New("client: not logged in")
+
+var kinds = map[codes.Code]error{
+	codes.InvalidArgument:   ErrInvalidArgument,
+	codes.Unauthenticated:   ErrUnauthorized,
+	codes.PermissionDenied:  ErrForbidden,
+	codes.NotFound:          ErrNotFound,
+	codes.AlreadyExists:     ErrConflict,
+	codes.ResourceExhausted: ErrRateLimited,
+	codes.Unavailable:       ErrUnavailable,
+	codes.DeadlineExceeded:  ErrUnavailable,
+	codes.Internal:          ErrInternal,
+	codes.Unknown:           ErrInternal,
+}
+
+// statusCodes are the codes of the http statuses the REST api answers with.
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// Error is a failure answered by the server.
+type Error struct {
+	// StatusCode is the http status of a REST response, 0 over gRPC.
+	StatusCode int
+	// Code is the gRPC code, or the code matching StatusCode over REST.
+	Code    codes.Code
+	Message string
+	// Violations are the invalid fields of the request.
+	Violations []FieldViolation
+	// RetryAfter is how long the server asked to wait before retrying, 0 when it didn't.
+	RetryAfter time.Duration
+}
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("simple bank: %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("simple bank: %s %s", e.Code, e.Message)
+}
+
+// Is matches e against the kind of its code, e.g. errors.Is(err, client.ErrNotFound).
+func (e *Error) Is(target error) bool {
+	kind, ok := kinds[e.Code]
+	return ok && kind == target
+}
+
+func newHTTPError(statusCode int, message string, retryAfter time.Duration) *Error {
+	code, ok := statusCodes[statusCode]
+	if !ok {
+		code = codes.Unknown
+	}
+	return &Error{StatusCode: statusCode, Code: code, Message: message, RetryAfter: retryAfter}
+}
+
+func newGRPCError(st *status.Status) *Error {
+	e := &Error{Code: st.Code(), Message: st.Message()}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				e.Violations = append(e.Violations, FieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+	return e
+}
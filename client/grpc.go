@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var errNoGRPC = errors.New("client: no gRPC connection, see WithGRPC")
+
+// GRPC returns the client of the gRPC api. Its calls carry the access token once logged in,
+// and fail with *Error like the calls of the REST api.
+func (client *Client) GRPC() pb.SimpleBankClient {
+	return pb.NewSimpleBankClient(authConn{client})
+}
+
+// authConn is the connection of GRPC, which authorizes and retries the calls made through the
+// connection given to WithGRPC.
+type authConn struct {
+	client *Client
+}
+
+func (conn authConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	client := conn.client
+	if client.grpcConn == nil {
+		return errNoGRPC
+	}
+
+	var rejected string
+	for retried := 0; ; {
+		callCtx, accessToken, err := conn.authorize(ctx, rejected)
+		if err != nil {
+			return err
+		}
+
+		err = client.grpcConn.Invoke(callCtx, method, args, reply, opts...)
+		if err == nil {
+			return nil
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return err
+		}
+
+		// the access token was revoked or expired early, renew it once
+		if st.Code() == codes.Unauthenticated && accessToken != "" && rejected == "" {
+			rejected = accessToken
+			continue
+		}
+		retryable := st.Code() == codes.Unavailable || st.Code() == codes.ResourceExhausted
+		if !retryable || retried >= client.retryPolicy.MaxRetries || ctx.Err() != nil {
+			return newGRPCError(st)
+		}
+		if err := sleep(ctx, client.retryPolicy.delay(retried)); err != nil {
+			return err
+		}
+		retried++
+	}
+}
+
+func (conn authConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if conn.client.grpcConn == nil {
+		return nil, errNoGRPC
+	}
+	ctx, _, err := conn.authorize(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return conn.client.grpcConn.NewStream(ctx, desc, method, opts...)
+}
+
+// authorize adds the access token to the metadata of ctx when logged in. The calls made before
+// Login go without it, for CreateUser and LoginUser.
+func (conn authConn) authorize(ctx context.Context, rejected string) (context.Context, string, error) {
+	if _, ok := conn.client.Session(); !ok {
+		return ctx, "", nil
+	}
+	accessToken, err := conn.client.accessToken(ctx, rejected)
+	if err != nil {
+		return nil, "", err
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken), accessToken, nil
+}
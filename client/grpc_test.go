@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeConn answers the calls with the errors of errs in turn, then with ok, and records the
+// authorization of each call.
+type fakeConn struct {
+	errs           []error
+	authorizations []string
+}
+
+func (conn *fakeConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	conn.authorizations = append(conn.authorizations, append(md.Get("authorization"), "")[0])
+	if len(conn.errs) == 0 {
+		return nil
+	}
+	err := conn.errs[0]
+	conn.errs = conn.errs[1:]
+	return err
+}
+
+func (conn *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "no streams")
+}
+
+func TestGRPC(t *testing.T) {
+	badRequest, err := status.New(codes.InvalidArgument, "invalid parameters").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "page_size", Description: "must be positive"}},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		session *Session
+		errs    []error
+		check   func(t *testing.T, err error, conn *fakeConn, renewals int)
+	}{
+		{
+			name:    "Authorized",
+			session: newTestSession(time.Hour),
+			check: func(t *testing.T, err error, conn *fakeConn, renewals int) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"Bearer access-1"}, conn.authorizations)
+			},
+		},
+		{
+			name: "NotLoggedIn",
+			errs: []error{status.Error(codes.Unauthenticated, "missing metadata")},
+			check: func(t *testing.T, err error, conn *fakeConn, renewals int) {
+				require.ErrorIs(t, err, ErrUnauthorized)
+				require.Equal(t, []string{""}, conn.authorizations)
+				require.Zero(t, renewals)
+			},
+		},
+		{
+			name:    "Rejected",
+			session: newTestSession(time.Hour),
+			errs:    []error{status.Error(codes.Unauthenticated, "invalid access token")},
+			check: func(t *testing.T, err error, conn *fakeConn, renewals int) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"Bearer access-1", "Bearer access-2"}, conn.authorizations)
+				require.Equal(t, 1, renewals)
+			},
+		},
+		{
+			name:    "Unavailable",
+			session: newTestSession(time.Hour),
+			errs:    []error{status.Error(codes.Unavailable, "connection refused")},
+			check: func(t *testing.T, err error, conn *fakeConn, renewals int) {
+				require.NoError(t, err)
+				require.Len(t, conn.authorizations, 2)
+			},
+		},
+		{
+			name:    "InvalidArgument",
+			session: newTestSession(time.Hour),
+			errs:    []error{badRequest.Err()},
+			check: func(t *testing.T, err error, conn *fakeConn, renewals int) {
+				require.ErrorIs(t, err, ErrInvalidArgument)
+				var apiErr *Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Zero(t, apiErr.StatusCode)
+				require.Equal(t, []FieldViolation{{Field: "page_size", Description: "must be positive"}}, apiErr.Violations)
+				require.Len(t, conn.authorizations, 1)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var renewals int
+			mux := http.NewServeMux()
+			mux.HandleFunc("/tokens/renew_access", renewHandler(t, &renewals))
+			conn := &fakeConn{errs: tc.errs}
+			client := newTestClient(t, mux, WithGRPC(conn))
+			client.session = tc.session
+
+			_, err := client.GRPC().ListAccounts(context.Background(), &pb.ListAccountsRequest{PageSize: 10})
+			tc.check(t, err, conn, renewals)
+		})
+	}
+}
+
+func TestGRPCWithoutConn(t *testing.T) {
+	client, err := New("http://localhost:8080")
+	require.NoError(t, err)
+
+	_, err = client.GRPC().GetServerInfo(context.Background(), &pb.GetServerInfoRequest{})
+	require.ErrorIs(t, err, errNoGRPC)
+}
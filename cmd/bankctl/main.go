@@ -0,0 +1,264 @@
+// Command bankctl runs the operational tasks of the admins on the database of app.env, each
+// recorded in the audit entries with the actor, the operating system user by default, see
+// admin.Operator:
+//
+//	go run ./cmd/bankctl freeze-account -id 42 -reason "chargeback fraud, ticket 1234"
+//	go run ./cmd/bankctl -actor alice adjust-balance -id 42 -amount -500 -code duplicate_transaction -reason "duplicate deposit"
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+
+	"github.com/backendmaster/simple_bank/admin"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, operator *admin.Operator, args []string) error
+}
+
+var commands = []command{
+	{"create-admin-user", "create a user with the admin role", createAdminUser},
+	{"freeze-account", "freeze an account, which can then neither send nor receive transfers", freezeAccount},
+	{"adjust-balance", "credit an account, or debit it with a negative amount", adjustBalance},
+	{"reissue-verification-email", "send the verification email of a user again", reissueVerificationEmail},
+	{"revoke-sessions", "block every session of a user", revokeSessions},
+	{"revoke-impersonation", "revoke the token an admin minted to act as a user", revokeImpersonation},
+	{"audit-log", "list the audit entries of a user or an account", auditLog},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func main() {
+	actor := flag.String("actor", currentUser(), "admin running the command, recorded in the audit entries")
+	flag.Usage = usage
+	flag.Parse()
+	cmd, ok := findCommand(flag.Arg(0))
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Load Config Failed: ")
+	}
+	err = util.ConfigureLogger(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not configure logger ")
+	}
+
+	ctx := context.Background()
+	connPool, err := db.NewPool(ctx, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not connect to database ")
+	}
+	defer connPool.Close()
+
+	operator, err := admin.NewOperator(db.NewStore(connPool), *actor)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create operator ")
+	}
+	if err := cmd.run(ctx, operator, flag.Args()[1:]); err != nil {
+		log.Fatal().Err(err).Str("command", cmd.name).Msg("can't not run command ")
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: bankctl [-actor name] <command> [flags]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-28s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// parse parses the flags of a command, all of which are required.
+func parse(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	var missing []string
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() == f.DefValue {
+			missing = append(missing, "-"+f.Name)
+		}
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf("%s needs %v", flags.Name(), missing)
+	}
+	return nil
+}
+
+func createAdminUser(ctx context.Context, operator *admin.Operator, args []string) error {
+	var arg admin.CreateAdminUserParams
+	flags := flag.NewFlagSet("create-admin-user", flag.ExitOnError)
+	flags.StringVar(&arg.Username, "username", "", "username of the admin")
+	flags.StringVar(&arg.Password, "password", "", "password of the admin")
+	flags.StringVar(&arg.FullName, "full-name", "", "full name of the admin")
+	flags.StringVar(&arg.Email, "email", "", "email of the admin, sent the verification email")
+	flags.StringVar(&arg.Reason, "reason", "", "why the admin is created")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	user, err := operator.CreateAdminUser(ctx, arg)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("username", user.Username).Str("role", user.Role).Msg("created admin user")
+	return nil
+}
+
+func freezeAccount(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("freeze-account", flag.ExitOnError)
+	id := flags.Int64("id", 0, "id of the account")
+	reason := flags.String("reason", "", "why the account is frozen")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	account, err := operator.FreezeAccount(ctx, *id, *reason)
+	if err != nil {
+		return err
+	}
+	log.Info().Int64("account", account.ID).Str("owner", account.Owner).Time("frozen_at", account.FrozenAt.Time).Msg("froze account")
+	return nil
+}
+
+func adjustBalance(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("adjust-balance", flag.ExitOnError)
+	id := flags.Int64("id", 0, "id of the account")
+	amount := flags.Int64("amount", 0, "amount to credit, negative to debit")
+	code := flags.String("code", "", "reason code the owner is notified of, e.g. bank_error")
+	reason := flags.String("reason", "", "why the balance is adjusted")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	result, err := operator.AdjustBalance(ctx, admin.AdjustBalanceParams{
+		AccountID:  *id,
+		Amount:     *amount,
+		ReasonCode: *code,
+		Reason:     *reason,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info().Int64("account", result.Account.ID).Int64("entry", result.Entry.ID).Int64("amount", result.Entry.Amount).
+		Int64("balance", result.Account.Balance).Msg("adjusted balance")
+	return nil
+}
+
+func reissueVerificationEmail(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("reissue-verification-email", flag.ExitOnError)
+	username := flags.String("username", "", "username of the user")
+	reason := flags.String("reason", "", "why the email is sent again")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if err := operator.ReissueVerificationEmail(ctx, *username, *reason); err != nil {
+		return err
+	}
+	log.Info().Str("username", *username).Msg("reissued verification email")
+	return nil
+}
+
+func revokeSessions(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("revoke-sessions", flag.ExitOnError)
+	username := flags.String("username", "", "username of the user")
+	reason := flags.String("reason", "", "why the sessions are revoked")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if err := operator.RevokeSessions(ctx, *username, *reason); err != nil {
+		return err
+	}
+	log.Info().Str("username", *username).Msg("revoked sessions")
+	return nil
+}
+
+func revokeImpersonation(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("revoke-impersonation", flag.ExitOnError)
+	id := flags.String("id", "", "id of the impersonation, the one of its token")
+	reason := flags.String("reason", "", "why the impersonation is revoked")
+	if err := parse(flags, args); err != nil {
+		return err
+	}
+	impersonationID, err := uuid.Parse(*id)
+	if err != nil {
+		return fmt.Errorf("invalid impersonation id: %w", err)
+	}
+
+	impersonation, err := operator.RevokeImpersonation(ctx, impersonationID, *reason)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("impersonation", impersonation.ID.String()).Str("admin", impersonation.Admin).
+		Str("username", impersonation.Username).Msg("revoked impersonation")
+	return nil
+}
+
+// auditLog prints the audit entries as json lines, newest first.
+func auditLog(ctx context.Context, operator *admin.Operator, args []string) error {
+	flags := flag.NewFlagSet("audit-log", flag.ExitOnError)
+	username := flags.String("username", "", "username of the user")
+	id := flags.Int64("account", 0, "id of the account")
+	limit := flags.Int("limit", 20, "number of entries")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var target string
+	switch {
+	case *username != "" && *id == 0:
+		target = admin.UserTarget(*username)
+	case *username == "" && *id != 0:
+		target = admin.AccountTarget(*id)
+	default:
+		return fmt.Errorf("audit-log needs either -username or -account")
+	}
+
+	entries, err := operator.AuditLog(ctx, target, int32(*limit))
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for _, entry := range entries {
+		// details is json already
+		err := encoder.Encode(struct {
+			db.AuditEntry
+			Details json.RawMessage `json:"details"`
+		}{entry, entry.Details})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Command seed fills the database of app.env with users, accounts and transfers for local
+// development and load tests, see seed.Options:
+//
+//	go run ./cmd/seed -users 50 -accounts 2 -transfers 1000
+package main
+
+import (
+	"context"
+	"flag"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/seed"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	var options seed.Options
+	flag.IntVar(&options.Users, "users", 10, "number of users to create")
+	flag.IntVar(&options.AccountsPerUser, "accounts", 2, "number of accounts of every user, at most 3")
+	flag.IntVar(&options.Transfers, "transfers", 100, "number of transfers between the accounts")
+	flag.StringVar(&options.Password, "password", "secret", "password of every user")
+	flag.Int64Var(&options.Seed, "seed", 0, "seed of the random data, random when 0")
+	flag.Parse()
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Load Config Failed: ")
+	}
+	err = util.ConfigureLogger(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not configure logger ")
+	}
+
+	ctx := context.Background()
+	connPool, err := db.NewPool(ctx, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not connect to database ")
+	}
+	defer connPool.Close()
+
+	result, err := seed.Run(ctx, db.NewStore(connPool), options)
+	if err != nil {
+		log.Fatal().Err(err).Int("users", len(result.Usernames)).Int("accounts", result.Accounts).Int("transfers", result.Transfers).
+			Msg("can't not seed database ")
+	}
+	log.Info().Strs("usernames", result.Usernames).Str("password", options.Password).
+		Int("accounts", result.Accounts).Int("transfers", result.Transfers).Msg("seeded database")
+}
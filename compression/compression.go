@@ -0,0 +1,270 @@
+package compression
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// skippedContentTypes are already compressed, or streamed by grpc-web, and gain nothing from another pass.
+var skippedContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/pdf",
+	"application/grpc",
+}
+
+// negotiate picks the encoding the client prefers among gzip and deflate from its Accept-Encoding
+// header, gzip winning ties. It returns "" when the client accepts neither.
+func negotiate(acceptEncoding string) string {
+	encoding := ""
+	bestQuality := 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "*" {
+			name = encodingGzip
+		}
+		if name != encodingGzip && name != encodingDeflate {
+			continue
+		}
+		if quality > bestQuality || (quality == bestQuality && name == encodingGzip && quality > 0) {
+			encoding = name
+			bestQuality = quality
+		}
+	}
+	return encoding
+}
+
+// compressWriter holds back the response until minSize bytes were written, so that small
+// responses are sent as they are and only the large ones pay for compression.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	statusCode int
+	buffer     []byte
+	decided    bool
+	compressor io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(statusCode int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.statusCode = statusCode
+}
+
+func (w *compressWriter) Write(body []byte) (int, error) {
+	if !w.decided {
+		w.buffer = append(w.buffer, body...)
+		if len(w.buffer) < w.minSize {
+			return len(body), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(body), nil
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Write(body)
+	}
+	return w.ResponseWriter.Write(body)
+}
+
+func (w *compressWriter) shouldCompress() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if w.statusCode == http.StatusNoContent || w.statusCode == http.StatusNotModified {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buffer)
+	}
+	for _, skipped := range skippedContentTypes {
+		if strings.HasPrefix(contentType, skipped) {
+			return false
+		}
+	}
+	return true
+}
+
+// decide sends the headers and the buffered body, compressed when compress is set and the response qualifies.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+
+	if compress && w.shouldCompress() {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if w.encoding == encodingGzip {
+			w.compressor = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			w.compressor, _ = flate.NewWriter(w.ResponseWriter, flate.DefaultCompression)
+		}
+	}
+
+	if w.statusCode != 0 {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+	}
+	if len(w.buffer) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(w.buffer)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buffer)
+	}
+	w.buffer = nil
+	return err
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(len(w.buffer) >= w.minSize)
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close sends what is still buffered and ends the compressed stream.
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
+
+// HttpCompression compresses the responses of handler of at least minSize bytes with gzip or
+// deflate, as negotiated with the client. A minSize of 0 turns compression off.
+func HttpCompression(minSize int, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		encoding := negotiate(req.Header.Get("Accept-Encoding"))
+		if minSize <= 0 || encoding == "" || req.Method == http.MethodHead {
+			handler.ServeHTTP(res, req)
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: res,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		defer writer.Close()
+		handler.ServeHTTP(writer, req)
+	})
+}
+
+type ginWriter struct {
+	gin.ResponseWriter
+	*compressWriter
+}
+
+func (w *ginWriter) Header() http.Header {
+	return w.ResponseWriter.Header()
+}
+
+func (w *ginWriter) WriteHeader(statusCode int) {
+	w.compressWriter.WriteHeader(statusCode)
+}
+
+// WriteHeaderNow is how gin sends a response without body, which is never compressed.
+func (w *ginWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *ginWriter) Write(body []byte) (int, error) {
+	return w.compressWriter.Write(body)
+}
+
+func (w *ginWriter) WriteString(body string) (int, error) {
+	return w.compressWriter.Write([]byte(body))
+}
+
+func (w *ginWriter) Status() int {
+	if !w.decided && w.statusCode != 0 {
+		return w.statusCode
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *ginWriter) Written() bool {
+	return len(w.buffer) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *ginWriter) Flush() {
+	w.compressWriter.Flush()
+}
+
+// GinCompression is the gin version of HttpCompression. It should be the last global middleware,
+// so that the logging and metrics middlewares see the final status of the response.
+// WebSocket upgrades are not wrapped, since the handler takes over the connection.
+func GinCompression(minSize int) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		encoding := negotiate(ctx.GetHeader("Accept-Encoding"))
+		if minSize <= 0 || encoding == "" || ctx.Request.Method == http.MethodHead || ctx.IsWebsocket() {
+			ctx.Next()
+			return
+		}
+
+		original := ctx.Writer
+		writer := &ginWriter{
+			ResponseWriter: original,
+			compressWriter: &compressWriter{
+				ResponseWriter: original,
+				encoding:       encoding,
+				minSize:        minSize,
+			},
+		}
+		ctx.Writer = writer
+		defer func() {
+			writer.Close()
+			ctx.Writer = original
+		}()
+		ctx.Next()
+	}
+}
@@ -0,0 +1,158 @@
+package compression
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const minSize = 1024
+
+func TestNegotiate(t *testing.T) {
+	require.Equal(t, "gzip", negotiate("gzip, deflate, br"))
+	require.Equal(t, "deflate", negotiate("gzip;q=0.5, deflate"))
+	require.Equal(t, "gzip", negotiate("*"))
+	require.Equal(t, "", negotiate("gzip;q=0, br"))
+	require.Equal(t, "", negotiate(""))
+}
+
+func decode(t *testing.T, encoding string, body io.Reader) string {
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gzipReader, err := gzip.NewReader(body)
+		require.NoError(t, err)
+		reader = gzipReader
+	case "deflate":
+		reader = flate.NewReader(body)
+	default:
+		reader = body
+	}
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGinCompression(t *testing.T) {
+	largeBody := `{"entries":"` + strings.Repeat("a", 2*minSize) + `"}`
+	smallBody := `{"entries":[]}`
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		encoding       string
+	}{
+		{
+			name:           "Gzip",
+			acceptEncoding: "gzip, deflate",
+			contentType:    "application/json",
+			body:           largeBody,
+			encoding:       "gzip",
+		},
+		{
+			name:           "Deflate",
+			acceptEncoding: "deflate",
+			contentType:    "application/json",
+			body:           largeBody,
+			encoding:       "deflate",
+		},
+		{
+			name:           "BelowThreshold",
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           smallBody,
+			encoding:       "",
+		},
+		{
+			name:           "AlreadyCompressed",
+			acceptEncoding: "gzip",
+			contentType:    "application/zip",
+			body:           largeBody,
+			encoding:       "",
+		},
+		{
+			name:           "NoAcceptEncoding",
+			acceptEncoding: "",
+			contentType:    "application/json",
+			body:           largeBody,
+			encoding:       "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(GinCompression(minSize))
+			router.GET("/entries", func(ctx *gin.Context) {
+				ctx.Data(http.StatusOK, tc.contentType, []byte(tc.body))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/entries", nil)
+			require.NoError(t, err)
+			request.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, tc.encoding, recorder.Header().Get("Content-Encoding"))
+			require.Equal(t, tc.contentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.body, decode(t, tc.encoding, recorder.Body))
+			if tc.encoding != "" {
+				require.Less(t, recorder.Body.Len(), len(tc.body))
+			}
+		})
+	}
+}
+
+func TestGinCompressionAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinCompression(minSize))
+	router.GET("/entries", func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusForbidden)
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/entries", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Content-Encoding"))
+	require.Zero(t, recorder.Body.Len())
+}
+
+func TestHttpCompression(t *testing.T) {
+	body := strings.Repeat("transfer ", minSize)
+	handler := HttpCompression(minSize, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(http.StatusCreated)
+		io.WriteString(res, body[:minSize/2])
+		io.WriteString(res, body[minSize/2:])
+	}))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/list_transfers", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	require.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+	require.Equal(t, body, decode(t, "gzip", recorder.Body))
+}
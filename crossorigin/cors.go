@@ -0,0 +1,76 @@
+package crossorigin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/cors"
+)
+
+// preflightMaxAge is how long, in seconds, browsers may cache the answer to a preflight request.
+const preflightMaxAge = 600
+
+var (
+	defaultMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultHeaders = []string{"Authorization", "Content-Type", "X-Request-Id", "If-None-Match"}
+	// exposedHeaders are the response headers browser clients are allowed to read.
+	exposedHeaders = []string{"X-Request-Id", "ETag", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"}
+)
+
+// New creates the cors policy described by config. The origins are shared with gRPC-Web,
+// while methods and headers fall back to what the api uses when they aren't configured.
+func New(config util.Config) *cors.Cors {
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultMethods
+	}
+	headers := config.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultHeaders
+	}
+
+	return cors.New(cors.Options{
+		AllowedOrigins:   config.AllowedOrigins,
+		AllowedMethods:   methods,
+		AllowedHeaders:   headers,
+		ExposedHeaders:   exposedHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           preflightMaxAge,
+	})
+}
+
+// HttpCors applies the cors policy of config to handler, answering preflight requests itself.
+func HttpCors(config util.Config, handler http.Handler) http.Handler {
+	return New(config).Handler(handler)
+}
+
+// GinCors is the gin version of HttpCors. It has to be a global middleware so that it also
+// runs for the preflight OPTIONS requests, which don't match any route.
+func GinCors(config util.Config) gin.HandlerFunc {
+	policy := New(config)
+
+	return func(ctx *gin.Context) {
+		policy.HandlerFunc(ctx.Writer, ctx.Request)
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// OriginAllowed reports whether browsers from origin may use the api, for the endpoints cors
+// doesn't cover such as WebSocket upgrades. Requests without an origin don't come from a browser.
+func OriginAllowed(config util.Config, origin string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range config.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
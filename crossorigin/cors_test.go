@@ -0,0 +1,109 @@
+package crossorigin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(config util.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinCors(config))
+	router.POST("/users", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestGinCors(t *testing.T) {
+	router := newTestRouter(util.Config{
+		AllowedOrigins:   []string{"http://localhost:3000"},
+		AllowCredentials: true,
+	})
+
+	testCases := []struct {
+		name          string
+		method        string
+		origin        string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Preflight",
+			method: http.MethodOptions,
+			origin: "http://localhost:3000",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				require.Equal(t, "http://localhost:3000", recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Equal(t, http.MethodPost, recorder.Header().Get("Access-Control-Allow-Methods"))
+				require.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+			},
+		},
+		{
+			name:   "AllowedOrigin",
+			method: http.MethodPost,
+			origin: "http://localhost:3000",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "http://localhost:3000", recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Contains(t, recorder.Header().Get("Access-Control-Expose-Headers"), "X-Request-Id")
+			},
+		},
+		{
+			name:   "DisallowedOrigin",
+			method: http.MethodPost,
+			origin: "http://evil.com",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, "/users", nil)
+			require.NoError(t, err)
+			request.Header.Set("Origin", tc.origin)
+			if tc.method == http.MethodOptions {
+				request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestHttpCors(t *testing.T) {
+	handler := HttpCors(util.Config{AllowedOrigins: []string{"*"}}, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodOptions, "/v1/create_user", nil)
+	require.NoError(t, err)
+	request.Header.Set("Origin", "http://localhost:3000")
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	request.Header.Set("Access-Control-Request-Headers", "Content-Type")
+
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Content-Type", recorder.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestOriginAllowed(t *testing.T) {
+	config := util.Config{AllowedOrigins: []string{"http://localhost:3000"}}
+	require.True(t, OriginAllowed(config, ""))
+	require.True(t, OriginAllowed(config, "http://LOCALHOST:3000"))
+	require.False(t, OriginAllowed(config, "http://evil.example"))
+	require.True(t, OriginAllowed(util.Config{AllowedOrigins: []string{"*"}}, "http://evil.example"))
+}
@@ -0,0 +1,75 @@
+// Package currency keeps the currency registry of util in sync with the currencies table, so that
+// the currencies the admins enable are offered by every instance of the bank.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultRefreshInterval = time.Minute
+
+// Store is what the registry needs of db.Store.
+type Store interface {
+	ListCurrencies(ctx context.Context) ([]db.Currency, error)
+}
+
+// Load replaces the currencies of the registry with those of the currencies table.
+func Load(ctx context.Context, store Store) error {
+	currencies, err := store.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list currencies: %w", err)
+	}
+	registered := make([]util.Currency, len(currencies))
+	for i, currency := range currencies {
+		registered[i] = Registered(currency)
+	}
+	util.SetCurrencies(registered)
+	return nil
+}
+
+// Registered turns a row of the currencies table into a currency of the registry.
+func Registered(currency db.Currency) util.Currency {
+	return util.Currency{
+		Code:       currency.Code,
+		MinorUnits: int(currency.MinorUnits),
+		Enabled:    currency.Enabled,
+	}
+}
+
+// Refresher reloads the registry every interval, so that a currency enabled through another
+// instance is offered by this one too.
+type Refresher struct {
+	store    Store
+	interval time.Duration
+}
+
+func NewRefresher(store Store, interval time.Duration) *Refresher {
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+	return &Refresher{store: store, interval: interval}
+}
+
+// Run reloads the registry every interval until ctx is done. The registry keeps the currencies
+// it has when a reload fails.
+func (refresher *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(refresher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := Load(ctx, refresher.store); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to reload currencies")
+			}
+		}
+	}
+}
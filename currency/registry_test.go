@@ -0,0 +1,44 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	defaults := []util.Currency{
+		{Code: util.USD, MinorUnits: 2, Enabled: true},
+		{Code: util.EUR, MinorUnits: 2, Enabled: true},
+		{Code: util.CAD, MinorUnits: 2, Enabled: true},
+	}
+	t.Cleanup(func() { util.SetCurrencies(defaults) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockCurrencyStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{
+		{Code: util.EUR, MinorUnits: 2, Enabled: true},
+		{Code: "JPY", MinorUnits: 0, Enabled: true},
+		{Code: "BHD", MinorUnits: 3, Enabled: false},
+	}, nil)
+	require.NoError(t, Load(context.Background(), store))
+
+	require.Equal(t, []string{util.EUR, "JPY"}, util.SupportedCurrencies())
+	require.False(t, util.IsSupportedCurrency(util.USD))
+	bhd, ok := util.LookupCurrency("BHD")
+	require.True(t, ok)
+	require.Equal(t, util.Currency{Code: "BHD", MinorUnits: 3}, bhd)
+
+	// a failed reload keeps the currencies loaded so far
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+	require.Error(t, Load(context.Background(), store))
+	require.Equal(t, []string{util.EUR, "JPY"}, util.SupportedCurrencies())
+}
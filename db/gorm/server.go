@@ -3,6 +3,7 @@ package gorm
 import (
 	"fmt"
 
+	"github.com/backendmaster/simple_bank/envelope"
 	"github.com/backendmaster/simple_bank/pb"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
@@ -38,6 +39,7 @@ func NewHttpServer(config util.Config, store *gorm.DB) (*HttpServer, error) {
 
 func (server *HttpServer) setupRouter() {
 	router := gin.Default()
+	router.Use(envelope.GinRequestID())
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
 	// router.POST("tokens/renew_access", server.renewAccessToken)
@@ -54,8 +56,8 @@ func (server *HttpServer) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errResponse(err error) gin.H {
-	return gin.H{"err": err.Error()}
+func errResponse(ctx *gin.Context, err error) envelope.Envelope {
+	return envelope.Errors(ctx, envelope.Error{Message: err.Error()})
 }
 
 type GrpcServer struct {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/backendmaster/simple_bank/envelope"
 	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -18,8 +19,11 @@ type User struct {
 	HashedPassword    string
 	FullName          string
 	Email             string
+	Role              string `gorm:"default:depositor"`
+	TenantID          string `gorm:"default:default"`
 	PasswordChangedAt time.Time
 	CreatedAt         time.Time
+	DeletedAt         gorm.DeletedAt
 }
 
 type createUserRequest struct {
@@ -51,13 +55,13 @@ func (server *HttpServer) createUser(ctx *gin.Context) {
 	var req createUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	hashedPassword, err := util.HashedPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 		return
 	}
 	newUser := User{
@@ -73,15 +77,15 @@ func (server *HttpServer) createUser(ctx *gin.Context) {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, errResponse(err))
+				ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 		return
 	}
 	rsp := newUserResponse(newUser)
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
 
 type loginUserRequest struct {
@@ -103,7 +107,7 @@ func (server *HttpServer) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
@@ -114,25 +118,25 @@ func (server *HttpServer) loginUser(ctx *gin.Context) {
 	result := server.store.First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) || errors.Is(result.Error, sql.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 	}
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errResponse(ctx, err))
 		return
 	}
 	// return loginUserResponse
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, "", server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, "", server.config.RefreshTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 	}
 
 	// session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
@@ -146,7 +150,7 @@ func (server *HttpServer) loginUser(ctx *gin.Context) {
 	// })
 
 	// if err != nil {
-	// 	ctx.JSON(http.StatusInternalServerError, errResponse(err))
+	// 	ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 	// }
 
 	rsp := loginUserResponse{
@@ -158,7 +162,7 @@ func (server *HttpServer) loginUser(ctx *gin.Context) {
 		User:                  newUserResponse(user),
 	}
 
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
 
 // func (server *GrpcServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
@@ -0,0 +1,28 @@
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "title" varchar NOT NULL,
+  "body" text NOT NULL,
+  "dedup_key" varchar UNIQUE,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "notification_preferences" (
+  "username" varchar PRIMARY KEY,
+  "email_enabled" boolean NOT NULL DEFAULT true,
+  "sms_enabled" boolean NOT NULL DEFAULT false,
+  "push_enabled" boolean NOT NULL DEFAULT false,
+  "phone_number" varchar NOT NULL DEFAULT '',
+  "push_token" varchar NOT NULL DEFAULT '',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "notifications" ("username", "id");
+
+CREATE INDEX ON "notifications" ("username") WHERE "read_at" IS NULL;
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "account_alerts";
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE "account_alerts" (
+  "account_id" bigint PRIMARY KEY,
+  "low_balance_threshold" bigint,
+  "large_debit_threshold" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_alerts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_alerts" ADD CONSTRAINT "positive_thresholds" CHECK ("low_balance_threshold" > 0 AND "large_debit_threshold" > 0);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "event_outbox";
\ No newline at end of file
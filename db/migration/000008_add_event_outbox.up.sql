@@ -0,0 +1,10 @@
+CREATE TABLE "event_outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "event_key" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "event_outbox" ("id") WHERE "published_at" IS NULL;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "entries_notify_new_entry" ON "entries";
+DROP FUNCTION IF EXISTS notify_new_entry();
\ No newline at end of file
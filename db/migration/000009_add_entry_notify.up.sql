@@ -0,0 +1,14 @@
+CREATE FUNCTION notify_new_entry() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('new_entry', json_build_object(
+    'id', NEW.id,
+    'account_id', NEW.account_id,
+    'amount', NEW.amount,
+    'created_at', NEW.created_at
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entries_notify_new_entry" AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_new_entry();
@@ -0,0 +1,61 @@
+ALTER TABLE "entries" RENAME TO "entries_partitioned";
+
+ALTER TABLE "entries_partitioned" DROP CONSTRAINT "entries_pkey";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY NONE;
+
+CREATE TABLE "entries" (
+  "id" bigint PRIMARY KEY DEFAULT nextval('entries_id_seq'),
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "entries" ("account_id");
+
+COMMENT ON COLUMN "entries"."amount" IS 'can be positive or negative';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+INSERT INTO "entries" SELECT * FROM "entries_partitioned";
+
+DROP TABLE "entries_partitioned";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY "entries"."id";
+
+CREATE TRIGGER "entries_notify_new_entry" AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_new_entry();
+
+ALTER TABLE "transfers" RENAME TO "transfers_partitioned";
+
+ALTER TABLE "transfers_partitioned" DROP CONSTRAINT "transfers_pkey";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY NONE;
+
+CREATE TABLE "transfers" (
+  "id" bigint PRIMARY KEY DEFAULT nextval('transfers_id_seq'),
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfers" ("from_account_id");
+
+CREATE INDEX ON "transfers" ("to_account_id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+INSERT INTO "transfers" SELECT * FROM "transfers_partitioned";
+
+DROP TABLE "transfers_partitioned";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY "transfers"."id";
+
+DROP FUNCTION IF EXISTS create_monthly_partition(text, timestamptz);
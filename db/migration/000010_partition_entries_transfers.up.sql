@@ -0,0 +1,102 @@
+-- create_monthly_partition creates the partition of parent holding the month, in UTC, of day
+-- unless it already exists. Rows of that month that landed in the default partition, because
+-- the partition didn't exist yet when they were inserted, are moved to it.
+CREATE FUNCTION create_monthly_partition(parent text, day timestamptz) RETURNS void AS $$
+DECLARE
+  first_day timestamp := date_trunc('month', day AT TIME ZONE 'UTC');
+  month_start timestamptz := first_day AT TIME ZONE 'UTC';
+  month_end timestamptz := (first_day + interval '1 month') AT TIME ZONE 'UTC';
+  partition_name text := parent || to_char(first_day, '"_y"YYYY"m"MM');
+BEGIN
+  IF to_regclass(partition_name) IS NOT NULL THEN
+    RETURN;
+  END IF;
+
+  EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', partition_name, parent);
+  EXECUTE format(
+    'WITH moved AS (DELETE FROM %I WHERE created_at >= $1 AND created_at < $2 RETURNING *) INSERT INTO %I SELECT * FROM moved',
+    parent || '_default', partition_name
+  ) USING month_start, month_end;
+  EXECUTE format(
+    'ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+    parent, partition_name, month_start, month_end
+  );
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "entries" RENAME TO "entries_unpartitioned";
+
+ALTER TABLE "entries_unpartitioned" DROP CONSTRAINT "entries_pkey";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY NONE;
+
+CREATE TABLE "entries" (
+  "id" bigint NOT NULL DEFAULT nextval('entries_id_seq'),
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("id", "created_at")
+) PARTITION BY RANGE ("created_at");
+
+CREATE TABLE "entries_default" PARTITION OF "entries" DEFAULT;
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+COMMENT ON COLUMN "entries"."amount" IS 'can be positive or negative';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" RENAME TO "transfers_unpartitioned";
+
+ALTER TABLE "transfers_unpartitioned" DROP CONSTRAINT "transfers_pkey";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY NONE;
+
+CREATE TABLE "transfers" (
+  "id" bigint NOT NULL DEFAULT nextval('transfers_id_seq'),
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("id", "created_at")
+) PARTITION BY RANGE ("created_at");
+
+CREATE TABLE "transfers_default" PARTITION OF "transfers" DEFAULT;
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+CREATE INDEX ON "transfers" ("to_account_id", "created_at");
+
+COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+-- partitions for the months already holding rows, and for the next ones until the maintenance task takes over
+SELECT create_monthly_partition(parent, day)
+FROM (
+  SELECT 'entries' AS parent, date_trunc('month', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day
+  FROM "entries_unpartitioned"
+  UNION
+  SELECT 'transfers', date_trunc('month', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+  FROM "transfers_unpartitioned"
+  UNION
+  SELECT parent, now() + make_interval(months => ahead)
+  FROM unnest(ARRAY['entries', 'transfers']) AS parent, generate_series(0, 2) AS ahead
+) months;
+
+INSERT INTO "entries" SELECT * FROM "entries_unpartitioned";
+
+INSERT INTO "transfers" SELECT * FROM "transfers_unpartitioned";
+
+DROP TABLE "entries_unpartitioned";
+
+DROP TABLE "transfers_unpartitioned";
+
+ALTER SEQUENCE "entries_id_seq" OWNED BY "entries"."id";
+
+ALTER SEQUENCE "transfers_id_seq" OWNED BY "transfers"."id";
+
+CREATE TRIGGER "entries_notify_new_entry" AFTER INSERT ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_new_entry();
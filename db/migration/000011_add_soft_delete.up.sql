@@ -0,0 +1,8 @@
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+
+ALTER TABLE "accounts" ADD COLUMN "deleted_at" timestamptz;
+
+-- a deleted account no longer keeps its owner from opening another one in the same currency
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "deleted_at" IS NULL;
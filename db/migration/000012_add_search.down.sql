@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "transfers_memo_search_idx";
+
+DROP INDEX IF EXISTS "users_search_idx";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
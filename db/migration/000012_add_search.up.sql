@@ -0,0 +1,8 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+-- the search queries must use these exact expressions for the indexes to be picked. Emails are split
+-- on @ and . so that a search for the domain alone finds them too.
+CREATE INDEX "users_search_idx" ON "users"
+USING GIN (to_tsvector('simple', "username" || ' ' || "full_name" || ' ' || translate("email", '@.', '  ')));
+
+CREATE INDEX "transfers_memo_search_idx" ON "transfers" USING GIN (to_tsvector('simple', "memo"));
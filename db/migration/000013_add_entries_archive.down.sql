@@ -0,0 +1,3 @@
+INSERT INTO "entries" SELECT * FROM "entries_archive";
+
+DROP TABLE IF EXISTS "entries_archive";
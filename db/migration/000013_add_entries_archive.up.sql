@@ -0,0 +1,13 @@
+-- entries_archive holds the entries past their retention, moved there by the archive task.
+CREATE TABLE "entries_archive" (
+  "id" bigint PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "entries_archive" ("account_id", "id");
+
+COMMENT ON COLUMN "entries_archive"."amount" IS 'can be positive or negative';
+
+ALTER TABLE "entries_archive" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "audit_entries";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "frozen_at";
@@ -0,0 +1,15 @@
+-- a frozen account can neither send nor receive transfers.
+ALTER TABLE "accounts" ADD COLUMN "frozen_at" timestamptz;
+
+-- audit_entries records the operations of the admins, written in the transaction of the operation.
+CREATE TABLE "audit_entries" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "target" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "details" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_entries" ("target", "created_at");
@@ -0,0 +1,32 @@
+-- audit_logs records every write to the api, by the audit log middlewares. Its rows can be
+-- inserted but never changed nor removed.
+CREATE TABLE "audit_logs" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "protocol" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "path" varchar NOT NULL,
+  "status_code" int NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "request_id" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_logs" ("actor", "id");
+
+CREATE INDEX ON "audit_logs" ("action", "id");
+
+CREATE INDEX ON "audit_logs" ("created_at");
+
+CREATE FUNCTION refuse_audit_log_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_logs_append_only" BEFORE UPDATE OR DELETE ON "audit_logs"
+FOR EACH ROW EXECUTE FUNCTION refuse_audit_log_change();
+
+CREATE TRIGGER "audit_logs_no_truncate" BEFORE TRUNCATE ON "audit_logs"
+FOR EACH STATEMENT EXECUTE FUNCTION refuse_audit_log_change();
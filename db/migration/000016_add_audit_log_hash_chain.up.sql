@@ -0,0 +1,6 @@
+-- every audit log holds the hash of the one before it, see db.AuditLogHash, so that a log changed
+-- or removed behind the back of the append-only trigger breaks the chain. The logs written before
+-- the chain have empty hashes.
+ALTER TABLE "audit_logs" ADD COLUMN "prev_hash" bytea NOT NULL DEFAULT '';
+
+ALTER TABLE "audit_logs" ADD COLUMN "hash" bytea NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE "audit_logs" DROP COLUMN IF EXISTS "impersonator";
+
+DROP TABLE IF EXISTS "impersonations";
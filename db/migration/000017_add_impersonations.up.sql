@@ -0,0 +1,18 @@
+-- impersonations are the short-lived access tokens admins mint to act as a user, e.g. to debug
+-- a support ticket. The token id is the id of its impersonation, so that it can be revoked.
+CREATE TABLE "impersonations" (
+  "id" uuid PRIMARY KEY,
+  "admin" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "impersonations" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE INDEX ON "impersonations" ("username");
+
+-- the admin behind the actor of an impersonated request.
+ALTER TABLE "audit_logs" ADD COLUMN "impersonator" varchar NOT NULL DEFAULT '';
@@ -0,0 +1,33 @@
+-- disputes are the cases users open on a transfer they sent or received. While a dispute is open
+-- or investigating, its amount is held on the account the transfer credited.
+CREATE TABLE "disputes" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "opened_by" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'open',
+  "resolution" varchar NOT NULL DEFAULT '',
+  "resolved_by" varchar NOT NULL DEFAULT '',
+  "reversal_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "resolved_at" timestamptz
+);
+
+ALTER TABLE "disputes" ADD CONSTRAINT "dispute_status" CHECK ("status" IN ('open', 'investigating', 'resolved', 'refunded'));
+
+ALTER TABLE "disputes" ADD CONSTRAINT "positive_dispute_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "disputes" ADD FOREIGN KEY ("opened_by") REFERENCES "users" ("username");
+
+-- a transfer is disputed once. The transfers are partitioned, so transfer_id can't reference them.
+CREATE UNIQUE INDEX ON "disputes" ("transfer_id");
+
+-- the holds of an account
+CREATE INDEX ON "disputes" ("account_id") WHERE "status" IN ('open', 'investigating');
+
+CREATE INDEX ON "disputes" ("status");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "sessions_username_created_at_idx";
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "country";
+
+DROP TABLE IF EXISTS "transfer_reviews";
@@ -0,0 +1,43 @@
+-- transfer_reviews are the transfers the fraud rules didn't let through: a transfer waiting for
+-- its owner to confirm it with a one-time code, or held until an admin approves or rejects it.
+-- An approval runs the transfer, whose id is then transfer_id.
+CREATE TABLE "transfer_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "decision" varchar NOT NULL,
+  "rules" text[] NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "otp_hash" varchar NOT NULL DEFAULT '',
+  "otp_attempts" int NOT NULL DEFAULT 0,
+  "reviewed_by" varchar NOT NULL DEFAULT '',
+  "note" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz
+);
+
+ALTER TABLE "transfer_reviews" ADD CONSTRAINT "transfer_review_decision" CHECK ("decision" IN ('require_otp', 'hold'));
+
+ALTER TABLE "transfer_reviews" ADD CONSTRAINT "transfer_review_status" CHECK ("status" IN ('pending', 'approved', 'rejected'));
+
+ALTER TABLE "transfer_reviews" ADD CONSTRAINT "positive_transfer_review_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+-- the review queue of the admins
+CREATE INDEX ON "transfer_reviews" ("status", "decision");
+
+-- the country the client logged in from, as resolved by the proxy in front of the server, which
+-- the fraud rules compare the country of a transfer with. Empty when it wasn't known.
+ALTER TABLE "sessions" ADD COLUMN "country" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "sessions" ("username", "created_at");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "screening_holds";
+
+DROP TABLE IF EXISTS "denylist_entries";
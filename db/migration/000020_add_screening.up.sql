@@ -0,0 +1,37 @@
+-- denylist_entries are the parties the bank doesn't serve, e.g. from a sanctions list: the full
+-- name or the email of a user, matched case-insensitively, or the id of an account.
+CREATE TABLE "denylist_entries" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "value" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "denylist_entries" ADD CONSTRAINT "denylist_entry_kind" CHECK ("kind" IN ('name', 'email', 'account'));
+
+CREATE UNIQUE INDEX ON "denylist_entries" ("kind", lower("value"));
+
+-- screening_holds are the users who matched the denylist, when they signed up or when the entry
+-- was added. While a hold is pending or confirmed, the transfers from and to the accounts of the
+-- user are held for a review; clearing it, for a namesake, lets them through again.
+CREATE TABLE "screening_holds" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "matches" text[] NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "reviewed_by" varchar NOT NULL DEFAULT '',
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz
+);
+
+ALTER TABLE "screening_holds" ADD CONSTRAINT "screening_hold_status" CHECK ("status" IN ('pending', 'cleared', 'confirmed'));
+
+ALTER TABLE "screening_holds" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+-- a user has at most one open hold
+CREATE UNIQUE INDEX "screening_holds_open_idx" ON "screening_holds" ("username") WHERE "status" IN ('pending', 'confirmed');
+
+CREATE INDEX ON "screening_holds" ("status");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "entry_categories";
+
+DROP TABLE IF EXISTS "category_rules";
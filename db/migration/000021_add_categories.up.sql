@@ -0,0 +1,37 @@
+-- category_rules categorize the new entries of the accounts of their owner: the first rule, by
+-- id, whose criteria all match sets the category of an entry. An empty criterion matches any
+-- entry.
+CREATE TABLE "category_rules" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "category" varchar NOT NULL,
+  "counterparty_account_id" bigint,
+  "direction" varchar NOT NULL DEFAULT '',
+  "memo_contains" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "category_rules" ADD CONSTRAINT "category_rule_direction" CHECK ("direction" IN ('', 'debit', 'credit'));
+
+ALTER TABLE "category_rules" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE INDEX ON "category_rules" ("owner");
+
+-- entry_categories are the category and the tags of the entries, set by their owner or by a
+-- rule, rule_id. The entries being partitioned and archived, an entry is only referred to by its
+-- id.
+CREATE TABLE "entry_categories" (
+  "entry_id" bigint PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "category" varchar NOT NULL DEFAULT '',
+  "tags" text[] NOT NULL DEFAULT '{}',
+  "rule_id" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "entry_categories" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "entry_categories" ADD FOREIGN KEY ("rule_id") REFERENCES "category_rules" ("id") ON DELETE SET NULL;
+
+-- the spend of an account by category
+CREATE INDEX ON "entry_categories" ("account_id", "category");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "entry_export_files";
+
+DROP TABLE IF EXISTS "entry_exports";
@@ -0,0 +1,36 @@
+-- entry_exports are the exports of the entries of an account between from_time and to_time too
+-- large to be served right away: the worker builds them, and they can be downloaded until they
+-- expire.
+CREATE TABLE "entry_exports" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "format" varchar NOT NULL,
+  "from_time" timestamptz NOT NULL,
+  "to_time" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "entries" bigint NOT NULL DEFAULT 0,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz,
+  "expires_at" timestamptz NOT NULL
+);
+
+ALTER TABLE "entry_exports" ADD CONSTRAINT "entry_export_format" CHECK ("format" IN ('ofx', 'qif', 'csv'));
+
+ALTER TABLE "entry_exports" ADD CONSTRAINT "entry_export_status" CHECK ("status" IN ('pending', 'ready', 'failed'));
+
+ALTER TABLE "entry_exports" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "entry_exports" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "entry_exports" ("expires_at");
+
+-- entry_export_files hold the files of the ready exports, apart from entry_exports so that
+-- reading the status of an export doesn't read its file.
+CREATE TABLE "entry_export_files" (
+  "export_id" bigint PRIMARY KEY,
+  "content" bytea NOT NULL
+);
+
+ALTER TABLE "entry_export_files" ADD FOREIGN KEY ("export_id") REFERENCES "entry_exports" ("id") ON DELETE CASCADE;
@@ -0,0 +1,28 @@
+-- generate_account_number returns a new account number: SB, two check digits computed like the
+-- ones of an IBAN, then 12 random digits, e.g. SB06123456789012.
+CREATE FUNCTION generate_account_number() RETURNS varchar AS $$
+DECLARE
+  bban varchar;
+  candidate varchar;
+BEGIN
+  LOOP
+    bban := lpad(floor(random() * 1000000000000)::bigint::text, 12, '0');
+    -- S is 28 and B is 11 in the check digits computation
+    candidate := 'SB' || lpad((98 - mod((bban || '281100')::numeric, 97))::text, 2, '0') || bban;
+    EXIT WHEN NOT EXISTS (SELECT 1 FROM accounts WHERE number = candidate);
+  END LOOP;
+  RETURN candidate;
+END;
+$$ LANGUAGE plpgsql VOLATILE;
+
+ALTER TABLE "accounts" ADD COLUMN "number" varchar;
+
+COMMENT ON COLUMN "accounts"."number" IS 'the number the owner shares to be paid, e.g. SB06123456789012';
+
+UPDATE "accounts" SET "number" = generate_account_number();
+
+ALTER TABLE "accounts" ALTER COLUMN "number" SET NOT NULL;
+
+ALTER TABLE "accounts" ALTER COLUMN "number" SET DEFAULT generate_account_number();
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_number_key" UNIQUE ("number");
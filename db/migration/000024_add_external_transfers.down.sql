@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "external_transfers";
+
+-- the suspense accounts can't go while entries or transfers reference them
+DELETE FROM "accounts" WHERE "owner" = 'bank.suspense' AND NOT EXISTS (
+  SELECT 1 FROM "entries" WHERE "entries"."account_id" = "accounts"."id"
+);
+
+DELETE FROM "users" WHERE "username" = 'bank.suspense' AND NOT EXISTS (
+  SELECT 1 FROM "accounts" WHERE "accounts"."owner" = 'bank.suspense'
+);
@@ -0,0 +1,51 @@
+-- the suspense accounts hold the money of the external transfers between the debit of the sender
+-- and the settlement, one per currency with a clearing network. Their owner can't log in: its
+-- password is no bcrypt hash, and its username no valid username to sign up with.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('bank.suspense', '!', 'Suspense', 'suspense@simplebank.invalid');
+
+INSERT INTO "accounts" ("owner", "balance", "currency")
+VALUES ('bank.suspense', 0, 'USD'), ('bank.suspense', 0, 'EUR');
+
+-- external_transfers are the transfers to an account at another bank, through ACH for the dollars
+-- and SEPA for the euros. The debit_transfer moved the amount to the suspense account; a
+-- settled transfer then left it, a returned one went back with the return_transfer.
+CREATE TABLE "external_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "rail" varchar NOT NULL,
+  "beneficiary_name" varchar NOT NULL,
+  "beneficiary_account" varchar NOT NULL,
+  "beneficiary_routing" varchar NOT NULL DEFAULT '',
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "reference" varchar NOT NULL DEFAULT '',
+  "return_reason" varchar NOT NULL DEFAULT '',
+  "debit_transfer_id" bigint NOT NULL,
+  "return_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "submitted_at" timestamptz,
+  "settle_at" timestamptz,
+  "completed_at" timestamptz
+);
+
+ALTER TABLE "external_transfers" ADD CONSTRAINT "external_transfer_status" CHECK ("status" IN ('pending', 'submitted', 'settled', 'returned'));
+
+ALTER TABLE "external_transfers" ADD CONSTRAINT "external_transfer_rail" CHECK ("rail" IN ('ach', 'sepa'));
+
+ALTER TABLE "external_transfers" ADD CONSTRAINT "positive_external_transfer_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "external_transfers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "external_transfers"."beneficiary_account" IS 'an IBAN for sepa, an account number for ach';
+
+COMMENT ON COLUMN "external_transfers"."beneficiary_routing" IS 'the routing number of the bank for ach';
+
+COMMENT ON COLUMN "external_transfers"."reference" IS 'the reference of the transfer in the clearing network';
+
+CREATE INDEX ON "external_transfers" ("account_id");
+
+-- the queue of the settlement worker
+CREATE INDEX ON "external_transfers" ("status", "settle_at") WHERE "status" IN ('pending', 'submitted');
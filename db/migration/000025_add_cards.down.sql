@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "card_authorizations";
+
+DROP TABLE IF EXISTS "cards";
+
+DELETE FROM "accounts" WHERE "owner" = 'bank.suspense' AND "currency" = 'CAD' AND NOT EXISTS (
+  SELECT 1 FROM "entries" WHERE "entries"."account_id" = "accounts"."id"
+);
@@ -0,0 +1,50 @@
+-- the card payments settle through the suspense accounts too, which need one in every currency
+-- a card can be issued in.
+INSERT INTO "accounts" ("owner", "balance", "currency")
+VALUES ('bank.suspense', 0, 'CAD');
+
+-- cards are the virtual cards of the accounts. The card number is only shown once, when the card
+-- is issued: a card is found by the fingerprint of its number, an HMAC under a key of the server.
+CREATE TABLE "cards" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "fingerprint" varchar UNIQUE NOT NULL,
+  "last4" varchar NOT NULL,
+  "expiry_month" int NOT NULL,
+  "expiry_year" int NOT NULL,
+  "per_transaction_limit" bigint NOT NULL DEFAULT 0,
+  "daily_limit" bigint NOT NULL DEFAULT 0,
+  "frozen_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "cards" ADD CONSTRAINT "card_limits" CHECK ("per_transaction_limit" >= 0 AND "daily_limit" >= 0);
+
+ALTER TABLE "cards" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "cards"."per_transaction_limit" IS '0 for no limit';
+
+COMMENT ON COLUMN "cards"."daily_limit" IS 'the most the card spends in a UTC day, 0 for no limit';
+
+CREATE INDEX ON "cards" ("account_id");
+
+-- card_authorizations are the answers to the authorization requests of the card network, the
+-- declined ones included. An approved authorization debited the account with the transfer.
+CREATE TABLE "card_authorizations" (
+  "id" bigserial PRIMARY KEY,
+  "card_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "merchant_name" varchar NOT NULL,
+  "merchant_category" varchar NOT NULL DEFAULT '',
+  "approved" boolean NOT NULL,
+  "decline_reason" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "card_authorizations" ADD FOREIGN KEY ("card_id") REFERENCES "cards" ("id");
+
+COMMENT ON COLUMN "card_authorizations"."merchant_category" IS 'the ISO 18245 merchant category code, e.g. 5411 for groceries';
+
+CREATE INDEX ON "card_authorizations" ("card_id", "created_at");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "authorization_holds";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "available_balance";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "held_balance";
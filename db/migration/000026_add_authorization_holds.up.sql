@@ -0,0 +1,47 @@
+-- the held balance of an account is the amount of its active authorization holds, which the
+-- transfers from it can't spend. Its available balance is what they can spend, the disputes
+-- holding amounts of their own, see GetHeldAmount.
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD COLUMN "available_balance" bigint GENERATED ALWAYS AS ("balance" - "held_balance") STORED;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "non_negative_held_balance" CHECK ("held_balance" >= 0);
+
+COMMENT ON COLUMN "accounts"."held_balance" IS 'the amount of the active authorization holds';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'the balance less the held balance';
+
+-- authorization_holds hold an amount on an account for a payment to another, e.g. a hotel
+-- holding a deposit, until the payee captures it, in full or in part, or releases it. The holds
+-- still active at expires_at are released.
+CREATE TABLE "authorization_holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'active',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz
+);
+
+ALTER TABLE "authorization_holds" ADD CONSTRAINT "authorization_hold_status" CHECK ("status" IN ('active', 'captured', 'released', 'expired'));
+
+ALTER TABLE "authorization_holds" ADD CONSTRAINT "positive_authorization_hold_amount" CHECK ("amount" > 0 AND "captured_amount" BETWEEN 0 AND "amount");
+
+ALTER TABLE "authorization_holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "authorization_holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "authorization_holds"."transfer_id" IS 'the transfer of the captured amount';
+
+CREATE INDEX ON "authorization_holds" ("account_id");
+
+CREATE INDEX ON "authorization_holds" ("to_account_id");
+
+-- the queue of the expiry worker
+CREATE INDEX ON "authorization_holds" ("expires_at") WHERE "status" = 'active';
@@ -0,0 +1,27 @@
+-- payment_requests ask for an amount to be paid to an account, shown to the payer as a QR code
+-- signed by the server. The first payer to pay an open request before it expires closes it.
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'open',
+  "payer_account_id" bigint,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "paid_at" timestamptz
+);
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "payment_request_status" CHECK ("status" IN ('open', 'paid'));
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "positive_payment_request_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer_account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'the transfer paying the request';
+
+CREATE INDEX ON "payment_requests" ("account_id");
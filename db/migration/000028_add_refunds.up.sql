@@ -0,0 +1,20 @@
+-- refunds pay back all or part of a transfer to the account it debited, each with a transfer of
+-- its own. The refunds of a transfer add up to its amount at most.
+CREATE TABLE "refunds" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "refund_transfer_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "refunds" ADD CONSTRAINT "positive_refund_amount" CHECK ("amount" > 0);
+
+ALTER TABLE "refunds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "refunds"."refund_transfer_id" IS 'the transfer paying the amount back';
+
+-- the transfers are partitioned, so transfer_id and refund_transfer_id can't reference them.
+CREATE INDEX ON "refunds" ("transfer_id");
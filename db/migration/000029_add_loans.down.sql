@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS "loan_installments";
+DROP TABLE IF EXISTS "loans";
+DROP TABLE IF EXISTS "loan_offers";
+
+-- the lending accounts can't go while entries or transfers reference them
+DELETE FROM "accounts" WHERE "owner" = 'bank.lending' AND NOT EXISTS (
+  SELECT 1 FROM "entries" WHERE "entries"."account_id" = "accounts"."id"
+);
+
+DELETE FROM "users" WHERE "username" = 'bank.lending' AND NOT EXISTS (
+  SELECT 1 FROM "accounts" WHERE "accounts"."owner" = 'bank.lending'
+);
@@ -0,0 +1,88 @@
+-- the lending accounts disburse the loans and collect their installments, one per currency. Like
+-- the suspense accounts, their owner can't log in, and their balance goes below zero by the
+-- principal the borrowers still owe.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('bank.lending', '!', 'Lending', 'lending@simplebank.invalid');
+
+INSERT INTO "accounts" ("owner", "balance", "currency")
+VALUES ('bank.lending', 0, 'USD'), ('bank.lending', 0, 'EUR');
+
+-- loan_offers are the loan products the admins configure: the range of the principal a user can
+-- borrow, at a fixed annual rate, repaid in monthly installments over the term.
+CREATE TABLE "loan_offers" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "min_amount" bigint NOT NULL,
+  "max_amount" bigint NOT NULL,
+  "annual_rate_bps" integer NOT NULL,
+  "term_months" integer NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "loan_offers" ADD CONSTRAINT "loan_offer_amounts" CHECK ("min_amount" > 0 AND "max_amount" >= "min_amount");
+
+ALTER TABLE "loan_offers" ADD CONSTRAINT "loan_offer_terms" CHECK ("annual_rate_bps" >= 0 AND "term_months" > 0);
+
+ALTER TABLE "loan_offers" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "loan_offers"."annual_rate_bps" IS 'the annual interest rate, in hundredths of a percent';
+
+-- loans are the principals disbursed to an account under an offer, with its terms at the time.
+-- A loan is delinquent while an installment past due couldn't be collected, and paid off once
+-- every installment is paid.
+CREATE TABLE "loans" (
+  "id" bigserial PRIMARY KEY,
+  "offer_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "principal" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "annual_rate_bps" integer NOT NULL,
+  "term_months" integer NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "disbursement_transfer_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz
+);
+
+ALTER TABLE "loans" ADD CONSTRAINT "loan_status" CHECK ("status" IN ('active', 'delinquent', 'paid_off'));
+
+ALTER TABLE "loans" ADD CONSTRAINT "positive_loan_principal" CHECK ("principal" > 0);
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("offer_id") REFERENCES "loan_offers" ("id");
+
+ALTER TABLE "loans" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "loans" ("account_id");
+
+CREATE INDEX ON "loans" ("id") WHERE "status" = 'delinquent';
+
+-- loan_installments are the amortization schedule of a loan, one installment a month. The
+-- repayment worker debits each from the account of the loan once it is due.
+CREATE TABLE "loan_installments" (
+  "id" bigserial PRIMARY KEY,
+  "loan_id" bigint NOT NULL,
+  "number" integer NOT NULL,
+  "due_at" timestamptz NOT NULL,
+  "principal" bigint NOT NULL,
+  "interest" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'due',
+  "transfer_id" bigint,
+  "paid_at" timestamptz
+);
+
+ALTER TABLE "loan_installments" ADD CONSTRAINT "loan_installment_status" CHECK ("status" IN ('due', 'paid'));
+
+ALTER TABLE "loan_installments" ADD CONSTRAINT "loan_installment_amount" CHECK ("amount" = "principal" + "interest" AND "amount" > 0);
+
+ALTER TABLE "loan_installments" ADD FOREIGN KEY ("loan_id") REFERENCES "loans" ("id");
+
+COMMENT ON COLUMN "loan_installments"."transfer_id" IS 'the transfer collecting the installment';
+
+CREATE UNIQUE INDEX ON "loan_installments" ("loan_id", "number");
+
+-- the queue of the repayment worker
+CREATE INDEX ON "loan_installments" ("due_at") WHERE "status" = 'due';
@@ -0,0 +1,36 @@
+-- the rewards accounts pay the cashback out when the users redeem their points, one per
+-- currency. Like the suspense accounts, their owner can't log in, and their balance goes below
+-- zero by the cashback paid out.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('bank.rewards', '!', 'Rewards', 'rewards@simplebank.invalid');
+
+INSERT INTO "accounts" ("owner", "balance", "currency")
+VALUES ('bank.rewards', 0, 'USD'), ('bank.rewards', 0, 'EUR'), ('bank.rewards', 0, 'CAD');
+
+-- reward_entries is the ledger of the cashback points of the accounts: an accrual for each
+-- qualifying transfer from the account, and a redemption, of negative points, for each credit of
+-- points to it. The points of an account add up to its reward balance.
+CREATE TABLE "reward_entries" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "points" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "reward_entries" ADD CONSTRAINT "reward_entry_kind" CHECK ("kind" IN ('accrual', 'redemption'));
+
+ALTER TABLE "reward_entries" ADD CONSTRAINT "reward_entry_points_sign" CHECK (
+  ("kind" = 'accrual' AND "points" > 0) OR ("kind" = 'redemption' AND "points" < 0)
+);
+
+ALTER TABLE "reward_entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "reward_entries"."transfer_id" IS 'the transfer earning the points, or the one crediting the redeemed points';
+
+CREATE INDEX ON "reward_entries" ("account_id", "created_at");
+
+-- a transfer earns its points once, however many times the worker runs its task. The transfers
+-- are partitioned, so transfer_id can't reference them.
+CREATE UNIQUE INDEX ON "reward_entries" ("transfer_id");
@@ -0,0 +1,41 @@
+-- referral_codes are the codes the users share to refer someone, one per user, created the first
+-- time it asks for it.
+CREATE TABLE "referral_codes" (
+  "username" varchar PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "referral_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+-- referrals are the users who signed up with the code of a referrer. A referral is pending until
+-- its referee makes a qualifying transfer, when both are paid a bonus from the rewards accounts,
+-- or until it is rejected, e.g. as its window ran out.
+CREATE TABLE "referrals" (
+  "id" bigserial PRIMARY KEY,
+  "referrer" varchar NOT NULL,
+  "referee" varchar UNIQUE NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "reason" varchar NOT NULL DEFAULT '',
+  "qualifying_transfer_id" bigint,
+  "referrer_transfer_id" bigint,
+  "referee_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz
+);
+
+ALTER TABLE "referrals" ADD CONSTRAINT "referral_status" CHECK ("status" IN ('pending', 'rewarded', 'rejected'));
+
+ALTER TABLE "referrals" ADD CONSTRAINT "referral_not_self" CHECK ("referrer" <> "referee");
+
+ALTER TABLE "referrals" ADD FOREIGN KEY ("referrer") REFERENCES "users" ("username");
+
+ALTER TABLE "referrals" ADD FOREIGN KEY ("referee") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "referrals"."reason" IS 'why the referral was rejected';
+
+COMMENT ON COLUMN "referrals"."qualifying_transfer_id" IS 'the transfer of the referee the bonuses were paid for';
+
+CREATE INDEX ON "referrals" ("referrer", "status");
+
+CREATE INDEX ON "referrals" ("id") WHERE "status" = 'pending';
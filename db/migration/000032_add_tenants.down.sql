@@ -0,0 +1,4 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "tenant_id";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "tenant_id";
+DROP TABLE IF EXISTS "tenants";
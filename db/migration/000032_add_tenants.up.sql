@@ -0,0 +1,40 @@
+-- tenants are the banks sharing the deployment. Each has its users, their accounts and their
+-- transfers, which no user of another tenant can see nor pay, and its own terms: the currencies
+-- its users open accounts in, a cap on the amount of a transfer, and the brand of its emails.
+CREATE TABLE "tenants" (
+  "id" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "currencies" varchar[] NOT NULL,
+  "max_transfer_amount" bigint NOT NULL DEFAULT 0,
+  "email_sender_name" varchar NOT NULL DEFAULT '',
+  "support_email" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "tenants" ADD CONSTRAINT "tenant_max_transfer_amount" CHECK ("max_transfer_amount" >= 0);
+
+COMMENT ON COLUMN "tenants"."max_transfer_amount" IS 'the most a transfer from an account of the tenant moves, 0 for no cap';
+
+COMMENT ON COLUMN "tenants"."email_sender_name" IS 'the sender of the emails to the users of the tenant, EMAIL_SENDER_NAME when empty';
+
+-- the users so far, and the accounts of the bank itself, belong to the default tenant
+INSERT INTO "tenants" ("id", "name", "currencies")
+VALUES ('default', 'Simple Bank', '{USD,EUR,CAD}');
+
+ALTER TABLE "users" ADD COLUMN "tenant_id" varchar NOT NULL DEFAULT 'default';
+
+ALTER TABLE "users" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+
+ALTER TABLE "accounts" ADD COLUMN "tenant_id" varchar NOT NULL DEFAULT 'default';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "tenant_id" varchar NOT NULL DEFAULT 'default';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("tenant_id") REFERENCES "tenants" ("id");
+
+CREATE INDEX ON "users" ("tenant_id");
+
+CREATE INDEX ON "accounts" ("tenant_id");
+
+CREATE INDEX ON "transfers" ("tenant_id");
@@ -0,0 +1,7 @@
+-- preferences are the settings of a user the api keeps as a document, see the preferences
+-- package: their locale, timezone and default currency, and the notifications they muted.
+ALTER TABLE "users" ADD COLUMN "preferences" jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE "users" ADD CONSTRAINT "user_preferences_object" CHECK (jsonb_typeof("preferences") = 'object');
+
+COMMENT ON COLUMN "users"."preferences" IS 'the settings of the user, the defaults of the preferences package for the missing keys';
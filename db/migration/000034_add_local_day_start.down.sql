@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS local_day_start(timestamptz, text);
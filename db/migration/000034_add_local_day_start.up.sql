@@ -0,0 +1,6 @@
+-- local_day_start returns the midnight starting the day of at in the timezone tz, an IANA name,
+-- UTC when it is NULL. Truncating the local time and converting it back keeps the start of the day
+-- at midnight across the changes of DST, whose days are 23 or 25 hours long.
+CREATE FUNCTION local_day_start(at timestamptz, tz text) RETURNS timestamptz AS $$
+  SELECT date_trunc('day', at AT TIME ZONE COALESCE(tz, 'UTC')) AT TIME ZONE COALESCE(tz, 'UTC');
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,30 @@
+-- currencies are those the accounts can be opened and the money moved in. The amounts are counted
+-- in the minor units of their currency, minor_units being its number of decimal places: 2 for the
+-- cents of USD, 0 for JPY, 3 for the fils of BHD. Only the enabled ones are offered.
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "minor_units" smallint NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currency_minor_units" CHECK ("minor_units" BETWEEN 0 AND 4);
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'the decimal places of the currency, which the amounts are counted in';
+
+-- the currencies offered so far stay enabled, the others wait for an admin to enable them
+INSERT INTO "currencies" ("code", "minor_units", "enabled") VALUES
+  ('USD', 2, true),
+  ('EUR', 2, true),
+  ('CAD', 2, true),
+  ('GBP', 2, false),
+  ('CHF', 2, false),
+  ('AUD', 2, false),
+  ('HKD', 2, false),
+  ('TWD', 2, false),
+  ('JPY', 0, false),
+  ('KRW', 0, false),
+  ('BHD', 3, false),
+  ('KWD', 3, false);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
@@ -0,0 +1,25 @@
+-- exchange_rates are the latest rates of the FX provider, against its base currency: a unit of
+-- base_currency buys rate_nanos billionths of a unit of quote_currency. published_at is when the
+-- provider published the rate, fetched_at when the bank last fetched it.
+CREATE TABLE "exchange_rates" (
+  "base_currency" varchar(3) NOT NULL,
+  "quote_currency" varchar(3) NOT NULL,
+  "rate_nanos" bigint NOT NULL,
+  "source" varchar NOT NULL,
+  "published_at" timestamptz NOT NULL,
+  "fetched_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("base_currency", "quote_currency")
+);
+
+ALTER TABLE "exchange_rates" ADD CONSTRAINT "positive_rate" CHECK ("rate_nanos" > 0);
+
+COMMENT ON COLUMN "exchange_rates"."rate_nanos" IS 'the billionths of a unit of quote_currency a unit of base_currency buys';
+
+-- the fx accounts convert the transfers between accounts in different currencies, one per
+-- currency: the payer pays the fx account of its currency, and the fx account of the currency of
+-- the payee pays the payee. Like the suspense accounts, their owner can't log in.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('bank.fx', '!', 'Foreign Exchange', 'fx@simplebank.invalid');
+
+INSERT INTO "accounts" ("owner", "balance", "currency")
+SELECT 'bank.fx', 0, "code" FROM "currencies" ORDER BY "code";
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "user_import_rows";
+
+DROP TABLE IF EXISTS "user_import_files";
+
+DROP TABLE IF EXISTS "user_imports";
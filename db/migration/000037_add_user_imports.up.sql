@@ -0,0 +1,46 @@
+-- user_imports are the imports by an admin of the users of a legacy system, from a csv or ndjson
+-- file the worker creates the users of row by row.
+CREATE TABLE "user_imports" (
+  "id" bigserial PRIMARY KEY,
+  "created_by" varchar NOT NULL,
+  "format" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "total_rows" bigint NOT NULL DEFAULT 0,
+  "imported_rows" bigint NOT NULL DEFAULT 0,
+  "failed_rows" bigint NOT NULL DEFAULT 0,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
+
+ALTER TABLE "user_imports" ADD CONSTRAINT "user_import_format" CHECK ("format" IN ('csv', 'ndjson'));
+
+ALTER TABLE "user_imports" ADD CONSTRAINT "user_import_status" CHECK ("status" IN ('pending', 'completed', 'failed'));
+
+ALTER TABLE "user_imports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+-- user_import_files hold the uploaded files, apart from user_imports like entry_export_files.
+CREATE TABLE "user_import_files" (
+  "import_id" bigint PRIMARY KEY,
+  "content" bytea NOT NULL
+);
+
+ALTER TABLE "user_import_files" ADD FOREIGN KEY ("import_id") REFERENCES "user_imports" ("id") ON DELETE CASCADE;
+
+-- user_import_rows are the report of an import: the user each row of its file imported, or why
+-- the row failed. A row is written in the transaction creating its user, so that a retry of the
+-- import resumes after the last row written.
+CREATE TABLE "user_import_rows" (
+  "import_id" bigint NOT NULL,
+  "line" bigint NOT NULL,
+  "username" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL,
+  "error" varchar NOT NULL DEFAULT '',
+  PRIMARY KEY ("import_id", "line")
+);
+
+ALTER TABLE "user_import_rows" ADD CONSTRAINT "user_import_row_status" CHECK ("status" IN ('imported', 'failed'));
+
+ALTER TABLE "user_import_rows" ADD FOREIGN KEY ("import_id") REFERENCES "user_imports" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "user_import_rows"."line" IS 'the line of the row in the file, from 1';
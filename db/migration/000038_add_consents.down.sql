@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "user_consents";
+
+DROP TABLE IF EXISTS "legal_documents";
@@ -0,0 +1,31 @@
+-- legal_documents are the versions of the terms of service and of the privacy policy the admins
+-- publish, the latest of a kind being the one the users must have accepted.
+CREATE TABLE "legal_documents" (
+  "kind" varchar NOT NULL,
+  "version" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "created_by" varchar NOT NULL,
+  "published_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("kind", "version")
+);
+
+ALTER TABLE "legal_documents" ADD CONSTRAINT "legal_document_kind" CHECK ("kind" IN ('terms', 'privacy'));
+
+ALTER TABLE "legal_documents" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "legal_documents" ("kind", "published_at");
+
+-- user_consents record which version of a document each user accepted, when and from where.
+CREATE TABLE "user_consents" (
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "version" varchar NOT NULL,
+  "client_ip" varchar NOT NULL DEFAULT '',
+  "user_agent" varchar NOT NULL DEFAULT '',
+  "accepted_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "kind", "version")
+);
+
+ALTER TABLE "user_consents" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "user_consents" ADD FOREIGN KEY ("kind", "version") REFERENCES "legal_documents" ("kind", "version");
@@ -0,0 +1,3 @@
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "device_id";
+
+DROP TABLE IF EXISTS "devices";
@@ -0,0 +1,25 @@
+-- devices are the devices the users log in from, identified by a fingerprint their client sends.
+-- A device is trusted once its user confirmed it with a one-time code, and a revoked one can't
+-- renew the tokens of its sessions anymore.
+CREATE TABLE "devices" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "fingerprint" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "verification_hash" varchar NOT NULL DEFAULT '',
+  "verification_attempts" int NOT NULL DEFAULT 0,
+  "verification_sent_at" timestamptz,
+  "trusted_at" timestamptz,
+  "revoked_at" timestamptz,
+  "last_seen_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "devices" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "devices" ("username", "fingerprint");
+
+-- device_id binds the refresh token of a session to the device it logged in from.
+ALTER TABLE "sessions" ADD COLUMN "device_id" bigint REFERENCES "devices" ("id");
+
+CREATE INDEX ON "sessions" ("device_id");
@@ -0,0 +1,26 @@
+-- login_alerts are the logins that differ from the history of their user, e.g. from a new country.
+-- The user reports one with the code of the link of its security notification, which blocks their
+-- sessions and holds the logins until they reset their password with the code emailed to them.
+CREATE TABLE "login_alerts" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "session_id" uuid NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "country" varchar NOT NULL DEFAULT '',
+  "reasons" varchar[] NOT NULL,
+  "report_hash" varchar NOT NULL,
+  "reported_at" timestamptz,
+  "reset_hash" varchar NOT NULL DEFAULT '',
+  "reset_attempts" int NOT NULL DEFAULT 0,
+  "reset_sent_at" timestamptz,
+  "password_reset_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "login_alerts" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "login_alerts" ADD FOREIGN KEY ("session_id") REFERENCES "sessions" ("id");
+
+-- the resets the logins of a user wait for
+CREATE INDEX ON "login_alerts" ("username") WHERE "reported_at" IS NOT NULL AND "password_reset_at" IS NULL;
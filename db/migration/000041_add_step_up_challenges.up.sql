@@ -0,0 +1,14 @@
+-- step_up_challenges are the one-time codes emailed to a user stepping up to a multi-factor
+-- authentication, e.g. before a large transfer. A challenge is completed once, by its code.
+CREATE TABLE "step_up_challenges" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "completed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "step_up_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE INDEX ON "step_up_challenges" ("username");
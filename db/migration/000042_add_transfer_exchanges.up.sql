@@ -0,0 +1,11 @@
+-- transfer_exchanges link the two transfers of an exchange: transfer_id, the payer paying the fx
+-- account of its currency, and exchange_transfer_id, the fx account of the currency of the payee
+-- paying the payee. A dispute of the exchange holds and reverses what the payee was credited.
+CREATE TABLE "transfer_exchanges" (
+  "transfer_id" bigint PRIMARY KEY,
+  "exchange_transfer_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- the transfers are partitioned, so neither id can reference them.
+CREATE UNIQUE INDEX ON "transfer_exchanges" ("exchange_transfer_id");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "card_authorizations_network_transaction_id_key";
+
+ALTER TABLE "card_authorizations" DROP COLUMN IF EXISTS "network_transaction_id";
@@ -0,0 +1,7 @@
+-- the id the card network gives an authorization request, the same when it retries the request, so
+-- that a replay is answered with the authorization of the original instead of debiting again.
+-- The authorizations before it have none.
+ALTER TABLE "card_authorizations" ADD COLUMN "network_transaction_id" varchar NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX "card_authorizations_network_transaction_id_key" ON "card_authorizations" ("network_transaction_id")
+WHERE "network_transaction_id" <> '';
@@ -0,0 +1,13 @@
+-- maintenance_modes holds the one row of the maintenance mode shared by every instance of the bank:
+-- read_only refuses the writes of the apis, and transfers_blocked is the kill switch of the new
+-- transfers. Each instance polls it, so that an admin switching it through one switches them all.
+CREATE TABLE "maintenance_modes" (
+  "id" boolean PRIMARY KEY DEFAULT true,
+  "read_only" boolean NOT NULL DEFAULT false,
+  "transfers_blocked" boolean NOT NULL DEFAULT false,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "maintenance_modes" ADD CONSTRAINT "maintenance_mode_single_row" CHECK ("id");
+
+INSERT INTO "maintenance_modes" DEFAULT VALUES;
@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// advisoryLockID is the key of the advisory lock held while migrating, so that instances started
+// together don't run the same migrations at once: the others wait, then find nothing left to do.
+const advisoryLockID = 4108762115
+
+// Migration is the pair of up and down scripts of a version.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrations returns the embedded migrations, oldest first.
+func Migrations() ([]Migration, error) {
+	entries, err := migrations.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		prefix, rest, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		data, err := migrations.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[uint(version)]
+		if m == nil {
+			m = &Migration{Version: uint(version)}
+			byVersion[uint(version)] = m
+		}
+		switch {
+		case strings.HasSuffix(rest, ".up.sql"):
+			m.Name = strings.TrimSuffix(rest, ".up.sql")
+			m.Up = string(data)
+		case strings.HasSuffix(rest, ".down.sql"):
+			m.Down = string(data)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Migrator applies the embedded migrations, keeping track of them in the schema_migrations
+// table of golang-migrate so that both can be used on the same database.
+type Migrator struct {
+	connPool   *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(connPool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{connPool: connPool, migrations: migrations}, nil
+}
+
+// ErrDirty is returned when the last migration failed halfway, which needs fixing by hand
+// before setting the version with Force.
+var ErrDirty = errors.New("database is dirty, fix it and force the version")
+
+// Up applies all the pending migrations and returns how many there were.
+func (migrator *Migrator) Up(ctx context.Context) (applied int, err error) {
+	err = migrator.withLock(ctx, func(conn *pgx.Conn) error {
+		current, err := migrator.checkVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrator.migrations {
+			if m.Version <= current {
+				continue
+			}
+			if err := apply(ctx, conn, m.Version, m.Version, m.Up); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+			}
+			log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("applied migration")
+			applied++
+		}
+		return nil
+	})
+	return
+}
+
+// Down reverts the steps newest migrations.
+func (migrator *Migrator) Down(ctx context.Context, steps int) error {
+	return migrator.withLock(ctx, func(conn *pgx.Conn) error {
+		current, err := migrator.checkVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrator.migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrator.migrations[i]
+			if m.Version > current {
+				continue
+			}
+			var previous uint
+			if i > 0 {
+				previous = migrator.migrations[i-1].Version
+			}
+			if err := apply(ctx, conn, m.Version, previous, m.Down); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+			}
+			log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("reverted migration")
+			steps--
+		}
+		return nil
+	})
+}
+
+// Version returns the current version, and whether its migration failed halfway.
+func (migrator *Migrator) Version(ctx context.Context) (version uint, dirty bool, err error) {
+	err = migrator.withLock(ctx, func(conn *pgx.Conn) error {
+		version, dirty, err = readVersion(ctx, conn)
+		return err
+	})
+	return
+}
+
+// Force sets the version without running any migration, e.g. once a dirty database is fixed.
+func (migrator *Migrator) Force(ctx context.Context, version uint) error {
+	return migrator.withLock(ctx, func(conn *pgx.Conn) error {
+		return setVersion(ctx, conn, version, false)
+	})
+}
+
+func (migrator *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	pooled, err := migrator.connPool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer pooled.Release()
+	// advisory locks belong to the session, so everything runs on this one connection
+	conn := pooled.Conn()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", advisoryLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockID)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`)
+	if err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (migrator *Migrator) checkVersion(ctx context.Context, conn *pgx.Conn) (uint, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+	return version, nil
+}
+
+// apply runs the script of a migration, recording version as dirty until it succeeds and then
+// version after it. Scripts don't run in a transaction of their own, like with golang-migrate.
+func apply(ctx context.Context, conn *pgx.Conn, version uint, after uint, script string) error {
+	if err := setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if _, err := conn.Exec(ctx, script); err != nil {
+		return err
+	}
+	return setVersion(ctx, conn, after, false)
+}
+
+func readVersion(ctx context.Context, conn *pgx.Conn) (version uint, dirty bool, err error) {
+	err = conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return
+}
+
+// setVersion replaces the single row of schema_migrations, leaving it empty for version 0.
+func setVersion(ctx context.Context, conn *pgx.Conn, version uint, dirty bool) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", int64(version), dirty)
+		return err
+	})
+}
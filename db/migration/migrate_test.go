@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		require.Equal(t, uint(i+1), m.Version, "versions must follow each other")
+		require.NotEmpty(t, m.Name)
+		require.NotEmpty(t, m.Up, "missing up script of %d_%s", m.Version, m.Name)
+		require.NotEmpty(t, m.Down, "missing down script of %d_%s", m.Version, m.Name)
+	}
+	require.Equal(t, LatestVersion(), migrations[len(migrations)-1].Version)
+	require.Equal(t, "init_schema", migrations[0].Name)
+}
@@ -0,0 +1,29 @@
+package migration
+
+import (
+	"embed"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var migrations embed.FS
+
+// LatestVersion returns the version of the newest migration, the one a fully migrated
+// database reports in the schema_migrations table of golang-migrate.
+func LatestVersion() uint {
+	entries, err := migrations.ReadDir(".")
+	if err != nil {
+		return 0
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err == nil && uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/backendmaster/simple_bank/db/sqlc (interfaces: Store)
+// Source: github.com/backendmaster/simple_bank/db/sqlc (interfaces: Store,AccountStore,UserStore,TransferStore,SessionStore,NotificationStore,OutboxStore,LedgerStore,PartitionStore,RetentionStore,AuditStore,DisputeStore,TransferReviewStore,ScreeningStore,CategoryStore,AnalyticsStore,ExportStore,ExternalTransferStore,CardStore,AuthorizationHoldStore,PaymentRequestStore,RefundStore,LoanStore,RewardStore,ReferralStore,TenantStore,CurrencyStore,ExchangeRateStore,UserImportStore,ConsentStore,DeviceStore,LoginAlertStore,StepUpStore,MaintenanceStore)
 
 // Package mockdb is a generated GoMock package.
 package mockdb
@@ -7,10 +7,12 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
 )
 
 // MockStore is a mock of Store interface.
@@ -51,271 +53,9461 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
-// CreateAccount mocks base method.
-func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+// AddAccountHeldBalance mocks base method.
+func (m *MockStore) AddAccountHeldBalance(arg0 context.Context, arg1 db.AddAccountHeldBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "AddAccountHeldBalance", arg0, arg1)
 	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+// AddAccountHeldBalance indicates an expected call of AddAccountHeldBalance.
+func (mr *MockStoreMockRecorder) AddAccountHeldBalance(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountHeldBalance", reflect.TypeOf((*MockStore)(nil).AddAccountHeldBalance), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+// AddDenylistEntryTx mocks base method.
+func (m *MockStore) AddDenylistEntryTx(arg0 context.Context, arg1 db.AddDenylistEntryTxParams) (db.AddDenylistEntryTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "AddDenylistEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AddDenylistEntryTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+// AddDenylistEntryTx indicates an expected call of AddDenylistEntryTx.
+func (mr *MockStoreMockRecorder) AddDenylistEntryTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDenylistEntryTx", reflect.TypeOf((*MockStore)(nil).AddDenylistEntryTx), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// AddDeviceVerificationAttempt mocks base method.
+func (m *MockStore) AddDeviceVerificationAttempt(arg0 context.Context, arg1 int64) (db.Device, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "AddDeviceVerificationAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+// AddDeviceVerificationAttempt indicates an expected call of AddDeviceVerificationAttempt.
+func (mr *MockStoreMockRecorder) AddDeviceVerificationAttempt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeviceVerificationAttempt", reflect.TypeOf((*MockStore)(nil).AddDeviceVerificationAttempt), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// AddLoginAlertResetAttempt mocks base method.
+func (m *MockStore) AddLoginAlertResetAttempt(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "AddLoginAlertResetAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// AddLoginAlertResetAttempt indicates an expected call of AddLoginAlertResetAttempt.
+func (mr *MockStoreMockRecorder) AddLoginAlertResetAttempt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoginAlertResetAttempt", reflect.TypeOf((*MockStore)(nil).AddLoginAlertResetAttempt), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// AddStepUpChallengeAttempt mocks base method.
+func (m *MockStore) AddStepUpChallengeAttempt(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "AddStepUpChallengeAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStepUpChallengeAttempt indicates an expected call of AddStepUpChallengeAttempt.
+func (mr *MockStoreMockRecorder) AddStepUpChallengeAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStepUpChallengeAttempt", reflect.TypeOf((*MockStore)(nil).AddStepUpChallengeAttempt), arg0, arg1)
+}
+
+// AddTransferReviewOTPAttempt mocks base method.
+func (m *MockStore) AddTransferReviewOTPAttempt(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransferReviewOTPAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTransferReviewOTPAttempt indicates an expected call of AddTransferReviewOTPAttempt.
+func (mr *MockStoreMockRecorder) AddTransferReviewOTPAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransferReviewOTPAttempt", reflect.TypeOf((*MockStore)(nil).AddTransferReviewOTPAttempt), arg0, arg1)
+}
+
+// AdjustBalanceTx mocks base method.
+func (m *MockStore) AdjustBalanceTx(arg0 context.Context, arg1 db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalanceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdjustBalanceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalanceTx indicates an expected call of AdjustBalanceTx.
+func (mr *MockStoreMockRecorder) AdjustBalanceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalanceTx", reflect.TypeOf((*MockStore)(nil).AdjustBalanceTx), arg0, arg1)
+}
+
+// AnonymizeUser mocks base method.
+func (m *MockStore) AnonymizeUser(arg0 context.Context, arg1 db.AnonymizeUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUser", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// AnonymizeUser indicates an expected call of AnonymizeUser.
+func (mr *MockStoreMockRecorder) AnonymizeUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUser", reflect.TypeOf((*MockStore)(nil).AnonymizeUser), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// ArchiveEntries mocks base method.
+func (m *MockStore) ArchiveEntries(arg0 context.Context, arg1 db.ArchiveEntriesParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "ArchiveEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveEntries indicates an expected call of ArchiveEntries.
+func (mr *MockStoreMockRecorder) ArchiveEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveEntries", reflect.TypeOf((*MockStore)(nil).ArchiveEntries), arg0, arg1)
+}
+
+// ArchiveEntriesBefore mocks base method.
+func (m *MockStore) ArchiveEntriesBefore(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveEntriesBefore indicates an expected call of ArchiveEntriesBefore.
+func (mr *MockStoreMockRecorder) ArchiveEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveEntriesBefore", reflect.TypeOf((*MockStore)(nil).ArchiveEntriesBefore), arg0, arg1)
+}
+
+// AuthorizeCardTx mocks base method.
+func (m *MockStore) AuthorizeCardTx(arg0 context.Context, arg1 db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeCardTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizeCardTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeCardTx indicates an expected call of AuthorizeCardTx.
+func (mr *MockStoreMockRecorder) AuthorizeCardTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeCardTx", reflect.TypeOf((*MockStore)(nil).AuthorizeCardTx), arg0, arg1)
+}
+
+// BindSessionDevice mocks base method.
+func (m *MockStore) BindSessionDevice(arg0 context.Context, arg1 db.BindSessionDeviceParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindSessionDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BindSessionDevice indicates an expected call of BindSessionDevice.
+func (mr *MockStoreMockRecorder) BindSessionDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindSessionDevice", reflect.TypeOf((*MockStore)(nil).BindSessionDevice), arg0, arg1)
+}
+
+// BlockDeviceSessions mocks base method.
+func (m *MockStore) BlockDeviceSessions(arg0 context.Context, arg1 pgtype.Int8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockDeviceSessions", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// BlockDeviceSessions indicates an expected call of BlockDeviceSessions.
+func (mr *MockStoreMockRecorder) BlockDeviceSessions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockDeviceSessions", reflect.TypeOf((*MockStore)(nil).BlockDeviceSessions), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CaptureAuthorizationHoldTx mocks base method.
+func (m *MockStore) CaptureAuthorizationHoldTx(arg0 context.Context, arg1 db.CaptureAuthorizationHoldTxParams) (db.CaptureAuthorizationHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CaptureAuthorizationHoldTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+// CaptureAuthorizationHoldTx indicates an expected call of CaptureAuthorizationHoldTx.
+func (mr *MockStoreMockRecorder) CaptureAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureAuthorizationHoldTx", reflect.TypeOf((*MockStore)(nil).CaptureAuthorizationHoldTx), arg0, arg1)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// CategorizeEntry mocks base method.
+func (m *MockStore) CategorizeEntry(arg0 context.Context, arg1 db.CategorizeEntryParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CategorizeEntry", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// CategorizeEntry indicates an expected call of CategorizeEntry.
+func (mr *MockStoreMockRecorder) CategorizeEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CategorizeEntry", reflect.TypeOf((*MockStore)(nil).CategorizeEntry), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// CloseAuthorizationHold mocks base method.
+func (m *MockStore) CloseAuthorizationHold(arg0 context.Context, arg1 db.CloseAuthorizationHoldParams) (db.AuthorizationHold, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "CloseAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// CloseAuthorizationHold indicates an expected call of CloseAuthorizationHold.
+func (mr *MockStoreMockRecorder) CloseAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAuthorizationHold", reflect.TypeOf((*MockStore)(nil).CloseAuthorizationHold), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// CloseReferral mocks base method.
+func (m *MockStore) CloseReferral(arg0 context.Context, arg1 db.CloseReferralParams) (db.Referral, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "CloseReferral", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+// CloseReferral indicates an expected call of CloseReferral.
+func (mr *MockStoreMockRecorder) CloseReferral(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseReferral", reflect.TypeOf((*MockStore)(nil).CloseReferral), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// CloseTransferReviewTx mocks base method.
+func (m *MockStore) CloseTransferReviewTx(arg0 context.Context, arg1 db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "CloseTransferReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CloseTransferReviewTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CloseTransferReviewTx indicates an expected call of CloseTransferReviewTx.
+func (mr *MockStoreMockRecorder) CloseTransferReviewTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseTransferReviewTx", reflect.TypeOf((*MockStore)(nil).CloseTransferReviewTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// CompleteExternalTransfer mocks base method.
+func (m *MockStore) CompleteExternalTransfer(arg0 context.Context, arg1 db.CompleteExternalTransferParams) (db.ExternalTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CompleteExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// CompleteExternalTransfer indicates an expected call of CompleteExternalTransfer.
+func (mr *MockStoreMockRecorder) CompleteExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExternalTransfer", reflect.TypeOf((*MockStore)(nil).CompleteExternalTransfer), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// CompleteExternalTransferTx mocks base method.
+func (m *MockStore) CompleteExternalTransferTx(arg0 context.Context, arg1 db.CompleteExternalTransferTxParams) (db.CompleteExternalTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "CompleteExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CompleteExternalTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// CompleteExternalTransferTx indicates an expected call of CompleteExternalTransferTx.
+func (mr *MockStoreMockRecorder) CompleteExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExternalTransferTx", reflect.TypeOf((*MockStore)(nil).CompleteExternalTransferTx), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// CompleteStepUpChallenge mocks base method.
+func (m *MockStore) CompleteStepUpChallenge(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "CompleteStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// CompleteStepUpChallenge indicates an expected call of CompleteStepUpChallenge.
+func (mr *MockStoreMockRecorder) CompleteStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteStepUpChallenge", reflect.TypeOf((*MockStore)(nil).CompleteStepUpChallenge), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// CountActiveSessions mocks base method.
+func (m *MockStore) CountActiveSessions(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "CountActiveSessions", arg0)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// CountActiveSessions indicates an expected call of CountActiveSessions.
+func (mr *MockStoreMockRecorder) CountActiveSessions(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveSessions", reflect.TypeOf((*MockStore)(nil).CountActiveSessions), arg0)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+// CountExportEntries mocks base method.
+func (m *MockStore) CountExportEntries(arg0 context.Context, arg1 db.CountExportEntriesParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTxResult)
+	ret := m.ctrl.Call(m, "CountExportEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// CountExportEntries indicates an expected call of CountExportEntries.
+func (mr *MockStoreMockRecorder) CountExportEntries(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExportEntries", reflect.TypeOf((*MockStore)(nil).CountExportEntries), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// CountRewardedReferrals mocks base method.
+func (m *MockStore) CountRewardedReferrals(arg0 context.Context, arg1 string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "CountRewardedReferrals", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRewardedReferrals indicates an expected call of CountRewardedReferrals.
+func (mr *MockStoreMockRecorder) CountRewardedReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRewardedReferrals", reflect.TypeOf((*MockStore)(nil).CountRewardedReferrals), arg0, arg1)
+}
+
+// CountTransfersSince mocks base method.
+func (m *MockStore) CountTransfersSince(arg0 context.Context, arg1 db.CountTransfersSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersSince indicates an expected call of CountTransfersSince.
+func (mr *MockStoreMockRecorder) CountTransfersSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersSince", reflect.TypeOf((*MockStore)(nil).CountTransfersSince), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
 	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
-// UpdateUser mocks base method.
-func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
+// CreateAuditEntry mocks base method.
+func (m *MockStore) CreateAuditEntry(arg0 context.Context, arg1 db.CreateAuditEntryParams) (db.AuditEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CreateAuditEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEntry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockStoreMockRecorder) CreateAuditEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockStore)(nil).CreateAuditEntry), arg0, arg1)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockStore) CreateAuditLog(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockStoreMockRecorder) CreateAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockStore)(nil).CreateAuditLog), arg0, arg1)
+}
+
+// CreateAuditLogTx mocks base method.
+func (m *MockStore) CreateAuditLogTx(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLogTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLogTx indicates an expected call of CreateAuditLogTx.
+func (mr *MockStoreMockRecorder) CreateAuditLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLogTx", reflect.TypeOf((*MockStore)(nil).CreateAuditLogTx), arg0, arg1)
+}
+
+// CreateAuthorizationHold mocks base method.
+func (m *MockStore) CreateAuthorizationHold(arg0 context.Context, arg1 db.CreateAuthorizationHoldParams) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorizationHold indicates an expected call of CreateAuthorizationHold.
+func (mr *MockStoreMockRecorder) CreateAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorizationHold", reflect.TypeOf((*MockStore)(nil).CreateAuthorizationHold), arg0, arg1)
+}
+
+// CreateCard mocks base method.
+func (m *MockStore) CreateCard(arg0 context.Context, arg1 db.CreateCardParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCard indicates an expected call of CreateCard.
+func (mr *MockStoreMockRecorder) CreateCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockStore)(nil).CreateCard), arg0, arg1)
+}
+
+// CreateCardAuthorization mocks base method.
+func (m *MockStore) CreateCardAuthorization(arg0 context.Context, arg1 db.CreateCardAuthorizationParams) (db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCardAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCardAuthorization indicates an expected call of CreateCardAuthorization.
+func (mr *MockStoreMockRecorder) CreateCardAuthorization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCardAuthorization", reflect.TypeOf((*MockStore)(nil).CreateCardAuthorization), arg0, arg1)
+}
+
+// CreateCategoryRule mocks base method.
+func (m *MockStore) CreateCategoryRule(arg0 context.Context, arg1 db.CreateCategoryRuleParams) (db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategoryRule indicates an expected call of CreateCategoryRule.
+func (mr *MockStoreMockRecorder) CreateCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategoryRule", reflect.TypeOf((*MockStore)(nil).CreateCategoryRule), arg0, arg1)
+}
+
+// CreateDenylistEntry mocks base method.
+func (m *MockStore) CreateDenylistEntry(arg0 context.Context, arg1 db.CreateDenylistEntryParams) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDenylistEntry indicates an expected call of CreateDenylistEntry.
+func (mr *MockStoreMockRecorder) CreateDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDenylistEntry", reflect.TypeOf((*MockStore)(nil).CreateDenylistEntry), arg0, arg1)
+}
+
+// CreateDispute mocks base method.
+func (m *MockStore) CreateDispute(arg0 context.Context, arg1 db.CreateDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDispute indicates an expected call of CreateDispute.
+func (mr *MockStoreMockRecorder) CreateDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDispute", reflect.TypeOf((*MockStore)(nil).CreateDispute), arg0, arg1)
+}
+
+// CreateEntries mocks base method.
+func (m *MockStore) CreateEntries(arg0 context.Context, arg1 []db.CreateEntriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntries indicates an expected call of CreateEntries.
+func (mr *MockStoreMockRecorder) CreateEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntries", reflect.TypeOf((*MockStore)(nil).CreateEntries), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateEntryExport mocks base method.
+func (m *MockStore) CreateEntryExport(arg0 context.Context, arg1 db.CreateEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntryExport indicates an expected call of CreateEntryExport.
+func (mr *MockStoreMockRecorder) CreateEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExport", reflect.TypeOf((*MockStore)(nil).CreateEntryExport), arg0, arg1)
+}
+
+// CreateEntryExportFile mocks base method.
+func (m *MockStore) CreateEntryExportFile(arg0 context.Context, arg1 db.CreateEntryExportFileParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExportFile", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEntryExportFile indicates an expected call of CreateEntryExportFile.
+func (mr *MockStoreMockRecorder) CreateEntryExportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExportFile", reflect.TypeOf((*MockStore)(nil).CreateEntryExportFile), arg0, arg1)
+}
+
+// CreateEntryExportTx mocks base method.
+func (m *MockStore) CreateEntryExportTx(arg0 context.Context, arg1 db.CreateEntryExportTxParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntryExportTx indicates an expected call of CreateEntryExportTx.
+func (mr *MockStoreMockRecorder) CreateEntryExportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExportTx", reflect.TypeOf((*MockStore)(nil).CreateEntryExportTx), arg0, arg1)
+}
+
+// CreateExternalTransfer mocks base method.
+func (m *MockStore) CreateExternalTransfer(arg0 context.Context, arg1 db.CreateExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalTransfer indicates an expected call of CreateExternalTransfer.
+func (mr *MockStoreMockRecorder) CreateExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalTransfer", reflect.TypeOf((*MockStore)(nil).CreateExternalTransfer), arg0, arg1)
+}
+
+// CreateExternalTransferTx mocks base method.
+func (m *MockStore) CreateExternalTransferTx(arg0 context.Context, arg1 db.CreateExternalTransferTxParams) (db.CreateExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalTransferTx indicates an expected call of CreateExternalTransferTx.
+func (mr *MockStoreMockRecorder) CreateExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalTransferTx", reflect.TypeOf((*MockStore)(nil).CreateExternalTransferTx), arg0, arg1)
+}
+
+// CreateImpersonation mocks base method.
+func (m *MockStore) CreateImpersonation(arg0 context.Context, arg1 db.CreateImpersonationParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonation indicates an expected call of CreateImpersonation.
+func (mr *MockStoreMockRecorder) CreateImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonation", reflect.TypeOf((*MockStore)(nil).CreateImpersonation), arg0, arg1)
+}
+
+// CreateImpersonationTx mocks base method.
+func (m *MockStore) CreateImpersonationTx(arg0 context.Context, arg1 db.CreateImpersonationTxParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonationTx indicates an expected call of CreateImpersonationTx.
+func (mr *MockStoreMockRecorder) CreateImpersonationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonationTx", reflect.TypeOf((*MockStore)(nil).CreateImpersonationTx), arg0, arg1)
+}
+
+// CreateLegalDocument mocks base method.
+func (m *MockStore) CreateLegalDocument(arg0 context.Context, arg1 db.CreateLegalDocumentParams) (db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLegalDocument", arg0, arg1)
+	ret0, _ := ret[0].(db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLegalDocument indicates an expected call of CreateLegalDocument.
+func (mr *MockStoreMockRecorder) CreateLegalDocument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLegalDocument", reflect.TypeOf((*MockStore)(nil).CreateLegalDocument), arg0, arg1)
+}
+
+// CreateLegalDocumentTx mocks base method.
+func (m *MockStore) CreateLegalDocumentTx(arg0 context.Context, arg1 db.CreateLegalDocumentTxParams) (db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLegalDocumentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLegalDocumentTx indicates an expected call of CreateLegalDocumentTx.
+func (mr *MockStoreMockRecorder) CreateLegalDocumentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLegalDocumentTx", reflect.TypeOf((*MockStore)(nil).CreateLegalDocumentTx), arg0, arg1)
+}
+
+// CreateLoan mocks base method.
+func (m *MockStore) CreateLoan(arg0 context.Context, arg1 db.CreateLoanParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoan", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoan indicates an expected call of CreateLoan.
+func (mr *MockStoreMockRecorder) CreateLoan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoan", reflect.TypeOf((*MockStore)(nil).CreateLoan), arg0, arg1)
+}
+
+// CreateLoanInstallment mocks base method.
+func (m *MockStore) CreateLoanInstallment(arg0 context.Context, arg1 db.CreateLoanInstallmentParams) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanInstallment indicates an expected call of CreateLoanInstallment.
+func (mr *MockStoreMockRecorder) CreateLoanInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanInstallment", reflect.TypeOf((*MockStore)(nil).CreateLoanInstallment), arg0, arg1)
+}
+
+// CreateLoanOffer mocks base method.
+func (m *MockStore) CreateLoanOffer(arg0 context.Context, arg1 db.CreateLoanOfferParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanOffer indicates an expected call of CreateLoanOffer.
+func (mr *MockStoreMockRecorder) CreateLoanOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanOffer", reflect.TypeOf((*MockStore)(nil).CreateLoanOffer), arg0, arg1)
+}
+
+// CreateLoanOfferTx mocks base method.
+func (m *MockStore) CreateLoanOfferTx(arg0 context.Context, arg1 db.CreateLoanOfferTxParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanOfferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanOfferTx indicates an expected call of CreateLoanOfferTx.
+func (mr *MockStoreMockRecorder) CreateLoanOfferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanOfferTx", reflect.TypeOf((*MockStore)(nil).CreateLoanOfferTx), arg0, arg1)
+}
+
+// CreateLoginAlert mocks base method.
+func (m *MockStore) CreateLoginAlert(arg0 context.Context, arg1 db.CreateLoginAlertParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginAlert indicates an expected call of CreateLoginAlert.
+func (mr *MockStoreMockRecorder) CreateLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginAlert", reflect.TypeOf((*MockStore)(nil).CreateLoginAlert), arg0, arg1)
+}
+
+// CreateMonthlyPartition mocks base method.
+func (m *MockStore) CreateMonthlyPartition(arg0 context.Context, arg1 db.CreateMonthlyPartitionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMonthlyPartition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMonthlyPartition indicates an expected call of CreateMonthlyPartition.
+func (mr *MockStoreMockRecorder) CreateMonthlyPartition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMonthlyPartition", reflect.TypeOf((*MockStore)(nil).CreateMonthlyPartition), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.EventOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.EventOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
+// CreateOutboxTask mocks base method.
+func (m *MockStore) CreateOutboxTask(arg0 context.Context, arg1 db.CreateOutboxTaskParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxTask indicates an expected call of CreateOutboxTask.
+func (mr *MockStoreMockRecorder) CreateOutboxTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTask", reflect.TypeOf((*MockStore)(nil).CreateOutboxTask), arg0, arg1)
+}
+
+// CreateOutboxTasksTx mocks base method.
+func (m *MockStore) CreateOutboxTasksTx(arg0 context.Context, arg1 db.CreateOutboxTasksTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxTasksTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxTasksTx indicates an expected call of CreateOutboxTasksTx.
+func (mr *MockStoreMockRecorder) CreateOutboxTasksTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTasksTx", reflect.TypeOf((*MockStore)(nil).CreateOutboxTasksTx), arg0, arg1)
+}
+
+// CreatePartitions mocks base method.
+func (m *MockStore) CreatePartitions(arg0 context.Context, arg1 time.Time, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePartitions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePartitions indicates an expected call of CreatePartitions.
+func (mr *MockStoreMockRecorder) CreatePartitions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePartitions", reflect.TypeOf((*MockStore)(nil).CreatePartitions), arg0, arg1, arg2)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockStore) CreatePaymentRequest(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockStoreMockRecorder) CreatePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequest), arg0, arg1)
+}
+
+// CreateReferral mocks base method.
+func (m *MockStore) CreateReferral(arg0 context.Context, arg1 db.CreateReferralParams) (db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReferral", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReferral indicates an expected call of CreateReferral.
+func (mr *MockStoreMockRecorder) CreateReferral(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReferral", reflect.TypeOf((*MockStore)(nil).CreateReferral), arg0, arg1)
+}
+
+// CreateReferralCode mocks base method.
+func (m *MockStore) CreateReferralCode(arg0 context.Context, arg1 db.CreateReferralCodeParams) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReferralCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReferralCode indicates an expected call of CreateReferralCode.
+func (mr *MockStoreMockRecorder) CreateReferralCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReferralCode", reflect.TypeOf((*MockStore)(nil).CreateReferralCode), arg0, arg1)
+}
+
+// CreateRefund mocks base method.
+func (m *MockStore) CreateRefund(arg0 context.Context, arg1 db.CreateRefundParams) (db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockStoreMockRecorder) CreateRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockStore)(nil).CreateRefund), arg0, arg1)
+}
+
+// CreateRewardEntry mocks base method.
+func (m *MockStore) CreateRewardEntry(arg0 context.Context, arg1 db.CreateRewardEntryParams) (db.RewardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRewardEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.RewardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRewardEntry indicates an expected call of CreateRewardEntry.
+func (mr *MockStoreMockRecorder) CreateRewardEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardEntry", reflect.TypeOf((*MockStore)(nil).CreateRewardEntry), arg0, arg1)
+}
+
+// CreateScreeningHold mocks base method.
+func (m *MockStore) CreateScreeningHold(arg0 context.Context, arg1 db.CreateScreeningHoldParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScreeningHold", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScreeningHold indicates an expected call of CreateScreeningHold.
+func (mr *MockStoreMockRecorder) CreateScreeningHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScreeningHold", reflect.TypeOf((*MockStore)(nil).CreateScreeningHold), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSessionTx mocks base method.
+func (m *MockStore) CreateSessionTx(arg0 context.Context, arg1 db.CreateSessionTxParams) (db.CreateSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSessionTx indicates an expected call of CreateSessionTx.
+func (mr *MockStoreMockRecorder) CreateSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionTx", reflect.TypeOf((*MockStore)(nil).CreateSessionTx), arg0, arg1)
+}
+
+// CreateStepUpChallenge mocks base method.
+func (m *MockStore) CreateStepUpChallenge(arg0 context.Context, arg1 db.CreateStepUpChallengeParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStepUpChallenge indicates an expected call of CreateStepUpChallenge.
+func (mr *MockStoreMockRecorder) CreateStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepUpChallenge", reflect.TypeOf((*MockStore)(nil).CreateStepUpChallenge), arg0, arg1)
+}
+
+// CreateStepUpChallengeTx mocks base method.
+func (m *MockStore) CreateStepUpChallengeTx(arg0 context.Context, arg1 db.CreateStepUpChallengeTxParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepUpChallengeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStepUpChallengeTx indicates an expected call of CreateStepUpChallengeTx.
+func (mr *MockStoreMockRecorder) CreateStepUpChallengeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepUpChallengeTx", reflect.TypeOf((*MockStore)(nil).CreateStepUpChallengeTx), arg0, arg1)
+}
+
+// CreateTenant mocks base method.
+func (m *MockStore) CreateTenant(arg0 context.Context, arg1 db.CreateTenantParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockStoreMockRecorder) CreateTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockStore)(nil).CreateTenant), arg0, arg1)
+}
+
+// CreateTenantTx mocks base method.
+func (m *MockStore) CreateTenantTx(arg0 context.Context, arg1 db.CreateTenantTxParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenantTx indicates an expected call of CreateTenantTx.
+func (mr *MockStoreMockRecorder) CreateTenantTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenantTx", reflect.TypeOf((*MockStore)(nil).CreateTenantTx), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferExchange mocks base method.
+func (m *MockStore) CreateTransferExchange(arg0 context.Context, arg1 db.CreateTransferExchangeParams) (db.TransferExchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferExchange", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferExchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferExchange indicates an expected call of CreateTransferExchange.
+func (mr *MockStoreMockRecorder) CreateTransferExchange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferExchange", reflect.TypeOf((*MockStore)(nil).CreateTransferExchange), arg0, arg1)
+}
+
+// CreateTransferReview mocks base method.
+func (m *MockStore) CreateTransferReview(arg0 context.Context, arg1 db.CreateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReview indicates an expected call of CreateTransferReview.
+func (mr *MockStoreMockRecorder) CreateTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReview", reflect.TypeOf((*MockStore)(nil).CreateTransferReview), arg0, arg1)
+}
+
+// CreateTransferReviewTx mocks base method.
+func (m *MockStore) CreateTransferReviewTx(arg0 context.Context, arg1 db.CreateTransferReviewTxParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReviewTx indicates an expected call of CreateTransferReviewTx.
+func (mr *MockStoreMockRecorder) CreateTransferReviewTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReviewTx", reflect.TypeOf((*MockStore)(nil).CreateTransferReviewTx), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserConsent mocks base method.
+func (m *MockStore) CreateUserConsent(arg0 context.Context, arg1 db.CreateUserConsentParams) (db.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserConsent", arg0, arg1)
+	ret0, _ := ret[0].(db.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserConsent indicates an expected call of CreateUserConsent.
+func (mr *MockStoreMockRecorder) CreateUserConsent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserConsent", reflect.TypeOf((*MockStore)(nil).CreateUserConsent), arg0, arg1)
+}
+
+// CreateUserImport mocks base method.
+func (m *MockStore) CreateUserImport(arg0 context.Context, arg1 db.CreateUserImportParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImport indicates an expected call of CreateUserImport.
+func (mr *MockStoreMockRecorder) CreateUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImport", reflect.TypeOf((*MockStore)(nil).CreateUserImport), arg0, arg1)
+}
+
+// CreateUserImportFile mocks base method.
+func (m *MockStore) CreateUserImportFile(arg0 context.Context, arg1 db.CreateUserImportFileParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportFile", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserImportFile indicates an expected call of CreateUserImportFile.
+func (mr *MockStoreMockRecorder) CreateUserImportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportFile", reflect.TypeOf((*MockStore)(nil).CreateUserImportFile), arg0, arg1)
+}
+
+// CreateUserImportRow mocks base method.
+func (m *MockStore) CreateUserImportRow(arg0 context.Context, arg1 db.CreateUserImportRowParams) (db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportRow", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImportRow indicates an expected call of CreateUserImportRow.
+func (mr *MockStoreMockRecorder) CreateUserImportRow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportRow", reflect.TypeOf((*MockStore)(nil).CreateUserImportRow), arg0, arg1)
+}
+
+// CreateUserImportTx mocks base method.
+func (m *MockStore) CreateUserImportTx(arg0 context.Context, arg1 db.CreateUserImportTxParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImportTx indicates an expected call of CreateUserImportTx.
+func (mr *MockStoreMockRecorder) CreateUserImportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportTx", reflect.TypeOf((*MockStore)(nil).CreateUserImportTx), arg0, arg1)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteCategoryRule mocks base method.
+func (m *MockStore) DeleteCategoryRule(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategoryRule indicates an expected call of DeleteCategoryRule.
+func (mr *MockStoreMockRecorder) DeleteCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategoryRule", reflect.TypeOf((*MockStore)(nil).DeleteCategoryRule), arg0, arg1)
+}
+
+// DeleteDenylistEntry mocks base method.
+func (m *MockStore) DeleteDenylistEntry(arg0 context.Context, arg1 int64) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDenylistEntry indicates an expected call of DeleteDenylistEntry.
+func (mr *MockStoreMockRecorder) DeleteDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDenylistEntry", reflect.TypeOf((*MockStore)(nil).DeleteDenylistEntry), arg0, arg1)
+}
+
+// DeleteDenylistEntryTx mocks base method.
+func (m *MockStore) DeleteDenylistEntryTx(arg0 context.Context, arg1 db.DeleteDenylistEntryTxParams) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDenylistEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDenylistEntryTx indicates an expected call of DeleteDenylistEntryTx.
+func (mr *MockStoreMockRecorder) DeleteDenylistEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDenylistEntryTx", reflect.TypeOf((*MockStore)(nil).DeleteDenylistEntryTx), arg0, arg1)
+}
+
+// DeleteExpiredEntryExports mocks base method.
+func (m *MockStore) DeleteExpiredEntryExports(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredEntryExports", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredEntryExports indicates an expected call of DeleteExpiredEntryExports.
+func (mr *MockStoreMockRecorder) DeleteExpiredEntryExports(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredEntryExports", reflect.TypeOf((*MockStore)(nil).DeleteExpiredEntryExports), arg0)
+}
+
+// DeleteUserTx mocks base method.
+func (m *MockStore) DeleteUserTx(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserTx indicates an expected call of DeleteUserTx.
+func (mr *MockStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockStore)(nil).DeleteUserTx), arg0, arg1)
+}
+
+// DisburseLoanTx mocks base method.
+func (m *MockStore) DisburseLoanTx(arg0 context.Context, arg1 db.DisburseLoanTxParams) (db.DisburseLoanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisburseLoanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DisburseLoanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisburseLoanTx indicates an expected call of DisburseLoanTx.
+func (mr *MockStoreMockRecorder) DisburseLoanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisburseLoanTx", reflect.TypeOf((*MockStore)(nil).DisburseLoanTx), arg0, arg1)
+}
+
+// FailEntryExport mocks base method.
+func (m *MockStore) FailEntryExport(arg0 context.Context, arg1 db.FailEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailEntryExport indicates an expected call of FailEntryExport.
+func (mr *MockStoreMockRecorder) FailEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailEntryExport", reflect.TypeOf((*MockStore)(nil).FailEntryExport), arg0, arg1)
+}
+
+// FailUserImport mocks base method.
+func (m *MockStore) FailUserImport(arg0 context.Context, arg1 db.FailUserImportParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailUserImport indicates an expected call of FailUserImport.
+func (mr *MockStoreMockRecorder) FailUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUserImport", reflect.TypeOf((*MockStore)(nil).FailUserImport), arg0, arg1)
+}
+
+// FinishEntryExport mocks base method.
+func (m *MockStore) FinishEntryExport(arg0 context.Context, arg1 db.FinishEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishEntryExport indicates an expected call of FinishEntryExport.
+func (mr *MockStoreMockRecorder) FinishEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishEntryExport", reflect.TypeOf((*MockStore)(nil).FinishEntryExport), arg0, arg1)
+}
+
+// FinishEntryExportTx mocks base method.
+func (m *MockStore) FinishEntryExportTx(arg0 context.Context, arg1 db.FinishEntryExportTxParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishEntryExportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishEntryExportTx indicates an expected call of FinishEntryExportTx.
+func (mr *MockStoreMockRecorder) FinishEntryExportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishEntryExportTx", reflect.TypeOf((*MockStore)(nil).FinishEntryExportTx), arg0, arg1)
+}
+
+// FinishLoginAlertReset mocks base method.
+func (m *MockStore) FinishLoginAlertReset(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLoginAlertReset", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLoginAlertReset indicates an expected call of FinishLoginAlertReset.
+func (mr *MockStoreMockRecorder) FinishLoginAlertReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLoginAlertReset", reflect.TypeOf((*MockStore)(nil).FinishLoginAlertReset), arg0, arg1)
+}
+
+// FinishUserImport mocks base method.
+func (m *MockStore) FinishUserImport(arg0 context.Context, arg1 int64) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishUserImport indicates an expected call of FinishUserImport.
+func (mr *MockStoreMockRecorder) FinishUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishUserImport", reflect.TypeOf((*MockStore)(nil).FinishUserImport), arg0, arg1)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockStore) FreezeAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockStoreMockRecorder) FreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockStore)(nil).FreezeAccount), arg0, arg1)
+}
+
+// FreezeAccountTx mocks base method.
+func (m *MockStore) FreezeAccountTx(arg0 context.Context, arg1 db.FreezeAccountTxParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccountTx indicates an expected call of FreezeAccountTx.
+func (mr *MockStoreMockRecorder) FreezeAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccountTx", reflect.TypeOf((*MockStore)(nil).FreezeAccountTx), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountAlert mocks base method.
+func (m *MockStore) GetAccountAlert(arg0 context.Context, arg1 int64) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountAlert indicates an expected call of GetAccountAlert.
+func (mr *MockStoreMockRecorder) GetAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAlert", reflect.TypeOf((*MockStore)(nil).GetAccountAlert), arg0, arg1)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(arg0 context.Context, arg1 db.GetAccountByNumberParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountIncludeDeleted mocks base method.
+func (m *MockStore) GetAccountIncludeDeleted(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountIncludeDeleted indicates an expected call of GetAccountIncludeDeleted.
+func (mr *MockStoreMockRecorder) GetAccountIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountIncludeDeleted", reflect.TypeOf((*MockStore)(nil).GetAccountIncludeDeleted), arg0, arg1)
+}
+
+// GetAuthorizationHold mocks base method.
+func (m *MockStore) GetAuthorizationHold(arg0 context.Context, arg1 int64) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorizationHold indicates an expected call of GetAuthorizationHold.
+func (mr *MockStoreMockRecorder) GetAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationHold", reflect.TypeOf((*MockStore)(nil).GetAuthorizationHold), arg0, arg1)
+}
+
+// GetAuthorizationHoldForUpdate mocks base method.
+func (m *MockStore) GetAuthorizationHoldForUpdate(arg0 context.Context, arg1 int64) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorizationHoldForUpdate indicates an expected call of GetAuthorizationHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetAuthorizationHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetAuthorizationHoldForUpdate), arg0, arg1)
+}
+
+// GetCard mocks base method.
+func (m *MockStore) GetCard(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCard indicates an expected call of GetCard.
+func (mr *MockStoreMockRecorder) GetCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockStore)(nil).GetCard), arg0, arg1)
+}
+
+// GetCardAuthorizationByNetworkTransactionID mocks base method.
+func (m *MockStore) GetCardAuthorizationByNetworkTransactionID(arg0 context.Context, arg1 string) (db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardAuthorizationByNetworkTransactionID", arg0, arg1)
+	ret0, _ := ret[0].(db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardAuthorizationByNetworkTransactionID indicates an expected call of GetCardAuthorizationByNetworkTransactionID.
+func (mr *MockStoreMockRecorder) GetCardAuthorizationByNetworkTransactionID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardAuthorizationByNetworkTransactionID", reflect.TypeOf((*MockStore)(nil).GetCardAuthorizationByNetworkTransactionID), arg0, arg1)
+}
+
+// GetCardByFingerprint mocks base method.
+func (m *MockStore) GetCardByFingerprint(arg0 context.Context, arg1 string) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardByFingerprint", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardByFingerprint indicates an expected call of GetCardByFingerprint.
+func (mr *MockStoreMockRecorder) GetCardByFingerprint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardByFingerprint", reflect.TypeOf((*MockStore)(nil).GetCardByFingerprint), arg0, arg1)
+}
+
+// GetCardForUpdate mocks base method.
+func (m *MockStore) GetCardForUpdate(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardForUpdate indicates an expected call of GetCardForUpdate.
+func (mr *MockStoreMockRecorder) GetCardForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardForUpdate", reflect.TypeOf((*MockStore)(nil).GetCardForUpdate), arg0, arg1)
+}
+
+// GetCategoryRule mocks base method.
+func (m *MockStore) GetCategoryRule(arg0 context.Context, arg1 int64) (db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryRule indicates an expected call of GetCategoryRule.
+func (mr *MockStoreMockRecorder) GetCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryRule", reflect.TypeOf((*MockStore)(nil).GetCategoryRule), arg0, arg1)
+}
+
+// GetCurrency mocks base method.
+func (m *MockStore) GetCurrency(arg0 context.Context, arg1 string) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrency indicates an expected call of GetCurrency.
+func (mr *MockStoreMockRecorder) GetCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockStore)(nil).GetCurrency), arg0, arg1)
+}
+
+// GetDenylistEntry mocks base method.
+func (m *MockStore) GetDenylistEntry(arg0 context.Context, arg1 int64) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDenylistEntry indicates an expected call of GetDenylistEntry.
+func (mr *MockStoreMockRecorder) GetDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDenylistEntry", reflect.TypeOf((*MockStore)(nil).GetDenylistEntry), arg0, arg1)
+}
+
+// GetDevice mocks base method.
+func (m *MockStore) GetDevice(arg0 context.Context, arg1 int64) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevice indicates an expected call of GetDevice.
+func (mr *MockStoreMockRecorder) GetDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevice", reflect.TypeOf((*MockStore)(nil).GetDevice), arg0, arg1)
+}
+
+// GetDispute mocks base method.
+func (m *MockStore) GetDispute(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDispute indicates an expected call of GetDispute.
+func (mr *MockStoreMockRecorder) GetDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDispute", reflect.TypeOf((*MockStore)(nil).GetDispute), arg0, arg1)
+}
+
+// GetDisputeForUpdate mocks base method.
+func (m *MockStore) GetDisputeForUpdate(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeForUpdate indicates an expected call of GetDisputeForUpdate.
+func (mr *MockStoreMockRecorder) GetDisputeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeForUpdate", reflect.TypeOf((*MockStore)(nil).GetDisputeForUpdate), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetEntryExport mocks base method.
+func (m *MockStore) GetEntryExport(arg0 context.Context, arg1 int64) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryExport indicates an expected call of GetEntryExport.
+func (mr *MockStoreMockRecorder) GetEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryExport", reflect.TypeOf((*MockStore)(nil).GetEntryExport), arg0, arg1)
+}
+
+// GetEntryExportFile mocks base method.
+func (m *MockStore) GetEntryExportFile(arg0 context.Context, arg1 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryExportFile", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryExportFile indicates an expected call of GetEntryExportFile.
+func (mr *MockStoreMockRecorder) GetEntryExportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryExportFile", reflect.TypeOf((*MockStore)(nil).GetEntryExportFile), arg0, arg1)
+}
+
+// GetExternalTransfer mocks base method.
+func (m *MockStore) GetExternalTransfer(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransfer indicates an expected call of GetExternalTransfer.
+func (mr *MockStoreMockRecorder) GetExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransfer", reflect.TypeOf((*MockStore)(nil).GetExternalTransfer), arg0, arg1)
+}
+
+// GetExternalTransferForUpdate mocks base method.
+func (m *MockStore) GetExternalTransferForUpdate(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransferForUpdate indicates an expected call of GetExternalTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetExternalTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetExternalTransferForUpdate), arg0, arg1)
+}
+
+// GetFXAccount mocks base method.
+func (m *MockStore) GetFXAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXAccount indicates an expected call of GetFXAccount.
+func (mr *MockStoreMockRecorder) GetFXAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXAccount", reflect.TypeOf((*MockStore)(nil).GetFXAccount), arg0, arg1)
+}
+
+// GetFirstQualifyingTransfer mocks base method.
+func (m *MockStore) GetFirstQualifyingTransfer(arg0 context.Context, arg1 db.GetFirstQualifyingTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstQualifyingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstQualifyingTransfer indicates an expected call of GetFirstQualifyingTransfer.
+func (mr *MockStoreMockRecorder) GetFirstQualifyingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstQualifyingTransfer", reflect.TypeOf((*MockStore)(nil).GetFirstQualifyingTransfer), arg0, arg1)
+}
+
+// GetHeldAmount mocks base method.
+func (m *MockStore) GetHeldAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeldAmount indicates an expected call of GetHeldAmount.
+func (mr *MockStoreMockRecorder) GetHeldAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldAmount", reflect.TypeOf((*MockStore)(nil).GetHeldAmount), arg0, arg1)
+}
+
+// GetImpersonation mocks base method.
+func (m *MockStore) GetImpersonation(arg0 context.Context, arg1 uuid.UUID) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImpersonation indicates an expected call of GetImpersonation.
+func (mr *MockStoreMockRecorder) GetImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImpersonation", reflect.TypeOf((*MockStore)(nil).GetImpersonation), arg0, arg1)
+}
+
+// GetLastAuditLogHash mocks base method.
+func (m *MockStore) GetLastAuditLogHash(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastAuditLogHash", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAuditLogHash indicates an expected call of GetLastAuditLogHash.
+func (mr *MockStoreMockRecorder) GetLastAuditLogHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAuditLogHash", reflect.TypeOf((*MockStore)(nil).GetLastAuditLogHash), arg0)
+}
+
+// GetLastLoginCountry mocks base method.
+func (m *MockStore) GetLastLoginCountry(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastLoginCountry", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastLoginCountry indicates an expected call of GetLastLoginCountry.
+func (mr *MockStoreMockRecorder) GetLastLoginCountry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastLoginCountry", reflect.TypeOf((*MockStore)(nil).GetLastLoginCountry), arg0, arg1)
+}
+
+// GetLastUserImportLine mocks base method.
+func (m *MockStore) GetLastUserImportLine(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastUserImportLine", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastUserImportLine indicates an expected call of GetLastUserImportLine.
+func (mr *MockStoreMockRecorder) GetLastUserImportLine(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastUserImportLine", reflect.TypeOf((*MockStore)(nil).GetLastUserImportLine), arg0, arg1)
+}
+
+// GetLendingAccount mocks base method.
+func (m *MockStore) GetLendingAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLendingAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLendingAccount indicates an expected call of GetLendingAccount.
+func (mr *MockStoreMockRecorder) GetLendingAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLendingAccount", reflect.TypeOf((*MockStore)(nil).GetLendingAccount), arg0, arg1)
+}
+
+// GetLoan mocks base method.
+func (m *MockStore) GetLoan(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoan", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoan indicates an expected call of GetLoan.
+func (mr *MockStoreMockRecorder) GetLoan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoan", reflect.TypeOf((*MockStore)(nil).GetLoan), arg0, arg1)
+}
+
+// GetLoanForUpdate mocks base method.
+func (m *MockStore) GetLoanForUpdate(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanForUpdate indicates an expected call of GetLoanForUpdate.
+func (mr *MockStoreMockRecorder) GetLoanForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanForUpdate", reflect.TypeOf((*MockStore)(nil).GetLoanForUpdate), arg0, arg1)
+}
+
+// GetLoanInstallmentForUpdate mocks base method.
+func (m *MockStore) GetLoanInstallmentForUpdate(arg0 context.Context, arg1 int64) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanInstallmentForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanInstallmentForUpdate indicates an expected call of GetLoanInstallmentForUpdate.
+func (mr *MockStoreMockRecorder) GetLoanInstallmentForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanInstallmentForUpdate", reflect.TypeOf((*MockStore)(nil).GetLoanInstallmentForUpdate), arg0, arg1)
+}
+
+// GetLoanOffer mocks base method.
+func (m *MockStore) GetLoanOffer(arg0 context.Context, arg1 int64) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanOffer indicates an expected call of GetLoanOffer.
+func (mr *MockStoreMockRecorder) GetLoanOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanOffer", reflect.TypeOf((*MockStore)(nil).GetLoanOffer), arg0, arg1)
+}
+
+// GetLoanStanding mocks base method.
+func (m *MockStore) GetLoanStanding(arg0 context.Context, arg1 int64) (db.GetLoanStandingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanStanding", arg0, arg1)
+	ret0, _ := ret[0].(db.GetLoanStandingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanStanding indicates an expected call of GetLoanStanding.
+func (mr *MockStoreMockRecorder) GetLoanStanding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanStanding", reflect.TypeOf((*MockStore)(nil).GetLoanStanding), arg0, arg1)
+}
+
+// GetLoginAlert mocks base method.
+func (m *MockStore) GetLoginAlert(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAlert indicates an expected call of GetLoginAlert.
+func (mr *MockStoreMockRecorder) GetLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAlert", reflect.TypeOf((*MockStore)(nil).GetLoginAlert), arg0, arg1)
+}
+
+// GetMaintenanceMode mocks base method.
+func (m *MockStore) GetMaintenanceMode(arg0 context.Context) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceMode", arg0)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceMode indicates an expected call of GetMaintenanceMode.
+func (mr *MockStoreMockRecorder) GetMaintenanceMode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceMode", reflect.TypeOf((*MockStore)(nil).GetMaintenanceMode), arg0)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockStore) GetNotificationPreferences(arg0 context.Context, arg1 string) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferences), arg0, arg1)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockStore) GetPaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockStoreMockRecorder) GetPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockStore)(nil).GetPaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockStore) GetPaymentRequestForUpdate(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestForUpdate), arg0, arg1)
+}
+
+// GetPrimaryAccount mocks base method.
+func (m *MockStore) GetPrimaryAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrimaryAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrimaryAccount indicates an expected call of GetPrimaryAccount.
+func (mr *MockStoreMockRecorder) GetPrimaryAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrimaryAccount", reflect.TypeOf((*MockStore)(nil).GetPrimaryAccount), arg0, arg1)
+}
+
+// GetReferralCode mocks base method.
+func (m *MockStore) GetReferralCode(arg0 context.Context, arg1 string) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralCode indicates an expected call of GetReferralCode.
+func (mr *MockStoreMockRecorder) GetReferralCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCode", reflect.TypeOf((*MockStore)(nil).GetReferralCode), arg0, arg1)
+}
+
+// GetReferralCodeByCode mocks base method.
+func (m *MockStore) GetReferralCodeByCode(arg0 context.Context, arg1 string) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralCodeByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralCodeByCode indicates an expected call of GetReferralCodeByCode.
+func (mr *MockStoreMockRecorder) GetReferralCodeByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCodeByCode", reflect.TypeOf((*MockStore)(nil).GetReferralCodeByCode), arg0, arg1)
+}
+
+// GetReferralForUpdate mocks base method.
+func (m *MockStore) GetReferralForUpdate(arg0 context.Context, arg1 int64) (db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralForUpdate indicates an expected call of GetReferralForUpdate.
+func (mr *MockStoreMockRecorder) GetReferralForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralForUpdate", reflect.TypeOf((*MockStore)(nil).GetReferralForUpdate), arg0, arg1)
+}
+
+// GetRefundedAmount mocks base method.
+func (m *MockStore) GetRefundedAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundedAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundedAmount indicates an expected call of GetRefundedAmount.
+func (mr *MockStoreMockRecorder) GetRefundedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundedAmount", reflect.TypeOf((*MockStore)(nil).GetRefundedAmount), arg0, arg1)
+}
+
+// GetReturnedAmount mocks base method.
+func (m *MockStore) GetReturnedAmount(arg0 context.Context, arg1 int64) (db.GetReturnedAmountRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturnedAmount", arg0, arg1)
+	ret0, _ := ret[0].(db.GetReturnedAmountRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturnedAmount indicates an expected call of GetReturnedAmount.
+func (mr *MockStoreMockRecorder) GetReturnedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturnedAmount", reflect.TypeOf((*MockStore)(nil).GetReturnedAmount), arg0, arg1)
+}
+
+// GetRewardBalance mocks base method.
+func (m *MockStore) GetRewardBalance(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardBalance", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardBalance indicates an expected call of GetRewardBalance.
+func (mr *MockStoreMockRecorder) GetRewardBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardBalance", reflect.TypeOf((*MockStore)(nil).GetRewardBalance), arg0, arg1)
+}
+
+// GetRewardsAccount mocks base method.
+func (m *MockStore) GetRewardsAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardsAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardsAccount indicates an expected call of GetRewardsAccount.
+func (mr *MockStoreMockRecorder) GetRewardsAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardsAccount", reflect.TypeOf((*MockStore)(nil).GetRewardsAccount), arg0, arg1)
+}
+
+// GetScreeningHold mocks base method.
+func (m *MockStore) GetScreeningHold(arg0 context.Context, arg1 int64) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreeningHold", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreeningHold indicates an expected call of GetScreeningHold.
+func (mr *MockStoreMockRecorder) GetScreeningHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreeningHold", reflect.TypeOf((*MockStore)(nil).GetScreeningHold), arg0, arg1)
+}
+
+// GetScreeningHoldForUpdate mocks base method.
+func (m *MockStore) GetScreeningHoldForUpdate(arg0 context.Context, arg1 int64) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreeningHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreeningHoldForUpdate indicates an expected call of GetScreeningHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetScreeningHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreeningHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetScreeningHoldForUpdate), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetStepUpChallenge mocks base method.
+func (m *MockStore) GetStepUpChallenge(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStepUpChallenge indicates an expected call of GetStepUpChallenge.
+func (mr *MockStoreMockRecorder) GetStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStepUpChallenge", reflect.TypeOf((*MockStore)(nil).GetStepUpChallenge), arg0, arg1)
+}
+
+// GetSuspenseAccount mocks base method.
+func (m *MockStore) GetSuspenseAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuspenseAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuspenseAccount indicates an expected call of GetSuspenseAccount.
+func (mr *MockStoreMockRecorder) GetSuspenseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuspenseAccount", reflect.TypeOf((*MockStore)(nil).GetSuspenseAccount), arg0, arg1)
+}
+
+// GetTenant mocks base method.
+func (m *MockStore) GetTenant(arg0 context.Context, arg1 string) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockStoreMockRecorder) GetTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockStore)(nil).GetTenant), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferExchange mocks base method.
+func (m *MockStore) GetTransferExchange(arg0 context.Context, arg1 int64) (db.TransferExchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferExchange", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferExchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferExchange indicates an expected call of GetTransferExchange.
+func (mr *MockStoreMockRecorder) GetTransferExchange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferExchange", reflect.TypeOf((*MockStore)(nil).GetTransferExchange), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetTransferReview mocks base method.
+func (m *MockStore) GetTransferReview(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReview indicates an expected call of GetTransferReview.
+func (mr *MockStoreMockRecorder) GetTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReview", reflect.TypeOf((*MockStore)(nil).GetTransferReview), arg0, arg1)
+}
+
+// GetTransferReviewForUpdate mocks base method.
+func (m *MockStore) GetTransferReviewForUpdate(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReviewForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReviewForUpdate indicates an expected call of GetTransferReviewForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferReviewForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReviewForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferReviewForUpdate), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserImport mocks base method.
+func (m *MockStore) GetUserImport(arg0 context.Context, arg1 int64) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImport indicates an expected call of GetUserImport.
+func (mr *MockStoreMockRecorder) GetUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImport", reflect.TypeOf((*MockStore)(nil).GetUserImport), arg0, arg1)
+}
+
+// GetUserImportFile mocks base method.
+func (m *MockStore) GetUserImportFile(arg0 context.Context, arg1 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImportFile", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImportFile indicates an expected call of GetUserImportFile.
+func (mr *MockStoreMockRecorder) GetUserImportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImportFile", reflect.TypeOf((*MockStore)(nil).GetUserImportFile), arg0, arg1)
+}
+
+// GetUserIncludeDeleted mocks base method.
+func (m *MockStore) GetUserIncludeDeleted(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIncludeDeleted indicates an expected call of GetUserIncludeDeleted.
+func (mr *MockStoreMockRecorder) GetUserIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIncludeDeleted", reflect.TypeOf((*MockStore)(nil).GetUserIncludeDeleted), arg0, arg1)
+}
+
+// HasPendingPasswordReset mocks base method.
+func (m *MockStore) HasPendingPasswordReset(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPendingPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPendingPasswordReset indicates an expected call of HasPendingPasswordReset.
+func (mr *MockStoreMockRecorder) HasPendingPasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPendingPasswordReset", reflect.TypeOf((*MockStore)(nil).HasPendingPasswordReset), arg0, arg1)
+}
+
+// HasTransferBetween mocks base method.
+func (m *MockStore) HasTransferBetween(arg0 context.Context, arg1 db.HasTransferBetweenParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTransferBetween", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTransferBetween indicates an expected call of HasTransferBetween.
+func (mr *MockStoreMockRecorder) HasTransferBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTransferBetween", reflect.TypeOf((*MockStore)(nil).HasTransferBetween), arg0, arg1)
+}
+
+// HoldUsersMatchingDenylist mocks base method.
+func (m *MockStore) HoldUsersMatchingDenylist(arg0 context.Context, arg1 db.HoldUsersMatchingDenylistParams) ([]db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldUsersMatchingDenylist", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldUsersMatchingDenylist indicates an expected call of HoldUsersMatchingDenylist.
+func (mr *MockStoreMockRecorder) HoldUsersMatchingDenylist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldUsersMatchingDenylist", reflect.TypeOf((*MockStore)(nil).HoldUsersMatchingDenylist), arg0, arg1)
+}
+
+// ImportUserTx mocks base method.
+func (m *MockStore) ImportUserTx(arg0 context.Context, arg1 db.ImportUserTxParams) (db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportUserTx indicates an expected call of ImportUserTx.
+func (mr *MockStoreMockRecorder) ImportUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportUserTx", reflect.TypeOf((*MockStore)(nil).ImportUserTx), arg0, arg1)
+}
+
+// ListAccountAuthorizationHolds mocks base method.
+func (m *MockStore) ListAccountAuthorizationHolds(arg0 context.Context, arg1 db.ListAccountAuthorizationHoldsParams) ([]db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountAuthorizationHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountAuthorizationHolds indicates an expected call of ListAccountAuthorizationHolds.
+func (mr *MockStoreMockRecorder) ListAccountAuthorizationHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountAuthorizationHolds", reflect.TypeOf((*MockStore)(nil).ListAccountAuthorizationHolds), arg0, arg1)
+}
+
+// ListAccountCards mocks base method.
+func (m *MockStore) ListAccountCards(arg0 context.Context, arg1 int64) ([]db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountCards", arg0, arg1)
+	ret0, _ := ret[0].([]db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountCards indicates an expected call of ListAccountCards.
+func (mr *MockStoreMockRecorder) ListAccountCards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountCards", reflect.TypeOf((*MockStore)(nil).ListAccountCards), arg0, arg1)
+}
+
+// ListAccountExternalTransfers mocks base method.
+func (m *MockStore) ListAccountExternalTransfers(arg0 context.Context, arg1 db.ListAccountExternalTransfersParams) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountExternalTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountExternalTransfers indicates an expected call of ListAccountExternalTransfers.
+func (mr *MockStoreMockRecorder) ListAccountExternalTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountExternalTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountExternalTransfers), arg0, arg1)
+}
+
+// ListAccountLoans mocks base method.
+func (m *MockStore) ListAccountLoans(arg0 context.Context, arg1 db.ListAccountLoansParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountLoans", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountLoans indicates an expected call of ListAccountLoans.
+func (mr *MockStoreMockRecorder) ListAccountLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountLoans", reflect.TypeOf((*MockStore)(nil).ListAccountLoans), arg0, arg1)
+}
+
+// ListAccountPaymentRequests mocks base method.
+func (m *MockStore) ListAccountPaymentRequests(arg0 context.Context, arg1 db.ListAccountPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountPaymentRequests indicates an expected call of ListAccountPaymentRequests.
+func (mr *MockStoreMockRecorder) ListAccountPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListAccountPaymentRequests), arg0, arg1)
+}
+
+// ListAccountScreeningMatches mocks base method.
+func (m *MockStore) ListAccountScreeningMatches(arg0 context.Context, arg1 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountScreeningMatches", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountScreeningMatches indicates an expected call of ListAccountScreeningMatches.
+func (mr *MockStoreMockRecorder) ListAccountScreeningMatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountScreeningMatches", reflect.TypeOf((*MockStore)(nil).ListAccountScreeningMatches), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccountsAfter mocks base method.
+func (m *MockStore) ListAccountsAfter(arg0 context.Context, arg1 db.ListAccountsAfterParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsAfter indicates an expected call of ListAccountsAfter.
+func (mr *MockStoreMockRecorder) ListAccountsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsAfter", reflect.TypeOf((*MockStore)(nil).ListAccountsAfter), arg0, arg1)
+}
+
+// ListAccountsBefore mocks base method.
+func (m *MockStore) ListAccountsBefore(arg0 context.Context, arg1 db.ListAccountsBeforeParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsBefore indicates an expected call of ListAccountsBefore.
+func (mr *MockStoreMockRecorder) ListAccountsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsBefore", reflect.TypeOf((*MockStore)(nil).ListAccountsBefore), arg0, arg1)
+}
+
+// ListAccountsIncludeDeleted mocks base method.
+func (m *MockStore) ListAccountsIncludeDeleted(arg0 context.Context, arg1 db.ListAccountsIncludeDeletedParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsIncludeDeleted indicates an expected call of ListAccountsIncludeDeleted.
+func (mr *MockStoreMockRecorder) ListAccountsIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsIncludeDeleted", reflect.TypeOf((*MockStore)(nil).ListAccountsIncludeDeleted), arg0, arg1)
+}
+
+// ListAllAccounts mocks base method.
+func (m *MockStore) ListAllAccounts(arg0 context.Context, arg1 string) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllAccounts indicates an expected call of ListAllAccounts.
+func (mr *MockStoreMockRecorder) ListAllAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllAccounts", reflect.TypeOf((*MockStore)(nil).ListAllAccounts), arg0, arg1)
+}
+
+// ListAuditEntries mocks base method.
+func (m *MockStore) ListAuditEntries(arg0 context.Context, arg1 db.ListAuditEntriesParams) ([]db.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntries indicates an expected call of ListAuditEntries.
+func (mr *MockStoreMockRecorder) ListAuditEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntries", reflect.TypeOf((*MockStore)(nil).ListAuditEntries), arg0, arg1)
+}
+
+// ListAuditLogsAfter mocks base method.
+func (m *MockStore) ListAuditLogsAfter(arg0 context.Context, arg1 db.ListAuditLogsAfterParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogsAfter indicates an expected call of ListAuditLogsAfter.
+func (mr *MockStoreMockRecorder) ListAuditLogsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogsAfter", reflect.TypeOf((*MockStore)(nil).ListAuditLogsAfter), arg0, arg1)
+}
+
+// ListAuditLogsBefore mocks base method.
+func (m *MockStore) ListAuditLogsBefore(arg0 context.Context, arg1 db.ListAuditLogsBeforeParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogsBefore indicates an expected call of ListAuditLogsBefore.
+func (mr *MockStoreMockRecorder) ListAuditLogsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogsBefore", reflect.TypeOf((*MockStore)(nil).ListAuditLogsBefore), arg0, arg1)
+}
+
+// ListBalanceMismatches mocks base method.
+func (m *MockStore) ListBalanceMismatches(arg0 context.Context, arg1 int32) ([]db.ListBalanceMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceMismatches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBalanceMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceMismatches indicates an expected call of ListBalanceMismatches.
+func (mr *MockStoreMockRecorder) ListBalanceMismatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceMismatches", reflect.TypeOf((*MockStore)(nil).ListBalanceMismatches), arg0, arg1)
+}
+
+// ListCardAuthorizations mocks base method.
+func (m *MockStore) ListCardAuthorizations(arg0 context.Context, arg1 db.ListCardAuthorizationsParams) ([]db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCardAuthorizations", arg0, arg1)
+	ret0, _ := ret[0].([]db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCardAuthorizations indicates an expected call of ListCardAuthorizations.
+func (mr *MockStoreMockRecorder) ListCardAuthorizations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCardAuthorizations", reflect.TypeOf((*MockStore)(nil).ListCardAuthorizations), arg0, arg1)
+}
+
+// ListCategoryRules mocks base method.
+func (m *MockStore) ListCategoryRules(arg0 context.Context, arg1 string) ([]db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategoryRules", arg0, arg1)
+	ret0, _ := ret[0].([]db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategoryRules indicates an expected call of ListCategoryRules.
+func (mr *MockStoreMockRecorder) ListCategoryRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategoryRules", reflect.TypeOf((*MockStore)(nil).ListCategoryRules), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockStore) ListCurrencies(arg0 context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockStoreMockRecorder) ListCurrencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockStore)(nil).ListCurrencies), arg0)
+}
+
+// ListDelinquentLoans mocks base method.
+func (m *MockStore) ListDelinquentLoans(arg0 context.Context, arg1 db.ListDelinquentLoansParams) ([]db.ListDelinquentLoansRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelinquentLoans", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDelinquentLoansRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelinquentLoans indicates an expected call of ListDelinquentLoans.
+func (mr *MockStoreMockRecorder) ListDelinquentLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelinquentLoans", reflect.TypeOf((*MockStore)(nil).ListDelinquentLoans), arg0, arg1)
+}
+
+// ListDenylistEntries mocks base method.
+func (m *MockStore) ListDenylistEntries(arg0 context.Context, arg1 db.ListDenylistEntriesParams) ([]db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDenylistEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDenylistEntries indicates an expected call of ListDenylistEntries.
+func (mr *MockStoreMockRecorder) ListDenylistEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDenylistEntries", reflect.TypeOf((*MockStore)(nil).ListDenylistEntries), arg0, arg1)
+}
+
+// ListDevices mocks base method.
+func (m *MockStore) ListDevices(arg0 context.Context, arg1 string) ([]db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockStoreMockRecorder) ListDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockStore)(nil).ListDevices), arg0, arg1)
+}
+
+// ListDisputes mocks base method.
+func (m *MockStore) ListDisputes(arg0 context.Context, arg1 db.ListDisputesParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisputes indicates an expected call of ListDisputes.
+func (mr *MockStoreMockRecorder) ListDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisputes", reflect.TypeOf((*MockStore)(nil).ListDisputes), arg0, arg1)
+}
+
+// ListDueLoanInstallments mocks base method.
+func (m *MockStore) ListDueLoanInstallments(arg0 context.Context, arg1 int32) ([]db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueLoanInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueLoanInstallments indicates an expected call of ListDueLoanInstallments.
+func (mr *MockStoreMockRecorder) ListDueLoanInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueLoanInstallments", reflect.TypeOf((*MockStore)(nil).ListDueLoanInstallments), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEntriesAfter mocks base method.
+func (m *MockStore) ListEntriesAfter(arg0 context.Context, arg1 db.ListEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesAfter indicates an expected call of ListEntriesAfter.
+func (mr *MockStoreMockRecorder) ListEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListEntriesAfter), arg0, arg1)
+}
+
+// ListEntryCategories mocks base method.
+func (m *MockStore) ListEntryCategories(arg0 context.Context, arg1 []int64) ([]db.EntryCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryCategories", arg0, arg1)
+	ret0, _ := ret[0].([]db.EntryCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryCategories indicates an expected call of ListEntryCategories.
+func (mr *MockStoreMockRecorder) ListEntryCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryCategories", reflect.TypeOf((*MockStore)(nil).ListEntryCategories), arg0, arg1)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockStore) ListExchangeRates(arg0 context.Context) ([]db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", arg0)
+	ret0, _ := ret[0].([]db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockStoreMockRecorder) ListExchangeRates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockStore)(nil).ListExchangeRates), arg0)
+}
+
+// ListExpiredAuthorizationHolds mocks base method.
+func (m *MockStore) ListExpiredAuthorizationHolds(arg0 context.Context, arg1 int32) ([]db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAuthorizationHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAuthorizationHolds indicates an expected call of ListExpiredAuthorizationHolds.
+func (mr *MockStoreMockRecorder) ListExpiredAuthorizationHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAuthorizationHolds", reflect.TypeOf((*MockStore)(nil).ListExpiredAuthorizationHolds), arg0, arg1)
+}
+
+// ListExportEntries mocks base method.
+func (m *MockStore) ListExportEntries(arg0 context.Context, arg1 db.ListExportEntriesParams) ([]db.ListExportEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListExportEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExportEntries indicates an expected call of ListExportEntries.
+func (mr *MockStoreMockRecorder) ListExportEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportEntries", reflect.TypeOf((*MockStore)(nil).ListExportEntries), arg0, arg1)
+}
+
+// ListExternalTransfersToSettle mocks base method.
+func (m *MockStore) ListExternalTransfersToSettle(arg0 context.Context, arg1 int32) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalTransfersToSettle", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalTransfersToSettle indicates an expected call of ListExternalTransfersToSettle.
+func (mr *MockStoreMockRecorder) ListExternalTransfersToSettle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalTransfersToSettle", reflect.TypeOf((*MockStore)(nil).ListExternalTransfersToSettle), arg0, arg1)
+}
+
+// ListLatestLegalDocuments mocks base method.
+func (m *MockStore) ListLatestLegalDocuments(arg0 context.Context) ([]db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestLegalDocuments", arg0)
+	ret0, _ := ret[0].([]db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatestLegalDocuments indicates an expected call of ListLatestLegalDocuments.
+func (mr *MockStoreMockRecorder) ListLatestLegalDocuments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestLegalDocuments", reflect.TypeOf((*MockStore)(nil).ListLatestLegalDocuments), arg0)
+}
+
+// ListLoanInstallments mocks base method.
+func (m *MockStore) ListLoanInstallments(arg0 context.Context, arg1 int64) ([]db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoanInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoanInstallments indicates an expected call of ListLoanInstallments.
+func (mr *MockStoreMockRecorder) ListLoanInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoanInstallments", reflect.TypeOf((*MockStore)(nil).ListLoanInstallments), arg0, arg1)
+}
+
+// ListLoanOffers mocks base method.
+func (m *MockStore) ListLoanOffers(arg0 context.Context, arg1 bool) ([]db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoanOffers", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoanOffers indicates an expected call of ListLoanOffers.
+func (mr *MockStoreMockRecorder) ListLoanOffers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoanOffers", reflect.TypeOf((*MockStore)(nil).ListLoanOffers), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListPendingLegalDocuments mocks base method.
+func (m *MockStore) ListPendingLegalDocuments(arg0 context.Context, arg1 string) ([]db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingLegalDocuments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingLegalDocuments indicates an expected call of ListPendingLegalDocuments.
+func (mr *MockStoreMockRecorder) ListPendingLegalDocuments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingLegalDocuments", reflect.TypeOf((*MockStore)(nil).ListPendingLegalDocuments), arg0, arg1)
+}
+
+// ListPendingOutboxEvents mocks base method.
+func (m *MockStore) ListPendingOutboxEvents(arg0 context.Context, arg1 int32) ([]db.EventOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.EventOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingOutboxEvents indicates an expected call of ListPendingOutboxEvents.
+func (mr *MockStoreMockRecorder) ListPendingOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOutboxEvents", reflect.TypeOf((*MockStore)(nil).ListPendingOutboxEvents), arg0, arg1)
+}
+
+// ListPendingOutboxTasks mocks base method.
+func (m *MockStore) ListPendingOutboxTasks(arg0 context.Context, arg1 int32) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingOutboxTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingOutboxTasks indicates an expected call of ListPendingOutboxTasks.
+func (mr *MockStoreMockRecorder) ListPendingOutboxTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOutboxTasks", reflect.TypeOf((*MockStore)(nil).ListPendingOutboxTasks), arg0, arg1)
+}
+
+// ListPendingReferrals mocks base method.
+func (m *MockStore) ListPendingReferrals(arg0 context.Context, arg1 db.ListPendingReferralsParams) ([]db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingReferrals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingReferrals indicates an expected call of ListPendingReferrals.
+func (mr *MockStoreMockRecorder) ListPendingReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReferrals", reflect.TypeOf((*MockStore)(nil).ListPendingReferrals), arg0, arg1)
+}
+
+// ListRecentSessions mocks base method.
+func (m *MockStore) ListRecentSessions(arg0 context.Context, arg1 db.ListRecentSessionsParams) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecentSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecentSessions indicates an expected call of ListRecentSessions.
+func (mr *MockStoreMockRecorder) ListRecentSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentSessions", reflect.TypeOf((*MockStore)(nil).ListRecentSessions), arg0, arg1)
+}
+
+// ListReferrals mocks base method.
+func (m *MockStore) ListReferrals(arg0 context.Context, arg1 db.ListReferralsParams) ([]db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReferrals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReferrals indicates an expected call of ListReferrals.
+func (mr *MockStoreMockRecorder) ListReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReferrals", reflect.TypeOf((*MockStore)(nil).ListReferrals), arg0, arg1)
+}
+
+// ListRewardEntries mocks base method.
+func (m *MockStore) ListRewardEntries(arg0 context.Context, arg1 db.ListRewardEntriesParams) ([]db.RewardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRewardEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.RewardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRewardEntries indicates an expected call of ListRewardEntries.
+func (mr *MockStoreMockRecorder) ListRewardEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRewardEntries", reflect.TypeOf((*MockStore)(nil).ListRewardEntries), arg0, arg1)
+}
+
+// ListScreeningHolds mocks base method.
+func (m *MockStore) ListScreeningHolds(arg0 context.Context, arg1 db.ListScreeningHoldsParams) ([]db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScreeningHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScreeningHolds indicates an expected call of ListScreeningHolds.
+func (mr *MockStoreMockRecorder) ListScreeningHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScreeningHolds", reflect.TypeOf((*MockStore)(nil).ListScreeningHolds), arg0, arg1)
+}
+
+// ListStatementEntriesAfter mocks base method.
+func (m *MockStore) ListStatementEntriesAfter(arg0 context.Context, arg1 db.ListStatementEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntriesAfter indicates an expected call of ListStatementEntriesAfter.
+func (mr *MockStoreMockRecorder) ListStatementEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListStatementEntriesAfter), arg0, arg1)
+}
+
+// ListStatementEntriesBefore mocks base method.
+func (m *MockStore) ListStatementEntriesBefore(arg0 context.Context, arg1 db.ListStatementEntriesBeforeParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntriesBefore indicates an expected call of ListStatementEntriesBefore.
+func (mr *MockStoreMockRecorder) ListStatementEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntriesBefore", reflect.TypeOf((*MockStore)(nil).ListStatementEntriesBefore), arg0, arg1)
+}
+
+// ListTenants mocks base method.
+func (m *MockStore) ListTenants(arg0 context.Context) ([]db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTenants", arg0)
+	ret0, _ := ret[0].([]db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTenants indicates an expected call of ListTenants.
+func (mr *MockStoreMockRecorder) ListTenants(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTenants", reflect.TypeOf((*MockStore)(nil).ListTenants), arg0)
+}
+
+// ListTransferRefunds mocks base method.
+func (m *MockStore) ListTransferRefunds(arg0 context.Context, arg1 int64) ([]db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferRefunds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferRefunds indicates an expected call of ListTransferRefunds.
+func (mr *MockStoreMockRecorder) ListTransferRefunds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferRefunds", reflect.TypeOf((*MockStore)(nil).ListTransferRefunds), arg0, arg1)
+}
+
+// ListTransferReviews mocks base method.
+func (m *MockStore) ListTransferReviews(arg0 context.Context, arg1 db.ListTransferReviewsParams) ([]db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferReviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferReviews indicates an expected call of ListTransferReviews.
+func (mr *MockStoreMockRecorder) ListTransferReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferReviews", reflect.TypeOf((*MockStore)(nil).ListTransferReviews), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListTransfersAfter mocks base method.
+func (m *MockStore) ListTransfersAfter(arg0 context.Context, arg1 db.ListTransfersAfterParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersAfter indicates an expected call of ListTransfersAfter.
+func (mr *MockStoreMockRecorder) ListTransfersAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersAfter", reflect.TypeOf((*MockStore)(nil).ListTransfersAfter), arg0, arg1)
+}
+
+// ListTransfersBefore mocks base method.
+func (m *MockStore) ListTransfersBefore(arg0 context.Context, arg1 db.ListTransfersBeforeParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersBefore indicates an expected call of ListTransfersBefore.
+func (mr *MockStoreMockRecorder) ListTransfersBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersBefore", reflect.TypeOf((*MockStore)(nil).ListTransfersBefore), arg0, arg1)
+}
+
+// ListUnbalancedTransfers mocks base method.
+func (m *MockStore) ListUnbalancedTransfers(arg0 context.Context, arg1 int32) ([]db.ListUnbalancedTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnbalancedTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUnbalancedTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnbalancedTransfers indicates an expected call of ListUnbalancedTransfers.
+func (mr *MockStoreMockRecorder) ListUnbalancedTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnbalancedTransfers", reflect.TypeOf((*MockStore)(nil).ListUnbalancedTransfers), arg0, arg1)
+}
+
+// ListUserConsents mocks base method.
+func (m *MockStore) ListUserConsents(arg0 context.Context, arg1 string) ([]db.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserConsents", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserConsents indicates an expected call of ListUserConsents.
+func (mr *MockStoreMockRecorder) ListUserConsents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserConsents", reflect.TypeOf((*MockStore)(nil).ListUserConsents), arg0, arg1)
+}
+
+// ListUserImportRowsAfter mocks base method.
+func (m *MockStore) ListUserImportRowsAfter(arg0 context.Context, arg1 db.ListUserImportRowsAfterParams) ([]db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserImportRowsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserImportRowsAfter indicates an expected call of ListUserImportRowsAfter.
+func (mr *MockStoreMockRecorder) ListUserImportRowsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserImportRowsAfter", reflect.TypeOf((*MockStore)(nil).ListUserImportRowsAfter), arg0, arg1)
+}
+
+// ListUserImportRowsBefore mocks base method.
+func (m *MockStore) ListUserImportRowsBefore(arg0 context.Context, arg1 db.ListUserImportRowsBeforeParams) ([]db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserImportRowsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserImportRowsBefore indicates an expected call of ListUserImportRowsBefore.
+func (mr *MockStoreMockRecorder) ListUserImportRowsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserImportRowsBefore", reflect.TypeOf((*MockStore)(nil).ListUserImportRowsBefore), arg0, arg1)
+}
+
+// LockAccountBalance mocks base method.
+func (m *MockStore) LockAccountBalance(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAccountBalance indicates an expected call of LockAccountBalance.
+func (mr *MockStoreMockRecorder) LockAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccountBalance", reflect.TypeOf((*MockStore)(nil).LockAccountBalance), arg0, arg1)
+}
+
+// LockAuditLogChain mocks base method.
+func (m *MockStore) LockAuditLogChain(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAuditLogChain", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAuditLogChain indicates an expected call of LockAuditLogChain.
+func (mr *MockStoreMockRecorder) LockAuditLogChain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAuditLogChain", reflect.TypeOf((*MockStore)(nil).LockAuditLogChain), arg0)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkLoanDelinquent mocks base method.
+func (m *MockStore) MarkLoanDelinquent(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanDelinquent", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanDelinquent indicates an expected call of MarkLoanDelinquent.
+func (mr *MockStoreMockRecorder) MarkLoanDelinquent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanDelinquent", reflect.TypeOf((*MockStore)(nil).MarkLoanDelinquent), arg0, arg1)
+}
+
+// MarkLoanDelinquentTx mocks base method.
+func (m *MockStore) MarkLoanDelinquentTx(arg0 context.Context, arg1 db.MarkLoanDelinquentTxParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanDelinquentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanDelinquentTx indicates an expected call of MarkLoanDelinquentTx.
+func (mr *MockStoreMockRecorder) MarkLoanDelinquentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanDelinquentTx", reflect.TypeOf((*MockStore)(nil).MarkLoanDelinquentTx), arg0, arg1)
+}
+
+// MarkLoanInstallmentPaid mocks base method.
+func (m *MockStore) MarkLoanInstallmentPaid(arg0 context.Context, arg1 db.MarkLoanInstallmentPaidParams) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanInstallmentPaid", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanInstallmentPaid indicates an expected call of MarkLoanInstallmentPaid.
+func (mr *MockStoreMockRecorder) MarkLoanInstallmentPaid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanInstallmentPaid", reflect.TypeOf((*MockStore)(nil).MarkLoanInstallmentPaid), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockStore) MarkOutboxEventPublished(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockStoreMockRecorder) MarkOutboxEventPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventPublished), arg0, arg1)
+}
+
+// MarkOutboxTaskPublished mocks base method.
+func (m *MockStore) MarkOutboxTaskPublished(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxTaskPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxTaskPublished indicates an expected call of MarkOutboxTaskPublished.
+func (mr *MockStoreMockRecorder) MarkOutboxTaskPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxTaskPublished", reflect.TypeOf((*MockStore)(nil).MarkOutboxTaskPublished), arg0, arg1)
+}
+
+// MarkPaymentRequestPaid mocks base method.
+func (m *MockStore) MarkPaymentRequestPaid(arg0 context.Context, arg1 db.MarkPaymentRequestPaidParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaymentRequestPaid", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkPaymentRequestPaid indicates an expected call of MarkPaymentRequestPaid.
+func (mr *MockStoreMockRecorder) MarkPaymentRequestPaid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaymentRequestPaid", reflect.TypeOf((*MockStore)(nil).MarkPaymentRequestPaid), arg0, arg1)
+}
+
+// MatchDenylist mocks base method.
+func (m *MockStore) MatchDenylist(arg0 context.Context, arg1 db.MatchDenylistParams) ([]db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchDenylist", arg0, arg1)
+	ret0, _ := ret[0].([]db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchDenylist indicates an expected call of MatchDenylist.
+func (mr *MockStoreMockRecorder) MatchDenylist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchDenylist", reflect.TypeOf((*MockStore)(nil).MatchDenylist), arg0, arg1)
+}
+
+// MigrationVersion mocks base method.
+func (m *MockStore) MigrationVersion(arg0 context.Context) (uint, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationVersion", arg0)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MigrationVersion indicates an expected call of MigrationVersion.
+func (mr *MockStoreMockRecorder) MigrationVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationVersion", reflect.TypeOf((*MockStore)(nil).MigrationVersion), arg0)
+}
+
+// OpenDisputeTx mocks base method.
+func (m *MockStore) OpenDisputeTx(arg0 context.Context, arg1 db.CreateDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDisputeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenDisputeTx indicates an expected call of OpenDisputeTx.
+func (mr *MockStoreMockRecorder) OpenDisputeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDisputeTx", reflect.TypeOf((*MockStore)(nil).OpenDisputeTx), arg0, arg1)
+}
+
+// PayPaymentRequestTx mocks base method.
+func (m *MockStore) PayPaymentRequestTx(arg0 context.Context, arg1 db.PayPaymentRequestTxParams) (db.PayPaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayPaymentRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayPaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayPaymentRequestTx indicates an expected call of PayPaymentRequestTx.
+func (mr *MockStoreMockRecorder) PayPaymentRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentRequestTx", reflect.TypeOf((*MockStore)(nil).PayPaymentRequestTx), arg0, arg1)
+}
+
+// Ping mocks base method.
+func (m *MockStore) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
+}
+
+// PlaceAuthorizationHoldTx mocks base method.
+func (m *MockStore) PlaceAuthorizationHoldTx(arg0 context.Context, arg1 db.CreateAuthorizationHoldParams) (db.PlaceAuthorizationHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PlaceAuthorizationHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceAuthorizationHoldTx indicates an expected call of PlaceAuthorizationHoldTx.
+func (mr *MockStoreMockRecorder) PlaceAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceAuthorizationHoldTx", reflect.TypeOf((*MockStore)(nil).PlaceAuthorizationHoldTx), arg0, arg1)
+}
+
+// PublishOutboxEventsTx mocks base method.
+func (m *MockStore) PublishOutboxEventsTx(arg0 context.Context, arg1 int32, arg2 func([]db.EventOutbox) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutboxEventsTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutboxEventsTx indicates an expected call of PublishOutboxEventsTx.
+func (mr *MockStoreMockRecorder) PublishOutboxEventsTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxEventsTx", reflect.TypeOf((*MockStore)(nil).PublishOutboxEventsTx), arg0, arg1, arg2)
+}
+
+// PublishOutboxTx mocks base method.
+func (m *MockStore) PublishOutboxTx(arg0 context.Context, arg1 int32, arg2 func(db.Outbox) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutboxTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutboxTx indicates an expected call of PublishOutboxTx.
+func (mr *MockStoreMockRecorder) PublishOutboxTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxTx", reflect.TypeOf((*MockStore)(nil).PublishOutboxTx), arg0, arg1, arg2)
+}
+
+// RedeemRewardsTx mocks base method.
+func (m *MockStore) RedeemRewardsTx(arg0 context.Context, arg1 db.RedeemRewardsTxParams) (db.RedeemRewardsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemRewardsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RedeemRewardsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemRewardsTx indicates an expected call of RedeemRewardsTx.
+func (mr *MockStoreMockRecorder) RedeemRewardsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemRewardsTx", reflect.TypeOf((*MockStore)(nil).RedeemRewardsTx), arg0, arg1)
+}
+
+// RefundTransferTx mocks base method.
+func (m *MockStore) RefundTransferTx(arg0 context.Context, arg1 db.RefundTransferTxParams) (db.RefundTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RefundTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTransferTx indicates an expected call of RefundTransferTx.
+func (mr *MockStoreMockRecorder) RefundTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransferTx", reflect.TypeOf((*MockStore)(nil).RefundTransferTx), arg0, arg1)
+}
+
+// ReleaseAuthorizationHoldTx mocks base method.
+func (m *MockStore) ReleaseAuthorizationHoldTx(arg0 context.Context, arg1 db.ReleaseAuthorizationHoldTxParams) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseAuthorizationHoldTx indicates an expected call of ReleaseAuthorizationHoldTx.
+func (mr *MockStoreMockRecorder) ReleaseAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAuthorizationHoldTx", reflect.TypeOf((*MockStore)(nil).ReleaseAuthorizationHoldTx), arg0, arg1)
+}
+
+// RenameDevice mocks base method.
+func (m *MockStore) RenameDevice(arg0 context.Context, arg1 db.RenameDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameDevice indicates an expected call of RenameDevice.
+func (mr *MockStoreMockRecorder) RenameDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameDevice", reflect.TypeOf((*MockStore)(nil).RenameDevice), arg0, arg1)
+}
+
+// RepayLoanInstallmentTx mocks base method.
+func (m *MockStore) RepayLoanInstallmentTx(arg0 context.Context, arg1 db.RepayLoanInstallmentTxParams) (db.RepayLoanInstallmentTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepayLoanInstallmentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RepayLoanInstallmentTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepayLoanInstallmentTx indicates an expected call of RepayLoanInstallmentTx.
+func (mr *MockStoreMockRecorder) RepayLoanInstallmentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepayLoanInstallmentTx", reflect.TypeOf((*MockStore)(nil).RepayLoanInstallmentTx), arg0, arg1)
+}
+
+// ReportLoginAlert mocks base method.
+func (m *MockStore) ReportLoginAlert(arg0 context.Context, arg1 db.ReportLoginAlertParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportLoginAlert indicates an expected call of ReportLoginAlert.
+func (mr *MockStoreMockRecorder) ReportLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLoginAlert", reflect.TypeOf((*MockStore)(nil).ReportLoginAlert), arg0, arg1)
+}
+
+// ReportLoginAlertTx mocks base method.
+func (m *MockStore) ReportLoginAlertTx(arg0 context.Context, arg1 db.ReportLoginAlertTxParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLoginAlertTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportLoginAlertTx indicates an expected call of ReportLoginAlertTx.
+func (mr *MockStoreMockRecorder) ReportLoginAlertTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLoginAlertTx", reflect.TypeOf((*MockStore)(nil).ReportLoginAlertTx), arg0, arg1)
+}
+
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// RevokeDevice mocks base method.
+func (m *MockStore) RevokeDevice(arg0 context.Context, arg1 db.RevokeDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDevice indicates an expected call of RevokeDevice.
+func (mr *MockStoreMockRecorder) RevokeDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDevice", reflect.TypeOf((*MockStore)(nil).RevokeDevice), arg0, arg1)
+}
+
+// RevokeDeviceTx mocks base method.
+func (m *MockStore) RevokeDeviceTx(arg0 context.Context, arg1 db.RevokeDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDeviceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDeviceTx indicates an expected call of RevokeDeviceTx.
+func (mr *MockStoreMockRecorder) RevokeDeviceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDeviceTx", reflect.TypeOf((*MockStore)(nil).RevokeDeviceTx), arg0, arg1)
+}
+
+// RevokeImpersonation mocks base method.
+func (m *MockStore) RevokeImpersonation(arg0 context.Context, arg1 uuid.UUID) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeImpersonation indicates an expected call of RevokeImpersonation.
+func (mr *MockStoreMockRecorder) RevokeImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeImpersonation", reflect.TypeOf((*MockStore)(nil).RevokeImpersonation), arg0, arg1)
+}
+
+// RevokeImpersonationTx mocks base method.
+func (m *MockStore) RevokeImpersonationTx(arg0 context.Context, arg1 db.RevokeImpersonationTxParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeImpersonationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeImpersonationTx indicates an expected call of RevokeImpersonationTx.
+func (mr *MockStoreMockRecorder) RevokeImpersonationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeImpersonationTx", reflect.TypeOf((*MockStore)(nil).RevokeImpersonationTx), arg0, arg1)
+}
+
+// RevokeSessionsTx mocks base method.
+func (m *MockStore) RevokeSessionsTx(arg0 context.Context, arg1 db.RevokeSessionsTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionsTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessionsTx indicates an expected call of RevokeSessionsTx.
+func (mr *MockStoreMockRecorder) RevokeSessionsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionsTx", reflect.TypeOf((*MockStore)(nil).RevokeSessionsTx), arg0, arg1)
+}
+
+// RewardReferralTx mocks base method.
+func (m *MockStore) RewardReferralTx(arg0 context.Context, arg1 db.RewardReferralTxParams) (db.RewardReferralTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewardReferralTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RewardReferralTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewardReferralTx indicates an expected call of RewardReferralTx.
+func (mr *MockStoreMockRecorder) RewardReferralTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewardReferralTx", reflect.TypeOf((*MockStore)(nil).RewardReferralTx), arg0, arg1)
+}
+
+// SaveExchangeRatesTx mocks base method.
+func (m *MockStore) SaveExchangeRatesTx(arg0 context.Context, arg1 []db.UpsertExchangeRateParams) ([]db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExchangeRatesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveExchangeRatesTx indicates an expected call of SaveExchangeRatesTx.
+func (mr *MockStoreMockRecorder) SaveExchangeRatesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExchangeRatesTx", reflect.TypeOf((*MockStore)(nil).SaveExchangeRatesTx), arg0, arg1)
+}
+
+// SearchTransfers mocks base method.
+func (m *MockStore) SearchTransfers(arg0 context.Context, arg1 db.SearchTransfersParams) ([]db.SearchTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfers indicates an expected call of SearchTransfers.
+func (mr *MockStoreMockRecorder) SearchTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfers", reflect.TypeOf((*MockStore)(nil).SearchTransfers), arg0, arg1)
+}
+
+// SearchUsers mocks base method.
+func (m *MockStore) SearchUsers(arg0 context.Context, arg1 db.SearchUsersParams) ([]db.SearchUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockStoreMockRecorder) SearchUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockStore)(nil).SearchUsers), arg0, arg1)
+}
+
+// SendDeviceVerificationTx mocks base method.
+func (m *MockStore) SendDeviceVerificationTx(arg0 context.Context, arg1 db.SendDeviceVerificationTxParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDeviceVerificationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendDeviceVerificationTx indicates an expected call of SendDeviceVerificationTx.
+func (mr *MockStoreMockRecorder) SendDeviceVerificationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDeviceVerificationTx", reflect.TypeOf((*MockStore)(nil).SendDeviceVerificationTx), arg0, arg1)
+}
+
+// SendPasswordResetCodeTx mocks base method.
+func (m *MockStore) SendPasswordResetCodeTx(arg0 context.Context, arg1 db.SendPasswordResetCodeTxParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordResetCodeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendPasswordResetCodeTx indicates an expected call of SendPasswordResetCodeTx.
+func (mr *MockStoreMockRecorder) SendPasswordResetCodeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordResetCodeTx", reflect.TypeOf((*MockStore)(nil).SendPasswordResetCodeTx), arg0, arg1)
+}
+
+// SetCardFrozen mocks base method.
+func (m *MockStore) SetCardFrozen(arg0 context.Context, arg1 db.SetCardFrozenParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCardFrozen", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCardFrozen indicates an expected call of SetCardFrozen.
+func (mr *MockStoreMockRecorder) SetCardFrozen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCardFrozen", reflect.TypeOf((*MockStore)(nil).SetCardFrozen), arg0, arg1)
+}
+
+// SetCurrencyEnabled mocks base method.
+func (m *MockStore) SetCurrencyEnabled(arg0 context.Context, arg1 db.SetCurrencyEnabledParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrencyEnabled", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCurrencyEnabled indicates an expected call of SetCurrencyEnabled.
+func (mr *MockStoreMockRecorder) SetCurrencyEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrencyEnabled", reflect.TypeOf((*MockStore)(nil).SetCurrencyEnabled), arg0, arg1)
+}
+
+// SetCurrencyEnabledTx mocks base method.
+func (m *MockStore) SetCurrencyEnabledTx(arg0 context.Context, arg1 db.SetCurrencyEnabledTxParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrencyEnabledTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCurrencyEnabledTx indicates an expected call of SetCurrencyEnabledTx.
+func (mr *MockStoreMockRecorder) SetCurrencyEnabledTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrencyEnabledTx", reflect.TypeOf((*MockStore)(nil).SetCurrencyEnabledTx), arg0, arg1)
+}
+
+// SetDeviceVerification mocks base method.
+func (m *MockStore) SetDeviceVerification(arg0 context.Context, arg1 db.SetDeviceVerificationParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeviceVerification", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDeviceVerification indicates an expected call of SetDeviceVerification.
+func (mr *MockStoreMockRecorder) SetDeviceVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeviceVerification", reflect.TypeOf((*MockStore)(nil).SetDeviceVerification), arg0, arg1)
+}
+
+// SetDisputeStatusTx mocks base method.
+func (m *MockStore) SetDisputeStatusTx(arg0 context.Context, arg1 db.SetDisputeStatusTxParams) (db.SetDisputeStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisputeStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SetDisputeStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisputeStatusTx indicates an expected call of SetDisputeStatusTx.
+func (mr *MockStoreMockRecorder) SetDisputeStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisputeStatusTx", reflect.TypeOf((*MockStore)(nil).SetDisputeStatusTx), arg0, arg1)
+}
+
+// SetEntryCategory mocks base method.
+func (m *MockStore) SetEntryCategory(arg0 context.Context, arg1 db.SetEntryCategoryParams) (db.EntryCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntryCategory", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEntryCategory indicates an expected call of SetEntryCategory.
+func (mr *MockStoreMockRecorder) SetEntryCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntryCategory", reflect.TypeOf((*MockStore)(nil).SetEntryCategory), arg0, arg1)
+}
+
+// SetLoanOfferActive mocks base method.
+func (m *MockStore) SetLoanOfferActive(arg0 context.Context, arg1 db.SetLoanOfferActiveParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanOfferActive", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanOfferActive indicates an expected call of SetLoanOfferActive.
+func (mr *MockStoreMockRecorder) SetLoanOfferActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanOfferActive", reflect.TypeOf((*MockStore)(nil).SetLoanOfferActive), arg0, arg1)
+}
+
+// SetLoanOfferActiveTx mocks base method.
+func (m *MockStore) SetLoanOfferActiveTx(arg0 context.Context, arg1 db.SetLoanOfferActiveTxParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanOfferActiveTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanOfferActiveTx indicates an expected call of SetLoanOfferActiveTx.
+func (mr *MockStoreMockRecorder) SetLoanOfferActiveTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanOfferActiveTx", reflect.TypeOf((*MockStore)(nil).SetLoanOfferActiveTx), arg0, arg1)
+}
+
+// SetLoanStatus mocks base method.
+func (m *MockStore) SetLoanStatus(arg0 context.Context, arg1 db.SetLoanStatusParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanStatus indicates an expected call of SetLoanStatus.
+func (mr *MockStoreMockRecorder) SetLoanStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanStatus", reflect.TypeOf((*MockStore)(nil).SetLoanStatus), arg0, arg1)
+}
+
+// SetLoginAlertResetCode mocks base method.
+func (m *MockStore) SetLoginAlertResetCode(arg0 context.Context, arg1 db.SetLoginAlertResetCodeParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoginAlertResetCode", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoginAlertResetCode indicates an expected call of SetLoginAlertResetCode.
+func (mr *MockStoreMockRecorder) SetLoginAlertResetCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoginAlertResetCode", reflect.TypeOf((*MockStore)(nil).SetLoginAlertResetCode), arg0, arg1)
+}
+
+// SetMaintenanceReadOnly mocks base method.
+func (m *MockStore) SetMaintenanceReadOnly(arg0 context.Context, arg1 bool) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenanceReadOnly", arg0, arg1)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMaintenanceReadOnly indicates an expected call of SetMaintenanceReadOnly.
+func (mr *MockStoreMockRecorder) SetMaintenanceReadOnly(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceReadOnly", reflect.TypeOf((*MockStore)(nil).SetMaintenanceReadOnly), arg0, arg1)
+}
+
+// SetMaintenanceTransfersBlocked mocks base method.
+func (m *MockStore) SetMaintenanceTransfersBlocked(arg0 context.Context, arg1 bool) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenanceTransfersBlocked", arg0, arg1)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMaintenanceTransfersBlocked indicates an expected call of SetMaintenanceTransfersBlocked.
+func (mr *MockStoreMockRecorder) SetMaintenanceTransfersBlocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceTransfersBlocked", reflect.TypeOf((*MockStore)(nil).SetMaintenanceTransfersBlocked), arg0, arg1)
+}
+
+// SetScreeningHoldStatusTx mocks base method.
+func (m *MockStore) SetScreeningHoldStatusTx(arg0 context.Context, arg1 db.SetScreeningHoldStatusTxParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScreeningHoldStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetScreeningHoldStatusTx indicates an expected call of SetScreeningHoldStatusTx.
+func (mr *MockStoreMockRecorder) SetScreeningHoldStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScreeningHoldStatusTx", reflect.TypeOf((*MockStore)(nil).SetScreeningHoldStatusTx), arg0, arg1)
+}
+
+// SubmitExternalTransfer mocks base method.
+func (m *MockStore) SubmitExternalTransfer(arg0 context.Context, arg1 db.SubmitExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitExternalTransfer indicates an expected call of SubmitExternalTransfer.
+func (mr *MockStoreMockRecorder) SubmitExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitExternalTransfer", reflect.TypeOf((*MockStore)(nil).SubmitExternalTransfer), arg0, arg1)
+}
+
+// SumCardAuthorizations mocks base method.
+func (m *MockStore) SumCardAuthorizations(arg0 context.Context, arg1 db.SumCardAuthorizationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumCardAuthorizations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumCardAuthorizations indicates an expected call of SumCardAuthorizations.
+func (mr *MockStoreMockRecorder) SumCardAuthorizations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumCardAuthorizations", reflect.TypeOf((*MockStore)(nil).SumCardAuthorizations), arg0, arg1)
+}
+
+// SumEntriesByCategory mocks base method.
+func (m *MockStore) SumEntriesByCategory(arg0 context.Context, arg1 db.SumEntriesByCategoryParams) ([]db.SumEntriesByCategoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByCategory", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumEntriesByCategoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByCategory indicates an expected call of SumEntriesByCategory.
+func (mr *MockStoreMockRecorder) SumEntriesByCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByCategory", reflect.TypeOf((*MockStore)(nil).SumEntriesByCategory), arg0, arg1)
+}
+
+// SumEntriesByDay mocks base method.
+func (m *MockStore) SumEntriesByDay(arg0 context.Context, arg1 db.SumEntriesByDayParams) ([]db.SumEntriesByDayRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByDay", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumEntriesByDayRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByDay indicates an expected call of SumEntriesByDay.
+func (mr *MockStoreMockRecorder) SumEntriesByDay(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByDay", reflect.TypeOf((*MockStore)(nil).SumEntriesByDay), arg0, arg1)
+}
+
+// SumEntriesSince mocks base method.
+func (m *MockStore) SumEntriesSince(arg0 context.Context, arg1 db.SumEntriesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesSince indicates an expected call of SumEntriesSince.
+func (mr *MockStoreMockRecorder) SumEntriesSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesSince", reflect.TypeOf((*MockStore)(nil).SumEntriesSince), arg0, arg1)
+}
+
+// SumTransfersByCounterparty mocks base method.
+func (m *MockStore) SumTransfersByCounterparty(arg0 context.Context, arg1 db.SumTransfersByCounterpartyParams) ([]db.SumTransfersByCounterpartyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTransfersByCounterparty", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumTransfersByCounterpartyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTransfersByCounterparty indicates an expected call of SumTransfersByCounterparty.
+func (mr *MockStoreMockRecorder) SumTransfersByCounterparty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTransfersByCounterparty", reflect.TypeOf((*MockStore)(nil).SumTransfersByCounterparty), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// TrustDevice mocks base method.
+func (m *MockStore) TrustDevice(arg0 context.Context, arg1 int64) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrustDevice indicates an expected call of TrustDevice.
+func (mr *MockStoreMockRecorder) TrustDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustDevice", reflect.TypeOf((*MockStore)(nil).TrustDevice), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+}
+
+// UpdateCardLimits mocks base method.
+func (m *MockStore) UpdateCardLimits(arg0 context.Context, arg1 db.UpdateCardLimitsParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCardLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCardLimits indicates an expected call of UpdateCardLimits.
+func (mr *MockStoreMockRecorder) UpdateCardLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCardLimits", reflect.TypeOf((*MockStore)(nil).UpdateCardLimits), arg0, arg1)
+}
+
+// UpdateDisputeStatus mocks base method.
+func (m *MockStore) UpdateDisputeStatus(arg0 context.Context, arg1 db.UpdateDisputeStatusParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDisputeStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDisputeStatus indicates an expected call of UpdateDisputeStatus.
+func (mr *MockStoreMockRecorder) UpdateDisputeStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDisputeStatus", reflect.TypeOf((*MockStore)(nil).UpdateDisputeStatus), arg0, arg1)
+}
+
+// UpdateScreeningHoldStatus mocks base method.
+func (m *MockStore) UpdateScreeningHoldStatus(arg0 context.Context, arg1 db.UpdateScreeningHoldStatusParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScreeningHoldStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScreeningHoldStatus indicates an expected call of UpdateScreeningHoldStatus.
+func (mr *MockStoreMockRecorder) UpdateScreeningHoldStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScreeningHoldStatus", reflect.TypeOf((*MockStore)(nil).UpdateScreeningHoldStatus), arg0, arg1)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockStore) UpdateTenant(arg0 context.Context, arg1 db.UpdateTenantParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockStoreMockRecorder) UpdateTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockStore)(nil).UpdateTenant), arg0, arg1)
+}
+
+// UpdateTenantTx mocks base method.
+func (m *MockStore) UpdateTenantTx(arg0 context.Context, arg1 db.UpdateTenantTxParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenantTx indicates an expected call of UpdateTenantTx.
+func (mr *MockStoreMockRecorder) UpdateTenantTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenantTx", reflect.TypeOf((*MockStore)(nil).UpdateTenantTx), arg0, arg1)
+}
+
+// UpdateTransferReviewStatus mocks base method.
+func (m *MockStore) UpdateTransferReviewStatus(arg0 context.Context, arg1 db.UpdateTransferReviewStatusParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransferReviewStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransferReviewStatus indicates an expected call of UpdateTransferReviewStatus.
+func (mr *MockStoreMockRecorder) UpdateTransferReviewStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferReviewStatus", reflect.TypeOf((*MockStore)(nil).UpdateTransferReviewStatus), arg0, arg1)
+}
+
+// UpdateUser mocks base method.
+func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
+}
+
+// UpdateUserPreferences mocks base method.
+func (m *MockStore) UpdateUserPreferences(arg0 context.Context, arg1 db.UpdateUserPreferencesParams) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPreferences", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPreferences indicates an expected call of UpdateUserPreferences.
+func (mr *MockStoreMockRecorder) UpdateUserPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPreferences", reflect.TypeOf((*MockStore)(nil).UpdateUserPreferences), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpsertAccountAlert mocks base method.
+func (m *MockStore) UpsertAccountAlert(arg0 context.Context, arg1 db.UpsertAccountAlertParams) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAccountAlert indicates an expected call of UpsertAccountAlert.
+func (mr *MockStoreMockRecorder) UpsertAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAccountAlert", reflect.TypeOf((*MockStore)(nil).UpsertAccountAlert), arg0, arg1)
+}
+
+// UpsertDevice mocks base method.
+func (m *MockStore) UpsertDevice(arg0 context.Context, arg1 db.UpsertDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDevice indicates an expected call of UpsertDevice.
+func (mr *MockStoreMockRecorder) UpsertDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDevice", reflect.TypeOf((*MockStore)(nil).UpsertDevice), arg0, arg1)
+}
+
+// UpsertExchangeRate mocks base method.
+func (m *MockStore) UpsertExchangeRate(arg0 context.Context, arg1 db.UpsertExchangeRateParams) (db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertExchangeRate", arg0, arg1)
+	ret0, _ := ret[0].(db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertExchangeRate indicates an expected call of UpsertExchangeRate.
+func (mr *MockStoreMockRecorder) UpsertExchangeRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertExchangeRate", reflect.TypeOf((*MockStore)(nil).UpsertExchangeRate), arg0, arg1)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockStore) UpsertNotificationPreferences(arg0 context.Context, arg1 db.UpsertNotificationPreferencesParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreferences), arg0, arg1)
+}
+
+// VerifyAuditLogChain mocks base method.
+func (m *MockStore) VerifyAuditLogChain(arg0 context.Context) (db.AuditChainReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditLogChain", arg0)
+	ret0, _ := ret[0].(db.AuditChainReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditLogChain indicates an expected call of VerifyAuditLogChain.
+func (mr *MockStoreMockRecorder) VerifyAuditLogChain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditLogChain", reflect.TypeOf((*MockStore)(nil).VerifyAuditLogChain), arg0)
+}
+
+// VerifyLedger mocks base method.
+func (m *MockStore) VerifyLedger(arg0 context.Context) (db.LedgerReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLedger", arg0)
+	ret0, _ := ret[0].(db.LedgerReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyLedger indicates an expected call of VerifyLedger.
+func (mr *MockStoreMockRecorder) VerifyLedger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLedger", reflect.TypeOf((*MockStore)(nil).VerifyLedger), arg0)
+}
+
+// MockAccountStore is a mock of AccountStore interface.
+type MockAccountStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountStoreMockRecorder
+}
+
+// MockAccountStoreMockRecorder is the mock recorder for MockAccountStore.
+type MockAccountStoreMockRecorder struct {
+	mock *MockAccountStore
+}
+
+// NewMockAccountStore creates a new mock instance.
+func NewMockAccountStore(ctrl *gomock.Controller) *MockAccountStore {
+	mock := &MockAccountStore{ctrl: ctrl}
+	mock.recorder = &MockAccountStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountStore) EXPECT() *MockAccountStoreMockRecorder {
+	return m.recorder
+}
+
+// AddAccountBalance mocks base method.
+func (m *MockAccountStore) AddAccountBalance(arg0 context.Context, arg1 db.AddAccountBalanceParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountBalance indicates an expected call of AddAccountBalance.
+func (mr *MockAccountStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockAccountStore)(nil).AddAccountBalance), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccountStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountStore)(nil).CreateAccount), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockAccountStore) FreezeAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockAccountStoreMockRecorder) FreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockAccountStore)(nil).FreezeAccount), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccountStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountAlert mocks base method.
+func (m *MockAccountStore) GetAccountAlert(arg0 context.Context, arg1 int64) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountAlert indicates an expected call of GetAccountAlert.
+func (mr *MockAccountStoreMockRecorder) GetAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAlert", reflect.TypeOf((*MockAccountStore)(nil).GetAccountAlert), arg0, arg1)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockAccountStore) GetAccountByNumber(arg0 context.Context, arg1 db.GetAccountByNumberParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockAccountStoreMockRecorder) GetAccountByNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockAccountStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockAccountStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockAccountStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockAccountStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountIncludeDeleted mocks base method.
+func (m *MockAccountStore) GetAccountIncludeDeleted(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountIncludeDeleted indicates an expected call of GetAccountIncludeDeleted.
+func (mr *MockAccountStoreMockRecorder) GetAccountIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountIncludeDeleted", reflect.TypeOf((*MockAccountStore)(nil).GetAccountIncludeDeleted), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccountsAfter mocks base method.
+func (m *MockAccountStore) ListAccountsAfter(arg0 context.Context, arg1 db.ListAccountsAfterParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsAfter indicates an expected call of ListAccountsAfter.
+func (mr *MockAccountStoreMockRecorder) ListAccountsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsAfter", reflect.TypeOf((*MockAccountStore)(nil).ListAccountsAfter), arg0, arg1)
+}
+
+// ListAccountsBefore mocks base method.
+func (m *MockAccountStore) ListAccountsBefore(arg0 context.Context, arg1 db.ListAccountsBeforeParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsBefore indicates an expected call of ListAccountsBefore.
+func (mr *MockAccountStoreMockRecorder) ListAccountsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsBefore", reflect.TypeOf((*MockAccountStore)(nil).ListAccountsBefore), arg0, arg1)
+}
+
+// ListAccountsIncludeDeleted mocks base method.
+func (m *MockAccountStore) ListAccountsIncludeDeleted(arg0 context.Context, arg1 db.ListAccountsIncludeDeletedParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsIncludeDeleted indicates an expected call of ListAccountsIncludeDeleted.
+func (mr *MockAccountStoreMockRecorder) ListAccountsIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsIncludeDeleted", reflect.TypeOf((*MockAccountStore)(nil).ListAccountsIncludeDeleted), arg0, arg1)
+}
+
+// ListAllAccounts mocks base method.
+func (m *MockAccountStore) ListAllAccounts(arg0 context.Context, arg1 string) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllAccounts indicates an expected call of ListAllAccounts.
+func (mr *MockAccountStoreMockRecorder) ListAllAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllAccounts", reflect.TypeOf((*MockAccountStore)(nil).ListAllAccounts), arg0, arg1)
+}
+
+// LockAccountBalance mocks base method.
+func (m *MockAccountStore) LockAccountBalance(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAccountBalance indicates an expected call of LockAccountBalance.
+func (mr *MockAccountStoreMockRecorder) LockAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccountBalance", reflect.TypeOf((*MockAccountStore)(nil).LockAccountBalance), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockAccountStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
+func (mr *MockAccountStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockAccountStore)(nil).UpdateAccount), arg0, arg1)
+}
+
+// UpsertAccountAlert mocks base method.
+func (m *MockAccountStore) UpsertAccountAlert(arg0 context.Context, arg1 db.UpsertAccountAlertParams) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAccountAlert indicates an expected call of UpsertAccountAlert.
+func (mr *MockAccountStoreMockRecorder) UpsertAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAccountAlert", reflect.TypeOf((*MockAccountStore)(nil).UpsertAccountAlert), arg0, arg1)
+}
+
+// MockUserStore is a mock of UserStore interface.
+type MockUserStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStoreMockRecorder
+}
+
+// MockUserStoreMockRecorder is the mock recorder for MockUserStore.
+type MockUserStoreMockRecorder struct {
+	mock *MockUserStore
+}
+
+// NewMockUserStore creates a new mock instance.
+func NewMockUserStore(ctrl *gomock.Controller) *MockUserStore {
+	mock := &MockUserStore{ctrl: ctrl}
+	mock.recorder = &MockUserStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStore) EXPECT() *MockUserStoreMockRecorder {
+	return m.recorder
+}
+
+// AnonymizeUser mocks base method.
+func (m *MockUserStore) AnonymizeUser(arg0 context.Context, arg1 db.AnonymizeUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeUser indicates an expected call of AnonymizeUser.
+func (mr *MockUserStoreMockRecorder) AnonymizeUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUser", reflect.TypeOf((*MockUserStore)(nil).AnonymizeUser), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockUserStore) CreateUserTx(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockUserStoreMockRecorder) CreateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockUserStore)(nil).CreateUserTx), arg0, arg1)
+}
+
+// DeleteUserTx mocks base method.
+func (m *MockUserStore) DeleteUserTx(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserTx indicates an expected call of DeleteUserTx.
+func (mr *MockUserStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockUserStore)(nil).DeleteUserTx), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockUserStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserIncludeDeleted mocks base method.
+func (m *MockUserStore) GetUserIncludeDeleted(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIncludeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIncludeDeleted indicates an expected call of GetUserIncludeDeleted.
+func (mr *MockUserStoreMockRecorder) GetUserIncludeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIncludeDeleted", reflect.TypeOf((*MockUserStore)(nil).GetUserIncludeDeleted), arg0, arg1)
+}
+
+// SearchUsers mocks base method.
+func (m *MockUserStore) SearchUsers(arg0 context.Context, arg1 db.SearchUsersParams) ([]db.SearchUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockUserStoreMockRecorder) SearchUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserStore)(nil).SearchUsers), arg0, arg1)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserStore)(nil).UpdateUser), arg0, arg1)
+}
+
+// UpdateUserPreferences mocks base method.
+func (m *MockUserStore) UpdateUserPreferences(arg0 context.Context, arg1 db.UpdateUserPreferencesParams) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPreferences", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPreferences indicates an expected call of UpdateUserPreferences.
+func (mr *MockUserStoreMockRecorder) UpdateUserPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPreferences", reflect.TypeOf((*MockUserStore)(nil).UpdateUserPreferences), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockUserStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUserStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUserStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// MockTransferStore is a mock of TransferStore interface.
+type MockTransferStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferStoreMockRecorder
+}
+
+// MockTransferStoreMockRecorder is the mock recorder for MockTransferStore.
+type MockTransferStoreMockRecorder struct {
+	mock *MockTransferStore
+}
+
+// NewMockTransferStore creates a new mock instance.
+func NewMockTransferStore(ctrl *gomock.Controller) *MockTransferStore {
+	mock := &MockTransferStore{ctrl: ctrl}
+	mock.recorder = &MockTransferStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferStore) EXPECT() *MockTransferStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateEntries mocks base method.
+func (m *MockTransferStore) CreateEntries(arg0 context.Context, arg1 []db.CreateEntriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntries indicates an expected call of CreateEntries.
+func (mr *MockTransferStoreMockRecorder) CreateEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntries", reflect.TypeOf((*MockTransferStore)(nil).CreateEntries), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockTransferStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockTransferStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockTransferStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockTransferStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockTransferStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockTransferStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferExchange mocks base method.
+func (m *MockTransferStore) CreateTransferExchange(arg0 context.Context, arg1 db.CreateTransferExchangeParams) (db.TransferExchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferExchange", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferExchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferExchange indicates an expected call of CreateTransferExchange.
+func (mr *MockTransferStoreMockRecorder) CreateTransferExchange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferExchange", reflect.TypeOf((*MockTransferStore)(nil).CreateTransferExchange), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockTransferStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockTransferStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockTransferStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockTransferStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockTransferStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockTransferStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferExchange mocks base method.
+func (m *MockTransferStore) GetTransferExchange(arg0 context.Context, arg1 int64) (db.TransferExchange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferExchange", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferExchange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferExchange indicates an expected call of GetTransferExchange.
+func (mr *MockTransferStoreMockRecorder) GetTransferExchange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferExchange", reflect.TypeOf((*MockTransferStore)(nil).GetTransferExchange), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockTransferStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockTransferStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockTransferStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockTransferStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockTransferStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockTransferStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEntriesAfter mocks base method.
+func (m *MockTransferStore) ListEntriesAfter(arg0 context.Context, arg1 db.ListEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesAfter indicates an expected call of ListEntriesAfter.
+func (mr *MockTransferStoreMockRecorder) ListEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesAfter", reflect.TypeOf((*MockTransferStore)(nil).ListEntriesAfter), arg0, arg1)
+}
+
+// ListStatementEntriesAfter mocks base method.
+func (m *MockTransferStore) ListStatementEntriesAfter(arg0 context.Context, arg1 db.ListStatementEntriesAfterParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntriesAfter indicates an expected call of ListStatementEntriesAfter.
+func (mr *MockTransferStoreMockRecorder) ListStatementEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntriesAfter", reflect.TypeOf((*MockTransferStore)(nil).ListStatementEntriesAfter), arg0, arg1)
+}
+
+// ListStatementEntriesBefore mocks base method.
+func (m *MockTransferStore) ListStatementEntriesBefore(arg0 context.Context, arg1 db.ListStatementEntriesBeforeParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementEntriesBefore indicates an expected call of ListStatementEntriesBefore.
+func (mr *MockTransferStoreMockRecorder) ListStatementEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementEntriesBefore", reflect.TypeOf((*MockTransferStore)(nil).ListStatementEntriesBefore), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockTransferStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockTransferStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockTransferStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListTransfersAfter mocks base method.
+func (m *MockTransferStore) ListTransfersAfter(arg0 context.Context, arg1 db.ListTransfersAfterParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersAfter indicates an expected call of ListTransfersAfter.
+func (mr *MockTransferStoreMockRecorder) ListTransfersAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersAfter", reflect.TypeOf((*MockTransferStore)(nil).ListTransfersAfter), arg0, arg1)
+}
+
+// ListTransfersBefore mocks base method.
+func (m *MockTransferStore) ListTransfersBefore(arg0 context.Context, arg1 db.ListTransfersBeforeParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfersBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfersBefore indicates an expected call of ListTransfersBefore.
+func (mr *MockTransferStoreMockRecorder) ListTransfersBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfersBefore", reflect.TypeOf((*MockTransferStore)(nil).ListTransfersBefore), arg0, arg1)
+}
+
+// SearchTransfers mocks base method.
+func (m *MockTransferStore) SearchTransfers(arg0 context.Context, arg1 db.SearchTransfersParams) ([]db.SearchTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfers indicates an expected call of SearchTransfers.
+func (mr *MockTransferStoreMockRecorder) SearchTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfers", reflect.TypeOf((*MockTransferStore)(nil).SearchTransfers), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockTransferStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockTransferStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockTransferStore)(nil).TransferTx), arg0, arg1)
+}
+
+// MockSessionStore is a mock of SessionStore interface.
+type MockSessionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionStoreMockRecorder
+}
+
+// MockSessionStoreMockRecorder is the mock recorder for MockSessionStore.
+type MockSessionStoreMockRecorder struct {
+	mock *MockSessionStore
+}
+
+// NewMockSessionStore creates a new mock instance.
+func NewMockSessionStore(ctrl *gomock.Controller) *MockSessionStore {
+	mock := &MockSessionStore{ctrl: ctrl}
+	mock.recorder = &MockSessionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionStore) EXPECT() *MockSessionStoreMockRecorder {
+	return m.recorder
+}
+
+// BindSessionDevice mocks base method.
+func (m *MockSessionStore) BindSessionDevice(arg0 context.Context, arg1 db.BindSessionDeviceParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindSessionDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BindSessionDevice indicates an expected call of BindSessionDevice.
+func (mr *MockSessionStoreMockRecorder) BindSessionDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindSessionDevice", reflect.TypeOf((*MockSessionStore)(nil).BindSessionDevice), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockSessionStore) BlockUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockSessionStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockSessionStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CountActiveSessions mocks base method.
+func (m *MockSessionStore) CountActiveSessions(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveSessions", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveSessions indicates an expected call of CountActiveSessions.
+func (mr *MockSessionStoreMockRecorder) CountActiveSessions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveSessions", reflect.TypeOf((*MockSessionStore)(nil).CountActiveSessions), arg0)
+}
+
+// CreateImpersonation mocks base method.
+func (m *MockSessionStore) CreateImpersonation(arg0 context.Context, arg1 db.CreateImpersonationParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonation indicates an expected call of CreateImpersonation.
+func (mr *MockSessionStoreMockRecorder) CreateImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonation", reflect.TypeOf((*MockSessionStore)(nil).CreateImpersonation), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSessionTx mocks base method.
+func (m *MockSessionStore) CreateSessionTx(arg0 context.Context, arg1 db.CreateSessionTxParams) (db.CreateSessionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateSessionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSessionTx indicates an expected call of CreateSessionTx.
+func (mr *MockSessionStoreMockRecorder) CreateSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionTx", reflect.TypeOf((*MockSessionStore)(nil).CreateSessionTx), arg0, arg1)
+}
+
+// GetImpersonation mocks base method.
+func (m *MockSessionStore) GetImpersonation(arg0 context.Context, arg1 uuid.UUID) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImpersonation indicates an expected call of GetImpersonation.
+func (mr *MockSessionStoreMockRecorder) GetImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImpersonation", reflect.TypeOf((*MockSessionStore)(nil).GetImpersonation), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockSessionStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockSessionStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockSessionStore)(nil).GetSession), arg0, arg1)
+}
+
+// ListRecentSessions mocks base method.
+func (m *MockSessionStore) ListRecentSessions(arg0 context.Context, arg1 db.ListRecentSessionsParams) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecentSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecentSessions indicates an expected call of ListRecentSessions.
+func (mr *MockSessionStoreMockRecorder) ListRecentSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentSessions", reflect.TypeOf((*MockSessionStore)(nil).ListRecentSessions), arg0, arg1)
+}
+
+// RevokeImpersonation mocks base method.
+func (m *MockSessionStore) RevokeImpersonation(arg0 context.Context, arg1 uuid.UUID) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeImpersonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeImpersonation indicates an expected call of RevokeImpersonation.
+func (mr *MockSessionStoreMockRecorder) RevokeImpersonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeImpersonation", reflect.TypeOf((*MockSessionStore)(nil).RevokeImpersonation), arg0, arg1)
+}
+
+// MockNotificationStore is a mock of NotificationStore interface.
+type MockNotificationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationStoreMockRecorder
+}
+
+// MockNotificationStoreMockRecorder is the mock recorder for MockNotificationStore.
+type MockNotificationStoreMockRecorder struct {
+	mock *MockNotificationStore
+}
+
+// NewMockNotificationStore creates a new mock instance.
+func NewMockNotificationStore(ctrl *gomock.Controller) *MockNotificationStore {
+	mock := &MockNotificationStore{ctrl: ctrl}
+	mock.recorder = &MockNotificationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationStore) EXPECT() *MockNotificationStoreMockRecorder {
+	return m.recorder
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockNotificationStore) CountUnreadNotifications(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockNotificationStoreMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockNotificationStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockNotificationStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockNotificationStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockNotificationStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockNotificationStore) GetNotificationPreferences(arg0 context.Context, arg1 string) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockNotificationStoreMockRecorder) GetNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockNotificationStore)(nil).GetNotificationPreferences), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotificationStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotificationStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockNotificationStore) MarkAllNotificationsRead(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockNotificationStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockNotificationStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockNotificationStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockNotificationStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockNotificationStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockNotificationStore) UpsertNotificationPreferences(arg0 context.Context, arg1 db.UpsertNotificationPreferencesParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockNotificationStoreMockRecorder) UpsertNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockNotificationStore)(nil).UpsertNotificationPreferences), arg0, arg1)
+}
+
+// MockOutboxStore is a mock of OutboxStore interface.
+type MockOutboxStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStoreMockRecorder
+}
+
+// MockOutboxStoreMockRecorder is the mock recorder for MockOutboxStore.
+type MockOutboxStoreMockRecorder struct {
+	mock *MockOutboxStore
+}
+
+// NewMockOutboxStore creates a new mock instance.
+func NewMockOutboxStore(ctrl *gomock.Controller) *MockOutboxStore {
+	mock := &MockOutboxStore{ctrl: ctrl}
+	mock.recorder = &MockOutboxStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxStore) EXPECT() *MockOutboxStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockOutboxStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.EventOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.EventOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockOutboxStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockOutboxStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
+// CreateOutboxTask mocks base method.
+func (m *MockOutboxStore) CreateOutboxTask(arg0 context.Context, arg1 db.CreateOutboxTaskParams) (db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxTask", arg0, arg1)
+	ret0, _ := ret[0].(db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxTask indicates an expected call of CreateOutboxTask.
+func (mr *MockOutboxStoreMockRecorder) CreateOutboxTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTask", reflect.TypeOf((*MockOutboxStore)(nil).CreateOutboxTask), arg0, arg1)
+}
+
+// ListPendingOutboxEvents mocks base method.
+func (m *MockOutboxStore) ListPendingOutboxEvents(arg0 context.Context, arg1 int32) ([]db.EventOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.EventOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingOutboxEvents indicates an expected call of ListPendingOutboxEvents.
+func (mr *MockOutboxStoreMockRecorder) ListPendingOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOutboxEvents", reflect.TypeOf((*MockOutboxStore)(nil).ListPendingOutboxEvents), arg0, arg1)
+}
+
+// ListPendingOutboxTasks mocks base method.
+func (m *MockOutboxStore) ListPendingOutboxTasks(arg0 context.Context, arg1 int32) ([]db.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingOutboxTasks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingOutboxTasks indicates an expected call of ListPendingOutboxTasks.
+func (mr *MockOutboxStoreMockRecorder) ListPendingOutboxTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOutboxTasks", reflect.TypeOf((*MockOutboxStore)(nil).ListPendingOutboxTasks), arg0, arg1)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockOutboxStore) MarkOutboxEventPublished(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockOutboxStoreMockRecorder) MarkOutboxEventPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockOutboxStore)(nil).MarkOutboxEventPublished), arg0, arg1)
+}
+
+// MarkOutboxTaskPublished mocks base method.
+func (m *MockOutboxStore) MarkOutboxTaskPublished(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxTaskPublished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxTaskPublished indicates an expected call of MarkOutboxTaskPublished.
+func (mr *MockOutboxStoreMockRecorder) MarkOutboxTaskPublished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxTaskPublished", reflect.TypeOf((*MockOutboxStore)(nil).MarkOutboxTaskPublished), arg0, arg1)
+}
+
+// PublishOutboxEventsTx mocks base method.
+func (m *MockOutboxStore) PublishOutboxEventsTx(arg0 context.Context, arg1 int32, arg2 func([]db.EventOutbox) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutboxEventsTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutboxEventsTx indicates an expected call of PublishOutboxEventsTx.
+func (mr *MockOutboxStoreMockRecorder) PublishOutboxEventsTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxEventsTx", reflect.TypeOf((*MockOutboxStore)(nil).PublishOutboxEventsTx), arg0, arg1, arg2)
+}
+
+// PublishOutboxTx mocks base method.
+func (m *MockOutboxStore) PublishOutboxTx(arg0 context.Context, arg1 int32, arg2 func(db.Outbox) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutboxTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutboxTx indicates an expected call of PublishOutboxTx.
+func (mr *MockOutboxStoreMockRecorder) PublishOutboxTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutboxTx", reflect.TypeOf((*MockOutboxStore)(nil).PublishOutboxTx), arg0, arg1, arg2)
+}
+
+// MockLedgerStore is a mock of LedgerStore interface.
+type MockLedgerStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerStoreMockRecorder
+}
+
+// MockLedgerStoreMockRecorder is the mock recorder for MockLedgerStore.
+type MockLedgerStoreMockRecorder struct {
+	mock *MockLedgerStore
+}
+
+// NewMockLedgerStore creates a new mock instance.
+func NewMockLedgerStore(ctrl *gomock.Controller) *MockLedgerStore {
+	mock := &MockLedgerStore{ctrl: ctrl}
+	mock.recorder = &MockLedgerStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerStore) EXPECT() *MockLedgerStoreMockRecorder {
+	return m.recorder
+}
+
+// ListBalanceMismatches mocks base method.
+func (m *MockLedgerStore) ListBalanceMismatches(arg0 context.Context, arg1 int32) ([]db.ListBalanceMismatchesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceMismatches", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBalanceMismatchesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceMismatches indicates an expected call of ListBalanceMismatches.
+func (mr *MockLedgerStoreMockRecorder) ListBalanceMismatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceMismatches", reflect.TypeOf((*MockLedgerStore)(nil).ListBalanceMismatches), arg0, arg1)
+}
+
+// ListUnbalancedTransfers mocks base method.
+func (m *MockLedgerStore) ListUnbalancedTransfers(arg0 context.Context, arg1 int32) ([]db.ListUnbalancedTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnbalancedTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUnbalancedTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnbalancedTransfers indicates an expected call of ListUnbalancedTransfers.
+func (mr *MockLedgerStoreMockRecorder) ListUnbalancedTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnbalancedTransfers", reflect.TypeOf((*MockLedgerStore)(nil).ListUnbalancedTransfers), arg0, arg1)
+}
+
+// VerifyLedger mocks base method.
+func (m *MockLedgerStore) VerifyLedger(arg0 context.Context) (db.LedgerReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLedger", arg0)
+	ret0, _ := ret[0].(db.LedgerReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyLedger indicates an expected call of VerifyLedger.
+func (mr *MockLedgerStoreMockRecorder) VerifyLedger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLedger", reflect.TypeOf((*MockLedgerStore)(nil).VerifyLedger), arg0)
+}
+
+// MockPartitionStore is a mock of PartitionStore interface.
+type MockPartitionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPartitionStoreMockRecorder
+}
+
+// MockPartitionStoreMockRecorder is the mock recorder for MockPartitionStore.
+type MockPartitionStoreMockRecorder struct {
+	mock *MockPartitionStore
+}
+
+// NewMockPartitionStore creates a new mock instance.
+func NewMockPartitionStore(ctrl *gomock.Controller) *MockPartitionStore {
+	mock := &MockPartitionStore{ctrl: ctrl}
+	mock.recorder = &MockPartitionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPartitionStore) EXPECT() *MockPartitionStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateMonthlyPartition mocks base method.
+func (m *MockPartitionStore) CreateMonthlyPartition(arg0 context.Context, arg1 db.CreateMonthlyPartitionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMonthlyPartition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMonthlyPartition indicates an expected call of CreateMonthlyPartition.
+func (mr *MockPartitionStoreMockRecorder) CreateMonthlyPartition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMonthlyPartition", reflect.TypeOf((*MockPartitionStore)(nil).CreateMonthlyPartition), arg0, arg1)
+}
+
+// CreatePartitions mocks base method.
+func (m *MockPartitionStore) CreatePartitions(arg0 context.Context, arg1 time.Time, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePartitions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePartitions indicates an expected call of CreatePartitions.
+func (mr *MockPartitionStoreMockRecorder) CreatePartitions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePartitions", reflect.TypeOf((*MockPartitionStore)(nil).CreatePartitions), arg0, arg1, arg2)
+}
+
+// MockRetentionStore is a mock of RetentionStore interface.
+type MockRetentionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionStoreMockRecorder
+}
+
+// MockRetentionStoreMockRecorder is the mock recorder for MockRetentionStore.
+type MockRetentionStoreMockRecorder struct {
+	mock *MockRetentionStore
+}
+
+// NewMockRetentionStore creates a new mock instance.
+func NewMockRetentionStore(ctrl *gomock.Controller) *MockRetentionStore {
+	mock := &MockRetentionStore{ctrl: ctrl}
+	mock.recorder = &MockRetentionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionStore) EXPECT() *MockRetentionStoreMockRecorder {
+	return m.recorder
+}
+
+// ArchiveEntries mocks base method.
+func (m *MockRetentionStore) ArchiveEntries(arg0 context.Context, arg1 db.ArchiveEntriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveEntries indicates an expected call of ArchiveEntries.
+func (mr *MockRetentionStoreMockRecorder) ArchiveEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveEntries", reflect.TypeOf((*MockRetentionStore)(nil).ArchiveEntries), arg0, arg1)
+}
+
+// ArchiveEntriesBefore mocks base method.
+func (m *MockRetentionStore) ArchiveEntriesBefore(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveEntriesBefore indicates an expected call of ArchiveEntriesBefore.
+func (mr *MockRetentionStoreMockRecorder) ArchiveEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveEntriesBefore", reflect.TypeOf((*MockRetentionStore)(nil).ArchiveEntriesBefore), arg0, arg1)
+}
+
+// MockAuditStore is a mock of AuditStore interface.
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore.
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance.
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// AdjustBalanceTx mocks base method.
+func (m *MockAuditStore) AdjustBalanceTx(arg0 context.Context, arg1 db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalanceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdjustBalanceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalanceTx indicates an expected call of AdjustBalanceTx.
+func (mr *MockAuditStoreMockRecorder) AdjustBalanceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalanceTx", reflect.TypeOf((*MockAuditStore)(nil).AdjustBalanceTx), arg0, arg1)
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockAuditStore) CreateAuditEntry(arg0 context.Context, arg1 db.CreateAuditEntryParams) (db.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockAuditStoreMockRecorder) CreateAuditEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockAuditStore)(nil).CreateAuditEntry), arg0, arg1)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockAuditStore) CreateAuditLog(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockAuditStoreMockRecorder) CreateAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockAuditStore)(nil).CreateAuditLog), arg0, arg1)
+}
+
+// CreateAuditLogTx mocks base method.
+func (m *MockAuditStore) CreateAuditLogTx(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLogTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLogTx indicates an expected call of CreateAuditLogTx.
+func (mr *MockAuditStoreMockRecorder) CreateAuditLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLogTx", reflect.TypeOf((*MockAuditStore)(nil).CreateAuditLogTx), arg0, arg1)
+}
+
+// CreateImpersonationTx mocks base method.
+func (m *MockAuditStore) CreateImpersonationTx(arg0 context.Context, arg1 db.CreateImpersonationTxParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImpersonationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImpersonationTx indicates an expected call of CreateImpersonationTx.
+func (mr *MockAuditStoreMockRecorder) CreateImpersonationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImpersonationTx", reflect.TypeOf((*MockAuditStore)(nil).CreateImpersonationTx), arg0, arg1)
+}
+
+// CreateOutboxTasksTx mocks base method.
+func (m *MockAuditStore) CreateOutboxTasksTx(arg0 context.Context, arg1 db.CreateOutboxTasksTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxTasksTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxTasksTx indicates an expected call of CreateOutboxTasksTx.
+func (mr *MockAuditStoreMockRecorder) CreateOutboxTasksTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxTasksTx", reflect.TypeOf((*MockAuditStore)(nil).CreateOutboxTasksTx), arg0, arg1)
+}
+
+// FreezeAccountTx mocks base method.
+func (m *MockAuditStore) FreezeAccountTx(arg0 context.Context, arg1 db.FreezeAccountTxParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccountTx indicates an expected call of FreezeAccountTx.
+func (mr *MockAuditStoreMockRecorder) FreezeAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccountTx", reflect.TypeOf((*MockAuditStore)(nil).FreezeAccountTx), arg0, arg1)
+}
+
+// GetLastAuditLogHash mocks base method.
+func (m *MockAuditStore) GetLastAuditLogHash(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastAuditLogHash", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAuditLogHash indicates an expected call of GetLastAuditLogHash.
+func (mr *MockAuditStoreMockRecorder) GetLastAuditLogHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAuditLogHash", reflect.TypeOf((*MockAuditStore)(nil).GetLastAuditLogHash), arg0)
+}
+
+// ListAuditEntries mocks base method.
+func (m *MockAuditStore) ListAuditEntries(arg0 context.Context, arg1 db.ListAuditEntriesParams) ([]db.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntries indicates an expected call of ListAuditEntries.
+func (mr *MockAuditStoreMockRecorder) ListAuditEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntries", reflect.TypeOf((*MockAuditStore)(nil).ListAuditEntries), arg0, arg1)
+}
+
+// ListAuditLogsAfter mocks base method.
+func (m *MockAuditStore) ListAuditLogsAfter(arg0 context.Context, arg1 db.ListAuditLogsAfterParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogsAfter indicates an expected call of ListAuditLogsAfter.
+func (mr *MockAuditStoreMockRecorder) ListAuditLogsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogsAfter", reflect.TypeOf((*MockAuditStore)(nil).ListAuditLogsAfter), arg0, arg1)
+}
+
+// ListAuditLogsBefore mocks base method.
+func (m *MockAuditStore) ListAuditLogsBefore(arg0 context.Context, arg1 db.ListAuditLogsBeforeParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogsBefore indicates an expected call of ListAuditLogsBefore.
+func (mr *MockAuditStoreMockRecorder) ListAuditLogsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogsBefore", reflect.TypeOf((*MockAuditStore)(nil).ListAuditLogsBefore), arg0, arg1)
+}
+
+// LockAuditLogChain mocks base method.
+func (m *MockAuditStore) LockAuditLogChain(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAuditLogChain", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAuditLogChain indicates an expected call of LockAuditLogChain.
+func (mr *MockAuditStoreMockRecorder) LockAuditLogChain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAuditLogChain", reflect.TypeOf((*MockAuditStore)(nil).LockAuditLogChain), arg0)
+}
+
+// RevokeImpersonationTx mocks base method.
+func (m *MockAuditStore) RevokeImpersonationTx(arg0 context.Context, arg1 db.RevokeImpersonationTxParams) (db.Impersonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeImpersonationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Impersonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeImpersonationTx indicates an expected call of RevokeImpersonationTx.
+func (mr *MockAuditStoreMockRecorder) RevokeImpersonationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeImpersonationTx", reflect.TypeOf((*MockAuditStore)(nil).RevokeImpersonationTx), arg0, arg1)
+}
+
+// RevokeSessionsTx mocks base method.
+func (m *MockAuditStore) RevokeSessionsTx(arg0 context.Context, arg1 db.RevokeSessionsTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionsTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessionsTx indicates an expected call of RevokeSessionsTx.
+func (mr *MockAuditStoreMockRecorder) RevokeSessionsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionsTx", reflect.TypeOf((*MockAuditStore)(nil).RevokeSessionsTx), arg0, arg1)
+}
+
+// SetDisputeStatusTx mocks base method.
+func (m *MockAuditStore) SetDisputeStatusTx(arg0 context.Context, arg1 db.SetDisputeStatusTxParams) (db.SetDisputeStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisputeStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SetDisputeStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisputeStatusTx indicates an expected call of SetDisputeStatusTx.
+func (mr *MockAuditStoreMockRecorder) SetDisputeStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisputeStatusTx", reflect.TypeOf((*MockAuditStore)(nil).SetDisputeStatusTx), arg0, arg1)
+}
+
+// VerifyAuditLogChain mocks base method.
+func (m *MockAuditStore) VerifyAuditLogChain(arg0 context.Context) (db.AuditChainReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAuditLogChain", arg0)
+	ret0, _ := ret[0].(db.AuditChainReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAuditLogChain indicates an expected call of VerifyAuditLogChain.
+func (mr *MockAuditStoreMockRecorder) VerifyAuditLogChain(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAuditLogChain", reflect.TypeOf((*MockAuditStore)(nil).VerifyAuditLogChain), arg0)
+}
+
+// MockDisputeStore is a mock of DisputeStore interface.
+type MockDisputeStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeStoreMockRecorder
+}
+
+// MockDisputeStoreMockRecorder is the mock recorder for MockDisputeStore.
+type MockDisputeStoreMockRecorder struct {
+	mock *MockDisputeStore
+}
+
+// NewMockDisputeStore creates a new mock instance.
+func NewMockDisputeStore(ctrl *gomock.Controller) *MockDisputeStore {
+	mock := &MockDisputeStore{ctrl: ctrl}
+	mock.recorder = &MockDisputeStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDisputeStore) EXPECT() *MockDisputeStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateDispute mocks base method.
+func (m *MockDisputeStore) CreateDispute(arg0 context.Context, arg1 db.CreateDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDispute indicates an expected call of CreateDispute.
+func (mr *MockDisputeStoreMockRecorder) CreateDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDispute", reflect.TypeOf((*MockDisputeStore)(nil).CreateDispute), arg0, arg1)
+}
+
+// GetDispute mocks base method.
+func (m *MockDisputeStore) GetDispute(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDispute", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDispute indicates an expected call of GetDispute.
+func (mr *MockDisputeStoreMockRecorder) GetDispute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDispute", reflect.TypeOf((*MockDisputeStore)(nil).GetDispute), arg0, arg1)
+}
+
+// GetDisputeForUpdate mocks base method.
+func (m *MockDisputeStore) GetDisputeForUpdate(arg0 context.Context, arg1 int64) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeForUpdate indicates an expected call of GetDisputeForUpdate.
+func (mr *MockDisputeStoreMockRecorder) GetDisputeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeForUpdate", reflect.TypeOf((*MockDisputeStore)(nil).GetDisputeForUpdate), arg0, arg1)
+}
+
+// GetHeldAmount mocks base method.
+func (m *MockDisputeStore) GetHeldAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeldAmount indicates an expected call of GetHeldAmount.
+func (mr *MockDisputeStoreMockRecorder) GetHeldAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldAmount", reflect.TypeOf((*MockDisputeStore)(nil).GetHeldAmount), arg0, arg1)
+}
+
+// ListDisputes mocks base method.
+func (m *MockDisputeStore) ListDisputes(arg0 context.Context, arg1 db.ListDisputesParams) ([]db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisputes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisputes indicates an expected call of ListDisputes.
+func (mr *MockDisputeStoreMockRecorder) ListDisputes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisputes", reflect.TypeOf((*MockDisputeStore)(nil).ListDisputes), arg0, arg1)
+}
+
+// OpenDisputeTx mocks base method.
+func (m *MockDisputeStore) OpenDisputeTx(arg0 context.Context, arg1 db.CreateDisputeParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDisputeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenDisputeTx indicates an expected call of OpenDisputeTx.
+func (mr *MockDisputeStoreMockRecorder) OpenDisputeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDisputeTx", reflect.TypeOf((*MockDisputeStore)(nil).OpenDisputeTx), arg0, arg1)
+}
+
+// UpdateDisputeStatus mocks base method.
+func (m *MockDisputeStore) UpdateDisputeStatus(arg0 context.Context, arg1 db.UpdateDisputeStatusParams) (db.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDisputeStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDisputeStatus indicates an expected call of UpdateDisputeStatus.
+func (mr *MockDisputeStoreMockRecorder) UpdateDisputeStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDisputeStatus", reflect.TypeOf((*MockDisputeStore)(nil).UpdateDisputeStatus), arg0, arg1)
+}
+
+// MockTransferReviewStore is a mock of TransferReviewStore interface.
+type MockTransferReviewStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferReviewStoreMockRecorder
+}
+
+// MockTransferReviewStoreMockRecorder is the mock recorder for MockTransferReviewStore.
+type MockTransferReviewStoreMockRecorder struct {
+	mock *MockTransferReviewStore
+}
+
+// NewMockTransferReviewStore creates a new mock instance.
+func NewMockTransferReviewStore(ctrl *gomock.Controller) *MockTransferReviewStore {
+	mock := &MockTransferReviewStore{ctrl: ctrl}
+	mock.recorder = &MockTransferReviewStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferReviewStore) EXPECT() *MockTransferReviewStoreMockRecorder {
+	return m.recorder
+}
+
+// AddTransferReviewOTPAttempt mocks base method.
+func (m *MockTransferReviewStore) AddTransferReviewOTPAttempt(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransferReviewOTPAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTransferReviewOTPAttempt indicates an expected call of AddTransferReviewOTPAttempt.
+func (mr *MockTransferReviewStoreMockRecorder) AddTransferReviewOTPAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransferReviewOTPAttempt", reflect.TypeOf((*MockTransferReviewStore)(nil).AddTransferReviewOTPAttempt), arg0, arg1)
+}
+
+// CloseTransferReviewTx mocks base method.
+func (m *MockTransferReviewStore) CloseTransferReviewTx(arg0 context.Context, arg1 db.CloseTransferReviewTxParams) (db.CloseTransferReviewTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseTransferReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CloseTransferReviewTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseTransferReviewTx indicates an expected call of CloseTransferReviewTx.
+func (mr *MockTransferReviewStoreMockRecorder) CloseTransferReviewTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseTransferReviewTx", reflect.TypeOf((*MockTransferReviewStore)(nil).CloseTransferReviewTx), arg0, arg1)
+}
+
+// CountTransfersSince mocks base method.
+func (m *MockTransferReviewStore) CountTransfersSince(arg0 context.Context, arg1 db.CountTransfersSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersSince indicates an expected call of CountTransfersSince.
+func (mr *MockTransferReviewStoreMockRecorder) CountTransfersSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersSince", reflect.TypeOf((*MockTransferReviewStore)(nil).CountTransfersSince), arg0, arg1)
+}
+
+// CreateTransferReview mocks base method.
+func (m *MockTransferReviewStore) CreateTransferReview(arg0 context.Context, arg1 db.CreateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReview indicates an expected call of CreateTransferReview.
+func (mr *MockTransferReviewStoreMockRecorder) CreateTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReview", reflect.TypeOf((*MockTransferReviewStore)(nil).CreateTransferReview), arg0, arg1)
+}
+
+// CreateTransferReviewTx mocks base method.
+func (m *MockTransferReviewStore) CreateTransferReviewTx(arg0 context.Context, arg1 db.CreateTransferReviewTxParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReviewTx indicates an expected call of CreateTransferReviewTx.
+func (mr *MockTransferReviewStoreMockRecorder) CreateTransferReviewTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReviewTx", reflect.TypeOf((*MockTransferReviewStore)(nil).CreateTransferReviewTx), arg0, arg1)
+}
+
+// GetLastLoginCountry mocks base method.
+func (m *MockTransferReviewStore) GetLastLoginCountry(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastLoginCountry", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastLoginCountry indicates an expected call of GetLastLoginCountry.
+func (mr *MockTransferReviewStoreMockRecorder) GetLastLoginCountry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastLoginCountry", reflect.TypeOf((*MockTransferReviewStore)(nil).GetLastLoginCountry), arg0, arg1)
+}
+
+// GetTransferReview mocks base method.
+func (m *MockTransferReviewStore) GetTransferReview(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReview indicates an expected call of GetTransferReview.
+func (mr *MockTransferReviewStoreMockRecorder) GetTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReview", reflect.TypeOf((*MockTransferReviewStore)(nil).GetTransferReview), arg0, arg1)
+}
+
+// GetTransferReviewForUpdate mocks base method.
+func (m *MockTransferReviewStore) GetTransferReviewForUpdate(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReviewForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReviewForUpdate indicates an expected call of GetTransferReviewForUpdate.
+func (mr *MockTransferReviewStoreMockRecorder) GetTransferReviewForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReviewForUpdate", reflect.TypeOf((*MockTransferReviewStore)(nil).GetTransferReviewForUpdate), arg0, arg1)
+}
+
+// HasTransferBetween mocks base method.
+func (m *MockTransferReviewStore) HasTransferBetween(arg0 context.Context, arg1 db.HasTransferBetweenParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTransferBetween", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTransferBetween indicates an expected call of HasTransferBetween.
+func (mr *MockTransferReviewStoreMockRecorder) HasTransferBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTransferBetween", reflect.TypeOf((*MockTransferReviewStore)(nil).HasTransferBetween), arg0, arg1)
+}
+
+// ListTransferReviews mocks base method.
+func (m *MockTransferReviewStore) ListTransferReviews(arg0 context.Context, arg1 db.ListTransferReviewsParams) ([]db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferReviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferReviews indicates an expected call of ListTransferReviews.
+func (mr *MockTransferReviewStoreMockRecorder) ListTransferReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferReviews", reflect.TypeOf((*MockTransferReviewStore)(nil).ListTransferReviews), arg0, arg1)
+}
+
+// UpdateTransferReviewStatus mocks base method.
+func (m *MockTransferReviewStore) UpdateTransferReviewStatus(arg0 context.Context, arg1 db.UpdateTransferReviewStatusParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransferReviewStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransferReviewStatus indicates an expected call of UpdateTransferReviewStatus.
+func (mr *MockTransferReviewStoreMockRecorder) UpdateTransferReviewStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferReviewStatus", reflect.TypeOf((*MockTransferReviewStore)(nil).UpdateTransferReviewStatus), arg0, arg1)
+}
+
+// MockScreeningStore is a mock of ScreeningStore interface.
+type MockScreeningStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockScreeningStoreMockRecorder
+}
+
+// MockScreeningStoreMockRecorder is the mock recorder for MockScreeningStore.
+type MockScreeningStoreMockRecorder struct {
+	mock *MockScreeningStore
+}
+
+// NewMockScreeningStore creates a new mock instance.
+func NewMockScreeningStore(ctrl *gomock.Controller) *MockScreeningStore {
+	mock := &MockScreeningStore{ctrl: ctrl}
+	mock.recorder = &MockScreeningStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScreeningStore) EXPECT() *MockScreeningStoreMockRecorder {
+	return m.recorder
+}
+
+// AddDenylistEntryTx mocks base method.
+func (m *MockScreeningStore) AddDenylistEntryTx(arg0 context.Context, arg1 db.AddDenylistEntryTxParams) (db.AddDenylistEntryTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDenylistEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AddDenylistEntryTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDenylistEntryTx indicates an expected call of AddDenylistEntryTx.
+func (mr *MockScreeningStoreMockRecorder) AddDenylistEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDenylistEntryTx", reflect.TypeOf((*MockScreeningStore)(nil).AddDenylistEntryTx), arg0, arg1)
+}
+
+// CreateDenylistEntry mocks base method.
+func (m *MockScreeningStore) CreateDenylistEntry(arg0 context.Context, arg1 db.CreateDenylistEntryParams) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDenylistEntry indicates an expected call of CreateDenylistEntry.
+func (mr *MockScreeningStoreMockRecorder) CreateDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDenylistEntry", reflect.TypeOf((*MockScreeningStore)(nil).CreateDenylistEntry), arg0, arg1)
+}
+
+// CreateScreeningHold mocks base method.
+func (m *MockScreeningStore) CreateScreeningHold(arg0 context.Context, arg1 db.CreateScreeningHoldParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScreeningHold", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScreeningHold indicates an expected call of CreateScreeningHold.
+func (mr *MockScreeningStoreMockRecorder) CreateScreeningHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScreeningHold", reflect.TypeOf((*MockScreeningStore)(nil).CreateScreeningHold), arg0, arg1)
+}
+
+// DeleteDenylistEntry mocks base method.
+func (m *MockScreeningStore) DeleteDenylistEntry(arg0 context.Context, arg1 int64) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDenylistEntry indicates an expected call of DeleteDenylistEntry.
+func (mr *MockScreeningStoreMockRecorder) DeleteDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDenylistEntry", reflect.TypeOf((*MockScreeningStore)(nil).DeleteDenylistEntry), arg0, arg1)
+}
+
+// DeleteDenylistEntryTx mocks base method.
+func (m *MockScreeningStore) DeleteDenylistEntryTx(arg0 context.Context, arg1 db.DeleteDenylistEntryTxParams) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDenylistEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDenylistEntryTx indicates an expected call of DeleteDenylistEntryTx.
+func (mr *MockScreeningStoreMockRecorder) DeleteDenylistEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDenylistEntryTx", reflect.TypeOf((*MockScreeningStore)(nil).DeleteDenylistEntryTx), arg0, arg1)
+}
+
+// GetDenylistEntry mocks base method.
+func (m *MockScreeningStore) GetDenylistEntry(arg0 context.Context, arg1 int64) (db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDenylistEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDenylistEntry indicates an expected call of GetDenylistEntry.
+func (mr *MockScreeningStoreMockRecorder) GetDenylistEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDenylistEntry", reflect.TypeOf((*MockScreeningStore)(nil).GetDenylistEntry), arg0, arg1)
+}
+
+// GetScreeningHold mocks base method.
+func (m *MockScreeningStore) GetScreeningHold(arg0 context.Context, arg1 int64) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreeningHold", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreeningHold indicates an expected call of GetScreeningHold.
+func (mr *MockScreeningStoreMockRecorder) GetScreeningHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreeningHold", reflect.TypeOf((*MockScreeningStore)(nil).GetScreeningHold), arg0, arg1)
+}
+
+// GetScreeningHoldForUpdate mocks base method.
+func (m *MockScreeningStore) GetScreeningHoldForUpdate(arg0 context.Context, arg1 int64) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreeningHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreeningHoldForUpdate indicates an expected call of GetScreeningHoldForUpdate.
+func (mr *MockScreeningStoreMockRecorder) GetScreeningHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreeningHoldForUpdate", reflect.TypeOf((*MockScreeningStore)(nil).GetScreeningHoldForUpdate), arg0, arg1)
+}
+
+// HoldUsersMatchingDenylist mocks base method.
+func (m *MockScreeningStore) HoldUsersMatchingDenylist(arg0 context.Context, arg1 db.HoldUsersMatchingDenylistParams) ([]db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldUsersMatchingDenylist", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldUsersMatchingDenylist indicates an expected call of HoldUsersMatchingDenylist.
+func (mr *MockScreeningStoreMockRecorder) HoldUsersMatchingDenylist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldUsersMatchingDenylist", reflect.TypeOf((*MockScreeningStore)(nil).HoldUsersMatchingDenylist), arg0, arg1)
+}
+
+// ListAccountScreeningMatches mocks base method.
+func (m *MockScreeningStore) ListAccountScreeningMatches(arg0 context.Context, arg1 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountScreeningMatches", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountScreeningMatches indicates an expected call of ListAccountScreeningMatches.
+func (mr *MockScreeningStoreMockRecorder) ListAccountScreeningMatches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountScreeningMatches", reflect.TypeOf((*MockScreeningStore)(nil).ListAccountScreeningMatches), arg0, arg1)
+}
+
+// ListDenylistEntries mocks base method.
+func (m *MockScreeningStore) ListDenylistEntries(arg0 context.Context, arg1 db.ListDenylistEntriesParams) ([]db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDenylistEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDenylistEntries indicates an expected call of ListDenylistEntries.
+func (mr *MockScreeningStoreMockRecorder) ListDenylistEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDenylistEntries", reflect.TypeOf((*MockScreeningStore)(nil).ListDenylistEntries), arg0, arg1)
+}
+
+// ListScreeningHolds mocks base method.
+func (m *MockScreeningStore) ListScreeningHolds(arg0 context.Context, arg1 db.ListScreeningHoldsParams) ([]db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScreeningHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScreeningHolds indicates an expected call of ListScreeningHolds.
+func (mr *MockScreeningStoreMockRecorder) ListScreeningHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScreeningHolds", reflect.TypeOf((*MockScreeningStore)(nil).ListScreeningHolds), arg0, arg1)
+}
+
+// MatchDenylist mocks base method.
+func (m *MockScreeningStore) MatchDenylist(arg0 context.Context, arg1 db.MatchDenylistParams) ([]db.DenylistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchDenylist", arg0, arg1)
+	ret0, _ := ret[0].([]db.DenylistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchDenylist indicates an expected call of MatchDenylist.
+func (mr *MockScreeningStoreMockRecorder) MatchDenylist(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchDenylist", reflect.TypeOf((*MockScreeningStore)(nil).MatchDenylist), arg0, arg1)
+}
+
+// SetScreeningHoldStatusTx mocks base method.
+func (m *MockScreeningStore) SetScreeningHoldStatusTx(arg0 context.Context, arg1 db.SetScreeningHoldStatusTxParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScreeningHoldStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetScreeningHoldStatusTx indicates an expected call of SetScreeningHoldStatusTx.
+func (mr *MockScreeningStoreMockRecorder) SetScreeningHoldStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScreeningHoldStatusTx", reflect.TypeOf((*MockScreeningStore)(nil).SetScreeningHoldStatusTx), arg0, arg1)
+}
+
+// UpdateScreeningHoldStatus mocks base method.
+func (m *MockScreeningStore) UpdateScreeningHoldStatus(arg0 context.Context, arg1 db.UpdateScreeningHoldStatusParams) (db.ScreeningHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScreeningHoldStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.ScreeningHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScreeningHoldStatus indicates an expected call of UpdateScreeningHoldStatus.
+func (mr *MockScreeningStoreMockRecorder) UpdateScreeningHoldStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScreeningHoldStatus", reflect.TypeOf((*MockScreeningStore)(nil).UpdateScreeningHoldStatus), arg0, arg1)
+}
+
+// MockCategoryStore is a mock of CategoryStore interface.
+type MockCategoryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryStoreMockRecorder
+}
+
+// MockCategoryStoreMockRecorder is the mock recorder for MockCategoryStore.
+type MockCategoryStoreMockRecorder struct {
+	mock *MockCategoryStore
+}
+
+// NewMockCategoryStore creates a new mock instance.
+func NewMockCategoryStore(ctrl *gomock.Controller) *MockCategoryStore {
+	mock := &MockCategoryStore{ctrl: ctrl}
+	mock.recorder = &MockCategoryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryStore) EXPECT() *MockCategoryStoreMockRecorder {
+	return m.recorder
+}
+
+// CategorizeEntry mocks base method.
+func (m *MockCategoryStore) CategorizeEntry(arg0 context.Context, arg1 db.CategorizeEntryParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CategorizeEntry", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CategorizeEntry indicates an expected call of CategorizeEntry.
+func (mr *MockCategoryStoreMockRecorder) CategorizeEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CategorizeEntry", reflect.TypeOf((*MockCategoryStore)(nil).CategorizeEntry), arg0, arg1)
+}
+
+// CreateCategoryRule mocks base method.
+func (m *MockCategoryStore) CreateCategoryRule(arg0 context.Context, arg1 db.CreateCategoryRuleParams) (db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategoryRule indicates an expected call of CreateCategoryRule.
+func (mr *MockCategoryStoreMockRecorder) CreateCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategoryRule", reflect.TypeOf((*MockCategoryStore)(nil).CreateCategoryRule), arg0, arg1)
+}
+
+// DeleteCategoryRule mocks base method.
+func (m *MockCategoryStore) DeleteCategoryRule(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategoryRule indicates an expected call of DeleteCategoryRule.
+func (mr *MockCategoryStoreMockRecorder) DeleteCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategoryRule", reflect.TypeOf((*MockCategoryStore)(nil).DeleteCategoryRule), arg0, arg1)
+}
+
+// GetCategoryRule mocks base method.
+func (m *MockCategoryStore) GetCategoryRule(arg0 context.Context, arg1 int64) (db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryRule", arg0, arg1)
+	ret0, _ := ret[0].(db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryRule indicates an expected call of GetCategoryRule.
+func (mr *MockCategoryStoreMockRecorder) GetCategoryRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryRule", reflect.TypeOf((*MockCategoryStore)(nil).GetCategoryRule), arg0, arg1)
+}
+
+// ListCategoryRules mocks base method.
+func (m *MockCategoryStore) ListCategoryRules(arg0 context.Context, arg1 string) ([]db.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategoryRules", arg0, arg1)
+	ret0, _ := ret[0].([]db.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategoryRules indicates an expected call of ListCategoryRules.
+func (mr *MockCategoryStoreMockRecorder) ListCategoryRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategoryRules", reflect.TypeOf((*MockCategoryStore)(nil).ListCategoryRules), arg0, arg1)
+}
+
+// ListEntryCategories mocks base method.
+func (m *MockCategoryStore) ListEntryCategories(arg0 context.Context, arg1 []int64) ([]db.EntryCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntryCategories", arg0, arg1)
+	ret0, _ := ret[0].([]db.EntryCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntryCategories indicates an expected call of ListEntryCategories.
+func (mr *MockCategoryStoreMockRecorder) ListEntryCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntryCategories", reflect.TypeOf((*MockCategoryStore)(nil).ListEntryCategories), arg0, arg1)
+}
+
+// SetEntryCategory mocks base method.
+func (m *MockCategoryStore) SetEntryCategory(arg0 context.Context, arg1 db.SetEntryCategoryParams) (db.EntryCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntryCategory", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEntryCategory indicates an expected call of SetEntryCategory.
+func (mr *MockCategoryStoreMockRecorder) SetEntryCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntryCategory", reflect.TypeOf((*MockCategoryStore)(nil).SetEntryCategory), arg0, arg1)
+}
+
+// MockAnalyticsStore is a mock of AnalyticsStore interface.
+type MockAnalyticsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsStoreMockRecorder
+}
+
+// MockAnalyticsStoreMockRecorder is the mock recorder for MockAnalyticsStore.
+type MockAnalyticsStoreMockRecorder struct {
+	mock *MockAnalyticsStore
+}
+
+// NewMockAnalyticsStore creates a new mock instance.
+func NewMockAnalyticsStore(ctrl *gomock.Controller) *MockAnalyticsStore {
+	mock := &MockAnalyticsStore{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsStore) EXPECT() *MockAnalyticsStoreMockRecorder {
+	return m.recorder
+}
+
+// SumEntriesByCategory mocks base method.
+func (m *MockAnalyticsStore) SumEntriesByCategory(arg0 context.Context, arg1 db.SumEntriesByCategoryParams) ([]db.SumEntriesByCategoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByCategory", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumEntriesByCategoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByCategory indicates an expected call of SumEntriesByCategory.
+func (mr *MockAnalyticsStoreMockRecorder) SumEntriesByCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByCategory", reflect.TypeOf((*MockAnalyticsStore)(nil).SumEntriesByCategory), arg0, arg1)
+}
+
+// SumEntriesByDay mocks base method.
+func (m *MockAnalyticsStore) SumEntriesByDay(arg0 context.Context, arg1 db.SumEntriesByDayParams) ([]db.SumEntriesByDayRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesByDay", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumEntriesByDayRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesByDay indicates an expected call of SumEntriesByDay.
+func (mr *MockAnalyticsStoreMockRecorder) SumEntriesByDay(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesByDay", reflect.TypeOf((*MockAnalyticsStore)(nil).SumEntriesByDay), arg0, arg1)
+}
+
+// SumEntriesSince mocks base method.
+func (m *MockAnalyticsStore) SumEntriesSince(arg0 context.Context, arg1 db.SumEntriesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesSince indicates an expected call of SumEntriesSince.
+func (mr *MockAnalyticsStoreMockRecorder) SumEntriesSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesSince", reflect.TypeOf((*MockAnalyticsStore)(nil).SumEntriesSince), arg0, arg1)
+}
+
+// SumTransfersByCounterparty mocks base method.
+func (m *MockAnalyticsStore) SumTransfersByCounterparty(arg0 context.Context, arg1 db.SumTransfersByCounterpartyParams) ([]db.SumTransfersByCounterpartyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTransfersByCounterparty", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumTransfersByCounterpartyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTransfersByCounterparty indicates an expected call of SumTransfersByCounterparty.
+func (mr *MockAnalyticsStoreMockRecorder) SumTransfersByCounterparty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTransfersByCounterparty", reflect.TypeOf((*MockAnalyticsStore)(nil).SumTransfersByCounterparty), arg0, arg1)
+}
+
+// MockExportStore is a mock of ExportStore interface.
+type MockExportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportStoreMockRecorder
+}
+
+// MockExportStoreMockRecorder is the mock recorder for MockExportStore.
+type MockExportStoreMockRecorder struct {
+	mock *MockExportStore
+}
+
+// NewMockExportStore creates a new mock instance.
+func NewMockExportStore(ctrl *gomock.Controller) *MockExportStore {
+	mock := &MockExportStore{ctrl: ctrl}
+	mock.recorder = &MockExportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportStore) EXPECT() *MockExportStoreMockRecorder {
+	return m.recorder
+}
+
+// CountExportEntries mocks base method.
+func (m *MockExportStore) CountExportEntries(arg0 context.Context, arg1 db.CountExportEntriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExportEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExportEntries indicates an expected call of CountExportEntries.
+func (mr *MockExportStoreMockRecorder) CountExportEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExportEntries", reflect.TypeOf((*MockExportStore)(nil).CountExportEntries), arg0, arg1)
+}
+
+// CreateEntryExport mocks base method.
+func (m *MockExportStore) CreateEntryExport(arg0 context.Context, arg1 db.CreateEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntryExport indicates an expected call of CreateEntryExport.
+func (mr *MockExportStoreMockRecorder) CreateEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExport", reflect.TypeOf((*MockExportStore)(nil).CreateEntryExport), arg0, arg1)
+}
+
+// CreateEntryExportFile mocks base method.
+func (m *MockExportStore) CreateEntryExportFile(arg0 context.Context, arg1 db.CreateEntryExportFileParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExportFile", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEntryExportFile indicates an expected call of CreateEntryExportFile.
+func (mr *MockExportStoreMockRecorder) CreateEntryExportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExportFile", reflect.TypeOf((*MockExportStore)(nil).CreateEntryExportFile), arg0, arg1)
+}
+
+// CreateEntryExportTx mocks base method.
+func (m *MockExportStore) CreateEntryExportTx(arg0 context.Context, arg1 db.CreateEntryExportTxParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryExportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntryExportTx indicates an expected call of CreateEntryExportTx.
+func (mr *MockExportStoreMockRecorder) CreateEntryExportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryExportTx", reflect.TypeOf((*MockExportStore)(nil).CreateEntryExportTx), arg0, arg1)
+}
+
+// DeleteExpiredEntryExports mocks base method.
+func (m *MockExportStore) DeleteExpiredEntryExports(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredEntryExports", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredEntryExports indicates an expected call of DeleteExpiredEntryExports.
+func (mr *MockExportStoreMockRecorder) DeleteExpiredEntryExports(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredEntryExports", reflect.TypeOf((*MockExportStore)(nil).DeleteExpiredEntryExports), arg0)
+}
+
+// FailEntryExport mocks base method.
+func (m *MockExportStore) FailEntryExport(arg0 context.Context, arg1 db.FailEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailEntryExport indicates an expected call of FailEntryExport.
+func (mr *MockExportStoreMockRecorder) FailEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailEntryExport", reflect.TypeOf((*MockExportStore)(nil).FailEntryExport), arg0, arg1)
+}
+
+// FinishEntryExport mocks base method.
+func (m *MockExportStore) FinishEntryExport(arg0 context.Context, arg1 db.FinishEntryExportParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishEntryExport indicates an expected call of FinishEntryExport.
+func (mr *MockExportStoreMockRecorder) FinishEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishEntryExport", reflect.TypeOf((*MockExportStore)(nil).FinishEntryExport), arg0, arg1)
+}
+
+// FinishEntryExportTx mocks base method.
+func (m *MockExportStore) FinishEntryExportTx(arg0 context.Context, arg1 db.FinishEntryExportTxParams) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishEntryExportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishEntryExportTx indicates an expected call of FinishEntryExportTx.
+func (mr *MockExportStoreMockRecorder) FinishEntryExportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishEntryExportTx", reflect.TypeOf((*MockExportStore)(nil).FinishEntryExportTx), arg0, arg1)
+}
+
+// GetEntryExport mocks base method.
+func (m *MockExportStore) GetEntryExport(arg0 context.Context, arg1 int64) (db.EntryExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryExport", arg0, arg1)
+	ret0, _ := ret[0].(db.EntryExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryExport indicates an expected call of GetEntryExport.
+func (mr *MockExportStoreMockRecorder) GetEntryExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryExport", reflect.TypeOf((*MockExportStore)(nil).GetEntryExport), arg0, arg1)
+}
+
+// GetEntryExportFile mocks base method.
+func (m *MockExportStore) GetEntryExportFile(arg0 context.Context, arg1 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryExportFile", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryExportFile indicates an expected call of GetEntryExportFile.
+func (mr *MockExportStoreMockRecorder) GetEntryExportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryExportFile", reflect.TypeOf((*MockExportStore)(nil).GetEntryExportFile), arg0, arg1)
+}
+
+// ListExportEntries mocks base method.
+func (m *MockExportStore) ListExportEntries(arg0 context.Context, arg1 db.ListExportEntriesParams) ([]db.ListExportEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListExportEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExportEntries indicates an expected call of ListExportEntries.
+func (mr *MockExportStoreMockRecorder) ListExportEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportEntries", reflect.TypeOf((*MockExportStore)(nil).ListExportEntries), arg0, arg1)
+}
+
+// MockExternalTransferStore is a mock of ExternalTransferStore interface.
+type MockExternalTransferStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExternalTransferStoreMockRecorder
+}
+
+// MockExternalTransferStoreMockRecorder is the mock recorder for MockExternalTransferStore.
+type MockExternalTransferStoreMockRecorder struct {
+	mock *MockExternalTransferStore
+}
+
+// NewMockExternalTransferStore creates a new mock instance.
+func NewMockExternalTransferStore(ctrl *gomock.Controller) *MockExternalTransferStore {
+	mock := &MockExternalTransferStore{ctrl: ctrl}
+	mock.recorder = &MockExternalTransferStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExternalTransferStore) EXPECT() *MockExternalTransferStoreMockRecorder {
+	return m.recorder
+}
+
+// CompleteExternalTransfer mocks base method.
+func (m *MockExternalTransferStore) CompleteExternalTransfer(arg0 context.Context, arg1 db.CompleteExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteExternalTransfer indicates an expected call of CompleteExternalTransfer.
+func (mr *MockExternalTransferStoreMockRecorder) CompleteExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExternalTransfer", reflect.TypeOf((*MockExternalTransferStore)(nil).CompleteExternalTransfer), arg0, arg1)
+}
+
+// CompleteExternalTransferTx mocks base method.
+func (m *MockExternalTransferStore) CompleteExternalTransferTx(arg0 context.Context, arg1 db.CompleteExternalTransferTxParams) (db.CompleteExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CompleteExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteExternalTransferTx indicates an expected call of CompleteExternalTransferTx.
+func (mr *MockExternalTransferStoreMockRecorder) CompleteExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteExternalTransferTx", reflect.TypeOf((*MockExternalTransferStore)(nil).CompleteExternalTransferTx), arg0, arg1)
+}
+
+// CreateExternalTransfer mocks base method.
+func (m *MockExternalTransferStore) CreateExternalTransfer(arg0 context.Context, arg1 db.CreateExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalTransfer indicates an expected call of CreateExternalTransfer.
+func (mr *MockExternalTransferStoreMockRecorder) CreateExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalTransfer", reflect.TypeOf((*MockExternalTransferStore)(nil).CreateExternalTransfer), arg0, arg1)
+}
+
+// CreateExternalTransferTx mocks base method.
+func (m *MockExternalTransferStore) CreateExternalTransferTx(arg0 context.Context, arg1 db.CreateExternalTransferTxParams) (db.CreateExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalTransferTx indicates an expected call of CreateExternalTransferTx.
+func (mr *MockExternalTransferStoreMockRecorder) CreateExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalTransferTx", reflect.TypeOf((*MockExternalTransferStore)(nil).CreateExternalTransferTx), arg0, arg1)
+}
+
+// GetExternalTransfer mocks base method.
+func (m *MockExternalTransferStore) GetExternalTransfer(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransfer indicates an expected call of GetExternalTransfer.
+func (mr *MockExternalTransferStoreMockRecorder) GetExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransfer", reflect.TypeOf((*MockExternalTransferStore)(nil).GetExternalTransfer), arg0, arg1)
+}
+
+// GetExternalTransferForUpdate mocks base method.
+func (m *MockExternalTransferStore) GetExternalTransferForUpdate(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransferForUpdate indicates an expected call of GetExternalTransferForUpdate.
+func (mr *MockExternalTransferStoreMockRecorder) GetExternalTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransferForUpdate", reflect.TypeOf((*MockExternalTransferStore)(nil).GetExternalTransferForUpdate), arg0, arg1)
+}
+
+// GetSuspenseAccount mocks base method.
+func (m *MockExternalTransferStore) GetSuspenseAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuspenseAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuspenseAccount indicates an expected call of GetSuspenseAccount.
+func (mr *MockExternalTransferStoreMockRecorder) GetSuspenseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuspenseAccount", reflect.TypeOf((*MockExternalTransferStore)(nil).GetSuspenseAccount), arg0, arg1)
+}
+
+// ListAccountExternalTransfers mocks base method.
+func (m *MockExternalTransferStore) ListAccountExternalTransfers(arg0 context.Context, arg1 db.ListAccountExternalTransfersParams) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountExternalTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountExternalTransfers indicates an expected call of ListAccountExternalTransfers.
+func (mr *MockExternalTransferStoreMockRecorder) ListAccountExternalTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountExternalTransfers", reflect.TypeOf((*MockExternalTransferStore)(nil).ListAccountExternalTransfers), arg0, arg1)
+}
+
+// ListExternalTransfersToSettle mocks base method.
+func (m *MockExternalTransferStore) ListExternalTransfersToSettle(arg0 context.Context, arg1 int32) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalTransfersToSettle", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalTransfersToSettle indicates an expected call of ListExternalTransfersToSettle.
+func (mr *MockExternalTransferStoreMockRecorder) ListExternalTransfersToSettle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalTransfersToSettle", reflect.TypeOf((*MockExternalTransferStore)(nil).ListExternalTransfersToSettle), arg0, arg1)
+}
+
+// SubmitExternalTransfer mocks base method.
+func (m *MockExternalTransferStore) SubmitExternalTransfer(arg0 context.Context, arg1 db.SubmitExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitExternalTransfer indicates an expected call of SubmitExternalTransfer.
+func (mr *MockExternalTransferStoreMockRecorder) SubmitExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitExternalTransfer", reflect.TypeOf((*MockExternalTransferStore)(nil).SubmitExternalTransfer), arg0, arg1)
+}
+
+// MockCardStore is a mock of CardStore interface.
+type MockCardStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardStoreMockRecorder
+}
+
+// MockCardStoreMockRecorder is the mock recorder for MockCardStore.
+type MockCardStoreMockRecorder struct {
+	mock *MockCardStore
+}
+
+// NewMockCardStore creates a new mock instance.
+func NewMockCardStore(ctrl *gomock.Controller) *MockCardStore {
+	mock := &MockCardStore{ctrl: ctrl}
+	mock.recorder = &MockCardStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCardStore) EXPECT() *MockCardStoreMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeCardTx mocks base method.
+func (m *MockCardStore) AuthorizeCardTx(arg0 context.Context, arg1 db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeCardTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizeCardTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeCardTx indicates an expected call of AuthorizeCardTx.
+func (mr *MockCardStoreMockRecorder) AuthorizeCardTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeCardTx", reflect.TypeOf((*MockCardStore)(nil).AuthorizeCardTx), arg0, arg1)
+}
+
+// CreateCard mocks base method.
+func (m *MockCardStore) CreateCard(arg0 context.Context, arg1 db.CreateCardParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCard indicates an expected call of CreateCard.
+func (mr *MockCardStoreMockRecorder) CreateCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockCardStore)(nil).CreateCard), arg0, arg1)
+}
+
+// CreateCardAuthorization mocks base method.
+func (m *MockCardStore) CreateCardAuthorization(arg0 context.Context, arg1 db.CreateCardAuthorizationParams) (db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCardAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCardAuthorization indicates an expected call of CreateCardAuthorization.
+func (mr *MockCardStoreMockRecorder) CreateCardAuthorization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCardAuthorization", reflect.TypeOf((*MockCardStore)(nil).CreateCardAuthorization), arg0, arg1)
+}
+
+// GetCard mocks base method.
+func (m *MockCardStore) GetCard(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCard indicates an expected call of GetCard.
+func (mr *MockCardStoreMockRecorder) GetCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockCardStore)(nil).GetCard), arg0, arg1)
+}
+
+// GetCardAuthorizationByNetworkTransactionID mocks base method.
+func (m *MockCardStore) GetCardAuthorizationByNetworkTransactionID(arg0 context.Context, arg1 string) (db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardAuthorizationByNetworkTransactionID", arg0, arg1)
+	ret0, _ := ret[0].(db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardAuthorizationByNetworkTransactionID indicates an expected call of GetCardAuthorizationByNetworkTransactionID.
+func (mr *MockCardStoreMockRecorder) GetCardAuthorizationByNetworkTransactionID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardAuthorizationByNetworkTransactionID", reflect.TypeOf((*MockCardStore)(nil).GetCardAuthorizationByNetworkTransactionID), arg0, arg1)
+}
+
+// GetCardByFingerprint mocks base method.
+func (m *MockCardStore) GetCardByFingerprint(arg0 context.Context, arg1 string) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardByFingerprint", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardByFingerprint indicates an expected call of GetCardByFingerprint.
+func (mr *MockCardStoreMockRecorder) GetCardByFingerprint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardByFingerprint", reflect.TypeOf((*MockCardStore)(nil).GetCardByFingerprint), arg0, arg1)
+}
+
+// GetCardForUpdate mocks base method.
+func (m *MockCardStore) GetCardForUpdate(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardForUpdate indicates an expected call of GetCardForUpdate.
+func (mr *MockCardStoreMockRecorder) GetCardForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardForUpdate", reflect.TypeOf((*MockCardStore)(nil).GetCardForUpdate), arg0, arg1)
+}
+
+// ListAccountCards mocks base method.
+func (m *MockCardStore) ListAccountCards(arg0 context.Context, arg1 int64) ([]db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountCards", arg0, arg1)
+	ret0, _ := ret[0].([]db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountCards indicates an expected call of ListAccountCards.
+func (mr *MockCardStoreMockRecorder) ListAccountCards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountCards", reflect.TypeOf((*MockCardStore)(nil).ListAccountCards), arg0, arg1)
+}
+
+// ListCardAuthorizations mocks base method.
+func (m *MockCardStore) ListCardAuthorizations(arg0 context.Context, arg1 db.ListCardAuthorizationsParams) ([]db.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCardAuthorizations", arg0, arg1)
+	ret0, _ := ret[0].([]db.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCardAuthorizations indicates an expected call of ListCardAuthorizations.
+func (mr *MockCardStoreMockRecorder) ListCardAuthorizations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCardAuthorizations", reflect.TypeOf((*MockCardStore)(nil).ListCardAuthorizations), arg0, arg1)
+}
+
+// SetCardFrozen mocks base method.
+func (m *MockCardStore) SetCardFrozen(arg0 context.Context, arg1 db.SetCardFrozenParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCardFrozen", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCardFrozen indicates an expected call of SetCardFrozen.
+func (mr *MockCardStoreMockRecorder) SetCardFrozen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCardFrozen", reflect.TypeOf((*MockCardStore)(nil).SetCardFrozen), arg0, arg1)
+}
+
+// SumCardAuthorizations mocks base method.
+func (m *MockCardStore) SumCardAuthorizations(arg0 context.Context, arg1 db.SumCardAuthorizationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumCardAuthorizations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumCardAuthorizations indicates an expected call of SumCardAuthorizations.
+func (mr *MockCardStoreMockRecorder) SumCardAuthorizations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumCardAuthorizations", reflect.TypeOf((*MockCardStore)(nil).SumCardAuthorizations), arg0, arg1)
+}
+
+// UpdateCardLimits mocks base method.
+func (m *MockCardStore) UpdateCardLimits(arg0 context.Context, arg1 db.UpdateCardLimitsParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCardLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCardLimits indicates an expected call of UpdateCardLimits.
+func (mr *MockCardStoreMockRecorder) UpdateCardLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCardLimits", reflect.TypeOf((*MockCardStore)(nil).UpdateCardLimits), arg0, arg1)
+}
+
+// MockAuthorizationHoldStore is a mock of AuthorizationHoldStore interface.
+type MockAuthorizationHoldStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationHoldStoreMockRecorder
+}
+
+// MockAuthorizationHoldStoreMockRecorder is the mock recorder for MockAuthorizationHoldStore.
+type MockAuthorizationHoldStoreMockRecorder struct {
+	mock *MockAuthorizationHoldStore
+}
+
+// NewMockAuthorizationHoldStore creates a new mock instance.
+func NewMockAuthorizationHoldStore(ctrl *gomock.Controller) *MockAuthorizationHoldStore {
+	mock := &MockAuthorizationHoldStore{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationHoldStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationHoldStore) EXPECT() *MockAuthorizationHoldStoreMockRecorder {
+	return m.recorder
+}
+
+// AddAccountHeldBalance mocks base method.
+func (m *MockAuthorizationHoldStore) AddAccountHeldBalance(arg0 context.Context, arg1 db.AddAccountHeldBalanceParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountHeldBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountHeldBalance indicates an expected call of AddAccountHeldBalance.
+func (mr *MockAuthorizationHoldStoreMockRecorder) AddAccountHeldBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountHeldBalance", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).AddAccountHeldBalance), arg0, arg1)
+}
+
+// CaptureAuthorizationHoldTx mocks base method.
+func (m *MockAuthorizationHoldStore) CaptureAuthorizationHoldTx(arg0 context.Context, arg1 db.CaptureAuthorizationHoldTxParams) (db.CaptureAuthorizationHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CaptureAuthorizationHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureAuthorizationHoldTx indicates an expected call of CaptureAuthorizationHoldTx.
+func (mr *MockAuthorizationHoldStoreMockRecorder) CaptureAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureAuthorizationHoldTx", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).CaptureAuthorizationHoldTx), arg0, arg1)
+}
+
+// CloseAuthorizationHold mocks base method.
+func (m *MockAuthorizationHoldStore) CloseAuthorizationHold(arg0 context.Context, arg1 db.CloseAuthorizationHoldParams) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAuthorizationHold indicates an expected call of CloseAuthorizationHold.
+func (mr *MockAuthorizationHoldStoreMockRecorder) CloseAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAuthorizationHold", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).CloseAuthorizationHold), arg0, arg1)
+}
+
+// CreateAuthorizationHold mocks base method.
+func (m *MockAuthorizationHoldStore) CreateAuthorizationHold(arg0 context.Context, arg1 db.CreateAuthorizationHoldParams) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorizationHold indicates an expected call of CreateAuthorizationHold.
+func (mr *MockAuthorizationHoldStoreMockRecorder) CreateAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorizationHold", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).CreateAuthorizationHold), arg0, arg1)
+}
+
+// GetAuthorizationHold mocks base method.
+func (m *MockAuthorizationHoldStore) GetAuthorizationHold(arg0 context.Context, arg1 int64) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationHold", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorizationHold indicates an expected call of GetAuthorizationHold.
+func (mr *MockAuthorizationHoldStoreMockRecorder) GetAuthorizationHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationHold", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).GetAuthorizationHold), arg0, arg1)
+}
+
+// GetAuthorizationHoldForUpdate mocks base method.
+func (m *MockAuthorizationHoldStore) GetAuthorizationHoldForUpdate(arg0 context.Context, arg1 int64) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizationHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorizationHoldForUpdate indicates an expected call of GetAuthorizationHoldForUpdate.
+func (mr *MockAuthorizationHoldStoreMockRecorder) GetAuthorizationHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizationHoldForUpdate", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).GetAuthorizationHoldForUpdate), arg0, arg1)
+}
+
+// ListAccountAuthorizationHolds mocks base method.
+func (m *MockAuthorizationHoldStore) ListAccountAuthorizationHolds(arg0 context.Context, arg1 db.ListAccountAuthorizationHoldsParams) ([]db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountAuthorizationHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountAuthorizationHolds indicates an expected call of ListAccountAuthorizationHolds.
+func (mr *MockAuthorizationHoldStoreMockRecorder) ListAccountAuthorizationHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountAuthorizationHolds", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).ListAccountAuthorizationHolds), arg0, arg1)
+}
+
+// ListExpiredAuthorizationHolds mocks base method.
+func (m *MockAuthorizationHoldStore) ListExpiredAuthorizationHolds(arg0 context.Context, arg1 int32) ([]db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAuthorizationHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAuthorizationHolds indicates an expected call of ListExpiredAuthorizationHolds.
+func (mr *MockAuthorizationHoldStoreMockRecorder) ListExpiredAuthorizationHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAuthorizationHolds", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).ListExpiredAuthorizationHolds), arg0, arg1)
+}
+
+// PlaceAuthorizationHoldTx mocks base method.
+func (m *MockAuthorizationHoldStore) PlaceAuthorizationHoldTx(arg0 context.Context, arg1 db.CreateAuthorizationHoldParams) (db.PlaceAuthorizationHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PlaceAuthorizationHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceAuthorizationHoldTx indicates an expected call of PlaceAuthorizationHoldTx.
+func (mr *MockAuthorizationHoldStoreMockRecorder) PlaceAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceAuthorizationHoldTx", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).PlaceAuthorizationHoldTx), arg0, arg1)
+}
+
+// ReleaseAuthorizationHoldTx mocks base method.
+func (m *MockAuthorizationHoldStore) ReleaseAuthorizationHoldTx(arg0 context.Context, arg1 db.ReleaseAuthorizationHoldTxParams) (db.AuthorizationHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseAuthorizationHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizationHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseAuthorizationHoldTx indicates an expected call of ReleaseAuthorizationHoldTx.
+func (mr *MockAuthorizationHoldStoreMockRecorder) ReleaseAuthorizationHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseAuthorizationHoldTx", reflect.TypeOf((*MockAuthorizationHoldStore)(nil).ReleaseAuthorizationHoldTx), arg0, arg1)
+}
+
+// MockPaymentRequestStore is a mock of PaymentRequestStore interface.
+type MockPaymentRequestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestStoreMockRecorder
+}
+
+// MockPaymentRequestStoreMockRecorder is the mock recorder for MockPaymentRequestStore.
+type MockPaymentRequestStoreMockRecorder struct {
+	mock *MockPaymentRequestStore
+}
+
+// NewMockPaymentRequestStore creates a new mock instance.
+func NewMockPaymentRequestStore(ctrl *gomock.Controller) *MockPaymentRequestStore {
+	mock := &MockPaymentRequestStore{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequestStore) EXPECT() *MockPaymentRequestStoreMockRecorder {
+	return m.recorder
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockPaymentRequestStore) CreatePaymentRequest(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockPaymentRequestStoreMockRecorder) CreatePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockPaymentRequestStore)(nil).CreatePaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockPaymentRequestStore) GetPaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockPaymentRequestStoreMockRecorder) GetPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockPaymentRequestStore)(nil).GetPaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockPaymentRequestStore) GetPaymentRequestForUpdate(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockPaymentRequestStoreMockRecorder) GetPaymentRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockPaymentRequestStore)(nil).GetPaymentRequestForUpdate), arg0, arg1)
+}
+
+// ListAccountPaymentRequests mocks base method.
+func (m *MockPaymentRequestStore) ListAccountPaymentRequests(arg0 context.Context, arg1 db.ListAccountPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountPaymentRequests indicates an expected call of ListAccountPaymentRequests.
+func (mr *MockPaymentRequestStoreMockRecorder) ListAccountPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountPaymentRequests", reflect.TypeOf((*MockPaymentRequestStore)(nil).ListAccountPaymentRequests), arg0, arg1)
+}
+
+// MarkPaymentRequestPaid mocks base method.
+func (m *MockPaymentRequestStore) MarkPaymentRequestPaid(arg0 context.Context, arg1 db.MarkPaymentRequestPaidParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaymentRequestPaid", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkPaymentRequestPaid indicates an expected call of MarkPaymentRequestPaid.
+func (mr *MockPaymentRequestStoreMockRecorder) MarkPaymentRequestPaid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaymentRequestPaid", reflect.TypeOf((*MockPaymentRequestStore)(nil).MarkPaymentRequestPaid), arg0, arg1)
+}
+
+// PayPaymentRequestTx mocks base method.
+func (m *MockPaymentRequestStore) PayPaymentRequestTx(arg0 context.Context, arg1 db.PayPaymentRequestTxParams) (db.PayPaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayPaymentRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayPaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayPaymentRequestTx indicates an expected call of PayPaymentRequestTx.
+func (mr *MockPaymentRequestStoreMockRecorder) PayPaymentRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentRequestTx", reflect.TypeOf((*MockPaymentRequestStore)(nil).PayPaymentRequestTx), arg0, arg1)
+}
+
+// MockRefundStore is a mock of RefundStore interface.
+type MockRefundStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundStoreMockRecorder
+}
+
+// MockRefundStoreMockRecorder is the mock recorder for MockRefundStore.
+type MockRefundStoreMockRecorder struct {
+	mock *MockRefundStore
+}
+
+// NewMockRefundStore creates a new mock instance.
+func NewMockRefundStore(ctrl *gomock.Controller) *MockRefundStore {
+	mock := &MockRefundStore{ctrl: ctrl}
+	mock.recorder = &MockRefundStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundStore) EXPECT() *MockRefundStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateRefund mocks base method.
+func (m *MockRefundStore) CreateRefund(arg0 context.Context, arg1 db.CreateRefundParams) (db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockRefundStoreMockRecorder) CreateRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockRefundStore)(nil).CreateRefund), arg0, arg1)
+}
+
+// GetRefundedAmount mocks base method.
+func (m *MockRefundStore) GetRefundedAmount(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundedAmount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundedAmount indicates an expected call of GetRefundedAmount.
+func (mr *MockRefundStoreMockRecorder) GetRefundedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundedAmount", reflect.TypeOf((*MockRefundStore)(nil).GetRefundedAmount), arg0, arg1)
+}
+
+// GetReturnedAmount mocks base method.
+func (m *MockRefundStore) GetReturnedAmount(arg0 context.Context, arg1 int64) (db.GetReturnedAmountRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturnedAmount", arg0, arg1)
+	ret0, _ := ret[0].(db.GetReturnedAmountRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturnedAmount indicates an expected call of GetReturnedAmount.
+func (mr *MockRefundStoreMockRecorder) GetReturnedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturnedAmount", reflect.TypeOf((*MockRefundStore)(nil).GetReturnedAmount), arg0, arg1)
+}
+
+// ListTransferRefunds mocks base method.
+func (m *MockRefundStore) ListTransferRefunds(arg0 context.Context, arg1 int64) ([]db.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferRefunds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferRefunds indicates an expected call of ListTransferRefunds.
+func (mr *MockRefundStoreMockRecorder) ListTransferRefunds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferRefunds", reflect.TypeOf((*MockRefundStore)(nil).ListTransferRefunds), arg0, arg1)
+}
+
+// RefundTransferTx mocks base method.
+func (m *MockRefundStore) RefundTransferTx(arg0 context.Context, arg1 db.RefundTransferTxParams) (db.RefundTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RefundTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTransferTx indicates an expected call of RefundTransferTx.
+func (mr *MockRefundStoreMockRecorder) RefundTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTransferTx", reflect.TypeOf((*MockRefundStore)(nil).RefundTransferTx), arg0, arg1)
+}
+
+// MockLoanStore is a mock of LoanStore interface.
+type MockLoanStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanStoreMockRecorder
+}
+
+// MockLoanStoreMockRecorder is the mock recorder for MockLoanStore.
+type MockLoanStoreMockRecorder struct {
+	mock *MockLoanStore
+}
+
+// NewMockLoanStore creates a new mock instance.
+func NewMockLoanStore(ctrl *gomock.Controller) *MockLoanStore {
+	mock := &MockLoanStore{ctrl: ctrl}
+	mock.recorder = &MockLoanStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanStore) EXPECT() *MockLoanStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateLoan mocks base method.
+func (m *MockLoanStore) CreateLoan(arg0 context.Context, arg1 db.CreateLoanParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoan", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoan indicates an expected call of CreateLoan.
+func (mr *MockLoanStoreMockRecorder) CreateLoan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoan", reflect.TypeOf((*MockLoanStore)(nil).CreateLoan), arg0, arg1)
+}
+
+// CreateLoanInstallment mocks base method.
+func (m *MockLoanStore) CreateLoanInstallment(arg0 context.Context, arg1 db.CreateLoanInstallmentParams) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanInstallment", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanInstallment indicates an expected call of CreateLoanInstallment.
+func (mr *MockLoanStoreMockRecorder) CreateLoanInstallment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanInstallment", reflect.TypeOf((*MockLoanStore)(nil).CreateLoanInstallment), arg0, arg1)
+}
+
+// CreateLoanOffer mocks base method.
+func (m *MockLoanStore) CreateLoanOffer(arg0 context.Context, arg1 db.CreateLoanOfferParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanOffer indicates an expected call of CreateLoanOffer.
+func (mr *MockLoanStoreMockRecorder) CreateLoanOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanOffer", reflect.TypeOf((*MockLoanStore)(nil).CreateLoanOffer), arg0, arg1)
+}
+
+// CreateLoanOfferTx mocks base method.
+func (m *MockLoanStore) CreateLoanOfferTx(arg0 context.Context, arg1 db.CreateLoanOfferTxParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoanOfferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoanOfferTx indicates an expected call of CreateLoanOfferTx.
+func (mr *MockLoanStoreMockRecorder) CreateLoanOfferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoanOfferTx", reflect.TypeOf((*MockLoanStore)(nil).CreateLoanOfferTx), arg0, arg1)
+}
+
+// DisburseLoanTx mocks base method.
+func (m *MockLoanStore) DisburseLoanTx(arg0 context.Context, arg1 db.DisburseLoanTxParams) (db.DisburseLoanTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisburseLoanTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DisburseLoanTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisburseLoanTx indicates an expected call of DisburseLoanTx.
+func (mr *MockLoanStoreMockRecorder) DisburseLoanTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisburseLoanTx", reflect.TypeOf((*MockLoanStore)(nil).DisburseLoanTx), arg0, arg1)
+}
+
+// GetLendingAccount mocks base method.
+func (m *MockLoanStore) GetLendingAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLendingAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLendingAccount indicates an expected call of GetLendingAccount.
+func (mr *MockLoanStoreMockRecorder) GetLendingAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLendingAccount", reflect.TypeOf((*MockLoanStore)(nil).GetLendingAccount), arg0, arg1)
+}
+
+// GetLoan mocks base method.
+func (m *MockLoanStore) GetLoan(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoan", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoan indicates an expected call of GetLoan.
+func (mr *MockLoanStoreMockRecorder) GetLoan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoan", reflect.TypeOf((*MockLoanStore)(nil).GetLoan), arg0, arg1)
+}
+
+// GetLoanForUpdate mocks base method.
+func (m *MockLoanStore) GetLoanForUpdate(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanForUpdate indicates an expected call of GetLoanForUpdate.
+func (mr *MockLoanStoreMockRecorder) GetLoanForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanForUpdate", reflect.TypeOf((*MockLoanStore)(nil).GetLoanForUpdate), arg0, arg1)
+}
+
+// GetLoanInstallmentForUpdate mocks base method.
+func (m *MockLoanStore) GetLoanInstallmentForUpdate(arg0 context.Context, arg1 int64) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanInstallmentForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanInstallmentForUpdate indicates an expected call of GetLoanInstallmentForUpdate.
+func (mr *MockLoanStoreMockRecorder) GetLoanInstallmentForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanInstallmentForUpdate", reflect.TypeOf((*MockLoanStore)(nil).GetLoanInstallmentForUpdate), arg0, arg1)
+}
+
+// GetLoanOffer mocks base method.
+func (m *MockLoanStore) GetLoanOffer(arg0 context.Context, arg1 int64) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanOffer", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanOffer indicates an expected call of GetLoanOffer.
+func (mr *MockLoanStoreMockRecorder) GetLoanOffer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanOffer", reflect.TypeOf((*MockLoanStore)(nil).GetLoanOffer), arg0, arg1)
+}
+
+// GetLoanStanding mocks base method.
+func (m *MockLoanStore) GetLoanStanding(arg0 context.Context, arg1 int64) (db.GetLoanStandingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanStanding", arg0, arg1)
+	ret0, _ := ret[0].(db.GetLoanStandingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanStanding indicates an expected call of GetLoanStanding.
+func (mr *MockLoanStoreMockRecorder) GetLoanStanding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanStanding", reflect.TypeOf((*MockLoanStore)(nil).GetLoanStanding), arg0, arg1)
+}
+
+// ListAccountLoans mocks base method.
+func (m *MockLoanStore) ListAccountLoans(arg0 context.Context, arg1 db.ListAccountLoansParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountLoans", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountLoans indicates an expected call of ListAccountLoans.
+func (mr *MockLoanStoreMockRecorder) ListAccountLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountLoans", reflect.TypeOf((*MockLoanStore)(nil).ListAccountLoans), arg0, arg1)
+}
+
+// ListDelinquentLoans mocks base method.
+func (m *MockLoanStore) ListDelinquentLoans(arg0 context.Context, arg1 db.ListDelinquentLoansParams) ([]db.ListDelinquentLoansRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelinquentLoans", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDelinquentLoansRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelinquentLoans indicates an expected call of ListDelinquentLoans.
+func (mr *MockLoanStoreMockRecorder) ListDelinquentLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelinquentLoans", reflect.TypeOf((*MockLoanStore)(nil).ListDelinquentLoans), arg0, arg1)
+}
+
+// ListDueLoanInstallments mocks base method.
+func (m *MockLoanStore) ListDueLoanInstallments(arg0 context.Context, arg1 int32) ([]db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueLoanInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueLoanInstallments indicates an expected call of ListDueLoanInstallments.
+func (mr *MockLoanStoreMockRecorder) ListDueLoanInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueLoanInstallments", reflect.TypeOf((*MockLoanStore)(nil).ListDueLoanInstallments), arg0, arg1)
+}
+
+// ListLoanInstallments mocks base method.
+func (m *MockLoanStore) ListLoanInstallments(arg0 context.Context, arg1 int64) ([]db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoanInstallments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoanInstallments indicates an expected call of ListLoanInstallments.
+func (mr *MockLoanStoreMockRecorder) ListLoanInstallments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoanInstallments", reflect.TypeOf((*MockLoanStore)(nil).ListLoanInstallments), arg0, arg1)
+}
+
+// ListLoanOffers mocks base method.
+func (m *MockLoanStore) ListLoanOffers(arg0 context.Context, arg1 bool) ([]db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoanOffers", arg0, arg1)
+	ret0, _ := ret[0].([]db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoanOffers indicates an expected call of ListLoanOffers.
+func (mr *MockLoanStoreMockRecorder) ListLoanOffers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoanOffers", reflect.TypeOf((*MockLoanStore)(nil).ListLoanOffers), arg0, arg1)
+}
+
+// MarkLoanDelinquent mocks base method.
+func (m *MockLoanStore) MarkLoanDelinquent(arg0 context.Context, arg1 int64) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanDelinquent", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanDelinquent indicates an expected call of MarkLoanDelinquent.
+func (mr *MockLoanStoreMockRecorder) MarkLoanDelinquent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanDelinquent", reflect.TypeOf((*MockLoanStore)(nil).MarkLoanDelinquent), arg0, arg1)
+}
+
+// MarkLoanDelinquentTx mocks base method.
+func (m *MockLoanStore) MarkLoanDelinquentTx(arg0 context.Context, arg1 db.MarkLoanDelinquentTxParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanDelinquentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanDelinquentTx indicates an expected call of MarkLoanDelinquentTx.
+func (mr *MockLoanStoreMockRecorder) MarkLoanDelinquentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanDelinquentTx", reflect.TypeOf((*MockLoanStore)(nil).MarkLoanDelinquentTx), arg0, arg1)
+}
+
+// MarkLoanInstallmentPaid mocks base method.
+func (m *MockLoanStore) MarkLoanInstallmentPaid(arg0 context.Context, arg1 db.MarkLoanInstallmentPaidParams) (db.LoanInstallment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoanInstallmentPaid", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanInstallment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoanInstallmentPaid indicates an expected call of MarkLoanInstallmentPaid.
+func (mr *MockLoanStoreMockRecorder) MarkLoanInstallmentPaid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoanInstallmentPaid", reflect.TypeOf((*MockLoanStore)(nil).MarkLoanInstallmentPaid), arg0, arg1)
+}
+
+// RepayLoanInstallmentTx mocks base method.
+func (m *MockLoanStore) RepayLoanInstallmentTx(arg0 context.Context, arg1 db.RepayLoanInstallmentTxParams) (db.RepayLoanInstallmentTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepayLoanInstallmentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RepayLoanInstallmentTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepayLoanInstallmentTx indicates an expected call of RepayLoanInstallmentTx.
+func (mr *MockLoanStoreMockRecorder) RepayLoanInstallmentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepayLoanInstallmentTx", reflect.TypeOf((*MockLoanStore)(nil).RepayLoanInstallmentTx), arg0, arg1)
+}
+
+// SetLoanOfferActive mocks base method.
+func (m *MockLoanStore) SetLoanOfferActive(arg0 context.Context, arg1 db.SetLoanOfferActiveParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanOfferActive", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanOfferActive indicates an expected call of SetLoanOfferActive.
+func (mr *MockLoanStoreMockRecorder) SetLoanOfferActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanOfferActive", reflect.TypeOf((*MockLoanStore)(nil).SetLoanOfferActive), arg0, arg1)
+}
+
+// SetLoanOfferActiveTx mocks base method.
+func (m *MockLoanStore) SetLoanOfferActiveTx(arg0 context.Context, arg1 db.SetLoanOfferActiveTxParams) (db.LoanOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanOfferActiveTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoanOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanOfferActiveTx indicates an expected call of SetLoanOfferActiveTx.
+func (mr *MockLoanStoreMockRecorder) SetLoanOfferActiveTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanOfferActiveTx", reflect.TypeOf((*MockLoanStore)(nil).SetLoanOfferActiveTx), arg0, arg1)
+}
+
+// SetLoanStatus mocks base method.
+func (m *MockLoanStore) SetLoanStatus(arg0 context.Context, arg1 db.SetLoanStatusParams) (db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoanStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoanStatus indicates an expected call of SetLoanStatus.
+func (mr *MockLoanStoreMockRecorder) SetLoanStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoanStatus", reflect.TypeOf((*MockLoanStore)(nil).SetLoanStatus), arg0, arg1)
+}
+
+// MockRewardStore is a mock of RewardStore interface.
+type MockRewardStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRewardStoreMockRecorder
+}
+
+// MockRewardStoreMockRecorder is the mock recorder for MockRewardStore.
+type MockRewardStoreMockRecorder struct {
+	mock *MockRewardStore
+}
+
+// NewMockRewardStore creates a new mock instance.
+func NewMockRewardStore(ctrl *gomock.Controller) *MockRewardStore {
+	mock := &MockRewardStore{ctrl: ctrl}
+	mock.recorder = &MockRewardStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRewardStore) EXPECT() *MockRewardStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateRewardEntry mocks base method.
+func (m *MockRewardStore) CreateRewardEntry(arg0 context.Context, arg1 db.CreateRewardEntryParams) (db.RewardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRewardEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.RewardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRewardEntry indicates an expected call of CreateRewardEntry.
+func (mr *MockRewardStoreMockRecorder) CreateRewardEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardEntry", reflect.TypeOf((*MockRewardStore)(nil).CreateRewardEntry), arg0, arg1)
+}
+
+// GetRewardBalance mocks base method.
+func (m *MockRewardStore) GetRewardBalance(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardBalance", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardBalance indicates an expected call of GetRewardBalance.
+func (mr *MockRewardStoreMockRecorder) GetRewardBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardBalance", reflect.TypeOf((*MockRewardStore)(nil).GetRewardBalance), arg0, arg1)
+}
+
+// GetRewardsAccount mocks base method.
+func (m *MockRewardStore) GetRewardsAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardsAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardsAccount indicates an expected call of GetRewardsAccount.
+func (mr *MockRewardStoreMockRecorder) GetRewardsAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardsAccount", reflect.TypeOf((*MockRewardStore)(nil).GetRewardsAccount), arg0, arg1)
+}
+
+// ListRewardEntries mocks base method.
+func (m *MockRewardStore) ListRewardEntries(arg0 context.Context, arg1 db.ListRewardEntriesParams) ([]db.RewardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRewardEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.RewardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRewardEntries indicates an expected call of ListRewardEntries.
+func (mr *MockRewardStoreMockRecorder) ListRewardEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRewardEntries", reflect.TypeOf((*MockRewardStore)(nil).ListRewardEntries), arg0, arg1)
+}
+
+// RedeemRewardsTx mocks base method.
+func (m *MockRewardStore) RedeemRewardsTx(arg0 context.Context, arg1 db.RedeemRewardsTxParams) (db.RedeemRewardsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemRewardsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RedeemRewardsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemRewardsTx indicates an expected call of RedeemRewardsTx.
+func (mr *MockRewardStoreMockRecorder) RedeemRewardsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemRewardsTx", reflect.TypeOf((*MockRewardStore)(nil).RedeemRewardsTx), arg0, arg1)
+}
+
+// MockReferralStore is a mock of ReferralStore interface.
+type MockReferralStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralStoreMockRecorder
+}
+
+// MockReferralStoreMockRecorder is the mock recorder for MockReferralStore.
+type MockReferralStoreMockRecorder struct {
+	mock *MockReferralStore
+}
+
+// NewMockReferralStore creates a new mock instance.
+func NewMockReferralStore(ctrl *gomock.Controller) *MockReferralStore {
+	mock := &MockReferralStore{ctrl: ctrl}
+	mock.recorder = &MockReferralStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralStore) EXPECT() *MockReferralStoreMockRecorder {
+	return m.recorder
+}
+
+// CloseReferral mocks base method.
+func (m *MockReferralStore) CloseReferral(arg0 context.Context, arg1 db.CloseReferralParams) (db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseReferral", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseReferral indicates an expected call of CloseReferral.
+func (mr *MockReferralStoreMockRecorder) CloseReferral(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseReferral", reflect.TypeOf((*MockReferralStore)(nil).CloseReferral), arg0, arg1)
+}
+
+// CountRewardedReferrals mocks base method.
+func (m *MockReferralStore) CountRewardedReferrals(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRewardedReferrals", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRewardedReferrals indicates an expected call of CountRewardedReferrals.
+func (mr *MockReferralStoreMockRecorder) CountRewardedReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRewardedReferrals", reflect.TypeOf((*MockReferralStore)(nil).CountRewardedReferrals), arg0, arg1)
+}
+
+// CreateReferral mocks base method.
+func (m *MockReferralStore) CreateReferral(arg0 context.Context, arg1 db.CreateReferralParams) (db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReferral", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReferral indicates an expected call of CreateReferral.
+func (mr *MockReferralStoreMockRecorder) CreateReferral(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReferral", reflect.TypeOf((*MockReferralStore)(nil).CreateReferral), arg0, arg1)
+}
+
+// CreateReferralCode mocks base method.
+func (m *MockReferralStore) CreateReferralCode(arg0 context.Context, arg1 db.CreateReferralCodeParams) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReferralCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReferralCode indicates an expected call of CreateReferralCode.
+func (mr *MockReferralStoreMockRecorder) CreateReferralCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReferralCode", reflect.TypeOf((*MockReferralStore)(nil).CreateReferralCode), arg0, arg1)
+}
+
+// GetFirstQualifyingTransfer mocks base method.
+func (m *MockReferralStore) GetFirstQualifyingTransfer(arg0 context.Context, arg1 db.GetFirstQualifyingTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstQualifyingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstQualifyingTransfer indicates an expected call of GetFirstQualifyingTransfer.
+func (mr *MockReferralStoreMockRecorder) GetFirstQualifyingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstQualifyingTransfer", reflect.TypeOf((*MockReferralStore)(nil).GetFirstQualifyingTransfer), arg0, arg1)
+}
+
+// GetReferralCode mocks base method.
+func (m *MockReferralStore) GetReferralCode(arg0 context.Context, arg1 string) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralCode indicates an expected call of GetReferralCode.
+func (mr *MockReferralStoreMockRecorder) GetReferralCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCode", reflect.TypeOf((*MockReferralStore)(nil).GetReferralCode), arg0, arg1)
+}
+
+// GetReferralCodeByCode mocks base method.
+func (m *MockReferralStore) GetReferralCodeByCode(arg0 context.Context, arg1 string) (db.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralCodeByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralCodeByCode indicates an expected call of GetReferralCodeByCode.
+func (mr *MockReferralStoreMockRecorder) GetReferralCodeByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralCodeByCode", reflect.TypeOf((*MockReferralStore)(nil).GetReferralCodeByCode), arg0, arg1)
+}
+
+// GetReferralForUpdate mocks base method.
+func (m *MockReferralStore) GetReferralForUpdate(arg0 context.Context, arg1 int64) (db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferralForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferralForUpdate indicates an expected call of GetReferralForUpdate.
+func (mr *MockReferralStoreMockRecorder) GetReferralForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferralForUpdate", reflect.TypeOf((*MockReferralStore)(nil).GetReferralForUpdate), arg0, arg1)
+}
+
+// ListPendingReferrals mocks base method.
+func (m *MockReferralStore) ListPendingReferrals(arg0 context.Context, arg1 db.ListPendingReferralsParams) ([]db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingReferrals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingReferrals indicates an expected call of ListPendingReferrals.
+func (mr *MockReferralStoreMockRecorder) ListPendingReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReferrals", reflect.TypeOf((*MockReferralStore)(nil).ListPendingReferrals), arg0, arg1)
+}
+
+// ListReferrals mocks base method.
+func (m *MockReferralStore) ListReferrals(arg0 context.Context, arg1 db.ListReferralsParams) ([]db.Referral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReferrals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Referral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReferrals indicates an expected call of ListReferrals.
+func (mr *MockReferralStoreMockRecorder) ListReferrals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReferrals", reflect.TypeOf((*MockReferralStore)(nil).ListReferrals), arg0, arg1)
+}
+
+// RewardReferralTx mocks base method.
+func (m *MockReferralStore) RewardReferralTx(arg0 context.Context, arg1 db.RewardReferralTxParams) (db.RewardReferralTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewardReferralTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RewardReferralTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewardReferralTx indicates an expected call of RewardReferralTx.
+func (mr *MockReferralStoreMockRecorder) RewardReferralTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewardReferralTx", reflect.TypeOf((*MockReferralStore)(nil).RewardReferralTx), arg0, arg1)
+}
+
+// MockTenantStore is a mock of TenantStore interface.
+type MockTenantStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantStoreMockRecorder
+}
+
+// MockTenantStoreMockRecorder is the mock recorder for MockTenantStore.
+type MockTenantStoreMockRecorder struct {
+	mock *MockTenantStore
+}
+
+// NewMockTenantStore creates a new mock instance.
+func NewMockTenantStore(ctrl *gomock.Controller) *MockTenantStore {
+	mock := &MockTenantStore{ctrl: ctrl}
+	mock.recorder = &MockTenantStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantStore) EXPECT() *MockTenantStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateTenant mocks base method.
+func (m *MockTenantStore) CreateTenant(arg0 context.Context, arg1 db.CreateTenantParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockTenantStoreMockRecorder) CreateTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockTenantStore)(nil).CreateTenant), arg0, arg1)
+}
+
+// CreateTenantTx mocks base method.
+func (m *MockTenantStore) CreateTenantTx(arg0 context.Context, arg1 db.CreateTenantTxParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenantTx indicates an expected call of CreateTenantTx.
+func (mr *MockTenantStoreMockRecorder) CreateTenantTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenantTx", reflect.TypeOf((*MockTenantStore)(nil).CreateTenantTx), arg0, arg1)
+}
+
+// GetTenant mocks base method.
+func (m *MockTenantStore) GetTenant(arg0 context.Context, arg1 string) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockTenantStoreMockRecorder) GetTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockTenantStore)(nil).GetTenant), arg0, arg1)
+}
+
+// ListTenants mocks base method.
+func (m *MockTenantStore) ListTenants(arg0 context.Context) ([]db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTenants", arg0)
+	ret0, _ := ret[0].([]db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTenants indicates an expected call of ListTenants.
+func (mr *MockTenantStoreMockRecorder) ListTenants(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTenants", reflect.TypeOf((*MockTenantStore)(nil).ListTenants), arg0)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockTenantStore) UpdateTenant(arg0 context.Context, arg1 db.UpdateTenantParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockTenantStoreMockRecorder) UpdateTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockTenantStore)(nil).UpdateTenant), arg0, arg1)
+}
+
+// UpdateTenantTx mocks base method.
+func (m *MockTenantStore) UpdateTenantTx(arg0 context.Context, arg1 db.UpdateTenantTxParams) (db.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenantTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenantTx indicates an expected call of UpdateTenantTx.
+func (mr *MockTenantStoreMockRecorder) UpdateTenantTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenantTx", reflect.TypeOf((*MockTenantStore)(nil).UpdateTenantTx), arg0, arg1)
+}
+
+// MockCurrencyStore is a mock of CurrencyStore interface.
+type MockCurrencyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyStoreMockRecorder
+}
+
+// MockCurrencyStoreMockRecorder is the mock recorder for MockCurrencyStore.
+type MockCurrencyStoreMockRecorder struct {
+	mock *MockCurrencyStore
+}
+
+// NewMockCurrencyStore creates a new mock instance.
+func NewMockCurrencyStore(ctrl *gomock.Controller) *MockCurrencyStore {
+	mock := &MockCurrencyStore{ctrl: ctrl}
+	mock.recorder = &MockCurrencyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyStore) EXPECT() *MockCurrencyStoreMockRecorder {
+	return m.recorder
+}
+
+// GetCurrency mocks base method.
+func (m *MockCurrencyStore) GetCurrency(arg0 context.Context, arg1 string) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrency indicates an expected call of GetCurrency.
+func (mr *MockCurrencyStoreMockRecorder) GetCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrency", reflect.TypeOf((*MockCurrencyStore)(nil).GetCurrency), arg0, arg1)
+}
+
+// ListCurrencies mocks base method.
+func (m *MockCurrencyStore) ListCurrencies(arg0 context.Context) ([]db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencies", arg0)
+	ret0, _ := ret[0].([]db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencies indicates an expected call of ListCurrencies.
+func (mr *MockCurrencyStoreMockRecorder) ListCurrencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencies", reflect.TypeOf((*MockCurrencyStore)(nil).ListCurrencies), arg0)
+}
+
+// SetCurrencyEnabled mocks base method.
+func (m *MockCurrencyStore) SetCurrencyEnabled(arg0 context.Context, arg1 db.SetCurrencyEnabledParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrencyEnabled", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCurrencyEnabled indicates an expected call of SetCurrencyEnabled.
+func (mr *MockCurrencyStoreMockRecorder) SetCurrencyEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrencyEnabled", reflect.TypeOf((*MockCurrencyStore)(nil).SetCurrencyEnabled), arg0, arg1)
+}
+
+// SetCurrencyEnabledTx mocks base method.
+func (m *MockCurrencyStore) SetCurrencyEnabledTx(arg0 context.Context, arg1 db.SetCurrencyEnabledTxParams) (db.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCurrencyEnabledTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCurrencyEnabledTx indicates an expected call of SetCurrencyEnabledTx.
+func (mr *MockCurrencyStoreMockRecorder) SetCurrencyEnabledTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCurrencyEnabledTx", reflect.TypeOf((*MockCurrencyStore)(nil).SetCurrencyEnabledTx), arg0, arg1)
+}
+
+// MockExchangeRateStore is a mock of ExchangeRateStore interface.
+type MockExchangeRateStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateStoreMockRecorder
+}
+
+// MockExchangeRateStoreMockRecorder is the mock recorder for MockExchangeRateStore.
+type MockExchangeRateStoreMockRecorder struct {
+	mock *MockExchangeRateStore
+}
+
+// NewMockExchangeRateStore creates a new mock instance.
+func NewMockExchangeRateStore(ctrl *gomock.Controller) *MockExchangeRateStore {
+	mock := &MockExchangeRateStore{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateStore) EXPECT() *MockExchangeRateStoreMockRecorder {
+	return m.recorder
+}
+
+// GetFXAccount mocks base method.
+func (m *MockExchangeRateStore) GetFXAccount(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFXAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFXAccount indicates an expected call of GetFXAccount.
+func (mr *MockExchangeRateStoreMockRecorder) GetFXAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFXAccount", reflect.TypeOf((*MockExchangeRateStore)(nil).GetFXAccount), arg0, arg1)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockExchangeRateStore) ListExchangeRates(arg0 context.Context) ([]db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", arg0)
+	ret0, _ := ret[0].([]db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockExchangeRateStoreMockRecorder) ListExchangeRates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockExchangeRateStore)(nil).ListExchangeRates), arg0)
+}
+
+// SaveExchangeRatesTx mocks base method.
+func (m *MockExchangeRateStore) SaveExchangeRatesTx(arg0 context.Context, arg1 []db.UpsertExchangeRateParams) ([]db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExchangeRatesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveExchangeRatesTx indicates an expected call of SaveExchangeRatesTx.
+func (mr *MockExchangeRateStoreMockRecorder) SaveExchangeRatesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExchangeRatesTx", reflect.TypeOf((*MockExchangeRateStore)(nil).SaveExchangeRatesTx), arg0, arg1)
+}
+
+// UpsertExchangeRate mocks base method.
+func (m *MockExchangeRateStore) UpsertExchangeRate(arg0 context.Context, arg1 db.UpsertExchangeRateParams) (db.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertExchangeRate", arg0, arg1)
+	ret0, _ := ret[0].(db.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertExchangeRate indicates an expected call of UpsertExchangeRate.
+func (mr *MockExchangeRateStoreMockRecorder) UpsertExchangeRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertExchangeRate", reflect.TypeOf((*MockExchangeRateStore)(nil).UpsertExchangeRate), arg0, arg1)
+}
+
+// MockUserImportStore is a mock of UserImportStore interface.
+type MockUserImportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserImportStoreMockRecorder
+}
+
+// MockUserImportStoreMockRecorder is the mock recorder for MockUserImportStore.
+type MockUserImportStoreMockRecorder struct {
+	mock *MockUserImportStore
+}
+
+// NewMockUserImportStore creates a new mock instance.
+func NewMockUserImportStore(ctrl *gomock.Controller) *MockUserImportStore {
+	mock := &MockUserImportStore{ctrl: ctrl}
+	mock.recorder = &MockUserImportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserImportStore) EXPECT() *MockUserImportStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateUserImport mocks base method.
+func (m *MockUserImportStore) CreateUserImport(arg0 context.Context, arg1 db.CreateUserImportParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImport indicates an expected call of CreateUserImport.
+func (mr *MockUserImportStoreMockRecorder) CreateUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImport", reflect.TypeOf((*MockUserImportStore)(nil).CreateUserImport), arg0, arg1)
+}
+
+// CreateUserImportFile mocks base method.
+func (m *MockUserImportStore) CreateUserImportFile(arg0 context.Context, arg1 db.CreateUserImportFileParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportFile", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserImportFile indicates an expected call of CreateUserImportFile.
+func (mr *MockUserImportStoreMockRecorder) CreateUserImportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportFile", reflect.TypeOf((*MockUserImportStore)(nil).CreateUserImportFile), arg0, arg1)
+}
+
+// CreateUserImportRow mocks base method.
+func (m *MockUserImportStore) CreateUserImportRow(arg0 context.Context, arg1 db.CreateUserImportRowParams) (db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportRow", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImportRow indicates an expected call of CreateUserImportRow.
+func (mr *MockUserImportStoreMockRecorder) CreateUserImportRow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportRow", reflect.TypeOf((*MockUserImportStore)(nil).CreateUserImportRow), arg0, arg1)
+}
+
+// CreateUserImportTx mocks base method.
+func (m *MockUserImportStore) CreateUserImportTx(arg0 context.Context, arg1 db.CreateUserImportTxParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserImportTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserImportTx indicates an expected call of CreateUserImportTx.
+func (mr *MockUserImportStoreMockRecorder) CreateUserImportTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserImportTx", reflect.TypeOf((*MockUserImportStore)(nil).CreateUserImportTx), arg0, arg1)
+}
+
+// FailUserImport mocks base method.
+func (m *MockUserImportStore) FailUserImport(arg0 context.Context, arg1 db.FailUserImportParams) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailUserImport indicates an expected call of FailUserImport.
+func (mr *MockUserImportStoreMockRecorder) FailUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUserImport", reflect.TypeOf((*MockUserImportStore)(nil).FailUserImport), arg0, arg1)
+}
+
+// FinishUserImport mocks base method.
+func (m *MockUserImportStore) FinishUserImport(arg0 context.Context, arg1 int64) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishUserImport indicates an expected call of FinishUserImport.
+func (mr *MockUserImportStoreMockRecorder) FinishUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishUserImport", reflect.TypeOf((*MockUserImportStore)(nil).FinishUserImport), arg0, arg1)
+}
+
+// GetLastUserImportLine mocks base method.
+func (m *MockUserImportStore) GetLastUserImportLine(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastUserImportLine", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastUserImportLine indicates an expected call of GetLastUserImportLine.
+func (mr *MockUserImportStoreMockRecorder) GetLastUserImportLine(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastUserImportLine", reflect.TypeOf((*MockUserImportStore)(nil).GetLastUserImportLine), arg0, arg1)
+}
+
+// GetUserImport mocks base method.
+func (m *MockUserImportStore) GetUserImport(arg0 context.Context, arg1 int64) (db.UserImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImport", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImport indicates an expected call of GetUserImport.
+func (mr *MockUserImportStoreMockRecorder) GetUserImport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImport", reflect.TypeOf((*MockUserImportStore)(nil).GetUserImport), arg0, arg1)
+}
+
+// GetUserImportFile mocks base method.
+func (m *MockUserImportStore) GetUserImportFile(arg0 context.Context, arg1 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserImportFile", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserImportFile indicates an expected call of GetUserImportFile.
+func (mr *MockUserImportStoreMockRecorder) GetUserImportFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserImportFile", reflect.TypeOf((*MockUserImportStore)(nil).GetUserImportFile), arg0, arg1)
+}
+
+// ImportUserTx mocks base method.
+func (m *MockUserImportStore) ImportUserTx(arg0 context.Context, arg1 db.ImportUserTxParams) (db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportUserTx indicates an expected call of ImportUserTx.
+func (mr *MockUserImportStoreMockRecorder) ImportUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportUserTx", reflect.TypeOf((*MockUserImportStore)(nil).ImportUserTx), arg0, arg1)
+}
+
+// ListUserImportRowsAfter mocks base method.
+func (m *MockUserImportStore) ListUserImportRowsAfter(arg0 context.Context, arg1 db.ListUserImportRowsAfterParams) ([]db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserImportRowsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserImportRowsAfter indicates an expected call of ListUserImportRowsAfter.
+func (mr *MockUserImportStoreMockRecorder) ListUserImportRowsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserImportRowsAfter", reflect.TypeOf((*MockUserImportStore)(nil).ListUserImportRowsAfter), arg0, arg1)
+}
+
+// ListUserImportRowsBefore mocks base method.
+func (m *MockUserImportStore) ListUserImportRowsBefore(arg0 context.Context, arg1 db.ListUserImportRowsBeforeParams) ([]db.UserImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserImportRowsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserImportRowsBefore indicates an expected call of ListUserImportRowsBefore.
+func (mr *MockUserImportStoreMockRecorder) ListUserImportRowsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserImportRowsBefore", reflect.TypeOf((*MockUserImportStore)(nil).ListUserImportRowsBefore), arg0, arg1)
+}
+
+// MockConsentStore is a mock of ConsentStore interface.
+type MockConsentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsentStoreMockRecorder
+}
+
+// MockConsentStoreMockRecorder is the mock recorder for MockConsentStore.
+type MockConsentStoreMockRecorder struct {
+	mock *MockConsentStore
+}
+
+// NewMockConsentStore creates a new mock instance.
+func NewMockConsentStore(ctrl *gomock.Controller) *MockConsentStore {
+	mock := &MockConsentStore{ctrl: ctrl}
+	mock.recorder = &MockConsentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsentStore) EXPECT() *MockConsentStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateLegalDocument mocks base method.
+func (m *MockConsentStore) CreateLegalDocument(arg0 context.Context, arg1 db.CreateLegalDocumentParams) (db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLegalDocument", arg0, arg1)
+	ret0, _ := ret[0].(db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLegalDocument indicates an expected call of CreateLegalDocument.
+func (mr *MockConsentStoreMockRecorder) CreateLegalDocument(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLegalDocument", reflect.TypeOf((*MockConsentStore)(nil).CreateLegalDocument), arg0, arg1)
+}
+
+// CreateLegalDocumentTx mocks base method.
+func (m *MockConsentStore) CreateLegalDocumentTx(arg0 context.Context, arg1 db.CreateLegalDocumentTxParams) (db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLegalDocumentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLegalDocumentTx indicates an expected call of CreateLegalDocumentTx.
+func (mr *MockConsentStoreMockRecorder) CreateLegalDocumentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLegalDocumentTx", reflect.TypeOf((*MockConsentStore)(nil).CreateLegalDocumentTx), arg0, arg1)
+}
+
+// CreateUserConsent mocks base method.
+func (m *MockConsentStore) CreateUserConsent(arg0 context.Context, arg1 db.CreateUserConsentParams) (db.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserConsent", arg0, arg1)
+	ret0, _ := ret[0].(db.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserConsent indicates an expected call of CreateUserConsent.
+func (mr *MockConsentStoreMockRecorder) CreateUserConsent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserConsent", reflect.TypeOf((*MockConsentStore)(nil).CreateUserConsent), arg0, arg1)
+}
+
+// ListLatestLegalDocuments mocks base method.
+func (m *MockConsentStore) ListLatestLegalDocuments(arg0 context.Context) ([]db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestLegalDocuments", arg0)
+	ret0, _ := ret[0].([]db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatestLegalDocuments indicates an expected call of ListLatestLegalDocuments.
+func (mr *MockConsentStoreMockRecorder) ListLatestLegalDocuments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestLegalDocuments", reflect.TypeOf((*MockConsentStore)(nil).ListLatestLegalDocuments), arg0)
+}
+
+// ListPendingLegalDocuments mocks base method.
+func (m *MockConsentStore) ListPendingLegalDocuments(arg0 context.Context, arg1 string) ([]db.LegalDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingLegalDocuments", arg0, arg1)
+	ret0, _ := ret[0].([]db.LegalDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingLegalDocuments indicates an expected call of ListPendingLegalDocuments.
+func (mr *MockConsentStoreMockRecorder) ListPendingLegalDocuments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingLegalDocuments", reflect.TypeOf((*MockConsentStore)(nil).ListPendingLegalDocuments), arg0, arg1)
+}
+
+// ListUserConsents mocks base method.
+func (m *MockConsentStore) ListUserConsents(arg0 context.Context, arg1 string) ([]db.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserConsents", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserConsents indicates an expected call of ListUserConsents.
+func (mr *MockConsentStoreMockRecorder) ListUserConsents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserConsents", reflect.TypeOf((*MockConsentStore)(nil).ListUserConsents), arg0, arg1)
+}
+
+// MockDeviceStore is a mock of DeviceStore interface.
+type MockDeviceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceStoreMockRecorder
+}
+
+// MockDeviceStoreMockRecorder is the mock recorder for MockDeviceStore.
+type MockDeviceStoreMockRecorder struct {
+	mock *MockDeviceStore
+}
+
+// NewMockDeviceStore creates a new mock instance.
+func NewMockDeviceStore(ctrl *gomock.Controller) *MockDeviceStore {
+	mock := &MockDeviceStore{ctrl: ctrl}
+	mock.recorder = &MockDeviceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceStore) EXPECT() *MockDeviceStoreMockRecorder {
+	return m.recorder
+}
+
+// AddDeviceVerificationAttempt mocks base method.
+func (m *MockDeviceStore) AddDeviceVerificationAttempt(arg0 context.Context, arg1 int64) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeviceVerificationAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDeviceVerificationAttempt indicates an expected call of AddDeviceVerificationAttempt.
+func (mr *MockDeviceStoreMockRecorder) AddDeviceVerificationAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeviceVerificationAttempt", reflect.TypeOf((*MockDeviceStore)(nil).AddDeviceVerificationAttempt), arg0, arg1)
+}
+
+// BlockDeviceSessions mocks base method.
+func (m *MockDeviceStore) BlockDeviceSessions(arg0 context.Context, arg1 pgtype.Int8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockDeviceSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockDeviceSessions indicates an expected call of BlockDeviceSessions.
+func (mr *MockDeviceStoreMockRecorder) BlockDeviceSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockDeviceSessions", reflect.TypeOf((*MockDeviceStore)(nil).BlockDeviceSessions), arg0, arg1)
+}
+
+// GetDevice mocks base method.
+func (m *MockDeviceStore) GetDevice(arg0 context.Context, arg1 int64) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevice indicates an expected call of GetDevice.
+func (mr *MockDeviceStoreMockRecorder) GetDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevice", reflect.TypeOf((*MockDeviceStore)(nil).GetDevice), arg0, arg1)
+}
+
+// ListDevices mocks base method.
+func (m *MockDeviceStore) ListDevices(arg0 context.Context, arg1 string) ([]db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDevices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDevices indicates an expected call of ListDevices.
+func (mr *MockDeviceStoreMockRecorder) ListDevices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDevices", reflect.TypeOf((*MockDeviceStore)(nil).ListDevices), arg0, arg1)
+}
+
+// RenameDevice mocks base method.
+func (m *MockDeviceStore) RenameDevice(arg0 context.Context, arg1 db.RenameDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameDevice indicates an expected call of RenameDevice.
+func (mr *MockDeviceStoreMockRecorder) RenameDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameDevice", reflect.TypeOf((*MockDeviceStore)(nil).RenameDevice), arg0, arg1)
+}
+
+// RevokeDevice mocks base method.
+func (m *MockDeviceStore) RevokeDevice(arg0 context.Context, arg1 db.RevokeDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDevice indicates an expected call of RevokeDevice.
+func (mr *MockDeviceStoreMockRecorder) RevokeDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDevice", reflect.TypeOf((*MockDeviceStore)(nil).RevokeDevice), arg0, arg1)
+}
+
+// RevokeDeviceTx mocks base method.
+func (m *MockDeviceStore) RevokeDeviceTx(arg0 context.Context, arg1 db.RevokeDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDeviceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDeviceTx indicates an expected call of RevokeDeviceTx.
+func (mr *MockDeviceStoreMockRecorder) RevokeDeviceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDeviceTx", reflect.TypeOf((*MockDeviceStore)(nil).RevokeDeviceTx), arg0, arg1)
+}
+
+// SendDeviceVerificationTx mocks base method.
+func (m *MockDeviceStore) SendDeviceVerificationTx(arg0 context.Context, arg1 db.SendDeviceVerificationTxParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDeviceVerificationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendDeviceVerificationTx indicates an expected call of SendDeviceVerificationTx.
+func (mr *MockDeviceStoreMockRecorder) SendDeviceVerificationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDeviceVerificationTx", reflect.TypeOf((*MockDeviceStore)(nil).SendDeviceVerificationTx), arg0, arg1)
+}
+
+// SetDeviceVerification mocks base method.
+func (m *MockDeviceStore) SetDeviceVerification(arg0 context.Context, arg1 db.SetDeviceVerificationParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeviceVerification", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDeviceVerification indicates an expected call of SetDeviceVerification.
+func (mr *MockDeviceStoreMockRecorder) SetDeviceVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeviceVerification", reflect.TypeOf((*MockDeviceStore)(nil).SetDeviceVerification), arg0, arg1)
+}
+
+// TrustDevice mocks base method.
+func (m *MockDeviceStore) TrustDevice(arg0 context.Context, arg1 int64) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrustDevice indicates an expected call of TrustDevice.
+func (mr *MockDeviceStoreMockRecorder) TrustDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustDevice", reflect.TypeOf((*MockDeviceStore)(nil).TrustDevice), arg0, arg1)
+}
+
+// UpsertDevice mocks base method.
+func (m *MockDeviceStore) UpsertDevice(arg0 context.Context, arg1 db.UpsertDeviceParams) (db.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDevice", arg0, arg1)
+	ret0, _ := ret[0].(db.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertDevice indicates an expected call of UpsertDevice.
+func (mr *MockDeviceStoreMockRecorder) UpsertDevice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDevice", reflect.TypeOf((*MockDeviceStore)(nil).UpsertDevice), arg0, arg1)
+}
+
+// MockLoginAlertStore is a mock of LoginAlertStore interface.
+type MockLoginAlertStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAlertStoreMockRecorder
+}
+
+// MockLoginAlertStoreMockRecorder is the mock recorder for MockLoginAlertStore.
+type MockLoginAlertStoreMockRecorder struct {
+	mock *MockLoginAlertStore
+}
+
+// NewMockLoginAlertStore creates a new mock instance.
+func NewMockLoginAlertStore(ctrl *gomock.Controller) *MockLoginAlertStore {
+	mock := &MockLoginAlertStore{ctrl: ctrl}
+	mock.recorder = &MockLoginAlertStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAlertStore) EXPECT() *MockLoginAlertStoreMockRecorder {
+	return m.recorder
+}
+
+// AddLoginAlertResetAttempt mocks base method.
+func (m *MockLoginAlertStore) AddLoginAlertResetAttempt(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLoginAlertResetAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLoginAlertResetAttempt indicates an expected call of AddLoginAlertResetAttempt.
+func (mr *MockLoginAlertStoreMockRecorder) AddLoginAlertResetAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoginAlertResetAttempt", reflect.TypeOf((*MockLoginAlertStore)(nil).AddLoginAlertResetAttempt), arg0, arg1)
+}
+
+// CreateLoginAlert mocks base method.
+func (m *MockLoginAlertStore) CreateLoginAlert(arg0 context.Context, arg1 db.CreateLoginAlertParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoginAlert indicates an expected call of CreateLoginAlert.
+func (mr *MockLoginAlertStoreMockRecorder) CreateLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginAlert", reflect.TypeOf((*MockLoginAlertStore)(nil).CreateLoginAlert), arg0, arg1)
+}
+
+// FinishLoginAlertReset mocks base method.
+func (m *MockLoginAlertStore) FinishLoginAlertReset(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLoginAlertReset", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLoginAlertReset indicates an expected call of FinishLoginAlertReset.
+func (mr *MockLoginAlertStoreMockRecorder) FinishLoginAlertReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLoginAlertReset", reflect.TypeOf((*MockLoginAlertStore)(nil).FinishLoginAlertReset), arg0, arg1)
+}
+
+// GetLoginAlert mocks base method.
+func (m *MockLoginAlertStore) GetLoginAlert(arg0 context.Context, arg1 int64) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAlert indicates an expected call of GetLoginAlert.
+func (mr *MockLoginAlertStoreMockRecorder) GetLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAlert", reflect.TypeOf((*MockLoginAlertStore)(nil).GetLoginAlert), arg0, arg1)
+}
+
+// HasPendingPasswordReset mocks base method.
+func (m *MockLoginAlertStore) HasPendingPasswordReset(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPendingPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPendingPasswordReset indicates an expected call of HasPendingPasswordReset.
+func (mr *MockLoginAlertStoreMockRecorder) HasPendingPasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPendingPasswordReset", reflect.TypeOf((*MockLoginAlertStore)(nil).HasPendingPasswordReset), arg0, arg1)
+}
+
+// ReportLoginAlert mocks base method.
+func (m *MockLoginAlertStore) ReportLoginAlert(arg0 context.Context, arg1 db.ReportLoginAlertParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLoginAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportLoginAlert indicates an expected call of ReportLoginAlert.
+func (mr *MockLoginAlertStoreMockRecorder) ReportLoginAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLoginAlert", reflect.TypeOf((*MockLoginAlertStore)(nil).ReportLoginAlert), arg0, arg1)
+}
+
+// ReportLoginAlertTx mocks base method.
+func (m *MockLoginAlertStore) ReportLoginAlertTx(arg0 context.Context, arg1 db.ReportLoginAlertTxParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLoginAlertTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportLoginAlertTx indicates an expected call of ReportLoginAlertTx.
+func (mr *MockLoginAlertStoreMockRecorder) ReportLoginAlertTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLoginAlertTx", reflect.TypeOf((*MockLoginAlertStore)(nil).ReportLoginAlertTx), arg0, arg1)
+}
+
+// ResetPasswordTx mocks base method.
+func (m *MockLoginAlertStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockLoginAlertStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockLoginAlertStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// SendPasswordResetCodeTx mocks base method.
+func (m *MockLoginAlertStore) SendPasswordResetCodeTx(arg0 context.Context, arg1 db.SendPasswordResetCodeTxParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordResetCodeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendPasswordResetCodeTx indicates an expected call of SendPasswordResetCodeTx.
+func (mr *MockLoginAlertStoreMockRecorder) SendPasswordResetCodeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordResetCodeTx", reflect.TypeOf((*MockLoginAlertStore)(nil).SendPasswordResetCodeTx), arg0, arg1)
+}
+
+// SetLoginAlertResetCode mocks base method.
+func (m *MockLoginAlertStore) SetLoginAlertResetCode(arg0 context.Context, arg1 db.SetLoginAlertResetCodeParams) (db.LoginAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLoginAlertResetCode", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLoginAlertResetCode indicates an expected call of SetLoginAlertResetCode.
+func (mr *MockLoginAlertStoreMockRecorder) SetLoginAlertResetCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLoginAlertResetCode", reflect.TypeOf((*MockLoginAlertStore)(nil).SetLoginAlertResetCode), arg0, arg1)
+}
+
+// MockStepUpStore is a mock of StepUpStore interface.
+type MockStepUpStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStepUpStoreMockRecorder
+}
+
+// MockStepUpStoreMockRecorder is the mock recorder for MockStepUpStore.
+type MockStepUpStoreMockRecorder struct {
+	mock *MockStepUpStore
+}
+
+// NewMockStepUpStore creates a new mock instance.
+func NewMockStepUpStore(ctrl *gomock.Controller) *MockStepUpStore {
+	mock := &MockStepUpStore{ctrl: ctrl}
+	mock.recorder = &MockStepUpStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStepUpStore) EXPECT() *MockStepUpStoreMockRecorder {
+	return m.recorder
+}
+
+// AddStepUpChallengeAttempt mocks base method.
+func (m *MockStepUpStore) AddStepUpChallengeAttempt(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStepUpChallengeAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStepUpChallengeAttempt indicates an expected call of AddStepUpChallengeAttempt.
+func (mr *MockStepUpStoreMockRecorder) AddStepUpChallengeAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStepUpChallengeAttempt", reflect.TypeOf((*MockStepUpStore)(nil).AddStepUpChallengeAttempt), arg0, arg1)
+}
+
+// CompleteStepUpChallenge mocks base method.
+func (m *MockStepUpStore) CompleteStepUpChallenge(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteStepUpChallenge indicates an expected call of CompleteStepUpChallenge.
+func (mr *MockStepUpStoreMockRecorder) CompleteStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteStepUpChallenge", reflect.TypeOf((*MockStepUpStore)(nil).CompleteStepUpChallenge), arg0, arg1)
+}
+
+// CreateStepUpChallenge mocks base method.
+func (m *MockStepUpStore) CreateStepUpChallenge(arg0 context.Context, arg1 db.CreateStepUpChallengeParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStepUpChallenge indicates an expected call of CreateStepUpChallenge.
+func (mr *MockStepUpStoreMockRecorder) CreateStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepUpChallenge", reflect.TypeOf((*MockStepUpStore)(nil).CreateStepUpChallenge), arg0, arg1)
+}
+
+// CreateStepUpChallengeTx mocks base method.
+func (m *MockStepUpStore) CreateStepUpChallengeTx(arg0 context.Context, arg1 db.CreateStepUpChallengeTxParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepUpChallengeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStepUpChallengeTx indicates an expected call of CreateStepUpChallengeTx.
+func (mr *MockStepUpStoreMockRecorder) CreateStepUpChallengeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepUpChallengeTx", reflect.TypeOf((*MockStepUpStore)(nil).CreateStepUpChallengeTx), arg0, arg1)
+}
+
+// GetStepUpChallenge mocks base method.
+func (m *MockStepUpStore) GetStepUpChallenge(arg0 context.Context, arg1 int64) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStepUpChallenge indicates an expected call of GetStepUpChallenge.
+func (mr *MockStepUpStoreMockRecorder) GetStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStepUpChallenge", reflect.TypeOf((*MockStepUpStore)(nil).GetStepUpChallenge), arg0, arg1)
+}
+
+// MockMaintenanceStore is a mock of MaintenanceStore interface.
+type MockMaintenanceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceStoreMockRecorder
+}
+
+// MockMaintenanceStoreMockRecorder is the mock recorder for MockMaintenanceStore.
+type MockMaintenanceStoreMockRecorder struct {
+	mock *MockMaintenanceStore
+}
+
+// NewMockMaintenanceStore creates a new mock instance.
+func NewMockMaintenanceStore(ctrl *gomock.Controller) *MockMaintenanceStore {
+	mock := &MockMaintenanceStore{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenanceStore) EXPECT() *MockMaintenanceStoreMockRecorder {
+	return m.recorder
+}
+
+// GetMaintenanceMode mocks base method.
+func (m *MockMaintenanceStore) GetMaintenanceMode(arg0 context.Context) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceMode", arg0)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceMode indicates an expected call of GetMaintenanceMode.
+func (mr *MockMaintenanceStoreMockRecorder) GetMaintenanceMode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceMode", reflect.TypeOf((*MockMaintenanceStore)(nil).GetMaintenanceMode), arg0)
+}
+
+// SetMaintenanceReadOnly mocks base method.
+func (m *MockMaintenanceStore) SetMaintenanceReadOnly(arg0 context.Context, arg1 bool) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenanceReadOnly", arg0, arg1)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMaintenanceReadOnly indicates an expected call of SetMaintenanceReadOnly.
+func (mr *MockMaintenanceStoreMockRecorder) SetMaintenanceReadOnly(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceReadOnly", reflect.TypeOf((*MockMaintenanceStore)(nil).SetMaintenanceReadOnly), arg0, arg1)
+}
+
+// SetMaintenanceTransfersBlocked mocks base method.
+func (m *MockMaintenanceStore) SetMaintenanceTransfersBlocked(arg0 context.Context, arg1 bool) (db.MaintenanceMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMaintenanceTransfersBlocked", arg0, arg1)
+	ret0, _ := ret[0].(db.MaintenanceMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMaintenanceTransfersBlocked indicates an expected call of SetMaintenanceTransfersBlocked.
+func (mr *MockMaintenanceStoreMockRecorder) SetMaintenanceTransfersBlocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenanceTransfersBlocked", reflect.TypeOf((*MockMaintenanceStore)(nil).SetMaintenanceTransfersBlocked), arg0, arg1)
 }
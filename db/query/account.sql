@@ -1,40 +1,97 @@
 -- name: CreateAccount :one
+-- an account belongs to the tenant of its owner.
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  tenant_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, COALESCE((SELECT tenant_id FROM users WHERE username = $1), 'default')
 ) RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetAccountIncludeDeleted :one
+-- for admins investigating an account that may have been deleted.
+SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByNumber :one
+-- an account number only resolves within its tenant.
+SELECT * FROM accounts
+WHERE number = $1 AND tenant_id = $2 AND deleted_at IS NULL LIMIT 1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
-WHERE id = $1 LIMIT 1
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListAccountsAfter :many
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner) AND deleted_at IS NULL AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListAccountsBefore :many
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner) AND deleted_at IS NULL AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListAccountsIncludeDeleted :many
+-- for admins investigating the accounts a user may have deleted.
+SELECT * FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
 OFFSET $3;
 
+-- name: ListAllAccounts :many
+-- every account of the owner, which has a handful at most.
+SELECT * FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL
+ORDER BY id;
+
 -- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: AddAccountBalance :one
 UPDATE accounts
 set balance = balance + sqlc.arg(amount)
-WHERE id = sqlc.arg(id)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: AddAccountHeldBalance :one
+-- holds amount more on the account, or releases it when negative.
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
+-- name: LockAccountBalance :exec
+-- serializes the balance updates of the account until the transaction ends, see BalanceLockAdvisory.
+SELECT pg_advisory_xact_lock(sqlc.arg(id)::bigint);
+
 -- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+-- the row is kept, its entries and transfers still reference it.
+UPDATE accounts
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: FreezeAccount :one
+UPDATE accounts
+SET frozen_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,60 @@
+-- name: SumEntriesByCategory :many
+-- the entries of an account between from_time and to_time, archived ones included, summed by
+-- category and direction. The totals are positive, an uncategorized entry has an empty category.
+SELECT
+  COALESCE(entry_categories.category, '')::varchar AS category,
+  (CASE WHEN period.amount < 0 THEN 'debit' ELSE 'credit' END)::varchar AS direction,
+  COUNT(*) AS count,
+  SUM(ABS(period.amount))::bigint AS total
+FROM (
+  SELECT id, amount FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+  UNION ALL
+  SELECT id, amount FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+) period
+LEFT JOIN entry_categories ON entry_categories.entry_id = period.id
+GROUP BY 1, 2
+ORDER BY 2, 4 DESC;
+
+-- name: SumEntriesByDay :many
+-- the entries of an account between from_time and to_time, archived ones included, summed by the
+-- day, e.g. 2026-03-29, they were created on in timezone. The days are those of the clock of the
+-- timezone, so that a day of a change of DST, 23 or 25 hours long, still is a single day.
+SELECT
+  to_char(period.created_at AT TIME ZONE sqlc.arg(timezone)::text, 'YYYY-MM-DD')::varchar AS day,
+  COALESCE(SUM(-period.amount) FILTER (WHERE period.amount < 0), 0)::bigint AS debit,
+  COALESCE(SUM(period.amount) FILTER (WHERE period.amount > 0), 0)::bigint AS credit
+FROM (
+  SELECT amount, created_at FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+  UNION ALL
+  SELECT amount, created_at FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+) period
+GROUP BY 1
+ORDER BY 1;
+
+-- name: SumEntriesSince :one
+-- what the entries of an account created from from_time on, archived ones included, moved its
+-- balance by.
+SELECT COALESCE(SUM(period.amount), 0)::bigint FROM (
+  SELECT amount FROM entries WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time)
+  UNION ALL
+  SELECT amount FROM entries_archive WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time)
+) period;
+
+-- name: SumTransfersByCounterparty :many
+-- the transfers from or to an account between from_time and to_time summed by the other account
+-- and direction, the largest totals first.
+SELECT
+  (CASE WHEN from_account_id = sqlc.arg(account_id) THEN to_account_id ELSE from_account_id END)::bigint AS counterparty_account_id,
+  (CASE WHEN from_account_id = sqlc.arg(account_id) THEN 'debit' ELSE 'credit' END)::varchar AS direction,
+  COUNT(*) AS count,
+  SUM(amount)::bigint AS total
+FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+GROUP BY 1, 2
+ORDER BY 4 DESC
+LIMIT sqlc.arg('limit');
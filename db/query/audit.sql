@@ -0,0 +1,65 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_entries (
+  actor,
+  action,
+  target,
+  reason,
+  details
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListAuditEntries :many
+SELECT * FROM audit_entries
+WHERE target = $1
+ORDER BY id DESC
+LIMIT $2;
+
+-- name: CreateAuditLog :one
+INSERT INTO audit_logs (
+  actor,
+  protocol,
+  action,
+  path,
+  status_code,
+  client_ip,
+  user_agent,
+  request_id,
+  created_at,
+  prev_hash,
+  hash,
+  impersonator
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: LockAuditLogChain :exec
+-- serializes the writes of the audit logs until the transaction ends, so that each is chained to
+-- the last one.
+SELECT pg_advisory_xact_lock(hashtext('audit_logs'), 0);
+
+-- name: GetLastAuditLogHash :one
+SELECT hash FROM audit_logs
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListAuditLogsAfter :many
+-- the filters match every log when null.
+SELECT * FROM audit_logs
+WHERE id > sqlc.arg(after_id)
+  AND (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(action)::varchar IS NULL OR action = sqlc.narg(action))
+  AND created_at >= COALESCE(sqlc.narg(from_time)::timestamptz, '-infinity')
+  AND created_at < COALESCE(sqlc.narg(to_time)::timestamptz, 'infinity')
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListAuditLogsBefore :many
+SELECT * FROM audit_logs
+WHERE id < sqlc.arg(before_id)
+  AND (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(action)::varchar IS NULL OR action = sqlc.narg(action))
+  AND created_at >= COALESCE(sqlc.narg(from_time)::timestamptz, '-infinity')
+  AND created_at < COALESCE(sqlc.narg(to_time)::timestamptz, 'infinity')
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,46 @@
+-- name: CloseAuthorizationHold :one
+UPDATE authorization_holds
+SET
+  status = sqlc.arg(status),
+  captured_amount = sqlc.arg(captured_amount),
+  transfer_id = sqlc.narg(transfer_id),
+  closed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateAuthorizationHold :one
+INSERT INTO authorization_holds (
+  account_id,
+  to_account_id,
+  amount,
+  currency,
+  memo,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetAuthorizationHold :one
+SELECT * FROM authorization_holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAuthorizationHoldForUpdate :one
+SELECT * FROM authorization_holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountAuthorizationHolds :many
+-- the holds on the account and those in its favor; the status filter matches every hold when null.
+SELECT * FROM authorization_holds
+WHERE (account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListExpiredAuthorizationHolds :many
+-- the active holds past their expiry, oldest first.
+SELECT * FROM authorization_holds
+WHERE status = 'active' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
@@ -0,0 +1,82 @@
+-- name: CreateCard :one
+INSERT INTO cards (
+  account_id,
+  fingerprint,
+  last4,
+  expiry_month,
+  expiry_year,
+  per_transaction_limit,
+  daily_limit
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: CreateCardAuthorization :one
+INSERT INTO card_authorizations (
+  card_id,
+  amount,
+  currency,
+  merchant_name,
+  merchant_category,
+  approved,
+  decline_reason,
+  transfer_id,
+  network_transaction_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetCard :one
+SELECT * FROM cards
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCardAuthorizationByNetworkTransactionID :one
+SELECT * FROM card_authorizations
+WHERE network_transaction_id = $1 LIMIT 1;
+
+-- name: GetCardByFingerprint :one
+SELECT * FROM cards
+WHERE fingerprint = $1 LIMIT 1;
+
+-- name: GetCardForUpdate :one
+-- locks the card, so that the authorizations of a card are checked against its limits one at a time.
+SELECT * FROM cards
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountCards :many
+SELECT * FROM cards
+WHERE account_id = $1
+ORDER BY id;
+
+-- name: ListCardAuthorizations :many
+SELECT * FROM card_authorizations
+WHERE card_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SetCardFrozen :one
+UPDATE cards
+SET frozen_at = CASE WHEN sqlc.arg(frozen)::boolean THEN COALESCE(frozen_at, now()) END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SumCardAuthorizations :one
+-- the amount the approved authorizations of a card spent on the day of at, which starts at
+-- midnight in the timezone of the preferences of the owner of the card, UTC by default.
+SELECT COALESCE(SUM(card_authorizations.amount), 0)::bigint
+FROM card_authorizations
+JOIN cards ON cards.id = card_authorizations.card_id
+JOIN accounts ON accounts.id = cards.account_id
+JOIN users ON users.username = accounts.owner
+WHERE card_authorizations.card_id = sqlc.arg(card_id) AND card_authorizations.approved
+  AND card_authorizations.created_at >= local_day_start(sqlc.arg(at), users.preferences->>'timezone');
+
+-- name: UpdateCardLimits :one
+UPDATE cards
+SET
+  per_transaction_limit = sqlc.arg(per_transaction_limit),
+  daily_limit = sqlc.arg(daily_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
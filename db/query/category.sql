@@ -0,0 +1,55 @@
+-- name: CreateCategoryRule :one
+INSERT INTO category_rules (
+  owner,
+  category,
+  counterparty_account_id,
+  direction,
+  memo_contains
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCategoryRule :one
+SELECT * FROM category_rules
+WHERE id = $1 LIMIT 1;
+
+-- name: ListCategoryRules :many
+SELECT * FROM category_rules
+WHERE owner = $1
+ORDER BY id;
+
+-- name: DeleteCategoryRule :exec
+DELETE FROM category_rules
+WHERE id = $1;
+
+-- name: CategorizeEntry :execrows
+-- sets the category of an entry by the first rule of owner it matches, unless it has one.
+INSERT INTO entry_categories (entry_id, account_id, category, rule_id)
+SELECT sqlc.arg(entry_id)::bigint, sqlc.arg(account_id)::bigint, category, id
+FROM category_rules
+WHERE owner = sqlc.arg(owner)
+  AND (counterparty_account_id IS NULL OR counterparty_account_id = sqlc.arg(counterparty_account_id)::bigint)
+  AND (direction = '' OR direction = sqlc.arg(direction))
+  AND (memo_contains = '' OR strpos(lower(sqlc.arg(memo)::varchar), lower(memo_contains)) > 0)
+ORDER BY id
+LIMIT 1
+ON CONFLICT (entry_id) DO NOTHING;
+
+-- name: SetEntryCategory :one
+-- a category set by the owner replaces the one of a rule.
+INSERT INTO entry_categories (
+  entry_id,
+  account_id,
+  category,
+  tags
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (entry_id) DO UPDATE
+SET category = EXCLUDED.category, tags = EXCLUDED.tags, rule_id = NULL, updated_at = now()
+RETURNING *;
+
+-- name: ListEntryCategories :many
+SELECT * FROM entry_categories
+WHERE entry_id = ANY(sqlc.arg(entry_ids)::bigint[])
+ORDER BY entry_id;
@@ -0,0 +1,49 @@
+-- name: CreateLegalDocument :one
+INSERT INTO legal_documents (
+  kind,
+  version,
+  url,
+  created_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListLatestLegalDocuments :many
+-- the latest version of each kind of document.
+SELECT * FROM legal_documents d
+WHERE NOT EXISTS (
+  SELECT 1 FROM legal_documents newer
+  WHERE newer.kind = d.kind AND newer.published_at > d.published_at
+)
+ORDER BY kind;
+
+-- name: ListPendingLegalDocuments :many
+-- the latest versions the user hasn't accepted yet.
+SELECT * FROM legal_documents d
+WHERE NOT EXISTS (
+  SELECT 1 FROM legal_documents newer
+  WHERE newer.kind = d.kind AND newer.published_at > d.published_at
+) AND NOT EXISTS (
+  SELECT 1 FROM user_consents c
+  WHERE c.username = sqlc.arg(username) AND c.kind = d.kind AND c.version = d.version
+)
+ORDER BY kind;
+
+-- name: CreateUserConsent :one
+-- accepting a version again keeps the first acceptance.
+INSERT INTO user_consents (
+  username,
+  kind,
+  version,
+  client_ip,
+  user_agent
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (username, kind, version) DO UPDATE SET accepted_at = user_consents.accepted_at
+RETURNING *;
+
+-- name: ListUserConsents :many
+SELECT * FROM user_consents
+WHERE username = $1
+ORDER BY accepted_at DESC;
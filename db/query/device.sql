@@ -0,0 +1,69 @@
+-- name: UpsertDevice :one
+-- registers the device a user logs in from. Logging in again from a known device keeps its name,
+-- and from a revoked one registers it again, untrusted.
+INSERT INTO devices (
+  username,
+  fingerprint,
+  name
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (username, fingerprint) DO UPDATE SET
+  last_seen_at = now(),
+  trusted_at = CASE WHEN devices.revoked_at IS NULL THEN devices.trusted_at END,
+  verification_hash = CASE WHEN devices.revoked_at IS NULL THEN devices.verification_hash ELSE '' END,
+  verification_attempts = CASE WHEN devices.revoked_at IS NULL THEN devices.verification_attempts ELSE 0 END,
+  revoked_at = NULL
+RETURNING *;
+
+-- name: GetDevice :one
+SELECT * FROM devices
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDevices :many
+-- the devices of the user that aren't revoked, the last seen first.
+SELECT * FROM devices
+WHERE username = $1 AND revoked_at IS NULL
+ORDER BY last_seen_at DESC, id DESC;
+
+-- name: RenameDevice :one
+UPDATE devices
+SET name = sqlc.arg(name)
+WHERE id = sqlc.arg(id) AND username = sqlc.arg(username) AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeDevice :one
+UPDATE devices
+SET revoked_at = now()
+WHERE id = sqlc.arg(id) AND username = sqlc.arg(username) AND revoked_at IS NULL
+RETURNING *;
+
+-- name: BlockDeviceSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE device_id = $1;
+
+-- name: SetDeviceVerification :one
+-- replaces the one-time code of an untrusted device, keeping the count of its wrong codes.
+UPDATE devices
+SET
+  verification_hash = sqlc.arg(verification_hash),
+  verification_sent_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddDeviceVerificationAttempt :one
+UPDATE devices
+SET verification_attempts = verification_attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: TrustDevice :one
+UPDATE devices
+SET
+  trusted_at = now(),
+  last_seen_at = now(),
+  verification_hash = '',
+  verification_attempts = 0
+WHERE id = $1
+RETURNING *;
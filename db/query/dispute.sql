@@ -0,0 +1,44 @@
+-- name: CreateDispute :one
+INSERT INTO disputes (
+  transfer_id,
+  account_id,
+  opened_by,
+  amount,
+  reason
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetDispute :one
+SELECT * FROM disputes
+WHERE id = $1 LIMIT 1;
+
+-- name: GetDisputeForUpdate :one
+SELECT * FROM disputes
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetHeldAmount :one
+-- the amount of the disputes still open or investigating on the account.
+SELECT COALESCE(SUM(amount), 0)::bigint FROM disputes
+WHERE account_id = $1 AND status IN ('open', 'investigating');
+
+-- name: ListDisputes :many
+-- the status filter matches every dispute when null.
+SELECT * FROM disputes
+WHERE sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: UpdateDisputeStatus :one
+UPDATE disputes
+SET
+  status = sqlc.arg(status),
+  resolution = sqlc.arg(resolution),
+  resolved_by = sqlc.arg(resolved_by),
+  reversal_transfer_id = sqlc.narg(reversal_transfer_id),
+  resolved_at = sqlc.narg(resolved_at),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
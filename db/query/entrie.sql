@@ -12,8 +12,64 @@ SELECT * FROM entries
 WHERE id = $1 LIMIT 1;
 
 -- name: ListEntries :many
+-- from_time and to_time bound created_at when set, so that only the partitions
+-- of the months in between are scanned.
 SELECT * FROM entries
-WHERE account_id = $1
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= COALESCE(sqlc.narg(from_time)::timestamptz, '-infinity')
+  AND created_at < COALESCE(sqlc.narg(to_time)::timestamptz, 'infinity')
 ORDER BY id
-LIMIT $2
-OFFSET $3;
\ No newline at end of file
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListEntriesAfter :many
+SELECT * FROM entries
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: CreateEntries :copyfrom
+INSERT INTO entries (
+  account_id,
+  amount
+) VALUES (
+  $1, $2
+);
+
+-- name: ArchiveEntries :execrows
+-- moves up to limit of the entries created before the cutoff to entries_archive, oldest first.
+WITH moved AS (
+  DELETE FROM entries
+  WHERE (id, created_at) IN (
+    SELECT id, created_at FROM entries
+    WHERE created_at < sqlc.arg(before)
+    ORDER BY created_at
+    LIMIT sqlc.arg('limit')
+  )
+  RETURNING *
+)
+INSERT INTO entries_archive SELECT * FROM moved;
+
+-- name: ListStatementEntriesAfter :many
+-- the statement of an account spans its entries and the archived ones.
+SELECT * FROM (
+  SELECT * FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND id > sqlc.arg(after_id)
+  UNION ALL
+  SELECT * FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND id > sqlc.arg(after_id)
+) statement
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListStatementEntriesBefore :many
+-- the statement of an account spans its entries and the archived ones.
+SELECT * FROM (
+  SELECT * FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND id < sqlc.arg(before_id)
+  UNION ALL
+  SELECT * FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND id < sqlc.arg(before_id)
+) statement
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
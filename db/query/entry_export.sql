@@ -0,0 +1,77 @@
+-- name: CountExportEntries :one
+-- the entries of an account between from_time and to_time, archived ones included.
+SELECT ((
+  SELECT COUNT(*) FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+) + (
+  SELECT COUNT(*) FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+))::bigint;
+
+-- name: ListExportEntries :many
+-- the entries of an account between from_time and to_time after after_id, archived ones included,
+-- with their category and tags.
+SELECT
+  export.id,
+  export.account_id,
+  export.amount,
+  export.created_at,
+  COALESCE(entry_categories.category, '')::varchar AS category,
+  COALESCE(entry_categories.tags, '{}')::text[] AS tags
+FROM (
+  SELECT * FROM entries
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+    AND id > sqlc.arg(after_id)
+  UNION ALL
+  SELECT * FROM entries_archive
+  WHERE account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+    AND id > sqlc.arg(after_id)
+) export
+LEFT JOIN entry_categories ON entry_categories.entry_id = export.id
+ORDER BY export.id
+LIMIT sqlc.arg('limit');
+
+-- name: CreateEntryExport :one
+INSERT INTO entry_exports (
+  owner,
+  account_id,
+  format,
+  from_time,
+  to_time,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetEntryExport :one
+SELECT * FROM entry_exports
+WHERE id = $1 LIMIT 1;
+
+-- name: FinishEntryExport :one
+UPDATE entry_exports
+SET status = 'ready', entries = sqlc.arg(entries), completed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: FailEntryExport :one
+UPDATE entry_exports
+SET status = 'failed', error = sqlc.arg(error), completed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: CreateEntryExportFile :exec
+INSERT INTO entry_export_files (
+  export_id,
+  content
+) VALUES (
+  $1, $2
+);
+
+-- name: GetEntryExportFile :one
+SELECT content FROM entry_export_files
+WHERE export_id = $1 LIMIT 1;
+
+-- name: DeleteExpiredEntryExports :execrows
+-- deletes the exports that expired, their files along with them.
+DELETE FROM entry_exports
+WHERE expires_at < now();
@@ -0,0 +1,20 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO event_outbox (
+  event_type,
+  event_key,
+  payload
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: ListPendingOutboxEvents :many
+SELECT * FROM event_outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE event_outbox
+SET published_at = now()
+WHERE id = $1;
@@ -0,0 +1,27 @@
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (
+  base_currency,
+  quote_currency,
+  rate_nanos,
+  source,
+  published_at,
+  fetched_at
+) VALUES (
+  $1, $2, $3, $4, $5, now()
+)
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+SET rate_nanos = EXCLUDED.rate_nanos,
+    source = EXCLUDED.source,
+    published_at = EXCLUDED.published_at,
+    fetched_at = EXCLUDED.fetched_at
+RETURNING *;
+
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+ORDER BY base_currency, quote_currency;
+
+-- name: GetFXAccount :one
+-- the account converting the transfers from and to currency.
+SELECT * FROM accounts
+WHERE owner = 'bank.fx' AND currency = $1 AND deleted_at IS NULL
+LIMIT 1;
@@ -0,0 +1,64 @@
+-- name: CompleteExternalTransfer :one
+UPDATE external_transfers
+SET
+  status = sqlc.arg(status),
+  return_reason = sqlc.arg(return_reason),
+  return_transfer_id = sqlc.narg(return_transfer_id),
+  completed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateExternalTransfer :one
+INSERT INTO external_transfers (
+  account_id,
+  amount,
+  currency,
+  rail,
+  beneficiary_name,
+  beneficiary_account,
+  beneficiary_routing,
+  memo,
+  debit_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetExternalTransfer :one
+SELECT * FROM external_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetExternalTransferForUpdate :one
+SELECT * FROM external_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetSuspenseAccount :one
+-- the account holding the external transfers in currency until they settle.
+SELECT * FROM accounts
+WHERE owner = 'bank.suspense' AND currency = $1 AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListAccountExternalTransfers :many
+SELECT * FROM external_transfers
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListExternalTransfersToSettle :many
+-- the pending transfers to submit and the submitted ones due to settle, oldest first.
+SELECT * FROM external_transfers
+WHERE status = 'pending' OR (status = 'submitted' AND settle_at <= now())
+ORDER BY id
+LIMIT $1;
+
+-- name: SubmitExternalTransfer :one
+-- moves a pending transfer to submitted, nothing when it was submitted in the meantime.
+UPDATE external_transfers
+SET
+  status = 'submitted',
+  reference = sqlc.arg(reference),
+  submitted_at = now(),
+  settle_at = sqlc.arg(settle_at)
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
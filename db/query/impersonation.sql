@@ -0,0 +1,21 @@
+-- name: CreateImpersonation :one
+INSERT INTO impersonations (
+  id,
+  admin,
+  username,
+  reason,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetImpersonation :one
+SELECT * FROM impersonations
+WHERE id = $1 LIMIT 1;
+
+-- name: RevokeImpersonation :one
+-- keeps the time of the first revocation.
+UPDATE impersonations
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE id = $1
+RETURNING *;
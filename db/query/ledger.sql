@@ -0,0 +1,38 @@
+-- name: ListBalanceMismatches :many
+SELECT
+  a.id AS account_id,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN (
+  SELECT account_id, amount FROM entries
+  UNION ALL
+  SELECT account_id, amount FROM entries_archive
+) e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+LIMIT $1;
+
+-- name: ListUnbalancedTransfers :many
+-- entries don't reference their transfer, TransferTx creates them in the same transaction
+-- so they share its created_at. Archived entries still count.
+SELECT * FROM (
+  SELECT
+    t.id AS transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    (SELECT COUNT(*) FROM entries e
+     WHERE e.account_id = t.from_account_id AND e.amount = -t.amount AND e.created_at = t.created_at) +
+    (SELECT COUNT(*) FROM entries_archive e
+     WHERE e.account_id = t.from_account_id AND e.amount = -t.amount AND e.created_at = t.created_at) AS debit_entries,
+    (SELECT COUNT(*) FROM entries e
+     WHERE e.account_id = t.to_account_id AND e.amount = t.amount AND e.created_at = t.created_at) +
+    (SELECT COUNT(*) FROM entries_archive e
+     WHERE e.account_id = t.to_account_id AND e.amount = t.amount AND e.created_at = t.created_at) AS credit_entries
+  FROM transfers t
+) checked
+WHERE debit_entries <> 1 OR credit_entries <> 1
+ORDER BY transfer_id
+LIMIT $1;
@@ -0,0 +1,140 @@
+-- name: CreateLoan :one
+INSERT INTO loans (
+  offer_id,
+  account_id,
+  principal,
+  currency,
+  annual_rate_bps,
+  term_months,
+  disbursement_transfer_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: CreateLoanInstallment :one
+INSERT INTO loan_installments (
+  loan_id,
+  number,
+  due_at,
+  principal,
+  interest,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateLoanOffer :one
+INSERT INTO loan_offers (
+  name,
+  currency,
+  min_amount,
+  max_amount,
+  annual_rate_bps,
+  term_months,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetLendingAccount :one
+-- the account disbursing the loans in currency and collecting their installments.
+SELECT * FROM accounts
+WHERE owner = 'bank.lending' AND currency = $1 AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetLoan :one
+SELECT * FROM loans
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLoanForUpdate :one
+SELECT * FROM loans
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetLoanInstallmentForUpdate :one
+SELECT * FROM loan_installments
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetLoanOffer :one
+SELECT * FROM loan_offers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLoanStanding :one
+-- the installments of the loan left to pay, and those of them past due.
+SELECT
+  COUNT(*)::bigint AS unpaid_installments,
+  (COUNT(*) FILTER (WHERE due_at <= now()))::bigint AS overdue_installments
+FROM loan_installments
+WHERE loan_id = $1 AND status = 'due';
+
+-- name: ListAccountLoans :many
+SELECT * FROM loans
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListDelinquentLoans :many
+-- the delinquent loans with what is past due on them, the longest overdue first.
+SELECT
+  loans.id, loans.offer_id, loans.account_id, loans.principal, loans.currency, loans.status,
+  COALESCE(SUM(loan_installments.amount), 0)::bigint AS overdue_amount,
+  COUNT(loan_installments.id)::bigint AS overdue_installments,
+  MIN(loan_installments.due_at)::timestamptz AS oldest_due_at
+FROM loans
+JOIN loan_installments ON loan_installments.loan_id = loans.id
+  AND loan_installments.status = 'due' AND loan_installments.due_at <= now()
+WHERE loans.status = 'delinquent'
+GROUP BY loans.id
+ORDER BY oldest_due_at, loans.id
+LIMIT $1
+OFFSET $2;
+
+-- name: ListDueLoanInstallments :many
+-- the installments due and not paid yet, oldest first.
+SELECT * FROM loan_installments
+WHERE status = 'due' AND due_at <= now()
+ORDER BY due_at, id
+LIMIT $1;
+
+-- name: ListLoanInstallments :many
+SELECT * FROM loan_installments
+WHERE loan_id = $1
+ORDER BY number;
+
+-- name: ListLoanOffers :many
+-- every offer, or only the active ones when active_only.
+SELECT * FROM loan_offers
+WHERE active OR NOT sqlc.arg(active_only)::boolean
+ORDER BY id;
+
+-- name: MarkLoanDelinquent :one
+-- moves an active loan to delinquent, nothing when it was delinquent already.
+UPDATE loans
+SET status = 'delinquent'
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: MarkLoanInstallmentPaid :one
+UPDATE loan_installments
+SET
+  status = 'paid',
+  transfer_id = sqlc.arg(transfer_id),
+  paid_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetLoanOfferActive :one
+UPDATE loan_offers
+SET active = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SetLoanStatus :one
+UPDATE loans
+SET
+  status = sqlc.arg(status),
+  closed_at = CASE WHEN sqlc.arg(status) = 'paid_off' THEN now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,57 @@
+-- name: CreateLoginAlert :one
+INSERT INTO login_alerts (
+  username,
+  session_id,
+  client_ip,
+  user_agent,
+  country,
+  reasons,
+  report_hash
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetLoginAlert :one
+SELECT * FROM login_alerts
+WHERE id = $1 LIMIT 1;
+
+-- name: ReportLoginAlert :one
+-- reports an alert along with the hash of the code resetting the password, once.
+UPDATE login_alerts
+SET
+  reported_at = now(),
+  reset_hash = sqlc.arg(reset_hash),
+  reset_sent_at = now()
+WHERE id = sqlc.arg(id) AND reported_at IS NULL
+RETURNING *;
+
+-- name: SetLoginAlertResetCode :one
+-- replaces the code resetting the password of a reported alert, with a new count of its wrong codes.
+UPDATE login_alerts
+SET
+  reset_hash = sqlc.arg(reset_hash),
+  reset_attempts = 0,
+  reset_sent_at = now()
+WHERE id = sqlc.arg(id) AND reported_at IS NOT NULL AND password_reset_at IS NULL
+RETURNING *;
+
+-- name: AddLoginAlertResetAttempt :one
+UPDATE login_alerts
+SET reset_attempts = reset_attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: FinishLoginAlertReset :one
+UPDATE login_alerts
+SET
+  password_reset_at = now(),
+  reset_hash = ''
+WHERE id = $1 AND reported_at IS NOT NULL AND password_reset_at IS NULL
+RETURNING *;
+
+-- name: HasPendingPasswordReset :one
+-- whether the user reported a login and didn't reset their password since.
+SELECT EXISTS (
+  SELECT 1 FROM login_alerts
+  WHERE username = $1 AND reported_at IS NOT NULL AND password_reset_at IS NULL
+);
@@ -0,0 +1,13 @@
+-- name: GetMaintenanceMode :one
+SELECT * FROM maintenance_modes
+LIMIT 1;
+
+-- name: SetMaintenanceReadOnly :one
+UPDATE maintenance_modes
+SET read_only = $1, updated_at = now()
+RETURNING *;
+
+-- name: SetMaintenanceTransfersBlocked :one
+UPDATE maintenance_modes
+SET transfers_blocked = $1, updated_at = now()
+RETURNING *;
@@ -0,0 +1,60 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  username,
+  kind,
+  title,
+  body,
+  dedup_key
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (dedup_key) DO NOTHING
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE username = sqlc.arg('username')
+  AND (NOT sqlc.arg('unread_only')::boolean OR read_at IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE username = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = coalesce(read_at, now())
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = now()
+WHERE username = $1 AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE username = $1 LIMIT 1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (
+  username,
+  email_enabled,
+  sms_enabled,
+  push_enabled,
+  phone_number,
+  push_token
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (username) DO UPDATE
+SET
+ email_enabled = EXCLUDED.email_enabled,
+ sms_enabled = EXCLUDED.sms_enabled,
+ push_enabled = EXCLUDED.push_enabled,
+ phone_number = EXCLUDED.phone_number,
+ push_token = EXCLUDED.push_token,
+ updated_at = now()
+RETURNING *;
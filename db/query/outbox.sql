@@ -0,0 +1,21 @@
+-- name: CreateOutboxTask :one
+INSERT INTO outbox (
+  task_type,
+  payload,
+  queue,
+  max_retry
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListPendingOutboxTasks :many
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxTaskPublished :exec
+UPDATE outbox
+SET published_at = now()
+WHERE id = $1;
@@ -0,0 +1,2 @@
+-- name: CreateMonthlyPartition :exec
+SELECT create_monthly_partition(sqlc.arg(parent)::text, sqlc.arg(day)::timestamptz);
@@ -0,0 +1,36 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+  account_id,
+  amount,
+  currency,
+  memo,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkPaymentRequestPaid :one
+UPDATE payment_requests
+SET
+  status = 'paid',
+  payer_account_id = sqlc.arg(payer_account_id),
+  transfer_id = sqlc.arg(transfer_id),
+  paid_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,77 @@
+-- name: CloseReferral :one
+-- a referral closes once, so closing a referral no longer pending returns no row.
+UPDATE referrals
+SET
+  status = sqlc.arg(status),
+  reason = sqlc.arg(reason),
+  qualifying_transfer_id = sqlc.narg(qualifying_transfer_id),
+  referrer_transfer_id = sqlc.narg(referrer_transfer_id),
+  referee_transfer_id = sqlc.narg(referee_transfer_id),
+  closed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: CountRewardedReferrals :one
+SELECT count(*) FROM referrals
+WHERE referrer = $1 AND status = 'rewarded';
+
+-- name: CreateReferral :one
+INSERT INTO referrals (
+  referrer,
+  referee
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: CreateReferralCode :one
+-- a user has one code, so creating the code of a user who has one returns no row.
+INSERT INTO referral_codes (
+  username,
+  code
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (username) DO NOTHING
+RETURNING *;
+
+-- name: GetFirstQualifyingTransfer :one
+-- the first transfer of at least min_amount the referee sent between from_time and to_time, to an
+-- account owned by neither the referee nor the referrer.
+SELECT transfers.* FROM transfers
+JOIN accounts AS from_account ON from_account.id = transfers.from_account_id
+JOIN accounts AS to_account ON to_account.id = transfers.to_account_id
+WHERE from_account.owner = sqlc.arg(referee)
+  AND to_account.owner <> sqlc.arg(referee)
+  AND to_account.owner <> sqlc.arg(referrer)
+  AND transfers.amount >= sqlc.arg(min_amount)
+  AND transfers.created_at >= sqlc.arg(from_time)
+  AND transfers.created_at < sqlc.arg(to_time)
+ORDER BY transfers.id
+LIMIT 1;
+
+-- name: GetReferralCode :one
+SELECT * FROM referral_codes
+WHERE username = $1 LIMIT 1;
+
+-- name: GetReferralCodeByCode :one
+SELECT * FROM referral_codes
+WHERE code = $1 LIMIT 1;
+
+-- name: GetReferralForUpdate :one
+SELECT * FROM referrals
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPendingReferrals :many
+SELECT * FROM referrals
+WHERE status = 'pending' AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListReferrals :many
+-- the referrals of referrer, the newest first.
+SELECT * FROM referrals
+WHERE referrer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
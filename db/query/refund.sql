@@ -0,0 +1,27 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+  transfer_id,
+  refund_transfer_id,
+  amount,
+  reason,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetRefundedAmount :one
+-- the amount of the refunds of the transfer so far.
+SELECT COALESCE(SUM(amount), 0)::bigint FROM refunds
+WHERE transfer_id = $1;
+
+-- name: GetReturnedAmount :one
+-- the amount of the transfer that went back to the account it debited: by its refunds, and by the
+-- reversal of its refunded dispute.
+SELECT
+  (SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE refunds.transfer_id = sqlc.arg(transfer_id))::bigint AS refunded,
+  (SELECT COALESCE(SUM(amount), 0) FROM disputes WHERE disputes.transfer_id = sqlc.arg(transfer_id) AND status = 'refunded')::bigint AS reversed;
+
+-- name: ListTransferRefunds :many
+SELECT * FROM refunds
+WHERE transfer_id = $1
+ORDER BY id;
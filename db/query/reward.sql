@@ -0,0 +1,31 @@
+-- name: CreateRewardEntry :one
+-- a transfer has one reward entry at most, so the accrual of a transfer already rewarded returns no
+-- row.
+INSERT INTO reward_entries (
+  account_id,
+  kind,
+  points,
+  transfer_id
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (transfer_id) DO NOTHING
+RETURNING *;
+
+-- name: GetRewardBalance :one
+-- the points the account can redeem.
+SELECT COALESCE(SUM(points), 0)::bigint FROM reward_entries
+WHERE account_id = $1;
+
+-- name: GetRewardsAccount :one
+-- the account paying out the cashback redeemed in currency.
+SELECT * FROM accounts
+WHERE owner = 'bank.rewards' AND currency = $1 AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListRewardEntries :many
+SELECT * FROM reward_entries
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,91 @@
+-- name: CreateDenylistEntry :one
+INSERT INTO denylist_entries (
+  kind,
+  value,
+  reason,
+  created_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: DeleteDenylistEntry :one
+DELETE FROM denylist_entries
+WHERE id = $1
+RETURNING *;
+
+-- name: GetDenylistEntry :one
+SELECT * FROM denylist_entries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDenylistEntries :many
+-- the kind filter matches every entry when null.
+SELECT * FROM denylist_entries
+WHERE sqlc.narg(kind)::varchar IS NULL OR kind = sqlc.narg(kind)
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: MatchDenylist :many
+-- the name and email entries a user with full_name and email matches.
+SELECT * FROM denylist_entries
+WHERE (kind = 'name' AND lower(value) = lower(sqlc.arg(full_name)))
+   OR (kind = 'email' AND lower(value) = lower(sqlc.arg(email)))
+ORDER BY id;
+
+-- name: ListAccountScreeningMatches :many
+-- the matches flagging an account: an entry of the account itself, or those of an open hold of its owner.
+SELECT ('account:' || value)::text AS match FROM denylist_entries
+WHERE kind = 'account' AND value = sqlc.arg(account_id)::bigint::text
+UNION ALL
+SELECT unnest(screening_holds.matches)::text AS match FROM screening_holds
+JOIN accounts ON accounts.owner = screening_holds.username
+WHERE accounts.id = sqlc.arg(account_id) AND screening_holds.status IN ('pending', 'confirmed');
+
+-- name: CreateScreeningHold :one
+INSERT INTO screening_holds (
+  username,
+  matches
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: HoldUsersMatchingDenylist :many
+-- holds the users matching a new name or email entry, but those with an open hold already.
+INSERT INTO screening_holds (username, matches)
+SELECT users.username, ARRAY[sqlc.arg(kind)::varchar || ':' || sqlc.arg(value)::varchar]
+FROM users
+WHERE users.deleted_at IS NULL
+  AND ((sqlc.arg(kind) = 'name' AND lower(users.full_name) = lower(sqlc.arg(value)))
+    OR (sqlc.arg(kind) = 'email' AND lower(users.email) = lower(sqlc.arg(value))))
+  AND NOT EXISTS (
+    SELECT 1 FROM screening_holds
+    WHERE screening_holds.username = users.username AND screening_holds.status IN ('pending', 'confirmed')
+  )
+RETURNING *;
+
+-- name: GetScreeningHold :one
+SELECT * FROM screening_holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScreeningHoldForUpdate :one
+SELECT * FROM screening_holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScreeningHolds :many
+-- the status filter matches every hold when null.
+SELECT * FROM screening_holds
+WHERE sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: UpdateScreeningHoldStatus :one
+UPDATE screening_holds
+SET
+  status = sqlc.arg(status),
+  reviewed_by = sqlc.arg(reviewed_by),
+  note = sqlc.arg(note),
+  reviewed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
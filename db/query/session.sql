@@ -6,11 +6,44 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  country,
+  device_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLastLoginCountry :one
+-- the country of the last login of the user that has one.
+SELECT country FROM sessions
+WHERE username = $1 AND country <> ''
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1;
+
+-- name: CountActiveSessions :one
+SELECT count(*) FROM sessions
+WHERE is_blocked = false AND expires_at > now();
+
+-- name: ListRecentSessions :many
+-- the last logins of the user, the latest first.
+SELECT * FROM sessions
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: BindSessionDevice :one
+-- binds a session logged in before every login was bound to its device, nothing when it is
+-- bound already.
+UPDATE sessions
+SET device_id = sqlc.arg(device_id)
+WHERE id = sqlc.arg(id) AND device_id IS NULL
+RETURNING *;
@@ -0,0 +1,24 @@
+-- name: CreateStepUpChallenge :one
+INSERT INTO step_up_challenges (
+  username,
+  code_hash
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetStepUpChallenge :one
+SELECT * FROM step_up_challenges
+WHERE id = $1 LIMIT 1;
+
+-- name: AddStepUpChallengeAttempt :one
+UPDATE step_up_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: CompleteStepUpChallenge :one
+-- completes a challenge once, so that its code can't step up another token.
+UPDATE step_up_challenges
+SET completed_at = now()
+WHERE id = $1 AND completed_at IS NULL
+RETURNING *;
@@ -0,0 +1,30 @@
+-- name: CreateTenant :one
+INSERT INTO tenants (
+  id,
+  name,
+  currencies,
+  max_transfer_amount,
+  email_sender_name,
+  support_email
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetTenant :one
+SELECT * FROM tenants
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTenants :many
+SELECT * FROM tenants
+ORDER BY id;
+
+-- name: UpdateTenant :one
+UPDATE tenants
+SET
+  name = coalesce(sqlc.narg('name'), name),
+  currencies = coalesce(sqlc.narg('currencies')::varchar[], currencies),
+  max_transfer_amount = coalesce(sqlc.narg('max_transfer_amount'), max_transfer_amount),
+  email_sender_name = coalesce(sqlc.narg('email_sender_name'), email_sender_name),
+  support_email = coalesce(sqlc.narg('support_email'), support_email)
+WHERE id = sqlc.arg('id')
+RETURNING *;
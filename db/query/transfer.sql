@@ -1,22 +1,74 @@
 
 -- name: CreateTransfer :one
+-- a transfer belongs to the tenant of its payer, or of its payee when the bank pays.
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  tenant_id
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4,
+  COALESCE(
+    (SELECT tenant_id FROM accounts WHERE id = $1 AND owner NOT LIKE 'bank.%'),
+    (SELECT tenant_id FROM accounts WHERE id = $2)
+  )
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+-- serializes what pays the transfer back, its refunds and the reversal of its dispute.
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListTransfers :many
+-- from_time and to_time bound created_at when set, so that only the partitions
+-- of the months in between are scanned.
 SELECT * FROM transfers
 WHERE 
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = sqlc.arg(from_account_id) OR
+    to_account_id = sqlc.arg(to_account_id))
+    AND created_at >= COALESCE(sqlc.narg(from_time)::timestamptz, '-infinity')
+    AND created_at < COALESCE(sqlc.narg(to_time)::timestamptz, 'infinity')
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListTransfersAfter :many
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND id > sqlc.arg(after_id)
 ORDER BY id
-LIMIT $3
-OFFSET $4;
\ No newline at end of file
+LIMIT sqlc.arg('limit');
+
+-- name: ListTransfersBefore :many
+SELECT * FROM transfers
+WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+  AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SearchTransfers :many
+-- query is a tsquery, see SearchQuery.
+SELECT transfers.*, ts_rank(to_tsvector('simple', memo), query)::real AS rank
+FROM transfers, to_tsquery('simple', sqlc.arg(query)) query
+WHERE to_tsvector('simple', memo) @@ query
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountTransfersSince :one
+-- the transfers the account sent since created_at.
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2;
+
+-- name: HasTransferBetween :one
+-- whether the account ever sent a transfer to to_account_id.
+SELECT EXISTS (
+  SELECT 1 FROM transfers
+  WHERE from_account_id = $1 AND to_account_id = $2
+);
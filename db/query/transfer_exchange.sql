@@ -0,0 +1,13 @@
+-- name: CreateTransferExchange :one
+INSERT INTO transfer_exchanges (
+  transfer_id,
+  exchange_transfer_id
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetTransferExchange :one
+-- the exchange of either of its transfers.
+SELECT * FROM transfer_exchanges
+WHERE transfer_id = sqlc.arg(transfer_id) OR exchange_transfer_id = sqlc.arg(transfer_id)
+LIMIT 1;
@@ -0,0 +1,49 @@
+-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+  username,
+  from_account_id,
+  to_account_id,
+  amount,
+  currency,
+  memo,
+  decision,
+  rules,
+  otp_hash
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetTransferReview :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferReviewForUpdate :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferReviews :many
+-- the status and decision filters match every review when null.
+SELECT * FROM transfer_reviews
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(decision)::varchar IS NULL OR decision = sqlc.narg(decision))
+ORDER BY id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: AddTransferReviewOTPAttempt :one
+UPDATE transfer_reviews
+SET otp_attempts = otp_attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateTransferReviewStatus :one
+UPDATE transfer_reviews
+SET
+  status = sqlc.arg(status),
+  reviewed_by = sqlc.arg(reviewed_by),
+  note = sqlc.arg(note),
+  transfer_id = sqlc.narg(transfer_id),
+  reviewed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
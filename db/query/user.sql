@@ -1,15 +1,22 @@
 -- name: CreateUser :one
+-- a user joins the default tenant unless tenant_id tells.
 INSERT INTO users (
   username,
   hashed_password,
   full_name,
-  email
+  email,
+  tenant_id
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, COALESCE(sqlc.narg(tenant_id)::varchar, 'default')
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
+WHERE username = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetUserIncludeDeleted :one
+-- for admins investigating a user that may have been deleted.
+SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
 -- name: UpdateUser :one
@@ -19,5 +26,39 @@ SET
  full_name = coalesce(sqlc.narg('full_name'), full_name),
  email = coalesce(sqlc.narg('email'),email),
  password_changed_at = coalesce(sqlc.narg('password_changed_at'), password_changed_at)
-WHERE username = sqlc.arg('username')
-RETURNING *;
\ No newline at end of file
+WHERE username = sqlc.arg('username') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: AnonymizeUser :one
+UPDATE users
+SET
+ hashed_password = '',
+ full_name = sqlc.arg('full_name'),
+ email = sqlc.arg('email'),
+ password_changed_at = now(),
+ deleted_at = now()
+WHERE username = sqlc.arg('username') AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SearchUsers :many
+-- query is a tsquery, see SearchQuery.
+SELECT users.*, ts_rank(to_tsvector('simple', username || ' ' || full_name || ' ' || translate(email, '@.', '  ')), query)::real AS rank
+FROM users, to_tsquery('simple', sqlc.arg(query)) query
+WHERE deleted_at IS NULL
+  AND to_tsvector('simple', username || ' ' || full_name || ' ' || translate(email, '@.', '  ')) @@ query
+ORDER BY rank DESC, username
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: UpdateUserPreferences :one
+-- preferences replaces the whole document, see the preferences package.
+UPDATE users
+SET preferences = $2
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING preferences;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,72 @@
+-- name: CreateUserImport :one
+INSERT INTO user_imports (
+  created_by,
+  format
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetUserImport :one
+SELECT * FROM user_imports
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateUserImportFile :exec
+INSERT INTO user_import_files (
+  import_id,
+  content
+) VALUES (
+  $1, $2
+);
+
+-- name: GetUserImportFile :one
+SELECT content FROM user_import_files
+WHERE import_id = $1 LIMIT 1;
+
+-- name: CreateUserImportRow :one
+INSERT INTO user_import_rows (
+  import_id,
+  line,
+  username,
+  status,
+  error
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetLastUserImportLine :one
+-- the line of the last row of the import written, 0 before the first one.
+SELECT COALESCE(MAX(line), 0)::bigint FROM user_import_rows
+WHERE import_id = $1;
+
+-- name: ListUserImportRowsAfter :many
+-- the status matches every row when null.
+SELECT * FROM user_import_rows
+WHERE import_id = sqlc.arg(import_id) AND line > sqlc.arg(after_line)
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY line
+LIMIT sqlc.arg('limit');
+
+-- name: ListUserImportRowsBefore :many
+SELECT * FROM user_import_rows
+WHERE import_id = sqlc.arg(import_id) AND line < sqlc.arg(before_line)
+  AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY line DESC
+LIMIT sqlc.arg('limit');
+
+-- name: FinishUserImport :one
+-- completes a pending import, counting its rows.
+UPDATE user_imports
+SET
+  status = 'completed',
+  total_rows = (SELECT COUNT(*) FROM user_import_rows WHERE import_id = sqlc.arg(id)),
+  imported_rows = (SELECT COUNT(*) FROM user_import_rows WHERE import_id = sqlc.arg(id) AND status = 'imported'),
+  failed_rows = (SELECT COUNT(*) FROM user_import_rows WHERE import_id = sqlc.arg(id) AND status = 'failed'),
+  completed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: FailUserImport :one
+UPDATE user_imports
+SET status = 'failed', error = sqlc.arg(error), completed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
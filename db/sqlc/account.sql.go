@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 // source: account.sql
 
 package db
@@ -12,8 +12,8 @@ import (
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 set balance = balance + $1
-WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id
 `
 
 type AddAccountBalanceParams struct {
@@ -22,7 +22,7 @@ type AddAccountBalanceParams struct {
 }
 
 func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, addAccountBalance, arg.Amount, arg.ID)
+	row := q.db.QueryRow(ctx, addAccountBalance, arg.Amount, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -30,6 +30,44 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// holds amount more on the account, or releases it when negative.
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
 	)
 	return i, err
 }
@@ -38,10 +76,11 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  tenant_id
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, COALESCE((SELECT tenant_id FROM users WHERE username = $1), 'default')
+) RETURNING id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id
 `
 
 type CreateAccountParams struct {
@@ -50,8 +89,9 @@ type CreateAccountParams struct {
 	Currency string `json:"currency"`
 }
 
+// an account belongs to the tenant of its owner.
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,27 +99,91 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
+UPDATE accounts
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
 `
 
+// the row is kept, its entries and transfers still reference it.
 func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
+	_, err := q.db.Exec(ctx, deleteAccount, id)
 	return err
 }
 
+const freezeAccount = `-- name: FreezeAccount :one
+UPDATE accounts
+SET frozen_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id
+`
+
+func (q *Queries) FreezeAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRow(ctx, freezeAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
+	)
+	return i, err
+}
+
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE id = $1 LIMIT 1
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccount, id)
+	row := q.db.QueryRow(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE number = $1 AND tenant_id = $2 AND deleted_at IS NULL LIMIT 1
+`
+
+type GetAccountByNumberParams struct {
+	Number   string `json:"number"`
+	TenantID string `json:"tenant_id"`
+}
+
+// an account number only resolves within its tenant.
+func (q *Queries) GetAccountByNumber(ctx context.Context, arg GetAccountByNumberParams) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByNumber, arg.Number, arg.TenantID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -87,18 +191,49 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE id = $1 LIMIT 1
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	row := q.db.QueryRow(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getAccountIncludeDeleted = `-- name: GetAccountIncludeDeleted :one
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE id = $1 LIMIT 1
+`
+
+// for admins investigating an account that may have been deleted.
+func (q *Queries) GetAccountIncludeDeleted(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountIncludeDeleted, id)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -106,13 +241,19 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE owner = $1
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL
 ORDER BY id
 LIMIT $2
 OFFSET $3
@@ -125,7 +266,52 @@ type ListAccountsParams struct {
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.FrozenAt,
+			&i.Number,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsAfter = `-- name: ListAccountsAfter :many
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListAccountsAfterParams struct {
+	Owner   string `json:"owner"`
+	AfterID int64  `json:"after_id"`
+	Limit   int32  `json:"limit"`
+}
+
+func (q *Queries) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsAfter, arg.Owner, arg.AfterID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -139,25 +325,169 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.FrozenAt,
+			&i.Number,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsBefore = `-- name: ListAccountsBefore :many
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL AND id < $2
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListAccountsBeforeParams struct {
+	Owner    string `json:"owner"`
+	BeforeID int64  `json:"before_id"`
+	Limit    int32  `json:"limit"`
+}
+
+func (q *Queries) ListAccountsBefore(ctx context.Context, arg ListAccountsBeforeParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsBefore, arg.Owner, arg.BeforeID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.FrozenAt,
+			&i.Number,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsIncludeDeleted = `-- name: ListAccountsIncludeDeleted :many
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListAccountsIncludeDeletedParams struct {
+	Owner  string `json:"owner"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+// for admins investigating the accounts a user may have deleted.
+func (q *Queries) ListAccountsIncludeDeleted(ctx context.Context, arg ListAccountsIncludeDeletedParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsIncludeDeleted, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.FrozenAt,
+			&i.Number,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+const listAllAccounts = `-- name: ListAllAccounts :many
+SELECT id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id FROM accounts
+WHERE owner = $1 AND deleted_at IS NULL
+ORDER BY id
+`
+
+// every account of the owner, which has a handful at most.
+func (q *Queries) ListAllAccounts(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAllAccounts, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.FrozenAt,
+			&i.Number,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAccountBalance = `-- name: LockAccountBalance :exec
+SELECT pg_advisory_xact_lock($1::bigint)
+`
+
+// serializes the balance updates of the account until the transaction ends, see BalanceLockAdvisory.
+func (q *Queries) LockAccountBalance(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, lockAccountBalance, id)
+	return err
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 set balance = $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, owner, balance, currency, created_at, deleted_at, frozen_at, number, held_balance, available_balance, tenant_id
 `
 
 type UpdateAccountParams struct {
@@ -166,7 +496,7 @@ type UpdateAccountParams struct {
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+	row := q.db.QueryRow(ctx, updateAccount, arg.ID, arg.Balance)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -174,6 +504,12 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.FrozenAt,
+		&i.Number,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.TenantID,
 	)
 	return i, err
 }
@@ -2,19 +2,24 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/testfixtures"
 	"github.com/backendmaster/simple_bank/util"
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomAccount(t *testing.T) Account {
-	user := createRandomUser(t)
+// createRandomAccount creates an account of a new user, unless testfixtures.WithOwner is given.
+func createRandomAccount(t testing.TB, options ...testfixtures.AccountOption) Account {
+	fixture := testfixtures.NewAccount(append([]testfixtures.AccountOption{testfixtures.WithOwner("")}, options...)...)
+	if fixture.Owner == "" {
+		fixture.Owner = createRandomUser(t).Username
+	}
 	args := CreateAccountParams{
-		Owner:    user.Username,
-		Balance:  util.RandomBalance(),
-		Currency: util.RandomCurrency(),
+		Owner:    fixture.Owner,
+		Balance:  fixture.Balance,
+		Currency: fixture.Currency,
 	}
 
 	account, err := testQuires.CreateAccount(context.Background(), args)
@@ -32,6 +37,33 @@ func TestCreateAccount(t *testing.T) {
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
+	require.True(t, util.IsValidAccountNumber(account.Number))
+}
+
+func TestGetAccountByNumber(t *testing.T) {
+	account := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	require.NotEqual(t, account.Number, account2.Number)
+
+	arg := GetAccountByNumberParams{
+		Number:   account.Number,
+		TenantID: account.TenantID,
+	}
+	found, err := testQuires.GetAccountByNumber(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, found.ID)
+
+	// the number doesn't resolve in another tenant
+	_, err = testQuires.GetAccountByNumber(context.Background(), GetAccountByNumberParams{
+		Number:   account.Number,
+		TenantID: util.RandomOwnerName(),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testQuires.DeleteAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	_, err = testQuires.GetAccountByNumber(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
 }
 
 func TestGetAccount(t *testing.T) {
@@ -77,8 +109,20 @@ func TestDeleteAccount(t *testing.T) {
 	account2, err := testQuires.GetAccount(context.Background(), account.ID)
 
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.EqualError(t, err, ErrRecordNotFound.Error())
 	require.Empty(t, account2)
+
+	account3, err := testQuires.GetAccountIncludeDeleted(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, account3.ID)
+	require.True(t, account3.DeletedAt.Valid)
+
+	// the owner can open another account in the same currency
+	_, err = testQuires.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
 }
 
 func TestListAccounts(t *testing.T) {
@@ -102,3 +146,53 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
+
+func TestListAllAccounts(t *testing.T) {
+	user := createRandomUser(t)
+	var accounts []Account
+	for _, currency := range []string{util.USD, util.EUR, util.CAD} {
+		account, err := testQuires.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+	require.NoError(t, testQuires.DeleteAccount(context.Background(), accounts[1].ID))
+
+	all, err := testQuires.ListAllAccounts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, []Account{accounts[0], accounts[2]}, all)
+}
+
+func TestListAccountsPage(t *testing.T) {
+	user := createRandomUser(t)
+	var accounts []Account
+	for _, currency := range []string{util.USD, util.EUR, util.CAD} {
+		account, err := testQuires.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	page, err := ListAccountsPage(context.Background(), testQuires, user.Username, pagination.Cursor{}, 2)
+	require.NoError(t, err)
+	require.Equal(t, accounts[:2], page.Items)
+	require.Empty(t, page.PrevCursor)
+
+	next, err := pagination.Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = ListAccountsPage(context.Background(), testQuires, user.Username, next, 2)
+	require.NoError(t, err)
+	require.Equal(t, accounts[2:], page.Items)
+	require.Empty(t, page.NextCursor)
+
+	prev, err := pagination.Decode(page.PrevCursor)
+	require.NoError(t, err)
+	page, err = ListAccountsPage(context.Background(), testQuires, user.Username, prev, 2)
+	require.NoError(t, err)
+	require.Equal(t, accounts[:2], page.Items)
+	require.Empty(t, page.PrevCursor)
+}
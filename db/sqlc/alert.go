@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	AlertLowBalance       = "low_balance"
+	AlertLargeTransaction = "large_transaction"
+)
+
+// TriggeredAlert is an alert rule of an account that a transfer set off.
+type TriggeredAlert struct {
+	Rule string
+	// Account is the debited account, with its balance after the transfer.
+	Account   Account
+	Amount    int64
+	Threshold int64
+}
+
+// AlertTaskFunc turns a triggered alert into the outbox task that notifies the owner of the account.
+type AlertTaskFunc func(alert TriggeredAlert) (CreateOutboxTaskParams, error)
+
+// checkAlerts evaluates the alert rules of account, debited by amount. The low balance alert only
+// goes off when the balance crosses the threshold, not on every transfer of an account already below it.
+func checkAlerts(ctx context.Context, q *Queries, account Account, amount int64) ([]TriggeredAlert, error) {
+	alert, err := q.GetAccountAlert(ctx, account.ID)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var triggered []TriggeredAlert
+	if alert.LowBalanceThreshold.Valid {
+		threshold := alert.LowBalanceThreshold.Int64
+		if account.Balance < threshold && account.Balance+amount >= threshold {
+			triggered = append(triggered, TriggeredAlert{Rule: AlertLowBalance, Account: account, Amount: amount, Threshold: threshold})
+		}
+	}
+	if alert.LargeDebitThreshold.Valid && amount > alert.LargeDebitThreshold.Int64 {
+		triggered = append(triggered, TriggeredAlert{
+			Rule:      AlertLargeTransaction,
+			Account:   account,
+			Amount:    amount,
+			Threshold: alert.LargeDebitThreshold.Int64,
+		})
+	}
+	return triggered, nil
+}
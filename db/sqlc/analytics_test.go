@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumEntriesByCategory(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+	start := time.Now()
+
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 20},
+		{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 5},
+		{FromAccountID: account3.ID, ToAccountID: account1.ID, Amount: 7},
+	} {
+		_, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+	result, err := store.TransferTx(context.Background(), TransferTxParams{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 3})
+	require.NoError(t, err)
+	_, err = testQuires.SetEntryCategory(context.Background(), SetEntryCategoryParams{
+		EntryID:   result.FromEntry.ID,
+		AccountID: account1.ID,
+		Category:  "groceries",
+		Tags:      []string{},
+	})
+	require.NoError(t, err)
+
+	categories, err := testQuires.SumEntriesByCategory(context.Background(), SumEntriesByCategoryParams{
+		AccountID: account1.ID,
+		FromTime:  start.Add(-time.Minute),
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []SumEntriesByCategoryRow{
+		{Category: "", Direction: DirectionCredit, Count: 1, Total: 7},
+		{Category: "", Direction: DirectionDebit, Count: 3, Total: 35},
+		{Category: "groceries", Direction: DirectionDebit, Count: 1, Total: 3},
+	}, categories)
+
+	counterparties, err := testQuires.SumTransfersByCounterparty(context.Background(), SumTransfersByCounterpartyParams{
+		AccountID: account1.ID,
+		FromTime:  start.Add(-time.Minute),
+		ToTime:    time.Now().Add(time.Minute),
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []SumTransfersByCounterpartyRow{
+		{CounterpartyAccountID: account2.ID, Direction: DirectionDebit, Count: 2, Total: 30},
+		{CounterpartyAccountID: account3.ID, Direction: DirectionDebit, Count: 2, Total: 8},
+		{CounterpartyAccountID: account3.ID, Direction: DirectionCredit, Count: 1, Total: 7},
+	}, counterparties)
+}
+
+func TestSumEntriesByDay(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	start := time.Now()
+
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 4},
+	} {
+		_, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	location, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	days, err := testQuires.SumEntriesByDay(context.Background(), SumEntriesByDayParams{
+		Timezone:  location.String(),
+		AccountID: account1.ID,
+		FromTime:  start.Add(-time.Minute),
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	// the day is that of the clock of the timezone, which is ahead of UTC by 14 hours
+	require.Equal(t, time.Now().In(location).Format("2006-01-02"), days[0].Day)
+	require.Equal(t, int64(10), days[0].Debit)
+	require.Equal(t, int64(4), days[0].Credit)
+
+	moved, err := testQuires.SumEntriesSince(context.Background(), SumEntriesSinceParams{
+		AccountID: account1.ID,
+		FromTime:  start.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-6), moved)
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// The transactions of the operations of the admins. Each writes its Audit entry along with the
+// operation, so that no operation is left without one.
+
+type FreezeAccountTxParams struct {
+	AccountID int64
+	Audit     CreateAuditEntryParams
+}
+
+// FreezeAccountTx freezes the account along with its account.frozen event, failing with
+// ErrAccountFrozen when it's frozen already.
+func (store *SQLStore) FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, "FreezeAccountTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		// locked, so that two admins can't both freeze it
+		account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.FrozenAt.Valid {
+			return ErrAccountFrozen
+		}
+
+		account, err = q.FreezeAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if _, err := q.CreateAuditEntry(ctx, arg.Audit); err != nil {
+			return err
+		}
+		return createAccountFrozenEvent(ctx, q, account, arg.Audit.Reason)
+	})
+	return account, err
+}
+
+type AdjustBalanceTxParams struct {
+	AccountID int64
+	// Amount is credited to the account, or debited when negative.
+	Amount int64
+	Audit  CreateAuditEntryParams
+	// AfterAdjust returns the outbox tasks to write with the adjustment, e.g. to notify the owner
+	// of the account.
+	AfterAdjust func(result AdjustBalanceTxResult) ([]CreateOutboxTaskParams, error)
+}
+
+type AdjustBalanceTxResult struct {
+	Account Account `json:"account"`
+	Entry   Entry   `json:"entry"`
+}
+
+// AdjustBalanceTx corrects the balance of an account with an entry of amount, which keeps the
+// ledger balanced, serializing the update with the transfers like TransferTx does.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	err := store.execTx(ctx, "AdjustBalanceTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		if store.balanceLock == BalanceLockAdvisory {
+			if err := q.LockAccountBalance(ctx, arg.AccountID); err != nil {
+				return err
+			}
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err = q.CreateAuditEntry(ctx, arg.Audit); err != nil {
+			return err
+		}
+		if arg.AfterAdjust == nil {
+			return nil
+		}
+		tasks, err := arg.AfterAdjust(result)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return result, err
+}
+
+type RevokeSessionsTxParams struct {
+	Username string
+	Audit    CreateAuditEntryParams
+}
+
+// RevokeSessionsTx blocks all the sessions of the user, so that their refresh tokens can't renew
+// an access token anymore.
+func (store *SQLStore) RevokeSessionsTx(ctx context.Context, arg RevokeSessionsTxParams) error {
+	return store.execTx(ctx, "RevokeSessionsTx", func(ctx context.Context, q *Queries) error {
+		if err := q.BlockUserSessions(ctx, arg.Username); err != nil {
+			return err
+		}
+
+		_, err := q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+}
+
+type CreateImpersonationTxParams struct {
+	CreateImpersonationParams
+	Audit CreateAuditEntryParams
+}
+
+// CreateImpersonationTx records the impersonation of a user by an admin, the id being the one of
+// its token.
+func (store *SQLStore) CreateImpersonationTx(ctx context.Context, arg CreateImpersonationTxParams) (Impersonation, error) {
+	var impersonation Impersonation
+
+	err := store.execTx(ctx, "CreateImpersonationTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		impersonation, err = q.CreateImpersonation(ctx, arg.CreateImpersonationParams)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return impersonation, err
+}
+
+type RevokeImpersonationTxParams struct {
+	ID    uuid.UUID
+	Audit CreateAuditEntryParams
+}
+
+// RevokeImpersonationTx revokes an impersonation, whose token is refused from then on, see
+// CheckImpersonation.
+func (store *SQLStore) RevokeImpersonationTx(ctx context.Context, arg RevokeImpersonationTxParams) (Impersonation, error) {
+	var impersonation Impersonation
+
+	err := store.execTx(ctx, "RevokeImpersonationTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		impersonation, err = q.RevokeImpersonation(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return impersonation, err
+}
+
+type CreateOutboxTasksTxParams struct {
+	OutboxTasks []CreateOutboxTaskParams
+	Audit       CreateAuditEntryParams
+}
+
+// CreateOutboxTasksTx stores outbox tasks an admin asked for, e.g. a verification email sent again.
+func (store *SQLStore) CreateOutboxTasksTx(ctx context.Context, arg CreateOutboxTasksTxParams) error {
+	return store.execTx(ctx, "CreateOutboxTasksTx", func(ctx context.Context, q *Queries) error {
+		if err := createOutboxTasks(ctx, q, arg.OutboxTasks); err != nil {
+			return err
+		}
+
+		_, err := q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+}
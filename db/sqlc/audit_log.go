@@ -0,0 +1,168 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// auditChainBatchSize is how many audit logs VerifyAuditLogChain reads at a time.
+	auditChainBatchSize = 500
+	// auditChainReportLimit caps the problems a report lists.
+	auditChainReportLimit = 1000
+)
+
+// AuditLogHash hashes the audit log chained to prevHash, the hash of the audit log before it. The
+// id isn't hashed, so the hash can be taken before the insert.
+func AuditLogHash(prevHash []byte, log AuditLog) []byte {
+	// the fields in a fixed order, with the time at the precision postgres keeps
+	fields := []interface{}{
+		log.Actor,
+		log.Protocol,
+		log.Action,
+		log.Path,
+		log.StatusCode,
+		log.ClientIp,
+		log.UserAgent,
+		log.RequestID,
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	// only when set, so that the audit logs hashed before it existed still match
+	if log.Impersonator != "" {
+		fields = append(fields, log.Impersonator)
+	}
+	content, _ := json.Marshal(fields)
+
+	hash := sha256.New()
+	hash.Write(prevHash)
+	hash.Write(content)
+	return hash.Sum(nil)
+}
+
+// CreateAuditLogTx writes the audit log chained to the last one. The chain is locked until the
+// transaction ends, so that two audit logs can't be chained to the same one.
+func (store *SQLStore) CreateAuditLogTx(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error) {
+	var log AuditLog
+
+	err := store.execTx(ctx, "CreateAuditLogTx", func(ctx context.Context, q *Queries) error {
+		if err := q.LockAuditLogChain(ctx); err != nil {
+			return err
+		}
+		prevHash, err := q.GetLastAuditLogHash(ctx)
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+		// the first audit log, which isn't a NULL
+		if prevHash == nil {
+			prevHash = []byte{}
+		}
+
+		arg.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		arg.PrevHash = prevHash
+		arg.Hash = AuditLogHash(prevHash, AuditLog{
+			Actor:        arg.Actor,
+			Protocol:     arg.Protocol,
+			Action:       arg.Action,
+			Path:         arg.Path,
+			StatusCode:   arg.StatusCode,
+			ClientIp:     arg.ClientIp,
+			UserAgent:    arg.UserAgent,
+			RequestID:    arg.RequestID,
+			CreatedAt:    arg.CreatedAt,
+			Impersonator: arg.Impersonator,
+		})
+		log, err = q.CreateAuditLog(ctx, arg)
+		return err
+	})
+	return log, err
+}
+
+// AuditChainProblem is an audit log that breaks the chain.
+type AuditChainProblem struct {
+	ID      int64  `json:"id"`
+	Problem string `json:"problem"`
+}
+
+// AuditChainReport lists the problems found by VerifyAuditLogChain.
+type AuditChainReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	// Checked counts the audit logs of the chain, and Unchained the ones written before it.
+	Checked   int64               `json:"checked"`
+	Unchained int64               `json:"unchained"`
+	HeadID    int64               `json:"head_id"`
+	HeadHash  string              `json:"head_hash"`
+	Problems  []AuditChainProblem `json:"problems"`
+}
+
+// OK tells whether the chain is intact.
+func (report AuditChainReport) OK() bool {
+	return len(report.Problems) == 0
+}
+
+func (report *AuditChainReport) addProblem(id int64, problem string) {
+	if len(report.Problems) < auditChainReportLimit {
+		report.Problems = append(report.Problems, AuditChainProblem{ID: id, Problem: problem})
+	}
+}
+
+// VerifyAuditLogChain recomputes the hash of every audit log of the chain and checks it's chained
+// to the one before it, reading a single snapshot. Each audit log is checked against the hashes
+// stored, so that one changed audit log is reported once rather than breaking all that follow.
+// HeadHash is worth keeping outside the database: the chain can't tell when it's rewritten whole.
+func (store *SQLStore) VerifyAuditLogChain(ctx context.Context) (AuditChainReport, error) {
+	report := AuditChainReport{CheckedAt: time.Now()}
+
+	tx, err := store.connPool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return report, err
+	}
+	// nothing to commit in a read-only transaction
+	defer tx.Rollback(context.Background())
+
+	q := New(newInstrumentedDB(tx))
+	var prevHash []byte
+	chained := false
+	arg := ListAuditLogsAfterParams{Limit: auditChainBatchSize}
+	for {
+		logs, err := q.ListAuditLogsAfter(ctx, arg)
+		if err != nil {
+			return report, err
+		}
+
+		for _, log := range logs {
+			switch {
+			case len(log.Hash) == 0 && !chained:
+				report.Unchained++
+				continue
+			case len(log.Hash) == 0:
+				report.addProblem(log.ID, "hash is missing")
+			// the first audit log of the chain has an empty prev_hash
+			case !bytes.Equal(log.PrevHash, prevHash):
+				report.addProblem(log.ID, "prev_hash doesn't match the hash of the audit log before it")
+			case !bytes.Equal(log.Hash, AuditLogHash(log.PrevHash, log)):
+				report.addProblem(log.ID, "hash doesn't match the content of the audit log")
+			}
+
+			chained = true
+			prevHash = log.Hash
+			report.Checked++
+			report.HeadID = log.ID
+			report.HeadHash = hex.EncodeToString(log.Hash)
+		}
+
+		if len(logs) < int(arg.Limit) {
+			return report, nil
+		}
+		arg.AfterID = logs[len(logs)-1].ID
+	}
+}
@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAuditEntry(target string) CreateAuditEntryParams {
+	return CreateAuditEntryParams{
+		Actor:   util.RandomOwnerName(),
+		Action:  util.RandomString(8),
+		Target:  target,
+		Reason:  util.RandomString(12),
+		Details: []byte(`{"amount":10}`),
+	}
+}
+
+func TestFreezeAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	target := "account:" + util.RandomString(10)
+
+	frozen, err := store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID: account1.ID,
+		Audit:     randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.True(t, frozen.FrozenAt.Valid)
+
+	_, err = store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID: account1.ID,
+		Audit:     randomAuditEntry(target),
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// only the first freeze is audited
+	entries, err := store.ListAuditEntries(context.Background(), ListAuditEntriesParams{Target: target, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.JSONEq(t, `{"amount":10}`, string(entries[0].Details))
+
+	// a frozen account can neither send nor receive
+	for _, arg := range []TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 10},
+	} {
+		_, err = store.TransferTx(context.Background(), arg)
+		require.ErrorIs(t, err, ErrAccountFrozen)
+	}
+	account, err := testQuires.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, account.Balance)
+}
+
+func TestAdjustBalanceTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	target := "account:" + util.RandomString(10)
+
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    -10,
+		Audit:     randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-10, result.Account.Balance)
+	require.Equal(t, int64(-10), result.Entry.Amount)
+	require.Equal(t, account.ID, result.Entry.AccountID)
+
+	entries, err := store.ListAuditEntries(context.Background(), ListAuditEntriesParams{Target: target, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestAdjustBalanceTxAfterAdjust(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	arg := AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    10,
+		Audit:     randomAuditEntry("account:" + util.RandomString(10)),
+	}
+
+	// a failing callback rolls the adjustment back
+	callbackErr := errors.New("callback failed")
+	arg.AfterAdjust = func(result AdjustBalanceTxResult) ([]CreateOutboxTaskParams, error) {
+		require.Equal(t, account.Balance+10, result.Account.Balance)
+		return nil, callbackErr
+	}
+	_, err := store.AdjustBalanceTx(context.Background(), arg)
+	require.ErrorIs(t, err, callbackErr)
+	unchanged, err := testQuires.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, unchanged.Balance)
+
+	task := CreateOutboxTaskParams{
+		TaskType: "task:test_" + account.Owner,
+		Payload:  json.RawMessage(fmt.Sprintf(`{"account_id":%d}`, account.ID)),
+		Queue:    "default",
+		MaxRetry: 5,
+	}
+	arg.AfterAdjust = func(result AdjustBalanceTxResult) ([]CreateOutboxTaskParams, error) {
+		return []CreateOutboxTaskParams{task}, nil
+	}
+	_, err = store.AdjustBalanceTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	found := false
+	_, err = store.PublishOutboxTx(context.Background(), 1000, func(outbox Outbox) error {
+		if outbox.TaskType == task.TaskType {
+			found = true
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func createRandomAuditLog(t *testing.T, actor string) AuditLog {
+	arg := CreateAuditLogParams{
+		Actor:      actor,
+		Protocol:   "http",
+		Action:     "POST /transfers",
+		Path:       "/transfers",
+		StatusCode: 200,
+		ClientIp:   "10.0.0.1",
+		UserAgent:  "curl/8.0",
+		RequestID:  util.RandomString(12),
+	}
+	log, err := NewStore(testDB).CreateAuditLogTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Actor, log.Actor)
+	require.Equal(t, arg.RequestID, log.RequestID)
+	require.NotZero(t, log.CreatedAt)
+	require.Equal(t, AuditLogHash(log.PrevHash, log), log.Hash)
+	return log
+}
+
+func TestListAuditLogsPage(t *testing.T) {
+	store := NewStore(testDB)
+	actor := util.RandomOwnerName()
+	var logs []AuditLog
+	for i := 0; i < 3; i++ {
+		logs = append(logs, createRandomAuditLog(t, actor))
+		createRandomAuditLog(t, util.RandomOwnerName())
+	}
+
+	filter := AuditLogFilter{Actor: pgtype.Text{String: actor, Valid: true}}
+	page, err := ListAuditLogsPage(context.Background(), store, filter, pagination.Cursor{}, 2)
+	require.NoError(t, err)
+	require.Equal(t, logs[:2], page.Items)
+	require.NotEmpty(t, page.NextCursor)
+
+	cursor, err := pagination.Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = ListAuditLogsPage(context.Background(), store, filter, cursor, 2)
+	require.NoError(t, err)
+	require.Equal(t, logs[2:], page.Items)
+	require.Empty(t, page.NextCursor)
+
+	filter.FromTime = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	page, err = ListAuditLogsPage(context.Background(), store, filter, pagination.Cursor{}, 2)
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+}
+
+func TestAuditLogsAppendOnly(t *testing.T) {
+	log := createRandomAuditLog(t, util.RandomOwnerName())
+
+	_, err := testDB.Exec(context.Background(), "UPDATE audit_logs SET actor = 'someone else' WHERE id = $1", log.ID)
+	require.ErrorContains(t, err, "append-only")
+	_, err = testDB.Exec(context.Background(), "DELETE FROM audit_logs WHERE id = $1", log.ID)
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestAuditLogHashChain(t *testing.T) {
+	log1 := createRandomAuditLog(t, util.RandomOwnerName())
+	log2 := createRandomAuditLog(t, util.RandomOwnerName())
+	require.Equal(t, log1.Hash, log2.PrevHash)
+	require.NotEqual(t, log1.Hash, log2.Hash)
+
+	changed := log2
+	changed.Actor = "someone else"
+	require.NotEqual(t, log2.Hash, AuditLogHash(changed.PrevHash, changed))
+	changed = log2
+	changed.CreatedAt = changed.CreatedAt.Add(time.Microsecond)
+	require.NotEqual(t, log2.Hash, AuditLogHash(changed.PrevHash, changed))
+	changed = log2
+	changed.Impersonator = "admin"
+	require.NotEqual(t, log2.Hash, AuditLogHash(changed.PrevHash, changed))
+}
+
+func TestVerifyAuditLogChain(t *testing.T) {
+	store := NewStore(testDB)
+	log := createRandomAuditLog(t, util.RandomOwnerName())
+
+	report, err := store.VerifyAuditLogChain(context.Background())
+	require.NoError(t, err)
+	require.True(t, report.OK(), report.Problems)
+	require.NotZero(t, report.Checked)
+	require.GreaterOrEqual(t, report.HeadID, log.ID)
+	require.NotEmpty(t, report.HeadHash)
+}
+
+func TestImpersonationTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	target := "user:" + user.Username
+
+	impersonation, err := store.CreateImpersonationTx(context.Background(), CreateImpersonationTxParams{
+		CreateImpersonationParams: CreateImpersonationParams{
+			ID:        uuid.New(),
+			Admin:     util.RandomOwnerName(),
+			Username:  user.Username,
+			Reason:    "ticket 1234",
+			ExpiresAt: time.Now().Add(time.Minute),
+		},
+		Audit: randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.False(t, impersonation.RevokedAt.Valid)
+	require.NoError(t, CheckImpersonation(context.Background(), store, impersonation.ID))
+
+	revoked, err := store.RevokeImpersonationTx(context.Background(), RevokeImpersonationTxParams{
+		ID:    impersonation.ID,
+		Audit: randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+	require.ErrorIs(t, CheckImpersonation(context.Background(), store, impersonation.ID), ErrImpersonationRevoked)
+	require.ErrorIs(t, CheckImpersonation(context.Background(), store, uuid.New()), ErrImpersonationRevoked)
+
+	// revoking again keeps the first revocation
+	again, err := testQuires.RevokeImpersonation(context.Background(), impersonation.ID)
+	require.NoError(t, err)
+	require.Equal(t, revoked.RevokedAt, again.RevokedAt)
+
+	entries, err := store.ListAuditEntries(context.Background(), ListAuditEntriesParams{Target: target, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
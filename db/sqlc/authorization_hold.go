@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of an authorization hold. An active hold keeps its amount out of the available
+// balance of the payer until the payee captures it, the payee or the payer releases it, or it
+// expires.
+const (
+	AuthorizationHoldActive   = "active"
+	AuthorizationHoldCaptured = "captured"
+	AuthorizationHoldReleased = "released"
+	AuthorizationHoldExpired  = "expired"
+)
+
+var (
+	// ErrAuthorizationHoldClosed is returned for a hold already captured, released or expired.
+	ErrAuthorizationHoldClosed = errors.New("authorization hold is closed or expired")
+	// ErrInsufficientFunds is returned by PlaceAuthorizationHoldTx for a hold over the available
+	// balance of the account.
+	ErrInsufficientFunds = errors.New("insufficient available balance")
+	// ErrInvalidCaptureAmount is returned by CaptureAuthorizationHoldTx for an amount over the held
+	// one.
+	ErrInvalidCaptureAmount = errors.New("capture amount must be between 1 and the held amount")
+)
+
+type PlaceAuthorizationHoldTxResult struct {
+	Hold AuthorizationHold `json:"hold"`
+	// Account is the account of the payer, its available balance less the held amount.
+	Account Account `json:"account"`
+}
+
+// PlaceAuthorizationHoldTx holds an amount on an account for a payee: the balance of the account
+// doesn't move, but its available balance goes down by the amount until the hold is captured or
+// released. The hold can't take the available balance below zero, nor below the amount held by
+// disputes.
+func (store *SQLStore) PlaceAuthorizationHoldTx(ctx context.Context, arg CreateAuthorizationHoldParams) (PlaceAuthorizationHoldTxResult, error) {
+	var result PlaceAuthorizationHoldTxResult
+
+	err := store.execTx(ctx, "PlaceAuthorizationHoldTx", func(ctx context.Context, q *Queries) error {
+		if store.balanceLock == BalanceLockAdvisory {
+			if err := q.LockAccountBalance(ctx, arg.AccountID); err != nil {
+				return err
+			}
+		}
+		// locks the account like a transfer debiting it, which sees the hold or runs first
+		account, err := q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		if account.FrozenAt.Valid {
+			return ErrAccountFrozen
+		}
+		disputed, err := q.GetHeldAmount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		if account.AvailableBalance < disputed {
+			return ErrInsufficientFunds
+		}
+		result.Account = account
+
+		result.Hold, err = q.CreateAuthorizationHold(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+type CaptureAuthorizationHoldTxParams struct {
+	ID int64
+	// Amount is what the payee takes, at most the held amount. The rest goes back to the payer.
+	Amount int64
+	// AlertTask is that of the transfer of the captured amount, see TransferTxParams.
+	AlertTask AlertTaskFunc
+}
+
+type CaptureAuthorizationHoldTxResult struct {
+	Hold     AuthorizationHold `json:"hold"`
+	Transfer TransferTxResult  `json:"transfer"`
+}
+
+// CaptureAuthorizationHoldTx closes an active hold by transferring the captured amount from the
+// payer to the payee, and releases the whole held amount. The transfer is refused like TransferTx
+// refuses it, e.g. for a frozen account, in which case the hold stays active.
+func (store *SQLStore) CaptureAuthorizationHoldTx(ctx context.Context, arg CaptureAuthorizationHoldTxParams) (CaptureAuthorizationHoldTxResult, error) {
+	var result CaptureAuthorizationHoldTxResult
+
+	err := store.execTx(ctx, "CaptureAuthorizationHoldTx", func(ctx context.Context, q *Queries) error {
+		hold, err := getActiveAuthorizationHold(ctx, q, arg.ID)
+		if err != nil {
+			return err
+		}
+		if arg.Amount <= 0 || arg.Amount > hold.Amount {
+			return ErrInvalidCaptureAmount
+		}
+
+		// the accounts are locked in the order of the transfer before the held balance of the
+		// payer is released, so that a transfer between them can't wait on this one
+		if err := store.lockTransferAccounts(ctx, q, hold.AccountID, hold.ToAccountID); err != nil {
+			return err
+		}
+		_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		memo := hold.Memo
+		if memo == "" {
+			memo = fmt.Sprintf("capture of authorization hold %d", hold.ID)
+		}
+		result.Transfer, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        arg.Amount,
+			Memo:          memo,
+			AlertTask:     arg.AlertTask,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CloseAuthorizationHold(ctx, CloseAuthorizationHoldParams{
+			ID:             hold.ID,
+			Status:         AuthorizationHoldCaptured,
+			CapturedAmount: arg.Amount,
+			TransferID:     pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+	return result, err
+}
+
+type ReleaseAuthorizationHoldTxParams struct {
+	ID int64
+	// Status is released, or expired for a hold past its expiry.
+	Status string
+}
+
+// ReleaseAuthorizationHoldTx closes an active hold without moving any money, its amount going
+// back to the available balance of the payer.
+func (store *SQLStore) ReleaseAuthorizationHoldTx(ctx context.Context, arg ReleaseAuthorizationHoldTxParams) (AuthorizationHold, error) {
+	var hold AuthorizationHold
+
+	if arg.Status != AuthorizationHoldReleased && arg.Status != AuthorizationHoldExpired {
+		return hold, fmt.Errorf("can't release an authorization hold as %q", arg.Status)
+	}
+
+	err := store.execTx(ctx, "ReleaseAuthorizationHoldTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		hold, err = q.GetAuthorizationHoldForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if hold.Status != AuthorizationHoldActive {
+			return ErrAuthorizationHoldClosed
+		}
+
+		if store.balanceLock == BalanceLockAdvisory {
+			if err := q.LockAccountBalance(ctx, hold.AccountID); err != nil {
+				return err
+			}
+		}
+		_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.CloseAuthorizationHold(ctx, CloseAuthorizationHoldParams{
+			ID:     hold.ID,
+			Status: arg.Status,
+		})
+		return err
+	})
+	return hold, err
+}
+
+// getActiveAuthorizationHold locks a hold that can still be captured, i.e. active and not past
+// its expiry, which the worker may not have released yet.
+func getActiveAuthorizationHold(ctx context.Context, q *Queries, id int64) (AuthorizationHold, error) {
+	hold, err := q.GetAuthorizationHoldForUpdate(ctx, id)
+	if err != nil {
+		return hold, err
+	}
+	if hold.Status != AuthorizationHoldActive || !hold.ExpiresAt.After(time.Now()) {
+		return hold, ErrAuthorizationHoldClosed
+	}
+	return hold, nil
+}
+
+// lockTransferAccounts locks the accounts of a transfer ahead of it, the way the transfer itself
+// locks them: the lowest id first, with the advisory locks of the balance lock or their rows. A
+// transaction moving money through more than two accounts, like an exchange through the fx
+// accounts, locks them all at once, since two of them crossing the same accounts in opposite
+// directions would otherwise take the locks of their transfers in opposite orders.
+func (store *SQLStore) lockTransferAccounts(ctx context.Context, q *Queries, accountIDs ...int64) error {
+	ids := append([]int64(nil), accountIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		var err error
+		if store.balanceLock == BalanceLockAdvisory {
+			err = q.LockAccountBalance(ctx, id)
+		} else {
+			_, err = q.GetAccountForUpdate(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func placeHold(t *testing.T, store Store, from, to Account, amount int64) PlaceAuthorizationHoldTxResult {
+	result, err := store.PlaceAuthorizationHoldTx(context.Background(), CreateAuthorizationHoldParams{
+		AccountID:   from.ID,
+		ToAccountID: to.ID,
+		Amount:      amount,
+		Currency:    from.Currency,
+		Memo:        "deposit",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, AuthorizationHoldActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	return result
+}
+
+func TestCaptureAuthorizationHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+
+	placed := placeHold(t, store, payer, payee, 60)
+	require.Equal(t, int64(100), placed.Account.Balance)
+	require.Equal(t, int64(60), placed.Account.HeldBalance)
+	require.Equal(t, int64(40), placed.Account.AvailableBalance)
+
+	// the held amount can't be spent by a transfer nor held twice
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        50,
+	})
+	require.ErrorIs(t, err, ErrFundsHeld)
+	_, err = store.PlaceAuthorizationHoldTx(context.Background(), CreateAuthorizationHoldParams{
+		AccountID:   payer.ID,
+		ToAccountID: payee.ID,
+		Amount:      50,
+		Currency:    util.USD,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.CaptureAuthorizationHoldTx(context.Background(), CaptureAuthorizationHoldTxParams{ID: placed.Hold.ID, Amount: 61})
+	require.ErrorIs(t, err, ErrInvalidCaptureAmount)
+
+	captured, err := store.CaptureAuthorizationHoldTx(context.Background(), CaptureAuthorizationHoldTxParams{ID: placed.Hold.ID, Amount: 45})
+	require.NoError(t, err)
+	require.Equal(t, AuthorizationHoldCaptured, captured.Hold.Status)
+	require.Equal(t, int64(45), captured.Hold.CapturedAmount)
+	require.Equal(t, captured.Transfer.Transfer.ID, captured.Hold.TransferID.Int64)
+	require.True(t, captured.Hold.ClosedAt.Valid)
+	require.Equal(t, int64(55), captured.Transfer.FromAccount.Balance)
+	require.Zero(t, captured.Transfer.FromAccount.HeldBalance)
+	require.Equal(t, int64(55), captured.Transfer.FromAccount.AvailableBalance)
+	require.Equal(t, int64(45), captured.Transfer.ToAccount.Balance)
+
+	_, err = store.CaptureAuthorizationHoldTx(context.Background(), CaptureAuthorizationHoldTxParams{ID: placed.Hold.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrAuthorizationHoldClosed)
+}
+
+func TestReleaseAuthorizationHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(100))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.EUR))
+
+	placed := placeHold(t, store, payer, payee, 30)
+
+	holds, err := testQuires.ListAccountAuthorizationHolds(context.Background(), ListAccountAuthorizationHoldsParams{
+		AccountID: payee.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+	require.Equal(t, placed.Hold.ID, holds[0].ID)
+
+	released, err := store.ReleaseAuthorizationHoldTx(context.Background(), ReleaseAuthorizationHoldTxParams{
+		ID:     placed.Hold.ID,
+		Status: AuthorizationHoldReleased,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AuthorizationHoldReleased, released.Status)
+	require.Zero(t, released.CapturedAmount)
+	require.False(t, released.TransferID.Valid)
+
+	account, err := testQuires.GetAccount(context.Background(), payer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+	require.Zero(t, account.HeldBalance)
+	require.Equal(t, int64(100), account.AvailableBalance)
+
+	_, err = store.ReleaseAuthorizationHoldTx(context.Background(), ReleaseAuthorizationHoldTxParams{
+		ID:     placed.Hold.ID,
+		Status: AuthorizationHoldExpired,
+	})
+	require.ErrorIs(t, err, ErrAuthorizationHoldClosed)
+}
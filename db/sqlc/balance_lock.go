@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// BalanceLock is how TransferTx serializes the transfers that update the same account balance.
+type BalanceLock string
+
+const (
+	// BalanceLockRow relies on the row locks the balance updates take, in the order of the account ids.
+	BalanceLockRow BalanceLock = "row"
+	// BalanceLockAdvisory takes an advisory lock on both accounts, in the order of their ids, before
+	// the transaction writes anything. The transfers of a hot account then queue on the advisory
+	// lock holding nothing else, instead of queueing on its row with their transfer and entries
+	// already inserted, and the waiters don't pile up into tuple locks.
+	BalanceLockAdvisory BalanceLock = "advisory"
+)
+
+// ParseBalanceLock parses the DB_BALANCE_LOCK setting, row when it is empty.
+func ParseBalanceLock(s string) (BalanceLock, error) {
+	switch lock := BalanceLock(s); lock {
+	case "":
+		return BalanceLockRow, nil
+	case BalanceLockRow, BalanceLockAdvisory:
+		return lock, nil
+	default:
+		return "", fmt.Errorf("unknown balance lock %q", s)
+	}
+}
+
+// lockBalances takes the advisory locks of the accounts of a transfer, the lowest id first
+// like addMoney so that two transfers between the same accounts can't wait on each other.
+func lockBalances(ctx context.Context, q *Queries, accountID1, accountID2 int64) error {
+	if accountID1 > accountID2 {
+		accountID1, accountID2 = accountID2, accountID1
+	}
+	if err := q.LockAccountBalance(ctx, accountID1); err != nil {
+		return err
+	}
+	if accountID2 == accountID1 {
+		return nil
+	}
+	return q.LockAccountBalance(ctx, accountID2)
+}
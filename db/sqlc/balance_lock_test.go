@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBalanceLock(t *testing.T) {
+	testCases := []struct {
+		setting string
+		lock    BalanceLock
+		wantErr bool
+	}{
+		{setting: "", lock: BalanceLockRow},
+		{setting: "row", lock: BalanceLockRow},
+		{setting: "advisory", lock: BalanceLockAdvisory},
+		{setting: "skip_locked", wantErr: true},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.setting, func(t *testing.T) {
+			lock, err := ParseBalanceLock(tc.setting)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.lock, lock)
+		})
+	}
+}
+
+func TestTransferAdvisoryLock(t *testing.T) {
+	store := NewStoreWithOptions(testDB, StoreOptions{BalanceLock: BalanceLockAdvisory})
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	n := 10
+	amount := int64(10)
+
+	errs := make(chan error)
+
+	// transfers both ways, so that a wrong lock order would deadlock
+	for i := 0; i < n; i++ {
+		fromAccount := account1.ID
+		toAccount := account2.ID
+
+		if i%2 == 1 {
+			fromAccount = account2.ID
+			toAccount = account1.ID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccount,
+				ToAccountID:   toAccount,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedAccount1, err := testQuires.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	updatedAccount2, err := testQuires.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+// BenchmarkTransferTxHotAccount compares the balance locks on transfers that all credit the same account:
+//
+//	go test ./db/sqlc -run '^$' -bench TransferTxHotAccount -cpu 16
+func BenchmarkTransferTxHotAccount(b *testing.B) {
+	for _, lock := range []BalanceLock{BalanceLockRow, BalanceLockAdvisory} {
+		b.Run(string(lock), func(b *testing.B) {
+			store := NewStoreWithOptions(testDB, StoreOptions{BalanceLock: lock})
+			hot := createRandomAccount(b)
+			senders := make([]Account, 32)
+			for i := range senders {
+				senders[i] = createRandomAccount(b)
+			}
+			var next int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				from := senders[atomic.AddInt64(&next, 1)%int64(len(senders))]
+				for pb.Next() {
+					_, err := store.TransferTx(context.Background(), TransferTxParams{
+						FromAccountID: from.ID,
+						ToAccountID:   hot.ID,
+						Amount:        1,
+					})
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
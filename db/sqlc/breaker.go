@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrStoreUnavailable is returned without reaching the database while the breaker is open.
+var ErrStoreUnavailable = errors.New("store unavailable")
+
+// Breaker is a circuit breaker in front of the database. After threshold queries in a row failed
+// to reach it, the breaker opens and the queries fail fast with ErrStoreUnavailable, instead of
+// each request waiting for a dead connection pool until its deadline. Once openTimeout is over a
+// single query is let through to probe the database, closing the breaker if it succeeds and
+// opening it again otherwise.
+type Breaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker returns nil, a breaker that never opens, when threshold isn't positive.
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &Breaker{threshold: threshold, openTimeout: openTimeout}
+}
+
+// allow returns ErrStoreUnavailable when the query must not be sent.
+func (breaker *Breaker) allow() error {
+	if breaker == nil {
+		return nil
+	}
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if !breaker.open {
+		return nil
+	}
+	if time.Since(breaker.openedAt) < breaker.openTimeout {
+		return ErrStoreUnavailable
+	}
+	// the other queries wait for the probe, or for another openTimeout if its outcome never comes
+	breaker.openedAt = time.Now()
+	breaker.probing = true
+	return nil
+}
+
+// record counts the outcome of a query allow let through.
+func (breaker *Breaker) record(ctx context.Context, err error) {
+	if breaker == nil {
+		return
+	}
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if !isOutage(ctx, err) {
+		if breaker.open {
+			log.Info().Msg("database is reachable again, closing the circuit breaker")
+			metrics.ObserveDBBreaker(false)
+		}
+		breaker.failures = 0
+		breaker.open = false
+		breaker.probing = false
+		return
+	}
+
+	breaker.failures++
+	if breaker.probing || (!breaker.open && breaker.failures >= breaker.threshold) {
+		if !breaker.open {
+			log.Error().Err(err).Int("failures", breaker.failures).Msg("database is unreachable, opening the circuit breaker")
+			metrics.ObserveDBBreaker(true)
+		}
+		breaker.open = true
+		breaker.openedAt = time.Now()
+		breaker.probing = false
+	}
+}
+
+// isOutage tells whether err means the database couldn't answer. Answers like a missing row or a
+// constraint violation don't, and neither does a client giving up on its request.
+func isOutage(ctx context.Context, err error) bool {
+	return err != nil &&
+		!errors.Is(err, ErrRecordNotFound) &&
+		ErrorCode(err) == "" &&
+		!errors.Is(ctx.Err(), context.Canceled)
+}
+
+// breakerDB sends the queries through the breaker.
+type breakerDB struct {
+	db      DBTX
+	breaker *Breaker
+}
+
+func newBreakerDB(db DBTX, breaker *Breaker) DBTX {
+	if breaker == nil {
+		return db
+	}
+	return &breakerDB{db: db, breaker: breaker}
+}
+
+func (b *breakerDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := b.breaker.allow(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := b.db.Exec(ctx, query, args...)
+	b.breaker.record(ctx, err)
+	return tag, err
+}
+
+func (b *breakerDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	if err := b.breaker.allow(); err != nil {
+		return nil, err
+	}
+	rows, err := b.db.Query(ctx, query, args...)
+	b.breaker.record(ctx, err)
+	return rows, err
+}
+
+func (b *breakerDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	if err := b.breaker.allow(); err != nil {
+		return unavailableRow{err: err}
+	}
+	return &breakerRow{row: b.db.QueryRow(ctx, query, args...), ctx: ctx, breaker: b.breaker}
+}
+
+func (b *breakerDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	if err := b.breaker.allow(); err != nil {
+		return 0, err
+	}
+	count, err := b.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	b.breaker.record(ctx, err)
+	return count, err
+}
+
+type breakerRow struct {
+	row     pgx.Row
+	ctx     context.Context
+	breaker *Breaker
+}
+
+func (r *breakerRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	r.breaker.record(r.ctx, err)
+	return err
+}
+
+type unavailableRow struct {
+	err error
+}
+
+func (r unavailableRow) Scan(dest ...interface{}) error {
+	return r.err
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB answers every query with err, counting the queries that reach it.
+type fakeDB struct {
+	err     error
+	queries int
+}
+
+func (db *fakeDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.queries++
+	return pgconn.CommandTag{}, db.err
+}
+
+func (db *fakeDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	db.queries++
+	return nil, db.err
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	db.queries++
+	return unavailableRow{err: db.err}
+}
+
+func (db *fakeDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	db.queries++
+	return 0, db.err
+}
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	outage := errors.New("dial tcp 127.0.0.1:5432: connect: connection refused")
+	fake := &fakeDB{err: outage}
+	breaker := NewBreaker(3, 50*time.Millisecond)
+	db := newBreakerDB(fake, breaker)
+
+	for i := 0; i < 3; i++ {
+		_, err := db.Exec(ctx, "-- name: DeleteAccount :exec")
+		require.ErrorIs(t, err, outage)
+	}
+
+	// open: the queries fail fast without reaching the database
+	_, err := db.Exec(ctx, "-- name: DeleteAccount :exec")
+	require.ErrorIs(t, err, ErrStoreUnavailable)
+	require.ErrorIs(t, db.QueryRow(ctx, "-- name: GetAccount :one").Scan(), ErrStoreUnavailable)
+	require.Equal(t, 3, fake.queries)
+
+	// a failed probe opens it again
+	time.Sleep(60 * time.Millisecond)
+	require.ErrorIs(t, db.QueryRow(ctx, "-- name: GetAccount :one").Scan(), outage)
+	_, err = db.Query(ctx, "-- name: ListAccounts :many")
+	require.ErrorIs(t, err, ErrStoreUnavailable)
+	require.Equal(t, 4, fake.queries)
+
+	// a successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	fake.err = nil
+	_, err = db.Query(ctx, "-- name: ListAccounts :many")
+	require.NoError(t, err)
+	_, err = db.CopyFrom(ctx, pgx.Identifier{"entries"}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 6, fake.queries)
+}
+
+func TestBreakerIgnoresAnswers(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fake := &fakeDB{}
+	breaker := NewBreaker(1, time.Minute)
+	db := newBreakerDB(fake, breaker)
+
+	fake.err = ErrRecordNotFound
+	require.ErrorIs(t, db.QueryRow(context.Background(), "-- name: GetAccount :one").Scan(), ErrRecordNotFound)
+	fake.err = &pgconn.PgError{Code: UniqueViolation}
+	_, err := db.Exec(context.Background(), "-- name: CreateAccount :one")
+	require.Error(t, err)
+	fake.err = context.Canceled
+	_, err = db.Exec(canceled, "-- name: CreateAccount :one")
+	require.ErrorIs(t, err, context.Canceled)
+
+	fake.err = nil
+	_, err = db.Exec(context.Background(), "-- name: CreateAccount :one")
+	require.NoError(t, err)
+	require.Equal(t, 4, fake.queries)
+}
+
+func TestNewBreakerDisabled(t *testing.T) {
+	require.Nil(t, NewBreaker(0, time.Second))
+
+	fake := &fakeDB{}
+	require.Same(t, fake, newBreakerDB(fake, nil))
+}
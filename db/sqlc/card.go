@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The reasons an authorization of a card is declined for, told to the card network.
+const (
+	DeclineInvalidCard       = "invalid_card"
+	DeclineExpiredCard       = "expired_card"
+	DeclineInvalidCVV        = "invalid_cvv"
+	DeclineCardFrozen        = "card_frozen"
+	DeclineCurrencyMismatch  = "currency_mismatch"
+	DeclineLimitExceeded     = "limit_exceeded"
+	DeclineInsufficientFunds = "insufficient_funds"
+	DeclineAccountFrozen     = "account_frozen"
+	DeclineDoNotHonor        = "do_not_honor"
+)
+
+type AuthorizeCardTxParams struct {
+	// NetworkTransactionID is the id the card network gives the request, the same when it retries
+	// it.
+	NetworkTransactionID string
+	CardID               int64
+	Amount               int64
+	Currency             string
+	MerchantName         string
+	MerchantCategory     string
+	// Decline, when set, is the reason the card was declined for before reaching the store, e.g. a
+	// wrong CVV: the authorization is then only recorded.
+	Decline string
+	// AlertTask and RewardTask are those of the debit, see TransferTxParams.
+	AlertTask  AlertTaskFunc
+	RewardTask RewardTaskFunc
+}
+
+type AuthorizeCardTxResult struct {
+	Authorization CardAuthorization `json:"authorization"`
+	// Transfer is the debit of an approved authorization.
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+	// Replayed tells that the network sent the request already, Authorization being the answer to
+	// the original.
+	Replayed bool `json:"replayed"`
+}
+
+// ErrNoNetworkTransactionID is returned by AuthorizeCardTx for a request without the id of the
+// network, which a replay couldn't be told from.
+var ErrNoNetworkTransactionID = errors.New("card authorization needs a network transaction id")
+
+// cardDecline is returned by the transaction of AuthorizeCardTx to roll back a declined debit.
+type cardDecline string
+
+func (reason cardDecline) Error() string {
+	return "card declined: " + string(reason)
+}
+
+// AuthorizeCardTx answers an authorization request of the card network. An approved authorization
+// debits the account of the card right away, the amount going to the suspense account of its
+// currency until the network settles it; a declined one is recorded with the reason. A card is
+// declined when it is frozen, over its limits, or when its account is frozen or the debit would
+// take its balance below zero or below the amount held by disputes.
+//
+// The network retries a request it got no answer for, with the same NetworkTransactionID: a replay
+// is answered with the authorization of the original, without debiting the account again. The
+// unique index on the id makes a replay racing the original roll back its debit.
+func (store *SQLStore) AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	if arg.NetworkTransactionID == "" {
+		return AuthorizeCardTxResult{}, ErrNoNetworkTransactionID
+	}
+	original, err := store.GetCardAuthorizationByNetworkTransactionID(ctx, arg.NetworkTransactionID)
+	if err == nil {
+		return AuthorizeCardTxResult{Authorization: original, Replayed: true}, nil
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return AuthorizeCardTxResult{}, err
+	}
+
+	result, err := store.authorizeCardOnce(ctx, arg)
+	if ErrorCode(err) == UniqueViolation {
+		original, err = store.GetCardAuthorizationByNetworkTransactionID(ctx, arg.NetworkTransactionID)
+		return AuthorizeCardTxResult{Authorization: original, Replayed: true}, err
+	}
+	return result, err
+}
+
+// authorizeCardOnce answers an authorization request the network didn't send before.
+func (store *SQLStore) authorizeCardOnce(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	var result AuthorizeCardTxResult
+
+	reason := arg.Decline
+	if reason == "" {
+		err := store.execTx(ctx, "AuthorizeCardTx", func(ctx context.Context, q *Queries) error {
+			var err error
+			result, err = store.authorizeCard(ctx, q, arg)
+			return err
+		})
+		var decline cardDecline
+		if !errors.As(err, &decline) {
+			return result, err
+		}
+		reason = string(decline)
+	}
+
+	authorization, err := store.CreateCardAuthorization(ctx, CreateCardAuthorizationParams{
+		CardID:               arg.CardID,
+		Amount:               arg.Amount,
+		Currency:             arg.Currency,
+		MerchantName:         arg.MerchantName,
+		MerchantCategory:     arg.MerchantCategory,
+		DeclineReason:        reason,
+		NetworkTransactionID: arg.NetworkTransactionID,
+	})
+	return AuthorizeCardTxResult{Authorization: authorization}, err
+}
+
+// authorizeCard checks an authorization against the card and debits the account in the
+// transaction of q, the card being locked so that its authorizations count against its daily
+// limit one at a time.
+func (store *SQLStore) authorizeCard(ctx context.Context, q *Queries, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	var result AuthorizeCardTxResult
+
+	card, err := q.GetCardForUpdate(ctx, arg.CardID)
+	if err != nil {
+		return result, err
+	}
+	if card.FrozenAt.Valid {
+		return result, cardDecline(DeclineCardFrozen)
+	}
+	if card.PerTransactionLimit > 0 && arg.Amount > card.PerTransactionLimit {
+		return result, cardDecline(DeclineLimitExceeded)
+	}
+	if card.DailyLimit > 0 {
+		// the day of the limit is that of the owner, starting at their midnight
+		spent, err := q.SumCardAuthorizations(ctx, SumCardAuthorizationsParams{
+			CardID: card.ID,
+			At:     time.Now(),
+		})
+		if err != nil {
+			return result, err
+		}
+		if spent+arg.Amount > card.DailyLimit {
+			return result, cardDecline(DeclineLimitExceeded)
+		}
+	}
+
+	account, err := q.GetAccount(ctx, card.AccountID)
+	if err != nil {
+		return result, err
+	}
+	if account.Currency != arg.Currency {
+		return result, cardDecline(DeclineCurrencyMismatch)
+	}
+	suspense, err := q.GetSuspenseAccount(ctx, account.Currency)
+	if err != nil {
+		return result, err
+	}
+
+	debit, err := store.transfer(ctx, q, TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   suspense.ID,
+		Amount:        arg.Amount,
+		Memo:          "card payment at " + arg.MerchantName,
+		AlertTask:     arg.AlertTask,
+		RewardTask:    arg.RewardTask,
+	})
+	switch {
+	case errors.Is(err, ErrAccountFrozen):
+		return result, cardDecline(DeclineAccountFrozen)
+	case errors.Is(err, ErrFundsHeld):
+		return result, cardDecline(DeclineInsufficientFunds)
+	case err != nil:
+		return result, err
+	}
+	if debit.FromAccount.Balance < 0 {
+		return result, cardDecline(DeclineInsufficientFunds)
+	}
+
+	result.Authorization, err = q.CreateCardAuthorization(ctx, CreateCardAuthorizationParams{
+		CardID:               card.ID,
+		Amount:               arg.Amount,
+		Currency:             arg.Currency,
+		MerchantName:         arg.MerchantName,
+		MerchantCategory:     arg.MerchantCategory,
+		Approved:             true,
+		TransferID:           pgtype.Int8{Int64: debit.Transfer.ID, Valid: true},
+		NetworkTransactionID: arg.NetworkTransactionID,
+	})
+	result.Transfer = &debit
+	return result, err
+}
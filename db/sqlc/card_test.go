@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCard(t *testing.T, account Account, dailyLimit int64) Card {
+	card, err := testQuires.CreateCard(context.Background(), CreateCardParams{
+		AccountID:   account.ID,
+		Fingerprint: util.RandomString(64),
+		Last4:       "1234",
+		ExpiryMonth: 10,
+		ExpiryYear:  2029,
+		DailyLimit:  dailyLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, card.AccountID)
+	require.False(t, card.FrozenAt.Valid)
+	return card
+}
+
+func authorizeCard(t *testing.T, store Store, card Card, amount int64) AuthorizeCardTxResult {
+	result, err := store.AuthorizeCardTx(context.Background(), AuthorizeCardTxParams{
+		NetworkTransactionID: util.RandomString(20),
+		CardID:               card.ID,
+		Amount:               amount,
+		Currency:             util.USD,
+		MerchantName:         "Corner Shop",
+	})
+	require.NoError(t, err)
+	require.Equal(t, card.ID, result.Authorization.CardID)
+	return result
+}
+
+func TestAuthorizeCardTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	card := createRandomCard(t, account, 70)
+
+	approved := authorizeCard(t, store, card, 50)
+	require.True(t, approved.Authorization.Approved)
+	require.NotNil(t, approved.Transfer)
+	require.Equal(t, approved.Transfer.Transfer.ID, approved.Authorization.TransferID.Int64)
+	require.Equal(t, int64(50), approved.Transfer.FromAccount.Balance)
+
+	// 50 spent today already
+	declined := authorizeCard(t, store, card, 30)
+	require.False(t, declined.Authorization.Approved)
+	require.Equal(t, DeclineLimitExceeded, declined.Authorization.DeclineReason)
+	require.Nil(t, declined.Transfer)
+
+	_, err := testQuires.SetCardFrozen(context.Background(), SetCardFrozenParams{ID: card.ID, Frozen: true})
+	require.NoError(t, err)
+	declined = authorizeCard(t, store, card, 10)
+	require.Equal(t, DeclineCardFrozen, declined.Authorization.DeclineReason)
+
+	spent, err := testQuires.SumCardAuthorizations(context.Background(), SumCardAuthorizationsParams{
+		CardID: card.ID,
+		At:     approved.Authorization.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), spent)
+
+	authorizations, err := testQuires.ListCardAuthorizations(context.Background(), ListCardAuthorizationsParams{
+		CardID: card.ID,
+		Limit:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, authorizations, 3)
+}
+
+func TestAuthorizeCardTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(10))
+	card := createRandomCard(t, account, 0)
+
+	declined := authorizeCard(t, store, card, 11)
+	require.Equal(t, DeclineInsufficientFunds, declined.Authorization.DeclineReason)
+
+	// the declined debit was rolled back
+	unchanged, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), unchanged.Balance)
+}
+
+func TestAuthorizeCardTxDeclined(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	card := createRandomCard(t, account, 0)
+
+	result, err := store.AuthorizeCardTx(context.Background(), AuthorizeCardTxParams{
+		NetworkTransactionID: util.RandomString(20),
+		CardID:               card.ID,
+		Amount:               10,
+		Currency:             util.USD,
+		MerchantName:         "Corner Shop",
+		Decline:              DeclineInvalidCVV,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Authorization.Approved)
+	require.Equal(t, DeclineInvalidCVV, result.Authorization.DeclineReason)
+
+	result, err = store.AuthorizeCardTx(context.Background(), AuthorizeCardTxParams{
+		NetworkTransactionID: util.RandomString(20),
+		CardID:               card.ID,
+		Amount:               10,
+		Currency:             util.EUR,
+		MerchantName:         "Corner Shop",
+	})
+	require.NoError(t, err)
+	require.Equal(t, DeclineCurrencyMismatch, result.Authorization.DeclineReason)
+}
+
+func TestAuthorizeCardTxReplay(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	card := createRandomCard(t, account, 0)
+
+	arg := AuthorizeCardTxParams{
+		NetworkTransactionID: util.RandomString(20),
+		CardID:               card.ID,
+		Amount:               10,
+		Currency:             util.USD,
+		MerchantName:         "Corner Shop",
+	}
+
+	// the replays racing the original see its authorization, and only one debits the account
+	n := 5
+	results := make(chan AuthorizeCardTxResult)
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.AuthorizeCardTx(context.Background(), arg)
+			errs <- err
+			results <- result
+		}()
+	}
+	var authorizationID int64
+	replays := 0
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		result := <-results
+		require.True(t, result.Authorization.Approved)
+		if authorizationID == 0 {
+			authorizationID = result.Authorization.ID
+		}
+		require.Equal(t, authorizationID, result.Authorization.ID)
+		if result.Replayed {
+			replays++
+		}
+	}
+	require.Equal(t, n-1, replays)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), updated.Balance)
+
+	arg.NetworkTransactionID = ""
+	_, err = store.AuthorizeCardTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrNoNetworkTransactionID)
+}
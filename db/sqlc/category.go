@@ -0,0 +1,39 @@
+package db
+
+import "context"
+
+// The directions a category rule matches: the entries taking money out of an account, or the
+// ones bringing it in. An empty direction matches both.
+const (
+	DirectionDebit  = "debit"
+	DirectionCredit = "credit"
+)
+
+// categorizeTransfer categorizes the two entries of a transfer by the rules of the owner of each
+// account, the other account being the counterparty.
+func categorizeTransfer(ctx context.Context, q *Queries, result TransferTxResult) error {
+	sides := []CategorizeEntryParams{
+		{
+			EntryID:               result.FromEntry.ID,
+			AccountID:             result.FromAccount.ID,
+			Owner:                 result.FromAccount.Owner,
+			CounterpartyAccountID: result.ToAccount.ID,
+			Direction:             DirectionDebit,
+			Memo:                  result.Transfer.Memo,
+		},
+		{
+			EntryID:               result.ToEntry.ID,
+			AccountID:             result.ToAccount.ID,
+			Owner:                 result.ToAccount.Owner,
+			CounterpartyAccountID: result.FromAccount.ID,
+			Direction:             DirectionCredit,
+			Memo:                  result.Transfer.Memo,
+		},
+	}
+	for _, side := range sides {
+		if _, err := q.CategorizeEntry(ctx, side); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxCategorizesEntries(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// the memo rule is tried first, the counterparty one only categorizes the other transfers
+	_, err := testQuires.CreateCategoryRule(context.Background(), CreateCategoryRuleParams{
+		Owner:        account1.Owner,
+		Category:     "rent",
+		Direction:    DirectionDebit,
+		MemoContains: "rent",
+	})
+	require.NoError(t, err)
+	_, err = testQuires.CreateCategoryRule(context.Background(), CreateCategoryRuleParams{
+		Owner:                 account1.Owner,
+		Category:              "family",
+		CounterpartyAccountID: pgtype.Int8{Int64: account2.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	salary, err := testQuires.CreateCategoryRule(context.Background(), CreateCategoryRuleParams{
+		Owner:     account2.Owner,
+		Category:  "salary",
+		Direction: DirectionCredit,
+	})
+	require.NoError(t, err)
+
+	rent, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Memo:          "October RENT",
+	})
+	require.NoError(t, err)
+	other, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	categories, err := testQuires.ListEntryCategories(context.Background(), []int64{
+		rent.FromEntry.ID, rent.ToEntry.ID, other.FromEntry.ID, other.ToEntry.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, categories, 4)
+	byEntry := make(map[int64]EntryCategory)
+	for _, category := range categories {
+		byEntry[category.EntryID] = category
+	}
+	require.Equal(t, "rent", byEntry[rent.FromEntry.ID].Category)
+	require.Equal(t, "family", byEntry[other.FromEntry.ID].Category)
+	require.Equal(t, "salary", byEntry[rent.ToEntry.ID].Category)
+	require.Equal(t, salary.ID, byEntry[rent.ToEntry.ID].RuleID.Int64)
+	require.Equal(t, account2.ID, byEntry[rent.ToEntry.ID].AccountID)
+}
+
+func TestSetEntryCategory(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	_, err := testQuires.CreateCategoryRule(context.Background(), CreateCategoryRuleParams{
+		Owner:     account1.Owner,
+		Category:  "transfers",
+		Direction: DirectionDebit,
+	})
+	require.NoError(t, err)
+	result, err := NewStore(testDB).TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// the owner's category replaces the one of the rule
+	category, err := testQuires.SetEntryCategory(context.Background(), SetEntryCategoryParams{
+		EntryID:   result.FromEntry.ID,
+		AccountID: account1.ID,
+		Category:  "groceries",
+		Tags:      []string{"weekly"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "groceries", category.Category)
+	require.Equal(t, []string{"weekly"}, category.Tags)
+	require.False(t, category.RuleID.Valid)
+
+	// an entry no rule matched can be categorized too
+	category, err = testQuires.SetEntryCategory(context.Background(), SetEntryCategoryParams{
+		EntryID:   result.ToEntry.ID,
+		AccountID: account2.ID,
+		Category:  "gifts",
+		Tags:      []string{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gifts", category.Category)
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// The kinds of the legal documents the users accept.
+const (
+	LegalTerms   = "terms"
+	LegalPrivacy = "privacy"
+)
+
+var (
+	// ErrConsentRequired is returned by CheckConsents while the user hasn't accepted the latest
+	// version of a document.
+	ErrConsentRequired = errors.New("accept the latest terms of service and privacy policy first")
+	// ErrNotLatestLegalDocument is returned by AcceptLegalDocument for a version that isn't the
+	// latest of its kind, which accepting wouldn't clear.
+	ErrNotLatestLegalDocument = errors.New("not the latest version of the document")
+)
+
+// CheckConsents fails with ErrConsentRequired while username has documents to accept. It is
+// checked on every sensitive operation, so that publishing a version takes effect right away.
+func CheckConsents(ctx context.Context, store ConsentStore, username string) error {
+	pending, err := store.ListPendingLegalDocuments(ctx, username)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return ErrConsentRequired
+	}
+	return nil
+}
+
+// AcceptLegalDocument records that a user accepted a version of a document, which must be the
+// latest of its kind.
+func AcceptLegalDocument(ctx context.Context, store ConsentStore, arg CreateUserConsentParams) (UserConsent, error) {
+	latest, err := store.ListLatestLegalDocuments(ctx)
+	if err != nil {
+		return UserConsent{}, err
+	}
+	for _, document := range latest {
+		if document.Kind == arg.Kind && document.Version == arg.Version {
+			return store.CreateUserConsent(ctx, arg)
+		}
+	}
+	return UserConsent{}, ErrNotLatestLegalDocument
+}
+
+type CreateLegalDocumentTxParams struct {
+	CreateLegalDocumentParams
+	Audit CreateAuditEntryParams
+}
+
+// CreateLegalDocumentTx publishes a version of a document, which the users must accept before
+// their next sensitive operation.
+func (store *SQLStore) CreateLegalDocumentTx(ctx context.Context, arg CreateLegalDocumentTxParams) (LegalDocument, error) {
+	var document LegalDocument
+
+	err := store.execTx(ctx, "CreateLegalDocumentTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		document, err = q.CreateLegalDocument(ctx, arg.CreateLegalDocumentParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return document, err
+}
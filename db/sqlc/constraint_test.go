@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserConstraints(t *testing.T) {
+	user := createRandomUser(t)
+
+	testCases := []struct {
+		name string
+		arg  CreateUserParams
+	}{
+		{
+			name: "DuplicateUsername",
+			arg: CreateUserParams{
+				Username:       user.Username,
+				HashedPassword: user.HashedPassword,
+				FullName:       user.FullName,
+				Email:          util.RandomEmail(),
+			},
+		},
+		{
+			name: "DuplicateEmail",
+			arg: CreateUserParams{
+				Username:       util.RandomOwnerName() + util.RandomOwnerName(),
+				HashedPassword: user.HashedPassword,
+				FullName:       user.FullName,
+				Email:          user.Email,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := testQuires.CreateUser(context.Background(), tc.arg)
+			require.Equal(t, UniqueViolation, ErrorCode(err))
+		})
+	}
+}
+
+func TestAccountConstraints(t *testing.T) {
+	account := createRandomAccount(t)
+
+	// one account per owner and currency
+	_, err := testQuires.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	// unless the other one was deleted
+	require.NoError(t, testQuires.DeleteAccount(context.Background(), account.ID))
+	_, err = testQuires.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	_, err = testQuires.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    util.RandomOwnerName() + util.RandomOwnerName(),
+		Currency: util.RandomCurrency(),
+	})
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+}
+
+func TestTransferTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   -account.ID,
+		Amount:        10,
+	})
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+
+	// nothing of the transfer is left
+	updatedAccount, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{AccountID: account.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
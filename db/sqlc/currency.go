@@ -0,0 +1,25 @@
+package db
+
+import "context"
+
+type SetCurrencyEnabledTxParams struct {
+	SetCurrencyEnabledParams
+	Audit CreateAuditEntryParams
+}
+
+// SetCurrencyEnabledTx offers a currency, or stops offering it. The accounts opened in a currency
+// no longer offered keep their balance.
+func (store *SQLStore) SetCurrencyEnabledTx(ctx context.Context, arg SetCurrencyEnabledTxParams) (Currency, error) {
+	var currency Currency
+
+	err := store.execTx(ctx, "SetCurrencyEnabledTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		currency, err = q.SetCurrencyEnabled(ctx, arg.SetCurrencyEnabledParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return currency, err
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQuires.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	minorUnits := map[string]int16{}
+	for _, currency := range currencies {
+		minorUnits[currency.Code] = currency.MinorUnits
+	}
+	require.Equal(t, int16(2), minorUnits[util.USD])
+	require.Equal(t, int16(0), minorUnits["JPY"])
+	require.Equal(t, int16(3), minorUnits["BHD"])
+}
+
+func TestSetCurrencyEnabledTx(t *testing.T) {
+	store := NewStore(testDB)
+	target := "currency:KWD"
+
+	currency, err := store.SetCurrencyEnabledTx(context.Background(), SetCurrencyEnabledTxParams{
+		SetCurrencyEnabledParams: SetCurrencyEnabledParams{Code: "KWD", Enabled: true},
+		Audit:                    randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.True(t, currency.Enabled)
+	require.Equal(t, int16(3), currency.MinorUnits)
+
+	currency, err = store.SetCurrencyEnabledTx(context.Background(), SetCurrencyEnabledTxParams{
+		SetCurrencyEnabledParams: SetCurrencyEnabledParams{Code: "KWD", Enabled: false},
+		Audit:                    randomAuditEntry(target),
+	})
+	require.NoError(t, err)
+	require.False(t, currency.Enabled)
+
+	_, err = store.SetCurrencyEnabledTx(context.Background(), SetCurrencyEnabledTxParams{
+		SetCurrencyEnabledParams: SetCurrencyEnabledParams{Code: "XYZ", Enabled: true},
+		Audit:                    randomAuditEntry("currency:XYZ"),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MaxDeviceNameLength is the length the default name of a device, its user agent, is cut to.
+const MaxDeviceNameLength = 64
+
+// userAgentFingerprintPrefix marks the fingerprints derived from a user agent.
+const userAgentFingerprintPrefix = "ua:"
+
+// DeviceFingerprint is the fingerprint of the device of a client: the one it sends, or one derived
+// from its user agent when it sends none, so that every session is bound to a device.
+func DeviceFingerprint(fingerprint, userAgent string) string {
+	if fingerprint != "" {
+		return fingerprint
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSpace(userAgent)))
+	return userAgentFingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+// LoginDevice is the device a login registers, named after the user agent unless told. A login
+// that doesn't send a fingerprint registers the device of its user agent, see DeviceFingerprint.
+func LoginDevice(username, fingerprint, name, userAgent string) UpsertDeviceParams {
+	fingerprint = DeviceFingerprint(fingerprint, userAgent)
+	if name == "" {
+		name = strings.TrimSpace(userAgent)
+	}
+	if len(name) > MaxDeviceNameLength {
+		name = strings.ToValidUTF8(name[:MaxDeviceNameLength], "")
+	}
+	if name == "" {
+		name = "unknown device"
+	}
+	return UpsertDeviceParams{Username: username, Fingerprint: fingerprint, Name: name}
+}
+
+type SendDeviceVerificationTxParams struct {
+	SetDeviceVerificationParams
+	// AfterUpdate returns the outbox tasks of the device once its code is replaced, e.g. the
+	// email of the code.
+	AfterUpdate func(device Device) ([]CreateOutboxTaskParams, error)
+}
+
+// SendDeviceVerificationTx replaces the one-time code of an untrusted device along with the
+// outbox tasks sending it.
+func (store *SQLStore) SendDeviceVerificationTx(ctx context.Context, arg SendDeviceVerificationTxParams) (Device, error) {
+	var device Device
+
+	err := store.execTx(ctx, "SendDeviceVerificationTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		device, err = q.SetDeviceVerification(ctx, arg.SetDeviceVerificationParams)
+		if err != nil || arg.AfterUpdate == nil {
+			return err
+		}
+		tasks, err := arg.AfterUpdate(device)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return device, err
+}
+
+// RevokeDeviceTx revokes a device of the user and blocks the sessions it logged in, so that their
+// refresh tokens can't renew an access token anymore. It returns ErrRecordNotFound for a device
+// of another user or already revoked.
+func (store *SQLStore) RevokeDeviceTx(ctx context.Context, arg RevokeDeviceParams) (Device, error) {
+	var device Device
+
+	err := store.execTx(ctx, "RevokeDeviceTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		device, err = q.RevokeDevice(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return q.BlockDeviceSessions(ctx, pgtype.Int8{Int64: device.ID, Valid: true})
+	})
+	return device, err
+}
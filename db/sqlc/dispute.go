@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of a dispute. An open or investigating dispute holds its amount on the account the
+// transfer credited; a resolved or refunded one is closed, the refunded ones with a reversal.
+const (
+	DisputeOpen          = "open"
+	DisputeInvestigating = "investigating"
+	DisputeResolved      = "resolved"
+	DisputeRefunded      = "refunded"
+)
+
+var (
+	// ErrDisputeExists is returned by OpenDisputeTx for a transfer already disputed.
+	ErrDisputeExists = errors.New("transfer is already disputed")
+	// ErrDisputeClosed is returned by SetDisputeStatusTx for a dispute already resolved or refunded.
+	ErrDisputeClosed = errors.New("dispute is closed")
+	// ErrInvalidDisputeStatus is returned by SetDisputeStatusTx for a status it can't move to.
+	ErrInvalidDisputeStatus = errors.New("invalid dispute status")
+	// ErrFundsHeld is returned by TransferTx for a debit spending the amount held by disputes or
+	// authorization holds.
+	ErrFundsHeld = errors.New("funds are held by an open dispute or an authorization hold")
+)
+
+// Active tells whether the dispute still holds its amount.
+func (dispute Dispute) Active() bool {
+	return dispute.Status == DisputeOpen || dispute.Status == DisputeInvestigating
+}
+
+// checkHeldAmount fails when a debit left the account with less than the amount held on it, by
+// its disputes and its authorization holds. Only the held amounts are enforced: an account without
+// a hold can still go below zero.
+func checkHeldAmount(ctx context.Context, q *Queries, account Account) error {
+	held, err := q.GetHeldAmount(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	held += account.HeldBalance
+	if held > 0 && account.Balance < held {
+		return ErrFundsHeld
+	}
+	return nil
+}
+
+// OpenDisputeTx opens a dispute, which holds its amount on the account from then on, less what the
+// refunds of the transfer already paid back. The dispute of an exchange holds the amount on the
+// payee, in its currency, at most what the exchange credited it. The account is locked like a
+// transfer locks it, so that a transfer debiting it sees the hold or runs first.
+func (store *SQLStore) OpenDisputeTx(ctx context.Context, arg CreateDisputeParams) (Dispute, error) {
+	var dispute Dispute
+
+	err := store.execTx(ctx, "OpenDisputeTx", func(ctx context.Context, q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		credited, err := creditedTransfer(ctx, q, transfer)
+		if err != nil {
+			return err
+		}
+		returned, err := q.GetReturnedAmount(ctx, transfer.ID)
+		if err != nil {
+			return err
+		}
+		if remaining := credited.Amount - returned.Refunded - returned.Reversed; remaining < arg.Amount {
+			if remaining <= 0 {
+				return fmt.Errorf("%w: %d of %d already returned", ErrRefundExceedsTransfer, credited.Amount-remaining, credited.Amount)
+			}
+			arg.Amount = remaining
+		}
+		if _, err := q.GetAccountForUpdate(ctx, arg.AccountID); err != nil {
+			return err
+		}
+
+		dispute, err = q.CreateDispute(ctx, arg)
+		if ErrorCode(err) == UniqueViolation {
+			return ErrDisputeExists
+		}
+		return err
+	})
+	return dispute, err
+}
+
+type SetDisputeStatusTxParams struct {
+	ID int64
+	// Status is investigating, or resolved or refunded to close the dispute.
+	Status     string
+	Resolution string
+	Admin      string
+	Audit      CreateAuditEntryParams
+}
+
+type SetDisputeStatusTxResult struct {
+	Dispute Dispute `json:"dispute"`
+	// Reversal is the transfer paying the disputed amount back, for a refunded dispute.
+	Reversal *TransferTxResult `json:"reversal,omitempty"`
+}
+
+// SetDisputeStatusTx moves an active dispute to investigating, or closes it, which releases its
+// hold. Refunding it reverses the transfer: the amount goes back from the account it credited,
+// even below the balance, like an adjustment would take it, unless the refunds of the transfer
+// already paid it back. The reversal of an exchange goes back through the fx accounts, and pays
+// the payer its share of what the exchange debited it, at the rate of the exchange.
+func (store *SQLStore) SetDisputeStatusTx(ctx context.Context, arg SetDisputeStatusTxParams) (SetDisputeStatusTxResult, error) {
+	var result SetDisputeStatusTxResult
+
+	switch arg.Status {
+	case DisputeInvestigating, DisputeResolved, DisputeRefunded:
+	default:
+		return result, fmt.Errorf("%w %q", ErrInvalidDisputeStatus, arg.Status)
+	}
+
+	err := store.execTx(ctx, "SetDisputeStatusTx", func(ctx context.Context, q *Queries) error {
+		dispute, err := q.GetDisputeForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !dispute.Active() {
+			return ErrDisputeClosed
+		}
+		if dispute.Status == arg.Status {
+			return fmt.Errorf("%w: dispute is already %s", ErrInvalidDisputeStatus, arg.Status)
+		}
+
+		update := UpdateDisputeStatusParams{
+			ID:         dispute.ID,
+			Status:     arg.Status,
+			Resolution: arg.Resolution,
+			ResolvedBy: arg.Admin,
+		}
+		if arg.Status != DisputeInvestigating {
+			update.ResolvedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+		if arg.Status == DisputeRefunded {
+			transfer, err := q.GetTransferForUpdate(ctx, dispute.TransferID)
+			if err != nil {
+				return err
+			}
+			credited, err := creditedTransfer(ctx, q, transfer)
+			if err != nil {
+				return err
+			}
+			if _, err := checkReturnedAmount(ctx, q, transfer.ID, credited.Amount, dispute.Amount); err != nil {
+				return err
+			}
+			reversal, err := store.reverseTransfer(ctx, q, transfer, credited, dispute.Amount)
+			if err != nil {
+				return err
+			}
+			result.Reversal = &reversal
+			update.ReversalTransferID = pgtype.Int8{Int64: reversal.Transfer.ID, Valid: true}
+		}
+
+		result.Dispute, err = q.UpdateDisputeStatus(ctx, update)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return result, err
+}
+
+// reverseTransfer pays amount back from the payee of the transfer to its payer. For an exchange,
+// credited is the transfer to the payee, and the payer gets back the same share of what the
+// exchange debited it.
+func (store *SQLStore) reverseTransfer(ctx context.Context, q *Queries, transfer, credited Transfer, amount int64) (TransferTxResult, error) {
+	arg := CreateTransferParams{
+		FromAccountID: credited.ToAccountID,
+		ToAccountID:   transfer.FromAccountID,
+		Amount:        amount,
+		Memo:          fmt.Sprintf("reversal of transfer #%d", transfer.ID),
+	}
+	if credited.ID == transfer.ID {
+		reversal, err := store.moveMoney(ctx, q, arg)
+		if err != nil {
+			return reversal, err
+		}
+		return reversal, createTransferCompletedEvent(ctx, q, reversal.Transfer, reversal.FromAccount.Currency)
+	}
+
+	payee, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	payer, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	reversal, err := store.exchangeMoney(ctx, q, TransferTxParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Memo:          arg.Memo,
+		Exchange: &Exchange{
+			FromCurrency: payee.Currency,
+			ToCurrency:   payer.Currency,
+			ToAmount:     transfer.Amount * amount / credited.Amount,
+		},
+	})
+	if err != nil {
+		return reversal, err
+	}
+	if err := createTransferCompletedEvent(ctx, q, reversal.Transfer, reversal.FromAccount.Currency); err != nil {
+		return reversal, err
+	}
+	return reversal, createTransferCompletedEvent(ctx, q, *reversal.ExchangeTransfer, reversal.ToAccount.Currency)
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisputeHold(t *testing.T) {
+	store := NewStore(testDB)
+	sender := createRandomAccount(t)
+	recipient := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	arg := CreateDisputeParams{
+		TransferID: transfer.Transfer.ID,
+		AccountID:  recipient.ID,
+		OpenedBy:   sender.Owner,
+		Amount:     transfer.Transfer.Amount,
+		Reason:     "I didn't make this payment",
+	}
+	dispute, err := store.OpenDisputeTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, DisputeOpen, dispute.Status)
+	require.True(t, dispute.Active())
+
+	_, err = store.OpenDisputeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrDisputeExists)
+
+	held, err := testQuires.GetHeldAmount(context.Background(), recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), held)
+
+	// the held amount can't be spent, the rest of the balance can
+	spendable := transfer.ToAccount.Balance - held
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: recipient.ID,
+		ToAccountID:   other.ID,
+		Amount:        spendable + 1,
+	})
+	require.ErrorIs(t, err, ErrFundsHeld)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: recipient.ID,
+		ToAccountID:   other.ID,
+		Amount:        spendable,
+	})
+	require.NoError(t, err)
+}
+
+func TestSetDisputeStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+	sender := createRandomAccount(t)
+	recipient := createRandomAccount(t)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	dispute, err := store.OpenDisputeTx(context.Background(), CreateDisputeParams{
+		TransferID: transfer.Transfer.ID,
+		AccountID:  recipient.ID,
+		OpenedBy:   recipient.Owner,
+		Amount:     transfer.Transfer.Amount,
+		Reason:     "sent to me by mistake",
+	})
+	require.NoError(t, err)
+
+	arg := SetDisputeStatusTxParams{
+		ID:         dispute.ID,
+		Status:     DisputeInvestigating,
+		Resolution: "asked the sender",
+		Admin:      "admin",
+		Audit:      randomAuditEntry("account:" + util.RandomString(10)),
+	}
+	result, err := store.SetDisputeStatusTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, DisputeInvestigating, result.Dispute.Status)
+	require.False(t, result.Dispute.ResolvedAt.Valid)
+	require.Nil(t, result.Reversal)
+
+	_, err = store.SetDisputeStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidDisputeStatus)
+
+	arg.Status = DisputeRefunded
+	arg.Resolution = "sender confirmed the mistake"
+	result, err = store.SetDisputeStatusTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, DisputeRefunded, result.Dispute.Status)
+	require.True(t, result.Dispute.ResolvedAt.Valid)
+	require.False(t, result.Dispute.Active())
+
+	// the reversal pays the amount back and releases the hold
+	require.NotNil(t, result.Reversal)
+	require.Equal(t, result.Reversal.Transfer.ID, result.Dispute.ReversalTransferID.Int64)
+	require.Equal(t, recipient.ID, result.Reversal.Transfer.FromAccountID)
+	require.Equal(t, sender.ID, result.Reversal.Transfer.ToAccountID)
+	require.Equal(t, sender.Balance, result.Reversal.ToAccount.Balance)
+	require.Equal(t, recipient.Balance, result.Reversal.FromAccount.Balance)
+	held, err := testQuires.GetHeldAmount(context.Background(), recipient.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+
+	arg.Status = DisputeResolved
+	_, err = store.SetDisputeStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrDisputeClosed)
+
+	arg.Status = DisputeOpen
+	_, err = store.SetDisputeStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidDisputeStatus)
+}
+
+func TestExchangeDispute(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        100,
+		Exchange:      &Exchange{FromCurrency: util.USD, ToCurrency: util.EUR, ToAmount: 92},
+	})
+	require.NoError(t, err)
+
+	// the dispute holds what the payee was credited, in euros
+	dispute, err := store.OpenDisputeTx(context.Background(), CreateDisputeParams{
+		TransferID: paid.Transfer.ID,
+		AccountID:  payee.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     paid.Transfer.Amount,
+		Reason:     "item never arrived",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(92), dispute.Amount)
+
+	result, err := store.SetDisputeStatusTx(context.Background(), SetDisputeStatusTxParams{
+		ID:         dispute.ID,
+		Status:     DisputeRefunded,
+		Resolution: "refund the payer",
+		Admin:      "admin",
+		Audit:      randomAuditEntry("account:" + util.RandomString(10)),
+	})
+	require.NoError(t, err)
+
+	// the reversal takes the euros from the payee and pays the dollars back to the payer
+	require.NotNil(t, result.Reversal)
+	require.Equal(t, payee.ID, result.Reversal.Transfer.FromAccountID)
+	require.Equal(t, int64(92), result.Reversal.Transfer.Amount)
+	require.NotNil(t, result.Reversal.ExchangeTransfer)
+	require.Equal(t, payer.ID, result.Reversal.ExchangeTransfer.ToAccountID)
+	require.Equal(t, int64(100), result.Reversal.ExchangeTransfer.Amount)
+	require.Zero(t, result.Reversal.FromAccount.Balance)
+	require.Equal(t, payer.Balance, result.Reversal.ToAccount.Balance)
+}
@@ -1,13 +1,49 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 // source: entrie.sql
 
 package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateEntriesParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+}
+
+const archiveEntries = `-- name: ArchiveEntries :execrows
+WITH moved AS (
+  DELETE FROM entries
+  WHERE (id, created_at) IN (
+    SELECT id, created_at FROM entries
+    WHERE created_at < $1
+    ORDER BY created_at
+    LIMIT $2
+  )
+  RETURNING id, account_id, amount, created_at
 )
+INSERT INTO entries_archive SELECT id, account_id, amount, created_at FROM moved
+`
+
+type ArchiveEntriesParams struct {
+	Before time.Time `json:"before"`
+	Limit  int32     `json:"limit"`
+}
+
+// moves up to limit of the entries created before the cutoff to entries_archive, oldest first.
+func (q *Queries) ArchiveEntries(ctx context.Context, arg ArchiveEntriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, archiveEntries, arg.Before, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
@@ -24,7 +60,7 @@ type CreateEntryParams struct {
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
@@ -41,7 +77,7 @@ WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, getEntry, id)
+	row := q.db.QueryRow(ctx, getEntry, id)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
@@ -55,19 +91,113 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 const listEntries = `-- name: ListEntries :many
 SELECT id, account_id, amount, created_at FROM entries
 WHERE account_id = $1
+  AND created_at >= COALESCE($2::timestamptz, '-infinity')
+  AND created_at < COALESCE($3::timestamptz, 'infinity')
 ORDER BY id
-LIMIT $2
-OFFSET $3
+LIMIT $4
+OFFSET $5
 `
 
 type ListEntriesParams struct {
+	AccountID int64              `json:"account_id"`
+	FromTime  pgtype.Timestamptz `json:"from_time"`
+	ToTime    pgtype.Timestamptz `json:"to_time"`
+	Limit     int32              `json:"limit"`
+	Offset    int32              `json:"offset"`
+}
+
+// from_time and to_time bound created_at when set, so that only the partitions
+// of the months in between are scanned.
+func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesAfter = `-- name: ListEntriesAfter :many
+SELECT id, account_id, amount, created_at FROM entries
+WHERE account_id = ANY($1::bigint[]) AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListEntriesAfterParams struct {
+	AccountIds []int64 `json:"account_ids"`
+	AfterID    int64   `json:"after_id"`
+	Limit      int32   `json:"limit"`
+}
+
+func (q *Queries) ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listEntriesAfter, arg.AccountIds, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntriesAfter = `-- name: ListStatementEntriesAfter :many
+SELECT id, account_id, amount, created_at FROM (
+  SELECT id, account_id, amount, created_at FROM entries
+  WHERE account_id = $1 AND id > $2
+  UNION ALL
+  SELECT id, account_id, amount, created_at FROM entries_archive
+  WHERE account_id = $1 AND id > $2
+) statement
+ORDER BY id
+LIMIT $3
+`
+
+type ListStatementEntriesAfterParams struct {
 	AccountID int64 `json:"account_id"`
+	AfterID   int64 `json:"after_id"`
 	Limit     int32 `json:"limit"`
-	Offset    int32 `json:"offset"`
 }
 
-func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+// the statement of an account spans its entries and the archived ones.
+func (q *Queries) ListStatementEntriesAfter(ctx context.Context, arg ListStatementEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listStatementEntriesAfter, arg.AccountID, arg.AfterID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -85,9 +215,50 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntriesBefore = `-- name: ListStatementEntriesBefore :many
+SELECT id, account_id, amount, created_at FROM (
+  SELECT id, account_id, amount, created_at FROM entries
+  WHERE account_id = $1 AND id < $2
+  UNION ALL
+  SELECT id, account_id, amount, created_at FROM entries_archive
+  WHERE account_id = $1 AND id < $2
+) statement
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListStatementEntriesBeforeParams struct {
+	AccountID int64 `json:"account_id"`
+	BeforeID  int64 `json:"before_id"`
+	Limit     int32 `json:"limit"`
+}
+
+// the statement of an account spans its entries and the archived ones.
+func (q *Queries) ListStatementEntriesBefore(ctx context.Context, arg ListStatementEntriesBeforeParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listStatementEntriesBefore, arg.AccountID, arg.BeforeID, arg.Limit)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
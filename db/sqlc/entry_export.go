@@ -0,0 +1,72 @@
+package db
+
+import "context"
+
+// The statuses of an entry export. A pending export waits for the worker; a ready one can be
+// downloaded until it expires, a failed one never will.
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+type CreateEntryExportTxParams struct {
+	CreateEntryExportParams
+	// AfterCreate returns the outbox tasks of the export once it is created, the one building
+	// its file.
+	AfterCreate func(export EntryExport) ([]CreateOutboxTaskParams, error)
+}
+
+// CreateEntryExportTx creates an export along with the outbox task that builds its file.
+func (store *SQLStore) CreateEntryExportTx(ctx context.Context, arg CreateEntryExportTxParams) (EntryExport, error) {
+	var export EntryExport
+
+	err := store.execTx(ctx, "CreateEntryExportTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		export, err = q.CreateEntryExport(ctx, arg.CreateEntryExportParams)
+		if err != nil || arg.AfterCreate == nil {
+			return err
+		}
+		tasks, err := arg.AfterCreate(export)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return export, err
+}
+
+type FinishEntryExportTxParams struct {
+	ID      int64
+	Content []byte
+	// Entries is the number of entries in Content.
+	Entries int64
+	// OutboxTasks are enqueued once the export is ready, e.g. the notification of its owner.
+	OutboxTasks []CreateOutboxTaskParams
+}
+
+// FinishEntryExportTx stores the file of a pending export and marks it ready. It returns
+// ErrRecordNotFound for an export that isn't pending anymore, e.g. built by an earlier try.
+func (store *SQLStore) FinishEntryExportTx(ctx context.Context, arg FinishEntryExportTxParams) (EntryExport, error) {
+	var export EntryExport
+
+	err := store.execTx(ctx, "FinishEntryExportTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		export, err = q.FinishEntryExport(ctx, FinishEntryExportParams{
+			ID:      arg.ID,
+			Entries: arg.Entries,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.CreateEntryExportFile(ctx, CreateEntryExportFileParams{
+			ExportID: export.ID,
+			Content:  arg.Content,
+		})
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, arg.OutboxTasks)
+	})
+	return export, err
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryExport(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	from := time.Now().Add(-time.Minute)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	_, err = testQuires.SetEntryCategory(context.Background(), SetEntryCategoryParams{
+		EntryID:   result.FromEntry.ID,
+		AccountID: account1.ID,
+		Category:  "family",
+		Tags:      []string{"gift"},
+	})
+	require.NoError(t, err)
+	to := time.Now().Add(time.Minute)
+
+	count, err := testQuires.CountExportEntries(context.Background(), CountExportEntriesParams{
+		AccountID: account1.ID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	entries, err := testQuires.ListExportEntries(context.Background(), ListExportEntriesParams{
+		AccountID: account1.ID,
+		FromTime:  from,
+		ToTime:    to,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, result.FromEntry.ID, entries[0].ID)
+	require.Equal(t, int64(-10), entries[0].Amount)
+	require.Equal(t, "family", entries[0].Category)
+	require.Equal(t, []string{"gift"}, entries[0].Tags)
+
+	entryExport, err := store.CreateEntryExportTx(context.Background(), CreateEntryExportTxParams{
+		CreateEntryExportParams: CreateEntryExportParams{
+			Owner:     account1.Owner,
+			AccountID: account1.ID,
+			Format:    "csv",
+			FromTime:  from,
+			ToTime:    to,
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+		AfterCreate: func(entryExport EntryExport) ([]CreateOutboxTaskParams, error) {
+			return []CreateOutboxTaskParams{{TaskType: "task:export_entries", Payload: []byte(`{}`), Queue: "low", MaxRetry: 3}}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExportPending, entryExport.Status)
+	require.False(t, entryExport.CompletedAt.Valid)
+
+	ready, err := store.FinishEntryExportTx(context.Background(), FinishEntryExportTxParams{
+		ID:      entryExport.ID,
+		Content: []byte("id,date\n"),
+		Entries: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExportReady, ready.Status)
+	require.Equal(t, int64(1), ready.Entries)
+	require.True(t, ready.CompletedAt.Valid)
+
+	file, err := testQuires.GetEntryExportFile(context.Background(), entryExport.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("id,date\n"), file)
+
+	// an export is completed once
+	_, err = store.FinishEntryExportTx(context.Background(), FinishEntryExportTxParams{ID: entryExport.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = testQuires.FailEntryExport(context.Background(), FailEntryExportParams{ID: entryExport.ID, Error: "failed"})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
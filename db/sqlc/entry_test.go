@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEntries(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	n := 1000
+	arg := make([]CreateEntriesParams, n)
+	for i := range arg {
+		arg[i] = CreateEntriesParams{
+			AccountID: account.ID,
+			Amount:    util.RandomInt(-100, 100),
+		}
+	}
+
+	count, err := store.CreateEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(n), count)
+
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.ID,
+		Limit:     int32(n + 1),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, n)
+	for i, entry := range entries {
+		require.Equal(t, arg[i].Amount, entry.Amount)
+	}
+}
+
+func TestArchiveEntriesBefore(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	var entries []Entry
+	for i := 0; i < 3; i++ {
+		entry, err := store.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    util.RandomInt(-100, 100),
+		})
+		require.NoError(t, err)
+		entries = append(entries, entry)
+	}
+
+	// archives every entry up to the second one, including the ones of the other tests
+	archived, err := store.ArchiveEntriesBefore(context.Background(), entries[1].CreatedAt.Add(time.Microsecond))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, archived, int64(2))
+
+	_, err = store.GetEntry(context.Background(), entries[0].ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	statement, err := store.ListStatementEntriesAfter(context.Background(), ListStatementEntriesAfterParams{
+		AccountID: account.ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, statement, 3)
+	for i, entry := range statement {
+		require.Equal(t, entries[i].ID, entry.ID)
+		require.Equal(t, entries[i].Amount, entry.Amount)
+	}
+}
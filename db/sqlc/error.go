@@ -0,0 +1,37 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes the handlers turn into client errors, or execTx retries,
+// see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	ForeignKeyViolation  = "23503"
+	UniqueViolation      = "23505"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// ErrRecordNotFound is returned by the :one queries when no row matches.
+var ErrRecordNotFound = pgx.ErrNoRows
+
+// ErrorCode returns the PostgreSQL error code of err, or "" if err doesn't come from the server.
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// ErrAccountFrozen is returned by the transactions that would move the money of a frozen account,
+// and by FreezeAccountTx for an account frozen already.
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// ErrTransfersBlocked is returned by the transactions that would move money while the maintenance
+// mode blocks the transfers or is read-only.
+var ErrTransfersBlocked = errors.New("transfers are suspended for maintenance, retry later")
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/backendmaster/simple_bank/events"
+)
+
+// writeOutboxEvent stores a domain event in the event outbox of the transaction, for the event
+// relay of the worker package to publish to the broker once the transaction commits.
+func writeOutboxEvent(ctx context.Context, q *Queries, eventType, key string, payload interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType: eventType,
+		EventKey:  key,
+		Payload:   jsonPayload,
+	})
+	return err
+}
+
+func createUserCreatedEvent(ctx context.Context, q *Queries, user User) error {
+	return writeOutboxEvent(ctx, q, events.TypeUserCreated, user.Username, events.UserCreated{
+		Username:  user.Username,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+	})
+}
+
+func createTransferCompletedEvent(ctx context.Context, q *Queries, transfer Transfer, currency string) error {
+	return writeOutboxEvent(ctx, q, events.TypeTransferCompleted,
+		strconv.FormatInt(transfer.FromAccountID, 10), events.TransferCompleted{
+			TransferID:    transfer.ID,
+			FromAccountID: transfer.FromAccountID,
+			ToAccountID:   transfer.ToAccountID,
+			Amount:        transfer.Amount,
+			Currency:      currency,
+			CreatedAt:     transfer.CreatedAt,
+		})
+}
+
+func createAccountFrozenEvent(ctx context.Context, q *Queries, account Account, reason string) error {
+	return writeOutboxEvent(ctx, q, events.TypeAccountFrozen, strconv.FormatInt(account.ID, 10), events.AccountFrozen{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Reason:    reason,
+		FrozenAt:  account.FrozenAt.Time,
+	})
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FXOwner is the user owning the fx accounts, see GetFXAccount.
+const FXOwner = "bank.fx"
+
+// ErrExchangeMismatch is returned by TransferTx for an exchange whose currencies aren't those of
+// the accounts of the transfer.
+var ErrExchangeMismatch = errors.New("exchange currencies don't match the accounts")
+
+// SaveExchangeRatesTx stores the rates fetched from the FX provider at once, so that the
+// conversions never mix the rates of two fetches.
+func (store *SQLStore) SaveExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) ([]ExchangeRate, error) {
+	saved := make([]ExchangeRate, 0, len(rates))
+
+	err := store.execTx(ctx, "SaveExchangeRatesTx", func(ctx context.Context, q *Queries) error {
+		saved = saved[:0]
+		for _, rate := range rates {
+			row, err := q.UpsertExchangeRate(ctx, rate)
+			if err != nil {
+				return err
+			}
+			saved = append(saved, row)
+		}
+		return nil
+	})
+	return saved, err
+}
+
+// Exchange converts a transfer between accounts in different currencies.
+type Exchange struct {
+	FromCurrency string
+	ToCurrency   string
+	// ToAmount is what the payee is credited, in the minor units of ToCurrency.
+	ToAmount int64
+}
+
+// exchangeMoney moves the money of a transfer with an exchange through the fx accounts: the payer
+// pays the amount to the fx account of its currency, then the fx account of the currency of the
+// payee pays it ToAmount. The four accounts are locked in the order of their ids before either
+// transfer runs, so that exchanges in opposite directions can't deadlock, and the two transfers
+// are linked in transfer_exchanges. The result holds the transfer of the payer, with the entry and
+// account of the payee, and the transfer to the payee as ExchangeTransfer.
+func (store *SQLStore) exchangeMoney(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	sellingAccount, err := q.GetFXAccount(ctx, arg.Exchange.FromCurrency)
+	if err != nil {
+		return result, fmt.Errorf("no fx account in %s: %w", arg.Exchange.FromCurrency, err)
+	}
+	buyingAccount, err := q.GetFXAccount(ctx, arg.Exchange.ToCurrency)
+	if err != nil {
+		return result, fmt.Errorf("no fx account in %s: %w", arg.Exchange.ToCurrency, err)
+	}
+	err = store.lockTransferAccounts(ctx, q, arg.FromAccountID, sellingAccount.ID, buyingAccount.ID, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	sold, err := store.moveMoney(ctx, q, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   sellingAccount.ID,
+		Amount:        arg.Amount,
+		Memo:          arg.Memo,
+	})
+	if err != nil {
+		return result, err
+	}
+	bought, err := store.moveMoney(ctx, q, CreateTransferParams{
+		FromAccountID: buyingAccount.ID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Exchange.ToAmount,
+		Memo:          arg.Memo,
+	})
+	if err != nil {
+		return result, err
+	}
+	_, err = q.CreateTransferExchange(ctx, CreateTransferExchangeParams{
+		TransferID:         sold.Transfer.ID,
+		ExchangeTransferID: bought.Transfer.ID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = TransferTxResult{
+		Transfer:         sold.Transfer,
+		FromAccount:      sold.FromAccount,
+		ToAccount:        bought.ToAccount,
+		FromEntry:        sold.FromEntry,
+		ToEntry:          bought.ToEntry,
+		ExchangeTransfer: &bought.Transfer,
+	}
+	if result.FromAccount.Currency != arg.Exchange.FromCurrency || result.ToAccount.Currency != arg.Exchange.ToCurrency {
+		return result, ErrExchangeMismatch
+	}
+	// the fx accounts belong to the bank, so neither leg checked the tenants of the two users
+	return result, checkTenants(result.FromAccount, result.ToAccount)
+}
+
+// creditedTransfer returns the transfer that credited the payee of the transfer: the transfer
+// itself, or the transfer to the payee when it is the transfer of the payer of an exchange.
+func creditedTransfer(ctx context.Context, q *Queries, transfer Transfer) (Transfer, error) {
+	exchange, err := q.GetTransferExchange(ctx, transfer.ID)
+	if errors.Is(err, ErrRecordNotFound) || (err == nil && exchange.TransferID != transfer.ID) {
+		return transfer, nil
+	}
+	if err != nil {
+		return transfer, err
+	}
+	return q.GetTransfer(ctx, exchange.ExchangeTransferID)
+}
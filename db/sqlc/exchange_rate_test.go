@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveExchangeRatesTx(t *testing.T) {
+	store := NewStore(testDB)
+	publishedAt := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	saved, err := store.SaveExchangeRatesTx(context.Background(), []UpsertExchangeRateParams{
+		{BaseCurrency: util.EUR, QuoteCurrency: util.USD, RateNanos: 1_082_300_000, Source: "ecb", PublishedAt: publishedAt},
+		{BaseCurrency: util.EUR, QuoteCurrency: "JPY", RateNanos: 162_450_000_000, Source: "ecb", PublishedAt: publishedAt},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	require.WithinDuration(t, time.Now(), saved[0].FetchedAt, time.Minute)
+
+	// a later fetch replaces the rate
+	saved, err = store.SaveExchangeRatesTx(context.Background(), []UpsertExchangeRateParams{
+		{BaseCurrency: util.EUR, QuoteCurrency: util.USD, RateNanos: 1_090_000_000, Source: "ecb", PublishedAt: publishedAt.AddDate(0, 0, 1)},
+	})
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+
+	rates, err := testQuires.ListExchangeRates(context.Background())
+	require.NoError(t, err)
+	var usd ExchangeRate
+	for _, rate := range rates {
+		if rate.BaseCurrency == util.EUR && rate.QuoteCurrency == util.USD {
+			usd = rate
+		}
+	}
+	require.Equal(t, int64(1_090_000_000), usd.RateNanos)
+	require.True(t, usd.PublishedAt.Equal(publishedAt.AddDate(0, 0, 1)))
+}
+
+func TestTransferTxExchange(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(0))
+	usdFX, err := testQuires.GetFXAccount(context.Background(), util.USD)
+	require.NoError(t, err)
+	eurFX, err := testQuires.GetFXAccount(context.Background(), util.EUR)
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        100,
+		Exchange:      &Exchange{FromCurrency: util.USD, ToCurrency: util.EUR, ToAmount: 92},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, usdFX.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.NotNil(t, result.ExchangeTransfer)
+	require.Equal(t, eurFX.ID, result.ExchangeTransfer.FromAccountID)
+	require.Equal(t, payee.ID, result.ExchangeTransfer.ToAccountID)
+	require.Equal(t, int64(92), result.ExchangeTransfer.Amount)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(92), result.ToEntry.Amount)
+	require.Equal(t, int64(900), result.FromAccount.Balance)
+	require.Equal(t, int64(92), result.ToAccount.Balance)
+
+	exchange, err := testQuires.GetTransferExchange(context.Background(), result.ExchangeTransfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, exchange.TransferID)
+	require.Equal(t, result.ExchangeTransfer.ID, exchange.ExchangeTransferID)
+
+	// an exchange has to match the currencies of the accounts
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        100,
+		Exchange:      &Exchange{FromCurrency: util.USD, ToCurrency: util.CAD, ToAmount: 137},
+	})
+	require.ErrorIs(t, err, ErrExchangeMismatch)
+}
+
+func TestTransferTxExchangeDeadLock(t *testing.T) {
+	store := NewStore(testDB)
+	usdAccount := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+	eurAccount := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(1000))
+
+	// USD to EUR and EUR to USD cross the two fx accounts in opposite directions
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		arg := TransferTxParams{
+			FromAccountID: usdAccount.ID,
+			ToAccountID:   eurAccount.ID,
+			Amount:        10,
+			Exchange:      &Exchange{FromCurrency: util.USD, ToCurrency: util.EUR, ToAmount: 10},
+		}
+		if i%2 == 1 {
+			arg.FromAccountID, arg.ToAccountID = eurAccount.ID, usdAccount.ID
+			arg.Exchange = &Exchange{FromCurrency: util.EUR, ToCurrency: util.USD, ToAmount: 10}
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), arg)
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedUSD, err := testQuires.GetAccount(context.Background(), usdAccount.ID)
+	require.NoError(t, err)
+	updatedEUR, err := testQuires.GetAccount(context.Background(), eurAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, usdAccount.Balance, updatedUSD.Balance)
+	require.Equal(t, eurAccount.Balance, updatedEUR.Balance)
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SuspenseOwner is the user owning the suspense accounts, see GetSuspenseAccount.
+const SuspenseOwner = "bank.suspense"
+
+// The statuses of an external transfer. A pending transfer waits for the settlement worker to
+// submit it to its clearing network; a submitted one waits for the network to settle it, or to
+// return it, e.g. for a closed beneficiary account.
+const (
+	ExternalPending   = "pending"
+	ExternalSubmitted = "submitted"
+	ExternalSettled   = "settled"
+	ExternalReturned  = "returned"
+)
+
+var (
+	// ErrExternalTransferClosed is returned by CompleteExternalTransferTx for a transfer already
+	// settled or returned.
+	ErrExternalTransferClosed = errors.New("external transfer is closed")
+	// ErrInvalidExternalTransferStatus is returned by CompleteExternalTransferTx for a status it
+	// can't complete a transfer with, or a transfer not submitted yet.
+	ErrInvalidExternalTransferStatus = errors.New("invalid external transfer status")
+)
+
+type CreateExternalTransferTxParams struct {
+	AccountID          int64
+	Amount             int64
+	Rail               string
+	BeneficiaryName    string
+	BeneficiaryAccount string
+	BeneficiaryRouting string
+	Memo               string
+	// AlertTask is that of the debit, see TransferTxParams.
+	AlertTask AlertTaskFunc
+}
+
+type CreateExternalTransferTxResult struct {
+	ExternalTransfer ExternalTransfer `json:"external_transfer"`
+	FromAccount      Account          `json:"from_account"`
+	// SuspenseAccountID is the suspense account the amount went to, for the callers caching the
+	// accounts.
+	SuspenseAccountID int64 `json:"-"`
+}
+
+// CreateExternalTransferTx debits the account of the amount of an external transfer, which the
+// suspense account of its currency holds until the transfer settles. The debit is a transfer to
+// the suspense account, so it is refused like TransferTx refuses it, e.g. for a frozen account.
+func (store *SQLStore) CreateExternalTransferTx(ctx context.Context, arg CreateExternalTransferTxParams) (CreateExternalTransferTxResult, error) {
+	var result CreateExternalTransferTxResult
+
+	err := store.execTx(ctx, "CreateExternalTransferTx", func(ctx context.Context, q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		suspense, err := q.GetSuspenseAccount(ctx, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		memo := arg.Memo
+		if memo == "" {
+			memo = "external transfer to " + arg.BeneficiaryName
+		}
+		debit, err := store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   suspense.ID,
+			Amount:        arg.Amount,
+			Memo:          memo,
+			AlertTask:     arg.AlertTask,
+		})
+		if err != nil {
+			return err
+		}
+		result.FromAccount = debit.FromAccount
+		result.SuspenseAccountID = suspense.ID
+
+		result.ExternalTransfer, err = q.CreateExternalTransfer(ctx, CreateExternalTransferParams{
+			AccountID:          account.ID,
+			Amount:             arg.Amount,
+			Currency:           account.Currency,
+			Rail:               arg.Rail,
+			BeneficiaryName:    arg.BeneficiaryName,
+			BeneficiaryAccount: arg.BeneficiaryAccount,
+			BeneficiaryRouting: arg.BeneficiaryRouting,
+			Memo:               arg.Memo,
+			DebitTransferID:    debit.Transfer.ID,
+		})
+		return err
+	})
+	return result, err
+}
+
+type CompleteExternalTransferTxParams struct {
+	ID int64
+	// Status is settled, or returned with a ReturnReason.
+	Status       string
+	ReturnReason string
+	// AfterComplete, when set, returns the outbox tasks of the completed transfer, e.g. to notify
+	// the owner of the account.
+	AfterComplete func(transfer ExternalTransfer) ([]CreateOutboxTaskParams, error)
+}
+
+type CompleteExternalTransferTxResult struct {
+	ExternalTransfer ExternalTransfer `json:"external_transfer"`
+	// Return is the transfer paying a returned transfer back.
+	Return *TransferTxResult `json:"return,omitempty"`
+	// SuspenseAccountID is the suspense account the amount left, for the callers caching the
+	// accounts.
+	SuspenseAccountID int64 `json:"-"`
+}
+
+// CompleteExternalTransferTx settles or returns a submitted external transfer. Settling it takes
+// the amount out of the suspense account with an entry, the money having left the bank; returning
+// it transfers the amount back to the account, even a frozen one.
+func (store *SQLStore) CompleteExternalTransferTx(ctx context.Context, arg CompleteExternalTransferTxParams) (CompleteExternalTransferTxResult, error) {
+	var result CompleteExternalTransferTxResult
+
+	if arg.Status != ExternalSettled && arg.Status != ExternalReturned {
+		return result, fmt.Errorf("%w %q", ErrInvalidExternalTransferStatus, arg.Status)
+	}
+
+	err := store.execTx(ctx, "CompleteExternalTransferTx", func(ctx context.Context, q *Queries) error {
+		result.Return = nil
+		transfer, err := q.GetExternalTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		switch transfer.Status {
+		case ExternalSettled, ExternalReturned:
+			return ErrExternalTransferClosed
+		case ExternalPending:
+			return fmt.Errorf("%w: external transfer isn't submitted yet", ErrInvalidExternalTransferStatus)
+		}
+		suspense, err := q.GetSuspenseAccount(ctx, transfer.Currency)
+		if err != nil {
+			return err
+		}
+		result.SuspenseAccountID = suspense.ID
+
+		update := CompleteExternalTransferParams{
+			ID:           transfer.ID,
+			Status:       arg.Status,
+			ReturnReason: arg.ReturnReason,
+		}
+		if arg.Status == ExternalSettled {
+			if err := settleSuspense(ctx, q, store.balanceLock, suspense.ID, transfer.Amount); err != nil {
+				return err
+			}
+		} else {
+			refund, err := store.moveMoney(ctx, q, CreateTransferParams{
+				FromAccountID: suspense.ID,
+				ToAccountID:   transfer.AccountID,
+				Amount:        transfer.Amount,
+				Memo:          fmt.Sprintf("return of external transfer #%d: %s", transfer.ID, arg.ReturnReason),
+			})
+			if err != nil {
+				return err
+			}
+			if err := createTransferCompletedEvent(ctx, q, refund.Transfer, refund.FromAccount.Currency); err != nil {
+				return err
+			}
+			result.Return = &refund
+			update.ReturnTransferID = pgtype.Int8{Int64: refund.Transfer.ID, Valid: true}
+		}
+
+		result.ExternalTransfer, err = q.CompleteExternalTransfer(ctx, update)
+		if err != nil || arg.AfterComplete == nil {
+			return err
+		}
+		tasks, err := arg.AfterComplete(result.ExternalTransfer)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return result, err
+}
+
+// settleSuspense takes the amount of a settled transfer out of the suspense account, with an
+// entry of its own like an adjustment, serializing the update with the transfers.
+func settleSuspense(ctx context.Context, q *Queries, balanceLock BalanceLock, suspenseID int64, amount int64) error {
+	if err := checkTransfersBlocked(ctx, q); err != nil {
+		return err
+	}
+	if balanceLock == BalanceLockAdvisory {
+		if err := q.LockAccountBalance(ctx, suspenseID); err != nil {
+			return err
+		}
+	}
+	if _, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: suspenseID,
+		Amount:    -amount,
+	}); err != nil {
+		return err
+	}
+	_, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     suspenseID,
+		Amount: -amount,
+	})
+	return err
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// createSubmittedExternalTransfer debits account of amount for an external transfer and
+// submits it.
+func createSubmittedExternalTransfer(t *testing.T, store Store, account Account, amount int64) ExternalTransfer {
+	result, err := store.CreateExternalTransferTx(context.Background(), CreateExternalTransferTxParams{
+		AccountID:          account.ID,
+		Amount:             amount,
+		Rail:               "sepa",
+		BeneficiaryName:    "Anna Schmidt",
+		BeneficiaryAccount: "DE89370400440532013000",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExternalPending, result.ExternalTransfer.Status)
+	require.Equal(t, account.Balance-amount, result.FromAccount.Balance)
+
+	transfer, err := store.SubmitExternalTransfer(context.Background(), SubmitExternalTransferParams{
+		ID:        result.ExternalTransfer.ID,
+		Reference: util.RandomString(10),
+		SettleAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExternalSubmitted, transfer.Status)
+	return transfer
+}
+
+func TestSettleExternalTransfer(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.EUR))
+	suspense, err := store.GetSuspenseAccount(context.Background(), util.EUR)
+	require.NoError(t, err)
+
+	transfer := createSubmittedExternalTransfer(t, store, account, 10)
+
+	result, err := store.CompleteExternalTransferTx(context.Background(), CompleteExternalTransferTxParams{
+		ID:     transfer.ID,
+		Status: ExternalSettled,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExternalSettled, result.ExternalTransfer.Status)
+	require.True(t, result.ExternalTransfer.CompletedAt.Valid)
+	require.Nil(t, result.Return)
+
+	// the suspense account got the amount then gave it away
+	settled, err := store.GetAccount(context.Background(), suspense.ID)
+	require.NoError(t, err)
+	require.Equal(t, suspense.Balance, settled.Balance)
+
+	_, err = store.CompleteExternalTransferTx(context.Background(), CompleteExternalTransferTxParams{
+		ID:     transfer.ID,
+		Status: ExternalReturned,
+	})
+	require.ErrorIs(t, err, ErrExternalTransferClosed)
+}
+
+func TestReturnExternalTransfer(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.EUR))
+	transfer := createSubmittedExternalTransfer(t, store, account, 10)
+
+	result, err := store.CompleteExternalTransferTx(context.Background(), CompleteExternalTransferTxParams{
+		ID:           transfer.ID,
+		Status:       ExternalReturned,
+		ReturnReason: "AC04: closed account number",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExternalReturned, result.ExternalTransfer.Status)
+	require.Equal(t, "AC04: closed account number", result.ExternalTransfer.ReturnReason)
+	require.NotNil(t, result.Return)
+	require.Equal(t, result.Return.Transfer.ID, result.ExternalTransfer.ReturnTransferID.Int64)
+	require.Equal(t, account.Balance, result.Return.ToAccount.Balance)
+}
+
+func TestCompleteExternalTransferNotSubmitted(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD))
+	created, err := store.CreateExternalTransferTx(context.Background(), CreateExternalTransferTxParams{
+		AccountID:          account.ID,
+		Amount:             10,
+		Rail:               "ach",
+		BeneficiaryName:    "John Smith",
+		BeneficiaryAccount: "000123456789",
+		BeneficiaryRouting: "110000000",
+	})
+	require.NoError(t, err)
+
+	_, err = store.CompleteExternalTransferTx(context.Background(), CompleteExternalTransferTxParams{
+		ID:     created.ExternalTransfer.ID,
+		Status: ExternalSettled,
+	})
+	require.ErrorIs(t, err, ErrInvalidExternalTransferStatus)
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrImpersonationRevoked is returned by CheckImpersonation for an impersonation that was revoked.
+var ErrImpersonationRevoked = errors.New("impersonation is revoked")
+
+// CheckImpersonation fails when the impersonation of the token id was revoked. Unlike the tokens
+// of a login, the impersonation tokens are checked against the store on every request, so that
+// revoking one takes effect right away.
+func CheckImpersonation(ctx context.Context, store SessionStore, id uuid.UUID) error {
+	impersonation, err := store.GetImpersonation(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrImpersonationRevoked
+		}
+		return err
+	}
+	if impersonation.RevokedAt.Valid {
+		return ErrImpersonationRevoked
+	}
+	return nil
+}
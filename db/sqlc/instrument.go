@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/backendmaster/simple_bank/db/sqlc")
+
+// instrumentedDB traces and records the duration of every query sent through it, labelled with
+// the name sqlc puts in the "-- name: GetUser :one" comment in front of each query.
+type instrumentedDB struct {
+	db DBTX
+}
+
+func newInstrumentedDB(db DBTX) DBTX {
+	return &instrumentedDB{db: db}
+}
+
+func (i *instrumentedDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, end := startQuery(ctx, query)
+	tag, err := i.db.Exec(ctx, query, args...)
+	end(err)
+	return tag, err
+}
+
+func (i *instrumentedDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	ctx, end := startQuery(ctx, query)
+	rows, err := i.db.Query(ctx, query, args...)
+	end(err)
+	return rows, err
+}
+
+// QueryRow ends the query once the row is scanned, since pgx only reports its errors then.
+func (i *instrumentedDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	ctx, end := startQuery(ctx, query)
+	return &instrumentedRow{row: i.db.QueryRow(ctx, query, args...), end: end}
+}
+
+// CopyFrom is labelled copy_from_<table>, as there is no query to take a name from.
+func (i *instrumentedDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	statement := fmt.Sprintf("COPY %s (%s) FROM STDIN", tableName.Sanitize(), strings.Join(columnNames, ", "))
+	ctx, end := startOperation(ctx, "copy_from_"+strings.Join(tableName, "_"), statement)
+	count, err := i.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	end(err)
+	return count, err
+}
+
+type instrumentedRow struct {
+	row pgx.Row
+	end func(err error)
+}
+
+func (r *instrumentedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// a missing row is an answer, not a failed query
+		r.end(nil)
+	} else {
+		r.end(err)
+	}
+	return err
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	return startOperation(ctx, queryName(query), query)
+}
+
+func startOperation(ctx context.Context, name string, statement string) (context.Context, func(err error)) {
+	timeNow := time.Now()
+	ctx, span := tracer.Start(ctx, "db."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationKey.String(name),
+			semconv.DBStatementKey.String(statement),
+		),
+	)
+
+	return ctx, func(err error) {
+		metrics.ObserveDBQuery(name, time.Since(timeNow), err)
+		endSpan(span, err)
+	}
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func queryName(query string) string {
+	const prefix = "-- name: "
+	if !strings.HasPrefix(query, prefix) {
+		return "unknown"
+	}
+	fields := strings.Fields(query[len(prefix):])
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return fields[0]
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ledgerReportLimit caps the discrepancies of each kind a report lists.
+const ledgerReportLimit = 1000
+
+// LedgerReport lists the discrepancies found by VerifyLedger.
+type LedgerReport struct {
+	CheckedAt           time.Time                    `json:"checked_at"`
+	BalanceMismatches   []ListBalanceMismatchesRow   `json:"balance_mismatches"`
+	UnbalancedTransfers []ListUnbalancedTransfersRow `json:"unbalanced_transfers"`
+}
+
+// OK tells whether the ledger is consistent.
+func (report LedgerReport) OK() bool {
+	return len(report.BalanceMismatches) == 0 && len(report.UnbalancedTransfers) == 0
+}
+
+// VerifyLedger checks that the balance of every account is the sum of its entries, archived or not, and that every
+// transfer has exactly one debit and one credit entry. Both checks read the same snapshot, so
+// that a transfer committing in between can't show up as a discrepancy.
+func (store *SQLStore) VerifyLedger(ctx context.Context) (LedgerReport, error) {
+	report := LedgerReport{CheckedAt: time.Now()}
+
+	tx, err := store.connPool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return report, err
+	}
+	// nothing to commit in a read-only transaction
+	defer tx.Rollback(context.Background())
+
+	q := New(newInstrumentedDB(tx))
+	report.BalanceMismatches, err = q.ListBalanceMismatches(ctx, ledgerReportLimit)
+	if err != nil {
+		return report, err
+	}
+	report.UnbalancedTransfers, err = q.ListUnbalancedTransfers(ctx, ledgerReportLimit)
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
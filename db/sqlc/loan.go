@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LendingOwner is the user owning the lending accounts, see GetLendingAccount.
+const LendingOwner = "bank.lending"
+
+// The statuses of a loan. An active loan is delinquent once an installment past due couldn't be
+// collected, and back to active once every installment past due is paid.
+const (
+	LoanActive     = "active"
+	LoanDelinquent = "delinquent"
+	LoanPaidOff    = "paid_off"
+)
+
+// The statuses of a loan installment.
+const (
+	InstallmentDue  = "due"
+	InstallmentPaid = "paid"
+)
+
+var (
+	// ErrLoanOfferInactive is returned by DisburseLoanTx for an offer the admins retired.
+	ErrLoanOfferInactive = errors.New("loan offer is inactive")
+	// ErrInvalidLoanAmount is returned by DisburseLoanTx for a principal out of the range of the
+	// offer.
+	ErrInvalidLoanAmount = errors.New("loan amount is out of the range of the offer")
+	// ErrInstallmentPaid is returned by RepayLoanInstallmentTx for an installment already paid.
+	ErrInstallmentPaid = errors.New("loan installment is paid")
+)
+
+type CreateLoanOfferTxParams struct {
+	CreateLoanOfferParams
+	Audit CreateAuditEntryParams
+}
+
+// CreateLoanOfferTx creates a loan offer, which the users can borrow under right away.
+func (store *SQLStore) CreateLoanOfferTx(ctx context.Context, arg CreateLoanOfferTxParams) (LoanOffer, error) {
+	var offer LoanOffer
+
+	err := store.execTx(ctx, "CreateLoanOfferTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		offer, err = q.CreateLoanOffer(ctx, arg.CreateLoanOfferParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return offer, err
+}
+
+type SetLoanOfferActiveTxParams struct {
+	SetLoanOfferActiveParams
+	Audit CreateAuditEntryParams
+}
+
+// SetLoanOfferActiveTx retires a loan offer, or offers it again. The loans disbursed under it
+// keep their terms.
+func (store *SQLStore) SetLoanOfferActiveTx(ctx context.Context, arg SetLoanOfferActiveTxParams) (LoanOffer, error) {
+	var offer LoanOffer
+
+	err := store.execTx(ctx, "SetLoanOfferActiveTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		offer, err = q.SetLoanOfferActive(ctx, arg.SetLoanOfferActiveParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return offer, err
+}
+
+type DisburseLoanTxParams struct {
+	OfferID   int64
+	AccountID int64
+	Amount    int64
+	// Installments are the amortization schedule of the loan, their LoanID left to the
+	// transaction. Their principals add up to Amount.
+	Installments []CreateLoanInstallmentParams
+	// OutboxTasks are written in the same transaction as the disbursement, see TransferTxParams.
+	OutboxTasks []CreateOutboxTaskParams
+}
+
+type DisburseLoanTxResult struct {
+	Loan         Loan              `json:"loan"`
+	Installments []LoanInstallment `json:"installments"`
+	Disbursement TransferTxResult  `json:"disbursement"`
+}
+
+// DisburseLoanTx lends the amount to the account under an active offer, with a transfer from the
+// lending account of its currency, refused like TransferTx refuses it, e.g. for a frozen account.
+// The loan keeps the terms of the offer at the time, and its schedule.
+func (store *SQLStore) DisburseLoanTx(ctx context.Context, arg DisburseLoanTxParams) (DisburseLoanTxResult, error) {
+	var result DisburseLoanTxResult
+
+	var scheduled int64
+	for _, installment := range arg.Installments {
+		scheduled += installment.Principal
+	}
+	if len(arg.Installments) == 0 || scheduled != arg.Amount {
+		return result, fmt.Errorf("the schedule repays %d of a principal of %d", scheduled, arg.Amount)
+	}
+
+	err := store.execTx(ctx, "DisburseLoanTx", func(ctx context.Context, q *Queries) error {
+		offer, err := q.GetLoanOffer(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+		if !offer.Active {
+			return ErrLoanOfferInactive
+		}
+		if arg.Amount < offer.MinAmount || arg.Amount > offer.MaxAmount {
+			return fmt.Errorf("%w: %d to %d", ErrInvalidLoanAmount, offer.MinAmount, offer.MaxAmount)
+		}
+		lending, err := q.GetLendingAccount(ctx, offer.Currency)
+		if err != nil {
+			return err
+		}
+
+		result.Disbursement, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: lending.ID,
+			ToAccountID:   arg.AccountID,
+			Amount:        arg.Amount,
+			Memo:          "disbursement of loan: " + offer.Name,
+			OutboxTasks:   arg.OutboxTasks,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Loan, err = q.CreateLoan(ctx, CreateLoanParams{
+			OfferID:                offer.ID,
+			AccountID:              arg.AccountID,
+			Principal:              arg.Amount,
+			Currency:               offer.Currency,
+			AnnualRateBps:          offer.AnnualRateBps,
+			TermMonths:             offer.TermMonths,
+			DisbursementTransferID: result.Disbursement.Transfer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Installments = make([]LoanInstallment, 0, len(arg.Installments))
+		for _, installment := range arg.Installments {
+			installment.LoanID = result.Loan.ID
+			created, err := q.CreateLoanInstallment(ctx, installment)
+			if err != nil {
+				return err
+			}
+			result.Installments = append(result.Installments, created)
+		}
+		return nil
+	})
+	return result, err
+}
+
+type RepayLoanInstallmentTxParams struct {
+	ID int64
+	// AlertTask is that of the repayment, see TransferTxParams.
+	AlertTask AlertTaskFunc
+}
+
+type RepayLoanInstallmentTxResult struct {
+	Installment LoanInstallment  `json:"installment"`
+	Loan        Loan             `json:"loan"`
+	Repayment   TransferTxResult `json:"repayment"`
+}
+
+// RepayLoanInstallmentTx collects an installment from the account of its loan, to the lending
+// account. Unlike a transfer, the repayment can't take the available balance of the account below
+// zero, in which case it fails with ErrInsufficientFunds and the installment stays due. The loan
+// is paid off with its last installment, and back to active once no installment is past due.
+func (store *SQLStore) RepayLoanInstallmentTx(ctx context.Context, arg RepayLoanInstallmentTxParams) (RepayLoanInstallmentTxResult, error) {
+	var result RepayLoanInstallmentTxResult
+
+	err := store.execTx(ctx, "RepayLoanInstallmentTx", func(ctx context.Context, q *Queries) error {
+		installment, err := q.GetLoanInstallmentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if installment.Status != InstallmentDue {
+			return ErrInstallmentPaid
+		}
+		// the installments of a loan lock it to update its status, one at a time
+		loan, err := q.GetLoanForUpdate(ctx, installment.LoanID)
+		if err != nil {
+			return err
+		}
+		lending, err := q.GetLendingAccount(ctx, loan.Currency)
+		if err != nil {
+			return err
+		}
+
+		result.Repayment, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: loan.AccountID,
+			ToAccountID:   lending.ID,
+			Amount:        installment.Amount,
+			Memo:          fmt.Sprintf("installment %d of loan #%d", installment.Number, loan.ID),
+			AlertTask:     arg.AlertTask,
+		})
+		if err != nil {
+			return err
+		}
+		if result.Repayment.FromAccount.AvailableBalance < 0 {
+			return ErrInsufficientFunds
+		}
+
+		result.Installment, err = q.MarkLoanInstallmentPaid(ctx, MarkLoanInstallmentPaidParams{
+			ID:         installment.ID,
+			TransferID: pgtype.Int8{Int64: result.Repayment.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		standing, err := q.GetLoanStanding(ctx, loan.ID)
+		if err != nil {
+			return err
+		}
+		status := LoanActive
+		switch {
+		case standing.UnpaidInstallments == 0:
+			status = LoanPaidOff
+		case standing.OverdueInstallments > 0:
+			status = LoanDelinquent
+		}
+		result.Loan = loan
+		if status != loan.Status {
+			result.Loan, err = q.SetLoanStatus(ctx, SetLoanStatusParams{
+				ID:     loan.ID,
+				Status: status,
+			})
+		}
+		return err
+	})
+	return result, err
+}
+
+type MarkLoanDelinquentTxParams struct {
+	ID int64
+	// AfterMark, when set, returns the outbox tasks of a loan turning delinquent, e.g. to notify
+	// the owner of its account.
+	AfterMark func(loan Loan) ([]CreateOutboxTaskParams, error)
+}
+
+// MarkLoanDelinquentTx moves an active loan to delinquent, once an installment past due couldn't
+// be collected. A loan delinquent already is left as is, with ErrRecordNotFound.
+func (store *SQLStore) MarkLoanDelinquentTx(ctx context.Context, arg MarkLoanDelinquentTxParams) (Loan, error) {
+	var loan Loan
+
+	err := store.execTx(ctx, "MarkLoanDelinquentTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		loan, err = q.MarkLoanDelinquent(ctx, arg.ID)
+		if err != nil || arg.AfterMark == nil {
+			return err
+		}
+		tasks, err := arg.AfterMark(loan)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return loan, err
+}
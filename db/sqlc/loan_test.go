@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomLoanOffer(t *testing.T, store Store) LoanOffer {
+	admin := createRandomUser(t)
+	offer, err := store.CreateLoanOfferTx(context.Background(), CreateLoanOfferTxParams{
+		CreateLoanOfferParams: CreateLoanOfferParams{
+			Name:          "loan " + util.RandomString(6),
+			Currency:      util.USD,
+			MinAmount:     100,
+			MaxAmount:     1000,
+			AnnualRateBps: 1200,
+			TermMonths:    2,
+			CreatedBy:     admin.Username,
+		},
+		Audit: randomAuditEntry("loan_offer:" + util.RandomString(6)),
+	})
+	require.NoError(t, err)
+	require.True(t, offer.Active)
+	return offer
+}
+
+func TestLoanRepayment(t *testing.T) {
+	store := NewStore(testDB)
+	offer := createRandomLoanOffer(t, store)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+
+	now := time.Now()
+	disbursed, err := store.DisburseLoanTx(context.Background(), DisburseLoanTxParams{
+		OfferID:   offer.ID,
+		AccountID: account.ID,
+		Amount:    200,
+		Installments: []CreateLoanInstallmentParams{
+			{Number: 1, DueAt: now.Add(-time.Hour), Principal: 100, Interest: 150, Amount: 250},
+			{Number: 2, DueAt: now.AddDate(0, 1, 0), Principal: 100, Interest: 0, Amount: 100},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, LoanActive, disbursed.Loan.Status)
+	require.Equal(t, int64(200), disbursed.Loan.Principal)
+	require.Equal(t, offer.AnnualRateBps, disbursed.Loan.AnnualRateBps)
+	require.Equal(t, disbursed.Disbursement.Transfer.ID, disbursed.Loan.DisbursementTransferID)
+	require.Equal(t, int64(200), disbursed.Disbursement.ToAccount.Balance)
+	require.Len(t, disbursed.Installments, 2)
+	first, second := disbursed.Installments[0], disbursed.Installments[1]
+
+	// the repayment can't overdraw the account, and leaves the installment due
+	_, err = store.RepayLoanInstallmentTx(context.Background(), RepayLoanInstallmentTxParams{ID: first.ID})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	account, err = testQuires.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), account.Balance)
+
+	delinquent, err := store.MarkLoanDelinquentTx(context.Background(), MarkLoanDelinquentTxParams{ID: disbursed.Loan.ID})
+	require.NoError(t, err)
+	require.Equal(t, LoanDelinquent, delinquent.Status)
+	_, err = store.MarkLoanDelinquentTx(context.Background(), MarkLoanDelinquentTxParams{ID: disbursed.Loan.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	delinquents, err := testQuires.ListDelinquentLoans(context.Background(), ListDelinquentLoansParams{Limit: 1000})
+	require.NoError(t, err)
+	var listed bool
+	for _, loan := range delinquents {
+		if loan.ID == disbursed.Loan.ID {
+			listed = true
+			require.Equal(t, first.Amount, loan.OverdueAmount)
+			require.Equal(t, int64(1), loan.OverdueInstallments)
+		}
+	}
+	require.True(t, listed)
+
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(150))
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   account.ID,
+		Amount:        150,
+	})
+	require.NoError(t, err)
+
+	// paying what is past due makes the loan active again
+	repaid, err := store.RepayLoanInstallmentTx(context.Background(), RepayLoanInstallmentTxParams{ID: first.ID})
+	require.NoError(t, err)
+	require.Equal(t, InstallmentPaid, repaid.Installment.Status)
+	require.Equal(t, repaid.Repayment.Transfer.ID, repaid.Installment.TransferID.Int64)
+	require.Equal(t, int64(100), repaid.Repayment.FromAccount.Balance)
+	require.Equal(t, LoanActive, repaid.Loan.Status)
+
+	_, err = store.RepayLoanInstallmentTx(context.Background(), RepayLoanInstallmentTxParams{ID: first.ID})
+	require.ErrorIs(t, err, ErrInstallmentPaid)
+
+	repaid, err = store.RepayLoanInstallmentTx(context.Background(), RepayLoanInstallmentTxParams{ID: second.ID})
+	require.NoError(t, err)
+	require.Zero(t, repaid.Repayment.FromAccount.Balance)
+	require.Equal(t, LoanPaidOff, repaid.Loan.Status)
+	require.True(t, repaid.Loan.ClosedAt.Valid)
+}
+
+func TestDisburseLoanTxRefused(t *testing.T) {
+	store := NewStore(testDB)
+	offer := createRandomLoanOffer(t, store)
+	account := createRandomAccount(t, testfixtures.WithCurrency(util.USD))
+	installments := func(amount int64) []CreateLoanInstallmentParams {
+		return []CreateLoanInstallmentParams{{Number: 1, DueAt: time.Now().AddDate(0, 1, 0), Principal: amount, Amount: amount}}
+	}
+
+	_, err := store.DisburseLoanTx(context.Background(), DisburseLoanTxParams{
+		OfferID:      offer.ID,
+		AccountID:    account.ID,
+		Amount:       offer.MaxAmount + 1,
+		Installments: installments(offer.MaxAmount + 1),
+	})
+	require.ErrorIs(t, err, ErrInvalidLoanAmount)
+
+	// the schedule must repay the whole principal
+	_, err = store.DisburseLoanTx(context.Background(), DisburseLoanTxParams{
+		OfferID:      offer.ID,
+		AccountID:    account.ID,
+		Amount:       500,
+		Installments: installments(400),
+	})
+	require.Error(t, err)
+
+	_, err = store.SetLoanOfferActiveTx(context.Background(), SetLoanOfferActiveTxParams{
+		SetLoanOfferActiveParams: SetLoanOfferActiveParams{ID: offer.ID, Active: false},
+		Audit:                    randomAuditEntry("loan_offer:" + offer.Name),
+	})
+	require.NoError(t, err)
+	_, err = store.DisburseLoanTx(context.Background(), DisburseLoanTxParams{
+		OfferID:      offer.ID,
+		AccountID:    account.ID,
+		Amount:       500,
+		Installments: installments(500),
+	})
+	require.ErrorIs(t, err, ErrLoanOfferInactive)
+
+	loans, err := testQuires.ListAccountLoans(context.Background(), ListAccountLoansParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Empty(t, loans)
+}
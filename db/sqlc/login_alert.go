@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	// ErrLoginAlertReported is returned by ReportLoginAlertTx for a login reported already.
+	ErrLoginAlertReported = errors.New("login is reported already")
+	// ErrNoPasswordReset is returned for the password reset of a login that isn't reported, or
+	// whose user reset their password already.
+	ErrNoPasswordReset = errors.New("no password reset is pending for the login")
+)
+
+type ReportLoginAlertTxParams struct {
+	ReportLoginAlertParams
+	// AfterReport returns the outbox tasks of the alert once reported, e.g. the email of the code
+	// resetting the password.
+	AfterReport func(alert LoginAlert) ([]CreateOutboxTaskParams, error)
+}
+
+// ReportLoginAlertTx reports a login its user didn't make. It blocks every session of the user,
+// that of the login and any other the intruder opened, and the logins wait for the password to
+// be reset, see HasPendingPasswordReset.
+func (store *SQLStore) ReportLoginAlertTx(ctx context.Context, arg ReportLoginAlertTxParams) (LoginAlert, error) {
+	var alert LoginAlert
+
+	err := store.execTx(ctx, "ReportLoginAlertTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		alert, err = q.ReportLoginAlert(ctx, arg.ReportLoginAlertParams)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrLoginAlertReported
+			}
+			return err
+		}
+		if err := q.BlockUserSessions(ctx, alert.Username); err != nil {
+			return err
+		}
+		if arg.AfterReport == nil {
+			return nil
+		}
+		tasks, err := arg.AfterReport(alert)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return alert, err
+}
+
+type SendPasswordResetCodeTxParams struct {
+	SetLoginAlertResetCodeParams
+	// AfterUpdate returns the outbox tasks of the alert once its code is replaced, e.g. the email
+	// of the code.
+	AfterUpdate func(alert LoginAlert) ([]CreateOutboxTaskParams, error)
+}
+
+// SendPasswordResetCodeTx replaces the code resetting the password of a reported login along
+// with the outbox tasks sending it.
+func (store *SQLStore) SendPasswordResetCodeTx(ctx context.Context, arg SendPasswordResetCodeTxParams) (LoginAlert, error) {
+	var alert LoginAlert
+
+	err := store.execTx(ctx, "SendPasswordResetCodeTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		alert, err = q.SetLoginAlertResetCode(ctx, arg.SetLoginAlertResetCodeParams)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrNoPasswordReset
+			}
+			return err
+		}
+		if arg.AfterUpdate == nil {
+			return nil
+		}
+		tasks, err := arg.AfterUpdate(alert)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return alert, err
+}
+
+type ResetPasswordTxParams struct {
+	AlertID        int64
+	HashedPassword string
+}
+
+// ResetPasswordTx sets the new password of the user of a reported login, which lets them log in
+// again.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, "ResetPasswordTx", func(ctx context.Context, q *Queries) error {
+		alert, err := q.FinishLoginAlertReset(ctx, arg.AlertID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrNoPasswordReset
+			}
+			return err
+		}
+		user, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username:          alert.Username,
+			HashedPassword:    pgtype.Text{String: arg.HashedPassword, Valid: true},
+			PasswordChangedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		})
+		return err
+	})
+	return user, err
+}
@@ -1,17 +1,17 @@
 package db
 
 import (
-	"database/sql"
+	"context"
 	"log"
 	"os"
 	"testing"
 
 	"github.com/backendmaster/simple_bank/util"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var testQuires *Queries
-var testDB *sql.DB
+var testDB *pgxpool.Pool
 
 func TestMain(m *testing.M) {
 	config, err := util.LoadConfig("../..")
@@ -19,11 +19,20 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatalf("Load Config Failed: %v", err)
 	}
-	testDB, err = sql.Open(config.DBDriver, config.DBSource)
+	// the database of app.env, or a container with -tags integration
+	source, closeTestDB, err := testDBSource(context.Background(), config)
+	if err != nil {
+		log.Fatalf("can't not start test database: %v", err)
+	}
+	config.DBSource = source
+	testDB, err = NewPool(context.Background(), config)
 	if err != nil {
 		log.Fatal("can't not connect to database")
 	}
 	testQuires = New(testDB)
 
-	os.Exit(m.Run())
+	code := m.Run()
+	testDB.Close()
+	closeTestDB()
+	os.Exit(code)
 }
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := testQuires.SetMaintenanceReadOnly(context.Background(), false)
+		require.NoError(t, err)
+		_, err = testQuires.SetMaintenanceTransfersBlocked(context.Background(), false)
+		require.NoError(t, err)
+	})
+
+	mode, err := testQuires.SetMaintenanceTransfersBlocked(context.Background(), true)
+	require.NoError(t, err)
+	require.True(t, mode.TransfersBlocked)
+	require.False(t, mode.ReadOnly)
+
+	got, err := testQuires.GetMaintenanceMode(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, mode, got)
+
+	mode, err = testQuires.SetMaintenanceReadOnly(context.Background(), true)
+	require.NoError(t, err)
+	require.True(t, mode.ReadOnly)
+	require.True(t, mode.TransfersBlocked)
+}
+
+func TestTransferTxBlocked(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	t.Cleanup(func() {
+		_, err := testQuires.SetMaintenanceTransfersBlocked(context.Background(), false)
+		require.NoError(t, err)
+	})
+	_, err := testQuires.SetMaintenanceTransfersBlocked(context.Background(), true)
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrTransfersBlocked)
+
+	// nothing moved
+	got, err := testQuires.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, got.Balance)
+
+	_, err = testQuires.SetMaintenanceTransfersBlocked(context.Background(), false)
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+}
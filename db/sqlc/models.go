@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 
 package db
 
@@ -8,16 +8,162 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Account struct {
+	ID        int64              `json:"id"`
+	Owner     string             `json:"owner"`
+	Balance   int64              `json:"balance"`
+	Currency  string             `json:"currency"`
+	CreatedAt time.Time          `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	FrozenAt  pgtype.Timestamptz `json:"frozen_at"`
+	// the number the owner shares to be paid, e.g. SB06123456789012
+	Number string `json:"number"`
+	// the amount of the active authorization holds
+	HeldBalance int64 `json:"held_balance"`
+	// the balance less the held balance
+	AvailableBalance int64  `json:"available_balance"`
+	TenantID         string `json:"tenant_id"`
+}
+
+type AccountAlert struct {
+	AccountID           int64       `json:"account_id"`
+	LowBalanceThreshold pgtype.Int8 `json:"low_balance_threshold"`
+	LargeDebitThreshold pgtype.Int8 `json:"large_debit_threshold"`
+	UpdatedAt           time.Time   `json:"updated_at"`
+}
+
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Reason    string    `json:"reason"`
+	Details   []byte    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID           int64     `json:"id"`
+	Actor        string    `json:"actor"`
+	Protocol     string    `json:"protocol"`
+	Action       string    `json:"action"`
+	Path         string    `json:"path"`
+	StatusCode   int32     `json:"status_code"`
+	ClientIp     string    `json:"client_ip"`
+	UserAgent    string    `json:"user_agent"`
+	RequestID    string    `json:"request_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	PrevHash     []byte    `json:"prev_hash"`
+	Hash         []byte    `json:"hash"`
+	Impersonator string    `json:"impersonator"`
+}
+
+type AuthorizationHold struct {
+	ID             int64  `json:"id"`
+	AccountID      int64  `json:"account_id"`
+	ToAccountID    int64  `json:"to_account_id"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	Memo           string `json:"memo"`
+	Status         string `json:"status"`
+	CapturedAmount int64  `json:"captured_amount"`
+	// the transfer of the captured amount
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ClosedAt   pgtype.Timestamptz `json:"closed_at"`
+}
+
+type Card struct {
+	ID          int64  `json:"id"`
+	AccountID   int64  `json:"account_id"`
+	Fingerprint string `json:"fingerprint"`
+	Last4       string `json:"last4"`
+	ExpiryMonth int32  `json:"expiry_month"`
+	ExpiryYear  int32  `json:"expiry_year"`
+	// 0 for no limit
+	PerTransactionLimit int64 `json:"per_transaction_limit"`
+	// the most the card spends in a UTC day, 0 for no limit
+	DailyLimit int64              `json:"daily_limit"`
+	FrozenAt   pgtype.Timestamptz `json:"frozen_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type CardAuthorization struct {
+	ID           int64  `json:"id"`
+	CardID       int64  `json:"card_id"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	MerchantName string `json:"merchant_name"`
+	// the ISO 18245 merchant category code, e.g. 5411 for groceries
+	MerchantCategory     string      `json:"merchant_category"`
+	Approved             bool        `json:"approved"`
+	DeclineReason        string      `json:"decline_reason"`
+	TransferID           pgtype.Int8 `json:"transfer_id"`
+	CreatedAt            time.Time   `json:"created_at"`
+	NetworkTransactionID string      `json:"network_transaction_id"`
+}
+
+type CategoryRule struct {
+	ID                    int64       `json:"id"`
+	Owner                 string      `json:"owner"`
+	Category              string      `json:"category"`
+	CounterpartyAccountID pgtype.Int8 `json:"counterparty_account_id"`
+	Direction             string      `json:"direction"`
+	MemoContains          string      `json:"memo_contains"`
+	CreatedAt             time.Time   `json:"created_at"`
+}
+
+type Currency struct {
+	Code string `json:"code"`
+	// the decimal places of the currency, which the amounts are counted in
+	MinorUnits int16     `json:"minor_units"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type DenylistEntry struct {
 	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Device struct {
+	ID                   int64              `json:"id"`
+	Username             string             `json:"username"`
+	Fingerprint          string             `json:"fingerprint"`
+	Name                 string             `json:"name"`
+	VerificationHash     string             `json:"verification_hash"`
+	VerificationAttempts int32              `json:"verification_attempts"`
+	VerificationSentAt   pgtype.Timestamptz `json:"verification_sent_at"`
+	TrustedAt            pgtype.Timestamptz `json:"trusted_at"`
+	RevokedAt            pgtype.Timestamptz `json:"revoked_at"`
+	LastSeenAt           time.Time          `json:"last_seen_at"`
+	CreatedAt            time.Time          `json:"created_at"`
+}
+
+type Dispute struct {
+	ID                 int64              `json:"id"`
+	TransferID         int64              `json:"transfer_id"`
+	AccountID          int64              `json:"account_id"`
+	OpenedBy           string             `json:"opened_by"`
+	Amount             int64              `json:"amount"`
+	Reason             string             `json:"reason"`
+	Status             string             `json:"status"`
+	Resolution         string             `json:"resolution"`
+	ResolvedBy         string             `json:"resolved_by"`
+	ReversalTransferID pgtype.Int8        `json:"reversal_transfer_id"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	ResolvedAt         pgtype.Timestamptz `json:"resolved_at"`
+}
+
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
@@ -26,15 +172,293 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type Session struct {
-	ID           uuid.UUID `json:"id"`
+type EntryCategory struct {
+	EntryID   int64       `json:"entry_id"`
+	AccountID int64       `json:"account_id"`
+	Category  string      `json:"category"`
+	Tags      []string    `json:"tags"`
+	RuleID    pgtype.Int8 `json:"rule_id"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type EntryExport struct {
+	ID          int64              `json:"id"`
+	Owner       string             `json:"owner"`
+	AccountID   int64              `json:"account_id"`
+	Format      string             `json:"format"`
+	FromTime    time.Time          `json:"from_time"`
+	ToTime      time.Time          `json:"to_time"`
+	Status      string             `json:"status"`
+	Entries     int64              `json:"entries"`
+	Error       string             `json:"error"`
+	CreatedAt   time.Time          `json:"created_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+}
+
+type EntryExportFile struct {
+	ExportID int64  `json:"export_id"`
+	Content  []byte `json:"content"`
+}
+
+type EventOutbox struct {
+	ID          int64              `json:"id"`
+	EventType   string             `json:"event_type"`
+	EventKey    string             `json:"event_key"`
+	Payload     []byte             `json:"payload"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	// the billionths of a unit of quote_currency a unit of base_currency buys
+	RateNanos   int64     `json:"rate_nanos"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+type ExternalTransfer struct {
+	ID              int64  `json:"id"`
+	AccountID       int64  `json:"account_id"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	Rail            string `json:"rail"`
+	BeneficiaryName string `json:"beneficiary_name"`
+	// an IBAN for sepa, an account number for ach
+	BeneficiaryAccount string `json:"beneficiary_account"`
+	// the routing number of the bank for ach
+	BeneficiaryRouting string `json:"beneficiary_routing"`
+	Memo               string `json:"memo"`
+	Status             string `json:"status"`
+	// the reference of the transfer in the clearing network
+	Reference        string             `json:"reference"`
+	ReturnReason     string             `json:"return_reason"`
+	DebitTransferID  int64              `json:"debit_transfer_id"`
+	ReturnTransferID pgtype.Int8        `json:"return_transfer_id"`
+	CreatedAt        time.Time          `json:"created_at"`
+	SubmittedAt      pgtype.Timestamptz `json:"submitted_at"`
+	SettleAt         pgtype.Timestamptz `json:"settle_at"`
+	CompletedAt      pgtype.Timestamptz `json:"completed_at"`
+}
+
+type Impersonation struct {
+	ID        uuid.UUID          `json:"id"`
+	Admin     string             `json:"admin"`
+	Username  string             `json:"username"`
+	Reason    string             `json:"reason"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type LegalDocument struct {
+	Kind        string    `json:"kind"`
+	Version     string    `json:"version"`
+	Url         string    `json:"url"`
+	CreatedBy   string    `json:"created_by"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type Loan struct {
+	ID                     int64              `json:"id"`
+	OfferID                int64              `json:"offer_id"`
+	AccountID              int64              `json:"account_id"`
+	Principal              int64              `json:"principal"`
+	Currency               string             `json:"currency"`
+	AnnualRateBps          int32              `json:"annual_rate_bps"`
+	TermMonths             int32              `json:"term_months"`
+	Status                 string             `json:"status"`
+	DisbursementTransferID int64              `json:"disbursement_transfer_id"`
+	CreatedAt              time.Time          `json:"created_at"`
+	ClosedAt               pgtype.Timestamptz `json:"closed_at"`
+}
+
+type LoanInstallment struct {
+	ID        int64     `json:"id"`
+	LoanID    int64     `json:"loan_id"`
+	Number    int32     `json:"number"`
+	DueAt     time.Time `json:"due_at"`
+	Principal int64     `json:"principal"`
+	Interest  int64     `json:"interest"`
+	Amount    int64     `json:"amount"`
+	Status    string    `json:"status"`
+	// the transfer collecting the installment
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	PaidAt     pgtype.Timestamptz `json:"paid_at"`
+}
+
+type LoanOffer struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Currency  string `json:"currency"`
+	MinAmount int64  `json:"min_amount"`
+	MaxAmount int64  `json:"max_amount"`
+	// the annual interest rate, in hundredths of a percent
+	AnnualRateBps int32     `json:"annual_rate_bps"`
+	TermMonths    int32     `json:"term_months"`
+	Active        bool      `json:"active"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LoginAlert struct {
+	ID              int64              `json:"id"`
+	Username        string             `json:"username"`
+	SessionID       uuid.UUID          `json:"session_id"`
+	ClientIp        string             `json:"client_ip"`
+	UserAgent       string             `json:"user_agent"`
+	Country         string             `json:"country"`
+	Reasons         []string           `json:"reasons"`
+	ReportHash      string             `json:"report_hash"`
+	ReportedAt      pgtype.Timestamptz `json:"reported_at"`
+	ResetHash       string             `json:"reset_hash"`
+	ResetAttempts   int32              `json:"reset_attempts"`
+	ResetSentAt     pgtype.Timestamptz `json:"reset_sent_at"`
+	PasswordResetAt pgtype.Timestamptz `json:"password_reset_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type MaintenanceMode struct {
+	ID               bool      `json:"id"`
+	ReadOnly         bool      `json:"read_only"`
+	TransfersBlocked bool      `json:"transfers_blocked"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type Notification struct {
+	ID        int64              `json:"id"`
+	Username  string             `json:"username"`
+	Kind      string             `json:"kind"`
+	Title     string             `json:"title"`
+	Body      string             `json:"body"`
+	DedupKey  pgtype.Text        `json:"dedup_key"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type NotificationPreference struct {
 	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	EmailEnabled bool      `json:"email_enabled"`
+	SmsEnabled   bool      `json:"sms_enabled"`
+	PushEnabled  bool      `json:"push_enabled"`
+	PhoneNumber  string    `json:"phone_number"`
+	PushToken    string    `json:"push_token"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID          int64              `json:"id"`
+	TaskType    string             `json:"task_type"`
+	Payload     []byte             `json:"payload"`
+	Queue       string             `json:"queue"`
+	MaxRetry    int32              `json:"max_retry"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID             int64       `json:"id"`
+	AccountID      int64       `json:"account_id"`
+	Amount         int64       `json:"amount"`
+	Currency       string      `json:"currency"`
+	Memo           string      `json:"memo"`
+	Status         string      `json:"status"`
+	PayerAccountID pgtype.Int8 `json:"payer_account_id"`
+	// the transfer paying the request
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	PaidAt     pgtype.Timestamptz `json:"paid_at"`
+}
+
+type Referral struct {
+	ID       int64  `json:"id"`
+	Referrer string `json:"referrer"`
+	Referee  string `json:"referee"`
+	Status   string `json:"status"`
+	// why the referral was rejected
+	Reason string `json:"reason"`
+	// the transfer of the referee the bonuses were paid for
+	QualifyingTransferID pgtype.Int8        `json:"qualifying_transfer_id"`
+	ReferrerTransferID   pgtype.Int8        `json:"referrer_transfer_id"`
+	RefereeTransferID    pgtype.Int8        `json:"referee_transfer_id"`
+	CreatedAt            time.Time          `json:"created_at"`
+	ClosedAt             pgtype.Timestamptz `json:"closed_at"`
+}
+
+type ReferralCode struct {
+	Username  string    `json:"username"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Refund struct {
+	ID         int64 `json:"id"`
+	TransferID int64 `json:"transfer_id"`
+	// the transfer paying the amount back
+	RefundTransferID int64     `json:"refund_transfer_id"`
+	Amount           int64     `json:"amount"`
+	Reason           string    `json:"reason"`
+	CreatedBy        string    `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type RewardEntry struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"account_id"`
+	Kind      string `json:"kind"`
+	Points    int64  `json:"points"`
+	// the transfer earning the points, or the one crediting the redeemed points
+	TransferID int64     `json:"transfer_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ScreeningHold struct {
+	ID         int64              `json:"id"`
+	Username   string             `json:"username"`
+	Matches    []string           `json:"matches"`
+	Status     string             `json:"status"`
+	ReviewedBy string             `json:"reviewed_by"`
+	Note       string             `json:"note"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ReviewedAt pgtype.Timestamptz `json:"reviewed_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID   `json:"id"`
+	Username     string      `json:"username"`
+	RefreshToken string      `json:"refresh_token"`
+	UserAgent    string      `json:"user_agent"`
+	ClientIp     string      `json:"client_ip"`
+	IsBlocked    bool        `json:"is_blocked"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Country      string      `json:"country"`
+	DeviceID     pgtype.Int8 `json:"device_id"`
+}
+
+type StepUpChallenge struct {
+	ID          int64              `json:"id"`
+	Username    string             `json:"username"`
+	CodeHash    string             `json:"code_hash"`
+	Attempts    int32              `json:"attempts"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type Tenant struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Currencies []string `json:"currencies"`
+	// the most a transfer from an account of the tenant moves, 0 for no cap
+	MaxTransferAmount int64 `json:"max_transfer_amount"`
+	// the sender of the emails to the users of the tenant, EMAIL_SENDER_NAME when empty
+	EmailSenderName string    `json:"email_sender_name"`
+	SupportEmail    string    `json:"support_email"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 type Transfer struct {
@@ -44,13 +468,82 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	Memo      string    `json:"memo"`
+	TenantID  string    `json:"tenant_id"`
+}
+
+type TransferExchange struct {
+	TransferID         int64     `json:"transfer_id"`
+	ExchangeTransferID int64     `json:"exchange_transfer_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type TransferReview struct {
+	ID            int64              `json:"id"`
+	Username      string             `json:"username"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        int64              `json:"amount"`
+	Currency      string             `json:"currency"`
+	Memo          string             `json:"memo"`
+	Decision      string             `json:"decision"`
+	Rules         []string           `json:"rules"`
+	Status        string             `json:"status"`
+	OtpHash       string             `json:"otp_hash"`
+	OtpAttempts   int32              `json:"otp_attempts"`
+	ReviewedBy    string             `json:"reviewed_by"`
+	Note          string             `json:"note"`
+	TransferID    pgtype.Int8        `json:"transfer_id"`
+	CreatedAt     time.Time          `json:"created_at"`
+	ReviewedAt    pgtype.Timestamptz `json:"reviewed_at"`
 }
 
 type User struct {
-	Username          string    `json:"username"`
-	HashedPassword    string    `json:"hashed_password"`
-	FullName          string    `json:"full_name"`
-	Email             string    `json:"email"`
-	PasswordChangedAt time.Time `json:"password_changed_at"`
-	CreatedAt         time.Time `json:"created_at"`
+	Username          string             `json:"username"`
+	HashedPassword    string             `json:"hashed_password"`
+	FullName          string             `json:"full_name"`
+	Email             string             `json:"email"`
+	PasswordChangedAt time.Time          `json:"password_changed_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	Role              string             `json:"role"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	TenantID          string             `json:"tenant_id"`
+	// the settings of the user, the defaults of the preferences package for the missing keys
+	Preferences []byte `json:"preferences"`
+}
+
+type UserConsent struct {
+	Username   string    `json:"username"`
+	Kind       string    `json:"kind"`
+	Version    string    `json:"version"`
+	ClientIp   string    `json:"client_ip"`
+	UserAgent  string    `json:"user_agent"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+type UserImport struct {
+	ID           int64              `json:"id"`
+	CreatedBy    string             `json:"created_by"`
+	Format       string             `json:"format"`
+	Status       string             `json:"status"`
+	TotalRows    int64              `json:"total_rows"`
+	ImportedRows int64              `json:"imported_rows"`
+	FailedRows   int64              `json:"failed_rows"`
+	Error        string             `json:"error"`
+	CreatedAt    time.Time          `json:"created_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+}
+
+type UserImportFile struct {
+	ImportID int64  `json:"import_id"`
+	Content  []byte `json:"content"`
+}
+
+type UserImportRow struct {
+	ImportID int64 `json:"import_id"`
+	// the line of the row in the file, from 1
+	Line     int64  `json:"line"`
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
 }
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomNotification(t *testing.T, username string, dedupKey pgtype.Text) Notification {
+	arg := CreateNotificationParams{
+		Username: username,
+		Kind:     "new_login",
+		Title:    util.RandomString(10),
+		Body:     util.RandomString(30),
+		DedupKey: dedupKey,
+	}
+
+	notification, err := testQuires.CreateNotification(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, notification.Username)
+	require.Equal(t, arg.Title, notification.Title)
+	require.Equal(t, arg.DedupKey, notification.DedupKey)
+	require.False(t, notification.ReadAt.Valid)
+	require.NotZero(t, notification.CreatedAt)
+	return notification
+}
+
+func TestCreateNotificationDedup(t *testing.T) {
+	user := createRandomUser(t)
+	dedupKey := pgtype.Text{String: "outbox:" + util.RandomString(10), Valid: true}
+	createRandomNotification(t, user.Username, dedupKey)
+
+	_, err := testQuires.CreateNotification(context.Background(), CreateNotificationParams{
+		Username: user.Username,
+		Kind:     "new_login",
+		Title:    util.RandomString(10),
+		Body:     util.RandomString(30),
+		DedupKey: dedupKey,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// notifications without a dedup key never conflict
+	createRandomNotification(t, user.Username, pgtype.Text{})
+	createRandomNotification(t, user.Username, pgtype.Text{})
+}
+
+func TestNotificationReadState(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomNotification(t, user.Username, pgtype.Text{})
+	}
+	notifications, err := testQuires.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: user.Username,
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	require.Greater(t, notifications[0].ID, notifications[1].ID)
+
+	read, err := testQuires.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[0].ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, read.ReadAt.Valid)
+
+	_, err = testQuires.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[1].ID,
+		Username: createRandomUser(t).Username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	unread, err := testQuires.ListNotifications(context.Background(), ListNotificationsParams{
+		Username:   user.Username,
+		UnreadOnly: true,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, unread, 2)
+
+	err = testQuires.MarkAllNotificationsRead(context.Background(), user.Username)
+	require.NoError(t, err)
+	count, err := testQuires.CountUnreadNotifications(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestUpsertNotificationPreferences(t *testing.T) {
+	user := createRandomUser(t)
+	_, err := testQuires.GetNotificationPreferences(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	arg := UpsertNotificationPreferencesParams{
+		Username:     user.Username,
+		EmailEnabled: true,
+		SmsEnabled:   true,
+		PhoneNumber:  "+14155552671",
+	}
+	preferences, err := testQuires.UpsertNotificationPreferences(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, preferences.SmsEnabled)
+
+	arg.EmailEnabled = false
+	preferences, err = testQuires.UpsertNotificationPreferences(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, preferences.EmailEnabled)
+
+	got, err := testQuires.GetNotificationPreferences(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, preferences, got)
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The page functions list rows by id with a keyset cursor instead of an offset, so a page costs
+// the same however deep in the list it is, and rows inserted meanwhile don't shift the pages.
+
+// ListAccountsPage returns the page of the accounts of owner at cursor.
+func ListAccountsPage(ctx context.Context, store AccountStore, owner string, cursor pagination.Cursor, size int32) (pagination.Page[Account], error) {
+	return pagination.Paginate(cursor, size, func(account Account) int64 { return account.ID },
+		func(cursor pagination.Cursor, limit int32) ([]Account, error) {
+			if cursor.Backward {
+				return store.ListAccountsBefore(ctx, ListAccountsBeforeParams{Owner: owner, BeforeID: cursor.ID, Limit: limit})
+			}
+			return store.ListAccountsAfter(ctx, ListAccountsAfterParams{Owner: owner, AfterID: cursor.ID, Limit: limit})
+		})
+}
+
+// ListEntriesPage returns the page of the statement of the account at cursor, its archived entries included.
+func ListEntriesPage(ctx context.Context, store TransferStore, accountID int64, cursor pagination.Cursor, size int32) (pagination.Page[Entry], error) {
+	return pagination.Paginate(cursor, size, func(entry Entry) int64 { return entry.ID },
+		func(cursor pagination.Cursor, limit int32) ([]Entry, error) {
+			if cursor.Backward {
+				return store.ListStatementEntriesBefore(ctx, ListStatementEntriesBeforeParams{AccountID: accountID, BeforeID: cursor.ID, Limit: limit})
+			}
+			return store.ListStatementEntriesAfter(ctx, ListStatementEntriesAfterParams{AccountID: accountID, AfterID: cursor.ID, Limit: limit})
+		})
+}
+
+// ListTransfersPage returns the page of the transfers from or to the account at cursor.
+func ListTransfersPage(ctx context.Context, store TransferStore, accountID int64, cursor pagination.Cursor, size int32) (pagination.Page[Transfer], error) {
+	return pagination.Paginate(cursor, size, func(transfer Transfer) int64 { return transfer.ID },
+		func(cursor pagination.Cursor, limit int32) ([]Transfer, error) {
+			if cursor.Backward {
+				return store.ListTransfersBefore(ctx, ListTransfersBeforeParams{AccountID: accountID, BeforeID: cursor.ID, Limit: limit})
+			}
+			return store.ListTransfersAfter(ctx, ListTransfersAfterParams{AccountID: accountID, AfterID: cursor.ID, Limit: limit})
+		})
+}
+
+// AuditLogFilter narrows ListAuditLogsPage down to the logs matching its set fields.
+type AuditLogFilter struct {
+	Actor    pgtype.Text
+	Action   pgtype.Text
+	FromTime pgtype.Timestamptz
+	ToTime   pgtype.Timestamptz
+}
+
+// ListAuditLogsPage returns the page of the audit logs matching filter at cursor.
+func ListAuditLogsPage(ctx context.Context, store AuditStore, filter AuditLogFilter, cursor pagination.Cursor, size int32) (pagination.Page[AuditLog], error) {
+	return pagination.Paginate(cursor, size, func(log AuditLog) int64 { return log.ID },
+		func(cursor pagination.Cursor, limit int32) ([]AuditLog, error) {
+			if cursor.Backward {
+				return store.ListAuditLogsBefore(ctx, ListAuditLogsBeforeParams{
+					BeforeID: cursor.ID,
+					Actor:    filter.Actor,
+					Action:   filter.Action,
+					FromTime: filter.FromTime,
+					ToTime:   filter.ToTime,
+					Limit:    limit,
+				})
+			}
+			return store.ListAuditLogsAfter(ctx, ListAuditLogsAfterParams{
+				AfterID:  cursor.ID,
+				Actor:    filter.Actor,
+				Action:   filter.Action,
+				FromTime: filter.FromTime,
+				ToTime:   filter.ToTime,
+				Limit:    limit,
+			})
+		})
+}
+
+// ListUserImportRowsPage returns the page of the report of an import at cursor, the rows with
+// status only when it is set. The cursor of a row is its line.
+func ListUserImportRowsPage(ctx context.Context, store UserImportStore, importID int64, status pgtype.Text, cursor pagination.Cursor, size int32) (pagination.Page[UserImportRow], error) {
+	return pagination.Paginate(cursor, size, func(row UserImportRow) int64 { return row.Line },
+		func(cursor pagination.Cursor, limit int32) ([]UserImportRow, error) {
+			if cursor.Backward {
+				return store.ListUserImportRowsBefore(ctx, ListUserImportRowsBeforeParams{ImportID: importID, BeforeLine: cursor.ID, Status: status, Limit: limit})
+			}
+			return store.ListUserImportRowsAfter(ctx, ListUserImportRowsAfterParams{ImportID: importID, AfterLine: cursor.ID, Status: status, Limit: limit})
+		})
+}
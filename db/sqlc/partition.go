@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// partitionedTables are partitioned by month of created_at.
+var partitionedTables = []string{"entries", "transfers"}
+
+// CreatePartitions creates the partitions of the month of from and of the monthsAhead following
+// ones, in UTC, for every partitioned table. Existing partitions are left alone, so it is safe to
+// run on a schedule. Rows inserted while no partition held their month wait in the default
+// partition, and are moved once it is created.
+func (store *SQLStore) CreatePartitions(ctx context.Context, from time.Time, monthsAhead int) error {
+	from = from.UTC()
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, table := range partitionedTables {
+		for i := 0; i <= monthsAhead; i++ {
+			day := month.AddDate(0, i, 0)
+			err := store.CreateMonthlyPartition(ctx, CreateMonthlyPartitionParams{
+				Parent: table,
+				Day:    day,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create partition of %s for %s: %w", table, day.Format("2006-01"), err)
+			}
+		}
+	}
+	return nil
+}
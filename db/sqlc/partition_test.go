@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePartitions(t *testing.T) {
+	store := NewStore(testDB)
+
+	// the months around the end of january, where adding a month to the 31st would skip february
+	from := time.Date(2031, time.January, 31, 23, 0, 0, 0, time.UTC)
+	require.NoError(t, store.CreatePartitions(context.Background(), from, 1))
+	// existing partitions are skipped
+	require.NoError(t, store.CreatePartitions(context.Background(), from, 1))
+
+	for _, partition := range []string{"entries_y2031m01", "entries_y2031m02", "transfers_y2031m01", "transfers_y2031m02"} {
+		var exists bool
+		err := testDB.QueryRow(context.Background(), "SELECT to_regclass($1) IS NOT NULL", partition).Scan(&exists)
+		require.NoError(t, err)
+		require.True(t, exists, partition)
+	}
+}
+
+func TestListEntriesBetween(t *testing.T) {
+	account := createRandomAccount(t)
+	entry, err := testQuires.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    10,
+	})
+	require.NoError(t, err)
+
+	entries, err := testQuires.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.ID,
+		FromTime:  pgtype.Timestamptz{Time: entry.CreatedAt.Add(-time.Minute), Valid: true},
+		ToTime:    pgtype.Timestamptz{Time: entry.CreatedAt.Add(time.Minute), Valid: true},
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, entry.ID, entries[0].ID)
+
+	entries, err = testQuires.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.ID,
+		ToTime:    pgtype.Timestamptz{Time: entry.CreatedAt, Valid: true},
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
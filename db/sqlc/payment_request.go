@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of a payment request.
+const (
+	PaymentRequestOpen = "open"
+	PaymentRequestPaid = "paid"
+)
+
+// ErrPaymentRequestClosed is returned by PayPaymentRequestTx for a request already paid or past
+// its expiry.
+var ErrPaymentRequestClosed = errors.New("payment request is paid or expired")
+
+type PayPaymentRequestTxParams struct {
+	ID            int64
+	FromAccountID int64
+	// OutboxTasks, AlertTask and RewardTask are those of the transfer, see TransferTxParams.
+	OutboxTasks []CreateOutboxTaskParams
+	AlertTask   AlertTaskFunc
+	RewardTask  RewardTaskFunc
+}
+
+type PayPaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest   `json:"payment_request"`
+	Transfer       TransferTxResult `json:"transfer"`
+}
+
+// PayPaymentRequestTx pays an open payment request from the account of the payer, with a transfer
+// refused like TransferTx refuses it. The request is locked, so that only the first of two payers
+// scanning it at once pays it.
+func (store *SQLStore) PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error) {
+	var result PayPaymentRequestTxResult
+
+	err := store.execTx(ctx, "PayPaymentRequestTx", func(ctx context.Context, q *Queries) error {
+		request, err := q.GetPaymentRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestOpen || !request.ExpiresAt.After(time.Now()) {
+			return ErrPaymentRequestClosed
+		}
+
+		memo := request.Memo
+		if memo == "" {
+			memo = fmt.Sprintf("payment request %d", request.ID)
+		}
+		result.Transfer, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   request.AccountID,
+			Amount:        request.Amount,
+			Memo:          memo,
+			OutboxTasks:   arg.OutboxTasks,
+			AlertTask:     arg.AlertTask,
+			RewardTask:    arg.RewardTask,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.MarkPaymentRequestPaid(ctx, MarkPaymentRequestPaidParams{
+			ID:             request.ID,
+			PayerAccountID: pgtype.Int8{Int64: arg.FromAccountID, Valid: true},
+			TransferID:     pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+	return result, err
+}
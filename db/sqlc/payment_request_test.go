@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+
+	request, err := testQuires.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		AccountID: payee.ID,
+		Amount:    40,
+		Currency:  util.USD,
+		Memo:      "lunch",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestOpen, request.Status)
+	require.False(t, request.PaidAt.Valid)
+
+	paid, err := store.PayPaymentRequestTx(context.Background(), PayPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPaid, paid.PaymentRequest.Status)
+	require.Equal(t, payer.ID, paid.PaymentRequest.PayerAccountID.Int64)
+	require.Equal(t, paid.Transfer.Transfer.ID, paid.PaymentRequest.TransferID.Int64)
+	require.True(t, paid.PaymentRequest.PaidAt.Valid)
+	require.Equal(t, "lunch", paid.Transfer.Transfer.Memo)
+	require.Equal(t, int64(60), paid.Transfer.FromAccount.Balance)
+	require.Equal(t, int64(40), paid.Transfer.ToAccount.Balance)
+
+	// a request is paid once
+	_, err = store.PayPaymentRequestTx(context.Background(), PayPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
+
+func TestPayExpiredPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.EUR))
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(100))
+
+	request, err := testQuires.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		AccountID: payee.ID,
+		Amount:    10,
+		Currency:  util.EUR,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = store.PayPaymentRequestTx(context.Background(), PayPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewPoolConfig parses DB_SOURCE and applies the pool tuning of config. Settings left at zero
+// keep the defaults of pgxpool, or the pool_* parameters of DB_SOURCE.
+func NewPoolConfig(config util.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(config.DBSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.DBMaxConns > 0 {
+		poolConfig.MaxConns = config.DBMaxConns
+	}
+	if config.DBMinConns > 0 {
+		poolConfig.MinConns = config.DBMinConns
+	}
+	if config.DBMaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.DBMaxConnIdleTime
+	}
+	if config.DBMaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.DBMaxConnLifetime
+	}
+	if config.DBStatementTimeout > 0 {
+		// set on every new connection, so that postgres cancels a runaway query even when the
+		// client that sent it is gone
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(config.DBStatementTimeout.Milliseconds(), 10)
+	}
+	poolConfig.ConnConfig.Tracer = queryTracer{slowThreshold: config.DBSlowQueryThreshold}
+	return poolConfig, nil
+}
+
+// NewPool creates the connection pool of config. The connections are opened lazily,
+// so it doesn't fail when the database is down.
+func NewPool(ctx context.Context, config util.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := NewPoolConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
+
+// OpenDB opens a database/sql handle for the code that still needs one, e.g. gorm. It connects
+// with the settings of connPool, statement timeout included, but keeps connections of its own,
+// limited by DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS.
+func OpenDB(config util.Config, connPool *pgxpool.Pool) *sql.DB {
+	conn := stdlib.OpenDB(*connPool.Config().ConnConfig)
+	if config.DBMaxOpenConns > 0 {
+		conn.SetMaxOpenConns(config.DBMaxOpenConns)
+	}
+	if config.DBMaxIdleConns > 0 {
+		conn.SetMaxIdleConns(config.DBMaxIdleConns)
+	}
+	conn.SetConnMaxLifetime(config.DBMaxConnLifetime)
+	conn.SetConnMaxIdleTime(config.DBMaxConnIdleTime)
+	return conn
+}
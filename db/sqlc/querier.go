@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 
 package db
 
@@ -8,27 +8,345 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// holds amount more on the account, or releases it when negative.
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	AddDeviceVerificationAttempt(ctx context.Context, id int64) (Device, error)
+	AddLoginAlertResetAttempt(ctx context.Context, id int64) (LoginAlert, error)
+	AddStepUpChallengeAttempt(ctx context.Context, id int64) (StepUpChallenge, error)
+	AddTransferReviewOTPAttempt(ctx context.Context, id int64) (TransferReview, error)
+	AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error)
+	// moves up to limit of the entries created before the cutoff to entries_archive, oldest first.
+	ArchiveEntries(ctx context.Context, arg ArchiveEntriesParams) (int64, error)
+	// binds a session logged in before every login was bound to its device, nothing when it is
+	// bound already.
+	BindSessionDevice(ctx context.Context, arg BindSessionDeviceParams) (Session, error)
+	BlockDeviceSessions(ctx context.Context, deviceID pgtype.Int8) error
+	BlockUserSessions(ctx context.Context, username string) error
+	// sets the category of an entry by the first rule of owner it matches, unless it has one.
+	CategorizeEntry(ctx context.Context, arg CategorizeEntryParams) (int64, error)
+	CloseAuthorizationHold(ctx context.Context, arg CloseAuthorizationHoldParams) (AuthorizationHold, error)
+	// a referral closes once, so closing a referral no longer pending returns no row.
+	CloseReferral(ctx context.Context, arg CloseReferralParams) (Referral, error)
+	CompleteExternalTransfer(ctx context.Context, arg CompleteExternalTransferParams) (ExternalTransfer, error)
+	// completes a challenge once, so that its code can't step up another token.
+	CompleteStepUpChallenge(ctx context.Context, id int64) (StepUpChallenge, error)
+	CountActiveSessions(ctx context.Context) (int64, error)
+	// the entries of an account between from_time and to_time, archived ones included.
+	CountExportEntries(ctx context.Context, arg CountExportEntriesParams) (int64, error)
+	CountRewardedReferrals(ctx context.Context, referrer string) (int64, error)
+	// the transfers the account sent since created_at.
+	CountTransfersSince(ctx context.Context, arg CountTransfersSinceParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, username string) (int64, error)
+	// an account belongs to the tenant of its owner.
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditEntry, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateAuthorizationHold(ctx context.Context, arg CreateAuthorizationHoldParams) (AuthorizationHold, error)
+	CreateCard(ctx context.Context, arg CreateCardParams) (Card, error)
+	CreateCardAuthorization(ctx context.Context, arg CreateCardAuthorizationParams) (CardAuthorization, error)
+	CreateCategoryRule(ctx context.Context, arg CreateCategoryRuleParams) (CategoryRule, error)
+	CreateDenylistEntry(ctx context.Context, arg CreateDenylistEntryParams) (DenylistEntry, error)
+	CreateDispute(ctx context.Context, arg CreateDisputeParams) (Dispute, error)
+	CreateEntries(ctx context.Context, arg []CreateEntriesParams) (int64, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateEntryExport(ctx context.Context, arg CreateEntryExportParams) (EntryExport, error)
+	CreateEntryExportFile(ctx context.Context, arg CreateEntryExportFileParams) error
+	CreateExternalTransfer(ctx context.Context, arg CreateExternalTransferParams) (ExternalTransfer, error)
+	CreateImpersonation(ctx context.Context, arg CreateImpersonationParams) (Impersonation, error)
+	CreateLegalDocument(ctx context.Context, arg CreateLegalDocumentParams) (LegalDocument, error)
+	CreateLoan(ctx context.Context, arg CreateLoanParams) (Loan, error)
+	CreateLoanInstallment(ctx context.Context, arg CreateLoanInstallmentParams) (LoanInstallment, error)
+	CreateLoanOffer(ctx context.Context, arg CreateLoanOfferParams) (LoanOffer, error)
+	CreateLoginAlert(ctx context.Context, arg CreateLoginAlertParams) (LoginAlert, error)
+	CreateMonthlyPartition(ctx context.Context, arg CreateMonthlyPartitionParams) error
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (EventOutbox, error)
+	CreateOutboxTask(ctx context.Context, arg CreateOutboxTaskParams) (Outbox, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreateReferral(ctx context.Context, arg CreateReferralParams) (Referral, error)
+	// a user has one code, so creating the code of a user who has one returns no row.
+	CreateReferralCode(ctx context.Context, arg CreateReferralCodeParams) (ReferralCode, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	// a transfer has one reward entry at most, so the accrual of a transfer already rewarded returns no
+	// row.
+	CreateRewardEntry(ctx context.Context, arg CreateRewardEntryParams) (RewardEntry, error)
+	CreateScreeningHold(ctx context.Context, arg CreateScreeningHoldParams) (ScreeningHold, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateStepUpChallenge(ctx context.Context, arg CreateStepUpChallengeParams) (StepUpChallenge, error)
+	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
+	// a transfer belongs to the tenant of its payer, or of its payee when the bank pays.
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferExchange(ctx context.Context, arg CreateTransferExchangeParams) (TransferExchange, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
+	// a user joins the default tenant unless tenant_id tells.
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// accepting a version again keeps the first acceptance.
+	CreateUserConsent(ctx context.Context, arg CreateUserConsentParams) (UserConsent, error)
+	CreateUserImport(ctx context.Context, arg CreateUserImportParams) (UserImport, error)
+	CreateUserImportFile(ctx context.Context, arg CreateUserImportFileParams) error
+	CreateUserImportRow(ctx context.Context, arg CreateUserImportRowParams) (UserImportRow, error)
+	// the row is kept, its entries and transfers still reference it.
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteCategoryRule(ctx context.Context, id int64) error
+	DeleteDenylistEntry(ctx context.Context, id int64) (DenylistEntry, error)
+	// deletes the exports that expired, their files along with them.
+	DeleteExpiredEntryExports(ctx context.Context) (int64, error)
+	FailEntryExport(ctx context.Context, arg FailEntryExportParams) (EntryExport, error)
+	FailUserImport(ctx context.Context, arg FailUserImportParams) (UserImport, error)
+	FinishEntryExport(ctx context.Context, arg FinishEntryExportParams) (EntryExport, error)
+	FinishLoginAlertReset(ctx context.Context, id int64) (LoginAlert, error)
+	// completes a pending import, counting its rows.
+	FinishUserImport(ctx context.Context, id int64) (UserImport, error)
+	FreezeAccount(ctx context.Context, id int64) (Account, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountAlert(ctx context.Context, accountID int64) (AccountAlert, error)
+	// an account number only resolves within its tenant.
+	GetAccountByNumber(ctx context.Context, arg GetAccountByNumberParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	// for admins investigating an account that may have been deleted.
+	GetAccountIncludeDeleted(ctx context.Context, id int64) (Account, error)
+	GetAuthorizationHold(ctx context.Context, id int64) (AuthorizationHold, error)
+	GetAuthorizationHoldForUpdate(ctx context.Context, id int64) (AuthorizationHold, error)
+	GetCard(ctx context.Context, id int64) (Card, error)
+	GetCardAuthorizationByNetworkTransactionID(ctx context.Context, networkTransactionID string) (CardAuthorization, error)
+	GetCardByFingerprint(ctx context.Context, fingerprint string) (Card, error)
+	// locks the card, so that the authorizations of a card are checked against its limits one at a time.
+	GetCardForUpdate(ctx context.Context, id int64) (Card, error)
+	GetCategoryRule(ctx context.Context, id int64) (CategoryRule, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetDenylistEntry(ctx context.Context, id int64) (DenylistEntry, error)
+	GetDevice(ctx context.Context, id int64) (Device, error)
+	GetDispute(ctx context.Context, id int64) (Dispute, error)
+	GetDisputeForUpdate(ctx context.Context, id int64) (Dispute, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetEntryExport(ctx context.Context, id int64) (EntryExport, error)
+	GetEntryExportFile(ctx context.Context, exportID int64) ([]byte, error)
+	GetExternalTransfer(ctx context.Context, id int64) (ExternalTransfer, error)
+	GetExternalTransferForUpdate(ctx context.Context, id int64) (ExternalTransfer, error)
+	// the account converting the transfers from and to currency.
+	GetFXAccount(ctx context.Context, currency string) (Account, error)
+	// the first transfer of at least min_amount the referee sent between from_time and to_time, to an
+	// account owned by neither the referee nor the referrer.
+	GetFirstQualifyingTransfer(ctx context.Context, arg GetFirstQualifyingTransferParams) (Transfer, error)
+	// the amount of the disputes still open or investigating on the account.
+	GetHeldAmount(ctx context.Context, accountID int64) (int64, error)
+	GetImpersonation(ctx context.Context, id uuid.UUID) (Impersonation, error)
+	GetLastAuditLogHash(ctx context.Context) ([]byte, error)
+	// the country of the last login of the user that has one.
+	GetLastLoginCountry(ctx context.Context, username string) (string, error)
+	// the line of the last row of the import written, 0 before the first one.
+	GetLastUserImportLine(ctx context.Context, importID int64) (int64, error)
+	// the account disbursing the loans in currency and collecting their installments.
+	GetLendingAccount(ctx context.Context, currency string) (Account, error)
+	GetLoan(ctx context.Context, id int64) (Loan, error)
+	GetLoanForUpdate(ctx context.Context, id int64) (Loan, error)
+	GetLoanInstallmentForUpdate(ctx context.Context, id int64) (LoanInstallment, error)
+	GetLoanOffer(ctx context.Context, id int64) (LoanOffer, error)
+	// the installments of the loan left to pay, and those of them past due.
+	GetLoanStanding(ctx context.Context, loanID int64) (GetLoanStandingRow, error)
+	GetLoginAlert(ctx context.Context, id int64) (LoginAlert, error)
+	GetMaintenanceMode(ctx context.Context) (MaintenanceMode, error)
+	GetNotificationPreferences(ctx context.Context, username string) (NotificationPreference, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetReferralCode(ctx context.Context, username string) (ReferralCode, error)
+	GetReferralCodeByCode(ctx context.Context, code string) (ReferralCode, error)
+	GetReferralForUpdate(ctx context.Context, id int64) (Referral, error)
+	// the amount of the refunds of the transfer so far.
+	GetRefundedAmount(ctx context.Context, transferID int64) (int64, error)
+	// the amount of the transfer that went back to the account it debited: by its refunds, and by the
+	// reversal of its refunded dispute.
+	GetReturnedAmount(ctx context.Context, transferID int64) (GetReturnedAmountRow, error)
+	// the points the account can redeem.
+	GetRewardBalance(ctx context.Context, accountID int64) (int64, error)
+	// the account paying out the cashback redeemed in currency.
+	GetRewardsAccount(ctx context.Context, currency string) (Account, error)
+	GetScreeningHold(ctx context.Context, id int64) (ScreeningHold, error)
+	GetScreeningHoldForUpdate(ctx context.Context, id int64) (ScreeningHold, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStepUpChallenge(ctx context.Context, id int64) (StepUpChallenge, error)
+	// the account holding the external transfers in currency until they settle.
+	GetSuspenseAccount(ctx context.Context, currency string) (Account, error)
+	GetTenant(ctx context.Context, id string) (Tenant, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	// the exchange of either of its transfers.
+	GetTransferExchange(ctx context.Context, transferID int64) (TransferExchange, error)
+	// serializes what pays the transfer back, its refunds and the reversal of its dispute.
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserImport(ctx context.Context, id int64) (UserImport, error)
+	GetUserImportFile(ctx context.Context, importID int64) ([]byte, error)
+	// for admins investigating a user that may have been deleted.
+	GetUserIncludeDeleted(ctx context.Context, username string) (User, error)
+	// whether the user reported a login and didn't reset their password since.
+	HasPendingPasswordReset(ctx context.Context, username string) (bool, error)
+	// whether the account ever sent a transfer to to_account_id.
+	HasTransferBetween(ctx context.Context, arg HasTransferBetweenParams) (bool, error)
+	// holds the users matching a new name or email entry, but those with an open hold already.
+	HoldUsersMatchingDenylist(ctx context.Context, arg HoldUsersMatchingDenylistParams) ([]ScreeningHold, error)
+	// the holds on the account and those in its favor; the status filter matches every hold when null.
+	ListAccountAuthorizationHolds(ctx context.Context, arg ListAccountAuthorizationHoldsParams) ([]AuthorizationHold, error)
+	ListAccountCards(ctx context.Context, accountID int64) ([]Card, error)
+	ListAccountExternalTransfers(ctx context.Context, arg ListAccountExternalTransfersParams) ([]ExternalTransfer, error)
+	ListAccountLoans(ctx context.Context, arg ListAccountLoansParams) ([]Loan, error)
+	ListAccountPaymentRequests(ctx context.Context, arg ListAccountPaymentRequestsParams) ([]PaymentRequest, error)
+	// the matches flagging an account: an entry of the account itself, or those of an open hold of its owner.
+	ListAccountScreeningMatches(ctx context.Context, accountID int64) ([]string, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	ListAccountsBefore(ctx context.Context, arg ListAccountsBeforeParams) ([]Account, error)
+	// for admins investigating the accounts a user may have deleted.
+	ListAccountsIncludeDeleted(ctx context.Context, arg ListAccountsIncludeDeletedParams) ([]Account, error)
+	// every account of the owner, which has a handful at most.
+	ListAllAccounts(ctx context.Context, owner string) ([]Account, error)
+	ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditEntry, error)
+	// the filters match every log when null.
+	ListAuditLogsAfter(ctx context.Context, arg ListAuditLogsAfterParams) ([]AuditLog, error)
+	ListAuditLogsBefore(ctx context.Context, arg ListAuditLogsBeforeParams) ([]AuditLog, error)
+	ListBalanceMismatches(ctx context.Context, limit int32) ([]ListBalanceMismatchesRow, error)
+	ListCardAuthorizations(ctx context.Context, arg ListCardAuthorizationsParams) ([]CardAuthorization, error)
+	ListCategoryRules(ctx context.Context, owner string) ([]CategoryRule, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	// the delinquent loans with what is past due on them, the longest overdue first.
+	ListDelinquentLoans(ctx context.Context, arg ListDelinquentLoansParams) ([]ListDelinquentLoansRow, error)
+	// the kind filter matches every entry when null.
+	ListDenylistEntries(ctx context.Context, arg ListDenylistEntriesParams) ([]DenylistEntry, error)
+	// the devices of the user that aren't revoked, the last seen first.
+	ListDevices(ctx context.Context, username string) ([]Device, error)
+	// the status filter matches every dispute when null.
+	ListDisputes(ctx context.Context, arg ListDisputesParams) ([]Dispute, error)
+	// the installments due and not paid yet, oldest first.
+	ListDueLoanInstallments(ctx context.Context, limit int32) ([]LoanInstallment, error)
+	// from_time and to_time bound created_at when set, so that only the partitions
+	// of the months in between are scanned.
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error)
+	ListEntryCategories(ctx context.Context, entryIds []int64) ([]EntryCategory, error)
+	// the active holds past their expiry, oldest first.
+	ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	ListExpiredAuthorizationHolds(ctx context.Context, limit int32) ([]AuthorizationHold, error)
+	// the entries of an account between from_time and to_time after after_id, archived ones included,
+	// with their category and tags.
+	ListExportEntries(ctx context.Context, arg ListExportEntriesParams) ([]ListExportEntriesRow, error)
+	// the pending transfers to submit and the submitted ones due to settle, oldest first.
+	ListExternalTransfersToSettle(ctx context.Context, limit int32) ([]ExternalTransfer, error)
+	// the latest version of each kind of document.
+	ListLatestLegalDocuments(ctx context.Context) ([]LegalDocument, error)
+	ListLoanInstallments(ctx context.Context, loanID int64) ([]LoanInstallment, error)
+	// every offer, or only the active ones when active_only.
+	ListLoanOffers(ctx context.Context, activeOnly bool) ([]LoanOffer, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	// the latest versions the user hasn't accepted yet.
+	ListPendingLegalDocuments(ctx context.Context, username string) ([]LegalDocument, error)
+	ListPendingOutboxEvents(ctx context.Context, limit int32) ([]EventOutbox, error)
+	ListPendingOutboxTasks(ctx context.Context, limit int32) ([]Outbox, error)
+	ListPendingReferrals(ctx context.Context, arg ListPendingReferralsParams) ([]Referral, error)
+	// the last logins of the user, the latest first.
+	ListRecentSessions(ctx context.Context, arg ListRecentSessionsParams) ([]Session, error)
+	// the referrals of referrer, the newest first.
+	ListReferrals(ctx context.Context, arg ListReferralsParams) ([]Referral, error)
+	ListRewardEntries(ctx context.Context, arg ListRewardEntriesParams) ([]RewardEntry, error)
+	// the status filter matches every hold when null.
+	ListScreeningHolds(ctx context.Context, arg ListScreeningHoldsParams) ([]ScreeningHold, error)
+	// the statement of an account spans its entries and the archived ones.
+	ListStatementEntriesAfter(ctx context.Context, arg ListStatementEntriesAfterParams) ([]Entry, error)
+	// the statement of an account spans its entries and the archived ones.
+	ListStatementEntriesBefore(ctx context.Context, arg ListStatementEntriesBeforeParams) ([]Entry, error)
+	ListTenants(ctx context.Context) ([]Tenant, error)
+	ListTransferRefunds(ctx context.Context, transferID int64) ([]Refund, error)
+	// the status and decision filters match every review when null.
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
+	// from_time and to_time bound created_at when set, so that only the partitions
+	// of the months in between are scanned.
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListTransfersAfter(ctx context.Context, arg ListTransfersAfterParams) ([]Transfer, error)
+	ListTransfersBefore(ctx context.Context, arg ListTransfersBeforeParams) ([]Transfer, error)
+	// entries don't reference their transfer, TransferTx creates them in the same transaction
+	// so they share its created_at. Archived entries still count.
+	ListUnbalancedTransfers(ctx context.Context, limit int32) ([]ListUnbalancedTransfersRow, error)
+	ListUserConsents(ctx context.Context, username string) ([]UserConsent, error)
+	// the status matches every row when null.
+	ListUserImportRowsAfter(ctx context.Context, arg ListUserImportRowsAfterParams) ([]UserImportRow, error)
+	ListUserImportRowsBefore(ctx context.Context, arg ListUserImportRowsBeforeParams) ([]UserImportRow, error)
+	// serializes the balance updates of the account until the transaction ends, see BalanceLockAdvisory.
+	LockAccountBalance(ctx context.Context, id int64) error
+	// serializes the writes of the audit logs until the transaction ends, so that each is chained to
+	// the last one.
+	LockAuditLogChain(ctx context.Context) error
+	MarkAllNotificationsRead(ctx context.Context, username string) error
+	// moves an active loan to delinquent, nothing when it was delinquent already.
+	MarkLoanDelinquent(ctx context.Context, id int64) (Loan, error)
+	MarkLoanInstallmentPaid(ctx context.Context, arg MarkLoanInstallmentPaidParams) (LoanInstallment, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkOutboxTaskPublished(ctx context.Context, id int64) error
+	MarkPaymentRequestPaid(ctx context.Context, arg MarkPaymentRequestPaidParams) (PaymentRequest, error)
+	// the name and email entries a user with full_name and email matches.
+	MatchDenylist(ctx context.Context, arg MatchDenylistParams) ([]DenylistEntry, error)
+	RenameDevice(ctx context.Context, arg RenameDeviceParams) (Device, error)
+	// reports an alert along with the hash of the code resetting the password, once.
+	ReportLoginAlert(ctx context.Context, arg ReportLoginAlertParams) (LoginAlert, error)
+	RevokeDevice(ctx context.Context, arg RevokeDeviceParams) (Device, error)
+	// keeps the time of the first revocation.
+	RevokeImpersonation(ctx context.Context, id uuid.UUID) (Impersonation, error)
+	// query is a tsquery, see SearchQuery.
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error)
+	// query is a tsquery, see SearchQuery.
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
+	SetCardFrozen(ctx context.Context, arg SetCardFrozenParams) (Card, error)
+	SetCurrencyEnabled(ctx context.Context, arg SetCurrencyEnabledParams) (Currency, error)
+	// replaces the one-time code of an untrusted device, keeping the count of its wrong codes.
+	SetDeviceVerification(ctx context.Context, arg SetDeviceVerificationParams) (Device, error)
+	// a category set by the owner replaces the one of a rule.
+	SetEntryCategory(ctx context.Context, arg SetEntryCategoryParams) (EntryCategory, error)
+	SetLoanOfferActive(ctx context.Context, arg SetLoanOfferActiveParams) (LoanOffer, error)
+	SetLoanStatus(ctx context.Context, arg SetLoanStatusParams) (Loan, error)
+	// replaces the code resetting the password of a reported alert, keeping the count of its wrong codes.
+	SetLoginAlertResetCode(ctx context.Context, arg SetLoginAlertResetCodeParams) (LoginAlert, error)
+	SetMaintenanceReadOnly(ctx context.Context, readOnly bool) (MaintenanceMode, error)
+	SetMaintenanceTransfersBlocked(ctx context.Context, transfersBlocked bool) (MaintenanceMode, error)
+	// moves a pending transfer to submitted, nothing when it was submitted in the meantime.
+	SubmitExternalTransfer(ctx context.Context, arg SubmitExternalTransferParams) (ExternalTransfer, error)
+	// the amount the approved authorizations of a card spent on the day of at, which starts at
+	// midnight in the timezone of the preferences of the owner of the card, UTC by default.
+	SumCardAuthorizations(ctx context.Context, arg SumCardAuthorizationsParams) (int64, error)
+	// the entries of an account between from_time and to_time, archived ones included, summed by
+	// category and direction. The totals are positive, an uncategorized entry has an empty category.
+	SumEntriesByCategory(ctx context.Context, arg SumEntriesByCategoryParams) ([]SumEntriesByCategoryRow, error)
+	// the entries of an account between from_time and to_time, archived ones included, summed by the
+	// day, e.g. 2026-03-29, they were created on in timezone. The days are those of the clock of the
+	// timezone, so that a day of a change of DST, 23 or 25 hours long, still is a single day.
+	SumEntriesByDay(ctx context.Context, arg SumEntriesByDayParams) ([]SumEntriesByDayRow, error)
+	// what the entries of an account created from from_time on, archived ones included, moved its
+	// balance by.
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
+	// the transfers from or to an account between from_time and to_time summed by the other account
+	// and direction, the largest totals first.
+	SumTransfersByCounterparty(ctx context.Context, arg SumTransfersByCounterpartyParams) ([]SumTransfersByCounterpartyRow, error)
+	TrustDevice(ctx context.Context, id int64) (Device, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateCardLimits(ctx context.Context, arg UpdateCardLimitsParams) (Card, error)
+	UpdateDisputeStatus(ctx context.Context, arg UpdateDisputeStatusParams) (Dispute, error)
+	UpdateScreeningHoldStatus(ctx context.Context, arg UpdateScreeningHoldStatusParams) (ScreeningHold, error)
+	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error)
+	UpdateTransferReviewStatus(ctx context.Context, arg UpdateTransferReviewStatusParams) (TransferReview, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	// preferences replaces the whole document, see the preferences package.
+	UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) ([]byte, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertAccountAlert(ctx context.Context, arg UpsertAccountAlertParams) (AccountAlert, error)
+	// registers the device a user logs in from. Logging in again from a known device keeps its name,
+	// and from a revoked one registers it again, untrusted.
+	UpsertDevice(ctx context.Context, arg UpsertDeviceParams) (Device, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of the referrals.
+const (
+	ReferralPending  = "pending"
+	ReferralRewarded = "rewarded"
+	ReferralRejected = "rejected"
+)
+
+var (
+	// ErrInvalidReferralCode is returned by CreateUserTx for a referral code nobody has.
+	ErrInvalidReferralCode = errors.New("invalid referral code")
+	// ErrReferralClosed is returned by RewardReferralTx for a referral no longer pending.
+	ErrReferralClosed = errors.New("referral is no longer pending")
+	// ErrNoReferrerAccount is returned by RewardReferralTx when the referrer has no account to
+	// pay its bonus to.
+	ErrNoReferrerAccount = errors.New("referrer has no account")
+)
+
+// referUser records user as referred by the owner of code.
+func referUser(ctx context.Context, q *Queries, code string, user User) (*Referral, error) {
+	referralCode, err := q.GetReferralCodeByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil, ErrInvalidReferralCode
+		}
+		return nil, err
+	}
+	referrer, err := q.GetUser(ctx, referralCode.Username)
+	if err != nil {
+		return nil, err
+	}
+	// a code doesn't refer users to another tenant than that of its owner
+	if referrer.TenantID != user.TenantID {
+		return nil, ErrInvalidReferralCode
+	}
+	referral, err := q.CreateReferral(ctx, CreateReferralParams{
+		Referrer: referralCode.Username,
+		Referee:  user.Username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+type RewardReferralTxParams struct {
+	ID int64
+	// Transfer is the qualifying transfer of the referee, whose payer account is paid its bonus.
+	Transfer Transfer
+	// Bonus is what each of the referrer and the referee is paid.
+	Bonus int64
+	// MaxPerReferrer caps the referrals a referrer is paid for, 0 for no cap.
+	MaxPerReferrer int64
+	// AlertTask is that of the credits, see TransferTxParams.
+	AlertTask AlertTaskFunc
+}
+
+type RewardReferralTxResult struct {
+	Referral Referral `json:"referral"`
+	// ReferrerTransfer and RefereeTransfer are the bonuses paid, none when the referral was
+	// rejected.
+	ReferrerTransfer *TransferTxResult `json:"referrer_transfer"`
+	RefereeTransfer  *TransferTxResult `json:"referee_transfer"`
+}
+
+// RewardReferralTx pays the bonuses of a pending referral whose referee qualified, from the
+// rewards accounts: to the account the referee qualified with, and to the oldest account of the
+// referrer. A referrer already paid for MaxPerReferrer referrals is paid nothing, and the referral
+// is rejected instead.
+func (store *SQLStore) RewardReferralTx(ctx context.Context, arg RewardReferralTxParams) (RewardReferralTxResult, error) {
+	var result RewardReferralTxResult
+
+	err := store.execTx(ctx, "RewardReferralTx", func(ctx context.Context, q *Queries) error {
+		// the referral stays locked until it is closed, so that it is paid once
+		referral, err := q.GetReferralForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if referral.Status != ReferralPending {
+			return ErrReferralClosed
+		}
+
+		if arg.MaxPerReferrer > 0 {
+			rewarded, err := q.CountRewardedReferrals(ctx, referral.Referrer)
+			if err != nil {
+				return err
+			}
+			if rewarded >= arg.MaxPerReferrer {
+				result.Referral, err = q.CloseReferral(ctx, CloseReferralParams{
+					ID:     referral.ID,
+					Status: ReferralRejected,
+					Reason: "the referrer reached the cap of rewarded referrals",
+				})
+				return err
+			}
+		}
+
+		refereeAccount, err := q.GetAccount(ctx, arg.Transfer.FromAccountID)
+		if err != nil {
+			return err
+		}
+		referrerAccounts, err := q.ListAccounts(ctx, ListAccountsParams{
+			Owner: referral.Referrer,
+			Limit: 1,
+		})
+		if err != nil {
+			return err
+		}
+		if len(referrerAccounts) == 0 {
+			return ErrNoReferrerAccount
+		}
+
+		result.ReferrerTransfer, err = store.payReferralBonus(ctx, q, referrerAccounts[0], arg)
+		if err != nil {
+			return err
+		}
+		result.RefereeTransfer, err = store.payReferralBonus(ctx, q, refereeAccount, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Referral, err = q.CloseReferral(ctx, CloseReferralParams{
+			ID:                   referral.ID,
+			Status:               ReferralRewarded,
+			QualifyingTransferID: pgtype.Int8{Int64: arg.Transfer.ID, Valid: true},
+			ReferrerTransferID:   pgtype.Int8{Int64: result.ReferrerTransfer.Transfer.ID, Valid: true},
+			RefereeTransferID:    pgtype.Int8{Int64: result.RefereeTransfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+	return result, err
+}
+
+// payReferralBonus credits the bonus of a referral to account, from the rewards account of its
+// currency.
+func (store *SQLStore) payReferralBonus(ctx context.Context, q *Queries, account Account, arg RewardReferralTxParams) (*TransferTxResult, error) {
+	rewards, err := q.GetRewardsAccount(ctx, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+	result, err := store.transfer(ctx, q, TransferTxParams{
+		FromAccountID: rewards.ID,
+		ToAccountID:   account.ID,
+		Amount:        arg.Bonus,
+		Memo:          "referral bonus",
+		AlertTask:     arg.AlertTask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
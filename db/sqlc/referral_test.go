@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomReferralCode(t *testing.T, username string) ReferralCode {
+	code, err := testQuires.CreateReferralCode(context.Background(), CreateReferralCodeParams{
+		Username: username,
+		Code:     strings.ToUpper(util.RandomString(8)),
+	})
+	require.NoError(t, err)
+
+	// a user has one code
+	_, err = testQuires.CreateReferralCode(context.Background(), CreateReferralCodeParams{
+		Username: username,
+		Code:     strings.ToUpper(util.RandomString(8)),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	return code
+}
+
+// signUpReferred creates a user referred with code.
+func signUpReferred(t *testing.T, store Store, code string) (User, Referral) {
+	fixture := testfixtures.NewUser(t)
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       fixture.Username,
+			HashedPassword: fixture.HashedPassword,
+			FullName:       fixture.FullName,
+			Email:          fixture.Email,
+		},
+		ReferralCode: code,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Referral)
+	require.Equal(t, result.User.Username, result.Referral.Referee)
+	require.Equal(t, ReferralPending, result.Referral.Status)
+	return result.User, *result.Referral
+}
+
+func TestCreateUserTxReferralCode(t *testing.T) {
+	store := NewStore(testDB)
+
+	fixture := testfixtures.NewUser(t)
+	_, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       fixture.Username,
+			HashedPassword: fixture.HashedPassword,
+			FullName:       fixture.FullName,
+			Email:          fixture.Email,
+		},
+		ReferralCode: "NOSUCHCO",
+	})
+	require.ErrorIs(t, err, ErrInvalidReferralCode)
+
+	// the signup is rolled back
+	_, err = testQuires.GetUser(context.Background(), fixture.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestRewardReferralTx(t *testing.T) {
+	store := NewStore(testDB)
+	referrer := createRandomUser(t)
+	referrerAccount := createRandomAccount(t, testfixtures.WithOwner(referrer.Username), testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(0))
+	code := createRandomReferralCode(t, referrer.Username)
+
+	referee, referral := signUpReferred(t, store, code.Code)
+	require.Equal(t, referrer.Username, referral.Referrer)
+	refereeAccount := createRandomAccount(t, testfixtures.WithOwner(referee.Username), testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(10000))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD))
+
+	// paying the referrer doesn't qualify, nor does an amount too small
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: refereeAccount.ID,
+		ToAccountID:   createRandomAccount(t, testfixtures.WithOwner(referrer.Username), testfixtures.WithCurrency(util.USD)).ID,
+		Amount:        1000,
+	})
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: refereeAccount.ID,
+		ToAccountID:   payee.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	qualifying := GetFirstQualifyingTransferParams{
+		Referee:   referee.Username,
+		Referrer:  referrer.Username,
+		MinAmount: 500,
+		FromTime:  referral.CreatedAt,
+		ToTime:    referral.CreatedAt.Add(time.Hour),
+	}
+	_, err = testQuires.GetFirstQualifyingTransfer(context.Background(), qualifying)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: refereeAccount.ID,
+		ToAccountID:   payee.ID,
+		Amount:        500,
+	})
+	require.NoError(t, err)
+	transfer, err := testQuires.GetFirstQualifyingTransfer(context.Background(), qualifying)
+	require.NoError(t, err)
+	require.Equal(t, paid.Transfer.ID, transfer.ID)
+
+	result, err := store.RewardReferralTx(context.Background(), RewardReferralTxParams{
+		ID:             referral.ID,
+		Transfer:       transfer,
+		Bonus:          1000,
+		MaxPerReferrer: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReferralRewarded, result.Referral.Status)
+	require.Equal(t, transfer.ID, result.Referral.QualifyingTransferID.Int64)
+	require.True(t, result.Referral.ClosedAt.Valid)
+
+	require.Equal(t, referrerAccount.ID, result.ReferrerTransfer.ToAccount.ID)
+	require.Equal(t, int64(1000), result.ReferrerTransfer.ToAccount.Balance)
+	require.Equal(t, RewardsOwner, result.ReferrerTransfer.FromAccount.Owner)
+	require.Equal(t, util.EUR, result.ReferrerTransfer.FromAccount.Currency)
+	require.Equal(t, refereeAccount.ID, result.RefereeTransfer.ToAccount.ID)
+	require.Equal(t, int64(10000-1000-10-500+1000), result.RefereeTransfer.ToAccount.Balance)
+	require.Equal(t, result.ReferrerTransfer.Transfer.ID, result.Referral.ReferrerTransferID.Int64)
+	require.Equal(t, result.RefereeTransfer.Transfer.ID, result.Referral.RefereeTransferID.Int64)
+
+	// a referral is paid once
+	_, err = store.RewardReferralTx(context.Background(), RewardReferralTxParams{
+		ID:       referral.ID,
+		Transfer: transfer,
+		Bonus:    1000,
+	})
+	require.ErrorIs(t, err, ErrReferralClosed)
+
+	referrals, err := testQuires.ListReferrals(context.Background(), ListReferralsParams{Referrer: referrer.Username, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, referrals, 1)
+	require.Equal(t, result.Referral, referrals[0])
+}
+
+func TestRewardReferralTxCap(t *testing.T) {
+	store := NewStore(testDB)
+	referrer := createRandomUser(t)
+	createRandomAccount(t, testfixtures.WithOwner(referrer.Username), testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+	code := createRandomReferralCode(t, referrer.Username)
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD))
+
+	var statuses []string
+	for i := 0; i < 2; i++ {
+		referee, referral := signUpReferred(t, store, code.Code)
+		account := createRandomAccount(t, testfixtures.WithOwner(referee.Username), testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+		paid, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   payee.ID,
+			Amount:        1000,
+		})
+		require.NoError(t, err)
+
+		result, err := store.RewardReferralTx(context.Background(), RewardReferralTxParams{
+			ID:             referral.ID,
+			Transfer:       paid.Transfer,
+			Bonus:          100,
+			MaxPerReferrer: 1,
+		})
+		require.NoError(t, err)
+		statuses = append(statuses, result.Referral.Status)
+		if result.Referral.Status == ReferralRejected {
+			require.Nil(t, result.ReferrerTransfer)
+			require.Nil(t, result.RefereeTransfer)
+			require.NotEmpty(t, result.Referral.Reason)
+		}
+	}
+	require.Equal(t, []string{ReferralRewarded, ReferralRejected}, statuses)
+
+	count, err := testQuires.CountRewardedReferrals(context.Background(), referrer.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestRewardReferralTxNoReferrerAccount(t *testing.T) {
+	store := NewStore(testDB)
+	referrer := createRandomUser(t)
+	code := createRandomReferralCode(t, referrer.Username)
+
+	referee, referral := signUpReferred(t, store, code.Code)
+	account := createRandomAccount(t, testfixtures.WithOwner(referee.Username), testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   createRandomAccount(t, testfixtures.WithCurrency(util.USD)).ID,
+		Amount:        1000,
+	})
+	require.NoError(t, err)
+
+	_, err = store.RewardReferralTx(context.Background(), RewardReferralTxParams{
+		ID:       referral.ID,
+		Transfer: paid.Transfer,
+		Bonus:    100,
+	})
+	require.ErrorIs(t, err, ErrNoReferrerAccount)
+
+	// the referral stays pending for the next run
+	pending, err := testQuires.ListPendingReferrals(context.Background(), ListPendingReferralsParams{AfterID: referral.ID - 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, referral.ID, pending[0].ID)
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRefundExceedsTransfer is returned by RefundTransferTx for a refund, and by SetDisputeStatusTx
+// for a reversal, taking what went back from the transfer over its amount.
+var ErrRefundExceedsTransfer = errors.New("refunds can't exceed the amount of the transfer")
+
+type RefundTransferTxParams struct {
+	TransferID int64
+	// Amount is what goes back to the account the transfer debited, at most what wasn't refunded
+	// yet.
+	Amount int64
+	Reason string
+	// Username is the user refunding the transfer, the owner of the account it credited.
+	Username string
+	// OutboxTasks and AlertTask are those of the refund transfer, see TransferTxParams.
+	OutboxTasks []CreateOutboxTaskParams
+	AlertTask   AlertTaskFunc
+}
+
+type RefundTransferTxResult struct {
+	Refund   Refund           `json:"refund"`
+	Transfer TransferTxResult `json:"transfer"`
+	// RefundedAmount is the amount of all the refunds of the transfer, this one included.
+	RefundedAmount int64 `json:"refunded_amount"`
+	// RemainingAmount is what can still be refunded, the reversal of a refunded dispute deducted.
+	RemainingAmount int64 `json:"remaining_amount"`
+}
+
+// checkReturnedAmount fails when paying amount back from the transfer took what went back from it,
+// by its refunds and the reversal of its refunded dispute, over limit, the amount the transfer
+// credited. The transfer is locked with GetTransferForUpdate, so that a refund and a reversal at
+// once can't both see the amount the other pays back as still returnable.
+func checkReturnedAmount(ctx context.Context, q *Queries, transferID int64, limit int64, amount int64) (GetReturnedAmountRow, error) {
+	returned, err := q.GetReturnedAmount(ctx, transferID)
+	if err != nil {
+		return returned, err
+	}
+	total := returned.Refunded + returned.Reversed
+	if amount <= 0 || total+amount > limit {
+		return returned, fmt.Errorf("%w: %d of %d already returned", ErrRefundExceedsTransfer, total, limit)
+	}
+	return returned, nil
+}
+
+// RefundTransferTx pays back all or part of a transfer, from the account it credited to the one
+// it debited, with a transfer refused like TransferTx refuses it. Unlike the reversal of a
+// refunded dispute, a transfer can be refunded several times, as long as its refunds and its
+// reversal add up to its amount at most.
+func (store *SQLStore) RefundTransferTx(ctx context.Context, arg RefundTransferTxParams) (RefundTransferTxResult, error) {
+	var result RefundTransferTxResult
+
+	err := store.execTx(ctx, "RefundTransferTx", func(ctx context.Context, q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+		returned, err := checkReturnedAmount(ctx, q, transfer.ID, transfer.Amount, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: transfer.ToAccountID,
+			ToAccountID:   transfer.FromAccountID,
+			Amount:        arg.Amount,
+			Memo:          fmt.Sprintf("refund of transfer #%d", transfer.ID),
+			OutboxTasks:   arg.OutboxTasks,
+			AlertTask:     arg.AlertTask,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Refund, err = q.CreateRefund(ctx, CreateRefundParams{
+			TransferID:       transfer.ID,
+			RefundTransferID: result.Transfer.Transfer.ID,
+			Amount:           arg.Amount,
+			Reason:           arg.Reason,
+			CreatedBy:        arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+		result.RefundedAmount = returned.Refunded + arg.Amount
+		result.RemainingAmount = transfer.Amount - returned.Reversed - result.RefundedAmount
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        80,
+	})
+	require.NoError(t, err)
+
+	first, err := store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     30,
+		Reason:     "returned item",
+		Username:   payee.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, paid.Transfer.ID, first.Refund.TransferID)
+	require.Equal(t, first.Transfer.Transfer.ID, first.Refund.RefundTransferID)
+	require.Equal(t, payee.ID, first.Transfer.Transfer.FromAccountID)
+	require.Equal(t, payer.ID, first.Transfer.Transfer.ToAccountID)
+	require.Equal(t, int64(30), first.RefundedAmount)
+	require.Equal(t, int64(50), first.RemainingAmount)
+	require.Equal(t, int64(50), first.Transfer.FromAccount.Balance)
+	require.Equal(t, int64(50), first.Transfer.ToAccount.Balance)
+
+	// the refunds add up to the amount of the transfer at most
+	_, err = store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     51,
+		Username:   payee.Owner,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	second, err := store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     50,
+		Username:   payee.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(80), second.RefundedAmount)
+	require.Zero(t, second.RemainingAmount)
+
+	refunds, err := testQuires.ListTransferRefunds(context.Background(), paid.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+	require.Equal(t, first.Refund, refunds[0])
+	require.Equal(t, "returned item", refunds[0].Reason)
+}
+
+func TestRefundedTransferDispute(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	// the payee has more than the hold of the dispute, so that it can still refund the transfer
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(1000))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        80,
+	})
+	require.NoError(t, err)
+	_, err = store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     30,
+		Username:   payee.Owner,
+	})
+	require.NoError(t, err)
+
+	// the dispute holds what wasn't refunded
+	dispute, err := store.OpenDisputeTx(context.Background(), CreateDisputeParams{
+		TransferID: paid.Transfer.ID,
+		AccountID:  payee.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     paid.Transfer.Amount,
+		Reason:     "item never arrived",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), dispute.Amount)
+
+	// a refund after the dispute was opened leaves its reversal nothing to pay back
+	_, err = store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     50,
+		Username:   payee.Owner,
+	})
+	require.NoError(t, err)
+	_, err = store.SetDisputeStatusTx(context.Background(), SetDisputeStatusTxParams{
+		ID:         dispute.ID,
+		Status:     DisputeRefunded,
+		Resolution: "refund the payer",
+		Admin:      "admin",
+		Audit:      randomAuditEntry("account:" + util.RandomString(10)),
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	returned, err := testQuires.GetReturnedAmount(context.Background(), paid.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(80), returned.Refunded)
+	require.Zero(t, returned.Reversed)
+
+	// nor can a new dispute hold anything of a transfer refunded in full
+	paid, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	_, err = store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     10,
+		Username:   payee.Owner,
+	})
+	require.NoError(t, err)
+	_, err = store.OpenDisputeTx(context.Background(), CreateDisputeParams{
+		TransferID: paid.Transfer.ID,
+		AccountID:  payee.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     paid.Transfer.Amount,
+		Reason:     "item never arrived",
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+}
+
+func TestReversedTransferRefund(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(100))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        80,
+	})
+	require.NoError(t, err)
+	dispute, err := store.OpenDisputeTx(context.Background(), CreateDisputeParams{
+		TransferID: paid.Transfer.ID,
+		AccountID:  payee.ID,
+		OpenedBy:   payer.Owner,
+		Amount:     paid.Transfer.Amount,
+		Reason:     "I didn't make this payment",
+	})
+	require.NoError(t, err)
+	_, err = store.SetDisputeStatusTx(context.Background(), SetDisputeStatusTxParams{
+		ID:         dispute.ID,
+		Status:     DisputeRefunded,
+		Resolution: "refund the payer",
+		Admin:      "admin",
+		Audit:      randomAuditEntry("account:" + util.RandomString(10)),
+	})
+	require.NoError(t, err)
+
+	// the reversal paid the whole transfer back, so it can't be refunded as well
+	_, err = store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+		TransferID: paid.Transfer.ID,
+		Amount:     1,
+		Username:   payee.Owner,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+}
+
+func TestRefundTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(100))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.EUR), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// only two of the refunds fit in the amount of the transfer
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.RefundTransferTx(context.Background(), RefundTransferTxParams{
+				TransferID: paid.Transfer.ID,
+				Amount:     40,
+				Username:   payee.Owner,
+			})
+			errs <- err
+		}()
+	}
+	refunded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			refunded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+	}
+	require.Equal(t, 2, refunded)
+
+	amount, err := testQuires.GetRefundedAmount(context.Background(), paid.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(80), amount)
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	replicaCheckInterval = 5 * time.Second
+	replicaCheckTimeout  = 2 * time.Second
+)
+
+// replicaLagQuery returns how far behind the primary the replica is, in seconds. A replica that
+// replayed all it received isn't lagging, however old its last transaction is, and a server that
+// isn't a replica returns 0.
+const replicaLagQuery = `SELECT COALESCE(
+  CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+  ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)::float8`
+
+// Replica is a read replica the store sends the read-only queries that tolerate stale data to.
+// It is only used while Run finds it reachable and at most maxLag behind the primary, and until
+// then, or when a query fails to reach it, the queries fall back to the primary.
+type Replica struct {
+	connPool *pgxpool.Pool
+	queries  *Queries
+	maxLag   time.Duration
+	healthy  atomic.Bool
+}
+
+func NewReplica(connPool *pgxpool.Pool, maxLag time.Duration) *Replica {
+	return &Replica{
+		connPool: connPool,
+		queries:  New(newInstrumentedDB(connPool)),
+		maxLag:   maxLag,
+	}
+}
+
+// Run checks the health and the lag of the replica until ctx is done.
+func (replica *Replica) Run(ctx context.Context) {
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		replica.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (replica *Replica) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lagSeconds float64
+	err := replica.connPool.QueryRow(ctx, replicaLagQuery).Scan(&lagSeconds)
+	if err == nil && lagSeconds > replica.maxLag.Seconds() {
+		err = errors.New("replica is lagging behind")
+	}
+
+	healthy := err == nil
+	if replica.healthy.Swap(healthy) != healthy {
+		if healthy {
+			log.Info().Msg("read replica is healthy, sending reads to it")
+		} else {
+			log.Warn().Err(err).Float64("lag seconds", lagSeconds).Msg("read replica is unhealthy, sending reads to the primary")
+		}
+	}
+}
+
+// reader returns the queries of the replica, or nil when the reads must go to the primary.
+func (replica *Replica) reader() *Queries {
+	if replica == nil || !replica.healthy.Load() {
+		return nil
+	}
+	return replica.queries
+}
+
+// failed tells whether err means the replica couldn't answer, rather than an answer like a
+// missing row, in which case the replica is left alone until the next check finds it healthy.
+func (replica *Replica) failed(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, ErrRecordNotFound) || ErrorCode(err) != "" || ctx.Err() != nil {
+		return false
+	}
+	if replica.healthy.Swap(false) {
+		log.Warn().Err(err).Msg("read replica query failed, sending reads to the primary")
+	}
+	return true
+}
+
+// GetAccount reads the account from the replica when there is a healthy one.
+func (store *SQLStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	if q := store.replica.reader(); q != nil {
+		account, err := q.GetAccount(ctx, id)
+		if !store.replica.failed(ctx, err) {
+			return account, err
+		}
+	}
+	return store.Queries.GetAccount(ctx, id)
+}
+
+// GetPrimaryAccount reads the account from the primary, even when there is a healthy replica, for
+// the reads that must not be stale, e.g. the one filling the account cache.
+func (store *SQLStore) GetPrimaryAccount(ctx context.Context, id int64) (Account, error) {
+	return store.Queries.GetAccount(ctx, id)
+}
+
+// ListAccounts reads the accounts from the replica when there is a healthy one.
+func (store *SQLStore) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
+	if q := store.replica.reader(); q != nil {
+		accounts, err := q.ListAccounts(ctx, arg)
+		if !store.replica.failed(ctx, err) {
+			return accounts, err
+		}
+	}
+	return store.Queries.ListAccounts(ctx, arg)
+}
+
+// ListEntries reads the entries from the replica when there is a healthy one.
+func (store *SQLStore) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	if q := store.replica.reader(); q != nil {
+		entries, err := q.ListEntries(ctx, arg)
+		if !store.replica.failed(ctx, err) {
+			return entries, err
+		}
+	}
+	return store.Queries.ListEntries(ctx, arg)
+}
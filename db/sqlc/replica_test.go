@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	// the test database isn't a replica, so it is never lagging
+	replica := NewReplica(testDB, time.Second)
+	store := NewStoreWithReplica(testDB, replica)
+	require.Nil(t, replica.reader())
+
+	replica.check(context.Background())
+	require.NotNil(t, replica.reader())
+
+	account := createRandomAccount(t)
+	got, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account, got)
+
+	_, err = store.GetAccount(context.Background(), 0)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.NotNil(t, replica.reader())
+
+	got, err = store.GetPrimaryAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account, got)
+}
+
+func TestReplicaFailed(t *testing.T) {
+	replica := NewReplica(nil, time.Second)
+	replica.healthy.Store(true)
+	ctx := context.Background()
+
+	require.False(t, replica.failed(ctx, nil))
+	require.False(t, replica.failed(ctx, ErrRecordNotFound))
+	require.False(t, replica.failed(ctx, &pgconn.PgError{Code: UniqueViolation}))
+	require.NotNil(t, replica.reader())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.False(t, replica.failed(canceled, context.Canceled))
+	require.NotNil(t, replica.reader())
+
+	// a replica that can't be reached is left out until the next check
+	require.True(t, replica.failed(ctx, errors.New("connection refused")))
+	require.Nil(t, replica.reader())
+
+	var none *Replica
+	require.Nil(t, none.reader())
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// archiveBatchSize is the number of entries each statement of ArchiveEntriesBefore moves, so that
+// no transaction holds the locks of a whole year of entries.
+const archiveBatchSize = 10000
+
+// ArchiveEntriesBefore moves the entries created before the cutoff to entries_archive, in batches,
+// and returns how many it moved. The statements and the ledger verification read both tables, so
+// the move is invisible to them.
+func (store *SQLStore) ArchiveEntriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		moved, err := store.ArchiveEntries(ctx, ArchiveEntriesParams{
+			Before: before,
+			Limit:  archiveBatchSize,
+		})
+		total += moved
+		if err != nil || moved < archiveBatchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// RewardsOwner is the user owning the rewards accounts, see GetRewardsAccount.
+const RewardsOwner = "bank.rewards"
+
+// The kinds of the reward entries.
+const (
+	RewardAccrual    = "accrual"
+	RewardRedemption = "redemption"
+)
+
+// RewardTaskFunc returns the outbox task accruing the cashback points of a transfer.
+type RewardTaskFunc func(transfer Transfer) (CreateOutboxTaskParams, error)
+
+// ErrInsufficientPoints is returned by RedeemRewardsTx for more points than the account has.
+var ErrInsufficientPoints = errors.New("insufficient reward points")
+
+type RedeemRewardsTxParams struct {
+	AccountID int64
+	// Points are credited to the account as as many units of its currency, e.g. cents.
+	Points int64
+	// AlertTask is that of the credit, see TransferTxParams.
+	AlertTask AlertTaskFunc
+}
+
+type RedeemRewardsTxResult struct {
+	Entry    RewardEntry      `json:"entry"`
+	Transfer TransferTxResult `json:"transfer"`
+	// Balance is the points the account has left.
+	Balance int64 `json:"balance"`
+}
+
+// RedeemRewardsTx credits points of an account to it, with a transfer from the rewards account of
+// its currency, and takes them off its reward balance.
+func (store *SQLStore) RedeemRewardsTx(ctx context.Context, arg RedeemRewardsTxParams) (RedeemRewardsTxResult, error) {
+	var result RedeemRewardsTxResult
+
+	err := store.execTx(ctx, "RedeemRewardsTx", func(ctx context.Context, q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		rewards, err := q.GetRewardsAccount(ctx, account.Currency)
+		if err != nil {
+			return err
+		}
+		// the account stays locked until the redemption is written, so that two redemptions
+		// can't both spend the same points
+		if err := store.lockTransferAccounts(ctx, q, rewards.ID, account.ID); err != nil {
+			return err
+		}
+		balance, err := q.GetRewardBalance(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		if arg.Points <= 0 || arg.Points > balance {
+			return ErrInsufficientPoints
+		}
+
+		result.Transfer, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountID: rewards.ID,
+			ToAccountID:   account.ID,
+			Amount:        arg.Points,
+			Memo:          "cashback redemption",
+			AlertTask:     arg.AlertTask,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateRewardEntry(ctx, CreateRewardEntryParams{
+			AccountID:  account.ID,
+			Kind:       RewardRedemption,
+			Points:     -arg.Points,
+			TransferID: result.Transfer.Transfer.ID,
+		})
+		result.Balance = balance - arg.Points
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedeemRewardsTx(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.CAD), testfixtures.WithBalance(10000))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.CAD), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        5000,
+	})
+	require.NoError(t, err)
+
+	accrual, err := testQuires.CreateRewardEntry(context.Background(), CreateRewardEntryParams{
+		AccountID:  payer.ID,
+		Kind:       RewardAccrual,
+		Points:     50,
+		TransferID: paid.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), accrual.Points)
+
+	// a transfer is rewarded once
+	_, err = testQuires.CreateRewardEntry(context.Background(), CreateRewardEntryParams{
+		AccountID:  payer.ID,
+		Kind:       RewardAccrual,
+		Points:     50,
+		TransferID: paid.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = store.RedeemRewardsTx(context.Background(), RedeemRewardsTxParams{AccountID: payer.ID, Points: 51})
+	require.ErrorIs(t, err, ErrInsufficientPoints)
+
+	result, err := store.RedeemRewardsTx(context.Background(), RedeemRewardsTxParams{AccountID: payer.ID, Points: 30})
+	require.NoError(t, err)
+	require.Equal(t, RewardRedemption, result.Entry.Kind)
+	require.Equal(t, int64(-30), result.Entry.Points)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Entry.TransferID)
+	require.Equal(t, RewardsOwner, result.Transfer.FromAccount.Owner)
+	require.Equal(t, int64(5030), result.Transfer.ToAccount.Balance)
+	require.Equal(t, int64(20), result.Balance)
+
+	balance, err := testQuires.GetRewardBalance(context.Background(), payer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), balance)
+
+	entries, err := testQuires.ListRewardEntries(context.Background(), ListRewardEntriesParams{AccountID: payer.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, result.Entry, entries[0])
+	require.Equal(t, accrual, entries[1])
+}
+
+func TestRedeemRewardsTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	payer := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(10000))
+	payee := createRandomAccount(t, testfixtures.WithCurrency(util.USD), testfixtures.WithBalance(0))
+
+	paid, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   payee.ID,
+		Amount:        5000,
+	})
+	require.NoError(t, err)
+	_, err = testQuires.CreateRewardEntry(context.Background(), CreateRewardEntryParams{
+		AccountID:  payer.ID,
+		Kind:       RewardAccrual,
+		Points:     100,
+		TransferID: paid.Transfer.ID,
+	})
+	require.NoError(t, err)
+
+	// only two of the redemptions fit in the points of the account
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.RedeemRewardsTx(context.Background(), RedeemRewardsTxParams{AccountID: payer.ID, Points: 40})
+			errs <- err
+		}()
+	}
+
+	redeemed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			redeemed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrInsufficientPoints)
+	}
+	require.Equal(t, 2, redeemed)
+
+	balance, err := testQuires.GetRewardBalance(context.Background(), payer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), balance)
+}
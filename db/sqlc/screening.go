@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The kinds of the denylist entries: the full name or the email of a user, or the id of an account.
+const (
+	DenylistName    = "name"
+	DenylistEmail   = "email"
+	DenylistAccount = "account"
+)
+
+// The statuses of a screening hold. A pending hold waits for an admin, who clears it for a namesake
+// or confirms the match; the transfers of the user are held for a review until it is cleared.
+const (
+	HoldPending   = "pending"
+	HoldCleared   = "cleared"
+	HoldConfirmed = "confirmed"
+)
+
+var (
+	// ErrDenylistEntryExists is returned by AddDenylistEntryTx for a value already on the denylist.
+	ErrDenylistEntryExists = errors.New("denylist entry already exists")
+	// ErrHoldCleared is returned by SetScreeningHoldStatusTx for a hold already cleared.
+	ErrHoldCleared = errors.New("screening hold is cleared")
+	// ErrInvalidHoldStatus is returned by SetScreeningHoldStatusTx for a status it can't move to.
+	ErrInvalidHoldStatus = errors.New("invalid screening hold status")
+)
+
+// ScreenFunc screens a user, returning what it matched, e.g. name:John Doe for its full name on
+// the denylist.
+type ScreenFunc func(ctx context.Context, user User) ([]string, error)
+
+// Match is how a hold lists the entry a user matched, the same as HoldUsersMatchingDenylist.
+func (entry DenylistEntry) Match() string {
+	return entry.Kind + ":" + entry.Value
+}
+
+// Open tells whether the hold still holds the transfers of the user.
+func (hold ScreeningHold) Open() bool {
+	return hold.Status == HoldPending || hold.Status == HoldConfirmed
+}
+
+// screenUser holds user when screen matches it.
+func screenUser(ctx context.Context, q *Queries, screen ScreenFunc, user User) (*ScreeningHold, error) {
+	matches, err := screen(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen user: %w", err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	hold, err := q.CreateScreeningHold(ctx, CreateScreeningHoldParams{
+		Username: user.Username,
+		Matches:  matches,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+type AddDenylistEntryTxParams struct {
+	CreateDenylistEntryParams
+	Audit CreateAuditEntryParams
+}
+
+type AddDenylistEntryTxResult struct {
+	Entry DenylistEntry `json:"entry"`
+	// Holds are those of the users the entry matched, but those held already.
+	Holds []ScreeningHold `json:"holds"`
+}
+
+// AddDenylistEntryTx adds an entry to the denylist and holds the existing users it matches, the
+// users signing up later being screened by CreateUserTx.
+func (store *SQLStore) AddDenylistEntryTx(ctx context.Context, arg AddDenylistEntryTxParams) (AddDenylistEntryTxResult, error) {
+	var result AddDenylistEntryTxResult
+
+	err := store.execTx(ctx, "AddDenylistEntryTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		result.Entry, err = q.CreateDenylistEntry(ctx, arg.CreateDenylistEntryParams)
+		if ErrorCode(err) == UniqueViolation {
+			return ErrDenylistEntryExists
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Holds, err = q.HoldUsersMatchingDenylist(ctx, HoldUsersMatchingDenylistParams{
+			Kind:  result.Entry.Kind,
+			Value: result.Entry.Value,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return result, err
+}
+
+type DeleteDenylistEntryTxParams struct {
+	ID    int64
+	Audit CreateAuditEntryParams
+}
+
+// DeleteDenylistEntryTx removes an entry from the denylist. The holds it caused stay, an admin
+// clears them.
+func (store *SQLStore) DeleteDenylistEntryTx(ctx context.Context, arg DeleteDenylistEntryTxParams) (DenylistEntry, error) {
+	var entry DenylistEntry
+
+	err := store.execTx(ctx, "DeleteDenylistEntryTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		entry, err = q.DeleteDenylistEntry(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return entry, err
+}
+
+type SetScreeningHoldStatusTxParams struct {
+	ID int64
+	// Status is cleared, or confirmed for a true match.
+	Status string
+	Note   string
+	Admin  string
+	Audit  CreateAuditEntryParams
+}
+
+// SetScreeningHoldStatusTx clears a hold, or confirms a pending one. A confirmed hold can still be
+// cleared, e.g. once the user is taken off the sanctions list.
+func (store *SQLStore) SetScreeningHoldStatusTx(ctx context.Context, arg SetScreeningHoldStatusTxParams) (ScreeningHold, error) {
+	var hold ScreeningHold
+
+	if arg.Status != HoldCleared && arg.Status != HoldConfirmed {
+		return hold, fmt.Errorf("%w %q", ErrInvalidHoldStatus, arg.Status)
+	}
+
+	err := store.execTx(ctx, "SetScreeningHoldStatusTx", func(ctx context.Context, q *Queries) error {
+		current, err := q.GetScreeningHoldForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !current.Open() {
+			return ErrHoldCleared
+		}
+		if current.Status == arg.Status {
+			return fmt.Errorf("%w: hold is already %s", ErrInvalidHoldStatus, arg.Status)
+		}
+
+		hold, err = q.UpdateScreeningHoldStatus(ctx, UpdateScreeningHoldStatusParams{
+			ID:         current.ID,
+			Status:     arg.Status,
+			ReviewedBy: arg.Admin,
+			Note:       arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return hold, err
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDenylistEntryTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := AddDenylistEntryTxParams{
+		CreateDenylistEntryParams: CreateDenylistEntryParams{
+			Kind:      DenylistEmail,
+			Value:     user.Email,
+			Reason:    "sanctions list",
+			CreatedBy: "admin",
+		},
+		Audit: randomAuditEntry("denylist:email:" + user.Email),
+	}
+	result, err := store.AddDenylistEntryTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.Email, result.Entry.Value)
+	require.Len(t, result.Holds, 1)
+	require.Equal(t, user.Username, result.Holds[0].Username)
+	require.Equal(t, []string{"email:" + user.Email}, result.Holds[0].Matches)
+	require.Equal(t, HoldPending, result.Holds[0].Status)
+
+	_, err = store.AddDenylistEntryTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrDenylistEntryExists)
+
+	entries, err := testQuires.MatchDenylist(context.Background(), MatchDenylistParams{
+		FullName: user.FullName,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	deleted, err := store.DeleteDenylistEntryTx(context.Background(), DeleteDenylistEntryTxParams{
+		ID:    result.Entry.ID,
+		Audit: arg.Audit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Entry.ID, deleted.ID)
+
+	// the hold outlives the entry until an admin clears it
+	hold, err := testQuires.GetScreeningHold(context.Background(), result.Holds[0].ID)
+	require.NoError(t, err)
+	require.True(t, hold.Open())
+}
+
+func TestListAccountScreeningMatches(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	matches, err := testQuires.ListAccountScreeningMatches(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	value := strconv.FormatInt(account.ID, 10)
+	_, err = store.AddDenylistEntryTx(context.Background(), AddDenylistEntryTxParams{
+		CreateDenylistEntryParams: CreateDenylistEntryParams{
+			Kind:      DenylistAccount,
+			Value:     value,
+			Reason:    "mule account",
+			CreatedBy: "admin",
+		},
+		Audit: randomAuditEntry("denylist:account:" + value),
+	})
+	require.NoError(t, err)
+
+	matches, err = testQuires.ListAccountScreeningMatches(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"account:" + value}, matches)
+}
+
+func TestSetScreeningHoldStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	hold, err := testQuires.CreateScreeningHold(context.Background(), CreateScreeningHoldParams{
+		Username: account.Owner,
+		Matches:  []string{"name:" + util.RandomOwnerName()},
+	})
+	require.NoError(t, err)
+
+	matches, err := testQuires.ListAccountScreeningMatches(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, hold.Matches, matches)
+
+	arg := SetScreeningHoldStatusTxParams{
+		ID:     hold.ID,
+		Status: HoldConfirmed,
+		Note:   "same date of birth",
+		Admin:  "admin",
+		Audit:  randomAuditEntry("user:" + account.Owner),
+	}
+	confirmed, err := store.SetScreeningHoldStatusTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, HoldConfirmed, confirmed.Status)
+
+	_, err = store.SetScreeningHoldStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidHoldStatus)
+
+	arg.Status = HoldCleared
+	cleared, err := store.SetScreeningHoldStatusTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, HoldCleared, cleared.Status)
+	require.True(t, cleared.ReviewedAt.Valid)
+
+	matches, err = testQuires.ListAccountScreeningMatches(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	_, err = store.SetScreeningHoldStatusTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrHoldCleared)
+}
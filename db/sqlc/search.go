@@ -0,0 +1,19 @@
+package db
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchQuery turns the free text an admin typed into the tsquery the search queries take, matching
+// the rows with a word starting with every word of text. Anything but letters and digits separates
+// words, which also keeps the tsquery operators out. It returns "" when text has no word at all.
+func SearchQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
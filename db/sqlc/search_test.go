@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchQuery(t *testing.T) {
+	require.Equal(t, "jo:* & smi:*", SearchQuery(" Jo  smi"))
+	require.Equal(t, "alice:* & example:*", SearchQuery("alice@example"))
+	require.Equal(t, "rent:* & 2024:*", SearchQuery("rent | !2024:*"))
+	require.Empty(t, SearchQuery(" &!() "))
+}
+
+func TestSearchUsers(t *testing.T) {
+	user := createRandomUser(t)
+
+	users, err := testQuires.SearchUsers(context.Background(), SearchUsersParams{
+		Query: SearchQuery(user.Username[:4] + " " + user.Email),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, users)
+
+	found := false
+	for _, row := range users {
+		require.Positive(t, row.Rank)
+		found = found || row.Username == user.Username
+	}
+	require.True(t, found)
+}
+
+func TestSearchTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	memo := "rent " + util.RandomString(12)
+
+	transfer, err := testQuires.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Memo:          memo,
+	})
+	require.NoError(t, err)
+	require.Equal(t, memo, transfer.Memo)
+
+	transfers, err := testQuires.SearchTransfers(context.Background(), SearchTransfersParams{
+		Query: SearchQuery(memo[5:12]),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, transfer.ID, transfers[0].ID)
+	require.Equal(t, memo, transfers[0].Memo)
+}
@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 // source: session.sql
 
 package db
@@ -10,8 +10,32 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, blockUserSessions, username)
+	return err
+}
+
+const countActiveSessions = `-- name: CountActiveSessions :one
+SELECT count(*) FROM sessions
+WHERE is_blocked = false AND expires_at > now()
+`
+
+func (q *Queries) CountActiveSessions(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveSessions)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
   id,
@@ -20,24 +44,28 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  country,
+  device_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, country, device_id
 `
 
 type CreateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	ID           uuid.UUID   `json:"id"`
+	Username     string      `json:"username"`
+	RefreshToken string      `json:"refresh_token"`
+	UserAgent    string      `json:"user_agent"`
+	ClientIp     string      `json:"client_ip"`
+	IsBlocked    bool        `json:"is_blocked"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	Country      string      `json:"country"`
+	DeviceID     pgtype.Int8 `json:"device_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession,
+	row := q.db.QueryRow(ctx, createSession,
 		arg.ID,
 		arg.Username,
 		arg.RefreshToken,
@@ -45,6 +73,8 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.Country,
+		arg.DeviceID,
 	)
 	var i Session
 	err := row.Scan(
@@ -56,17 +86,110 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.Country,
+		&i.DeviceID,
 	)
 	return i, err
 }
 
+const getLastLoginCountry = `-- name: GetLastLoginCountry :one
+SELECT country FROM sessions
+WHERE username = $1 AND country <> ''
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+// the country of the last login of the user that has one.
+func (q *Queries) GetLastLoginCountry(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRow(ctx, getLastLoginCountry, username)
+	var country string
+	err := row.Scan(&country)
+	return country, err
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, country, device_id FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
-	row := q.db.QueryRowContext(ctx, getSession, id)
+	row := q.db.QueryRow(ctx, getSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.Country,
+		&i.DeviceID,
+	)
+	return i, err
+}
+
+const listRecentSessions = `-- name: ListRecentSessions :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, country, device_id FROM sessions
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type ListRecentSessionsParams struct {
+	Username string `json:"username"`
+	Limit    int32  `json:"limit"`
+}
+
+// the last logins of the user, the latest first.
+func (q *Queries) ListRecentSessions(ctx context.Context, arg ListRecentSessionsParams) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listRecentSessions, arg.Username, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.Country,
+			&i.DeviceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bindSessionDevice = `-- name: BindSessionDevice :one
+UPDATE sessions
+SET device_id = $1
+WHERE id = $2 AND device_id IS NULL
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, country, device_id
+`
+
+type BindSessionDeviceParams struct {
+	DeviceID pgtype.Int8 `json:"device_id"`
+	ID       uuid.UUID   `json:"id"`
+}
+
+// binds a session logged in before every login was bound to its device, nothing when it is
+// bound already.
+func (q *Queries) BindSessionDevice(ctx context.Context, arg BindSessionDeviceParams) (Session, error) {
+	row := q.db.QueryRow(ctx, bindSessionDevice, arg.DeviceID, arg.ID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -77,6 +200,8 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.Country,
+		&i.DeviceID,
 	)
 	return i, err
 }
@@ -0,0 +1,30 @@
+package db
+
+import "context"
+
+type CreateStepUpChallengeTxParams struct {
+	CreateStepUpChallengeParams
+	// AfterCreate returns the outbox tasks of the challenge once created, e.g. the email of its
+	// code.
+	AfterCreate func(challenge StepUpChallenge) ([]CreateOutboxTaskParams, error)
+}
+
+// CreateStepUpChallengeTx creates the challenge of a step-up along with the outbox tasks sending
+// its code.
+func (store *SQLStore) CreateStepUpChallengeTx(ctx context.Context, arg CreateStepUpChallengeTxParams) (StepUpChallenge, error) {
+	var challenge StepUpChallenge
+
+	err := store.execTx(ctx, "CreateStepUpChallengeTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		challenge, err = q.CreateStepUpChallenge(ctx, arg.CreateStepUpChallengeParams)
+		if err != nil || arg.AfterCreate == nil {
+			return err
+		}
+		tasks, err := arg.AfterCreate(challenge)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return challenge, err
+}
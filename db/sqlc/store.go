@@ -2,52 +2,631 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Store provides all the queries and transactions. Code that only needs some of them should
+// depend on the focused interfaces below instead, and so should its tests mock.
 type Store interface {
-	Querier
+	AccountStore
+	UserStore
+	TransferStore
+	SessionStore
+	NotificationStore
+	OutboxStore
+	LedgerStore
+	PartitionStore
+	RetentionStore
+	AuditStore
+	DisputeStore
+	TransferReviewStore
+	ScreeningStore
+	CategoryStore
+	AnalyticsStore
+	ExportStore
+	ExternalTransferStore
+	CardStore
+	AuthorizationHoldStore
+	PaymentRequestStore
+	RefundStore
+	LoanStore
+	RewardStore
+	ReferralStore
+	TenantStore
+	CurrencyStore
+	ExchangeRateStore
+	UserImportStore
+	ConsentStore
+	DeviceStore
+	LoginAlertStore
+	StepUpStore
+	MaintenanceStore
+	GetPrimaryAccount(ctx context.Context, id int64) (Account, error)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+// Store must keep covering every query sqlc generates.
+var _ Querier = Store(nil)
+
+// AccountStore reads and writes the accounts and their alert rules.
+type AccountStore interface {
+	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	DeleteAccount(ctx context.Context, id int64) error
+	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, arg GetAccountByNumberParams) (Account, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	FreezeAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountIncludeDeleted(ctx context.Context, id int64) (Account, error)
+	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	ListAccountsBefore(ctx context.Context, arg ListAccountsBeforeParams) ([]Account, error)
+	ListAccountsIncludeDeleted(ctx context.Context, arg ListAccountsIncludeDeletedParams) ([]Account, error)
+	ListAllAccounts(ctx context.Context, owner string) ([]Account, error)
+	LockAccountBalance(ctx context.Context, id int64) error
+	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	GetAccountAlert(ctx context.Context, accountID int64) (AccountAlert, error)
+	UpsertAccountAlert(ctx context.Context, arg UpsertAccountAlertParams) (AccountAlert, error)
+}
+
+// UserStore reads and writes the users.
+type UserStore interface {
+	AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	GetUser(ctx context.Context, username string) (User, error)
+	GetUserIncludeDeleted(ctx context.Context, username string) (User, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) ([]byte, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	DeleteUserTx(ctx context.Context, username string) (User, error)
+}
+
+// TransferStore reads and writes the transfers and the entries of the ledger.
+type TransferStore interface {
+	CreateEntries(ctx context.Context, arg []CreateEntriesParams) (int64, error)
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferExchange(ctx context.Context, arg CreateTransferExchangeParams) (TransferExchange, error)
+	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferExchange(ctx context.Context, transferID int64) (TransferExchange, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesAfter(ctx context.Context, arg ListEntriesAfterParams) ([]Entry, error)
+	ListStatementEntriesAfter(ctx context.Context, arg ListStatementEntriesAfterParams) ([]Entry, error)
+	ListStatementEntriesBefore(ctx context.Context, arg ListStatementEntriesBeforeParams) ([]Entry, error)
+	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListTransfersAfter(ctx context.Context, arg ListTransfersAfterParams) ([]Transfer, error)
+	ListTransfersBefore(ctx context.Context, arg ListTransfersBeforeParams) ([]Transfer, error)
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
 }
 
+// SessionStore reads and writes the login sessions and the impersonations of the admins.
+type SessionStore interface {
+	BindSessionDevice(ctx context.Context, arg BindSessionDeviceParams) (Session, error)
+	BlockUserSessions(ctx context.Context, username string) error
+	CountActiveSessions(ctx context.Context) (int64, error)
+	CreateImpersonation(ctx context.Context, arg CreateImpersonationParams) (Impersonation, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	GetImpersonation(ctx context.Context, id uuid.UUID) (Impersonation, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	ListRecentSessions(ctx context.Context, arg ListRecentSessionsParams) ([]Session, error)
+	RevokeImpersonation(ctx context.Context, id uuid.UUID) (Impersonation, error)
+	CreateSessionTx(ctx context.Context, arg CreateSessionTxParams) (CreateSessionTxResult, error)
+}
+
+// NotificationStore reads and writes the in-app notifications and the notification preferences.
+type NotificationStore interface {
+	CountUnreadNotifications(ctx context.Context, username string) (int64, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	GetNotificationPreferences(ctx context.Context, username string) (NotificationPreference, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, username string) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error)
+}
+
+// LedgerStore checks the consistency of the ledger.
+type LedgerStore interface {
+	ListBalanceMismatches(ctx context.Context, limit int32) ([]ListBalanceMismatchesRow, error)
+	ListUnbalancedTransfers(ctx context.Context, limit int32) ([]ListUnbalancedTransfersRow, error)
+	VerifyLedger(ctx context.Context) (LedgerReport, error)
+}
+
+// PartitionStore creates the monthly partitions of the entries and transfers.
+type PartitionStore interface {
+	CreateMonthlyPartition(ctx context.Context, arg CreateMonthlyPartitionParams) error
+	CreatePartitions(ctx context.Context, from time.Time, monthsAhead int) error
+}
+
+// RetentionStore moves the ledger entries past their retention to the archive.
+type RetentionStore interface {
+	ArchiveEntries(ctx context.Context, arg ArchiveEntriesParams) (int64, error)
+	ArchiveEntriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DisputeStore reads and writes the disputes of the transfers and the amounts they hold.
+type DisputeStore interface {
+	CreateDispute(ctx context.Context, arg CreateDisputeParams) (Dispute, error)
+	GetDispute(ctx context.Context, id int64) (Dispute, error)
+	GetDisputeForUpdate(ctx context.Context, id int64) (Dispute, error)
+	GetHeldAmount(ctx context.Context, accountID int64) (int64, error)
+	ListDisputes(ctx context.Context, arg ListDisputesParams) ([]Dispute, error)
+	UpdateDisputeStatus(ctx context.Context, arg UpdateDisputeStatusParams) (Dispute, error)
+	OpenDisputeTx(ctx context.Context, arg CreateDisputeParams) (Dispute, error)
+}
+
+// TransferReviewStore reads and writes the transfers the fraud rules held for a review, and the
+// login countries the rules compare a transfer with.
+type TransferReviewStore interface {
+	AddTransferReviewOTPAttempt(ctx context.Context, id int64) (TransferReview, error)
+	CountTransfersSince(ctx context.Context, arg CountTransfersSinceParams) (int64, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
+	GetLastLoginCountry(ctx context.Context, username string) (string, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error)
+	HasTransferBetween(ctx context.Context, arg HasTransferBetweenParams) (bool, error)
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
+	UpdateTransferReviewStatus(ctx context.Context, arg UpdateTransferReviewStatusParams) (TransferReview, error)
+	CloseTransferReviewTx(ctx context.Context, arg CloseTransferReviewTxParams) (CloseTransferReviewTxResult, error)
+	CreateTransferReviewTx(ctx context.Context, arg CreateTransferReviewTxParams) (TransferReview, error)
+}
+
+// ScreeningStore reads and writes the denylist and the users held for matching it.
+type ScreeningStore interface {
+	CreateDenylistEntry(ctx context.Context, arg CreateDenylistEntryParams) (DenylistEntry, error)
+	CreateScreeningHold(ctx context.Context, arg CreateScreeningHoldParams) (ScreeningHold, error)
+	DeleteDenylistEntry(ctx context.Context, id int64) (DenylistEntry, error)
+	GetDenylistEntry(ctx context.Context, id int64) (DenylistEntry, error)
+	GetScreeningHold(ctx context.Context, id int64) (ScreeningHold, error)
+	GetScreeningHoldForUpdate(ctx context.Context, id int64) (ScreeningHold, error)
+	HoldUsersMatchingDenylist(ctx context.Context, arg HoldUsersMatchingDenylistParams) ([]ScreeningHold, error)
+	ListAccountScreeningMatches(ctx context.Context, accountID int64) ([]string, error)
+	ListDenylistEntries(ctx context.Context, arg ListDenylistEntriesParams) ([]DenylistEntry, error)
+	ListScreeningHolds(ctx context.Context, arg ListScreeningHoldsParams) ([]ScreeningHold, error)
+	MatchDenylist(ctx context.Context, arg MatchDenylistParams) ([]DenylistEntry, error)
+	UpdateScreeningHoldStatus(ctx context.Context, arg UpdateScreeningHoldStatusParams) (ScreeningHold, error)
+	AddDenylistEntryTx(ctx context.Context, arg AddDenylistEntryTxParams) (AddDenylistEntryTxResult, error)
+	DeleteDenylistEntryTx(ctx context.Context, arg DeleteDenylistEntryTxParams) (DenylistEntry, error)
+	SetScreeningHoldStatusTx(ctx context.Context, arg SetScreeningHoldStatusTxParams) (ScreeningHold, error)
+}
+
+// CategoryStore reads and writes the categories and tags of the entries and the rules setting
+// them.
+type CategoryStore interface {
+	CategorizeEntry(ctx context.Context, arg CategorizeEntryParams) (int64, error)
+	CreateCategoryRule(ctx context.Context, arg CreateCategoryRuleParams) (CategoryRule, error)
+	DeleteCategoryRule(ctx context.Context, id int64) error
+	GetCategoryRule(ctx context.Context, id int64) (CategoryRule, error)
+	ListCategoryRules(ctx context.Context, owner string) ([]CategoryRule, error)
+	ListEntryCategories(ctx context.Context, entryIds []int64) ([]EntryCategory, error)
+	SetEntryCategory(ctx context.Context, arg SetEntryCategoryParams) (EntryCategory, error)
+}
+
+// AnalyticsStore sums the entries and the transfers of an account over a period.
+type AnalyticsStore interface {
+	SumEntriesByCategory(ctx context.Context, arg SumEntriesByCategoryParams) ([]SumEntriesByCategoryRow, error)
+	SumEntriesByDay(ctx context.Context, arg SumEntriesByDayParams) ([]SumEntriesByDayRow, error)
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
+	SumTransfersByCounterparty(ctx context.Context, arg SumTransfersByCounterpartyParams) ([]SumTransfersByCounterpartyRow, error)
+}
+
+// ExportStore reads the entries of the exports and keeps the exports built by the worker.
+type ExportStore interface {
+	CountExportEntries(ctx context.Context, arg CountExportEntriesParams) (int64, error)
+	CreateEntryExport(ctx context.Context, arg CreateEntryExportParams) (EntryExport, error)
+	CreateEntryExportFile(ctx context.Context, arg CreateEntryExportFileParams) error
+	DeleteExpiredEntryExports(ctx context.Context) (int64, error)
+	FailEntryExport(ctx context.Context, arg FailEntryExportParams) (EntryExport, error)
+	FinishEntryExport(ctx context.Context, arg FinishEntryExportParams) (EntryExport, error)
+	GetEntryExport(ctx context.Context, id int64) (EntryExport, error)
+	GetEntryExportFile(ctx context.Context, exportID int64) ([]byte, error)
+	ListExportEntries(ctx context.Context, arg ListExportEntriesParams) ([]ListExportEntriesRow, error)
+	CreateEntryExportTx(ctx context.Context, arg CreateEntryExportTxParams) (EntryExport, error)
+	FinishEntryExportTx(ctx context.Context, arg FinishEntryExportTxParams) (EntryExport, error)
+}
+
+// ExternalTransferStore reads and writes the transfers to other banks and the suspense accounts
+// holding them until they settle.
+type ExternalTransferStore interface {
+	CompleteExternalTransfer(ctx context.Context, arg CompleteExternalTransferParams) (ExternalTransfer, error)
+	CreateExternalTransfer(ctx context.Context, arg CreateExternalTransferParams) (ExternalTransfer, error)
+	GetExternalTransfer(ctx context.Context, id int64) (ExternalTransfer, error)
+	GetExternalTransferForUpdate(ctx context.Context, id int64) (ExternalTransfer, error)
+	GetSuspenseAccount(ctx context.Context, currency string) (Account, error)
+	ListAccountExternalTransfers(ctx context.Context, arg ListAccountExternalTransfersParams) ([]ExternalTransfer, error)
+	ListExternalTransfersToSettle(ctx context.Context, limit int32) ([]ExternalTransfer, error)
+	SubmitExternalTransfer(ctx context.Context, arg SubmitExternalTransferParams) (ExternalTransfer, error)
+	CompleteExternalTransferTx(ctx context.Context, arg CompleteExternalTransferTxParams) (CompleteExternalTransferTxResult, error)
+	CreateExternalTransferTx(ctx context.Context, arg CreateExternalTransferTxParams) (CreateExternalTransferTxResult, error)
+}
+
+// CardStore reads and writes the virtual cards of the accounts and their authorizations.
+type CardStore interface {
+	CreateCard(ctx context.Context, arg CreateCardParams) (Card, error)
+	CreateCardAuthorization(ctx context.Context, arg CreateCardAuthorizationParams) (CardAuthorization, error)
+	GetCard(ctx context.Context, id int64) (Card, error)
+	GetCardAuthorizationByNetworkTransactionID(ctx context.Context, networkTransactionID string) (CardAuthorization, error)
+	GetCardByFingerprint(ctx context.Context, fingerprint string) (Card, error)
+	GetCardForUpdate(ctx context.Context, id int64) (Card, error)
+	ListAccountCards(ctx context.Context, accountID int64) ([]Card, error)
+	ListCardAuthorizations(ctx context.Context, arg ListCardAuthorizationsParams) ([]CardAuthorization, error)
+	SetCardFrozen(ctx context.Context, arg SetCardFrozenParams) (Card, error)
+	SumCardAuthorizations(ctx context.Context, arg SumCardAuthorizationsParams) (int64, error)
+	UpdateCardLimits(ctx context.Context, arg UpdateCardLimitsParams) (Card, error)
+	AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error)
+}
+
+// AuthorizationHoldStore reads and writes the authorization holds and the balances they hold.
+type AuthorizationHoldStore interface {
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	CloseAuthorizationHold(ctx context.Context, arg CloseAuthorizationHoldParams) (AuthorizationHold, error)
+	CreateAuthorizationHold(ctx context.Context, arg CreateAuthorizationHoldParams) (AuthorizationHold, error)
+	GetAuthorizationHold(ctx context.Context, id int64) (AuthorizationHold, error)
+	GetAuthorizationHoldForUpdate(ctx context.Context, id int64) (AuthorizationHold, error)
+	ListAccountAuthorizationHolds(ctx context.Context, arg ListAccountAuthorizationHoldsParams) ([]AuthorizationHold, error)
+	ListExpiredAuthorizationHolds(ctx context.Context, limit int32) ([]AuthorizationHold, error)
+	CaptureAuthorizationHoldTx(ctx context.Context, arg CaptureAuthorizationHoldTxParams) (CaptureAuthorizationHoldTxResult, error)
+	PlaceAuthorizationHoldTx(ctx context.Context, arg CreateAuthorizationHoldParams) (PlaceAuthorizationHoldTxResult, error)
+	ReleaseAuthorizationHoldTx(ctx context.Context, arg ReleaseAuthorizationHoldTxParams) (AuthorizationHold, error)
+}
+
+// PaymentRequestStore reads and writes the payment requests and pays them.
+type PaymentRequestStore interface {
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	ListAccountPaymentRequests(ctx context.Context, arg ListAccountPaymentRequestsParams) ([]PaymentRequest, error)
+	MarkPaymentRequestPaid(ctx context.Context, arg MarkPaymentRequestPaidParams) (PaymentRequest, error)
+	PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error)
+}
+
+// RefundStore reads and writes the refunds of the transfers and pays them.
+type RefundStore interface {
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	GetRefundedAmount(ctx context.Context, transferID int64) (int64, error)
+	GetReturnedAmount(ctx context.Context, transferID int64) (GetReturnedAmountRow, error)
+	ListTransferRefunds(ctx context.Context, transferID int64) ([]Refund, error)
+	RefundTransferTx(ctx context.Context, arg RefundTransferTxParams) (RefundTransferTxResult, error)
+}
+
+// LoanStore reads and writes the loan offers, the loans and their installments, and moves the
+// money of the loans.
+type LoanStore interface {
+	CreateLoan(ctx context.Context, arg CreateLoanParams) (Loan, error)
+	CreateLoanInstallment(ctx context.Context, arg CreateLoanInstallmentParams) (LoanInstallment, error)
+	CreateLoanOffer(ctx context.Context, arg CreateLoanOfferParams) (LoanOffer, error)
+	GetLendingAccount(ctx context.Context, currency string) (Account, error)
+	GetLoan(ctx context.Context, id int64) (Loan, error)
+	GetLoanForUpdate(ctx context.Context, id int64) (Loan, error)
+	GetLoanInstallmentForUpdate(ctx context.Context, id int64) (LoanInstallment, error)
+	GetLoanOffer(ctx context.Context, id int64) (LoanOffer, error)
+	GetLoanStanding(ctx context.Context, loanID int64) (GetLoanStandingRow, error)
+	ListAccountLoans(ctx context.Context, arg ListAccountLoansParams) ([]Loan, error)
+	ListDelinquentLoans(ctx context.Context, arg ListDelinquentLoansParams) ([]ListDelinquentLoansRow, error)
+	ListDueLoanInstallments(ctx context.Context, limit int32) ([]LoanInstallment, error)
+	ListLoanInstallments(ctx context.Context, loanID int64) ([]LoanInstallment, error)
+	ListLoanOffers(ctx context.Context, activeOnly bool) ([]LoanOffer, error)
+	MarkLoanDelinquent(ctx context.Context, id int64) (Loan, error)
+	MarkLoanInstallmentPaid(ctx context.Context, arg MarkLoanInstallmentPaidParams) (LoanInstallment, error)
+	SetLoanOfferActive(ctx context.Context, arg SetLoanOfferActiveParams) (LoanOffer, error)
+	SetLoanStatus(ctx context.Context, arg SetLoanStatusParams) (Loan, error)
+	CreateLoanOfferTx(ctx context.Context, arg CreateLoanOfferTxParams) (LoanOffer, error)
+	DisburseLoanTx(ctx context.Context, arg DisburseLoanTxParams) (DisburseLoanTxResult, error)
+	MarkLoanDelinquentTx(ctx context.Context, arg MarkLoanDelinquentTxParams) (Loan, error)
+	RepayLoanInstallmentTx(ctx context.Context, arg RepayLoanInstallmentTxParams) (RepayLoanInstallmentTxResult, error)
+	SetLoanOfferActiveTx(ctx context.Context, arg SetLoanOfferActiveTxParams) (LoanOffer, error)
+}
+
+// RewardStore reads and writes the ledger of the cashback points, and pays out the points redeemed.
+type RewardStore interface {
+	CreateRewardEntry(ctx context.Context, arg CreateRewardEntryParams) (RewardEntry, error)
+	GetRewardBalance(ctx context.Context, accountID int64) (int64, error)
+	GetRewardsAccount(ctx context.Context, currency string) (Account, error)
+	ListRewardEntries(ctx context.Context, arg ListRewardEntriesParams) ([]RewardEntry, error)
+	RedeemRewardsTx(ctx context.Context, arg RedeemRewardsTxParams) (RedeemRewardsTxResult, error)
+}
+
+// ReferralStore reads and writes the referral codes and the referrals they made, and pays out the
+// bonuses of the referrals that qualified.
+type ReferralStore interface {
+	CloseReferral(ctx context.Context, arg CloseReferralParams) (Referral, error)
+	CountRewardedReferrals(ctx context.Context, referrer string) (int64, error)
+	CreateReferral(ctx context.Context, arg CreateReferralParams) (Referral, error)
+	CreateReferralCode(ctx context.Context, arg CreateReferralCodeParams) (ReferralCode, error)
+	GetFirstQualifyingTransfer(ctx context.Context, arg GetFirstQualifyingTransferParams) (Transfer, error)
+	GetReferralCode(ctx context.Context, username string) (ReferralCode, error)
+	GetReferralCodeByCode(ctx context.Context, code string) (ReferralCode, error)
+	GetReferralForUpdate(ctx context.Context, id int64) (Referral, error)
+	ListPendingReferrals(ctx context.Context, arg ListPendingReferralsParams) ([]Referral, error)
+	ListReferrals(ctx context.Context, arg ListReferralsParams) ([]Referral, error)
+	RewardReferralTx(ctx context.Context, arg RewardReferralTxParams) (RewardReferralTxResult, error)
+}
+
+// TenantStore reads and writes the tenants sharing the bank.
+type TenantStore interface {
+	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
+	GetTenant(ctx context.Context, id string) (Tenant, error)
+	ListTenants(ctx context.Context) ([]Tenant, error)
+	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error)
+	CreateTenantTx(ctx context.Context, arg CreateTenantTxParams) (Tenant, error)
+	UpdateTenantTx(ctx context.Context, arg UpdateTenantTxParams) (Tenant, error)
+}
+
+// CurrencyStore reads the currencies of the registry and lets the admins enable them.
+type CurrencyStore interface {
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	SetCurrencyEnabled(ctx context.Context, arg SetCurrencyEnabledParams) (Currency, error)
+	SetCurrencyEnabledTx(ctx context.Context, arg SetCurrencyEnabledTxParams) (Currency, error)
+}
+
+// ExchangeRateStore reads and writes the exchange rates of the FX provider, and the fx accounts
+// converting the transfers between currencies.
+type ExchangeRateStore interface {
+	GetFXAccount(ctx context.Context, currency string) (Account, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	SaveExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) ([]ExchangeRate, error)
+}
+
+// UserImportStore reads and writes the imports of the users of a legacy system and the report of
+// their rows.
+type UserImportStore interface {
+	CreateUserImport(ctx context.Context, arg CreateUserImportParams) (UserImport, error)
+	CreateUserImportFile(ctx context.Context, arg CreateUserImportFileParams) error
+	CreateUserImportRow(ctx context.Context, arg CreateUserImportRowParams) (UserImportRow, error)
+	FailUserImport(ctx context.Context, arg FailUserImportParams) (UserImport, error)
+	FinishUserImport(ctx context.Context, id int64) (UserImport, error)
+	GetLastUserImportLine(ctx context.Context, importID int64) (int64, error)
+	GetUserImport(ctx context.Context, id int64) (UserImport, error)
+	GetUserImportFile(ctx context.Context, importID int64) ([]byte, error)
+	ListUserImportRowsAfter(ctx context.Context, arg ListUserImportRowsAfterParams) ([]UserImportRow, error)
+	ListUserImportRowsBefore(ctx context.Context, arg ListUserImportRowsBeforeParams) ([]UserImportRow, error)
+	CreateUserImportTx(ctx context.Context, arg CreateUserImportTxParams) (UserImport, error)
+	ImportUserTx(ctx context.Context, arg ImportUserTxParams) (UserImportRow, error)
+}
+
+// ConsentStore reads and writes the versions of the legal documents and the consents of the users
+// to them.
+type ConsentStore interface {
+	CreateLegalDocument(ctx context.Context, arg CreateLegalDocumentParams) (LegalDocument, error)
+	CreateUserConsent(ctx context.Context, arg CreateUserConsentParams) (UserConsent, error)
+	ListLatestLegalDocuments(ctx context.Context) ([]LegalDocument, error)
+	ListPendingLegalDocuments(ctx context.Context, username string) ([]LegalDocument, error)
+	ListUserConsents(ctx context.Context, username string) ([]UserConsent, error)
+	CreateLegalDocumentTx(ctx context.Context, arg CreateLegalDocumentTxParams) (LegalDocument, error)
+}
+
+// DeviceStore reads and writes the devices the users log in from.
+type DeviceStore interface {
+	AddDeviceVerificationAttempt(ctx context.Context, id int64) (Device, error)
+	BlockDeviceSessions(ctx context.Context, deviceID pgtype.Int8) error
+	GetDevice(ctx context.Context, id int64) (Device, error)
+	ListDevices(ctx context.Context, username string) ([]Device, error)
+	RenameDevice(ctx context.Context, arg RenameDeviceParams) (Device, error)
+	RevokeDevice(ctx context.Context, arg RevokeDeviceParams) (Device, error)
+	SetDeviceVerification(ctx context.Context, arg SetDeviceVerificationParams) (Device, error)
+	TrustDevice(ctx context.Context, id int64) (Device, error)
+	UpsertDevice(ctx context.Context, arg UpsertDeviceParams) (Device, error)
+	SendDeviceVerificationTx(ctx context.Context, arg SendDeviceVerificationTxParams) (Device, error)
+	RevokeDeviceTx(ctx context.Context, arg RevokeDeviceParams) (Device, error)
+}
+
+// LoginAlertStore reads and writes the suspicious logins and the password resets they lead to.
+type LoginAlertStore interface {
+	AddLoginAlertResetAttempt(ctx context.Context, id int64) (LoginAlert, error)
+	CreateLoginAlert(ctx context.Context, arg CreateLoginAlertParams) (LoginAlert, error)
+	FinishLoginAlertReset(ctx context.Context, id int64) (LoginAlert, error)
+	GetLoginAlert(ctx context.Context, id int64) (LoginAlert, error)
+	HasPendingPasswordReset(ctx context.Context, username string) (bool, error)
+	ReportLoginAlert(ctx context.Context, arg ReportLoginAlertParams) (LoginAlert, error)
+	SetLoginAlertResetCode(ctx context.Context, arg SetLoginAlertResetCodeParams) (LoginAlert, error)
+	ReportLoginAlertTx(ctx context.Context, arg ReportLoginAlertTxParams) (LoginAlert, error)
+	SendPasswordResetCodeTx(ctx context.Context, arg SendPasswordResetCodeTxParams) (LoginAlert, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (User, error)
+}
+
+// StepUpStore reads and writes the one-time codes of the multi-factor step-ups.
+type StepUpStore interface {
+	AddStepUpChallengeAttempt(ctx context.Context, id int64) (StepUpChallenge, error)
+	CompleteStepUpChallenge(ctx context.Context, id int64) (StepUpChallenge, error)
+	CreateStepUpChallenge(ctx context.Context, arg CreateStepUpChallengeParams) (StepUpChallenge, error)
+	GetStepUpChallenge(ctx context.Context, id int64) (StepUpChallenge, error)
+	CreateStepUpChallengeTx(ctx context.Context, arg CreateStepUpChallengeTxParams) (StepUpChallenge, error)
+}
+
+// MaintenanceStore reads and writes the maintenance mode shared by every instance of the bank.
+type MaintenanceStore interface {
+	GetMaintenanceMode(ctx context.Context) (MaintenanceMode, error)
+	SetMaintenanceReadOnly(ctx context.Context, readOnly bool) (MaintenanceMode, error)
+	SetMaintenanceTransfersBlocked(ctx context.Context, transfersBlocked bool) (MaintenanceMode, error)
+}
+
+// AuditStore runs the operations of the admins, each recorded by an audit entry, and keeps the
+// hash chain of the audit logs of the writes to the api.
+type AuditStore interface {
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditEntry, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	GetLastAuditLogHash(ctx context.Context) ([]byte, error)
+	ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditEntry, error)
+	ListAuditLogsAfter(ctx context.Context, arg ListAuditLogsAfterParams) ([]AuditLog, error)
+	ListAuditLogsBefore(ctx context.Context, arg ListAuditLogsBeforeParams) ([]AuditLog, error)
+	LockAuditLogChain(ctx context.Context) error
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	CreateAuditLogTx(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateImpersonationTx(ctx context.Context, arg CreateImpersonationTxParams) (Impersonation, error)
+	CreateOutboxTasksTx(ctx context.Context, arg CreateOutboxTasksTxParams) error
+	FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (Account, error)
+	RevokeImpersonationTx(ctx context.Context, arg RevokeImpersonationTxParams) (Impersonation, error)
+	RevokeSessionsTx(ctx context.Context, arg RevokeSessionsTxParams) error
+	SetDisputeStatusTx(ctx context.Context, arg SetDisputeStatusTxParams) (SetDisputeStatusTxResult, error)
+	VerifyAuditLogChain(ctx context.Context) (AuditChainReport, error)
+}
+
+// OutboxStore writes and publishes the outbox tasks and events.
+type OutboxStore interface {
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (EventOutbox, error)
+	CreateOutboxTask(ctx context.Context, arg CreateOutboxTaskParams) (Outbox, error)
+	ListPendingOutboxEvents(ctx context.Context, limit int32) ([]EventOutbox, error)
+	ListPendingOutboxTasks(ctx context.Context, limit int32) ([]Outbox, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkOutboxTaskPublished(ctx context.Context, id int64) error
+	PublishOutboxTx(ctx context.Context, limit int32, publish func(task Outbox) error) (int, error)
+	PublishOutboxEventsTx(ctx context.Context, limit int32, publish func(events []EventOutbox) error) (int, error)
+}
+
 // Store provides all functions to execute SQL queries and transactions
 type SQLStore struct {
-	db *sql.DB
+	connPool    *pgxpool.Pool
+	replica     *Replica
+	breaker     *Breaker
+	balanceLock BalanceLock
 	*Queries
 }
 
+// StoreOptions are the optional parts of a store, the zero value being a store on the primary alone.
+type StoreOptions struct {
+	// Replica serves some reads, see Replica. Transactions and writes always go to the primary.
+	Replica *Replica
+	// Breaker fails the queries to the primary fast while it is down, see Breaker.
+	Breaker *Breaker
+	// BalanceLock is how TransferTx serializes the updates of an account balance, BalanceLockRow when empty.
+	BalanceLock BalanceLock
+}
+
 // NewStore creates a new store
-func NewStore(db *sql.DB) Store {
+func NewStore(connPool *pgxpool.Pool) Store {
+	return NewStoreWithOptions(connPool, StoreOptions{})
+}
+
+// NewStoreWithReplica creates a store sending some reads to replica, see Replica. Transactions
+// and writes always go to the primary connPool.
+func NewStoreWithReplica(connPool *pgxpool.Pool, replica *Replica) Store {
+	return NewStoreWithOptions(connPool, StoreOptions{Replica: replica})
+}
+
+// NewStoreWithOptions creates a store on the primary connPool with options.
+func NewStoreWithOptions(connPool *pgxpool.Pool, options StoreOptions) Store {
+	balanceLock := options.BalanceLock
+	if balanceLock == "" {
+		balanceLock = BalanceLockRow
+	}
 	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+		connPool:    connPool,
+		replica:     options.Replica,
+		breaker:     options.Breaker,
+		balanceLock: balanceLock,
+		Queries:     New(newInstrumentedDB(newBreakerDB(connPool, options.Breaker))),
 	}
 }
 
-// ExecTx executes a function within a database transaction
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+// Ping checks that the database can still be reached.
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.connPool.Ping(ctx)
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate, and whether
+// the last migration failed halfway.
+func (store *SQLStore) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	err = store.connPool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	return
+}
+
+const (
+	// maxTxRetries is how many times a transaction is retried after a serialization failure or a deadlock.
+	maxTxRetries = 3
+	txRetryDelay = 20 * time.Millisecond
+)
+
+// ExecTx executes a function within a database transaction.
+// The transaction gets its own span named after name, parent of the spans of its queries.
+// A transaction that fails with a serialization failure or a deadlock is run again from the
+// start, so fn must not keep state from an attempt to the next.
+func (store *SQLStore) execTx(ctx context.Context, name string, fn func(context.Context, *Queries) error) (err error) {
+	ctx, span := tracer.Start(ctx, name)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	for attempt := 1; ; attempt++ {
+		err = store.runTx(ctx, fn)
+		code := ErrorCode(err)
+		if !isRetryable(code) || attempt > maxTxRetries {
+			return err
+		}
+
+		metrics.ObserveDBTxRetry(name, code)
+		span.AddEvent("retry", trace.WithAttributes(attribute.String("db.error_code", code)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+}
+
+func (store *SQLStore) runTx(ctx context.Context, fn func(context.Context, *Queries) error) error {
+	if err := store.breaker.allow(); err != nil {
+		return err
+	}
+	tx, err := store.connPool.Begin(ctx)
+	store.breaker.record(ctx, err)
 	if err != nil {
 		return err
 	}
 
-	q := New(tx)
-	err = fn(q)
+	q := New(newInstrumentedDB(newBreakerDB(tx, store.breaker)))
+	err = fn(ctx, q)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
 		}
 		return err
 	}
 
-	return tx.Commit()
+	return tx.Commit(ctx)
+}
+
+func isRetryable(code string) bool {
+	return code == SerializationFailure || code == DeadlockDetected
+}
+
+// retryDelay doubles with every attempt, with a jitter so that the transactions that
+// conflicted don't all run into each other again.
+func retryDelay(attempt int) time.Duration {
+	delay := txRetryDelay << (attempt - 1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
 }
 
 type TransferTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Memo          string `json:"memo"`
+	// OutboxTasks are written in the same transaction as the transfer, see createOutboxTasks.
+	OutboxTasks []CreateOutboxTaskParams `json:"-"`
+	// AlertTask, when set, adds an outbox task for every alert rule of the debited account
+	// the transfer sets off, so that the alerts see the exact balance the transfer left.
+	AlertTask AlertTaskFunc `json:"-"`
+	// RewardTask, when set, adds the outbox task accruing the cashback points of the transfer,
+	// which the worker works out after the commit.
+	RewardTask RewardTaskFunc `json:"-"`
+	// Exchange, when set, converts the transfer to the currency of the payee, see exchangeMoney.
+	Exchange *Exchange `json:"-"`
 }
 
 type TransferTxResult struct {
@@ -56,50 +635,155 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// ExchangeTransfer is the transfer paying the payee of a transfer with an exchange.
+	ExchangeTransfer *Transfer `json:"exchange_transfer,omitempty"`
 }
 
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execTx(ctx, "TransferTx", func(ctx context.Context, q *Queries) error {
 		var err error
+		result, err = store.transfer(ctx, q, arg)
+		return err
+	})
+	return result, err
+}
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+// transfer runs a transfer of TransferTx in the transaction of q.
+func (store *SQLStore) transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+	if arg.Exchange != nil {
+		result, err = store.exchangeMoney(ctx, q, arg)
+	} else {
+		result, err = store.moveMoney(ctx, q, CreateTransferParams{
 			FromAccountID: arg.FromAccountID,
 			ToAccountID:   arg.ToAccountID,
 			Amount:        arg.Amount,
+			Memo:          arg.Memo,
 		})
+	}
+	if err != nil {
+		return result, err
+	}
+	// checked once the balances are updated, when the rows are locked until the commit
+	if result.FromAccount.FrozenAt.Valid || result.ToAccount.FrozenAt.Valid {
+		return result, ErrAccountFrozen
+	}
+	if err := checkHeldAmount(ctx, q, result.FromAccount); err != nil {
+		return result, err
+	}
+	if err := checkTransferLimit(ctx, q, result.FromAccount, arg.Amount); err != nil {
+		return result, err
+	}
 
+	tasks := arg.OutboxTasks
+	if arg.AlertTask != nil {
+		alerts, err := checkAlerts(ctx, q, result.FromAccount, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
 		}
-
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-
-		if err != nil {
-			return err
+		for _, alert := range alerts {
+			task, err := arg.AlertTask(alert)
+			if err != nil {
+				return result, err
+			}
+			tasks = append(tasks, task)
 		}
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	}
+	if arg.RewardTask != nil {
+		task, err := arg.RewardTask(result.Transfer)
 		if err != nil {
-			return err
+			return result, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := categorizeTransfer(ctx, q, result); err != nil {
+		return result, err
+	}
+	if err := createTransferCompletedEvent(ctx, q, result.Transfer, result.FromAccount.Currency); err != nil {
+		return result, err
+	}
+	if result.ExchangeTransfer != nil {
+		if err := createTransferCompletedEvent(ctx, q, *result.ExchangeTransfer, result.ToAccount.Currency); err != nil {
+			return result, err
 		}
+	}
+	return result, createOutboxTasks(ctx, q, tasks)
+}
+
+// moveMoney writes the transfer with its two entries and updates both balances, locking them in
+// the order of the account ids.
+func (store *SQLStore) moveMoney(ctx context.Context, q *Queries, arg CreateTransferParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
 
-		// TO DO update account balance
-		if arg.FromAccountID <= arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	if err := checkTransfersBlocked(ctx, q); err != nil {
+		return result, err
+	}
+	if store.balanceLock == BalanceLockAdvisory {
+		if err := lockBalances(ctx, q, arg.FromAccountID, arg.ToAccountID); err != nil {
+			return result, err
 		}
+	}
 
-		return nil
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    -arg.Amount,
 	})
-	return result, err
+	if err != nil {
+		return result, err
+	}
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if arg.FromAccountID <= arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, checkTenants(result.FromAccount, result.ToAccount)
+}
+
+// checkTransfersBlocked refuses to move money while the maintenance mode shared by the instances
+// blocks the transfers or is read-only. Every transfer goes through moveMoney, so the ones of the
+// worker, e.g. the loan installments or the referral bonuses, stop with those of the apis.
+func checkTransfersBlocked(ctx context.Context, q *Queries) error {
+	mode, err := q.GetMaintenanceMode(ctx)
+	if err != nil {
+		return err
+	}
+	if mode.TransfersBlocked || mode.ReadOnly {
+		return ErrTransfersBlocked
+	}
+	return nil
+}
+
+// createOutboxTasks stores the tasks to enqueue once the transaction commits. Enqueueing them
+// right after the commit instead would lose them if the server crashed in between, so the
+// outbox relay of the worker package publishes them from the table.
+func createOutboxTasks(ctx context.Context, q *Queries, tasks []CreateOutboxTaskParams) error {
+	for _, task := range tasks {
+		if _, err := q.CreateOutboxTask(ctx, task); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func addMoney(
@@ -129,3 +813,247 @@ func addMoney(
 	}
 	return
 }
+
+type CreateUserTxParams struct {
+	CreateUserParams
+	OutboxTasks []CreateOutboxTaskParams
+	// AfterCreate, when set, runs in the transaction once the user is created and returns more
+	// outbox tasks built from it. An error rolls the user back, so that no user is left without
+	// e.g. its verification email.
+	AfterCreate func(user User) ([]CreateOutboxTaskParams, error)
+	// Role, when set, replaces the depositor role users are created with.
+	Role string
+	// Audit, when set, records the user as created by an admin.
+	Audit *CreateAuditEntryParams
+	// Screen, when set, screens the user once created. A match holds it for a review rather than
+	// failing the signup, so that a namesake can still be cleared.
+	Screen ScreenFunc
+	// ReferralCode, when set, is the code of the user who referred the new one.
+	ReferralCode string
+}
+
+type CreateUserTxResult struct {
+	User User
+	// Hold is the screening hold of a user who matched the denylist.
+	Hold *ScreeningHold
+	// Referral is the pending referral of a user who signed up with a referral code.
+	Referral *Referral
+}
+
+// CreateUserTx creates the user along with its outbox tasks, e.g. the verification email, and its user.created event.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, "CreateUserTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		result, err = createUserTx(ctx, q, arg)
+		return err
+	})
+	return result, err
+}
+
+// createUserTx runs CreateUserTx in the transaction of q.
+func createUserTx(ctx context.Context, q *Queries, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+	var err error
+
+	result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+	if err != nil {
+		return result, err
+	}
+	if arg.Role != "" {
+		result.User, err = q.UpdateUserRole(ctx, UpdateUserRoleParams{
+			Username: result.User.Username,
+			Role:     arg.Role,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if err := createUserCreatedEvent(ctx, q, result.User); err != nil {
+		return result, err
+	}
+	if arg.ReferralCode != "" {
+		result.Referral, err = referUser(ctx, q, arg.ReferralCode, result.User)
+		if err != nil {
+			return result, err
+		}
+	}
+	if arg.Screen != nil {
+		result.Hold, err = screenUser(ctx, q, arg.Screen, result.User)
+		if err != nil {
+			return result, err
+		}
+	}
+	if arg.Audit != nil {
+		if _, err := q.CreateAuditEntry(ctx, *arg.Audit); err != nil {
+			return result, err
+		}
+	}
+
+	tasks := arg.OutboxTasks
+	if arg.AfterCreate != nil {
+		more, err := arg.AfterCreate(result.User)
+		if err != nil {
+			return result, err
+		}
+		tasks = append(tasks, more...)
+	}
+	return result, createOutboxTasks(ctx, q, tasks)
+}
+
+type CreateSessionTxParams struct {
+	CreateSessionParams
+	// Device, when its fingerprint is set, is the device of the login, which the session is
+	// bound to.
+	Device UpsertDeviceParams
+	// Alert, when set, records the login as suspicious, its SessionID being that of the session.
+	Alert *CreateLoginAlertParams
+	// AfterAlert returns the outbox tasks of the alert once created, e.g. the security
+	// notification with the link reporting it.
+	AfterAlert  func(alert LoginAlert) ([]CreateOutboxTaskParams, error)
+	OutboxTasks []CreateOutboxTaskParams
+}
+
+type CreateSessionTxResult struct {
+	Session Session
+	// Device is the device the session is bound to, nil without a fingerprint.
+	Device *Device
+	// Alert is the alert of a suspicious login.
+	Alert *LoginAlert
+}
+
+// CreateSessionTx creates the session of a login along with its outbox tasks, e.g. the new login notification.
+// A login from a device registers it, or marks it seen when it is known. A suspicious login is
+// recorded as an alert of the session.
+func (store *SQLStore) CreateSessionTx(ctx context.Context, arg CreateSessionTxParams) (CreateSessionTxResult, error) {
+	var result CreateSessionTxResult
+
+	err := store.execTx(ctx, "CreateSessionTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		session := arg.CreateSessionParams
+		result.Device = nil
+		if arg.Device.Fingerprint != "" {
+			device, err := q.UpsertDevice(ctx, arg.Device)
+			if err != nil {
+				return err
+			}
+			result.Device = &device
+			session.DeviceID = pgtype.Int8{Int64: device.ID, Valid: true}
+		}
+
+		result.Session, err = q.CreateSession(ctx, session)
+		if err != nil {
+			return err
+		}
+
+		result.Alert = nil
+		if arg.Alert != nil {
+			params := *arg.Alert
+			params.SessionID = result.Session.ID
+			alert, err := q.CreateLoginAlert(ctx, params)
+			if err != nil {
+				return err
+			}
+			result.Alert = &alert
+			if arg.AfterAlert != nil {
+				tasks, err := arg.AfterAlert(alert)
+				if err != nil {
+					return err
+				}
+				if err := createOutboxTasks(ctx, q, tasks); err != nil {
+					return err
+				}
+			}
+		}
+
+		return createOutboxTasks(ctx, q, arg.OutboxTasks)
+	})
+	return result, err
+}
+
+// PublishOutboxTx hands up to limit pending outbox tasks to publish, oldest first, and marks
+// them as published. The rows stay locked until the transaction ends, so that concurrent relays
+// skip them instead of publishing them twice. It returns how many tasks were published.
+func (store *SQLStore) PublishOutboxTx(ctx context.Context, limit int32, publish func(task Outbox) error) (int, error) {
+	published := 0
+
+	err := store.execTx(ctx, "PublishOutboxTx", func(ctx context.Context, q *Queries) error {
+		published = 0
+		tasks, err := q.ListPendingOutboxTasks(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			if err := publish(task); err != nil {
+				return err
+			}
+			if err := q.MarkOutboxTaskPublished(ctx, task.ID); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// PublishOutboxEventsTx hands up to limit pending events to publish in one batch, oldest first,
+// and marks them as published, locking the rows like PublishOutboxTx does. It returns how many
+// events were published.
+func (store *SQLStore) PublishOutboxEventsTx(ctx context.Context, limit int32, publish func(events []EventOutbox) error) (int, error) {
+	published := 0
+
+	err := store.execTx(ctx, "PublishOutboxEventsTx", func(ctx context.Context, q *Queries) error {
+		events, err := q.ListPendingOutboxEvents(ctx, limit)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := publish(events); err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := q.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+		published = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// DeleteUserTx anonymizes the user's personal data, marks it deleted and blocks all of its sessions.
+// The row itself is kept since accounts and the ledger still reference the username.
+func (store *SQLStore) DeleteUserTx(ctx context.Context, username string) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, "DeleteUserTx", func(ctx context.Context, q *Queries) error {
+		var err error
+
+		user, err = q.AnonymizeUser(ctx, AnonymizeUserParams{
+			FullName: "deleted user",
+			Email:    fmt.Sprintf("deleted-%s@anonymized.invalid", username),
+			Username: username,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, username)
+	})
+	return user, err
+}
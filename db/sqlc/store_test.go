@@ -2,9 +2,18 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/backendmaster/simple_bank/events"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,3 +165,285 @@ func TestTransferDeadLock(t *testing.T) {
 	require.Equal(t, account1.Balance, updateAccount1.Balance)
 	require.Equal(t, account2.Balance, updateAccount2.Balance)
 }
+
+func TestCreateUserTxWithOutbox(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashedPassword(util.RandomString(6))
+	require.NoError(t, err)
+	username := util.RandomOwnerName()
+	task := CreateOutboxTaskParams{
+		TaskType: "task:test_" + username,
+		Payload:  json.RawMessage(fmt.Sprintf(`{"username":%q}`, username)),
+		Queue:    "critical",
+		MaxRetry: 10,
+	}
+
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       username,
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwnerName(),
+			Email:          util.RandomEmail(),
+		},
+		OutboxTasks: []CreateOutboxTaskParams{task},
+	})
+	require.NoError(t, err)
+	require.Equal(t, username, result.User.Username)
+
+	var published []Outbox
+	_, err = store.PublishOutboxTx(context.Background(), 1000, func(outbox Outbox) error {
+		published = append(published, outbox)
+		return nil
+	})
+	require.NoError(t, err)
+
+	found := false
+	for _, outbox := range published {
+		if outbox.TaskType == task.TaskType {
+			found = true
+			require.JSONEq(t, string(task.Payload), string(outbox.Payload))
+			require.Equal(t, task.Queue, outbox.Queue)
+		}
+	}
+	require.True(t, found)
+
+	// published tasks are not handed out again
+	_, err = store.PublishOutboxTx(context.Background(), 1000, func(outbox Outbox) error {
+		require.NotEqual(t, task.TaskType, outbox.TaskType)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateUserTxEvent(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashedPassword(util.RandomString(6))
+	require.NoError(t, err)
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwnerName(),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwnerName(),
+			Email:          util.RandomEmail(),
+		},
+	})
+	require.NoError(t, err)
+
+	var found *EventOutbox
+	_, err = store.PublishOutboxEventsTx(context.Background(), 1000, func(rows []EventOutbox) error {
+		for i := range rows {
+			if rows[i].EventType == events.TypeUserCreated && rows[i].EventKey == result.User.Username {
+				found = &rows[i]
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	var payload events.UserCreated
+	require.NoError(t, json.Unmarshal(found.Payload, &payload))
+	require.Equal(t, result.User.Username, payload.Username)
+	require.Equal(t, result.User.Role, payload.Role)
+
+	// published events are not handed out again
+	_, err = store.PublishOutboxEventsTx(context.Background(), 1000, func(rows []EventOutbox) error {
+		for _, row := range rows {
+			require.NotEqual(t, found.ID, row.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateUserTxAfterCreate(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashedPassword(util.RandomString(6))
+	require.NoError(t, err)
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwnerName(),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwnerName(),
+			Email:          util.RandomEmail(),
+		},
+	}
+
+	// a failing callback rolls the user back
+	callbackErr := errors.New("callback failed")
+	arg.AfterCreate = func(user User) ([]CreateOutboxTaskParams, error) {
+		require.Equal(t, arg.Username, user.Username)
+		return nil, callbackErr
+	}
+	_, err = store.CreateUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, callbackErr)
+	_, err = store.GetUser(context.Background(), arg.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	task := CreateOutboxTaskParams{
+		TaskType: "task:test_" + arg.Username,
+		Payload:  json.RawMessage(fmt.Sprintf(`{"username":%q}`, arg.Username)),
+		Queue:    "critical",
+		MaxRetry: 10,
+	}
+	arg.AfterCreate = func(user User) ([]CreateOutboxTaskParams, error) {
+		return []CreateOutboxTaskParams{task}, nil
+	}
+	result, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+
+	found := false
+	_, err = store.PublishOutboxTx(context.Background(), 1000, func(outbox Outbox) error {
+		if outbox.TaskType == task.TaskType {
+			found = true
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestTransferTxAlerts(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t, testfixtures.WithBalance(1000))
+	account2 := createRandomAccount(t)
+
+	// one transfer of amount crosses the low balance threshold, and is a large debit
+	amount := int64(10)
+	_, err := testQuires.UpsertAccountAlert(context.Background(), UpsertAccountAlertParams{
+		AccountID:           account1.ID,
+		LowBalanceThreshold: pgtype.Int8{Int64: account1.Balance - amount/2, Valid: true},
+		LargeDebitThreshold: pgtype.Int8{Int64: amount - 1, Valid: true},
+	})
+	require.NoError(t, err)
+
+	transfer := func() []TriggeredAlert {
+		var alerts []TriggeredAlert
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+			AlertTask: func(alert TriggeredAlert) (CreateOutboxTaskParams, error) {
+				alerts = append(alerts, alert)
+				return CreateOutboxTaskParams{
+					TaskType: "task:test_alert",
+					Payload:  json.RawMessage(`{}`),
+					Queue:    "default",
+					MaxRetry: 1,
+				}, nil
+			},
+		})
+		require.NoError(t, err)
+		return alerts
+	}
+
+	alerts := transfer()
+	require.Len(t, alerts, 2)
+	require.Equal(t, AlertLowBalance, alerts[0].Rule)
+	require.Equal(t, account1.Balance-amount, alerts[0].Account.Balance)
+	require.Equal(t, AlertLargeTransaction, alerts[1].Rule)
+
+	// the balance is already below the threshold, so only the large debit goes off again
+	alerts = transfer()
+	require.Len(t, alerts, 1)
+	require.Equal(t, AlertLargeTransaction, alerts[0].Rule)
+
+	// accounts without rules set off nothing
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        amount,
+		AlertTask: func(alert TriggeredAlert) (CreateOutboxTaskParams, error) {
+			t.Fatalf("unexpected alert %v", alert)
+			return CreateOutboxTaskParams{}, nil
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestExecTxRetry(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+
+	attempts := 0
+	err := store.execTx(context.Background(), "TestExecTxRetry", func(ctx context.Context, q *Queries) error {
+		attempts++
+		if attempts == 1 {
+			return &pgconn.PgError{Code: SerializationFailure}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	// gives up after maxTxRetries, and doesn't retry other errors
+	attempts = 0
+	err = store.execTx(context.Background(), "TestExecTxRetry", func(ctx context.Context, q *Queries) error {
+		attempts++
+		return &pgconn.PgError{Code: DeadlockDetected}
+	})
+	require.Equal(t, DeadlockDetected, ErrorCode(err))
+	require.Equal(t, maxTxRetries+1, attempts)
+
+	attempts = 0
+	err = store.execTx(context.Background(), "TestExecTxRetry", func(ctx context.Context, q *Queries) error {
+		attempts++
+		return &pgconn.PgError{Code: UniqueViolation}
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestExecTxRetryDeadlock(t *testing.T) {
+	store := NewStore(testDB).(*SQLStore)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// both transactions lock one account, wait for the other to have locked its own, then
+	// lock the other account: postgres aborts one of them, which execTx runs again
+	var locked sync.WaitGroup
+	locked.Add(2)
+	var attempts int32
+	move := func(fromID, toID int64) error {
+		first := true
+		return store.execTx(context.Background(), "TestExecTxRetryDeadlock", func(ctx context.Context, q *Queries) error {
+			atomic.AddInt32(&attempts, 1)
+			if _, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: fromID, Amount: -1}); err != nil {
+				return err
+			}
+			if first {
+				first = false
+				locked.Done()
+				locked.Wait()
+			}
+			_, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: toID, Amount: 1})
+			return err
+		})
+	}
+
+	errs := make(chan error)
+	go func() { errs <- move(account1.ID, account2.ID) }()
+	go func() { errs <- move(account2.ID, account1.ID) }()
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt := 1; attempt <= maxTxRetries; attempt++ {
+		delay := retryDelay(attempt)
+		max := txRetryDelay << (attempt - 1)
+		require.GreaterOrEqual(t, delay, max/2)
+		require.Less(t, delay, max)
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// BankOwnerPrefix starts the usernames of the users owning the accounts of the bank itself, e.g.
+// SuspenseOwner. Their accounts belong to the default tenant but pay and are paid by every tenant.
+const BankOwnerPrefix = "bank."
+
+var (
+	// ErrCrossTenant is returned for money moved between accounts of different tenants.
+	ErrCrossTenant = errors.New("accounts belong to different tenants")
+	// ErrTransferLimitExceeded is returned for a transfer over the cap of the tenant of its payer.
+	ErrTransferLimitExceeded = errors.New("transfer amount exceeds the limit of the tenant")
+)
+
+// IsBankOwner tells whether owner is one of the users owning the accounts of the bank.
+func IsBankOwner(owner string) bool {
+	return strings.HasPrefix(owner, BankOwnerPrefix)
+}
+
+// OffersCurrency tells whether the users of the tenant can open accounts in currency.
+func (tenant Tenant) OffersCurrency(currency string) bool {
+	for _, offered := range tenant.Currencies {
+		if offered == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTenants returns ErrCrossTenant unless both accounts belong to the same tenant, or one of
+// them to the bank.
+func checkTenants(from, to Account) error {
+	if from.TenantID == to.TenantID || IsBankOwner(from.Owner) || IsBankOwner(to.Owner) {
+		return nil
+	}
+	return ErrCrossTenant
+}
+
+// checkTransferLimit returns ErrTransferLimitExceeded when amount is over the cap of the tenant
+// of the payer account. The bank pays whatever it owes.
+func checkTransferLimit(ctx context.Context, q *Queries, from Account, amount int64) error {
+	if IsBankOwner(from.Owner) {
+		return nil
+	}
+	tenant, err := q.GetTenant(ctx, from.TenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.MaxTransferAmount > 0 && amount > tenant.MaxTransferAmount {
+		return ErrTransferLimitExceeded
+	}
+	return nil
+}
+
+type CreateTenantTxParams struct {
+	CreateTenantParams
+	Audit CreateAuditEntryParams
+}
+
+// CreateTenantTx creates a tenant, which users can sign up to right away.
+func (store *SQLStore) CreateTenantTx(ctx context.Context, arg CreateTenantTxParams) (Tenant, error) {
+	var tenant Tenant
+
+	err := store.execTx(ctx, "CreateTenantTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		tenant, err = q.CreateTenant(ctx, arg.CreateTenantParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return tenant, err
+}
+
+type UpdateTenantTxParams struct {
+	UpdateTenantParams
+	Audit CreateAuditEntryParams
+}
+
+// UpdateTenantTx changes the terms of a tenant. The accounts opened in a currency it no longer
+// offers stay open.
+func (store *SQLStore) UpdateTenantTx(ctx context.Context, arg UpdateTenantTxParams) (Tenant, error) {
+	var tenant Tenant
+
+	err := store.execTx(ctx, "UpdateTenantTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		tenant, err = q.UpdateTenant(ctx, arg.UpdateTenantParams)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditEntry(ctx, arg.Audit)
+		return err
+	})
+	return tenant, err
+}
@@ -0,0 +1,30 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"log"
+
+	"github.com/backendmaster/simple_bank/testenv"
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// testDBSource starts a migrated Postgres container, removed by close.
+func testDBSource(ctx context.Context, config util.Config) (source string, close func(), err error) {
+	env, err := testenv.New()
+	if err != nil {
+		return "", nil, err
+	}
+	close = func() {
+		if err := env.Close(); err != nil {
+			log.Printf("can't not remove test containers: %v", err)
+		}
+	}
+	source, err = env.Postgres(ctx)
+	if err != nil {
+		close()
+		return "", nil, err
+	}
+	return source, close, nil
+}
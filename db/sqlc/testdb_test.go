@@ -0,0 +1,14 @@
+//go:build !integration
+
+package db
+
+import (
+	"context"
+
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// testDBSource is the database of app.env, migrated beforehand with make migrateup.
+func testDBSource(ctx context.Context, config util.Config) (source string, close func(), err error) {
+	return config.DBSource, func() {}, nil
+}
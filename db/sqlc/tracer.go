@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// queryTracer logs every query sent on the connections of a pool with its name, a hash of its
+// arguments and its duration. The hash tells apart calls made with different arguments without
+// putting the values, passwords and emails among them, in the logs. Queries taking longer than
+// slowThreshold are logged as warnings and counted, a zero threshold flags none.
+type queryTracer struct {
+	slowThreshold time.Duration
+}
+
+type queryTraceKey struct{}
+
+type startedQuery struct {
+	name     string
+	argsHash string
+	start    time.Time
+}
+
+func (tracer queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, startedQuery{
+		name:     queryName(data.SQL),
+		argsHash: hashArgs(data.Args),
+		start:    time.Now(),
+	})
+}
+
+func (tracer queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryTraceKey{}).(startedQuery)
+	if !ok {
+		return
+	}
+	duration := time.Since(query.start)
+	slow := tracer.slowThreshold > 0 && duration >= tracer.slowThreshold
+
+	var event *zerolog.Event
+	switch {
+	case data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows):
+		event = log.Error().Err(data.Err)
+	case slow:
+		event = log.Warn()
+	default:
+		event = log.Debug()
+	}
+	if slow {
+		metrics.ObserveDBSlowQuery(query.name)
+	}
+
+	event.Str("query", query.name).Str("args hash", query.argsHash).
+		Dur("duration", duration).Bool("slow", slow).Msg("database query")
+}
+
+func hashArgs(args []interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+	hash := fnv.New64a()
+	for _, arg := range args {
+		fmt.Fprintf(hash, "%T:%v;", arg, arg)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
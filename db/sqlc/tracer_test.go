@@ -0,0 +1,69 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTracer(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&buffer).Level(zerolog.DebugLevel)
+	t.Cleanup(func() {
+		log.Logger = logger
+	})
+
+	testCases := []struct {
+		name          string
+		slowThreshold time.Duration
+		err           error
+		level         string
+		slow          bool
+	}{
+		{name: "Fast", slowThreshold: time.Hour, level: "debug", slow: false},
+		{name: "Slow", slowThreshold: time.Nanosecond, level: "warn", slow: true},
+		{name: "NoThreshold", slowThreshold: 0, level: "debug", slow: false},
+		{name: "NoRows", slowThreshold: time.Hour, err: pgx.ErrNoRows, level: "debug", slow: false},
+		{name: "Error", slowThreshold: time.Hour, err: errors.New("connection reset"), level: "error", slow: false},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			buffer.Reset()
+			tracer := queryTracer{slowThreshold: tc.slowThreshold}
+
+			ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+				SQL:  "-- name: GetUser :one\nSELECT * FROM users WHERE username = $1",
+				Args: []interface{}{"alice"},
+			})
+			time.Sleep(time.Millisecond)
+			tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: tc.err})
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+			require.Equal(t, tc.level, entry["level"])
+			require.Equal(t, "GetUser", entry["query"])
+			require.Equal(t, tc.slow, entry["slow"])
+			require.Equal(t, hashArgs([]interface{}{"alice"}), entry["args hash"])
+			require.NotContains(t, buffer.String(), "alice")
+		})
+	}
+}
+
+func TestHashArgs(t *testing.T) {
+	require.Empty(t, hashArgs(nil))
+	require.Equal(t, hashArgs([]interface{}{int64(1), "a"}), hashArgs([]interface{}{int64(1), "a"}))
+	require.NotEqual(t, hashArgs([]interface{}{int64(1)}), hashArgs([]interface{}{int64(2)}))
+	require.NotEqual(t, hashArgs([]interface{}{int64(1)}), hashArgs([]interface{}{int32(1)}))
+}
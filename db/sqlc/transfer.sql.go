@@ -1,32 +1,66 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 // source: transfer.sql
 
 package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countTransfersSince = `-- name: CountTransfersSince :one
+SELECT count(*) FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2
+`
+
+type CountTransfersSinceParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// the transfers the account sent since created_at.
+func (q *Queries) CountTransfersSince(ctx context.Context, arg CountTransfersSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTransfersSince, arg.FromAccountID, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  tenant_id
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $4,
+  COALESCE(
+    (SELECT tenant_id FROM accounts WHERE id = $1 AND owner NOT LIKE 'bank.%'),
+    (SELECT tenant_id FROM accounts WHERE id = $2)
+  )
+) RETURNING id, from_account_id, to_account_id, amount, created_at, memo, tenant_id
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Memo          string `json:"memo"`
 }
 
+// a transfer belongs to the tenant of its payer, or of its payee when the bank pays.
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,17 +68,19 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, tenant_id FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, getTransfer, id)
+	row := q.db.QueryRow(ctx, getTransfer, id)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -52,31 +88,83 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.TenantID,
 	)
 	return i, err
 }
 
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, tenant_id FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+// serializes what pays the transfer back, its refunds and the reversal of its dispute.
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Memo,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const hasTransferBetween = `-- name: HasTransferBetween :one
+SELECT EXISTS (
+  SELECT 1 FROM transfers
+  WHERE from_account_id = $1 AND to_account_id = $2
+)
+`
+
+type HasTransferBetweenParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+}
+
+// whether the account ever sent a transfer to to_account_id.
+func (q *Queries) HasTransferBetween(ctx context.Context, arg HasTransferBetweenParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasTransferBetween, arg.FromAccountID, arg.ToAccountID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, tenant_id FROM transfers
 WHERE 
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = $1 OR
+    to_account_id = $2)
+    AND created_at >= COALESCE($3::timestamptz, '-infinity')
+    AND created_at < COALESCE($4::timestamptz, 'infinity')
 ORDER BY id
-LIMIT $3
-OFFSET $4
+LIMIT $5
+OFFSET $6
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	FromTime      pgtype.Timestamptz `json:"from_time"`
+	ToTime        pgtype.Timestamptz `json:"to_time"`
+	Limit         int32              `json:"limit"`
+	Offset        int32              `json:"offset"`
 }
 
+// from_time and to_time bound created_at when set, so that only the partitions
+// of the months in between are scanned.
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
-	rows, err := q.db.QueryContext(ctx, listTransfers,
+	rows, err := q.db.Query(ctx, listTransfers,
 		arg.FromAccountID,
 		arg.ToAccountID,
+		arg.FromTime,
+		arg.ToTime,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -93,14 +181,153 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listTransfersAfter = `-- name: ListTransfersAfter :many
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, tenant_id FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListTransfersAfterParams struct {
+	AccountID int64 `json:"account_id"`
+	AfterID   int64 `json:"after_id"`
+	Limit     int32 `json:"limit"`
+}
+
+func (q *Queries) ListTransfersAfter(ctx context.Context, arg ListTransfersAfterParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listTransfersAfter, arg.AccountID, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersBefore = `-- name: ListTransfersBefore :many
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, tenant_id FROM transfers
+WHERE (from_account_id = $1 OR to_account_id = $1)
+  AND id < $2
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListTransfersBeforeParams struct {
+	AccountID int64 `json:"account_id"`
+	BeforeID  int64 `json:"before_id"`
+	Limit     int32 `json:"limit"`
+}
+
+func (q *Queries) ListTransfersBefore(ctx context.Context, arg ListTransfersBeforeParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listTransfersBefore, arg.AccountID, arg.BeforeID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.tenant_id, ts_rank(to_tsvector('simple', memo), query)::real AS rank
+FROM transfers, to_tsquery('simple', $1) query
+WHERE to_tsvector('simple', memo) @@ query
+ORDER BY rank DESC, id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type SearchTransfersParams struct {
+	Query  string `json:"query"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type SearchTransfersRow struct {
+	ID            int64     `json:"id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+	Memo          string    `json:"memo"`
+	TenantID      string    `json:"tenant_id"`
+	Rank          float32   `json:"rank"`
+}
+
+// query is a tsquery, see SearchQuery.
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error) {
+	rows, err := q.db.Query(ctx, searchTransfers, arg.Query, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTransfersRow{}
+	for rows.Next() {
+		var i SearchTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Memo,
+			&i.TenantID,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
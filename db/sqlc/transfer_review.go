@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of a transfer review. A pending review waits for its one-time code or for an
+// admin; an approved one ran its transfer, a rejected one never will.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+var (
+	// ErrReviewClosed is returned by CloseTransferReviewTx for a review already approved or rejected.
+	ErrReviewClosed = errors.New("transfer review is closed")
+	// ErrInvalidReviewStatus is returned by CloseTransferReviewTx for a status it can't close a review with.
+	ErrInvalidReviewStatus = errors.New("invalid transfer review status")
+)
+
+type CreateTransferReviewTxParams struct {
+	CreateTransferReviewParams
+	// AfterCreate, when set, returns the outbox tasks of the review once it is created, e.g. the
+	// email of its one-time code.
+	AfterCreate func(review TransferReview) ([]CreateOutboxTaskParams, error)
+}
+
+// CreateTransferReviewTx holds a transfer for a review along with its outbox tasks.
+func (store *SQLStore) CreateTransferReviewTx(ctx context.Context, arg CreateTransferReviewTxParams) (TransferReview, error) {
+	var review TransferReview
+
+	err := store.execTx(ctx, "CreateTransferReviewTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		review, err = q.CreateTransferReview(ctx, arg.CreateTransferReviewParams)
+		if err != nil || arg.AfterCreate == nil {
+			return err
+		}
+		tasks, err := arg.AfterCreate(review)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return review, err
+}
+
+type CloseTransferReviewTxParams struct {
+	ID int64
+	// Status is approved, which runs the transfer, or rejected.
+	Status     string
+	ReviewedBy string
+	Note       string
+	// OutboxTasks, AlertTask and RewardTask are those of the transfer an approval runs, see
+	// TransferTxParams.
+	OutboxTasks []CreateOutboxTaskParams
+	AlertTask   AlertTaskFunc
+	RewardTask  RewardTaskFunc
+	// Audit, when set, records the review as closed by an admin rather than by the one-time code
+	// of the user.
+	Audit *CreateAuditEntryParams
+}
+
+type CloseTransferReviewTxResult struct {
+	Review TransferReview `json:"review"`
+	// Transfer is the transfer an approval ran.
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// CloseTransferReviewTx approves or rejects a pending review. Approving it runs the transfer in
+// the same transaction, so that a transfer failing e.g. on a frozen account leaves the review
+// pending, and two approvals can't both run it.
+func (store *SQLStore) CloseTransferReviewTx(ctx context.Context, arg CloseTransferReviewTxParams) (CloseTransferReviewTxResult, error) {
+	var result CloseTransferReviewTxResult
+
+	if arg.Status != ReviewApproved && arg.Status != ReviewRejected {
+		return result, fmt.Errorf("%w %q", ErrInvalidReviewStatus, arg.Status)
+	}
+
+	err := store.execTx(ctx, "CloseTransferReviewTx", func(ctx context.Context, q *Queries) error {
+		review, err := q.GetTransferReviewForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if review.Status != ReviewPending {
+			return ErrReviewClosed
+		}
+
+		update := UpdateTransferReviewStatusParams{
+			ID:         review.ID,
+			Status:     arg.Status,
+			ReviewedBy: arg.ReviewedBy,
+			Note:       arg.Note,
+		}
+		if arg.Status == ReviewApproved {
+			transfer, err := store.transfer(ctx, q, TransferTxParams{
+				FromAccountID: review.FromAccountID,
+				ToAccountID:   review.ToAccountID,
+				Amount:        review.Amount,
+				Memo:          review.Memo,
+				OutboxTasks:   arg.OutboxTasks,
+				AlertTask:     arg.AlertTask,
+				RewardTask:    arg.RewardTask,
+			})
+			if err != nil {
+				return err
+			}
+			result.Transfer = &transfer
+			update.TransferID = pgtype.Int8{Int64: transfer.Transfer.ID, Valid: true}
+		}
+
+		result.Review, err = q.UpdateTransferReviewStatus(ctx, update)
+		if err != nil {
+			return err
+		}
+		if arg.Audit != nil {
+			_, err = q.CreateAuditEntry(ctx, *arg.Audit)
+		}
+		return err
+	})
+	return result, err
+}
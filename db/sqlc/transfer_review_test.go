@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransferReview(t *testing.T, store Store, from, to Account) TransferReview {
+	review, err := store.CreateTransferReviewTx(context.Background(), CreateTransferReviewTxParams{
+		CreateTransferReviewParams: CreateTransferReviewParams{
+			Username:      from.Owner,
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        10,
+			Currency:      from.Currency,
+			Decision:      "hold",
+			Rules:         []string{"new_payee_large_amount"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReviewPending, review.Status)
+	require.Equal(t, []string{"new_payee_large_amount"}, review.Rules)
+	require.False(t, review.TransferID.Valid)
+	return review
+}
+
+func TestCloseTransferReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+	sender := createRandomAccount(t)
+	recipient := createRandomAccount(t)
+	review := createRandomTransferReview(t, store, sender, recipient)
+
+	// a transfer held for a review hasn't moved any money
+	since, err := testQuires.CountTransfersSince(context.Background(), CountTransfersSinceParams{
+		FromAccountID: sender.ID,
+		CreatedAt:     time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Zero(t, since)
+
+	arg := CloseTransferReviewTxParams{
+		ID:         review.ID,
+		Status:     ReviewApproved,
+		ReviewedBy: "admin",
+		Note:       "called the owner",
+	}
+	result, err := store.CloseTransferReviewTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, ReviewApproved, result.Review.Status)
+	require.True(t, result.Review.ReviewedAt.Valid)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Review.TransferID.Int64)
+	require.Equal(t, sender.Balance-10, result.Transfer.FromAccount.Balance)
+
+	paid, err := testQuires.HasTransferBetween(context.Background(), HasTransferBetweenParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, paid)
+
+	// a closed review can't run its transfer twice
+	_, err = store.CloseTransferReviewTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrReviewClosed)
+
+	arg.Status = ReviewPending
+	_, err = store.CloseTransferReviewTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidReviewStatus)
+}
+
+func TestRejectTransferReview(t *testing.T) {
+	store := NewStore(testDB)
+	sender := createRandomAccount(t)
+	recipient := createRandomAccount(t)
+	review := createRandomTransferReview(t, store, sender, recipient)
+
+	attempted, err := testQuires.AddTransferReviewOTPAttempt(context.Background(), review.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), attempted.OtpAttempts)
+
+	result, err := store.CloseTransferReviewTx(context.Background(), CloseTransferReviewTxParams{
+		ID:         review.ID,
+		Status:     ReviewRejected,
+		ReviewedBy: sender.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ReviewRejected, result.Review.Status)
+	require.Nil(t, result.Transfer)
+	require.False(t, result.Review.TransferID.Valid)
+
+	account, err := testQuires.GetAccount(context.Background(), sender.ID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance, account.Balance)
+}
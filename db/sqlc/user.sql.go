@@ -1,39 +1,81 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.18.0
 // source: user.sql
 
 package db
 
 import (
 	"context"
-	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeUser = `-- name: AnonymizeUser :one
+UPDATE users
+SET
+ hashed_password = '',
+ full_name = $1,
+ email = $2,
+ password_changed_at = now(),
+ deleted_at = now()
+WHERE username = $3 AND deleted_at IS NULL
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences
+`
+
+type AnonymizeUserParams struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, anonymizeUser, arg.FullName, arg.Email, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username,
   hashed_password,
   full_name,
-  email
+  email,
+  tenant_id
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+  $1, $2, $3, $4, COALESCE($5::varchar, 'default')
+) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences
 `
 
 type CreateUserParams struct {
-	Username       string `json:"username"`
-	HashedPassword string `json:"hashed_password"`
-	FullName       string `json:"full_name"`
-	Email          string `json:"email"`
+	Username       string      `json:"username"`
+	HashedPassword string      `json:"hashed_password"`
+	FullName       string      `json:"full_name"`
+	Email          string      `json:"email"`
+	TenantID       pgtype.Text `json:"tenant_id"`
 }
 
+// a user joins the default tenant unless tenant_id tells.
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser,
+	row := q.db.QueryRow(ctx, createUser,
 		arg.Username,
 		arg.HashedPassword,
 		arg.FullName,
 		arg.Email,
+		arg.TenantID,
 	)
 	var i User
 	err := row.Scan(
@@ -43,17 +85,21 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
-WHERE username = $1 LIMIT 1
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences FROM users
+WHERE username = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUser, username)
+	row := q.db.QueryRow(ctx, getUser, username)
 	var i User
 	err := row.Scan(
 		&i.Username,
@@ -62,10 +108,101 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
 	)
 	return i, err
 }
 
+const getUserIncludeDeleted = `-- name: GetUserIncludeDeleted :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences FROM users
+WHERE username = $1 LIMIT 1
+`
+
+// for admins investigating a user that may have been deleted.
+func (q *Queries) GetUserIncludeDeleted(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserIncludeDeleted, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT users.username, users.hashed_password, users.full_name, users.email, users.password_changed_at, users.created_at, users.role, users.deleted_at, users.tenant_id, users.preferences, ts_rank(to_tsvector('simple', username || ' ' || full_name || ' ' || translate(email, '@.', '  ')), query)::real AS rank
+FROM users, to_tsquery('simple', $1) query
+WHERE deleted_at IS NULL
+  AND to_tsvector('simple', username || ' ' || full_name || ' ' || translate(email, '@.', '  ')) @@ query
+ORDER BY rank DESC, username
+LIMIT $2
+OFFSET $3
+`
+
+type SearchUsersParams struct {
+	Query  string `json:"query"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type SearchUsersRow struct {
+	Username          string             `json:"username"`
+	HashedPassword    string             `json:"hashed_password"`
+	FullName          string             `json:"full_name"`
+	Email             string             `json:"email"`
+	PasswordChangedAt time.Time          `json:"password_changed_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	Role              string             `json:"role"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	TenantID          string             `json:"tenant_id"`
+	Preferences       []byte             `json:"preferences"`
+	Rank              float32            `json:"rank"`
+}
+
+// query is a tsquery, see SearchQuery.
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Query, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchUsersRow{}
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.TenantID,
+			&i.Preferences,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
@@ -73,20 +210,20 @@ SET
  full_name = coalesce($2, full_name),
  email = coalesce($3,email),
  password_changed_at = coalesce($4, password_changed_at)
-WHERE username = $5
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+WHERE username = $5 AND deleted_at IS NULL
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences
 `
 
 type UpdateUserParams struct {
-	HashedPassword    sql.NullString `json:"hashed_password"`
-	FullName          sql.NullString `json:"full_name"`
-	Email             sql.NullString `json:"email"`
-	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
-	Username          string         `json:"username"`
+	HashedPassword    pgtype.Text        `json:"hashed_password"`
+	FullName          pgtype.Text        `json:"full_name"`
+	Email             pgtype.Text        `json:"email"`
+	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
+	Username          string             `json:"username"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUser,
+	row := q.db.QueryRow(ctx, updateUser,
 		arg.HashedPassword,
 		arg.FullName,
 		arg.Email,
@@ -101,6 +238,60 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
+	)
+	return i, err
+}
+
+const updateUserPreferences = `-- name: UpdateUserPreferences :one
+UPDATE users
+SET preferences = $2
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING preferences
+`
+
+type UpdateUserPreferencesParams struct {
+	Username    string `json:"username"`
+	Preferences []byte `json:"preferences"`
+}
+
+// preferences replaces the whole document, see the preferences package.
+func (q *Queries) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) ([]byte, error) {
+	row := q.db.QueryRow(ctx, updateUserPreferences, arg.Username, arg.Preferences)
+	var preferences []byte
+	err := row.Scan(&preferences)
+	return preferences, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deleted_at, tenant_id, preferences
+`
+
+type UpdateUserRoleParams struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.TenantID,
+		&i.Preferences,
 	)
 	return i, err
 }
@@ -0,0 +1,88 @@
+package db
+
+import "context"
+
+// The statuses of a user import. A pending import waits for the worker, which completes it once
+// every row of its file is imported or failed; a failed import couldn't be read at all.
+const (
+	UserImportPending   = "pending"
+	UserImportCompleted = "completed"
+	UserImportFailed    = "failed"
+)
+
+// The statuses of a row of a user import.
+const (
+	UserImportRowImported = "imported"
+	UserImportRowFailed   = "failed"
+)
+
+type CreateUserImportTxParams struct {
+	CreateUserImportParams
+	Content []byte
+	// Audit records the import as run by an admin.
+	Audit CreateAuditEntryParams
+	// AfterCreate returns the outbox tasks of the import once it is created, the one importing
+	// its rows.
+	AfterCreate func(userImport UserImport) ([]CreateOutboxTaskParams, error)
+}
+
+// CreateUserImportTx creates an import along with its file, its audit entry and the outbox task
+// that imports its rows.
+func (store *SQLStore) CreateUserImportTx(ctx context.Context, arg CreateUserImportTxParams) (UserImport, error) {
+	var userImport UserImport
+
+	err := store.execTx(ctx, "CreateUserImportTx", func(ctx context.Context, q *Queries) error {
+		var err error
+		userImport, err = q.CreateUserImport(ctx, arg.CreateUserImportParams)
+		if err != nil {
+			return err
+		}
+		err = q.CreateUserImportFile(ctx, CreateUserImportFileParams{
+			ImportID: userImport.ID,
+			Content:  arg.Content,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := q.CreateAuditEntry(ctx, arg.Audit); err != nil {
+			return err
+		}
+		if arg.AfterCreate == nil {
+			return nil
+		}
+		tasks, err := arg.AfterCreate(userImport)
+		if err != nil {
+			return err
+		}
+		return createOutboxTasks(ctx, q, tasks)
+	})
+	return userImport, err
+}
+
+type ImportUserTxParams struct {
+	ImportID int64
+	// Line is the line of the row of the user in the file of the import.
+	Line int64
+	CreateUserTxParams
+}
+
+// ImportUserTx creates the user of a row of an import, like CreateUserTx, along with the row of
+// the report, so that a retry of the import never imports a row twice.
+func (store *SQLStore) ImportUserTx(ctx context.Context, arg ImportUserTxParams) (UserImportRow, error) {
+	var row UserImportRow
+
+	err := store.execTx(ctx, "ImportUserTx", func(ctx context.Context, q *Queries) error {
+		result, err := createUserTx(ctx, q, arg.CreateUserTxParams)
+		if err != nil {
+			return err
+		}
+		row, err = q.CreateUserImportRow(ctx, CreateUserImportRowParams{
+			ImportID: arg.ImportID,
+			Line:     arg.Line,
+			Username: result.User.Username,
+			Status:   UserImportRowImported,
+		})
+		return err
+	})
+	return row, err
+}
@@ -2,23 +2,22 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/backendmaster/simple_bank/testfixtures"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomUser(t *testing.T) User {
-	hashedPassword, err := util.HashedPassword(util.RandomString(6))
-	require.NoError(t, err)
-
+func createRandomUser(t testing.TB, options ...testfixtures.UserOption) User {
+	fixture := testfixtures.NewUser(t, options...)
 	args := CreateUserParams{
-		Username:       util.RandomOwnerName(),
-		HashedPassword: hashedPassword,
-		FullName:       util.RandomOwnerName(),
-		Email:          util.RandomEmail(),
+		Username:       fixture.Username,
+		HashedPassword: fixture.HashedPassword,
+		FullName:       fixture.FullName,
+		Email:          fixture.Email,
 	}
 
 	user, err := testQuires.CreateUser(context.Background(), args)
@@ -62,7 +61,7 @@ func TestUpdateUserOnlyFullName(t *testing.T) {
 	newFullName := util.RandomString(10)
 	arg := UpdateUserParams{
 		Username: oldUser.Username,
-		FullName: sql.NullString{
+		FullName: pgtype.Text{
 			String: newFullName,
 			Valid:  true,
 		},
@@ -85,7 +84,7 @@ func TestUpdateUserOnlyEmail(t *testing.T) {
 	newEmail := util.RandomEmail()
 	arg := UpdateUserParams{
 		Username: oldUser.Username,
-		Email: sql.NullString{
+		Email: pgtype.Text{
 			String: newEmail,
 			Valid:  true,
 		},
@@ -110,11 +109,11 @@ func TestUpdateUserOnlyPassword(t *testing.T) {
 	require.NoError(t, err)
 	arg := UpdateUserParams{
 		Username: oldUser.Username,
-		HashedPassword: sql.NullString{
+		HashedPassword: pgtype.Text{
 			String: newHashedPassword,
 			Valid:  true,
 		},
-		PasswordChangedAt: sql.NullTime{
+		PasswordChangedAt: pgtype.Timestamptz{
 			Time:  time.Now(),
 			Valid: true,
 		},
@@ -142,19 +141,19 @@ func TestUpdateUserAllFields(t *testing.T) {
 	require.NoError(t, err)
 	arg := UpdateUserParams{
 		Username: oldUser.Username,
-		HashedPassword: sql.NullString{
+		HashedPassword: pgtype.Text{
 			String: newHashedPassword,
 			Valid:  true,
 		},
-		FullName: sql.NullString{
+		FullName: pgtype.Text{
 			String: newFullName,
 			Valid:  true,
 		},
-		Email: sql.NullString{
+		Email: pgtype.Text{
 			String: newEmail,
 			Valid:  true,
 		},
-		PasswordChangedAt: sql.NullTime{
+		PasswordChangedAt: pgtype.Timestamptz{
 			Time:  time.Now(),
 			Valid: true,
 		},
@@ -173,3 +172,30 @@ func TestUpdateUserAllFields(t *testing.T) {
 	require.Equal(t, newEmail, newUser.Email)
 	require.WithinDuration(t, newUser.PasswordChangedAt, time.Now(), time.Second)
 }
+
+func TestDeleteUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	deleted, err := store.DeleteUserTx(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, deleted.DeletedAt.Valid)
+	require.NotEqual(t, user.Email, deleted.Email)
+
+	_, err = store.GetUser(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = store.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		FullName: pgtype.Text{String: util.RandomOwnerName(), Valid: true},
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	found, err := store.GetUserIncludeDeleted(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, deleted.DeletedAt, found.DeletedAt)
+
+	// deleting twice finds no user to delete
+	_, err = store.DeleteUserTx(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/backendmaster/simple_bank/domain"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 )
@@ -19,29 +21,30 @@ func NewUsersHandlerDelivery(usercase domain.UsersTableUseCase) usersHandlerDeli
 	}
 }
 
-func errResponse(err error) gin.H {
-	return gin.H{"err": err.Error()}
+func errResponse(ctx *gin.Context, err error) envelope.Envelope {
+	return envelope.Errors(ctx, envelope.Error{Message: err.Error()})
 }
 
 func (d *usersHandlerDelivery) handlerCreateUser(ctx *gin.Context) {
 	var req domain.CreateUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
 	rsp, err := d.usercase.CreateUser(context.Background(), req)
 	if err != nil {
 		if errors.Is(err, domain.ErrorUniqueViolation) {
-			ctx.JSON(http.StatusInternalServerError, errResponse(err))
+			ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 		return
 	}
+	metrics.ObserveSignup()
 
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
 
 func (d *usersHandlerDelivery) handlerLoginUser(ctx *gin.Context) {
@@ -49,7 +52,7 @@ func (d *usersHandlerDelivery) handlerLoginUser(ctx *gin.Context) {
 	var req domain.LoginUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		ctx.JSON(http.StatusBadRequest, errResponse(ctx, err))
 		return
 	}
 
@@ -57,16 +60,18 @@ func (d *usersHandlerDelivery) handlerLoginUser(ctx *gin.Context) {
 
 	if err != nil {
 		if errors.Cause(err) == domain.ErrorUserNotFound {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
+			metrics.ObserveLoginFailure(metrics.LoginUserNotFound)
+			ctx.JSON(http.StatusNotFound, errResponse(ctx, err))
 		} else if errors.Cause(err) == domain.ErrorInternalServerError {
-			ctx.JSON(http.StatusInternalServerError, errResponse(err))
+			ctx.JSON(http.StatusInternalServerError, errResponse(ctx, err))
 		} else if errors.Cause(err) == domain.ErrorStatusForbidden {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
 		} else if errors.Cause(err) == domain.ErrorPermissionNowAllowed {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
+			metrics.ObserveLoginFailure(metrics.LoginWrongPassword)
+			ctx.JSON(http.StatusForbidden, errResponse(ctx, err))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, rsp)
+	envelope.JSON(ctx, http.StatusOK, rsp)
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/backendmaster/simple_bank/envelope"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/gin-gonic/gin"
 )
@@ -16,8 +17,7 @@ const (
 )
 
 func errAbortUnauthorizedResponse(ctx *gin.Context, err error) {
-	errHeader := gin.H{"err": err.Error()}
-	ctx.AbortWithStatusJSON(http.StatusUnauthorized, errHeader)
+	envelope.Abort(ctx, http.StatusUnauthorized, err.Error())
 }
 
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
@@ -1,24 +1,46 @@
 package delivery
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"net/http"
+	"time"
 
+	"github.com/backendmaster/simple_bank/envelope"
 	"github.com/rs/zerolog/log"
 
+	"github.com/backendmaster/simple_bank/compression"
+	"github.com/backendmaster/simple_bank/crossorigin"
 	"github.com/backendmaster/simple_bank/db/gorm"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/health"
+	"github.com/backendmaster/simple_bank/limits"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/recovery"
 	repository "github.com/backendmaster/simple_bank/repository/postgresql"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/tracing"
 	"github.com/backendmaster/simple_bank/usercase"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/version"
 	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
 	userhandler usersHandlerDelivery
+	store       health.Store
+	mode        *maintenance.Mode
+	limiter     ratelimit.Limiter
+	config      util.Config
+	timeouts    map[string]time.Duration
 	router      *gin.Engine
+	httpServer  *http.Server
 }
 
-func NewGormServer(config util.Config, conn *sql.DB) (*Server, error) {
+func NewGormServer(config util.Config, conn *sql.DB, store db.Store, mode *maintenance.Mode) (*Server, error) {
 	gormDb, err := gorm.NewDB(conn)
 	if err != nil {
 		log.Fatal().Err(err)
@@ -32,8 +54,17 @@ func NewGormServer(config util.Config, conn *sql.DB) (*Server, error) {
 	sessionUseCase := usercase.NewSessionUseCase(sessionRepo)
 	usersTableUseCase := usercase.NewusersTableUserCase(config, userRepo, sessionUseCase, tokenMaker)
 	userhandler := NewUsersHandlerDelivery(usersTableUseCase)
+	timeouts, err := limits.ParseRouteTimeouts(config.RouteTimeouts)
+	if err != nil {
+		return nil, err
+	}
 	server := &Server{
 		userhandler: userhandler,
+		store:       store,
+		mode:        mode,
+		limiter:     ratelimit.New(config),
+		config:      config,
+		timeouts:    timeouts,
 	}
 	server.SetupRouter()
 	return server, nil
@@ -41,9 +72,20 @@ func NewGormServer(config util.Config, conn *sql.DB) (*Server, error) {
 
 func (s *Server) SetupRouter() {
 
-	router := gin.Default()
-	router.POST("/users", s.userhandler.handlerCreateUser)
-	router.POST("/users/login", s.userhandler.handlerLoginUser)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(envelope.GinRequestID(), gin.Logger(), tracing.GinTracing(), metrics.GinMetrics(), recovery.GinRecovery(), crossorigin.GinCors(s.config), maintenance.GinReadOnly(s.mode),
+		limits.GinTimeout(s.config.RequestTimeout, s.timeouts), limits.GinMaxBodySize(s.config.MaxBodySize, nil),
+		compression.GinCompression(s.config.CompressionMinSize))
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/healthz", gin.WrapH(health.Liveness()))
+	router.GET("/version", gin.WrapH(version.Handler()))
+	router.GET("/readyz", gin.WrapH(health.Readiness(s.store)))
+	rateLimit := ratelimit.GinRateLimit(s.limiter, func(ctx *gin.Context) string {
+		return ratelimit.IPKey(ctx.Request)
+	})
+	router.POST("/users", rateLimit, s.userhandler.handlerCreateUser)
+	router.POST("/users/login", rateLimit, s.userhandler.handlerLoginUser)
 	// d.router.POST("tokens/renew_access", server.renewAccessToken)
 
 	// authRoute := router.Group("/").Use(authMiddleware(server.tokenMaker))
@@ -52,8 +94,21 @@ func (s *Server) SetupRouter() {
 	// authRoute.GET("/accounts", server.listAccount)
 	// authRoute.POST("/transfers", server.createTransfer)
 	s.router = router
+	s.httpServer = &http.Server{Handler: router}
 }
 
+// Start serves the api on address until Shutdown is called.
 func (s *Server) Start(address string) error {
-	return s.router.Run(address)
+	s.httpServer.Addr = address
+	err := s.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for the in-flight requests to finish,
+// or for ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,17 @@
+// Package swagger serves the OpenAPI docs of the gateway, generated from the proto files by
+// make proto, with a Swagger UI to try the endpoints out.
+package swagger
+
+import (
+	"embed"
+	"net/http"
+)
+
+//go:embed index.html simple_bank.swagger.json
+var files embed.FS
+
+// Handler serves the UI at prefix and the spec at prefix + "simple_bank.swagger.json". prefix
+// must end with a slash.
+func Handler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, http.FileServer(http.FS(files)))
+}
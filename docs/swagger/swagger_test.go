@@ -0,0 +1,75 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	handler := Handler("/swagger/")
+
+	testCases := []struct {
+		name          string
+		path          string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "UI",
+			path: "/swagger/",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+				require.Contains(t, recorder.Body.String(), "simple_bank.swagger.json")
+			},
+		},
+		{
+			name: "Spec",
+			path: "/swagger/simple_bank.swagger.json",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var spec struct {
+					SecurityDefinitions map[string]struct {
+						Type string `json:"type"`
+						In   string `json:"in"`
+						Name string `json:"name"`
+					} `json:"securityDefinitions"`
+					Security []map[string][]string                           `json:"security"`
+					Paths    map[string]map[string]struct{ Security *[]any } `json:"paths"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &spec))
+				require.Equal(t, "header", spec.SecurityDefinitions["Bearer"].In)
+				require.Equal(t, "Authorization", spec.SecurityDefinitions["Bearer"].Name)
+				require.Equal(t, []map[string][]string{{"Bearer": {}}}, spec.Security)
+
+				// logging in doesn't need a token, the rest of the endpoints inherit the requirement
+				require.NotNil(t, spec.Paths["/v1/login_user"]["post"].Security)
+				require.Empty(t, *spec.Paths["/v1/login_user"]["post"].Security)
+				require.Nil(t, spec.Paths["/v1/accounts"]["get"].Security)
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/swagger/swagger.go",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
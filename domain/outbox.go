@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// OutboxTask is a background task written in the same transaction as the change that
+// triggers it, and later published to the task queue by the outbox relay.
+type OutboxTask struct {
+	ID          int64 `gorm:"primary_key"`
+	TaskType    string
+	Payload     []byte
+	Queue       string
+	MaxRetry    int32
+	PublishedAt *time.Time
+	CreatedAt   time.Time
+}
+
+func (OutboxTask) TableName() string {
+	return "outbox"
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 var (
@@ -25,8 +26,11 @@ type User struct {
 	HashedPassword    string
 	FullName          string
 	Email             string
+	Role              string `gorm:"default:depositor"`
+	TenantID          string `gorm:"default:default"`
 	PasswordChangedAt time.Time
 	CreatedAt         time.Time
+	DeletedAt         gorm.DeletedAt
 }
 
 type CreateUserRequest struct {
@@ -62,14 +66,14 @@ type LoginUserResponse struct {
 
 type UsersRepository interface {
 	GetByUsername(cxt context.Context, username string) (*User, error)
-	Create(cxt context.Context, user User) (*User, error)
+	Create(cxt context.Context, user User, tasks ...OutboxTask) (*User, error)
 	Update(cxt context.Context, user User) (*User, error)
 	// PrintLog() string
 }
 
 type UsersTableUseCase interface {
 	CreateUser(cxt context.Context, req CreateUserRequest) (*UserResponse, error)
-	CreateToken(username string, duration time.Duration) (string, *token.Payload, error)
+	CreateToken(username string, role string, tenant string, duration time.Duration) (string, *token.Payload, error)
 	// PrintLog() string
 	LoginUser(cxt context.Context, req LoginUserRequest) (*LoginUserResponse, error)
 }
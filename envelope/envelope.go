@@ -0,0 +1,91 @@
+// Package envelope writes the bodies of the gin api, and of the gin middlewares in front of it,
+// in the one shape every client parses:
+//
+//	{"data": {...}, "request_id": "...", "pagination": {"next_cursor": "..."}, "errors": [{"message": "..."}]}
+//
+// data is what the handler answers with, null for an error, pagination is only there for the
+// pages of a list and errors only for an error. A GET request asks for a part of data only with
+// FieldsParam.
+package envelope
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "x-request-id"
+
+// Envelope is the body of every json response of the gin api.
+type Envelope struct {
+	Data       any         `json:"data"`
+	RequestID  string      `json:"request_id"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Errors     []Error     `json:"errors,omitempty"`
+}
+
+// Pagination holds the cursors of the pages around the one in data, empty when there is none.
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+	// Field is the path of the field of the request breaking a rule, for the errors of a binding.
+	Field string `json:"field,omitempty"`
+}
+
+// GinRequestID makes sure every request carries a request id, reusing the one sent by the
+// client (or a proxy in front of us) when it is present, like gapi.RequestID does for the gateway.
+func GinRequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+			ctx.Request.Header.Set(RequestIDHeader, requestID)
+		}
+		ctx.Header(RequestIDHeader, requestID)
+		ctx.Next()
+	}
+}
+
+// RequestID returns the request id of the request of ctx.
+func RequestID(ctx *gin.Context) string {
+	return ctx.GetHeader(RequestIDHeader)
+}
+
+// JSON answers data with status, only the fields of it asked for by FieldsParam.
+func JSON(ctx *gin.Context, status int, data any) {
+	data, err := selectFields(ctx, data)
+	if err != nil {
+		Abort(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.JSON(status, Envelope{Data: data, RequestID: RequestID(ctx)})
+}
+
+// Page answers data, a page of a list, along with the cursors of the pages around it.
+func Page(ctx *gin.Context, status int, data any, nextCursor, prevCursor string) {
+	data, err := selectFields(ctx, data)
+	if err != nil {
+		Abort(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx.JSON(status, Envelope{
+		Data:       data,
+		RequestID:  RequestID(ctx),
+		Pagination: &Pagination{NextCursor: nextCursor, PrevCursor: prevCursor},
+	})
+}
+
+// Errors is the envelope of errs.
+func Errors(ctx *gin.Context, errs ...Error) Envelope {
+	return Envelope{RequestID: RequestID(ctx), Errors: errs}
+}
+
+// Abort answers the error message with status and stops the handlers after the caller.
+func Abort(ctx *gin.Context, status int, message string) {
+	ctx.AbortWithStatusJSON(status, Errors(ctx, Error{Message: message}))
+}
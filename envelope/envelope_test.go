@@ -0,0 +1,51 @@
+package envelope
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinRequestID())
+	router.GET("/accounts", func(ctx *gin.Context) {
+		Page(ctx, http.StatusOK, []int{1, 2}, "next", "")
+	})
+	router.GET("/accounts/:id", func(ctx *gin.Context) {
+		Abort(ctx, http.StatusNotFound, "account not found")
+	})
+	return router
+}
+
+func TestPage(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+	require.NoError(t, err)
+	request.Header.Set(RequestIDHeader, "request-1")
+	newTestRouter().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "request-1", recorder.Header().Get(RequestIDHeader))
+	require.JSONEq(t, `{"data": [1, 2], "request_id": "request-1", "pagination": {"next_cursor": "next"}}`, recorder.Body.String())
+}
+
+func TestAbort(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	newTestRouter().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	var rsp Envelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Nil(t, rsp.Data)
+	require.NotEmpty(t, rsp.RequestID)
+	require.Equal(t, rsp.RequestID, recorder.Header().Get(RequestIDHeader))
+	require.Equal(t, []Error{{Message: "account not found"}}, rsp.Errors)
+}
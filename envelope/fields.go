@@ -0,0 +1,111 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldsParam is the query parameter of a GET request asking for a partial response: the comma
+// separated paths of the fields of data to answer with, e.g. fields=id,balance, or
+// fields=accounts.id,accounts.balance for a list, the paths going through the items of arrays.
+// The envelope itself, the request id and the pagination, is always answered.
+const FieldsParam = "fields"
+
+var fieldPath = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// fieldTree holds the paths of the fields to answer with, by name; a nil subtree selects the
+// whole field.
+type fieldTree map[string]fieldTree
+
+// parseFields parses the value of FieldsParam, nil when it is empty.
+func parseFields(fields string) (fieldTree, error) {
+	if fields == "" {
+		return nil, nil
+	}
+
+	tree := fieldTree{}
+	for _, path := range strings.Split(fields, ",") {
+		path = strings.TrimSpace(path)
+		if !fieldPath.MatchString(path) {
+			return nil, fmt.Errorf("invalid field %q: must be a path like id or accounts.balance", path)
+		}
+
+		node := tree
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			sub, ok := node[name]
+			if ok && sub == nil {
+				// the whole field is already selected
+				break
+			}
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if !ok {
+				sub = fieldTree{}
+				node[name] = sub
+			}
+			node = sub
+		}
+	}
+	return tree, nil
+}
+
+// prune keeps the fields of value that tree selects. The fields missing from value, e.g. those
+// omitted when empty, are left out rather than refused.
+func (tree fieldTree) prune(value any) any {
+	switch value := value.(type) {
+	case []any:
+		for i := range value {
+			value[i] = tree.prune(value[i])
+		}
+		return value
+	case map[string]any:
+		selected := make(map[string]any, len(tree))
+		for name, sub := range tree {
+			field, ok := value[name]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				field = sub.prune(field)
+			}
+			selected[name] = field
+		}
+		return selected
+	default:
+		return value
+	}
+}
+
+// selectFields returns the part of data the FieldsParam of a GET request asks for, data itself
+// when it asks for none.
+func selectFields(ctx *gin.Context, data any) (any, error) {
+	if ctx.Request.Method != http.MethodGet {
+		return data, nil
+	}
+	tree, err := parseFields(ctx.Query(FieldsParam))
+	if err != nil || tree == nil {
+		return data, err
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// keeps the int64 amounts and ids exact, which float64 would round
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return tree.prune(value), nil
+}
@@ -0,0 +1,118 @@
+package envelope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type testAccount struct {
+	ID       int64  `json:"id"`
+	Balance  int64  `json:"balance"`
+	Currency string `json:"currency"`
+	Display  *struct {
+		Amount int64  `json:"amount"`
+		Rate   string `json:"rate"`
+	} `json:"display,omitempty"`
+}
+
+func TestParseFields(t *testing.T) {
+	tree, err := parseFields("")
+	require.NoError(t, err)
+	require.Nil(t, tree)
+
+	tree, err = parseFields("id, accounts.balance,accounts.id,display,display.rate")
+	require.NoError(t, err)
+	require.Equal(t, fieldTree{
+		"id":       nil,
+		"accounts": fieldTree{"balance": nil, "id": nil},
+		"display":  nil,
+	}, tree)
+
+	for _, fields := range []string{"id,", "Balance", "accounts..id", "id;balance"} {
+		_, err := parseFields(fields)
+		require.Error(t, err, fields)
+	}
+}
+
+func TestFields(t *testing.T) {
+	// beyond the 53 bits of a float64
+	const bigBalance = 1<<60 + 1
+	accounts := []testAccount{{ID: 1, Balance: bigBalance, Currency: "USD"}, {ID: 2, Balance: 20, Currency: "EUR"}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/accounts", func(ctx *gin.Context) {
+		Page(ctx, http.StatusOK, gin.H{"accounts": accounts, "total": 2}, "next", "")
+	})
+	router.GET("/accounts/:id", func(ctx *gin.Context) {
+		JSON(ctx, http.StatusOK, accounts[0])
+	})
+	router.POST("/accounts", func(ctx *gin.Context) {
+		JSON(ctx, http.StatusOK, accounts[0])
+	})
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Get",
+			method:     http.MethodGet,
+			path:       "/accounts/1?fields=id,balance,display.rate",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"id": 1, "balance": 1152921504606846977}, "request_id": ""}`,
+		},
+		{
+			name:       "List",
+			method:     http.MethodGet,
+			path:       "/accounts?fields=accounts.currency",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"accounts": [{"currency": "USD"}, {"currency": "EUR"}]}, "request_id": "", "pagination": {"next_cursor": "next"}}`,
+		},
+		{
+			name:       "NoFields",
+			method:     http.MethodGet,
+			path:       "/accounts/1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"id": 1, "balance": 1152921504606846977, "currency": "USD"}, "request_id": ""}`,
+		},
+		{
+			name:       "NotGet",
+			method:     http.MethodPost,
+			path:       "/accounts?fields=id",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data": {"id": 1, "balance": 1152921504606846977, "currency": "USD"}, "request_id": ""}`,
+		},
+		{
+			name:       "InvalidFields",
+			method:     http.MethodGet,
+			path:       "/accounts/1?fields=ID",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"data": null, "request_id": "", "errors": [{"message": "invalid field \"ID\": must be a path like id or accounts.balance"}]}`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.path, nil)
+			require.NoError(t, err)
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.wantStatus, recorder.Code)
+			require.JSONEq(t, tc.wantBody, recorder.Body.String())
+			if tc.wantStatus == http.StatusOK && tc.name != "List" {
+				// JSONEq compares the numbers as float64s
+				require.Contains(t, recorder.Body.String(), `"balance":1152921504606846977`)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// ChannelNewEntry is the channel the trigger of the entries table notifies every new entry on.
+const ChannelNewEntry = "new_entry"
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	// subscriptionBuffer is how many entries a subscriber can fall behind before it misses some.
+	subscriptionBuffer = 16
+)
+
+// Bus fans out the entries postgres notifies with LISTEN/NOTIFY to the subscribers of their account,
+// so that every instance learns about the entries of the others without an external broker.
+// Notifications are not persisted: the ones sent while the listener is reconnecting are lost,
+// and so are the entries a subscriber that can't keep up falls behind on.
+type Bus struct {
+	connPool *pgxpool.Pool
+
+	mu          sync.Mutex
+	subscribers map[int64]map[*subscription]struct{}
+}
+
+func NewBus(connPool *pgxpool.Pool) *Bus {
+	return &Bus{
+		connPool:    connPool,
+		subscribers: make(map[int64]map[*subscription]struct{}),
+	}
+}
+
+type subscription struct {
+	accountID int64
+	ch        chan db.Entry
+}
+
+// SubscribeEntries subscribes to the new entries of accountID until cancel is called, which closes entries.
+func (bus *Bus) SubscribeEntries(accountID int64) (entries <-chan db.Entry, cancel func()) {
+	sub := &subscription{
+		accountID: accountID,
+		ch:        make(chan db.Entry, subscriptionBuffer),
+	}
+
+	bus.mu.Lock()
+	if bus.subscribers[accountID] == nil {
+		bus.subscribers[accountID] = make(map[*subscription]struct{})
+	}
+	bus.subscribers[accountID][sub] = struct{}{}
+	bus.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() { bus.unsubscribe(sub) })
+	}
+}
+
+func (bus *Bus) unsubscribe(sub *subscription) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	delete(bus.subscribers[sub.accountID], sub)
+	if len(bus.subscribers[sub.accountID]) == 0 {
+		delete(bus.subscribers, sub.accountID)
+	}
+	close(sub.ch)
+}
+
+// Run listens to the notifications of postgres and dispatches them until ctx is done,
+// reconnecting with a growing delay when the connection is lost.
+func (bus *Bus) Run(ctx context.Context) error {
+	reconnectInterval := minReconnectInterval
+	for {
+		connected := time.Now()
+		err := bus.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// a connection that lasted is no sign of a database still down
+		if time.Since(connected) > maxReconnectInterval {
+			reconnectInterval = minReconnectInterval
+		}
+		// whatever was notified until the next connection is lost
+		log.Error().Err(err).Dur("retry in", reconnectInterval).Msg("event bus lost its connection, notifications may be missed")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectInterval):
+		}
+		reconnectInterval *= 2
+		if reconnectInterval > maxReconnectInterval {
+			reconnectInterval = maxReconnectInterval
+		}
+	}
+}
+
+// listen dispatches the notifications received on a connection of its own until it fails.
+func (bus *Bus) listen(ctx context.Context) error {
+	pooled, err := bus.connPool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// the connection listens for as long as it lives, so it is taken out of the pool for good
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+ChannelNewEntry); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		bus.dispatch(notification.Channel, notification.Payload)
+	}
+}
+
+func (bus *Bus) dispatch(channel, payload string) {
+	if channel != ChannelNewEntry {
+		return
+	}
+
+	var entry db.Entry
+	if err := json.Unmarshal([]byte(payload), &entry); err != nil {
+		log.Error().Err(err).Str("payload", payload).Msg("failed to decode new entry notification")
+		return
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for sub := range bus.subscribers[entry.AccountID] {
+		select {
+		case sub.ch <- entry:
+		default:
+			log.Warn().Int64("account id", entry.AccountID).Int64("entry id", entry.ID).
+				Msg("event bus subscriber is too slow, dropped entry")
+		}
+	}
+}
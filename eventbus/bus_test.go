@@ -0,0 +1,55 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const entryPayload = `{"id":5,"account_id":1,"amount":-10,"created_at":"2023-03-01T10:00:00.123456+00:00"}`
+
+func TestDispatch(t *testing.T) {
+	bus := NewBus(nil)
+	entries, cancel := bus.SubscribeEntries(1)
+	defer cancel()
+	otherEntries, cancelOther := bus.SubscribeEntries(2)
+	defer cancelOther()
+
+	bus.dispatch(ChannelNewEntry, entryPayload)
+	bus.dispatch("other_channel", entryPayload)
+	bus.dispatch(ChannelNewEntry, "not json")
+
+	entry := <-entries
+	require.Equal(t, int64(5), entry.ID)
+	require.Equal(t, int64(1), entry.AccountID)
+	require.Equal(t, int64(-10), entry.Amount)
+	require.True(t, entry.CreatedAt.Equal(time.Date(2023, 3, 1, 10, 0, 0, 123456000, time.UTC)))
+	require.Empty(t, entries)
+	require.Empty(t, otherEntries)
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	bus := NewBus(nil)
+	entries, cancel := bus.SubscribeEntries(1)
+	defer cancel()
+
+	// the bus drops what doesn't fit in the buffer instead of waiting for the subscriber
+	for i := 0; i < subscriptionBuffer+5; i++ {
+		bus.dispatch(ChannelNewEntry, entryPayload)
+	}
+	require.Len(t, entries, subscriptionBuffer)
+}
+
+func TestSubscriptionCancel(t *testing.T) {
+	bus := NewBus(nil)
+	entries, cancel := bus.SubscribeEntries(1)
+	cancel()
+	cancel()
+
+	_, ok := <-entries
+	require.False(t, ok)
+	require.Empty(t, bus.subscribers)
+
+	bus.dispatch(ChannelNewEntry, entryPayload)
+}
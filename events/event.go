@@ -0,0 +1,49 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TypeUserCreated       = "user.created"
+	TypeTransferCompleted = "transfer.completed"
+	TypeAccountFrozen     = "account.frozen"
+)
+
+// Event is the envelope of a domain event on the broker. The same event can be published more
+// than once, e.g. when the relay stops before marking it as published, so consumers should
+// ignore the ids they have already seen.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"key"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// UserCreated is the payload of a user.created event, keyed by username.
+type UserCreated struct {
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferCompleted is the payload of a transfer.completed event, keyed by the debited account,
+// so that the transfers of an account reach the consumers in order.
+type TransferCompleted struct {
+	TransferID    int64     `json:"transfer_id"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AccountFrozen is the payload of an account.frozen event, keyed by account id.
+type AccountFrozen struct {
+	AccountID int64     `json:"account_id"`
+	Owner     string    `json:"owner"`
+	Reason    string    `json:"reason"`
+	FrozenAt  time.Time `json:"frozen_at"`
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher writes the events to a kafka topic, partitioned by their key.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) Publisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// the relay waits for every batch, so there is nothing to gain in waiting for more messages
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (publisher *KafkaPublisher) Publish(ctx context.Context, events ...Event) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		messages = append(messages, kafka.Message{
+			Key:   []byte(event.Key),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(event.ID)},
+				{Key: "event_type", Value: []byte(event.Type)},
+			},
+		})
+	}
+
+	if err := publisher.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to write events to kafka: %w", err)
+	}
+	return nil
+}
+
+func (publisher *KafkaPublisher) Close() error {
+	return publisher.writer.Close()
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes the events on NATS, each on the subject of its type under subjectPrefix,
+// e.g. simple_bank.events.transfer.completed.
+type NATSPublisher struct {
+	conn          *nats.Conn
+	subjectPrefix string
+}
+
+func NewNATSPublisher(url string, subjectPrefix string) (Publisher, error) {
+	conn, err := nats.Connect(url, nats.Name("simple_bank"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	return &NATSPublisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}, nil
+}
+
+func (publisher *NATSPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		if err := publisher.conn.Publish(publisher.subject(event), data); err != nil {
+			return fmt.Errorf("failed to publish event to nats: %w", err)
+		}
+	}
+
+	// the flush round trip makes sure the server got the events before they are marked as published
+	if err := publisher.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush events to nats: %w", err)
+	}
+	return nil
+}
+
+func (publisher *NATSPublisher) subject(event Event) string {
+	return publisher.subjectPrefix + "." + event.Type
+}
+
+func (publisher *NATSPublisher) Close() error {
+	return publisher.conn.Drain()
+}
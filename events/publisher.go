@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	BrokerLog   = "log"
+	BrokerKafka = "kafka"
+	BrokerNATS  = "nats"
+)
+
+// Publisher sends domain events to a message broker. Publish returns once the broker
+// has accepted all of the events, in the order they are given.
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+	Close() error
+}
+
+// NewPublisher creates the publisher of the broker chosen by EVENT_BROKER, which only logs the events by default.
+func NewPublisher(config util.Config) (Publisher, error) {
+	switch config.EventBroker {
+	case BrokerLog, "":
+		return logPublisher{}, nil
+	case BrokerKafka:
+		return NewKafkaPublisher(config.KafkaBrokers, config.EventTopic), nil
+	case BrokerNATS:
+		return NewNATSPublisher(config.NATSURL, config.EventTopic)
+	default:
+		return nil, fmt.Errorf("unknown event broker %q", config.EventBroker)
+	}
+}
+
+// logPublisher only logs the events, for development.
+type logPublisher struct{}
+
+func (logPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		log.Info().Str("type", event.Type).Str("id", event.ID).Str("key", event.Key).Msg("published event")
+	}
+	return nil
+}
+
+func (logPublisher) Close() error {
+	return nil
+}
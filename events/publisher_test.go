@@ -0,0 +1,103 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublisher(t *testing.T) {
+	publisher, err := NewPublisher(util.Config{})
+	require.NoError(t, err)
+	require.IsType(t, logPublisher{}, publisher)
+
+	publisher, err = NewPublisher(util.Config{EventBroker: BrokerKafka, KafkaBrokers: []string{"localhost:9092"}, EventTopic: "events"})
+	require.NoError(t, err)
+	require.IsType(t, &KafkaPublisher{}, publisher)
+	require.NoError(t, publisher.Close())
+
+	_, err = NewPublisher(util.Config{EventBroker: "rabbitmq"})
+	require.Error(t, err)
+}
+
+type natsMessage struct {
+	subject string
+	data    []byte
+}
+
+// startNATSServer answers just enough of the nats protocol for a client to connect and publish.
+func startNATSServer(t *testing.T) (string, <-chan natsMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan natsMessage, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "PING":
+				fmt.Fprintf(conn, "PONG\r\n")
+			case "PUB":
+				size, _ := strconv.Atoi(fields[len(fields)-1])
+				data := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, data); err != nil {
+					return
+				}
+				messages <- natsMessage{subject: fields[1], data: data[:size]}
+			}
+		}
+	}()
+
+	return "nats://" + listener.Addr().String(), messages
+}
+
+func TestNATSPublisher(t *testing.T) {
+	url, messages := startNATSServer(t)
+
+	publisher, err := NewNATSPublisher(url, "simple_bank.events")
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	event := Event{
+		ID:         "1",
+		Type:       TypeUserCreated,
+		Key:        "tom",
+		OccurredAt: time.Now().UTC().Truncate(time.Second),
+		Payload:    json.RawMessage(`{"username":"tom"}`),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, publisher.Publish(ctx, event))
+
+	msg := <-messages
+	require.Equal(t, "simple_bank.events.user.created", msg.subject)
+	var published Event
+	require.NoError(t, json.Unmarshal(msg.data, &published))
+	require.Equal(t, event, published)
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+)
+
+var csvHeader = []string{"id", "date", "amount", "currency", "direction", "category", "tags"}
+
+// csvEncoder writes a row per entry under a header, the tags separated by semicolons.
+type csvEncoder struct {
+	writer    *csv.Writer
+	currency  string
+	statement Statement
+}
+
+func newCSVEncoder(w io.Writer, statement Statement) (*csvEncoder, error) {
+	encoder := &csvEncoder{
+		writer:    csv.NewWriter(w),
+		currency:  statement.Account.Currency,
+		statement: statement,
+	}
+	if err := encoder.writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return encoder, nil
+}
+
+func (encoder *csvEncoder) Encode(entry db.ListExportEntriesRow) error {
+	return encoder.writer.Write([]string{
+		strconv.FormatInt(entry.ID, 10),
+		encoder.statement.in(entry.CreatedAt).Format(time.RFC3339),
+		money.Format(entry.Amount, encoder.currency),
+		encoder.currency,
+		direction(entry),
+		entry.Category,
+		strings.Join(entry.Tags, ";"),
+	})
+}
+
+func (encoder *csvEncoder) Close() error {
+	encoder.writer.Flush()
+	return encoder.writer.Error()
+}
@@ -0,0 +1,133 @@
+// Package export writes the entries of an account in the formats the accounting tools import:
+// OFX, QIF and CSV.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+const (
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+	FormatCSV = "csv"
+)
+
+// ErrUnknownFormat is returned for a format the package doesn't write.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// batchSize is the number of entries Write reads at once, so that a large export is never held
+// in memory twice.
+const batchSize = 500
+
+// Store is the part of db.Store the exports need.
+type Store interface {
+	ListExportEntries(ctx context.Context, arg db.ListExportEntriesParams) ([]db.ListExportEntriesRow, error)
+}
+
+// Statement is what an export is of: the entries of Account created from From until To.
+type Statement struct {
+	Account db.Account
+	From    time.Time
+	To      time.Time
+	// At is when the export is made, the time the balance of Account is as of.
+	At time.Time
+	// Location is the timezone of the owner of Account, which the dates are written in, UTC
+	// when nil. OFX dates carry their timezone and are always in UTC.
+	Location *time.Location
+}
+
+// in returns t in the timezone of statement.
+func (statement Statement) in(t time.Time) time.Time {
+	if statement.Location == nil {
+		return t.UTC()
+	}
+	return t.In(statement.Location)
+}
+
+// Encoder writes the entries of a statement one by one, oldest first. Close writes what follows
+// the last entry and flushes the writer.
+type Encoder interface {
+	Encode(entry db.ListExportEntriesRow) error
+	Close() error
+}
+
+// NewEncoder returns the encoder of format writing to w, which has written what precedes the
+// first entry, e.g. the header of a CSV.
+func NewEncoder(w io.Writer, format string, statement Statement) (Encoder, error) {
+	switch format {
+	case FormatOFX:
+		return newOFXEncoder(w, statement)
+	case FormatQIF:
+		return newQIFEncoder(w, statement)
+	case FormatCSV:
+		return newCSVEncoder(w, statement)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+// ContentType returns the media type of the files of format.
+func ContentType(format string) string {
+	switch format {
+	case FormatOFX:
+		return "application/x-ofx"
+	case FormatQIF:
+		return "application/qif"
+	case FormatCSV:
+		return "text/csv"
+	}
+	return "application/octet-stream"
+}
+
+// FileName names the file of an export, e.g. account-1-20260101-20260201.csv.
+func FileName(format string, statement Statement) string {
+	return fmt.Sprintf("account-%d-%s-%s.%s", statement.Account.ID,
+		statement.in(statement.From).Format("20060102"), statement.in(statement.To).Format("20060102"), format)
+}
+
+// Write writes the entries of statement to w in format, reading them batch by batch, and
+// returns how many it wrote.
+func Write(ctx context.Context, store Store, w io.Writer, format string, statement Statement) (int64, error) {
+	encoder, err := NewEncoder(w, format, statement)
+	if err != nil {
+		return 0, err
+	}
+
+	var count, afterID int64
+	for {
+		entries, err := store.ListExportEntries(ctx, db.ListExportEntriesParams{
+			AccountID: statement.Account.ID,
+			FromTime:  statement.From,
+			ToTime:    statement.To,
+			AfterID:   afterID,
+			Limit:     batchSize,
+		})
+		if err != nil {
+			return count, err
+		}
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(entries) < batchSize {
+			break
+		}
+		afterID = entries[len(entries)-1].ID
+	}
+	return count, encoder.Close()
+}
+
+// direction tells a debit, money going out of the account, from a credit.
+func direction(entry db.ListExportEntriesRow) string {
+	if entry.Amount < 0 {
+		return db.DirectionDebit
+	}
+	return db.DirectionCredit
+}
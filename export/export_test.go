@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatement() Statement {
+	return Statement{
+		Account: db.Account{ID: 7, Owner: "alice", Balance: 90, Currency: "USD"},
+		From:    time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+		At:      time.Date(2026, time.November, 2, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func testEntries() []db.ListExportEntriesRow {
+	return []db.ListExportEntriesRow{
+		{ID: 11, AccountID: 7, Amount: 100, CreatedAt: time.Date(2026, time.October, 3, 8, 0, 0, 0, time.UTC), Tags: []string{}},
+		{ID: 12, AccountID: 7, Amount: -10, CreatedAt: time.Date(2026, time.October, 4, 12, 15, 0, 0, time.UTC),
+			Category: "groceries", Tags: []string{"food", "weekly"}},
+	}
+}
+
+func encode(t *testing.T, format string) string {
+	var buf bytes.Buffer
+	encoder, err := NewEncoder(&buf, format, testStatement())
+	require.NoError(t, err)
+	for _, entry := range testEntries() {
+		require.NoError(t, encoder.Encode(entry))
+	}
+	require.NoError(t, encoder.Close())
+	return buf.String()
+}
+
+func TestCSV(t *testing.T) {
+	require.Equal(t, "id,date,amount,currency,direction,category,tags\n"+
+		"11,2026-10-03T08:00:00Z,1.00,USD,credit,,\n"+
+		"12,2026-10-04T12:15:00Z,-0.10,USD,debit,groceries,food;weekly\n", encode(t, FormatCSV))
+}
+
+func TestQIF(t *testing.T) {
+	require.Equal(t, "!Type:Bank\n"+
+		"D10/03/2026\nT1.00\nN11\n^\n"+
+		"D10/04/2026\nT-0.10\nN12\nLgroceries\nMfood, weekly\n^\n", encode(t, FormatQIF))
+}
+
+func TestOFX(t *testing.T) {
+	ofx := encode(t, FormatOFX)
+	require.Contains(t, ofx, `<?OFX OFXHEADER="200" VERSION="220"`)
+	require.Contains(t, ofx, "<CURDEF>USD</CURDEF>")
+	require.Contains(t, ofx, "<ACCTID>7</ACCTID>")
+	require.Contains(t, ofx, "<DTSTART>20261001000000[0:GMT]</DTSTART>\n<DTEND>20261101000000[0:GMT]</DTEND>")
+	require.Contains(t, ofx, "<STMTTRN>\n<TRNTYPE>CREDIT</TRNTYPE>\n<DTPOSTED>20261003080000[0:GMT]</DTPOSTED>\n"+
+		"<TRNAMT>1.00</TRNAMT>\n<FITID>11</FITID>\n</STMTTRN>")
+	require.Contains(t, ofx, "<TRNTYPE>DEBIT</TRNTYPE>")
+	require.Contains(t, ofx, "<NAME>groceries</NAME>\n<MEMO>food, weekly</MEMO>")
+	require.Contains(t, ofx, "<BALAMT>0.90</BALAMT>\n<DTASOF>20261102093000[0:GMT]</DTASOF>")
+	require.Contains(t, ofx, "</OFX>\n")
+}
+
+func TestLocation(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	statement := testStatement()
+	statement.Location = taipei
+
+	var buf bytes.Buffer
+	encoder, err := NewEncoder(&buf, FormatCSV, statement)
+	require.NoError(t, err)
+	require.NoError(t, encoder.Encode(testEntries()[0]))
+	require.NoError(t, encoder.Close())
+	require.Contains(t, buf.String(), "11,2026-10-03T16:00:00+08:00,1.00,USD,credit,,\n")
+
+	// the statement starts on October 1st at midnight in UTC, 8am in Taipei
+	require.Equal(t, "account-7-20261001-20261101.csv", FileName(FormatCSV, statement))
+	statement.From = time.Date(2026, time.September, 30, 20, 0, 0, 0, time.UTC)
+	require.Equal(t, "account-7-20261001-20261101.csv", FileName(FormatCSV, statement))
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewEncoder(&bytes.Buffer{}, "xls", testStatement())
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	statement := testStatement()
+
+	// a full batch is followed by another read after its last entry
+	batch := make([]db.ListExportEntriesRow, batchSize)
+	for i := range batch {
+		batch[i] = db.ListExportEntriesRow{ID: int64(i + 1), AccountID: statement.Account.ID, Amount: 1, CreatedAt: statement.From}
+	}
+	gomock.InOrder(
+		store.EXPECT().
+			ListExportEntries(gomock.Any(), gomock.Eq(db.ListExportEntriesParams{
+				AccountID: statement.Account.ID,
+				FromTime:  statement.From,
+				ToTime:    statement.To,
+				AfterID:   0,
+				Limit:     batchSize,
+			})).
+			Times(1).
+			Return(batch, nil),
+		store.EXPECT().
+			ListExportEntries(gomock.Any(), gomock.Eq(db.ListExportEntriesParams{
+				AccountID: statement.Account.ID,
+				FromTime:  statement.From,
+				ToTime:    statement.To,
+				AfterID:   batchSize,
+				Limit:     batchSize,
+			})).
+			Times(1).
+			Return(testEntries(), nil),
+	)
+
+	var buf bytes.Buffer
+	count, err := Write(context.Background(), store, &buf, FormatCSV, statement)
+	require.NoError(t, err)
+	require.EqualValues(t, batchSize+2, count)
+	require.Equal(t, batchSize+3, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestFileName(t *testing.T) {
+	require.Equal(t, "account-7-20261001-20261101.qif", FileName(FormatQIF, testStatement()))
+	require.Equal(t, "text/csv", ContentType(FormatCSV))
+}
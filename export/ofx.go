@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+)
+
+// ofxBankID identifies the bank in the OFX files.
+const ofxBankID = "SIMPLEBANK"
+
+// ofxDate is the datetime format of OFX, always written in UTC.
+const ofxDate = "20060102150405"
+
+// ofxEncoder writes an OFX 2.2 bank statement: the transactions of the entries, and the balance
+// of the account when the export is made.
+type ofxEncoder struct {
+	writer    *bufio.Writer
+	statement Statement
+}
+
+func newOFXEncoder(w io.Writer, statement Statement) (*ofxEncoder, error) {
+	encoder := &ofxEncoder{writer: bufio.NewWriter(w), statement: statement}
+	_, err := fmt.Fprintf(encoder.writer, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1>
+<SONRS>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<DTSERVER>%s</DTSERVER>
+<LANGUAGE>ENG</LANGUAGE>
+</SONRS>
+</SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>0</TRNUID>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS>
+<CURDEF>%s</CURDEF>
+<BANKACCTFROM>
+<BANKID>%s</BANKID>
+<ACCTID>%d</ACCTID>
+<ACCTTYPE>CHECKING</ACCTTYPE>
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>%s</DTSTART>
+<DTEND>%s</DTEND>
+`,
+		formatOFXDate(statement.At), escapeOFX(statement.Account.Currency), ofxBankID, statement.Account.ID,
+		formatOFXDate(statement.From), formatOFXDate(statement.To),
+	)
+	return encoder, err
+}
+
+func (encoder *ofxEncoder) Encode(entry db.ListExportEntriesRow) error {
+	fmt.Fprintf(encoder.writer, "<STMTTRN>\n<TRNTYPE>%s</TRNTYPE>\n<DTPOSTED>%s</DTPOSTED>\n<TRNAMT>%s</TRNAMT>\n<FITID>%d</FITID>\n",
+		strings.ToUpper(direction(entry)), formatOFXDate(entry.CreatedAt), money.Format(entry.Amount, encoder.statement.Account.Currency), entry.ID)
+	if entry.Category != "" {
+		fmt.Fprintf(encoder.writer, "<NAME>%s</NAME>\n", escapeOFX(entry.Category))
+	}
+	if len(entry.Tags) > 0 {
+		fmt.Fprintf(encoder.writer, "<MEMO>%s</MEMO>\n", escapeOFX(strings.Join(entry.Tags, ", ")))
+	}
+	// the writer keeps the first error, which Flush returns
+	_, err := encoder.writer.WriteString("</STMTTRN>\n")
+	return err
+}
+
+func (encoder *ofxEncoder) Close() error {
+	fmt.Fprintf(encoder.writer, `</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>%s</BALAMT>
+<DTASOF>%s</DTASOF>
+</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`, money.Format(encoder.statement.Account.Balance, encoder.statement.Account.Currency), formatOFXDate(encoder.statement.At))
+	return encoder.writer.Flush()
+}
+
+func formatOFXDate(t time.Time) string {
+	return t.UTC().Format(ofxDate) + "[0:GMT]"
+}
+
+func escapeOFX(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s)) // a strings.Builder never fails
+	return escaped.String()
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+)
+
+// qifDate is the date format of QIF, US style as most tools expect it.
+const qifDate = "01/02/2006"
+
+// qifEncoder writes a bank account QIF: a record per entry, ended by a caret. QIF has no
+// currency, the tools take the one of the account they import into.
+type qifEncoder struct {
+	writer    *bufio.Writer
+	statement Statement
+}
+
+func newQIFEncoder(w io.Writer, statement Statement) (*qifEncoder, error) {
+	encoder := &qifEncoder{writer: bufio.NewWriter(w), statement: statement}
+	_, err := encoder.writer.WriteString("!Type:Bank\n")
+	return encoder, err
+}
+
+func (encoder *qifEncoder) Encode(entry db.ListExportEntriesRow) error {
+	fmt.Fprintf(encoder.writer, "D%s\nT%s\nN%d\n", encoder.statement.in(entry.CreatedAt).Format(qifDate), money.Format(entry.Amount, encoder.statement.Account.Currency), entry.ID)
+	if entry.Category != "" {
+		fmt.Fprintf(encoder.writer, "L%s\n", entry.Category)
+	}
+	if len(entry.Tags) > 0 {
+		fmt.Fprintf(encoder.writer, "M%s\n", strings.Join(entry.Tags, ", "))
+	}
+	// the writer keeps the first error, which Flush returns
+	_, err := encoder.writer.WriteString("^\n")
+	return err
+}
+
+func (encoder *qifEncoder) Close() error {
+	return encoder.writer.Flush()
+}
@@ -0,0 +1,125 @@
+// Package fraud evaluates the transfers against the fraud rules before they run. Each rule allows
+// a transfer, asks its owner to confirm it with a one-time code, or holds it until an admin
+// reviews it, and the engine applies the strictest decision of its rules:
+//
+//	engine, err := fraud.New(config, store, screening.NewDenylist(store))
+//	verdict, err := engine.Evaluate(ctx, fraud.Transfer{...})
+//	if verdict.Decision != fraud.Allow { ... }
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/screening"
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// Decision is what a rule decides for a transfer, from the most lenient to the strictest.
+type Decision string
+
+const (
+	Allow      Decision = "allow"
+	RequireOTP Decision = "require_otp"
+	Hold       Decision = "hold"
+)
+
+func (decision Decision) severity() int {
+	switch decision {
+	case RequireOTP:
+		return 1
+	case Hold:
+		return 2
+	}
+	return 0
+}
+
+// Transfer is what the rules know of a transfer about to run.
+type Transfer struct {
+	Username    string
+	FromAccount db.Account
+	ToAccount   db.Account
+	Amount      int64
+	// Country is the country the request came from, empty when it isn't known.
+	Country string
+	Time    time.Time
+}
+
+// Rule is a fraud rule. A rule failing to decide fails the evaluation, rather than letting the
+// transfer through unchecked.
+type Rule interface {
+	// Name identifies the rule in the verdicts, and so in the review queue.
+	Name() string
+	Evaluate(ctx context.Context, transfer Transfer) (Decision, error)
+}
+
+// Store is the part of db.Store the rules need.
+type Store interface {
+	CountTransfersSince(ctx context.Context, arg db.CountTransfersSinceParams) (int64, error)
+	HasTransferBetween(ctx context.Context, arg db.HasTransferBetweenParams) (bool, error)
+	GetLastLoginCountry(ctx context.Context, username string) (string, error)
+}
+
+// Verdict is the decision of the engine for a transfer.
+type Verdict struct {
+	Decision Decision `json:"decision"`
+	// Rules are the names of the rules that didn't allow the transfer.
+	Rules []string `json:"rules"`
+}
+
+// Engine evaluates the transfers against its rules.
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// New creates the engine of the rules config enables, each by its own setting:
+// FRAUD_VELOCITY_LIMIT, FRAUD_LARGE_AMOUNT, FRAUD_QUIET_HOURS and FRAUD_COUNTRY_HEADER, and of
+// the screening rule of screener when it isn't nil.
+func New(config util.Config, store Store, screener screening.Screener) (*Engine, error) {
+	var rules []Rule
+	if config.FraudVelocityLimit > 0 {
+		rules = append(rules, NewVelocityRule(store, config.FraudVelocityLimit, config.FraudVelocityWindow))
+	}
+	if config.FraudLargeAmount > 0 {
+		rules = append(rules, NewPayeeRule(store, config.FraudLargeAmount))
+	}
+	if config.FraudQuietHours != "" {
+		rule, err := ParseUnusualHoursRule(config.FraudQuietHours)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if config.FraudCountryHeader != "" {
+		rules = append(rules, NewGeoRule(store))
+	}
+	if screener != nil {
+		rules = append(rules, NewScreeningRule(screener))
+	}
+	return NewEngine(rules...), nil
+}
+
+// Evaluate runs every rule on the transfer, so that the verdict lists all the rules it set off.
+func (engine *Engine) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	verdict := Verdict{Decision: Allow}
+	for _, rule := range engine.rules {
+		decision, err := rule.Evaluate(ctx, transfer)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("failed to evaluate fraud rule %s: %w", rule.Name(), err)
+		}
+		if decision == Allow {
+			continue
+		}
+		verdict.Rules = append(verdict.Rules, rule.Name())
+		if decision.severity() > verdict.Decision.severity() {
+			verdict.Decision = decision
+		}
+	}
+	return verdict, nil
+}
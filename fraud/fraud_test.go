@@ -0,0 +1,297 @@
+package fraud
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/screening"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTransfer(amount int64) Transfer {
+	return Transfer{
+		Username:    util.RandomOwnerName(),
+		FromAccount: db.Account{ID: util.RandomInt(1, 1000), Owner: util.RandomOwnerName(), Currency: util.USD},
+		ToAccount:   db.Account{ID: util.RandomInt(1001, 2000), Owner: util.RandomOwnerName(), Currency: util.USD},
+		Amount:      amount,
+		Country:     "FR",
+		Time:        time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEngine(t *testing.T) {
+	transfer := randomTransfer(500)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, verdict Verdict, err error)
+	}{
+		{
+			name: "Allow",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(2), nil)
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetLastLoginCountry(gomock.Any(), gomock.Eq(transfer.Username)).Times(1).Return("fr", nil)
+			},
+			checkResponse: func(t *testing.T, verdict Verdict, err error) {
+				require.NoError(t, err)
+				require.Equal(t, Allow, verdict.Decision)
+				require.Empty(t, verdict.Rules)
+			},
+		},
+		{
+			name: "RequireOTP",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountTransfersSince(gomock.Any(), gomock.Eq(db.CountTransfersSinceParams{
+						FromAccountID: transfer.FromAccount.ID,
+						CreatedAt:     transfer.Time.Add(-time.Hour),
+					})).
+					Times(1).
+					Return(int64(3), nil)
+				store.EXPECT().GetLastLoginCountry(gomock.Any(), gomock.Any()).Times(1).Return("DE", nil)
+			},
+			checkResponse: func(t *testing.T, verdict Verdict, err error) {
+				require.NoError(t, err)
+				require.Equal(t, RequireOTP, verdict.Decision)
+				require.Equal(t, []string{"velocity", "geo_mismatch"}, verdict.Rules)
+			},
+		},
+		{
+			name: "RuleError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sql.ErrConnDone)
+				store.EXPECT().GetLastLoginCountry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, verdict Verdict, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			engine, err := New(util.Config{
+				FraudVelocityLimit:  3,
+				FraudVelocityWindow: time.Hour,
+				FraudLargeAmount:    1000,
+				FraudQuietHours:     "1-5",
+				FraudCountryHeader:  "CF-IPCountry",
+			}, store, nil)
+			require.NoError(t, err)
+
+			verdict, err := engine.Evaluate(context.Background(), transfer)
+			tc.checkResponse(t, verdict, err)
+		})
+	}
+}
+
+func TestEngineStrictestDecision(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transfer := randomTransfer(5000)
+	transfer.Time = time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+
+	quietHours, err := ParseUnusualHoursRule("1-5")
+	require.NoError(t, err)
+	engine := NewEngine(quietHours, NewPayeeRule(store, 1000))
+
+	verdict, err := engine.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Hold, verdict.Decision)
+	require.Equal(t, []string{"unusual_hours", "new_payee_large_amount"}, verdict.Rules)
+}
+
+func TestPayeeRule(t *testing.T) {
+	testCases := []struct {
+		name       string
+		amount     int64
+		sameOwner  bool
+		buildStubs func(store *mockdb.MockStore)
+		decision   Decision
+	}{
+		{
+			name:   "NewPayee",
+			amount: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			},
+			decision: Hold,
+		},
+		{
+			name:   "KnownPayee",
+			amount: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(1).Return(true, nil)
+			},
+			decision: Allow,
+		},
+		{
+			name:   "SmallAmount",
+			amount: 999,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(0)
+			},
+			decision: Allow,
+		},
+		{
+			name:      "OwnAccounts",
+			amount:    1000,
+			sameOwner: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(0)
+			},
+			decision: Allow,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			transfer := randomTransfer(tc.amount)
+			if tc.sameOwner {
+				transfer.ToAccount.Owner = transfer.FromAccount.Owner
+			}
+			decision, err := NewPayeeRule(store, 1000).Evaluate(context.Background(), transfer)
+			require.NoError(t, err)
+			require.Equal(t, tc.decision, decision)
+		})
+	}
+}
+
+func TestUnusualHoursRule(t *testing.T) {
+	testCases := []struct {
+		hours    string
+		hour     int
+		decision Decision
+	}{
+		{hours: "1-5", hour: 0, decision: Allow},
+		{hours: "1-5", hour: 1, decision: RequireOTP},
+		{hours: "1-5", hour: 4, decision: RequireOTP},
+		{hours: "1-5", hour: 5, decision: Allow},
+		{hours: "22-4", hour: 23, decision: RequireOTP},
+		{hours: "22-4", hour: 2, decision: RequireOTP},
+		{hours: "22-4", hour: 12, decision: Allow},
+	}
+
+	for _, tc := range testCases {
+		rule, err := ParseUnusualHoursRule(tc.hours)
+		require.NoError(t, err)
+
+		transfer := randomTransfer(10)
+		transfer.Time = time.Date(2026, 10, 16, tc.hour, 30, 0, 0, time.UTC)
+		decision, err := rule.Evaluate(context.Background(), transfer)
+		require.NoError(t, err)
+		require.Equal(t, tc.decision, decision, "%s at %d:30", tc.hours, tc.hour)
+	}
+
+	for _, hours := range []string{"", "5", "1-1", "0-24", "a-b"} {
+		_, err := ParseUnusualHoursRule(hours)
+		require.Error(t, err, hours)
+	}
+}
+
+func TestGeoRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rule := NewGeoRule(store)
+	transfer := randomTransfer(10)
+
+	// a user who never logged in with a known country
+	store.EXPECT().GetLastLoginCountry(gomock.Any(), gomock.Any()).Times(1).Return("", db.ErrRecordNotFound)
+	decision, err := rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	// a transfer whose country isn't known
+	transfer.Country = ""
+	decision, err = rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+}
+
+func TestScreeningRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rule := NewScreeningRule(screening.NewDenylist(store))
+	transfer := randomTransfer(10)
+
+	store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(transfer.FromAccount.ID)).Times(2).Return([]string{}, nil)
+	store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(transfer.ToAccount.ID)).Times(1).Return([]string{}, nil)
+	decision, err := rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	// a transfer to a flagged account is held like one from it
+	store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(transfer.ToAccount.ID)).Times(1).Return([]string{"account:1"}, nil)
+	decision, err = rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Hold, decision)
+}
+
+func TestOTP(t *testing.T) {
+	code, hash, err := NewOTP()
+	require.NoError(t, err)
+	require.Len(t, code, 6)
+	require.NotContains(t, hash, code)
+
+	require.True(t, CheckOTP(code, hash))
+	require.False(t, CheckOTP("", hash))
+
+	other, _, err := NewOTP()
+	require.NoError(t, err)
+	if other != code {
+		require.False(t, CheckOTP(other, hash))
+	}
+}
+
+func TestCheckLogin(t *testing.T) {
+	history := []db.Session{
+		{ClientIp: "10.0.0.1", UserAgent: "bank-app/1.0", Country: "FR"},
+		{ClientIp: "10.0.0.2", UserAgent: "Firefox", Country: ""},
+	}
+
+	require.Nil(t, CheckLogin(nil, "1.2.3.4", "US", "curl"))
+	require.Nil(t, CheckLogin(history, "10.0.0.1", "FR", "bank-app/1.0"))
+	require.Nil(t, CheckLogin(history, "1.2.3.4", "fr", "bank-app/1.0"))
+	require.Nil(t, CheckLogin(history, "10.0.0.2", "", "curl"))
+	require.Equal(t, []string{LoginNewCountry}, CheckLogin(history, "10.0.0.1", "US", "bank-app/1.0"))
+	require.Equal(t, []string{LoginNewIP, LoginNewUserAgent}, CheckLogin(history, "1.2.3.4", "", "curl"))
+	require.Equal(t, []string{LoginNewCountry, LoginNewIP, LoginNewUserAgent}, CheckLogin(history, "1.2.3.4", "US", "curl"))
+}
+
+func TestNewLinkCode(t *testing.T) {
+	code, hash, err := NewLinkCode()
+	require.NoError(t, err)
+	require.Len(t, code, 64)
+	require.True(t, CheckOTP(code, hash))
+	require.False(t, CheckOTP(code[1:], hash))
+}
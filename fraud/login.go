@@ -0,0 +1,61 @@
+package fraud
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// LoginHistorySize is how many of the last sessions of a user a login is compared to.
+const LoginHistorySize = 20
+
+// The reasons a login is suspicious.
+const (
+	LoginNewCountry   = "new_country"
+	LoginNewIP        = "new_ip"
+	LoginNewUserAgent = "new_user_agent"
+)
+
+// CheckLogin compares a login to the last sessions of its user and returns why it is suspicious,
+// nil when it isn't. A login is suspicious from a country the user never logged in from, or
+// from both an IP and a user agent they never used. The first login of a user, and a country
+// unknown on either side, raise nothing.
+func CheckLogin(history []db.Session, clientIP, country, userAgent string) []string {
+	if len(history) == 0 {
+		return nil
+	}
+
+	knownIP, knownUserAgent, knownCountry := false, false, false
+	countries := 0
+	for _, session := range history {
+		knownIP = knownIP || session.ClientIp == clientIP
+		knownUserAgent = knownUserAgent || session.UserAgent == userAgent
+		if session.Country != "" {
+			countries++
+			knownCountry = knownCountry || strings.EqualFold(session.Country, country)
+		}
+	}
+
+	var reasons []string
+	if country != "" && countries > 0 && !knownCountry {
+		reasons = append(reasons, LoginNewCountry)
+	}
+	if !knownIP && !knownUserAgent {
+		reasons = append(reasons, LoginNewIP, LoginNewUserAgent)
+	}
+	return reasons
+}
+
+// NewLinkCode generates the code of a link emailed to a user, too long to be guessed unlike a
+// one-time code. Only its hash is stored, checked by CheckOTP.
+func NewLinkCode() (code string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate link code: %w", err)
+	}
+	code = hex.EncodeToString(b)
+	return code, hashOTP(code), nil
+}
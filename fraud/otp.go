@@ -0,0 +1,39 @@
+package fraud
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// OTPValidity is how long the owner of a transfer has to confirm it with its one-time code.
+	OTPValidity = 10 * time.Minute
+	// MaxOTPAttempts is how many wrong codes reject a transfer.
+	MaxOTPAttempts = 5
+)
+
+// NewOTP generates the 6 digit one-time code of a transfer. Only its hash is stored, the code
+// itself is emailed to the owner.
+func NewOTP() (code string, hash string, err error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate one-time code: %w", err)
+	}
+	code = fmt.Sprintf("%06d", n.Int64())
+	return code, hashOTP(code), nil
+}
+
+func hashOTP(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckOTP tells whether code is the one-time code of hash.
+func CheckOTP(code, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashOTP(code)), []byte(hash)) == 1
+}
@@ -0,0 +1,177 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/screening"
+)
+
+// VelocityRule asks for a one-time code once an account sent limit transfers within window.
+type VelocityRule struct {
+	store  Store
+	limit  int64
+	window time.Duration
+}
+
+func NewVelocityRule(store Store, limit int, window time.Duration) *VelocityRule {
+	return &VelocityRule{store: store, limit: int64(limit), window: window}
+}
+
+func (rule *VelocityRule) Name() string {
+	return "velocity"
+}
+
+func (rule *VelocityRule) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	count, err := rule.store.CountTransfersSince(ctx, db.CountTransfersSinceParams{
+		FromAccountID: transfer.FromAccount.ID,
+		CreatedAt:     transfer.Time.Add(-rule.window),
+	})
+	if err != nil {
+		return "", err
+	}
+	if count >= rule.limit {
+		return RequireOTP, nil
+	}
+	return Allow, nil
+}
+
+// PayeeRule holds a transfer of at least largeAmount to an account the sender never paid
+// before. Transfers between the accounts of the same owner are left alone.
+type PayeeRule struct {
+	store       Store
+	largeAmount int64
+}
+
+func NewPayeeRule(store Store, largeAmount int64) *PayeeRule {
+	return &PayeeRule{store: store, largeAmount: largeAmount}
+}
+
+func (rule *PayeeRule) Name() string {
+	return "new_payee_large_amount"
+}
+
+func (rule *PayeeRule) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	if transfer.Amount < rule.largeAmount || transfer.FromAccount.Owner == transfer.ToAccount.Owner {
+		return Allow, nil
+	}
+	paid, err := rule.store.HasTransferBetween(ctx, db.HasTransferBetweenParams{
+		FromAccountID: transfer.FromAccount.ID,
+		ToAccountID:   transfer.ToAccount.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !paid {
+		return Hold, nil
+	}
+	return Allow, nil
+}
+
+// UnusualHoursRule asks for a one-time code for the transfers sent between the start and end
+// hours, in UTC. A range like 22-4 spans midnight.
+type UnusualHoursRule struct {
+	start int
+	end   int
+}
+
+// ParseUnusualHoursRule parses the hours of FRAUD_QUIET_HOURS, of the form start-end, e.g. 1-5
+// for the transfers from 01:00 to 04:59.
+func ParseUnusualHoursRule(hours string) (*UnusualHoursRule, error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(hours), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q, expected start-end", hours)
+	}
+	rule := &UnusualHoursRule{}
+	var err1, err2 error
+	rule.start, err1 = strconv.Atoi(start)
+	rule.end, err2 = strconv.Atoi(end)
+	if err1 != nil || err2 != nil || !validHour(rule.start) || !validHour(rule.end) || rule.start == rule.end {
+		return nil, fmt.Errorf("invalid quiet hours %q: hours must be two different numbers between 0 and 23", hours)
+	}
+	return rule, nil
+}
+
+func validHour(hour int) bool {
+	return hour >= 0 && hour < 24
+}
+
+func (rule *UnusualHoursRule) Name() string {
+	return "unusual_hours"
+}
+
+func (rule *UnusualHoursRule) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	hour := transfer.Time.UTC().Hour()
+	quiet := hour >= rule.start && hour < rule.end
+	if rule.start > rule.end {
+		quiet = hour >= rule.start || hour < rule.end
+	}
+	if quiet {
+		return RequireOTP, nil
+	}
+	return Allow, nil
+}
+
+// GeoRule asks for a one-time code for a transfer from another country than the last login of
+// the user, e.g. sent with an access token stolen from them. A transfer or a user whose country
+// isn't known is allowed.
+type GeoRule struct {
+	store Store
+}
+
+func NewGeoRule(store Store) *GeoRule {
+	return &GeoRule{store: store}
+}
+
+func (rule *GeoRule) Name() string {
+	return "geo_mismatch"
+}
+
+func (rule *GeoRule) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	if transfer.Country == "" {
+		return Allow, nil
+	}
+	country, err := rule.store.GetLastLoginCountry(ctx, transfer.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return Allow, nil
+		}
+		return "", err
+	}
+	if !strings.EqualFold(country, transfer.Country) {
+		return RequireOTP, nil
+	}
+	return Allow, nil
+}
+
+// ScreeningRule holds the transfers from or to an account the screener flags, e.g. one of a user
+// on the denylist.
+type ScreeningRule struct {
+	screener screening.Screener
+}
+
+func NewScreeningRule(screener screening.Screener) *ScreeningRule {
+	return &ScreeningRule{screener: screener}
+}
+
+func (rule *ScreeningRule) Name() string {
+	return "screening"
+}
+
+func (rule *ScreeningRule) Evaluate(ctx context.Context, transfer Transfer) (Decision, error) {
+	for _, account := range []db.Account{transfer.FromAccount, transfer.ToAccount} {
+		matches, err := rule.screener.ScreenAccount(ctx, account)
+		if err != nil {
+			return Allow, err
+		}
+		if len(matches) > 0 {
+			return Hold, nil
+		}
+	}
+	return Allow, nil
+}
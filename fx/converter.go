@@ -0,0 +1,183 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// cacheTTL is how long the converter goes by the rates it read, the refresh task fetching
+	// new ones far less often.
+	cacheTTL = time.Minute
+	// DefaultMaxAge is the FX_RATE_MAX_AGE of an empty config.
+	DefaultMaxAge = 6 * time.Hour
+)
+
+// Store is what the converter needs of db.Store.
+type Store interface {
+	ListExchangeRates(ctx context.Context) ([]db.ExchangeRate, error)
+}
+
+// Conversion is an amount converted into another currency.
+type Conversion struct {
+	FromCurrency string `json:"from_currency"`
+	FromAmount   int64  `json:"from_amount"`
+	ToCurrency   string `json:"to_currency"`
+	ToAmount     int64  `json:"to_amount"`
+	// Rate is the units of ToCurrency a unit of FromCurrency buys, e.g. 0.92.
+	Rate string `json:"rate"`
+	// FetchedAt is when the bank fetched the oldest of the rates the conversion went by.
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Converter converts the amounts at the exchange rates of the store, which it caches.
+type Converter struct {
+	store  Store
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	rates    map[string]map[string]db.ExchangeRate
+	loadedAt time.Time
+}
+
+// NewConverter creates a converter refusing the rates fetched longer than maxAge ago.
+func NewConverter(store Store, maxAge time.Duration) *Converter {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	return &Converter{store: store, maxAge: maxAge}
+}
+
+// Convert converts amount, in the minor units of from, into the minor units of to, rounded to the
+// nearest one. The currencies needn't be quoted against each other: 100 CAD is converted into EUR
+// by the rates of both against the base of the provider.
+func (converter *Converter) Convert(ctx context.Context, amount int64, from, to string) (Conversion, error) {
+	fromCurrency, ok := util.LookupCurrency(from)
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w: unknown currency %s", ErrNoRate, from)
+	}
+	toCurrency, ok := util.LookupCurrency(to)
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w: unknown currency %s", ErrNoRate, to)
+	}
+	conversion := Conversion{FromCurrency: from, FromAmount: amount, ToCurrency: to}
+	if from == to {
+		conversion.ToAmount = amount
+		conversion.Rate = FormatRate(RateScale)
+		conversion.FetchedAt = time.Now()
+		return conversion, nil
+	}
+
+	rates, err := converter.load(ctx)
+	if err != nil {
+		return Conversion{}, err
+	}
+	fromNanos, toNanos, fetchedAt, ok := crossRates(rates, from, to)
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w %s and %s", ErrNoRate, from, to)
+	}
+	if age := time.Since(fetchedAt); age > converter.maxAge {
+		return Conversion{}, fmt.Errorf("%w: the rates of %s and %s were fetched %s ago", ErrStaleRate, from, to, age.Round(time.Minute))
+	}
+
+	// amount × toNanos / fromNanos, from the minor units of from to those of to
+	magnitude := big.NewInt(amount)
+	magnitude.Abs(magnitude)
+	numerator := new(big.Int).Mul(magnitude, big.NewInt(toNanos))
+	numerator.Mul(numerator, pow10(toCurrency.MinorUnits))
+	denominator := new(big.Int).Mul(big.NewInt(fromNanos), pow10(fromCurrency.MinorUnits))
+	converted := roundedQuotient(numerator, denominator)
+	if !converted.IsInt64() {
+		return Conversion{}, fmt.Errorf("%d %s is too large to convert into %s", amount, from, to)
+	}
+	conversion.ToAmount = converted.Int64()
+	if amount < 0 {
+		conversion.ToAmount = -conversion.ToAmount
+	}
+	rate := roundedQuotient(new(big.Int).Mul(big.NewInt(toNanos), big.NewInt(RateScale)), big.NewInt(fromNanos))
+	conversion.Rate = FormatRate(rate.Int64())
+	conversion.FetchedAt = fetchedAt
+	return conversion, nil
+}
+
+// load returns the rates by base and quote currency, read again from the store once cacheTTL is
+// over. A failed read keeps the rates read before, which stop converting once too old.
+func (converter *Converter) load(ctx context.Context) (map[string]map[string]db.ExchangeRate, error) {
+	converter.mu.Lock()
+	defer converter.mu.Unlock()
+
+	if converter.rates != nil && time.Since(converter.loadedAt) < cacheTTL {
+		return converter.rates, nil
+	}
+	list, err := converter.store.ListExchangeRates(ctx)
+	if err != nil {
+		if converter.rates != nil {
+			log.Warn().Err(err).Msg("failed to reload exchange rates, converting at the cached ones")
+			return converter.rates, nil
+		}
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+
+	rates := make(map[string]map[string]db.ExchangeRate)
+	for _, rate := range list {
+		if rates[rate.BaseCurrency] == nil {
+			rates[rate.BaseCurrency] = make(map[string]db.ExchangeRate)
+		}
+		rates[rate.BaseCurrency][rate.QuoteCurrency] = rate
+	}
+	converter.rates = rates
+	converter.loadedAt = time.Now()
+	return rates, nil
+}
+
+// crossRates returns the rates of from and to against the same base currency, the one fetched
+// last when more than one quotes both, e.g. after the provider changed. fetchedAt is when the
+// older of the two was fetched.
+func crossRates(rates map[string]map[string]db.ExchangeRate, from, to string) (fromNanos, toNanos int64, fetchedAt time.Time, found bool) {
+	bases := make([]string, 0, len(rates))
+	for base := range rates {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+
+	for _, base := range bases {
+		fromRate, ok := quote(rates[base], base, from)
+		if !ok {
+			continue
+		}
+		toRate, ok := quote(rates[base], base, to)
+		if !ok {
+			continue
+		}
+		// the base is quoted against itself by no rate, so only the other one tells the time
+		fetched := fromRate.FetchedAt
+		if fetched.IsZero() || (!toRate.FetchedAt.IsZero() && toRate.FetchedAt.Before(fetched)) {
+			fetched = toRate.FetchedAt
+		}
+		if !found || fetched.After(fetchedAt) {
+			fromNanos, toNanos, fetchedAt, found = fromRate.RateNanos, toRate.RateNanos, fetched, true
+		}
+	}
+	return
+}
+
+// quote returns the rate of currency against base, which is 1 for the base itself.
+func quote(quotes map[string]db.ExchangeRate, base, currency string) (db.ExchangeRate, bool) {
+	if currency == base {
+		return db.ExchangeRate{BaseCurrency: base, QuoteCurrency: base, RateNanos: RateScale}, true
+	}
+	rate, ok := quotes[currency]
+	return rate, ok
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
@@ -0,0 +1,153 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomRates(fetchedAt time.Time) []db.ExchangeRate {
+	rates := []db.ExchangeRate{
+		{QuoteCurrency: util.CAD, RateNanos: 1_486_200_000},
+		{QuoteCurrency: "JPY", RateNanos: 162_370_000_000},
+		{QuoteCurrency: util.USD, RateNanos: 1_082_300_000},
+	}
+	for i := range rates {
+		rates[i].BaseCurrency = util.EUR
+		rates[i].Source = ProviderECB
+		rates[i].PublishedAt = fetchedAt.Truncate(24 * time.Hour)
+		rates[i].FetchedAt = fetchedAt
+	}
+	return rates
+}
+
+func TestConvert(t *testing.T) {
+	util.SetCurrency(util.Currency{Code: "JPY", MinorUnits: 0})
+	fetchedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name       string
+		amount     int64
+		from       string
+		to         string
+		rates      []db.ExchangeRate
+		conversion Conversion
+		err        error
+	}{
+		{
+			name:       "FromQuote",
+			amount:     10000,
+			from:       util.USD,
+			to:         util.EUR,
+			rates:      randomRates(fetchedAt),
+			conversion: Conversion{FromCurrency: util.USD, FromAmount: 10000, ToCurrency: util.EUR, ToAmount: 9240, Rate: "0.923958237", FetchedAt: fetchedAt},
+		},
+		{
+			name:       "CrossRate",
+			amount:     10000,
+			from:       util.USD,
+			to:         util.CAD,
+			rates:      randomRates(fetchedAt),
+			conversion: Conversion{FromCurrency: util.USD, FromAmount: 10000, ToCurrency: util.CAD, ToAmount: 13732, Rate: "1.373186732", FetchedAt: fetchedAt},
+		},
+		{
+			name:       "MinorUnits",
+			amount:     1000,
+			from:       "JPY",
+			to:         util.USD,
+			rates:      randomRates(fetchedAt),
+			conversion: Conversion{FromCurrency: "JPY", FromAmount: 1000, ToCurrency: util.USD, ToAmount: 667, Rate: "0.00666564", FetchedAt: fetchedAt},
+		},
+		{
+			name:       "Negative",
+			amount:     -10000,
+			from:       util.EUR,
+			to:         "JPY",
+			rates:      randomRates(fetchedAt),
+			conversion: Conversion{FromCurrency: util.EUR, FromAmount: -10000, ToCurrency: "JPY", ToAmount: -16237, Rate: "162.37", FetchedAt: fetchedAt},
+		},
+		{
+			name:   "NoRate",
+			amount: 10000,
+			from:   util.USD,
+			to:     util.CAD,
+			rates:  randomRates(fetchedAt)[1:],
+			err:    ErrNoRate,
+		},
+		{
+			name:   "UnknownCurrency",
+			amount: 10000,
+			from:   util.USD,
+			to:     "XYZ",
+			rates:  randomRates(fetchedAt),
+			err:    ErrNoRate,
+		},
+		{
+			name:   "Stale",
+			amount: 10000,
+			from:   util.USD,
+			to:     util.EUR,
+			rates:  randomRates(time.Now().Add(-DefaultMaxAge - time.Hour)),
+			err:    ErrStaleRate,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockExchangeRateStore(ctrl)
+			store.EXPECT().ListExchangeRates(gomock.Any()).AnyTimes().Return(tc.rates, nil)
+
+			conversion, err := NewConverter(store, 0).Convert(context.Background(), tc.amount, tc.from, tc.to)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.conversion, conversion)
+		})
+	}
+}
+
+func TestConvertSameCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockExchangeRateStore(ctrl)
+	store.EXPECT().ListExchangeRates(gomock.Any()).Times(0)
+
+	conversion, err := NewConverter(store, 0).Convert(context.Background(), 10000, util.USD, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, int64(10000), conversion.ToAmount)
+	require.Equal(t, "1", conversion.Rate)
+}
+
+func TestConverterCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockExchangeRateStore(ctrl)
+	store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(randomRates(time.Now()), nil)
+
+	converter := NewConverter(store, 0)
+	for i := 0; i < 3; i++ {
+		_, err := converter.Convert(context.Background(), 10000, util.USD, util.EUR)
+		require.NoError(t, err)
+	}
+
+	// a failed reload keeps the rates read before
+	converter.loadedAt = time.Now().Add(-cacheTTL)
+	store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+	conversion, err := converter.Convert(context.Background(), 10000, util.USD, util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, int64(9240), conversion.ToAmount)
+}
@@ -0,0 +1,82 @@
+package fx
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const ecbEndpoint = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECB fetches the euro reference rates the European Central Bank publishes every working day.
+// They need no key, but only cover about 30 currencies.
+type ECB struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewECB() *ECB {
+	return &ECB{
+		endpoint: ecbEndpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ecbEnvelope is the document of the reference rates of a day, e.g.
+// <Cube><Cube time="2026-10-15"><Cube currency="USD" rate="1.0823"/>...</Cube></Cube>.
+type ecbEnvelope struct {
+	Cube struct {
+		Day struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func (provider *ECB) Latest(ctx context.Context) (Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.endpoint, nil)
+	if err != nil {
+		return Rates{}, err
+	}
+	rsp, err := provider.client.Do(req)
+	if err != nil {
+		return Rates{}, fmt.Errorf("failed to fetch the rates of the ecb: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		rspBody, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+		return Rates{}, fmt.Errorf("the ecb answered with status %d: %s", rsp.StatusCode, rspBody)
+	}
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(rsp.Body).Decode(&envelope); err != nil {
+		return Rates{}, fmt.Errorf("failed to decode the rates of the ecb: %w", err)
+	}
+
+	day := envelope.Cube.Day
+	// the rates are those of the day, the ecb doesn't tell the time it published them
+	publishedAt, err := time.Parse("2006-01-02", day.Time)
+	if err != nil {
+		return Rates{}, fmt.Errorf("invalid day of the rates of the ecb %q: %w", day.Time, err)
+	}
+	rates := Rates{
+		Source:      ProviderECB,
+		Base:        "EUR",
+		PublishedAt: publishedAt,
+		Nanos:       make(map[string]int64, len(day.Rates)),
+	}
+	for _, rate := range day.Rates {
+		nanos, err := ParseRate(rate.Rate)
+		if err != nil {
+			return Rates{}, fmt.Errorf("invalid rate of the ecb for %s: %w", rate.Currency, err)
+		}
+		rates.Nanos[rate.Currency] = nanos
+	}
+	return rates, nil
+}
@@ -0,0 +1,92 @@
+// Package fx fetches the exchange rates of a provider, the European Central Bank or Open Exchange
+// Rates, and converts the amounts between currencies at the rates the bank stored. The rates are
+// fixed point: a rate of 1.0823 is 1_082_300_000 nanos.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// RateScale is the nanos of a rate of 1.
+const RateScale = 1_000_000_000
+
+// The providers of FX_PROVIDER.
+const (
+	ProviderECB               = "ecb"
+	ProviderOpenExchangeRates = "openexchangerates"
+)
+
+var (
+	// ErrNoRate is returned for a conversion between currencies the stored rates don't link.
+	ErrNoRate = errors.New("no exchange rate between the currencies")
+	// ErrStaleRate is returned for a conversion at rates fetched longer than FX_RATE_MAX_AGE ago.
+	ErrStaleRate = errors.New("exchange rates are stale")
+	// ErrInvalidRate is returned by ParseRate for a value that is no positive decimal number.
+	ErrInvalidRate = errors.New("invalid exchange rate")
+)
+
+// Rates are the exchange rates a provider published at once, against its base currency.
+type Rates struct {
+	Source      string
+	Base        string
+	PublishedAt time.Time
+	// Nanos holds, by currency, the billionths of a unit of it a unit of Base buys.
+	Nanos map[string]int64
+}
+
+// Provider is where the refresh task fetches the exchange rates.
+type Provider interface {
+	Latest(ctx context.Context) (Rates, error)
+}
+
+// NewProvider creates the provider chosen by FX_PROVIDER, the European Central Bank by default.
+func NewProvider(config util.Config) (Provider, error) {
+	switch config.FXProvider {
+	case ProviderECB, "":
+		return NewECB(), nil
+	case ProviderOpenExchangeRates:
+		if config.FXAppID == "" {
+			return nil, errors.New("open exchange rates needs FX_APP_ID")
+		}
+		return NewOpenExchangeRates(config.FXAppID), nil
+	}
+	return nil, fmt.Errorf("unknown fx provider %q", config.FXProvider)
+}
+
+// ParseRate reads value, a decimal number like 1.0823 or 1.2e-5, as nanos, rounded to the
+// nearest one.
+func ParseRate(value string) (int64, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || strings.Contains(value, "/") || rate.Sign() <= 0 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidRate, value)
+	}
+	nanos := roundedQuotient(new(big.Int).Mul(rate.Num(), big.NewInt(RateScale)), rate.Denom())
+	if !nanos.IsInt64() || nanos.Sign() <= 0 {
+		return 0, fmt.Errorf("%w %q: out of range", ErrInvalidRate, value)
+	}
+	return nanos.Int64(), nil
+}
+
+// FormatRate writes nanos as a decimal number, without the trailing zeros: 1.0823 for 1_082_300_000.
+func FormatRate(nanos int64) string {
+	units := nanos / RateScale
+	fraction := strings.TrimRight(fmt.Sprintf("%09d", nanos%RateScale), "0")
+	if fraction == "" {
+		return fmt.Sprintf("%d", units)
+	}
+	return fmt.Sprintf("%d.%s", units, fraction)
+}
+
+// roundedQuotient divides the positive numerator by the positive denominator, rounding half up.
+func roundedQuotient(numerator, denominator *big.Int) *big.Int {
+	doubled := new(big.Int).Lsh(numerator, 1)
+	doubled.Add(doubled, denominator)
+	return doubled.Quo(doubled, new(big.Int).Lsh(denominator, 1))
+}
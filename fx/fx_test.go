@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		value string
+		nanos int64
+		valid bool
+	}{
+		{value: "1.0823", nanos: 1_082_300_000, valid: true},
+		{value: "1", nanos: RateScale, valid: true},
+		{value: "162.37", nanos: 162_370_000_000, valid: true},
+		{value: "1.2e-5", nanos: 12_000, valid: true},
+		{value: "0.0000000015", nanos: 2, valid: true},
+		{value: "0.0000000004"},
+		{value: "0"},
+		{value: "-1.5"},
+		{value: "1/3"},
+		{value: "abc"},
+		{value: ""},
+		{value: "99999999999"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			nanos, err := ParseRate(tc.value)
+			if !tc.valid {
+				require.ErrorIs(t, err, ErrInvalidRate)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.nanos, nanos)
+		})
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	require.Equal(t, "1.0823", FormatRate(1_082_300_000))
+	require.Equal(t, "1", FormatRate(RateScale))
+	require.Equal(t, "162.37", FormatRate(162_370_000_000))
+	require.Equal(t, "0.000012", FormatRate(12_000))
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(util.Config{})
+	require.NoError(t, err)
+	require.IsType(t, &ECB{}, provider)
+
+	_, err = NewProvider(util.Config{FXProvider: ProviderOpenExchangeRates})
+	require.Error(t, err)
+
+	provider, err = NewProvider(util.Config{FXProvider: ProviderOpenExchangeRates, FXAppID: "app"})
+	require.NoError(t, err)
+	require.IsType(t, &OpenExchangeRates{}, provider)
+
+	_, err = NewProvider(util.Config{FXProvider: "bank-of-nowhere"})
+	require.Error(t, err)
+}
+
+func TestECBLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2026-10-15">
+			<Cube currency="USD" rate="1.0823"/>
+			<Cube currency="JPY" rate="162.37"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+	}))
+	defer server.Close()
+
+	provider := NewECB()
+	provider.endpoint = server.URL
+	rates, err := provider.Latest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ProviderECB, rates.Source)
+	require.Equal(t, "EUR", rates.Base)
+	require.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), rates.PublishedAt)
+	require.Equal(t, map[string]int64{"USD": 1_082_300_000, "JPY": 162_370_000_000}, rates.Nanos)
+}
+
+func TestECBLatestFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	provider := NewECB()
+	provider.endpoint = server.URL
+	_, err := provider.Latest(context.Background())
+	require.ErrorContains(t, err, "503")
+}
+
+func TestOpenExchangeRatesLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("app_id") != "app" {
+			http.Error(w, `{"error": true, "message": "invalid_app_id"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"timestamp": 1792144800, "base": "USD", "rates": {"EUR": 0.923958, "CAD": 1.3712, "USD": 1}}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenExchangeRates("app")
+	provider.endpoint = server.URL
+	rates, err := provider.Latest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ProviderOpenExchangeRates, rates.Source)
+	require.Equal(t, "USD", rates.Base)
+	require.Equal(t, time.Unix(1792144800, 0).UTC(), rates.PublishedAt)
+	require.Equal(t, map[string]int64{"EUR": 923_958_000, "CAD": 1_371_200_000, "USD": RateScale}, rates.Nanos)
+
+	provider = NewOpenExchangeRates("other")
+	provider.endpoint = server.URL
+	_, err = provider.Latest(context.Background())
+	require.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,75 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const openExchangeRatesEndpoint = "https://openexchangerates.org/api/latest.json"
+
+// OpenExchangeRates fetches the hourly rates of Open Exchange Rates, against the dollar, which
+// cover about 170 currencies.
+type OpenExchangeRates struct {
+	appID    string
+	endpoint string
+	client   *http.Client
+}
+
+func NewOpenExchangeRates(appID string) *OpenExchangeRates {
+	return &OpenExchangeRates{
+		appID:    appID,
+		endpoint: openExchangeRatesEndpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type openExchangeRatesResponse struct {
+	Timestamp int64                  `json:"timestamp"`
+	Base      string                 `json:"base"`
+	Rates     map[string]json.Number `json:"rates"`
+}
+
+func (provider *OpenExchangeRates) Latest(ctx context.Context) (Rates, error) {
+	endpoint := provider.endpoint + "?" + url.Values{"app_id": {provider.appID}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Rates{}, err
+	}
+	rsp, err := provider.client.Do(req)
+	if err != nil {
+		return Rates{}, fmt.Errorf("failed to fetch the rates of open exchange rates: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		rspBody, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+		return Rates{}, fmt.Errorf("open exchange rates answered with status %d: %s", rsp.StatusCode, rspBody)
+	}
+	var latest openExchangeRatesResponse
+	// the rates stay json.Number, so that they don't go through a float64
+	decoder := json.NewDecoder(rsp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&latest); err != nil {
+		return Rates{}, fmt.Errorf("failed to decode the rates of open exchange rates: %w", err)
+	}
+
+	rates := Rates{
+		Source:      ProviderOpenExchangeRates,
+		Base:        latest.Base,
+		PublishedAt: time.Unix(latest.Timestamp, 0).UTC(),
+		Nanos:       make(map[string]int64, len(latest.Rates)),
+	}
+	for currency, rate := range latest.Rates {
+		nanos, err := ParseRate(rate.String())
+		if err != nil {
+			return Rates{}, fmt.Errorf("invalid rate of open exchange rates for %s: %w", currency, err)
+		}
+		rates.Nanos[currency] = nanos
+	}
+	return rates, nil
+}
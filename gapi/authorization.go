@@ -2,11 +2,18 @@ package gapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
+	db "github.com/backendmaster/simple_bank/db/sqlc"
 	"github.com/backendmaster/simple_bank/token"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -14,7 +21,31 @@ const (
 	authorizationType   = "bearer"
 )
 
+// authorizeUser authenticates the access token of the call. The impersonation tokens are also
+// checked against the store, so that a revoked one is refused, and their calls are logged with
+// both identities.
 func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	payload, err := server.accessTokenPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Impersonated() {
+		if err := db.CheckImpersonation(ctx, server.store, payload.ID); err != nil {
+			return nil, err
+		}
+		method, _ := grpc.Method(ctx)
+		log.Info().Str("protocol", "grpc").
+			Str("user", payload.Username).
+			Str("impersonator", payload.Impersonator).
+			Str("impersonation id", payload.ID.String()).
+			Str("method", method).
+			Msg("impersonated request")
+	}
+	return payload, nil
+}
+
+// accessTokenPayload verifies the access token in the metadata of the call.
+func (server *Server) accessTokenPayload(ctx context.Context) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("missing metadata")
@@ -43,3 +74,34 @@ func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error)
 	}
 	return payload, nil
 }
+
+// authorizeAccount returns the status error to answer with unless the account exists and belongs to the caller.
+func (server *Server) authorizeAccount(ctx context.Context, payload *token.Payload, accountID int64) error {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return status.Errorf(codes.NotFound, "account not found %s", err)
+		}
+		return status.Errorf(internalCode(err), "failed to get account %s", err)
+	}
+	if account.Owner != payload.Username {
+		return permissionDeniedError(fmt.Errorf("account doesn't belong to the authenticated user"))
+	}
+	return nil
+}
+
+// AuditActor is the user of the audit log of a call and the admin impersonating them, or "" when
+// it has no valid access token. A revoked impersonation is still recorded.
+func (server *Server) AuditActor(ctx context.Context) (string, string) {
+	payload, err := server.accessTokenPayload(ctx)
+	if err != nil {
+		return "", ""
+	}
+	return payload.Username, payload.Impersonator
+}
+
+// HttpAuditActor is the AuditActor of a request to the gateway.
+func (server *Server) HttpAuditActor(req *http.Request) (string, string) {
+	md := metadata.Pairs(authorizationHeader, req.Header.Get(authorizationHeader))
+	return server.AuditActor(metadata.NewIncomingContext(req.Context(), md))
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuthorizeImpersonation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		impersonation db.Impersonation
+		err           error
+		checkErr      func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:          "Revoked",
+			impersonation: db.Impersonation{RevokedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrImpersonationRevoked)
+			},
+		},
+		{
+			name: "NotFound",
+			err:  db.ErrRecordNotFound,
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrImpersonationRevoked)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			accessToken, payload, err := server.tokenMaker.CreateImpersonationToken("alice", util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+			require.NoError(t, err)
+			store.EXPECT().
+				GetImpersonation(gomock.Any(), gomock.Eq(payload.ID)).
+				Times(1).
+				Return(tc.impersonation, tc.err)
+
+			md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationType, accessToken))
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			_, err = server.authorizeUser(ctx)
+			tc.checkErr(t, err)
+
+			// the audit logs record both identities, revoked or not
+			user, impersonator := server.AuditActor(ctx)
+			require.Equal(t, "alice", user)
+			require.Equal(t, "admin", impersonator)
+		})
+	}
+}
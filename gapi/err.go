@@ -1,6 +1,13 @@
 package gapi
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/i18n"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -13,15 +20,89 @@ func FieldViolation(filed string, err error) *errdetails.BadRequest_FieldViolati
 	}
 }
 
-func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) error {
+// invalidArgumentError is the InvalidArgument error of violations, translated in locale.
+func invalidArgumentError(locale string, violations []*errdetails.BadRequest_FieldViolation) error {
+	for _, violation := range violations {
+		violation.Description = i18n.T(locale, violation.Description)
+	}
 	badRequest := &errdetails.BadRequest{FieldViolations: violations}
-	statusInvalid := status.New(codes.InvalidArgument, "invalid parameters")
+	statusInvalid := status.New(codes.InvalidArgument, i18n.T(locale, "validation.invalid_parameters"))
 
 	statusDetails, _ := statusInvalid.WithDetails(badRequest)
 
 	return statusDetails.Err()
 }
 
+// validator is implemented by every request generated with protoc-gen-validate rules.
+type validator interface {
+	ValidateAll() error
+}
+
+type fieldValidationError interface {
+	error
+	Field() string
+	Reason() string
+}
+
+// validateRequest runs the proto validation rules of req, and returns the InvalidArgument error
+// of the rules it breaks in the locale of the caller.
+func (server *Server) validateRequest(ctx context.Context, req validator) error {
+	err := req.ValidateAll()
+	if err == nil {
+		return nil
+	}
+	locale := server.locale(ctx)
+	return invalidArgumentError(locale, fieldViolations(locale, err))
+}
+
+// fieldViolations turns every rule broken in err, the error of a proto validation, into a field
+// violation. The fields of the users get the messages the REST api has for them; the reasons of
+// the other fields are only in English, and just tell the field is invalid in other locales.
+func fieldViolations(locale string, err error) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	var multiErr interface{ AllErrors() []error }
+	if errors.As(err, &multiErr) {
+		errs = multiErr.AllErrors()
+	}
+
+	for _, err := range errs {
+		var fieldErr fieldValidationError
+		if !errors.As(err, &fieldErr) {
+			violations = append(violations, FieldViolation("", err))
+			continue
+		}
+		field := protoFieldName(fieldErr.Field())
+		description := fieldErr.Reason()
+		if rule, ok := userFieldRules[field]; ok {
+			description = i18n.Rule(locale, field, rule, "", "")
+		} else if locale != i18n.English {
+			description = i18n.Rule(locale, field, "invalid", "", "")
+		}
+		violations = append(violations, FieldViolation(field, errors.New(description)))
+	}
+	return violations
+}
+
+// protoFieldName converts the go field name reported by the generated validators (FullName)
+// back to the proto field name (full_name) that clients send.
+func protoFieldName(field string) string {
+	var name strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				name.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
+
 func unauthenticationError(err error) error {
 	return status.Errorf(codes.Unauthenticated, "unauthentication err %s", err)
 }
@@ -29,3 +110,13 @@ func unauthenticationError(err error) error {
 func permissionDeniedError(err error) error {
 	return status.Errorf(codes.PermissionDenied, "request user info not allowed err %s", err)
 }
+
+// internalCode is the code of an unexpected error: Unavailable when the store failed fast because
+// the database is down, or refused to move money for maintenance, so that clients know to retry
+// later, and Internal otherwise.
+func internalCode(err error) codes.Code {
+	if errors.Is(err, db.ErrStoreUnavailable) || errors.Is(err, db.ErrTransfersBlocked) {
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
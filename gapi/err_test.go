@@ -0,0 +1,104 @@
+package gapi
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValidateRequest(t *testing.T) {
+	testCases := []struct {
+		name   string
+		locale string
+		req    validator
+		fields []string
+		// descriptions are those of the fields, when checked
+		descriptions []string
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateUserRequest{
+				Username: util.RandomOwnerName(),
+				FullName: "Alice Bob",
+				Email:    util.RandomEmail(),
+				Password: util.RandomString(6),
+			},
+		},
+		{
+			name: "InvalidFields",
+			req: &pb.CreateUserRequest{
+				Username: "bad#user",
+				FullName: "Alice1",
+				Email:    "invalid-email",
+				Password: "123",
+			},
+			fields: []string{"username", "full_name", "email", "password"},
+		},
+		{
+			name:   "TraditionalChinese",
+			locale: i18n.TraditionalChinese,
+			req: &pb.ListAccountsRequest{
+				PageSize: 0,
+			},
+			fields:       []string{"page_size"},
+			descriptions: []string{"每頁筆數無效"},
+		},
+		{
+			name:   "UserFieldInTraditionalChinese",
+			locale: i18n.TraditionalChinese,
+			req: &pb.UpdateUserRequest{
+				Username: "bad#user",
+			},
+			fields:       []string{"username"},
+			descriptions: []string{"使用者名稱必須是 3 到 10 個小寫字母、數字或底線"},
+		},
+		{
+			name: "UnsetOptionalFields",
+			req: &pb.UpdateUserRequest{
+				Username: util.RandomOwnerName(),
+			},
+		},
+		{
+			name: "InvalidOptionalField",
+			req: &pb.UpdateUserRequest{
+				Username: util.RandomOwnerName(),
+				Email:    new(string),
+			},
+			fields: []string{"email"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			locale := tc.locale
+			if locale == "" {
+				locale = i18n.English
+			}
+			violations := fieldViolations(locale, tc.req.ValidateAll())
+			require.Len(t, violations, len(tc.fields))
+			for i, violation := range violations {
+				require.Equal(t, tc.fields[i], violation.GetField())
+				require.NotEmpty(t, violation.GetDescription())
+				if tc.descriptions != nil {
+					require.Equal(t, tc.descriptions[i], violation.GetDescription())
+				}
+			}
+		})
+	}
+}
+
+func TestInternalCode(t *testing.T) {
+	require.Equal(t, codes.Internal, internalCode(sql.ErrConnDone))
+	require.Equal(t, codes.Unavailable, internalCode(db.ErrStoreUnavailable))
+	require.Equal(t, codes.Unavailable, internalCode(fmt.Errorf("get user: %w", db.ErrStoreUnavailable)))
+	require.Equal(t, codes.Unavailable, internalCode(db.ErrTransfersBlocked))
+}
@@ -0,0 +1,119 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/tracing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const internalErrorMessage = "internal server error"
+
+type gatewayFieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type gatewayErrorResponse struct {
+	Err       string                  `json:"err"`
+	Code      string                  `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Details   []gatewayFieldViolation `json:"details,omitempty"`
+}
+
+// NewGatewayMux creates the grpc-gateway mux with the same response conventions as the gin api:
+// snake_case fields, every field present, and errors as {"err": ...} bodies.
+func NewGatewayMux() *runtime.ServeMux {
+	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	})
+
+	return runtime.NewServeMux(
+		jsonOption,
+		runtime.WithErrorHandler(GatewayErrorHandler),
+		runtime.WithMetadata(forwardRequestID),
+		runtime.WithMetadata(recordRoute),
+	)
+}
+
+// GatewayErrorHandler writes grpc errors as json, hiding the message of internal errors from the client.
+func GatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, res http.ResponseWriter, req *http.Request, err error) {
+	httpStatus := 0
+	var customStatus *runtime.HTTPStatusError
+	if errors.As(err, &customStatus) {
+		httpStatus = customStatus.HTTPStatus
+		err = customStatus.Err
+	}
+
+	st := status.Convert(err)
+	if httpStatus == 0 {
+		httpStatus = runtime.HTTPStatusFromCode(st.Code())
+	}
+
+	requestID := req.Header.Get(requestIDHeader)
+	rsp := gatewayErrorResponse{
+		Err:       st.Message(),
+		Code:      st.Code().String(),
+		RequestID: requestID,
+	}
+
+	switch st.Code() {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		log.Error().Err(err).
+			Str("request id", requestID).
+			Str("request path", req.RequestURI).
+			Msg("internal error from gateway call")
+		rsp.Err = internalErrorMessage
+	}
+
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				rsp.Details = append(rsp.Details, gatewayFieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	body, err := json.Marshal(rsp)
+	if err != nil {
+		httpStatus = http.StatusInternalServerError
+		body = []byte(`{"err":"` + internalErrorMessage + `"}`)
+	}
+
+	res.Header().Del("Trailer")
+	res.Header().Del("Transfer-Encoding")
+	res.Header().Set("Content-Type", "application/json")
+	if st.Code() == codes.Unauthenticated {
+		res.Header().Set("WWW-Authenticate", authorizationType)
+	}
+	res.WriteHeader(httpStatus)
+	res.Write(body)
+}
+
+// recordRoute labels the metrics and the span of a gateway request with its path pattern, e.g. /v1/get_user/{username}.
+func recordRoute(ctx context.Context, req *http.Request) metadata.MD {
+	if pattern, ok := runtime.HTTPPathPattern(ctx); ok {
+		metrics.SetRoute(req, pattern)
+		tracing.SetRoute(req, pattern)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGatewayErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		checkResponse func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse)
+	}{
+		{
+			name: "InternalErrorHidden",
+			err:  status.Errorf(codes.Internal, "failed to create user: pq: connection refused"),
+			checkResponse: func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, internalErrorMessage, rsp.Err)
+				require.Equal(t, codes.Internal.String(), rsp.Code)
+			},
+		},
+		{
+			name: "NonStatusError",
+			err:  errors.New("some plain error"),
+			checkResponse: func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, internalErrorMessage, rsp.Err)
+			},
+		},
+		{
+			name: "NotFound",
+			err:  status.Errorf(codes.NotFound, "user not found"),
+			checkResponse: func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, "user not found", rsp.Err)
+				require.Empty(t, rsp.Details)
+			},
+		},
+		{
+			name: "InvalidArgument",
+			err: invalidArgumentError(i18n.English, []*errdetails.BadRequest_FieldViolation{
+				FieldViolation("username", errors.New("invalid username")),
+			}),
+			checkResponse: func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Len(t, rsp.Details, 1)
+				require.Equal(t, "username", rsp.Details[0].Field)
+				require.Equal(t, "invalid username", rsp.Details[0].Description)
+			},
+		},
+		{
+			name: "Unauthenticated",
+			err:  unauthenticationError(errors.New("missing token")),
+			checkResponse: func(recorder *httptest.ResponseRecorder, rsp gatewayErrorResponse) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Equal(t, authorizationType, recorder.Header().Get("WWW-Authenticate"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/v1/create_user", nil)
+			require.NoError(t, err)
+			request.Header.Set(requestIDHeader, "test-request-id")
+
+			GatewayErrorHandler(context.Background(), NewGatewayMux(), nil, recorder, request, tc.err)
+
+			require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			var rsp gatewayErrorResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+			require.Equal(t, "test-request-id", rsp.RequestID)
+			tc.checkResponse(recorder, rsp)
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var requestID string
+	handler := RequestID(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID = req.Header.Get(requestIDHeader)
+	}))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	handler.ServeHTTP(recorder, request)
+	require.NotEmpty(t, requestID)
+	require.Equal(t, requestID, recorder.Header().Get(requestIDHeader))
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeader, "from-client")
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, "from-client", requestID)
+	require.Equal(t, "from-client", recorder.Header().Get(requestIDHeader))
+}
@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"net/http"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/tracing"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// GrpcWebHandler lets browser clients call grpcServer with the gRPC-Web protocol on the same port as
+// the json gateway; every request that is not gRPC-Web (or its CORS preflight) is passed to gateway.
+func GrpcWebHandler(grpcServer *grpc.Server, gateway http.Handler, allowedOrigins []string) http.Handler {
+	wrappedServer := grpcweb.WrapServer(
+		grpcServer,
+		grpcweb.WithOriginFunc(allowOrigin(allowedOrigins)),
+	)
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if wrappedServer.IsGrpcWebRequest(req) || wrappedServer.IsAcceptableGrpcCorsRequest(req) {
+			metrics.SetRoute(req, req.URL.Path)
+			tracing.SetRoute(req, req.URL.Path)
+			wrappedServer.ServeHTTP(res, req)
+			return
+		}
+		gateway.ServeHTTP(res, req)
+	})
+}
+
+func allowOrigin(allowedOrigins []string) func(origin string) bool {
+	return func(origin string) bool {
+		for _, allowed := range allowedOrigins {
+			if allowed == "*" || allowed == origin {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+const allowedOrigin = "http://localhost:3000"
+
+func newTestGrpcWebHandler(t *testing.T, gateway http.Handler) http.Handler {
+	grpcServer := grpc.NewServer()
+	pb.RegisterSimpleBankServer(grpcServer, newTestServer(t, nil))
+	return GrpcWebHandler(grpcServer, gateway, []string{allowedOrigin})
+}
+
+func TestGrpcWebHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildRequest  func(t *testing.T) *http.Request
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, gatewayCalled bool)
+	}{
+		{
+			name: "GrpcWebCall",
+			buildRequest: func(t *testing.T) *http.Request {
+				body, err := proto.Marshal(&pb.GetUserRequest{Username: "alice"})
+				require.NoError(t, err)
+
+				frame := make([]byte, 5, 5+len(body))
+				binary.BigEndian.PutUint32(frame[1:], uint32(len(body)))
+				frame = append(frame, body...)
+
+				request, err := http.NewRequest(http.MethodPost, "/pb.simple_bank/GetUser", bytes.NewReader(frame))
+				require.NoError(t, err)
+				request.Header.Set("Content-Type", "application/grpc-web+proto")
+				request.Header.Set("Origin", allowedOrigin)
+				return request
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, gatewayCalled bool) {
+				require.False(t, gatewayCalled)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, allowedOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+				// no authorization header, so the call itself is rejected by the grpc handler
+				require.Equal(t, strconv.Itoa(int(codes.Unauthenticated)), recorder.Header().Get("Grpc-Status"))
+			},
+		},
+		{
+			name: "PreflightAllowedOrigin",
+			buildRequest: func(t *testing.T) *http.Request {
+				return newPreflightRequest(t, allowedOrigin)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, gatewayCalled bool) {
+				require.False(t, gatewayCalled)
+				require.Equal(t, allowedOrigin, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+		{
+			name: "PreflightDisallowedOrigin",
+			buildRequest: func(t *testing.T) *http.Request {
+				return newPreflightRequest(t, "http://evil.example.com")
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, gatewayCalled bool) {
+				require.False(t, gatewayCalled)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+		{
+			name: "GatewayRequest",
+			buildRequest: func(t *testing.T) *http.Request {
+				request, err := http.NewRequest(http.MethodPost, "/v1/login_user", nil)
+				require.NoError(t, err)
+				request.Header.Set("Content-Type", "application/json")
+				return request
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, gatewayCalled bool) {
+				require.True(t, gatewayCalled)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayCalled := false
+			gateway := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				gatewayCalled = true
+			})
+
+			handler := newTestGrpcWebHandler(t, gateway)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, tc.buildRequest(t))
+			tc.checkResponse(t, recorder, gatewayCalled)
+		})
+	}
+}
+
+func newPreflightRequest(t *testing.T, origin string) *http.Request {
+	request, err := http.NewRequest(http.MethodOptions, "/pb.simple_bank/GetUser", nil)
+	require.NoError(t, err)
+	request.Header.Set("Origin", origin)
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	request.Header.Set("Access-Control-Request-Headers", "x-grpc-web, content-type, authorization")
+	return request
+}
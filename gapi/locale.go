@@ -0,0 +1,41 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/backendmaster/simple_bank/i18n"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	acceptLanguageHeader            = "accept-language"
+	grpcGatewayAcceptLanguageHeader = "grpcgateway-accept-language"
+)
+
+// userFieldRules are the binding tags of the REST api checking the fields of the users like the
+// proto rules do, whose messages the violations of those fields get.
+var userFieldRules = map[string]string{
+	"username":  "username",
+	"full_name": "full_name",
+	"password":  "password",
+	"email":     "user_email",
+}
+
+// locale returns the locale the errors of the call are written in: the one of its accept-language
+// metadata, which the gateway forwards from the header, else the one of the preferences of the
+// caller, else English.
+func (server *Server) locale(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range []string{acceptLanguageHeader, grpcGatewayAcceptLanguageHeader} {
+			if values := md.Get(key); len(values) > 0 {
+				if locale, ok := i18n.Negotiate(values[0]); ok {
+					return locale
+				}
+			}
+		}
+	}
+	if payload, err := server.accessTokenPayload(ctx); err == nil && i18n.IsSupported(payload.Locale) {
+		return payload.Locale
+	}
+	return i18n.English
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLocale(t *testing.T) {
+	server := newTestServer(t, nil)
+	accessToken, _, err := server.tokenMaker.CreateToken(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, i18n.TraditionalChinese, time.Minute)
+	require.NoError(t, err)
+	bearerToken := fmt.Sprintf("%s %s", authorizationType, accessToken)
+
+	testCases := []struct {
+		name   string
+		md     metadata.MD
+		locale string
+	}{
+		{name: "NoMetadata", locale: i18n.English},
+		{name: "Grpc", md: metadata.Pairs(acceptLanguageHeader, "zh-TW"), locale: i18n.TraditionalChinese},
+		{name: "Gateway", md: metadata.Pairs(grpcGatewayAcceptLanguageHeader, "zh-Hant, en;q=0.5"), locale: i18n.TraditionalChinese},
+		{name: "Preference", md: metadata.Pairs(authorizationHeader, bearerToken), locale: i18n.TraditionalChinese},
+		{
+			name:   "HeaderOverPreference",
+			md:     metadata.Pairs(authorizationHeader, bearerToken, acceptLanguageHeader, "en-GB"),
+			locale: i18n.English,
+		},
+		{name: "UnsupportedLanguage", md: metadata.Pairs(acceptLanguageHeader, "fr"), locale: i18n.English},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+			require.Equal(t, tc.locale, server.locale(ctx))
+		})
+	}
+}
+
+func TestValidateRequestInLocale(t *testing.T) {
+	server := newTestServer(t, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(acceptLanguageHeader, "zh-TW"))
+
+	err := server.validateRequest(ctx, &pb.CreateUserRequest{
+		Username: "bad#user",
+		FullName: "Alice Bob",
+		Email:    util.RandomEmail(),
+		Password: util.RandomString(6),
+	})
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "參數無效", st.Message())
+	require.Len(t, st.Details(), 1)
+}
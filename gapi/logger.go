@@ -5,37 +5,65 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
-func GrpcLogger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	timeNow := time.Now()
-	statusCode := codes.Unknown
-	logger := log.Info()
-	result, err := handler(ctx, req)
-	if st, ok := status.FromError(err); ok {
-		statusCode = st.Code()
+// GrpcLogger logs every unary call, along with its request when it fails. Sensitive fields
+// of the request are masked by redactor.
+func GrpcLogger(redactor *util.Redactor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		timeNow := time.Now()
+		statusCode := codes.Unknown
+		logger := log.Info()
+		result, err := handler(ctx, req)
+		if st, ok := status.FromError(err); ok {
+			statusCode = st.Code()
+		}
+		if err != nil {
+			logger = log.Error().Err(err).RawJSON("request", redactRequest(redactor, req))
+		}
+		duration := time.Since(timeNow)
+		logger.Str("protocol", "grpc").
+			Str("request id", extractRequestID(ctx)).
+			Str("method", info.FullMethod).
+			Dur("duration", duration).
+			Int("status code", int(statusCode)).
+			Str("status text", statusCode.String()).
+			Msg("receive request")
+
+		return result, err
+	}
+}
+
+func redactRequest(redactor *util.Redactor, req interface{}) []byte {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return []byte(`null`)
 	}
+	body, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
 	if err != nil {
-		logger = log.Error().Err(err)
+		return []byte(`null`)
 	}
-	duration := time.Since(timeNow)
-	logger.Str("protocol", "grpc").
-		Str("method", info.FullMethod).
-		Dur("duration", duration).
-		Int("status code", int(statusCode)).
-		Str("status text", statusCode.String()).
-		Msg("receive request")
-
-	return result, err
+	return redactor.RedactJSON(body)
 }
 
+// maxLoggedBody bounds how much of a failed response is kept for the logs. Error bodies are small
+// json documents, while successful responses can be arbitrarily large and are never kept.
+const maxLoggedBody = 1024
+
+// ResponseRecorder records the status code and size of a response, keeping the start of the body
+// only when the response is a client or server error.
 type ResponseRecorder struct {
 	http.ResponseWriter
 	StatusCode int
+	Size       int
 	Body       []byte
 }
 
@@ -45,11 +73,27 @@ func (rec *ResponseRecorder) WriteHeader(statusCode int) {
 }
 
 func (rec *ResponseRecorder) Write(body []byte) (int, error) {
-	rec.Body = body
-	return rec.ResponseWriter.Write(body)
+	n, err := rec.ResponseWriter.Write(body)
+	rec.Size += n
+	if rec.StatusCode >= http.StatusBadRequest && len(rec.Body) < maxLoggedBody {
+		keep := body[:n]
+		if len(keep) > maxLoggedBody-len(rec.Body) {
+			keep = keep[:maxLoggedBody-len(rec.Body)]
+		}
+		rec.Body = append(rec.Body, keep...)
+	}
+	return n, err
 }
 
-func HttpLogger(handler http.Handler) http.Handler {
+func (rec *ResponseRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// HttpLogger logs every http request with its status and response size. 5xx responses are logged
+// as errors and 4xx as warnings, both with their body, whose sensitive fields are masked by redactor.
+func HttpLogger(redactor *util.Redactor, handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		timeNow := time.Now()
 		rec := &ResponseRecorder{
@@ -59,16 +103,23 @@ func HttpLogger(handler http.Handler) http.Handler {
 		handler.ServeHTTP(rec, req)
 		duration := time.Since(timeNow)
 
-		logger := log.Info()
-		if rec.StatusCode != http.StatusOK {
-			logger = log.Error().Bytes("body", rec.Body)
+		var logger *zerolog.Event
+		switch {
+		case rec.StatusCode >= http.StatusInternalServerError:
+			logger = log.Error().Bytes("body", redactor.RedactJSON(rec.Body))
+		case rec.StatusCode >= http.StatusBadRequest:
+			logger = log.Warn().Bytes("body", redactor.RedactJSON(rec.Body))
+		default:
+			logger = log.Info()
 		}
 		logger.Str("protocol", "http").
+			Str("request id", req.Header.Get(requestIDHeader)).
 			Str("method", req.Method).
 			Str("request path", req.RequestURI).
 			Dur("duration", duration).
 			Int("status code", rec.StatusCode).
 			Str("status text", http.StatusText(rec.StatusCode)).
+			Int("response size", rec.Size).
 			Msg("receive http request")
 	})
 }
@@ -0,0 +1,116 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buffer bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&buffer)
+	t.Cleanup(func() {
+		log.Logger = logger
+	})
+	return &buffer
+}
+
+func TestHttpLoggerRedactsBody(t *testing.T) {
+	logs := captureLogs(t)
+	handler := HttpLogger(util.NewRedactor(nil), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusConflict)
+		res.Write([]byte(`{"err":"email already exists","email":"alice@email.com"}`))
+	}))
+
+	request, err := http.NewRequest(http.MethodPost, "/v1/create_user", nil)
+	require.NoError(t, err)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	require.Contains(t, logs.String(), "email already exists")
+	require.NotContains(t, logs.String(), "alice@email.com")
+}
+
+func TestGrpcLoggerRedactsRequest(t *testing.T) {
+	logs := captureLogs(t)
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.simple_bank/LoginUser"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Errorf(codes.NotFound, "incorrect password")
+	}
+	req := &pb.LoginUserRequest{
+		Username: "alice",
+		Password: "secret123",
+	}
+
+	_, err := GrpcLogger(util.NewRedactor(nil))(context.Background(), req, info, handler)
+	require.Error(t, err)
+
+	require.Contains(t, logs.String(), `"username":"alice"`)
+	require.NotContains(t, logs.String(), "secret123")
+}
+
+func TestHttpLoggerStatusLevels(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		level      string
+		size       string
+		hasBody    bool
+	}{
+		{name: "Created", statusCode: http.StatusCreated, level: "info", size: "20"},
+		{name: "NoContent", statusCode: http.StatusNoContent, level: "info", size: "0"},
+		{name: "Redirect", statusCode: http.StatusFound, level: "info", size: "20"},
+		{name: "NotFound", statusCode: http.StatusNotFound, level: "warn", size: "20", hasBody: true},
+		{name: "Internal", statusCode: http.StatusInternalServerError, level: "error", size: "20", hasBody: true},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			logs := captureLogs(t)
+			handler := HttpLogger(util.NewRedactor(nil), http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(tc.statusCode)
+				res.Write([]byte(`{"err":"some error"}`))
+			}))
+
+			request, err := http.NewRequest(http.MethodGet, "/v1/get_user/alice", nil)
+			require.NoError(t, err)
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			require.Contains(t, logs.String(), `"level":"`+tc.level+`"`)
+			require.Contains(t, logs.String(), `"response size":`+tc.size)
+			if tc.hasBody {
+				require.Contains(t, logs.String(), "some error")
+			} else {
+				require.NotContains(t, logs.String(), "some error")
+			}
+		})
+	}
+}
+
+func TestResponseRecorderBoundsBody(t *testing.T) {
+	rec := &ResponseRecorder{
+		ResponseWriter: httptest.NewRecorder(),
+		StatusCode:     http.StatusOK,
+	}
+	rec.Write(bytes.Repeat([]byte("a"), 4096))
+	require.Empty(t, rec.Body)
+
+	rec.WriteHeader(http.StatusBadRequest)
+	rec.Write(bytes.Repeat([]byte("b"), 4096))
+	rec.Write(bytes.Repeat([]byte("c"), 10))
+	require.Len(t, rec.Body, maxLoggedBody)
+	require.Equal(t, 8202, rec.Size)
+}
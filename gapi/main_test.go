@@ -0,0 +1,52 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		StepUpMaxAge:        5 * time.Minute,
+	}
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	return server
+}
+
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role string, duration time.Duration) context.Context {
+	accessToken, _, err := tokenMaker.CreateToken(username, role, util.DefaultTenant, "", duration)
+	require.NoError(t, err)
+
+	bearerToken := fmt.Sprintf("%s %s", authorizationType, accessToken)
+	md := metadata.MD{
+		authorizationHeader: []string{
+			bearerToken,
+		},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func randomUser(t *testing.T, options ...testfixtures.UserOption) (user db.User, password string) {
+	fixture := testfixtures.NewUser(t, options...)
+	user = db.User{
+		Username:       fixture.Username,
+		Role:           fixture.Role,
+		HashedPassword: fixture.HashedPassword,
+		FullName:       fixture.FullName,
+		Email:          fixture.Email,
+	}
+	return user, fixture.Password
+}
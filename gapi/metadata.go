@@ -2,7 +2,9 @@ package gapi
 
 import (
 	"context"
+	"strings"
 
+	"github.com/backendmaster/simple_bank/util"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -11,30 +13,60 @@ const (
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	userAgentHeader            = "user-agent"
 	xForwardForHeader          = "x-forwarded-for"
+	// the http gateway forwards them as Grpc-Metadata-X-Device-Fingerprint and
+	// Grpc-Metadata-X-Device-Name
+	deviceFingerprintHeader = "x-device-fingerprint"
+	deviceNameHeader        = "x-device-name"
 )
 
 type Metadata struct {
 	UserAgent string
 	ClientIP  string
+	// Country is the country of FRAUD_COUNTRY_HEADER, empty when it isn't set.
+	Country string
+	// DeviceFingerprint and DeviceName are the device of a login, see the device_fingerprint and
+	// device_name of the http login.
+	DeviceFingerprint string
+	DeviceName        string
 }
 
+// extractMetadata reads the client info the same way for direct grpc calls, grpc-web and the http gateway,
+// whose in-process calls carry the http request's user agent and forwarded-for chain as metadata.
 func (server *Server) extractMetadata(ctx context.Context) *Metadata {
 	mtdt := &Metadata{}
 
+	var forwardedFor []string
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		if userAgent := md.Get(grpcGatewayUserAgentHeader); len(userAgent) > 0 {
+		if userAgent := md.Get(userAgentHeader); len(userAgent) > 0 {
 			mtdt.UserAgent = userAgent[0]
 		}
-		if userAgent := md.Get(userAgentHeader); len(userAgent) > 0 {
+		if userAgent := md.Get(grpcGatewayUserAgentHeader); len(userAgent) > 0 {
 			mtdt.UserAgent = userAgent[0]
 		}
-		if clientIP := md.Get(xForwardForHeader); len(clientIP) > 0 {
-			mtdt.ClientIP = clientIP[0]
+		forwardedFor = md.Get(xForwardForHeader)
+		if fingerprint := md.Get(deviceFingerprintHeader); len(fingerprint) > 0 {
+			mtdt.DeviceFingerprint = fingerprint[0]
+		}
+		if name := md.Get(deviceNameHeader); len(name) > 0 {
+			mtdt.DeviceName = name[0]
+		}
+		if header := server.config.FraudCountryHeader; header != "" {
+			if country := md.Get(header); len(country) > 0 {
+				mtdt.Country = strings.ToUpper(strings.TrimSpace(country[0]))
+			}
 		}
 	}
+
+	remoteAddr := ""
 	if p, ok := peer.FromContext(ctx); ok {
-		mtdt.ClientIP = p.Addr.String()
+		remoteAddr = p.Addr.String()
+	} else if len(forwardedFor) > 0 {
+		// the gateway calls in process, having appended the address of its client to the chain
+		chain := strings.Split(strings.Join(forwardedFor, ","), ",")
+		remoteAddr = strings.TrimSpace(chain[len(chain)-1])
+		forwardedFor = chain[:len(chain)-1]
 	}
+	mtdt.ClientIP = util.ClientIP(forwardedFor, remoteAddr)
 
 	return mtdt
 }
@@ -0,0 +1,117 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	clientAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 54321}
+	proxyAddr := &net.TCPAddr{IP: net.ParseIP("192.168.0.1"), Port: 54321}
+	require.NoError(t, util.SetTrustedProxies([]string{"192.168.0.0/16"}))
+	t.Cleanup(func() { util.SetTrustedProxies(nil) })
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		userAgent string
+		clientIP  string
+	}{
+		{
+			name: "Grpc",
+			ctx: peer.NewContext(
+				metadata.NewIncomingContext(context.Background(), metadata.Pairs(userAgentHeader, "grpc-go/1.51.0")),
+				&peer.Peer{Addr: clientAddr},
+			),
+			userAgent: "grpc-go/1.51.0",
+			clientIP:  "10.0.0.1",
+		},
+		{
+			name: "GrpcBehindProxy",
+			ctx: peer.NewContext(
+				metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+					userAgentHeader, "grpc-go/1.51.0",
+					xForwardForHeader, "198.51.100.2, 203.0.113.7, 192.168.0.2",
+				)),
+				&peer.Peer{Addr: proxyAddr},
+			),
+			userAgent: "grpc-go/1.51.0",
+			clientIP:  "203.0.113.7",
+		},
+		{
+			name: "GrpcForgedForwardedFor",
+			ctx: peer.NewContext(
+				metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+					userAgentHeader, "grpc-go/1.51.0",
+					xForwardForHeader, "203.0.113.7",
+				)),
+				&peer.Peer{Addr: clientAddr},
+			),
+			userAgent: "grpc-go/1.51.0",
+			clientIP:  "10.0.0.1",
+		},
+		{
+			name: "Gateway",
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				grpcGatewayUserAgentHeader, "Mozilla/5.0",
+				userAgentHeader, "grpc-go/1.51.0",
+				xForwardForHeader, "203.0.113.7",
+			)),
+			userAgent: "Mozilla/5.0",
+			clientIP:  "203.0.113.7",
+		},
+		{
+			name: "GatewayBehindProxy",
+			ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				grpcGatewayUserAgentHeader, "Mozilla/5.0",
+				xForwardForHeader, "198.51.100.2, 203.0.113.7, 192.168.0.1",
+			)),
+			userAgent: "Mozilla/5.0",
+			clientIP:  "203.0.113.7",
+		},
+		{
+			name:      "NoMetadata",
+			ctx:       context.Background(),
+			userAgent: "",
+			clientIP:  "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			mtdt := server.extractMetadata(tc.ctx)
+			require.Equal(t, tc.userAgent, mtdt.UserAgent)
+			require.Equal(t, tc.clientIP, mtdt.ClientIP)
+		})
+	}
+}
+
+func TestExtractMetadataCountry(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("cf-ipcountry", "fr"))
+
+	server := newTestServer(t, nil)
+	require.Empty(t, server.extractMetadata(ctx).Country)
+
+	server.config.FraudCountryHeader = "CF-IPCountry"
+	require.Equal(t, "FR", server.extractMetadata(ctx).Country)
+}
+
+func TestExtractMetadataDevice(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		deviceFingerprintHeader, "3f2a9c",
+		deviceNameHeader, "Pixel 8",
+	))
+
+	mtdt := newTestServer(t, nil).extractMetadata(ctx)
+	require.Equal(t, "3f2a9c", mtdt.DeviceFingerprint)
+	require.Equal(t, "Pixel 8", mtdt.DeviceName)
+}
@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDHeader = "x-request-id"
+
+// RequestID makes sure every http request carries a request id, reusing the one
+// sent by the client (or a proxy in front of us) when it is present.
+func RequestID(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+			req.Header.Set(requestIDHeader, requestID)
+		}
+		res.Header().Set(requestIDHeader, requestID)
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// forwardRequestID passes the request id of a gateway call down to the grpc handlers.
+func forwardRequestID(ctx context.Context, req *http.Request) metadata.MD {
+	return metadata.Pairs(requestIDHeader, req.Header.Get(requestIDHeader))
+}
+
+func extractRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if requestID := md.Get(requestIDHeader); len(requestID) > 0 {
+			return requestID[0]
+		}
+	}
+	return ""
+}
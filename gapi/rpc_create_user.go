@@ -2,60 +2,52 @@ package gapi
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/metrics"
 	"github.com/backendmaster/simple_bank/pb"
 	"github.com/backendmaster/simple_bank/util"
-	"github.com/backendmaster/simple_bank/val"
-	"github.com/lib/pq"
+	"github.com/backendmaster/simple_bank/worker"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	if violations := validateCreateUserRequest(req); violations != nil {
-		return nil, invalidArgumentError(violations)
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
 	}
 	hashedPassword, err := util.HashedPassword(req.GetPassword())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to hash password %s", err)
-	}
-	arg := db.CreateUserParams{
-		Username:       req.GetUsername(),
-		HashedPassword: hashedPassword,
-		FullName:       req.GetFullName(),
-		Email:          req.GetEmail(),
+		return nil, status.Errorf(internalCode(err), "failed to hash password %s", err)
+	}
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.GetUsername(),
+			HashedPassword: hashedPassword,
+			FullName:       req.GetFullName(),
+			Email:          req.GetEmail(),
+		},
+		AfterCreate:  worker.AfterCreateUser,
+		Screen:       server.screener.ScreenUser,
+		ReferralCode: strings.ToUpper(req.GetReferralCode()),
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	txResult, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "unique_violation":
-				return nil, status.Errorf(codes.AlreadyExists, "username already exists %s", err)
-			}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "username already exists %s", err)
+		}
+		if errors.Is(err, db.ErrInvalidReferralCode) {
+			return nil, invalidArgumentError(server.locale(ctx), []*errdetails.BadRequest_FieldViolation{FieldViolation("referral_code", err)})
 		}
-		return nil, status.Errorf(codes.Internal, "failed to create user %s", err)
+		return nil, status.Errorf(internalCode(err), "failed to create user %s", err)
 	}
+	metrics.ObserveSignup()
 	rsp := &pb.CreateUserResponse{
-		User: convertUser(user),
+		User: convertUser(txResult.User),
 	}
 	return rsp, nil
 }
-
-func validateCreateUserRequest(req *pb.CreateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
-	if err := val.ValidateUserName(req.GetUsername()); err != nil {
-		violations = append(violations, FieldViolation("username", err))
-	}
-	if err := val.ValidatePassword(req.GetPassword()); err != nil {
-		violations = append(violations, FieldViolation("password", err))
-	}
-	if err := val.ValidateEmail(req.GetEmail()); err != nil {
-		violations = append(violations, FieldViolation("email", err))
-	}
-	if err := val.ValidateFullName(req.GetFullName()); err != nil {
-		violations = append(violations, FieldViolation("fullname", err))
-	}
-	return violations
-}
@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteUser anonymizes the user instead of removing the row, so the ledger stays intact.
+func (server *Server) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticationError(err)
+	}
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if payload.Username != req.GetUsername() && payload.Role != util.AdminRole {
+		return nil, permissionDeniedError(fmt.Errorf("cannot delete other user"))
+	}
+
+	user, err := server.store.DeleteUserTx(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found %s", err)
+		}
+		return nil, status.Errorf(internalCode(err), "failed to delete user %s", err)
+	}
+	rsp := &pb.DeleteUserResponse{
+		User: convertUser(user),
+	}
+	return rsp, nil
+}
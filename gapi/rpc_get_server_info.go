@@ -0,0 +1,19 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/version"
+)
+
+func (server *Server) GetServerInfo(ctx context.Context, req *pb.GetServerInfoRequest) (*pb.GetServerInfoResponse, error) {
+	info := version.Get()
+	rsp := &pb.GetServerInfoResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	}
+	return rsp, nil
+}
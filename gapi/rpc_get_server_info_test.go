@@ -0,0 +1,20 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServerInfoAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	rsp, err := server.GetServerInfo(context.Background(), &pb.GetServerInfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, version.Version, rsp.GetVersion())
+	require.Equal(t, version.Commit, rsp.GetCommit())
+	require.Equal(t, version.BuildTime, rsp.GetBuildTime())
+}
@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticationError(err)
+	}
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if payload.Username != req.GetUsername() && payload.Role != util.AdminRole {
+		return nil, permissionDeniedError(fmt.Errorf("cannot get other user's info"))
+	}
+
+	user, err := server.store.GetUser(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found %s", err)
+		}
+		return nil, status.Errorf(internalCode(err), "failed to get user %s", err)
+	}
+	rsp := &pb.GetUserResponse{
+		User: convertUser(user),
+	}
+	return rsp, nil
+}
@@ -0,0 +1,39 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticationError(err)
+	}
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	cursor, err := pagination.Decode(req.GetCursor())
+	if err != nil {
+		return nil, invalidArgumentError(server.locale(ctx), []*errdetails.BadRequest_FieldViolation{FieldViolation("cursor", err)})
+	}
+
+	page, err := db.ListAccountsPage(ctx, server.store, payload.Username, cursor, req.GetPageSize())
+	if err != nil {
+		return nil, status.Errorf(internalCode(err), "failed to list accounts %s", err)
+	}
+	rsp := &pb.ListAccountsResponse{
+		Accounts:   make([]*pb.Account, len(page.Items)),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for i, account := range page.Items {
+		rsp.Accounts[i] = convertAccount(account)
+	}
+	return rsp, nil
+}
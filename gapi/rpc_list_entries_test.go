@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := db.Account{ID: util.RandomInt(1, 100), Owner: user.Username, Currency: util.USD}
+	entries := []db.Entry{
+		{ID: 7, AccountID: account.ID, Amount: 10},
+		{ID: 9, AccountID: account.ID, Amount: -5},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListEntriesRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListEntriesResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5, Cursor: pagination.Cursor{ID: 3}.Encode()},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListStatementEntriesAfterParams{AccountID: account.ID, AfterID: 3, Limit: 6}
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEntries(), 2)
+				require.Equal(t, int64(-5), res.GetEntries()[1].GetAmount())
+				require.Empty(t, res.GetNextCursor())
+				require.Equal(t, pagination.Cursor{ID: 7, Backward: true}.Encode(), res.GetPrevCursor())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req:  &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, "other", util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "AccountNotFound",
+			req:  &pb.ListEntriesRequest{AccountId: account.ID, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InvalidAccountID",
+			req:  &pb.ListEntriesRequest{AccountId: 0, PageSize: 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListEntriesResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ListEntries(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	payload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, unauthenticationError(err)
+	}
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	cursor, err := pagination.Decode(req.GetCursor())
+	if err != nil {
+		return nil, invalidArgumentError(server.locale(ctx), []*errdetails.BadRequest_FieldViolation{FieldViolation("cursor", err)})
+	}
+	if err := server.authorizeAccount(ctx, payload, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	page, err := db.ListTransfersPage(ctx, server.store, req.GetAccountId(), cursor, req.GetPageSize())
+	if err != nil {
+		return nil, status.Errorf(internalCode(err), "failed to list transfers %s", err)
+	}
+	rsp := &pb.ListTransfersResponse{
+		Transfers:  make([]*pb.Transfer, len(page.Items)),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for i, transfer := range page.Items {
+		rsp.Transfers[i] = convertTransfer(transfer)
+	}
+	return rsp, nil
+}
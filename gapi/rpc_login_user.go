@@ -2,60 +2,104 @@ package gapi
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/notification"
 	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/preferences"
 	"github.com/backendmaster/simple_bank/util"
-	"github.com/backendmaster/simple_bank/val"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"github.com/backendmaster/simple_bank/worker"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
-	// violations := validateLoginUserRequest(req)
+	// violations := validateRequest(req)
 	// if violations != nil {
 	// 	return nil, invalidArgumentError(violations)
 	// }
 	// check user is existed and check password
 	user, err := server.store.GetUser(ctx, req.GetUsername())
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			metrics.ObserveLoginFailure(metrics.LoginUserNotFound)
 			return nil, status.Errorf(codes.NotFound, "user not found %s", err)
 		}
-		return nil, status.Errorf(codes.Internal, "login user failed %s", err)
+		return nil, status.Errorf(internalCode(err), "login user failed %s", err)
 	}
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
+		metrics.ObserveLoginFailure(metrics.LoginWrongPassword)
 		return nil, status.Errorf(codes.NotFound, "incorrect password")
 	}
+	// a reported login holds the logins until the password is reset, the old one being known
+	resetPending, err := server.store.HasPendingPasswordReset(ctx, user.Username)
+	if err != nil {
+		return nil, status.Errorf(internalCode(err), "check password reset failed %s", err)
+	}
+	if resetPending {
+		return nil, status.Errorf(codes.PermissionDenied, "reset your password with the code emailed to you first")
+	}
+	history, err := server.store.ListRecentSessions(ctx, db.ListRecentSessionsParams{
+		Username: user.Username,
+		Limit:    fraud.LoginHistorySize,
+	})
+	if err != nil {
+		return nil, status.Errorf(internalCode(err), "list recent sessions failed %s", err)
+	}
 	// return loginUserResponse
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, preferences.Locale(user.Preferences), server.config.AccessTokenDuration)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "create access token failed %s", err)
+		return nil, status.Errorf(internalCode(err), "create access token failed %s", err)
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, user.TenantID, preferences.Locale(user.Preferences), server.config.RefreshTokenDuration)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "create refresh token failed %s", err)
+		return nil, status.Errorf(internalCode(err), "create refresh token failed %s", err)
 	}
 
 	mtdt := server.extractMetadata(ctx)
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     refreshPayload.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    mtdt.UserAgent,
-		ClientIp:     mtdt.ClientIP,
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+	newLoginTask, err := worker.NewSendNotificationTask(&worker.PayloadSendNotification{
+		Username: user.Username,
+		Kind:     notification.KindNewLogin,
+		Data: map[string]string{
+			"client_ip":  mtdt.ClientIP,
+			"user_agent": mtdt.UserAgent,
+		},
 	})
+	if err != nil {
+		return nil, status.Errorf(internalCode(err), "failed to create new login task %s", err)
+	}
+
+	arg := db.CreateSessionTxParams{
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           refreshPayload.ID,
+			Username:     refreshPayload.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    mtdt.UserAgent,
+			ClientIp:     mtdt.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    refreshPayload.ExpiredAt,
+			Country:      mtdt.Country,
+		},
+		Device: db.LoginDevice(user.Username, mtdt.DeviceFingerprint, mtdt.DeviceName, mtdt.UserAgent),
+	}
+	if err := server.flagSuspiciousLogin(&arg, history); err != nil {
+		return nil, status.Errorf(codes.Internal, "check login failed %s", err)
+	}
+	if arg.Alert == nil {
+		arg.OutboxTasks = []db.CreateOutboxTaskParams{newLoginTask}
+	}
+	result, err := server.store.CreateSessionTx(ctx, arg)
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "create session failed: %s", err)
+		return nil, status.Errorf(internalCode(err), "create session failed: %s", err)
 	}
+	session := result.Session
 
 	rsp := &pb.LoginUserResponse{
 		User:                  convertUser(user),
@@ -69,12 +113,34 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 	return rsp, nil
 }
 
-func validateLoginUserRequest(req *pb.LoginUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
-	if err := val.ValidateUserName(req.GetUsername()); err != nil {
-		violations = append(violations, FieldViolation("username", err))
+// flagSuspiciousLogin sets the alert of the session of arg when the login is unlike history, the
+// last sessions of its user, along with the security notification with the link reporting it.
+// The user reports it through the REST api.
+func (server *Server) flagSuspiciousLogin(arg *db.CreateSessionTxParams, history []db.Session) error {
+	session := arg.CreateSessionParams
+	reasons := fraud.CheckLogin(history, session.ClientIp, session.Country, session.UserAgent)
+	if reasons == nil {
+		return nil
+	}
+
+	code, hash, err := fraud.NewLinkCode()
+	if err != nil {
+		return err
 	}
-	if err := val.ValidatePassword(req.GetPassword()); err != nil {
-		violations = append(violations, FieldViolation("password", err))
+	arg.Alert = &db.CreateLoginAlertParams{
+		Username:   session.Username,
+		ClientIp:   session.ClientIp,
+		UserAgent:  session.UserAgent,
+		Country:    session.Country,
+		Reasons:    reasons,
+		ReportHash: hash,
+	}
+	arg.AfterAlert = func(alert db.LoginAlert) ([]db.CreateOutboxTaskParams, error) {
+		task, err := worker.NewSuspiciousLoginTask(alert, server.config.LoginReportURL, code)
+		if err != nil {
+			return nil, err
+		}
+		return []db.CreateOutboxTaskParams{task}, nil
 	}
-	return violations
+	return nil
 }
@@ -2,14 +2,14 @@ package gapi
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"time"
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
 	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
-	"github.com/backendmaster/simple_bank/val"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -19,20 +19,24 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	if err != nil {
 		return nil, unauthenticationError(err)
 	}
-	if violations := validateUpdateUserRequest(req); violations != nil {
-		return nil, invalidArgumentError(violations)
+	if err := server.validateRequest(ctx, req); err != nil {
+		return nil, err
 	}
 
 	if payload.Username != req.Username {
 		return nil, permissionDeniedError(err)
 	}
+	// changing how the account is reached or logged in to asks for a recent authentication
+	if (req.Email != nil || req.Password != nil) && !payload.AuthenticatedSince(token.ACRPassword, server.config.StepUpMaxAge) {
+		return nil, status.Errorf(codes.Unauthenticated, "authenticate again to change the email or password, see POST /tokens/step_up")
+	}
 	arg := db.UpdateUserParams{
 		Username: req.GetUsername(),
-		FullName: sql.NullString{
+		FullName: pgtype.Text{
 			String: req.GetFullName(),
 			Valid:  req.FullName != nil,
 		},
-		Email: sql.NullString{
+		Email: pgtype.Text{
 			String: req.GetEmail(),
 			Valid:  req.Email != nil,
 		},
@@ -41,13 +45,13 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 	if req.Password != nil {
 		hashedPassword, err := util.HashedPassword(req.GetPassword())
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to hash password %s", err)
+			return nil, status.Errorf(internalCode(err), "failed to hash password %s", err)
 		}
-		arg.HashedPassword = sql.NullString{
+		arg.HashedPassword = pgtype.Text{
 			String: hashedPassword,
 			Valid:  true,
 		}
-		arg.PasswordChangedAt = sql.NullTime{
+		arg.PasswordChangedAt = pgtype.Timestamptz{
 			Time:  time.Now(),
 			Valid: true,
 		}
@@ -55,35 +59,13 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 
 	user, err := server.store.UpdateUser(ctx, arg)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			return nil, status.Errorf(codes.NotFound, "user not founde %s", err)
 		}
-		return nil, status.Errorf(codes.Internal, "failed to update user %s", err)
+		return nil, status.Errorf(internalCode(err), "failed to update user %s", err)
 	}
 	rsp := &pb.UpdateUserResponse{
 		User: convertUser(user),
 	}
 	return rsp, nil
 }
-
-func validateUpdateUserRequest(req *pb.UpdateUserRequest) (violations []*errdetails.BadRequest_FieldViolation) {
-	if err := val.ValidateUserName(req.GetUsername()); err != nil {
-		violations = append(violations, FieldViolation("username", err))
-	}
-	if req.Password != nil {
-		if err := val.ValidatePassword(req.GetPassword()); err != nil {
-			violations = append(violations, FieldViolation("password", err))
-		}
-	}
-	if req.Email != nil {
-		if err := val.ValidateEmail(req.GetEmail()); err != nil {
-			violations = append(violations, FieldViolation("email", err))
-		}
-	}
-	if req.FullName != nil {
-		if err := val.ValidateFullName(req.GetFullName()); err != nil {
-			violations = append(violations, FieldViolation("fullname", err))
-		}
-	}
-	return violations
-}
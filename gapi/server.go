@@ -5,6 +5,7 @@ import (
 
 	db "github.com/backendmaster/simple_bank/db/sqlc"
 	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/screening"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,7 @@ type Server struct {
 	pb.UnimplementedSimpleBankServer
 	config     util.Config
 	store      db.Store
+	screener   screening.Screener
 	tokenMaker token.Maker
 	router     *gin.Engine
 }
@@ -26,6 +28,7 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	server := &Server{
 		config:     config,
 		store:      store,
+		screener:   screening.NewDenylist(store),
 		tokenMaker: tokenMaker}
 
 	return server, nil
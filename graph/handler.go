@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/ratelimit"
+)
+
+// complexityLimit bounds the cost of a query, the fields of a page costing first times their
+// own so that nesting pages can't fetch the whole database in one query.
+const complexityLimit = 2000
+
+// NewHandler serves the GraphQL api over store, as the caller put in the context of the
+// requests by WithPayload. The createTransfer mutation is refused while the kill switch of mode
+// is on, when the fraud rules of engine don't allow the transfer, and once its account made the
+// quota of transfers of the transfers limiter, which may be nil. A large transfer also asks for
+// the recent authentication of stepUp.
+func NewHandler(store db.Store, mode *maintenance.Mode, engine *fraud.Engine, transfers ratelimit.Limiter, stepUp StepUp) http.Handler {
+	config := Config{Resolvers: &Resolver{store: store, mode: mode, fraud: engine, transfers: transfers, stepUp: stepUp}}
+	config.Complexity.Account.Entries = pageComplexity
+	config.Complexity.Account.Transfers = pageComplexity
+	config.Complexity.User.Accounts = pageComplexity
+
+	server := handler.New(NewExecutableSchema(config))
+	// queries only, which are served in read-only mode too
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(complexityLimit))
+	return server
+}
+
+func pageComplexity(childComplexity int, first int64, after *string) int {
+	return 1 + int(first)*childComplexity
+}
@@ -0,0 +1,158 @@
+package graph
+
+// This file will not be regenerated automatically.
+//
+// It serves as dependency injection for your app, add any dependencies you require here.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/token"
+)
+
+const (
+	maxPageSize   = 50
+	maxMemoLength = 140
+)
+
+type Resolver struct {
+	store     db.Store
+	mode      *maintenance.Mode
+	fraud     *fraud.Engine
+	transfers ratelimit.Limiter
+	stepUp    StepUp
+}
+
+// StepUp is the multi-factor authentication within MaxAge a transfer of at least the amount of its
+// currency in Amounts asks for, as POST /transfers of the REST api does. A currency without an
+// amount, or a zero one, asks for none.
+type StepUp struct {
+	Amounts map[string]int64
+	MaxAge  time.Duration
+}
+
+type (
+	payloadKey struct{}
+	countryKey struct{}
+)
+
+// WithPayload returns a copy of ctx carrying the payload of the access token of the caller,
+// which every query and mutation answers for.
+func WithPayload(ctx context.Context, payload *token.Payload) context.Context {
+	return context.WithValue(ctx, payloadKey{}, payload)
+}
+
+// WithCountry returns a copy of ctx carrying the country the request came from, which the fraud
+// rules check the transfers with.
+func WithCountry(ctx context.Context, country string) context.Context {
+	return context.WithValue(ctx, countryKey{}, country)
+}
+
+func countryFrom(ctx context.Context) string {
+	country, _ := ctx.Value(countryKey{}).(string)
+	return country
+}
+
+func payloadFrom(ctx context.Context) (*token.Payload, error) {
+	payload, ok := ctx.Value(payloadKey{}).(*token.Payload)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+	return payload, nil
+}
+
+// authorizeAccount returns the account, unless it doesn't exist or doesn't belong to the caller.
+func (r *Resolver) authorizeAccount(ctx context.Context, id int64) (db.Account, error) {
+	payload, err := payloadFrom(ctx)
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	account, err := r.store.GetAccount(ctx, id)
+	if err != nil {
+		return db.Account{}, err
+	}
+	if account.Owner != payload.Username {
+		return db.Account{}, errors.New("account doesn't belongs to authenticated user")
+	}
+	return account, nil
+}
+
+// checkStepUp refuses a large transfer from a caller who didn't step up recently, see POST
+// /tokens/step_up/challenges of the REST api.
+func (r *Resolver) checkStepUp(ctx context.Context, currency string, amount int64) error {
+	if threshold := r.stepUp.Amounts[currency]; threshold <= 0 || amount < threshold {
+		return nil
+	}
+	payload, err := payloadFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if !payload.AuthenticatedSince(token.ACRMultiFactor, r.stepUp.MaxAge) {
+		return errors.New("authenticate again to continue, see POST /tokens/step_up/challenges")
+	}
+	return nil
+}
+
+// checkFraud refuses a transfer the fraud rules don't allow. The mutation can't hold it for a
+// review, which POST /transfers of the REST api does.
+func (r *Resolver) checkFraud(ctx context.Context, fromAccount, toAccount db.Account, amount int64) error {
+	payload, err := payloadFrom(ctx)
+	if err != nil {
+		return err
+	}
+	verdict, err := r.fraud.Evaluate(ctx, fraud.Transfer{
+		Username:    payload.Username,
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+		Amount:      amount,
+		Country:     countryFrom(ctx),
+		Time:        time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if verdict.Decision != fraud.Allow {
+		return fmt.Errorf("transfer needs a review (%s), send it with POST /transfers", strings.Join(verdict.Rules, ", "))
+	}
+	return nil
+}
+
+func checkCurrency(account db.Account, currency string) error {
+	if account.Currency != currency {
+		return fmt.Errorf("account %v mismatched: %v vs %v", account.ID, account.Currency, currency)
+	}
+	return nil
+}
+
+// pageArgs parses the arguments of a paged field.
+func pageArgs(first int64, after *string) (pagination.Cursor, int32, error) {
+	if first < 1 || first > maxPageSize {
+		return pagination.Cursor{}, 0, fmt.Errorf("first must be between 1 and %d", maxPageSize)
+	}
+	var cursor pagination.Cursor
+	if after != nil {
+		var err error
+		if cursor, err = pagination.Decode(*after); err != nil {
+			return pagination.Cursor{}, 0, err
+		}
+	}
+	return cursor, int32(first), nil
+}
+
+// optionalCursor turns the empty cursor of a page into null.
+func optionalCursor(cursor string) *string {
+	if cursor == "" {
+		return nil
+	}
+	return &cursor
+}
@@ -0,0 +1,191 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.31
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/graph/model"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
+)
+
+// Owner is the resolver for the owner field.
+func (r *accountResolver) Owner(ctx context.Context, obj *db.Account) (*db.User, error) {
+	user, err := r.store.GetUser(ctx, obj.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Entries is the resolver for the entries field.
+func (r *accountResolver) Entries(ctx context.Context, obj *db.Account, first int64, after *string) (*model.EntryPage, error) {
+	cursor, size, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := db.ListEntriesPage(ctx, r.store, obj.ID, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EntryPage{
+		Items:      page.Items,
+		NextCursor: optionalCursor(page.NextCursor),
+		PrevCursor: optionalCursor(page.PrevCursor),
+	}, nil
+}
+
+// Transfers is the resolver for the transfers field.
+func (r *accountResolver) Transfers(ctx context.Context, obj *db.Account, first int64, after *string) (*model.TransferPage, error) {
+	cursor, size, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := db.ListTransfersPage(ctx, r.store, obj.ID, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TransferPage{
+		Items:      page.Items,
+		NextCursor: optionalCursor(page.NextCursor),
+		PrevCursor: optionalCursor(page.PrevCursor),
+	}, nil
+}
+
+// CreateTransfer is the resolver for the createTransfer field.
+func (r *mutationResolver) CreateTransfer(ctx context.Context, input model.TransferInput) (*db.TransferTxResult, error) {
+	if err := r.mode.CheckTransfers(); err != nil {
+		return nil, err
+	}
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if !util.IsSupportedCurrency(input.Currency) {
+		return nil, fmt.Errorf("currency %v is not supported", input.Currency)
+	}
+	if !util.IsValidAmount(input.Currency, input.Amount) {
+		return nil, fmt.Errorf("amount is more than an amount in %v can be", input.Currency)
+	}
+	if utf8.RuneCountInString(input.Memo) > maxMemoLength {
+		return nil, fmt.Errorf("memo must contain at most %d characters", maxMemoLength)
+	}
+	if err := r.checkStepUp(ctx, input.Currency, input.Amount); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := r.authorizeAccount(ctx, input.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.CheckConsents(ctx, r.store, fromAccount.Owner); err != nil {
+		return nil, err
+	}
+	if err := checkCurrency(fromAccount, input.Currency); err != nil {
+		return nil, err
+	}
+	toAccount, err := r.store.GetAccount(ctx, input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCurrency(toAccount, input.Currency); err != nil {
+		return nil, err
+	}
+	if _, err := ratelimit.AllowTransfer(ctx, r.transfers, fromAccount.ID); err != nil {
+		return nil, err
+	}
+	if err := r.checkFraud(ctx, fromAccount, toAccount, input.Amount); err != nil {
+		return nil, err
+	}
+
+	notifyTask, err := worker.NewNotifyTransferTask(&worker.PayloadNotifyTransfer{
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        input.Amount,
+		Memo:          input.Memo,
+		OutboxTasks:   []db.CreateOutboxTaskParams{notifyTask},
+		AlertTask:     worker.NewAccountAlertTask,
+		RewardTask:    worker.NewAccrueRewardsTask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.ObserveTransfer(input.Currency, input.Amount)
+
+	return &result, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*db.User, error) {
+	payload, err := payloadFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Account is the resolver for the account field.
+func (r *queryResolver) Account(ctx context.Context, id int64) (*db.Account, error) {
+	account, err := r.authorizeAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Accounts is the resolver for the accounts field.
+func (r *userResolver) Accounts(ctx context.Context, obj *db.User, first int64, after *string) (*model.AccountPage, error) {
+	cursor, size, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := db.ListAccountsPage(ctx, r.store, obj.Username, cursor, size)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AccountPage{
+		Items:      page.Items,
+		NextCursor: optionalCursor(page.NextCursor),
+		PrevCursor: optionalCursor(page.PrevCursor),
+	}, nil
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type accountResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
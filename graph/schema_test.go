@@ -0,0 +1,425 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/pagination"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/testfixtures"
+	"github.com/backendmaster/simple_bank/token"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func randomAccount(owner string, options ...testfixtures.AccountOption) db.Account {
+	account := testfixtures.NewAccount(append([]testfixtures.AccountOption{testfixtures.WithOwner(owner)}, options...)...)
+	return db.Account{
+		ID:       account.ID,
+		Owner:    account.Owner,
+		Balance:  account.Balance,
+		Currency: account.Currency,
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+		Role:     util.DepositorRole,
+	}
+	account := randomAccount(user.Username, testfixtures.WithCurrency(util.USD))
+	otherAccount := randomAccount(util.RandomOwnerName(), testfixtures.WithID(account.ID+1), testfixtures.WithCurrency(util.USD))
+	entry := db.Entry{ID: 1, AccountID: account.ID, Amount: -10, CreatedAt: time.Now().UTC()}
+
+	testCases := []struct {
+		name          string
+		query         string
+		variables     map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rsp graphQLResponse)
+	}{
+		{
+			name:  "Nested",
+			query: `{ me { username accounts(first: 5) { items { id balance owner { username } entries(first: 5) { items { id amount } nextCursor } } nextCursor } } }`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+				store.EXPECT().
+					ListAccountsAfter(gomock.Any(), gomock.Eq(db.ListAccountsAfterParams{Owner: user.Username, Limit: 6})).
+					Times(1).
+					Return([]db.Account{account}, nil)
+				store.EXPECT().
+					ListStatementEntriesAfter(gomock.Any(), gomock.Eq(db.ListStatementEntriesAfterParams{AccountID: account.ID, Limit: 6})).
+					Times(1).
+					Return([]db.Entry{entry}, nil)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Empty(t, rsp.Errors)
+
+				var data struct {
+					Me struct {
+						Username string `json:"username"`
+						Accounts struct {
+							Items []struct {
+								ID      int64 `json:"id"`
+								Balance int64 `json:"balance"`
+								Owner   struct {
+									Username string `json:"username"`
+								} `json:"owner"`
+								Entries struct {
+									Items      []db.Entry `json:"items"`
+									NextCursor *string    `json:"nextCursor"`
+								} `json:"entries"`
+							} `json:"items"`
+							NextCursor *string `json:"nextCursor"`
+						} `json:"accounts"`
+					} `json:"me"`
+				}
+				require.NoError(t, json.Unmarshal(rsp.Data, &data))
+				require.Equal(t, user.Username, data.Me.Username)
+				require.Len(t, data.Me.Accounts.Items, 1)
+				require.Nil(t, data.Me.Accounts.NextCursor)
+				got := data.Me.Accounts.Items[0]
+				require.Equal(t, account.ID, got.ID)
+				require.Equal(t, account.Balance, got.Balance)
+				require.Equal(t, user.Username, got.Owner.Username)
+				require.Len(t, got.Entries.Items, 1)
+				require.Equal(t, entry.Amount, got.Entries.Items[0].Amount)
+				require.Nil(t, got.Entries.NextCursor)
+			},
+		},
+		{
+			name:      "AccountOfAnotherUser",
+			query:     `query($id: Int!) { account(id: $id) { id balance } }`,
+			variables: map[string]any{"id": otherAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "doesn't belongs")
+				require.JSONEq(t, "null", string(rsp.Data))
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: `{ me { accounts(after: "invalid") { nextCursor } } }`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Equal(t, pagination.ErrInvalidCursor.Error(), rsp.Errors[0].Message)
+			},
+		},
+		{
+			name:  "TooComplex",
+			query: `{ me { accounts(first: 50) { items { entries(first: 50) { items { id } } } } } }`,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "complexity")
+			},
+		},
+		{
+			name:  "CreateTransfer",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { amount memo } fromAccount { id balance } fromEntry { amount } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        10,
+				"currency":      util.USD,
+				"memo":          "rent",
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.Equal(t, account.ID, arg.FromAccountID)
+						require.Equal(t, otherAccount.ID, arg.ToAccountID)
+						require.Equal(t, int64(10), arg.Amount)
+						require.Equal(t, "rent", arg.Memo)
+						require.Len(t, arg.OutboxTasks, 1)
+						require.NotNil(t, arg.AlertTask)
+
+						fromAccount := account
+						fromAccount.Balance -= arg.Amount
+						return db.TransferTxResult{
+							Transfer:    db.Transfer{FromAccountID: account.ID, ToAccountID: otherAccount.ID, Amount: arg.Amount, Memo: arg.Memo},
+							FromAccount: fromAccount,
+							ToAccount:   otherAccount,
+							FromEntry:   db.Entry{AccountID: account.ID, Amount: -arg.Amount},
+							ToEntry:     db.Entry{AccountID: otherAccount.ID, Amount: arg.Amount},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Empty(t, rsp.Errors)
+				expected := map[string]any{
+					"createTransfer": map[string]any{
+						"transfer":    map[string]any{"amount": 10, "memo": "rent"},
+						"fromAccount": map[string]any{"id": account.ID, "balance": account.Balance - 10},
+						"fromEntry":   map[string]any{"amount": -10},
+					},
+				}
+				data, err := json.Marshal(expected)
+				require.NoError(t, err)
+				require.JSONEq(t, string(data), string(rsp.Data))
+			},
+		},
+		{
+			name:  "TransferFlaggedByFraudRules",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        5000,
+				"currency":      util.USD,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().HasTransferBetween(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "new_payee_large_amount")
+			},
+		},
+		{
+			name:  "TransferFromAccountOfAnotherUser",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": otherAccount.ID,
+				"toAccountId":   account.ID,
+				"amount":        10,
+				"currency":      util.USD,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "doesn't belongs")
+			},
+		},
+		{
+			name:  "TransferConsentRequired",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        10,
+				"currency":      util.USD,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListPendingLegalDocuments(gomock.Any(), gomock.Eq(account.Owner)).
+					Times(1).
+					Return([]db.LegalDocument{{Kind: db.LegalTerms, Version: "2"}}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Equal(t, db.ErrConsentRequired.Error(), rsp.Errors[0].Message)
+			},
+		},
+		{
+			name:  "TransferCurrencyMismatch",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        10,
+				"currency":      util.EUR,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "mismatched")
+			},
+		},
+		{
+			name:  "TransferNeedsStepUp",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        1_000_000,
+				"currency":      util.USD,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "authenticate again")
+			},
+		},
+		{
+			name:  "TransferInvalidAmount",
+			query: `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`,
+			variables: map[string]any{"input": map[string]any{
+				"fromAccountId": account.ID,
+				"toAccountId":   otherAccount.ID,
+				"amount":        -10,
+				"currency":      util.USD,
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rsp graphQLResponse) {
+				require.Len(t, rsp.Errors, 1)
+				require.Contains(t, rsp.Errors[0].Message, "amount")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			// the user accepted the latest legal documents, unless the case stubs otherwise
+			store.EXPECT().ListPendingLegalDocuments(gomock.Any(), gomock.Any()).AnyTimes().Return([]db.LegalDocument{}, nil)
+
+			body, err := json.Marshal(map[string]any{"query": tc.query, "variables": tc.variables})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+			payload := &token.Payload{Username: user.Username, Role: user.Role}
+			request = request.WithContext(WithPayload(request.Context(), payload))
+
+			recorder := httptest.NewRecorder()
+			engine := fraud.NewEngine(fraud.NewPayeeRule(store, 1000))
+			NewHandler(store, maintenance.NewMode(false), engine, nil, StepUp{Amounts: map[string]int64{util.USD: 1_000_000}, MaxAge: time.Minute}).ServeHTTP(recorder, request)
+
+			var rsp graphQLResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp), recorder.Body.String())
+			tc.checkResponse(t, rsp)
+		})
+	}
+}
+
+func TestGraphQLGetRefusesMutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	query := `mutation { createTransfer(input: {fromAccountId: 1, toAccountId: 2, amount: 10, currency: "USD"}) { transfer { id } } }`
+	request, err := http.NewRequest(http.MethodGet, "/graphql", nil)
+	require.NoError(t, err)
+	values := request.URL.Query()
+	values.Set("query", query)
+	request.URL.RawQuery = values.Encode()
+	request = request.WithContext(WithPayload(request.Context(), &token.Payload{Username: util.RandomOwnerName()}))
+
+	recorder := httptest.NewRecorder()
+	NewHandler(store, maintenance.NewMode(false), fraud.NewEngine(), nil, StepUp{}).ServeHTTP(recorder, request)
+	require.NotEqual(t, http.StatusOK, recorder.Code)
+}
+
+func TestGraphQLTransfersBlocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	mode := maintenance.NewMode(false)
+	mode.SetTransfersBlocked(true)
+
+	query := `mutation { createTransfer(input: {fromAccountId: 1, toAccountId: 2, amount: 10, currency: "USD"}) { transfer { id } } }`
+	body, err := json.Marshal(map[string]any{"query": query})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(WithPayload(request.Context(), &token.Payload{Username: util.RandomOwnerName()}))
+
+	recorder := httptest.NewRecorder()
+	NewHandler(store, mode, fraud.NewEngine(), nil, StepUp{}).ServeHTTP(recorder, request)
+
+	var rsp graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp), recorder.Body.String())
+	require.Len(t, rsp.Errors, 1)
+	require.Equal(t, maintenance.ErrTransfersBlocked.Error(), rsp.Errors[0].Message)
+}
+
+func TestGraphQLTransferRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := util.RandomOwnerName()
+	account := randomAccount(owner, testfixtures.WithCurrency(util.USD))
+	otherAccount := randomAccount(util.RandomOwnerName(), testfixtures.WithCurrency(util.USD))
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().ListPendingLegalDocuments(gomock.Any(), gomock.Eq(owner)).Times(1).Return([]db.LegalDocument{}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	// the account already made its only transfer of the hour
+	transfers := ratelimit.NewSlidingMemoryLimiter(1, time.Hour)
+	_, err := ratelimit.AllowTransfer(context.Background(), transfers, account.ID)
+	require.NoError(t, err)
+
+	query := `mutation($input: TransferInput!) { createTransfer(input: $input) { transfer { id } } }`
+	variables := map[string]any{"input": map[string]any{
+		"fromAccountId": account.ID,
+		"toAccountId":   otherAccount.ID,
+		"amount":        10,
+		"currency":      util.USD,
+	}}
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(WithPayload(request.Context(), &token.Payload{Username: owner}))
+
+	recorder := httptest.NewRecorder()
+	NewHandler(store, maintenance.NewMode(false), fraud.NewEngine(), transfers, StepUp{}).ServeHTTP(recorder, request)
+
+	var rsp graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp), recorder.Body.String())
+	require.Len(t, rsp.Errors, 1)
+	require.Contains(t, rsp.Errors[0].Message, ratelimit.ErrTooManyTransfers.Error())
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/backendmaster/simple_bank/db/migration"
+)
+
+const (
+	statusOK     = "ok"
+	checkTimeout = 2 * time.Second
+)
+
+// Store is the part of db.Store the readiness checks need.
+type Store interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness answers as long as the process is able to serve requests at all.
+func Liveness() http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		writeResponse(res, http.StatusOK, response{Status: statusOK})
+	})
+}
+
+// Readiness answers 200 only when the database is reachable and migrated to the version
+// this binary was built with, and 503 with the failing checks otherwise.
+func Readiness(store Store) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+
+		rsp := response{
+			Status: statusOK,
+			Checks: map[string]string{
+				"database":   checkResult(store.Ping(ctx)),
+				"migrations": checkResult(checkMigrations(ctx, store)),
+			},
+		}
+
+		statusCode := http.StatusOK
+		for _, result := range rsp.Checks {
+			if result != statusOK {
+				rsp.Status = "unavailable"
+				statusCode = http.StatusServiceUnavailable
+			}
+		}
+		writeResponse(res, statusCode, rsp)
+	})
+}
+
+func checkMigrations(ctx context.Context, store Store) error {
+	version, dirty, err := store.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+	if latest := migration.LatestVersion(); version < latest {
+		return fmt.Errorf("database is at version %d, expected %d", version, latest)
+	}
+	return nil
+}
+
+func checkResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return statusOK
+}
+
+func writeResponse(res http.ResponseWriter, statusCode int, rsp response) {
+	body, err := json.Marshal(rsp)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(statusCode)
+	res.Write(body)
+}
@@ -0,0 +1,147 @@
+// Package i18n translates the messages the apis return, validation errors included, into the
+// locales the users read them in. The catalogs are embedded JSON files of catalogs/, one per
+// locale, keyed by message: validation.<tag> for the rules of the fields, field.<name> for the
+// labels of the fields, and the English text itself for the errors of the apis.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// English is the locale of the catalogs every other one falls back to.
+	English = "en"
+	// TraditionalChinese is the locale of the users of Taiwan.
+	TraditionalChinese = "zh-TW"
+)
+
+// Locales are the locales there is a catalog of.
+var Locales = []string{English, TraditionalChinese}
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogs maps the locales to their messages by key.
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	catalogs := make(map[string]map[string]string, len(Locales))
+	for _, locale := range Locales {
+		data, err := catalogFiles.ReadFile("catalogs/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: no catalog for %s: %v", locale, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", locale, err))
+		}
+		catalogs[locale] = messages
+	}
+	return catalogs
+}
+
+// IsSupported tells whether there is a catalog of locale.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T returns the message of key in locale, falling back to English and then to key itself, so
+// that the errors without a translation read as they always did. args are pairs of names and
+// values replacing the {name} placeholders of the message.
+func T(locale, key string, args ...string) string {
+	message, ok := lookup(locale, key)
+	if !ok {
+		message = key
+	}
+	if len(args) < 2 {
+		return message
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+func lookup(locale, key string) (string, bool) {
+	if message, ok := catalogs[locale][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[English][key]
+	return message, ok
+}
+
+// Field returns the label of the field named name in locale, name itself when there is none,
+// e.g. in English where the names of the fields are the labels.
+func Field(locale, name string) string {
+	if label, ok := catalogs[locale]["field."+name]; ok {
+		return label
+	}
+	return name
+}
+
+// Rule returns the message of a field breaking the validation rule tag with param, e.g. min=3.
+// kind tells the messages of the lengths of the strings and the counts of the lists from those
+// of the numbers apart; it is "string", "items" or empty. The rules without a message of their
+// own get validation.invalid.
+func Rule(locale, field, tag, param, kind string) string {
+	key := "validation." + tag
+	if kind != "" {
+		if _, ok := lookup(locale, key+"."+kind); ok {
+			key += "." + kind
+		}
+	}
+	if _, ok := lookup(locale, key); !ok {
+		key = "validation.invalid"
+	}
+	return T(locale, key, "field", Field(locale, field), "param", param)
+}
+
+// Negotiate picks the locale of a request from the value of its Accept-Language header, in the
+// order of the weights of the languages. It reports false when none of them has a catalog.
+func Negotiate(acceptLanguage string) (string, bool) {
+	type weighted struct {
+		locale string
+		weight float64
+	}
+	var candidates []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if locale, ok := match(tag); ok && weight > 0 {
+			candidates = append(candidates, weighted{locale, weight})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+	return candidates[0].locale, true
+}
+
+// match returns the locale with a catalog for the language tag, e.g. zh-TW for zh-Hant-HK.
+func match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return English, true
+	case tag == "zh-tw" || tag == "zh-hk" || tag == "zh-mo" || strings.HasPrefix(tag, "zh-hant"):
+		return TraditionalChinese, true
+	}
+	return "", false
+}
@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogs(t *testing.T) {
+	// the English texts are keys of their own, but the rules all need an English message
+	for key := range catalogs[TraditionalChinese] {
+		if _, ok := catalogs[English][key]; !ok {
+			require.NotRegexp(t, `^validation\.`, key)
+		}
+	}
+	for key := range catalogs[English] {
+		require.Contains(t, catalogs[TraditionalChinese], key)
+	}
+}
+
+func TestT(t *testing.T) {
+	require.Equal(t, "帳戶不屬於已驗證的使用者", T(TraditionalChinese, "account doesn't belong to the authenticated user"))
+	require.Equal(t, "account doesn't belong to the authenticated user", T(English, "account doesn't belong to the authenticated user"))
+	require.Equal(t, "not translated", T(TraditionalChinese, "not translated"))
+	require.Equal(t, "not translated", T("fr", "not translated"))
+	require.Equal(t, "amount must be greater than 0", T(English, "validation.gt", "field", "amount", "param", "0"))
+}
+
+func TestRule(t *testing.T) {
+	testCases := []struct {
+		name    string
+		locale  string
+		tag     string
+		param   string
+		kind    string
+		message string
+	}{
+		{name: "Required", locale: English, tag: "required", message: "username is required"},
+		{name: "RequiredChinese", locale: TraditionalChinese, tag: "required", message: "使用者名稱為必填"},
+		{name: "StringLength", locale: English, tag: "min", param: "6", kind: "string", message: "username must be at least 6 characters long"},
+		{name: "Number", locale: English, tag: "min", param: "6", message: "username must be at least 6"},
+		{name: "NoKindMessage", locale: English, tag: "gt", param: "0", kind: "string", message: "username must be greater than 0"},
+		{name: "UnknownTag", locale: TraditionalChinese, tag: "hexcolor", message: "使用者名稱無效"},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.message, Rule(tc.locale, "username", tc.tag, tc.param, tc.kind))
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		header string
+		locale string
+		ok     bool
+	}{
+		{header: "", ok: false},
+		{header: "fr-FR, de;q=0.8", ok: false},
+		{header: "en-US,en;q=0.9", locale: English, ok: true},
+		{header: "zh-TW", locale: TraditionalChinese, ok: true},
+		{header: "zh-Hant-HK, en;q=0.5", locale: TraditionalChinese, ok: true},
+		{header: "fr, en;q=0.5, zh-TW;q=0.8", locale: TraditionalChinese, ok: true},
+		{header: "zh-CN, en;q=0.7", locale: English, ok: true},
+		{header: "zh-TW;q=0, en;q=0.1", locale: English, ok: true},
+		{header: "zh-TW;q=abc", ok: false},
+	}
+
+	for _, tc := range testCases {
+		locale, ok := Negotiate(tc.header)
+		require.Equal(t, tc.ok, ok, tc.header)
+		require.Equal(t, tc.locale, locale, tc.header)
+	}
+}
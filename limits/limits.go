@@ -0,0 +1,83 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+)
+
+// ParseRouteTimeouts parses ROUTE_TIMEOUTS entries of the form route=duration, e.g. /transfers=10s.
+func ParseRouteTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		route, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route timeout %q, expected route=duration", entry)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route timeout %q: %w", entry, err)
+		}
+		timeouts[route] = timeout
+	}
+	return timeouts, nil
+}
+
+// GinTimeout gives every request a deadline, timeout by default or the one of its route in routeTimeouts.
+// Handlers see the deadline through their context, so the store calls of a slow request are cancelled
+// instead of holding a connection forever. The engine needs ContextWithFallback for gin contexts to carry it.
+// WebSocket upgrades and event streams are left alone, their connection lasts as long as the client wants.
+func GinTimeout(timeout time.Duration, routeTimeouts map[string]time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.IsWebsocket() || strings.Contains(ctx.GetHeader("Accept"), "text/event-stream") {
+			ctx.Next()
+			return
+		}
+		routeTimeout, ok := routeTimeouts[ctx.FullPath()]
+		if !ok {
+			routeTimeout = timeout
+		}
+		if routeTimeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), routeTimeout)
+		defer cancel()
+		ctx.Request = ctx.Request.WithContext(timeoutCtx)
+		ctx.Next()
+
+		if timeoutCtx.Err() == context.DeadlineExceeded && !ctx.Writer.Written() {
+			envelope.Abort(ctx, http.StatusServiceUnavailable, "request timed out")
+		}
+	}
+}
+
+// GinMaxBodySize refuses request bodies larger than maxSize bytes, or the size of their route in
+// routeSizes, with a 413. Bodies without a Content-Length are cut at that size, making the json
+// binding of the handler fail.
+func GinMaxBodySize(maxSize int64, routeSizes map[string]int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		size, ok := routeSizes[ctx.FullPath()]
+		if !ok {
+			size = maxSize
+		}
+		if size <= 0 || ctx.Request.Body == nil {
+			ctx.Next()
+			return
+		}
+		if ctx.Request.ContentLength > size {
+			err := fmt.Errorf("request body is larger than %d bytes", size)
+			envelope.Abort(ctx, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, size)
+		ctx.Next()
+	}
+}
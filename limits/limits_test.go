@@ -0,0 +1,116 @@
+package limits
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRouteTimeouts(t *testing.T) {
+	timeouts, err := ParseRouteTimeouts([]string{"/transfers=10s", " /accounts=2s"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]time.Duration{
+		"/transfers": 10 * time.Second,
+		"/accounts":  2 * time.Second,
+	}, timeouts)
+
+	_, err = ParseRouteTimeouts([]string{"/transfers"})
+	require.Error(t, err)
+
+	_, err = ParseRouteTimeouts([]string{"/transfers=soon"})
+	require.Error(t, err)
+}
+
+func TestGinTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(GinTimeout(20*time.Millisecond, map[string]time.Duration{"/transfers": time.Second}))
+
+	slowHandler := func(ctx *gin.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+			ctx.Status(http.StatusOK)
+		}
+	}
+	router.GET("/accounts", slowHandler)
+	router.POST("/transfers", slowHandler)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/transfers", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// websocket upgrades get no deadline
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/accounts", nil)
+	require.NoError(t, err)
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "websocket")
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// and neither do event streams
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/accounts", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept", "text/event-stream")
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestGinMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinMaxBodySize(16, map[string]int64{"/imports": 32}))
+	readBody := func(ctx *gin.Context) {
+		_, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	}
+	router.POST("/users", readBody)
+	router.POST("/imports", readBody)
+
+	testCases := []struct {
+		name     string
+		path     string
+		body     io.Reader
+		expected int
+	}{
+		{name: "Small", path: "/users", body: strings.NewReader(`{"a":1}`), expected: http.StatusOK},
+		{name: "TooLarge", path: "/users", body: bytes.NewReader(bytes.Repeat([]byte("a"), 17)), expected: http.StatusRequestEntityTooLarge},
+		{name: "TooLargeWithoutLength", path: "/users", body: io.MultiReader(bytes.NewReader(bytes.Repeat([]byte("a"), 17))), expected: http.StatusBadRequest},
+		{name: "RouteSize", path: "/imports", body: bytes.NewReader(bytes.Repeat([]byte("a"), 32)), expected: http.StatusOK},
+		{name: "TooLargeForRoute", path: "/imports", body: bytes.NewReader(bytes.Repeat([]byte("a"), 33)), expected: http.StatusRequestEntityTooLarge},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, tc.path, tc.body)
+			require.NoError(t, err)
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expected, recorder.Code)
+		})
+	}
+}
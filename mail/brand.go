@@ -0,0 +1,18 @@
+package mail
+
+// Brand is how the emails to a user present the bank, that of the tenant of the user: its name in
+// the header, the sender of the email, and where to write for help in the footer.
+type Brand struct {
+	Name string
+	// SenderName is the FromName of the messages, the one of the EmailSender when empty.
+	SenderName   string
+	SupportEmail string
+}
+
+// DefaultBrand is what the emails showed before there were tenants.
+var DefaultBrand = Brand{Name: "Simple Bank"}
+
+// Apply signs msg with the sender name of the brand.
+func (brand Brand) Apply(msg *Message) {
+	msg.FromName = brand.SenderName
+}
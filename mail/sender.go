@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/jordan-wright/email"
+)
+
+const (
+	DriverSMTP     = "smtp"
+	DriverSES      = "ses"
+	DriverSendGrid = "sendgrid"
+)
+
+// Message is an html email. Render fills in its subject and content from a template.
+type Message struct {
+	// FromName, when set, replaces the sender name of the EmailSender.
+	FromName    string
+	Subject     string
+	Content     string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	AttachFiles []string
+}
+
+// EmailSender sends emails through one of the mail providers.
+type EmailSender interface {
+	SendEmail(ctx context.Context, msg Message) error
+}
+
+// NewEmailSender creates the sender of the driver chosen by EMAIL_DRIVER, smtp by default.
+func NewEmailSender(config util.Config) (EmailSender, error) {
+	switch config.EmailDriver {
+	case DriverSMTP, "":
+		return NewSMTPSender(config.EmailSenderName, config.EmailSenderAddress,
+			config.SMTPAddress, config.SMTPUsername, config.SMTPPassword), nil
+	case DriverSES:
+		return NewSESSender(config.EmailSenderName, config.EmailSenderAddress,
+			config.SESRegion, config.SESAccessKeyID, config.SESSecretAccessKey), nil
+	case DriverSendGrid:
+		return NewSendGridSender(config.EmailSenderName, config.EmailSenderAddress, config.SendGridAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown email driver %q", config.EmailDriver)
+	}
+}
+
+// newEmail builds the mime message of msg, which the smtp and ses drivers send as is.
+func newEmail(fromName, fromAddress string, msg Message) (*email.Email, error) {
+	if msg.FromName != "" {
+		fromName = msg.FromName
+	}
+	e := email.NewEmail()
+	e.From = fmt.Sprintf("%s <%s>", fromName, fromAddress)
+	e.Subject = msg.Subject
+	e.HTML = []byte(msg.Content)
+	e.To = msg.To
+	e.Cc = msg.Cc
+	e.Bcc = msg.Bcc
+
+	for _, file := range msg.AttachFiles {
+		if _, err := e.AttachFile(file); err != nil {
+			return nil, fmt.Errorf("failed to attach file %s: %w", file, err)
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,86 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/sendgrid/sendgrid-go"
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+const (
+	sendGridHost     = "https://api.sendgrid.com"
+	sendGridSendPath = "/v3/mail/send"
+)
+
+// SendGridSender sends emails through the v3 mail send api of SendGrid.
+type SendGridSender struct {
+	fromName    string
+	fromAddress string
+	apiKey      string
+	host        string
+}
+
+func NewSendGridSender(fromName, fromAddress, apiKey string) EmailSender {
+	return &SendGridSender{
+		fromName:    fromName,
+		fromAddress: fromAddress,
+		apiKey:      apiKey,
+		host:        sendGridHost,
+	}
+}
+
+func (sender *SendGridSender) SendEmail(ctx context.Context, msg Message) error {
+	fromName := sender.fromName
+	if msg.FromName != "" {
+		fromName = msg.FromName
+	}
+	m := sgmail.NewV3Mail()
+	m.SetFrom(sgmail.NewEmail(fromName, sender.fromAddress))
+	m.Subject = msg.Subject
+	m.AddContent(sgmail.NewContent("text/html", msg.Content))
+
+	p := sgmail.NewPersonalization()
+	p.AddTos(sendGridEmails(msg.To)...)
+	p.AddCCs(sendGridEmails(msg.Cc)...)
+	p.AddBCCs(sendGridEmails(msg.Bcc)...)
+	m.AddPersonalizations(p)
+
+	for _, file := range msg.AttachFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to attach file %s: %w", file, err)
+		}
+		attachment := sgmail.NewAttachment().
+			SetContent(base64.StdEncoding.EncodeToString(content)).
+			SetType(mime.TypeByExtension(filepath.Ext(file))).
+			SetFilename(filepath.Base(file)).
+			SetDisposition("attachment")
+		m.AddAttachment(attachment)
+	}
+
+	req := sendgrid.GetRequest(sender.apiKey, sendGridSendPath, sender.host)
+	req.Method = http.MethodPost
+	req.Body = sgmail.GetRequestBody(m)
+	rsp, err := sendgrid.MakeRequestWithContext(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to send email through sendgrid: %w", err)
+	}
+	if rsp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sendgrid rejected the email with status %d: %s", rsp.StatusCode, rsp.Body)
+	}
+	return nil
+}
+
+func sendGridEmails(addresses []string) []*sgmail.Email {
+	emails := make([]*sgmail.Email, len(addresses))
+	for i, address := range addresses {
+		emails[i] = sgmail.NewEmail("", address)
+	}
+	return emails
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendGridSender(t *testing.T) {
+	var arg sgmail.SGMailV3
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, sendGridSendPath, req.URL.Path)
+		require.Equal(t, "Bearer api-key", req.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&arg))
+		res.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "statement.txt")
+	require.NoError(t, os.WriteFile(file, []byte("statement"), 0o600))
+
+	sender := NewSendGridSender("Simple Bank", "no-reply@simplebank.local", "api-key").(*SendGridSender)
+	sender.host = server.URL
+
+	err := sender.SendEmail(context.Background(), Message{
+		Subject:     "Your statement",
+		Content:     "<p>Hello</p>",
+		To:          []string{"tom@example.com"},
+		AttachFiles: []string{file},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Your statement", arg.Subject)
+	require.Equal(t, "no-reply@simplebank.local", arg.From.Address)
+	require.Len(t, arg.Personalizations, 1)
+	require.Equal(t, "tom@example.com", arg.Personalizations[0].To[0].Address)
+	require.Len(t, arg.Attachments, 1)
+	require.Equal(t, "statement.txt", arg.Attachments[0].Filename)
+
+	err = sender.SendEmail(context.Background(), Message{AttachFiles: []string{"missing.txt"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sesService    = "ses"
+	sesSendPath   = "/v2/email/outbound-emails"
+	amzDateFormat = "20060102T150405Z"
+)
+
+// SESSender sends emails through the SendEmail api of AWS SES v2, as raw mime messages so that
+// attachments work the same as with smtp.
+type SESSender struct {
+	fromName        string
+	fromAddress     string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	endpoint        string
+	client          *http.Client
+	now             func() time.Time
+}
+
+func NewSESSender(fromName, fromAddress, region, accessKeyID, secretAccessKey string) EmailSender {
+	return &SESSender{
+		fromName:        fromName,
+		fromAddress:     fromAddress,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		endpoint:        fmt.Sprintf("https://email.%s.amazonaws.com", region),
+		client:          &http.Client{Timeout: 30 * time.Second},
+		now:             time.Now,
+	}
+}
+
+type sesDestination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string         `json:"FromEmailAddress"`
+	Destination      sesDestination `json:"Destination"`
+	Content          struct {
+		Raw struct {
+			Data []byte `json:"Data"`
+		} `json:"Raw"`
+	} `json:"Content"`
+}
+
+func (sender *SESSender) SendEmail(ctx context.Context, msg Message) error {
+	e, err := newEmail(sender.fromName, sender.fromAddress, msg)
+	if err != nil {
+		return err
+	}
+	raw, err := e.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	arg := sesSendEmailRequest{
+		FromEmailAddress: e.From,
+		Destination: sesDestination{
+			ToAddresses:  msg.To,
+			CcAddresses:  msg.Cc,
+			BccAddresses: msg.Bcc,
+		},
+	}
+	arg.Content.Raw.Data = raw
+	body, err := json.Marshal(arg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ses request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sender.endpoint+sesSendPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signRequest(req, body, sender.accessKeyID, sender.secretAccessKey, sender.region, sesService, sender.now())
+
+	rsp, err := sender.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email through ses: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		rspBody, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+		return fmt.Errorf("ses rejected the email with status %d: %s", rsp.StatusCode, rspBody)
+	}
+	return nil
+}
+
+// signRequest adds the AWS signature version 4 of req to its Authorization header,
+// signing the host and every header already set on req.
+func signRequest(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
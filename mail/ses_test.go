@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSignRequest checks the signer against the get-vanilla case of the AWS signature v4 test suite.
+func TestSignRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signRequest(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service", now)
+
+	require.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	require.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestSESSender(t *testing.T) {
+	var arg sesSendEmailRequest
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, sesSendPath, req.URL.Path)
+		require.Contains(t, req.Header.Get("Authorization"), "Credential=key-id/")
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&arg))
+		res.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	sender := NewSESSender("Simple Bank", "no-reply@simplebank.local", "us-east-1", "key-id", "secret").(*SESSender)
+	sender.endpoint = server.URL
+
+	err := sender.SendEmail(context.Background(), Message{
+		Subject: "Verify your email address",
+		Content: "<p>Hello</p>",
+		To:      []string{"tom@example.com"},
+		Bcc:     []string{"audit@example.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Simple Bank <no-reply@simplebank.local>", arg.FromEmailAddress)
+	require.Equal(t, []string{"tom@example.com"}, arg.Destination.ToAddresses)
+	require.Equal(t, []string{"audit@example.com"}, arg.Destination.BccAddresses)
+	require.Contains(t, string(arg.Content.Raw.Data), "Subject: Verify your email address")
+	require.NotContains(t, string(arg.Content.Raw.Data), "audit@example.com")
+
+	server.Config.Handler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusBadRequest)
+	})
+	err = sender.SendEmail(context.Background(), Message{To: []string{"tom@example.com"}})
+	require.Error(t, err)
+}
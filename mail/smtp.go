@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+)
+
+// SMTPSender sends emails through an smtp server, e.g. mailhog in development.
+type SMTPSender struct {
+	fromName      string
+	fromAddress   string
+	serverAddress string
+	auth          smtp.Auth
+}
+
+// NewSMTPSender creates an SMTPSender for the server at serverAddress (host:port).
+// It only authenticates when a username is given.
+func NewSMTPSender(fromName, fromAddress, serverAddress, username, password string) EmailSender {
+	sender := &SMTPSender{
+		fromName:      fromName,
+		fromAddress:   fromAddress,
+		serverAddress: serverAddress,
+	}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(serverAddress)
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+func (sender *SMTPSender) SendEmail(ctx context.Context, msg Message) error {
+	e, err := newEmail(sender.fromName, sender.fromAddress, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Send(sender.serverAddress, sender.auth); err != nil {
+		return fmt.Errorf("failed to send email through smtp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/backendmaster/simple_bank/money"
+)
+
+const (
+	TemplateVerifyEmail    = "verify_email"
+	TemplateNotifyTransfer = "notify_transfer"
+	TemplateNotification   = "notification"
+	TemplateTransferOTP    = "transfer_otp"
+	TemplateDeviceOTP      = "device_otp"
+	TemplatePasswordReset  = "password_reset"
+	TemplateStepUpOTP      = "step_up_otp"
+)
+
+// VerifyEmailData is the data of the verify_email template.
+type VerifyEmailData struct {
+	Brand     Brand
+	FullName  string
+	VerifyURL string
+}
+
+// NotifyTransferData is the data of the notify_transfer template, sent to the owner of AccountID.
+type NotifyTransferData struct {
+	Brand          Brand
+	FullName       string
+	AccountID      int64
+	CounterpartyID int64
+	Amount         int64
+	Currency       string
+	Received       bool
+}
+
+// NotificationData is the data of the notification template, the email channel of the notification package.
+type NotificationData struct {
+	Brand    Brand
+	FullName string
+	Title    string
+	Body     string
+}
+
+// TransferOTPData is the data of the transfer_otp template, the one-time code confirming a transfer
+// the fraud rules flagged.
+type TransferOTPData struct {
+	Brand         Brand
+	FullName      string
+	Code          string
+	FromAccountID int64
+	ToAccountID   int64
+	Amount        int64
+	Currency      string
+	ValidMinutes  int
+}
+
+// DeviceOTPData is the data of the device_otp template, the one-time code trusting a device a
+// user logged in from.
+type DeviceOTPData struct {
+	Brand        Brand
+	FullName     string
+	Code         string
+	DeviceName   string
+	ValidMinutes int
+}
+
+// PasswordResetData is the data of the password_reset template, the code resetting the password
+// of a user who reported a login they didn't make.
+type PasswordResetData struct {
+	Brand        Brand
+	FullName     string
+	Code         string
+	ValidMinutes int
+}
+
+// StepUpOTPData is the data of the step_up_otp template, the one-time code a user steps up to a
+// multi-factor authentication with.
+type StepUpOTPData struct {
+	Brand        Brand
+	FullName     string
+	Code         string
+	ValidMinutes int
+}
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = parseTemplates(TemplateVerifyEmail, TemplateNotifyTransfer, TemplateNotification, TemplateTransferOTP, TemplateDeviceOTP, TemplatePasswordReset, TemplateStepUpOTP)
+
+var templateFuncs = template.FuncMap{
+	// brand is the brand the templates show, DefaultBrand for a zero one
+	"brand": func(brand Brand) Brand {
+		if brand.Name == "" {
+			return DefaultBrand
+		}
+		return brand
+	},
+	// money writes an amount in minor units in units of its currency, e.g. 12.34 for 1234 USD
+	"money": money.Format,
+}
+
+// parseTemplates parses every email template with the shared layout. Each template defines
+// a "subject" and a "body", which the layout wraps under the Brand of the data.
+func parseTemplates(names ...string) map[string]*template.Template {
+	parsed := make(map[string]*template.Template, len(names))
+	for _, name := range names {
+		parsed[name] = template.Must(template.New(name).Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+	}
+	return parsed
+}
+
+// Render renders the subject and the content of the email template name with data. The templates
+// present the bank with the Brand of data, DefaultBrand when it has none, but the sender name of
+// the message is left to the caller.
+func Render(name string, data any) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, content bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject of %s: %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&content, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render content of %s: %w", name, err)
+	}
+
+	return Message{
+		// the subject is a header, not html, so it must not keep the escaping of html/template
+		Subject: html.UnescapeString(strings.TrimSpace(subject.String())),
+		Content: content.String(),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	msg, err := Render(TemplateVerifyEmail, VerifyEmailData{
+		FullName:  "Tom & Jerry",
+		VerifyURL: "http://localhost:3000/verify_email?username=tom",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Verify your email address", msg.Subject)
+	require.Contains(t, msg.Content, "Hello Tom &amp; Jerry,")
+	require.Contains(t, msg.Content, `href="http://localhost:3000/verify_email?username=tom"`)
+	require.Contains(t, msg.Content, "<title>Verify your email address</title>")
+	require.Contains(t, msg.Content, "registering with Simple Bank")
+
+	msg, err = Render(TemplateNotifyTransfer, NotifyTransferData{
+		FullName:       "Tom",
+		AccountID:      1,
+		CounterpartyID: 2,
+		Amount:         10,
+		Currency:       "USD",
+		Received:       true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "You received a transfer", msg.Subject)
+	require.Contains(t, msg.Content, "Your account #1 received 0.10 USD from account #2.")
+
+	brand := Brand{Name: "Acme Bank", SenderName: "Acme", SupportEmail: "help@acme.com"}
+	msg, err = Render(TemplateVerifyEmail, VerifyEmailData{FullName: "Tom", Brand: brand})
+	require.NoError(t, err)
+	require.Contains(t, msg.Content, "registering with Acme Bank")
+	require.Contains(t, msg.Content, "help@acme.com")
+	require.NotContains(t, msg.Content, "Simple Bank")
+	brand.Apply(&msg)
+	require.Equal(t, "Acme", msg.FromName)
+
+	_, err = Render("unknown", nil)
+	require.Error(t, err)
+}
@@ -3,24 +3,48 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/backendmaster/simple_bank/api"
+	"github.com/backendmaster/simple_bank/auditlog"
+	"github.com/backendmaster/simple_bank/cache"
+	"github.com/backendmaster/simple_bank/compression"
+	"github.com/backendmaster/simple_bank/crossorigin"
+	"github.com/backendmaster/simple_bank/currency"
 	"github.com/backendmaster/simple_bank/db/gorm"
+	"github.com/backendmaster/simple_bank/db/migration"
 	db "github.com/backendmaster/simple_bank/db/sqlc"
 	"github.com/backendmaster/simple_bank/delivery"
+	"github.com/backendmaster/simple_bank/docs/swagger"
+	"github.com/backendmaster/simple_bank/eventbus"
+	"github.com/backendmaster/simple_bank/events"
+	"github.com/backendmaster/simple_bank/fx"
 	"github.com/backendmaster/simple_bank/gapi"
+	"github.com/backendmaster/simple_bank/health"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/maintenance"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/backendmaster/simple_bank/notification"
 	"github.com/backendmaster/simple_bank/pb"
+	"github.com/backendmaster/simple_bank/ratelimit"
+	"github.com/backendmaster/simple_bank/recovery"
+	"github.com/backendmaster/simple_bank/tracing"
 	"github.com/backendmaster/simple_bank/util"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	_ "github.com/lib/pq"
-	"github.com/rs/zerolog"
+	"github.com/backendmaster/simple_bank/version"
+	"github.com/backendmaster/simple_bank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func main() {
@@ -29,18 +53,152 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Load Config Failed: ")
 	}
+	err = util.ConfigureLogger(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not configure logger ")
+	}
+	err = util.SetTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid TRUSTED_PROXIES")
+	}
 
-	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(config, os.Args[2:])
+		return
+	}
+	log.Info().Str("commit", version.Commit).Str("build time", version.BuildTime).Msg("starting simple bank")
+
+	shutdownTracing, err := tracing.Start(context.Background(), config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not start tracing ")
+	}
+	defer shutdownTracing(context.Background())
+
+	connPool, err := db.NewPool(context.Background(), config)
 	if err != nil {
 		log.Fatal().Err(err).Msg("can't not connect to database ")
 	}
+	if config.MigrateOnStart {
+		runMigrations(context.Background(), connPool)
+	}
+	err = metrics.RegisterDBPoolStats(connPool, "simple_bank")
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not register database metrics ")
+	}
 
-	// store := db.NewStore(conn)
+	var replica *db.Replica
+	if config.DBReplicaSource != "" {
+		replicaConfig := config
+		replicaConfig.DBSource = config.DBReplicaSource
+		replicaPool, err := db.NewPool(context.Background(), replicaConfig)
+		if err != nil {
+			log.Fatal().Err(err).Msg("can't not connect to read replica ")
+		}
+		defer replicaPool.Close()
+		replica = db.NewReplica(replicaPool, config.DBReplicaMaxLag)
+	}
 
-	runGormHttpServer(config, conn)
-	// go runGateWayServer(config, store)
-	// runGrpcServer(config, store)
-	// runGinServer(config, store)
+	balanceLock, err := db.ParseBalanceLock(config.DBBalanceLock)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not parse balance lock ")
+	}
+	store := cache.New(config, db.NewStoreWithOptions(connPool, db.StoreOptions{
+		Replica:     replica,
+		Breaker:     db.NewBreaker(config.DBBreakerThreshold, config.DBBreakerOpenTimeout),
+		BalanceLock: balanceLock,
+	}))
+	err = metrics.RegisterActiveSessions(store.CountActiveSessions)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not register session metrics ")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = currency.Load(ctx, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not load currencies ")
+	}
+	mode, err := loadMaintenanceMode(ctx, config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not load maintenance mode ")
+	}
+
+	redisOpt := asynq.RedisClientOpt{
+		Addr: config.RedisAddress,
+	}
+	retryPolicies, err := worker.ParseRetryPolicies(config.TaskRetryPolicies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not parse task retry policies ")
+	}
+	queueConcurrency, err := worker.ParseQueueConcurrency(config.WorkerConcurrency)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not parse worker concurrency ")
+	}
+	err = metrics.RegisterQueueDepth(worker.NewRedisTaskInspector(redisOpt).QueueDepths)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not register task queue metrics ")
+	}
+	taskDistributor := worker.NewRedisTaskDistributor(redisOpt, retryPolicies)
+	eventPublisher, err := events.NewPublisher(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create event publisher ")
+	}
+	entryBus := eventbus.NewBus(connPool)
+	var workers sync.WaitGroup
+	workers.Add(6)
+	go func() {
+		defer workers.Done()
+		runTaskProcessor(ctx, config, redisOpt, store, retryPolicies, queueConcurrency)
+	}()
+	go func() {
+		defer workers.Done()
+		worker.NewOutboxRelay(store, taskDistributor, config.OutboxInterval).Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		worker.NewEventRelay(store, eventPublisher, config.EventInterval).Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		currency.NewRefresher(store, config.CurrencyRefreshInterval).Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		maintenance.NewRefresher(mode, store, config.MaintenanceInterval).Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		if err := entryBus.Run(ctx); err != nil {
+			log.Error().Err(err).Msg("can't not run event bus ")
+		}
+	}()
+	if replica != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			replica.Run(ctx)
+		}()
+	}
+
+	// gorm still needs a database/sql handle
+	conn := db.OpenDB(config, connPool)
+	runGormHttpServer(ctx, config, conn, store, mode)
+	// go runGateWayServer(ctx, config, store, mode)
+	// runGrpcServer(ctx, config, store, mode)
+	// runGinServer(ctx, config, store, mode, entryBus)
+	workers.Wait()
+
+	err = eventPublisher.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("can't not close event publisher ")
+	}
+	// the servers and the workers are drained by now, so nothing is left using the pool
+	err = conn.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("can't not close database ")
+	}
+	connPool.Close()
 }
 
 // type User struct {
@@ -92,27 +250,183 @@ func main() {
 // 	json.NewEncoder(w).Encode(rsp)
 // }
 
-func runGormHttpServer(config util.Config, conn *sql.DB) {
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-	server, err := delivery.NewGormServer(config, conn)
+func runTaskProcessor(
+	ctx context.Context,
+	config util.Config,
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	retryPolicies worker.RetryPolicies,
+	queueConcurrency worker.QueueConcurrency,
+) {
+	mailer, err := mail.NewEmailSender(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create email sender ")
+	}
+
+	channels, err := notification.NewChannels(config, mailer)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create notification channels ")
+	}
+	notifier := notification.NewNotifier(store, channels)
+
+	fxProvider, err := fx.NewProvider(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create fx provider ")
+	}
+
+	taskProcessor := worker.NewRedisTaskProcessor(config, redisOpt, store, retryPolicies, queueConcurrency, mailer, notifier, fxProvider)
+	log.Info().Msg("start task processor")
+	err = taskProcessor.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not start task processor ")
+	}
+
+	scheduler, err := worker.NewScheduler(redisOpt, map[string]string{
+		worker.TaskVerifyLedger:             config.LedgerVerifySchedule,
+		worker.TaskVerifyAuditLogs:          config.AuditVerifySchedule,
+		worker.TaskCreatePartitions:         config.PartitionSchedule,
+		worker.TaskArchiveEntries:           config.ArchiveSchedule,
+		worker.TaskDeleteExpiredExports:     config.ExportCleanupSchedule,
+		worker.TaskSettleExternalTransfers:  config.SettlementSchedule,
+		worker.TaskExpireAuthorizationHolds: config.HoldExpirySchedule,
+		worker.TaskCollectLoanInstallments:  config.LoanRepaymentSchedule,
+		worker.TaskQualifyReferrals:         config.ReferralSchedule,
+		worker.TaskRefreshExchangeRates:     config.FXRefreshSchedule,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not create task scheduler ")
+	}
+	err = scheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not start task scheduler ")
+	}
+	defer scheduler.Shutdown()
+
+	<-ctx.Done()
+	log.Info().Msg("graceful shutdown task processor")
+	// blocks until the in-flight tasks are done, or requeued once SHUTDOWN_TIMEOUT is over
+	taskProcessor.Shutdown()
+	log.Info().Msg("task processor stopped")
+}
+
+// loadMaintenanceMode loads the maintenance mode shared by the instances, after switching it on
+// when READ_ONLY or TRANSFERS_BLOCKED asks for it.
+func loadMaintenanceMode(ctx context.Context, config util.Config, store db.Store) (*maintenance.Mode, error) {
+	if config.ReadOnly {
+		if _, err := store.SetMaintenanceReadOnly(ctx, true); err != nil {
+			return nil, err
+		}
+	}
+	if config.TransfersBlocked {
+		if _, err := store.SetMaintenanceTransfersBlocked(ctx, true); err != nil {
+			return nil, err
+		}
+	}
+	mode := maintenance.NewMode(false)
+	if err := mode.Load(ctx, store); err != nil {
+		return nil, err
+	}
+	return mode, nil
+}
+
+func runMigrations(ctx context.Context, connPool *pgxpool.Pool) {
+	migrator, err := migration.NewMigrator(connPool)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not load migrations ")
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not run migrations ")
+	}
+	log.Info().Int("applied", applied).Msg("database is up to date")
+}
+
+// runMigrateCommand runs "migrate up", "migrate down [N]", "migrate version" or "migrate force V".
+func runMigrateCommand(config util.Config, args []string) {
+	ctx := context.Background()
+	connPool, err := db.NewPool(ctx, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not connect to database ")
+	}
+	defer connPool.Close()
+	migrator, err := migration.NewMigrator(connPool)
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not load migrations ")
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		runMigrations(ctx, connPool)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatal().Str("steps", args[1]).Msg("invalid number of migrations to revert ")
+			}
+		}
+		err = migrator.Down(ctx, steps)
+	case "version":
+		var current uint
+		var dirty bool
+		current, dirty, err = migrator.Version(ctx)
+		if err == nil {
+			log.Info().Uint("version", current).Bool("dirty", dirty).Uint("latest", migration.LatestVersion()).Msg("migration version")
+		}
+	case "force":
+		if len(args) < 2 {
+			log.Fatal().Msg("usage: migrate force VERSION ")
+		}
+		var forced uint64
+		forced, err = strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			log.Fatal().Str("version", args[1]).Msg("invalid migration version ")
+		}
+		err = migrator.Force(ctx, uint(forced))
+	default:
+		log.Fatal().Str("command", command).Msg("unknown migrate command, expected up, down, version or force ")
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("can't not run migrate " + command + " ")
+	}
+}
+
+func runGormHttpServer(ctx context.Context, config util.Config, conn *sql.DB, store db.Store, mode *maintenance.Mode) {
+	server, err := delivery.NewGormServer(config, conn, store, mode)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Can't not create gapi server ")
 	}
-	err = server.Start(config.HTTPServerAddress)
 
+	go func() {
+		log.Info().Msgf("start http server at %s", config.HTTPServerAddress)
+		err := server.Start(config.HTTPServerAddress)
+		if err != nil {
+			log.Fatal().Err(err).Msg("can't not start server ")
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info().Msg("graceful shutdown http server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Fatal().Err(err).Msg("can't not start server ")
+		log.Error().Err(err).Msg("can't not shutdown http server gracefully ")
 	}
 }
-func runGrpcServer(config util.Config, store db.Store) {
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+func runGrpcServer(ctx context.Context, config util.Config, store db.Store, mode *maintenance.Mode) {
 	server, err := gapi.NewServer(config, store)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Can't not create gapi server ")
 	}
 
-	grpcLogger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(grpcLogger)
+	interceptors := grpc.ChainUnaryInterceptor(tracing.GrpcTracing(), gapi.GrpcLogger(util.NewRedactor(config.RedactedFields)), metrics.GrpcMetrics, maintenance.GrpcReadOnly(mode), recovery.GrpcRecovery,
+		auditlog.GrpcAuditLog(auditlog.New(config, store), server.AuditActor))
+	grpcServer := grpc.NewServer(interceptors)
 	pb.RegisterSimpleBankServer(grpcServer, server)
 	reflection.Register(grpcServer)
 
@@ -121,28 +435,41 @@ func runGrpcServer(config util.Config, store db.Store) {
 		log.Fatal().Err(err).Msg("can't not create listener ")
 	}
 
-	log.Info().Msgf("start grpc server at %s", listener.Addr().String())
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal().Err(err).Msg("can't not create grpc server ")
-	}
+	go func() {
+		log.Info().Msgf("start grpc server at %s", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			log.Fatal().Err(err).Msg("can't not create grpc server ")
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info().Msg("graceful shutdown grpc server")
+	gracefulStopGrpc(grpcServer, config.ShutdownTimeout)
+}
 
+// gracefulStopGrpc waits for the in-flight rpcs to finish, cancelling them once timeout is over.
+func gracefulStopGrpc(server *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Warn().Msg("grpc server didn't stop in time, cancelling in-flight rpcs")
+		server.Stop()
+	}
 }
-func runGateWayServer(config util.Config, store db.Store) {
+func runGateWayServer(ctx context.Context, config util.Config, store db.Store, mode *maintenance.Mode) {
 	server, err := gapi.NewServer(config, store)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Can't not create gapi server ")
 	}
 
-	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-		MarshalOptions: protojson.MarshalOptions{
-			UseProtoNames: true,
-		},
-		UnmarshalOptions: protojson.UnmarshalOptions{
-			DiscardUnknown: true,
-		},
-	})
-	grpcMux := runtime.NewServeMux(jsonOption)
+	grpcMux := gapi.NewGatewayMux()
 
 	contex, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -152,32 +479,71 @@ func runGateWayServer(config util.Config, store db.Store) {
 		log.Fatal().Err(err).Msg("can not register handler server ")
 	}
 
+	auditLog := auditlog.New(config, store)
 	mux := http.NewServeMux()
-	mux.Handle("/", grpcMux)
+	// the in-process calls of the gateway skip the interceptors, their requests are logged instead
+	mux.Handle("/", auditlog.HttpAuditLog(auditLog, server.HttpAuditActor, grpcMux))
+
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", health.Liveness())
+	mux.Handle("/version", version.Handler())
+	mux.Handle("/readyz", health.Readiness(store))
+	mux.Handle("/swagger/", swagger.Handler("/swagger/"))
+
+	redactor := util.NewRedactor(config.RedactedFields)
+	grpcWebServer := grpc.NewServer(grpc.ChainUnaryInterceptor(tracing.GrpcTracing(), gapi.GrpcLogger(redactor), metrics.GrpcMetrics, maintenance.GrpcReadOnly(mode), recovery.GrpcRecovery,
+		auditlog.GrpcAuditLog(auditLog, server.AuditActor)))
+	pb.RegisterSimpleBankServer(grpcWebServer, server)
 
 	listener, err := net.Listen("tcp", config.HTTPServerAddress)
 	if err != nil {
 		log.Fatal().Err(err).Msg("can't not create listener ")
 	}
 
-	log.Info().Msgf("start http gateway server at %s", listener.Addr().String())
-	hander := gapi.HttpLogger(mux)
-	err = http.Serve(listener, hander)
+	hander := gapi.RequestID(tracing.HttpTracing(gapi.HttpLogger(redactor, metrics.HttpMetrics(recovery.HttpRecovery(gapi.GrpcWebHandler(grpcWebServer, crossorigin.HttpCors(config, compression.HttpCompression(config.CompressionMinSize, maintenance.HttpReadOnly(mode, ratelimit.HttpRateLimit(ratelimit.New(config), gapi.GatewayErrorHandler, mux, "/v1/create_user", "/v1/login_user")))), config.AllowedOrigins))))))
+	httpServer := &http.Server{Handler: hander}
+	go func() {
+		log.Info().Msgf("start http gateway server at %s", listener.Addr().String())
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("can't not create http gateway server ")
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info().Msg("graceful shutdown http gateway server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	err = httpServer.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Fatal().Err(err).Msg("can't not create http gateway server ")
+		log.Error().Err(err).Msg("can't not shutdown http gateway server gracefully ")
 	}
-
+	grpcWebServer.Stop()
 }
-func runGinServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
+func runGinServer(ctx context.Context, config util.Config, store db.Store, mode *maintenance.Mode, entryBus *eventbus.Bus) {
+	taskInspector := worker.NewRedisTaskInspector(asynq.RedisClientOpt{
+		Addr: config.RedisAddress,
+	})
+	server, err := api.NewServer(config, store, mode, taskInspector, entryBus)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Can't not create gin server ")
 	}
 
-	err = server.Start(config.HTTPServerAddress)
-
+	go func() {
+		log.Info().Msgf("start gin server at %s", config.HTTPServerAddress)
+		err := server.Start(config.HTTPServerAddress)
+		if err != nil {
+			log.Fatal().Err(err).Msg("can't not start server ")
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info().Msg("graceful shutdown gin server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
 	if err != nil {
-		log.Fatal().Err(err).Msg("can't not start server ")
+		log.Error().Err(err).Msg("can't not shutdown gin server gracefully ")
 	}
 }
 
@@ -0,0 +1,200 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// retryAfter is the Retry-After, in seconds, sent with the responses refused in read-only mode.
+const retryAfter = "60"
+
+const readOnlyMessage = "service is in read-only mode for maintenance, retry later"
+
+const defaultRefreshInterval = 5 * time.Second
+
+// ErrTransfersBlocked is returned for the transfers created while the kill switch is on. It is the
+// error of the store too, which refuses them even when a route or a task doesn't check the mode.
+var ErrTransfersBlocked = db.ErrTransfersBlocked
+
+// Mode holds the read-only flag and the kill switch of the transfers. Both are safe to toggle
+// while requests are being served. The mode of an instance is a copy of the one shared by every
+// instance in the maintenance_modes table, kept in sync by Load and a Refresher.
+type Mode struct {
+	readOnly         atomic.Bool
+	transfersBlocked atomic.Bool
+}
+
+// NewMode creates a mode starting as read-only or not.
+func NewMode(readOnly bool) *Mode {
+	mode := &Mode{}
+	mode.readOnly.Store(readOnly)
+	return mode
+}
+
+func (mode *Mode) ReadOnly() bool {
+	return mode.readOnly.Load()
+}
+
+func (mode *Mode) SetReadOnly(readOnly bool) {
+	mode.readOnly.Store(readOnly)
+}
+
+// TransfersBlocked tells whether the kill switch blocks the new transfers. Unlike read-only mode,
+// it leaves every other write alone.
+func (mode *Mode) TransfersBlocked() bool {
+	return mode.transfersBlocked.Load()
+}
+
+func (mode *Mode) SetTransfersBlocked(blocked bool) {
+	mode.transfersBlocked.Store(blocked)
+}
+
+// Set switches the mode to shared, a row of the maintenance_modes table.
+func (mode *Mode) Set(shared db.MaintenanceMode) {
+	mode.SetReadOnly(shared.ReadOnly)
+	mode.SetTransfersBlocked(shared.TransfersBlocked)
+}
+
+// Store is what the mode needs of db.Store.
+type Store interface {
+	GetMaintenanceMode(ctx context.Context) (db.MaintenanceMode, error)
+}
+
+// Load switches the mode to the one shared by the instances.
+func (mode *Mode) Load(ctx context.Context, store Store) error {
+	shared, err := store.GetMaintenanceMode(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance mode: %w", err)
+	}
+	mode.Set(shared)
+	return nil
+}
+
+// Refresher reloads the mode every interval, so that an admin switching it through another
+// instance switches this one too.
+type Refresher struct {
+	mode     *Mode
+	store    Store
+	interval time.Duration
+}
+
+func NewRefresher(mode *Mode, store Store, interval time.Duration) *Refresher {
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+	return &Refresher{mode: mode, store: store, interval: interval}
+}
+
+// Run reloads the mode every interval until ctx is done. The mode stays as it is when a reload
+// fails.
+func (refresher *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(refresher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := refresher.mode.Load(ctx, refresher.store); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to reload maintenance mode")
+			}
+		}
+	}
+}
+
+// CheckTransfers returns ErrTransfersBlocked while the kill switch is on.
+func (mode *Mode) CheckTransfers() error {
+	if mode.TransfersBlocked() {
+		return ErrTransfersBlocked
+	}
+	return nil
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// HttpReadOnly refuses every request but GET, HEAD and OPTIONS with a 503 while mode is read-only.
+func HttpReadOnly(mode *Mode, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if mode.ReadOnly() && !isReadMethod(req.Method) {
+			res.Header().Set("Retry-After", retryAfter)
+			res.Header().Set("Content-Type", "application/json")
+			res.WriteHeader(http.StatusServiceUnavailable)
+			res.Write([]byte(`{"err":"` + readOnlyMessage + `"}`))
+			return
+		}
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// GinReadOnly is the gin version of HttpReadOnly. Routes in exempt, like the one switching
+// the mode back off, are served in read-only mode too.
+func GinReadOnly(mode *Mode, exempt ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if mode.ReadOnly() && !isReadMethod(ctx.Request.Method) && !isExempt(ctx.FullPath(), exempt) {
+			ctx.Header("Retry-After", retryAfter)
+			envelope.Abort(ctx, http.StatusServiceUnavailable, readOnlyMessage)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// GinBlockTransfers refuses the requests of the route creating transfers with a 503 while the kill
+// switch of mode is on.
+func GinBlockTransfers(mode *Mode) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := mode.CheckTransfers(); err != nil {
+			ctx.Header("Retry-After", retryAfter)
+			envelope.Abort(ctx, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func isExempt(route string, exempt []string) bool {
+	for _, exemptRoute := range exempt {
+		if route == exemptRoute {
+			return true
+		}
+	}
+	return false
+}
+
+// GrpcReadOnly refuses the rpcs that aren't reads with Unavailable while mode is read-only.
+// An rpc is a read when its name starts with Get or List.
+func GrpcReadOnly(mode *Mode) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if mode.ReadOnly() && !isReadRpc(info.FullMethod) {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+			return nil, status.Error(codes.Unavailable, readOnlyMessage)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func isReadRpc(fullMethod string) bool {
+	name := path.Base(fullMethod)
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
@@ -0,0 +1,115 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHttpReadOnly(t *testing.T) {
+	mode := NewMode(true)
+	handler := HttpReadOnly(mode, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := []struct {
+		name     string
+		method   string
+		readOnly bool
+		expected int
+	}{
+		{name: "ReadOnlyGet", method: http.MethodGet, readOnly: true, expected: http.StatusOK},
+		{name: "ReadOnlyPost", method: http.MethodPost, readOnly: true, expected: http.StatusServiceUnavailable},
+		{name: "ReadOnlyDelete", method: http.MethodDelete, readOnly: true, expected: http.StatusServiceUnavailable},
+		{name: "ReadWritePost", method: http.MethodPost, readOnly: false, expected: http.StatusOK},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			mode.SetReadOnly(tc.readOnly)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, "/v1/create_user", nil)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expected, recorder.Code)
+			if tc.expected == http.StatusServiceUnavailable {
+				require.Equal(t, retryAfter, recorder.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestGrpcReadOnly(t *testing.T) {
+	interceptor := GrpcReadOnly(NewMode(true))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pb.simple_bank/CreateUser"}, handler)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	rsp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pb.simple_bank/GetUser"}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", rsp)
+}
+
+func TestGinBlockTransfers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mode := NewMode(false)
+	router := gin.New()
+	router.POST("/transfers", GinBlockTransfers(mode), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, blocked := range []bool{false, true} {
+		mode.SetTransfersBlocked(blocked)
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/transfers", nil)
+		require.NoError(t, err)
+		router.ServeHTTP(recorder, request)
+
+		if blocked {
+			require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			require.Equal(t, retryAfter, recorder.Header().Get("Retry-After"))
+			require.ErrorIs(t, mode.CheckTransfers(), ErrTransfersBlocked)
+		} else {
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.NoError(t, mode.CheckTransfers())
+		}
+	}
+	// the kill switch doesn't make the mode read-only
+	require.False(t, mode.ReadOnly())
+}
+
+func TestLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockMaintenanceStore(ctrl)
+	store.EXPECT().GetMaintenanceMode(gomock.Any()).Times(1).Return(db.MaintenanceMode{ID: true, ReadOnly: true, TransfersBlocked: true}, nil)
+
+	mode := NewMode(false)
+	require.NoError(t, mode.Load(context.Background(), store))
+	require.True(t, mode.ReadOnly())
+	require.True(t, mode.TransfersBlocked())
+
+	// a failed reload keeps the mode loaded so far
+	store.EXPECT().GetMaintenanceMode(gomock.Any()).Times(1).Return(db.MaintenanceMode{}, errors.New("connection refused"))
+	require.Error(t, mode.Load(context.Background(), store))
+	require.True(t, mode.ReadOnly())
+	require.True(t, mode.TransfersBlocked())
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons a login can fail, used as the reason label of login_failures_total.
+const (
+	LoginUserNotFound  = "user_not_found"
+	LoginWrongPassword = "wrong_password"
+)
+
+const activeSessionsTimeout = 2 * time.Second
+
+var (
+	transfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Number of transfers created by currency.",
+	}, []string{"currency"})
+
+	transferAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_amount_total",
+		Help:      "Sum of the amounts transferred by currency.",
+	}, []string{"currency"})
+
+	signupsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signups_total",
+		Help:      "Number of users created.",
+	})
+
+	ledgerDiscrepancies = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_discrepancies",
+		Help:      "Number of discrepancies found by the last ledger verification by kind.",
+	}, []string{"kind"})
+
+	auditLogChainProblems = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "audit_log_chain_problems",
+		Help:      "Number of audit logs breaking the hash chain found by the last verification.",
+	})
+
+	exchangeRatesFetchedTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_rates_fetched_timestamp_seconds",
+		Help:      "Unix time the exchange rates were last fetched from the FX provider.",
+	})
+
+	exchangeRatesStale = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_rates_stale",
+		Help:      "1 when the last refresh found the exchange rates too old to convert at, else 0.",
+	})
+
+	loginFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Number of failed logins by reason.",
+	}, []string{"reason"})
+
+	activeSessionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "active_sessions"),
+		"Number of sessions that are neither expired nor blocked.",
+		nil, nil,
+	)
+)
+
+// ObserveTransfer counts a successful transfer and adds its amount to the volume of its currency.
+func ObserveTransfer(currency string, amount int64) {
+	transfersTotal.WithLabelValues(currency).Inc()
+	transferAmountTotal.WithLabelValues(currency).Add(float64(amount))
+}
+
+// ObserveSignup counts a newly created user.
+func ObserveSignup() {
+	signupsTotal.Inc()
+}
+
+// ObserveLoginFailure counts a failed login, reason being one of the Login* constants.
+func ObserveLoginFailure(reason string) {
+	loginFailuresTotal.WithLabelValues(reason).Inc()
+}
+
+// ObserveLedgerVerification records the discrepancies found by a ledger verification, so that
+// an alert can fire on them without anyone reading the logs.
+func ObserveLedgerVerification(balanceMismatches int, unbalancedTransfers int) {
+	ledgerDiscrepancies.WithLabelValues("balance_mismatch").Set(float64(balanceMismatches))
+	ledgerDiscrepancies.WithLabelValues("unbalanced_transfer").Set(float64(unbalancedTransfers))
+}
+
+// ObserveAuditLogVerification records the audit logs breaking the hash chain found by a verification.
+func ObserveAuditLogVerification(problems int) {
+	auditLogChainProblems.Set(float64(problems))
+}
+
+// ObserveExchangeRates records when the exchange rates were last fetched and whether they are too
+// old to convert at, in which case the transfers between currencies fail until they are fetched.
+func ObserveExchangeRates(fetchedAt time.Time, stale bool) {
+	if fetchedAt.IsZero() {
+		// never fetched
+		exchangeRatesFetchedTimestamp.Set(0)
+	} else {
+		exchangeRatesFetchedTimestamp.Set(float64(fetchedAt.Unix()))
+	}
+	if stale {
+		exchangeRatesStale.Set(1)
+	} else {
+		exchangeRatesStale.Set(0)
+	}
+}
+
+// activeSessionsCollector counts the active sessions when metrics are scraped,
+// since sessions stop being active by expiring rather than through a request we could observe.
+type activeSessionsCollector struct {
+	count func(ctx context.Context) (int64, error)
+}
+
+func (collector activeSessionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeSessionsDesc
+}
+
+func (collector activeSessionsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), activeSessionsTimeout)
+	defer cancel()
+
+	count, err := collector.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(activeSessionsDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(activeSessionsDesc, prometheus.GaugeValue, float64(count))
+}
+
+// RegisterActiveSessions exposes the number of active sessions, as returned by count on every scrape.
+func RegisterActiveSessions(count func(ctx context.Context) (int64, error)) error {
+	return prometheus.Register(activeSessionsCollector{count: count})
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ObserveDBQuery records the duration of a database query and whether it failed.
+func ObserveDBQuery(query string, duration time.Duration, err error) {
+	dbQueryDuration.WithLabelValues(query).Observe(duration.Seconds())
+	if err != nil {
+		dbQueryErrorsTotal.WithLabelValues(query).Inc()
+	}
+}
+
+// ObserveDBSlowQuery counts a database query that took longer than the slow query threshold.
+func ObserveDBSlowQuery(query string) {
+	dbSlowQueriesTotal.WithLabelValues(query).Inc()
+}
+
+// ObserveDBTxRetry counts a transaction retried after it failed with the postgres error code.
+func ObserveDBTxRetry(tx string, code string) {
+	dbTxRetriesTotal.WithLabelValues(tx, code).Inc()
+}
+
+// ObserveDBBreaker records whether the circuit breaker of the database is open.
+func ObserveDBBreaker(open bool) {
+	if open {
+		dbBreakerOpen.Set(1)
+	} else {
+		dbBreakerOpen.Set(0)
+	}
+}
+
+// dbPoolCollector reads the stats of the connection pool when metrics are scraped.
+type dbPoolCollector struct {
+	stat func() *pgxpool.Stat
+
+	maxConns          *prometheus.Desc
+	totalConns        *prometheus.Desc
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	acquiresTotal     *prometheus.Desc
+	emptyAcquireTotal *prometheus.Desc
+	acquireSeconds    *prometheus.Desc
+}
+
+func newDBPoolCollector(stat func() *pgxpool.Stat, dbName string) dbPoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help,
+			nil, prometheus.Labels{"db_name": dbName})
+	}
+	return dbPoolCollector{
+		stat:              stat,
+		maxConns:          desc("max_connections", "Maximum number of connections of the pool."),
+		totalConns:        desc("connections", "Number of open connections, in use or idle."),
+		acquiredConns:     desc("acquired_connections", "Number of connections in use."),
+		idleConns:         desc("idle_connections", "Number of idle connections."),
+		acquiresTotal:     desc("acquires_total", "Number of connections acquired from the pool."),
+		emptyAcquireTotal: desc("empty_acquires_total", "Number of acquires that had to wait for a connection."),
+		acquireSeconds:    desc("acquire_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+func (collector dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.maxConns
+	ch <- collector.totalConns
+	ch <- collector.acquiredConns
+	ch <- collector.idleConns
+	ch <- collector.acquiresTotal
+	ch <- collector.emptyAcquireTotal
+	ch <- collector.acquireSeconds
+}
+
+func (collector dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := collector.stat()
+	ch <- prometheus.MustNewConstMetric(collector.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(collector.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(collector.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(collector.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(collector.acquiresTotal, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(collector.emptyAcquireTotal, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(collector.acquireSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+// RegisterDBPoolStats exposes the stats (open, in use, idle, acquire wait...) of the connection pool.
+func RegisterDBPoolStats(pool *pgxpool.Pool, dbName string) error {
+	return prometheus.Register(newDBPoolCollector(pool.Stat, dbName))
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcMetrics records request count, latency and in-flight requests of every unary call.
+func GrpcMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	timeNow := time.Now()
+	inFlight := grpcRequestsInFlight.WithLabelValues(info.FullMethod)
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	result, err := handler(ctx, req)
+
+	grpcRequestsTotal.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	grpcRequestDuration.WithLabelValues(info.FullMethod).Observe(time.Since(timeNow).Seconds())
+	return result, err
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unknownRoute = "unknown"
+
+type routeKey struct{}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	size       int
+}
+
+func (rec *statusRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *statusRecorder) Write(body []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(body)
+	rec.size += n
+	return n, err
+}
+
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// HttpMetrics records request count, latency, response size and in-flight requests of handler.
+// The route label is set by the handler through SetRoute so that path parameters
+// like usernames don't end up as separate time series.
+func HttpMetrics(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		timeNow := time.Now()
+		inFlight := httpRequestsInFlight.WithLabelValues(req.Method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		route := unknownRoute
+		req = req.WithContext(context.WithValue(req.Context(), routeKey{}, &route))
+		rec := &statusRecorder{
+			ResponseWriter: res,
+			statusCode:     http.StatusOK,
+		}
+		handler.ServeHTTP(rec, req)
+
+		observeHttpRequest(req.Method, route, rec.statusCode, rec.size, time.Since(timeNow))
+	})
+}
+
+// SetRoute reports the route pattern that matched req to HttpMetrics.
+func SetRoute(req *http.Request, route string) {
+	if holder, ok := req.Context().Value(routeKey{}).(*string); ok {
+		*holder = route
+	}
+}
+
+// GinMetrics is the gin version of HttpMetrics, labelling requests with the gin route.
+func GinMetrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeNow := time.Now()
+		inFlight := httpRequestsInFlight.WithLabelValues(ctx.Request.Method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unknownRoute
+		}
+		size := ctx.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		observeHttpRequest(ctx.Request.Method, route, ctx.Writer.Status(), size, time.Since(timeNow))
+	}
+}
+
+func observeHttpRequest(method string, route string, statusCode int, size int, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(statusCode)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+	httpResponseSize.WithLabelValues(method, route).Observe(float64(size))
+}
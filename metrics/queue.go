@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var queueDepthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "task_queue_depth"),
+	"Number of background tasks in a queue by state.",
+	[]string{"queue", "state"}, nil,
+)
+
+// QueueDepth is the number of tasks of a queue in each state.
+type QueueDepth struct {
+	Queue     string
+	Pending   int
+	Active    int
+	Scheduled int
+	Retry     int
+	Archived  int
+}
+
+// queueDepthCollector reads the depth of the task queues from redis when metrics are scraped.
+type queueDepthCollector struct {
+	depths func() ([]QueueDepth, error)
+}
+
+func (collector queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+func (collector queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	depths, err := collector.depths()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	for _, depth := range depths {
+		states := map[string]int{
+			"pending":   depth.Pending,
+			"active":    depth.Active,
+			"scheduled": depth.Scheduled,
+			"retry":     depth.Retry,
+			"archived":  depth.Archived,
+		}
+		for state, count := range states {
+			ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(count), depth.Queue, state)
+		}
+	}
+}
+
+// RegisterQueueDepth exposes the depth of the task queues, as returned by depths on every scrape.
+func RegisterQueueDepth(depths func() ([]QueueDepth, error)) error {
+	return prometheus.Register(queueDepthCollector{depths: depths})
+}
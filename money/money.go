@@ -0,0 +1,115 @@
+// Package money counts the amounts in the minor units of their currency, e.g. the cents of USD,
+// and writes them in its units, with as many decimal places as the registry of util gives it.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/backendmaster/simple_bank/util"
+)
+
+var (
+	// ErrUnknownCurrency is returned for an amount in a currency missing from the registry.
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrInvalidAmount is returned by Parse for a value that is no decimal number, or too large.
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrInvalidPrecision is returned by Parse for a value with more decimal places than its
+	// currency has, e.g. 12.345 USD or 1.5 JPY.
+	ErrInvalidPrecision = errors.New("amount has more decimal places than its currency")
+)
+
+// Money is an amount in the minor units of its currency: 1234 is 12.34 USD but 1234 JPY.
+type Money struct {
+	Amount     int64
+	Currency   string
+	MinorUnits int
+}
+
+// New returns amount, in the minor units of currency, as Money.
+func New(amount int64, currency string) (Money, error) {
+	registered, ok := util.LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	return Money{Amount: amount, Currency: registered.Code, MinorUnits: registered.MinorUnits}, nil
+}
+
+// Parse reads value, a decimal number of units of currency like 12.34 or -0.5, as Money. It
+// returns ErrInvalidPrecision when value has more decimal places than currency, except for
+// trailing zeros.
+func Parse(value, currency string) (Money, error) {
+	registered, ok := util.LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+
+	digits := strings.TrimPrefix(value, "-")
+	negative := len(digits) < len(value)
+	units, fraction, _ := strings.Cut(digits, ".")
+	if units == "" || !isDigits(units) || !isDigits(fraction) || strings.HasSuffix(digits, ".") {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, value)
+	}
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > registered.MinorUnits {
+		return Money{}, fmt.Errorf("%w: %q has %d decimal places, %s has %d", ErrInvalidPrecision, value, len(fraction), registered.Code, registered.MinorUnits)
+	}
+	fraction += strings.Repeat("0", registered.MinorUnits-len(fraction))
+
+	var amount int64
+	for _, digit := range units + fraction {
+		if amount > (math.MaxInt64-int64(digit-'0'))/10 {
+			return Money{}, fmt.Errorf("%w %q: too large", ErrInvalidAmount, value)
+		}
+		amount = amount*10 + int64(digit-'0')
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: registered.Code, MinorUnits: registered.MinorUnits}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal writes the amount in units of the currency, with all its decimal places, e.g. 12.30
+// for 1230 USD, 1230 for 1230 JPY and -0.005 for -5 BHD.
+func (money Money) Decimal() string {
+	// the digits of math.MinInt64 have no positive int64
+	digits := strings.TrimPrefix(fmt.Sprintf("%d", money.Amount), "-")
+	sign := ""
+	if money.Amount < 0 {
+		sign = "-"
+	}
+	if money.MinorUnits <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= money.MinorUnits {
+		digits = strings.Repeat("0", money.MinorUnits-len(digits)+1) + digits
+	}
+	split := len(digits) - money.MinorUnits
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// String writes the amount with its currency, e.g. 12.34 USD.
+func (money Money) String() string {
+	return money.Decimal() + " " + money.Currency
+}
+
+// Format writes amount, in the minor units of currency, in units of currency, e.g. 12.34 for 1234
+// USD. An amount in a currency missing from the registry is written as is.
+func Format(amount int64, currency string) string {
+	money, err := New(amount, currency)
+	if err != nil {
+		return fmt.Sprintf("%d", amount)
+	}
+	return money.Decimal()
+}
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestCurrencies(t *testing.T) {
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, MinorUnits: 2, Enabled: true},
+		{Code: "JPY", MinorUnits: 0, Enabled: true},
+		{Code: "BHD", MinorUnits: 3, Enabled: false},
+	})
+	t.Cleanup(func() {
+		util.SetCurrencies([]util.Currency{
+			{Code: util.USD, MinorUnits: 2, Enabled: true},
+			{Code: util.EUR, MinorUnits: 2, Enabled: true},
+			{Code: util.CAD, MinorUnits: 2, Enabled: true},
+		})
+	})
+}
+
+func TestParse(t *testing.T) {
+	setTestCurrencies(t)
+
+	testCases := []struct {
+		value    string
+		currency string
+		amount   int64
+		err      error
+	}{
+		{value: "12.34", currency: util.USD, amount: 1234},
+		{value: "12.3", currency: util.USD, amount: 1230},
+		{value: "12", currency: util.USD, amount: 1200},
+		{value: "0.05", currency: util.USD, amount: 5},
+		{value: "-7.5", currency: util.USD, amount: -750},
+		// trailing zeros are no extra precision
+		{value: "12.3400", currency: util.USD, amount: 1234},
+		{value: "1500", currency: "JPY", amount: 1500},
+		{value: "1500.0", currency: "JPY", amount: 1500},
+		{value: "1.005", currency: "BHD", amount: 1005},
+		{value: "12.345", currency: util.USD, err: ErrInvalidPrecision},
+		{value: "1.5", currency: "JPY", err: ErrInvalidPrecision},
+		{value: "1.0005", currency: "BHD", err: ErrInvalidPrecision},
+		{value: "", currency: util.USD, err: ErrInvalidAmount},
+		{value: ".5", currency: util.USD, err: ErrInvalidAmount},
+		{value: "5.", currency: util.USD, err: ErrInvalidAmount},
+		{value: "+5", currency: util.USD, err: ErrInvalidAmount},
+		{value: "1,000.00", currency: util.USD, err: ErrInvalidAmount},
+		{value: "1e3", currency: util.USD, err: ErrInvalidAmount},
+		{value: "92233720368547758.08", currency: util.USD, err: ErrInvalidAmount},
+		{value: "10", currency: "XYZ", err: ErrUnknownCurrency},
+	}
+
+	for _, tc := range testCases {
+		money, err := Parse(tc.value, tc.currency)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, "%q %s", tc.value, tc.currency)
+			continue
+		}
+		require.NoError(t, err, "%q %s", tc.value, tc.currency)
+		require.Equal(t, tc.amount, money.Amount, "%q %s", tc.value, tc.currency)
+		require.Equal(t, tc.currency, money.Currency)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	setTestCurrencies(t)
+
+	testCases := []struct {
+		amount   int64
+		currency string
+		decimal  string
+	}{
+		{1234, util.USD, "12.34"},
+		{1230, util.USD, "12.30"},
+		{5, util.USD, "0.05"},
+		{0, util.USD, "0.00"},
+		{-750, util.USD, "-7.50"},
+		{1230, "JPY", "1230"},
+		{-5, "BHD", "-0.005"},
+	}
+
+	for _, tc := range testCases {
+		money, err := New(tc.amount, tc.currency)
+		require.NoError(t, err)
+		require.Equal(t, tc.decimal, money.Decimal())
+		require.Equal(t, tc.decimal+" "+tc.currency, money.String())
+		require.Equal(t, tc.decimal, Format(tc.amount, tc.currency))
+
+		parsed, err := Parse(money.Decimal(), tc.currency)
+		require.NoError(t, err)
+		require.Equal(t, money, parsed)
+	}
+
+	require.Equal(t, "1234", Format(1234, "XYZ"))
+}
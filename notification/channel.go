@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+const (
+	DriverLog    = "log"
+	DriverTwilio = "twilio"
+	DriverFCM    = "fcm"
+)
+
+// Channel delivers notifications to the recipients through one medium.
+type Channel interface {
+	Send(ctx context.Context, recipient Recipient, msg Message) error
+}
+
+// NewChannels creates the email channel on top of mailer, and the sms and push channels of the
+// drivers chosen by SMS_DRIVER and PUSH_DRIVER, which only log the messages by default.
+func NewChannels(config util.Config, mailer mail.EmailSender) (map[string]Channel, error) {
+	channels := map[string]Channel{
+		ChannelEmail: NewEmailChannel(mailer),
+	}
+
+	switch config.SMSDriver {
+	case DriverLog, "":
+		channels[ChannelSMS] = logChannel{channel: ChannelSMS}
+	case DriverTwilio:
+		channels[ChannelSMS] = NewTwilioChannel(config.TwilioAccountSID, config.TwilioAuthToken, config.TwilioFromNumber)
+	default:
+		return nil, fmt.Errorf("unknown sms driver %q", config.SMSDriver)
+	}
+
+	switch config.PushDriver {
+	case DriverLog, "":
+		channels[ChannelPush] = logChannel{channel: ChannelPush}
+	case DriverFCM:
+		channels[ChannelPush] = NewFCMChannel(config.FCMServerKey)
+	default:
+		return nil, fmt.Errorf("unknown push driver %q", config.PushDriver)
+	}
+
+	return channels, nil
+}
+
+// EmailChannel sends notifications as html emails.
+type EmailChannel struct {
+	mailer mail.EmailSender
+}
+
+func NewEmailChannel(mailer mail.EmailSender) Channel {
+	return &EmailChannel{mailer: mailer}
+}
+
+func (channel *EmailChannel) Send(ctx context.Context, recipient Recipient, msg Message) error {
+	email, err := mail.Render(mail.TemplateNotification, mail.NotificationData{
+		FullName: recipient.FullName,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		Brand:    recipient.Brand,
+	})
+	if err != nil {
+		return err
+	}
+	recipient.Brand.Apply(&email)
+	email.To = []string{recipient.Email}
+	return channel.mailer.SendEmail(ctx, email)
+}
+
+// logChannel only logs the notifications, for development and for channels without a provider.
+type logChannel struct {
+	channel string
+}
+
+func (channel logChannel) Send(ctx context.Context, recipient Recipient, msg Message) error {
+	log.Info().Str("channel", channel.channel).Str("username", recipient.Username).
+		Str("title", msg.Title).Msg("sent notification")
+	return nil
+}
@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRecipient = Recipient{
+	Username:    "tom",
+	PhoneNumber: "+14155552671",
+	PushToken:   "device-token",
+}
+
+func TestTwilioChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/2010-04-01/Accounts/sid/Messages.json", req.URL.Path)
+		username, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "sid", username)
+		require.Equal(t, "token", password)
+		require.NoError(t, req.ParseForm())
+		require.Equal(t, testRecipient.PhoneNumber, req.PostForm.Get("To"))
+		require.Equal(t, "Title\nBody", req.PostForm.Get("Body"))
+		res.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	channel := NewTwilioChannel("sid", "token", "+15005550006").(*TwilioChannel)
+	channel.host = server.URL
+	require.NoError(t, channel.Send(context.Background(), testRecipient, Message{Title: "Title", Body: "Body"}))
+}
+
+func TestFCMChannel(t *testing.T) {
+	result := `{"success":1,"failure":0}`
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "key=server-key", req.Header.Get("Authorization"))
+		var msg fcmMessage
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&msg))
+		require.Equal(t, testRecipient.PushToken, msg.To)
+		require.Equal(t, "Title", msg.Notification.Title)
+		res.Write([]byte(result))
+	}))
+	defer server.Close()
+
+	channel := NewFCMChannel("server-key").(*FCMChannel)
+	channel.endpoint = server.URL
+	require.NoError(t, channel.Send(context.Background(), testRecipient, Message{Title: "Title", Body: "Body"}))
+
+	result = `{"success":0,"failure":1,"results":[{"error":"NotRegistered"}]}`
+	err := channel.Send(context.Background(), testRecipient, Message{Title: "Title", Body: "Body"})
+	require.ErrorContains(t, err, "NotRegistered")
+}
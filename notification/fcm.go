@@ -0,0 +1,80 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const fcmEndpoint = "https://fcm.googleapis.com/fcm/send"
+
+// FCMChannel sends notifications as push notifications through the http api of Firebase Cloud Messaging.
+type FCMChannel struct {
+	serverKey string
+	endpoint  string
+	client    *http.Client
+}
+
+func NewFCMChannel(serverKey string) Channel {
+	return &FCMChannel{
+		serverKey: serverKey,
+		endpoint:  fcmEndpoint,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmMessage struct {
+	To           string          `json:"to"`
+	Notification fcmNotification `json:"notification"`
+}
+
+type fcmResponse struct {
+	Failure int `json:"failure"`
+	Results []struct {
+		Error string `json:"error"`
+	} `json:"results"`
+}
+
+func (channel *FCMChannel) Send(ctx context.Context, recipient Recipient, msg Message) error {
+	body, err := json.Marshal(fcmMessage{
+		To:           recipient.PushToken,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+channel.serverKey)
+
+	rsp, err := channel.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push through fcm: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	rspBody, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fcm rejected the push with status %d: %s", rsp.StatusCode, rspBody)
+	}
+
+	// fcm answers 200 even when the token is invalid, with the error in the results
+	var result fcmResponse
+	if err := json.Unmarshal(rspBody, &result); err == nil && result.Failure > 0 && len(result.Results) > 0 {
+		return fmt.Errorf("fcm rejected the push: %s", result.Results[0].Error)
+	}
+	return nil
+}
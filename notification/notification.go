@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/backendmaster/simple_bank/mail"
+)
+
+const (
+	KindTransferReceived         = "transfer_received"
+	KindLowBalance               = "low_balance"
+	KindLargeTransaction         = "large_transaction"
+	KindNewLogin                 = "new_login"
+	KindBalanceAdjusted          = "balance_adjusted"
+	KindExportReady              = "export_ready"
+	KindExternalTransferSettled  = "external_transfer_settled"
+	KindExternalTransferReturned = "external_transfer_returned"
+	KindLoanDelinquent           = "loan_delinquent"
+	KindSuspiciousLogin          = "suspicious_login"
+)
+
+// ErrUnknownKind is returned for a notification kind without a template. Retrying can't fix it.
+var ErrUnknownKind = errors.New("unknown notification kind")
+
+// Message is the rendered text of a notification, the same on every channel and in the inbox.
+type Message struct {
+	Title string
+	Body  string
+}
+
+// Recipient is where the channels deliver the notifications of a user.
+type Recipient struct {
+	Username    string
+	FullName    string
+	Email       string
+	PhoneNumber string
+	PushToken   string
+	// Brand presents the bank in the emails, as the tenant of the user.
+	Brand mail.Brand
+}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = parseTemplates(KindTransferReceived, KindLowBalance, KindLargeTransaction, KindNewLogin, KindBalanceAdjusted, KindExportReady,
+	KindExternalTransferSettled, KindExternalTransferReturned, KindLoanDelinquent, KindSuspiciousLogin)
+
+// parseTemplates parses the template of each kind, which defines a "title" and a "body".
+// A key missing from the data is an error rather than a "<no value>" sent to the user.
+func parseTemplates(kinds ...string) map[string]*template.Template {
+	parsed := make(map[string]*template.Template, len(kinds))
+	for _, kind := range kinds {
+		parsed[kind] = template.Must(template.New(kind).Option("missingkey=error").
+			ParseFS(templateFS, "templates/"+kind+".tmpl"))
+	}
+	return parsed
+}
+
+// IsKind tells whether kind is a kind of notification.
+func IsKind(kind string) bool {
+	_, ok := templates[kind]
+	return ok
+}
+
+// Render renders the notification of kind with data.
+func Render(kind string, data map[string]string) (Message, error) {
+	tmpl, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("%w %q", ErrUnknownKind, kind)
+	}
+
+	var title, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&title, "title", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render title of %s: %w", kind, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render body of %s: %w", kind, err)
+	}
+
+	return Message{
+		Title: strings.TrimSpace(title.String()),
+		Body:  strings.TrimSpace(body.String()),
+	}, nil
+}
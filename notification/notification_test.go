@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	msg, err := Render(KindTransferReceived, map[string]string{
+		"from_account_id": "1",
+		"to_account_id":   "2",
+		"amount":          "10",
+		"currency":        "USD",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "You received 10 USD", msg.Title)
+	require.Equal(t, "Your account #2 received 10 USD from account #1.", msg.Body)
+
+	_, err = Render(KindNewLogin, map[string]string{"client_ip": "127.0.0.1"})
+	require.Error(t, err)
+
+	_, err = Render("unknown", nil)
+	require.ErrorIs(t, err, ErrUnknownKind)
+}
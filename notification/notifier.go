@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPreferences are the preferences of a user who never saved any: email only.
+func DefaultPreferences(username string) db.NotificationPreference {
+	return db.NotificationPreference{
+		Username:     username,
+		EmailEnabled: true,
+	}
+}
+
+// Store is the part of db.Store the notifier needs.
+type Store interface {
+	db.NotificationStore
+	GetUser(ctx context.Context, username string) (db.User, error)
+	GetTenant(ctx context.Context, id string) (db.Tenant, error)
+}
+
+// TenantBrand is how the emails to the users of tenant present the bank.
+func TenantBrand(tenant db.Tenant) mail.Brand {
+	return mail.Brand{
+		Name:         tenant.Name,
+		SenderName:   tenant.EmailSenderName,
+		SupportEmail: tenant.SupportEmail,
+	}
+}
+
+// Notifier stores notifications in the inbox of the users and delivers them on the channels they enabled.
+type Notifier struct {
+	store    Store
+	channels map[string]Channel
+}
+
+func NewNotifier(store Store, channels map[string]Channel) *Notifier {
+	return &Notifier{
+		store:    store,
+		channels: channels,
+	}
+}
+
+// Notify sends the notification of kind to username. dedupKey, when not empty, keeps a retried
+// task from adding the notification to the inbox twice. The channels don't know about retries,
+// so a retry after one of them failed delivers again on the ones that succeeded. A kind the user
+// muted only goes to the inbox.
+func (notifier *Notifier) Notify(ctx context.Context, dedupKey, username, kind string, data map[string]string) error {
+	msg, err := Render(kind, data)
+	if err != nil {
+		return err
+	}
+
+	user, err := notifier.store.GetUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	tenant, err := notifier.store.GetTenant(ctx, user.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	_, err = notifier.store.CreateNotification(ctx, db.CreateNotificationParams{
+		Username: username,
+		Kind:     kind,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		DedupKey: pgtype.Text{String: dedupKey, Valid: dedupKey != ""},
+	})
+	// no rows means the notification with dedupKey is already in the inbox
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	settings, err := preferences.Parse(user.Preferences)
+	if err != nil {
+		// the api validates the preferences it saves, and a notification is better sent than lost
+		log.Warn().Err(err).Str("username", username).Msg("invalid user preferences")
+		settings = preferences.Default()
+	}
+	if settings.Mutes(kind) {
+		return nil
+	}
+
+	preferences, err := notifier.store.GetNotificationPreferences(ctx, username)
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get notification preferences: %w", err)
+		}
+		preferences = DefaultPreferences(username)
+	}
+
+	recipient := Recipient{
+		Username:    user.Username,
+		FullName:    user.FullName,
+		Email:       user.Email,
+		PhoneNumber: preferences.PhoneNumber,
+		PushToken:   preferences.PushToken,
+		Brand:       TenantBrand(tenant),
+	}
+
+	var sendErr error
+	for _, name := range enabledChannels(preferences) {
+		channel, ok := notifier.channels[name]
+		if !ok {
+			continue
+		}
+		if err := channel.Send(ctx, recipient, msg); err != nil {
+			log.Error().Err(err).Str("channel", name).Str("username", username).
+				Str("kind", kind).Msg("send notification failed")
+			if sendErr == nil {
+				sendErr = fmt.Errorf("failed to send notification on %s: %w", name, err)
+			}
+		}
+	}
+	return sendErr
+}
+
+// enabledChannels lists the channels of preferences that are on and have somewhere to deliver to.
+func enabledChannels(preferences db.NotificationPreference) []string {
+	var channels []string
+	if preferences.EmailEnabled {
+		channels = append(channels, ChannelEmail)
+	}
+	if preferences.SmsEnabled && preferences.PhoneNumber != "" {
+		channels = append(channels, ChannelSMS)
+	}
+	if preferences.PushEnabled && preferences.PushToken != "" {
+		channels = append(channels, ChannelPush)
+	}
+	return channels
+}
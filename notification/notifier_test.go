@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChannel struct {
+	sent []Recipient
+	err  error
+}
+
+func (channel *fakeChannel) Send(ctx context.Context, recipient Recipient, msg Message) error {
+	if channel.err != nil {
+		return channel.err
+	}
+	channel.sent = append(channel.sent, recipient)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+		TenantID: "acme",
+	}
+	tenant := db.Tenant{ID: "acme", Name: "Acme Bank", EmailSenderName: "Acme", SupportEmail: "help@acme.com"}
+	data := map[string]string{"client_ip": "127.0.0.1", "user_agent": "curl"}
+
+	testCases := []struct {
+		name         string
+		dedupKey     string
+		sendErr      error
+		buildStubs   func(store *mockdb.MockStore)
+		checkChannel func(t *testing.T, email, sms, push *fakeChannel, err error)
+	}{
+		{
+			name:     "DefaultPreferences",
+			dedupKey: "outbox:1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(user.TenantID)).Times(1).Return(tenant, nil)
+				store.EXPECT().
+					CreateNotification(gomock.Any(), gomock.Eq(db.CreateNotificationParams{
+						Username: user.Username,
+						Kind:     KindNewLogin,
+						Title:    "New login to your account",
+						Body:     "Someone logged in to your account from 127.0.0.1 (curl). If this wasn't you, change your password right away.",
+						DedupKey: pgtype.Text{String: "outbox:1", Valid: true},
+					})).
+					Times(1).
+					Return(db.Notification{ID: 1}, nil)
+				store.EXPECT().
+					GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+			},
+			checkChannel: func(t *testing.T, email, sms, push *fakeChannel, err error) {
+				require.NoError(t, err)
+				require.Len(t, email.sent, 1)
+				require.Equal(t, user.Email, email.sent[0].Email)
+				require.Equal(t, TenantBrand(tenant), email.sent[0].Brand)
+				require.Empty(t, sms.sent)
+				require.Empty(t, push.sent)
+			},
+		},
+		{
+			name: "AlreadyInInbox",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(tenant, nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetNotificationPreferences(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.NotificationPreference{SmsEnabled: true, PhoneNumber: "+14155552671", PushEnabled: true}, nil)
+			},
+			checkChannel: func(t *testing.T, email, sms, push *fakeChannel, err error) {
+				require.NoError(t, err)
+				require.Empty(t, email.sent)
+				require.Len(t, sms.sent, 1)
+				require.Equal(t, "+14155552671", sms.sent[0].PhoneNumber)
+				// push is on, but there is no token to send to
+				require.Empty(t, push.sent)
+			},
+		},
+		{
+			name:    "SendError",
+			sendErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(tenant, nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{ID: 1}, nil)
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(1).Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+			},
+			checkChannel: func(t *testing.T, email, sms, push *fakeChannel, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "Muted",
+			buildStubs: func(store *mockdb.MockStore) {
+				muted := user
+				muted.Preferences = []byte(`{"notifications": {"muted": ["new_login"]}}`)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(muted, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(tenant, nil)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{ID: 1}, nil)
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkChannel: func(t *testing.T, email, sms, push *fakeChannel, err error) {
+				require.NoError(t, err)
+				require.Empty(t, email.sent)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkChannel: func(t *testing.T, email, sms, push *fakeChannel, err error) {
+				require.ErrorIs(t, err, db.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			email, sms, push := &fakeChannel{err: tc.sendErr}, &fakeChannel{}, &fakeChannel{}
+			notifier := NewNotifier(store, map[string]Channel{
+				ChannelEmail: email,
+				ChannelSMS:   sms,
+				ChannelPush:  push,
+			})
+
+			err := notifier.Notify(context.Background(), tc.dedupKey, user.Username, KindNewLogin, data)
+			tc.checkChannel(t, email, sms, push, err)
+		})
+	}
+}
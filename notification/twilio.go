@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twilioHost = "https://api.twilio.com"
+
+// TwilioChannel sends notifications as text messages through the messages api of Twilio.
+type TwilioChannel struct {
+	accountSID string
+	authToken  string
+	fromNumber string
+	host       string
+	client     *http.Client
+}
+
+func NewTwilioChannel(accountSID, authToken, fromNumber string) Channel {
+	return &TwilioChannel{
+		accountSID: accountSID,
+		authToken:  authToken,
+		fromNumber: fromNumber,
+		host:       twilioHost,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (channel *TwilioChannel) Send(ctx context.Context, recipient Recipient, msg Message) error {
+	form := url.Values{
+		"From": {channel.fromNumber},
+		"To":   {recipient.PhoneNumber},
+		"Body": {msg.Title + "\n" + msg.Body},
+	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", channel.host, channel.accountSID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(channel.accountSID, channel.authToken)
+
+	rsp, err := channel.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms through twilio: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode >= http.StatusMultipleChoices {
+		rspBody, _ := io.ReadAll(io.LimitReader(rsp.Body, 4096))
+		return fmt.Errorf("twilio rejected the sms with status %d: %s", rsp.StatusCode, rspBody)
+	}
+	return nil
+}
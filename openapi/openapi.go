@@ -0,0 +1,271 @@
+// Package openapi builds an OpenAPI 3 document out of the routes of an api and the Go types
+// they bind and answer with, so that the spec can't drift from the code it describes:
+//
+//	doc := openapi.Generate(openapi.Info{Title: "Simple Bank", Version: "v1"}, routes, nil)
+//
+// The schemas come from the json tags of the types, the parameters from their uri and form
+// tags, and the constraints from their binding tags. The json responses are documented in the
+// envelope the api wraps them in, see package envelope, unless their route is Raw.
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+// Document is the root of an OpenAPI document, with only the parts Generate fills.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lowercase http methods of a path to their operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is the subset of the JSON schema of OpenAPI 3.0 the Go types map to.
+type Schema struct {
+	Ref              string             `json:"$ref,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	Nullable         bool               `json:"nullable,omitempty"`
+	Enum             []string           `json:"enum,omitempty"`
+	Pattern          string             `json:"pattern,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty"`
+	Maximum          *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum bool               `json:"exclusiveMinimum,omitempty"`
+	MinLength        *int               `json:"minLength,omitempty"`
+	MaxLength        *int               `json:"maxLength,omitempty"`
+	MinItems         *int               `json:"minItems,omitempty"`
+	MaxItems         *int               `json:"maxItems,omitempty"`
+	UniqueItems      bool               `json:"uniqueItems,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+}
+
+// Route documents one route of the api.
+type Route struct {
+	Method  string
+	Path    string // in the syntax of gin, e.g. /accounts/:id
+	Tag     string
+	Summary string
+	// Auth tells whether the route needs a bearer access token.
+	Auth bool
+	// URI, Query and Body are the values the handler binds, nil when it binds none.
+	// BodyContentType defaults to application/json, another one for a Body read raw, e.g. a file.
+	URI             any
+	Query           any
+	Body            any
+	BodyContentType string
+	// Status is the status of a successful response, Response the value it answers with, nil
+	// when it has no body. ContentType defaults to application/json.
+	Status      int
+	Response    any
+	ContentType string
+	// Raw tells that the json Response is the whole body rather than the data of the envelope,
+	// for the routes served by other packages, e.g. /version.
+	Raw bool
+}
+
+const (
+	bearerAuth       = "bearerAuth"
+	errorSchema      = "Error"
+	paginationSchema = "Pagination"
+	jsonContentType  = "application/json"
+)
+
+// Generate documents routes. bindings gives the constraints of the custom binding tags of
+// the api, e.g. "currency", which are merged into the schemas of the fields that use them.
+func Generate(info Info, routes []Route, bindings map[string]Schema) Document {
+	g := newGenerator(bindings)
+	doc := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+		},
+	}
+	// the envelope of the errors of the api, with the field breaking a rule for those of the bindings
+	g.schemas[errorSchema] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data":       {Nullable: true},
+			"request_id": {Type: "string"},
+			"errors": {Type: "array", Items: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"message": {Type: "string"},
+					"field":   {Type: "string"},
+				},
+				Required: []string{"message"},
+			}},
+		},
+		Required: []string{"request_id", "errors"},
+	}
+	g.schemas[paginationSchema] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"next_cursor": {Type: "string"},
+			"prev_cursor": {Type: "string"},
+		},
+	}
+
+	for _, route := range routes {
+		path := Path(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = g.operation(route)
+		if route.Auth {
+			doc.Components.SecuritySchemes = map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "PASETO"},
+			}
+		}
+	}
+	return doc
+}
+
+// Path turns a path of gin into a path of OpenAPI, /accounts/:id into /accounts/{id}.
+func Path(ginPath string) string {
+	if !strings.HasPrefix(ginPath, "/") {
+		ginPath = "/" + ginPath
+	}
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (g *generator) operation(route Route) *Operation {
+	op := &Operation{
+		Summary:   route.Summary,
+		Responses: map[string]Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.URI != nil {
+		op.Parameters = append(op.Parameters, g.parameters(route.URI, "path")...)
+	}
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, g.parameters(route.Query, "query")...)
+	}
+	if route.Body != nil {
+		contentType := route.BodyContentType
+		if contentType == "" {
+			contentType = jsonContentType
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentType: {Schema: g.schema(route.Body)}},
+		}
+	}
+	if route.Auth {
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		contentType := route.ContentType
+		if contentType == "" {
+			contentType = jsonContentType
+		}
+		schema := g.schema(route.Response)
+		if contentType == jsonContentType && !route.Raw {
+			schema = envelope(schema)
+			if route.Method == http.MethodGet {
+				op.Parameters = append(op.Parameters, fieldsParameter)
+			}
+		}
+		response.Content = map[string]MediaType{contentType: {Schema: schema}}
+	}
+	op.Responses[strconv.Itoa(status)] = response
+	op.Responses["default"] = Response{
+		Description: "error",
+		Content:     map[string]MediaType{jsonContentType: {Schema: &Schema{Ref: schemaRef(errorSchema)}}},
+	}
+	return op
+}
+
+// fieldsParameter asks the GET routes answering an envelope for a partial response, see
+// envelope.FieldsParam.
+var fieldsParameter = Parameter{
+	Name:        "fields",
+	In:          "query",
+	Description: "the comma separated paths of the fields of data to answer with, e.g. id,balance or accounts.id",
+	Schema:      &Schema{Type: "string"},
+}
+
+// envelope is the schema of the envelope of data, the schema of the response of a route.
+func envelope(data *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data":       data,
+			"request_id": {Type: "string"},
+			"pagination": {Ref: schemaRef(paginationSchema)},
+		},
+		Required: []string{"data", "request_id"},
+	}
+}
@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{"/accounts", "/accounts"},
+		{"/accounts/:id/entries", "/accounts/{id}/entries"},
+		{"/admin/dead_tasks/:queue/:id", "/admin/dead_tasks/{queue}/{id}"},
+		{"tokens/renew_access", "/tokens/renew_access"},
+		{"/files/*path", "/files/{path}"},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.want, Path(tc.path))
+		})
+	}
+}
+
+type base struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type item struct {
+	base
+	ID        int64              `json:"id"`
+	Note      *string            `json:"note"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	secret    string
+	Ignored   string `json:"-"`
+}
+
+type itemURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type itemQuery struct {
+	Tags  []string `form:"tag" binding:"required,max=5,dive,min=2"`
+	Limit int32    `form:"limit" binding:"omitempty,gt=0"`
+}
+
+type createItemRequest struct {
+	Name string `json:"name" binding:"required,max=20,code"`
+}
+
+// Error collides with the schema of the errors of the api.
+type Error struct {
+	Message string `json:"message"`
+}
+
+type batchResponse struct {
+	Items  []item  `json:"items"`
+	Errors []Error `json:"errors"`
+}
+
+func TestGenerate(t *testing.T) {
+	routes := []Route{
+		{Method: http.MethodGet, Path: "/items/:id", Auth: true, URI: itemURI{}, Query: itemQuery{}, Response: item{}},
+		{Method: http.MethodPost, Path: "/items", Body: createItemRequest{}, Response: item{}, Status: http.StatusCreated},
+		{Method: http.MethodDelete, Path: "/items/:id", Auth: true, URI: itemURI{}, Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/items/batch", Body: []createItemRequest{}, Response: batchResponse{}},
+		{Method: http.MethodPost, Path: "/items/import", Body: "", BodyContentType: "text/csv", Status: http.StatusAccepted},
+		{Method: http.MethodGet, Path: "/version", Response: item{}, Raw: true},
+	}
+	bindings := map[string]Schema{"code": {Pattern: "^[A-Z]+$"}}
+	doc := Generate(Info{Title: "items", Version: "v1"}, routes, bindings)
+
+	require.Equal(t, Version, doc.OpenAPI)
+	require.Len(t, doc.Paths, 5)
+	require.Contains(t, doc.Components.SecuritySchemes, bearerAuth)
+
+	get := doc.Paths["/items/{id}"]["get"]
+	require.Equal(t, []map[string][]string{{bearerAuth: {}}}, get.Security)
+	require.Len(t, get.Parameters, 4)
+	id := get.Parameters[0]
+	require.Equal(t, "path", id.In)
+	require.True(t, id.Required)
+	require.Equal(t, 1.0, *id.Schema.Minimum)
+	tags := get.Parameters[1]
+	require.Equal(t, "tag", tags.Name)
+	require.True(t, tags.Required)
+	require.Equal(t, "array", tags.Schema.Type)
+	require.Equal(t, 5, *tags.Schema.MaxItems)
+	require.Equal(t, 2, *tags.Schema.Items.MinLength)
+	limit := get.Parameters[2]
+	require.False(t, limit.Required)
+	require.True(t, limit.Schema.ExclusiveMinimum)
+	require.Equal(t, fieldsParameter, get.Parameters[3])
+	ok := get.Responses["200"].Content[jsonContentType].Schema
+	require.Equal(t, schemaRef("Item"), ok.Properties["data"].Ref)
+	require.Equal(t, schemaRef(paginationSchema), ok.Properties["pagination"].Ref)
+	require.Equal(t, []string{"data", "request_id"}, ok.Required)
+	require.Equal(t, schemaRef(errorSchema), get.Responses["default"].Content[jsonContentType].Schema.Ref)
+
+	schema := doc.Components.Schemas["Item"]
+	require.Len(t, schema.Properties, 4)
+	require.Equal(t, "date-time", schema.Properties["created_at"].Format)
+	require.True(t, schema.Properties["note"].Nullable)
+	require.True(t, schema.Properties["deleted_at"].Nullable)
+
+	create := doc.Paths["/items"]["post"]
+	require.Empty(t, create.Security)
+	require.Contains(t, create.Responses, "201")
+	body := doc.Components.Schemas["CreateItemRequest"]
+	require.Equal(t, []string{"name"}, body.Required)
+	require.Equal(t, 20, *body.Properties["name"].MaxLength)
+	require.Equal(t, "^[A-Z]+$", body.Properties["name"].Pattern)
+
+	upload := doc.Paths["/items/import"]["post"]
+	require.Equal(t, "string", upload.RequestBody.Content["text/csv"].Schema.Type)
+	require.NotContains(t, upload.RequestBody.Content, jsonContentType)
+
+	remove := doc.Paths["/items/{id}"]["delete"]
+	require.Empty(t, remove.Responses["204"].Content)
+
+	batch := doc.Components.Schemas["BatchResponse"]
+	require.Equal(t, schemaRef("OpenapiError"), batch.Properties["errors"].Items.Ref)
+	require.Equal(t, []string{"request_id", "errors"}, doc.Components.Schemas[errorSchema].Required)
+
+	version := doc.Paths["/version"]["get"]
+	require.Equal(t, schemaRef("Item"), version.Responses["200"].Content[jsonContentType].Schema.Ref)
+	require.Empty(t, version.Parameters)
+	require.Empty(t, create.Parameters)
+}
@@ -0,0 +1,282 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// knownTypes are the types which don't marshal to json like their kind does.
+var knownTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):          {Type: "string", Format: "date-time"},
+	reflect.TypeOf(pgtype.Timestamptz{}): {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(pgtype.Int8{}):        {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(pgtype.Text{}):        {Type: "string", Nullable: true},
+	reflect.TypeOf(uuid.UUID{}):          {Type: "string", Format: "uuid"},
+	// any json value
+	reflect.TypeOf(json.RawMessage{}): {},
+}
+
+type generator struct {
+	bindings map[string]Schema
+	schemas  map[string]*Schema
+	names    map[reflect.Type]string
+	types    map[string]reflect.Type
+}
+
+func newGenerator(bindings map[string]Schema) *generator {
+	return &generator{
+		bindings: bindings,
+		schemas:  map[string]*Schema{},
+		names:    map[reflect.Type]string{},
+		types:    map[string]reflect.Type{},
+	}
+}
+
+// schema returns the schema of the json of v. Named structs are added to the components and
+// referenced.
+func (g *generator) schema(v any) *Schema {
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	if known, ok := knownTypes[t]; ok {
+		return &known
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := g.typeSchema(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &Schema{Ref: schemaRef(g.component(t))}
+	}
+	return &Schema{}
+}
+
+// component adds the schema of the named struct t to the components and returns its name.
+func (g *generator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := exported(t.Name())
+	if _, taken := g.schemas[name]; taken && g.types[name] != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = exported(pkg) + name
+	}
+	g.names[t] = name
+	g.types[name] = t
+
+	// registered before its fields, so that a type referencing itself ends
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.object(t)
+	return name
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields(t, "json") {
+		property := g.typeSchema(f.typ)
+		if g.applyBinding(property, f.binding) {
+			schema.Required = append(schema.Required, f.name)
+		}
+		schema.Properties[f.name] = property
+	}
+	return schema
+}
+
+// parameters returns the parameters of the fields of v, bound from the path or the query.
+func (g *generator) parameters(v any, in string) []Parameter {
+	tag := "form"
+	if in == "path" {
+		tag = "uri"
+	}
+
+	var parameters []Parameter
+	for _, f := range fields(reflect.TypeOf(v), tag) {
+		schema := g.typeSchema(f.typ)
+		required := g.applyBinding(schema, f.binding)
+		parameters = append(parameters, Parameter{
+			Name:     f.name,
+			In:       in,
+			Required: required || in == "path",
+			Schema:   schema,
+		})
+	}
+	return parameters
+}
+
+// applyBinding adds the constraints of the binding tag of a field to its schema, and tells
+// whether the field is required.
+func (g *generator) applyBinding(schema *Schema, binding string) (required bool) {
+	if binding == "" || schema.Ref != "" {
+		return strings.Contains(binding, "required")
+	}
+
+	target := schema
+	for _, rule := range strings.Split(binding, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = target == schema
+		case "dive":
+			if target.Items != nil {
+				target = target.Items
+			}
+		case "unique":
+			target.UniqueItems = true
+		case "min", "max", "gt":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			limit(target, name, n)
+		case "email":
+			target.Format = "email"
+		case "e164":
+			target.Pattern = `^\+[1-9]\d{1,14}$`
+		default:
+			if custom, ok := g.bindings[name]; ok {
+				merge(target, custom)
+			}
+		}
+	}
+	return required
+}
+
+// limit applies a min, max or gt rule, which bounds the length of strings and arrays and the
+// value of numbers.
+func limit(schema *Schema, rule string, n float64) {
+	length := int(n)
+	switch schema.Type {
+	case "string":
+		if rule == "max" {
+			schema.MaxLength = &length
+		} else {
+			if rule == "gt" {
+				length++
+			}
+			schema.MinLength = &length
+		}
+	case "array":
+		if rule == "max" {
+			schema.MaxItems = &length
+		} else {
+			if rule == "gt" {
+				length++
+			}
+			schema.MinItems = &length
+		}
+	case "integer", "number":
+		if rule == "max" {
+			schema.Maximum = &n
+		} else {
+			schema.Minimum = &n
+			schema.ExclusiveMinimum = rule == "gt"
+		}
+	}
+}
+
+// merge copies the constraints set in custom to schema.
+func merge(schema *Schema, custom Schema) {
+	if custom.Format != "" {
+		schema.Format = custom.Format
+	}
+	if custom.Pattern != "" {
+		schema.Pattern = custom.Pattern
+	}
+	if custom.Enum != nil {
+		schema.Enum = custom.Enum
+	}
+	if custom.MinLength != nil {
+		schema.MinLength = custom.MinLength
+	}
+	if custom.MaxLength != nil {
+		schema.MaxLength = custom.MaxLength
+	}
+}
+
+type field struct {
+	name    string
+	typ     reflect.Type
+	binding string
+}
+
+// fields returns the fields of the struct t named by tag, with the fields of its embedded
+// structs promoted like encoding/json and gin do.
+func fields(t reflect.Type, tag string) []field {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		value, ok := sf.Tag.Lookup(tag)
+		if value == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(value, ",")
+		if sf.Anonymous && name == "" && indirect(sf.Type).Kind() == reflect.Struct {
+			fs = append(fs, fields(sf.Type, tag)...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if !ok || name == "" {
+			name = sf.Name
+		}
+		fs = append(fs, field{name: name, typ: sf.Type, binding: sf.Tag.Get("binding")})
+	}
+	return fs
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
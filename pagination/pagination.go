@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points between two rows of a list ordered by id. Clients only ever see it encoded,
+// so the way it's built can change without breaking them.
+type Cursor struct {
+	// ID is the id of the row the page starts after, or ends before when Backward is set.
+	ID       int64 `json:"id"`
+	Backward bool  `json:"backward,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor given to clients.
+func (cursor Cursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor returned by Encode. The empty string is the cursor of the first page.
+func Decode(s string) (Cursor, error) {
+	var cursor Cursor
+	if s == "" {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// Page is a page of a list along with the cursors of the pages around it, empty when there is none.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+}
+
+// Paginate returns the page of at most size items at cursor, in ascending id order. fetch must return
+// up to limit items with an id greater than cursor.ID in ascending order, or lower than cursor.ID in
+// descending order when cursor.Backward is set. One more item than size is fetched to tell whether
+// there are more rows past the page.
+func Paginate[T any](cursor Cursor, size int32, id func(T) int64, fetch func(cursor Cursor, limit int32) ([]T, error)) (Page[T], error) {
+	items, err := fetch(cursor, size+1)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	more := len(items) > int(size)
+	if more {
+		items = items[:size]
+	}
+	if cursor.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := Page[T]{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+	first, last := id(items[0]), id(items[len(items)-1])
+	if cursor.Backward {
+		page.NextCursor = Cursor{ID: last}.Encode()
+		if more {
+			page.PrevCursor = Cursor{ID: first, Backward: true}.Encode()
+		}
+	} else {
+		if more {
+			page.NextCursor = Cursor{ID: last}.Encode()
+		}
+		if cursor.ID > 0 {
+			page.PrevCursor = Cursor{ID: first, Backward: true}.Encode()
+		}
+	}
+	return page, nil
+}
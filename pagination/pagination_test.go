@@ -0,0 +1,103 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fetchFrom serves the pages of a list of ids the way the keyset queries do.
+func fetchFrom(ids []int64) func(cursor Cursor, limit int32) ([]int64, error) {
+	return func(cursor Cursor, limit int32) ([]int64, error) {
+		var result []int64
+		if cursor.Backward {
+			for i := len(ids) - 1; i >= 0 && len(result) < int(limit); i-- {
+				if ids[i] < cursor.ID {
+					result = append(result, ids[i])
+				}
+			}
+			return result, nil
+		}
+		for _, id := range ids {
+			if id > cursor.ID && len(result) < int(limit) {
+				result = append(result, id)
+			}
+		}
+		return result, nil
+	}
+}
+
+func identity(id int64) int64 { return id }
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{ID: 42, Backward: true}
+	decoded, err := Decode(cursor.Encode())
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+
+	decoded, err = Decode("")
+	require.NoError(t, err)
+	require.Equal(t, Cursor{}, decoded)
+
+	for _, s := range []string{"not base64!", "bm90IGpzb24", Cursor{ID: -1}.Encode()} {
+		_, err = Decode(s)
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	ids := []int64{1, 2, 3, 4, 5}
+	fetch := fetchFrom(ids)
+
+	page, err := Paginate(Cursor{}, 2, identity, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, page.Items)
+	require.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+
+	next, err := Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = Paginate(next, 2, identity, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 4}, page.Items)
+	require.NotEmpty(t, page.PrevCursor)
+
+	next, err = Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = Paginate(next, 2, identity, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []int64{5}, page.Items)
+	require.Empty(t, page.NextCursor)
+
+	prev, err := Decode(page.PrevCursor)
+	require.NoError(t, err)
+	page, err = Paginate(prev, 2, identity, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 4}, page.Items)
+	require.NotEmpty(t, page.NextCursor)
+
+	prev, err = Decode(page.PrevCursor)
+	require.NoError(t, err)
+	page, err = Paginate(prev, 2, identity, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, page.Items)
+	require.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	page, err := Paginate(Cursor{}, 2, identity, fetchFrom(nil))
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+	require.Empty(t, page.NextCursor)
+	require.Empty(t, page.PrevCursor)
+}
+
+func TestPaginateError(t *testing.T) {
+	fetchErr := errors.New("fetch failed")
+	_, err := Paginate(Cursor{}, 2, identity, func(Cursor, int32) ([]int64, error) {
+		return nil, fetchErr
+	})
+	require.ErrorIs(t, err, fetchErr)
+}
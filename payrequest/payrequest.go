@@ -0,0 +1,136 @@
+// Package payrequest signs the payment requests and encodes them as QR codes. A QR code holds a
+// URI with the fields of the request and their signature, an HMAC under a key of the server, so
+// that the app of the payer shows what it pays and the server knows the payer scanned the request
+// rather than guessed its id.
+package payrequest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// MinKeySize is the size, in bytes, of the shortest signing key.
+const MinKeySize = 32
+
+// Scheme is the scheme of the URIs of the payment requests, which the app of the bank opens.
+const Scheme = "simplebank"
+
+// ErrInvalidURI is returned by Parse for a URI that isn't one of a payment request.
+var ErrInvalidURI = errors.New("invalid payment request uri")
+
+// Payload is what the QR code of a payment request holds.
+type Payload struct {
+	ID int64 `json:"id"`
+	// AccountNumber is the number of the account the request pays, see db.Account.
+	AccountNumber string    `json:"account_number"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Memo          string    `json:"memo"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Signature     string    `json:"signature"`
+}
+
+// Signer signs and verifies the payment requests. Changing its key invalidates the QR codes of the
+// open requests.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key string) (*Signer, error) {
+	if len(key) < MinKeySize {
+		return nil, fmt.Errorf("invalid payment request key size: must be at least %d characters", MinKeySize)
+	}
+	return &Signer{key: []byte(key)}, nil
+}
+
+// Sign returns the payload of request, paying the account with accountNumber.
+func (signer *Signer) Sign(request db.PaymentRequest, accountNumber string) Payload {
+	payload := Payload{
+		ID:            request.ID,
+		AccountNumber: accountNumber,
+		Amount:        request.Amount,
+		Currency:      request.Currency,
+		Memo:          request.Memo,
+		ExpiresAt:     request.ExpiresAt.UTC().Truncate(time.Second),
+	}
+	payload.Signature = signer.signature(payload)
+	return payload
+}
+
+// Verify tells whether signature is that of the payload of request.
+func (signer *Signer) Verify(request db.PaymentRequest, accountNumber string, signature string) bool {
+	expected := signer.Sign(request, accountNumber).Signature
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// signature signs the fields of payload, the memo last, so that a | in it can't shift the others.
+func (signer *Signer) signature(payload Payload) string {
+	mac := hmac.New(sha256.New, signer.key)
+	fmt.Fprintf(mac, "%d|%s|%d|%s|%d|%s",
+		payload.ID,
+		payload.AccountNumber,
+		payload.Amount,
+		payload.Currency,
+		payload.ExpiresAt.Unix(),
+		payload.Memo,
+	)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// URI returns the URI the QR code of payload holds, e.g.
+// simplebank://pay?id=1&account=SB06123456789012&amount=500&currency=USD&expires=1792152000&memo=&sig=...
+func (payload Payload) URI() string {
+	query := url.Values{
+		"id":       {strconv.FormatInt(payload.ID, 10)},
+		"account":  {payload.AccountNumber},
+		"amount":   {strconv.FormatInt(payload.Amount, 10)},
+		"currency": {payload.Currency},
+		"expires":  {strconv.FormatInt(payload.ExpiresAt.Unix(), 10)},
+		"memo":     {payload.Memo},
+		"sig":      {payload.Signature},
+	}
+	return (&url.URL{Scheme: Scheme, Host: "pay", RawQuery: query.Encode()}).String()
+}
+
+// Parse reads the payload of a URI returned by URI, without checking its signature, which only
+// the server can.
+func Parse(uri string) (Payload, error) {
+	var payload Payload
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != Scheme || u.Host != "pay" {
+		return payload, ErrInvalidURI
+	}
+	query := u.Query()
+	payload.ID, err = strconv.ParseInt(query.Get("id"), 10, 64)
+	if err != nil {
+		return payload, ErrInvalidURI
+	}
+	payload.Amount, err = strconv.ParseInt(query.Get("amount"), 10, 64)
+	if err != nil {
+		return payload, ErrInvalidURI
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return payload, ErrInvalidURI
+	}
+	payload.ExpiresAt = time.Unix(expires, 0).UTC()
+	payload.AccountNumber = query.Get("account")
+	payload.Currency = query.Get("currency")
+	payload.Memo = query.Get("memo")
+	payload.Signature = query.Get("sig")
+	return payload, nil
+}
+
+// QRCode returns the QR code of payload as a PNG image of size by size pixels.
+func QRCode(payload Payload, size int) ([]byte, error) {
+	return qrcode.Encode(payload.URI(), qrcode.Medium, size)
+}
@@ -0,0 +1,78 @@
+package payrequest
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner("short")
+	require.Error(t, err)
+
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	request := db.PaymentRequest{
+		ID:        42,
+		AccountID: 7,
+		Amount:    1250,
+		Currency:  util.EUR,
+		Memo:      "dinner | friday & co",
+		ExpiresAt: time.Date(2026, 10, 17, 12, 0, 0, 123456000, time.UTC),
+	}
+	number := "SB06123456789012"
+
+	payload := signer.Sign(request, number)
+	require.Equal(t, request.ID, payload.ID)
+	require.Equal(t, number, payload.AccountNumber)
+	require.Equal(t, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), payload.ExpiresAt)
+	require.True(t, signer.Verify(request, number, payload.Signature))
+
+	tampered := request
+	tampered.Amount = 12500
+	require.False(t, signer.Verify(tampered, number, payload.Signature))
+	require.False(t, signer.Verify(request, "SB06123456789013", payload.Signature))
+	require.False(t, signer.Verify(request, number, ""))
+
+	other, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	require.False(t, other.Verify(request, number, payload.Signature))
+}
+
+func TestURI(t *testing.T) {
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	payload := signer.Sign(db.PaymentRequest{
+		ID:        42,
+		Amount:    1250,
+		Currency:  util.USD,
+		Memo:      "rent & bills",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, "SB06123456789012")
+
+	uri := payload.URI()
+	require.Regexp(t, `^simplebank://pay\?`, uri)
+	parsed, err := Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, payload, parsed)
+
+	for _, invalid := range []string{"", "https://pay?id=1", "simplebank://pay?id=x&amount=1&expires=1", "simplebank://pay?id=1"} {
+		_, err := Parse(invalid)
+		require.ErrorIs(t, err, ErrInvalidURI, invalid)
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	data, err := QRCode(Payload{ID: 1, AccountNumber: "SB06123456789012", Amount: 100, Currency: util.USD}, 256)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 256, img.Bounds().Dx())
+	require.Equal(t, 256, img.Bounds().Dy())
+}
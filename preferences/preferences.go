@@ -0,0 +1,128 @@
+// Package preferences holds the settings of a user, kept as a JSON document in the preferences
+// column of the users table: the locale and the timezone they read the api in, the currency they
+// open accounts in, and the notifications they muted.
+package preferences
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	// the alpine image has no zoneinfo, and the timezones of the users must load anyway
+	_ "time/tzdata"
+
+	"github.com/backendmaster/simple_bank/i18n"
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// DefaultLocale is the locale of the users who didn't choose one.
+const DefaultLocale = i18n.English
+
+// ErrInvalid is returned for preferences that don't follow the schema.
+var ErrInvalid = errors.New("invalid preferences")
+
+// Preferences are the settings of a user.
+type Preferences struct {
+	// Locale is one of i18n.Locales.
+	Locale string `json:"locale"`
+	// Timezone is an IANA name, e.g. Asia/Taipei, which the statements of the user are dated in.
+	Timezone string `json:"timezone"`
+	// DefaultCurrency is the currency of the accounts the user opens without telling, if any.
+	DefaultCurrency string        `json:"default_currency,omitempty"`
+	Notifications   Notifications `json:"notifications"`
+}
+
+// Notifications are the settings of the notifications of a user, on top of the channels they
+// enabled.
+type Notifications struct {
+	// Muted are the kinds of notifications only the inbox gets, not the channels.
+	Muted []string `json:"muted"`
+}
+
+// Default returns the preferences of a user who never saved any.
+func Default() Preferences {
+	return Preferences{
+		Locale:        DefaultLocale,
+		Timezone:      "UTC",
+		Notifications: Notifications{Muted: []string{}},
+	}
+}
+
+// Parse reads the preferences stored for a user, the keys missing from data keeping their default.
+func Parse(data []byte) (Preferences, error) {
+	preferences := Default()
+	if len(data) == 0 {
+		return preferences, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&preferences); err != nil {
+		return Default(), fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if preferences.Notifications.Muted == nil {
+		preferences.Notifications.Muted = []string{}
+	}
+	return preferences, preferences.Validate()
+}
+
+// Marshal returns the document to store for preferences, once they are valid.
+func (preferences Preferences) Marshal() ([]byte, error) {
+	if err := preferences.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(preferences)
+}
+
+// Validate checks the values of preferences against the schema. The kinds of the muted
+// notifications are checked by the api, which knows them.
+func (preferences Preferences) Validate() error {
+	if !IsLocale(preferences.Locale) {
+		return fmt.Errorf("%w: unsupported locale %q", ErrInvalid, preferences.Locale)
+	}
+	// LoadLocation takes "" and "Local" for the timezone of the server, which isn't the user's
+	_, err := time.LoadLocation(preferences.Timezone)
+	if err != nil || preferences.Timezone == "" || preferences.Timezone == "Local" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalid, preferences.Timezone)
+	}
+	if preferences.DefaultCurrency != "" && !util.IsSupportedCurrency(preferences.DefaultCurrency) {
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalid, preferences.DefaultCurrency)
+	}
+	return nil
+}
+
+// Location returns the timezone of preferences, UTC when it doesn't load.
+func (preferences Preferences) Location() *time.Location {
+	location, err := time.LoadLocation(preferences.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Mutes tells whether the notifications of kind only go to the inbox.
+func (preferences Preferences) Mutes(kind string) bool {
+	for _, muted := range preferences.Notifications.Muted {
+		if muted == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocale tells whether the api has messages in locale.
+func IsLocale(locale string) bool {
+	return i18n.IsSupported(locale)
+}
+
+// Locale returns the locale of the preferences stored for a user, DefaultLocale when they don't
+// parse, e.g. for the tokens of their logins.
+func Locale(data []byte) string {
+	preferences, err := Parse(data)
+	if err != nil {
+		return DefaultLocale
+	}
+	return preferences.Locale
+}
@@ -0,0 +1,83 @@
+package preferences
+
+import (
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        string
+		preferences Preferences
+		err         bool
+	}{
+		{name: "Empty", data: "", preferences: Default()},
+		{name: "NeverSaved", data: "{}", preferences: Default()},
+		{
+			name: "Saved",
+			data: `{"locale": "zh-TW", "timezone": "Asia/Taipei", "default_currency": "USD", "notifications": {"muted": ["low_balance"]}}`,
+			preferences: Preferences{
+				Locale:          "zh-TW",
+				Timezone:        "Asia/Taipei",
+				DefaultCurrency: util.USD,
+				Notifications:   Notifications{Muted: []string{"low_balance"}},
+			},
+		},
+		{
+			name: "MissingKeys",
+			data: `{"timezone": "Europe/Paris"}`,
+			preferences: Preferences{
+				Locale:        DefaultLocale,
+				Timezone:      "Europe/Paris",
+				Notifications: Notifications{Muted: []string{}},
+			},
+		},
+		{name: "UnknownKey", data: `{"theme": "dark"}`, err: true},
+		{name: "UnsupportedLocale", data: `{"locale": "fr"}`, err: true},
+		{name: "UnknownTimezone", data: `{"timezone": "Mars/Olympus"}`, err: true},
+		{name: "ServerTimezone", data: `{"timezone": "Local"}`, err: true},
+		{name: "UnsupportedCurrency", data: `{"default_currency": "XYZ"}`, err: true},
+		{name: "WrongType", data: `{"notifications": {"muted": "low_balance"}}`, err: true},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			preferences, err := Parse([]byte(tc.data))
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.preferences, preferences)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	preferences := Default()
+	preferences.Timezone = "America/New_York"
+	preferences.Notifications.Muted = []string{"new_login"}
+
+	data, err := preferences.Marshal()
+	require.NoError(t, err)
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.Equal(t, preferences, parsed)
+	require.Equal(t, "America/New_York", parsed.Location().String())
+	require.True(t, parsed.Mutes("new_login"))
+	require.False(t, parsed.Mutes("low_balance"))
+
+	preferences.Locale = "fr"
+	_, err = preferences.Marshal()
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestLocale(t *testing.T) {
+	require.Equal(t, "zh-TW", Locale([]byte(`{"locale": "zh-TW"}`)))
+	require.Equal(t, DefaultLocale, Locale([]byte(`{}`)))
+	require.Equal(t, DefaultLocale, Locale([]byte(`{"locale": "fr"}`)))
+}
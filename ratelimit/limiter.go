@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// Result tells whether a request is allowed and how much of its key's quota is left.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	Period     time.Duration
+}
+
+// Limiter counts the requests of each key in fixed windows, allowing at most limit per window.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// New creates the limiter described by config: backed by redis when REDIS_ADDRESS is set,
+// so that every instance shares the same counters, and in memory otherwise.
+// It returns nil, which the middlewares treat as no limit, when RATE_LIMIT isn't set.
+func New(config util.Config) Limiter {
+	if config.RateLimit <= 0 {
+		return nil
+	}
+	if config.RedisAddress != "" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return NewRedisLimiter(client, config.RateLimit, config.RateLimitWindow)
+	}
+	return NewMemoryLimiter(config.RateLimit, config.RateLimitWindow)
+}
+
+func newResult(limit int, period time.Duration, count int, resetAfter time.Duration) Result {
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:    count <= limit,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: resetAfter,
+		Period:     period,
+	}
+}
+
+type window struct {
+	count   int
+	resetAt time.Time
+}
+
+// MemoryLimiter keeps the counters in the process, which is only right for a single instance.
+type MemoryLimiter struct {
+	limit     int
+	period    time.Duration
+	mutex     sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter(limit int, period time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:     limit,
+		period:    period,
+		windows:   make(map[string]*window),
+		lastSweep: time.Now(),
+	}
+}
+
+func (limiter *MemoryLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := time.Now()
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	// drop the expired windows once per period so that keys seen only once don't pile up
+	if now.Sub(limiter.lastSweep) >= limiter.period {
+		for windowKey, w := range limiter.windows {
+			if !now.Before(w.resetAt) {
+				delete(limiter.windows, windowKey)
+			}
+		}
+		limiter.lastSweep = now
+	}
+
+	w, ok := limiter.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &window{resetAt: now.Add(limiter.period)}
+		limiter.windows[key] = w
+	}
+	w.count++
+
+	return newResult(limiter.limit, limiter.period, w.count, w.resetAt.Sub(now)), nil
+}
+
+// incrementScript increments the counter of a key, starting its window on the first request.
+// It runs as a script so that a counter can't be left without an expiry.
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// RedisLimiter keeps the counters in redis, shared by every instance of the api.
+type RedisLimiter struct {
+	client redis.Scripter
+	limit  int
+	period time.Duration
+}
+
+func NewRedisLimiter(client redis.Scripter, limit int, period time.Duration) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+		limit:  limit,
+		period: period,
+	}
+}
+
+func (limiter *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := incrementScript.Run(ctx, limiter.client, []string{"ratelimit:" + key}, limiter.period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(limiter.limit, limiter.period, int(values[0]), time.Duration(values[1])*time.Millisecond), nil
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/backendmaster/simple_bank/gapi"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func requireLimit(t *testing.T, limiter Limiter) {
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, "ip:1.2.3.4")
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, 3-i, result.Remaining)
+		require.True(t, result.ResetAfter > 0 && result.ResetAfter <= time.Minute)
+	}
+
+	result, err := limiter.Allow(ctx, "ip:1.2.3.4")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+
+	result, err = limiter.Allow(ctx, "ip:5.6.7.8")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	requireLimit(t, NewMemoryLimiter(3, time.Minute))
+}
+
+func TestMemoryLimiterWindowReset(t *testing.T) {
+	limiter := NewMemoryLimiter(1, 20*time.Millisecond)
+
+	result, err := limiter.Allow(context.Background(), "user:alice")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	result, err = limiter.Allow(context.Background(), "user:alice")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+
+	time.Sleep(30 * time.Millisecond)
+	result, err = limiter.Allow(context.Background(), "user:alice")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestRedisLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	requireLimit(t, NewRedisLimiter(client, 3, time.Minute))
+
+	server.FastForward(time.Minute)
+	result, err := NewRedisLimiter(client, 3, time.Minute).Allow(context.Background(), "ip:1.2.3.4")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestGinRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	keyFunc := func(ctx *gin.Context) string {
+		return IPKey(ctx.Request)
+	}
+	router.POST("/users/login", GinRateLimit(NewMemoryLimiter(1, time.Minute), keyFunc), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "60", recorder.Header().Get("X-RateLimit-Reset"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+}
+
+func TestGinRateLimitFailOpen(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users", GinRateLimit(NewRedisLimiter(client, 1, time.Minute), func(ctx *gin.Context) string {
+		return IPKey(ctx.Request)
+	}), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestGinRateLimitForgedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/users/login", GinRateLimit(NewMemoryLimiter(1, time.Minute), func(ctx *gin.Context) string {
+		return IPKey(ctx.Request)
+	}), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	// the client isn't a trusted proxy: a new X-Forwarded-For on each request doesn't get a new quota
+	for i, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/users/login", nil)
+		require.NoError(t, err)
+		request.RemoteAddr = "192.0.2.1:54321"
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+		router.ServeHTTP(recorder, request)
+		require.Equal(t, expected, recorder.Code)
+	}
+}
+
+func TestHttpRateLimit(t *testing.T) {
+	handler := HttpRateLimit(NewMemoryLimiter(1, time.Minute), gapi.GatewayErrorHandler, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+	}), "/v1/login_user")
+
+	for _, path := range []string{"/v1/login_user", "/v1/get_user/alice", "/v1/get_user/alice"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, path, nil)
+		require.NoError(t, err)
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/v1/login_user", nil)
+	require.NoError(t, err)
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+	// the body is the error of the gateway
+	require.JSONEq(t, `{"err":"`+rateLimitMessage+`","code":"ResourceExhausted"}`, recorder.Body.String())
+}
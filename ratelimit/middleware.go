@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const rateLimitMessage = "too many requests, retry later"
+
+// allow checks the quota of key and sets the X-RateLimit-* headers. When the limiter fails,
+// e.g. redis is down, the request is let through: an outage of the limiter shouldn't take the api down.
+func allow(limiter Limiter, req *http.Request, header http.Header, key string) bool {
+	result, err := limiter.Allow(req.Context(), key)
+	if err != nil {
+		log.Error().Err(err).Str("key", key).Msg("rate limiter failed")
+		return true
+	}
+	SetHeaders(header, result)
+	return result.Allowed
+}
+
+// SetHeaders tells the client its quota with the X-RateLimit-* headers, and when to retry with
+// Retry-After once it went over it.
+func SetHeaders(header http.Header, result Result) {
+	resetSeconds := strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds())))
+	header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("X-RateLimit-Reset", resetSeconds)
+	if !result.Allowed {
+		header.Set("Retry-After", resetSeconds)
+	}
+}
+
+// IPKey identifies the client of req by its ip, which X-Forwarded-For only sets when it comes
+// from a trusted proxy, so that a client can't get a new quota by sending a new header.
+func IPKey(req *http.Request) string {
+	return "ip:" + util.ClientIP(req.Header.Values("X-Forwarded-For"), req.RemoteAddr)
+}
+
+// GinRateLimit answers 429 once the client identified by key went over its quota.
+// The route is part of the key, so that each route has its own quota.
+func GinRateLimit(limiter Limiter, key func(ctx *gin.Context) string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if limiter != nil && !allow(limiter, ctx.Request, ctx.Writer.Header(), ctx.FullPath()+":"+key(ctx)) {
+			envelope.Abort(ctx, http.StatusTooManyRequests, rateLimitMessage)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// HttpRateLimit is the net/http version of GinRateLimit, limiting only the requests to paths by client ip.
+// The 429 is written by errorHandler, the one of the gateway, as a ResourceExhausted error.
+func HttpRateLimit(limiter Limiter, errorHandler runtime.ErrorHandlerFunc, handler http.Handler, paths ...string) http.Handler {
+	limited := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		limited[path] = true
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if limiter != nil && limited[req.URL.Path] && !allow(limiter, req, res.Header(), req.URL.Path+":"+IPKey(req)) {
+			errorHandler(req.Context(), nil, nil, res, req, status.Error(codes.ResourceExhausted, rateLimitMessage))
+			return
+		}
+		handler.ServeHTTP(res, req)
+	})
+}
@@ -0,0 +1,221 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrTooManyTransfers is returned by AllowTransfer once an account made its quota of transfers.
+var ErrTooManyTransfers = errors.New("too many transfers")
+
+// NewTransferLimiter creates the limiter of the outgoing transfers of each account described by
+// config, at most TRANSFER_RATE_LIMIT per TRANSFER_RATE_LIMIT_WINDOW, backed by redis when
+// REDIS_ADDRESS is set. It returns nil, which AllowTransfer treats as no limit, when
+// TRANSFER_RATE_LIMIT isn't set.
+func NewTransferLimiter(config util.Config) Limiter {
+	if config.TransferRateLimit <= 0 {
+		return nil
+	}
+	if config.RedisAddress != "" {
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return NewSlidingRedisLimiter(client, config.TransferRateLimit, config.TransferRateLimitWindow)
+	}
+	return NewSlidingMemoryLimiter(config.TransferRateLimit, config.TransferRateLimitWindow)
+}
+
+// AllowTransfer counts a transfer from accountID, returning an error wrapping ErrTooManyTransfers
+// that tells when to retry once the account made its quota. Like the middlewares, it lets the
+// transfer through when the limiter fails.
+func AllowTransfer(ctx context.Context, limiter Limiter, accountID int64) (Result, error) {
+	if limiter == nil {
+		return Result{Allowed: true}, nil
+	}
+	result, err := limiter.Allow(ctx, fmt.Sprintf("transfers:account:%d", accountID))
+	if err != nil {
+		log.Error().Err(err).Int64("account_id", accountID).Msg("transfer limiter failed")
+		return Result{Allowed: true}, nil
+	}
+	if !result.Allowed {
+		return result, fmt.Errorf("%w: account %d can make at most %d transfers per %s, retry in %s",
+			ErrTooManyTransfers, accountID, result.Limit, formatPeriod(result.Period), result.ResetAfter.Round(time.Second))
+	}
+	return result, nil
+}
+
+// formatPeriod drops the zero minutes and seconds of period, 1h rather than 1h0m0s.
+func formatPeriod(period time.Duration) string {
+	s := period.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// slidingResult approximates the requests of the last period by weighting the count of the
+// previous fixed window by how much of it the sliding window still covers, elapsed being how
+// far into the current window now is. When the request isn't allowed, ResetAfter is when the
+// previous window has faded enough for one more.
+func slidingResult(limit int, period, elapsed time.Duration, previous, current int, allowed bool) Result {
+	weight := 1 - float64(elapsed)/float64(period)
+	count := float64(previous)*weight + float64(current)
+	remaining := limit - int(count+0.999999)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	resetAfter := period - elapsed
+	if !allowed {
+		if current < limit {
+			// the current window has room, wait for the previous one to fade
+			fade := time.Duration(float64(period) * (1 - float64(limit-1-current)/float64(previous)))
+			resetAfter = fade - elapsed
+		} else {
+			// the current window is full, wait for it to become the previous one and fade in turn
+			fade := time.Duration(float64(period) * (1 - float64(limit-1)/float64(current)))
+			resetAfter = period - elapsed + fade
+		}
+		if resetAfter < 0 {
+			resetAfter = 0
+		}
+	}
+	return Result{
+		Allowed:    allowed,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: resetAfter,
+		Period:     period,
+	}
+}
+
+// slidingAllows tells whether one more request fits in the sliding window, in milliseconds so
+// that it gives the same answer as slidingScript.
+func slidingAllows(limit int, period, elapsed time.Duration, previous, current int) bool {
+	periodMs := period.Milliseconds()
+	return int64(previous)*(periodMs-elapsed.Milliseconds())+int64(current+1)*periodMs <= int64(limit)*periodMs
+}
+
+type slidingWindow struct {
+	index    int64
+	previous int
+	current  int
+}
+
+// SlidingMemoryLimiter keeps the counters of a sliding window in the process, which is only
+// right for a single instance. Unlike MemoryLimiter, it doesn't let a client make twice its
+// quota around the boundary of two windows.
+type SlidingMemoryLimiter struct {
+	limit   int
+	period  time.Duration
+	now     func() time.Time
+	mutex   sync.Mutex
+	windows map[string]*slidingWindow
+}
+
+func NewSlidingMemoryLimiter(limit int, period time.Duration) *SlidingMemoryLimiter {
+	return &SlidingMemoryLimiter{
+		limit:   limit,
+		period:  period,
+		now:     time.Now,
+		windows: make(map[string]*slidingWindow),
+	}
+}
+
+// Allow counts the request of key only when it is allowed, so that a client retrying too soon
+// doesn't push its own reset further away.
+func (limiter *SlidingMemoryLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := limiter.now()
+	index := now.UnixNano() / int64(limiter.period)
+	elapsed := time.Duration(now.UnixNano() % int64(limiter.period))
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	w, ok := limiter.windows[key]
+	if !ok {
+		// drop the windows too old to count anymore, before adding a key
+		for windowKey, old := range limiter.windows {
+			if old.index < index-1 {
+				delete(limiter.windows, windowKey)
+			}
+		}
+		w = &slidingWindow{index: index}
+		limiter.windows[key] = w
+	}
+	switch {
+	case w.index == index-1:
+		w.previous, w.current = w.current, 0
+	case w.index < index-1:
+		w.previous, w.current = 0, 0
+	}
+	w.index = index
+
+	allowed := slidingAllows(limiter.limit, limiter.period, elapsed, w.previous, w.current)
+	if allowed {
+		w.current++
+	}
+	return slidingResult(limiter.limit, limiter.period, elapsed, w.previous, w.current, allowed), nil
+}
+
+// slidingScript increments the counter of the current window of a key when the count of the
+// two windows, the previous one weighted by the milliseconds it still covers, leaves room for one
+// more request. The counter lives for two periods, as the previous window of the next one.
+var slidingScript = redis.NewScript(`
+local previous = tonumber(redis.call("GET", KEYS[1]) or "0")
+local current = tonumber(redis.call("GET", KEYS[2]) or "0")
+local limit, covered, period = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+if previous * covered + (current + 1) * period > limit * period then
+	return {0, previous, current}
+end
+current = redis.call("INCR", KEYS[2])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[2], 2 * period)
+end
+return {1, previous, current}
+`)
+
+// SlidingRedisLimiter keeps the counters of a sliding window in redis, shared by every instance
+// of the api. Each key costs two counters whatever its number of requests.
+type SlidingRedisLimiter struct {
+	client redis.Scripter
+	limit  int
+	period time.Duration
+	now    func() time.Time
+}
+
+func NewSlidingRedisLimiter(client redis.Scripter, limit int, period time.Duration) *SlidingRedisLimiter {
+	return &SlidingRedisLimiter{
+		client: client,
+		limit:  limit,
+		period: period,
+		now:    time.Now,
+	}
+}
+
+func (limiter *SlidingRedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := limiter.now()
+	index := now.UnixNano() / int64(limiter.period)
+	elapsed := time.Duration(now.UnixNano() % int64(limiter.period))
+
+	keys := []string{
+		"ratelimit:" + key + ":" + strconv.FormatInt(index-1, 10),
+		"ratelimit:" + key + ":" + strconv.FormatInt(index, 10),
+	}
+	values, err := slidingScript.Run(ctx, limiter.client, keys,
+		limiter.limit, (limiter.period - elapsed).Milliseconds(), limiter.period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	return slidingResult(limiter.limit, limiter.period, elapsed, int(values[1]), int(values[2]), values[0] == 1), nil
+}
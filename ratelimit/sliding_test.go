@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func requireSlidingLimit(t *testing.T, limiter Limiter, clock *time.Time) {
+	ctx := context.Background()
+	start := *clock
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, "transfers:account:1")
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "transfers:account:1")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Minute, result.Period)
+	// the full window must become the previous one, and a third of it must fade
+	require.Equal(t, 80*time.Second, result.ResetAfter)
+
+	result, err = limiter.Allow(ctx, "transfers:account:2")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// fixed windows would allow a burst right after the boundary, the sliding one doesn't
+	*clock = start.Add(61 * time.Second)
+	result, err = limiter.Allow(ctx, "transfers:account:1")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 19*time.Second, result.ResetAfter)
+
+	*clock = start.Add(79 * time.Second)
+	result, err = limiter.Allow(ctx, "transfers:account:1")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+
+	// the denied requests weren't counted
+	*clock = start.Add(80 * time.Second)
+	result, err = limiter.Allow(ctx, "transfers:account:1")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+
+	*clock = start.Add(3 * time.Minute)
+	result, err = limiter.Allow(ctx, "transfers:account:1")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Remaining)
+}
+
+func TestSlidingMemoryLimiter(t *testing.T) {
+	clock := time.Unix(0, 0).Add(1000 * time.Minute)
+	limiter := NewSlidingMemoryLimiter(3, time.Minute)
+	limiter.now = func() time.Time { return clock }
+
+	requireSlidingLimit(t, limiter, &clock)
+}
+
+func TestSlidingRedisLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	clock := time.Unix(0, 0).Add(1000 * time.Minute)
+	limiter := NewSlidingRedisLimiter(client, 3, time.Minute)
+	limiter.now = func() time.Time { return clock }
+
+	requireSlidingLimit(t, limiter, &clock)
+	// a counter outlives its window to count as the previous one of the next
+	require.Equal(t, 2*time.Minute, server.TTL("ratelimit:transfers:account:1:1003"))
+}
+
+func TestAllowTransfer(t *testing.T) {
+	result, err := AllowTransfer(context.Background(), nil, 1)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	limiter := NewSlidingMemoryLimiter(1, time.Hour)
+	_, err = AllowTransfer(context.Background(), limiter, 1)
+	require.NoError(t, err)
+
+	result, err = AllowTransfer(context.Background(), limiter, 1)
+	require.ErrorIs(t, err, ErrTooManyTransfers)
+	require.False(t, result.Allowed)
+	require.Contains(t, err.Error(), "account 1 can make at most 1 transfers per 1h, retry in")
+
+	_, err = AllowTransfer(context.Background(), limiter, 2)
+	require.NoError(t, err)
+}
@@ -0,0 +1,76 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/backendmaster/simple_bank/money"
+)
+
+// PDF renders receipt as a one page PDF, with the standard Helvetica font so that no font is
+// embedded.
+func PDF(receipt Receipt) []byte {
+	lines := []string{
+		"Simple Bank - Transfer receipt",
+		"",
+		"Transfer: #" + strconv.FormatInt(receipt.TransferID, 10),
+		"Date: " + receipt.CreatedAt.Format(time.RFC1123),
+		"From account: #" + strconv.FormatInt(receipt.FromAccountID, 10),
+		"To account: #" + strconv.FormatInt(receipt.ToAccountID, 10),
+		fmt.Sprintf("Amount: %s %s", money.Format(receipt.Amount, receipt.Currency), receipt.Currency),
+		"Memo: " + receipt.Memo,
+		"",
+		"Verification code: " + receipt.VerificationCode,
+		"Check this receipt at /receipts/verify with its transfer id and verification code.",
+	}
+
+	var content bytes.Buffer
+	content.WriteString("BT\n/F1 12 Tf\n14 TL\n50 780 Td\n")
+	for _, line := range lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDF(line))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+// escapePDF escapes s for a literal string of PDF, replacing what the font can't show.
+func escapePDF(s string) string {
+	var escaped strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r < ' ' || r > '~':
+			escaped.WriteByte('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
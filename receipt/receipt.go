@@ -0,0 +1,100 @@
+// Package receipt issues the receipts of the transfers. A receipt carries a verification code,
+// an HMAC of its fields under a key of the server, which anyone holding the receipt can check
+// with the server without an account, e.g. the counterparty of the transfer.
+package receipt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// MinKeySize is the size, in bytes, of the shortest signing key.
+const MinKeySize = 32
+
+// codeSize is the number of bytes of the HMAC a verification code keeps, 80 bits being out of
+// reach of guessing through a rate limited endpoint.
+const codeSize = 10
+
+// Receipt is the receipt of a completed transfer.
+type Receipt struct {
+	TransferID       int64     `json:"transfer_id"`
+	FromAccountID    int64     `json:"from_account_id"`
+	ToAccountID      int64     `json:"to_account_id"`
+	Amount           int64     `json:"amount"`
+	Currency         string    `json:"currency"`
+	Memo             string    `json:"memo"`
+	CreatedAt        time.Time `json:"created_at"`
+	VerificationCode string    `json:"verification_code"`
+}
+
+// Signer issues and verifies the receipts. The receipts are derived from the transfers, so
+// changing the key invalidates the codes of the receipts issued before.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key string) (*Signer, error) {
+	if len(key) < MinKeySize {
+		return nil, fmt.Errorf("invalid receipt key size: must be at least %d characters", MinKeySize)
+	}
+	return &Signer{key: []byte(key)}, nil
+}
+
+// Issue returns the receipt of transfer, in currency, the currency of its accounts.
+func (signer *Signer) Issue(transfer db.Transfer, currency string) Receipt {
+	receipt := Receipt{
+		TransferID:    transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        transfer.Amount,
+		Currency:      currency,
+		Memo:          transfer.Memo,
+		CreatedAt:     transfer.CreatedAt.UTC(),
+	}
+	receipt.VerificationCode = signer.code(receipt)
+	return receipt
+}
+
+// Verify tells whether code is the verification code of the receipt of transfer. It ignores the
+// case and the separators of code, which people type in.
+func (signer *Signer) Verify(transfer db.Transfer, currency string, code string) bool {
+	expected := normalizeCode(signer.Issue(transfer, currency).VerificationCode)
+	return hmac.Equal([]byte(expected), []byte(normalizeCode(code)))
+}
+
+// code signs the fields of receipt, the memo last, so that a | in it can't shift the others.
+func (signer *Signer) code(receipt Receipt) string {
+	mac := hmac.New(sha256.New, signer.key)
+	fmt.Fprintf(mac, "%d|%d|%d|%d|%s|%s|%s",
+		receipt.TransferID,
+		receipt.FromAccountID,
+		receipt.ToAccountID,
+		receipt.Amount,
+		receipt.Currency,
+		receipt.CreatedAt.Format(time.RFC3339Nano),
+		receipt.Memo,
+	)
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil)[:codeSize])
+
+	// groups of 4, e.g. ABCD-EFGH-IJKL-MNOP, to be read out loud
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
+
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
@@ -0,0 +1,79 @@
+package receipt
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	_, err := NewSigner("short")
+	require.Error(t, err)
+
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	transfer := db.Transfer{
+		ID:            42,
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        150,
+		CreatedAt:     time.Date(2026, 10, 16, 12, 0, 0, 123456000, time.UTC),
+		Memo:          "rent | october",
+	}
+
+	receipt := signer.Issue(transfer, util.USD)
+	require.Equal(t, transfer.ID, receipt.TransferID)
+	require.Equal(t, util.USD, receipt.Currency)
+	require.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, receipt.VerificationCode)
+	require.Equal(t, receipt, signer.Issue(transfer, util.USD))
+
+	require.True(t, signer.Verify(transfer, util.USD, receipt.VerificationCode))
+	// as typed in by a person
+	typed := strings.ToLower(strings.ReplaceAll(receipt.VerificationCode, "-", " "))
+	require.True(t, signer.Verify(transfer, util.USD, typed))
+
+	tampered := transfer
+	tampered.Amount = 1500
+	require.False(t, signer.Verify(tampered, util.USD, receipt.VerificationCode))
+	require.False(t, signer.Verify(transfer, util.EUR, receipt.VerificationCode))
+	require.False(t, signer.Verify(transfer, util.USD, ""))
+
+	other, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	require.False(t, other.Verify(transfer, util.USD, receipt.VerificationCode))
+}
+
+func TestPDF(t *testing.T) {
+	pdf := PDF(Receipt{
+		TransferID:       42,
+		FromAccountID:    1,
+		ToAccountID:      2,
+		Amount:           150,
+		Currency:         util.USD,
+		Memo:             "rent (october) é",
+		CreatedAt:        time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		VerificationCode: "ABCD-EFGH-IJKL-MNOP",
+	})
+
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	require.Contains(t, string(pdf), "(Amount: 1.50 USD) Tj")
+	require.Contains(t, string(pdf), `(Memo: rent \(october\) ?) Tj`)
+	require.Contains(t, string(pdf), "(Verification code: ABCD-EFGH-IJKL-MNOP) Tj")
+
+	// the cross-reference table points at the objects
+	xref := bytes.Index(pdf, []byte("xref\n"))
+	require.Contains(t, string(pdf), "startxref\n"+strconv.Itoa(xref)+"\n")
+	entries := strings.Split(string(pdf[xref:]), "\n")[3:8]
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(entry[:10])
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")))
+	}
+}
@@ -0,0 +1,119 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	requestIDHeader      = "x-request-id"
+	internalErrorMessage = "internal server error"
+)
+
+type errorResponse struct {
+	Err       string `json:"err"`
+	RequestID string `json:"request_id"`
+}
+
+// GrpcRecovery turns a panic in a unary handler into an Internal error carrying the request id,
+// so that one bad request doesn't take the whole server down.
+func GrpcRecovery(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			requestID := ""
+			if md, ok := metadata.FromIncomingContext(ctx); ok {
+				if values := md.Get(requestIDHeader); len(values) > 0 {
+					requestID = values[0]
+				}
+			}
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+
+			logPanic(r, "grpc", requestID, info.FullMethod)
+			err = status.Errorf(codes.Internal, "%s (request id: %s)", internalErrorMessage, requestID)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// HttpRecovery turns a panic in handler into a 500 response carrying the request id.
+// http.ErrAbortHandler is let through since it is the way to abort a response on purpose.
+func HttpRecovery(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if r := recover(); r != nil {
+				if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(r)
+				}
+
+				requestID := requestIDFromHeader(req)
+				logPanic(r, "http", requestID, req.RequestURI)
+
+				body, _ := json.Marshal(errorResponse{Err: internalErrorMessage, RequestID: requestID})
+				res.Header().Set(requestIDHeader, requestID)
+				res.Header().Set("Content-Type", "application/json")
+				res.WriteHeader(http.StatusInternalServerError)
+				res.Write(body)
+			}
+		}()
+
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// GinRecovery is the gin version of HttpRecovery, replacing the default gin recovery
+// so that panics end up in the same logs and metrics as the rest of the requests.
+func GinRecovery() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(r)
+				}
+
+				requestID := requestIDFromHeader(ctx.Request)
+				logPanic(r, "http", requestID, ctx.Request.RequestURI)
+
+				ctx.Request.Header.Set(requestIDHeader, requestID)
+				ctx.Header(requestIDHeader, requestID)
+				envelope.Abort(ctx, http.StatusInternalServerError, internalErrorMessage)
+			}
+		}()
+
+		ctx.Next()
+	}
+}
+
+func requestIDFromHeader(req *http.Request) string {
+	if requestID := req.Header.Get(requestIDHeader); requestID != "" {
+		return requestID
+	}
+	return uuid.NewString()
+}
+
+func logPanic(r interface{}, protocol string, requestID string, method string) {
+	metrics.ObservePanic(protocol)
+	log.Error().
+		Str("protocol", protocol).
+		Str("request id", requestID).
+		Str("method", method).
+		Str("panic", fmt.Sprint(r)).
+		Bytes("stack", debug.Stack()).
+		Msg("recovered from panic")
+}
@@ -0,0 +1,82 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/envelope"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcRecovery(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.simple_bank/GetUser"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil map")
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDHeader, "test-request-id"))
+
+	_, err := GrpcRecovery(ctx, nil, info, handler)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Internal, st.Code())
+	require.Contains(t, st.Message(), "test-request-id")
+}
+
+func TestHttpRecovery(t *testing.T) {
+	handler := HttpRecovery(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic("nil map")
+	}))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/get_user/alice", nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeader, "test-request-id")
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var rsp errorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, internalErrorMessage, rsp.Err)
+	require.Equal(t, "test-request-id", rsp.RequestID)
+}
+
+func TestHttpRecoveryAbortHandler(t *testing.T) {
+	handler := HttpRecovery(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	request, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	})
+}
+
+func TestGinRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinRecovery())
+	router.GET("/accounts/:id", func(ctx *gin.Context) {
+		panic("nil map")
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var rsp envelope.Envelope
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.NotEmpty(t, rsp.RequestID)
+	require.Equal(t, []envelope.Error{{Message: internalErrorMessage}}, rsp.Errors)
+	require.Equal(t, rsp.RequestID, recorder.Header().Get(requestIDHeader))
+}
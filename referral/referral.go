@@ -0,0 +1,63 @@
+// Package referral is the referral program: a user shares its code, and once someone who signed up
+// with it makes a qualifying transfer within the window of the program, both are paid a bonus.
+package referral
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// CodeLength is the length of the referral codes.
+const CodeLength = 8
+
+// codeAlphabet leaves out the letters and digits easily mistaken for one another, e.g. O and 0,
+// since the codes are typed in by hand.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewCode generates a random referral code.
+func NewCode() (string, error) {
+	code := make([]byte, CodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(codeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %w", err)
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// Program holds the terms of the referrals.
+type Program struct {
+	// Bonus is what the referrer and the referee are each paid, in the currency of the account
+	// credited, e.g. cents. The program is off at 0.
+	Bonus int64
+	// MinAmount is the amount the qualifying transfer of a referee needs.
+	MinAmount int64
+	// Window is how long after signing up a referee has to qualify.
+	Window time.Duration
+	// MaxPerReferrer caps the referrals a referrer is paid for, 0 for no cap. The referrals past
+	// the cap are rejected, so that nobody farms bonuses from accounts of its own.
+	MaxPerReferrer int64
+}
+
+// Deadline returns when the window of a referral to qualify closes.
+func (program Program) Deadline(referral db.Referral) time.Time {
+	return referral.CreatedAt.Add(program.Window)
+}
+
+// QualifyingTransfer returns the params finding the first transfer qualifying a referral: one the
+// referee sent within the window to someone other than the referrer, of at least MinAmount.
+func (program Program) QualifyingTransfer(referral db.Referral) db.GetFirstQualifyingTransferParams {
+	return db.GetFirstQualifyingTransferParams{
+		Referee:   referral.Referee,
+		Referrer:  referral.Referrer,
+		MinAmount: program.MinAmount,
+		FromTime:  referral.CreatedAt,
+		ToTime:    program.Deadline(referral),
+	}
+}
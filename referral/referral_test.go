@@ -0,0 +1,39 @@
+package referral
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCode(t *testing.T) {
+	codes := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		code, err := NewCode()
+		require.NoError(t, err)
+		require.Len(t, code, CodeLength)
+		for _, c := range code {
+			require.True(t, strings.ContainsRune(codeAlphabet, c), "unexpected %q in %q", c, code)
+		}
+		codes[code] = true
+	}
+	require.Len(t, codes, 100)
+}
+
+func TestQualifyingTransfer(t *testing.T) {
+	program := Program{Bonus: 1000, MinAmount: 2500, Window: 48 * time.Hour}
+	signup := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	referral := db.Referral{Referrer: "alice", Referee: "bob", CreatedAt: signup}
+
+	require.Equal(t, signup.Add(48*time.Hour), program.Deadline(referral))
+	require.Equal(t, db.GetFirstQualifyingTransferParams{
+		Referee:   "bob",
+		Referrer:  "alice",
+		MinAmount: 2500,
+		FromTime:  signup,
+		ToTime:    time.Date(2024, time.March, 3, 12, 0, 0, 0, time.UTC),
+	}, program.QualifyingTransfer(referral))
+}
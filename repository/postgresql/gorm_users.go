@@ -35,12 +35,23 @@ func (p *postgresqlUserRepository) GetByUsername(cxt context.Context, username s
 	return user, nil
 }
 
-func (p *postgresqlUserRepository) Create(cxt context.Context, user domain.User) (*domain.User, error) {
+// Create inserts the user and its outbox tasks in one transaction.
+func (p *postgresqlUserRepository) Create(cxt context.Context, user domain.User, tasks ...domain.OutboxTask) (*domain.User, error) {
 
 	// repository.Create(user)
-	result := p.db.Create(&user)
-	if result.Error != nil {
-		if pqErr, ok := result.Error.(*pq.Error); ok {
+	err := p.db.WithContext(cxt).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		for i := range tasks {
+			if err := tx.Create(&tasks[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
 				return nil, domain.ErrorUniqueViolation
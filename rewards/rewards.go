@@ -0,0 +1,42 @@
+// Package rewards is the cashback program: a payment to someone else, by transfer or by card,
+// earns its payer points, a share of its amount, which they redeem later as a credit to the
+// account, a point for each unit of its currency, e.g. a cent.
+package rewards
+
+import (
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// bpsPerUnit is the number of basis points in a rate of 1, i.e. 100%.
+const bpsPerUnit = 10000
+
+// Program holds the terms of the cashback.
+type Program struct {
+	// RateBps is the share of the amount of a transfer it earns, in hundredths of a percent. The
+	// program is off at 0.
+	RateBps int64
+	// MinAmount is the amount a transfer needs to earn points.
+	MinAmount int64
+	// MaxPoints caps the points of a transfer, 0 for no cap.
+	MaxPoints int64
+}
+
+// Points returns the points a transfer earns its payer: none unless a user pays someone else at
+// least MinAmount, the share of RateBps of its amount otherwise, rounded down. A card payment is
+// paid to the suspense account, and earns points like any other.
+func (program Program) Points(transfer db.Transfer, from, to db.Account) int64 {
+	if program.RateBps <= 0 || transfer.Amount < program.MinAmount {
+		return 0
+	}
+	// moving money between one's own accounts earns nothing, nor does money the bank pays out,
+	// e.g. the redemptions themselves
+	if from.Owner == to.Owner || db.IsBankOwner(from.Owner) {
+		return 0
+	}
+
+	points := transfer.Amount * program.RateBps / bpsPerUnit
+	if program.MaxPoints > 0 && points > program.MaxPoints {
+		points = program.MaxPoints
+	}
+	return points
+}
@@ -0,0 +1,44 @@
+package rewards
+
+import (
+	"testing"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoints(t *testing.T) {
+	program := Program{RateBps: 150, MinAmount: 1000, MaxPoints: 500}
+	alice := db.Account{ID: 1, Owner: "alice"}
+	aliceSavings := db.Account{ID: 2, Owner: "alice"}
+	bob := db.Account{ID: 3, Owner: "bob"}
+	suspense := db.Account{ID: 4, Owner: db.SuspenseOwner}
+
+	testCases := []struct {
+		name    string
+		program Program
+		amount  int64
+		from    db.Account
+		to      db.Account
+		points  int64
+	}{
+		{name: "Payment", program: program, amount: 10000, from: alice, to: bob, points: 150},
+		{name: "RoundedDown", program: program, amount: 1999, from: alice, to: bob, points: 29},
+		{name: "MinAmount", program: program, amount: 1000, from: alice, to: bob, points: 15},
+		{name: "UnderMinAmount", program: program, amount: 999, from: alice, to: bob},
+		{name: "Capped", program: program, amount: 1000000, from: alice, to: bob, points: 500},
+		{name: "NoCap", program: Program{RateBps: 150}, amount: 1000000, from: alice, to: bob, points: 15000},
+		{name: "OwnAccounts", program: program, amount: 10000, from: alice, to: aliceSavings},
+		{name: "CardPayment", program: program, amount: 10000, from: alice, to: suspense, points: 150},
+		{name: "FromBank", program: program, amount: 10000, from: suspense, to: bob},
+		{name: "Off", amount: 10000, from: alice, to: bob},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			transfer := db.Transfer{FromAccountID: tc.from.ID, ToAccountID: tc.to.ID, Amount: tc.amount}
+			require.Equal(t, tc.points, tc.program.Points(transfer, tc.from, tc.to))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package screening screens the users and the accounts against the parties the bank doesn't
+// serve, e.g. those of a sanctions list. The users are screened when they sign up, and the
+// accounts on every transfer from or to them; a match holds the transfer for a review.
+package screening
+
+import (
+	"context"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+)
+
+// Screener is the screening hook. Denylist screens against the denylist of the database, another
+// implementation could ask a sanctions provider.
+type Screener interface {
+	// ScreenUser returns what user matched, e.g. name:John Doe, nothing for a user in the clear.
+	// Its signature is that of db.ScreenFunc.
+	ScreenUser(ctx context.Context, user db.User) ([]string, error)
+	// ScreenAccount returns what flags account, nothing for an account in the clear.
+	ScreenAccount(ctx context.Context, account db.Account) ([]string, error)
+}
+
+// Store is the part of db.Store the denylist needs.
+type Store interface {
+	MatchDenylist(ctx context.Context, arg db.MatchDenylistParams) ([]db.DenylistEntry, error)
+	ListAccountScreeningMatches(ctx context.Context, accountID int64) ([]string, error)
+}
+
+// Denylist screens against the denylist entries of store. An account is flagged by an entry of
+// its own, or by an open screening hold of its owner, which the admins clear for a namesake.
+type Denylist struct {
+	store Store
+}
+
+func NewDenylist(store Store) *Denylist {
+	return &Denylist{store: store}
+}
+
+func (denylist *Denylist) ScreenUser(ctx context.Context, user db.User) ([]string, error) {
+	entries, err := denylist.store.MatchDenylist(ctx, db.MatchDenylistParams{
+		FullName: user.FullName,
+		Email:    user.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		matches = append(matches, entry.Match())
+	}
+	return matches, nil
+}
+
+func (denylist *Denylist) ScreenAccount(ctx context.Context, account db.Account) ([]string, error) {
+	return denylist.store.ListAccountScreeningMatches(ctx, account.ID)
+}
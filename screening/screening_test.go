@@ -0,0 +1,47 @@
+package screening
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenylist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	denylist := NewDenylist(store)
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: "John Doe",
+		Email:    util.RandomEmail(),
+	}
+
+	store.EXPECT().
+		MatchDenylist(gomock.Any(), gomock.Eq(db.MatchDenylistParams{FullName: user.FullName, Email: user.Email})).
+		Times(1).
+		Return([]db.DenylistEntry{
+			{Kind: db.DenylistName, Value: "john doe"},
+			{Kind: db.DenylistEmail, Value: user.Email},
+		}, nil)
+	matches, err := denylist.ScreenUser(context.Background(), user)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name:john doe", "email:" + user.Email}, matches)
+
+	store.EXPECT().MatchDenylist(gomock.Any(), gomock.Any()).Times(1).Return([]db.DenylistEntry{}, nil)
+	matches, err = denylist.ScreenUser(context.Background(), user)
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	account := db.Account{ID: util.RandomInt(1, 1000), Owner: user.Username}
+	store.EXPECT().ListAccountScreeningMatches(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]string{"name:john doe"}, nil)
+	matches, err = denylist.ScreenAccount(context.Background(), account)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name:john doe"}, matches)
+}
@@ -0,0 +1,185 @@
+// Package seed fills a development database with users, accounts and a transfer history, going
+// through the store like the servers do so that the data keeps every invariant of the ledger.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// Store is what seeding needs of db.Store.
+type Store interface {
+	db.UserStore
+	db.AccountStore
+	db.TransferStore
+}
+
+// Options are how much data to seed.
+type Options struct {
+	Users int
+	// AccountsPerUser is at most one per currency, a user can't have two accounts in the same currency.
+	AccountsPerUser int
+	Transfers       int
+	// Password is the password of every seeded user, so that they can all log in.
+	Password string
+	// Seed makes the generated data reproducible on an empty database, a random one when 0.
+	Seed int64
+}
+
+// Result is what was seeded.
+type Result struct {
+	Usernames []string
+	Accounts  int
+	Transfers int
+}
+
+var (
+	currencies = []string{util.USD, util.EUR, util.CAD}
+	firstNames = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Irene", "Jack", "Karen", "Liam"}
+	lastNames  = []string{"Smith", "Johnson", "Brown", "Taylor", "Miller", "Wilson", "Moore", "Clark", "Lewis", "Walker"}
+	// most transfers have no memo
+	memos = []string{"", "", "", "", "rent", "dinner", "groceries", "concert tickets", "birthday present", "utilities", "coffee", "taxi"}
+)
+
+// maxUsernameAttempts is how many usernames are tried for a user before giving up, when
+// the ones generated are already taken, e.g. by a previous run with the same seed.
+const maxUsernameAttempts = 5
+
+// maxTransferAttempts is how many pairs of accounts are tried for a transfer before giving up,
+// when the ones picked are both broke.
+const maxTransferAttempts = 10
+
+// Run seeds store according to options.
+func Run(ctx context.Context, store Store, options Options) (Result, error) {
+	var result Result
+	if options.AccountsPerUser > len(currencies) {
+		return result, fmt.Errorf("at most %d accounts per user, one per currency", len(currencies))
+	}
+	seed := options.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	hashedPassword, err := util.HashedPassword(options.Password)
+	if err != nil {
+		return result, err
+	}
+
+	accounts := make(map[string][]db.Account)
+	for i := 0; i < options.Users; i++ {
+		user, err := createUser(ctx, store, rng, hashedPassword)
+		if err != nil {
+			return result, err
+		}
+		result.Usernames = append(result.Usernames, user.Username)
+
+		for _, j := range rng.Perm(len(currencies))[:options.AccountsPerUser] {
+			account, err := store.CreateAccount(ctx, db.CreateAccountParams{
+				Owner:    user.Username,
+				Balance:  1000 + rng.Int63n(9000),
+				Currency: currencies[j],
+			})
+			if err != nil {
+				return result, fmt.Errorf("create account of %s: %w", user.Username, err)
+			}
+			accounts[account.Currency] = append(accounts[account.Currency], account)
+			result.Accounts++
+		}
+	}
+
+	for result.Transfers < options.Transfers {
+		arg, ok := randomTransfer(rng, accounts)
+		if !ok {
+			break
+		}
+		if _, err := store.TransferTx(ctx, arg); err != nil {
+			return result, fmt.Errorf("transfer from %d to %d: %w", arg.FromAccountID, arg.ToAccountID, err)
+		}
+		result.Transfers++
+	}
+	return result, nil
+}
+
+func createUser(ctx context.Context, store Store, rng *rand.Rand, hashedPassword string) (db.User, error) {
+	for attempt := 1; ; attempt++ {
+		first := firstNames[rng.Intn(len(firstNames))]
+		last := lastNames[rng.Intn(len(lastNames))]
+		username := fmt.Sprintf("%s%d", randomLetters(rng, 6), rng.Intn(1000))
+		user, err := store.CreateUser(ctx, db.CreateUserParams{
+			Username:       username,
+			HashedPassword: hashedPassword,
+			FullName:       first + " " + last,
+			Email:          username + "@example.com",
+		})
+		if db.ErrorCode(err) == db.UniqueViolation && attempt < maxUsernameAttempts {
+			continue
+		}
+		if err != nil {
+			return user, fmt.Errorf("create user %s: %w", username, err)
+		}
+		return user, nil
+	}
+}
+
+// randomTransfer picks two accounts of different owners in the same currency and an amount the
+// sender can afford, mostly small ones with the odd large one. It keeps the balances of accounts
+// up to date, and is not ok when no two accounts can trade.
+func randomTransfer(rng *rand.Rand, accounts map[string][]db.Account) (db.TransferTxParams, bool) {
+	var tradable []string
+	for _, currency := range currencies {
+		if len(accounts[currency]) > 1 {
+			tradable = append(tradable, currency)
+		}
+	}
+	if len(tradable) == 0 {
+		return db.TransferTxParams{}, false
+	}
+
+	for attempt := 0; attempt < maxTransferAttempts; attempt++ {
+		currency := tradable[rng.Intn(len(tradable))]
+		candidates := accounts[currency]
+		from := &candidates[rng.Intn(len(candidates))]
+		to := &candidates[rng.Intn(len(candidates))]
+		for to.Owner == from.Owner {
+			to = &candidates[rng.Intn(len(candidates))]
+		}
+
+		amount := 1 + rng.Int63n(100)
+		if rng.Intn(10) == 0 {
+			amount = 100 + rng.Int63n(900)
+		}
+		if amount > from.Balance {
+			// the sender is broke, the receiver pays it back instead
+			from, to = to, from
+			if amount > from.Balance {
+				amount = from.Balance
+			}
+		}
+		if amount == 0 {
+			continue
+		}
+		from.Balance -= amount
+		to.Balance += amount
+
+		return db.TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			Memo:          memos[rng.Intn(len(memos))],
+		}, true
+	}
+	return db.TransferTxParams{}, false
+}
+
+func randomLetters(rng *rand.Rand, n int) string {
+	letters := make([]byte, n)
+	for i := range letters {
+		letters[i] = byte('a' + rng.Intn(26))
+	}
+	return string(letters)
+}
@@ -0,0 +1,138 @@
+package seed
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     Options
+		buildStubs  func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, result Result, err error)
+	}{
+		{
+			name:    "OK",
+			options: Options{Users: 4, AccountsPerUser: 3, Transfers: 20, Password: "secret", Seed: 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				accounts := stubUsersAndAccounts(store)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(20).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						from, to := accounts[arg.FromAccountID], accounts[arg.ToAccountID]
+						require.NotEqual(t, from.Owner, to.Owner)
+						require.Equal(t, from.Currency, to.Currency)
+						require.Positive(t, arg.Amount)
+						require.LessOrEqual(t, arg.Amount, from.Balance)
+						from.Balance -= arg.Amount
+						to.Balance += arg.Amount
+						return db.TransferTxResult{}, nil
+					})
+			},
+			checkResult: func(t *testing.T, result Result, err error) {
+				require.NoError(t, err)
+				require.Len(t, result.Usernames, 4)
+				require.Equal(t, 12, result.Accounts)
+				require.Equal(t, 20, result.Transfers)
+			},
+		},
+		{
+			name:    "UsernameTaken",
+			options: Options{Users: 1, AccountsPerUser: 1, Password: "secret", Seed: 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						CreateUser(gomock.Any(), gomock.Any()).
+						Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation}),
+					store.EXPECT().
+						CreateUser(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.User, error) {
+							return db.User{Username: arg.Username}, nil
+						}),
+				)
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{ID: 1}, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, result Result, err error) {
+				require.NoError(t, err)
+				require.Len(t, result.Usernames, 1)
+			},
+		},
+		{
+			name:    "NoOneToTradeWith",
+			options: Options{Users: 1, AccountsPerUser: 3, Transfers: 10, Password: "secret", Seed: 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUsersAndAccounts(store)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, result Result, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, result.Accounts)
+				require.Zero(t, result.Transfers)
+			},
+		},
+		{
+			name:    "TooManyAccounts",
+			options: Options{Users: 1, AccountsPerUser: 4, Password: "secret"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, result Result, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			result, err := Run(context.Background(), store, tc.options)
+			tc.checkResult(t, result, err)
+		})
+	}
+}
+
+// stubUsersAndAccounts makes the store create the users and accounts it is given, and returns
+// the accounts created by id.
+func stubUsersAndAccounts(store *mockdb.MockStore) map[int64]*db.Account {
+	store.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.User, error) {
+			return db.User{Username: arg.Username, FullName: arg.FullName, Email: arg.Email}, nil
+		})
+	accounts := make(map[int64]*db.Account)
+	store.EXPECT().
+		CreateAccount(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.CreateAccountParams) (db.Account, error) {
+			account := db.Account{ID: int64(len(accounts) + 1), Owner: arg.Owner, Balance: arg.Balance, Currency: arg.Currency}
+			accounts[account.ID] = &account
+			return account, nil
+		})
+	return accounts
+}
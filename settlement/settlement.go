@@ -0,0 +1,151 @@
+// Package settlement clears the transfers to the accounts of other banks, through ACH for the
+// dollars and SEPA for the euros. A transfer is submitted to its network, which settles it some
+// time later, or returns it, e.g. for a closed beneficiary account. The bank isn't a member of
+// either network, so Simulator stands in for them.
+package settlement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+)
+
+// The rails, i.e. the clearing networks, of the external transfers.
+const (
+	RailACH  = "ach"
+	RailSEPA = "sepa"
+)
+
+var (
+	// ErrNoRail is returned by RailFor for a currency no network clears.
+	ErrNoRail = errors.New("no clearing network for the currency")
+	// ErrInvalidBeneficiary is returned by ValidateBeneficiary for an account the network would refuse.
+	ErrInvalidBeneficiary = errors.New("invalid beneficiary account")
+)
+
+var (
+	isIBANFormat          = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`).MatchString
+	isRoutingNumberFormat = regexp.MustCompile(`^[0-9]{9}$`).MatchString
+	isACHAccountFormat    = regexp.MustCompile(`^[0-9]{4,17}$`).MatchString
+)
+
+// RailFor returns the rail of the external transfers in currency.
+func RailFor(currency string) (string, error) {
+	switch currency {
+	case util.USD:
+		return RailACH, nil
+	case util.EUR:
+		return RailSEPA, nil
+	}
+	return "", fmt.Errorf("%w %s", ErrNoRail, currency)
+}
+
+// ValidateBeneficiary checks the account of a beneficiary on rail: an IBAN for SEPA, and for ACH
+// an account number at the bank of a routing number. Both carry check digits, which catch most
+// typos before the network returns the transfer.
+func ValidateBeneficiary(rail, account, routing string) error {
+	switch rail {
+	case RailSEPA:
+		if !isValidIBAN(account) {
+			return fmt.Errorf("%w: %q is no IBAN", ErrInvalidBeneficiary, account)
+		}
+		if routing != "" {
+			return fmt.Errorf("%w: a SEPA transfer has no routing number", ErrInvalidBeneficiary)
+		}
+	case RailACH:
+		if !isACHAccountFormat(account) {
+			return fmt.Errorf("%w: %q is no account number", ErrInvalidBeneficiary, account)
+		}
+		if !isValidRoutingNumber(routing) {
+			return fmt.Errorf("%w: %q is no routing number", ErrInvalidBeneficiary, routing)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rail %q", ErrInvalidBeneficiary, rail)
+	}
+	return nil
+}
+
+// isValidIBAN checks the format and the check digits of an IBAN, e.g. DE89370400440532013000.
+func isValidIBAN(iban string) bool {
+	if !isIBANFormat(iban) {
+		return false
+	}
+	// the first 4 characters move to the end and the letters become numbers, A being 10, and the
+	// remainder by 97 of the resulting number must be 1
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprint(&digits, r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	number, _ := new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
+
+// isValidRoutingNumber checks the check digit of an ABA routing number, e.g. 110000000.
+func isValidRoutingNumber(routing string) bool {
+	if !isRoutingNumberFormat(routing) {
+		return false
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, r := range routing {
+		sum += weights[i%3] * int(r-'0')
+	}
+	return sum%10 == 0
+}
+
+// Outcome is how a network ended a transfer.
+type Outcome struct {
+	Returned bool
+	// Reason is the return code of the network and its meaning, e.g. R02: account closed.
+	Reason string
+}
+
+// Network is where the settlement worker submits the external transfers.
+type Network interface {
+	// Submit submits transfer, returning its reference in the network and when it is due to settle.
+	Submit(ctx context.Context, transfer db.ExternalTransfer) (reference string, settleAt time.Time, err error)
+	// Outcome tells how a submitted transfer due to settle ended.
+	Outcome(ctx context.Context, transfer db.ExternalTransfer) (Outcome, error)
+}
+
+// closedAccountSuffix ends the beneficiary accounts Simulator returns the transfers to.
+const closedAccountSuffix = "0000"
+
+// returnReasons are the codes the networks return a transfer to a closed account with.
+var returnReasons = map[string]string{
+	RailACH:  "R02: account closed",
+	RailSEPA: "AC04: closed account number",
+}
+
+// Simulator is a Network settling every transfer delay after its submission, but those to a
+// beneficiary account ending in 0000, e.g. DE94370400440532010000, which it returns as closed.
+type Simulator struct {
+	delay time.Duration
+}
+
+func NewSimulator(delay time.Duration) *Simulator {
+	return &Simulator{delay: delay}
+}
+
+func (simulator *Simulator) Submit(ctx context.Context, transfer db.ExternalTransfer) (string, time.Time, error) {
+	reference := fmt.Sprintf("%s-%010d", strings.ToUpper(transfer.Rail), transfer.ID)
+	return reference, time.Now().Add(simulator.delay), nil
+}
+
+func (simulator *Simulator) Outcome(ctx context.Context, transfer db.ExternalTransfer) (Outcome, error) {
+	if strings.HasSuffix(transfer.BeneficiaryAccount, closedAccountSuffix) {
+		return Outcome{Returned: true, Reason: returnReasons[transfer.Rail]}, nil
+	}
+	return Outcome{}, nil
+}
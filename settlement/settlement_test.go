@@ -0,0 +1,76 @@
+package settlement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRailFor(t *testing.T) {
+	rail, err := RailFor(util.USD)
+	require.NoError(t, err)
+	require.Equal(t, RailACH, rail)
+
+	rail, err = RailFor(util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, RailSEPA, rail)
+
+	_, err = RailFor(util.CAD)
+	require.ErrorIs(t, err, ErrNoRail)
+}
+
+func TestValidateBeneficiary(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rail    string
+		account string
+		routing string
+		valid   bool
+	}{
+		{name: "IBAN", rail: RailSEPA, account: "DE89370400440532013000", valid: true},
+		{name: "IBANTypo", rail: RailSEPA, account: "DE89370400440532013001"},
+		{name: "IBANWithRouting", rail: RailSEPA, account: "DE89370400440532013000", routing: "110000000"},
+		{name: "NotIBAN", rail: RailSEPA, account: "000123456789"},
+		{name: "ACH", rail: RailACH, account: "000123456789", routing: "110000000", valid: true},
+		{name: "RoutingTypo", rail: RailACH, account: "000123456789", routing: "110000001"},
+		{name: "WithoutRouting", rail: RailACH, account: "000123456789"},
+		{name: "ACHAccountTooShort", rail: RailACH, account: "123", routing: "110000000"},
+		{name: "UnknownRail", rail: "swift", account: "DE89370400440532013000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBeneficiary(tc.rail, tc.account, tc.routing)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.True(t, errors.Is(err, ErrInvalidBeneficiary), err)
+			}
+		})
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	simulator := NewSimulator(time.Hour)
+	transfer := db.ExternalTransfer{ID: 42, Rail: RailSEPA, BeneficiaryAccount: "DE89370400440532013000"}
+
+	reference, settleAt, err := simulator.Submit(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, "SEPA-0000000042", reference)
+	require.WithinDuration(t, time.Now().Add(time.Hour), settleAt, time.Second)
+
+	outcome, err := simulator.Outcome(context.Background(), transfer)
+	require.NoError(t, err)
+	require.False(t, outcome.Returned)
+
+	transfer.BeneficiaryAccount = "DE94370400440532010000"
+	require.NoError(t, ValidateBeneficiary(transfer.Rail, transfer.BeneficiaryAccount, ""))
+	outcome, err = simulator.Outcome(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, Outcome{Returned: true, Reason: "AC04: closed account number"}, outcome)
+}
@@ -4,7 +4,7 @@ set -e
 echo "run db migration"
 # source /app/app.env
 echo "$DB_SOURCE"
-/app/migrate -path /app/migration -database "$DB_SOURCE" -verbose up
+/app/main migrate up
 
 echo "start the app"
 exec "$@"
\ No newline at end of file
@@ -0,0 +1,120 @@
+// Package testenv starts the Postgres and Redis the integration tests run against, in throwaway
+// docker containers, so that they need nothing but docker:
+//
+//	go test -tags integration ./db/sqlc ./cache
+package testenv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/backendmaster/simple_bank/db/migration"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// maxWait is how long a container has to accept connections.
+	maxWait = time.Minute
+	// expire is when docker kills the containers of a test run that died before removing them.
+	expire = 10 * time.Minute
+)
+
+// Env is the containers of a test run.
+type Env struct {
+	pool      *dockertest.Pool
+	resources []*dockertest.Resource
+}
+
+// New connects to the docker daemon of the environment, e.g. DOCKER_HOST.
+func New() (*Env, error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("connect to docker: %w", err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		return nil, fmt.Errorf("connect to docker: %w", err)
+	}
+	pool.MaxWait = maxWait
+	return &Env{pool: pool}, nil
+}
+
+// Postgres starts a Postgres migrated to the latest version and returns its source.
+func (env *Env) Postgres(ctx context.Context) (string, error) {
+	resource, err := env.run(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "12-alpine",
+		Env:        []string{"POSTGRES_USER=root", "POSTGRES_PASSWORD=secret", "POSTGRES_DB=simple_bank"},
+	})
+	if err != nil {
+		return "", err
+	}
+	source := fmt.Sprintf("postgresql://root:secret@%s/simple_bank?sslmode=disable", resource.GetHostPort("5432/tcp"))
+
+	connPool, err := pgxpool.New(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	defer connPool.Close()
+	if err := env.pool.Retry(func() error { return connPool.Ping(ctx) }); err != nil {
+		return "", fmt.Errorf("wait for postgres: %w", err)
+	}
+
+	migrator, err := migration.NewMigrator(connPool)
+	if err != nil {
+		return "", err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return "", fmt.Errorf("migrate postgres: %w", err)
+	}
+	return source, nil
+}
+
+// Redis starts a Redis and returns its address.
+func (env *Env) Redis(ctx context.Context) (string, error) {
+	resource, err := env.run(&dockertest.RunOptions{
+		Repository: "redis",
+		Tag:        "7-alpine",
+	})
+	if err != nil {
+		return "", err
+	}
+	address := resource.GetHostPort("6379/tcp")
+
+	client := redis.NewClient(&redis.Options{Addr: address})
+	defer client.Close()
+	if err := env.pool.Retry(func() error { return client.Ping(ctx).Err() }); err != nil {
+		return "", fmt.Errorf("wait for redis: %w", err)
+	}
+	return address, nil
+}
+
+// Close removes the containers.
+func (env *Env) Close() error {
+	var firstErr error
+	for _, resource := range env.resources {
+		if err := env.pool.Purge(resource); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	env.resources = nil
+	return firstErr
+}
+
+func (env *Env) run(options *dockertest.RunOptions) (*dockertest.Resource, error) {
+	resource, err := env.pool.RunWithOptions(options, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("start %s: %w", options.Repository, err)
+	}
+	env.resources = append(env.resources, resource)
+	if err := resource.Expire(uint(expire.Seconds())); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
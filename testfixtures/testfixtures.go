@@ -0,0 +1,112 @@
+// Package testfixtures builds the random users and accounts of the tests, with options to pin
+// the fields a test cares about:
+//
+//	account := testfixtures.NewAccount(testfixtures.WithOwner(user.Username), testfixtures.WithCurrency(util.USD))
+//
+// It doesn't depend on db so that the tests of db can use it too, the tests turn the fixtures
+// into the db params and models they need.
+package testfixtures
+
+import (
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// User is a random user, with its password both in the clear and hashed.
+type User struct {
+	Username       string
+	Role           string
+	Password       string
+	HashedPassword string
+	FullName       string
+	Email          string
+}
+
+type UserOption func(user *User)
+
+func WithUsername(username string) UserOption {
+	return func(user *User) {
+		user.Username = username
+	}
+}
+
+func WithRole(role string) UserOption {
+	return func(user *User) {
+		user.Role = role
+	}
+}
+
+func WithPassword(password string) UserOption {
+	return func(user *User) {
+		user.Password = password
+	}
+}
+
+// NewUser returns a random depositor, with options applied.
+func NewUser(t testing.TB, options ...UserOption) User {
+	user := User{
+		Username: util.RandomOwnerName(),
+		Role:     util.DepositorRole,
+		Password: util.RandomString(6),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+	}
+	for _, option := range options {
+		option(&user)
+	}
+
+	hashedPassword, err := util.HashedPassword(user.Password)
+	require.NoError(t, err)
+	user.HashedPassword = hashedPassword
+	return user
+}
+
+// Account is a random account.
+type Account struct {
+	ID       int64
+	Owner    string
+	Balance  int64
+	Currency string
+}
+
+type AccountOption func(account *Account)
+
+func WithID(id int64) AccountOption {
+	return func(account *Account) {
+		account.ID = id
+	}
+}
+
+func WithOwner(owner string) AccountOption {
+	return func(account *Account) {
+		account.Owner = owner
+	}
+}
+
+func WithBalance(balance int64) AccountOption {
+	return func(account *Account) {
+		account.Balance = balance
+	}
+}
+
+func WithCurrency(currency string) AccountOption {
+	return func(account *Account) {
+		account.Currency = currency
+	}
+}
+
+// NewAccount returns a random account of a random owner, with options applied.
+func NewAccount(options ...AccountOption) Account {
+	account := Account{
+		ID:       util.RandomInt(1, 100),
+		Owner:    util.RandomOwnerName(),
+		Balance:  util.RandomBalance(),
+		Currency: util.RandomCurrency(),
+	}
+	for _, option := range options {
+		option(&account)
+	}
+	return account
+}
@@ -0,0 +1,29 @@
+package testfixtures
+
+import (
+	"testing"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser(t *testing.T) {
+	user := NewUser(t)
+	require.Equal(t, util.DepositorRole, user.Role)
+	require.NoError(t, util.CheckPassword(user.Password, user.HashedPassword))
+
+	user = NewUser(t, WithUsername("alice"), WithRole(util.AdminRole), WithPassword("secret"))
+	require.Equal(t, "alice", user.Username)
+	require.Equal(t, util.AdminRole, user.Role)
+	require.NoError(t, util.CheckPassword("secret", user.HashedPassword))
+}
+
+func TestNewAccount(t *testing.T) {
+	account := NewAccount()
+	require.NotZero(t, account.ID)
+	require.NotEmpty(t, account.Owner)
+	require.True(t, util.IsSupportedCurrency(account.Currency))
+
+	account = NewAccount(WithID(7), WithOwner("alice"), WithBalance(100), WithCurrency(util.EUR))
+	require.Equal(t, Account{ID: 7, Owner: "alice", Balance: 100, Currency: util.EUR}, account)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/backendmaster/simple_bank/util"
 	"github.com/dgrijalva/jwt-go"
 )
 
@@ -21,8 +22,8 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey: secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+func (maker *JWTMaker) CreateToken(username string, role string, tenant string, locale string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -32,6 +33,34 @@ func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (str
 	return token, payload, err
 }
 
+func (maker *JWTMaker) CreateImpersonationToken(username string, role string, tenant string, locale string, impersonator string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.Impersonator = impersonator
+	// the admin didn't authenticate as the user, so the token never passes a step-up
+	payload.AuthTime = time.Time{}
+	payload.ACR = ""
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	return token, payload, err
+}
+
+func (maker *JWTMaker) CreateTokenAuthenticatedAt(username string, role string, tenant string, locale string, authTime time.Time, acr string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.AuthTime = authTime
+	payload.ACR = acr
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	token, err := jwtToken.SignedString([]byte(maker.secretKey))
+	return token, payload, err
+}
+
 func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
@@ -53,5 +82,9 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	if !ok {
 		return nil, ErrInvalidToken
 	}
+	// the tokens minted before the tenants were introduced are those of the default tenant
+	if payload.Tenant == "" {
+		payload.Tenant = util.DefaultTenant
+	}
 	return payload, nil
 }
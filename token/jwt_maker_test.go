@@ -14,11 +14,14 @@ func TestJWTMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwnerName()
+	role := util.DepositorRole
+	tenant := "acme"
+	locale := "zh-TW"
 	issueAt := time.Now()
 	duration := time.Minute
 	expiredAt := time.Now().Add(duration)
 
-	token, payload, err := jwtMaker.CreateToken(username, duration)
+	token, payload, err := jwtMaker.CreateToken(username, role, tenant, locale, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	payload, err = jwtMaker.VerifyToken(token)
@@ -27,6 +30,9 @@ func TestJWTMaker(t *testing.T) {
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, payload.Username, username)
+	require.Equal(t, payload.Role, role)
+	require.Equal(t, payload.Tenant, tenant)
+	require.Equal(t, payload.Locale, locale)
 	require.WithinDuration(t, payload.IssuedAt, issueAt, time.Second)
 	require.WithinDuration(t, payload.ExpiredAt, expiredAt, time.Second)
 
@@ -36,7 +42,7 @@ func TestExpiredJWTToken(t *testing.T) {
 	jwtMaker, err := NewJWTMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := jwtMaker.CreateToken(util.RandomOwnerName(), -time.Minute)
+	token, payload, err := jwtMaker.CreateToken(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -48,7 +54,7 @@ func TestExpiredJWTToken(t *testing.T) {
 }
 
 func TestInvalidJWTToken(t *testing.T) {
-	payload, err := NewPayload(util.RandomOwnerName(), time.Minute)
+	payload, err := NewPayload(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
@@ -64,3 +70,35 @@ func TestInvalidJWTToken(t *testing.T) {
 	require.Nil(t, payload)
 
 }
+
+func TestJWTImpersonationToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", time.Minute)
+	require.NoError(t, err)
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.False(t, payload.Impersonated())
+
+	token, _, err = maker.CreateImpersonationToken(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.True(t, payload.Impersonated())
+	require.Equal(t, "admin", payload.Impersonator)
+}
+
+func TestJWTTokenAuthenticatedAt(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	authTime := time.Now().Add(-time.Minute)
+	token, _, err := maker.CreateTokenAuthenticatedAt(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", authTime, ACRMultiFactor, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.WithinDuration(t, authTime, payload.AuthTime, time.Second)
+	require.Equal(t, ACRMultiFactor, payload.ACR)
+}
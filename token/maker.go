@@ -3,7 +3,14 @@ package token
 import "time"
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	CreateToken(username string, role string, tenant string, locale string, duration time.Duration) (string, *Payload, error)
+
+	// CreateImpersonationToken creates a token acting as username on behalf of the admin impersonator.
+	CreateImpersonationToken(username string, role string, tenant string, locale string, impersonator string, duration time.Duration) (string, *Payload, error)
+
+	// CreateTokenAuthenticatedAt creates a token whose user proved who they are at authTime with acr,
+	// e.g. a step-up, or the login of the refresh token an access token is renewed with.
+	CreateTokenAuthenticatedAt(username string, role string, tenant string, locale string, authTime time.Time, acr string, duration time.Duration) (string, *Payload, error)
 
 	VerifyToken(token string) (*Payload, error)
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aead/chacha20poly1305"
+	"github.com/backendmaster/simple_bank/util"
 	"github.com/o1egl/paseto"
 )
 
@@ -23,8 +24,8 @@ func NewPasetoMaker(symmericKey string) (Maker, error) {
 	return maker, nil
 }
 
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+func (maker *PasetoMaker) CreateToken(username string, role string, tenant string, locale string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -33,6 +34,32 @@ func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (
 	return token, payload, err
 }
 
+func (maker *PasetoMaker) CreateImpersonationToken(username string, role string, tenant string, locale string, impersonator string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.Impersonator = impersonator
+	// the admin didn't authenticate as the user, so the token never passes a step-up
+	payload.AuthTime = time.Time{}
+	payload.ACR = ""
+
+	token, err := maker.paseto.Encrypt(maker.symmericKey, payload, nil)
+	return token, payload, err
+}
+
+func (maker *PasetoMaker) CreateTokenAuthenticatedAt(username string, role string, tenant string, locale string, authTime time.Time, acr string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, locale, duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.AuthTime = authTime
+	payload.ACR = acr
+
+	token, err := maker.paseto.Encrypt(maker.symmericKey, payload, nil)
+	return token, payload, err
+}
+
 func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
 
@@ -45,6 +72,10 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the tokens minted before the tenants were introduced are those of the default tenant
+	if payload.Tenant == "" {
+		payload.Tenant = util.DefaultTenant
+	}
 
 	return payload, nil
 }
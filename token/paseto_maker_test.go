@@ -13,11 +13,14 @@ func TestPasetoMaker(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwnerName()
+	role := util.DepositorRole
+	tenant := "acme"
+	locale := "zh-TW"
 	issueAt := time.Now()
 	duration := time.Minute
 	expiredAt := time.Now().Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, role, tenant, locale, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -27,16 +30,34 @@ func TestPasetoMaker(t *testing.T) {
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, payload.Username, username)
+	require.Equal(t, payload.Role, role)
+	require.Equal(t, payload.Tenant, tenant)
+	require.Equal(t, payload.Locale, locale)
 	require.WithinDuration(t, payload.IssuedAt, issueAt, time.Second)
 	require.WithinDuration(t, payload.ExpiredAt, expiredAt, time.Second)
+	require.WithinDuration(t, payload.AuthTime, issueAt, time.Second)
+	require.Equal(t, ACRPassword, payload.ACR)
+	require.True(t, payload.AuthenticatedSince(ACRPassword, time.Minute))
+	require.False(t, payload.AuthenticatedSince(ACRMultiFactor, time.Minute))
+}
+
+func TestPasetoTokenWithoutTenant(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwnerName(), util.DepositorRole, "", "", time.Minute)
+	require.NoError(t, err)
 
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, util.DefaultTenant, payload.Tenant)
 }
 
 func TestExpiredPasetoToken(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomOwnerName(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -46,3 +67,37 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoImpersonationToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwnerName()
+	token, created, err := maker.CreateImpersonationToken(username, util.DepositorRole, util.DefaultTenant, "", "admin", time.Minute)
+	require.NoError(t, err)
+	require.True(t, created.Impersonated())
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, "admin", payload.Impersonator)
+	require.False(t, payload.AuthenticatedSince(ACRPassword, time.Hour))
+}
+
+func TestPasetoTokenAuthenticatedAt(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	authTime := time.Now().Add(-time.Minute)
+	token, _, err := maker.CreateTokenAuthenticatedAt(util.RandomOwnerName(), util.DepositorRole, util.DefaultTenant, "", authTime, ACRMultiFactor, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.WithinDuration(t, authTime, payload.AuthTime, time.Second)
+	require.Equal(t, ACRMultiFactor, payload.ACR)
+	require.True(t, payload.AuthenticatedSince(ACRMultiFactor, 5*time.Minute))
+	require.True(t, payload.AuthenticatedSince(ACRPassword, 5*time.Minute))
+	require.False(t, payload.AuthenticatedSince(ACRMultiFactor, 30*time.Second))
+}
@@ -13,26 +13,71 @@ var (
 )
 
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	// Tenant is that of the user, whose accounts and transfers are the only ones the token reaches.
+	Tenant string `json:"tenant"`
+	// Locale is the one the user chose in their preferences, which the api answers in when the
+	// request doesn't name one.
+	Locale    string    `json:"locale,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_t"`
+	// Impersonator is the admin acting as Username with the token, empty for the tokens of a login.
+	Impersonator string `json:"impersonator,omitempty"`
+	// AuthTime is when the user last proved who they are, by logging in or stepping up, which a
+	// renewed token keeps. It is zero for the tokens minted before it and for impersonations.
+	AuthTime time.Time `json:"auth_time"`
+	// ACR is how the user proved who they are at AuthTime, one of the ACR constants.
+	ACR string `json:"acr,omitempty"`
 }
 
-func NewPayload(usrname string, duration time.Duration) (*Payload, error) {
+// The authentication context classes of a token, from the weakest.
+const (
+	// ACRPassword is an authentication with the password of the user.
+	ACRPassword = "pwd"
+	// ACRMultiFactor is an authentication with the password of the user and a one-time code
+	// emailed to them.
+	ACRMultiFactor = "mfa"
+)
+
+// acrLevels ranks the authentication context classes, an unknown one being the weakest.
+var acrLevels = map[string]int{ACRPassword: 1, ACRMultiFactor: 2}
+
+func NewPayload(usrname string, role string, tenant string, locale string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	payload := Payload{
 		ID:        tokenID,
 		Username:  usrname,
-		IssuedAt:  time.Now(),
-		ExpiredAt: time.Now().Add(duration),
+		Role:      role,
+		Tenant:    tenant,
+		Locale:    locale,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+		AuthTime:  now,
+		ACR:       ACRPassword,
 	}
 	return &payload, nil
 }
 
+// AuthenticatedSince tells whether the user of the token proved who they are with at least acr
+// within maxAge.
+func (payload *Payload) AuthenticatedSince(acr string, maxAge time.Duration) bool {
+	if payload.AuthTime.IsZero() || time.Since(payload.AuthTime) > maxAge {
+		return false
+	}
+	return acrLevels[payload.ACR] >= acrLevels[acr]
+}
+
+// Impersonated tells whether the token was minted for an admin acting as the user.
+func (payload *Payload) Impersonated() bool {
+	return payload.Impersonator != ""
+}
+
 func (payload *Payload) Valid() error {
 	if time.Now().After(payload.ExpiredAt) {
 		return ErrExpiredToken
@@ -0,0 +1,11 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// GrpcTracing starts a server span for every unary call, continuing the trace found in the incoming metadata.
+func GrpcTracing() grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor()
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/backendmaster/simple_bank/tracing"
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rec *statusRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *statusRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// HttpTracing starts a server span for every request, continuing the trace of the caller
+// when the request carries a traceparent header. Handlers that know the matched route
+// can rename the span with SetRoute.
+func HttpTracing(handler http.Handler) http.Handler {
+	tracer := otel.Tracer(instrumentationName)
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		ctx, span := tracer.Start(ctx, req.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", "", req)...),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{
+			ResponseWriter: res,
+			statusCode:     http.StatusOK,
+		}
+		handler.ServeHTTP(rec, req.WithContext(ctx))
+		endHttpSpan(span, rec.statusCode)
+	})
+}
+
+// SetRoute names the span of the request after the route pattern that matched it.
+func SetRoute(req *http.Request, route string) {
+	span := trace.SpanFromContext(req.Context())
+	span.SetName(req.Method + " " + route)
+	span.SetAttributes(semconv.HTTPRouteKey.String(route))
+}
+
+// GinTracing is the gin version of HttpTracing, naming spans after the gin route.
+func GinTracing() gin.HandlerFunc {
+	tracer := otel.Tracer(instrumentationName)
+
+	return func(ctx *gin.Context) {
+		req := ctx.Request
+		spanName := req.Method
+		if route := ctx.FullPath(); route != "" {
+			spanName = req.Method + " " + route
+		}
+
+		spanCtx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		spanCtx, span := tracer.Start(spanCtx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", ctx.FullPath(), req)...),
+		)
+		defer span.End()
+
+		ctx.Request = req.WithContext(spanCtx)
+		ctx.Next()
+		endHttpSpan(span, ctx.Writer.Status())
+	}
+}
+
+func endHttpSpan(span trace.Span, statusCode int) {
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(statusCode)...)
+	code, description := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(statusCode, trace.SpanKindServer)
+	if code == codes.Error {
+		span.SetStatus(code, description)
+	}
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+func newSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestGinTracing(t *testing.T) {
+	spans := newSpanRecorder(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(GinTracing())
+	router.GET("/accounts/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	request.Header.Set("traceparent", traceparent)
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "GET /accounts/:id", ended[0].Name())
+	require.Equal(t, traceID, ended[0].SpanContext().TraceID().String())
+	require.Equal(t, codes.Unset, ended[0].Status().Code)
+}
+
+func TestHttpTracing(t *testing.T) {
+	spans := newSpanRecorder(t)
+
+	handler := HttpTracing(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		SetRoute(req, "/v1/get_user/{username}")
+		res.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/get_user/alice", nil)
+	require.NoError(t, err)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "GET /v1/get_user/{username}", ended[0].Name())
+	require.False(t, ended[0].Parent().IsValid())
+	require.Equal(t, codes.Error, ended[0].Status().Code)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/backendmaster/simple_bank/domain"
 	"github.com/backendmaster/simple_bank/token"
 	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/worker"
 	"github.com/pkg/errors"
 )
 
@@ -52,8 +53,20 @@ func (u *usersTableUseCase) CreateUser(ctx context.Context, req domain.CreateUse
 		Email:          req.Email,
 	}
 
+	verifyEmailTask, err := worker.NewSendVerifyEmailTask(&worker.PayloadSendVerifyEmail{
+		Username: req.Username,
+	})
+	if err != nil {
+		return nil, domain.ErrorInternalServerError
+	}
+
 	// repository.Create(user)
-	user, err := u.usersrepo.Create(ctx, newUser)
+	user, err := u.usersrepo.Create(ctx, newUser, domain.OutboxTask{
+		TaskType: verifyEmailTask.TaskType,
+		Payload:  verifyEmailTask.Payload,
+		Queue:    verifyEmailTask.Queue,
+		MaxRetry: verifyEmailTask.MaxRetry,
+	})
 	if err != nil {
 		if err == domain.ErrorInternalServerError || err == domain.ErrorUniqueViolation {
 			return nil, err
@@ -68,8 +81,8 @@ func (u *usersTableUseCase) CreateUser(ctx context.Context, req domain.CreateUse
 // 	return "HI"
 // }
 
-func (u *usersTableUseCase) CreateToken(username string, duration time.Duration) (string, *token.Payload, error) {
-	token, accessPayload, err := u.tokenMaker.CreateToken(username, duration)
+func (u *usersTableUseCase) CreateToken(username string, role string, tenant string, duration time.Duration) (string, *token.Payload, error) {
+	token, accessPayload, err := u.tokenMaker.CreateToken(username, role, tenant, "", duration)
 	if err != nil {
 		return "", nil, err
 	}
@@ -88,12 +101,12 @@ func (u *usersTableUseCase) LoginUser(ctx context.Context, req domain.LoginUserR
 		return nil, errors.Wrapf(domain.ErrorPermissionNowAllowed, "Mismatched Password")
 	}
 	// return loginUserResponse
-	accessToken, accessPayload, err := u.CreateToken(user.Username, u.accessTokenDuration)
+	accessToken, accessPayload, err := u.CreateToken(user.Username, user.Role, user.TenantID, u.accessTokenDuration)
 	if err != nil {
 		return nil, errors.Wrapf(domain.ErrorInternalServerError, "Create AccessToken Failed")
 	}
 
-	refreshToken, refreshPayload, err := u.CreateToken(user.Username, u.refreshTokenDuration)
+	refreshToken, refreshPayload, err := u.CreateToken(user.Username, user.Role, user.TenantID, u.refreshTokenDuration)
 	if err != nil {
 		return nil, errors.Wrapf(domain.ErrorInternalServerError, "Create RefreshToken Failed")
 	}
@@ -0,0 +1,186 @@
+// Package userimport reads the files of the users an admin imports from a legacy system, a csv
+// file with a header or an ndjson file, a user by row:
+//
+//	username,full_name,email,password,hashed_password,tenant
+//	alice,Alice Liddell,alice@example.com,secret123,,
+//	bob,Bob Builder,bob@example.com,,$2a$10$...,acme
+//
+// A row has either the password of the user, which the bank hashes, or its bcrypt hash from the
+// legacy system, so that the users log in with the password they already have.
+package userimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/backendmaster/simple_bank/val"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// The formats of the files.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrInvalidHeader is returned by Read for a csv file whose header misses a column or has an
+	// unknown one, which fails the whole file rather than each of its rows.
+	ErrInvalidHeader = errors.New("invalid csv header")
+)
+
+var tenantID = regexp.MustCompile(`^[a-z0-9]{1,32}$`)
+
+// Row is a user of the file.
+type Row struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	// Password is the password of the user in clear, HashedPassword its bcrypt hash.
+	Password       string `json:"password"`
+	HashedPassword string `json:"hashed_password"`
+	// Tenant is the bank the user joins, the default one if empty.
+	Tenant string `json:"tenant"`
+}
+
+// Validate checks the row against the rules of a signup.
+func (row Row) Validate() error {
+	if err := val.ValidateUserName(row.Username); err != nil {
+		return fmt.Errorf("invalid username: %w", err)
+	}
+	if err := val.ValidateFullName(row.FullName); err != nil {
+		return fmt.Errorf("invalid full_name: %w", err)
+	}
+	if err := val.ValidateEmail(row.Email); err != nil {
+		return fmt.Errorf("invalid email: %w", err)
+	}
+	if row.Tenant != "" && !tenantID.MatchString(row.Tenant) {
+		return errors.New("invalid tenant: must be up to 32 lowercase letters or digits")
+	}
+
+	switch {
+	case row.Password != "" && row.HashedPassword != "":
+		return errors.New("a row has either a password or a hashed_password, not both")
+	case row.Password != "":
+		if err := val.ValidatePassword(row.Password); err != nil {
+			return fmt.Errorf("invalid password: %w", err)
+		}
+	case row.HashedPassword != "":
+		if _, err := bcrypt.Cost([]byte(row.HashedPassword)); err != nil {
+			return errors.New("invalid hashed_password: must be a bcrypt hash")
+		}
+	default:
+		return errors.New("a row needs a password or a hashed_password")
+	}
+	return nil
+}
+
+// Hash returns the bcrypt hash of the password of a valid row, hashing it unless the row has it.
+func (row Row) Hash() (string, error) {
+	if row.HashedPassword != "" {
+		return row.HashedPassword, nil
+	}
+	return util.HashedPassword(row.Password)
+}
+
+// Read calls fn with each row of content, a file in format, and its line. A row that can't be
+// parsed is passed with the error instead, so that it doesn't stop the rows after it. Read stops
+// at the first error of fn.
+func Read(format string, content []byte, fn func(line int64, row Row, err error) error) error {
+	switch format {
+	case FormatCSV:
+		return readCSV(content, fn)
+	case FormatNDJSON:
+		return readNDJSON(content, fn)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+var csvColumns = map[string]func(row *Row) *string{
+	"username":        func(row *Row) *string { return &row.Username },
+	"full_name":       func(row *Row) *string { return &row.FullName },
+	"email":           func(row *Row) *string { return &row.Email },
+	"password":        func(row *Row) *string { return &row.Password },
+	"hashed_password": func(row *Row) *string { return &row.HashedPassword },
+	"tenant":          func(row *Row) *string { return &row.Tenant },
+}
+
+func readCSV(content []byte, fn func(line int64, row Row, err error) error) error {
+	reader := csv.NewReader(bytes.NewReader(content))
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidHeader, err)
+	}
+	seen := map[string]bool{}
+	for _, column := range header {
+		if csvColumns[column] == nil || seen[column] {
+			return fmt.Errorf("%w: unknown or repeated column %q", ErrInvalidHeader, column)
+		}
+		seen[column] = true
+	}
+	for _, column := range []string{"username", "full_name", "email"} {
+		if !seen[column] {
+			return fmt.Errorf("%w: missing column %q", ErrInvalidHeader, column)
+		}
+	}
+	if !seen["password"] && !seen["hashed_password"] {
+		return fmt.Errorf("%w: missing column password or hashed_password", ErrInvalidHeader)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := fn(int64(parseErr.StartLine), Row{}, parseErr.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		var row Row
+		for i, column := range header {
+			*csvColumns[column](&row) = record[i]
+		}
+		if err := fn(int64(line), row, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func readNDJSON(content []byte, fn func(line int64, row Row, err error) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	// the lines are short, but don't let a long one fail the whole file
+	scanner.Buffer(nil, 1<<20)
+	var line int64
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var row Row
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&row)
+		if err := fn(line, row, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
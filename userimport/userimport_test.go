@@ -0,0 +1,88 @@
+package userimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type readRow struct {
+	line int64
+	row  Row
+	err  bool
+}
+
+func readAll(format string, content string) ([]readRow, error) {
+	var rows []readRow
+	err := Read(format, []byte(content), func(line int64, row Row, err error) error {
+		rows = append(rows, readRow{line: line, row: row, err: err != nil})
+		return nil
+	})
+	return rows, err
+}
+
+func TestReadCSV(t *testing.T) {
+	rows, err := readAll(FormatCSV, "email,username,full_name,password\n"+
+		"alice@example.com,alice,Alice Liddell,secret123\n"+
+		"bob@example.com,bob,\"Bob\nBuilder\",secret123\n"+
+		"carol@example.com,carol\n"+
+		"dave@example.com,dave,Dave,secret123\n")
+	require.NoError(t, err)
+	require.Equal(t, []readRow{
+		{line: 2, row: Row{Username: "alice", FullName: "Alice Liddell", Email: "alice@example.com", Password: "secret123"}},
+		{line: 3, row: Row{Username: "bob", FullName: "Bob\nBuilder", Email: "bob@example.com", Password: "secret123"}},
+		{line: 5, err: true},
+		{line: 6, row: Row{Username: "dave", FullName: "Dave", Email: "dave@example.com", Password: "secret123"}},
+	}, rows)
+
+	for _, header := range []string{"", "username,full_name,email", "username,full_name,email,password,password", "username,name,email,password"} {
+		_, err := readAll(FormatCSV, header+"\n")
+		require.ErrorIs(t, err, ErrInvalidHeader, header)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	rows, err := readAll(FormatNDJSON, `{"username": "alice", "full_name": "Alice Liddell", "email": "alice@example.com", "password": "secret123"}
+
+{"username": "bob", "nickname": "bobby"}
+not json
+`)
+	require.NoError(t, err)
+	require.Equal(t, []readRow{
+		{line: 1, row: Row{Username: "alice", FullName: "Alice Liddell", Email: "alice@example.com", Password: "secret123"}},
+		{line: 3, row: Row{Username: "bob"}, err: true},
+		{line: 4, err: true},
+	}, rows)
+
+	_, err = readAll("xlsx", "")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestValidate(t *testing.T) {
+	valid := Row{Username: "alice", FullName: "Alice Liddell", Email: "alice@example.com", Password: "secret123"}
+	require.NoError(t, valid.Validate())
+
+	hashed := valid
+	hashed.Password = ""
+	hashed.HashedPassword = "$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z3x7T1N1m0Ub3R3lLZ6j2V8e"
+	hashed.Tenant = "acme"
+	require.NoError(t, hashed.Validate())
+	hash, err := hashed.Hash()
+	require.NoError(t, err)
+	require.Equal(t, hashed.HashedPassword, hash)
+
+	for name, change := range map[string]func(row *Row){
+		"InvalidUsername":     func(row *Row) { row.Username = "Alice!" },
+		"InvalidEmail":        func(row *Row) { row.Email = "alice" },
+		"InvalidTenant":       func(row *Row) { row.Tenant = "Acme Bank" },
+		"ShortPassword":       func(row *Row) { row.Password = "abc" },
+		"NotBcrypt":           func(row *Row) { row.Password, row.HashedPassword = "", "md5:5f4dcc3b5aa765d61d8327deb882cf99" },
+		"BothPasswords":       func(row *Row) { row.HashedPassword = hashed.HashedPassword },
+		"NoPassword":          func(row *Row) { row.Password = "" },
+		"InvalidFullNameChar": func(row *Row) { row.FullName = "Alice 1" },
+	} {
+		row := valid
+		change(&row)
+		require.Error(t, row.Validate(), name)
+	}
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AccountNumberPattern is the format of the account numbers: SB, two check digits, then 12
+// digits, e.g. SB06123456789012. The database generates them, see generate_account_number.
+const AccountNumberPattern = "^SB[0-9]{14}$"
+
+var isAccountNumberFormat = regexp.MustCompile(AccountNumberPattern).MatchString
+
+// NormalizeAccountNumber turns an account number as people write it, e.g. sb06 1234 5678 9012,
+// into the one stored.
+func NormalizeAccountNumber(number string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(number), ""))
+}
+
+// IsValidAccountNumber tells whether a normalized account number has the format and the check
+// digits of one, catching most typos before it is looked up.
+func IsValidAccountNumber(number string) bool {
+	if !isAccountNumberFormat(number) {
+		return false
+	}
+	// like for an IBAN, the first 4 characters move to the end, a letter becomes its position in
+	// the alphabet plus 9, and the remainder by 97 of the resulting number must be 1
+	rearranged := number[4:] + "2811" + number[2:4]
+	remainder := 0
+	for _, digit := range rearranged {
+		remainder = (remainder*10 + int(digit-'0')) % 97
+	}
+	return remainder == 1
+}
+
+// FormatAccountNumber groups an account number by 4 characters, the way it is printed.
+func FormatAccountNumber(number string) string {
+	var formatted strings.Builder
+	for i, r := range number {
+		if i > 0 && i%4 == 0 {
+			formatted.WriteByte(' ')
+		}
+		formatted.WriteRune(r)
+	}
+	return formatted.String()
+}
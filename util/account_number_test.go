@@ -0,0 +1,36 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidAccountNumber(t *testing.T) {
+	testCases := []struct {
+		number string
+		valid  bool
+	}{
+		{"SB06123456789012", true},
+		{"SB77000000000001", true},
+		// a digit mistyped or two digits swapped
+		{"SB06123456789013", false},
+		{"SB06213456789012", false},
+		{"SB60123456789012", false},
+		{"SB0612345678901", false},
+		{"GB06123456789012", false},
+		{"sb06123456789012", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.valid, IsValidAccountNumber(tc.number), tc.number)
+	}
+}
+
+func TestNormalizeAccountNumber(t *testing.T) {
+	number := NormalizeAccountNumber(" sb06 1234\t5678 9012 ")
+	require.Equal(t, "SB06123456789012", number)
+	require.True(t, IsValidAccountNumber(number))
+	require.Equal(t, "SB06 1234 5678 9012", FormatAccountNumber(number))
+}
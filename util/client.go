@@ -0,0 +1,95 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+)
+
+// trustedProxies are the networks of the proxies in front of the servers, set by SetTrustedProxies.
+// Only the X-Forwarded-For entries they appended are trusted.
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+// SetTrustedProxies trusts the proxies in the given networks, in CIDR notation or single addresses,
+// to forward the ip of the client. Without any, X-Forwarded-For is ignored.
+func SetTrustedProxies(entries []string) error {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	trustedProxies.Store(&networks)
+	return nil
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	networks := trustedProxies.Load()
+	if networks == nil {
+		return false
+	}
+	for _, network := range *networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the ip of the client that sent the request. Every proxy on the way appends the
+// address it got the request from to X-Forwarded-For, and the client can send any entries before
+// them, so the chain is read from the right: the client is the first address that isn't a trusted
+// proxy. A request from an untrusted remoteAddr came straight from the client, whatever its header.
+func ClientIP(forwardedFor []string, remoteAddr string) string {
+	clientIP := stripPort(remoteAddr)
+	if ip := net.ParseIP(clientIP); ip == nil || !isTrustedProxy(ip) {
+		return clientIP
+	}
+
+	var chain []string
+	for _, value := range forwardedFor {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				chain = append(chain, entry)
+			}
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := net.ParseIP(stripPort(chain[i]))
+		if ip == nil {
+			// a garbled entry wasn't appended by a trusted proxy: keep the last address it handed over
+			return clientIP
+		}
+		clientIP = ip.String()
+		if !isTrustedProxy(ip) {
+			return clientIP
+		}
+	}
+	return clientIP
+}
+
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
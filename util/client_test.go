@@ -0,0 +1,98 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	require.NoError(t, SetTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"}))
+	t.Cleanup(func() { SetTrustedProxies(nil) })
+
+	testCases := []struct {
+		name         string
+		forwardedFor []string
+		remoteAddr   string
+		clientIP     string
+	}{
+		{
+			name:       "RemoteAddr",
+			remoteAddr: "10.0.0.1:54321",
+			clientIP:   "10.0.0.1",
+		},
+		{
+			name:       "RemoteAddrIPv6",
+			remoteAddr: "[::1]:54321",
+			clientIP:   "::1",
+		},
+		{
+			name:       "RemoteAddrWithoutPort",
+			remoteAddr: "10.0.0.1",
+			clientIP:   "10.0.0.1",
+		},
+		{
+			name:         "SingleProxy",
+			forwardedFor: []string{"203.0.113.7"},
+			remoteAddr:   "10.0.0.1:54321",
+			clientIP:     "203.0.113.7",
+		},
+		{
+			name:         "IPv6Proxy",
+			forwardedFor: []string{"203.0.113.7"},
+			remoteAddr:   "[2001:db8::1]:54321",
+			clientIP:     "203.0.113.7",
+		},
+		{
+			name:         "ProxyChain",
+			forwardedFor: []string{"198.51.100.2, 203.0.113.7", "10.0.0.2"},
+			remoteAddr:   "10.0.0.1:54321",
+			clientIP:     "203.0.113.7",
+		},
+		{
+			name:         "UntrustedRemoteAddr",
+			forwardedFor: []string{"203.0.113.7"},
+			remoteAddr:   "198.51.100.2:54321",
+			clientIP:     "198.51.100.2",
+		},
+		{
+			name:         "OnlyTrustedProxies",
+			forwardedFor: []string{"10.0.0.3, 10.0.0.2"},
+			remoteAddr:   "10.0.0.1:54321",
+			clientIP:     "10.0.0.3",
+		},
+		{
+			name:         "GarbledEntry",
+			forwardedFor: []string{"203.0.113.7, not-an-ip"},
+			remoteAddr:   "10.0.0.1:54321",
+			clientIP:     "10.0.0.1",
+		},
+		{
+			name:         "EmptyHeader",
+			forwardedFor: []string{" "},
+			remoteAddr:   "10.0.0.1:54321",
+			clientIP:     "10.0.0.1",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.clientIP, ClientIP(tc.forwardedFor, tc.remoteAddr))
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	require.NoError(t, SetTrustedProxies(nil))
+	require.Equal(t, "10.0.0.1", ClientIP([]string{"203.0.113.7"}, "10.0.0.1:54321"))
+}
+
+func TestSetTrustedProxies(t *testing.T) {
+	t.Cleanup(func() { SetTrustedProxies(nil) })
+
+	require.NoError(t, SetTrustedProxies([]string{" 10.0.0.0/8", "", "::1"}))
+	require.Error(t, SetTrustedProxies([]string{"10.0.0.0/33"}))
+	require.Error(t, SetTrustedProxies([]string{"proxy"}))
+}
@@ -7,13 +7,116 @@ import (
 )
 
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DBDriver                string        `mapstructure:"DB_DRIVER"`
+	DBSource                string        `mapstructure:"DB_SOURCE"`
+	DBMaxConns              int32         `mapstructure:"DB_MAX_CONNS"`
+	DBMinConns              int32         `mapstructure:"DB_MIN_CONNS"`
+	DBMaxConnIdleTime       time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DBMaxConnLifetime       time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DBMaxOpenConns          int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns          int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBStatementTimeout      time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	DBSlowQueryThreshold    time.Duration `mapstructure:"DB_SLOW_QUERY_THRESHOLD"`
+	DBBreakerThreshold      int           `mapstructure:"DB_BREAKER_THRESHOLD"`
+	DBBreakerOpenTimeout    time.Duration `mapstructure:"DB_BREAKER_OPEN_TIMEOUT"`
+	DBReplicaSource         string        `mapstructure:"DB_REPLICA_SOURCE"`
+	DBReplicaMaxLag         time.Duration `mapstructure:"DB_REPLICA_MAX_LAG"`
+	DBBalanceLock           string        `mapstructure:"DB_BALANCE_LOCK"`
+	MigrateOnStart          bool          `mapstructure:"MIGRATE_ON_START"`
+	HTTPServerAddress       string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GRPCServerAddress       string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	AllowedOrigins          []string      `mapstructure:"ALLOWED_ORIGINS"`
+	AllowedMethods          []string      `mapstructure:"ALLOWED_METHODS"`
+	AllowedHeaders          []string      `mapstructure:"ALLOWED_HEADERS"`
+	AllowCredentials        bool          `mapstructure:"ALLOW_CREDENTIALS"`
+	TrustedProxies          []string      `mapstructure:"TRUSTED_PROXIES"`
+	ShutdownTimeout         time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ReadOnly                bool          `mapstructure:"READ_ONLY"`
+	TransfersBlocked        bool          `mapstructure:"TRANSFERS_BLOCKED"`
+	MaintenanceInterval     time.Duration `mapstructure:"MAINTENANCE_REFRESH_INTERVAL"`
+	AuditLog                bool          `mapstructure:"AUDIT_LOG"`
+	RedisAddress            string        `mapstructure:"REDIS_ADDRESS"`
+	AccountCacheTTL         time.Duration `mapstructure:"ACCOUNT_CACHE_TTL"`
+	OutboxInterval          time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	CurrencyRefreshInterval time.Duration `mapstructure:"CURRENCY_REFRESH_INTERVAL"`
+	TaskRetryPolicies       []string      `mapstructure:"TASK_RETRY_POLICIES"`
+	WorkerConcurrency       []string      `mapstructure:"WORKER_CONCURRENCY"`
+	LedgerVerifySchedule    string        `mapstructure:"LEDGER_VERIFY_SCHEDULE"`
+	AuditVerifySchedule     string        `mapstructure:"AUDIT_VERIFY_SCHEDULE"`
+	PartitionSchedule       string        `mapstructure:"PARTITION_SCHEDULE"`
+	PartitionMonthsAhead    int           `mapstructure:"PARTITION_MONTHS_AHEAD"`
+	ArchiveSchedule         string        `mapstructure:"ARCHIVE_SCHEDULE"`
+	EntryRetentionYears     int           `mapstructure:"ENTRY_RETENTION_YEARS"`
+	ExportCleanupSchedule   string        `mapstructure:"EXPORT_CLEANUP_SCHEDULE"`
+	SettlementSchedule      string        `mapstructure:"SETTLEMENT_SCHEDULE"`
+	SettlementDelay         time.Duration `mapstructure:"SETTLEMENT_DELAY"`
+	HoldExpirySchedule      string        `mapstructure:"HOLD_EXPIRY_SCHEDULE"`
+	LoanRepaymentSchedule   string        `mapstructure:"LOAN_REPAYMENT_SCHEDULE"`
+	CashbackRateBps         int64         `mapstructure:"CASHBACK_RATE_BPS"`
+	CashbackMinAmount       int64         `mapstructure:"CASHBACK_MIN_AMOUNT"`
+	CashbackMaxPoints       int64         `mapstructure:"CASHBACK_MAX_POINTS"`
+	ReferralSchedule        string        `mapstructure:"REFERRAL_SCHEDULE"`
+	ReferralBonus           int64         `mapstructure:"REFERRAL_BONUS"`
+	ReferralMinAmount       int64         `mapstructure:"REFERRAL_MIN_AMOUNT"`
+	ReferralWindow          time.Duration `mapstructure:"REFERRAL_WINDOW"`
+	ReferralMaxPerReferrer  int64         `mapstructure:"REFERRAL_MAX_PER_REFERRER"`
+	FXProvider              string        `mapstructure:"FX_PROVIDER"`
+	FXAppID                 string        `mapstructure:"FX_APP_ID"`
+	FXRefreshSchedule       string        `mapstructure:"FX_REFRESH_SCHEDULE"`
+	FXRateMaxAge            time.Duration `mapstructure:"FX_RATE_MAX_AGE"`
+	EmailDriver             string        `mapstructure:"EMAIL_DRIVER"`
+	EmailSenderName         string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress      string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailVerifyURL          string        `mapstructure:"EMAIL_VERIFY_URL"`
+	LoginReportURL          string        `mapstructure:"LOGIN_REPORT_URL"`
+	SMTPAddress             string        `mapstructure:"SMTP_ADDRESS"`
+	SMTPUsername            string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword            string        `mapstructure:"SMTP_PASSWORD"`
+	SESRegion               string        `mapstructure:"SES_REGION"`
+	SESAccessKeyID          string        `mapstructure:"SES_ACCESS_KEY_ID"`
+	SESSecretAccessKey      string        `mapstructure:"SES_SECRET_ACCESS_KEY"`
+	SendGridAPIKey          string        `mapstructure:"SENDGRID_API_KEY"`
+	SMSDriver               string        `mapstructure:"SMS_DRIVER"`
+	TwilioAccountSID        string        `mapstructure:"TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken         string        `mapstructure:"TWILIO_AUTH_TOKEN"`
+	TwilioFromNumber        string        `mapstructure:"TWILIO_FROM_NUMBER"`
+	PushDriver              string        `mapstructure:"PUSH_DRIVER"`
+	FCMServerKey            string        `mapstructure:"FCM_SERVER_KEY"`
+	EventBroker             string        `mapstructure:"EVENT_BROKER"`
+	EventTopic              string        `mapstructure:"EVENT_TOPIC"`
+	EventInterval           time.Duration `mapstructure:"EVENT_INTERVAL"`
+	KafkaBrokers            []string      `mapstructure:"KAFKA_BROKERS"`
+	NATSURL                 string        `mapstructure:"NATS_URL"`
+	RateLimit               int           `mapstructure:"RATE_LIMIT"`
+	RateLimitWindow         time.Duration `mapstructure:"RATE_LIMIT_WINDOW"`
+	TransferRateLimit       int           `mapstructure:"TRANSFER_RATE_LIMIT"`
+	TransferRateLimitWindow time.Duration `mapstructure:"TRANSFER_RATE_LIMIT_WINDOW"`
+	RequestTimeout          time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	RouteTimeouts           []string      `mapstructure:"ROUTE_TIMEOUTS"`
+	UnversionedRoutesSunset string        `mapstructure:"UNVERSIONED_ROUTES_SUNSET"`
+	MaxBodySize             int64         `mapstructure:"MAX_BODY_SIZE"`
+	CompressionMinSize      int           `mapstructure:"COMPRESSION_MIN_SIZE"`
+	OTLPEndpoint            string        `mapstructure:"OTLP_ENDPOINT"`
+	RedactedFields          []string      `mapstructure:"REDACTED_FIELDS"`
+	LogLevel                string        `mapstructure:"LOG_LEVEL"`
+	LogFormat               string        `mapstructure:"LOG_FORMAT"`
+	LogSampleRate           uint32        `mapstructure:"LOG_SAMPLE_RATE"`
+	LogErrorSampleRate      uint32        `mapstructure:"LOG_ERROR_SAMPLE_RATE"`
+	TokenSymmetricKey       string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	ReceiptKey              string        `mapstructure:"RECEIPT_KEY"`
+	CardKey                 string        `mapstructure:"CARD_KEY"`
+	CardNetworkKey          string        `mapstructure:"CARD_NETWORK_KEY"`
+	PaymentRequestKey       string        `mapstructure:"PAYMENT_REQUEST_KEY"`
+	AccessTokenDuration     time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration    time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	ImpersonationTTL        time.Duration `mapstructure:"IMPERSONATION_TTL"`
+	StepUpMaxAge            time.Duration `mapstructure:"STEP_UP_MAX_AGE"`
+	StepUpTransferAmounts   []string      `mapstructure:"STEP_UP_TRANSFER_AMOUNTS"`
+	FraudVelocityLimit      int           `mapstructure:"FRAUD_VELOCITY_LIMIT"`
+	FraudVelocityWindow     time.Duration `mapstructure:"FRAUD_VELOCITY_WINDOW"`
+	FraudLargeAmount        int64         `mapstructure:"FRAUD_LARGE_AMOUNT"`
+	FraudQuietHours         string        `mapstructure:"FRAUD_QUIET_HOURS"`
+	FraudCountryHeader      string        `mapstructure:"FRAUD_COUNTRY_HEADER"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
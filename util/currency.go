@@ -1,15 +1,127 @@
 package util
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The currencies the bank has always offered, which the registry starts with.
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
+// MaxMajorAmount is the most units of a currency an amount can be, whatever its minor units, so
+// that the amounts stay comparable across currencies and far from overflowing when converted.
+const MaxMajorAmount = 1_000_000_000_000
+
+// Currency is a currency of the registry. Its amounts are counted in its minor units, MinorUnits
+// being its number of decimal places: 2 for the cents of USD, 0 for JPY, 3 for the fils of BHD.
+type Currency struct {
+	Code       string
+	MinorUnits int
+	Enabled    bool
+}
+
+// MaxAmount is the most an amount in the currency can be, in its minor units.
+func (currency Currency) MaxAmount() int64 {
+	max := int64(MaxMajorAmount)
+	for i := 0; i < currency.MinorUnits; i++ {
+		max *= 10
+	}
+	return max
+}
+
+// currencies is the registry, by code. It holds the currencies of the currencies table once
+// SetCurrencies loads them, and the ones the bank has always offered until then.
+var currencies atomic.Pointer[map[string]Currency]
+
+// currenciesMu serializes the changes of the registry, which readers never wait for.
+var currenciesMu sync.Mutex
+
+func init() {
+	SetCurrencies([]Currency{
+		{Code: USD, MinorUnits: 2, Enabled: true},
+		{Code: EUR, MinorUnits: 2, Enabled: true},
+		{Code: CAD, MinorUnits: 2, Enabled: true},
+	})
+}
+
+// SetCurrencies replaces the currencies of the registry.
+func SetCurrencies(list []Currency) {
+	registry := make(map[string]Currency, len(list))
+	for _, currency := range list {
+		registry[currency.Code] = currency
+	}
+	currenciesMu.Lock()
+	defer currenciesMu.Unlock()
+	currencies.Store(&registry)
+}
+
+// SetCurrency adds currency to the registry, or replaces the one with its code.
+func SetCurrency(currency Currency) {
+	currenciesMu.Lock()
+	defer currenciesMu.Unlock()
+	old := *currencies.Load()
+	registry := make(map[string]Currency, len(old)+1)
+	for code, registered := range old {
+		registry[code] = registered
+	}
+	registry[currency.Code] = currency
+	currencies.Store(&registry)
+}
+
+// LookupCurrency returns the currency of the registry with code, enabled or not.
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := (*currencies.Load())[code]
+	return currency, ok
+}
+
+// IsSupportedCurrency tells whether currency is enabled, so that accounts can be opened and
+// money moved in it.
 func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
+	found, ok := LookupCurrency(currency)
+	return ok && found.Enabled
+}
+
+// SupportedCurrencies returns the codes of the enabled currencies, in alphabetical order.
+func SupportedCurrencies() []string {
+	var codes []string
+	for code, currency := range *currencies.Load() {
+		if currency.Enabled {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// IsValidAmount tells whether amount, in the minor units of currency, is positive and at most the
+// MaxAmount of the currency. An amount in a currency missing from the registry is never valid.
+func IsValidAmount(currency string, amount int64) bool {
+	found, ok := LookupCurrency(currency)
+	return ok && amount > 0 && amount <= found.MaxAmount()
+}
+
+// ParseCurrencyAmounts parses entries of the form currency=amount, the amount in the minor units of
+// the currency, e.g. USD=500000 for 5000 dollars.
+func ParseCurrencyAmounts(entries []string) (map[string]int64, error) {
+	amounts := make(map[string]int64, len(entries))
+	for _, entry := range entries {
+		currency, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid currency amount %q, expected currency=amount", entry)
+		}
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("invalid currency amount %q", entry)
+		}
+		amounts[currency] = amount
 	}
-	return false
+	return amounts, nil
 }
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	require.Equal(t, []string{CAD, EUR, USD}, SupportedCurrencies())
+
+	defaults := *currencies.Load()
+	t.Cleanup(func() { currencies.Store(&defaults) })
+
+	SetCurrencies([]Currency{
+		{Code: USD, MinorUnits: 2, Enabled: true},
+		{Code: "JPY", MinorUnits: 0, Enabled: true},
+		{Code: "BHD", MinorUnits: 3, Enabled: false},
+	})
+	require.Equal(t, []string{"JPY", USD}, SupportedCurrencies())
+	require.True(t, IsSupportedCurrency("JPY"))
+	require.False(t, IsSupportedCurrency("BHD"))
+	require.False(t, IsSupportedCurrency(EUR))
+
+	bhd, ok := LookupCurrency("BHD")
+	require.True(t, ok)
+	require.Equal(t, 3, bhd.MinorUnits)
+	_, ok = LookupCurrency(EUR)
+	require.False(t, ok)
+
+	SetCurrency(Currency{Code: "BHD", MinorUnits: 3, Enabled: true})
+	require.Equal(t, []string{"BHD", "JPY", USD}, SupportedCurrencies())
+}
+
+func TestIsValidAmount(t *testing.T) {
+	defaults := *currencies.Load()
+	t.Cleanup(func() { currencies.Store(&defaults) })
+	SetCurrencies([]Currency{
+		{Code: USD, MinorUnits: 2, Enabled: true},
+		{Code: "JPY", MinorUnits: 0, Enabled: true},
+	})
+
+	testCases := []struct {
+		currency string
+		amount   int64
+		valid    bool
+	}{
+		{USD, 1, true},
+		{USD, MaxMajorAmount * 100, true},
+		{USD, MaxMajorAmount*100 + 1, false},
+		{"JPY", MaxMajorAmount, true},
+		// a trillion dollars in cents is far more than a trillion yen
+		{"JPY", MaxMajorAmount * 100, false},
+		{USD, 0, false},
+		{USD, -5, false},
+		{EUR, 100, false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.valid, IsValidAmount(tc.currency, tc.amount), "%d %s", tc.amount, tc.currency)
+	}
+}
+
+func TestParseCurrencyAmounts(t *testing.T) {
+	amounts, err := ParseCurrencyAmounts([]string{"USD=500000", " JPY=700000"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{USD: 500_000, "JPY": 700_000}, amounts)
+
+	_, err = ParseCurrencyAmounts([]string{"USD"})
+	require.Error(t, err)
+	_, err = ParseCurrencyAmounts([]string{"USD=five"})
+	require.Error(t, err)
+	_, err = ParseCurrencyAmounts([]string{"USD=-1"})
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/backendmaster/simple_bank/version"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Output formats of LOG_FORMAT.
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// NewLogger builds the logger described by config, writing to out.
+// Every event carries the version of the binary. An empty level defaults to info and an empty format to json. With a sample rate of N,
+// only one in N events of that kind is kept: LOG_SAMPLE_RATE applies to debug and info
+// events, LOG_ERROR_SAMPLE_RATE to warnings and errors. 0 or 1 keeps every event.
+func NewLogger(config Config, out io.Writer) (zerolog.Logger, error) {
+	level := zerolog.InfoLevel
+	if config.LogLevel != "" {
+		var err error
+		level, err = zerolog.ParseLevel(config.LogLevel)
+		if err != nil {
+			return zerolog.Logger{}, fmt.Errorf("invalid log level %q: %w", config.LogLevel, err)
+		}
+	}
+
+	switch config.LogFormat {
+	case "", LogFormatJSON:
+	case LogFormatConsole:
+		out = zerolog.ConsoleWriter{Out: out}
+	default:
+		return zerolog.Logger{}, fmt.Errorf("invalid log format %q", config.LogFormat)
+	}
+
+	logger := zerolog.New(out).Level(level).With().Timestamp().Str("version", version.Version).Logger()
+	if config.LogSampleRate > 1 || config.LogErrorSampleRate > 1 {
+		logger = logger.Sample(zerolog.LevelSampler{
+			DebugSampler: newSampler(config.LogSampleRate),
+			InfoSampler:  newSampler(config.LogSampleRate),
+			WarnSampler:  newSampler(config.LogErrorSampleRate),
+			ErrorSampler: newSampler(config.LogErrorSampleRate),
+		})
+	}
+	return logger, nil
+}
+
+// ConfigureLogger replaces the global logger with the one described by config.
+func ConfigureLogger(config Config) error {
+	logger, err := NewLogger(config, os.Stderr)
+	if err != nil {
+		return err
+	}
+	log.Logger = logger
+	return nil
+}
+
+func newSampler(rate uint32) zerolog.Sampler {
+	if rate <= 1 {
+		return nil
+	}
+	return &zerolog.BasicSampler{N: rate}
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(Config{LogLevel: "warn"}, &buffer)
+	require.NoError(t, err)
+
+	logger.Info().Msg("dropped")
+	logger.Warn().Msg("kept")
+	require.NotContains(t, buffer.String(), "dropped")
+	require.Contains(t, buffer.String(), `"message":"kept"`)
+}
+
+func TestNewLoggerConsole(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(Config{LogFormat: LogFormatConsole}, &buffer)
+	require.NoError(t, err)
+
+	logger.Info().Msg("hello")
+	require.Contains(t, buffer.String(), "hello")
+	require.False(t, strings.HasPrefix(buffer.String(), "{"))
+}
+
+func TestNewLoggerSampling(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(Config{LogSampleRate: 5}, &buffer)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		logger.Info().Msg("request")
+		logger.Error().Msg("failure")
+	}
+	require.Equal(t, 2, strings.Count(buffer.String(), "request"))
+	require.Equal(t, 10, strings.Count(buffer.String(), "failure"))
+}
+
+func TestNewLoggerInvalidConfig(t *testing.T) {
+	_, err := NewLogger(Config{LogLevel: "loud"}, &bytes.Buffer{})
+	require.Error(t, err)
+
+	_, err = NewLogger(Config{LogFormat: "xml"}, &bytes.Buffer{})
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// RedactedValue replaces the value of every sensitive field in a redacted body.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedFields are redacted when no field list is configured.
+var DefaultRedactedFields = []string{"password", "token", "email"}
+
+// Redactor masks sensitive fields of json bodies before they are logged.
+type Redactor struct {
+	fields []string
+}
+
+// NewRedactor creates a redactor for the given fields, falling back to DefaultRedactedFields when fields is empty.
+// A field matches every json key containing it regardless of case, so "token" also covers access_token and refresh_token.
+func NewRedactor(fields []string) *Redactor {
+	redactor := &Redactor{}
+	for _, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			redactor.fields = append(redactor.fields, field)
+		}
+	}
+	if len(redactor.fields) == 0 {
+		redactor.fields = DefaultRedactedFields
+	}
+	return redactor
+}
+
+// RedactJSON returns body with the value of every sensitive field replaced by RedactedValue.
+// A body that isn't valid json can't be inspected, so it is dropped entirely.
+func (redactor *Redactor) RedactJSON(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(RedactedValue)
+	}
+
+	redacted, err := json.Marshal(redactor.redact(value))
+	if err != nil {
+		return []byte(RedactedValue)
+	}
+	return redacted
+}
+
+func (redactor *Redactor) redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if redactor.isSensitive(key) {
+				value[key] = RedactedValue
+			} else {
+				value[key] = redactor.redact(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactor.redact(item)
+		}
+	}
+	return value
+}
+
+func (redactor *Redactor) isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range redactor.fields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactJSON(t *testing.T) {
+	redactor := NewRedactor(nil)
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "SensitiveFields",
+			body:     `{"username":"alice","password":"secret","access_token":"v2.local.abc","email":"alice@email.com"}`,
+			expected: `{"access_token":"[REDACTED]","email":"[REDACTED]","password":"[REDACTED]","username":"alice"}`,
+		},
+		{
+			name:     "NestedFields",
+			body:     `{"user":{"Email":"alice@email.com","full_name":"Alice"},"sessions":[{"refresh_token":"abc","id":1}]}`,
+			expected: `{"sessions":[{"id":1,"refresh_token":"[REDACTED]"}],"user":{"Email":"[REDACTED]","full_name":"Alice"}}`,
+		},
+		{
+			name:     "NoSensitiveFields",
+			body:     `{"err":"account not found","balance":12345678901234567890}`,
+			expected: `{"balance":12345678901234567890,"err":"account not found"}`,
+		},
+		{
+			name:     "NotJSON",
+			body:     `password=secret`,
+			expected: RedactedValue,
+		},
+		{
+			name:     "Empty",
+			body:     ``,
+			expected: ``,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, string(redactor.RedactJSON([]byte(tc.body))))
+		})
+	}
+}
+
+func TestNewRedactorFields(t *testing.T) {
+	redactor := NewRedactor([]string{" Full_Name ", ""})
+
+	body := redactor.RedactJSON([]byte(`{"full_name":"Alice","password":"secret"}`))
+	require.Equal(t, `{"full_name":"[REDACTED]","password":"secret"}`, string(body))
+}
@@ -0,0 +1,4 @@
+package util
+
+// DefaultTenant is the tenant of the users who sign up without one, and of the bank itself.
+const DefaultTenant = "default"
@@ -1,18 +1,25 @@
 package val
 
 import (
+	"errors"
 	"fmt"
 	"net/mail"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
+// The rules are the ones of the validate.rules of the user requests in proto, so that the REST
+// api, which binds with them, and the gRPC api accept the same values. Lengths count runes
+// like protoc-gen-validate does.
+
 var (
 	isValidateUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
-	isValidateFullname = regexp.MustCompile(`^[a-zA-z\s]+$`).MatchString
+	isValidateFullname = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
-	n := len(value)
+	n := utf8.RuneCountInString(value)
 	if n < minLength || n > maxLength {
 		return fmt.Errorf("value: %v must contain %v-%v characters ", value, minLength, maxLength)
 	}
@@ -37,7 +44,7 @@ func ValidateEmail(email string) error {
 	if err := ValidateString(email, 3, 200); err != nil {
 		return err
 	}
-	if _, err := mail.ParseAddress(email); err != nil {
+	if err := validateEmailAddress(email); err != nil {
 		return fmt.Errorf("value: %v is not valid email address", email)
 	}
 	return nil
@@ -52,3 +59,40 @@ func ValidateFullName(name string) error {
 	}
 	return nil
 }
+
+// validateEmailAddress is the email rule of protoc-gen-validate: an address mail.ParseAddress
+// accepts, whose domain is a valid hostname.
+func validateEmailAddress(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return err
+	}
+	if len(address.Address) > 254 {
+		return errors.New("email addresses cannot exceed 254 characters")
+	}
+	local, domain, _ := strings.Cut(address.Address, "@")
+	if len(local) > 64 {
+		return errors.New("email address local phrase cannot exceed 64 characters")
+	}
+	return validateHostname(domain)
+}
+
+func validateHostname(host string) error {
+	if len(host) > 253 {
+		return errors.New("hostname cannot exceed 253 characters")
+	}
+	for _, part := range strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".") {
+		if l := len(part); l == 0 || l > 63 {
+			return errors.New("hostname part must be non-empty and cannot exceed 63 characters")
+		}
+		if part[0] == '-' || part[len(part)-1] == '-' {
+			return errors.New("hostname parts cannot begin or end with hyphens")
+		}
+		for _, r := range part {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return fmt.Errorf("hostname parts can only contain alphanumeric characters or hyphens, got %q", string(r))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package val
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/backendmaster/simple_bank/pb"
+	"github.com/stretchr/testify/require"
+)
+
+// The fuzz targets check that every value val accepts, and only those, passes the validate.rules
+// of the same field in proto, the way the gRPC api checks it. Run one with e.g.
+//
+//	go test ./val -fuzz FuzzValidateEmail
+
+// grpcAccepts tells whether the field of req passes the proto validation.
+func grpcAccepts(t *testing.T, req *pb.CreateUserRequest, field string) bool {
+	err := req.ValidateAll()
+	if err == nil {
+		return true
+	}
+	var multiErr pb.CreateUserRequestMultiError
+	require.True(t, errors.As(err, &multiErr))
+	for _, err := range multiErr.AllErrors() {
+		var fieldErr pb.CreateUserRequestValidationError
+		if errors.As(err, &fieldErr) && fieldErr.Field() == field {
+			return false
+		}
+	}
+	return true
+}
+
+func fuzzSeeds(f *testing.F, seeds ...string) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+}
+
+func FuzzValidateUserName(f *testing.F) {
+	fuzzSeeds(f, "alice", "bob_42", "ab", "abcdefghijk", "Alice", "al ice", "ali-ce", "élise", "")
+	f.Fuzz(func(t *testing.T, username string) {
+		err := ValidateUserName(username)
+		require.Equal(t, grpcAccepts(t, &pb.CreateUserRequest{Username: username}, "Username"), err == nil, "username %q: %v", username, err)
+	})
+}
+
+func FuzzValidateFullName(f *testing.F) {
+	fuzzSeeds(f, "Alice Bob", "Al", "Alice\tBob", "Alice_Bob", "Alice [Bob]", "Zoë", strings.Repeat("a", 21), "")
+	f.Fuzz(func(t *testing.T, fullName string) {
+		err := ValidateFullName(fullName)
+		require.Equal(t, grpcAccepts(t, &pb.CreateUserRequest{FullName: fullName}, "FullName"), err == nil, "full name %q: %v", fullName, err)
+	})
+}
+
+func FuzzValidatePassword(f *testing.F) {
+	fuzzSeeds(f, "secret", "short", "0123456789", "01234567890", "pässwörd", "密码密码密码", "")
+	f.Fuzz(func(t *testing.T, password string) {
+		err := ValidatePassword(password)
+		require.Equal(t, grpcAccepts(t, &pb.CreateUserRequest{Password: password}, "Password"), err == nil, "password %q: %v", password, err)
+	})
+}
+
+func FuzzValidateEmail(f *testing.F) {
+	fuzzSeeds(f, "alice@email.com", "a@b", "Alice <alice@email.com>", "alice@", "@email.com", "alice@-email.com",
+		"alice@email..com", "alice@EMAIL.com", "alice@émail.com", strings.Repeat("a", 65)+"@email.com", "")
+	f.Fuzz(func(t *testing.T, email string) {
+		err := ValidateEmail(email)
+		require.Equal(t, grpcAccepts(t, &pb.CreateUserRequest{Email: email}, "Email"), err == nil, "email %q: %v", email, err)
+	})
+}
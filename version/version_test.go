@@ -0,0 +1,23 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/version", nil)
+	require.NoError(t, err)
+
+	Handler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var info Info
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &info))
+	require.Equal(t, Get(), info)
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/hibiken/asynq"
+)
+
+// TaskDistributor enqueues the background tasks that the handlers don't want to run inline.
+type TaskDistributor interface {
+	DistributeTaskSendVerifyEmail(
+		ctx context.Context,
+		payload *PayloadSendVerifyEmail,
+		opts ...asynq.Option,
+	) error
+	DistributeOutboxTask(ctx context.Context, outbox db.Outbox) error
+}
+
+type RedisTaskDistributor struct {
+	client   *asynq.Client
+	policies RetryPolicies
+}
+
+func NewRedisTaskDistributor(redisOpt asynq.RedisClientOpt, policies RetryPolicies) TaskDistributor {
+	client := asynq.NewClient(redisOpt)
+	return &RedisTaskDistributor{
+		client:   client,
+		policies: policies,
+	}
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/events"
+	"github.com/rs/zerolog/log"
+)
+
+// EventRelay publishes the domain events written to the event outbox to the message broker.
+// It is separate from the OutboxRelay, so that a broker outage doesn't hold up the tasks.
+// An event published right before the relay stops can be published again, with the same id.
+type EventRelay struct {
+	store     db.OutboxStore
+	publisher events.Publisher
+	interval  time.Duration
+}
+
+func NewEventRelay(store db.OutboxStore, publisher events.Publisher, interval time.Duration) *EventRelay {
+	if interval <= 0 {
+		interval = defaultOutboxInterval
+	}
+	return &EventRelay{
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run polls the event outbox every interval until ctx is done.
+func (relay *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := relay.publishPending(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to publish outbox events")
+			}
+		}
+	}
+}
+
+// publishPending publishes batches of pending events until the event outbox is empty.
+func (relay *EventRelay) publishPending(ctx context.Context) error {
+	for {
+		published, err := relay.store.PublishOutboxEventsTx(ctx, outboxBatchSize, func(rows []db.EventOutbox) error {
+			batch := make([]events.Event, 0, len(rows))
+			for _, row := range rows {
+				batch = append(batch, newEvent(row))
+			}
+			return relay.publisher.Publish(ctx, batch...)
+		})
+		if err != nil {
+			return err
+		}
+		if published < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+func newEvent(row db.EventOutbox) events.Event {
+	return events.Event{
+		ID:         strconv.FormatInt(row.ID, 10),
+		Type:       row.EventType,
+		Key:        row.EventKey,
+		OccurredAt: row.CreatedAt,
+		Payload:    row.Payload,
+	}
+}
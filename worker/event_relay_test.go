@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/events"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	published []events.Event
+	err       error
+}
+
+func (publisher *fakePublisher) Publish(ctx context.Context, batch ...events.Event) error {
+	if publisher.err != nil {
+		return publisher.err
+	}
+	publisher.published = append(publisher.published, batch...)
+	return nil
+}
+
+func (publisher *fakePublisher) Close() error {
+	return nil
+}
+
+func publishEvents(rows []db.EventOutbox) func(ctx context.Context, limit int32, publish func([]db.EventOutbox) error) (int, error) {
+	return func(ctx context.Context, limit int32, publish func([]db.EventOutbox) error) (int, error) {
+		if len(rows) == 0 {
+			return 0, nil
+		}
+		if err := publish(rows); err != nil {
+			return 0, err
+		}
+		return len(rows), nil
+	}
+}
+
+func TestEventRelay(t *testing.T) {
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	row := db.EventOutbox{
+		ID:        7,
+		EventType: events.TypeTransferCompleted,
+		EventKey:  "1",
+		Payload:   json.RawMessage(`{"transfer_id":3}`),
+		CreatedAt: createdAt,
+	}
+	fullBatch := make([]db.EventOutbox, outboxBatchSize)
+	for i := range fullBatch {
+		fullBatch[i].ID = int64(i + 1)
+	}
+
+	testCases := []struct {
+		name        string
+		publishErr  error
+		buildStubs  func(store *mockdb.MockOutboxStore)
+		checkResult func(t *testing.T, published []events.Event, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				store.EXPECT().
+					PublishOutboxEventsTx(gomock.Any(), gomock.Eq(int32(outboxBatchSize)), gomock.Any()).
+					Times(1).
+					DoAndReturn(publishEvents([]db.EventOutbox{row}))
+			},
+			checkResult: func(t *testing.T, published []events.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []events.Event{{
+					ID:         "7",
+					Type:       events.TypeTransferCompleted,
+					Key:        "1",
+					OccurredAt: createdAt,
+					Payload:    row.Payload,
+				}}, published)
+			},
+		},
+		{
+			name: "FullBatch",
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						PublishOutboxEventsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(publishEvents(fullBatch)),
+					store.EXPECT().
+						PublishOutboxEventsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(publishEvents(nil)),
+				)
+			},
+			checkResult: func(t *testing.T, published []events.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, published, outboxBatchSize)
+			},
+		},
+		{
+			name:       "PublishError",
+			publishErr: errors.New("broker is down"),
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				store.EXPECT().
+					PublishOutboxEventsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(publishEvents([]db.EventOutbox{row}))
+			},
+			checkResult: func(t *testing.T, published []events.Event, err error) {
+				require.Error(t, err)
+				require.Empty(t, published)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockOutboxStore(ctrl)
+			tc.buildStubs(store)
+			publisher := &fakePublisher{err: tc.publishErr}
+
+			relay := NewEventRelay(store, publisher, 0)
+			err := relay.publishPending(context.Background())
+			tc.checkResult(t, publisher.published, err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/hibiken/asynq"
+)
+
+// TaskInspector gives access to the dead-letter queue: the tasks that failed their last retry,
+// or that asked to skip retrying, and are kept by asynq as archived tasks.
+type TaskInspector interface {
+	ListDeadTasks(queue string, pageSize int, page int) ([]*asynq.TaskInfo, error)
+	GetDeadTask(queue string, id string) (*asynq.TaskInfo, error)
+	RequeueDeadTask(queue string, id string) error
+	DiscardDeadTask(queue string, id string) error
+	QueueDepths() ([]metrics.QueueDepth, error)
+}
+
+type RedisTaskInspector struct {
+	inspector *asynq.Inspector
+}
+
+func NewRedisTaskInspector(redisOpt asynq.RedisClientOpt) TaskInspector {
+	return &RedisTaskInspector{
+		inspector: asynq.NewInspector(redisOpt),
+	}
+}
+
+func (inspector *RedisTaskInspector) ListDeadTasks(queue string, pageSize int, page int) ([]*asynq.TaskInfo, error) {
+	return inspector.inspector.ListArchivedTasks(queue, asynq.PageSize(pageSize), asynq.Page(page))
+}
+
+func (inspector *RedisTaskInspector) GetDeadTask(queue string, id string) (*asynq.TaskInfo, error) {
+	info, err := inspector.inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		return nil, err
+	}
+	if info.State != asynq.TaskStateArchived {
+		return nil, asynq.ErrTaskNotFound
+	}
+	return info, nil
+}
+
+// RequeueDeadTask moves the task back to its queue, to be processed again with a fresh retry count.
+func (inspector *RedisTaskInspector) RequeueDeadTask(queue string, id string) error {
+	if _, err := inspector.GetDeadTask(queue, id); err != nil {
+		return err
+	}
+	return inspector.inspector.RunTask(queue, id)
+}
+
+func (inspector *RedisTaskInspector) DiscardDeadTask(queue string, id string) error {
+	if _, err := inspector.GetDeadTask(queue, id); err != nil {
+		return err
+	}
+	return inspector.inspector.DeleteTask(queue, id)
+}
+
+// QueueDepths counts the tasks of every queue known to redis by state.
+func (inspector *RedisTaskInspector) QueueDepths() ([]metrics.QueueDepth, error) {
+	queues, err := inspector.inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+
+	depths := make([]metrics.QueueDepth, 0, len(queues))
+	for _, queue := range queues {
+		info, err := inspector.inspector.GetQueueInfo(queue)
+		if err != nil {
+			return nil, err
+		}
+		depths = append(depths, metrics.QueueDepth{
+			Queue:     queue,
+			Pending:   info.Pending,
+			Active:    info.Active,
+			Scheduled: info.Scheduled,
+			Retry:     info.Retry,
+			Archived:  info.Archived,
+		})
+	}
+	return depths, nil
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger sends the logs of asynq through zerolog, so they follow the configured level and format.
+type Logger struct{}
+
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+func (logger *Logger) Print(level zerolog.Level, args ...interface{}) {
+	log.WithLevel(level).Msg(fmt.Sprint(args...))
+}
+
+func (logger *Logger) Debug(args ...interface{}) {
+	logger.Print(zerolog.DebugLevel, args...)
+}
+
+func (logger *Logger) Info(args ...interface{}) {
+	logger.Print(zerolog.InfoLevel, args...)
+}
+
+func (logger *Logger) Warn(args ...interface{}) {
+	logger.Print(zerolog.WarnLevel, args...)
+}
+
+func (logger *Logger) Error(args ...interface{}) {
+	logger.Print(zerolog.ErrorLevel, args...)
+}
+
+func (logger *Logger) Fatal(args ...interface{}) {
+	logger.Print(zerolog.FatalLevel, args...)
+}
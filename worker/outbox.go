@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultOutboxInterval = time.Second
+	outboxBatchSize       = 100
+	// outboxRetention keeps the id of a finished task in redis, so that publishing it a second
+	// time after a crash of the relay is still detected as a duplicate.
+	outboxRetention = 24 * time.Hour
+)
+
+// newOutboxTask builds the outbox row of a task, to be written in the transaction of the change that triggers it.
+func newOutboxTask(taskType string, payload interface{}, queue string, maxRetry int32) (db.CreateOutboxTaskParams, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return db.CreateOutboxTaskParams{}, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return db.CreateOutboxTaskParams{
+		TaskType: taskType,
+		Payload:  jsonPayload,
+		Queue:    queue,
+		MaxRetry: maxRetry,
+	}, nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeOutboxTask(ctx context.Context, outbox db.Outbox) error {
+	opts := []asynq.Option{
+		asynq.TaskID(fmt.Sprintf("outbox:%d", outbox.ID)),
+		asynq.Queue(outbox.Queue),
+		asynq.MaxRetry(int(outbox.MaxRetry)),
+		asynq.Retention(outboxRetention),
+	}
+	// the configured policy wins over the max retry the row was written with
+	opts = append(opts, distributor.policies.For(outbox.TaskType).options()...)
+	task := asynq.NewTask(outbox.TaskType, outbox.Payload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			// enqueued already by a relay that crashed before marking the row as published
+			return nil
+		}
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("outbox id", outbox.ID).
+		Str("queue", info.Queue).Int("max retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// OutboxRelay publishes the tasks written to the outbox table to the task queue.
+// Each row is enqueued under an id derived from its own, so it is processed exactly once
+// even when the relay stops between enqueueing a task and marking its row as published.
+type OutboxRelay struct {
+	store       db.OutboxStore
+	distributor TaskDistributor
+	interval    time.Duration
+}
+
+func NewOutboxRelay(store db.OutboxStore, distributor TaskDistributor, interval time.Duration) *OutboxRelay {
+	if interval <= 0 {
+		interval = defaultOutboxInterval
+	}
+	return &OutboxRelay{
+		store:       store,
+		distributor: distributor,
+		interval:    interval,
+	}
+}
+
+// Run polls the outbox every interval until ctx is done.
+func (relay *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := relay.publishPending(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to publish outbox tasks")
+			}
+		}
+	}
+}
+
+// publishPending publishes batches of pending tasks until the outbox is empty.
+func (relay *OutboxRelay) publishPending(ctx context.Context) error {
+	for {
+		published, err := relay.store.PublishOutboxTx(ctx, outboxBatchSize, func(task db.Outbox) error {
+			return relay.distributor.DistributeOutboxTask(ctx, task)
+		})
+		if err != nil {
+			return err
+		}
+		if published < outboxBatchSize {
+			return nil
+		}
+	}
+}
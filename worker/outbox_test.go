@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDistributor struct {
+	published []int64
+	err       error
+}
+
+func (distributor *fakeDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error {
+	return nil
+}
+
+func (distributor *fakeDistributor) DistributeOutboxTask(ctx context.Context, outbox db.Outbox) error {
+	if distributor.err != nil {
+		return distributor.err
+	}
+	distributor.published = append(distributor.published, outbox.ID)
+	return nil
+}
+
+func publishTasks(tasks []db.Outbox) func(ctx context.Context, limit int32, publish func(db.Outbox) error) (int, error) {
+	return func(ctx context.Context, limit int32, publish func(db.Outbox) error) (int, error) {
+		for i, task := range tasks {
+			if err := publish(task); err != nil {
+				return i, err
+			}
+		}
+		return len(tasks), nil
+	}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	fullBatch := make([]db.Outbox, outboxBatchSize)
+	for i := range fullBatch {
+		fullBatch[i].ID = int64(i + 1)
+	}
+
+	testCases := []struct {
+		name          string
+		distributeErr error
+		buildStubs    func(store *mockdb.MockOutboxStore)
+		checkResult   func(t *testing.T, published []int64, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				store.EXPECT().
+					PublishOutboxTx(gomock.Any(), gomock.Eq(int32(outboxBatchSize)), gomock.Any()).
+					Times(1).
+					DoAndReturn(publishTasks([]db.Outbox{{ID: 1}, {ID: 2}}))
+			},
+			checkResult: func(t *testing.T, published []int64, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []int64{1, 2}, published)
+			},
+		},
+		{
+			name: "FullBatch",
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						PublishOutboxTx(gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(publishTasks(fullBatch)),
+					store.EXPECT().
+						PublishOutboxTx(gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(publishTasks(nil)),
+				)
+			},
+			checkResult: func(t *testing.T, published []int64, err error) {
+				require.NoError(t, err)
+				require.Len(t, published, outboxBatchSize)
+			},
+		},
+		{
+			name:          "DistributeError",
+			distributeErr: errors.New("redis is down"),
+			buildStubs: func(store *mockdb.MockOutboxStore) {
+				store.EXPECT().
+					PublishOutboxTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(publishTasks([]db.Outbox{{ID: 1}}))
+			},
+			checkResult: func(t *testing.T, published []int64, err error) {
+				require.Error(t, err)
+				require.Empty(t, published)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockOutboxStore(ctrl)
+			tc.buildStubs(store)
+			distributor := &fakeDistributor{err: tc.distributeErr}
+
+			relay := NewOutboxRelay(store, distributor, 0)
+			err := relay.publishPending(context.Background())
+			tc.checkResult(t, distributor.published, err)
+		})
+	}
+}
+
+func TestNewOutboxTask(t *testing.T) {
+	task, err := NewSendVerifyEmailTask(&PayloadSendVerifyEmail{Username: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, TaskSendVerifyEmail, task.TaskType)
+	require.Equal(t, QueueCritical, task.Queue)
+	require.JSONEq(t, `{"username":"alice"}`, string(task.Payload))
+}
@@ -0,0 +1,237 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/referral"
+	"github.com/backendmaster/simple_bank/rewards"
+	"github.com/backendmaster/simple_bank/settlement"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	QueueCritical = "critical"
+	QueueDefault  = "default"
+	QueueLow      = "low"
+)
+
+// DefaultQueueConcurrency is the number of workers of each queue when WORKER_CONCURRENCY is empty.
+var DefaultQueueConcurrency = QueueConcurrency{
+	QueueCritical: 10,
+	QueueDefault:  5,
+	QueueLow:      2,
+}
+
+// QueueConcurrency holds the number of workers of each queue.
+type QueueConcurrency map[string]int
+
+// ParseQueueConcurrency parses WORKER_CONCURRENCY entries of the form queue=workers, e.g. critical=10.
+func ParseQueueConcurrency(entries []string) (QueueConcurrency, error) {
+	if len(entries) == 0 {
+		return DefaultQueueConcurrency, nil
+	}
+
+	concurrency := make(QueueConcurrency, len(entries))
+	for _, entry := range entries {
+		queue, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid queue concurrency %q, expected queue=workers", entry)
+		}
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers <= 0 {
+			return nil, fmt.Errorf("invalid queue concurrency %q: workers must be a positive number", entry)
+		}
+		concurrency[queue] = workers
+	}
+	return concurrency, nil
+}
+
+// TaskProcessor runs the tasks enqueued by a TaskDistributor.
+type TaskProcessor interface {
+	Start() error
+	Shutdown()
+	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskNotifyTransfer(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendTransferOTP(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendDeviceOTP(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendStepUpOTP(ctx context.Context, task *asynq.Task) error
+	ProcessTaskVerifyLedger(ctx context.Context, task *asynq.Task) error
+	ProcessTaskVerifyAuditLogs(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCreatePartitions(ctx context.Context, task *asynq.Task) error
+	ProcessTaskArchiveEntries(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExportEntries(ctx context.Context, task *asynq.Task) error
+	ProcessTaskImportUsers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeleteExpiredExports(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSettleExternalTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireAuthorizationHolds(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCollectLoanInstallments(ctx context.Context, task *asynq.Task) error
+	ProcessTaskAccrueRewards(ctx context.Context, task *asynq.Task) error
+	ProcessTaskQualifyReferrals(ctx context.Context, task *asynq.Task) error
+	ProcessTaskRefreshExchangeRates(ctx context.Context, task *asynq.Task) error
+}
+
+type RedisTaskProcessor struct {
+	servers              []*asynq.Server
+	store                db.Store
+	mailer               mail.EmailSender
+	notifier             *notification.Notifier
+	network              settlement.Network
+	rewards              rewards.Program
+	referrals            referral.Program
+	fxProvider           fx.Provider
+	fxRateMaxAge         time.Duration
+	verifyEmailURL       string
+	partitionMonthsAhead int
+	entryRetentionYears  int
+}
+
+// NewRedisTaskProcessor creates the processor. Each queue gets its own asynq server with its own
+// workers, rather than sharing them by priority, so that a backlog in the low queue can never hold
+// the workers the verification emails need.
+//
+// On Shutdown it stops fetching new tasks and gives the in-flight ones config.ShutdownTimeout
+// to finish, after which asynq cancels their context and puts them back in their queue,
+// without counting it as a retry.
+func NewRedisTaskProcessor(
+	config util.Config,
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	policies RetryPolicies,
+	concurrency QueueConcurrency,
+	mailer mail.EmailSender,
+	notifier *notification.Notifier,
+	fxProvider fx.Provider,
+) TaskProcessor {
+	processor := &RedisTaskProcessor{
+		store:                store,
+		mailer:               mailer,
+		notifier:             notifier,
+		network:              settlement.NewSimulator(config.SettlementDelay),
+		fxProvider:           fxProvider,
+		fxRateMaxAge:         config.FXRateMaxAge,
+		verifyEmailURL:       config.EmailVerifyURL,
+		partitionMonthsAhead: config.PartitionMonthsAhead,
+		entryRetentionYears:  config.EntryRetentionYears,
+		rewards: rewards.Program{
+			RateBps:   config.CashbackRateBps,
+			MinAmount: config.CashbackMinAmount,
+			MaxPoints: config.CashbackMaxPoints,
+		},
+		referrals: referral.Program{
+			Bonus:          config.ReferralBonus,
+			MinAmount:      config.ReferralMinAmount,
+			Window:         config.ReferralWindow,
+			MaxPerReferrer: config.ReferralMaxPerReferrer,
+		},
+	}
+
+	if processor.fxRateMaxAge <= 0 {
+		processor.fxRateMaxAge = fx.DefaultMaxAge
+	}
+
+	for queue, workers := range concurrency {
+		server := asynq.NewServer(
+			redisOpt,
+			asynq.Config{
+				Concurrency: workers,
+				Queues: map[string]int{
+					queue: 1,
+				},
+				ShutdownTimeout: config.ShutdownTimeout,
+				RetryDelayFunc:  policies.retryDelayFunc(),
+				ErrorHandler:    asynq.ErrorHandlerFunc(handleTaskError),
+				Logger:          NewLogger(),
+			},
+		)
+		processor.servers = append(processor.servers, server)
+	}
+	return processor
+}
+
+// handleTaskError logs the failures of tasks, and warns when a task is moved to the dead-letter
+// queue, where it waits for an admin to requeue or discard it.
+func handleTaskError(ctx context.Context, task *asynq.Task, err error) {
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	taskID, _ := asynq.GetTaskID(ctx)
+	queue, _ := asynq.GetQueueName(ctx)
+
+	log.Error().Err(err).Str("type", task.Type()).Str("task id", taskID).
+		Int("retried", retried).Int("max retry", maxRetry).Msg("process task failed")
+
+	if retried >= maxRetry || errors.Is(err, asynq.SkipRetry) {
+		log.Warn().Str("type", task.Type()).Str("task id", taskID).Str("queue", queue).
+			Msg("task moved to the dead-letter queue")
+	}
+}
+
+// userBrand is how the emails to user present the bank, as its tenant.
+func (processor *RedisTaskProcessor) userBrand(ctx context.Context, user db.User) (mail.Brand, error) {
+	tenant, err := processor.store.GetTenant(ctx, user.TenantID)
+	if err != nil {
+		return mail.Brand{}, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return notification.TenantBrand(tenant), nil
+}
+
+func (processor *RedisTaskProcessor) Start() error {
+	mux := asynq.NewServeMux()
+
+	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskNotifyTransfer, processor.ProcessTaskNotifyTransfer)
+	mux.HandleFunc(TaskSendNotification, processor.ProcessTaskSendNotification)
+	mux.HandleFunc(TaskSendTransferOTP, processor.ProcessTaskSendTransferOTP)
+	mux.HandleFunc(TaskSendDeviceOTP, processor.ProcessTaskSendDeviceOTP)
+	mux.HandleFunc(TaskSendPasswordReset, processor.ProcessTaskSendPasswordReset)
+	mux.HandleFunc(TaskSendStepUpOTP, processor.ProcessTaskSendStepUpOTP)
+	mux.HandleFunc(TaskVerifyLedger, processor.ProcessTaskVerifyLedger)
+	mux.HandleFunc(TaskVerifyAuditLogs, processor.ProcessTaskVerifyAuditLogs)
+	mux.HandleFunc(TaskCreatePartitions, processor.ProcessTaskCreatePartitions)
+	mux.HandleFunc(TaskArchiveEntries, processor.ProcessTaskArchiveEntries)
+	mux.HandleFunc(TaskExportEntries, processor.ProcessTaskExportEntries)
+	mux.HandleFunc(TaskImportUsers, processor.ProcessTaskImportUsers)
+	mux.HandleFunc(TaskDeleteExpiredExports, processor.ProcessTaskDeleteExpiredExports)
+	mux.HandleFunc(TaskSettleExternalTransfers, processor.ProcessTaskSettleExternalTransfers)
+	mux.HandleFunc(TaskExpireAuthorizationHolds, processor.ProcessTaskExpireAuthorizationHolds)
+	mux.HandleFunc(TaskCollectLoanInstallments, processor.ProcessTaskCollectLoanInstallments)
+	mux.HandleFunc(TaskAccrueRewards, processor.ProcessTaskAccrueRewards)
+	mux.HandleFunc(TaskQualifyReferrals, processor.ProcessTaskQualifyReferrals)
+	mux.HandleFunc(TaskRefreshExchangeRates, processor.ProcessTaskRefreshExchangeRates)
+
+	for i, server := range processor.servers {
+		if err := server.Start(mux); err != nil {
+			for _, started := range processor.servers[:i] {
+				started.Shutdown()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown drains the queues in parallel, so that the whole processor stops within one ShutdownTimeout.
+func (processor *RedisTaskProcessor) Shutdown() {
+	var wg sync.WaitGroup
+	for _, server := range processor.servers {
+		wg.Add(1)
+		go func(server *asynq.Server) {
+			defer wg.Done()
+			server.Shutdown()
+		}(server)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueueConcurrency(t *testing.T) {
+	concurrency, err := ParseQueueConcurrency(nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultQueueConcurrency, concurrency)
+
+	concurrency, err = ParseQueueConcurrency([]string{"critical=20", " low=1"})
+	require.NoError(t, err)
+	require.Equal(t, QueueConcurrency{QueueCritical: 20, QueueLow: 1}, concurrency)
+
+	for _, entry := range []string{"critical", "critical=many", "low=0"} {
+		_, err = ParseQueueConcurrency([]string{entry})
+		require.Error(t, err, entry)
+	}
+}
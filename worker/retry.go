@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	BackoffExponential = "exponential"
+	BackoffLinear      = "linear"
+	BackoffConstant    = "constant"
+)
+
+// RetryPolicy controls how a type of task is retried. A zero MaxRetry or Timeout leaves
+// the value given when the task was enqueued, and a zero MaxDelay leaves the delay uncapped.
+type RetryPolicy struct {
+	MaxRetry  int
+	Backoff   string
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Timeout   time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	Backoff:   BackoffExponential,
+	BaseDelay: time.Second,
+	MaxDelay:  time.Hour,
+}
+
+// RetryPolicies holds the retry policy of each task type.
+type RetryPolicies map[string]RetryPolicy
+
+// For returns the policy of taskType, or the default exponential backoff from 1s to 1h.
+func (policies RetryPolicies) For(taskType string) RetryPolicy {
+	if policy, ok := policies[taskType]; ok {
+		return policy
+	}
+	return defaultRetryPolicy
+}
+
+// ParseRetryPolicies parses TASK_RETRY_POLICIES entries of the form
+// type=max retry/backoff/base delay/max delay/timeout, e.g. task:send_verify_email=10/exponential/1s/1h/30s.
+func ParseRetryPolicies(entries []string) (RetryPolicies, error) {
+	policies := make(RetryPolicies, len(entries))
+	for _, entry := range entries {
+		taskType, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		fields := strings.Split(value, "/")
+		if !ok || len(fields) != 5 {
+			return nil, fmt.Errorf("invalid retry policy %q, expected type=max retry/backoff/base delay/max delay/timeout", entry)
+		}
+
+		var policy RetryPolicy
+		var err error
+		if policy.MaxRetry, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid retry policy %q: %w", entry, err)
+		}
+		switch fields[1] {
+		case BackoffExponential, BackoffLinear, BackoffConstant:
+			policy.Backoff = fields[1]
+		default:
+			return nil, fmt.Errorf("invalid retry policy %q: unknown backoff %q", entry, fields[1])
+		}
+		if policy.BaseDelay, err = time.ParseDuration(fields[2]); err != nil {
+			return nil, fmt.Errorf("invalid retry policy %q: %w", entry, err)
+		}
+		if policy.MaxDelay, err = time.ParseDuration(fields[3]); err != nil {
+			return nil, fmt.Errorf("invalid retry policy %q: %w", entry, err)
+		}
+		if policy.Timeout, err = time.ParseDuration(fields[4]); err != nil {
+			return nil, fmt.Errorf("invalid retry policy %q: %w", entry, err)
+		}
+		policies[taskType] = policy
+	}
+	return policies, nil
+}
+
+// Delay returns how long to wait before the retry following retried failed attempts.
+func (policy RetryPolicy) Delay(retried int) time.Duration {
+	delay := policy.BaseDelay
+	switch policy.Backoff {
+	case BackoffExponential:
+		for i := 0; i < retried; i++ {
+			// stop doubling at the cap, or short of overflowing without one
+			if (policy.MaxDelay > 0 && delay >= policy.MaxDelay) || delay > math.MaxInt64/2 {
+				break
+			}
+			delay *= 2
+		}
+	case BackoffLinear:
+		delay = policy.BaseDelay * time.Duration(retried+1)
+	}
+
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return delay
+}
+
+// options returns the enqueue options that apply the policy.
+func (policy RetryPolicy) options() []asynq.Option {
+	var opts []asynq.Option
+	if policy.MaxRetry > 0 {
+		opts = append(opts, asynq.MaxRetry(policy.MaxRetry))
+	}
+	if policy.Timeout > 0 {
+		opts = append(opts, asynq.Timeout(policy.Timeout))
+	}
+	return opts
+}
+
+// retryDelayFunc lets asynq wait between retries as the policy of each task type says.
+func (policies RetryPolicies) retryDelayFunc() asynq.RetryDelayFunc {
+	return func(retried int, err error, task *asynq.Task) time.Duration {
+		return policies.For(task.Type()).Delay(retried)
+	}
+}
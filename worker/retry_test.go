@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryPolicies(t *testing.T) {
+	policies, err := ParseRetryPolicies([]string{
+		"task:send_verify_email=10/exponential/1s/1h/30s",
+		" task:deliver_webhook=20/linear/1m/6h/10s",
+	})
+	require.NoError(t, err)
+	require.Equal(t, RetryPolicy{
+		MaxRetry:  10,
+		Backoff:   BackoffExponential,
+		BaseDelay: time.Second,
+		MaxDelay:  time.Hour,
+		Timeout:   30 * time.Second,
+	}, policies.For(TaskSendVerifyEmail))
+	require.Equal(t, BackoffLinear, policies.For("task:deliver_webhook").Backoff)
+	require.Equal(t, defaultRetryPolicy, policies.For(TaskNotifyTransfer))
+
+	invalidEntries := []string{
+		"task:send_verify_email",
+		"task:send_verify_email=10/exponential/1s/1h",
+		"task:send_verify_email=ten/exponential/1s/1h/30s",
+		"task:send_verify_email=10/fibonacci/1s/1h/30s",
+		"task:send_verify_email=10/exponential/soon/1h/30s",
+	}
+	for _, entry := range invalidEntries {
+		_, err = ParseRetryPolicies([]string{entry})
+		require.Error(t, err, entry)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   RetryPolicy
+		retried  int
+		expected time.Duration
+	}{
+		{
+			name:     "ExponentialFirstRetry",
+			policy:   defaultRetryPolicy,
+			retried:  0,
+			expected: time.Second,
+		},
+		{
+			name:     "Exponential",
+			policy:   defaultRetryPolicy,
+			retried:  3,
+			expected: 8 * time.Second,
+		},
+		{
+			name:     "ExponentialCapped",
+			policy:   defaultRetryPolicy,
+			retried:  1000,
+			expected: time.Hour,
+		},
+		{
+			name:     "ExponentialUncapped",
+			policy:   RetryPolicy{Backoff: BackoffExponential, BaseDelay: time.Second},
+			retried:  12,
+			expected: 4096 * time.Second,
+		},
+		{
+			name:     "ExponentialUncappedNoOverflow",
+			policy:   RetryPolicy{Backoff: BackoffExponential, BaseDelay: time.Second},
+			retried:  1000,
+			expected: time.Second << 33,
+		},
+		{
+			name:     "Linear",
+			policy:   RetryPolicy{Backoff: BackoffLinear, BaseDelay: time.Minute, MaxDelay: time.Hour},
+			retried:  4,
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "LinearCapped",
+			policy:   RetryPolicy{Backoff: BackoffLinear, BaseDelay: time.Minute, MaxDelay: time.Hour},
+			retried:  100,
+			expected: time.Hour,
+		},
+		{
+			name:     "Constant",
+			policy:   RetryPolicy{Backoff: BackoffConstant, BaseDelay: 30 * time.Second},
+			retried:  7,
+			expected: 30 * time.Second,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.policy.Delay(tc.retried))
+		})
+	}
+}
+
+func TestRetryDelayFunc(t *testing.T) {
+	policies := RetryPolicies{
+		TaskNotifyTransfer: {Backoff: BackoffConstant, BaseDelay: time.Minute},
+	}
+	retryDelay := policies.retryDelayFunc()
+
+	require.Equal(t, time.Minute, retryDelay(5, nil, asynq.NewTask(TaskNotifyTransfer, nil)))
+	require.Equal(t, 32*time.Second, retryDelay(5, nil, asynq.NewTask(TaskSendVerifyEmail, nil)))
+}
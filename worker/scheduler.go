@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// NewScheduler enqueues the periodic tasks, schedules mapping their type to a cronspec, e.g.
+// "0 3 * * *" for every night at 3am. Tasks with an empty cronspec are not scheduled. Every
+// instance runs a scheduler, a task being unique for an hour keeps them from enqueueing it
+// more than once.
+func NewScheduler(redisOpt asynq.RedisClientOpt, schedules map[string]string) (*asynq.Scheduler, error) {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{Logger: NewLogger()})
+	for taskType, cronspec := range schedules {
+		if cronspec == "" {
+			continue
+		}
+		_, err := scheduler.Register(cronspec, asynq.NewTask(taskType, nil),
+			asynq.Queue(QueueLow),
+			asynq.MaxRetry(1),
+			asynq.Unique(time.Hour),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q of %s: %w", cronspec, taskType, err)
+		}
+	}
+	return scheduler, nil
+}
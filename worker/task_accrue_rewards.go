@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskAccrueRewards = "task:accrue_rewards"
+
+type PayloadAccrueRewards struct {
+	TransferID int64 `json:"transfer_id"`
+}
+
+// NewAccrueRewardsTask builds the outbox row that accrues the cashback points of a transfer to its
+// payer. It is a db.RewardTaskFunc.
+func NewAccrueRewardsTask(transfer db.Transfer) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskAccrueRewards, &PayloadAccrueRewards{TransferID: transfer.ID}, QueueLow, 10)
+}
+
+// ProcessTaskAccrueRewards writes the points a transfer earns under the cashback program to the
+// reward ledger of the account it debited. A transfer earning no points writes nothing, and one
+// rewarded already is skipped, so that a retry never rewards it twice.
+func (processor *RedisTaskProcessor) ProcessTaskAccrueRewards(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadAccrueRewards
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transfer, err := processor.store.GetTransfer(ctx, payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+	// the accounts may be closed since, but the points still go to the payer
+	from, err := processor.store.GetAccountIncludeDeleted(ctx, transfer.FromAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	to, err := processor.store.GetAccountIncludeDeleted(ctx, transfer.ToAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	points := processor.rewards.Points(transfer, from, to)
+	if points == 0 {
+		log.Info().Str("type", task.Type()).Int64("transfer id", transfer.ID).Msg("transfer earns no reward points")
+		return nil
+	}
+	_, err = processor.store.CreateRewardEntry(ctx, db.CreateRewardEntryParams{
+		AccountID:  from.ID,
+		Kind:       db.RewardAccrual,
+		Points:     points,
+		TransferID: transfer.ID,
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		log.Info().Str("type", task.Type()).Int64("transfer id", transfer.ID).Msg("transfer rewarded already")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to accrue reward points: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("transfer id", transfer.ID).
+		Int64("account id", from.ID).Int64("points", points).Msg("processed task")
+	return nil
+}
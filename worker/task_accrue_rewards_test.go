@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/rewards"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskAccrueRewards(t *testing.T) {
+	program := rewards.Program{RateBps: 100, MinAmount: 500}
+	transfer := db.Transfer{ID: 7, FromAccountID: 1, ToAccountID: 2, Amount: 2500}
+	from := db.Account{ID: 1, Owner: "alice"}
+	to := db.Account{ID: 2, Owner: "bob"}
+
+	testCases := []struct {
+		name       string
+		to         db.Account
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			to:   to,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateRewardEntry(gomock.Any(), gomock.Eq(db.CreateRewardEntryParams{
+						AccountID:  from.ID,
+						Kind:       db.RewardAccrual,
+						Points:     25,
+						TransferID: transfer.ID,
+					})).
+					Times(1).
+					Return(db.RewardEntry{ID: 1}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "OwnAccount",
+			to:   db.Account{ID: 2, Owner: from.Owner},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateRewardEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RewardedAlready",
+			to:   to,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateRewardEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RewardEntry{}, db.ErrRecordNotFound)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "CreateError",
+			to:   to,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateRewardEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RewardEntry{}, errors.New("connection refused"))
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+			store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+			store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(tc.to.ID)).Times(1).Return(tc.to, nil)
+			tc.buildStubs(store)
+
+			outbox, err := NewAccrueRewardsTask(transfer)
+			require.NoError(t, err)
+			var payload PayloadAccrueRewards
+			require.NoError(t, json.Unmarshal(outbox.Payload, &payload))
+			require.Equal(t, transfer.ID, payload.TransferID)
+
+			processor := &RedisTaskProcessor{store: store, rewards: program}
+			err = processor.ProcessTaskAccrueRewards(context.Background(), asynq.NewTask(outbox.TaskType, outbox.Payload))
+			tc.checkErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskArchiveEntries = "task:archive_entries"
+
+// ProcessTaskArchiveEntries moves the entries older than ENTRY_RETENTION_YEARS to the archive.
+// A retention of 0 keeps every entry where it is.
+func (processor *RedisTaskProcessor) ProcessTaskArchiveEntries(ctx context.Context, task *asynq.Task) error {
+	if processor.entryRetentionYears <= 0 {
+		log.Info().Msg("entry retention is disabled, nothing to archive")
+		return nil
+	}
+
+	before := time.Now().AddDate(-processor.entryRetentionYears, 0, 0)
+	archived, err := processor.store.ArchiveEntriesBefore(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to archive entries after %d of them: %w", archived, err)
+	}
+	log.Info().Int64("archived", archived).Time("before", before).Msg("entries archived")
+	return nil
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskArchiveEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	processor := &RedisTaskProcessor{store: store, entryRetentionYears: 7}
+	task := asynq.NewTask(TaskArchiveEntries, nil)
+
+	cutoff := time.Now().AddDate(-7, 0, 0)
+	store.EXPECT().
+		ArchiveEntriesBefore(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, cutoff, before, time.Second)
+			return 42, nil
+		})
+	require.NoError(t, processor.ProcessTaskArchiveEntries(context.Background(), task))
+
+	store.EXPECT().ArchiveEntriesBefore(gomock.Any(), gomock.Any()).Times(1).Return(int64(10), errors.New("connection refused"))
+	require.Error(t, processor.ProcessTaskArchiveEntries(context.Background(), task))
+
+	processor.entryRetentionYears = 0
+	store.EXPECT().ArchiveEntriesBefore(gomock.Any(), gomock.Any()).Times(0)
+	require.NoError(t, processor.ProcessTaskArchiveEntries(context.Background(), task))
+}
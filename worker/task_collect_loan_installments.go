@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCollectLoanInstallments = "task:collect_loan_installments"
+
+// installmentBatchSize is how many loan installments a run collects at most, the next run taking
+// the rest.
+const installmentBatchSize = 100
+
+// ProcessTaskCollectLoanInstallments debits the loan installments due from the accounts of their
+// loans. An installment the account can't pay stays due for the next run to try again, and its
+// loan turns delinquent, which its borrower is notified of once.
+func (processor *RedisTaskProcessor) ProcessTaskCollectLoanInstallments(ctx context.Context, task *asynq.Task) error {
+	installments, err := processor.store.ListDueLoanInstallments(ctx, installmentBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due loan installments: %w", err)
+	}
+
+	failed, unpaid := 0, 0
+	var lastErr error
+	for _, installment := range installments {
+		_, err := processor.store.RepayLoanInstallmentTx(ctx, db.RepayLoanInstallmentTxParams{
+			ID:        installment.ID,
+			AlertTask: NewAccountAlertTask,
+		})
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrFundsHeld) {
+			unpaid++
+			err = processor.markLoanDelinquent(ctx, installment)
+		}
+		if err != nil && !errors.Is(err, db.ErrInstallmentPaid) {
+			log.Error().Err(err).Int64("loan installment id", installment.ID).Msg("failed to collect loan installment")
+			failed++
+			lastErr = err
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("installments", len(installments)).Int("unpaid", unpaid).Int("failed", failed).Msg("processed task")
+	if lastErr != nil {
+		return fmt.Errorf("failed to collect %d of %d loan installments: %w", failed, len(installments), lastErr)
+	}
+	return nil
+}
+
+// markLoanDelinquent marks the loan of an installment it couldn't collect delinquent, and notifies
+// the owner of its account, unless the loan was delinquent already.
+func (processor *RedisTaskProcessor) markLoanDelinquent(ctx context.Context, installment db.LoanInstallment) error {
+	loan, err := processor.store.GetLoan(ctx, installment.LoanID)
+	if err != nil {
+		return fmt.Errorf("failed to get loan: %w", err)
+	}
+	account, err := processor.store.GetAccountIncludeDeleted(ctx, loan.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	_, err = processor.store.MarkLoanDelinquentTx(ctx, db.MarkLoanDelinquentTxParams{
+		ID: loan.ID,
+		AfterMark: func(loan db.Loan) ([]db.CreateOutboxTaskParams, error) {
+			notify, err := NewSendNotificationTask(&PayloadSendNotification{
+				Username: account.Owner,
+				Kind:     notification.KindLoanDelinquent,
+				Data: map[string]string{
+					"loan_id":    strconv.FormatInt(loan.ID, 10),
+					"account_id": strconv.FormatInt(loan.AccountID, 10),
+					"amount":     money.Format(installment.Amount, loan.Currency),
+					"currency":   loan.Currency,
+					"due_date":   installment.DueAt.UTC().Format("2006-01-02"),
+				},
+			})
+			return []db.CreateOutboxTaskParams{notify}, err
+		},
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to mark loan delinquent: %w", err)
+	}
+	return nil
+}
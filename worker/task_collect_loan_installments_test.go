@@ -0,0 +1,158 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskCollectLoanInstallments(t *testing.T) {
+	dueAt := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	installments := []db.LoanInstallment{
+		{ID: 1, LoanID: 7, Number: 3, DueAt: dueAt, Amount: 8885, Status: db.InstallmentDue},
+		{ID: 2, LoanID: 8, Number: 1, DueAt: dueAt, Amount: 334, Status: db.InstallmentDue},
+	}
+	loan := db.Loan{ID: 7, AccountID: 42, Currency: util.USD, Status: db.LoanActive}
+	account := db.Account{ID: 42, Owner: util.RandomOwnerName()}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(installments, nil)
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(len(installments)).
+					DoAndReturn(func(_ context.Context, arg db.RepayLoanInstallmentTxParams) (db.RepayLoanInstallmentTxResult, error) {
+						require.Contains(t, []int64{installments[0].ID, installments[1].ID}, arg.ID)
+						require.NotNil(t, arg.AlertTask)
+						return db.RepayLoanInstallmentTxResult{}, nil
+					})
+				store.EXPECT().MarkLoanDelinquentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(installments[:1], nil)
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepayLoanInstallmentTxResult{}, db.ErrInsufficientFunds)
+				store.EXPECT().GetLoan(gomock.Any(), gomock.Eq(loan.ID)).Times(1).Return(loan, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					MarkLoanDelinquentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.MarkLoanDelinquentTxParams) (db.Loan, error) {
+						require.Equal(t, loan.ID, arg.ID)
+
+						delinquent := loan
+						delinquent.Status = db.LoanDelinquent
+						tasks, err := arg.AfterMark(delinquent)
+						require.NoError(t, err)
+						require.Len(t, tasks, 1)
+						require.Equal(t, TaskSendNotification, tasks[0].TaskType)
+						require.Contains(t, string(tasks[0].Payload), notification.KindLoanDelinquent)
+						require.Contains(t, string(tasks[0].Payload), account.Owner)
+						require.Contains(t, string(tasks[0].Payload), "2024-03-01")
+						return delinquent, nil
+					})
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyDelinquent",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(installments[:1], nil)
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepayLoanInstallmentTxResult{}, db.ErrAccountFrozen)
+				store.EXPECT().GetLoan(gomock.Any(), gomock.Any()).Times(1).Return(loan, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					MarkLoanDelinquentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Loan{}, db.ErrRecordNotFound)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyPaid",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(installments[:1], nil)
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepayLoanInstallmentTxResult{}, db.ErrInstallmentPaid)
+				store.EXPECT().MarkLoanDelinquentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "FailureDoesNotStopOthers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(installments, nil)
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepayLoanInstallmentTxResult{}, errors.New("connection refused"))
+				store.EXPECT().
+					RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepayLoanInstallmentTxResult{}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "failed to collect 1 of 2 loan installments")
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDueLoanInstallments(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+				store.EXPECT().RepayLoanInstallmentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			err := processor.ProcessTaskCollectLoanInstallments(context.Background(), asynq.NewTask(TaskCollectLoanInstallments, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCreatePartitions = "task:create_partitions"
+
+// ProcessTaskCreatePartitions creates the partitions of the current month and of the next
+// PARTITION_MONTHS_AHEAD ones, so that they exist before the first row of their month.
+func (processor *RedisTaskProcessor) ProcessTaskCreatePartitions(ctx context.Context, task *asynq.Task) error {
+	err := processor.store.CreatePartitions(ctx, time.Now(), processor.partitionMonthsAhead)
+	if err != nil {
+		return fmt.Errorf("failed to create partitions: %w", err)
+	}
+	log.Info().Int("months ahead", processor.partitionMonthsAhead).Msg("partitions created")
+	return nil
+}
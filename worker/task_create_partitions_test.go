@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskCreatePartitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	processor := &RedisTaskProcessor{store: store, partitionMonthsAhead: 2}
+	task := asynq.NewTask(TaskCreatePartitions, nil)
+
+	store.EXPECT().CreatePartitions(gomock.Any(), gomock.Any(), gomock.Eq(2)).Times(1).Return(nil)
+	require.NoError(t, processor.ProcessTaskCreatePartitions(context.Background(), task))
+
+	store.EXPECT().CreatePartitions(gomock.Any(), gomock.Any(), gomock.Eq(2)).Times(1).Return(errors.New("connection refused"))
+	require.Error(t, processor.ProcessTaskCreatePartitions(context.Background(), task))
+}
+
+func TestNewScheduler(t *testing.T) {
+	redisOpt := asynq.RedisClientOpt{Addr: "localhost:6379"}
+
+	_, err := NewScheduler(redisOpt, map[string]string{
+		TaskVerifyLedger:     "0 3 * * *",
+		TaskCreatePartitions: "",
+	})
+	require.NoError(t, err)
+
+	_, err = NewScheduler(redisOpt, map[string]string{TaskVerifyLedger: "every night"})
+	require.Error(t, err)
+}
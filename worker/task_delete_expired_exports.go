@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskDeleteExpiredExports = "task:delete_expired_exports"
+
+// ProcessTaskDeleteExpiredExports deletes the entry exports that expired, along with their files.
+func (processor *RedisTaskProcessor) ProcessTaskDeleteExpiredExports(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteExpiredEntryExports(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired entry exports: %w", err)
+	}
+	log.Info().Int64("deleted", deleted).Msg("expired entry exports deleted")
+	return nil
+}
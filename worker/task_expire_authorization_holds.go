@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExpireAuthorizationHolds = "task:expire_authorization_holds"
+
+// holdExpiryBatchSize is how many authorization holds a run expires at most, the next run taking
+// the rest.
+const holdExpiryBatchSize = 100
+
+// ProcessTaskExpireAuthorizationHolds releases the active authorization holds past their expiry,
+// their amount going back to the available balance of the payer. A hold the payee closed in the
+// meantime is skipped.
+func (processor *RedisTaskProcessor) ProcessTaskExpireAuthorizationHolds(ctx context.Context, task *asynq.Task) error {
+	holds, err := processor.store.ListExpiredAuthorizationHolds(ctx, holdExpiryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list expired authorization holds: %w", err)
+	}
+
+	failed := 0
+	var lastErr error
+	for _, hold := range holds {
+		_, err := processor.store.ReleaseAuthorizationHoldTx(ctx, db.ReleaseAuthorizationHoldTxParams{
+			ID:     hold.ID,
+			Status: db.AuthorizationHoldExpired,
+		})
+		if err != nil && !errors.Is(err, db.ErrAuthorizationHoldClosed) {
+			log.Error().Err(err).Int64("authorization hold id", hold.ID).Msg("failed to expire authorization hold")
+			failed++
+			lastErr = err
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("holds", len(holds)).Int("failed", failed).Msg("processed task")
+	if lastErr != nil {
+		return fmt.Errorf("failed to expire %d of %d authorization holds: %w", failed, len(holds), lastErr)
+	}
+	return nil
+}
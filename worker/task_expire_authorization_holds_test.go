@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskExpireAuthorizationHolds(t *testing.T) {
+	holds := []db.AuthorizationHold{
+		{ID: 1, AccountID: 7, ToAccountID: 8, Amount: 50, Status: db.AuthorizationHoldActive},
+		{ID: 2, AccountID: 7, ToAccountID: 9, Amount: 20, Status: db.AuthorizationHoldActive},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExpiredAuthorizationHolds(gomock.Any(), gomock.Any()).Times(1).Return(holds, nil)
+				for _, hold := range holds {
+					store.EXPECT().
+						ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Eq(db.ReleaseAuthorizationHoldTxParams{
+							ID:     hold.ID,
+							Status: db.AuthorizationHoldExpired,
+						})).
+						Times(1).
+						Return(db.AuthorizationHold{}, nil)
+				}
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyClosed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExpiredAuthorizationHolds(gomock.Any(), gomock.Any()).Times(1).Return(holds[:1], nil)
+				store.EXPECT().
+					ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuthorizationHold{}, db.ErrAuthorizationHoldClosed)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "FailureDoesNotStopOthers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExpiredAuthorizationHolds(gomock.Any(), gomock.Any()).Times(1).Return(holds, nil)
+				store.EXPECT().
+					ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuthorizationHold{}, errors.New("connection refused"))
+				store.EXPECT().
+					ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuthorizationHold{}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "failed to expire 1 of 2 authorization holds")
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExpiredAuthorizationHolds(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+				store.EXPECT().ReleaseAuthorizationHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			err := processor.ProcessTaskExpireAuthorizationHolds(context.Background(), asynq.NewTask(TaskExpireAuthorizationHolds, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
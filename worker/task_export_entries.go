@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/export"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/preferences"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExportEntries = "task:export_entries"
+
+// errExportFailed is what the owner of a failed export reads, the actual error only being logged.
+var errExportFailed = errors.New("the export could not be built, please try again")
+
+type PayloadExportEntries struct {
+	ExportID int64 `json:"export_id"`
+}
+
+// NewExportEntriesTask builds the outbox row that builds the file of an entry export. It goes to
+// the low queue, so that large exports never hold the workers of the emails.
+func NewExportEntriesTask(payload *PayloadExportEntries) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskExportEntries, payload, QueueLow, 3)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskExportEntries(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExportEntries
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	entryExport, err := processor.store.GetEntryExport(ctx, payload.ExportID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("entry export doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get entry export: %w", err)
+	}
+	if entryExport.Status != db.ExportPending {
+		log.Info().Str("type", task.Type()).Int64("export id", entryExport.ID).Msg("entry export already completed")
+		return nil
+	}
+
+	account, err := processor.store.GetAccount(ctx, entryExport.AccountID)
+	if err != nil {
+		return processor.failExport(ctx, entryExport, fmt.Errorf("failed to get account: %w", err),
+			errors.Is(err, db.ErrRecordNotFound))
+	}
+	// the dates of the file and of the notification are in the timezone of the owner
+	owner, err := processor.store.GetUser(ctx, entryExport.Owner)
+	if err != nil {
+		return processor.failExport(ctx, entryExport, fmt.Errorf("failed to get user: %w", err),
+			errors.Is(err, db.ErrRecordNotFound))
+	}
+	settings, err := preferences.Parse(owner.Preferences)
+	if err != nil {
+		return processor.failExport(ctx, entryExport, err, true)
+	}
+	location := settings.Location()
+
+	var file bytes.Buffer
+	entries, err := export.Write(ctx, processor.store, &file, entryExport.Format, export.Statement{
+		Account:  account,
+		From:     entryExport.FromTime,
+		To:       entryExport.ToTime,
+		At:       time.Now(),
+		Location: location,
+	})
+	if err != nil {
+		return processor.failExport(ctx, entryExport, fmt.Errorf("failed to export entries: %w", err),
+			errors.Is(err, export.ErrUnknownFormat))
+	}
+
+	notify, err := NewSendNotificationTask(&PayloadSendNotification{
+		Username: entryExport.Owner,
+		Kind:     notification.KindExportReady,
+		Data: map[string]string{
+			"export_id":  strconv.FormatInt(entryExport.ID, 10),
+			"account_id": strconv.FormatInt(entryExport.AccountID, 10),
+			"format":     entryExport.Format,
+			"entries":    strconv.FormatInt(entries, 10),
+			"expires_at": entryExport.ExpiresAt.In(location).Format(time.RFC1123),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build notification task: %w", err)
+	}
+	_, err = processor.store.FinishEntryExportTx(ctx, db.FinishEntryExportTxParams{
+		ID:          entryExport.ID,
+		Content:     file.Bytes(),
+		Entries:     entries,
+		OutboxTasks: []db.CreateOutboxTaskParams{notify},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// another try completed it in the meantime
+			return nil
+		}
+		return fmt.Errorf("failed to store entry export: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("export id", entryExport.ID).Str("format", entryExport.Format).
+		Int64("entries", entries).Int("bytes", file.Len()).Msg("processed task")
+	return nil
+}
+
+// failExport marks an export failed when its task won't be tried again, because the error is
+// final or the retries are used up, so that its owner doesn't wait for it forever. It returns
+// the error of the task.
+func (processor *RedisTaskProcessor) failExport(ctx context.Context, entryExport db.EntryExport, err error, final bool) error {
+	retried, ok := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	if !final && ok && retried < maxRetry {
+		return err
+	}
+
+	log.Error().Err(err).Int64("export id", entryExport.ID).Msg("entry export failed")
+	_, failErr := processor.store.FailEntryExport(ctx, db.FailEntryExportParams{
+		ID:    entryExport.ID,
+		Error: errExportFailed.Error(),
+	})
+	if failErr != nil && !errors.Is(failErr, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to mark entry export failed: %w", failErr)
+	}
+	return fmt.Errorf("%s: %w", err, asynq.SkipRetry)
+}
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskExportEntries(t *testing.T) {
+	account := db.Account{ID: 7, Owner: "tom", Balance: 40, Currency: "USD"}
+	entryExport := db.EntryExport{
+		ID:        3,
+		Owner:     account.Owner,
+		AccountID: account.ID,
+		Format:    "csv",
+		FromTime:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ToTime:    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:    db.ExportPending,
+		ExpiresAt: time.Date(2027, 1, 8, 0, 0, 0, 0, time.UTC),
+	}
+	owner := db.User{Username: account.Owner, Preferences: []byte(`{"timezone": "Asia/Taipei"}`)}
+	entries := []db.ListExportEntriesRow{
+		{ID: 1, AccountID: account.ID, Amount: 50, CreatedAt: entryExport.FromTime, Tags: []string{}},
+		{ID: 2, AccountID: account.ID, Amount: -10, CreatedAt: entryExport.FromTime, Tags: []string{}},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(entryExport.ID)).Times(1).Return(entryExport, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return(owner, nil)
+				store.EXPECT().ListExportEntries(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+				store.EXPECT().
+					FinishEntryExportTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.FinishEntryExportTxParams) (db.EntryExport, error) {
+						require.Equal(t, entryExport.ID, arg.ID)
+						require.Equal(t, int64(2), arg.Entries)
+						require.Equal(t, "id,date,amount,currency,direction,category,tags\n"+
+							"1,2026-01-01T08:00:00+08:00,0.50,USD,credit,,\n"+
+							"2,2026-01-01T08:00:00+08:00,-0.10,USD,debit,,\n", string(arg.Content))
+
+						require.Len(t, arg.OutboxTasks, 1)
+						var payload PayloadSendNotification
+						require.NoError(t, json.Unmarshal(arg.OutboxTasks[0].Payload, &payload))
+						require.Equal(t, account.Owner, payload.Username)
+						require.Equal(t, "Fri, 08 Jan 2027 08:00:00 CST", payload.Data["expires_at"])
+						msg, err := notification.Render(payload.Kind, payload.Data)
+						require.NoError(t, err)
+						require.Contains(t, msg.Body, "The csv export of the 2 entries of your account #7 is ready")
+						require.Contains(t, msg.Body, "/exports/3/download")
+						return entryExport, nil
+					})
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyCompleted",
+			buildStubs: func(store *mockdb.MockStore) {
+				completed := entryExport
+				completed.Status = db.ExportReady
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(entryExport.ID)).Times(1).Return(completed, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().FinishEntryExportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ExportNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Any()).Times(1).Return(db.EntryExport{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+			},
+		},
+		{
+			// outside of asynq the task has no retries left, so the export fails for good
+			name: "ListEntriesFailed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntryExport(gomock.Any(), gomock.Eq(entryExport.ID)).Times(1).Return(entryExport, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(account.Owner)).Times(1).Return(owner, nil)
+				store.EXPECT().ListExportEntries(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+				store.EXPECT().FinishEntryExportTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FailEntryExport(gomock.Any(), gomock.Eq(db.FailEntryExportParams{ID: entryExport.ID, Error: errExportFailed.Error()})).
+					Times(1).
+					Return(db.EntryExport{}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			payload, err := json.Marshal(PayloadExportEntries{ExportID: entryExport.ID})
+			require.NoError(t, err)
+
+			err = processor.ProcessTaskExportEntries(context.Background(), asynq.NewTask(TaskExportEntries, payload))
+			tc.checkErr(t, err)
+		})
+	}
+}
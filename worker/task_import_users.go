@@ -0,0 +1,165 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/screening"
+	"github.com/backendmaster/simple_bank/userimport"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskImportUsers = "task:import_users"
+
+type PayloadImportUsers struct {
+	ImportID int64 `json:"import_id"`
+}
+
+// NewImportUsersTask builds the outbox row that imports the rows of a user import. It goes to the
+// low queue, hashing the passwords of a large file taking a while. A retry resumes after the last
+// row written, so it is tried more than an export.
+func NewImportUsersTask(payload *PayloadImportUsers) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskImportUsers, payload, QueueLow, 10)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskImportUsers(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadImportUsers
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	userImport, err := processor.store.GetUserImport(ctx, payload.ImportID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user import doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user import: %w", err)
+	}
+	if userImport.Status != db.UserImportPending {
+		log.Info().Str("type", task.Type()).Int64("import id", userImport.ID).Msg("user import already completed")
+		return nil
+	}
+
+	content, err := processor.store.GetUserImportFile(ctx, userImport.ID)
+	if err != nil {
+		return processor.failImport(ctx, userImport, fmt.Errorf("failed to get user import file: %w", err),
+			errors.Is(err, db.ErrRecordNotFound))
+	}
+	// the rows written by an earlier try are already imported or failed
+	lastLine, err := processor.store.GetLastUserImportLine(ctx, userImport.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get last line of user import: %w", err)
+	}
+
+	screener := screening.NewDenylist(processor.store)
+	err = userimport.Read(userImport.Format, content, func(line int64, row userimport.Row, err error) error {
+		if line <= lastLine {
+			return nil
+		}
+		if err == nil {
+			err = row.Validate()
+		}
+		if err != nil {
+			return processor.failImportRow(ctx, userImport.ID, line, row.Username, err)
+		}
+		return processor.importUser(ctx, userImport.ID, line, row, screener)
+	})
+	if err != nil {
+		return processor.failImport(ctx, userImport, err,
+			errors.Is(err, userimport.ErrUnknownFormat) || errors.Is(err, userimport.ErrInvalidHeader))
+	}
+
+	userImport, err = processor.store.FinishUserImport(ctx, userImport.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// another try completed it in the meantime
+			return nil
+		}
+		return fmt.Errorf("failed to finish user import: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("import id", userImport.ID).Int64("rows", userImport.TotalRows).
+		Int64("imported", userImport.ImportedRows).Int64("failed", userImport.FailedRows).Msg("processed task")
+	return nil
+}
+
+// importUser creates the user of a valid row, like a signup, and writes the row of the report.
+// A user the database refuses, e.g. one whose username is taken, only fails its row.
+func (processor *RedisTaskProcessor) importUser(ctx context.Context, importID, line int64, row userimport.Row, screener *screening.Denylist) error {
+	hashedPassword, err := row.Hash()
+	if err != nil {
+		return err
+	}
+
+	_, err = processor.store.ImportUserTx(ctx, db.ImportUserTxParams{
+		ImportID: importID,
+		Line:     line,
+		CreateUserTxParams: db.CreateUserTxParams{
+			CreateUserParams: db.CreateUserParams{
+				Username:       row.Username,
+				HashedPassword: hashedPassword,
+				FullName:       row.FullName,
+				Email:          row.Email,
+				TenantID:       pgtype.Text{String: row.Tenant, Valid: row.Tenant != ""},
+			},
+			AfterCreate: AfterCreateUser,
+			Screen:      screener.ScreenUser,
+		},
+	})
+	switch db.ErrorCode(err) {
+	case db.UniqueViolation:
+		return processor.failImportRow(ctx, importID, line, row.Username, errors.New("the username or the email is already taken"))
+	case db.ForeignKeyViolation:
+		// the only foreign key of a user is its tenant
+		return processor.failImportRow(ctx, importID, line, row.Username, fmt.Errorf("unknown tenant %q", row.Tenant))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import user of line %d: %w", line, err)
+	}
+	return nil
+}
+
+// failImportRow writes the row of the report of a row that failed with rowErr.
+func (processor *RedisTaskProcessor) failImportRow(ctx context.Context, importID, line int64, username string, rowErr error) error {
+	_, err := processor.store.CreateUserImportRow(ctx, db.CreateUserImportRowParams{
+		ImportID: importID,
+		Line:     line,
+		Username: username,
+		Status:   db.UserImportRowFailed,
+		Error:    rowErr.Error(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write row of user import: %w", err)
+	}
+	return nil
+}
+
+// failImport marks an import failed when its task won't be tried again, because the error is
+// final, e.g. a file of an unknown format, or the retries are used up, telling the admin why.
+// It returns the error of the task.
+func (processor *RedisTaskProcessor) failImport(ctx context.Context, userImport db.UserImport, err error, final bool) error {
+	retried, ok := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	if !final && ok && retried < maxRetry {
+		return err
+	}
+
+	log.Error().Err(err).Int64("import id", userImport.ID).Msg("user import failed")
+	message := "the import could not be completed, please try again"
+	if final {
+		message = err.Error()
+	}
+	_, failErr := processor.store.FailUserImport(ctx, db.FailUserImportParams{
+		ID:    userImport.ID,
+		Error: message,
+	})
+	if failErr != nil && !errors.Is(failErr, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to mark user import failed: %w", failErr)
+	}
+	return fmt.Errorf("%s: %w", err, asynq.SkipRetry)
+}
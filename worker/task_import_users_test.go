@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskImportUsers(t *testing.T) {
+	hashedPassword, err := util.HashedPassword("secret123")
+	require.NoError(t, err)
+	userImport := db.UserImport{ID: 5, CreatedBy: "admin", Format: "ndjson", Status: db.UserImportPending}
+	content := []byte(`{"username": "alice", "full_name": "Alice Liddell", "email": "alice@example.com", "hashed_password": "` + hashedPassword + `"}
+{"username": "bob", "full_name": "Bob Builder", "email": "not an email", "hashed_password": "` + hashedPassword + `"}
+{"username": "carol", "full_name": "Carol Danvers", "email": "carol@example.com", "hashed_password": "` + hashedPassword + `", "tenant": "acme"}
+`)
+	completed := userImport
+	completed.Status = db.UserImportCompleted
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(userImport, nil)
+				store.EXPECT().GetUserImportFile(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(content, nil)
+				store.EXPECT().GetLastUserImportLine(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(int64(0), nil)
+				gomock.InOrder(
+					store.EXPECT().
+						ImportUserTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ context.Context, arg db.ImportUserTxParams) (db.UserImportRow, error) {
+							require.Equal(t, int64(1), arg.Line)
+							require.Equal(t, "alice", arg.Username)
+							require.Equal(t, hashedPassword, arg.HashedPassword)
+							require.False(t, arg.TenantID.Valid)
+							require.NotNil(t, arg.AfterCreate)
+							return db.UserImportRow{ImportID: userImport.ID, Line: 1, Username: "alice", Status: db.UserImportRowImported}, nil
+						}),
+					store.EXPECT().
+						CreateUserImportRow(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ context.Context, arg db.CreateUserImportRowParams) (db.UserImportRow, error) {
+							require.Equal(t, int64(2), arg.Line)
+							require.Equal(t, db.UserImportRowFailed, arg.Status)
+							require.Contains(t, arg.Error, "invalid email")
+							return db.UserImportRow{}, nil
+						}),
+					store.EXPECT().
+						ImportUserTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(_ context.Context, arg db.ImportUserTxParams) (db.UserImportRow, error) {
+							require.Equal(t, "acme", arg.TenantID.String)
+							return db.UserImportRow{}, &pgconn.PgError{Code: db.ForeignKeyViolation}
+						}),
+					store.EXPECT().
+						CreateUserImportRow(gomock.Any(), gomock.Eq(db.CreateUserImportRowParams{
+							ImportID: userImport.ID,
+							Line:     3,
+							Username: "carol",
+							Status:   db.UserImportRowFailed,
+							Error:    `unknown tenant "acme"`,
+						})).
+						Times(1).
+						Return(db.UserImportRow{}, nil),
+				)
+				store.EXPECT().FinishUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(completed, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ResumesAfterLastLine",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(userImport, nil)
+				store.EXPECT().GetUserImportFile(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(content, nil)
+				store.EXPECT().GetLastUserImportLine(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(int64(2), nil)
+				store.EXPECT().
+					ImportUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ImportUserTxParams) (db.UserImportRow, error) {
+						require.Equal(t, int64(3), arg.Line)
+						return db.UserImportRow{}, nil
+					})
+				store.EXPECT().CreateUserImportRow(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().FinishUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(completed, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyCompleted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(completed, nil)
+				store.EXPECT().GetUserImportFile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InvalidHeader",
+			buildStubs: func(store *mockdb.MockStore) {
+				csvImport := userImport
+				csvImport.Format = "csv"
+				store.EXPECT().GetUserImport(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(csvImport, nil)
+				store.EXPECT().GetUserImportFile(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return([]byte("login,email\nalice,alice@example.com\n"), nil)
+				store.EXPECT().GetLastUserImportLine(gomock.Any(), gomock.Eq(userImport.ID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().ImportUserTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FailUserImport(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.FailUserImportParams) (db.UserImport, error) {
+						require.Equal(t, userImport.ID, arg.ID)
+						require.Contains(t, arg.Error, `unknown or repeated column "login"`)
+						return db.UserImport{}, nil
+					})
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			payload, err := json.Marshal(PayloadImportUsers{ImportID: userImport.ID})
+			require.NoError(t, err)
+
+			err = processor.ProcessTaskImportUsers(context.Background(), asynq.NewTask(TaskImportUsers, payload))
+			tc.checkErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskNotifyTransfer = "task:notify_transfer"
+
+type PayloadNotifyTransfer struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+}
+
+// NewNotifyTransferTask builds the outbox row that tells the owners of both accounts about a transfer.
+func NewNotifyTransferTask(payload *PayloadNotifyTransfer) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskNotifyTransfer, payload, QueueDefault, 5)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskNotifyTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadNotifyTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	fromAccount, err := processor.store.GetAccount(ctx, payload.FromAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	toAccount, err := processor.store.GetAccount(ctx, payload.ToAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, fromAccount.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	// the sender gets a receipt by email, the receiver a notification on the channels they chose
+	msg, err := mail.Render(mail.TemplateNotifyTransfer, mail.NotifyTransferData{
+		FullName:       user.FullName,
+		AccountID:      fromAccount.ID,
+		CounterpartyID: toAccount.ID,
+		Amount:         payload.Amount,
+		Currency:       fromAccount.Currency,
+		Brand:          brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	// a retry after the notification failed emails the sender again, which is better than telling no one
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send transfer email: %w", err)
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	dedupKey := ""
+	if taskID != "" {
+		dedupKey = taskID + ":" + notification.KindTransferReceived
+	}
+	err = processor.notifier.Notify(ctx, dedupKey, toAccount.Owner, notification.KindTransferReceived, map[string]string{
+		"from_account_id": strconv.FormatInt(fromAccount.ID, 10),
+		"to_account_id":   strconv.FormatInt(toAccount.ID, 10),
+		"amount":          money.Format(payload.Amount, toAccount.Currency),
+		"currency":        toAccount.Currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to notify receiver: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("from owner", fromAccount.Owner).Str("to owner", toAccount.Owner).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskQualifyReferrals = "task:qualify_referrals"
+
+// referralBatchSize is how many pending referrals a run reads at a time.
+const referralBatchSize = 100
+
+// ProcessTaskQualifyReferrals goes through the pending referrals, paying the bonuses of those
+// whose referee made a qualifying transfer, and rejecting those whose window closed without one.
+// A referral that qualified but couldn't be paid, e.g. as its referrer has no account yet, is
+// tried again by the next run, until its window closes.
+func (processor *RedisTaskProcessor) ProcessTaskQualifyReferrals(ctx context.Context, task *asynq.Task) error {
+	if processor.referrals.Bonus <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	total, rewarded, rejected, failed := 0, 0, 0, 0
+	var lastErr error
+	var afterID int64
+	for {
+		referrals, err := processor.store.ListPendingReferrals(ctx, db.ListPendingReferralsParams{
+			AfterID: afterID,
+			Limit:   referralBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list pending referrals: %w", err)
+		}
+		for _, referral := range referrals {
+			status, err := processor.qualifyReferral(ctx, referral, now)
+			switch {
+			case err != nil:
+				log.Error().Err(err).Int64("referral id", referral.ID).Msg("failed to qualify referral")
+				failed++
+				lastErr = err
+			case status == db.ReferralRewarded:
+				rewarded++
+			case status == db.ReferralRejected:
+				rejected++
+			}
+		}
+		total += len(referrals)
+		if len(referrals) < referralBatchSize {
+			break
+		}
+		afterID = referrals[len(referrals)-1].ID
+	}
+
+	log.Info().Str("type", task.Type()).Int("referrals", total).Int("rewarded", rewarded).Int("rejected", rejected).Int("failed", failed).Msg("processed task")
+	if lastErr != nil {
+		return fmt.Errorf("failed to qualify %d of %d referrals: %w", failed, total, lastErr)
+	}
+	return nil
+}
+
+// qualifyReferral pays the bonuses of a referral that qualified, or rejects it once its window
+// closed, returning the status it was left in.
+func (processor *RedisTaskProcessor) qualifyReferral(ctx context.Context, referral db.Referral, now time.Time) (string, error) {
+	expired := !now.Before(processor.referrals.Deadline(referral))
+
+	transfer, err := processor.store.GetFirstQualifyingTransfer(ctx, processor.referrals.QualifyingTransfer(referral))
+	if errors.Is(err, db.ErrRecordNotFound) {
+		if !expired {
+			return db.ReferralPending, nil
+		}
+		return processor.rejectReferral(ctx, referral, "no qualifying transfer within the window")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get qualifying transfer: %w", err)
+	}
+
+	result, err := processor.store.RewardReferralTx(ctx, db.RewardReferralTxParams{
+		ID:             referral.ID,
+		Transfer:       transfer,
+		Bonus:          processor.referrals.Bonus,
+		MaxPerReferrer: processor.referrals.MaxPerReferrer,
+		AlertTask:      NewAccountAlertTask,
+	})
+	switch {
+	case errors.Is(err, db.ErrReferralClosed):
+		return "", nil
+	case errors.Is(err, db.ErrTransfersBlocked):
+		// the referral stays pending, even past its window, until the transfers are unblocked
+		return db.ReferralPending, nil
+	case err != nil && expired:
+		// the bonuses couldn't be paid in time, e.g. as an account is frozen
+		return processor.rejectReferral(ctx, referral, err.Error())
+	case err != nil:
+		return "", fmt.Errorf("failed to reward referral: %w", err)
+	}
+	return result.Referral.Status, nil
+}
+
+func (processor *RedisTaskProcessor) rejectReferral(ctx context.Context, referral db.Referral, reason string) (string, error) {
+	_, err := processor.store.CloseReferral(ctx, db.CloseReferralParams{
+		ID:     referral.ID,
+		Status: db.ReferralRejected,
+		Reason: reason,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to reject referral: %w", err)
+	}
+	return db.ReferralRejected, nil
+}
@@ -0,0 +1,184 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/referral"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskQualifyReferrals(t *testing.T) {
+	program := referral.Program{Bonus: 1000, MinAmount: 500, Window: 30 * 24 * time.Hour, MaxPerReferrer: 5}
+	open := db.Referral{
+		ID:        1,
+		Referrer:  util.RandomOwnerName(),
+		Referee:   util.RandomOwnerName(),
+		Status:    db.ReferralPending,
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	expired := open
+	expired.ID = 2
+	expired.CreatedAt = time.Now().Add(-program.Window - time.Hour)
+	transfer := db.Transfer{ID: 9, FromAccountID: 3, ToAccountID: 4, Amount: 500}
+
+	testCases := []struct {
+		name       string
+		program    referral.Program
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name:    "Rewarded",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPendingReferrals(gomock.Any(), gomock.Eq(db.ListPendingReferralsParams{Limit: referralBatchSize})).
+					Times(1).
+					Return([]db.Referral{open}, nil)
+				store.EXPECT().
+					GetFirstQualifyingTransfer(gomock.Any(), gomock.Eq(program.QualifyingTransfer(open))).
+					Times(1).
+					Return(transfer, nil)
+				store.EXPECT().
+					RewardReferralTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RewardReferralTxParams) (db.RewardReferralTxResult, error) {
+						require.Equal(t, open.ID, arg.ID)
+						require.Equal(t, transfer, arg.Transfer)
+						require.Equal(t, program.Bonus, arg.Bonus)
+						require.Equal(t, program.MaxPerReferrer, arg.MaxPerReferrer)
+						require.NotNil(t, arg.AlertTask)
+						return db.RewardReferralTxResult{Referral: db.Referral{ID: open.ID, Status: db.ReferralRewarded}}, nil
+					})
+				store.EXPECT().CloseReferral(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "NotQualifiedYet",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return([]db.Referral{open}, nil)
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.Transfer{}, db.ErrRecordNotFound)
+				store.EXPECT().RewardReferralTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CloseReferral(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "Expired",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return([]db.Referral{expired}, nil)
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.Transfer{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CloseReferral(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseReferralParams) (db.Referral, error) {
+						require.Equal(t, expired.ID, arg.ID)
+						require.Equal(t, db.ReferralRejected, arg.Status)
+						require.NotEmpty(t, arg.Reason)
+						return db.Referral{}, nil
+					})
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "UnpaidUntilExpired",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return([]db.Referral{open, expired}, nil)
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(2).Return(transfer, nil)
+				store.EXPECT().RewardReferralTx(gomock.Any(), gomock.Any()).Times(2).Return(db.RewardReferralTxResult{}, db.ErrNoReferrerAccount)
+				store.EXPECT().
+					CloseReferral(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CloseReferralParams) (db.Referral, error) {
+						require.Equal(t, expired.ID, arg.ID)
+						require.Equal(t, db.ErrNoReferrerAccount.Error(), arg.Reason)
+						return db.Referral{}, nil
+					})
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, db.ErrNoReferrerAccount)
+				require.ErrorContains(t, err, "failed to qualify 1 of 2 referrals")
+			},
+		},
+		{
+			// an expired referral isn't rejected for the transfers being blocked
+			name:    "TransfersBlocked",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return([]db.Referral{expired}, nil)
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().RewardReferralTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RewardReferralTxResult{}, db.ErrTransfersBlocked)
+				store.EXPECT().CloseReferral(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "AlreadyClosed",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return([]db.Referral{open}, nil)
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(1).Return(transfer, nil)
+				store.EXPECT().RewardReferralTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RewardReferralTxResult{}, db.ErrReferralClosed)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "ProgramOff",
+			program: referral.Program{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:    "ListError",
+			program: program,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPendingReferrals(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("connection refused"))
+				store.EXPECT().GetFirstQualifyingTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store, referrals: tc.program}
+			err := processor.ProcessTaskQualifyReferrals(context.Background(), asynq.NewTask(TaskQualifyReferrals, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
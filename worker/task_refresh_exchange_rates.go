@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskRefreshExchangeRates = "task:refresh_exchange_rates"
+
+// ProcessTaskRefreshExchangeRates fetches the latest exchange rates of the FX provider and stores
+// them. Whether the fetch worked or not, it then checks when the stored rates were fetched, and
+// alerts once they are older than FX_RATE_MAX_AGE, past which the converter refuses them.
+func (processor *RedisTaskProcessor) ProcessTaskRefreshExchangeRates(ctx context.Context, task *asynq.Task) error {
+	refreshErr := processor.refreshExchangeRates(ctx)
+
+	stored, err := processor.store.ListExchangeRates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+	var fetchedAt time.Time
+	for _, rate := range stored {
+		if rate.FetchedAt.After(fetchedAt) {
+			fetchedAt = rate.FetchedAt
+		}
+	}
+	age := time.Since(fetchedAt)
+	stale := age > processor.fxRateMaxAge
+	metrics.ObserveExchangeRates(fetchedAt, stale)
+	if stale {
+		log.Error().Err(refreshErr).Time("fetched at", fetchedAt).Dur("max age", processor.fxRateMaxAge).
+			Msg("exchange rates are stale, transfers between currencies are refused")
+	}
+
+	if refreshErr != nil {
+		return refreshErr
+	}
+	log.Info().Str("type", task.Type()).Int("rates", len(stored)).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) refreshExchangeRates(ctx context.Context) error {
+	rates, err := processor.fxProvider.Latest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+
+	quotes := make([]string, 0, len(rates.Nanos))
+	for quote := range rates.Nanos {
+		quotes = append(quotes, quote)
+	}
+	// in the same order every time, so that two refreshes lock the rows in the same order
+	sort.Strings(quotes)
+	arg := make([]db.UpsertExchangeRateParams, len(quotes))
+	for i, quote := range quotes {
+		arg[i] = db.UpsertExchangeRateParams{
+			BaseCurrency:  rates.Base,
+			QuoteCurrency: quote,
+			RateNanos:     rates.Nanos[quote],
+			Source:        rates.Source,
+			PublishedAt:   rates.PublishedAt,
+		}
+	}
+	if _, err := processor.store.SaveExchangeRatesTx(ctx, arg); err != nil {
+		return fmt.Errorf("failed to save exchange rates: %w", err)
+	}
+	return nil
+}
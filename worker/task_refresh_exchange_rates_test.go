@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fx"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns the same rates, or error, every time.
+type fakeProvider struct {
+	rates fx.Rates
+	err   error
+}
+
+func (provider fakeProvider) Latest(ctx context.Context) (fx.Rates, error) {
+	return provider.rates, provider.err
+}
+
+func TestProcessTaskRefreshExchangeRates(t *testing.T) {
+	rates := fx.Rates{
+		Source:      fx.ProviderECB,
+		Base:        util.EUR,
+		PublishedAt: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		Nanos:       map[string]int64{util.USD: 1_082_300_000, util.CAD: 1_486_200_000},
+	}
+	stored := []db.ExchangeRate{
+		{BaseCurrency: util.EUR, QuoteCurrency: util.CAD, RateNanos: 1_486_200_000, FetchedAt: time.Now()},
+		{BaseCurrency: util.EUR, QuoteCurrency: util.USD, RateNanos: 1_082_300_000, FetchedAt: time.Now()},
+	}
+
+	testCases := []struct {
+		name       string
+		provider   fx.Provider
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name:     "OK",
+			provider: fakeProvider{rates: rates},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SaveExchangeRatesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg []db.UpsertExchangeRateParams) ([]db.ExchangeRate, error) {
+						// sorted by quote currency
+						require.Equal(t, []db.UpsertExchangeRateParams{
+							{BaseCurrency: util.EUR, QuoteCurrency: util.CAD, RateNanos: 1_486_200_000, Source: fx.ProviderECB, PublishedAt: rates.PublishedAt},
+							{BaseCurrency: util.EUR, QuoteCurrency: util.USD, RateNanos: 1_082_300_000, Source: fx.ProviderECB, PublishedAt: rates.PublishedAt},
+						}, arg)
+						return stored, nil
+					})
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(stored, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "ProviderDown",
+			provider: fakeProvider{err: errors.New("connection refused")},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SaveExchangeRatesTx(gomock.Any(), gomock.Any()).Times(0)
+				// still checked, to alert once the rates went stale
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(stored, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "connection refused")
+			},
+		},
+		{
+			name:     "SaveFailed",
+			provider: fakeProvider{rates: rates},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SaveExchangeRatesTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().ListExchangeRates(gomock.Any()).Times(1).Return(nil, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store, fxProvider: tc.provider, fxRateMaxAge: fx.DefaultMaxAge}
+			err := processor.ProcessTaskRefreshExchangeRates(context.Background(), asynq.NewTask(TaskRefreshExchangeRates, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendDeviceOTP = "task:send_device_otp"
+
+type PayloadSendDeviceOTP struct {
+	DeviceID int64  `json:"device_id"`
+	Code     string `json:"code"`
+}
+
+// NewSendDeviceOTPTask builds the outbox row that emails the one-time code trusting a device to
+// its user. Like the code of a transfer, it can't be sent again once expired.
+func NewSendDeviceOTPTask(payload *PayloadSendDeviceOTP) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendDeviceOTP, payload, QueueCritical, 3)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendDeviceOTP(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendDeviceOTP
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	device, err := processor.store.GetDevice(ctx, payload.DeviceID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("device doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get device: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, device.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.TemplateDeviceOTP, mail.DeviceOTPData{
+		FullName:     user.FullName,
+		Code:         payload.Code,
+		DeviceName:   device.Name,
+		ValidMinutes: int(fraud.OTPValidity.Minutes()),
+		Brand:        brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send device code email: %w", err)
+	}
+
+	// the payload holds the code, which must not end up in the logs
+	log.Info().Str("type", task.Type()).Int64("device id", device.ID).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskSendDeviceOTP(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+	}
+	device := db.Device{
+		ID:       util.RandomInt(1, 1000),
+		Username: user.Username,
+		Name:     "Pixel 8",
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent []mail.Message, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Eq(device.ID)).Times(1).Return(device, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(db.Tenant{ID: util.DefaultTenant, Name: "Simple Bank"}, nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.NoError(t, err)
+				require.Len(t, sent, 1)
+				require.Equal(t, []string{user.Email}, sent[0].To)
+				require.Equal(t, "Your code to trust a new device", sent[0].Subject)
+				require.Contains(t, sent[0].Content, "<strong>123456</strong>")
+				require.Contains(t, sent[0].Content, `on the device "Pixel 8"`)
+				require.Contains(t, sent[0].Content, "valid for 10 minutes")
+			},
+		},
+		{
+			name: "DeviceNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDevice(gomock.Any(), gomock.Any()).Times(1).Return(db.Device{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+				require.Empty(t, sent)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			sender := &fakeEmailSender{}
+			processor := &RedisTaskProcessor{store: store, mailer: sender}
+
+			payload, err := json.Marshal(PayloadSendDeviceOTP{DeviceID: device.ID, Code: "123456"})
+			require.NoError(t, err)
+			err = processor.ProcessTaskSendDeviceOTP(context.Background(), asynq.NewTask(TaskSendDeviceOTP, payload))
+			tc.checkSent(t, sender.sent, err)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendNotification = "task:send_notification"
+
+type PayloadSendNotification struct {
+	Username string            `json:"username"`
+	Kind     string            `json:"kind"`
+	Data     map[string]string `json:"data"`
+}
+
+// NewSendNotificationTask builds the outbox row that sends a notification of the notification package.
+func NewSendNotificationTask(payload *PayloadSendNotification) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendNotification, payload, QueueDefault, 5)
+}
+
+// NewAccountAlertTask builds the outbox row that notifies the owner of an account about an alert
+// set off by a transfer. It is the db.AlertTaskFunc of TransferTx.
+func NewAccountAlertTask(alert db.TriggeredAlert) (db.CreateOutboxTaskParams, error) {
+	var kind string
+	switch alert.Rule {
+	case db.AlertLowBalance:
+		kind = notification.KindLowBalance
+	case db.AlertLargeTransaction:
+		kind = notification.KindLargeTransaction
+	default:
+		return db.CreateOutboxTaskParams{}, fmt.Errorf("unknown alert rule %q", alert.Rule)
+	}
+
+	return NewSendNotificationTask(&PayloadSendNotification{
+		Username: alert.Account.Owner,
+		Kind:     kind,
+		Data: map[string]string{
+			"account_id": strconv.FormatInt(alert.Account.ID, 10),
+			"balance":    money.Format(alert.Account.Balance, alert.Account.Currency),
+			"currency":   alert.Account.Currency,
+			"amount":     money.Format(alert.Amount, alert.Account.Currency),
+			"threshold":  money.Format(alert.Threshold, alert.Account.Currency),
+		},
+	})
+}
+
+// NewBalanceAdjustedTask builds the outbox row that tells the owner of an account an admin adjusted
+// its balance, and why in the words of reason.
+func NewBalanceAdjustedTask(result db.AdjustBalanceTxResult, reason string) (db.CreateOutboxTaskParams, error) {
+	return NewSendNotificationTask(&PayloadSendNotification{
+		Username: result.Account.Owner,
+		Kind:     notification.KindBalanceAdjusted,
+		Data: map[string]string{
+			"account_id": strconv.FormatInt(result.Account.ID, 10),
+			"balance":    money.Format(result.Account.Balance, result.Account.Currency),
+			"currency":   result.Account.Currency,
+			"amount":     signedAmount(result.Entry.Amount, result.Account.Currency),
+			"reason":     reason,
+		},
+	})
+}
+
+// NewSuspiciousLoginTask builds the outbox row that warns a user of a suspicious login, with the
+// link to report it at reportURL carrying the id of alert and the code of the link.
+func NewSuspiciousLoginTask(alert db.LoginAlert, reportURL, code string) (db.CreateOutboxTaskParams, error) {
+	link := reportURL + "?" + url.Values{
+		"id":   {strconv.FormatInt(alert.ID, 10)},
+		"code": {code},
+	}.Encode()
+
+	return NewSendNotificationTask(&PayloadSendNotification{
+		Username: alert.Username,
+		Kind:     notification.KindSuspiciousLogin,
+		Data: map[string]string{
+			"client_ip":  alert.ClientIp,
+			"user_agent": alert.UserAgent,
+			"country":    alert.Country,
+			"report_url": link,
+		},
+	})
+}
+
+// signedAmount writes amount in units of currency with its sign, so that a credit reads +0.10.
+func signedAmount(amount int64, currency string) string {
+	if amount > 0 {
+		return "+" + money.Format(amount, currency)
+	}
+	return money.Format(amount, currency)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendNotification(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	// the id of the task stays the same across its retries
+	taskID, _ := asynq.GetTaskID(ctx)
+	err := processor.notifier.Notify(ctx, taskID, payload.Username, payload.Kind, payload.Data)
+	if err != nil {
+		if errors.Is(err, notification.ErrUnknownKind) || errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+		return err
+	}
+
+	log.Info().Str("type", task.Type()).Str("username", payload.Username).
+		Str("kind", payload.Kind).Msg("processed task")
+	return nil
+}
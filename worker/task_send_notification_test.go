@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountAlertTask(t *testing.T) {
+	alert := db.TriggeredAlert{
+		Rule:      db.AlertLowBalance,
+		Account:   db.Account{ID: 7, Owner: "tom", Balance: 40, Currency: "USD"},
+		Amount:    70,
+		Threshold: 50,
+	}
+
+	task, err := NewAccountAlertTask(alert)
+	require.NoError(t, err)
+	require.Equal(t, TaskSendNotification, task.TaskType)
+
+	var payload PayloadSendNotification
+	require.NoError(t, json.Unmarshal(task.Payload, &payload))
+	require.Equal(t, "tom", payload.Username)
+	require.Equal(t, notification.KindLowBalance, payload.Kind)
+
+	// the payload has every key the template of the kind needs
+	msg, err := notification.Render(payload.Kind, payload.Data)
+	require.NoError(t, err)
+	require.Contains(t, msg.Body, "is 0.40 USD, below your alert threshold of 0.50 USD")
+
+	alert.Rule = db.AlertLargeTransaction
+	task, err = NewAccountAlertTask(alert)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(task.Payload, &payload))
+	_, err = notification.Render(payload.Kind, payload.Data)
+	require.NoError(t, err)
+
+	alert.Rule = "unknown"
+	_, err = NewAccountAlertTask(alert)
+	require.Error(t, err)
+}
+
+func TestNewBalanceAdjustedTask(t *testing.T) {
+	result := db.AdjustBalanceTxResult{
+		Account: db.Account{ID: 7, Owner: "tom", Balance: 140, Currency: "USD"},
+		Entry:   db.Entry{AccountID: 7, Amount: 100},
+	}
+
+	task, err := NewBalanceAdjustedTask(result, "refund of a fee")
+	require.NoError(t, err)
+	require.Equal(t, TaskSendNotification, task.TaskType)
+
+	var payload PayloadSendNotification
+	require.NoError(t, json.Unmarshal(task.Payload, &payload))
+	require.Equal(t, "tom", payload.Username)
+	require.Equal(t, notification.KindBalanceAdjusted, payload.Kind)
+
+	msg, err := notification.Render(payload.Kind, payload.Data)
+	require.NoError(t, err)
+	require.Equal(t, "An adjustment of +1.00 USD was posted to your account #7: refund of a fee. The balance is now 1.40 USD.", msg.Body)
+}
+
+func TestNewSuspiciousLoginTask(t *testing.T) {
+	alert := db.LoginAlert{ID: 12, Username: "tom", ClientIp: "1.2.3.4", UserAgent: "curl/8.0", Country: "US"}
+
+	task, err := NewSuspiciousLoginTask(alert, "http://localhost:3000/report_login", "abc")
+	require.NoError(t, err)
+	require.Equal(t, TaskSendNotification, task.TaskType)
+
+	var payload PayloadSendNotification
+	require.NoError(t, json.Unmarshal(task.Payload, &payload))
+	require.Equal(t, "tom", payload.Username)
+	require.Equal(t, notification.KindSuspiciousLogin, payload.Kind)
+
+	msg, err := notification.Render(payload.Kind, payload.Data)
+	require.NoError(t, err)
+	require.Contains(t, msg.Body, "from 1.2.3.4 (curl/8.0, US)")
+	require.Contains(t, msg.Body, "http://localhost:3000/report_login?code=abc&id=12")
+}
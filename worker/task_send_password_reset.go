@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendPasswordReset = "task:send_password_reset"
+
+type PayloadSendPasswordReset struct {
+	AlertID int64  `json:"alert_id"`
+	Code    string `json:"code"`
+}
+
+// NewSendPasswordResetTask builds the outbox row that emails the code resetting the password of a
+// user who reported a login. It goes by email only, out of reach of whoever made the login.
+func NewSendPasswordResetTask(payload *PayloadSendPasswordReset) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendPasswordReset, payload, QueueCritical, 3)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordReset
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	alert, err := processor.store.GetLoginAlert(ctx, payload.AlertID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("login alert doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get login alert: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, alert.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.TemplatePasswordReset, mail.PasswordResetData{
+		FullName:     user.FullName,
+		Code:         payload.Code,
+		ValidMinutes: int(fraud.OTPValidity.Minutes()),
+		Brand:        brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	// the payload holds the code, which must not end up in the logs
+	log.Info().Str("type", task.Type()).Int64("alert id", alert.ID).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendStepUpOTP = "task:send_step_up_otp"
+
+type PayloadSendStepUpOTP struct {
+	ChallengeID int64  `json:"challenge_id"`
+	Code        string `json:"code"`
+}
+
+// NewSendStepUpOTPTask builds the outbox row that emails the one-time code of a step-up to its
+// user, the second factor after their password.
+func NewSendStepUpOTPTask(payload *PayloadSendStepUpOTP) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendStepUpOTP, payload, QueueCritical, 3)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendStepUpOTP(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendStepUpOTP
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	challenge, err := processor.store.GetStepUpChallenge(ctx, payload.ChallengeID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("step-up challenge doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get step-up challenge: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, challenge.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.TemplateStepUpOTP, mail.StepUpOTPData{
+		FullName:     user.FullName,
+		Code:         payload.Code,
+		ValidMinutes: int(fraud.OTPValidity.Minutes()),
+		Brand:        brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send step-up code email: %w", err)
+	}
+
+	// the payload holds the code, which must not end up in the logs
+	log.Info().Str("type", task.Type()).Int64("challenge id", challenge.ID).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
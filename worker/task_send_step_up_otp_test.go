@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskSendStepUpOTP(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+	}
+	challenge := db.StepUpChallenge{
+		ID:       util.RandomInt(1, 1000),
+		Username: user.Username,
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent []mail.Message, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challenge.ID)).Times(1).Return(challenge, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(db.Tenant{ID: util.DefaultTenant, Name: "Simple Bank"}, nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.NoError(t, err)
+				require.Len(t, sent, 1)
+				require.Equal(t, []string{user.Email}, sent[0].To)
+				require.Equal(t, "Your code to confirm it's you", sent[0].Subject)
+				require.Contains(t, sent[0].Content, "<strong>123456</strong>")
+				require.Contains(t, sent[0].Content, "valid for 10 minutes")
+			},
+		},
+		{
+			name: "ChallengeNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Any()).Times(1).Return(db.StepUpChallenge{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+				require.Empty(t, sent)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			sender := &fakeEmailSender{}
+			processor := &RedisTaskProcessor{store: store, mailer: sender}
+
+			payload, err := json.Marshal(PayloadSendStepUpOTP{ChallengeID: challenge.ID, Code: "123456"})
+			require.NoError(t, err)
+			err = processor.ProcessTaskSendStepUpOTP(context.Background(), asynq.NewTask(TaskSendStepUpOTP, payload))
+			tc.checkSent(t, sender.sent, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/fraud"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendTransferOTP = "task:send_transfer_otp"
+
+type PayloadSendTransferOTP struct {
+	Username string `json:"username"`
+	ReviewID int64  `json:"review_id"`
+	Code     string `json:"code"`
+}
+
+// NewSendTransferOTPTask builds the outbox row that emails the one-time code of a transfer review
+// to its owner. The code can't be sent again once it expired, so the task gives up quickly.
+func NewSendTransferOTPTask(payload *PayloadSendTransferOTP) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendTransferOTP, payload, QueueCritical, 3)
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendTransferOTP(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendTransferOTP
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	review, err := processor.store.GetTransferReview(ctx, payload.ReviewID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("transfer review doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get transfer review: %w", err)
+	}
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.TemplateTransferOTP, mail.TransferOTPData{
+		FullName:      user.FullName,
+		Code:          payload.Code,
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        review.Amount,
+		Currency:      review.Currency,
+		ValidMinutes:  int(fraud.OTPValidity.Minutes()),
+		Brand:         brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send transfer code email: %w", err)
+	}
+
+	// the payload holds the code, which must not end up in the logs
+	log.Info().Str("type", task.Type()).Int64("review id", review.ID).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
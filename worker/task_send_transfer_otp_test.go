@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskSendTransferOTP(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+	}
+	review := db.TransferReview{
+		ID:            util.RandomInt(1, 1000),
+		Username:      user.Username,
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        250,
+		Currency:      util.USD,
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent []mail.Message, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Any()).Times(1).Return(db.Tenant{ID: util.DefaultTenant, Name: "Simple Bank"}, nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.NoError(t, err)
+				require.Len(t, sent, 1)
+				require.Equal(t, []string{user.Email}, sent[0].To)
+				require.Contains(t, sent[0].Content, "<strong>123456</strong>")
+				require.Contains(t, sent[0].Content, "To send 2.50 USD from your account #1 to account #2")
+				require.Contains(t, sent[0].Content, "valid for 10 minutes")
+			},
+		},
+		{
+			name: "ReviewNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferReview{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+				require.Empty(t, sent)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			sender := &fakeEmailSender{}
+			processor := &RedisTaskProcessor{store: store, mailer: sender}
+
+			payload, err := json.Marshal(PayloadSendTransferOTP{Username: user.Username, ReviewID: review.ID, Code: "123456"})
+			require.NoError(t, err)
+			err = processor.ProcessTaskSendTransferOTP(context.Background(), asynq.NewTask(TaskSendTransferOTP, payload))
+			tc.checkSent(t, sender.sent, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendVerifyEmail = "task:send_verify_email"
+
+type PayloadSendVerifyEmail struct {
+	Username string `json:"username"`
+}
+
+// NewSendVerifyEmailTask builds the outbox row that sends the verification email to a new user.
+func NewSendVerifyEmailTask(payload *PayloadSendVerifyEmail) (db.CreateOutboxTaskParams, error) {
+	return newOutboxTask(TaskSendVerifyEmail, payload, QueueCritical, 10)
+}
+
+// AfterCreateUser builds the outbox tasks of a new user, its verification email. It is the
+// AfterCreate of db.CreateUserTx.
+func AfterCreateUser(user db.User) ([]db.CreateOutboxTaskParams, error) {
+	task, err := NewSendVerifyEmailTask(&PayloadSendVerifyEmail{Username: user.Username})
+	if err != nil {
+		return nil, err
+	}
+	return []db.CreateOutboxTaskParams{task}, nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
+	ctx context.Context,
+	payload *PayloadSendVerifyEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	opts = append(distributor.policies.For(TaskSendVerifyEmail).options(), opts...)
+	task := asynq.NewTask(TaskSendVerifyEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendVerifyEmail
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	brand, err := processor.userBrand(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.TemplateVerifyEmail, mail.VerifyEmailData{
+		FullName:  user.FullName,
+		VerifyURL: processor.verifyEmailLink(user.Username),
+		Brand:     brand,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", asynq.SkipRetry)
+	}
+	brand.Apply(&msg)
+	msg.To = []string{user.Email}
+
+	if err := processor.mailer.SendEmail(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send verify email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
+
+// verifyEmailLink points the user to EMAIL_VERIFY_URL.
+// TODO: add a secret code to the link once there is an endpoint to check it
+func (processor *RedisTaskProcessor) verifyEmailLink(username string) string {
+	return processor.verifyEmailURL + "?" + url.Values{"username": {username}}.Encode()
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/mail"
+	"github.com/backendmaster/simple_bank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEmailSender struct {
+	sent []mail.Message
+	err  error
+}
+
+func (sender *fakeEmailSender) SendEmail(ctx context.Context, msg mail.Message) error {
+	if sender.err != nil {
+		return sender.err
+	}
+	sender.sent = append(sender.sent, msg)
+	return nil
+}
+
+func TestProcessTaskSendVerifyEmail(t *testing.T) {
+	user := db.User{
+		Username: util.RandomOwnerName(),
+		FullName: util.RandomOwnerName(),
+		Email:    util.RandomEmail(),
+		TenantID: "acme",
+	}
+	tenant := db.Tenant{ID: "acme", Name: "Acme Bank", EmailSenderName: "Acme", SupportEmail: "help@acme.com"}
+
+	testCases := []struct {
+		name       string
+		sendErr    error
+		buildStubs func(store *mockdb.MockStore)
+		checkSent  func(t *testing.T, sent []mail.Message, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(user.TenantID)).Times(1).Return(tenant, nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.NoError(t, err)
+				require.Len(t, sent, 1)
+				require.Equal(t, []string{user.Email}, sent[0].To)
+				require.Contains(t, sent[0].Content, "http://localhost:3000/verify_email?username="+user.Username)
+				// the email is branded as the tenant of the user
+				require.Equal(t, "Acme", sent[0].FromName)
+				require.Contains(t, sent[0].Content, "Thank you for registering with Acme Bank")
+				require.Contains(t, sent[0].Content, "help@acme.com")
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.ErrorIs(t, err, asynq.SkipRetry)
+				require.Empty(t, sent)
+			},
+		},
+		{
+			name:    "SendError",
+			sendErr: errors.New("smtp unavailable"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetTenant(gomock.Any(), gomock.Eq(user.TenantID)).Times(1).Return(tenant, nil)
+			},
+			checkSent: func(t *testing.T, sent []mail.Message, err error) {
+				require.Error(t, err)
+				require.NotErrorIs(t, err, asynq.SkipRetry)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			sender := &fakeEmailSender{err: tc.sendErr}
+			processor := &RedisTaskProcessor{
+				store:          store,
+				mailer:         sender,
+				verifyEmailURL: "http://localhost:3000/verify_email",
+			}
+
+			payload, err := json.Marshal(PayloadSendVerifyEmail{Username: user.Username})
+			require.NoError(t, err)
+			err = processor.ProcessTaskSendVerifyEmail(context.Background(), asynq.NewTask(TaskSendVerifyEmail, payload))
+			tc.checkSent(t, sender.sent, err)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/money"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSettleExternalTransfers = "task:settle_external_transfers"
+
+// settlementBatchSize is how many external transfers a run of the settlement goes through at
+// most, the next run taking the rest.
+const settlementBatchSize = 100
+
+// ProcessTaskSettleExternalTransfers submits the pending external transfers to their network, and
+// settles or returns the submitted ones due, as the network tells. A transfer failing is tried
+// again by the next run, and doesn't keep the others from going through.
+func (processor *RedisTaskProcessor) ProcessTaskSettleExternalTransfers(ctx context.Context, task *asynq.Task) error {
+	transfers, err := processor.store.ListExternalTransfersToSettle(ctx, settlementBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list external transfers to settle: %w", err)
+	}
+
+	failed := 0
+	var lastErr error
+	for _, transfer := range transfers {
+		if transfer.Status == db.ExternalPending {
+			err = processor.submitExternalTransfer(ctx, transfer)
+		} else {
+			err = processor.completeExternalTransfer(ctx, transfer)
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("external transfer id", transfer.ID).Msg("failed to settle external transfer")
+			failed++
+			lastErr = err
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("transfers", len(transfers)).Int("failed", failed).Msg("processed task")
+	if lastErr != nil {
+		return fmt.Errorf("failed to settle %d of %d external transfers: %w", failed, len(transfers), lastErr)
+	}
+	return nil
+}
+
+func (processor *RedisTaskProcessor) submitExternalTransfer(ctx context.Context, transfer db.ExternalTransfer) error {
+	reference, settleAt, err := processor.network.Submit(ctx, transfer)
+	if err != nil {
+		return fmt.Errorf("failed to submit external transfer: %w", err)
+	}
+
+	_, err = processor.store.SubmitExternalTransfer(ctx, db.SubmitExternalTransferParams{
+		ID:        transfer.ID,
+		Reference: reference,
+		SettleAt:  pgtype.Timestamptz{Time: settleAt, Valid: true},
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to mark external transfer submitted: %w", err)
+	}
+	return nil
+}
+
+// completeExternalTransfer settles or returns a submitted transfer, and notifies the owner of its
+// account of how it ended.
+func (processor *RedisTaskProcessor) completeExternalTransfer(ctx context.Context, transfer db.ExternalTransfer) error {
+	outcome, err := processor.network.Outcome(ctx, transfer)
+	if err != nil {
+		return fmt.Errorf("failed to get outcome of external transfer: %w", err)
+	}
+	account, err := processor.store.GetAccountIncludeDeleted(ctx, transfer.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	arg := db.CompleteExternalTransferTxParams{
+		ID:     transfer.ID,
+		Status: db.ExternalSettled,
+	}
+	kind := notification.KindExternalTransferSettled
+	if outcome.Returned {
+		arg.Status = db.ExternalReturned
+		arg.ReturnReason = outcome.Reason
+		kind = notification.KindExternalTransferReturned
+	}
+	arg.AfterComplete = func(completed db.ExternalTransfer) ([]db.CreateOutboxTaskParams, error) {
+		notify, err := NewSendNotificationTask(&PayloadSendNotification{
+			Username: account.Owner,
+			Kind:     kind,
+			Data: map[string]string{
+				"external_transfer_id": strconv.FormatInt(completed.ID, 10),
+				"account_id":           strconv.FormatInt(completed.AccountID, 10),
+				"amount":               money.Format(completed.Amount, completed.Currency),
+				"currency":             completed.Currency,
+				"beneficiary_name":     completed.BeneficiaryName,
+				"reference":            completed.Reference,
+				"reason":               completed.ReturnReason,
+			},
+		})
+		return []db.CreateOutboxTaskParams{notify}, err
+	}
+
+	_, err = processor.store.CompleteExternalTransferTx(ctx, arg)
+	if err != nil && !errors.Is(err, db.ErrExternalTransferClosed) {
+		return fmt.Errorf("failed to complete external transfer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/backendmaster/simple_bank/notification"
+	"github.com/backendmaster/simple_bank/settlement"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskSettleExternalTransfers(t *testing.T) {
+	account := db.Account{ID: 7, Owner: "tom", Balance: 40, Currency: "EUR"}
+	pending := db.ExternalTransfer{
+		ID:                 1,
+		AccountID:          account.ID,
+		Amount:             25,
+		Currency:           account.Currency,
+		Rail:               settlement.RailSEPA,
+		BeneficiaryName:    "Anna Schmidt",
+		BeneficiaryAccount: "DE89370400440532013000",
+		Status:             db.ExternalPending,
+	}
+	submitted := pending
+	submitted.ID = 2
+	submitted.Status = db.ExternalSubmitted
+	submitted.Reference = "SEPA-0000000002"
+	closed := submitted
+	closed.ID = 3
+	closed.BeneficiaryAccount = "DE94370400440532010000"
+	closed.Reference = "SEPA-0000000003"
+
+	// completeTx checks the completion of a transfer, and renders the notification it sends.
+	completeTx := func(t *testing.T, status string, body string) func(context.Context, db.CompleteExternalTransferTxParams) (db.CompleteExternalTransferTxResult, error) {
+		return func(_ context.Context, arg db.CompleteExternalTransferTxParams) (db.CompleteExternalTransferTxResult, error) {
+			require.Equal(t, status, arg.Status)
+
+			completed := submitted
+			completed.ID = arg.ID
+			completed.Status = arg.Status
+			completed.ReturnReason = arg.ReturnReason
+			tasks, err := arg.AfterComplete(completed)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			var payload PayloadSendNotification
+			require.NoError(t, json.Unmarshal(tasks[0].Payload, &payload))
+			require.Equal(t, account.Owner, payload.Username)
+			msg, err := notification.Render(payload.Kind, payload.Data)
+			require.NoError(t, err)
+			require.Equal(t, body, msg.Body)
+			return db.CompleteExternalTransferTxResult{ExternalTransfer: completed}, nil
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "Submit",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExternalTransfersToSettle(gomock.Any(), gomock.Any()).Times(1).Return([]db.ExternalTransfer{pending}, nil)
+				store.EXPECT().
+					SubmitExternalTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SubmitExternalTransferParams) (db.ExternalTransfer, error) {
+						require.Equal(t, pending.ID, arg.ID)
+						require.Equal(t, "SEPA-0000000001", arg.Reference)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.SettleAt.Time, time.Second)
+						return db.ExternalTransfer{}, nil
+					})
+				store.EXPECT().CompleteExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Settle",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExternalTransfersToSettle(gomock.Any(), gomock.Any()).Times(1).Return([]db.ExternalTransfer{submitted}, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CompleteExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(completeTx(t, db.ExternalSettled,
+						"Your transfer of 0.25 EUR from your account #7 to Anna Schmidt settled with the reference SEPA-0000000002."))
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Return",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExternalTransfersToSettle(gomock.Any(), gomock.Any()).Times(1).Return([]db.ExternalTransfer{closed}, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					CompleteExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(completeTx(t, db.ExternalReturned,
+						"Your transfer of 0.25 EUR to Anna Schmidt was returned by the bank of the beneficiary (AC04: closed account number). The amount is back on your account #7."))
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyCompleted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExternalTransfersToSettle(gomock.Any(), gomock.Any()).Times(1).Return([]db.ExternalTransfer{submitted}, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					CompleteExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CompleteExternalTransferTxResult{}, db.ErrExternalTransferClosed)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "FailureDoesNotStopOthers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExternalTransfersToSettle(gomock.Any(), gomock.Any()).Times(1).Return([]db.ExternalTransfer{submitted, pending}, nil)
+				store.EXPECT().GetAccountIncludeDeleted(gomock.Any(), gomock.Any()).Times(1).Return(account, nil)
+				store.EXPECT().
+					CompleteExternalTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CompleteExternalTransferTxResult{}, errors.New("connection refused"))
+				store.EXPECT().SubmitExternalTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ExternalTransfer{}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "failed to settle 1 of 2 external transfers")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store, network: settlement.NewSimulator(time.Hour)}
+			err := processor.ProcessTaskSettleExternalTransfers(context.Background(), asynq.NewTask(TaskSettleExternalTransfers, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskVerifyAuditLogs = "task:verify_audit_logs"
+
+func (processor *RedisTaskProcessor) ProcessTaskVerifyAuditLogs(ctx context.Context, task *asynq.Task) error {
+	report, err := processor.store.VerifyAuditLogChain(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify audit log chain: %w", err)
+	}
+	metrics.ObserveAuditLogVerification(len(report.Problems))
+
+	if report.OK() {
+		// the head is logged, so that a rewrite of the whole chain can be told from the logs
+		log.Info().Int64("checked", report.Checked).Int64("head id", report.HeadID).
+			Str("head hash", report.HeadHash).Msg("audit log chain verified, no tampering")
+		return nil
+	}
+	for _, problem := range report.Problems {
+		log.Error().Int64("audit log id", problem.ID).Str("problem", problem.Problem).Msg("audit log chain is broken")
+	}
+	// tampering needs a human, not a retry
+	return nil
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskVerifyAuditLogs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyAuditLogChain(gomock.Any()).Times(1).Return(db.AuditChainReport{CheckedAt: time.Now(), Checked: 3}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Tampered",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyAuditLogChain(gomock.Any()).Times(1).Return(db.AuditChainReport{
+					CheckedAt: time.Now(),
+					Checked:   3,
+					Problems:  []db.AuditChainProblem{{ID: 2, Problem: "hash doesn't match the content of the audit log"}},
+				}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyAuditLogChain(gomock.Any()).Times(1).Return(db.AuditChainReport{}, errors.New("connection refused"))
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			err := processor.ProcessTaskVerifyAuditLogs(context.Background(), asynq.NewTask(TaskVerifyAuditLogs, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}
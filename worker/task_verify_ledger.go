@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/backendmaster/simple_bank/metrics"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskVerifyLedger = "task:verify_ledger"
+
+func (processor *RedisTaskProcessor) ProcessTaskVerifyLedger(ctx context.Context, task *asynq.Task) error {
+	report, err := processor.store.VerifyLedger(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify ledger: %w", err)
+	}
+	metrics.ObserveLedgerVerification(len(report.BalanceMismatches), len(report.UnbalancedTransfers))
+
+	if report.OK() {
+		log.Info().Msg("ledger verified, no discrepancy")
+		return nil
+	}
+	for _, mismatch := range report.BalanceMismatches {
+		log.Error().Int64("account id", mismatch.AccountID).Int64("balance", mismatch.Balance).
+			Int64("entries total", mismatch.EntriesTotal).Msg("account balance doesn't match its entries")
+	}
+	for _, transfer := range report.UnbalancedTransfers {
+		log.Error().Int64("transfer id", transfer.TransferID).Int64("debit entries", transfer.DebitEntries).
+			Int64("credit entries", transfer.CreditEntries).Msg("transfer doesn't have one debit and one credit entry")
+	}
+	// the discrepancies need a human, not a retry
+	return nil
+}
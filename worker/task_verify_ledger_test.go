@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/backendmaster/simple_bank/db/mock"
+	db "github.com/backendmaster/simple_bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskVerifyLedger(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyLedger(gomock.Any()).Times(1).Return(db.LedgerReport{CheckedAt: time.Now()}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Discrepancies",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyLedger(gomock.Any()).Times(1).Return(db.LedgerReport{
+					CheckedAt: time.Now(),
+					UnbalancedTransfers: []db.ListUnbalancedTransfersRow{
+						{TransferID: 1, FromAccountID: 1, ToAccountID: 2, Amount: 10, DebitEntries: 1},
+					},
+				}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VerifyLedger(gomock.Any()).Times(1).Return(db.LedgerReport{}, errors.New("connection refused"))
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+			err := processor.ProcessTaskVerifyLedger(context.Background(), asynq.NewTask(TaskVerifyLedger, nil))
+			tc.checkErr(t, err)
+		})
+	}
+}